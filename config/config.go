@@ -52,6 +52,8 @@ type Config struct {
 	Lease            string `toml:"lease" json:"lease"`
 	Log              Log    `toml:"log" json:"log"`
 	Status           Status `toml:"status" json:"status"`
+
+	PreparedPlanCache PreparedPlanCache `toml:"prepared-plan-cache" json:"prepared-plan-cache"`
 }
 
 // Log is the log section of config.
@@ -84,6 +86,12 @@ type Status struct {
 	ReportStatus bool `toml:"report-status" json:"report-status"`
 }
 
+// PreparedPlanCache is the PreparedPlanCache section of the config.
+type PreparedPlanCache struct {
+	Enabled  bool `toml:"enabled" json:"enabled"`
+	Capacity uint `toml:"capacity" json:"capacity"`
+}
+
 var defaultConf = Config{
 	Host:             "0.0.0.0",
 	AdvertiseAddress: "",
@@ -101,6 +109,10 @@ var defaultConf = Config{
 		StatusHost:   "0.0.0.0",
 		StatusPort:   10080,
 	},
+	PreparedPlanCache: PreparedPlanCache{
+		Enabled:  true,
+		Capacity: 100,
+	},
 }
 
 var (
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/expression"
@@ -333,7 +334,7 @@ func buildColumnAndConstraint(ctx sessionctx.Context, offset int,
 func checkColumnDefaultValue(ctx sessionctx.Context, col *table.Column, value interface{}) (bool, interface{}, error) {
 	hasDefaultValue := true
 	if value != nil && (col.Tp == mysql.TypeTinyBlob || col.Tp == mysql.TypeMediumBlob ||
-		col.Tp == mysql.TypeLongBlob || col.Tp == mysql.TypeBlob) {
+		col.Tp == mysql.TypeLongBlob || col.Tp == mysql.TypeBlob || col.Tp == mysql.TypeJSON) {
 		// In non-strict SQL mode.
 		if !ctx.GetSessionVars().SQLMode.HasStrictMode() && value == "" {
 			if col.Tp == mysql.TypeBlob || col.Tp == mysql.TypeLongBlob {
@@ -405,6 +406,13 @@ func columnDefToCol(ctx sessionctx.Context, offset int, colDef *ast.ColumnDef, o
 					return nil, nil, errors.Trace(err)
 				}
 				removeOnUpdateNowFlag(col)
+			case ast.ColumnOptionOnUpdate:
+				// TODO: Support other time functions.
+				if col.Tp != mysql.TypeTimestamp && col.Tp != mysql.TypeDatetime ||
+					!expression.IsValidCurrentTimestampExpr(v.Expr, colDef.Tp) {
+					return nil, nil, ErrInvalidOnUpdate.GenWithStackByArgs(col.Name)
+				}
+				col.Flag |= mysql.OnUpdateNowFlag
 			case ast.ColumnOptionComment:
 				err := setColumnComment(ctx, col, v)
 				if err != nil {
@@ -463,6 +471,31 @@ func columnDefToCol(ctx sessionctx.Context, offset int, colDef *ast.ColumnDef, o
 }
 
 func getDefaultValue(ctx sessionctx.Context, col *table.Column, c *ast.ColumnOption) (interface{}, error) {
+	tp, fsp := col.FieldType.Tp, col.FieldType.Decimal
+	if tp == mysql.TypeTimestamp || tp == mysql.TypeDatetime {
+		if x, ok := c.Expr.(*ast.FuncCallExpr); ok && x.FnName.L == ast.CurrentTimestamp &&
+			!expression.IsValidCurrentTimestampExpr(x, &col.FieldType) {
+			return nil, ErrInvalidDefaultValue.GenWithStackByArgs(col.Name.O)
+		}
+		vd, err := expression.GetTimeValue(ctx, c.Expr, tp, int8(fsp))
+		value := vd.GetValue()
+		if err != nil {
+			return nil, ErrInvalidDefaultValue.GenWithStackByArgs(col.Name.O)
+		}
+
+		// Value is nil means `default null`.
+		if value == nil {
+			return nil, nil
+		}
+
+		// If value is types.Time, convert it to string.
+		if vv, ok := value.(types.Time); ok {
+			return vv.String(), nil
+		}
+
+		return value, nil
+	}
+
 	v, err := expression.EvalAstExpr(ctx, c.Expr)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -472,9 +505,58 @@ func getDefaultValue(ctx sessionctx.Context, col *table.Column, c *ast.ColumnOpt
 		return nil, nil
 	}
 
+	switch tp {
+	case mysql.TypeBit:
+		if v.Kind() == types.KindInt64 || v.Kind() == types.KindUint64 {
+			// For BIT fields, convert int into BinaryLiteral.
+			return types.NewBinaryLiteralFromUint(v.GetUint64(), -1).ToString(), nil
+		}
+	case mysql.TypeEnum, mysql.TypeSet:
+		return getHybridDefaultValue(v, col)
+	}
+
 	return v.ToString()
 }
 
+// getHybridDefaultValue checks the default value of an ENUM or SET column
+// against its element list, and returns it in the canonical element spelling.
+func getHybridDefaultValue(v types.Datum, col *table.Column) (string, error) {
+	var (
+		name string
+		err  error
+	)
+	switch v.Kind() {
+	case types.KindInt64, types.KindUint64:
+		if col.Tp == mysql.TypeEnum {
+			var e types.Enum
+			e, err = types.ParseEnumValue(col.Elems, v.GetUint64())
+			name = e.Name
+		} else {
+			var s types.Set
+			s, err = types.ParseSetValue(col.Elems, v.GetUint64())
+			name = s.Name
+		}
+	default:
+		var str string
+		if str, err = v.ToString(); err != nil {
+			return "", errors.Trace(err)
+		}
+		if col.Tp == mysql.TypeEnum {
+			var e types.Enum
+			e, err = types.ParseEnumName(col.Elems, str)
+			name = e.Name
+		} else {
+			var s types.Set
+			s, err = types.ParseSetName(col.Elems, str)
+			name = s.Name
+		}
+	}
+	if err != nil {
+		return "", ErrInvalidDefaultValue.GenWithStackByArgs(col.Name.O)
+	}
+	return name, nil
+}
+
 func removeOnUpdateNowFlag(c *table.Column) {
 	// For timestamp Col, if it is set null or default value,
 	// OnUpdateNowFlag should be removed.
@@ -890,6 +972,66 @@ func (d *ddl) CreateTable(ctx sessionctx.Context, s *ast.CreateTableStmt) (err e
 	return errors.Trace(err)
 }
 
+// CreateView creates a view. The columns of the view are resolved by the planner
+// and stored in stmt.SchemaCols.
+func (d *ddl) CreateView(ctx sessionctx.Context, s *ast.CreateViewStmt) (err error) {
+	ident := ast.Ident{Schema: s.ViewName.Schema, Name: s.ViewName.Name}
+	is := d.GetInfoSchemaWithInterceptor(ctx)
+	schema, ok := is.SchemaByName(ident.Schema)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(ident.Schema)
+	}
+	if err = checkTooLongTable(ident.Name); err != nil {
+		return errors.Trace(err)
+	}
+	var oldViewTblID int64
+	if oldView, err := is.TableByName(ident.Schema, ident.Name); err == nil {
+		if !oldView.Meta().IsView() {
+			return ErrWrongObject.GenWithStackByArgs(ident.Schema, ident.Name, "VIEW")
+		}
+		if !s.OrReplace {
+			return infoschema.ErrTableExists.GenWithStackByArgs(ident)
+		}
+		oldViewTblID = oldView.Meta().ID
+	}
+
+	viewInfo := &model.ViewInfo{
+		Algorithm:   s.Algorithm,
+		Definer:     s.Definer,
+		Security:    s.Security,
+		SelectStmt:  s.Select.Text(),
+		CheckOption: s.CheckOption,
+		Cols:        s.Cols,
+	}
+	cols := make([]*table.Column, 0, len(s.SchemaCols))
+	for i, name := range s.SchemaCols {
+		cols = append(cols, table.ToColumn(&model.ColumnInfo{
+			Name:    name,
+			Offset:  i,
+			State:   model.StatePublic,
+			Version: model.CurrLatestColumnInfoVersion,
+		}))
+	}
+	tbInfo, err := buildTableInfo(ctx, d, ident.Name, cols, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tbInfo.View = viewInfo
+	tbInfo.Charset, tbInfo.Collate = charset.GetDefaultCharsetAndCollate()
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tbInfo.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionCreateView,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{tbInfo, s.OrReplace, oldViewTblID},
+	}
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 func checkCharsetAndCollation(cs string, co string) error {
 	if !charset.ValidCharsetAndCollation(cs, co) {
 		return ErrUnknownCharacterSet.GenWithStackByArgs(cs)
@@ -930,6 +1072,11 @@ func (d *ddl) AlterTable(ctx sessionctx.Context, ident ast.Ident, specs []*ast.A
 		return errors.Trace(err)
 	}
 
+	is := d.infoHandle.Get()
+	if tb, err := is.TableByName(ident.Schema, ident.Name); err == nil && tb.Meta().IsView() {
+		return ErrWrongObject.GenWithStackByArgs(ident.Schema, ident.Name, "BASE TABLE")
+	}
+
 	for _, spec := range validSpecs {
 		switch spec.Tp {
 		case ast.AlterTableAddColumns:
@@ -1254,6 +1401,7 @@ func setDefaultValue(ctx sessionctx.Context, col *table.Column, option *ast.Colu
 	if hasDefaultValue, value, err = checkColumnDefaultValue(ctx, col, value); err != nil {
 		return hasDefaultValue, errors.Trace(err)
 	}
+	value, err = convertTimestampDefaultValToUTC(ctx, value, col)
 	if err != nil {
 		return hasDefaultValue, errors.Trace(err)
 	}
@@ -1264,6 +1412,28 @@ func setDefaultValue(ctx sessionctx.Context, col *table.Column, option *ast.Colu
 	return hasDefaultValue, nil
 }
 
+// convertTimestampDefaultValToUTC converts the default value of a TIMESTAMP
+// column from the session time zone to UTC, because it is read back in UTC.
+func convertTimestampDefaultValToUTC(ctx sessionctx.Context, defaultVal interface{}, col *table.Column) (interface{}, error) {
+	if defaultVal == nil || col.Tp != mysql.TypeTimestamp {
+		return defaultVal, nil
+	}
+	if vv, ok := defaultVal.(string); ok {
+		if vv != types.ZeroDatetimeStr && !strings.EqualFold(vv, ast.CurrentTimestamp) {
+			t, err := types.ParseTime(ctx.GetSessionVars().StmtCtx, vv, col.Tp, int8(col.Decimal))
+			if err != nil {
+				return defaultVal, errors.Trace(err)
+			}
+			err = t.ConvertTimeZone(ctx.GetSessionVars().Location(), time.UTC)
+			if err != nil {
+				return defaultVal, errors.Trace(err)
+			}
+			defaultVal = t.String()
+		}
+	}
+	return defaultVal, nil
+}
+
 func setColumnComment(ctx sessionctx.Context, col *table.Column, option *ast.ColumnOption) error {
 	value, err := expression.EvalAstExpr(ctx, option.Expr)
 	if err != nil {
@@ -1295,6 +1465,13 @@ func processColumnOptions(ctx sessionctx.Context, col *table.Column, options []*
 			col.Flag &= ^mysql.NotNullFlag
 		case ast.ColumnOptionAutoIncrement:
 			col.Flag |= mysql.AutoIncrementFlag
+		case ast.ColumnOptionOnUpdate:
+			// TODO: Support other time functions.
+			if col.Tp != mysql.TypeTimestamp && col.Tp != mysql.TypeDatetime ||
+				!expression.IsValidCurrentTimestampExpr(opt.Expr, &col.FieldType) {
+				return ErrInvalidOnUpdate.GenWithStackByArgs(col.Name)
+			}
+			col.Flag |= mysql.OnUpdateNowFlag
 		case ast.ColumnOptionPrimaryKey, ast.ColumnOptionUniqKey:
 			return errUnsupportedModifyColumn.GenWithStack("can't change column constraint - %v", opt.Tp)
 		case ast.ColumnOptionCollate:
@@ -1746,6 +1923,10 @@ func (d *ddl) DropTable(ctx sessionctx.Context, ti ast.Ident) (err error) {
 		return errors.Trace(err)
 	}
 
+	if tb.Meta().IsView() {
+		return infoschema.ErrTableNotExists.GenWithStackByArgs(ti.Schema, ti.Name)
+	}
+
 	job := &model.Job{
 		SchemaID:   schema.ID,
 		TableID:    tb.Meta().ID,
@@ -1759,6 +1940,30 @@ func (d *ddl) DropTable(ctx sessionctx.Context, ti ast.Ident) (err error) {
 	return errors.Trace(err)
 }
 
+// DropView will proceed even if some view in the list does not exists.
+func (d *ddl) DropView(ctx sessionctx.Context, ti ast.Ident) (err error) {
+	schema, tb, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if !tb.Meta().IsView() {
+		return ErrWrongObject.GenWithStackByArgs(ti.Schema, ti.Name, "VIEW")
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tb.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionDropView,
+		BinlogInfo: &model.HistoryInfo{},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 func getAnonymousIndex(t table.Table, colName model.CIStr) model.CIStr {
 	id := 2
 	l := len(t.Indices())
@@ -1789,6 +1994,9 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if t.Meta().IsView() {
+		return ErrWrongObject.GenWithStackByArgs(ti.Schema, ti.Name, "BASE TABLE")
+	}
 
 	// Deal with anonymous index.
 	if len(indexName.L) == 0 {
@@ -1857,6 +2065,9 @@ func (d *ddl) dropIndex(ctx sessionctx.Context, ti ast.Ident, isPK bool, indexNa
 	if err != nil {
 		return errors.Trace(infoschema.ErrTableNotExists.GenWithStackByArgs(ti.Schema, ti.Name))
 	}
+	if t.Meta().IsView() {
+		return ErrWrongObject.GenWithStackByArgs(ti.Schema, ti.Name, "BASE TABLE")
+	}
 
 	indexInfo := t.Meta().FindIndexByName(indexName.L)
 	if isPK {
@@ -450,7 +450,9 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = onDropSchema(t, job)
 	case model.ActionCreateTable:
 		ver, err = onCreateTable(d, t, job)
-	case model.ActionDropTable:
+	case model.ActionCreateView:
+		ver, err = onCreateView(d, t, job)
+	case model.ActionDropTable, model.ActionDropView:
 		ver, err = onDropTableOrView(t, job)
 	case model.ActionAddColumn:
 		ver, err = onAddColumn(d, t, job)
@@ -615,6 +617,16 @@ func updateSchemaVersion(t *meta.Meta, job *model.Job) (int64, error) {
 		SchemaID: job.SchemaID,
 		TableID:  job.TableID,
 	}
+	if job.Type == model.ActionCreateView {
+		tbInfo := &model.TableInfo{}
+		var orReplace bool
+		var oldTbInfoID int64
+		if err := job.DecodeArgs(tbInfo, &orReplace, &oldTbInfoID); err != nil {
+			return 0, errors.Trace(err)
+		}
+		// When replacing a view, the old view is dropped.
+		diff.OldTableID = oldTbInfoID
+	}
 	err = t.SetSchemaDiff(diff)
 	return schemaVersion, errors.Trace(err)
 }
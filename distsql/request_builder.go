@@ -108,11 +108,14 @@ func (builder *RequestBuilder) SetKeepOrder(order bool) *RequestBuilder {
 	return builder
 }
 
-func (builder *RequestBuilder) getIsolationLevel() kv.IsoLevel {
+func (builder *RequestBuilder) getIsolationLevel(sv *variable.SessionVars) kv.IsoLevel {
 	switch builder.Tp {
 	case kv.ReqTypeAnalyze:
 		return kv.RC
 	}
+	if sv.IsReadCommittedTxn() {
+		return kv.RC
+	}
 	return kv.SI
 }
 
@@ -120,7 +123,7 @@ func (builder *RequestBuilder) getIsolationLevel() kv.IsoLevel {
 // "Concurrency", "IsolationLevel", "NotFillCache", "ReplicaRead".
 func (builder *RequestBuilder) SetFromSessionVars(sv *variable.SessionVars) *RequestBuilder {
 	builder.Request.Concurrency = sv.DistSQLScanConcurrency
-	builder.Request.IsolationLevel = builder.getIsolationLevel()
+	builder.Request.IsolationLevel = builder.getIsolationLevel(sv)
 	builder.Request.NotFillCache = sv.StmtCtx.NotFillCache
 	builder.Request.ReplicaRead = sv.GetReplicaRead()
 	return builder
@@ -19,6 +19,7 @@ import (
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
@@ -538,3 +539,14 @@ func (s *testSuite) TestRequestBuilder7(c *C) {
 
 	c.Assert(actual, DeepEquals, expect)
 }
+
+func (s *testSuite) TestRequestBuilder8(c *C) {
+	vars := variable.NewSessionVars()
+	vars.TxnCtx.Isolation = ast.ReadCommitted
+
+	actual, err := (&RequestBuilder{}).
+		SetFromSessionVars(vars).
+		Build()
+	c.Assert(err, IsNil)
+	c.Assert(actual.IsolationLevel, Equals, kv.RC)
+}
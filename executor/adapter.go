@@ -21,10 +21,12 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/planner"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
@@ -130,6 +132,49 @@ func (a *recordSet) Close() error {
 	return err
 }
 
+// chunkRowRecordSet wraps the rows materialized by a pessimistic `SELECT ... FOR UPDATE`,
+// the rows must be read and locked before the statement returns.
+type chunkRowRecordSet struct {
+	rows     []chunk.Row
+	idx      int
+	fields   []*ast.ResultField
+	e        Executor
+	execStmt *ExecStmt
+}
+
+func (c *chunkRowRecordSet) Fields() []*ast.ResultField {
+	return c.fields
+}
+
+func (c *chunkRowRecordSet) Next(ctx context.Context, chk *chunk.Chunk) error {
+	chk.Reset()
+	for !chk.IsFull() && c.idx < len(c.rows) {
+		chk.AppendRow(c.rows[c.idx])
+		c.idx++
+	}
+	sessVars := c.execStmt.Ctx.GetSessionVars()
+	if chk.NumRows() == 0 {
+		sessVars.LastFoundRows = sessVars.StmtCtx.FoundRows()
+		return nil
+	}
+	sessVars.StmtCtx.AddFoundRows(uint64(chk.NumRows()))
+	return nil
+}
+
+func (c *chunkRowRecordSet) NewChunk() *chunk.Chunk {
+	return newFirstChunk(c.e)
+}
+
+func (c *chunkRowRecordSet) Close() error {
+	sessVars := c.execStmt.Ctx.GetSessionVars()
+	sessVars.PrevStmt = FormatSQL(c.execStmt.OriginText())
+	return nil
+}
+
+// maxPessimisticRetryCount is the max times a statement is retried for the write conflicts
+// when locking the keys in a pessimistic transaction.
+const maxPessimisticRetryCount = 256
+
 // ExecStmt implements the sqlexec.Statement interface, it builds a planner.Plan to an sqlexec.Statement.
 type ExecStmt struct {
 	// InfoSchema stores a reference to the schema information.
@@ -148,6 +193,9 @@ type ExecStmt struct {
 
 	// OutputNames will be set if using cached plan
 	OutputNames []*types.FieldName
+
+	isSelectForUpdate bool
+	retryCount        uint
 }
 
 // OriginText returns original statement as a string.
@@ -157,9 +205,34 @@ func (a *ExecStmt) OriginText() string {
 
 // IsReadOnly returns true if a statement is read only.
 func (a *ExecStmt) IsReadOnly() bool {
+	if execStmt, ok := a.StmtNode.(*ast.ExecuteStmt); ok {
+		s, err := getPreparedStmt(execStmt, a.Ctx.GetSessionVars())
+		if err != nil {
+			logutil.BgLogger().Error("getPreparedStmt failed", zap.Error(err))
+			return false
+		}
+		return ast.IsReadOnly(s)
+	}
 	return ast.IsReadOnly(a.StmtNode)
 }
 
+// RebuildPlan rebuilds current execute statement plan.
+// It returns the current information schema version that 'a' is using.
+func (a *ExecStmt) RebuildPlan(ctx context.Context) (int64, error) {
+	is := infoschema.GetInfoSchema(a.Ctx)
+	a.InfoSchema = is
+	if err := plannercore.Preprocess(a.Ctx, a.StmtNode, is); err != nil {
+		return 0, err
+	}
+	p, names, err := planner.Optimize(ctx, a.Ctx, a.StmtNode, is)
+	if err != nil {
+		return 0, err
+	}
+	a.OutputNames = names
+	a.Plan = p
+	return is.SchemaMetaVersion(), nil
+}
+
 // Exec builds an Executor from a plan. If the Executor doesn't return result,
 // like the INSERT, UPDATE statements, it executes in this function, if the Executor returns
 // result, execution is done after this function returns, in the returned sqlexec.RecordSet Next method.
@@ -177,6 +250,21 @@ func (a *ExecStmt) Exec(ctx context.Context) (_ sqlexec.RecordSet, err error) {
 	}()
 
 	sctx := a.Ctx
+	isPessimistic := a.isPessimisticLockStmt()
+	if isPessimistic {
+		// The statement reads and locks the latest data at the for update ts.
+		if err = UpdateForUpdateTS(sctx, 0); err != nil {
+			return nil, err
+		}
+		defer func() {
+			terror.Log(resetSnapshotTS(sctx))
+		}()
+	} else if sctx.GetSessionVars().InTxn() && sctx.GetSessionVars().IsReadCommittedTxn() {
+		// Every statement of a read committed transaction reads the data committed before it starts.
+		if err = UpdateForUpdateTS(sctx, 0); err != nil {
+			return nil, err
+		}
+	}
 	e, err := a.buildExecutor()
 	if err != nil {
 		return nil, err
@@ -187,7 +275,12 @@ func (a *ExecStmt) Exec(ctx context.Context) (_ sqlexec.RecordSet, err error) {
 		return nil, err
 	}
 
-	if handled, result, err := a.handleNoDelay(ctx, e); handled {
+	// Special handle for "select for update statement" in pessimistic transaction.
+	if isPessimistic && a.isSelectForUpdate {
+		return a.handlePessimisticSelectForUpdate(ctx, e)
+	}
+
+	if handled, result, err := a.handleNoDelay(ctx, e, isPessimistic); handled {
 		return result, err
 	}
 
@@ -206,11 +299,14 @@ func (a *ExecStmt) Exec(ctx context.Context) (_ sqlexec.RecordSet, err error) {
 	}, nil
 }
 
-func (a *ExecStmt) handleNoDelay(ctx context.Context, e Executor) (bool, sqlexec.RecordSet, error) {
+func (a *ExecStmt) handleNoDelay(ctx context.Context, e Executor, isPessimistic bool) (bool, sqlexec.RecordSet, error) {
 	toCheck := e
 
 	// If the executor doesn't return any result to the client, we execute it without delay.
 	if toCheck.Schema().Len() == 0 {
+		if isPessimistic {
+			return true, nil, a.handlePessimisticDML(ctx, e)
+		}
 		r, err := a.handleNoDelayExecutor(ctx, e)
 		return true, r, err
 	}
@@ -218,6 +314,173 @@ func (a *ExecStmt) handleNoDelay(ctx context.Context, e Executor) (bool, sqlexec
 	return false, nil, nil
 }
 
+// isPessimisticLockStmt returns whether the statement locks the keys it reads or writes,
+// which is true for the DML statements and `SELECT ... FOR UPDATE` in a pessimistic transaction.
+func (a *ExecStmt) isPessimisticLockStmt() bool {
+	sessVars := a.Ctx.GetSessionVars()
+	if !sessVars.TxnCtx.IsPessimistic {
+		return false
+	}
+	stmt := a.StmtNode
+	if execStmt, ok := stmt.(*ast.ExecuteStmt); ok {
+		s, err := getPreparedStmt(execStmt, sessVars)
+		if err != nil {
+			return false
+		}
+		stmt = s
+	}
+	switch x := stmt.(type) {
+	case *ast.InsertStmt, *ast.UpdateStmt, *ast.DeleteStmt:
+		return true
+	case *ast.SelectStmt:
+		return x.LockTp != ast.SelectLockNone
+	}
+	return false
+}
+
+func (a *ExecStmt) handlePessimisticSelectForUpdate(ctx context.Context, e Executor) (sqlexec.RecordSet, error) {
+	for {
+		rs, err := a.runPessimisticSelectForUpdate(ctx, e)
+		e, err = a.handlePessimisticLockError(ctx, err)
+		if err != nil {
+			return nil, err
+		}
+		if e == nil {
+			return rs, nil
+		}
+	}
+}
+
+func (a *ExecStmt) runPessimisticSelectForUpdate(ctx context.Context, e Executor) (sqlexec.RecordSet, error) {
+	defer func() {
+		terror.Log(e.Close())
+	}()
+	var rows []chunk.Row
+	var err error
+	req := newFirstChunk(e)
+	for {
+		err = Next(ctx, e, req)
+		if err != nil {
+			// Handle 'write conflict' error.
+			break
+		}
+		if req.NumRows() == 0 {
+			fields := colNames2ResultFields(e.Schema(), a.OutputNames, a.Ctx.GetSessionVars().CurrentDB)
+			return &chunkRowRecordSet{rows: rows, fields: fields, e: e, execStmt: a}, nil
+		}
+		iter := chunk.NewIterator4Chunk(req)
+		for r := iter.Begin(); r != iter.End(); r = iter.Next() {
+			rows = append(rows, r)
+		}
+		req = chunk.Renew(req, a.Ctx.GetSessionVars().MaxChunkSize)
+	}
+	return nil, err
+}
+
+// pessimisticTxn is the transaction which can tell the keys written by the statement.
+type pessimisticTxn interface {
+	kv.Transaction
+	// KeysNeedToLock returns the keys need to be locked.
+	KeysNeedToLock() ([]kv.Key, error)
+}
+
+func (a *ExecStmt) handlePessimisticDML(ctx context.Context, e Executor) error {
+	sctx := a.Ctx
+	txn, err := sctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	sessVars := sctx.GetSessionVars()
+	for {
+		_, err = a.handleNoDelayExecutor(ctx, e)
+		if err == nil {
+			var keys []kv.Key
+			keys, err = txn.(pessimisticTxn).KeysNeedToLock()
+			if err != nil {
+				return err
+			}
+			err = doLockKeys(ctx, sctx, newLockCtx(sessVars, sessVars.LockWaitTimeout), keys...)
+		}
+		e, err = a.handlePessimisticLockError(ctx, err)
+		if err != nil {
+			return err
+		}
+		if e == nil {
+			return nil
+		}
+	}
+}
+
+// handlePessimisticLockError updates the for update ts and rebuilds the executor to retry
+// the statement if the error is a write conflict. It returns a nil Executor if there's no
+// need to retry.
+func (a *ExecStmt) handlePessimisticLockError(ctx context.Context, err error) (Executor, error) {
+	if err == nil {
+		return nil, nil
+	}
+	if !terror.ErrorEqual(kv.ErrWriteConflict, err) {
+		return nil, err
+	}
+	if a.retryCount >= maxPessimisticRetryCount {
+		return nil, errors.New("pessimistic lock retry limit reached")
+	}
+	a.retryCount++
+	logutil.Logger(ctx).Debug("pessimistic write conflict, retry statement",
+		zap.Uint64("txn", a.Ctx.GetSessionVars().TxnCtx.StartTS),
+		zap.Uint64("forUpdateTS", a.Ctx.GetSessionVars().TxnCtx.GetForUpdateTS()),
+		zap.Error(err))
+	err = UpdateForUpdateTS(a.Ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	e, err := a.buildExecutor()
+	if err != nil {
+		return nil, err
+	}
+	// Rollback the statement change before retry it.
+	a.Ctx.StmtRollback()
+	a.Ctx.GetSessionVars().StmtCtx.ResetForRetry()
+
+	if err = e.Open(ctx); err != nil {
+		terror.Call(e.Close)
+		return nil, err
+	}
+	return e, nil
+}
+
+// UpdateForUpdateTS updates the ForUpdateTS of the transaction, if newForUpdateTS is 0,
+// it obtains a new TS from the store. The snapshot of the transaction reads at the
+// ForUpdateTS until resetSnapshotTS is called.
+func UpdateForUpdateTS(seCtx sessionctx.Context, newForUpdateTS uint64) error {
+	txn, err := seCtx.Txn(true)
+	if err != nil {
+		return err
+	}
+	if newForUpdateTS == 0 {
+		version, err := seCtx.GetStore().CurrentVersion()
+		if err != nil {
+			return err
+		}
+		newForUpdateTS = version.Ver
+	}
+	txnCtx := seCtx.GetSessionVars().TxnCtx
+	txnCtx.SetForUpdateTS(newForUpdateTS)
+	txn.SetOption(kv.SnapshotTS, txnCtx.GetForUpdateTS())
+	return nil
+}
+
+// resetSnapshotTS makes the snapshot of the transaction read at the start ts again.
+func resetSnapshotTS(seCtx sessionctx.Context) error {
+	txn, err := seCtx.Txn(false)
+	if err != nil {
+		return err
+	}
+	if txn.Valid() {
+		txn.SetOption(kv.SnapshotTS, seCtx.GetSessionVars().TxnCtx.StartTS)
+	}
+	return nil
+}
+
 func (a *ExecStmt) handleNoDelayExecutor(ctx context.Context, e Executor) (sqlexec.RecordSet, error) {
 	var err error
 	defer func() {
@@ -241,6 +504,17 @@ func (a *ExecStmt) buildExecutor() (Executor, error) {
 		return nil, errors.Trace(b.err)
 	}
 
+	// ExecuteExec is not a real Executor, we only use it to build another Executor from a prepared statement.
+	if executorExec, ok := e.(*ExecuteExec); ok {
+		err := executorExec.Build(b)
+		if err != nil {
+			return nil, err
+		}
+		ctx.SetValue(sessionctx.QueryString, executorExec.stmt.Text())
+		a.Plan = executorExec.plan
+		e = executorExec.stmtExec
+	}
+	a.isSelectForUpdate = b.isSelectForUpdate
 	return e, nil
 }
 
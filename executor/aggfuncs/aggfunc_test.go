@@ -78,9 +78,9 @@ func (s *testSuite) testMergePartialResult(c *C, p aggTest) {
 	iter := chunk.NewIterator4Chunk(srcChk)
 
 	args := []expression.Expression{&expression.Column{RetType: p.dataType, Index: 0}}
-	desc, err := aggregation.NewAggFuncDesc(s.ctx, p.funcName, args)
+	desc, err := aggregation.NewAggFuncDesc(s.ctx, p.funcName, args, false)
 	c.Assert(err, IsNil)
-	partialDesc, finalDesc := desc.Split([]int{0, 1})
+	partialDesc, finalDesc := desc.Split([]int{0, 1, 2})
 
 	// build partial func for partial phase.
 	partialFunc := aggfuncs.Build(s.ctx, partialDesc, 0)
@@ -169,7 +169,7 @@ func (s *testSuite) testAggFunc(c *C, p aggTest) {
 	srcChk.AppendDatum(0, &types.Datum{})
 
 	args := []expression.Expression{&expression.Column{RetType: p.dataType, Index: 0}}
-	desc, err := aggregation.NewAggFuncDesc(s.ctx, p.funcName, args)
+	desc, err := aggregation.NewAggFuncDesc(s.ctx, p.funcName, args, false)
 	c.Assert(err, IsNil)
 	finalFunc := aggfuncs.Build(s.ctx, desc, 0)
 	finalPr := finalFunc.AllocPartialResult()
@@ -203,3 +203,44 @@ func (s *testSuite) testAggFunc(c *C, p aggTest) {
 	c.Assert(err, IsNil)
 	c.Assert(result, Equals, 0)
 }
+
+// testAggFuncWithDesc checks the result of the aggregate function described by
+// desc over all the rows of srcChk, both in one phase and in two phases whose
+// partial results are merged by the final function.
+func (s *testSuite) testAggFuncWithDesc(c *C, desc *aggregation.AggFuncDesc, srcChk *chunk.Chunk, expected types.Datum) {
+	finalFunc := aggfuncs.Build(s.ctx, desc, 0)
+	finalPr := finalFunc.AllocPartialResult()
+	resultChk := chunk.NewChunkWithCapacity([]*types.FieldType{desc.RetTp}, 1)
+
+	iter := chunk.NewIterator4Chunk(srcChk)
+	for row := iter.Begin(); row != iter.End(); row = iter.Next() {
+		err := finalFunc.UpdatePartialResult(s.ctx, []chunk.Row{row}, finalPr)
+		c.Assert(err, IsNil)
+	}
+	err := finalFunc.AppendFinalResult2Chunk(s.ctx, finalPr, resultChk)
+	c.Assert(err, IsNil)
+	dt := resultChk.GetRow(0).GetDatum(0, desc.RetTp)
+	result, err := dt.CompareDatum(s.ctx.GetSessionVars().StmtCtx, &expected)
+	c.Assert(err, IsNil)
+	c.Assert(result, Equals, 0, Commentf("got %v, expected %v", dt.GetValue(), expected.GetValue()))
+
+	// Feed every row to its own partial result, then merge them.
+	partialDesc, finalDesc := desc.Split([]int{0, 1, 2})
+	partialFunc := aggfuncs.Build(s.ctx, partialDesc, 0)
+	finalFunc = aggfuncs.Build(s.ctx, finalDesc, 0)
+	finalPr = finalFunc.AllocPartialResult()
+	for row := iter.Begin(); row != iter.End(); row = iter.Next() {
+		partialPr := partialFunc.AllocPartialResult()
+		err = partialFunc.UpdatePartialResult(s.ctx, []chunk.Row{row}, partialPr)
+		c.Assert(err, IsNil)
+		err = finalFunc.MergePartialResult(s.ctx, partialPr, finalPr)
+		c.Assert(err, IsNil)
+	}
+	resultChk.Reset()
+	err = finalFunc.AppendFinalResult2Chunk(s.ctx, finalPr, resultChk)
+	c.Assert(err, IsNil)
+	dt = resultChk.GetRow(0).GetDatum(0, desc.RetTp)
+	result, err = dt.CompareDatum(s.ctx.GetSessionVars().StmtCtx, &expected)
+	c.Assert(err, IsNil)
+	c.Assert(result, Equals, 0, Commentf("got %v, expected %v", dt.GetValue(), expected.GetValue()))
+}
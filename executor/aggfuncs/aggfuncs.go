@@ -52,6 +52,28 @@ var (
 	// All the AggFunc implementations for "SUM" are listed here.
 	_ AggFunc = (*sum4Int64)(nil)
 	_ AggFunc = (*sum4Float64)(nil)
+
+	// All the AggFunc implementations for "GROUP_CONCAT" are listed here.
+	_ AggFunc = (*groupConcat)(nil)
+	_ AggFunc = (*groupConcatDistinctOrder)(nil)
+
+	// All the AggFunc implementations for "BIT_OR", "BIT_XOR" and "BIT_AND" are listed here.
+	_ AggFunc = (*bitOrUint64)(nil)
+	_ AggFunc = (*bitXorUint64)(nil)
+	_ AggFunc = (*bitAndUint64)(nil)
+
+	// All the AggFunc implementations for "VAR_POP", "VAR_SAMP", "STDDEV_POP" and "STDDEV_SAMP" are listed here.
+	_ AggFunc = (*varianceOriginal4Float64)(nil)
+	_ AggFunc = (*variancePartial4Float64)(nil)
+
+	// All the AggFunc implementations for the functions with DISTINCT are listed here.
+	_ AggFunc = (*distinctAggFunc)(nil)
+
+	// All the AggFunc implementations for window functions are listed here.
+	_ AggFunc = (*rowNumber)(nil)
+	_ AggFunc = (*rank)(nil)
+	_ AggFunc = (*lead)(nil)
+	_ AggFunc = (*lag)(nil)
 )
 
 // PartialResult represents data structure to store the partial result for the
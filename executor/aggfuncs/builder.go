@@ -14,16 +14,29 @@
 package aggfuncs
 
 import (
+	"fmt"
+	"strconv"
+
+	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/expression/aggregation"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
 )
 
 // Build is used to build a specific AggFunc implementation according to the
 // input aggFuncDesc.
 func Build(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	if aggFuncDesc.HasDistinct {
+		switch aggFuncDesc.Name {
+		case ast.AggFuncCount, ast.AggFuncSum, ast.AggFuncAvg, ast.AggFuncBitXor, ast.AggFuncVarPop,
+			ast.AggFuncVarSamp, ast.AggFuncStddevPop, ast.AggFuncStddevSamp:
+			return buildDistinct(ctx, aggFuncDesc, ordinal)
+		}
+	}
 	switch aggFuncDesc.Name {
 	case ast.AggFuncCount:
 		return buildCount(aggFuncDesc, ordinal)
@@ -37,10 +50,65 @@ func Build(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal
 		return buildMaxMin(aggFuncDesc, ordinal, true)
 	case ast.AggFuncMin:
 		return buildMaxMin(aggFuncDesc, ordinal, false)
+	case ast.AggFuncGroupConcat:
+		return buildGroupConcat(ctx, aggFuncDesc, ordinal)
+	case ast.AggFuncBitOr:
+		return buildBitOr(aggFuncDesc, ordinal)
+	case ast.AggFuncBitXor:
+		return buildBitXor(aggFuncDesc, ordinal)
+	case ast.AggFuncBitAnd:
+		return buildBitAnd(aggFuncDesc, ordinal)
+	case ast.AggFuncVarPop, ast.AggFuncVarSamp, ast.AggFuncStddevPop, ast.AggFuncStddevSamp:
+		return buildVariance(aggFuncDesc, ordinal)
 	}
 	return nil
 }
 
+// buildDistinct builds the AggFunc implementation for the aggregate functions
+// with DISTINCT, DISTINCT is meaningless for the other functions and ignored.
+func buildDistinct(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	innerDesc := aggFuncDesc.Clone()
+	innerDesc.HasDistinct = false
+	innerDesc.Mode = aggregation.CompleteMode
+	fieldTps := make([]*types.FieldType, 0, len(aggFuncDesc.Args))
+	for i, arg := range aggFuncDesc.Args {
+		fieldTps = append(fieldTps, arg.GetType())
+		innerDesc.Args[i] = &expression.Column{Index: i, RetType: arg.GetType()}
+	}
+	inner := Build(ctx, innerDesc, ordinal)
+	if inner == nil {
+		return nil
+	}
+	return &distinctAggFunc{
+		baseAggFunc: baseAggFunc{
+			args:    aggFuncDesc.Args,
+			ordinal: ordinal,
+		},
+		inner:    inner,
+		fieldTps: fieldTps,
+	}
+}
+
+// BuildWindowFunctions builds specific window function according to function description and order by columns.
+func BuildWindowFunctions(ctx sessionctx.Context, windowFuncDesc *aggregation.AggFuncDesc, ordinal int, orderByCols []*expression.Column) AggFunc {
+	switch windowFuncDesc.Name {
+	case ast.WindowFuncRank:
+		return buildRank(ordinal, orderByCols, false)
+	case ast.WindowFuncDenseRank:
+		return buildRank(ordinal, orderByCols, true)
+	case ast.WindowFuncRowNumber:
+		return buildRowNumber(windowFuncDesc, ordinal)
+	case ast.WindowFuncFirstValue:
+		return buildFirstRow(windowFuncDesc, ordinal)
+	case ast.WindowFuncLead:
+		return buildLead(ctx, windowFuncDesc, ordinal)
+	case ast.WindowFuncLag:
+		return buildLag(ctx, windowFuncDesc, ordinal)
+	default:
+		return Build(ctx, windowFuncDesc, ordinal)
+	}
+}
+
 // buildCount builds the AggFunc implementation for function "COUNT".
 func buildCount(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 	base := baseAggFunc{
@@ -55,6 +123,14 @@ func buildCount(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 			return &countOriginal4Int{baseCount{base}}
 		case types.ETReal:
 			return &countOriginal4Real{baseCount{base}}
+		case types.ETDecimal:
+			return &countOriginal4Decimal{baseCount{base}}
+		case types.ETDatetime, types.ETTimestamp:
+			return &countOriginal4Time{baseCount{base}}
+		case types.ETDuration:
+			return &countOriginal4Duration{baseCount{base}}
+		case types.ETJson:
+			return &countOriginal4JSON{baseCount{base}}
 		case types.ETString:
 			return &countOriginal4String{baseCount{base}}
 		}
@@ -76,6 +152,8 @@ func buildSum(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 	switch aggFuncDesc.RetTp.EvalType() {
 	case types.ETInt:
 		return &sum4Int64{base}
+	case types.ETDecimal:
+		return &sum4Decimal{base}
 	default:
 		return &sum4Float64{base}
 	}
@@ -94,6 +172,8 @@ func buildAvg(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 		switch aggFuncDesc.RetTp.EvalType() {
 		case types.ETInt:
 			return &avgOriginal4Int64{baseAvgInt64{base}}
+		case types.ETDecimal:
+			return &avgOriginal4Decimal{baseAvgDecimal{base}}
 		default:
 			return &avgOriginal4Float64{baseAvgFloat64{base}}
 		}
@@ -104,6 +184,8 @@ func buildAvg(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 		switch aggFuncDesc.RetTp.Tp {
 		case mysql.TypeLonglong:
 			return &avgPartial4Int64{baseAvgInt64{base}}
+		case mysql.TypeNewDecimal:
+			return &avgPartial4Decimal{baseAvgDecimal{base}}
 		case mysql.TypeDouble:
 			return &avgPartial4Float64{baseAvgFloat64{base}}
 		}
@@ -132,6 +214,14 @@ func buildFirstRow(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 		case mysql.TypeDouble:
 			return &firstRow4Float64{base}
 		}
+	case types.ETDecimal:
+		return &firstRow4Decimal{base}
+	case types.ETDatetime, types.ETTimestamp:
+		return &firstRow4Time{base}
+	case types.ETDuration:
+		return &firstRow4Duration{base}
+	case types.ETJson:
+		return &firstRow4JSON{base}
 	case types.ETString:
 		return &firstRow4String{base}
 	}
@@ -165,8 +255,142 @@ func buildMaxMin(aggFuncDesc *aggregation.AggFuncDesc, ordinal int, isMax bool)
 		case mysql.TypeDouble:
 			return &maxMin4Float64{base}
 		}
+	case types.ETDecimal:
+		return &maxMin4Decimal{base}
+	case types.ETDatetime, types.ETTimestamp:
+		return &maxMin4Time{base}
+	case types.ETDuration:
+		return &maxMin4Duration{base}
+	case types.ETJson:
+		return &maxMin4JSON{base}
 	case types.ETString:
 		return &maxMin4String{base}
 	}
 	return nil
 }
+
+// buildGroupConcat builds the AggFunc implementation for function "GROUP_CONCAT".
+func buildGroupConcat(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	// The last arg is promised to be a not-null string constant, so the error can be ignored.
+	c, _ := aggFuncDesc.Args[len(aggFuncDesc.Args)-1].(*expression.Constant)
+	sep, _, err := c.EvalString(nil, chunk.Row{})
+	// This err should never happen.
+	if err != nil {
+		panic(fmt.Sprintf("Error happened when buildGroupConcat: %s", err.Error()))
+	}
+	var s string
+	s, err = variable.GetSessionSystemVar(ctx.GetSessionVars(), variable.GroupConcatMaxLen)
+	if err != nil {
+		panic(fmt.Sprintf("Error happened when buildGroupConcat: no system variable named '%s'", variable.GroupConcatMaxLen))
+	}
+	maxLen, err := strconv.ParseUint(s, 10, 64)
+	// Should never happen
+	if err != nil {
+		panic(fmt.Sprintf("Error happened when buildGroupConcat: %s", err.Error()))
+	}
+	var truncated int32
+	base := baseGroupConcat4String{
+		baseAggFunc: baseAggFunc{
+			args:    aggFuncDesc.Args[:len(aggFuncDesc.Args)-1],
+			ordinal: ordinal,
+		},
+		byItems:   aggFuncDesc.OrderByItems,
+		sep:       sep,
+		maxLen:    maxLen,
+		truncated: &truncated,
+	}
+	if aggFuncDesc.HasDistinct || len(aggFuncDesc.OrderByItems) > 0 {
+		return &groupConcatDistinctOrder{baseGroupConcat4String: base, distinct: aggFuncDesc.HasDistinct}
+	}
+	return &groupConcat{base}
+}
+
+// buildBitOr builds the AggFunc implementation for function "BIT_OR".
+func buildBitOr(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	base := baseAggFunc{
+		args:    aggFuncDesc.Args,
+		ordinal: ordinal,
+	}
+	return &bitOrUint64{baseBitAggFunc{base}}
+}
+
+// buildBitXor builds the AggFunc implementation for function "BIT_XOR".
+func buildBitXor(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	base := baseAggFunc{
+		args:    aggFuncDesc.Args,
+		ordinal: ordinal,
+	}
+	return &bitXorUint64{baseBitAggFunc{base}}
+}
+
+// buildBitAnd builds the AggFunc implementation for function "BIT_AND".
+func buildBitAnd(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	base := baseAggFunc{
+		args:    aggFuncDesc.Args,
+		ordinal: ordinal,
+	}
+	return &bitAndUint64{baseBitAggFunc{base}}
+}
+
+// buildVariance builds the AggFunc implementation for function "VAR_POP",
+// "VAR_SAMP", "STDDEV_POP" and "STDDEV_SAMP".
+func buildVariance(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	base := baseVariance4Float64{
+		baseAggFunc: baseAggFunc{
+			args:    aggFuncDesc.Args,
+			ordinal: ordinal,
+		},
+		isSamp:   aggFuncDesc.Name == ast.AggFuncVarSamp || aggFuncDesc.Name == ast.AggFuncStddevSamp,
+		isStddev: aggFuncDesc.Name == ast.AggFuncStddevPop || aggFuncDesc.Name == ast.AggFuncStddevSamp,
+	}
+	switch aggFuncDesc.Mode {
+	case aggregation.CompleteMode, aggregation.Partial1Mode:
+		return &varianceOriginal4Float64{base}
+	case aggregation.Partial2Mode, aggregation.FinalMode:
+		return &variancePartial4Float64{base}
+	}
+	return nil
+}
+
+func buildRowNumber(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	base := baseAggFunc{
+		args:    aggFuncDesc.Args,
+		ordinal: ordinal,
+	}
+	return &rowNumber{base}
+}
+
+func buildRank(ordinal int, orderByCols []*expression.Column, isDense bool) AggFunc {
+	base := baseAggFunc{
+		ordinal: ordinal,
+	}
+	return &rank{baseAggFunc: base, isDense: isDense, rowComparer: buildRowComparer(orderByCols)}
+}
+
+func buildLeadLag(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal int) baseLeadLag {
+	offset := uint64(1)
+	if len(aggFuncDesc.Args) >= 2 {
+		offset, _, _ = expression.GetUint64FromConstant(aggFuncDesc.Args[1])
+	}
+	var defaultExpr expression.Expression
+	defaultExpr = expression.Null
+	if len(aggFuncDesc.Args) == 3 {
+		defaultExpr = aggFuncDesc.Args[2]
+		if defaultExpr.GetType().EvalType() != aggFuncDesc.RetTp.EvalType() {
+			defaultExpr = expression.BuildCastFunction(ctx, defaultExpr, aggFuncDesc.RetTp)
+		}
+	}
+	base := baseAggFunc{
+		args:    aggFuncDesc.Args,
+		ordinal: ordinal,
+	}
+	return baseLeadLag{baseAggFunc: base, offset: offset, defaultExpr: defaultExpr, valueEvaluator: buildValueEvaluator(aggFuncDesc.RetTp)}
+}
+
+func buildLead(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	return &lead{buildLeadLag(ctx, aggFuncDesc, ordinal)}
+}
+
+func buildLag(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	return &lag{buildLeadLag(ctx, aggFuncDesc, ordinal)}
+}
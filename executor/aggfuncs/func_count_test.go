@@ -15,8 +15,12 @@ package aggfuncs_test
 
 import (
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/expression/aggregation"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
 )
 
 func (s *testSuite) TestMergePartialResult4Count(c *C) {
@@ -35,3 +39,27 @@ func (s *testSuite) TestCount(c *C) {
 		s.testAggFunc(c, test)
 	}
 }
+
+func (s *testSuite) TestDistinct(c *C) {
+	ft := types.NewFieldType(mysql.TypeLonglong)
+	srcChk := chunk.NewChunkWithCapacity([]*types.FieldType{ft}, 6)
+	for _, v := range []interface{}{int64(1), int64(3), nil, int64(3), int64(1), int64(2)} {
+		d := types.NewDatum(v)
+		srcChk.AppendDatum(0, &d)
+	}
+	args := []expression.Expression{&expression.Column{RetType: ft, Index: 0}}
+	tests := []struct {
+		funcName string
+		expected types.Datum
+	}{
+		{ast.AggFuncCount, types.NewIntDatum(3)},
+		{ast.AggFuncSum, types.NewIntDatum(6)},
+		{ast.AggFuncBitXor, types.NewUintDatum(0)},
+		{ast.AggFuncMax, types.NewIntDatum(3)},
+	}
+	for _, test := range tests {
+		desc, err := aggregation.NewAggFuncDesc(s.ctx, test.funcName, args, true)
+		c.Assert(err, IsNil)
+		s.testAggFuncWithDesc(c, desc, srcChk, test.expected)
+	}
+}
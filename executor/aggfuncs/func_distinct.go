@@ -0,0 +1,123 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs
+
+import (
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/set"
+)
+
+// distinctAggFunc makes the wrapped aggregate function only aggregate the
+// distinct values of its arguments. The partial result keeps the distinct
+// values instead of aggregating them at once, so the partial results of
+// different workers can be merged without aggregating a value twice. The
+// wrapped function, whose arguments are the columns of the kept values,
+// aggregates them when the final result is required.
+type distinctAggFunc struct {
+	baseAggFunc
+	inner    AggFunc
+	fieldTps []*types.FieldType
+}
+
+type partialResult4Distinct struct {
+	valSet set.StringSet
+	keys   []string
+	rows   [][]types.Datum
+	keyBuf []byte
+}
+
+func (e *distinctAggFunc) AllocPartialResult() PartialResult {
+	p := new(partialResult4Distinct)
+	p.valSet = set.NewStringSet()
+	return PartialResult(p)
+}
+
+func (e *distinctAggFunc) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4Distinct)(pr)
+	p.valSet = set.NewStringSet()
+	p.keys = nil
+	p.rows = nil
+}
+
+func (e *distinctAggFunc) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (err error) {
+	p := (*partialResult4Distinct)(pr)
+	sc := sctx.GetSessionVars().StmtCtx
+	for _, row := range rowsInGroup {
+		vals := make([]types.Datum, 0, len(e.args))
+		isNull := false
+		for _, arg := range e.args {
+			d, err := arg.Eval(row)
+			if err != nil {
+				return err
+			}
+			// Null values are ignored by all the aggregate functions
+			// supporting distinct.
+			if d.IsNull() {
+				isNull = true
+				break
+			}
+			vals = append(vals, *d.Copy())
+		}
+		if isNull {
+			continue
+		}
+		p.keyBuf, err = codec.EncodeValue(sc, p.keyBuf[:0], vals...)
+		if err != nil {
+			return err
+		}
+		if p.valSet.Exist(string(p.keyBuf)) {
+			continue
+		}
+		key := string(p.keyBuf)
+		p.valSet.Insert(key)
+		p.keys = append(p.keys, key)
+		p.rows = append(p.rows, vals)
+	}
+	return nil
+}
+
+func (e *distinctAggFunc) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p1, p2 := (*partialResult4Distinct)(src), (*partialResult4Distinct)(dst)
+	for i, key := range p1.keys {
+		if p2.valSet.Exist(key) {
+			continue
+		}
+		p2.valSet.Insert(key)
+		p2.keys = append(p2.keys, key)
+		p2.rows = append(p2.rows, p1.rows[i])
+	}
+	return nil
+}
+
+func (e *distinctAggFunc) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4Distinct)(pr)
+	valChk := chunk.NewChunkWithCapacity(e.fieldTps, len(p.rows))
+	for _, vals := range p.rows {
+		for i := range vals {
+			valChk.AppendDatum(i, &vals[i])
+		}
+	}
+	rows := make([]chunk.Row, 0, len(p.rows))
+	for i := 0; i < valChk.NumRows(); i++ {
+		rows = append(rows, valChk.GetRow(i))
+	}
+	innerPr := e.inner.AllocPartialResult()
+	if err := e.inner.UpdatePartialResult(sctx, rows, innerPr); err != nil {
+		return err
+	}
+	return e.inner.AppendFinalResult2Chunk(sctx, innerPr, chk)
+}
@@ -0,0 +1,277 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs
+
+import (
+	"bytes"
+	"sort"
+	"sync/atomic"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/planner/util"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/set"
+)
+
+type baseGroupConcat4String struct {
+	baseAggFunc
+	byItems []*util.ByItems
+
+	sep    string
+	maxLen uint64
+	// According to MySQL, a 'group_concat' function generates exactly one
+	// 'truncated' warning during its life time, no matter how many groups are
+	// actually truncated. 'truncated' acts as a sentinel to indicate whether
+	// this warning has already been generated.
+	truncated *int32
+}
+
+func (e *baseGroupConcat4String) handleTruncateError(sctx sessionctx.Context) {
+	if atomic.CompareAndSwapInt32(e.truncated, 0, 1) {
+		sctx.GetSessionVars().StmtCtx.AppendWarning(expression.ErrCutValueGroupConcat.GenWithStackByArgs(e.args[0].String()))
+	}
+}
+
+func (e *baseGroupConcat4String) truncatePartialResultIfNeed(sctx sessionctx.Context, buffer *bytes.Buffer) {
+	if e.maxLen > 0 && uint64(buffer.Len()) > e.maxLen {
+		buffer.Truncate(int(e.maxLen))
+		e.handleTruncateError(sctx)
+	}
+}
+
+// evalValue concatenates the values of the arguments of the row into
+// valsBuf, it returns true if any of the values is null, which means the row
+// should be skipped.
+func (e *baseGroupConcat4String) evalValue(sctx sessionctx.Context, row chunk.Row, valsBuf *bytes.Buffer) (isNull bool, err error) {
+	valsBuf.Reset()
+	for _, arg := range e.args {
+		var v string
+		v, isNull, err = arg.EvalString(sctx, row)
+		if err != nil || isNull {
+			return isNull, err
+		}
+		valsBuf.WriteString(v)
+	}
+	return false, nil
+}
+
+type partialResult4GroupConcat struct {
+	valsBuf *bytes.Buffer
+	buffer  *bytes.Buffer
+}
+
+type groupConcat struct {
+	baseGroupConcat4String
+}
+
+func (e *groupConcat) AllocPartialResult() PartialResult {
+	p := new(partialResult4GroupConcat)
+	p.valsBuf = &bytes.Buffer{}
+	return PartialResult(p)
+}
+
+func (e *groupConcat) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4GroupConcat)(pr)
+	p.buffer = nil
+}
+
+func (e *groupConcat) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4GroupConcat)(pr)
+	for _, row := range rowsInGroup {
+		isNull, err := e.evalValue(sctx, row, p.valsBuf)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			continue
+		}
+		if p.buffer == nil {
+			p.buffer = &bytes.Buffer{}
+		} else {
+			p.buffer.WriteString(e.sep)
+		}
+		p.buffer.Write(p.valsBuf.Bytes())
+		e.truncatePartialResultIfNeed(sctx, p.buffer)
+	}
+	return nil
+}
+
+func (e *groupConcat) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p1, p2 := (*partialResult4GroupConcat)(src), (*partialResult4GroupConcat)(dst)
+	if p1.buffer == nil {
+		return nil
+	}
+	if p2.buffer == nil {
+		p2.buffer = &bytes.Buffer{}
+	} else {
+		p2.buffer.WriteString(e.sep)
+	}
+	p2.buffer.Write(p1.buffer.Bytes())
+	e.truncatePartialResultIfNeed(sctx, p2.buffer)
+	return nil
+}
+
+func (e *groupConcat) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4GroupConcat)(pr)
+	if p.buffer == nil {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	chk.AppendString(e.ordinal, p.buffer.String())
+	return nil
+}
+
+// groupConcatRow is a row kept by groupConcatDistinctOrder.
+type groupConcatRow struct {
+	// key is the encoded values of the arguments, which is only used to
+	// remove the duplicated rows.
+	key     string
+	val     string
+	byItems []types.Datum
+}
+
+type partialResult4GroupConcatDistinctOrder struct {
+	valsBuf *bytes.Buffer
+	keyBuf  []byte
+	valSet  set.StringSet
+	rows    []groupConcatRow
+}
+
+// groupConcatDistinctOrder is used when GROUP_CONCAT has DISTINCT or ORDER BY.
+// It keeps all the rows of a group until the final result is required, then
+// sorts the rows and concatenates them.
+type groupConcatDistinctOrder struct {
+	baseGroupConcat4String
+	distinct bool
+}
+
+func (e *groupConcatDistinctOrder) AllocPartialResult() PartialResult {
+	p := new(partialResult4GroupConcatDistinctOrder)
+	p.valsBuf = &bytes.Buffer{}
+	p.valSet = set.NewStringSet()
+	return PartialResult(p)
+}
+
+func (e *groupConcatDistinctOrder) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4GroupConcatDistinctOrder)(pr)
+	p.valSet = set.NewStringSet()
+	p.rows = nil
+}
+
+func (e *groupConcatDistinctOrder) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4GroupConcatDistinctOrder)(pr)
+	for _, row := range rowsInGroup {
+		r := groupConcatRow{}
+		p.valsBuf.Reset()
+		p.keyBuf = p.keyBuf[:0]
+		isNull := false
+		for _, arg := range e.args {
+			v, null, err := arg.EvalString(sctx, row)
+			if err != nil {
+				return err
+			}
+			if null {
+				isNull = true
+				break
+			}
+			p.valsBuf.WriteString(v)
+			if e.distinct {
+				p.keyBuf = codec.EncodeCompactBytes(p.keyBuf, []byte(v))
+			}
+		}
+		if isNull {
+			continue
+		}
+		if e.distinct {
+			if p.valSet.Exist(string(p.keyBuf)) {
+				continue
+			}
+			r.key = string(p.keyBuf)
+			p.valSet.Insert(r.key)
+		}
+		r.val = p.valsBuf.String()
+		r.byItems = make([]types.Datum, 0, len(e.byItems))
+		for _, item := range e.byItems {
+			d, err := item.Expr.Eval(row)
+			if err != nil {
+				return err
+			}
+			r.byItems = append(r.byItems, *d.Copy())
+		}
+		p.rows = append(p.rows, r)
+	}
+	return nil
+}
+
+func (e *groupConcatDistinctOrder) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p1, p2 := (*partialResult4GroupConcatDistinctOrder)(src), (*partialResult4GroupConcatDistinctOrder)(dst)
+	for _, r := range p1.rows {
+		if e.distinct {
+			if p2.valSet.Exist(r.key) {
+				continue
+			}
+			p2.valSet.Insert(r.key)
+		}
+		p2.rows = append(p2.rows, r)
+	}
+	return nil
+}
+
+func (e *groupConcatDistinctOrder) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4GroupConcatDistinctOrder)(pr)
+	if len(p.rows) == 0 {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	if len(e.byItems) > 0 {
+		sc := sctx.GetSessionVars().StmtCtx
+		var err error
+		sort.SliceStable(p.rows, func(i, j int) bool {
+			for k, item := range e.byItems {
+				cmp, cmpErr := p.rows[i].byItems[k].CompareDatum(sc, &p.rows[j].byItems[k])
+				if cmpErr != nil {
+					err = cmpErr
+					return false
+				}
+				if cmp == 0 {
+					continue
+				}
+				if item.Desc {
+					return cmp > 0
+				}
+				return cmp < 0
+			}
+			return false
+		})
+		if err != nil {
+			return err
+		}
+	}
+	buffer := &bytes.Buffer{}
+	for i, r := range p.rows {
+		if i > 0 {
+			buffer.WriteString(e.sep)
+		}
+		buffer.WriteString(r.val)
+		if e.maxLen > 0 && uint64(buffer.Len()) > e.maxLen {
+			break
+		}
+	}
+	e.truncatePartialResultIfNeed(sctx, buffer)
+	chk.AppendString(e.ordinal, buffer.String())
+	return nil
+}
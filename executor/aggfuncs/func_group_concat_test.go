@@ -0,0 +1,72 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs_test
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/expression/aggregation"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/planner/util"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func (s *testSuite) TestGroupConcat(c *C) {
+	ft := types.NewFieldType(mysql.TypeString)
+	srcChk := chunk.NewChunkWithCapacity([]*types.FieldType{ft}, 6)
+	for _, v := range []interface{}{"1", "3", nil, "3", "1", "2"} {
+		d := types.NewDatum(v)
+		srcChk.AppendDatum(0, &d)
+	}
+	col := &expression.Column{RetType: ft, Index: 0}
+	sep := &expression.Constant{Value: types.NewStringDatum("-"), RetType: types.NewFieldType(mysql.TypeString)}
+	tests := []struct {
+		distinct bool
+		byItems  []*util.ByItems
+		expected string
+	}{
+		{false, nil, "1-3-3-1-2"},
+		{true, nil, "1-3-2"},
+		{false, []*util.ByItems{{Expr: col, Desc: true}}, "3-3-2-1-1"},
+		{true, []*util.ByItems{{Expr: col}}, "1-2-3"},
+	}
+	for _, test := range tests {
+		desc, err := aggregation.NewAggFuncDesc(s.ctx, ast.AggFuncGroupConcat, []expression.Expression{col, sep}, test.distinct)
+		c.Assert(err, IsNil)
+		desc.OrderByItems = test.byItems
+		s.testAggFuncWithDesc(c, desc, srcChk, types.NewStringDatum(test.expected))
+	}
+}
+
+func (s *testSuite) TestGroupConcatTruncate(c *C) {
+	ft := types.NewFieldType(mysql.TypeString)
+	srcChk := chunk.NewChunkWithCapacity([]*types.FieldType{ft}, 3)
+	for _, v := range []string{"abc", "def", "ghi"} {
+		d := types.NewStringDatum(v)
+		srcChk.AppendDatum(0, &d)
+	}
+	col := &expression.Column{RetType: ft, Index: 0}
+	sep := &expression.Constant{Value: types.NewStringDatum(","), RetType: types.NewFieldType(mysql.TypeString)}
+	err := s.ctx.GetSessionVars().SetSystemVar("group_concat_max_len", "5")
+	c.Assert(err, IsNil)
+	defer func() {
+		c.Assert(s.ctx.GetSessionVars().SetSystemVar("group_concat_max_len", "1024"), IsNil)
+	}()
+	desc, err := aggregation.NewAggFuncDesc(s.ctx, ast.AggFuncGroupConcat, []expression.Expression{col, sep}, false)
+	c.Assert(err, IsNil)
+	s.testAggFuncWithDesc(c, desc, srcChk, types.NewStringDatum("abc,d"))
+	c.Assert(s.ctx.GetSessionVars().StmtCtx.WarningCount() > 0, IsTrue)
+}
@@ -0,0 +1,284 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs
+
+import (
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+type baseLeadLag struct {
+	baseAggFunc
+	valueEvaluator
+
+	defaultExpr expression.Expression
+	offset      uint64
+}
+
+type partialResult4LeadLag struct {
+	rows   []chunk.Row
+	curIdx uint64
+}
+
+func (v *baseLeadLag) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4LeadLag{})
+}
+
+func (v *baseLeadLag) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4LeadLag)(pr)
+	p.rows = p.rows[:0]
+	p.curIdx = 0
+}
+
+func (v *baseLeadLag) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4LeadLag)(pr)
+	p.rows = append(p.rows, rowsInGroup...)
+	return nil
+}
+
+type lead struct {
+	baseLeadLag
+}
+
+func (v *lead) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4LeadLag)(pr)
+	var err error
+	if p.curIdx+v.offset < uint64(len(p.rows)) {
+		err = v.evaluateRow(sctx, v.args[0], p.rows[p.curIdx+v.offset])
+	} else {
+		err = v.evaluateRow(sctx, v.defaultExpr, p.rows[p.curIdx])
+	}
+	if err != nil {
+		return err
+	}
+	v.appendResult(chk, v.ordinal)
+	p.curIdx++
+	return nil
+}
+
+type lag struct {
+	baseLeadLag
+}
+
+func (v *lag) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4LeadLag)(pr)
+	var err error
+	if p.curIdx >= v.offset {
+		err = v.evaluateRow(sctx, v.args[0], p.rows[p.curIdx-v.offset])
+	} else {
+		err = v.evaluateRow(sctx, v.defaultExpr, p.rows[p.curIdx])
+	}
+	if err != nil {
+		return err
+	}
+	v.appendResult(chk, v.ordinal)
+	p.curIdx++
+	return nil
+}
+
+// valueEvaluator is used to evaluate values for `lead` and `lag`.
+type valueEvaluator interface {
+	// evaluateRow evaluates the expression using row and stores the result inside.
+	evaluateRow(ctx sessionctx.Context, expr expression.Expression, row chunk.Row) error
+	// appendResult appends the result to chunk.
+	appendResult(chk *chunk.Chunk, colIdx int)
+}
+
+type value4Int struct {
+	val    int64
+	isNull bool
+}
+
+func (v *value4Int) evaluateRow(ctx sessionctx.Context, expr expression.Expression, row chunk.Row) error {
+	var err error
+	v.val, v.isNull, err = expr.EvalInt(ctx, row)
+	return err
+}
+
+func (v *value4Int) appendResult(chk *chunk.Chunk, colIdx int) {
+	if v.isNull {
+		chk.AppendNull(colIdx)
+	} else {
+		chk.AppendInt64(colIdx, v.val)
+	}
+}
+
+type value4Float32 struct {
+	val    float32
+	isNull bool
+}
+
+func (v *value4Float32) evaluateRow(ctx sessionctx.Context, expr expression.Expression, row chunk.Row) error {
+	var val float64
+	var err error
+	val, v.isNull, err = expr.EvalReal(ctx, row)
+	v.val = float32(val)
+	return err
+}
+
+func (v *value4Float32) appendResult(chk *chunk.Chunk, colIdx int) {
+	if v.isNull {
+		chk.AppendNull(colIdx)
+	} else {
+		chk.AppendFloat32(colIdx, v.val)
+	}
+}
+
+type value4Float64 struct {
+	val    float64
+	isNull bool
+}
+
+func (v *value4Float64) evaluateRow(ctx sessionctx.Context, expr expression.Expression, row chunk.Row) error {
+	var err error
+	v.val, v.isNull, err = expr.EvalReal(ctx, row)
+	return err
+}
+
+func (v *value4Float64) appendResult(chk *chunk.Chunk, colIdx int) {
+	if v.isNull {
+		chk.AppendNull(colIdx)
+	} else {
+		chk.AppendFloat64(colIdx, v.val)
+	}
+}
+
+type value4Decimal struct {
+	val    *types.MyDecimal
+	isNull bool
+}
+
+func (v *value4Decimal) evaluateRow(ctx sessionctx.Context, expr expression.Expression, row chunk.Row) error {
+	var err error
+	v.val, v.isNull, err = expr.EvalDecimal(ctx, row)
+	return err
+}
+
+func (v *value4Decimal) appendResult(chk *chunk.Chunk, colIdx int) {
+	if v.isNull {
+		chk.AppendNull(colIdx)
+	} else {
+		chk.AppendMyDecimal(colIdx, v.val)
+	}
+}
+
+type value4Time struct {
+	val    types.Time
+	isNull bool
+}
+
+func (v *value4Time) evaluateRow(ctx sessionctx.Context, expr expression.Expression, row chunk.Row) error {
+	var err error
+	v.val, v.isNull, err = expr.EvalTime(ctx, row)
+	return err
+}
+
+func (v *value4Time) appendResult(chk *chunk.Chunk, colIdx int) {
+	if v.isNull {
+		chk.AppendNull(colIdx)
+	} else {
+		chk.AppendTime(colIdx, v.val)
+	}
+}
+
+type value4Duration struct {
+	val    types.Duration
+	isNull bool
+}
+
+func (v *value4Duration) evaluateRow(ctx sessionctx.Context, expr expression.Expression, row chunk.Row) error {
+	var err error
+	v.val, v.isNull, err = expr.EvalDuration(ctx, row)
+	return err
+}
+
+func (v *value4Duration) appendResult(chk *chunk.Chunk, colIdx int) {
+	if v.isNull {
+		chk.AppendNull(colIdx)
+	} else {
+		chk.AppendDuration(colIdx, v.val)
+	}
+}
+
+type value4String struct {
+	val    string
+	isNull bool
+}
+
+func (v *value4String) evaluateRow(ctx sessionctx.Context, expr expression.Expression, row chunk.Row) error {
+	var err error
+	v.val, v.isNull, err = expr.EvalString(ctx, row)
+	return err
+}
+
+func (v *value4String) appendResult(chk *chunk.Chunk, colIdx int) {
+	if v.isNull {
+		chk.AppendNull(colIdx)
+	} else {
+		chk.AppendString(colIdx, v.val)
+	}
+}
+
+type value4JSON struct {
+	val    json.BinaryJSON
+	isNull bool
+}
+
+func (v *value4JSON) evaluateRow(ctx sessionctx.Context, expr expression.Expression, row chunk.Row) error {
+	var err error
+	v.val, v.isNull, err = expr.EvalJSON(ctx, row)
+	v.val = v.val.Copy() // deep copy to avoid content change.
+	return err
+}
+
+func (v *value4JSON) appendResult(chk *chunk.Chunk, colIdx int) {
+	if v.isNull {
+		chk.AppendNull(colIdx)
+	} else {
+		chk.AppendJSON(colIdx, v.val)
+	}
+}
+
+func buildValueEvaluator(tp *types.FieldType) valueEvaluator {
+	evalType := tp.EvalType()
+	if tp.Tp == mysql.TypeBit {
+		evalType = types.ETString
+	}
+	switch evalType {
+	case types.ETInt:
+		return &value4Int{}
+	case types.ETReal:
+		switch tp.Tp {
+		case mysql.TypeFloat:
+			return &value4Float32{}
+		case mysql.TypeDouble:
+			return &value4Float64{}
+		}
+	case types.ETDecimal:
+		return &value4Decimal{}
+	case types.ETDatetime, types.ETTimestamp:
+		return &value4Time{}
+	case types.ETDuration:
+		return &value4Duration{}
+	case types.ETString:
+		return &value4String{}
+	case types.ETJson:
+		return &value4JSON{}
+	}
+	return nil
+}
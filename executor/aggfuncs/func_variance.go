@@ -0,0 +1,162 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs
+
+import (
+	"math"
+
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// All the following functions compute var_pop, var_samp, stddev_pop and
+// stddev_samp, which store the partial results in "partialResult4Variance".
+//
+// "baseVariance4Float64" is wrapped by:
+// - "varianceOriginal4Float64"
+// - "variancePartial4Float64"
+type baseVariance4Float64 struct {
+	baseAggFunc
+	// isSamp indicates whether the function computes the sample variance
+	// instead of the population variance.
+	isSamp bool
+	// isStddev indicates whether the function returns the square root of the
+	// variance.
+	isStddev bool
+}
+
+// partialResult4Variance stores the count and the sum of the input values, and
+// the sum of squared differences from their mean, which can be updated and
+// merged without losing precision.
+type partialResult4Variance struct {
+	count    int64
+	sum      float64
+	variance float64
+}
+
+// calculateIntermediate returns the variance after `input` is added, `count`
+// and `sum` have already taken `input` into account.
+func calculateIntermediate(count int64, sum float64, input float64, variance float64) float64 {
+	t := float64(count)*input - sum
+	variance += (t * t) / (float64(count * (count - 1)))
+	return variance
+}
+
+// calculateMerge returns the variance of the union of two groups of values.
+func calculateMerge(srcCount, dstCount int64, srcSum, dstSum, srcVariance, dstVariance float64) float64 {
+	srcCountFloat64 := float64(srcCount)
+	dstCountFloat64 := float64(dstCount)
+
+	t := (srcCountFloat64/dstCountFloat64)*dstSum - srcSum
+	dstVariance += srcVariance + ((dstCountFloat64/srcCountFloat64)/(dstCountFloat64+srcCountFloat64))*t*t
+	return dstVariance
+}
+
+func (e *baseVariance4Float64) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4Variance{})
+}
+
+func (e *baseVariance4Float64) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4Variance)(pr)
+	p.count = 0
+	p.sum = 0
+	p.variance = 0
+}
+
+func (e *baseVariance4Float64) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4Variance)(pr)
+	count := p.count
+	if e.isSamp {
+		count--
+	}
+	if count <= 0 {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	result := p.variance / float64(count)
+	if e.isStddev {
+		result = math.Sqrt(result)
+	}
+	chk.AppendFloat64(e.ordinal, result)
+	return nil
+}
+
+func (e *baseVariance4Float64) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p1, p2 := (*partialResult4Variance)(src), (*partialResult4Variance)(dst)
+	if p1.count == 0 {
+		return nil
+	}
+	if p2.count == 0 {
+		*p2 = *p1
+		return nil
+	}
+	p2.variance = calculateMerge(p1.count, p2.count, p1.sum, p2.sum, p1.variance, p2.variance)
+	p2.count += p1.count
+	p2.sum += p1.sum
+	return nil
+}
+
+type varianceOriginal4Float64 struct {
+	baseVariance4Float64
+}
+
+func (e *varianceOriginal4Float64) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4Variance)(pr)
+	for _, row := range rowsInGroup {
+		input, isNull, err := e.args[0].EvalReal(sctx, row)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			continue
+		}
+		p.count++
+		p.sum += input
+		if p.count > 1 {
+			p.variance = calculateIntermediate(p.count, p.sum, input, p.variance)
+		}
+	}
+	return nil
+}
+
+// variancePartial4Float64 consumes the partial results of other variance
+// functions, whose arguments are the count, the sum and the variance.
+type variancePartial4Float64 struct {
+	baseVariance4Float64
+}
+
+func (e *variancePartial4Float64) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	for _, row := range rowsInGroup {
+		count, isNull, err := e.args[0].EvalInt(sctx, row)
+		if err != nil {
+			return err
+		}
+		if isNull || count == 0 {
+			continue
+		}
+		sum, _, err := e.args[1].EvalReal(sctx, row)
+		if err != nil {
+			return err
+		}
+		variance, _, err := e.args[2].EvalReal(sctx, row)
+		if err != nil {
+			return err
+		}
+		partial := partialResult4Variance{count: count, sum: sum, variance: variance}
+		if err = e.MergePartialResult(sctx, PartialResult(&partial), pr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs_test
+
+import (
+	"math"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+)
+
+func (s *testSuite) TestMergePartialResult4Variance(c *C) {
+	tests := []aggTest{
+		buildAggTester(ast.AggFuncVarPop, mysql.TypeDouble, 5, 2.0, 2.0/3, 1.734375),
+		buildAggTester(ast.AggFuncVarSamp, mysql.TypeDouble, 5, 2.5, 1.0, 13.875/7),
+	}
+	for _, test := range tests {
+		s.testMergePartialResult(c, test)
+	}
+}
+
+func (s *testSuite) TestVariance(c *C) {
+	tests := []aggTest{
+		buildAggTester(ast.AggFuncVarPop, mysql.TypeDouble, 5, nil, 2.0),
+		buildAggTester(ast.AggFuncVarSamp, mysql.TypeLonglong, 5, nil, 2.5),
+		buildAggTester(ast.AggFuncStddevPop, mysql.TypeDouble, 5, nil, math.Sqrt(2)),
+		buildAggTester(ast.AggFuncStddevSamp, mysql.TypeDouble, 5, nil, math.Sqrt(2.5)),
+	}
+	for _, test := range tests {
+		s.testAggFunc(c, test)
+	}
+}
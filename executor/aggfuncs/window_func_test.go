@@ -0,0 +1,104 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs_test
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/executor/aggfuncs"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/expression/aggregation"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+type windowTest struct {
+	dataType    *types.FieldType
+	numRows     int
+	dataGen     func(i int) types.Datum
+	funcName    string
+	args        []expression.Expression
+	orderByCols []*expression.Column
+	results     []types.Datum
+}
+
+func buildWindowTester(funcName string, numRows int, dataGen func(i int) types.Datum, args []expression.Expression, results ...interface{}) windowTest {
+	tp := types.NewFieldType(mysql.TypeLonglong)
+	pt := windowTest{
+		dataType:    tp,
+		numRows:     numRows,
+		dataGen:     dataGen,
+		funcName:    funcName,
+		args:        args,
+		orderByCols: []*expression.Column{{RetType: tp, Index: 0}},
+	}
+	for _, result := range results {
+		pt.results = append(pt.results, types.NewDatum(result))
+	}
+	return pt
+}
+
+func (s *testSuite) testWindowFunc(c *C, p windowTest) {
+	srcChk := chunk.NewChunkWithCapacity([]*types.FieldType{p.dataType}, p.numRows)
+	for i := 0; i < p.numRows; i++ {
+		dt := p.dataGen(i)
+		srcChk.AppendDatum(0, &dt)
+	}
+
+	desc, err := aggregation.NewAggFuncDesc(s.ctx, p.funcName, p.args, false)
+	c.Assert(err, IsNil)
+	finalFunc := aggfuncs.BuildWindowFunctions(s.ctx, desc, 0, p.orderByCols)
+	finalPr := finalFunc.AllocPartialResult()
+	resultChk := chunk.NewChunkWithCapacity([]*types.FieldType{desc.RetTp}, 1)
+
+	iter := chunk.NewIterator4Chunk(srcChk)
+	for row := iter.Begin(); row != iter.End(); row = iter.Next() {
+		err = finalFunc.UpdatePartialResult(s.ctx, []chunk.Row{row}, finalPr)
+		c.Assert(err, IsNil)
+	}
+
+	c.Assert(p.results, HasLen, p.numRows)
+	for i := 0; i < p.numRows; i++ {
+		err = finalFunc.AppendFinalResult2Chunk(s.ctx, finalPr, resultChk)
+		c.Assert(err, IsNil)
+		dt := resultChk.GetRow(0).GetDatum(0, desc.RetTp)
+		result, err := dt.CompareDatum(s.ctx.GetSessionVars().StmtCtx, &p.results[i])
+		c.Assert(err, IsNil)
+		c.Assert(result, Equals, 0, Commentf("%s: row %d", p.funcName, i))
+		resultChk.Reset()
+	}
+}
+
+func (s *testSuite) TestWindowFunctions(c *C) {
+	intTp := types.NewFieldType(mysql.TypeLonglong)
+	col := &expression.Column{RetType: intTp, Index: 0}
+	distinct := func(i int) types.Datum { return types.NewIntDatum(int64(i)) }
+	paired := func(i int) types.Datum { return types.NewIntDatum(int64(i / 2)) }
+	offset := &expression.Constant{Value: types.NewIntDatum(2), RetType: intTp}
+	dflt := &expression.Constant{Value: types.NewIntDatum(-1), RetType: intTp}
+
+	tests := []windowTest{
+		buildWindowTester(ast.WindowFuncRowNumber, 4, paired, nil, 1, 2, 3, 4),
+		buildWindowTester(ast.WindowFuncRank, 5, paired, nil, 1, 1, 3, 3, 5),
+		buildWindowTester(ast.WindowFuncDenseRank, 5, paired, nil, 1, 1, 2, 2, 3),
+		buildWindowTester(ast.WindowFuncLead, 4, distinct, []expression.Expression{col}, 1, 2, 3, nil),
+		buildWindowTester(ast.WindowFuncLead, 4, distinct, []expression.Expression{col, offset, dflt}, 2, 3, -1, -1),
+		buildWindowTester(ast.WindowFuncLag, 4, distinct, []expression.Expression{col}, nil, 0, 1, 2),
+		buildWindowTester(ast.WindowFuncLag, 4, distinct, []expression.Expression{col, offset, dflt}, -1, -1, 0, 1),
+	}
+	for _, test := range tests {
+		s.testWindowFunc(c, test)
+	}
+}
@@ -638,3 +638,210 @@ func (e *HashAggExec) parallelExec(ctx context.Context, chk *chunk.Chunk) error
 		}
 	}
 }
+
+// StreamAggExec deals with all the aggregate functions.
+// It assumes all the input data is sorted by group by key.
+// When Next() is called, it will return a result for the same group.
+type StreamAggExec struct {
+	baseExecutor
+
+	executed bool
+	// isChildReturnEmpty indicates whether the child executor only returns an empty input.
+	isChildReturnEmpty bool
+	defaultVal         *chunk.Chunk
+	groupChecker       *groupChecker
+	inputIter          *chunk.Iterator4Chunk
+	inputRow           chunk.Row
+	aggFuncs           []aggfuncs.AggFunc
+	partialResults     []aggfuncs.PartialResult
+	groupRows          []chunk.Row
+	childResult        *chunk.Chunk
+}
+
+// Open implements the Executor Open interface.
+func (e *StreamAggExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.childResult = newFirstChunk(e.children[0])
+	e.executed = false
+	e.isChildReturnEmpty = true
+	e.inputIter = chunk.NewIterator4Chunk(e.childResult)
+	e.inputRow = e.inputIter.End()
+	e.groupChecker.reset()
+
+	e.partialResults = make([]aggfuncs.PartialResult, 0, len(e.aggFuncs))
+	for _, aggFunc := range e.aggFuncs {
+		e.partialResults = append(e.partialResults, aggFunc.AllocPartialResult())
+	}
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *StreamAggExec) Close() error {
+	e.childResult = nil
+	e.groupRows = nil
+	return e.baseExecutor.Close()
+}
+
+// Next implements the Executor Next interface.
+func (e *StreamAggExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for !e.executed && !req.IsFull() {
+		err := e.consumeOneGroup(ctx, req)
+		if err != nil {
+			e.executed = true
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *StreamAggExec) consumeOneGroup(ctx context.Context, chk *chunk.Chunk) error {
+	for !e.executed {
+		if err := e.fetchChildIfNecessary(ctx, chk); err != nil {
+			return err
+		}
+		for ; e.inputRow != e.inputIter.End(); e.inputRow = e.inputIter.Next() {
+			meetNewGroup, err := e.groupChecker.meetNewGroup(e.inputRow)
+			if err != nil {
+				return err
+			}
+			if meetNewGroup {
+				err := e.consumeGroupRows()
+				if err != nil {
+					return err
+				}
+				err = e.appendResult2Chunk(chk)
+				if err != nil {
+					return err
+				}
+			}
+			e.groupRows = append(e.groupRows, e.inputRow)
+			if meetNewGroup {
+				e.inputRow = e.inputIter.Next()
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func (e *StreamAggExec) consumeGroupRows() error {
+	if len(e.groupRows) == 0 {
+		return nil
+	}
+
+	for i, aggFunc := range e.aggFuncs {
+		err := aggFunc.UpdatePartialResult(e.ctx, e.groupRows, e.partialResults[i])
+		if err != nil {
+			return err
+		}
+	}
+	e.groupRows = e.groupRows[:0]
+	return nil
+}
+
+func (e *StreamAggExec) fetchChildIfNecessary(ctx context.Context, chk *chunk.Chunk) (err error) {
+	if e.inputRow != e.inputIter.End() {
+		return nil
+	}
+
+	// Before fetching a new batch of input, we should consume the last group,
+	// because the rows of the group are held by the chunk to be reused.
+	err = e.consumeGroupRows()
+	if err != nil {
+		return err
+	}
+
+	err = Next(ctx, e.children[0], e.childResult)
+	if err != nil {
+		return err
+	}
+
+	// No more data.
+	if e.childResult.NumRows() == 0 {
+		if !e.isChildReturnEmpty {
+			err = e.appendResult2Chunk(chk)
+		} else if e.defaultVal != nil {
+			chk.Append(e.defaultVal, 0, 1)
+		}
+		e.executed = true
+		return err
+	}
+	// Reach here, "e.childResult.NumRows() > 0" is guaranteed.
+	e.isChildReturnEmpty = false
+	e.inputRow = e.inputIter.Begin()
+	return nil
+}
+
+// appendResult2Chunk appends result of all the aggregation functions to the
+// result chunk, and reset the evaluation context for each aggregation.
+func (e *StreamAggExec) appendResult2Chunk(chk *chunk.Chunk) error {
+	for i, aggFunc := range e.aggFuncs {
+		err := aggFunc.AppendFinalResult2Chunk(e.ctx, e.partialResults[i], chk)
+		if err != nil {
+			return err
+		}
+		aggFunc.ResetPartialResult(e.partialResults[i])
+	}
+	if len(e.aggFuncs) == 0 {
+		chk.SetNumVirtualRows(chk.NumRows() + 1)
+	}
+	return nil
+}
+
+// groupChecker checks whether the rows read in order belong to the group of
+// the previous rows.
+type groupChecker struct {
+	StmtCtx      *stmtctx.StatementContext
+	GroupByItems []expression.Expression
+	curGroupKey  []types.Datum
+	tmpGroupKey  []types.Datum
+}
+
+func newGroupChecker(stmtCtx *stmtctx.StatementContext, items []expression.Expression) *groupChecker {
+	return &groupChecker{
+		StmtCtx:      stmtCtx,
+		GroupByItems: items,
+	}
+}
+
+// meetNewGroup returns a value that represents if the new group is different from last group.
+func (e *groupChecker) meetNewGroup(row chunk.Row) (bool, error) {
+	if len(e.GroupByItems) == 0 {
+		return false, nil
+	}
+	e.tmpGroupKey = e.tmpGroupKey[:0]
+	matched, firstGroup := true, false
+	if len(e.curGroupKey) == 0 {
+		matched, firstGroup = false, true
+	}
+	for i, item := range e.GroupByItems {
+		v, err := item.Eval(row)
+		if err != nil {
+			return false, err
+		}
+		if matched {
+			c, err := v.CompareDatum(e.StmtCtx, &e.curGroupKey[i])
+			if err != nil {
+				return false, err
+			}
+			matched = c == 0
+		}
+		e.tmpGroupKey = append(e.tmpGroupKey, v)
+	}
+	if matched {
+		return false, nil
+	}
+	e.curGroupKey = e.curGroupKey[:0]
+	for _, v := range e.tmpGroupKey {
+		e.curGroupKey = append(e.curGroupKey, *((&v).Copy()))
+	}
+	return !firstGroup, nil
+}
+
+func (e *groupChecker) reset() {
+	e.curGroupKey = e.curGroupKey[:0]
+	e.tmpGroupKey = e.tmpGroupKey[:0]
+}
@@ -97,3 +97,84 @@ func (s *testSuiteAgg) TestInjectProjBelowTopN(c *C) {
 		tk.MustQuery(tt).Check(testkit.Rows(output[i]...))
 	}
 }
+
+func (s *testSuiteAgg) TestAggDecimal(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b decimal(10,2))")
+	tk.MustQuery("select sum(b), avg(b), max(b), count(b) from t").Check(testkit.Rows("<nil> <nil> <nil> 0"))
+	tk.MustExec("insert into t values(1, 1.1), (1, 2.25), (2, -0.5), (2, NULL), (3, 100)")
+	tk.MustQuery("select a, sum(b), avg(b) from t group by a order by a").Check(testkit.Rows(
+		"1 3.35 1.675000",
+		"2 -0.50 -0.500000",
+		"3 100.00 100.000000",
+	))
+	tk.MustQuery("select sum(b), avg(b), max(b), min(b), count(b) from t").Check(testkit.Rows("102.85 25.712500 100.00 -0.50 4"))
+	tk.MustQuery("select sum(b+0.001) from t where a = 1").Check(testkit.Rows("3.352"))
+}
+
+func (s *testSuiteAgg) TestGroupConcatAggr(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int, name varchar(20))")
+	tk.MustQuery("select group_concat(name) from t").Check(testkit.Rows("<nil>"))
+	tk.MustExec("insert into t values(1, 'a'), (2, 'b'), (1, 'c'), (2, NULL), (1, 'a'), (3, 'd')")
+	tk.MustQuery("select id, group_concat(name order by name) from t group by id order by id").Check(testkit.Rows(
+		"1 a,a,c", "2 b", "3 d"))
+	tk.MustQuery("select group_concat(distinct name order by name desc separator '|') from t").Check(testkit.Rows("d|c|b|a"))
+	tk.MustQuery("select group_concat(id, name order by id, name) from t where id < 3").Check(testkit.Rows("1a,1a,1c,2b"))
+	tk.MustQuery("select group_concat(distinct id order by id separator '') from t").Check(testkit.Rows("123"))
+
+	tk.MustExec("set @@session.group_concat_max_len = 5")
+	tk.MustQuery("select group_concat(name order by name) from t").Check(testkit.Rows("a,a,b"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1260 Some rows were cut by GROUPCONCAT(test.t.name)"))
+}
+
+func (s *testSuiteAgg) TestDistinctAggr(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int)")
+	tk.MustExec("insert into t values(1, 1), (1, 1), (1, 2), (2, NULL), (2, 3), (2, 3)")
+	tk.MustQuery("select count(distinct b), sum(distinct b), avg(distinct b) from t").Check(testkit.Rows("3 6 2"))
+	tk.MustQuery("select a, count(distinct b), sum(distinct b) from t group by a order by a").Check(testkit.Rows(
+		"1 2 3", "2 1 3"))
+	tk.MustQuery("select count(distinct a, b) from t").Check(testkit.Rows("3"))
+	tk.MustQuery("select count(distinct b), count(b) from t where a > 5").Check(testkit.Rows("0 0"))
+}
+
+func (s *testSuiteAgg) TestBitAndOrXor(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int)")
+	tk.MustQuery("select bit_and(b), bit_or(b), bit_xor(b) from t").Check(testkit.Rows("18446744073709551615 0 0"))
+	tk.MustExec("insert into t values(1, 7), (1, 5), (1, NULL), (2, 12), (2, 10)")
+	tk.MustQuery("select a, bit_and(b), bit_or(b), bit_xor(b) from t group by a order by a").Check(testkit.Rows(
+		"1 5 7 2", "2 8 14 6"))
+	tk.MustQuery("select bit_and(b), bit_or(b), bit_xor(b) from t").Check(testkit.Rows("0 15 4"))
+}
+
+func (s *testSuiteAgg) TestVarianceAggr(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int)")
+	tk.MustQuery("select var_pop(b), var_samp(b), stddev_pop(b), stddev_samp(b) from t").Check(testkit.Rows("<nil> <nil> <nil> <nil>"))
+	tk.MustExec("insert into t values(1, 2), (1, 4), (1, 4), (1, 4), (2, 5), (2, 5), (2, 7), (2, 9), (3, 1), (3, NULL)")
+	tk.MustQuery("select var_pop(b), variance(b), std(b), stddev(b), stddev_pop(b) from t where a < 3").Check(testkit.Rows("4 4 2 2 2"))
+	tk.MustQuery("select a, var_pop(b), var_samp(b) from t group by a order by a").Check(testkit.Rows(
+		"1 0.75 1", "2 2.75 3.6666666666666665", "3 0 <nil>"))
+}
+
+func (s *testSuiteAgg) TestStreamAgg(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, index idx(a))")
+	tk.MustQuery("select count(b), sum(b) from t").Check(testkit.Rows("0 <nil>"))
+	tk.MustQuery("select a, count(b) from t group by a order by a").Check(testkit.Rows())
+	tk.MustExec("insert into t values(3, 1), (1, 2), (2, NULL), (1, 3), (3, 4), (NULL, 5), (1, 6), (2, 7)")
+	// Make the rows of a group span several chunks.
+	tk.MustExec("set @@tidb_init_chunk_size=1")
+	tk.MustQuery("select a, count(b), sum(b), max(b) from t group by a order by a").Check(testkit.Rows(
+		"<nil> 1 5 5", "1 3 11 6", "2 1 7 7", "3 2 5 4"))
+	tk.MustQuery("select a, count(*) from t group by a order by a desc limit 2").Check(testkit.Rows("3 2", "2 2"))
+	tk.MustQuery("select count(a), sum(a) from t").Check(testkit.Rows("7 13"))
+}
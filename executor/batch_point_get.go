@@ -0,0 +1,181 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/model"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func (b *executorBuilder) buildBatchPointGet(p *plannercore.BatchPointGetPlan) Executor {
+	handles := p.Handles
+	if p.InvalidHandles != nil {
+		handles = make([]int64, 0, len(p.Handles))
+		for i, handle := range p.Handles {
+			if !p.InvalidHandles[i] {
+				handles = append(handles, handle)
+			}
+		}
+	}
+	return &BatchPointGetExec{
+		baseExecutor: newBaseExecutor(b.ctx, p.Schema(), p.ExplainID()),
+		tblInfo:      p.TblInfo,
+		idxInfo:      p.IndexInfo,
+		idxVals:      p.IndexValues,
+		handles:      handles,
+		columns:      pointGetColumns(p.TblInfo, p.Schema()),
+	}
+}
+
+// BatchPointGetExec executes a bunch of point select queries.
+// The keys are read from the storage in batches first, then each key is read
+// through the union store of the transaction, so the uncommitted changes of
+// the transaction are visible to it.
+type BatchPointGetExec struct {
+	baseExecutor
+
+	tblInfo *model.TableInfo
+	idxInfo *model.IndexInfo
+	handles []int64
+	idxVals [][]types.Datum
+	columns []*table.Column
+
+	prepared   bool
+	rowHandles []int64
+	values     [][]byte
+	index      int
+}
+
+// Open implements the Executor interface.
+func (e *BatchPointGetExec) Open(context.Context) error {
+	e.prepared = false
+	e.rowHandles = nil
+	e.values = nil
+	e.index = 0
+	return nil
+}
+
+// Close implements the Executor interface.
+func (e *BatchPointGetExec) Close() error {
+	return nil
+}
+
+// Next implements the Executor interface.
+func (e *BatchPointGetExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if !e.prepared {
+		if err := e.initialize(ctx); err != nil {
+			return err
+		}
+		e.prepared = true
+	}
+	for !req.IsFull() && e.index < len(e.values) {
+		err := decodeRowValToChunk(e.ctx, e.tblInfo, e.columns, e.rowHandles[e.index], e.values[e.index], req)
+		if err != nil {
+			return err
+		}
+		e.index++
+	}
+	return nil
+}
+
+func (e *BatchPointGetExec) initialize(ctx context.Context) error {
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+
+	var handles []int64
+	if e.idxInfo != nil {
+		dedup := make(map[string]struct{}, len(e.idxVals))
+		idxKeys := make([]kv.Key, 0, len(e.idxVals))
+		for _, idxVals := range e.idxVals {
+			idxKey, ok, err := encodeUniqueIndexKey(e.ctx, e.tblInfo, e.idxInfo, idxVals)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			if _, found := dedup[string(idxKey)]; found {
+				continue
+			}
+			dedup[string(idxKey)] = struct{}{}
+			idxKeys = append(idxKeys, idxKey)
+		}
+		// Fill the snapshot cache of the transaction, then read the keys through the union store.
+		if _, err = txn.GetSnapshot().BatchGet(ctx, idxKeys); err != nil {
+			return err
+		}
+		handles = make([]int64, 0, len(idxKeys))
+		for _, idxKey := range idxKeys {
+			handleVal, err := getValueFromTxn(ctx, txn, idxKey)
+			if err != nil {
+				return err
+			}
+			if len(handleVal) == 0 {
+				continue
+			}
+			handle, err := tables.DecodeHandle(handleVal)
+			if err != nil {
+				return err
+			}
+			handles = append(handles, handle)
+		}
+	} else {
+		dedup := make(map[int64]struct{}, len(e.handles))
+		handles = make([]int64, 0, len(e.handles))
+		for _, handle := range e.handles {
+			if _, found := dedup[handle]; found {
+				continue
+			}
+			dedup[handle] = struct{}{}
+			handles = append(handles, handle)
+		}
+	}
+
+	rowKeys := make([]kv.Key, len(handles))
+	for i, handle := range handles {
+		rowKeys[i] = tablecodec.EncodeRowKeyWithHandle(e.tblInfo.ID, handle)
+	}
+	if _, err = txn.GetSnapshot().BatchGet(ctx, rowKeys); err != nil {
+		return err
+	}
+	e.rowHandles = make([]int64, 0, len(handles))
+	e.values = make([][]byte, 0, len(handles))
+	for i, rowKey := range rowKeys {
+		rowVal, err := getValueFromTxn(ctx, txn, rowKey)
+		if err != nil {
+			return err
+		}
+		if len(rowVal) == 0 {
+			if e.idxInfo != nil {
+				return kv.ErrNotExist.GenWithStack("inconsistent extra index %s, handle %d not found in table",
+					e.idxInfo.Name.O, handles[i])
+			}
+			continue
+		}
+		e.rowHandles = append(e.rowHandles, handles[i])
+		e.values = append(e.values, rowVal)
+	}
+	return nil
+}
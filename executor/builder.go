@@ -14,6 +14,7 @@
 package executor
 
 import (
+	"bytes"
 	"context"
 	"math"
 	"sort"
@@ -29,6 +30,7 @@ import (
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/expression/aggregation"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	plannercore "github.com/pingcap/tidb/planner/core"
@@ -37,6 +39,7 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/admin"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/ranger"
 	"github.com/pingcap/tipb/go-tipb"
 )
 
@@ -48,6 +51,11 @@ type executorBuilder struct {
 	startTS uint64 // cached when the first time getStartTS() is called
 	// err is set when there is error happened during Executor building process.
 	err error
+	// cteStorages maps the storage id of a common table expression to its storage,
+	// so all the references to the common table expression share the result.
+	cteStorages map[int]*cteStorage
+	// isSelectForUpdate is set when a `SELECT ... FOR UPDATE` is built.
+	isSelectForUpdate bool
 }
 
 func newExecutorBuilder(ctx sessionctx.Context, is infoschema.InfoSchema) *executorBuilder {
@@ -70,14 +78,22 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 		return nil
 	case *plannercore.DDL:
 		return b.buildDDL(v)
+	case *plannercore.Deallocate:
+		return b.buildDeallocate(v)
 	case *plannercore.Delete:
 		return b.buildDelete(v)
+	case *plannercore.Update:
+		return b.buildUpdate(v)
+	case *plannercore.Execute:
+		return b.buildExecute(v)
 	case *plannercore.Explain:
 		return b.buildExplain(v)
 	case *plannercore.Insert:
 		return b.buildInsert(v)
 	case *plannercore.PhysicalLimit:
 		return b.buildLimit(v)
+	case *plannercore.Prepare:
+		return b.buildPrepare(v)
 	case *plannercore.ShowDDL:
 		return b.buildShowDDL(v)
 	case *plannercore.PhysicalShowDDLJobs:
@@ -98,16 +114,24 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 		return b.buildHashJoin(v)
 	case *plannercore.PhysicalMergeJoin:
 		return b.buildMergeJoin(v)
+	case *plannercore.PhysicalIndexJoin:
+		return b.buildIndexLookUpJoin(v)
 	case *plannercore.PhysicalSelection:
 		return b.buildSelection(v)
 	case *plannercore.PhysicalHashAgg:
 		return b.buildHashAgg(v)
+	case *plannercore.PhysicalStreamAgg:
+		return b.buildStreamAgg(v)
 	case *plannercore.PhysicalProjection:
 		return b.buildProjection(v)
 	case *plannercore.PhysicalMemTable:
 		return b.buildMemTable(v)
 	case *plannercore.PhysicalTableDual:
 		return b.buildTableDual(v)
+	case *plannercore.PointGetPlan:
+		return b.buildPointGet(v)
+	case *plannercore.BatchPointGetPlan:
+		return b.buildBatchPointGet(v)
 	case *plannercore.Analyze:
 		return b.buildAnalyze(v)
 	case *plannercore.PhysicalTableReader:
@@ -116,6 +140,20 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 		return b.buildIndexReader(v)
 	case *plannercore.PhysicalIndexLookUpReader:
 		return b.buildIndexLookUpReader(v)
+	case *plannercore.PhysicalApply:
+		return b.buildApply(v)
+	case *plannercore.PhysicalMaxOneRow:
+		return b.buildMaxOneRow(v)
+	case *plannercore.PhysicalLock:
+		return b.buildSelectLock(v)
+	case *plannercore.PhysicalUnionAll:
+		return b.buildUnionAll(v)
+	case *plannercore.PhysicalWindow:
+		return b.buildWindow(v)
+	case *plannercore.PhysicalCTE:
+		return b.buildCTE(v)
+	case *plannercore.PhysicalCTETable:
+		return b.buildCTETableReader(v)
 	default:
 		if mp, ok := p.(MockPhysicalPlan); ok {
 			return mp.GetExecutor()
@@ -201,6 +239,37 @@ func (b *executorBuilder) buildShow(v *plannercore.PhysicalShow) Executor {
 	return e
 }
 
+func (b *executorBuilder) buildDeallocate(v *plannercore.Deallocate) Executor {
+	base := newBaseExecutor(b.ctx, nil, v.ExplainID())
+	base.initCap = chunk.ZeroCapacity
+	e := &DeallocateExec{
+		baseExecutor: base,
+		Name:         v.Name,
+	}
+	return e
+}
+
+func (b *executorBuilder) buildPrepare(v *plannercore.Prepare) Executor {
+	e := &PrepareExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID()),
+		is:           b.is,
+		name:         v.Name,
+		sqlText:      v.SQLText,
+	}
+	return e
+}
+
+func (b *executorBuilder) buildExecute(v *plannercore.Execute) Executor {
+	e := &ExecuteExec{
+		baseExecutor: newBaseExecutor(b.ctx, nil, v.ExplainID()),
+		is:           b.is,
+		name:         v.Name,
+		stmt:         v.Stmt,
+		plan:         v.Plan,
+	}
+	return e
+}
+
 func (b *executorBuilder) buildSimple(v *plannercore.Simple) Executor {
 	base := newBaseExecutor(b.ctx, v.Schema(), v.ExplainID())
 	base.initCap = chunk.ZeroCapacity
@@ -428,6 +497,70 @@ func (b *executorBuilder) buildMergeJoin(v *plannercore.PhysicalMergeJoin) Execu
 	return e
 }
 
+func (b *executorBuilder) buildIndexLookUpJoin(v *plannercore.PhysicalIndexJoin) Executor {
+	outerExec := b.build(v.Children()[1-v.InnerChildIdx])
+	if b.err != nil {
+		return nil
+	}
+	outerTypes := retTypes(outerExec)
+	innerPlan := v.Children()[v.InnerChildIdx]
+	innerTypes := make([]*types.FieldType, innerPlan.Schema().Len())
+	for i, col := range innerPlan.Schema().Columns {
+		innerTypes[i] = col.RetType
+	}
+
+	var (
+		outerFilter           []expression.Expression
+		leftTypes, rightTypes []*types.FieldType
+	)
+
+	if v.InnerChildIdx == 0 {
+		leftTypes, rightTypes = innerTypes, outerTypes
+		outerFilter = v.RightConditions
+		if len(v.LeftConditions) > 0 {
+			b.err = errors.Annotate(ErrBuildExecutor, "join's inner condition should be empty")
+			return nil
+		}
+	} else {
+		leftTypes, rightTypes = outerTypes, innerTypes
+		outerFilter = v.LeftConditions
+		if len(v.RightConditions) > 0 {
+			b.err = errors.Annotate(ErrBuildExecutor, "join's inner condition should be empty")
+			return nil
+		}
+	}
+	defaultValues := v.DefaultValues
+	if defaultValues == nil {
+		defaultValues = make([]types.Datum, len(innerTypes))
+	}
+	e := &IndexLookUpJoin{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID(), outerExec),
+		outerCtx: outerCtx{
+			rowTypes: outerTypes,
+			filter:   outerFilter,
+		},
+		innerCtx: innerCtx{
+			readerBuilder: &dataReaderBuilder{Plan: innerPlan, executorBuilder: b},
+			rowTypes:      innerTypes,
+		},
+		workerWg:      new(sync.WaitGroup),
+		joiner:        newJoiner(b.ctx, v.JoinType, v.InnerChildIdx == 0, defaultValues, v.OtherConditions, leftTypes, rightTypes),
+		indexRanges:   v.Ranges,
+		keyOff2IdxOff: v.KeyOff2IdxOff,
+	}
+	outerKeyCols := make([]int, len(v.OuterJoinKeys))
+	for i := 0; i < len(v.OuterJoinKeys); i++ {
+		outerKeyCols[i] = v.OuterJoinKeys[i].Index
+	}
+	e.outerCtx.keyCols = outerKeyCols
+	innerKeyCols := make([]int, len(v.InnerJoinKeys))
+	for i := 0; i < len(v.InnerJoinKeys); i++ {
+		innerKeyCols[i] = v.InnerJoinKeys[i].Index
+	}
+	e.innerCtx.keyCols = innerKeyCols
+	return e
+}
+
 func (b *executorBuilder) buildHashJoin(v *plannercore.PhysicalHashJoin) Executor {
 	leftExec := b.build(v.Children()[0])
 	if b.err != nil {
@@ -483,6 +616,91 @@ func (b *executorBuilder) buildHashJoin(v *plannercore.PhysicalHashJoin) Executo
 	return e
 }
 
+func (b *executorBuilder) buildApply(v *plannercore.PhysicalApply) Executor {
+	leftChild := b.build(v.Children()[0])
+	if b.err != nil {
+		return nil
+	}
+	rightChild := b.build(v.Children()[1])
+	if b.err != nil {
+		return nil
+	}
+	otherConditions := append(expression.ScalarFuncs2Exprs(v.EqualConditions), v.OtherConditions...)
+	defaultValues := v.DefaultValues
+	if defaultValues == nil {
+		defaultValues = make([]types.Datum, v.Children()[v.InnerChildIdx].Schema().Len())
+	}
+	tupleJoiner := newJoiner(b.ctx, v.JoinType, v.InnerChildIdx == 0,
+		defaultValues, otherConditions, retTypes(leftChild), retTypes(rightChild))
+	outerExec, innerExec := leftChild, rightChild
+	outerFilter, innerFilter := v.LeftConditions, v.RightConditions
+	if v.InnerChildIdx == 0 {
+		outerExec, innerExec = rightChild, leftChild
+		outerFilter, innerFilter = v.RightConditions, v.LeftConditions
+	}
+	e := &NestedLoopApplyExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID(), outerExec, innerExec),
+		innerExec:    innerExec,
+		outerExec:    outerExec,
+		outerFilter:  outerFilter,
+		innerFilter:  innerFilter,
+		outer:        v.JoinType != plannercore.InnerJoin,
+		joiner:       tupleJoiner,
+		outerSchema:  v.OuterSchema,
+	}
+	return e
+}
+
+func (b *executorBuilder) buildSelectLock(v *plannercore.PhysicalLock) Executor {
+	b.isSelectForUpdate = true
+	// Build 'select for update' using the 'for update' ts.
+	b.startTS = b.ctx.GetSessionVars().TxnCtx.GetForUpdateTS()
+
+	src := b.build(v.Children()[0])
+	if b.err != nil {
+		return nil
+	}
+	if !b.ctx.GetSessionVars().InTxn() {
+		// Locking of rows for update using SELECT FOR UPDATE only applies when autocommit
+		// is disabled (either by beginning transaction with START TRANSACTION or by setting
+		// autocommit to 0. If autocommit is enabled, the rows matching the specification are not locked.
+		// See https://dev.mysql.com/doc/refman/5.7/en/innodb-locking-reads.html
+		return src
+	}
+	e := &SelectLockExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID(), src),
+		Lock:         v.Lock,
+		tblID2Handle: v.TblID2Handle,
+	}
+	return e
+}
+
+func (b *executorBuilder) buildMaxOneRow(v *plannercore.PhysicalMaxOneRow) Executor {
+	childExec := b.build(v.Children()[0])
+	if b.err != nil {
+		return nil
+	}
+	base := newBaseExecutor(b.ctx, v.Schema(), v.ExplainID(), childExec)
+	base.initCap = 2
+	base.maxChunkSize = 2
+	e := &MaxOneRowExec{baseExecutor: base}
+	return e
+}
+
+func (b *executorBuilder) buildUnionAll(v *plannercore.PhysicalUnionAll) Executor {
+	childExecs := make([]Executor, len(v.Children()))
+	for i, child := range v.Children() {
+		childExecs[i] = b.build(child)
+		if b.err != nil {
+			return nil
+		}
+	}
+	e := &UnionExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID(), childExecs...),
+	}
+	return e
+}
+
 func (b *executorBuilder) buildHashAgg(v *plannercore.PhysicalHashAgg) Executor {
 	src := b.build(v.Children()[0])
 	if b.err != nil {
@@ -528,6 +746,10 @@ func (b *executorBuilder) buildHashAgg(v *plannercore.PhysicalHashAgg) Executor
 			ordinal = append(ordinal, partialOrdinal+1)
 			partialOrdinal++
 		}
+		if aggregation.IsVarianceFunc(aggDesc.Name) {
+			ordinal = append(ordinal, partialOrdinal, partialOrdinal+1)
+			partialOrdinal += 2
+		}
 		partialAggDesc, finalDesc := aggDesc.Split(ordinal)
 		partialAggFunc := aggfuncs.Build(b.ctx, partialAggDesc, i)
 		finalAggFunc := aggfuncs.Build(b.ctx, finalDesc, i)
@@ -541,6 +763,34 @@ func (b *executorBuilder) buildHashAgg(v *plannercore.PhysicalHashAgg) Executor
 	return e
 }
 
+func (b *executorBuilder) buildStreamAgg(v *plannercore.PhysicalStreamAgg) Executor {
+	src := b.build(v.Children()[0])
+	if b.err != nil {
+		return nil
+	}
+	e := &StreamAggExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID(), src),
+		groupChecker: newGroupChecker(b.ctx.GetSessionVars().StmtCtx, v.GroupByItems),
+		aggFuncs:     make([]aggfuncs.AggFunc, 0, len(v.AggFuncs)),
+	}
+	// The same as buildHashAgg, the default value is only needed when there
+	// is no group by item and not all the functions are FIRST_ROW.
+	if len(v.GroupByItems) != 0 || aggregation.IsAllFirstRow(v.AggFuncs) {
+		e.defaultVal = nil
+	} else {
+		e.defaultVal = chunk.NewChunkWithCapacity(retTypes(e), 1)
+	}
+	for i, aggDesc := range v.AggFuncs {
+		aggFunc := aggfuncs.Build(b.ctx, aggDesc, i)
+		e.aggFuncs = append(e.aggFuncs, aggFunc)
+		if e.defaultVal != nil {
+			value := aggDesc.GetDefaultValue()
+			e.defaultVal.AppendDatum(i, &value)
+		}
+	}
+	return e
+}
+
 func (b *executorBuilder) buildSelection(v *plannercore.PhysicalSelection) Executor {
 	childExec := b.build(v.Children()[0])
 	if b.err != nil {
@@ -598,6 +848,10 @@ func (b *executorBuilder) getStartTS() (uint64, error) {
 		return 0, err
 	}
 	b.startTS = txn.StartTS()
+	if b.ctx.GetSessionVars().IsReadCommittedTxn() {
+		// A read committed transaction reads at the ts obtained when the statement starts.
+		b.startTS = b.ctx.GetSessionVars().TxnCtx.GetForUpdateTS()
+	}
 	if b.startTS == 0 {
 		return 0, errors.Trace(ErrGetStartTS)
 	}
@@ -646,6 +900,27 @@ func (b *executorBuilder) buildTopN(v *plannercore.PhysicalTopN) Executor {
 	}
 }
 
+func (b *executorBuilder) buildUpdate(v *plannercore.Update) Executor {
+	tblID2table := make(map[int64]table.Table, len(v.TblColPosInfos))
+	for _, info := range v.TblColPosInfos {
+		tblID2table[info.TblID], _ = b.is.TableByID(info.TblID)
+	}
+	b.startTS = b.ctx.GetSessionVars().TxnCtx.GetForUpdateTS()
+	selExec := b.build(v.SelectPlan)
+	if b.err != nil {
+		return nil
+	}
+	base := newBaseExecutor(b.ctx, v.Schema(), v.ExplainID(), selExec)
+	base.initCap = chunk.ZeroCapacity
+	updateExec := &UpdateExec{
+		baseExecutor:   base,
+		OrderedList:    v.OrderedList,
+		tblID2table:    tblID2table,
+		tblColPosInfos: v.TblColPosInfos,
+	}
+	return updateExec
+}
+
 func (b *executorBuilder) buildDelete(v *plannercore.Delete) Executor {
 	tblID2table := make(map[int64]table.Table)
 	for _, info := range v.TblColPosInfos {
@@ -667,6 +942,7 @@ func (b *executorBuilder) buildDelete(v *plannercore.Delete) Executor {
 }
 
 func (b *executorBuilder) buildAnalyzeIndexPushdown(task plannercore.AnalyzeIndexTask) *analyzeTask {
+	_, offset := timeZoneOfLocation(b.ctx.GetSessionVars().Location())
 	sc := b.ctx.GetSessionVars().StmtCtx
 	e := &AnalyzeIndexExec{
 		ctx:             b.ctx,
@@ -674,8 +950,9 @@ func (b *executorBuilder) buildAnalyzeIndexPushdown(task plannercore.AnalyzeInde
 		idxInfo:         task.IndexInfo,
 		concurrency:     b.ctx.GetSessionVars().IndexSerialScanConcurrency,
 		analyzePB: &tipb.AnalyzeReq{
-			Tp:    tipb.AnalyzeType_TypeIndex,
-			Flags: sc.PushDownFlags(),
+			Tp:             tipb.AnalyzeType_TypeIndex,
+			Flags:          sc.PushDownFlags(),
+			TimeZoneOffset: offset,
 		},
 	}
 	e.analyzePB.IdxReq = &tipb.AnalyzeIndexReq{
@@ -695,6 +972,7 @@ func (b *executorBuilder) buildAnalyzeColumnsPushdown(task plannercore.AnalyzeCo
 		cols = append([]*model.ColumnInfo{task.PKInfo}, cols...)
 	}
 
+	_, offset := timeZoneOfLocation(b.ctx.GetSessionVars().Location())
 	sc := b.ctx.GetSessionVars().StmtCtx
 	e := &AnalyzeColumnsExec{
 		ctx:             b.ctx,
@@ -703,8 +981,9 @@ func (b *executorBuilder) buildAnalyzeColumnsPushdown(task plannercore.AnalyzeCo
 		pkInfo:          task.PKInfo,
 		concurrency:     b.ctx.GetSessionVars().DistSQLScanConcurrency,
 		analyzePB: &tipb.AnalyzeReq{
-			Tp:    tipb.AnalyzeType_TypeColumn,
-			Flags: sc.PushDownFlags(),
+			Tp:             tipb.AnalyzeType_TypeColumn,
+			Flags:          sc.PushDownFlags(),
+			TimeZoneOffset: offset,
 		},
 	}
 	depth := int32(defaultCMSketchDepth)
@@ -756,12 +1035,20 @@ func constructDistExec(sctx sessionctx.Context, plans []plannercore.PhysicalPlan
 
 func (b *executorBuilder) constructDAGReq(plans []plannercore.PhysicalPlan) (dagReq *tipb.DAGRequest, err error) {
 	dagReq = &tipb.DAGRequest{}
+	dagReq.TimeZoneName, dagReq.TimeZoneOffset = timeZoneOfLocation(b.ctx.GetSessionVars().Location())
 	sc := b.ctx.GetSessionVars().StmtCtx
 	dagReq.Flags = sc.PushDownFlags()
 	dagReq.Executors, err = constructDistExec(b.ctx, plans)
 	return dagReq, err
 }
 
+// timeZoneOfLocation returns the name and the current offset in seconds east
+// of UTC of loc. The name is empty for a fixed offset time zone such as '+08:00'.
+func timeZoneOfLocation(loc *time.Location) (string, int64) {
+	_, offset := time.Now().In(loc).Zone()
+	return loc.String(), int64(offset)
+}
+
 func buildNoRangeTableReader(b *executorBuilder, v *plannercore.PhysicalTableReader) (*TableReaderExecutor, error) {
 	dagReq, err := b.constructDAGReq(v.TablePlans)
 	if err != nil {
@@ -959,9 +1246,208 @@ func (builder *dataReaderBuilder) buildTableReaderFromHandles(ctx context.Contex
 	return e, nil
 }
 
+func (builder *dataReaderBuilder) buildExecutorForIndexJoin(ctx context.Context, lookUpContents []*indexJoinLookUpContent,
+	indexRanges []*ranger.Range, keyOff2IdxOff []int) (Executor, error) {
+	switch v := builder.Plan.(type) {
+	case *plannercore.PhysicalTableReader:
+		return builder.buildTableReaderForIndexJoin(ctx, v, lookUpContents)
+	case *plannercore.PhysicalIndexReader:
+		return builder.buildIndexReaderForIndexJoin(ctx, v, lookUpContents, indexRanges, keyOff2IdxOff)
+	case *plannercore.PhysicalIndexLookUpReader:
+		return builder.buildIndexLookUpReaderForIndexJoin(ctx, v, lookUpContents, indexRanges, keyOff2IdxOff)
+	case *plannercore.PhysicalUnionScan:
+		return builder.buildUnionScanForIndexJoin(ctx, v, lookUpContents, indexRanges, keyOff2IdxOff)
+	}
+	return nil, errors.New("Wrong plan type for dataReaderBuilder")
+}
+
+func (builder *dataReaderBuilder) buildUnionScanForIndexJoin(ctx context.Context, v *plannercore.PhysicalUnionScan,
+	lookUpContents []*indexJoinLookUpContent, indexRanges []*ranger.Range, keyOff2IdxOff []int) (Executor, error) {
+	childBuilder := &dataReaderBuilder{Plan: v.Children()[0], executorBuilder: builder.executorBuilder}
+	reader, err := childBuilder.buildExecutorForIndexJoin(ctx, lookUpContents, indexRanges, keyOff2IdxOff)
+	if err != nil {
+		return nil, err
+	}
+	us := builder.buildUnionScanFromReader(reader, v).(*UnionScanExec)
+	err = us.open(ctx)
+	return us, err
+}
+
+func (builder *dataReaderBuilder) buildTableReaderForIndexJoin(ctx context.Context, v *plannercore.PhysicalTableReader,
+	lookUpContents []*indexJoinLookUpContent) (Executor, error) {
+	e, err := buildNoRangeTableReader(builder.executorBuilder, v)
+	if err != nil {
+		return nil, err
+	}
+	handles := make([]int64, 0, len(lookUpContents))
+	for _, content := range lookUpContents {
+		handles = append(handles, content.keys[0].GetInt64())
+	}
+	return builder.buildTableReaderFromHandles(ctx, e, handles)
+}
+
+func (builder *dataReaderBuilder) buildIndexReaderForIndexJoin(ctx context.Context, v *plannercore.PhysicalIndexReader,
+	lookUpContents []*indexJoinLookUpContent, indexRanges []*ranger.Range, keyOff2IdxOff []int) (Executor, error) {
+	e, err := buildNoRangeIndexReader(builder.executorBuilder, v)
+	if err != nil {
+		return nil, err
+	}
+	kvRanges, err := buildKvRangesForIndexJoin(e.ctx, e.physicalTableID, e.index.ID, lookUpContents, indexRanges, keyOff2IdxOff)
+	if err != nil {
+		return nil, err
+	}
+	err = e.open(ctx, kvRanges)
+	return e, err
+}
+
+func (builder *dataReaderBuilder) buildIndexLookUpReaderForIndexJoin(ctx context.Context, v *plannercore.PhysicalIndexLookUpReader,
+	lookUpContents []*indexJoinLookUpContent, indexRanges []*ranger.Range, keyOff2IdxOff []int) (Executor, error) {
+	e, err := buildNoRangeIndexLookUpReader(builder.executorBuilder, v)
+	if err != nil {
+		return nil, err
+	}
+	e.kvRanges, err = buildKvRangesForIndexJoin(e.ctx, getPhysicalTableID(e.table), e.index.ID, lookUpContents, indexRanges, keyOff2IdxOff)
+	if err != nil {
+		return nil, err
+	}
+	err = e.open(ctx)
+	return e, err
+}
+
+// buildKvRangesForIndexJoin builds kv ranges for index join when the inner plan is index scan plan.
+// The positions of the join keys in the template ranges are filled with the values of the outer rows.
+func buildKvRangesForIndexJoin(ctx sessionctx.Context, tableID, indexID int64, lookUpContents []*indexJoinLookUpContent,
+	ranges []*ranger.Range, keyOff2IdxOff []int) ([]kv.KeyRange, error) {
+	kvRanges := make([]kv.KeyRange, 0, len(ranges)*len(lookUpContents))
+	sc := ctx.GetSessionVars().StmtCtx
+	for _, content := range lookUpContents {
+		for _, ran := range ranges {
+			for keyOff, idxOff := range keyOff2IdxOff {
+				ran.LowVal[idxOff] = content.keys[keyOff]
+				ran.HighVal[idxOff] = content.keys[keyOff]
+			}
+		}
+		tmpKvRanges, err := distsql.IndexRangesToKVRanges(sc, tableID, indexID, ranges)
+		if err != nil {
+			return nil, err
+		}
+		kvRanges = append(kvRanges, tmpKvRanges...)
+	}
+	// kvRanges don't overlap each other. So compare StartKey is enough.
+	sort.Slice(kvRanges, func(i, j int) bool {
+		return bytes.Compare(kvRanges[i].StartKey, kvRanges[j].StartKey) < 0
+	})
+	return kvRanges, nil
+}
+
 func getPhysicalTableID(t table.Table) int64 {
 	if p, ok := t.(table.PhysicalTable); ok {
 		return p.GetPhysicalID()
 	}
 	return t.Meta().ID
 }
+
+func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) *WindowExec {
+	childExec := b.build(v.Children()[0])
+	if b.err != nil {
+		return nil
+	}
+	base := newBaseExecutor(b.ctx, v.Schema(), v.ExplainID(), childExec)
+	partitionBy := make([]*expression.Column, 0, len(v.PartitionBy))
+	for _, item := range v.PartitionBy {
+		partitionBy = append(partitionBy, item.Col)
+	}
+	orderByCols := make([]*expression.Column, 0, len(v.OrderBy))
+	for _, item := range v.OrderBy {
+		orderByCols = append(orderByCols, item.Col)
+	}
+	windowFuncs := make([]aggfuncs.AggFunc, 0, len(v.WindowFuncDescs))
+	partialResults := make([]aggfuncs.PartialResult, 0, len(v.WindowFuncDescs))
+	resultColIdx := v.Schema().Len() - len(v.WindowFuncDescs)
+	for _, desc := range v.WindowFuncDescs {
+		aggDesc, err := aggregation.NewAggFuncDesc(b.ctx, desc.Name, desc.Args, false)
+		if err != nil {
+			b.err = err
+			return nil
+		}
+		agg := aggfuncs.BuildWindowFunctions(b.ctx, aggDesc, resultColIdx, orderByCols)
+		windowFuncs = append(windowFuncs, agg)
+		partialResults = append(partialResults, agg.AllocPartialResult())
+		resultColIdx++
+	}
+	var processor windowProcessor
+	if v.Frame == nil {
+		processor = &aggWindowProcessor{
+			windowFuncs:    windowFuncs,
+			partialResults: partialResults,
+		}
+	} else if v.Frame.Type == ast.Rows {
+		processor = &rowFrameWindowProcessor{
+			windowFuncs:    windowFuncs,
+			partialResults: partialResults,
+			start:          v.Frame.Start,
+			end:            v.Frame.End,
+		}
+	} else {
+		cmpResult := int64(-1)
+		if len(v.OrderBy) > 0 && v.OrderBy[0].Desc {
+			cmpResult = 1
+		}
+		processor = &rangeFrameWindowProcessor{
+			windowFuncs:       windowFuncs,
+			partialResults:    partialResults,
+			start:             v.Frame.Start,
+			end:               v.Frame.End,
+			orderByCols:       orderByCols,
+			expectedCmpResult: cmpResult,
+		}
+	}
+	return &WindowExec{
+		baseExecutor: base,
+		partitionBy:  partitionBy,
+		processor:    processor,
+	}
+}
+
+func (b *executorBuilder) buildCTE(v *plannercore.PhysicalCTE) Executor {
+	storage, ok := b.cteStorages[v.CTE.IDForStorage]
+	if !ok {
+		storage = &cteStorage{
+			ctx:        b.ctx,
+			isDistinct: v.CTE.IsDistinct,
+		}
+		if b.cteStorages == nil {
+			b.cteStorages = make(map[int]*cteStorage)
+		}
+		// The storage is registered before building the recursive part, which
+		// reads the work table of it.
+		b.cteStorages[v.CTE.IDForStorage] = storage
+		storage.seedExec = b.build(v.SeedPlan)
+		if b.err != nil {
+			return nil
+		}
+		storage.fieldTypes = retTypes(storage.seedExec)
+		if v.RecurPlan != nil {
+			storage.recurExec = b.build(v.RecurPlan)
+			if b.err != nil {
+				return nil
+			}
+		}
+	}
+	return &CTEExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID()),
+		storage:      storage,
+	}
+}
+
+func (b *executorBuilder) buildCTETableReader(v *plannercore.PhysicalCTETable) Executor {
+	storage, ok := b.cteStorages[v.IDForStorage]
+	if !ok {
+		b.err = errors.Annotate(ErrBuildExecutor, "the storage of the common table expression is not found")
+		return nil
+	}
+	return &CTETableReaderExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID()),
+		storage:      storage,
+	}
+}
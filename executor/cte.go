@@ -0,0 +1,205 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
+)
+
+// cteStorage holds the result of a materialized common table expression. It is
+// shared by all the references to the common table expression in a query, so
+// the common table expression is only computed once.
+//
+// For a recursive common table expression, the rows produced by the seed part
+// form the first work table. Then the recursive part, which reads the work table
+// by a CTETableReaderExec, is executed repeatedly and the rows produced by every
+// iteration form the next work table, until an iteration produces no row.
+type cteStorage struct {
+	ctx        sessionctx.Context
+	seedExec   Executor
+	recurExec  Executor
+	isDistinct bool
+	fieldTypes []*types.FieldType
+
+	computed bool
+	err      error
+	// result holds all the rows of the common table expression.
+	result *chunk.List
+	// workTable holds the rows produced by the last iteration.
+	workTable *chunk.List
+	// distinctKeys holds the encoded keys of the rows in result, it's only
+	// used when the parts are joined by UNION DISTINCT.
+	distinctKeys map[string]struct{}
+}
+
+// compute computes the result of the common table expression if it has not
+// been computed.
+func (s *cteStorage) compute(ctx context.Context) error {
+	if s.computed {
+		return s.err
+	}
+	s.computed = true
+	s.err = s.doCompute(ctx)
+	return s.err
+}
+
+func (s *cteStorage) doCompute(ctx context.Context) error {
+	maxChunkSize := s.ctx.GetSessionVars().MaxChunkSize
+	s.result = chunk.NewList(s.fieldTypes, maxChunkSize, maxChunkSize)
+	if s.isDistinct {
+		s.distinctKeys = make(map[string]struct{})
+	}
+	s.workTable = chunk.NewList(s.fieldTypes, maxChunkSize, maxChunkSize)
+	err := s.executePart(ctx, s.seedExec, s.workTable)
+	if err != nil {
+		return err
+	}
+	if s.recurExec == nil {
+		return nil
+	}
+	maxDepth := s.ctx.GetSessionVars().CTEMaxRecursionDepth
+	for iter := 1; s.workTable.Len() > 0; iter++ {
+		newWorkTable := chunk.NewList(s.fieldTypes, maxChunkSize, maxChunkSize)
+		err = s.executePart(ctx, s.recurExec, newWorkTable)
+		if err != nil {
+			return err
+		}
+		if newWorkTable.Len() > 0 && iter > maxDepth {
+			return ErrCTEMaxRecursionDepth.GenWithStackByArgs(iter)
+		}
+		s.workTable = newWorkTable
+	}
+	return nil
+}
+
+// executePart executes the seed part or an iteration of the recursive part,
+// and appends the new rows to both the result and the work table.
+func (s *cteStorage) executePart(ctx context.Context, e Executor, workTable *chunk.List) (err error) {
+	if err = e.Open(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := e.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	sc := s.ctx.GetSessionVars().StmtCtx
+	chk := newFirstChunk(e)
+	for {
+		err = Next(ctx, e, chk)
+		if err != nil {
+			return err
+		}
+		if chk.NumRows() == 0 {
+			return nil
+		}
+		for i := 0; i < chk.NumRows(); i++ {
+			row := chk.GetRow(i)
+			if s.isDistinct {
+				var key []byte
+				key, err = codec.EncodeKey(sc, nil, row.GetDatumRow(s.fieldTypes)...)
+				if err != nil {
+					return err
+				}
+				if _, ok := s.distinctKeys[string(key)]; ok {
+					continue
+				}
+				s.distinctKeys[string(key)] = struct{}{}
+			}
+			s.result.AppendRow(row)
+			workTable.AppendRow(row)
+		}
+	}
+}
+
+// cteCursor reads the rows of a chunk.List in order.
+type cteCursor struct {
+	chkIdx int
+	rowIdx int
+}
+
+func (c *cteCursor) reset() {
+	c.chkIdx, c.rowIdx = 0, 0
+}
+
+// next fills req with the next rows of list.
+func (c *cteCursor) next(list *chunk.List, req *chunk.Chunk) {
+	for !req.IsFull() && c.chkIdx < list.NumChunks() {
+		chk := list.GetChunk(c.chkIdx)
+		if c.rowIdx >= chk.NumRows() {
+			c.chkIdx++
+			c.rowIdx = 0
+			continue
+		}
+		req.AppendRow(chk.GetRow(c.rowIdx))
+		c.rowIdx++
+	}
+}
+
+// CTEExec represents a reference to a materialized common table expression.
+type CTEExec struct {
+	baseExecutor
+
+	storage *cteStorage
+	cursor  cteCursor
+}
+
+// Open implements the Executor Open interface.
+func (e *CTEExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.cursor.reset()
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *CTEExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if err := e.storage.compute(ctx); err != nil {
+		return err
+	}
+	e.cursor.next(e.storage.result, req)
+	return nil
+}
+
+// CTETableReaderExec reads the work table of a recursive common table
+// expression, which holds the rows produced by the last iteration.
+type CTETableReaderExec struct {
+	baseExecutor
+
+	storage *cteStorage
+	cursor  cteCursor
+}
+
+// Open implements the Executor Open interface.
+func (e *CTETableReaderExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.cursor.reset()
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *CTETableReaderExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	e.cursor.next(e.storage.workTable, req)
+	return nil
+}
@@ -41,6 +41,8 @@ var (
 	ErrWrongObject                 = terror.ClassExecutor.New(mysql.ErrWrongObject, mysql.MySQLErrName[mysql.ErrWrongObject])
 	ErrRoleNotGranted              = terror.ClassPrivilege.New(mysql.ErrRoleNotGranted, mysql.MySQLErrName[mysql.ErrRoleNotGranted])
 	ErrQueryInterrupted            = terror.ClassExecutor.New(mysql.ErrQueryInterrupted, mysql.MySQLErrName[mysql.ErrQueryInterrupted])
+	ErrSubqueryMoreThan1Row        = terror.ClassExecutor.New(mysql.ErrSubqueryNo1Row, mysql.MySQLErrName[mysql.ErrSubqueryNo1Row])
+	ErrCTEMaxRecursionDepth        = terror.ClassExecutor.New(mysql.ErrCTEMaxRecursionDepth, mysql.MySQLErrName[mysql.ErrCTEMaxRecursionDepth])
 )
 
 func init() {
@@ -67,7 +69,9 @@ func init() {
 		mysql.ErrWrongObject:                 mysql.ErrWrongObject,
 		mysql.ErrRoleNotGranted:              mysql.ErrRoleNotGranted,
 		mysql.ErrQueryInterrupted:            mysql.ErrQueryInterrupted,
+		mysql.ErrSubqueryNo1Row:              mysql.ErrSubqueryNo1Row,
 		mysql.ErrWrongValueCountOnRow:        mysql.ErrWrongValueCountOnRow,
+		mysql.ErrCTEMaxRecursionDepth:        mysql.ErrCTEMaxRecursionDepth,
 	}
 	terror.ErrClassToMySQLCodes[terror.ClassExecutor] = tableMySQLErrCodes
 }
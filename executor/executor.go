@@ -16,6 +16,8 @@ package executor
 import (
 	"context"
 	"fmt"
+	"runtime"
+	"sync"
 	"sync/atomic"
 
 	"github.com/cznic/mathutil"
@@ -25,23 +27,34 @@ import (
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/terror"
+	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/admin"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
 )
 
 var (
 	_ Executor = &baseExecutor{}
 	_ Executor = &HashAggExec{}
+	_ Executor = &StreamAggExec{}
 	_ Executor = &HashJoinExec{}
 	_ Executor = &IndexLookUpExecutor{}
 	_ Executor = &IndexReaderExecutor{}
 	_ Executor = &LimitExec{}
+	_ Executor = &MaxOneRowExec{}
+	_ Executor = &IndexLookUpJoin{}
 	_ Executor = &MergeJoinExec{}
+	_ Executor = &NestedLoopApplyExec{}
 	_ Executor = &ProjectionExec{}
+	_ Executor = &SelectLockExec{}
 	_ Executor = &SelectionExec{}
 	_ Executor = &ShowDDLExec{}
 	_ Executor = &ShowDDLJobsExec{}
@@ -50,8 +63,46 @@ var (
 	_ Executor = &TableReaderExecutor{}
 	_ Executor = &TableScanExec{}
 	_ Executor = &TopNExec{}
+	_ Executor = &UnionExec{}
+	_ Executor = &WindowExec{}
+	_ Executor = &CTEExec{}
+	_ Executor = &CTETableReaderExec{}
 )
 
+func init() {
+	// While doing optimization in the plan package, we need to execute uncorrelated subquery,
+	// but the plan package cannot import the executor package because of the dependency cycle.
+	// So we assign a function implemented in the executor package to the plan package to avoid the dependency cycle.
+	plannercore.EvalSubquery = func(ctx context.Context, p plannercore.PhysicalPlan, is infoschema.InfoSchema, sctx sessionctx.Context) (rows [][]types.Datum, err error) {
+		e := newExecutorBuilder(sctx, is)
+		exec := e.build(p)
+		if e.err != nil {
+			return rows, e.err
+		}
+		err = exec.Open(ctx)
+		defer terror.Call(exec.Close)
+		if err != nil {
+			return rows, err
+		}
+		chk := newFirstChunk(exec)
+		for {
+			err = Next(ctx, exec, chk)
+			if err != nil {
+				return rows, err
+			}
+			if chk.NumRows() == 0 {
+				return rows, nil
+			}
+			iter := chunk.NewIterator4Chunk(chk)
+			for r := iter.Begin(); r != iter.End(); r = iter.Next() {
+				row := r.GetDatumRow(retTypes(exec))
+				rows = append(rows, row)
+			}
+			chk = chunk.Renew(chk, sctx.GetSessionVars().MaxChunkSize)
+		}
+	}
+}
+
 type baseExecutor struct {
 	ctx           sessionctx.Context
 	id            fmt.Stringer
@@ -342,6 +393,259 @@ func (e *TableDualExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	return nil
 }
 
+// UnionExec pulls all it's children's result and returns to its parent directly.
+// A "resultPuller" is started for every child to pull result from that child and push it to the "resultPool", the used
+// "Chunk" is obtained from the corresponding "resourcePool". All resultPullers are running concurrently.
+type UnionExec struct {
+	baseExecutor
+
+	stopFetchData atomic.Value
+	wg            sync.WaitGroup
+
+	finished      chan struct{}
+	resourcePools []chan *chunk.Chunk
+	resultPool    chan *unionWorkerResult
+	initialized   bool
+
+	childrenResults []*chunk.Chunk
+}
+
+// unionWorkerResult stores the result for a union worker.
+// A "resultPuller" is started for every child to pull result from that child, unionWorkerResult is used to store that pulled result.
+// "src" is used for Chunk reuse: after pulling result from "resultPool", main-thread must push a valid unused Chunk to "src" to
+// enable the corresponding "resultPuller" continue to work.
+type unionWorkerResult struct {
+	chk *chunk.Chunk
+	err error
+	src chan<- *chunk.Chunk
+}
+
+func (e *UnionExec) waitAllFinished() {
+	e.wg.Wait()
+	close(e.resultPool)
+}
+
+// Open implements the Executor Open interface.
+func (e *UnionExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	for _, child := range e.children {
+		e.childrenResults = append(e.childrenResults, newFirstChunk(child))
+	}
+	e.stopFetchData.Store(false)
+	e.initialized = false
+	e.finished = make(chan struct{})
+	return nil
+}
+
+func (e *UnionExec) initialize(ctx context.Context) {
+	e.resultPool = make(chan *unionWorkerResult, len(e.children))
+	e.resourcePools = make([]chan *chunk.Chunk, len(e.children))
+	for i := range e.children {
+		e.resourcePools[i] = make(chan *chunk.Chunk, 1)
+		e.resourcePools[i] <- e.childrenResults[i]
+		e.wg.Add(1)
+		go e.resultPuller(ctx, i)
+	}
+	go e.waitAllFinished()
+}
+
+func (e *UnionExec) resultPuller(ctx context.Context, childID int) {
+	result := &unionWorkerResult{
+		err: nil,
+		chk: nil,
+		src: e.resourcePools[childID],
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			buf := make([]byte, 4096)
+			stackSize := runtime.Stack(buf, false)
+			buf = buf[:stackSize]
+			logutil.Logger(ctx).Error("resultPuller panicked", zap.String("stack", string(buf)))
+			result.err = errors.Errorf("%v", r)
+			e.resultPool <- result
+			e.stopFetchData.Store(true)
+		}
+		e.wg.Done()
+	}()
+	for {
+		if e.stopFetchData.Load().(bool) {
+			return
+		}
+		select {
+		case <-e.finished:
+			return
+		case result.chk = <-e.resourcePools[childID]:
+		}
+		result.err = Next(ctx, e.children[childID], result.chk)
+		if result.err == nil && result.chk.NumRows() == 0 {
+			return
+		}
+		e.resultPool <- result
+		if result.err != nil {
+			e.stopFetchData.Store(true)
+			return
+		}
+	}
+}
+
+// Next implements the Executor Next interface.
+func (e *UnionExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.GrowAndReset(e.maxChunkSize)
+	if !e.initialized {
+		e.initialize(ctx)
+		e.initialized = true
+	}
+	result, ok := <-e.resultPool
+	if !ok {
+		return nil
+	}
+	if result.err != nil {
+		return errors.Trace(result.err)
+	}
+
+	req.SwapColumns(result.chk)
+	result.src <- result.chk
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *UnionExec) Close() error {
+	if e.finished != nil {
+		close(e.finished)
+	}
+	e.childrenResults = nil
+	if e.resultPool != nil {
+		for range e.resultPool {
+		}
+	}
+	e.resourcePools = nil
+	return e.baseExecutor.Close()
+}
+
+// SelectLockExec represents a select lock executor.
+// It is built from the "SELECT .. FOR UPDATE" statement.
+// For "SELECT .. FOR UPDATE" statement, it locks every row key from source Executor.
+// In an optimistic transaction the keys are buffered and checked for conflicts when
+// committing, in a pessimistic transaction they are locked in TiKV before the
+// statement returns.
+type SelectLockExec struct {
+	baseExecutor
+
+	Lock ast.SelectLockType
+	keys []kv.Key
+
+	tblID2Handle map[int64][]*expression.Column
+}
+
+// Next implements the Executor Next interface.
+func (e *SelectLockExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.GrowAndReset(e.maxChunkSize)
+	err := Next(ctx, e.children[0], req)
+	if err != nil {
+		return err
+	}
+	// If there's no handle or it's not a `SELECT FOR UPDATE` statement.
+	if len(e.tblID2Handle) == 0 || (e.Lock != ast.SelectLockForUpdate && e.Lock != ast.SelectLockForUpdateNoWait) {
+		return nil
+	}
+	if req.NumRows() != 0 {
+		iter := chunk.NewIterator4Chunk(req)
+		for id, cols := range e.tblID2Handle {
+			for _, col := range cols {
+				for row := iter.Begin(); row != iter.End(); row = iter.Next() {
+					e.keys = append(e.keys, tablecodec.EncodeRowKeyWithHandle(id, row.GetInt64(col.Index)))
+				}
+			}
+		}
+		return nil
+	}
+	// Lock keys only once when finished fetching all results.
+	lockWaitTime := e.ctx.GetSessionVars().LockWaitTimeout
+	if e.Lock == ast.SelectLockForUpdateNoWait {
+		lockWaitTime = kv.LockNoWait
+	}
+	keys := e.keys
+	e.keys = nil
+	if len(keys) > 0 {
+		// The locked rows are returned to the client, so the transaction can't be retried.
+		e.ctx.GetSessionVars().TxnCtx.ForUpdate = true
+	}
+	return doLockKeys(ctx, e.ctx, newLockCtx(e.ctx.GetSessionVars(), lockWaitTime), keys...)
+}
+
+func newLockCtx(seVars *variable.SessionVars, lockWaitTime int64) *kv.LockCtx {
+	return &kv.LockCtx{
+		Killed:       &seVars.Killed,
+		ForUpdateTS:  seVars.TxnCtx.GetForUpdateTS(),
+		LockWaitTime: lockWaitTime,
+	}
+}
+
+// doLockKeys is the main entry for locking keys, in a pessimistic transaction the keys
+// are locked in TiKV at the for update ts of the lock context.
+func doLockKeys(ctx context.Context, se sessionctx.Context, lockCtx *kv.LockCtx, keys ...kv.Key) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	txn, err := se.Txn(true)
+	if err != nil {
+		return err
+	}
+	return txn.LockKeys(sessionctx.SetCommitCtx(ctx, se), lockCtx, keys...)
+}
+
+// MaxOneRowExec checks if the number of rows that a query returns is at maximum one.
+// It's built from subquery expression.
+type MaxOneRowExec struct {
+	baseExecutor
+
+	evaluated bool
+}
+
+// Open implements the Executor Open interface.
+func (e *MaxOneRowExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.evaluated = false
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *MaxOneRowExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.evaluated {
+		return nil
+	}
+	e.evaluated = true
+	err := Next(ctx, e.children[0], req)
+	if err != nil {
+		return err
+	}
+
+	if num := req.NumRows(); num == 0 {
+		for i := range e.schema.Columns {
+			req.AppendNull(i)
+		}
+		return nil
+	} else if num != 1 {
+		return ErrSubqueryMoreThan1Row
+	}
+
+	childChunk := newFirstChunk(e.children[0])
+	err = Next(ctx, e.children[0], childChunk)
+	if err != nil {
+		return err
+	}
+	if childChunk.NumRows() != 0 {
+		return ErrSubqueryMoreThan1Row
+	}
+
+	return nil
+}
+
 // SelectionExec represents a filter executor.
 type SelectionExec struct {
 	baseExecutor
@@ -538,6 +842,8 @@ func extractStmtHintsFromStmtNode(stmtNode ast.StmtNode) []*ast.TableOptimizerHi
 	switch x := stmtNode.(type) {
 	case *ast.SelectStmt:
 		return x.TableHints
+	case *ast.UpdateStmt:
+		return x.TableHints
 	case *ast.DeleteStmt:
 		return nil
 	// TODO: support hint for InsertStmt
@@ -609,9 +915,16 @@ func handleStmtHints(hints []*ast.TableOptimizerHint) (stmtHints stmtctx.StmtHin
 // ResetContextOfStmt resets the StmtContext and session variables.
 // Before every execution, we must clear statement context.
 func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
+	vars := ctx.GetSessionVars()
+	// Detect whether the statement is prepared, in which case its real statement is used.
+	if execStmt, ok := s.(*ast.ExecuteStmt); ok {
+		s, err = getPreparedStmt(execStmt, vars)
+		if err != nil {
+			return
+		}
+	}
 	hints := extractStmtHintsFromStmtNode(s)
 	stmtHints, hintWarns := handleStmtHints(hints)
-	vars := ctx.GetSessionVars()
 	sc := &stmtctx.StatementContext{
 		StmtHints: stmtHints,
 		TimeZone:  vars.Location(),
@@ -626,6 +939,13 @@ func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 	// IgnoreErr and StrictSQLMode) to avoid setting the same bool variables and
 	// pushing them down to TiKV as flags.
 	switch stmt := s.(type) {
+	case *ast.UpdateStmt:
+		sc.InUpdateStmt = true
+		sc.BadNullAsWarning = !vars.StrictSQLMode
+		sc.TruncateAsWarning = !vars.StrictSQLMode
+		sc.DividedByZeroAsWarning = !vars.StrictSQLMode
+		sc.AllowInvalidDate = vars.SQLMode.HasAllowInvalidDatesMode()
+		sc.IgnoreZeroInDate = !vars.StrictSQLMode || sc.AllowInvalidDate
 	case *ast.DeleteStmt:
 		sc.InDeleteStmt = true
 		sc.BadNullAsWarning = !vars.StrictSQLMode
@@ -643,6 +963,12 @@ func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 		sc.IgnoreZeroInDate = !vars.StrictSQLMode || sc.AllowInvalidDate
 	case *ast.CreateTableStmt, *ast.AlterTableStmt:
 		// Make sure the sql_mode is strict when checking column default value.
+	case *ast.SetOprStmt:
+		sc.InSelectStmt = true
+		sc.OverflowAsWarning = true
+		sc.TruncateAsWarning = true
+		sc.IgnoreZeroInDate = true
+		sc.AllowInvalidDate = vars.SQLMode.HasAllowInvalidDatesMode()
 	case *ast.SelectStmt:
 		sc.InSelectStmt = true
 
@@ -680,7 +1006,7 @@ func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 		sc.PrevLastInsertID = vars.StmtCtx.PrevLastInsertID
 	}
 	sc.PrevAffectedRows = 0
-	if vars.StmtCtx.InDeleteStmt || vars.StmtCtx.InInsertStmt {
+	if vars.StmtCtx.InUpdateStmt || vars.StmtCtx.InDeleteStmt || vars.StmtCtx.InInsertStmt {
 		sc.PrevAffectedRows = int64(vars.StmtCtx.AffectedRows())
 	} else if vars.StmtCtx.InSelectStmt {
 		sc.PrevAffectedRows = -1
@@ -688,6 +1014,9 @@ func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 	errCount, warnCount := vars.StmtCtx.NumErrorWarnings()
 	vars.SysErrorCount = errCount
 	vars.SysWarningCount = warnCount
+	vars.PreparedParams = vars.PreparedParams[:0]
+	vars.PrevFoundInPlanCache = vars.FoundInPlanCache
+	vars.FoundInPlanCache = false
 	vars.StmtCtx = sc
 	for _, warn := range hintWarns {
 		vars.StmtCtx.AppendWarning(warn)
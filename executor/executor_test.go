@@ -27,9 +27,11 @@ import (
 	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
+	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/store/mockstore/mocktikv"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tidb/util/testkit"
@@ -38,6 +40,7 @@ import (
 	"os"
 	"strconv"
 	"testing"
+	"time"
 )
 
 func TestT(t *testing.T) {
@@ -638,6 +641,162 @@ func (s *testSuiteP1) TestIn(c *C) {
 	tk.MustQuery(queryStr).Check(testkit.Rows("7"))
 }
 
+func (s *testSuiteP1) TestDecimal(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b decimal(10,2), index idx(b))")
+	tk.MustExec("insert into t values(1, 1.1), (2, 2.255), (3, -0.5), (4, NULL), (5, 100)")
+	tk.MustQuery("select b from t order by b").Check(testkit.Rows("<nil>", "-0.50", "1.10", "2.26", "100.00"))
+	tk.MustQuery("select b * 2, b + 1, b / 4 from t where a = 1").Check(testkit.Rows("2.20 2.10 0.275000"))
+	tk.MustQuery("select 0.1 + 0.2, 1 / 3, -2.50 - 0.5").Check(testkit.Rows("0.3 0.3333 -3.00"))
+	tk.MustQuery("select a from t where b > 1 order by a").Check(testkit.Rows("1", "2", "5"))
+	tk.MustQuery("select a from t where b = 2.26").Check(testkit.Rows("2"))
+	tk.MustQuery("select a from t where b in (1.1, 100) order by a").Check(testkit.Rows("1", "5"))
+	tk.MustQuery("select b from t where b >= 1.1 and b < 3 order by b").Check(testkit.Rows("1.10", "2.26"))
+	tk.MustQuery("select b from t where b / 0 is null order by b").Check(testkit.Rows("<nil>", "-0.50", "1.10", "2.26", "100.00"))
+
+	tk.MustExec("update t set b = b * 2 where a = 2")
+	tk.MustQuery("select b from t where b > 4").Check(testkit.Rows("4.52", "100.00"))
+	tk.MustExec("delete from t where b = 4.52")
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("4"))
+}
+
+func (s *testSuiteP1) TestTimeTypes(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t, t1, t2, t3")
+	tk.MustExec("create table t(a int primary key, d date, dt datetime(3), tm time, index idx(dt))")
+	tk.MustExec("insert into t values(1, '2019-01-01', '2019-01-01 10:11:12.1234', '10:11:12'), (2, '2020-02-29', '2020-02-29 23:59:59.9999', '-1:00:00'), (3, NULL, NULL, NULL)")
+	tk.MustQuery("select d, dt, tm from t order by a").Check(testkit.Rows(
+		"2019-01-01 2019-01-01 10:11:12.123 10:11:12",
+		"2020-02-29 2020-03-01 00:00:00.000 -01:00:00",
+		"<nil> <nil> <nil>"))
+	tk.MustQuery("select a from t where dt > '2019-06-01' order by a").Check(testkit.Rows("2"))
+	tk.MustQuery("select a from t where dt between '2019-01-01' and '2019-01-02'").Check(testkit.Rows("1"))
+	tk.MustQuery("select a from t where d = 20190101").Check(testkit.Rows("1"))
+	tk.MustQuery("select a from t where tm < '00:00:00'").Check(testkit.Rows("2"))
+	tk.MustQuery("select max(dt), min(tm), count(d) from t").Check(testkit.Rows("2020-03-01 00:00:00.000 -01:00:00 2"))
+	tk.MustQuery("select cast(d as datetime), cast(tm as signed), cast('2019-1-1 1:2:3' as date) from t where a = 1").Check(testkit.Rows("2019-01-01 00:00:00 101112 2019-01-01"))
+
+	// TIMESTAMP values are stored in UTC and shown in the session time zone.
+	tk.MustExec("create table t1(a int, ts timestamp, index idx(ts))")
+	tk.MustExec("set time_zone = '+00:00'")
+	tk.MustExec("insert into t1 values(1, '2019-01-01 00:00:00')")
+	tk.MustExec("set time_zone = '+08:00'")
+	tk.MustQuery("select ts from t1").Check(testkit.Rows("2019-01-01 08:00:00"))
+	tk.MustQuery("select a from t1 where ts = '2019-01-01 08:00:00'").Check(testkit.Rows("1"))
+	tk.MustQuery("select a from t1 use index(idx) where ts > '2019-01-01 07:00:00'").Check(testkit.Rows("1"))
+	_, err := tk.Exec("set time_zone = '+15:00'")
+	c.Assert(err, NotNil)
+
+	// ON UPDATE CURRENT_TIMESTAMP.
+	tk.MustExec("create table t2(a int, b datetime default '2000-01-01 00:00:00' on update current_timestamp)")
+	tk.MustExec("insert into t2(a) values(1)")
+	tk.MustQuery("select b from t2").Check(testkit.Rows("2000-01-01 00:00:00"))
+	tk.MustExec("update t2 set a = 1")
+	tk.MustQuery("select b from t2").Check(testkit.Rows("2000-01-01 00:00:00"))
+	tk.MustExec("update t2 set a = 2")
+	tk.MustQuery("select b > '2000-01-01 00:00:00' from t2").Check(testkit.Rows("1"))
+	tk.MustExec("update t2 set a = 3, b = '2001-01-01'")
+	tk.MustQuery("select b from t2").Check(testkit.Rows("2001-01-01 00:00:00"))
+	_, err = tk.Exec("create table t3(a int on update current_timestamp)")
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("create table t3(a datetime(3) on update current_timestamp)")
+	c.Assert(err, NotNil)
+
+	tk.MustExec("create table t3(a int, b timestamp(6) default current_timestamp(6))")
+	tk.MustExec("insert into t3(a) values(1)")
+	tk.MustQuery("select a from t3 where b is not null").Check(testkit.Rows("1"))
+}
+
+func (s *testSuiteP1) TestJSON(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int primary key, j json)")
+	tk.MustExec(`insert into t values(1, '{"a": {"b": "x"}, "c": [1, 2]}'), (2, '[1, "2", null]'), (3, NULL), (4, '3')`)
+	tk.MustQuery("select j from t order by id").Check(testkit.Rows(`{"a": {"b": "x"}, "c": [1, 2]}`, `[1, "2", null]`, "<nil>", "3"))
+	tk.MustQuery("select j->'$.a.b', j->>'$.a.b', j->'$.c[1]' from t where id = 1").Check(testkit.Rows(`"x" x 2`))
+	tk.MustQuery("select id from t where j->'$.c[0]' = 1").Check(testkit.Rows("1"))
+	tk.MustQuery("select id from t where j->>'$.a.b' = 'x'").Check(testkit.Rows("1"))
+	tk.MustQuery("select id from t where j is null").Check(testkit.Rows("3"))
+	tk.MustQuery("select id from t where j = cast('3' as json)").Check(testkit.Rows("4"))
+	tk.MustQuery("select json_extract(j, '$[1]', '$[2]') from t where id = 2").Check(testkit.Rows(`["2", null]`))
+	tk.MustQuery(`select json_unquote('"a\tb"'), json_unquote(json_extract(j, '$[1]')) from t where id = 2`).Check(testkit.Rows("a	b 2"))
+	tk.MustQuery("select json_set(j, '$.a.b', 'y', '$.d', 1.5) from t where id = 1").Check(testkit.Rows(`{"a": {"b": "y"}, "c": [1, 2], "d": 1.5}`))
+	tk.MustQuery("select json_object('k', j->'$.c', 'n', null), json_array(1, 'a', j->'$.a') from t where id = 1").Check(testkit.Rows(
+		`{"k": [1, 2], "n": null} [1, "a", {"b": "x"}]`))
+	tk.MustQuery(`select id, json_contains(j, '[1, 2]', '$.c') from t order by id`).Check(testkit.Rows("1 1", "2 <nil>", "3 <nil>", "4 <nil>"))
+	tk.MustQuery(`select id from t where json_contains(j, '"2"')`).Check(testkit.Rows("2"))
+	tk.MustQuery("select max(j->'$.c[1]'), count(j) from t").Check(testkit.Rows("2 3"))
+
+	tk.MustExec(`update t set j = json_set(j, '$[3]', true) where id = 2`)
+	tk.MustQuery("select j from t where id = 2").Check(testkit.Rows(`[1, "2", null, true]`))
+	_, err := tk.Exec(`insert into t values(5, '{"a": 1')`)
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("select json_object('a')")
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("create table t1(j json, index idx(j))")
+	c.Assert(err, NotNil)
+}
+
+func (s *testSuiteP1) TestEnumSetBit(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int primary key, e enum('x', 'Y', 'z') default 'y', s set('a', 'b', 'c'), b bit(6) default 5, index idx_e(e), index idx_s(s))")
+	tk.MustExec("insert into t values(1, 'z', 'c,a', b'101010'), (2, 'x', '', 1), (3, 'X', 'b', NULL), (4, 2, 3, 0)")
+	tk.MustExec("insert into t(id) values(5)")
+	tk.MustQuery("select id, e, s, b+0 from t order by id").Check(testkit.Rows(
+		"1 z a,c 42", "2 x  1", "3 x b <nil>", "4 Y a,b 0", "5 Y <nil> 5"))
+	tk.MustQuery("select e+0, s+0 from t where id = 1").Check(testkit.Rows("3 5"))
+
+	// ENUM and SET are compared by name with strings and by index with numbers.
+	tk.MustQuery("select id from t where e = 'Y' order by id").Check(testkit.Rows("4", "5"))
+	tk.MustQuery("select id from t where e = 3").Check(testkit.Rows("1"))
+	tk.MustQuery("select id from t where e > 'x'").Check(testkit.Rows("1"))
+	tk.MustQuery("select id from t where e > 1 order by id").Check(testkit.Rows("1", "4", "5"))
+	tk.MustQuery("select id from t where s = 'a,c'").Check(testkit.Rows("1"))
+	tk.MustQuery("select id from t where s = 3").Check(testkit.Rows("4"))
+	tk.MustQuery("select id from t where b = 42").Check(testkit.Rows("1"))
+	tk.MustQuery("select id from t where b = b'1'").Check(testkit.Rows("2"))
+
+	// Index lookups go through the index values.
+	tk.MustQuery("select id from t use index(idx_e) where e = 'x' order by id").Check(testkit.Rows("2", "3"))
+	tk.MustQuery("select id from t use index(idx_e) where e = 'w'").Check(testkit.Rows())
+	tk.MustQuery("select id from t use index(idx_e) where e in ('z', 'x') order by id").Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery("select id from t use index(idx_s) where s = 'a,b'").Check(testkit.Rows("4"))
+
+	// ENUM is sorted by its index, SET by its bitmask value.
+	tk.MustQuery("select e from t order by e, id").Check(testkit.Rows("x", "x", "Y", "Y", "z"))
+	tk.MustQuery("select id from t where s is not null order by s, id").Check(testkit.Rows("2", "3", "4", "1"))
+
+	tk.MustExec("update t set e = 'z', s = 'b,a', b = b'11' where id = 2")
+	tk.MustQuery("select e, s, b+0 from t where id = 2").Check(testkit.Rows("z a,b 3"))
+
+	createSQL := tk.MustQuery("show create table t").Rows()[0][1]
+	expected := "CREATE TABLE `t` (\n" +
+		"  `id` int(11) NOT NULL,\n" +
+		"  `e` enum('x','Y','z') DEFAULT 'Y',\n" +
+		"  `s` set('a','b','c') DEFAULT NULL,\n" +
+		"  `b` bit(6) DEFAULT b'101',\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  KEY `idx_e` (`e`),\n" +
+		"  KEY `idx_s` (`s`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"
+	c.Assert(createSQL, Equals, expected)
+
+	_, err := tk.Exec("insert into t(id, e) values(6, 'w')")
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("insert into t(id, s) values(6, 'a,d')")
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("insert into t(id, b) values(6, 64)")
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("create table t1(e enum('a', 'b') default 'c')")
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("create table t1(s set('a', 'b') default 'a,c')")
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("create table t1(e enum('a', 'A'))")
+	c.Assert(err, NotNil)
+}
+
 func (s *testSuiteP1) TestTablePKisHandleScan(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -1150,6 +1309,391 @@ func (s *testSuite) TestLimit(c *C) {
 	))
 }
 
+func (s *testSuite) TestSetOperation(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int, b varchar(10))")
+	tk.MustExec("create table t2(a int, b varchar(10))")
+	tk.MustExec("insert into t1 values(1, 'a'), (2, 'b'), (2, 'b'), (null, null)")
+	tk.MustExec("insert into t2 values(2, 'b'), (3, 'c'), (null, null)")
+
+	tk.MustQuery("select a from t1 union all select a from t2").Sort().Check(testkit.Rows("1", "2", "2", "2", "3", "<nil>", "<nil>"))
+	tk.MustQuery("select a from t1 union select a from t2").Sort().Check(testkit.Rows("1", "2", "3", "<nil>"))
+	tk.MustQuery("select a, b from t1 union distinct select a, b from t2 order by a").Check(testkit.Rows("<nil> <nil>", "1 a", "2 b", "3 c"))
+	tk.MustQuery("select a from t1 union all select a from t2 order by a desc limit 2").Check(testkit.Rows("3", "2"))
+	tk.MustQuery("(select a from t1 order by a limit 1) union all (select a from t2 order by a desc limit 1)").Sort().Check(testkit.Rows("3", "<nil>"))
+	tk.MustQuery("select a from t2 union all select a from t2 union select a from t2").Sort().Check(testkit.Rows("2", "3", "<nil>"))
+	tk.MustQuery("select a from t2 union select a from t2 union all select a from t2").Sort().Check(testkit.Rows("2", "2", "3", "3", "<nil>", "<nil>"))
+
+	// The result type is unified among all the selects.
+	tk.MustQuery("select a from t1 where a = 1 union select b from t2 where a = 3").Sort().Check(testkit.Rows("1", "c"))
+	tk.MustQuery("select 1 union select 'abc'").Sort().Check(testkit.Rows("1", "abc"))
+
+	tk.MustQuery("select a, b from t1 intersect select a, b from t2").Sort().Check(testkit.Rows("2 b", "<nil> <nil>"))
+	tk.MustQuery("select a from t1 except select a from t2").Check(testkit.Rows("1"))
+	tk.MustQuery("select a from t2 except select a from t1").Check(testkit.Rows("3"))
+	tk.MustQuery("select a from t1 union select a from t2 except select a from t1").Check(testkit.Rows("3"))
+	tk.MustQuery("select a from t1 except select a from t2 intersect select a from t1").Check(testkit.Rows("1"))
+
+	tk.MustQuery("select * from (select a from t1 union select a from t2) t where a > 1 order by a").Check(testkit.Rows("2", "3"))
+	tk.MustQuery("select a from t1 where a in (select a from t2 union select 1)").Sort().Check(testkit.Rows("1", "2", "2"))
+	tk.MustExec("insert into t2 (a, b) select a, b from t1 where a = 1 union select 4, 'd'")
+	tk.MustQuery("select a, b from t2 order by a").Check(testkit.Rows("<nil> <nil>", "1 a", "2 b", "3 c", "4 d"))
+
+	tk.MustGetErrCode("select a from t1 union select a, b from t2", mysql.ErrWrongNumberOfColumnsInSelect)
+	tk.MustGetErrCode("select a from t1 intersect select a, b from t2", mysql.ErrWrongNumberOfColumnsInSelect)
+}
+
+func (s *testSuite) TestWindowFunctions(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int)")
+	tk.MustExec("insert into t values(1, 1), (1, 2), (2, 1), (2, 3), (2, 3)")
+
+	tk.MustQuery("select a, b, row_number() over (partition by a order by b) from t").Sort().Check(testkit.Rows("1 1 1", "1 2 2", "2 1 1", "2 3 2", "2 3 3"))
+	tk.MustQuery("select b, rank() over w, dense_rank() over w from t window w as (order by b)").Sort().Check(testkit.Rows("1 1 1", "1 1 1", "2 3 2", "3 4 3", "3 4 3"))
+	tk.MustQuery("select a, b, row_number() over (w order by b) from t window w as (partition by a)").Sort().Check(testkit.Rows("1 1 1", "1 2 2", "2 1 1", "2 3 2", "2 3 3"))
+	tk.MustQuery("select a, b, lead(b) over w, lag(b, 1, 0) over w from t window w as (partition by a order by b)").Sort().Check(testkit.Rows("1 1 2 0", "1 2 <nil> 1", "2 1 3 0", "2 3 3 1", "2 3 <nil> 3"))
+	tk.MustQuery("select a, b, first_value(b) over (partition by a order by b desc) from t").Sort().Check(testkit.Rows("1 1 2", "1 2 2", "2 1 3", "2 3 3", "2 3 3"))
+
+	// Aggregate functions used as window functions.
+	tk.MustQuery("select a, sum(b) over (partition by a) from t").Sort().Check(testkit.Rows("1 3", "1 3", "2 7", "2 7", "2 7"))
+	tk.MustQuery("select b, sum(b) over (order by b) from t").Sort().Check(testkit.Rows("1 2", "1 2", "2 4", "3 10", "3 10"))
+	tk.MustQuery("select b, sum(b) over (order by b rows between 1 preceding and 1 following) from t").Sort().Check(testkit.Rows("1 2", "1 4", "2 6", "3 6", "3 8"))
+	tk.MustQuery("select b, count(*) over (order by b range between 1 preceding and current row) from t").Sort().Check(testkit.Rows("1 2", "1 2", "2 3", "3 3", "3 3"))
+	tk.MustQuery("select a, b, rank() over (order by a) as r from t order by r desc, b").Check(testkit.Rows("2 1 3", "2 3 3", "2 3 3", "1 1 1", "1 2 1"))
+
+	tk.MustGetErrCode("select row_number() over w from t", mysql.ErrWindowNoSuchWindow)
+	tk.MustGetErrCode("select a from t window w as (), w as ()", mysql.ErrWindowDuplicateName)
+	tk.MustGetErrCode("select sum(b) over (order by a, b range between 1 preceding and current row) from t", mysql.ErrWindowRangeFrameOrderType)
+	tk.MustGetErrCode("select a from t where row_number() over () > 1", mysql.ErrWindowInvalidWindowFuncUse)
+}
+
+func (s *testSuite) TestCTE(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int)")
+	tk.MustExec("insert into t values(1, 1), (1, 2), (2, 3)")
+
+	// Non-recursive common table expressions.
+	tk.MustQuery("with c as (select a, b from t where a = 1) select b from c").Sort().Check(testkit.Rows("1", "2"))
+	tk.MustQuery("with c(x, y) as (select a, sum(b) from t group by a) select c1.x, c2.y from c c1 join c c2 on c1.x = c2.x").Sort().Check(testkit.Rows("1 3", "2 3"))
+	tk.MustQuery("with c1 as (select a from t), c2 as (select a + 1 as a from c1) select count(*) from c1 join c2 on c1.a = c2.a").Check(testkit.Rows("2"))
+	tk.MustQuery("select a from t where b in (with c as (select 3) select * from c)").Check(testkit.Rows("2"))
+	// Without RECURSIVE, the name in the definition refers to the table.
+	tk.MustQuery("with t as (select * from t where a = 2) select * from t").Check(testkit.Rows("2 3"))
+
+	// Recursive common table expressions.
+	tk.MustQuery("with recursive c(n) as (select 1 union all select n + 1 from c where n < 5) select n from c").Sort().Check(testkit.Rows("1", "2", "3", "4", "5"))
+	tk.MustQuery("with recursive c(n) as (select 1 union select (n + 1) % 3 from c) select n from c").Sort().Check(testkit.Rows("0", "1", "2"))
+	tk.MustExec("drop table if exists emp")
+	tk.MustExec("create table emp(id int, name varchar(10), manager int)")
+	tk.MustExec("insert into emp values(1, 'a', null), (2, 'b', 1), (3, 'c', 1), (4, 'd', 2), (5, 'e', 4)")
+	tk.MustQuery("with recursive chain as (select id, name, 0 as lvl from emp where manager is null " +
+		"union all select e.id, e.name, chain.lvl + 1 from emp e join chain on e.manager = chain.id) " +
+		"select name, lvl from chain").Sort().Check(testkit.Rows("a 0", "b 1", "c 1", "d 2", "e 3"))
+
+	tk.MustExec("set @@cte_max_recursion_depth = 3")
+	tk.MustQuery("with recursive c(n) as (select 1 union all select n + 1 from c where n < 4) select count(*) from c").Check(testkit.Rows("4"))
+	tk.MustGetErrCode("with recursive c(n) as (select 1 union all select n + 1 from c where n < 5) select count(*) from c", mysql.ErrCTEMaxRecursionDepth)
+	tk.MustExec("set @@cte_max_recursion_depth = default")
+
+	tk.MustGetErrCode("with c as (select 1), c as (select 2) select * from c", mysql.ErrNonuniqTable)
+	tk.MustGetErrCode("with c(x, y) as (select 1) select * from c", mysql.ErrViewWrongList)
+	tk.MustGetErrCode("with recursive c(n) as (select n from c) select * from c", mysql.ErrCTERecursiveRequiresUnion)
+	tk.MustGetErrCode("with recursive c(n) as (select n + 1 from c union all select 1) select * from c", mysql.ErrCTERecursiveRequiresNonRecursiveFirst)
+	tk.MustGetErrCode("with recursive c(n) as (select 1 union all select count(*) from c) select * from c", mysql.ErrCTERecursiveForbidsAggregation)
+	tk.MustGetErrCode("with recursive c(n) as (select 1 union all select c1.n from c c1, c c2) select * from c", mysql.ErrCTERecursiveRequiresSingleReference)
+}
+
+func (s *testSuite) TestView(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop view if exists v1, v2")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int)")
+	tk.MustExec("insert into t values(1, 1), (2, 2), (3, 3)")
+
+	tk.MustExec("create view v1 as select a, b from t where a > 1")
+	tk.MustQuery("select * from v1").Sort().Check(testkit.Rows("2 2", "3 3"))
+	tk.MustQuery("select b from v1 where a = 3").Check(testkit.Rows("3"))
+	tk.MustExec("create view v2(x, s) as select a, a + b from v1")
+	tk.MustQuery("select x, s from v2 order by x").Check(testkit.Rows("2 4", "3 6"))
+	tk.MustQuery("select t.a, v2.s from t join v2 on t.a = v2.x").Sort().Check(testkit.Rows("2 4", "3 6"))
+	// The view reflects the latest data of the base table.
+	tk.MustExec("insert into t values(4, 4)")
+	tk.MustQuery("select count(*) from v1").Check(testkit.Rows("3"))
+
+	tk.MustGetErrCode("create view v1 as select 1", mysql.ErrTableExists)
+	tk.MustExec("create or replace view v1 as select a from t where a = 1")
+	tk.MustQuery("select * from v1").Check(testkit.Rows("1"))
+	// v2 is invalid since it references the column b which v1 no longer has.
+	tk.MustGetErrCode("select * from v2", mysql.ErrViewInvalid)
+	tk.MustGetErrCode("create view v3(x, y) as select a from t", mysql.ErrViewWrongList)
+	tk.MustGetErrCode("create view v3 as select a, a from t", mysql.ErrDupFieldName)
+	tk.MustGetErrCode("create or replace view t as select 1", mysql.ErrWrongObject)
+
+	tk.MustGetErrCode("insert into v1 values(1)", mysql.ErrNonInsertableTable)
+	tk.MustGetErrCode("update v1 set a = 2", mysql.ErrNonUpdatableTable)
+	tk.MustGetErrCode("delete from v1", mysql.ErrNonUpdatableTable)
+	tk.MustGetErrCode("alter table v1 add column c int", mysql.ErrWrongObject)
+
+	tk.MustQuery("show create view v1").Check(testkit.Rows("v1 CREATE ALGORITHM=UNDEFINED DEFINER=`root`@`%` SQL SECURITY DEFINER VIEW `v1` (`a`) AS select a from t where a = 1 utf8mb4 utf8mb4_bin"))
+	tk.MustGetErrCode("show create view t", mysql.ErrWrongObject)
+	tk.MustQuery("select table_name, check_option, security_type from information_schema.views where table_schema = 'test'").Sort().Check(
+		testkit.Rows("v1 NONE DEFINER", "v2 NONE DEFINER"))
+	tk.MustQuery("select table_type from information_schema.tables where table_schema = 'test' and table_name = 'v1'").Check(testkit.Rows("VIEW"))
+
+	tk.MustGetErrCode("drop view t", mysql.ErrWrongObject)
+	tk.MustGetErrCode("drop table v1", mysql.ErrBadTable)
+	tk.MustExec("drop view v1, v2")
+	tk.MustGetErrCode("select * from v1", mysql.ErrNoSuchTable)
+	tk.MustExec("drop view if exists v1")
+}
+
+func (s *testSuite) TestPrepared(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b varchar(10))")
+	tk.MustExec("insert into t values(1, 'a'), (2, 'b'), (3, 'c')")
+
+	tk.MustExec("prepare stmt from 'select b from t where a > ? order by a limit ?'")
+	tk.MustExec("set @a = 1, @b = 1")
+	tk.MustQuery("execute stmt using @a, @b").Check(testkit.Rows("b"))
+	tk.MustExec("set @b = 5")
+	tk.MustQuery("execute stmt using @a, @b").Check(testkit.Rows("b", "c"))
+	tk.MustGetErrCode("execute stmt using @a", mysql.ErrWrongParamCount)
+
+	// The statement text can be read from a user variable.
+	tk.MustExec("set @sql = 'update t set b = ? where a = ?'")
+	tk.MustExec("prepare stmt2 from @sql")
+	tk.MustExec("set @a = 'x', @b = 3")
+	tk.MustExec("execute stmt2 using @a, @b")
+	tk.MustQuery("select b from t where a = 3").Check(testkit.Rows("x"))
+	tk.MustExec("deallocate prepare stmt2")
+	tk.MustGetErrCode("execute stmt2 using @a, @b", mysql.ErrPreparedStmtNotFound)
+	tk.MustGetErrCode("deallocate prepare stmt2", mysql.ErrPreparedStmtNotFound)
+
+	// The prepared statement is rebuilt after the schema changes.
+	tk.MustExec("alter table t add column c int")
+	tk.MustExec("set @a = 2, @b = 1")
+	tk.MustQuery("execute stmt using @a, @b").Check(testkit.Rows("x"))
+	tk.MustExec("drop prepare stmt")
+
+	tk.MustGetErrCode("prepare stmt from 'select 1; select 2'", mysql.ErrPrepareMulti)
+	tk.MustGetErrCode("prepare stmt from 'create table t2(a int default ?)'", mysql.ErrPrepareDDL)
+	tk.MustGetErrCode("select ?", mysql.ErrSyntax)
+
+	// Prepare and execute through the binary protocol interface.
+	stmtID, paramCount, fields, err := tk.Se.PrepareStmt("select a, b from t where a = ?")
+	c.Assert(err, IsNil)
+	c.Assert(paramCount, Equals, 1)
+	c.Assert(fields, HasLen, 2)
+	rs, err := tk.Se.ExecutePreparedStmt(context.Background(), stmtID, []types.Datum{types.NewIntDatum(1)})
+	c.Assert(err, IsNil)
+	tk.ResultSetToResult(rs, Commentf("%v", stmtID)).Check(testkit.Rows("1 a"))
+	c.Assert(tk.Se.DropPreparedStmt(stmtID), IsNil)
+	_, err = tk.Se.ExecutePreparedStmt(context.Background(), stmtID, []types.Datum{types.NewIntDatum(1)})
+	c.Assert(plannercore.ErrStmtNotFound.Equal(err), IsTrue)
+}
+
+func (s *testSuite) TestPreparedPlanCache(c *C) {
+	orgEnable := plannercore.PreparedPlanCacheEnabled()
+	defer plannercore.SetPreparedPlanCache(orgEnable)
+	plannercore.SetPreparedPlanCache(true)
+
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b int, c int, key idx_b(b))")
+	tk.MustExec("insert into t values(1, 1, 1), (2, 2, 2), (3, 3, 3)")
+
+	// The ranges of the cached plan are rebuilt with the new parameters.
+	tk.MustExec("prepare stmt from 'select c from t where a = ?'")
+	tk.MustExec("set @a = 1")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("1"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustExec("set @a = 2")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	// A parameter of another type can't reuse the plan.
+	tk.MustExec("set @a = '3'")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	tk.MustExec("prepare stmt from 'select a from t where b > ? and b < ? order by a'")
+	tk.MustExec("set @a = 3, @b = 1")
+	tk.MustQuery("execute stmt using @a, @b").Check(testkit.Rows())
+	tk.MustExec("set @a = 1, @b = 3")
+	tk.MustQuery("execute stmt using @a, @b").Check(testkit.Rows("2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// A condition on the parameters only is not folded into the plan.
+	tk.MustExec("prepare stmt from 'select a from t where a > 2 and ? > 0'")
+	tk.MustExec("set @a = 0")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows())
+	tk.MustExec("set @a = 1")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// The functions like NOW() are evaluated again on every execution of the cached plan.
+	tk.MustExec("prepare stmt from 'select now(6)'")
+	first := tk.MustQuery("execute stmt").Rows()[0][0]
+	time.Sleep(10 * time.Millisecond)
+	second := tk.MustQuery("execute stmt").Rows()[0][0]
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	c.Assert(second, Not(Equals), first)
+
+	// The cached plan is invalidated after the schema changes.
+	tk.MustExec("prepare stmt from 'select b from t where a = ?'")
+	tk.MustExec("set @a = 1")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("1"))
+	tk.MustExec("alter table t add column d int")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("1"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("1"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	tk.MustExec("prepare stmt from 'update t set c = ? where a = ?'")
+	tk.MustExec("set @a = 10, @b = 1")
+	tk.MustExec("execute stmt using @a, @b")
+	tk.MustExec("set @a = 20, @b = 2")
+	tk.MustExec("execute stmt using @a, @b")
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustQuery("select c from t order by a").Check(testkit.Rows("10", "20", "3"))
+
+	// A statement using a parameter in LIMIT is not cached.
+	tk.MustExec("prepare stmt from 'select a from t order by a limit ?'")
+	tk.MustExec("set @a = 1")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("1"))
+	tk.MustExec("set @a = 2")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	stmtID, _, _, err := tk.Se.PrepareStmt("select c from t where b = ?")
+	c.Assert(err, IsNil)
+	for i, expected := range []string{"10", "20", "3"} {
+		rs, err := tk.Se.ExecutePreparedStmt(context.Background(), stmtID, []types.Datum{types.NewIntDatum(int64(i + 1))})
+		c.Assert(err, IsNil)
+		tk.ResultSetToResult(rs, Commentf("%v", stmtID)).Check(testkit.Rows(expected))
+	}
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	_, err = tk.Exec("set @@last_plan_from_cache = 1")
+	c.Assert(err, NotNil)
+}
+
+func (s *testSuite) TestPointGet(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b int, c varchar(10), unique key uk_b_c(b, c))")
+	tk.MustExec("insert into t values(1, 1, 'a'), (2, 2, 'b'), (3, 3, 'c')")
+
+	tk.MustQuery("explain select * from t where a = 1").Check(testkit.Rows("Point_Get_1 1.00 root table:t, handle:1"))
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 1 a"))
+	tk.MustQuery("select c, c, b from t where 2 = a").Check(testkit.Rows("b b 2"))
+	tk.MustQuery("select * from t where a = 4").Check(testkit.Rows())
+	tk.MustQuery("select * from t where a = 2147483648").Check(testkit.Rows())
+	tk.MustQuery("select * from t where a = '1'").Check(testkit.Rows("1 1 a"))
+	tk.MustQuery("explain select a from t where b = 2 and c = 'b'").Check(testkit.Rows("Point_Get_1 1.00 root table:t, index:b, c"))
+	tk.MustQuery("select a from t where b = 2 and c = 'b'").Check(testkit.Rows("2"))
+	tk.MustQuery("select a from t where c = 'c' and b = 3").Check(testkit.Rows("3"))
+	tk.MustQuery("select a from t where b = 2 and c = 'c'").Check(testkit.Rows())
+
+	// The uncommitted changes of the transaction are visible to the point get.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values(4, 4, 'd')")
+	tk.MustExec("update t set c = 'x' where a = 2")
+	tk.MustExec("delete from t where a = 3")
+	tk.MustQuery("select * from t where a = 4").Check(testkit.Rows("4 4 d"))
+	tk.MustQuery("select * from t where a = 2").Check(testkit.Rows("2 2 x"))
+	tk.MustQuery("select a from t where b = 2 and c = 'x'").Check(testkit.Rows("2"))
+	tk.MustQuery("select a from t where b = 2 and c = 'b'").Check(testkit.Rows())
+	tk.MustQuery("select * from t where a = 3").Check(testkit.Rows())
+	tk.MustExec("rollback")
+	tk.MustQuery("select * from t where a = 4").Check(testkit.Rows())
+	tk.MustQuery("select * from t where a = 3").Check(testkit.Rows("3 3 c"))
+
+	// The handle and the index values of the cached plan are rebuilt with the new parameters.
+	orgEnable := plannercore.PreparedPlanCacheEnabled()
+	defer plannercore.SetPreparedPlanCache(orgEnable)
+	plannercore.SetPreparedPlanCache(true)
+	tk.MustExec("prepare stmt from 'select c from t where a = ?'")
+	tk.MustExec("set @a = 1")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("a"))
+	tk.MustExec("set @a = 2")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("b"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustExec("prepare stmt from 'select a from t where b = ? and c = ?'")
+	tk.MustExec("set @a = 1, @b = 'a'")
+	tk.MustQuery("execute stmt using @a, @b").Check(testkit.Rows("1"))
+	tk.MustExec("set @a = 3, @b = 'c'")
+	tk.MustQuery("execute stmt using @a, @b").Check(testkit.Rows("3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// The cached plan returns nothing if no row can have the parameter as its handle.
+	tk.MustExec("prepare stmt from 'select c from t where a = ?'")
+	tk.MustExec("set @a = '1'")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("a"))
+	tk.MustExec("set @a = '1.5'")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows())
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustExec("set @a = '99999999999999999999'")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows())
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustExec("set @a = '2'")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("b"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+}
+
+func (s *testSuite) TestBatchPointGet(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b int, c int, unique key uk_b_c(b, c))")
+	tk.MustExec("insert into t values(1, 1, 1), (2, 2, 2), (3, 3, 3), (4, 4, 4)")
+
+	tk.MustQuery("explain select * from t where a in (1, 3)").Check(testkit.Rows("Batch_Point_Get_1 2.00 root table:t, handle:[1 3]"))
+	tk.MustQuery("select * from t where a in (3, 1, 5, 3)").Check(testkit.Rows("3 3 3", "1 1 1"))
+	tk.MustQuery("select b from t where (b, c) in ((2, 2), (4, 4), (4, 5))").Check(testkit.Rows("2", "4"))
+	tk.MustQuery("select b from t where (c, b) in ((2, 2), (3, 3))").Check(testkit.Rows("2", "3"))
+
+	// The uncommitted changes of the transaction are visible to the batch point get.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values(5, 5, 5)")
+	tk.MustExec("delete from t where a = 1")
+	tk.MustExec("update t set c = 10 where a = 2")
+	tk.MustQuery("select * from t where a in (1, 2, 5)").Check(testkit.Rows("2 2 10", "5 5 5"))
+	tk.MustQuery("select a from t where (b, c) in ((1, 1), (2, 2), (2, 10), (5, 5))").Check(testkit.Rows("2", "5"))
+	tk.MustExec("rollback")
+	tk.MustQuery("select * from t where a in (1, 2, 5)").Check(testkit.Rows("1 1 1", "2 2 2"))
+
+	orgEnable := plannercore.PreparedPlanCacheEnabled()
+	defer plannercore.SetPreparedPlanCache(orgEnable)
+	plannercore.SetPreparedPlanCache(true)
+	tk.MustExec("prepare stmt from 'select c from t where a in (?, ?)'")
+	tk.MustExec("set @a = 1, @b = 2")
+	tk.MustQuery("execute stmt using @a, @b").Check(testkit.Rows("1", "2"))
+	tk.MustExec("set @a = 3, @b = 4")
+	tk.MustQuery("execute stmt using @a, @b").Check(testkit.Rows("3", "4"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// The handles no row can have are skipped.
+	tk.MustExec("set @a = '1', @b = '2'")
+	tk.MustQuery("execute stmt using @a, @b").Check(testkit.Rows("1", "2"))
+	tk.MustExec("set @a = '1.5', @b = '2'")
+	tk.MustQuery("execute stmt using @a, @b").Check(testkit.Rows("2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustExec("set @a = '3', @b = '99999999999999999999'")
+	tk.MustQuery("execute stmt using @a, @b").Check(testkit.Rows("3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+}
+
 type testSuite2 struct {
 	*baseTestSuite
 }
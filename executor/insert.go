@@ -38,6 +38,17 @@ func (e *InsertExec) exec(ctx context.Context, rows [][]types.Datum) error {
 	}
 	sessVars.GetWriteStmtBufs().BufStore = kv.NewBufferStore(txn, kv.TempTxnMemBufCap)
 	sessVars.StmtCtx.AddRecordRows(uint64(len(rows)))
+	// The keys are only read when the constraints are checked in place, otherwise they are
+	// presumed not to exist and checked on commit. Read them in batch instead of one request per key.
+	if sessVars.ConstraintCheckInPlace {
+		toBeCheckedRows, err := getKeysNeedCheck(ctx, e.ctx, e.Table, rows)
+		if err != nil {
+			return err
+		}
+		if _, err = prefetchUniqueIndices(ctx, txn, toBeCheckedRows); err != nil {
+			return err
+		}
+	}
 	for _, row := range rows {
 		if _, err := e.addRecord(ctx, row); err != nil {
 			return err
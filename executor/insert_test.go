@@ -14,13 +14,22 @@
 package executor_test
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/store/tikv"
+	"github.com/pingcap/tidb/store/tikv/tikvrpc"
 	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/util/testkit"
 )
 
@@ -69,3 +78,52 @@ func (s *testSuite3) TestAllocateContinuousRowID(c *C) {
 	}
 	wg.Wait()
 }
+
+// tableReadCounter counts the point reads of a table sent to the storage.
+type tableReadCounter struct {
+	tikv.Client
+	tableID int64
+	count   int64
+}
+
+func (c *tableReadCounter) SendRequest(ctx context.Context, addr string, req *tikvrpc.Request, timeout time.Duration) (*tikvrpc.Response, error) {
+	if req.Type == tikvrpc.CmdGet {
+		if tableID := atomic.LoadInt64(&c.tableID); tableID != 0 && tablecodec.DecodeTableID(req.Get().Key) == tableID {
+			atomic.AddInt64(&c.count, 1)
+		}
+	}
+	return c.Client.SendRequest(ctx, addr, req, timeout)
+}
+
+func (s *testSuite3) TestInsertPrefetch(c *C) {
+	counter := &tableReadCounter{}
+	store, err := mockstore.NewMockTikvStore(mockstore.WithHijackClient(func(client tikv.Client) tikv.Client {
+		counter.Client = client
+		return counter
+	}))
+	c.Assert(err, IsNil)
+	defer store.Close()
+	dom, err := session.BootstrapSession(store)
+	c.Assert(err, IsNil)
+	defer dom.Close()
+
+	tk := testkit.NewTestKitWithInit(c, store)
+	tk.MustExec("create table t (a int primary key, b int, unique key(b))")
+	tbl, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	atomic.StoreInt64(&counter.tableID, tbl.Meta().ID)
+
+	// The keys are presumed not to exist, the plain INSERT doesn't read them.
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+	c.Assert(atomic.LoadInt64(&counter.count), Equals, int64(0))
+
+	// The keys are read in batch when the constraints are checked in place, each handle and
+	// unique key is read once, the later checks of the rows are served by the snapshot cache.
+	tk.MustExec("set @@tidb_constraint_check_in_place = 1")
+	atomic.StoreInt64(&counter.count, 0)
+	tk.MustExec("insert into t values (3, 3), (4, 4)")
+	c.Assert(atomic.LoadInt64(&counter.count), Equals, int64(4))
+	_, err = tk.Exec("insert into t values (5, 5), (6, 1)")
+	c.Assert(err, NotNil)
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 1", "2 2", "3 3", "4 4"))
+}
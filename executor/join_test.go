@@ -389,6 +389,39 @@ func (s *testSuiteJoin3) TestMergejoinOrder(c *C) {
 	))
 }
 
+func (s *testSuiteJoin3) TestIndexLookupJoin(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_init_chunk_size=2")
+	tk.MustExec("set @@tidb_index_lookup_join_concurrency=2")
+	tk.MustExec("drop table if exists t, s")
+	tk.MustExec("create table t(a int, b int, index idx_a(a))")
+	tk.MustExec("create table s(a int primary key, b int)")
+	tk.MustExec("insert into t values(1, 1), (2, 2), (2, 3), (3, 3), (4, 4), (5, 5), (null, 6)")
+	tk.MustExec("insert into s values(1, 10), (2, 20), (4, 40), (6, 60)")
+
+	// The inner side is read by the handle of s.
+	tk.MustQuery("select /*+ INL_JOIN(s) */ * from t join s on t.a = s.a").Sort().Check(testkit.Rows(
+		"1 1 1 10", "2 2 2 20", "2 3 2 20", "4 4 4 40"))
+	tk.MustQuery("select /*+ INL_JOIN(s) */ * from t left join s on t.a = s.a and s.b > 10").Sort().Check(testkit.Rows(
+		"1 1 <nil> <nil>", "2 2 2 20", "2 3 2 20", "3 3 <nil> <nil>", "4 4 4 40", "5 5 <nil> <nil>", "<nil> 6 <nil> <nil>"))
+	// The inner side is read by the index of t.
+	tk.MustQuery("select /*+ INL_JOIN(t) */ s.a, t.b from s join t on t.a = s.a and t.b > 2").Sort().Check(testkit.Rows(
+		"2 3", "4 4"))
+	tk.MustQuery("select /*+ INL_JOIN(t) */ s.a, t.a from s left join t on t.a = s.a").Sort().Check(testkit.Rows(
+		"1 1", "2 2", "2 2", "4 4", "6 <nil>"))
+
+	// The inner side should see the uncommitted rows of the transaction.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values(6, 7)")
+	tk.MustExec("delete from s where a = 1")
+	tk.MustQuery("select /*+ INL_JOIN(t) */ s.a, t.b from s join t on t.a = s.a").Sort().Check(testkit.Rows(
+		"2 2", "2 3", "4 4", "6 7"))
+	tk.MustQuery("select /*+ INL_JOIN(s) */ t.a, s.b from t join s on t.a = s.a").Sort().Check(testkit.Rows(
+		"2 20", "2 20", "4 40", "6 60"))
+	tk.MustExec("rollback")
+}
+
 func (s *testSuiteJoin1) TestEmbeddedOuterJoin(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -412,3 +445,103 @@ func (s *testSuiteJoin1) TestInjectProjOnTopN(c *C) {
 		"2",
 	))
 }
+
+func (s *testSuiteJoin1) TestSubquerySameTable(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int)")
+	tk.MustExec("insert t values (1), (2)")
+	result := tk.MustQuery("select a from t where exists(select 1 from t as x where x.a < t.a)")
+	result.Check(testkit.Rows("2"))
+	result = tk.MustQuery("select a from t where not exists(select 1 from t as x where x.a < t.a)")
+	result.Check(testkit.Rows("1"))
+}
+
+func (s *testSuiteJoin1) TestSubquery(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (c int, d int)")
+	tk.MustExec("insert t values (1, 1)")
+	tk.MustExec("insert t values (2, 2)")
+	tk.MustExec("insert t values (3, 4)")
+	tk.MustExec("commit")
+
+	result := tk.MustQuery("select * from t where exists(select * from t k where t.c = k.c having sum(c) = 1)")
+	result.Check(testkit.Rows("1 1"))
+	result = tk.MustQuery("select * from t where exists(select k.c, k.d from t k, t p where t.c = k.d)")
+	result.Check(testkit.Rows("1 1", "2 2"))
+	result = tk.MustQuery("select 1 = (select count(*) from t where t.c = k.d) from t k")
+	result.Sort().Check(testkit.Rows("0", "1", "1"))
+	result = tk.MustQuery("select 1 = (select count(*) from t where exists( select * from t m where t.c = k.d)) from t k")
+	result.Sort().Check(testkit.Rows("0", "1", "1"))
+	result = tk.MustQuery("select t.c = any (select count(*) from t) from t")
+	result.Sort().Check(testkit.Rows("0", "0", "1"))
+	result = tk.MustQuery("select * from t where (t.c, 6) = any (select count(*), sum(t.c) from t)")
+	result.Check(testkit.Rows("3 4"))
+	result = tk.MustQuery("select t.c from t where (t.c) < all (select count(*) from t)")
+	result.Check(testkit.Rows("1", "2"))
+	result = tk.MustQuery("select t.c from t where (t.c, t.d) = any (select * from t)")
+	result.Sort().Check(testkit.Rows("1", "2", "3"))
+	result = tk.MustQuery("select t.c from t where (t.c, t.d) != all (select * from t)")
+	result.Check(testkit.Rows())
+	result = tk.MustQuery("select (select count(*) from t where t.c = k.d) from t k")
+	result.Sort().Check(testkit.Rows("0", "1", "1"))
+	result = tk.MustQuery("select t.c from t where (t.c, t.d) != all (select * from t k where k.c < t.c)")
+	result.Sort().Check(testkit.Rows("1", "2", "3"))
+	result = tk.MustQuery("select t.c from t where t.c != any (select c from t)")
+	result.Sort().Check(testkit.Rows("1", "2", "3"))
+	result = tk.MustQuery("select t.c from t where t.c = all (select c from t where c = 1)")
+	result.Check(testkit.Rows("1"))
+	result = tk.MustQuery("select t.c, (select d from t k where k.c = t.c) from t order by t.c")
+	result.Check(testkit.Rows("1 1", "2 2", "3 4"))
+
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int)")
+	tk.MustExec("insert t values (1), (2), (3)")
+	result = tk.MustQuery("select (select k.id from t as k where k.id = t.id + 1) from t order by t.id")
+	result.Check(testkit.Rows("2", "3", "<nil>"))
+	result = tk.MustQuery("select (select max(id) from t)")
+	result.Check(testkit.Rows("3"))
+	result = tk.MustQuery("select exists (select id from t where id > 2), not exists (select id from t where id > 3)")
+	result.Check(testkit.Rows("1 1"))
+	_, err := tk.Exec("select (select id from t)")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[executor:1242]Subquery returns more than 1 row")
+	err = tk.QueryToErr("select id, (select id from t as k where k.id >= t.id) from t")
+	c.Assert(err, NotNil)
+}
+
+func (s *testSuiteJoin1) TestInSubquery(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int)")
+	tk.MustExec("insert t values (1, 1), (2, 1)")
+	result := tk.MustQuery("select m1.a from t as m1 where m1.a in (select m2.b from t as m2)")
+	result.Check(testkit.Rows("1"))
+	result = tk.MustQuery("select m1.a from t as m1 where (3, m1.b) not in (select * from t as m2)")
+	result.Sort().Check(testkit.Rows("1", "2"))
+	result = tk.MustQuery("select m1.a from t as m1 where m1.a in (select 1 from t as m2 where m2.a > m1.b)")
+	result.Check(testkit.Rows("1"))
+	result = tk.MustQuery("select a in (select b from t) from t order by a")
+	result.Check(testkit.Rows("1", "0"))
+
+	tk.MustExec("drop table if exists t1")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("insert t1 values (1), (null)")
+	result = tk.MustQuery("select a, a in (select a from t1 where a is not null) from t1 order by a")
+	result.Check(testkit.Rows("<nil> <nil>", "1 1"))
+	result = tk.MustQuery("select 2 not in (select a from t1)")
+	result.Check(testkit.Rows("<nil>"))
+	result = tk.MustQuery("select * from t where a not in (select a from t1)")
+	result.Check(testkit.Rows())
+	result = tk.MustQuery("select * from t where a not in (select a from t1 where a is not null)")
+	result.Check(testkit.Rows("2 1"))
+
+	tk.MustExec("set @@tidb_opt_insubq_to_join_and_agg = 0")
+	result = tk.MustQuery("select m1.a from t as m1 where m1.a in (select m2.b from t as m2)")
+	result.Check(testkit.Rows("1"))
+	tk.MustExec("set @@tidb_opt_insubq_to_join_and_agg = 1")
+}
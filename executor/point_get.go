@@ -0,0 +1,178 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/terror"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func (b *executorBuilder) buildPointGet(p *plannercore.PointGetPlan) Executor {
+	if p.IsTableDual {
+		// The handle of a cached plan is rebound to a value no row can have.
+		return &TableDualExec{baseExecutor: newBaseExecutor(b.ctx, p.Schema(), p.ExplainID())}
+	}
+	return &PointGetExecutor{
+		baseExecutor: newBaseExecutor(b.ctx, p.Schema(), p.ExplainID()),
+		tblInfo:      p.TblInfo,
+		idxInfo:      p.IndexInfo,
+		idxVals:      p.IndexValues,
+		handle:       p.Handle,
+		columns:      pointGetColumns(p.TblInfo, p.Schema()),
+	}
+}
+
+// PointGetExecutor executes point select query.
+// It reads the row through the union store of the transaction, so the
+// uncommitted changes of the transaction are visible to it.
+type PointGetExecutor struct {
+	baseExecutor
+
+	tblInfo *model.TableInfo
+	handle  int64
+	idxInfo *model.IndexInfo
+	idxVals []types.Datum
+	columns []*table.Column
+	done    bool
+}
+
+// Open implements the Executor interface.
+func (e *PointGetExecutor) Open(context.Context) error {
+	e.done = false
+	return nil
+}
+
+// Close implements the Executor interface.
+func (e *PointGetExecutor) Close() error {
+	return nil
+}
+
+// Next implements the Executor interface.
+func (e *PointGetExecutor) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	handle := e.handle
+	if e.idxInfo != nil {
+		idxKey, ok, err := encodeUniqueIndexKey(e.ctx, e.tblInfo, e.idxInfo, e.idxVals)
+		if err != nil || !ok {
+			return err
+		}
+		handleVal, err := getValueFromTxn(ctx, txn, idxKey)
+		if err != nil || len(handleVal) == 0 {
+			return err
+		}
+		handle, err = tables.DecodeHandle(handleVal)
+		if err != nil {
+			return err
+		}
+	}
+
+	rowVal, err := getValueFromTxn(ctx, txn, tablecodec.EncodeRowKeyWithHandle(e.tblInfo.ID, handle))
+	if err != nil {
+		return err
+	}
+	if len(rowVal) == 0 {
+		if e.idxInfo != nil {
+			return kv.ErrNotExist.GenWithStack("inconsistent extra index %s, handle %d not found in table",
+				e.idxInfo.Name.O, handle)
+		}
+		return nil
+	}
+	return decodeRowValToChunk(e.ctx, e.tblInfo, e.columns, handle, rowVal, req)
+}
+
+// pointGetColumns returns the table columns of the schema of a point get plan.
+func pointGetColumns(tblInfo *model.TableInfo, schema *expression.Schema) []*table.Column {
+	columns := make([]*table.Column, 0, schema.Len())
+	for _, col := range schema.Columns {
+		for _, colInfo := range tblInfo.Columns {
+			if colInfo.ID == col.ID {
+				columns = append(columns, table.ToColumn(colInfo))
+				break
+			}
+		}
+	}
+	return columns
+}
+
+// getValueFromTxn reads the value of the key through the union store of the transaction.
+// It returns a nil value if the key doesn't exist.
+func getValueFromTxn(ctx context.Context, txn kv.Transaction, key kv.Key) ([]byte, error) {
+	val, err := txn.Get(ctx, key)
+	if kv.IsErrNotFound(err) {
+		return nil, nil
+	}
+	return val, err
+}
+
+// encodeUniqueIndexKey encodes the key of the unique index from the index values.
+// ok is false if no row can match the values, e.g. the values contain null or
+// can't be converted to the types of the index columns.
+func encodeUniqueIndexKey(sctx sessionctx.Context, tblInfo *model.TableInfo, idxInfo *model.IndexInfo,
+	idxVals []types.Datum) (key kv.Key, ok bool, err error) {
+	sc := sctx.GetSessionVars().StmtCtx
+	vals := make([]types.Datum, len(idxVals))
+	for i, idxCol := range idxInfo.Columns {
+		colInfo := tblInfo.Columns[idxCol.Offset]
+		vals[i], err = idxVals[i].ConvertTo(sc, &colInfo.FieldType)
+		if err != nil {
+			if terror.ErrorEqual(types.ErrOverflow, err) {
+				return nil, false, nil
+			}
+			return nil, false, err
+		}
+		cmp, err := vals[i].CompareDatum(sc, &idxVals[i])
+		if err != nil || cmp != 0 {
+			return nil, false, err
+		}
+	}
+	idx := tables.NewIndex(tblInfo.ID, tblInfo, idxInfo)
+	key, distinct, err := idx.GenIndexKey(sc, vals, 0, nil)
+	if err != nil || !distinct {
+		return nil, false, err
+	}
+	return key, true, nil
+}
+
+// decodeRowValToChunk decodes the row value and appends the columns to the chunk.
+func decodeRowValToChunk(sctx sessionctx.Context, tblInfo *model.TableInfo, columns []*table.Column,
+	handle int64, rowVal []byte, chk *chunk.Chunk) error {
+	row, _, err := tables.DecodeRawRowData(sctx, tblInfo, handle, columns, rowVal)
+	if err != nil {
+		return err
+	}
+	for i := range row {
+		chk.AppendDatum(i, &row[i])
+	}
+	return nil
+}
@@ -97,12 +97,16 @@ func (e *ShowExec) fetchAll(ctx context.Context) error {
 		return e.fetchShowCreateTable()
 	case ast.ShowCreateDatabase:
 		return e.fetchShowCreateDatabase()
+	case ast.ShowCreateView:
+		return e.fetchShowCreateView()
 	case ast.ShowDatabases:
 		return e.fetchShowDatabases()
 	case ast.ShowTables:
 		return e.fetchShowTables()
 	case ast.ShowVariables:
 		return e.fetchShowVariables()
+	case ast.ShowStatus:
+		return e.fetchShowStatus()
 	case ast.ShowWarnings:
 		return e.fetchShowWarnings(false)
 	case ast.ShowErrors:
@@ -146,7 +150,11 @@ func (e *ShowExec) fetchShowTables() error {
 	var tableTypes = make(map[string]string)
 	for _, v := range e.is.SchemaTables(e.DBName) {
 		tableNames = append(tableNames, v.Meta().Name.O)
-		tableTypes[v.Meta().Name.O] = "BASE TABLE"
+		if v.Meta().IsView() {
+			tableTypes[v.Meta().Name.O] = "VIEW"
+		} else {
+			tableTypes[v.Meta().Name.O] = "BASE TABLE"
+		}
 	}
 	sort.Strings(tableNames)
 	for _, v := range tableNames {
@@ -204,6 +212,32 @@ func (e *ShowExec) fetchShowVariables() (err error) {
 	return nil
 }
 
+func (e *ShowExec) fetchShowStatus() error {
+	sessionVars := e.ctx.GetSessionVars()
+	statusVars, err := variable.GetStatusVars(sessionVars)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	names := make([]string, 0, len(statusVars))
+	for name := range statusVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		v := statusVars[name]
+		// Session only status variables are not shown by `show global status`.
+		if e.GlobalScope && v.Scope == variable.ScopeSession {
+			continue
+		}
+		value, err := types.ToString(v.Value)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		e.appendRow([]interface{}{name, value})
+	}
+	return nil
+}
+
 func getDefaultCollate(charsetName string) string {
 	for _, c := range charset.GetSupportedCharsets() {
 		if strings.EqualFold(c.Name, charsetName) {
@@ -273,10 +307,26 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 					if !mysql.HasNotNullFlag(col.Flag) {
 						buf.WriteString(" DEFAULT NULL")
 					}
+				case "CURRENT_TIMESTAMP":
+					buf.WriteString(" DEFAULT CURRENT_TIMESTAMP")
+					buf.WriteString(table.OptionalFsp(&col.FieldType))
 				default:
 					defaultValStr := fmt.Sprintf("%v", defaultValue)
+					// If column is timestamp, and default value is not current_timestamp, should convert the default value to the current session time zone.
+					if col.Tp == mysql.TypeTimestamp && defaultValStr != types.ZeroDatetimeStr {
+						timeValue, err := table.GetColDefaultValue(ctx, col)
+						if err != nil {
+							return err
+						}
+						defaultValStr = timeValue.GetMysqlTime().String()
+					}
 
-					fmt.Fprintf(buf, " DEFAULT '%s'", format.OutputFormat(defaultValStr))
+					if col.Tp == mysql.TypeBit {
+						defaultValBinaryLiteral := types.BinaryLiteral(defaultValStr)
+						fmt.Fprintf(buf, " DEFAULT %s", defaultValBinaryLiteral.ToBitLiteralString(true))
+					} else {
+						fmt.Fprintf(buf, " DEFAULT '%s'", format.OutputFormat(defaultValStr))
+					}
 				}
 			}
 			if mysql.HasOnUpdateNowFlag(col.Flag) {
@@ -383,17 +433,62 @@ func (e *ShowExec) fetchShowCreateTable() error {
 		return errors.Trace(err)
 	}
 
+	tableInfo := tb.Meta()
+	if tableInfo.IsView() {
+		e.appendShowCreateView(tableInfo)
+		return nil
+	}
+
 	allocator := tb.Allocator(e.ctx)
 	var buf bytes.Buffer
 	// TODO: let the result more like MySQL.
-	if err = ConstructResultOfShowCreateTable(e.ctx, tb.Meta(), allocator, &buf); err != nil {
+	if err = ConstructResultOfShowCreateTable(e.ctx, tableInfo, allocator, &buf); err != nil {
 		return err
 	}
 
-	e.appendRow([]interface{}{tb.Meta().Name.O, buf.String()})
+	e.appendRow([]interface{}{tableInfo.Name.O, buf.String()})
+	return nil
+}
+
+// ConstructResultOfShowCreateView constructs the result for show create view.
+func ConstructResultOfShowCreateView(ctx sessionctx.Context, tableInfo *model.TableInfo, buf *bytes.Buffer) {
+	sqlMode := ctx.GetSessionVars().SQLMode
+	viewInfo := tableInfo.View
+	fmt.Fprintf(buf, "CREATE ALGORITHM=%s ", viewInfo.Algorithm)
+	fmt.Fprintf(buf, "DEFINER=%s@%s ", escape(model.NewCIStr(viewInfo.Definer.Username), sqlMode), escape(model.NewCIStr(viewInfo.Definer.Hostname), sqlMode))
+	fmt.Fprintf(buf, "SQL SECURITY %s ", viewInfo.Security)
+	fmt.Fprintf(buf, "VIEW %s (", escape(tableInfo.Name, sqlMode))
+	for i, col := range tableInfo.Columns {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(escape(col.Name, sqlMode))
+	}
+	fmt.Fprintf(buf, ") AS %s", viewInfo.SelectStmt)
+	if viewInfo.CheckOption != model.CheckOptionNone {
+		fmt.Fprintf(buf, " WITH %s CHECK OPTION", viewInfo.CheckOption)
+	}
+}
+
+// fetchShowCreateView composes show create view result.
+func (e *ShowExec) fetchShowCreateView() error {
+	tb, err := e.getTable()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !tb.Meta().IsView() {
+		return ErrWrongObject.GenWithStackByArgs(e.DBName.O, tb.Meta().Name.O, "VIEW")
+	}
+	e.appendShowCreateView(tb.Meta())
 	return nil
 }
 
+func (e *ShowExec) appendShowCreateView(tableInfo *model.TableInfo) {
+	var buf bytes.Buffer
+	ConstructResultOfShowCreateView(e.ctx, tableInfo, &buf)
+	e.appendRow([]interface{}{tableInfo.Name.O, buf.String(), tableInfo.Charset, tableInfo.Collate})
+}
+
 // ConstructResultOfShowCreateDatabase constructs the result for show create database.
 func ConstructResultOfShowCreateDatabase(ctx sessionctx.Context, dbInfo *model.DBInfo, ifNotExists bool, buf *bytes.Buffer) (err error) {
 	sqlMode := ctx.GetSessionVars().SQLMode
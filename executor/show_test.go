@@ -51,6 +51,15 @@ func (s *testSuite5) TestShowErrors(c *C) {
 	tk.MustQuery("show errors").Check(testutil.RowsWithSep("|", "Error|1050|Table 'test.show_errors' already exists"))
 }
 
+func (s *testSuite5) TestShowStatus(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustQuery("show status where variable_name = 'Txn_retry_count'").Check(testutil.RowsWithSep("|", "Txn_retry_count|0"))
+	tk.MustQuery("show session status where variable_name = 'Ssl_verify_mode'").Check(testutil.RowsWithSep("|", "Ssl_verify_mode|0"))
+	// Session only status variables are not shown in the global scope.
+	tk.MustQuery("show global status where variable_name = 'Txn_retry_count'").Check(testutil.RowsWithSep("|"))
+	tk.MustQuery("show global status where variable_name = 'Ssl_verify_mode'").Check(testutil.RowsWithSep("|", "Ssl_verify_mode|0"))
+}
+
 func (s *testSuite5) TestShowEscape(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 
@@ -17,6 +17,7 @@ import (
 	"context"
 
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
@@ -89,9 +90,23 @@ func (e *SimpleExec) executeBegin(ctx context.Context, s *ast.BeginStmt) error {
 	// the transaction with COMMIT or ROLLBACK. The autocommit mode then
 	// reverts to its previous state.
 	e.ctx.GetSessionVars().SetStatusFlag(mysql.ServerStatusInTrans, true)
+	// The transaction mode of the BEGIN statement takes precedence over the session variable.
+	txnMode := s.Mode
+	if txnMode == "" {
+		txnMode = e.ctx.GetSessionVars().TxnMode
+	}
+	if txnMode == ast.Pessimistic {
+		e.ctx.GetSessionVars().TxnCtx.IsPessimistic = true
+	}
 	// Call ctx.Txn(true) to active pending txn.
-	_, err := e.ctx.Txn(true)
-	return err
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	if e.ctx.GetSessionVars().TxnCtx.IsPessimistic {
+		txn.SetOption(kv.Pessimistic, true)
+	}
+	return nil
 }
 
 func (e *SimpleExec) executeCommit(s *ast.CommitStmt) {
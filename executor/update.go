@@ -0,0 +1,238 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// UpdateExec represents a new update executor.
+// See https://dev.mysql.com/doc/refman/5.7/en/update.html
+type UpdateExec struct {
+	baseExecutor
+
+	OrderedList []*expression.Assignment
+
+	// updatedRowKeys is a map for unique (Table, handle) pair.
+	// The value is true if the row is changed, or false otherwise
+	updatedRowKeys map[int64]map[int64]bool
+	tblID2table    map[int64]table.Table
+
+	matched uint64 // a counter of matched rows during update
+	// tblColPosInfos stores relationship between column ordinal to its table handle.
+	// the columns ordinals is present in ordinal range format, @see plannercore.TblColPosInfos
+	tblColPosInfos plannercore.TblColPosInfoSlice
+	evalBuffer     chunk.MutRow
+	assignFlag     []bool
+	drained        bool
+}
+
+func (e *UpdateExec) exec(ctx context.Context, row, newData []types.Datum) error {
+	if e.updatedRowKeys == nil {
+		e.updatedRowKeys = make(map[int64]map[int64]bool)
+	}
+	for _, content := range e.tblColPosInfos {
+		tbl := e.tblID2table[content.TblID]
+		if e.updatedRowKeys[content.TblID] == nil {
+			e.updatedRowKeys[content.TblID] = make(map[int64]bool)
+		}
+		if e.canNotUpdate(row[content.HandleOrdinal]) {
+			// The row comes from the outer side of an outer join and does not exist.
+			continue
+		}
+		handle := row[content.HandleOrdinal].GetInt64()
+		oldData := row[content.Start:content.End]
+		newTableData := newData[content.Start:content.End]
+		updatable := false
+		flags := e.assignFlag[content.Start:content.End]
+		for _, flag := range flags {
+			if flag {
+				updatable = true
+				break
+			}
+		}
+		if !updatable {
+			// If there's nothing to update, we can just skip current row
+			continue
+		}
+		changed, ok := e.updatedRowKeys[content.TblID][handle]
+		if !ok {
+			// Row is matched for the first time, increment `matched` counter
+			e.matched++
+		}
+		if changed {
+			// Each matched row is updated once, even if it matches the conditions multiple times.
+			continue
+		}
+
+		// Update row. updateRecord modifies the flags, so pass a copy of them.
+		flags = append([]bool(nil), flags...)
+		changed, _, _, err := updateRecord(ctx, e.ctx, handle, oldData, newTableData, flags, tbl)
+		if err != nil {
+			return err
+		}
+		e.updatedRowKeys[content.TblID][handle] = changed
+	}
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *UpdateExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if !e.drained {
+		numRows, err := e.updateRows(ctx)
+		if err != nil {
+			return err
+		}
+		e.drained = true
+		e.ctx.GetSessionVars().StmtCtx.AddRecordRows(uint64(numRows))
+		e.setMessage()
+	}
+	return nil
+}
+
+func (e *UpdateExec) updateRows(ctx context.Context) (int, error) {
+	fields := retTypes(e.children[0])
+	colsInfo := make([]*table.Column, len(fields))
+	for _, content := range e.tblColPosInfos {
+		tbl := e.tblID2table[content.TblID]
+		for i, c := range tbl.WritableCols() {
+			colsInfo[content.Start+i] = c
+		}
+	}
+	assignFlag, err := e.getUpdateColumns(len(fields))
+	if err != nil {
+		return 0, err
+	}
+	e.assignFlag = assignFlag
+	e.evalBuffer = chunk.MutRowFromTypes(fields)
+	globalRowIdx := 0
+	chk := newFirstChunk(e.children[0])
+	for {
+		err := Next(ctx, e.children[0], chk)
+		if err != nil {
+			return 0, err
+		}
+		if chk.NumRows() == 0 {
+			break
+		}
+
+		for rowIdx := 0; rowIdx < chk.NumRows(); rowIdx++ {
+			chunkRow := chk.GetRow(rowIdx)
+			datumRow := chunkRow.GetDatumRow(fields)
+			newRow, err := e.composeNewRow(globalRowIdx, datumRow, colsInfo)
+			if err != nil {
+				return 0, err
+			}
+			if err := e.exec(ctx, datumRow, newRow); err != nil {
+				return 0, err
+			}
+			globalRowIdx++
+		}
+		chk = chunk.Renew(chk, e.maxChunkSize)
+	}
+	return globalRowIdx, nil
+}
+
+// getUpdateColumns marks the columns which appear in the SET list.
+func (e *UpdateExec) getUpdateColumns(schemaLen int) ([]bool, error) {
+	assignFlag := make([]bool, schemaLen)
+	for _, v := range e.OrderedList {
+		if !e.ctx.GetSessionVars().AllowWriteRowID && v.Col.ID == model.ExtraHandleID {
+			return nil, errors.Errorf("insert, update and replace statements for _tidb_rowid are not supported.")
+		}
+		assignFlag[v.Col.Index] = true
+	}
+	return assignFlag, nil
+}
+
+func (e *UpdateExec) handleErr(colName model.CIStr, rowIdx int, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if types.ErrDataTooLong.Equal(err) {
+		return resetErrDataTooLong(colName.O, rowIdx+1, err)
+	}
+
+	if types.ErrOverflow.Equal(err) {
+		return types.ErrWarnDataOutOfRange.GenWithStackByArgs(colName.O, rowIdx+1)
+	}
+
+	return err
+}
+
+func (e *UpdateExec) composeNewRow(rowIdx int, oldRow []types.Datum, cols []*table.Column) ([]types.Datum, error) {
+	newRowData := types.CloneRow(oldRow)
+	e.evalBuffer.SetDatums(newRowData...)
+	for _, assign := range e.OrderedList {
+		handleIdx, handleFound := e.tblColPosInfos.FindHandle(assign.Col.Index)
+		if handleFound && e.canNotUpdate(oldRow[handleIdx]) {
+			continue
+		}
+		val, err := assign.Expr.Eval(e.evalBuffer.ToRow())
+		if err = e.handleErr(assign.ColName, rowIdx, err); err != nil {
+			return nil, err
+		}
+
+		// info of `_tidb_rowid` column is nil.
+		// No need to cast `_tidb_rowid` column value.
+		if cols[assign.Col.Index] != nil {
+			val, err = table.CastValue(e.ctx, val, cols[assign.Col.Index].ColumnInfo)
+			if err = e.handleErr(assign.ColName, rowIdx, err); err != nil {
+				return nil, err
+			}
+		}
+
+		newRowData[assign.Col.Index] = *val.Copy()
+		e.evalBuffer.SetDatum(assign.Col.Index, val)
+	}
+	return newRowData, nil
+}
+
+// canNotUpdate checks whether the handle is NULL, which means the row
+// is padded by an outer join and there is nothing to update.
+func (e *UpdateExec) canNotUpdate(handle types.Datum) bool {
+	return handle.IsNull()
+}
+
+// Close implements the Executor Close interface.
+func (e *UpdateExec) Close() error {
+	return e.children[0].Close()
+}
+
+// Open implements the Executor Open interface.
+func (e *UpdateExec) Open(ctx context.Context) error {
+	return e.children[0].Open(ctx)
+}
+
+// setMessage sets info message(ERR_UPDATE_INFO) generated by UPDATE statement
+func (e *UpdateExec) setMessage() {
+	stmtCtx := e.ctx.GetSessionVars().StmtCtx
+	numMatched := e.matched
+	numChanged := stmtCtx.UpdatedRows()
+	numWarnings := stmtCtx.WarningCount()
+	msg := fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUpdateInfo], numMatched, numChanged, numWarnings)
+	stmtCtx.SetMessage(msg)
+}
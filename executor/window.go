@@ -0,0 +1,360 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/executor/aggfuncs"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// WindowExec is the executor for window functions. Its child is sorted on the
+// partition by and order by items, so the rows of one partition are adjacent.
+// It buffers the rows of a partition, then outputs every row of the partition
+// along with the results of the window functions.
+type WindowExec struct {
+	baseExecutor
+
+	partitionBy []*expression.Column
+	processor   windowProcessor
+
+	// cmpFuncs are used to compare the partition by columns of two rows.
+	cmpFuncs []chunk.CompareFunc
+
+	childResult *chunk.Chunk
+	childCursor int
+	childDone   bool
+
+	// partition holds the rows of the partition being output.
+	partition []chunk.Row
+	// nextRow is the index of the next row in partition to output.
+	nextRow int
+}
+
+// Open implements the Executor Open interface.
+func (e *WindowExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.cmpFuncs = make([]chunk.CompareFunc, 0, len(e.partitionBy))
+	for _, col := range e.partitionBy {
+		e.cmpFuncs = append(e.cmpFuncs, chunk.GetCompareFunc(col.RetType))
+	}
+	e.childResult = nil
+	e.childCursor = 0
+	e.childDone = false
+	e.partition = e.partition[:0]
+	e.nextRow = 0
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *WindowExec) Close() error {
+	e.childResult = nil
+	e.partition = nil
+	return e.baseExecutor.Close()
+}
+
+// Next implements the Executor Next interface.
+func (e *WindowExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for !req.IsFull() {
+		if e.nextRow >= len(e.partition) {
+			err := e.fetchNextPartition(ctx)
+			if err != nil {
+				return err
+			}
+			if len(e.partition) == 0 {
+				return nil
+			}
+		}
+		row := e.partition[e.nextRow]
+		req.AppendPartialRow(0, row)
+		err := e.processor.appendResult2Chunk(e.ctx, e.partition, e.nextRow, req)
+		if err != nil {
+			return err
+		}
+		e.nextRow++
+	}
+	return nil
+}
+
+// fetchNextPartition reads the rows of the next partition from the child
+// executor and feeds them to the window processor.
+func (e *WindowExec) fetchNextPartition(ctx context.Context) error {
+	e.partition = e.partition[:0]
+	e.nextRow = 0
+	for {
+		if e.childResult == nil || e.childCursor >= e.childResult.NumRows() {
+			if e.childDone {
+				break
+			}
+			// A new chunk is allocated every time since the buffered rows
+			// of the partition still reference the previous one.
+			e.childResult = newFirstChunk(e.children[0])
+			e.childCursor = 0
+			err := Next(ctx, e.children[0], e.childResult)
+			if err != nil {
+				return err
+			}
+			if e.childResult.NumRows() == 0 {
+				e.childDone = true
+				break
+			}
+		}
+		row := e.childResult.GetRow(e.childCursor)
+		if len(e.partition) > 0 && !e.samePartition(e.partition[len(e.partition)-1], row) {
+			break
+		}
+		e.partition = append(e.partition, row)
+		e.childCursor++
+	}
+	if len(e.partition) == 0 {
+		return nil
+	}
+	e.processor.resetPartialResult()
+	return e.processor.consumePartition(e.ctx, e.partition)
+}
+
+func (e *WindowExec) samePartition(prev, curr chunk.Row) bool {
+	for i, col := range e.partitionBy {
+		if e.cmpFuncs[i](prev, col.Index, curr, col.Index) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// windowProcessor is the interface for processing different kinds of windows.
+type windowProcessor interface {
+	// consumePartition is called once with all the rows of a partition
+	// before any result of that partition is appended.
+	consumePartition(ctx sessionctx.Context, rows []chunk.Row) error
+	// appendResult2Chunk appends the window function results of rows[idx]
+	// to the chunk.
+	appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, idx int, chk *chunk.Chunk) error
+	// resetPartialResult resets the partial result to the original state.
+	resetPartialResult()
+}
+
+type aggWindowProcessor struct {
+	windowFuncs    []aggfuncs.AggFunc
+	partialResults []aggfuncs.PartialResult
+}
+
+func (p *aggWindowProcessor) consumePartition(ctx sessionctx.Context, rows []chunk.Row) error {
+	for i, windowFunc := range p.windowFuncs {
+		err := windowFunc.UpdatePartialResult(ctx, rows, p.partialResults[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *aggWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, idx int, chk *chunk.Chunk) error {
+	for i, windowFunc := range p.windowFuncs {
+		err := windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *aggWindowProcessor) resetPartialResult() {
+	for i, windowFunc := range p.windowFuncs {
+		windowFunc.ResetPartialResult(p.partialResults[i])
+	}
+}
+
+// appendFrameResult computes the window functions on the rows of a frame.
+func appendFrameResult(ctx sessionctx.Context, windowFuncs []aggfuncs.AggFunc, partialResults []aggfuncs.PartialResult, frame []chunk.Row, chk *chunk.Chunk) error {
+	for i, windowFunc := range windowFuncs {
+		windowFunc.ResetPartialResult(partialResults[i])
+		err := windowFunc.UpdatePartialResult(ctx, frame, partialResults[i])
+		if err != nil {
+			return err
+		}
+		err = windowFunc.AppendFinalResult2Chunk(ctx, partialResults[i], chk)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type rowFrameWindowProcessor struct {
+	windowFuncs    []aggfuncs.AggFunc
+	partialResults []aggfuncs.PartialResult
+	start          *plannercore.FrameBound
+	end            *plannercore.FrameBound
+}
+
+func (p *rowFrameWindowProcessor) getStartOffset(numRows, idx int) int {
+	if p.start.UnBounded {
+		return 0
+	}
+	switch p.start.Type {
+	case ast.Preceding:
+		if uint64(idx) >= p.start.Num {
+			return idx - int(p.start.Num)
+		}
+		return 0
+	case ast.Following:
+		offset := uint64(idx) + p.start.Num
+		if offset >= uint64(numRows) {
+			return numRows
+		}
+		return int(offset)
+	default:
+		return idx
+	}
+}
+
+func (p *rowFrameWindowProcessor) getEndOffset(numRows, idx int) int {
+	if p.end.UnBounded {
+		return numRows
+	}
+	switch p.end.Type {
+	case ast.Preceding:
+		if uint64(idx) >= p.end.Num {
+			return idx - int(p.end.Num) + 1
+		}
+		return 0
+	case ast.Following:
+		offset := uint64(idx) + p.end.Num
+		if offset >= uint64(numRows) {
+			return numRows
+		}
+		return int(offset) + 1
+	default:
+		return idx + 1
+	}
+}
+
+func (p *rowFrameWindowProcessor) consumePartition(ctx sessionctx.Context, rows []chunk.Row) error {
+	return nil
+}
+
+func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, idx int, chk *chunk.Chunk) error {
+	start, end := p.getStartOffset(len(rows), idx), p.getEndOffset(len(rows), idx)
+	if start > end {
+		start = end
+	}
+	return appendFrameResult(ctx, p.windowFuncs, p.partialResults, rows[start:end], chk)
+}
+
+func (p *rowFrameWindowProcessor) resetPartialResult() {
+	for i, windowFunc := range p.windowFuncs {
+		windowFunc.ResetPartialResult(p.partialResults[i])
+	}
+}
+
+type rangeFrameWindowProcessor struct {
+	windowFuncs     []aggfuncs.AggFunc
+	partialResults  []aggfuncs.PartialResult
+	start           *plannercore.FrameBound
+	end             *plannercore.FrameBound
+	lastStartOffset int
+	lastEndOffset   int
+	orderByCols     []*expression.Column
+	// expectedCmpResult is used to decide if one value is included in the frame.
+	expectedCmpResult int64
+}
+
+// compareBound compares the order by values of rows[idx] with the bound
+// calculated on the current row.
+func (p *rangeFrameWindowProcessor) compareBound(ctx sessionctx.Context, bound *plannercore.FrameBound, row, curRow chunk.Row) (int64, error) {
+	var res int64
+	var err error
+	for i := range bound.CalcFuncs {
+		res, _, err = bound.CmpFuncs[i](ctx, p.orderByCols[i], bound.CalcFuncs[i], row, curRow)
+		if err != nil {
+			return 0, err
+		}
+		if res != 0 {
+			break
+		}
+	}
+	return res, nil
+}
+
+func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows []chunk.Row, idx int) (int, error) {
+	if p.start.UnBounded {
+		return 0, nil
+	}
+	for ; p.lastStartOffset < len(rows); p.lastStartOffset++ {
+		res, err := p.compareBound(ctx, p.start, rows[p.lastStartOffset], rows[idx])
+		if err != nil {
+			return 0, err
+		}
+		// For asc, break when the current value is greater or equal to the calculated result;
+		// For desc, break when the current value is less or equal to the calculated result.
+		if (p.expectedCmpResult == -1 && res >= 0) || (p.expectedCmpResult == 1 && res <= 0) {
+			break
+		}
+	}
+	return p.lastStartOffset, nil
+}
+
+func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows []chunk.Row, idx int) (int, error) {
+	if p.end.UnBounded {
+		return len(rows), nil
+	}
+	for ; p.lastEndOffset < len(rows); p.lastEndOffset++ {
+		res, err := p.compareBound(ctx, p.end, rows[p.lastEndOffset], rows[idx])
+		if err != nil {
+			return 0, err
+		}
+		// For asc, break when the calculated result is greater than the current value.
+		// For desc, break when the calculated result is less than the current value.
+		if (p.expectedCmpResult == -1 && res > 0) || (p.expectedCmpResult == 1 && res < 0) {
+			break
+		}
+	}
+	return p.lastEndOffset, nil
+}
+
+func (p *rangeFrameWindowProcessor) consumePartition(ctx sessionctx.Context, rows []chunk.Row) error {
+	return nil
+}
+
+func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, idx int, chk *chunk.Chunk) error {
+	start, err := p.getStartOffset(ctx, rows, idx)
+	if err != nil {
+		return err
+	}
+	end, err := p.getEndOffset(ctx, rows, idx)
+	if err != nil {
+		return err
+	}
+	if start > end {
+		start = end
+	}
+	return appendFrameResult(ctx, p.windowFuncs, p.partialResults, rows[start:end], chk)
+}
+
+func (p *rangeFrameWindowProcessor) resetPartialResult() {
+	for i, windowFunc := range p.windowFuncs {
+		windowFunc.ResetPartialResult(p.partialResults[i])
+	}
+	p.lastStartOffset = 0
+	p.lastEndOffset = 0
+}
@@ -459,6 +459,113 @@ func (s *testSuite4) TestReplace(c *C) {
 	tk.MustQuery("select * from t1;").Check(testkit.Rows("1 20 30", "2 30 20", "50 20 30"))
 }
 
+func (s *testSuite4) TestUpdate(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	s.fillData(tk, "update_test")
+
+	updateStr := `UPDATE update_test SET name = "abc" where id > 0;`
+	tk.MustExec(updateStr)
+	tk.CheckExecResult(2, 0)
+	tk.CheckLastMessage("Rows matched: 2  Changed: 2  Warnings: 0")
+
+	// select data
+	tk.MustExec("begin")
+	r := tk.MustQuery(`SELECT * from update_test limit 2;`)
+	r.Check(testkit.Rows("1 abc", "2 abc"))
+	tk.MustExec("commit")
+
+	tk.MustExec(`UPDATE update_test SET name = "foo"`)
+	tk.CheckExecResult(2, 0)
+
+	// table update_test has only 2 rows, the matched but unchanged row is not counted.
+	tk.MustExec(`UPDATE update_test SET name = "foo" where id = 1`)
+	tk.CheckExecResult(0, 0)
+	tk.CheckLastMessage("Rows matched: 1  Changed: 0  Warnings: 0")
+
+	// update with order by and limit.
+	tk.MustExec(`UPDATE update_test SET name = "bar" ORDER BY id desc LIMIT 1`)
+	tk.CheckExecResult(1, 0)
+	tk.MustQuery("select * from update_test").Check(testkit.Rows("1 foo", "2 bar"))
+
+	// update the handle column.
+	tk.MustExec("UPDATE update_test SET id = id + 10 where id = 1")
+	tk.CheckExecResult(1, 0)
+	tk.MustQuery("select * from update_test").Check(testkit.Rows("2 bar", "11 foo"))
+
+	// update with self-referencing assignments, later assignments see the former ones.
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, index idx_b(b))")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("update t set a = a + 1, b = a * 10 where a > 1")
+	tk.CheckExecResult(2, 0)
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 1", "3 30", "4 40"))
+	tk.MustQuery("select a from t use index(idx_b) where b = 30").Check(testkit.Rows("3"))
+	tk.MustExec("update t set b = default where a = 1")
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 <nil>"))
+
+	// update unique key to a duplicated value.
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int primary key, b int unique key)")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+	_, err := tk.Exec("update t set b = 2 where a = 1")
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("update t set a = 2 where a = 1")
+	c.Assert(err, NotNil)
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 1", "2 2"))
+
+	// update auto_increment column rebases the allocator.
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key auto_increment, c int)")
+	tk.MustExec("insert into t(c) values (1)")
+	tk.MustExec("update t set id = 10 where c = 1")
+	tk.MustExec("insert into t(c) values (2)")
+	tk.MustQuery("select * from t").Check(testkit.Rows("10 1", "11 2"))
+
+	_, err = tk.Exec("update t set xxx = 1")
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("update (select * from t) as tt set tt.c = 1")
+	c.Assert(err, NotNil)
+}
+
+func (s *testSuite4) TestMultipleTableUpdate(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists items, month")
+	tk.MustExec(`CREATE TABLE items (id int, price varchar(10));`)
+	tk.MustExec(`INSERT INTO items values (11, "items_price_11"), (12, "items_price_12"), (13, "items_price_13");`)
+	tk.MustExec(`CREATE TABLE month (mid int, mprice varchar(10));`)
+	tk.MustExec(`INSERT INTO month values (11, "month_price_11"), (22, "month_price_22"), (13, "month_price_13");`)
+	tk.MustExec(`UPDATE items, month SET items.price=month.mprice WHERE items.id=month.mid;`)
+	tk.CheckExecResult(2, 0)
+	tk.MustQuery("SELECT * FROM items").Check(testkit.Rows("11 month_price_11", "12 items_price_12", "13 month_price_13"))
+
+	// Single-table syntax but with multiple tables.
+	tk.MustExec(`UPDATE items join month on items.id=month.mid SET items.price=month.mid;`)
+	tk.MustQuery("SELECT * FROM items").Check(testkit.Rows("11 11", "12 items_price_12", "13 13"))
+
+	// JoinTable with alias table name.
+	tk.MustExec(`UPDATE items T0 join month T1 on T0.id=T1.mid SET T0.price=T1.mprice;`)
+	tk.MustQuery("SELECT * FROM items").Check(testkit.Rows("11 month_price_11", "12 items_price_12", "13 month_price_13"))
+
+	// Update both tables, each row is updated only once.
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1 (c1 int, c2 int)")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2)")
+	tk.MustExec("create table t2 (c1 int, c2 int)")
+	tk.MustExec("insert into t2 values (1, 1), (1, 2)")
+	tk.MustExec("update t1, t2 set t1.c2 = t1.c2 + 10, t2.c2 = t2.c2 + 100 where t1.c1 = t2.c1")
+	tk.CheckExecResult(3, 0)
+	tk.CheckLastMessage("Rows matched: 3  Changed: 3  Warnings: 0")
+	tk.MustQuery("select * from t1").Check(testkit.Rows("1 11", "2 2"))
+	tk.MustQuery("select * from t2").Check(testkit.Rows("1 101", "1 102"))
+
+	// Rows padded by outer join are not updated.
+	tk.MustExec("update t1 left join t2 on t1.c1 = t2.c1 set t2.c2 = 0")
+	tk.MustQuery("select * from t2").Check(testkit.Rows("1 0", "1 0"))
+	tk.MustQuery("select count(*) from t2").Check(testkit.Rows("2"))
+}
+
 func (s *testSuite) TestDelete(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	s.fillData(tk, "delete_test")
@@ -67,6 +67,20 @@ func NewDistAggFunc(expr *tipb.Expr, fieldTps []*types.FieldType, sc *stmtctx.St
 		return &maxMinFunction{aggFunction: newAggFunc(ast.AggFuncMin, args)}, nil
 	case tipb.ExprType_First:
 		return &firstRowFunction{aggFunction: newAggFunc(ast.AggFuncFirstRow, args)}, nil
+	case tipb.ExprType_Agg_BitOr:
+		return &bitOrFunction{aggFunction: newAggFunc(ast.AggFuncBitOr, args)}, nil
+	case tipb.ExprType_Agg_BitXor:
+		return &bitXorFunction{aggFunction: newAggFunc(ast.AggFuncBitXor, args)}, nil
+	case tipb.ExprType_Agg_BitAnd:
+		return &bitAndFunction{aggFunction: newAggFunc(ast.AggFuncBitAnd, args)}, nil
+	case tipb.ExprType_VarPop:
+		return &varianceFunction{aggFunction: newAggFunc(ast.AggFuncVarPop, args)}, nil
+	case tipb.ExprType_VarSamp:
+		return &varianceFunction{aggFunction: newAggFunc(ast.AggFuncVarSamp, args)}, nil
+	case tipb.ExprType_StddevPop:
+		return &varianceFunction{aggFunction: newAggFunc(ast.AggFuncStddevPop, args)}, nil
+	case tipb.ExprType_StddevSamp:
+		return &varianceFunction{aggFunction: newAggFunc(ast.AggFuncStddevSamp, args)}, nil
 	}
 	return nil, errors.Errorf("Unknown aggregate function type %v", expr.Tp)
 }
@@ -77,6 +91,7 @@ type AggEvaluateContext struct {
 	Value       types.Datum
 	Buffer      *bytes.Buffer // Buffer is used for group_concat.
 	GotFirstRow bool          // It will check if the agg has met the first row key.
+	Variance    float64       // Variance is the sum of squared differences from the mean, used for var_pop, var_samp, stddev_pop and stddev_samp.
 }
 
 // AggFunctionMode stands for the aggregation function's mode.
@@ -137,13 +152,27 @@ func (af *aggFunction) updateSum(sc *stmtctx.StatementContext, evalCtx *AggEvalu
 
 // NeedCount indicates whether the aggregate function should record count.
 func NeedCount(name string) bool {
-	return name == ast.AggFuncCount || name == ast.AggFuncAvg
+	return name == ast.AggFuncCount || name == ast.AggFuncAvg || IsVarianceFunc(name)
 }
 
 // NeedValue indicates whether the aggregate function should record value.
 func NeedValue(name string) bool {
 	switch name {
-	case ast.AggFuncSum, ast.AggFuncAvg, ast.AggFuncFirstRow, ast.AggFuncMax, ast.AggFuncMin:
+	case ast.AggFuncSum, ast.AggFuncAvg, ast.AggFuncFirstRow, ast.AggFuncMax, ast.AggFuncMin,
+		ast.AggFuncBitOr, ast.AggFuncBitXor, ast.AggFuncBitAnd, ast.AggFuncVarPop, ast.AggFuncVarSamp,
+		ast.AggFuncStddevPop, ast.AggFuncStddevSamp:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsVarianceFunc indicates whether the aggregate function computes a variance
+// or a standard deviation, whose partial result is made up of the count, the
+// sum and the sum of squared differences from the mean.
+func IsVarianceFunc(name string) bool {
+	switch name {
+	case ast.AggFuncVarPop, ast.AggFuncVarSamp, ast.AggFuncStddevPop, ast.AggFuncStddevSamp:
 		return true
 	default:
 		return false
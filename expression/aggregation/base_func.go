@@ -17,11 +17,14 @@ import (
 	"bytes"
 	"fmt"
 	log "github.com/sirupsen/logrus"
+	"math"
 	"strings"
 
+	"github.com/cznic/mathutil"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
@@ -100,8 +103,18 @@ func (a *baseFuncDesc) typeInfer(ctx sessionctx.Context) error {
 		a.typeInfer4Sum(ctx)
 	case ast.AggFuncAvg:
 		a.typeInfer4Avg(ctx)
-	case ast.AggFuncMax, ast.AggFuncMin, ast.AggFuncFirstRow:
+	case ast.AggFuncGroupConcat:
+		a.typeInfer4GroupConcat(ctx)
+	case ast.AggFuncMax, ast.AggFuncMin, ast.AggFuncFirstRow, ast.WindowFuncFirstValue:
 		a.typeInfer4MaxMin(ctx)
+	case ast.AggFuncBitAnd, ast.AggFuncBitOr, ast.AggFuncBitXor:
+		a.typeInfer4BitFuncs(ctx)
+	case ast.AggFuncVarPop, ast.AggFuncVarSamp, ast.AggFuncStddevPop, ast.AggFuncStddevSamp:
+		a.typeInfer4Variance(ctx)
+	case ast.WindowFuncRowNumber, ast.WindowFuncRank, ast.WindowFuncDenseRank:
+		a.typeInfer4NumberFuncs()
+	case ast.WindowFuncLead, ast.WindowFuncLag:
+		a.typeInfer4LeadLag(ctx)
 	default:
 		return errors.Errorf("unsupported agg function: %s", a.Name)
 	}
@@ -120,6 +133,12 @@ func (a *baseFuncDesc) typeInfer4Sum(ctx sessionctx.Context) {
 	switch a.Args[0].GetType().Tp {
 	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
 		a.RetTp = types.NewFieldType(mysql.TypeLonglong)
+	case mysql.TypeNewDecimal:
+		a.RetTp = types.NewFieldType(mysql.TypeNewDecimal)
+		a.RetTp.Flen, a.RetTp.Decimal = mysql.MaxDecimalWidth, a.Args[0].GetType().Decimal
+		if a.RetTp.Decimal < 0 || a.RetTp.Decimal > mysql.MaxDecimalScale {
+			a.RetTp.Decimal = mysql.MaxDecimalScale
+		}
 	case mysql.TypeDouble, mysql.TypeFloat:
 		a.RetTp = types.NewFieldType(mysql.TypeDouble)
 		a.RetTp.Flen, a.RetTp.Decimal = mysql.MaxRealWidth, a.Args[0].GetType().Decimal
@@ -136,6 +155,14 @@ func (a *baseFuncDesc) typeInfer4Avg(ctx sessionctx.Context) {
 	switch a.Args[0].GetType().Tp {
 	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
 		a.RetTp = types.NewFieldType(mysql.TypeLonglong)
+	case mysql.TypeNewDecimal:
+		a.RetTp = types.NewFieldType(mysql.TypeNewDecimal)
+		if a.Args[0].GetType().Decimal < 0 {
+			a.RetTp.Decimal = mysql.MaxDecimalScale
+		} else {
+			a.RetTp.Decimal = mathutil.Min(a.Args[0].GetType().Decimal+types.DivFracIncr, mysql.MaxDecimalScale)
+		}
+		a.RetTp.Flen = mysql.MaxDecimalWidth
 	case mysql.TypeDouble, mysql.TypeFloat:
 		a.RetTp = types.NewFieldType(mysql.TypeDouble)
 		a.RetTp.Flen, a.RetTp.Decimal = mysql.MaxRealWidth, a.Args[0].GetType().Decimal
@@ -146,6 +173,16 @@ func (a *baseFuncDesc) typeInfer4Avg(ctx sessionctx.Context) {
 	types.SetBinChsClnFlag(a.RetTp)
 }
 
+func (a *baseFuncDesc) typeInfer4GroupConcat(ctx sessionctx.Context) {
+	a.RetTp = types.NewFieldType(mysql.TypeVarString)
+	a.RetTp.Charset, a.RetTp.Collate = charset.GetDefaultCharsetAndCollate()
+	a.RetTp.Flen, a.RetTp.Decimal = mysql.MaxBlobWidth, 0
+	// The last argument is the separator, which is always a string constant.
+	for i := 0; i < len(a.Args)-1; i++ {
+		a.Args[i] = expression.WrapWithCastAsString(ctx, a.Args[i])
+	}
+}
+
 func (a *baseFuncDesc) typeInfer4MaxMin(ctx sessionctx.Context) {
 	a.RetTp = a.Args[0].GetType()
 	if (a.Name == ast.AggFuncMax || a.Name == ast.AggFuncMin) && a.RetTp.Tp != mysql.TypeBit {
@@ -157,6 +194,38 @@ func (a *baseFuncDesc) typeInfer4MaxMin(ctx sessionctx.Context) {
 	}
 }
 
+func (a *baseFuncDesc) typeInfer4BitFuncs(ctx sessionctx.Context) {
+	a.RetTp = types.NewFieldType(mysql.TypeLonglong)
+	a.RetTp.Flen = 21
+	types.SetBinChsClnFlag(a.RetTp)
+	a.RetTp.Flag |= mysql.UnsignedFlag | mysql.NotNullFlag
+	a.Args[0] = expression.WrapWithCastAsInt(ctx, a.Args[0])
+}
+
+// typeInfer4Variance returns a "double" for var_pop, var_samp, stddev_pop and
+// stddev_samp, no matter what the type of the argument is.
+func (a *baseFuncDesc) typeInfer4Variance(ctx sessionctx.Context) {
+	a.RetTp = types.NewFieldType(mysql.TypeDouble)
+	a.RetTp.Flen, a.RetTp.Decimal = mysql.MaxRealWidth, types.UnspecifiedLength
+	types.SetBinChsClnFlag(a.RetTp)
+	a.Args[0] = expression.WrapWithCastAsReal(ctx, a.Args[0])
+}
+
+func (a *baseFuncDesc) typeInfer4NumberFuncs() {
+	a.RetTp = types.NewFieldType(mysql.TypeLonglong)
+	a.RetTp.Flen = 21
+	types.SetBinChsClnFlag(a.RetTp)
+}
+
+func (a *baseFuncDesc) typeInfer4LeadLag(ctx sessionctx.Context) {
+	if len(a.Args) <= 2 {
+		a.typeInfer4MaxMin(ctx)
+	} else {
+		// Merge the type of first and third argument.
+		a.RetTp = expression.InferType4ControlFuncs(a.Args[0].GetType(), a.Args[2].GetType())
+	}
+}
+
 // GetDefaultValue gets the default value when the function's input is null.
 // According to MySQL, default values of the function are listed as follows:
 // e.g.
@@ -178,8 +247,13 @@ func (a *baseFuncDesc) GetDefaultValue() (v types.Datum) {
 	case ast.AggFuncCount:
 		v = types.NewIntDatum(0)
 	case ast.AggFuncFirstRow, ast.AggFuncAvg, ast.AggFuncSum, ast.AggFuncMax,
-		ast.AggFuncMin:
+		ast.AggFuncMin, ast.AggFuncGroupConcat, ast.AggFuncVarPop, ast.AggFuncVarSamp,
+		ast.AggFuncStddevPop, ast.AggFuncStddevSamp:
 		v = types.Datum{}
+	case ast.AggFuncBitAnd:
+		v = types.NewUintDatum(uint64(math.MaxUint64))
+	case ast.AggFuncBitOr, ast.AggFuncBitXor:
+		v = types.NewUintDatum(0)
 	}
 	return
 }
@@ -187,8 +261,12 @@ func (a *baseFuncDesc) GetDefaultValue() (v types.Datum) {
 // We do not need to wrap cast upon these functions,
 // since the EvalXXX method called by the arg is determined by the corresponding arg type.
 var noNeedCastAggFuncs = map[string]struct{}{
-	ast.AggFuncCount:    {},
-	ast.AggFuncMax:      {},
-	ast.AggFuncMin:      {},
-	ast.AggFuncFirstRow: {},
+	ast.AggFuncCount:         {},
+	ast.AggFuncGroupConcat:   {},
+	ast.AggFuncMax:           {},
+	ast.AggFuncMin:           {},
+	ast.AggFuncFirstRow:      {},
+	ast.WindowFuncFirstValue: {},
+	ast.WindowFuncLead:       {},
+	ast.WindowFuncLag:        {},
 }
@@ -0,0 +1,69 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggregation
+
+import (
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+type bitOrFunction struct {
+	aggFunction
+}
+
+// CreateContext implements Aggregation interface.
+func (bf *bitOrFunction) CreateContext(sc *stmtctx.StatementContext) *AggEvaluateContext {
+	evalCtx := bf.aggFunction.CreateContext(sc)
+	evalCtx.Value.SetUint64(0)
+	return evalCtx
+}
+
+// ResetContext implements Aggregation interface.
+func (bf *bitOrFunction) ResetContext(sc *stmtctx.StatementContext, evalCtx *AggEvaluateContext) {
+	evalCtx.Value.SetUint64(0)
+}
+
+// Update implements Aggregation interface.
+func (bf *bitOrFunction) Update(evalCtx *AggEvaluateContext, sc *stmtctx.StatementContext, row chunk.Row) error {
+	value, err := bf.Args[0].Eval(row)
+	if err != nil {
+		return err
+	}
+	if value.IsNull() {
+		return nil
+	}
+	var v uint64
+	if value.Kind() == types.KindUint64 {
+		v = value.GetUint64()
+	} else {
+		i, err := value.ToInt64(sc)
+		if err != nil {
+			return err
+		}
+		v = uint64(i)
+	}
+	evalCtx.Value.SetUint64(evalCtx.Value.GetUint64() | v)
+	return nil
+}
+
+// GetResult implements Aggregation interface.
+func (bf *bitOrFunction) GetResult(evalCtx *AggEvaluateContext) types.Datum {
+	return evalCtx.Value
+}
+
+// GetPartialResult implements Aggregation interface.
+func (bf *bitOrFunction) GetPartialResult(evalCtx *AggEvaluateContext) []types.Datum {
+	return []types.Datum{bf.GetResult(evalCtx)}
+}
@@ -14,9 +14,13 @@
 package aggregation
 
 import (
+	"bytes"
+	"math"
+
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
 )
@@ -26,19 +30,60 @@ type AggFuncDesc struct {
 	baseFuncDesc
 	// Mode represents the execution mode of the aggregation function.
 	Mode AggFunctionMode
+	// HasDistinct represents whether the aggregation function contains distinct attribute.
+	HasDistinct bool
+	// OrderByItems represents the order by clause used in GROUP_CONCAT.
+	OrderByItems []*util.ByItems
 }
 
 // NewAggFuncDesc creates an aggregation function signature descriptor.
-func NewAggFuncDesc(ctx sessionctx.Context, name string, args []expression.Expression) (*AggFuncDesc, error) {
+func NewAggFuncDesc(ctx sessionctx.Context, name string, args []expression.Expression, hasDistinct bool) (*AggFuncDesc, error) {
 	b, err := newBaseFuncDesc(ctx, name, args)
 	if err != nil {
 		return nil, err
 	}
-	return &AggFuncDesc{baseFuncDesc: b}, nil
+	return &AggFuncDesc{baseFuncDesc: b, HasDistinct: hasDistinct}, nil
+}
+
+// String implements the fmt.Stringer interface.
+func (a *AggFuncDesc) String() string {
+	buffer := bytes.NewBufferString(a.Name)
+	buffer.WriteString("(")
+	if a.HasDistinct {
+		buffer.WriteString("distinct ")
+	}
+	for i, arg := range a.Args {
+		buffer.WriteString(arg.String())
+		if i+1 != len(a.Args) {
+			buffer.WriteString(", ")
+		}
+	}
+	if len(a.OrderByItems) > 0 {
+		buffer.WriteString(" order by ")
+	}
+	for i, arg := range a.OrderByItems {
+		buffer.WriteString(arg.String())
+		if i+1 != len(a.OrderByItems) {
+			buffer.WriteString(", ")
+		}
+	}
+	buffer.WriteString(")")
+	return buffer.String()
 }
 
 // Equal checks whether two aggregation function signatures are equal.
 func (a *AggFuncDesc) Equal(ctx sessionctx.Context, other *AggFuncDesc) bool {
+	if a.HasDistinct != other.HasDistinct {
+		return false
+	}
+	if len(a.OrderByItems) != len(other.OrderByItems) {
+		return false
+	}
+	for i := range a.OrderByItems {
+		if a.OrderByItems[i].Desc != other.OrderByItems[i].Desc || !a.OrderByItems[i].Expr.Equal(ctx, other.OrderByItems[i].Expr) {
+			return false
+		}
+	}
 	return a.baseFuncDesc.equal(ctx, &other.baseFuncDesc)
 }
 
@@ -46,6 +91,10 @@ func (a *AggFuncDesc) Equal(ctx sessionctx.Context, other *AggFuncDesc) bool {
 func (a *AggFuncDesc) Clone() *AggFuncDesc {
 	clone := *a
 	clone.baseFuncDesc = *a.baseFuncDesc.clone()
+	clone.OrderByItems = make([]*util.ByItems, len(a.OrderByItems))
+	for i, byItem := range a.OrderByItems {
+		clone.OrderByItems[i] = byItem.Clone()
+	}
 	return &clone
 }
 
@@ -67,7 +116,32 @@ func (a *AggFuncDesc) Split(ordinal []int) (partialAggDesc, finalAggDesc *AggFun
 	}
 	finalAggDesc.Name = a.Name
 	finalAggDesc.RetTp = a.RetTp
+	if a.HasDistinct || a.Name == ast.AggFuncGroupConcat {
+		// The partial results of distinct aggregate functions and group_concat
+		// keep the original values, so the final phase uses the original
+		// arguments to know their types.
+		clone := a.Clone()
+		finalAggDesc.Args = clone.Args
+		finalAggDesc.HasDistinct = clone.HasDistinct
+		finalAggDesc.OrderByItems = clone.OrderByItems
+		return
+	}
 	switch a.Name {
+	case ast.AggFuncVarPop, ast.AggFuncVarSamp, ast.AggFuncStddevPop, ast.AggFuncStddevSamp:
+		args := make([]expression.Expression, 0, 3)
+		args = append(args, &expression.Column{
+			Index:   ordinal[0],
+			RetType: types.NewFieldType(mysql.TypeLonglong),
+		})
+		args = append(args, &expression.Column{
+			Index:   ordinal[1],
+			RetType: a.RetTp,
+		})
+		args = append(args, &expression.Column{
+			Index:   ordinal[2],
+			RetType: a.RetTp,
+		})
+		finalAggDesc.Args = args
 	case ast.AggFuncAvg:
 		args := make([]expression.Expression, 0, 2)
 		args = append(args, &expression.Column{
@@ -130,8 +204,13 @@ func (a *AggFuncDesc) EvalNullValueInOuterJoin(ctx sessionctx.Context, schema *e
 	case ast.AggFuncSum, ast.AggFuncMax, ast.AggFuncMin,
 		ast.AggFuncFirstRow:
 		return a.evalNullValueInOuterJoin4Sum(ctx, schema)
-	case ast.AggFuncAvg:
+	case ast.AggFuncAvg, ast.AggFuncGroupConcat, ast.AggFuncVarPop, ast.AggFuncVarSamp,
+		ast.AggFuncStddevPop, ast.AggFuncStddevSamp:
 		return types.Datum{}, false
+	case ast.AggFuncBitAnd:
+		return a.evalNullValueInOuterJoin4BitAnd(ctx, schema)
+	case ast.AggFuncBitOr, ast.AggFuncBitXor:
+		return a.evalNullValueInOuterJoin4BitOr(ctx, schema)
 	default:
 		panic("unsupported agg function")
 	}
@@ -153,6 +232,14 @@ func (a *AggFuncDesc) GetAggFunc(ctx sessionctx.Context) Aggregation {
 		return &maxMinFunction{aggFunction: aggFunc, isMax: false}
 	case ast.AggFuncFirstRow:
 		return &firstRowFunction{aggFunction: aggFunc}
+	case ast.AggFuncBitOr:
+		return &bitOrFunction{aggFunction: aggFunc}
+	case ast.AggFuncBitXor:
+		return &bitXorFunction{aggFunction: aggFunc}
+	case ast.AggFuncBitAnd:
+		return &bitAndFunction{aggFunction: aggFunc}
+	case ast.AggFuncVarPop, ast.AggFuncVarSamp, ast.AggFuncStddevPop, ast.AggFuncStddevSamp:
+		return &varianceFunction{aggFunction: aggFunc}
 	default:
 		panic("unsupported agg function")
 	}
@@ -177,3 +264,27 @@ func (a *AggFuncDesc) evalNullValueInOuterJoin4Sum(ctx sessionctx.Context, schem
 	}
 	return con.Value, true
 }
+
+func (a *AggFuncDesc) evalNullValueInOuterJoin4BitAnd(ctx sessionctx.Context, schema *expression.Schema) (types.Datum, bool) {
+	result := expression.EvaluateExprWithNull(ctx, schema, a.Args[0])
+	con, ok := result.(*expression.Constant)
+	if !ok {
+		return types.Datum{}, false
+	}
+	if con.Value.IsNull() {
+		return types.NewDatum(uint64(math.MaxUint64)), true
+	}
+	return con.Value, true
+}
+
+func (a *AggFuncDesc) evalNullValueInOuterJoin4BitOr(ctx sessionctx.Context, schema *expression.Schema) (types.Datum, bool) {
+	result := expression.EvaluateExprWithNull(ctx, schema, a.Args[0])
+	con, ok := result.(*expression.Constant)
+	if !ok {
+		return types.Datum{}, false
+	}
+	if con.Value.IsNull() {
+		return types.NewDatum(0), true
+	}
+	return con.Value, true
+}
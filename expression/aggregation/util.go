@@ -66,6 +66,8 @@ func calculateSum(sc *stmtctx.StatementContext, sum, v types.Datum) (data types.
 		if err == nil {
 			data = types.NewIntDatum(d)
 		}
+	case types.KindMysqlDecimal:
+		data = types.CloneDatum(v)
 	default:
 		var f float64
 		f, err = v.ToFloat64(sc)
@@ -83,7 +85,7 @@ func calculateSum(sc *stmtctx.StatementContext, sum, v types.Datum) (data types.
 	switch sum.Kind() {
 	case types.KindNull:
 		return data, nil
-	case types.KindFloat64, types.KindInt64:
+	case types.KindFloat64, types.KindInt64, types.KindMysqlDecimal:
 		return types.ComputePlus(sum, data)
 	default:
 		return data, errors.Errorf("invalid value %v for aggregate", sum.Kind())
@@ -0,0 +1,118 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggregation
+
+import (
+	"math"
+
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// varianceFunction implements var_pop, var_samp, stddev_pop and stddev_samp.
+// evalCtx.Count and evalCtx.Value hold the count and the sum of the input
+// values, evalCtx.Variance holds the sum of squared differences from the mean,
+// which can be merged without losing precision.
+type varianceFunction struct {
+	aggFunction
+}
+
+// CreateContext implements Aggregation interface.
+func (vf *varianceFunction) CreateContext(sc *stmtctx.StatementContext) *AggEvaluateContext {
+	evalCtx := vf.aggFunction.CreateContext(sc)
+	evalCtx.Value.SetFloat64(0)
+	return evalCtx
+}
+
+// ResetContext implements Aggregation interface.
+func (vf *varianceFunction) ResetContext(sc *stmtctx.StatementContext, evalCtx *AggEvaluateContext) {
+	evalCtx.Count = 0
+	evalCtx.Value.SetFloat64(0)
+	evalCtx.Variance = 0
+}
+
+// Update implements Aggregation interface.
+func (vf *varianceFunction) Update(evalCtx *AggEvaluateContext, sc *stmtctx.StatementContext, row chunk.Row) error {
+	switch vf.Mode {
+	case Partial1Mode, CompleteMode:
+		value, err := vf.Args[0].Eval(row)
+		if err != nil || value.IsNull() {
+			return err
+		}
+		x, err := value.ToFloat64(sc)
+		if err != nil {
+			return err
+		}
+		evalCtx.Count++
+		sum := evalCtx.Value.GetFloat64() + x
+		evalCtx.Value.SetFloat64(sum)
+		if evalCtx.Count > 1 {
+			t := float64(evalCtx.Count)*x - sum
+			evalCtx.Variance += t * t / float64(evalCtx.Count*(evalCtx.Count-1))
+		}
+	case Partial2Mode, FinalMode:
+		var partial [3]types.Datum
+		for i := range partial {
+			d, err := vf.Args[i].Eval(row)
+			if err != nil {
+				return err
+			}
+			partial[i] = d
+		}
+		count := partial[0].GetInt64()
+		if count == 0 {
+			return nil
+		}
+		sum, err := partial[1].ToFloat64(sc)
+		if err != nil {
+			return err
+		}
+		variance, err := partial[2].ToFloat64(sc)
+		if err != nil {
+			return err
+		}
+		if evalCtx.Count > 0 {
+			t := float64(count)/float64(evalCtx.Count)*evalCtx.Value.GetFloat64() - sum
+			variance += evalCtx.Variance + float64(evalCtx.Count)/float64(count)/float64(evalCtx.Count+count)*t*t
+		}
+		evalCtx.Count += count
+		evalCtx.Value.SetFloat64(evalCtx.Value.GetFloat64() + sum)
+		evalCtx.Variance = variance
+	}
+	return nil
+}
+
+// GetResult implements Aggregation interface.
+func (vf *varianceFunction) GetResult(evalCtx *AggEvaluateContext) (d types.Datum) {
+	count := evalCtx.Count
+	if vf.Name == ast.AggFuncVarSamp || vf.Name == ast.AggFuncStddevSamp {
+		count--
+	}
+	if count <= 0 {
+		return
+	}
+	result := evalCtx.Variance / float64(count)
+	if vf.Name == ast.AggFuncStddevPop || vf.Name == ast.AggFuncStddevSamp {
+		result = math.Sqrt(result)
+	}
+	d.SetFloat64(result)
+	return
+}
+
+// GetPartialResult implements Aggregation interface.
+func (vf *varianceFunction) GetPartialResult(evalCtx *AggEvaluateContext) []types.Datum {
+	return []types.Datum{types.NewIntDatum(evalCtx.Count), types.NewFloat64Datum(evalCtx.Value.GetFloat64()), types.NewFloat64Datum(evalCtx.Variance)}
+}
@@ -73,12 +73,52 @@ func (g *defaultGener) gen() interface{} {
 			return -rand.Float64() * 1000000
 		}
 		return rand.Float64() * 1000000
+	case types.ETDecimal:
+		d := new(types.MyDecimal)
+		f := rand.Float64() * 100000
+		if rand.Float64() < 0.5 {
+			f = -f
+		}
+		if err := d.FromFloat64(f); err != nil {
+			panic(err)
+		}
+		return d
 	case types.ETString:
 		return randString()
+	case types.ETDatetime, types.ETTimestamp:
+		gt := types.FromDate(rand.Intn(2200), rand.Intn(10)+1, rand.Intn(20)+1, rand.Intn(12), rand.Intn(60), rand.Intn(60), rand.Intn(1000000))
+		return types.NewTime(gt, mysql.TypeDatetime, types.DefaultFsp)
+	case types.ETDuration:
+		d := types.Duration{
+			// use rand.Int32() to make it not overflow when AddDuration
+			Duration: time.Duration(rand.Int31()),
+		}
+		if rand.Float64() < 0.5 {
+			d.Duration = -d.Duration
+		}
+		return d
 	}
 	return nil
 }
 
+// dateTimeGener is used to generate datetimes whose years are in [beginYear, endYear).
+type dateTimeGener struct {
+	beginYear int
+	endYear   int
+	fsp       int8
+}
+
+func (g *dateTimeGener) gen() interface{} {
+	year := g.beginYear + rand.Intn(g.endYear-g.beginYear)
+	gt := types.FromDate(year, rand.Intn(12)+1, rand.Intn(28)+1, rand.Intn(24), rand.Intn(60), rand.Intn(60), rand.Intn(1000000))
+	t := types.NewTime(gt, mysql.TypeDatetime, types.MaxFsp)
+	t, err := t.RoundFrac(nil, g.fsp)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
 // selectStringGener select one string randomly from the candidates array
 type selectStringGener struct {
 	candidates []string
@@ -206,8 +246,14 @@ func fillColumnWithGener(eType types.EvalType, chk *chunk.Chunk, colIdx int, gen
 			col.AppendInt64(v.(int64))
 		case types.ETReal:
 			col.AppendFloat64(v.(float64))
+		case types.ETDecimal:
+			col.AppendMyDecimal(v.(*types.MyDecimal))
 		case types.ETString:
 			col.AppendString(v.(string))
+		case types.ETDatetime, types.ETTimestamp:
+			col.AppendTime(v.(types.Time))
+		case types.ETDuration:
+			col.AppendDuration(v.(types.Duration))
 		}
 	}
 }
@@ -234,8 +280,16 @@ func eType2FieldType(eType types.EvalType) *types.FieldType {
 		return types.NewFieldType(mysql.TypeLonglong)
 	case types.ETReal:
 		return types.NewFieldType(mysql.TypeDouble)
+	case types.ETDecimal:
+		return types.NewFieldType(mysql.TypeNewDecimal)
 	case types.ETString:
 		return types.NewFieldType(mysql.TypeVarString)
+	case types.ETDatetime:
+		return types.NewFieldType(mysql.TypeDatetime)
+	case types.ETTimestamp:
+		return types.NewFieldType(mysql.TypeTimestamp)
+	case types.ETDuration:
+		return types.NewFieldType(mysql.TypeDuration)
 	default:
 		panic(fmt.Sprintf("EvalType=%v is not supported.", eType))
 	}
@@ -488,6 +542,54 @@ func testVectorizedBuiltinFunc(c *C, vecExprCases vecExprBenchCases) {
 					}
 					i++
 				}
+			case types.ETDecimal:
+				err := baseFunc.vecEvalDecimal(input, output)
+				c.Assert(err, IsNil, Commentf("func: %v, case: %+v", baseFuncName, testCase))
+				// do not forget to call ResizeXXX/ReserveXXX
+				c.Assert(getColumnLen(output, testCase.retEvalType), Equals, input.NumRows())
+				vecWarnCnt = ctx.GetSessionVars().StmtCtx.WarningCount()
+				decs := output.Decimals()
+				for row := it.Begin(); row != it.End(); row = it.Next() {
+					val, isNull, err := baseFunc.evalDecimal(row)
+					c.Assert(err, IsNil, commentf(i))
+					c.Assert(isNull, Equals, output.IsNull(i), commentf(i))
+					if !isNull {
+						c.Assert(val.Compare(&decs[i]), Equals, 0, commentf(i))
+					}
+					i++
+				}
+			case types.ETDatetime, types.ETTimestamp:
+				err := baseFunc.vecEvalTime(input, output)
+				c.Assert(err, IsNil, Commentf("func: %v, case: %+v", baseFuncName, testCase))
+				// do not forget to call ResizeXXX/ReserveXXX
+				c.Assert(getColumnLen(output, testCase.retEvalType), Equals, input.NumRows())
+				vecWarnCnt = ctx.GetSessionVars().StmtCtx.WarningCount()
+				times := output.Times()
+				for row := it.Begin(); row != it.End(); row = it.Next() {
+					val, isNull, err := baseFunc.evalTime(row)
+					c.Assert(err, IsNil, commentf(i))
+					c.Assert(isNull, Equals, output.IsNull(i), commentf(i))
+					if !isNull {
+						c.Assert(val.Compare(times[i]), Equals, 0, commentf(i))
+					}
+					i++
+				}
+			case types.ETDuration:
+				err := baseFunc.vecEvalDuration(input, output)
+				c.Assert(err, IsNil, Commentf("func: %v, case: %+v", baseFuncName, testCase))
+				// do not forget to call ResizeXXX/ReserveXXX
+				c.Assert(getColumnLen(output, testCase.retEvalType), Equals, input.NumRows())
+				vecWarnCnt = ctx.GetSessionVars().StmtCtx.WarningCount()
+				ds := output.GoDurations()
+				for row := it.Begin(); row != it.End(); row = it.Next() {
+					val, isNull, err := baseFunc.evalDuration(row)
+					c.Assert(err, IsNil, commentf(i))
+					c.Assert(isNull, Equals, output.IsNull(i), commentf(i))
+					if !isNull {
+						c.Assert(val.Duration, Equals, ds[i], commentf(i))
+					}
+					i++
+				}
 			default:
 				c.Fatal(fmt.Sprintf("evalType=%v is not supported", testCase.retEvalType))
 			}
@@ -543,12 +645,30 @@ func benchmarkVectorizedBuiltinFunc(b *testing.B, vecExprCases vecExprBenchCases
 							b.Fatal(err)
 						}
 					}
+				case types.ETDecimal:
+					for i := 0; i < b.N; i++ {
+						if err := baseFunc.vecEvalDecimal(input, output); err != nil {
+							b.Fatal(err)
+						}
+					}
 				case types.ETString:
 					for i := 0; i < b.N; i++ {
 						if err := baseFunc.vecEvalString(input, output); err != nil {
 							b.Fatal(err)
 						}
 					}
+				case types.ETDatetime, types.ETTimestamp:
+					for i := 0; i < b.N; i++ {
+						if err := baseFunc.vecEvalTime(input, output); err != nil {
+							b.Fatal(err)
+						}
+					}
+				case types.ETDuration:
+					for i := 0; i < b.N; i++ {
+						if err := baseFunc.vecEvalDuration(input, output); err != nil {
+							b.Fatal(err)
+						}
+					}
 				default:
 					b.Fatal(fmt.Sprintf("evalType=%v is not supported", testCase.retEvalType))
 				}
@@ -602,6 +722,51 @@ func benchmarkVectorizedBuiltinFunc(b *testing.B, vecExprCases vecExprBenchCases
 							}
 						}
 					}
+				case types.ETDecimal:
+					for i := 0; i < b.N; i++ {
+						output.Reset(testCase.retEvalType)
+						for row := it.Begin(); row != it.End(); row = it.Next() {
+							v, isNull, err := baseFunc.evalDecimal(row)
+							if err != nil {
+								b.Fatal(err)
+							}
+							if isNull {
+								output.AppendNull()
+							} else {
+								output.AppendMyDecimal(v)
+							}
+						}
+					}
+				case types.ETDatetime, types.ETTimestamp:
+					for i := 0; i < b.N; i++ {
+						output.Reset(testCase.retEvalType)
+						for row := it.Begin(); row != it.End(); row = it.Next() {
+							v, isNull, err := baseFunc.evalTime(row)
+							if err != nil {
+								b.Fatal(err)
+							}
+							if isNull {
+								output.AppendNull()
+							} else {
+								output.AppendTime(v)
+							}
+						}
+					}
+				case types.ETDuration:
+					for i := 0; i < b.N; i++ {
+						output.Reset(testCase.retEvalType)
+						for row := it.Begin(); row != it.End(); row = it.Next() {
+							v, isNull, err := baseFunc.evalDuration(row)
+							if err != nil {
+								b.Fatal(err)
+							}
+							if isNull {
+								output.AppendNull()
+							} else {
+								output.AppendDuration(v)
+							}
+						}
+					}
 				default:
 					b.Fatal(fmt.Sprintf("evalType=%v is not supported", testCase.retEvalType))
 				}
@@ -36,6 +36,7 @@ import (
 	"github.com/pingcap/tidb/parser/opcode"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tipb/go-tipb"
 )
@@ -114,12 +115,47 @@ func newBaseBuiltinFuncWithTp(ctx sessionctx.Context, args []Expression, retType
 			Decimal: types.UnspecifiedLength,
 			Flag:    mysql.BinaryFlag,
 		}
+	case types.ETDecimal:
+		fieldType = &types.FieldType{
+			Tp:      mysql.TypeNewDecimal,
+			Flen:    11,
+			Decimal: 0,
+			Flag:    mysql.BinaryFlag,
+		}
 	case types.ETString:
 		fieldType = &types.FieldType{
 			Tp:      mysql.TypeVarString,
 			Flen:    0,
 			Decimal: types.UnspecifiedLength,
 		}
+	case types.ETDatetime:
+		fieldType = &types.FieldType{
+			Tp:      mysql.TypeDatetime,
+			Flen:    mysql.MaxDatetimeWidthWithFsp,
+			Decimal: int(types.MaxFsp),
+			Flag:    mysql.BinaryFlag,
+		}
+	case types.ETTimestamp:
+		fieldType = &types.FieldType{
+			Tp:      mysql.TypeTimestamp,
+			Flen:    mysql.MaxDatetimeWidthWithFsp,
+			Decimal: int(types.MaxFsp),
+			Flag:    mysql.BinaryFlag,
+		}
+	case types.ETDuration:
+		fieldType = &types.FieldType{
+			Tp:      mysql.TypeDuration,
+			Flen:    mysql.MaxDurationWidthWithFsp,
+			Decimal: int(types.MaxFsp),
+			Flag:    mysql.BinaryFlag,
+		}
+	case types.ETJson:
+		fieldType = &types.FieldType{
+			Tp:      mysql.TypeJSON,
+			Flen:    mysql.MaxBlobWidth,
+			Decimal: 0,
+			Flag:    mysql.BinaryFlag,
+		}
 	}
 	if mysql.HasBinaryFlag(fieldType.Flag) {
 		fieldType.Charset, fieldType.Collate = charset.CharsetBin, charset.CollationBin
@@ -180,6 +216,22 @@ func (b *baseBuiltinFunc) evalString(row chunk.Row) (string, bool, error) {
 	return "", false, errors.Errorf("baseBuiltinFunc.evalString() should never be called, please contact the TiDB team for help")
 }
 
+func (b *baseBuiltinFunc) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	return nil, false, errors.Errorf("baseBuiltinFunc.evalDecimal() should never be called, please contact the TiDB team for help")
+}
+
+func (b *baseBuiltinFunc) evalTime(row chunk.Row) (types.Time, bool, error) {
+	return types.ZeroTime, false, errors.Errorf("baseBuiltinFunc.evalTime() should never be called, please contact the TiDB team for help")
+}
+
+func (b *baseBuiltinFunc) evalDuration(row chunk.Row) (types.Duration, bool, error) {
+	return types.Duration{}, false, errors.Errorf("baseBuiltinFunc.evalDuration() should never be called, please contact the TiDB team for help")
+}
+
+func (b *baseBuiltinFunc) evalJSON(row chunk.Row) (json.BinaryJSON, bool, error) {
+	return json.BinaryJSON{}, false, errors.Errorf("baseBuiltinFunc.evalJSON() should never be called, please contact the TiDB team for help")
+}
+
 func (b *baseBuiltinFunc) vectorized() bool {
 	return false
 }
@@ -285,6 +337,14 @@ type builtinFunc interface {
 	evalReal(row chunk.Row) (val float64, isNull bool, err error)
 	// evalString evaluates string representation of builtinFunc by given row.
 	evalString(row chunk.Row) (val string, isNull bool, err error)
+	// evalDecimal evaluates decimal representation of builtinFunc by given row.
+	evalDecimal(row chunk.Row) (val *types.MyDecimal, isNull bool, err error)
+	// evalTime evaluates DATE/DATETIME/TIMESTAMP representation of builtinFunc by given row.
+	evalTime(row chunk.Row) (val types.Time, isNull bool, err error)
+	// evalDuration evaluates duration representation of builtinFunc by given row.
+	evalDuration(row chunk.Row) (val types.Duration, isNull bool, err error)
+	// evalJSON evaluates JSON representation of builtinFunc by given row.
+	evalJSON(row chunk.Row) (val json.BinaryJSON, isNull bool, err error)
 	// getArgs returns the arguments expressions.
 	getArgs() []Expression
 	// equal check if this function equals to another function.
@@ -333,15 +393,115 @@ var funcs = map[string]functionClass{
 	// common functions
 	ast.IsNull: &isNullFunctionClass{baseFunctionClass{ast.IsNull, 1, 1}},
 
+	// math functions
+	ast.Abs:      &absFunctionClass{baseFunctionClass{ast.Abs, 1, 1}},
+	ast.Acos:     &acosFunctionClass{baseFunctionClass{ast.Acos, 1, 1}},
+	ast.Asin:     &asinFunctionClass{baseFunctionClass{ast.Asin, 1, 1}},
+	ast.Atan:     &atanFunctionClass{baseFunctionClass{ast.Atan, 1, 2}},
+	ast.Atan2:    &atanFunctionClass{baseFunctionClass{ast.Atan2, 2, 2}},
+	ast.Ceil:     &ceilFunctionClass{baseFunctionClass{ast.Ceil, 1, 1}},
+	ast.Ceiling:  &ceilFunctionClass{baseFunctionClass{ast.Ceiling, 1, 1}},
+	ast.Cos:      &cosFunctionClass{baseFunctionClass{ast.Cos, 1, 1}},
+	ast.Cot:      &cotFunctionClass{baseFunctionClass{ast.Cot, 1, 1}},
+	ast.Degrees:  &degreesFunctionClass{baseFunctionClass{ast.Degrees, 1, 1}},
+	ast.Exp:      &expFunctionClass{baseFunctionClass{ast.Exp, 1, 1}},
+	ast.Floor:    &floorFunctionClass{baseFunctionClass{ast.Floor, 1, 1}},
+	ast.Ln:       &lnFunctionClass{baseFunctionClass{ast.Ln, 1, 1}},
+	ast.Log:      &logFunctionClass{baseFunctionClass{ast.Log, 1, 2}},
+	ast.Log2:     &log2FunctionClass{baseFunctionClass{ast.Log2, 1, 1}},
+	ast.Log10:    &log10FunctionClass{baseFunctionClass{ast.Log10, 1, 1}},
+	ast.PI:       &piFunctionClass{baseFunctionClass{ast.PI, 0, 0}},
+	ast.Pow:      &powFunctionClass{baseFunctionClass{ast.Pow, 2, 2}},
+	ast.Power:    &powFunctionClass{baseFunctionClass{ast.Power, 2, 2}},
+	ast.Radians:  &radiansFunctionClass{baseFunctionClass{ast.Radians, 1, 1}},
+	ast.Rand:     &randFunctionClass{baseFunctionClass{ast.Rand, 0, 1}},
+	ast.Round:    &roundFunctionClass{baseFunctionClass{ast.Round, 1, 2}},
+	ast.Sign:     &signFunctionClass{baseFunctionClass{ast.Sign, 1, 1}},
+	ast.Sin:      &sinFunctionClass{baseFunctionClass{ast.Sin, 1, 1}},
+	ast.Sqrt:     &sqrtFunctionClass{baseFunctionClass{ast.Sqrt, 1, 1}},
+	ast.Tan:      &tanFunctionClass{baseFunctionClass{ast.Tan, 1, 1}},
+	ast.Truncate: &truncateFunctionClass{baseFunctionClass{ast.Truncate, 2, 2}},
+
 	// string functions
 	ast.Length:      &lengthFunctionClass{baseFunctionClass{ast.Length, 1, 1}},
 	ast.OctetLength: &lengthFunctionClass{baseFunctionClass{ast.OctetLength, 1, 1}},
 	ast.Strcmp:      &strcmpFunctionClass{baseFunctionClass{ast.Strcmp, 2, 2}},
+	ast.Concat:      &concatFunctionClass{baseFunctionClass{ast.Concat, 1, -1}},
+	ast.ConcatWS:    &concatWSFunctionClass{baseFunctionClass{ast.ConcatWS, 2, -1}},
+	ast.Substring:   &substringFunctionClass{baseFunctionClass{ast.Substring, 2, 3}},
+	ast.Substr:      &substringFunctionClass{baseFunctionClass{ast.Substr, 2, 3}},
+	ast.Mid:         &substringFunctionClass{baseFunctionClass{ast.Mid, 3, 3}},
+	ast.Left:        &leftFunctionClass{baseFunctionClass{ast.Left, 2, 2}},
+	ast.Right:       &rightFunctionClass{baseFunctionClass{ast.Right, 2, 2}},
+	ast.Upper:       &upperFunctionClass{baseFunctionClass{ast.Upper, 1, 1}},
+	ast.Ucase:       &upperFunctionClass{baseFunctionClass{ast.Ucase, 1, 1}},
+	ast.Lower:       &lowerFunctionClass{baseFunctionClass{ast.Lower, 1, 1}},
+	ast.Lcase:       &lowerFunctionClass{baseFunctionClass{ast.Lcase, 1, 1}},
+	ast.Trim:        &trimFunctionClass{baseFunctionClass{ast.Trim, 1, 2}},
+	ast.LTrim:       &lTrimFunctionClass{baseFunctionClass{ast.LTrim, 1, 1}},
+	ast.RTrim:       &rTrimFunctionClass{baseFunctionClass{ast.RTrim, 1, 1}},
+	ast.Replace:     &replaceFunctionClass{baseFunctionClass{ast.Replace, 3, 3}},
+	ast.Locate:      &locateFunctionClass{baseFunctionClass{ast.Locate, 2, 3}},
+	ast.Position:    &locateFunctionClass{baseFunctionClass{ast.Position, 2, 2}},
+	ast.Instr:       &instrFunctionClass{baseFunctionClass{ast.Instr, 2, 2}},
+	ast.Lpad:        &lpadFunctionClass{baseFunctionClass{ast.Lpad, 3, 3}},
+	ast.Rpad:        &rpadFunctionClass{baseFunctionClass{ast.Rpad, 3, 3}},
+	ast.Repeat:      &repeatFunctionClass{baseFunctionClass{ast.Repeat, 2, 2}},
+	ast.Reverse:     &reverseFunctionClass{baseFunctionClass{ast.Reverse, 1, 1}},
+	ast.Hex:         &hexFunctionClass{baseFunctionClass{ast.Hex, 1, 1}},
+	ast.Unhex:       &unhexFunctionClass{baseFunctionClass{ast.Unhex, 1, 1}},
+	ast.Field:       &fieldFunctionClass{baseFunctionClass{ast.Field, 2, -1}},
+	ast.Elt:         &eltFunctionClass{baseFunctionClass{ast.Elt, 2, -1}},
+	ast.Like:        &likeFunctionClass{baseFunctionClass{ast.Like, 3, 3}},
+	ast.Regexp:      &regexpFunctionClass{baseFunctionClass{ast.Regexp, 2, 2}},
+
+	// time functions
+	ast.AddDate:          &dateArithFunctionClass{baseFunctionClass{ast.AddDate, 3, 3}, false},
+	ast.DateAdd:          &dateArithFunctionClass{baseFunctionClass{ast.DateAdd, 3, 3}, false},
+	ast.SubDate:          &dateArithFunctionClass{baseFunctionClass{ast.SubDate, 3, 3}, true},
+	ast.DateSub:          &dateArithFunctionClass{baseFunctionClass{ast.DateSub, 3, 3}, true},
+	ast.Curdate:          &currentDateFunctionClass{baseFunctionClass{ast.Curdate, 0, 0}},
+	ast.CurrentDate:      &currentDateFunctionClass{baseFunctionClass{ast.CurrentDate, 0, 0}},
+	ast.Curtime:          &currentTimeFunctionClass{baseFunctionClass{ast.Curtime, 0, 1}},
+	ast.CurrentTime:      &currentTimeFunctionClass{baseFunctionClass{ast.CurrentTime, 0, 1}},
+	ast.Now:              &nowFunctionClass{baseFunctionClass{ast.Now, 0, 1}},
+	ast.CurrentTimestamp: &nowFunctionClass{baseFunctionClass{ast.CurrentTimestamp, 0, 1}},
+	ast.LocalTime:        &nowFunctionClass{baseFunctionClass{ast.LocalTime, 0, 1}},
+	ast.LocalTimestamp:   &nowFunctionClass{baseFunctionClass{ast.LocalTimestamp, 0, 1}},
+	ast.Sysdate:          &sysDateFunctionClass{baseFunctionClass{ast.Sysdate, 0, 1}},
+	ast.UTCDate:          &utcDateFunctionClass{baseFunctionClass{ast.UTCDate, 0, 0}},
+	ast.UTCTime:          &utcTimeFunctionClass{baseFunctionClass{ast.UTCTime, 0, 1}},
+	ast.UTCTimestamp:     &utcTimestampFunctionClass{baseFunctionClass{ast.UTCTimestamp, 0, 1}},
+	ast.Year:             &yearFunctionClass{baseFunctionClass{ast.Year, 1, 1}},
+	ast.Month:            &monthFunctionClass{baseFunctionClass{ast.Month, 1, 1}},
+	ast.Day:              &dayOfMonthFunctionClass{baseFunctionClass{ast.Day, 1, 1}},
+	ast.DayOfMonth:       &dayOfMonthFunctionClass{baseFunctionClass{ast.DayOfMonth, 1, 1}},
+	ast.DateDiff:         &dateDiffFunctionClass{baseFunctionClass{ast.DateDiff, 2, 2}},
+	ast.DateFormat:       &dateFormatFunctionClass{baseFunctionClass{ast.DateFormat, 2, 2}},
+	ast.StrToDate:        &strToDateFunctionClass{baseFunctionClass{ast.StrToDate, 2, 2}},
+	ast.UnixTimestamp:    &unixTimestampFunctionClass{baseFunctionClass{ast.UnixTimestamp, 0, 1}},
+	ast.FromUnixTime:     &fromUnixTimeFunctionClass{baseFunctionClass{ast.FromUnixTime, 1, 2}},
+	ast.Extract:          &extractFunctionClass{baseFunctionClass{ast.Extract, 2, 2}},
+	ast.TimestampDiff:    &timestampDiffFunctionClass{baseFunctionClass{ast.TimestampDiff, 3, 3}},
+
+	// json functions
+	ast.JSONExtract:  &jsonExtractFunctionClass{baseFunctionClass{ast.JSONExtract, 2, -1}},
+	ast.JSONUnquote:  &jsonUnquoteFunctionClass{baseFunctionClass{ast.JSONUnquote, 1, 1}},
+	ast.JSONSet:      &jsonSetFunctionClass{baseFunctionClass{ast.JSONSet, 3, -1}},
+	ast.JSONObject:   &jsonObjectFunctionClass{baseFunctionClass{ast.JSONObject, 0, -1}},
+	ast.JSONArray:    &jsonArrayFunctionClass{baseFunctionClass{ast.JSONArray, 0, -1}},
+	ast.JSONContains: &jsonContainsFunctionClass{baseFunctionClass{ast.JSONContains, 2, 3}},
 
 	// control functions
+	ast.Case:   &caseWhenFunctionClass{baseFunctionClass{ast.Case, 1, -1}},
 	ast.If:     &ifFunctionClass{baseFunctionClass{ast.If, 3, 3}},
 	ast.Ifnull: &ifNullFunctionClass{baseFunctionClass{ast.Ifnull, 2, 2}},
 
+	// compare functions
+	ast.Coalesce: &coalesceFunctionClass{baseFunctionClass{ast.Coalesce, 1, -1}},
+	ast.Greatest: &greatestFunctionClass{baseFunctionClass{ast.Greatest, 2, -1}},
+	ast.Least:    &leastFunctionClass{baseFunctionClass{ast.Least, 2, -1}},
+
 	ast.LogicAnd:   &logicAndFunctionClass{baseFunctionClass{ast.LogicAnd, 2, 2}},
 	ast.LogicOr:    &logicOrFunctionClass{baseFunctionClass{ast.LogicOr, 2, 2}},
 	ast.GE:         &compareFunctionClass{baseFunctionClass{ast.GE, 2, 2}, opcode.GE},
@@ -354,6 +514,8 @@ var funcs = map[string]functionClass{
 	ast.Minus:      &arithmeticMinusFunctionClass{baseFunctionClass{ast.Minus, 2, 2}},
 	ast.Div:        &arithmeticDivideFunctionClass{baseFunctionClass{ast.Div, 2, 2}},
 	ast.Mul:        &arithmeticMultiplyFunctionClass{baseFunctionClass{ast.Mul, 2, 2}},
+	ast.Mod:        &arithmeticModFunctionClass{baseFunctionClass{ast.Mod, 2, 2}},
+	ast.IntDiv:     &arithmeticIntDivideFunctionClass{baseFunctionClass{ast.IntDiv, 2, 2}},
 	ast.UnaryNot:   &unaryNotFunctionClass{baseFunctionClass{ast.UnaryNot, 1, 1}},
 	ast.UnaryMinus: &unaryMinusFunctionClass{baseFunctionClass{ast.UnaryMinus, 1, 1}},
 	ast.In:         &inFunctionClass{baseFunctionClass{ast.In, 2, -1}},
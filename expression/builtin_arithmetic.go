@@ -19,6 +19,7 @@ import (
 
 	"github.com/cznic/mathutil"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
@@ -30,29 +31,46 @@ var (
 	_ functionClass = &arithmeticMinusFunctionClass{}
 	_ functionClass = &arithmeticDivideFunctionClass{}
 	_ functionClass = &arithmeticMultiplyFunctionClass{}
+	_ functionClass = &arithmeticIntDivideFunctionClass{}
+	_ functionClass = &arithmeticModFunctionClass{}
 )
 
 var (
 	_ builtinFunc = &builtinArithmeticPlusRealSig{}
+	_ builtinFunc = &builtinArithmeticPlusDecimalSig{}
 	_ builtinFunc = &builtinArithmeticPlusIntSig{}
 	_ builtinFunc = &builtinArithmeticMinusRealSig{}
+	_ builtinFunc = &builtinArithmeticMinusDecimalSig{}
 	_ builtinFunc = &builtinArithmeticMinusIntSig{}
 	_ builtinFunc = &builtinArithmeticDivideRealSig{}
+	_ builtinFunc = &builtinArithmeticDivideDecimalSig{}
 	_ builtinFunc = &builtinArithmeticMultiplyRealSig{}
+	_ builtinFunc = &builtinArithmeticMultiplyDecimalSig{}
 	_ builtinFunc = &builtinArithmeticMultiplyIntUnsignedSig{}
 	_ builtinFunc = &builtinArithmeticMultiplyIntSig{}
+	_ builtinFunc = &builtinArithmeticIntDivideIntSig{}
+	_ builtinFunc = &builtinArithmeticIntDivideDecimalSig{}
+	_ builtinFunc = &builtinArithmeticModIntSig{}
+	_ builtinFunc = &builtinArithmeticModRealSig{}
+	_ builtinFunc = &builtinArithmeticModDecimalSig{}
 )
 
 // numericContextResultType returns types.EvalType for numeric function's parameters.
 // the returned types.EvalType should be one of: types.ETInt, types.ETDecimal, types.ETReal
 func numericContextResultType(ft *types.FieldType) types.EvalType {
+	if types.IsTypeTemporal(ft.Tp) {
+		if ft.Decimal > 0 {
+			return types.ETDecimal
+		}
+		return types.ETInt
+	}
 	if types.IsBinaryStr(ft) {
 		return types.ETInt
 	}
 	evalTp4Ft := types.ETReal
 	if !ft.Hybrid() {
 		evalTp4Ft = ft.EvalType()
-		if evalTp4Ft != types.ETInt {
+		if evalTp4Ft != types.ETDecimal && evalTp4Ft != types.ETInt {
 			evalTp4Ft = types.ETReal
 		}
 	}
@@ -100,6 +118,28 @@ func setFlenDecimal4RealOrDecimal(retTp, a, b *types.FieldType, isReal bool, isM
 	}
 }
 
+func (c *arithmeticDivideFunctionClass) setType4DivDecimal(retTp, a, b *types.FieldType) {
+	var deca, decb = a.Decimal, b.Decimal
+	if deca == types.UnspecifiedLength {
+		deca = 0
+	}
+	if decb == types.UnspecifiedLength {
+		decb = 0
+	}
+	retTp.Decimal = deca + types.DivFracIncr
+	if retTp.Decimal > mysql.MaxDecimalScale {
+		retTp.Decimal = mysql.MaxDecimalScale
+	}
+	if a.Flen == types.UnspecifiedLength {
+		retTp.Flen = mysql.MaxDecimalWidth
+		return
+	}
+	retTp.Flen = a.Flen + decb + types.DivFracIncr
+	if retTp.Flen > mysql.MaxDecimalWidth {
+		retTp.Flen = mysql.MaxDecimalWidth
+	}
+}
+
 func (c *arithmeticDivideFunctionClass) setType4DivReal(retTp *types.FieldType) {
 	retTp.Decimal = types.UnspecifiedLength
 	retTp.Flen = mysql.MaxRealWidth
@@ -121,6 +161,12 @@ func (c *arithmeticPlusFunctionClass) getFunction(ctx sessionctx.Context, args [
 		sig := &builtinArithmeticPlusRealSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_PlusReal)
 		return sig, nil
+	} else if lhsEvalTp == types.ETDecimal || rhsEvalTp == types.ETDecimal {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDecimal, types.ETDecimal)
+		setFlenDecimal4RealOrDecimal(bf.tp, args[0].GetType(), args[1].GetType(), false, false)
+		sig := &builtinArithmeticPlusDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_PlusDecimal)
+		return sig, nil
 	}
 	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETInt, types.ETInt)
 	if mysql.HasUnsignedFlag(args[0].GetType().Flag) || mysql.HasUnsignedFlag(args[1].GetType().Flag) {
@@ -184,6 +230,36 @@ func (s *builtinArithmeticPlusIntSig) evalInt(row chunk.Row) (val int64, isNull
 	return a + b, false, nil
 }
 
+type builtinArithmeticPlusDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (s *builtinArithmeticPlusDecimalSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticPlusDecimalSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticPlusDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	a, isNull, err := s.args[0].EvalDecimal(s.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	b, isNull, err := s.args[1].EvalDecimal(s.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	c := &types.MyDecimal{}
+	err = types.DecimalAdd(a, b, c)
+	if err != nil {
+		if err == types.ErrOverflow {
+			err = types.ErrOverflow.GenWithStackByArgs("DECIMAL", fmt.Sprintf("(%s + %s)", s.args[0].String(), s.args[1].String()))
+		}
+		return nil, true, err
+	}
+	return c, false, nil
+}
+
 type builtinArithmeticPlusRealSig struct {
 	baseBuiltinFunc
 }
@@ -225,6 +301,12 @@ func (c *arithmeticMinusFunctionClass) getFunction(ctx sessionctx.Context, args
 		sig := &builtinArithmeticMinusRealSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_MinusReal)
 		return sig, nil
+	} else if lhsEvalTp == types.ETDecimal || rhsEvalTp == types.ETDecimal {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDecimal, types.ETDecimal)
+		setFlenDecimal4RealOrDecimal(bf.tp, args[0].GetType(), args[1].GetType(), false, false)
+		sig := &builtinArithmeticMinusDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_MinusDecimal)
+		return sig, nil
 	}
 	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETInt, types.ETInt)
 	setFlenDecimal4Int(bf.tp, args[0].GetType(), args[1].GetType())
@@ -261,6 +343,36 @@ func (s *builtinArithmeticMinusRealSig) evalReal(row chunk.Row) (float64, bool,
 	return a - b, false, nil
 }
 
+type builtinArithmeticMinusDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (s *builtinArithmeticMinusDecimalSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticMinusDecimalSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticMinusDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	a, isNull, err := s.args[0].EvalDecimal(s.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	b, isNull, err := s.args[1].EvalDecimal(s.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	c := &types.MyDecimal{}
+	err = types.DecimalSub(a, b, c)
+	if err != nil {
+		if err == types.ErrOverflow {
+			err = types.ErrOverflow.GenWithStackByArgs("DECIMAL", fmt.Sprintf("(%s - %s)", s.args[0].String(), s.args[1].String()))
+		}
+		return nil, true, err
+	}
+	return c, false, nil
+}
+
 type builtinArithmeticMinusIntSig struct {
 	baseBuiltinFunc
 }
@@ -339,6 +451,12 @@ func (c *arithmeticMultiplyFunctionClass) getFunction(ctx sessionctx.Context, ar
 		sig := &builtinArithmeticMultiplyRealSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_MultiplyReal)
 		return sig, nil
+	} else if lhsEvalTp == types.ETDecimal || rhsEvalTp == types.ETDecimal {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDecimal, types.ETDecimal)
+		setFlenDecimal4RealOrDecimal(bf.tp, args[0].GetType(), args[1].GetType(), false, true)
+		sig := &builtinArithmeticMultiplyDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_MultiplyDecimal)
+		return sig, nil
 	}
 	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETInt, types.ETInt)
 	if mysql.HasUnsignedFlag(lhsTp.Flag) || mysql.HasUnsignedFlag(rhsTp.Flag) {
@@ -362,6 +480,14 @@ func (s *builtinArithmeticMultiplyRealSig) Clone() builtinFunc {
 	return newSig
 }
 
+type builtinArithmeticMultiplyDecimalSig struct{ baseBuiltinFunc }
+
+func (s *builtinArithmeticMultiplyDecimalSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticMultiplyDecimalSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
 type builtinArithmeticMultiplyIntUnsignedSig struct{ baseBuiltinFunc }
 
 func (s *builtinArithmeticMultiplyIntUnsignedSig) Clone() builtinFunc {
@@ -394,6 +520,26 @@ func (s *builtinArithmeticMultiplyRealSig) evalReal(row chunk.Row) (float64, boo
 	return result, false, nil
 }
 
+func (s *builtinArithmeticMultiplyDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	a, isNull, err := s.args[0].EvalDecimal(s.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	b, isNull, err := s.args[1].EvalDecimal(s.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	c := &types.MyDecimal{}
+	err = types.DecimalMul(a, b, c)
+	if err != nil && !terror.ErrorEqual(err, types.ErrTruncated) {
+		if err == types.ErrOverflow {
+			err = types.ErrOverflow.GenWithStackByArgs("DECIMAL", fmt.Sprintf("(%s * %s)", s.args[0].String(), s.args[1].String()))
+		}
+		return nil, true, err
+	}
+	return c, false, nil
+}
+
 func (s *builtinArithmeticMultiplyIntUnsignedSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
 	a, isNull, err := s.args[0].EvalInt(s.ctx, row)
 	if isNull || err != nil {
@@ -436,10 +582,19 @@ func (c *arithmeticDivideFunctionClass) getFunction(ctx sessionctx.Context, args
 	if err := c.verifyArgs(args); err != nil {
 		return nil, err
 	}
-	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, types.ETReal, types.ETReal)
-	c.setType4DivReal(bf.tp)
-	sig := &builtinArithmeticDivideRealSig{bf}
-	sig.setPbCode(tipb.ScalarFuncSig_DivideReal)
+	lhsTp, rhsTp := args[0].GetType(), args[1].GetType()
+	lhsEvalTp, rhsEvalTp := numericContextResultType(lhsTp), numericContextResultType(rhsTp)
+	if lhsEvalTp == types.ETReal || rhsEvalTp == types.ETReal {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, types.ETReal, types.ETReal)
+		c.setType4DivReal(bf.tp)
+		sig := &builtinArithmeticDivideRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_DivideReal)
+		return sig, nil
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDecimal, types.ETDecimal)
+	c.setType4DivDecimal(bf.tp, lhsTp, rhsTp)
+	sig := &builtinArithmeticDivideDecimalSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_DivideDecimal)
 	return sig, nil
 }
 
@@ -469,3 +624,302 @@ func (s *builtinArithmeticDivideRealSig) evalReal(row chunk.Row) (float64, bool,
 	}
 	return result, false, nil
 }
+
+type builtinArithmeticDivideDecimalSig struct{ baseBuiltinFunc }
+
+func (s *builtinArithmeticDivideDecimalSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticDivideDecimalSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticDivideDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	a, isNull, err := s.args[0].EvalDecimal(s.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+
+	b, isNull, err := s.args[1].EvalDecimal(s.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+
+	c := &types.MyDecimal{}
+	err = types.DecimalDiv(a, b, c, types.DivFracIncr)
+	if err == types.ErrDivByZero {
+		return c, true, handleDivisionByZeroError(s.ctx)
+	} else if err == nil {
+		_, frac := c.PrecisionAndFrac()
+		if frac != s.tp.Decimal {
+			err = c.Round(c, s.tp.Decimal, types.ModeHalfEven)
+		}
+	}
+	return c, false, err
+}
+
+type arithmeticIntDivideFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *arithmeticIntDivideFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	lhsTp, rhsTp := args[0].GetType(), args[1].GetType()
+	lhsEvalTp, rhsEvalTp := numericContextResultType(lhsTp), numericContextResultType(rhsTp)
+	isUnsigned := mysql.HasUnsignedFlag(lhsTp.Flag) || mysql.HasUnsignedFlag(rhsTp.Flag)
+	if lhsEvalTp == types.ETInt && rhsEvalTp == types.ETInt {
+		wrapArgsWithCast(ctx, args, types.ETInt, types.ETInt)
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETInt, types.ETInt)
+		if isUnsigned {
+			bf.tp.Flag |= mysql.UnsignedFlag
+		}
+		sig := &builtinArithmeticIntDivideIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IntDivideInt)
+		return sig, nil
+	}
+	wrapArgsWithCast(ctx, args, types.ETDecimal, types.ETDecimal)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETDecimal, types.ETDecimal)
+	if isUnsigned {
+		bf.tp.Flag |= mysql.UnsignedFlag
+	}
+	sig := &builtinArithmeticIntDivideDecimalSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_IntDivideDecimal)
+	return sig, nil
+}
+
+type builtinArithmeticIntDivideIntSig struct{ baseBuiltinFunc }
+
+func (s *builtinArithmeticIntDivideIntSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticIntDivideIntSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticIntDivideIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	b, isNull, err := s.args[1].EvalInt(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if b == 0 {
+		return 0, true, handleDivisionByZeroError(s.ctx)
+	}
+	a, isNull, err := s.args[0].EvalInt(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	ret, err := s.intDivide(a, b)
+	return ret, err != nil, err
+}
+
+// intDivide returns a DIV b, b must not be 0.
+func (s *builtinArithmeticIntDivideIntSig) intDivide(a, b int64) (int64, error) {
+	isLHSUnsigned := mysql.HasUnsignedFlag(s.args[0].GetType().Flag)
+	isRHSUnsigned := mysql.HasUnsignedFlag(s.args[1].GetType().Flag)
+	var (
+		ret  int64
+		uRet uint64
+		err  error
+	)
+	switch {
+	case isLHSUnsigned && isRHSUnsigned:
+		return int64(uint64(a) / uint64(b)), nil
+	case isLHSUnsigned && !isRHSUnsigned:
+		uRet, err = types.DivUintWithInt(uint64(a), b)
+		ret = int64(uRet)
+	case !isLHSUnsigned && isRHSUnsigned:
+		uRet, err = types.DivIntWithUint(a, uint64(b))
+		ret = int64(uRet)
+	default:
+		ret, err = types.DivInt64(a, b)
+	}
+	if err != nil {
+		tp := "BIGINT"
+		if isLHSUnsigned || isRHSUnsigned {
+			tp = "BIGINT UNSIGNED"
+		}
+		return 0, types.ErrOverflow.GenWithStackByArgs(tp, fmt.Sprintf("(%s DIV %s)", s.args[0].String(), s.args[1].String()))
+	}
+	return ret, nil
+}
+
+type builtinArithmeticIntDivideDecimalSig struct{ baseBuiltinFunc }
+
+func (s *builtinArithmeticIntDivideDecimalSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticIntDivideDecimalSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticIntDivideDecimalSig) evalInt(row chunk.Row) (int64, bool, error) {
+	a, isNull, err := s.args[0].EvalDecimal(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	b, isNull, err := s.args[1].EvalDecimal(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+
+	c := &types.MyDecimal{}
+	err = types.DecimalDiv(a, b, c, types.DivFracIncr)
+	if err == types.ErrDivByZero {
+		return 0, true, handleDivisionByZeroError(s.ctx)
+	}
+	if err != nil && err != types.ErrTruncated {
+		return 0, true, err
+	}
+
+	var ret int64
+	if mysql.HasUnsignedFlag(s.tp.Flag) {
+		var uRet uint64
+		uRet, err = c.ToUint()
+		ret = int64(uRet)
+	} else {
+		ret, err = c.ToInt()
+	}
+	// The error returned by ToInt and ToUint may be ErrTruncated or
+	// ErrOverflow, the fraction part is expected to be truncated.
+	if err == types.ErrOverflow {
+		tp := "BIGINT"
+		if mysql.HasUnsignedFlag(s.tp.Flag) {
+			tp = "BIGINT UNSIGNED"
+		}
+		return 0, true, types.ErrOverflow.GenWithStackByArgs(tp, fmt.Sprintf("(%s DIV %s)", s.args[0].String(), s.args[1].String()))
+	}
+	return ret, false, nil
+}
+
+type arithmeticModFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *arithmeticModFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	lhsTp, rhsTp := args[0].GetType(), args[1].GetType()
+	lhsEvalTp, rhsEvalTp := numericContextResultType(lhsTp), numericContextResultType(rhsTp)
+	// The sign of the result is the sign of the dividend.
+	isUnsigned := mysql.HasUnsignedFlag(lhsTp.Flag)
+	if lhsEvalTp == types.ETReal || rhsEvalTp == types.ETReal {
+		wrapArgsWithCast(ctx, args, types.ETReal, types.ETReal)
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, types.ETReal, types.ETReal)
+		setFlenDecimal4RealOrDecimal(bf.tp, lhsTp, rhsTp, true, false)
+		if isUnsigned {
+			bf.tp.Flag |= mysql.UnsignedFlag
+		}
+		sig := &builtinArithmeticModRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_ModReal)
+		return sig, nil
+	} else if lhsEvalTp == types.ETDecimal || rhsEvalTp == types.ETDecimal {
+		wrapArgsWithCast(ctx, args, types.ETDecimal, types.ETDecimal)
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDecimal, types.ETDecimal)
+		setFlenDecimal4RealOrDecimal(bf.tp, lhsTp, rhsTp, false, false)
+		if isUnsigned {
+			bf.tp.Flag |= mysql.UnsignedFlag
+		}
+		sig := &builtinArithmeticModDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_ModDecimal)
+		return sig, nil
+	}
+	wrapArgsWithCast(ctx, args, types.ETInt, types.ETInt)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETInt, types.ETInt)
+	if isUnsigned {
+		bf.tp.Flag |= mysql.UnsignedFlag
+	}
+	sig := &builtinArithmeticModIntSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_ModInt)
+	return sig, nil
+}
+
+type builtinArithmeticModRealSig struct{ baseBuiltinFunc }
+
+func (s *builtinArithmeticModRealSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticModRealSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticModRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	b, isNull, err := s.args[1].EvalReal(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if b == 0 {
+		return 0, true, handleDivisionByZeroError(s.ctx)
+	}
+	a, isNull, err := s.args[0].EvalReal(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return math.Mod(a, b), false, nil
+}
+
+type builtinArithmeticModDecimalSig struct{ baseBuiltinFunc }
+
+func (s *builtinArithmeticModDecimalSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticModDecimalSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticModDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	a, isNull, err := s.args[0].EvalDecimal(s.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	b, isNull, err := s.args[1].EvalDecimal(s.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	c := &types.MyDecimal{}
+	err = types.DecimalMod(a, b, c)
+	if err == types.ErrDivByZero {
+		return c, true, handleDivisionByZeroError(s.ctx)
+	}
+	return c, err != nil, err
+}
+
+type builtinArithmeticModIntSig struct{ baseBuiltinFunc }
+
+func (s *builtinArithmeticModIntSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticModIntSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticModIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	b, isNull, err := s.args[1].EvalInt(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if b == 0 {
+		return 0, true, handleDivisionByZeroError(s.ctx)
+	}
+	a, isNull, err := s.args[0].EvalInt(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return modInt(a, b, mysql.HasUnsignedFlag(s.args[0].GetType().Flag), mysql.HasUnsignedFlag(s.args[1].GetType().Flag)), false, nil
+}
+
+// modInt returns a % b, the sign of the result is the sign of a. b must not be 0.
+func modInt(a, b int64, isLHSUnsigned, isRHSUnsigned bool) int64 {
+	switch {
+	case isLHSUnsigned && isRHSUnsigned:
+		return int64(uint64(a) % uint64(b))
+	case isLHSUnsigned && !isRHSUnsigned:
+		if b < 0 {
+			return int64(uint64(a) % uint64(-b))
+		}
+		return int64(uint64(a) % uint64(b))
+	case !isLHSUnsigned && isRHSUnsigned:
+		if a < 0 {
+			return -int64(uint64(-a) % uint64(b))
+		}
+		return int64(uint64(a) % uint64(b))
+	default:
+		return a % b
+	}
+}
@@ -14,9 +14,12 @@
 package expression
 
 import (
+	"math"
+
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/testutil"
@@ -268,6 +271,14 @@ func (s *testEvaluatorSuite) TestArithmeticMultiply(c *C) {
 			args:   []interface{}{float64(11), float64(11)},
 			expect: float64(121),
 		},
+		{
+			args:   []interface{}{types.NewDecFromStringForTest("12.5"), types.NewDecFromStringForTest("-0.04")},
+			expect: types.NewDecFromStringForTest("-0.500"),
+		},
+		{
+			args:   []interface{}{types.NewDecFromStringForTest("12.5"), int64(2)},
+			expect: types.NewDecFromStringForTest("25.0"),
+		},
 		{
 			args:   []interface{}{nil, float64(-0.11101)},
 			expect: nil,
@@ -291,3 +302,157 @@ func (s *testEvaluatorSuite) TestArithmeticMultiply(c *C) {
 		c.Assert(val, testutil.DatumEquals, types.NewDatum(tc.expect))
 	}
 }
+
+func (s *testEvaluatorSuite) TestArithmeticDecimal(c *C) {
+	testCases := []struct {
+		funcName string
+		args     []interface{}
+		expect   string
+	}{
+		{ast.Plus, []interface{}{types.NewDecFromStringForTest("0.1"), types.NewDecFromStringForTest("0.2")}, "0.3"},
+		{ast.Plus, []interface{}{types.NewDecFromStringForTest("1.25"), int64(-3)}, "-1.75"},
+		{ast.Minus, []interface{}{types.NewDecFromStringForTest("0.3"), types.NewDecFromStringForTest("0.1")}, "0.2"},
+		{ast.Minus, []interface{}{int64(1), types.NewDecFromStringForTest("1.000")}, "0.000"},
+		{ast.Div, []interface{}{int64(1), int64(3)}, "0.3333"},
+		{ast.Div, []interface{}{types.NewDecFromStringForTest("10.00"), int64(4)}, "2.500000"},
+	}
+
+	for _, tc := range testCases {
+		sig, err := funcs[tc.funcName].getFunction(s.ctx, s.primitiveValsToConstants(tc.args))
+		c.Assert(err, IsNil)
+		c.Assert(sig, NotNil)
+		c.Assert(sig.getRetTp().EvalType(), Equals, types.ETDecimal)
+		val, err := evalBuiltinFunc(sig, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(val.GetMysqlDecimal().String(), Equals, tc.expect)
+	}
+
+	sig, err := funcs[ast.Div].getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{types.NewDecFromStringForTest("1.5"), int64(0)}))
+	c.Assert(err, IsNil)
+	val, err := evalBuiltinFunc(sig, chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(val.IsNull(), IsTrue)
+}
+
+func (s *testEvaluatorSuite) TestArithmeticIntDivide(c *C) {
+	testCases := []struct {
+		args   []interface{}
+		expect interface{}
+		err    error
+	}{
+		{
+			args:   []interface{}{int64(13), int64(11)},
+			expect: int64(1),
+		},
+		{
+			args:   []interface{}{int64(-13), int64(11)},
+			expect: int64(-1),
+		},
+		{
+			args:   []interface{}{uint64(13), int64(11)},
+			expect: int64(1),
+		},
+		{
+			args:   []interface{}{float64(11.01), float64(1.1)},
+			expect: int64(10),
+		},
+		{
+			args:   []interface{}{types.NewDecFromStringForTest("13.5"), int64(2)},
+			expect: int64(6),
+		},
+		{
+			args:   []interface{}{int64(10), int64(0)},
+			expect: nil,
+		},
+		{
+			args:   []interface{}{nil, int64(2)},
+			expect: nil,
+		},
+		{
+			args: []interface{}{int64(math.MinInt64), int64(-1)},
+			err:  types.ErrOverflow,
+		},
+		{
+			args:   []interface{}{int64(-1), uint64(2)},
+			expect: int64(0),
+		},
+		{
+			args: []interface{}{int64(-13), uint64(11)},
+			err:  types.ErrOverflow,
+		},
+		{
+			args: []interface{}{uint64(13), int64(-11)},
+			err:  types.ErrOverflow,
+		},
+	}
+
+	for _, tc := range testCases {
+		sig, err := funcs[ast.IntDiv].getFunction(s.ctx, s.datumsToConstants(types.MakeDatums(tc.args...)))
+		c.Assert(err, IsNil)
+		c.Assert(sig, NotNil)
+		val, err := evalBuiltinFunc(sig, chunk.Row{})
+		if tc.err != nil {
+			c.Assert(terror.ErrorEqual(err, tc.err), IsTrue)
+			continue
+		}
+		c.Assert(err, IsNil)
+		c.Assert(val, testutil.DatumEquals, types.NewDatum(tc.expect))
+	}
+}
+
+func (s *testEvaluatorSuite) TestArithmeticMod(c *C) {
+	testCases := []struct {
+		args   []interface{}
+		expect interface{}
+	}{
+		{
+			args:   []interface{}{int64(13), int64(11)},
+			expect: int64(2),
+		},
+		{
+			args:   []interface{}{int64(-13), int64(11)},
+			expect: int64(-2),
+		},
+		{
+			args:   []interface{}{int64(13), int64(-11)},
+			expect: int64(2),
+		},
+		{
+			args:   []interface{}{uint64(13), int64(-11)},
+			expect: int64(2),
+		},
+		{
+			args:   []interface{}{int64(-13), uint64(11)},
+			expect: int64(-2),
+		},
+		{
+			args:   []interface{}{float64(10.5), float64(3)},
+			expect: float64(1.5),
+		},
+		{
+			args:   []interface{}{types.NewDecFromStringForTest("10.5"), int64(3)},
+			expect: types.NewDecFromStringForTest("1.5"),
+		},
+		{
+			args:   []interface{}{int64(13), int64(0)},
+			expect: nil,
+		},
+		{
+			args:   []interface{}{float64(13), float64(0)},
+			expect: nil,
+		},
+		{
+			args:   []interface{}{nil, int64(2)},
+			expect: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		sig, err := funcs[ast.Mod].getFunction(s.ctx, s.datumsToConstants(types.MakeDatums(tc.args...)))
+		c.Assert(err, IsNil)
+		c.Assert(sig, NotNil)
+		val, err := evalBuiltinFunc(sig, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(val, testutil.DatumEquals, types.NewDatum(tc.expect))
+	}
+}
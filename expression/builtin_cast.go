@@ -0,0 +1,1674 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// We implement 7 CastAsXXFunctionClass for `cast` built-in functions.
+// XX means the return type of the `cast` built-in functions.
+// XX contains the following 7 types:
+// Int, Real, Decimal, Time, Duration, String, JSON.
+
+// We implement 49 CastYYAsXXSig built-in function signatures.
+// YY and XX both contain the same 7 types.
+// YY means the input type of the `cast` built-in function.
+// XX means the output type of the `cast` built-in function.
+
+package expression
+
+import (
+	"strconv"
+
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+var (
+	_ functionClass = &castAsIntFunctionClass{}
+	_ functionClass = &castAsRealFunctionClass{}
+	_ functionClass = &castAsDecimalFunctionClass{}
+	_ functionClass = &castAsStringFunctionClass{}
+	_ functionClass = &castAsTimeFunctionClass{}
+	_ functionClass = &castAsDurationFunctionClass{}
+	_ functionClass = &castAsJSONFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinCastIntAsIntSig{}
+	_ builtinFunc = &builtinCastIntAsRealSig{}
+	_ builtinFunc = &builtinCastIntAsDecimalSig{}
+	_ builtinFunc = &builtinCastIntAsStringSig{}
+	_ builtinFunc = &builtinCastIntAsTimeSig{}
+	_ builtinFunc = &builtinCastIntAsDurationSig{}
+	_ builtinFunc = &builtinCastIntAsJSONSig{}
+
+	_ builtinFunc = &builtinCastRealAsIntSig{}
+	_ builtinFunc = &builtinCastRealAsRealSig{}
+	_ builtinFunc = &builtinCastRealAsDecimalSig{}
+	_ builtinFunc = &builtinCastRealAsStringSig{}
+	_ builtinFunc = &builtinCastRealAsTimeSig{}
+	_ builtinFunc = &builtinCastRealAsDurationSig{}
+	_ builtinFunc = &builtinCastRealAsJSONSig{}
+
+	_ builtinFunc = &builtinCastDecimalAsIntSig{}
+	_ builtinFunc = &builtinCastDecimalAsRealSig{}
+	_ builtinFunc = &builtinCastDecimalAsDecimalSig{}
+	_ builtinFunc = &builtinCastDecimalAsStringSig{}
+	_ builtinFunc = &builtinCastDecimalAsTimeSig{}
+	_ builtinFunc = &builtinCastDecimalAsDurationSig{}
+	_ builtinFunc = &builtinCastDecimalAsJSONSig{}
+
+	_ builtinFunc = &builtinCastStringAsIntSig{}
+	_ builtinFunc = &builtinCastStringAsRealSig{}
+	_ builtinFunc = &builtinCastStringAsDecimalSig{}
+	_ builtinFunc = &builtinCastStringAsStringSig{}
+	_ builtinFunc = &builtinCastStringAsTimeSig{}
+	_ builtinFunc = &builtinCastStringAsDurationSig{}
+	_ builtinFunc = &builtinCastStringAsJSONSig{}
+
+	_ builtinFunc = &builtinCastTimeAsIntSig{}
+	_ builtinFunc = &builtinCastTimeAsRealSig{}
+	_ builtinFunc = &builtinCastTimeAsDecimalSig{}
+	_ builtinFunc = &builtinCastTimeAsStringSig{}
+	_ builtinFunc = &builtinCastTimeAsTimeSig{}
+	_ builtinFunc = &builtinCastTimeAsDurationSig{}
+	_ builtinFunc = &builtinCastTimeAsJSONSig{}
+
+	_ builtinFunc = &builtinCastDurationAsIntSig{}
+	_ builtinFunc = &builtinCastDurationAsRealSig{}
+	_ builtinFunc = &builtinCastDurationAsDecimalSig{}
+	_ builtinFunc = &builtinCastDurationAsStringSig{}
+	_ builtinFunc = &builtinCastDurationAsTimeSig{}
+	_ builtinFunc = &builtinCastDurationAsDurationSig{}
+	_ builtinFunc = &builtinCastDurationAsJSONSig{}
+
+	_ builtinFunc = &builtinCastJSONAsIntSig{}
+	_ builtinFunc = &builtinCastJSONAsRealSig{}
+	_ builtinFunc = &builtinCastJSONAsDecimalSig{}
+	_ builtinFunc = &builtinCastJSONAsStringSig{}
+	_ builtinFunc = &builtinCastJSONAsTimeSig{}
+	_ builtinFunc = &builtinCastJSONAsDurationSig{}
+	_ builtinFunc = &builtinCastJSONAsJSONSig{}
+)
+
+type castAsIntFunctionClass struct {
+	baseFunctionClass
+
+	tp *types.FieldType
+}
+
+func (c *castAsIntFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFunc(ctx, args)
+	bf.tp = c.tp
+	switch args[0].GetType().EvalType() {
+	case types.ETInt:
+		sig = &builtinCastIntAsIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastIntAsInt)
+	case types.ETReal:
+		sig = &builtinCastRealAsIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastRealAsInt)
+	case types.ETDecimal:
+		sig = &builtinCastDecimalAsIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDecimalAsInt)
+	case types.ETString:
+		sig = &builtinCastStringAsIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastStringAsInt)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinCastTimeAsIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastTimeAsInt)
+	case types.ETDuration:
+		sig = &builtinCastDurationAsIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDurationAsInt)
+	case types.ETJson:
+		sig = &builtinCastJSONAsIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastJsonAsInt)
+	default:
+		panic("unsupported types.EvalType in castAsIntFunctionClass")
+	}
+	return sig, nil
+}
+
+type castAsRealFunctionClass struct {
+	baseFunctionClass
+
+	tp *types.FieldType
+}
+
+func (c *castAsRealFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFunc(ctx, args)
+	bf.tp = c.tp
+	switch args[0].GetType().EvalType() {
+	case types.ETInt:
+		sig = &builtinCastIntAsRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastIntAsReal)
+	case types.ETReal:
+		sig = &builtinCastRealAsRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastRealAsReal)
+	case types.ETDecimal:
+		sig = &builtinCastDecimalAsRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDecimalAsReal)
+	case types.ETString:
+		sig = &builtinCastStringAsRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastStringAsReal)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinCastTimeAsRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastTimeAsReal)
+	case types.ETDuration:
+		sig = &builtinCastDurationAsRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDurationAsReal)
+	case types.ETJson:
+		sig = &builtinCastJSONAsRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastJsonAsReal)
+	default:
+		panic("unsupported types.EvalType in castAsRealFunctionClass")
+	}
+	return sig, nil
+}
+
+type castAsDecimalFunctionClass struct {
+	baseFunctionClass
+
+	tp *types.FieldType
+}
+
+func (c *castAsDecimalFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFunc(ctx, args)
+	bf.tp = c.tp
+	switch args[0].GetType().EvalType() {
+	case types.ETInt:
+		sig = &builtinCastIntAsDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastIntAsDecimal)
+	case types.ETReal:
+		sig = &builtinCastRealAsDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastRealAsDecimal)
+	case types.ETDecimal:
+		sig = &builtinCastDecimalAsDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDecimalAsDecimal)
+	case types.ETString:
+		sig = &builtinCastStringAsDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastStringAsDecimal)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinCastTimeAsDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastTimeAsDecimal)
+	case types.ETDuration:
+		sig = &builtinCastDurationAsDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDurationAsDecimal)
+	case types.ETJson:
+		sig = &builtinCastJSONAsDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastJsonAsDecimal)
+	default:
+		panic("unsupported types.EvalType in castAsDecimalFunctionClass")
+	}
+	return sig, nil
+}
+
+type castAsStringFunctionClass struct {
+	baseFunctionClass
+
+	tp *types.FieldType
+}
+
+func (c *castAsStringFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFunc(ctx, args)
+	bf.tp = c.tp
+	switch args[0].GetType().EvalType() {
+	case types.ETInt:
+		sig = &builtinCastIntAsStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastIntAsString)
+	case types.ETReal:
+		sig = &builtinCastRealAsStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastRealAsString)
+	case types.ETDecimal:
+		sig = &builtinCastDecimalAsStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDecimalAsString)
+	case types.ETString:
+		sig = &builtinCastStringAsStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastStringAsString)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinCastTimeAsStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastTimeAsString)
+	case types.ETDuration:
+		sig = &builtinCastDurationAsStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDurationAsString)
+	case types.ETJson:
+		sig = &builtinCastJSONAsStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastJsonAsString)
+	default:
+		panic("unsupported types.EvalType in castAsStringFunctionClass")
+	}
+	return sig, nil
+}
+
+type castAsTimeFunctionClass struct {
+	baseFunctionClass
+
+	tp *types.FieldType
+}
+
+func (c *castAsTimeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFunc(ctx, args)
+	bf.tp = c.tp
+	switch args[0].GetType().EvalType() {
+	case types.ETInt:
+		sig = &builtinCastIntAsTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastIntAsTime)
+	case types.ETReal:
+		sig = &builtinCastRealAsTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastRealAsTime)
+	case types.ETDecimal:
+		sig = &builtinCastDecimalAsTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDecimalAsTime)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinCastTimeAsTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastTimeAsTime)
+	case types.ETDuration:
+		sig = &builtinCastDurationAsTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDurationAsTime)
+	case types.ETString:
+		sig = &builtinCastStringAsTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastStringAsTime)
+	case types.ETJson:
+		sig = &builtinCastJSONAsTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastJsonAsTime)
+	default:
+		panic("unsupported types.EvalType in castAsTimeFunctionClass")
+	}
+	return sig, nil
+}
+
+type castAsDurationFunctionClass struct {
+	baseFunctionClass
+
+	tp *types.FieldType
+}
+
+func (c *castAsDurationFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFunc(ctx, args)
+	bf.tp = c.tp
+	switch args[0].GetType().EvalType() {
+	case types.ETInt:
+		sig = &builtinCastIntAsDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastIntAsDuration)
+	case types.ETReal:
+		sig = &builtinCastRealAsDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastRealAsDuration)
+	case types.ETDecimal:
+		sig = &builtinCastDecimalAsDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDecimalAsDuration)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinCastTimeAsDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastTimeAsDuration)
+	case types.ETDuration:
+		sig = &builtinCastDurationAsDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDurationAsDuration)
+	case types.ETString:
+		sig = &builtinCastStringAsDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastStringAsDuration)
+	case types.ETJson:
+		sig = &builtinCastJSONAsDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastJsonAsDuration)
+	default:
+		panic("unsupported types.EvalType in castAsDurationFunctionClass")
+	}
+	return sig, nil
+}
+
+type castAsJSONFunctionClass struct {
+	baseFunctionClass
+
+	tp *types.FieldType
+}
+
+func (c *castAsJSONFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFunc(ctx, args)
+	bf.tp = c.tp
+	switch args[0].GetType().EvalType() {
+	case types.ETInt:
+		sig = &builtinCastIntAsJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastIntAsJson)
+	case types.ETReal:
+		sig = &builtinCastRealAsJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastRealAsJson)
+	case types.ETDecimal:
+		sig = &builtinCastDecimalAsJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDecimalAsJson)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinCastTimeAsJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastTimeAsJson)
+	case types.ETDuration:
+		sig = &builtinCastDurationAsJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDurationAsJson)
+	case types.ETString:
+		sig = &builtinCastStringAsJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastStringAsJson)
+	case types.ETJson:
+		sig = &builtinCastJSONAsJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastJsonAsJson)
+	default:
+		panic("unsupported types.EvalType in castAsJSONFunctionClass")
+	}
+	return sig, nil
+}
+
+type builtinCastIntAsIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastIntAsIntSig) Clone() builtinFunc {
+	newSig := &builtinCastIntAsIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastIntAsIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	return b.args[0].EvalInt(b.ctx, row)
+}
+
+type builtinCastIntAsRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastIntAsRealSig) Clone() builtinFunc {
+	newSig := &builtinCastIntAsRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastIntAsRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	if mysql.HasUnsignedFlag(b.args[0].GetType().Flag) {
+		res = float64(uint64(val))
+	} else {
+		res = float64(val)
+	}
+	return res, false, nil
+}
+
+type builtinCastIntAsDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastIntAsDecimalSig) Clone() builtinFunc {
+	newSig := &builtinCastIntAsDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastIntAsDecimalSig) evalDecimal(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	if mysql.HasUnsignedFlag(b.args[0].GetType().Flag) {
+		res = types.NewDecFromUint(uint64(val))
+	} else {
+		res = types.NewDecFromInt(val)
+	}
+	res, err = types.ProduceDecWithSpecifiedTp(res, b.tp, b.ctx.GetSessionVars().StmtCtx)
+	return res, isNull, err
+}
+
+type builtinCastIntAsStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastIntAsStringSig) Clone() builtinFunc {
+	newSig := &builtinCastIntAsStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastIntAsStringSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	if mysql.HasUnsignedFlag(b.args[0].GetType().Flag) {
+		res = strconv.FormatUint(uint64(val), 10)
+	} else {
+		res = strconv.FormatInt(val, 10)
+	}
+	return res, false, nil
+}
+
+type builtinCastRealAsIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastRealAsIntSig) Clone() builtinFunc {
+	newSig := &builtinCastRealAsIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastRealAsIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	if !mysql.HasUnsignedFlag(b.tp.Flag) {
+		res, err = types.ConvertFloatToInt(val, types.IntergerSignedLowerBound(mysql.TypeLonglong), types.IntergerSignedUpperBound(mysql.TypeLonglong), mysql.TypeDouble)
+	} else {
+		var uintVal uint64
+		sc := b.ctx.GetSessionVars().StmtCtx
+		uintVal, err = types.ConvertFloatToUint(sc, val, types.IntergerUnsignedUpperBound(mysql.TypeLonglong), mysql.TypeDouble)
+		res = int64(uintVal)
+	}
+	if types.ErrOverflow.Equal(err) {
+		err = b.ctx.GetSessionVars().StmtCtx.HandleOverflow(err, err)
+	}
+	return res, false, err
+}
+
+type builtinCastRealAsRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastRealAsRealSig) Clone() builtinFunc {
+	newSig := &builtinCastRealAsRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastRealAsRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	res, isNull, err = b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return
+	}
+	if mysql.HasUnsignedFlag(b.tp.Flag) && res < 0 {
+		res = 0
+	}
+	return
+}
+
+type builtinCastRealAsDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastRealAsDecimalSig) Clone() builtinFunc {
+	newSig := &builtinCastRealAsDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastRealAsDecimalSig) evalDecimal(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res = new(types.MyDecimal)
+	sc := b.ctx.GetSessionVars().StmtCtx
+	if err = sc.HandleTruncate(res.FromFloat64(val)); err != nil {
+		return res, true, err
+	}
+	res, err = types.ProduceDecWithSpecifiedTp(res, b.tp, sc)
+	return res, false, err
+}
+
+type builtinCastRealAsStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastRealAsStringSig) Clone() builtinFunc {
+	newSig := &builtinCastRealAsStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastRealAsStringSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	bits := 64
+	if b.args[0].GetType().Tp == mysql.TypeFloat {
+		// If we strconv.FormatFloat the value with 64bits, the result is incorrect!
+		bits = 32
+	}
+	return strconv.FormatFloat(val, 'f', -1, bits), false, nil
+}
+
+type builtinCastDecimalAsIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDecimalAsIntSig) Clone() builtinFunc {
+	newSig := &builtinCastDecimalAsIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDecimalAsIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+
+	// Round is needed for both unsigned and signed.
+	var to types.MyDecimal
+	err = val.Round(&to, 0, types.ModeHalfEven)
+	if err != nil {
+		return 0, true, err
+	}
+
+	if !mysql.HasUnsignedFlag(b.tp.Flag) {
+		res, err = to.ToInt()
+	} else if val.IsNegative() {
+		res = 0
+	} else {
+		var uintRes uint64
+		uintRes, err = to.ToUint()
+		res = int64(uintRes)
+	}
+
+	if types.ErrOverflow.Equal(err) {
+		warnErr := types.ErrTruncatedWrongVal.GenWithStackByArgs("DECIMAL", val)
+		err = b.ctx.GetSessionVars().StmtCtx.HandleOverflow(err, warnErr)
+	}
+	return res, false, err
+}
+
+type builtinCastDecimalAsRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDecimalAsRealSig) Clone() builtinFunc {
+	newSig := &builtinCastDecimalAsRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDecimalAsRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = val.ToFloat64()
+	if mysql.HasUnsignedFlag(b.tp.Flag) && res < 0 {
+		res = 0
+	}
+	return res, false, err
+}
+
+type builtinCastDecimalAsDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDecimalAsDecimalSig) Clone() builtinFunc {
+	newSig := &builtinCastDecimalAsDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDecimalAsDecimalSig) evalDecimal(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	evalDecimal, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res = &types.MyDecimal{}
+	if !(mysql.HasUnsignedFlag(b.tp.Flag) && evalDecimal.IsNegative()) {
+		*res = *evalDecimal
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	res, err = types.ProduceDecWithSpecifiedTp(res, b.tp, sc)
+	return res, false, err
+}
+
+type builtinCastDecimalAsStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDecimalAsStringSig) Clone() builtinFunc {
+	newSig := &builtinCastDecimalAsStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDecimalAsStringSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	return string(val.ToString()), false, nil
+}
+
+type builtinCastStringAsIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastStringAsIntSig) Clone() builtinFunc {
+	newSig := &builtinCastStringAsIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastStringAsIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	if mysql.HasUnsignedFlag(b.tp.Flag) {
+		var ures uint64
+		ures, err = types.StrToUint(sc, val)
+		res = int64(ures)
+	} else {
+		res, err = types.StrToInt(sc, val)
+	}
+	if types.ErrOverflow.Equal(err) {
+		err = sc.HandleOverflow(err, err)
+	} else {
+		err = sc.HandleTruncate(err)
+	}
+	return res, false, err
+}
+
+type builtinCastStringAsRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastStringAsRealSig) Clone() builtinFunc {
+	newSig := &builtinCastStringAsRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastStringAsRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	res, err = types.StrToFloat(sc, val)
+	if err != nil {
+		return 0, false, sc.HandleTruncate(err)
+	}
+	if mysql.HasUnsignedFlag(b.tp.Flag) && res < 0 {
+		res = 0
+	}
+	return res, false, nil
+}
+
+type builtinCastStringAsDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastStringAsDecimalSig) Clone() builtinFunc {
+	newSig := &builtinCastStringAsDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastStringAsDecimalSig) evalDecimal(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res = new(types.MyDecimal)
+	sc := b.ctx.GetSessionVars().StmtCtx
+	if err = sc.HandleTruncate(res.FromString([]byte(val))); err != nil {
+		return res, false, err
+	}
+	if mysql.HasUnsignedFlag(b.tp.Flag) && res.IsNegative() {
+		res = &types.MyDecimal{}
+	}
+	res, err = types.ProduceDecWithSpecifiedTp(res, b.tp, sc)
+	return res, false, err
+}
+
+type builtinCastStringAsStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastStringAsStringSig) Clone() builtinFunc {
+	newSig := &builtinCastStringAsStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastStringAsStringSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	return b.args[0].EvalString(b.ctx, row)
+}
+
+type builtinCastIntAsTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastIntAsTimeSig) Clone() builtinFunc {
+	newSig := &builtinCastIntAsTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastIntAsTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = types.ParseTimeFromNum(b.ctx.GetSessionVars().StmtCtx, val, b.tp.Tp, int8(b.tp.Decimal))
+	if err != nil {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, err)
+	}
+	return res, false, nil
+}
+
+type builtinCastIntAsDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastIntAsDurationSig) Clone() builtinFunc {
+	newSig := &builtinCastIntAsDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastIntAsDurationSig) evalDuration(row chunk.Row) (res types.Duration, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = types.NumberToDuration(val, int8(b.tp.Decimal))
+	if err != nil {
+		if types.ErrOverflow.Equal(err) {
+			err = b.ctx.GetSessionVars().StmtCtx.HandleOverflow(err, err)
+		}
+		return res, true, err
+	}
+	return res, false, nil
+}
+
+type builtinCastRealAsTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastRealAsTimeSig) Clone() builtinFunc {
+	newSig := &builtinCastRealAsTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastRealAsTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	res, err = types.ParseTimeFromFloatString(sc, strconv.FormatFloat(val, 'f', -1, 64), b.tp.Tp, int8(b.tp.Decimal))
+	if err != nil {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, err)
+	}
+	return res, false, nil
+}
+
+type builtinCastRealAsDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastRealAsDurationSig) Clone() builtinFunc {
+	newSig := &builtinCastRealAsDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastRealAsDurationSig) evalDuration(row chunk.Row) (res types.Duration, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	res, err = types.ParseDuration(sc, strconv.FormatFloat(val, 'f', -1, 64), int8(b.tp.Decimal))
+	if types.ErrTruncatedWrongVal.Equal(err) {
+		err = sc.HandleTruncate(err)
+	}
+	return res, false, err
+}
+
+type builtinCastDecimalAsTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDecimalAsTimeSig) Clone() builtinFunc {
+	newSig := &builtinCastDecimalAsTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDecimalAsTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	res, err = types.ParseTimeFromFloatString(sc, string(val.ToString()), b.tp.Tp, int8(b.tp.Decimal))
+	if err != nil {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, err)
+	}
+	return res, false, nil
+}
+
+type builtinCastDecimalAsDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDecimalAsDurationSig) Clone() builtinFunc {
+	newSig := &builtinCastDecimalAsDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDecimalAsDurationSig) evalDuration(row chunk.Row) (res types.Duration, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	res, err = types.ParseDuration(sc, string(val.ToString()), int8(b.tp.Decimal))
+	if types.ErrTruncatedWrongVal.Equal(err) {
+		err = sc.HandleTruncate(err)
+		// An invalid decimal value is truncated to zero.
+		res = types.ZeroDuration
+	}
+	return res, false, err
+}
+
+type builtinCastStringAsTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastStringAsTimeSig) Clone() builtinFunc {
+	newSig := &builtinCastStringAsTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastStringAsTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = types.ParseTime(b.ctx.GetSessionVars().StmtCtx, val, b.tp.Tp, int8(b.tp.Decimal))
+	if err != nil {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, err)
+	}
+	return res, false, nil
+}
+
+type builtinCastStringAsDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastStringAsDurationSig) Clone() builtinFunc {
+	newSig := &builtinCastStringAsDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastStringAsDurationSig) evalDuration(row chunk.Row) (res types.Duration, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	res, err = types.ParseDuration(sc, val, int8(b.tp.Decimal))
+	if types.ErrTruncatedWrongVal.Equal(err) {
+		err = sc.HandleTruncate(err)
+	}
+	return res, false, err
+}
+
+type builtinCastTimeAsIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastTimeAsIntSig) Clone() builtinFunc {
+	newSig := &builtinCastTimeAsIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastTimeAsIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	t, err := val.RoundFrac(b.ctx.GetSessionVars().StmtCtx, types.DefaultFsp)
+	if err != nil {
+		return res, false, err
+	}
+	res, err = t.ToNumber().ToInt()
+	return res, false, err
+}
+
+type builtinCastTimeAsRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastTimeAsRealSig) Clone() builtinFunc {
+	newSig := &builtinCastTimeAsRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastTimeAsRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = val.ToNumber().ToFloat64()
+	return res, false, err
+}
+
+type builtinCastTimeAsDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastTimeAsDecimalSig) Clone() builtinFunc {
+	newSig := &builtinCastTimeAsDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastTimeAsDecimalSig) evalDecimal(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = types.ProduceDecWithSpecifiedTp(val.ToNumber(), b.tp, b.ctx.GetSessionVars().StmtCtx)
+	return res, false, err
+}
+
+type builtinCastTimeAsStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastTimeAsStringSig) Clone() builtinFunc {
+	newSig := &builtinCastTimeAsStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastTimeAsStringSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = types.ProduceStrWithSpecifiedTp(val.String(), b.tp, b.ctx.GetSessionVars().StmtCtx, false)
+	return res, false, err
+}
+
+type builtinCastTimeAsTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastTimeAsTimeSig) Clone() builtinFunc {
+	newSig := &builtinCastTimeAsTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastTimeAsTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	res, isNull, err = b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	if res, err = res.Convert(sc, b.tp.Tp); err != nil {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, err)
+	}
+	res, err = res.RoundFrac(sc, int8(b.tp.Decimal))
+	if b.tp.Tp == mysql.TypeDate {
+		// Truncate hh:mm:ss part if the type is Date.
+		res.SetCoreTime(types.FromDate(res.Year(), res.Month(), res.Day(), 0, 0, 0, 0))
+		res.SetType(b.tp.Tp)
+	}
+	return res, false, err
+}
+
+type builtinCastTimeAsDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastTimeAsDurationSig) Clone() builtinFunc {
+	newSig := &builtinCastTimeAsDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastTimeAsDurationSig) evalDuration(row chunk.Row) (res types.Duration, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = val.ConvertToDuration()
+	if err != nil {
+		return res, false, err
+	}
+	res, err = res.RoundFrac(int8(b.tp.Decimal))
+	return res, false, err
+}
+
+type builtinCastDurationAsIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDurationAsIntSig) Clone() builtinFunc {
+	newSig := &builtinCastDurationAsIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDurationAsIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalDuration(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	dur, err := val.RoundFrac(types.DefaultFsp)
+	if err != nil {
+		return res, false, err
+	}
+	res, err = dur.ToNumber().ToInt()
+	return res, false, err
+}
+
+type builtinCastDurationAsRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDurationAsRealSig) Clone() builtinFunc {
+	newSig := &builtinCastDurationAsRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDurationAsRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalDuration(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = val.ToNumber().ToFloat64()
+	return res, false, err
+}
+
+type builtinCastDurationAsDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDurationAsDecimalSig) Clone() builtinFunc {
+	newSig := &builtinCastDurationAsDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDurationAsDecimalSig) evalDecimal(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalDuration(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = types.ProduceDecWithSpecifiedTp(val.ToNumber(), b.tp, b.ctx.GetSessionVars().StmtCtx)
+	return res, false, err
+}
+
+type builtinCastDurationAsStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDurationAsStringSig) Clone() builtinFunc {
+	newSig := &builtinCastDurationAsStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDurationAsStringSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalDuration(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = types.ProduceStrWithSpecifiedTp(val.String(), b.tp, b.ctx.GetSessionVars().StmtCtx, false)
+	return res, false, err
+}
+
+type builtinCastDurationAsTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDurationAsTimeSig) Clone() builtinFunc {
+	newSig := &builtinCastDurationAsTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDurationAsTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalDuration(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	res, err = val.ConvertToTime(sc, b.tp.Tp)
+	if err != nil {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, err)
+	}
+	res, err = res.RoundFrac(sc, int8(b.tp.Decimal))
+	return res, false, err
+}
+
+type builtinCastDurationAsDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDurationAsDurationSig) Clone() builtinFunc {
+	newSig := &builtinCastDurationAsDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDurationAsDurationSig) evalDuration(row chunk.Row) (res types.Duration, isNull bool, err error) {
+	res, isNull, err = b.args[0].EvalDuration(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = res.RoundFrac(int8(b.tp.Decimal))
+	return res, false, err
+}
+
+type builtinCastIntAsJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastIntAsJSONSig) Clone() builtinFunc {
+	newSig := &builtinCastIntAsJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastIntAsJSONSig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	if mysql.HasIsBooleanFlag(b.args[0].GetType().Flag) {
+		res = json.CreateBinary(val != 0)
+	} else if mysql.HasUnsignedFlag(b.args[0].GetType().Flag) {
+		res = json.CreateBinary(uint64(val))
+	} else {
+		res = json.CreateBinary(val)
+	}
+	return res, false, nil
+}
+
+type builtinCastRealAsJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastRealAsJSONSig) Clone() builtinFunc {
+	newSig := &builtinCastRealAsJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastRealAsJSONSig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	return json.CreateBinary(val), false, nil
+}
+
+type builtinCastDecimalAsJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDecimalAsJSONSig) Clone() builtinFunc {
+	newSig := &builtinCastDecimalAsJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDecimalAsJSONSig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	// FIXME: Only float64 is supported by the JSON binary encoding, so precision may be lost here.
+	f64, err := val.ToFloat64()
+	if err != nil {
+		return res, true, err
+	}
+	return json.CreateBinary(f64), false, nil
+}
+
+type builtinCastStringAsJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastStringAsJSONSig) Clone() builtinFunc {
+	newSig := &builtinCastStringAsJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastStringAsJSONSig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	if mysql.HasParseToJSONFlag(b.tp.Flag) {
+		res, err = json.ParseBinaryFromString(val)
+	} else {
+		res = json.CreateBinary(val)
+	}
+	return res, false, err
+}
+
+type builtinCastTimeAsJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastTimeAsJSONSig) Clone() builtinFunc {
+	newSig := &builtinCastTimeAsJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastTimeAsJSONSig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	return json.CreateBinary(val.String()), false, nil
+}
+
+type builtinCastDurationAsJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDurationAsJSONSig) Clone() builtinFunc {
+	newSig := &builtinCastDurationAsJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDurationAsJSONSig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalDuration(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	val.Fsp = types.MaxFsp
+	return json.CreateBinary(val.String()), false, nil
+}
+
+type builtinCastJSONAsJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastJSONAsJSONSig) Clone() builtinFunc {
+	newSig := &builtinCastJSONAsJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastJSONAsJSONSig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	return b.args[0].EvalJSON(b.ctx, row)
+}
+
+type builtinCastJSONAsIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastJSONAsIntSig) Clone() builtinFunc {
+	newSig := &builtinCastJSONAsIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastJSONAsIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	res, err = types.ConvertJSONToInt(sc, val, mysql.HasUnsignedFlag(b.tp.Flag))
+	if types.ErrOverflow.Equal(err) {
+		err = sc.HandleOverflow(err, err)
+	} else {
+		err = sc.HandleTruncate(err)
+	}
+	return res, false, err
+}
+
+type builtinCastJSONAsRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastJSONAsRealSig) Clone() builtinFunc {
+	newSig := &builtinCastJSONAsRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastJSONAsRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = types.ConvertJSONToFloat(b.ctx.GetSessionVars().StmtCtx, val)
+	return res, false, err
+}
+
+type builtinCastJSONAsDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastJSONAsDecimalSig) Clone() builtinFunc {
+	newSig := &builtinCastJSONAsDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastJSONAsDecimalSig) evalDecimal(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	res, err = types.ConvertJSONToDecimal(sc, val)
+	if err != nil {
+		return res, false, err
+	}
+	res, err = types.ProduceDecWithSpecifiedTp(res, b.tp, sc)
+	return res, false, err
+}
+
+type builtinCastJSONAsStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastJSONAsStringSig) Clone() builtinFunc {
+	newSig := &builtinCastJSONAsStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastJSONAsStringSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	return val.String(), false, nil
+}
+
+type builtinCastJSONAsTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastJSONAsTimeSig) Clone() builtinFunc {
+	newSig := &builtinCastJSONAsTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastJSONAsTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	s, err := val.Unquote()
+	if err != nil {
+		return res, false, err
+	}
+	res, err = types.ParseTime(b.ctx.GetSessionVars().StmtCtx, s, b.tp.Tp, int8(b.tp.Decimal))
+	if err != nil {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, err)
+	}
+	return res, false, nil
+}
+
+type builtinCastJSONAsDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastJSONAsDurationSig) Clone() builtinFunc {
+	newSig := &builtinCastJSONAsDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastJSONAsDurationSig) evalDuration(row chunk.Row) (res types.Duration, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	s, err := val.Unquote()
+	if err != nil {
+		return res, false, err
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	res, err = types.ParseDuration(sc, s, int8(b.tp.Decimal))
+	if types.ErrTruncatedWrongVal.Equal(err) {
+		err = sc.HandleTruncate(err)
+	}
+	return res, false, err
+}
+
+// BuildCastFunction builds a CAST ScalarFunction from the Expression.
+func BuildCastFunction(ctx sessionctx.Context, expr Expression, tp *types.FieldType) (res Expression) {
+	var fc functionClass
+	switch tp.EvalType() {
+	case types.ETInt:
+		fc = &castAsIntFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+	case types.ETReal:
+		fc = &castAsRealFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+	case types.ETDecimal:
+		fc = &castAsDecimalFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+	case types.ETDatetime, types.ETTimestamp:
+		fc = &castAsTimeFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+	case types.ETDuration:
+		fc = &castAsDurationFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+	case types.ETJson:
+		fc = &castAsJSONFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+	default:
+		fc = &castAsStringFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+	}
+	f, err := fc.getFunction(ctx, []Expression{expr})
+	terror.Log(err)
+	res = &ScalarFunction{
+		FuncName: model.NewCIStr(ast.Cast),
+		RetType:  tp,
+		Function: f,
+	}
+	return FoldConstant(res)
+}
+
+// wrapWithCastAsJSON wraps `expr` with `cast` if the return type of expr is
+// not type json, otherwise, returns `expr` directly. A string is parsed as a
+// JSON document if parse is true, or else it is treated as a JSON string.
+func wrapWithCastAsJSON(ctx sessionctx.Context, expr Expression, parse bool) Expression {
+	if expr.GetType().EvalType() == types.ETJson {
+		return expr
+	}
+	tp := &types.FieldType{
+		Tp:      mysql.TypeJSON,
+		Flen:    mysql.MaxBlobWidth,
+		Decimal: 0,
+		Charset: charset.CharsetBin,
+		Collate: charset.CollationBin,
+		Flag:    mysql.BinaryFlag,
+	}
+	if parse && expr.GetType().EvalType() == types.ETString {
+		tp.Flag |= mysql.ParseToJSONFlag
+	}
+	return BuildCastFunction(ctx, expr, tp)
+}
+
+// WrapWithCastAsInt wraps `expr` with `cast` if the return type of expr is not
+// type int, otherwise, returns `expr` directly.
+func WrapWithCastAsInt(ctx sessionctx.Context, expr Expression) Expression {
+	if expr.GetType().EvalType() == types.ETInt {
+		return expr
+	}
+	tp := types.NewFieldType(mysql.TypeLonglong)
+	tp.Flen, tp.Decimal = expr.GetType().Flen, 0
+	types.SetBinChsClnFlag(tp)
+	tp.Flag |= expr.GetType().Flag & mysql.UnsignedFlag
+	return BuildCastFunction(ctx, expr, tp)
+}
+
+// WrapWithCastAsReal wraps `expr` with `cast` if the return type of expr is not
+// type real, otherwise, returns `expr` directly.
+func WrapWithCastAsReal(ctx sessionctx.Context, expr Expression) Expression {
+	if expr.GetType().EvalType() == types.ETReal {
+		return expr
+	}
+	tp := types.NewFieldType(mysql.TypeDouble)
+	tp.Flen, tp.Decimal = mysql.MaxRealWidth, types.UnspecifiedLength
+	types.SetBinChsClnFlag(tp)
+	tp.Flag |= expr.GetType().Flag & mysql.UnsignedFlag
+	return BuildCastFunction(ctx, expr, tp)
+}
+
+// WrapWithCastAsDecimal wraps `expr` with `cast` if the return type of expr is
+// not type decimal, otherwise, returns `expr` directly.
+func WrapWithCastAsDecimal(ctx sessionctx.Context, expr Expression) Expression {
+	if expr.GetType().EvalType() == types.ETDecimal {
+		return expr
+	}
+	tp := types.NewFieldType(mysql.TypeNewDecimal)
+	tp.Flen, tp.Decimal = expr.GetType().Flen, expr.GetType().Decimal
+	if expr.GetType().EvalType() == types.ETInt {
+		tp.Flen = mysql.MaxIntWidth
+	}
+	types.SetBinChsClnFlag(tp)
+	tp.Flag |= expr.GetType().Flag & mysql.UnsignedFlag
+	return BuildCastFunction(ctx, expr, tp)
+}
+
+// WrapWithCastAsString wraps `expr` with `cast` if the return type of expr is
+// not type string, otherwise, returns `expr` directly.
+func WrapWithCastAsString(ctx sessionctx.Context, expr Expression) Expression {
+	exprTp := expr.GetType()
+	if exprTp.EvalType() == types.ETString {
+		return expr
+	}
+	argLen := exprTp.Flen
+	// The decimal point and the negative sign of a decimal are counted in the
+	// length of the string.
+	if exprTp.EvalType() == types.ETDecimal && argLen != types.UnspecifiedLength {
+		argLen += 2
+	}
+	if exprTp.EvalType() == types.ETInt {
+		argLen = mysql.MaxIntWidth
+	}
+	tp := types.NewFieldType(mysql.TypeVarString)
+	tp.Charset, tp.Collate = charset.GetDefaultCharsetAndCollate()
+	tp.Flen, tp.Decimal = argLen, types.UnspecifiedLength
+	return BuildCastFunction(ctx, expr, tp)
+}
+
+// WrapWithCastAsTime wraps `expr` with `cast` if the return type of expr is not
+// same as type of the specified `tp` , otherwise, returns `expr` directly.
+func WrapWithCastAsTime(ctx sessionctx.Context, expr Expression, tp *types.FieldType) Expression {
+	exprTp := expr.GetType().Tp
+	if tp.Tp == exprTp {
+		return expr
+	} else if (exprTp == mysql.TypeDate || exprTp == mysql.TypeTimestamp) && tp.Tp == mysql.TypeDatetime {
+		return expr
+	}
+	switch x := expr.GetType(); x.Tp {
+	case mysql.TypeDatetime, mysql.TypeTimestamp, mysql.TypeDate, mysql.TypeDuration:
+		tp.Decimal = x.Decimal
+	default:
+		tp.Decimal = int(types.MaxFsp)
+	}
+	switch tp.Tp {
+	case mysql.TypeDate:
+		tp.Flen = mysql.MaxDateWidth
+	case mysql.TypeDatetime, mysql.TypeTimestamp:
+		tp.Flen = mysql.MaxDatetimeWidthNoFsp
+		if tp.Decimal > 0 {
+			tp.Flen = tp.Flen + 1 + tp.Decimal
+		}
+	}
+	types.SetBinChsClnFlag(tp)
+	return BuildCastFunction(ctx, expr, tp)
+}
+
+// WrapWithCastAsDuration wraps `expr` with `cast` if the return type of expr is
+// not type duration, otherwise, returns `expr` directly.
+func WrapWithCastAsDuration(ctx sessionctx.Context, expr Expression) Expression {
+	if expr.GetType().Tp == mysql.TypeDuration {
+		return expr
+	}
+	tp := types.NewFieldType(mysql.TypeDuration)
+	switch x := expr.GetType(); x.Tp {
+	case mysql.TypeDatetime, mysql.TypeTimestamp, mysql.TypeDate:
+		tp.Decimal = x.Decimal
+	default:
+		tp.Decimal = int(types.MaxFsp)
+	}
+	tp.Flen = mysql.MaxDurationWidthNoFsp
+	if tp.Decimal > 0 {
+		tp.Flen = tp.Flen + 1 + tp.Decimal
+	}
+	types.SetBinChsClnFlag(tp)
+	return BuildCastFunction(ctx, expr, tp)
+}
+
+// wrapArgsWithCast wraps every argument whose return type differs from the
+// corresponding type in argTps with `cast`, so the built-in function signature
+// can evaluate it as the type it expects.
+func wrapArgsWithCast(ctx sessionctx.Context, args []Expression, argTps ...types.EvalType) {
+	for i := range args {
+		switch argTps[i] {
+		case types.ETInt:
+			args[i] = WrapWithCastAsInt(ctx, args[i])
+		case types.ETReal:
+			args[i] = WrapWithCastAsReal(ctx, args[i])
+		case types.ETDecimal:
+			args[i] = WrapWithCastAsDecimal(ctx, args[i])
+		case types.ETString:
+			args[i] = WrapWithCastAsString(ctx, args[i])
+		case types.ETDatetime, types.ETTimestamp:
+			args[i] = WrapWithCastAsTime(ctx, args[i], types.NewFieldType(mysql.TypeDatetime))
+		case types.ETDuration:
+			args[i] = WrapWithCastAsDuration(ctx, args[i])
+		}
+	}
+}
@@ -20,36 +20,669 @@ import (
 	"github.com/pingcap/tidb/parser/opcode"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tipb/go-tipb"
 )
 
 var (
+	_ functionClass = &coalesceFunctionClass{}
+	_ functionClass = &greatestFunctionClass{}
+	_ functionClass = &leastFunctionClass{}
 	_ functionClass = &compareFunctionClass{}
 )
 
 var (
+	_ builtinFunc = &builtinCoalesceIntSig{}
+	_ builtinFunc = &builtinCoalesceRealSig{}
+	_ builtinFunc = &builtinCoalesceDecimalSig{}
+	_ builtinFunc = &builtinCoalesceStringSig{}
+	_ builtinFunc = &builtinCoalesceTimeSig{}
+	_ builtinFunc = &builtinCoalesceDurationSig{}
+	_ builtinFunc = &builtinCoalesceJSONSig{}
+
+	_ builtinFunc = &builtinGreatestIntSig{}
+	_ builtinFunc = &builtinGreatestRealSig{}
+	_ builtinFunc = &builtinGreatestDecimalSig{}
+	_ builtinFunc = &builtinGreatestStringSig{}
+	_ builtinFunc = &builtinGreatestTimeSig{}
+
+	_ builtinFunc = &builtinLeastIntSig{}
+	_ builtinFunc = &builtinLeastRealSig{}
+	_ builtinFunc = &builtinLeastDecimalSig{}
+	_ builtinFunc = &builtinLeastStringSig{}
+	_ builtinFunc = &builtinLeastTimeSig{}
+
 	_ builtinFunc = &builtinLTIntSig{}
 	_ builtinFunc = &builtinLTRealSig{}
+	_ builtinFunc = &builtinLTDecimalSig{}
 	_ builtinFunc = &builtinLTStringSig{}
+	_ builtinFunc = &builtinLTTimeSig{}
+	_ builtinFunc = &builtinLTDurationSig{}
+	_ builtinFunc = &builtinLTJSONSig{}
 
 	_ builtinFunc = &builtinLEIntSig{}
 	_ builtinFunc = &builtinLERealSig{}
+	_ builtinFunc = &builtinLEDecimalSig{}
 	_ builtinFunc = &builtinLEStringSig{}
+	_ builtinFunc = &builtinLETimeSig{}
+	_ builtinFunc = &builtinLEDurationSig{}
+	_ builtinFunc = &builtinLEJSONSig{}
 
 	_ builtinFunc = &builtinGTIntSig{}
 	_ builtinFunc = &builtinGTRealSig{}
+	_ builtinFunc = &builtinGTDecimalSig{}
 	_ builtinFunc = &builtinGTStringSig{}
+	_ builtinFunc = &builtinGTTimeSig{}
+	_ builtinFunc = &builtinGTDurationSig{}
+	_ builtinFunc = &builtinGTJSONSig{}
 
 	_ builtinFunc = &builtinGEIntSig{}
 	_ builtinFunc = &builtinGERealSig{}
+	_ builtinFunc = &builtinGEDecimalSig{}
 	_ builtinFunc = &builtinGEStringSig{}
+	_ builtinFunc = &builtinGETimeSig{}
+	_ builtinFunc = &builtinGEDurationSig{}
+	_ builtinFunc = &builtinGEJSONSig{}
 
 	_ builtinFunc = &builtinNEIntSig{}
 	_ builtinFunc = &builtinNERealSig{}
+	_ builtinFunc = &builtinNEDecimalSig{}
 	_ builtinFunc = &builtinNEStringSig{}
+	_ builtinFunc = &builtinNETimeSig{}
+	_ builtinFunc = &builtinNEDurationSig{}
+	_ builtinFunc = &builtinNEJSONSig{}
 )
 
+// coalesceFunctionClass returns the first non-NULL value in the list,
+// or NULL if there are no non-NULL values.
+type coalesceFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *coalesceFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err = c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	retTp, notNull := types.NewFieldType(mysql.TypeNull), false
+	for _, arg := range args {
+		retTp = InferType4ControlFuncs(retTp, arg.GetType())
+		notNull = notNull || mysql.HasNotNullFlag(arg.GetType().Flag)
+	}
+	// The result is not NULL as long as any argument is not NULL.
+	retTp.Flag &^= mysql.NotNullFlag
+	if notNull {
+		retTp.Flag |= mysql.NotNullFlag
+	}
+	evalTps := retTp.EvalType()
+	argTps := make([]types.EvalType, 0, len(args))
+	for range args {
+		argTps = append(argTps, evalTps)
+	}
+	wrapArgsWithCast(ctx, args, argTps...)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, evalTps, argTps...)
+	retTp.Flag |= bf.tp.Flag
+	bf.tp = retTp
+	switch evalTps {
+	case types.ETInt:
+		sig = &builtinCoalesceIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CoalesceInt)
+	case types.ETReal:
+		sig = &builtinCoalesceRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CoalesceReal)
+	case types.ETDecimal:
+		sig = &builtinCoalesceDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CoalesceDecimal)
+	case types.ETString:
+		sig = &builtinCoalesceStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CoalesceString)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinCoalesceTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CoalesceTime)
+	case types.ETDuration:
+		sig = &builtinCoalesceDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CoalesceDuration)
+	case types.ETJson:
+		sig = &builtinCoalesceJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CoalesceJson)
+	}
+	return sig, nil
+}
+
+// builtinCoalesceIntSig is builtin function coalesce signature which return type int
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_coalesce
+type builtinCoalesceIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceIntSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalInt(b.ctx, row)
+		if err != nil || !isNull {
+			break
+		}
+	}
+	return res, isNull, err
+}
+
+// builtinCoalesceRealSig is builtin function coalesce signature which return type real
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_coalesce
+type builtinCoalesceRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceRealSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalReal(b.ctx, row)
+		if err != nil || !isNull {
+			break
+		}
+	}
+	return res, isNull, err
+}
+
+// builtinCoalesceDecimalSig is builtin function coalesce signature which return type decimal
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_coalesce
+type builtinCoalesceDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceDecimalSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceDecimalSig) evalDecimal(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalDecimal(b.ctx, row)
+		if err != nil || !isNull {
+			break
+		}
+	}
+	return res, isNull, err
+}
+
+// builtinCoalesceStringSig is builtin function coalesce signature which return type string
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_coalesce
+type builtinCoalesceStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceStringSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceStringSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalString(b.ctx, row)
+		if err != nil || !isNull {
+			break
+		}
+	}
+	return res, isNull, err
+}
+
+// builtinCoalesceTimeSig is builtin function coalesce signature which return type time
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_coalesce
+type builtinCoalesceTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceTimeSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalTime(b.ctx, row)
+		if err != nil || !isNull {
+			break
+		}
+	}
+	return res, isNull, err
+}
+
+// builtinCoalesceDurationSig is builtin function coalesce signature which return type duration
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_coalesce
+type builtinCoalesceDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceDurationSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceDurationSig) evalDuration(row chunk.Row) (res types.Duration, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalDuration(b.ctx, row)
+		if err != nil || !isNull {
+			break
+		}
+	}
+	return res, isNull, err
+}
+
+// builtinCoalesceJSONSig is builtin function coalesce signature which return type json
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_coalesce
+type builtinCoalesceJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceJSONSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceJSONSig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalJSON(b.ctx, row)
+		if err != nil || !isNull {
+			break
+		}
+	}
+	return res, isNull, err
+}
+
+// getCmpTp4MinMax gets the compare type for GREATEST and LEAST. The arguments
+// are compared as datetime only if all of them are temporal.
+func getCmpTp4MinMax(args []Expression) (argTp types.EvalType) {
+	allTemporal := true
+	lft := args[0].GetType()
+	cmpEvalType := lft.EvalType()
+	for i := range args {
+		rft := args[i].GetType()
+		if i > 0 {
+			cmpEvalType = getBaseCmpType(cmpEvalType, rft.EvalType(), lft, rft)
+		}
+		if !types.IsTypeTime(rft.Tp) {
+			allTemporal = false
+		}
+	}
+	if allTemporal {
+		cmpEvalType = types.ETDatetime
+	}
+	return cmpEvalType
+}
+
+// ResolveType4Between resolves the compare type for the BETWEEN expression,
+// the three arguments should be compared using the same type.
+func ResolveType4Between(args [3]Expression) types.EvalType {
+	lft := args[0].GetType()
+	cmpTp := lft.EvalType()
+	for i := 1; i < 3; i++ {
+		rft := args[i].GetType()
+		cmpTp = getBaseCmpType(cmpTp, rft.EvalType(), lft, rft)
+	}
+	if cmpTp == types.ETString {
+		for _, arg := range args {
+			if types.IsTypeTime(arg.GetType().Tp) {
+				return types.ETDatetime
+			}
+		}
+	}
+	return cmpTp
+}
+
+// newMinMaxBaseBuiltinFunc creates the baseBuiltinFunc for GREATEST and LEAST,
+// all the arguments are casted to the compare type.
+func newMinMaxBaseBuiltinFunc(ctx sessionctx.Context, args []Expression) (bf baseBuiltinFunc, tp types.EvalType) {
+	tp = getCmpTp4MinMax(args)
+	argTps := make([]types.EvalType, len(args))
+	allUnsigned := true
+	for i := range args {
+		argTps[i] = tp
+		allUnsigned = allUnsigned && mysql.HasUnsignedFlag(args[i].GetType().Flag)
+	}
+	wrapArgsWithCast(ctx, args, argTps...)
+	bf = newBaseBuiltinFuncWithTp(ctx, args, tp, argTps...)
+	if tp == types.ETInt && allUnsigned {
+		bf.tp.Flag |= mysql.UnsignedFlag
+	}
+	return bf, tp
+}
+
+type greatestFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *greatestFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err = c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, tp := newMinMaxBaseBuiltinFunc(ctx, args)
+	switch tp {
+	case types.ETInt:
+		sig = &builtinGreatestIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_GreatestInt)
+	case types.ETReal:
+		sig = &builtinGreatestRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_GreatestReal)
+	case types.ETDecimal:
+		sig = &builtinGreatestDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_GreatestDecimal)
+	case types.ETString:
+		sig = &builtinGreatestStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_GreatestString)
+	case types.ETDatetime:
+		sig = &builtinGreatestTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_GreatestTime)
+	}
+	return sig, nil
+}
+
+type leastFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *leastFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err = c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, tp := newMinMaxBaseBuiltinFunc(ctx, args)
+	switch tp {
+	case types.ETInt:
+		sig = &builtinLeastIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_LeastInt)
+	case types.ETReal:
+		sig = &builtinLeastRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_LeastReal)
+	case types.ETDecimal:
+		sig = &builtinLeastDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_LeastDecimal)
+	case types.ETString:
+		sig = &builtinLeastStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_LeastString)
+	case types.ETDatetime:
+		sig = &builtinLeastTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_LeastTime)
+	}
+	return sig, nil
+}
+
+type builtinGreatestIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGreatestIntSig) Clone() builtinFunc {
+	newSig := &builtinGreatestIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGreatestIntSig) compareInt(lhs, rhs int64) int {
+	if mysql.HasUnsignedFlag(b.tp.Flag) {
+		return types.CompareUint64(uint64(lhs), uint64(rhs))
+	}
+	return types.CompareInt64(lhs, rhs)
+}
+
+// evalInt evals a builtinGreatestIntSig.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_greatest
+func (b *builtinGreatestIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	for i, arg := range b.args {
+		v, isNull, err := arg.EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return 0, true, err
+		}
+		if i == 0 || b.compareInt(v, res) > 0 {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
+type builtinGreatestRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGreatestRealSig) Clone() builtinFunc {
+	newSig := &builtinGreatestRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinGreatestRealSig.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_greatest
+func (b *builtinGreatestRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	for i, arg := range b.args {
+		v, isNull, err := arg.EvalReal(b.ctx, row)
+		if isNull || err != nil {
+			return 0, true, err
+		}
+		if i == 0 || types.CompareFloat64(v, res) > 0 {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
+type builtinGreatestDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGreatestDecimalSig) Clone() builtinFunc {
+	newSig := &builtinGreatestDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals a builtinGreatestDecimalSig.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_greatest
+func (b *builtinGreatestDecimalSig) evalDecimal(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	for i, arg := range b.args {
+		v, isNull, err := arg.EvalDecimal(b.ctx, row)
+		if isNull || err != nil {
+			return nil, true, err
+		}
+		if i == 0 || v.Compare(res) > 0 {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
+type builtinGreatestStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGreatestStringSig) Clone() builtinFunc {
+	newSig := &builtinGreatestStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinGreatestStringSig.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_greatest
+func (b *builtinGreatestStringSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	for i, arg := range b.args {
+		v, isNull, err := arg.EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return "", true, err
+		}
+		if i == 0 || types.CompareString(v, res) > 0 {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
+type builtinGreatestTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGreatestTimeSig) Clone() builtinFunc {
+	newSig := &builtinGreatestTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals a builtinGreatestTimeSig.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_greatest
+func (b *builtinGreatestTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	for i, arg := range b.args {
+		v, isNull, err := arg.EvalTime(b.ctx, row)
+		if isNull || err != nil {
+			return res, true, err
+		}
+		if i == 0 || v.Compare(res) > 0 {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
+type builtinLeastIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeastIntSig) Clone() builtinFunc {
+	newSig := &builtinLeastIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLeastIntSig) compareInt(lhs, rhs int64) int {
+	if mysql.HasUnsignedFlag(b.tp.Flag) {
+		return types.CompareUint64(uint64(lhs), uint64(rhs))
+	}
+	return types.CompareInt64(lhs, rhs)
+}
+
+// evalInt evals a builtinLeastIntSig.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_least
+func (b *builtinLeastIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	for i, arg := range b.args {
+		v, isNull, err := arg.EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return 0, true, err
+		}
+		if i == 0 || b.compareInt(v, res) < 0 {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
+type builtinLeastRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeastRealSig) Clone() builtinFunc {
+	newSig := &builtinLeastRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinLeastRealSig.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_least
+func (b *builtinLeastRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	for i, arg := range b.args {
+		v, isNull, err := arg.EvalReal(b.ctx, row)
+		if isNull || err != nil {
+			return 0, true, err
+		}
+		if i == 0 || types.CompareFloat64(v, res) < 0 {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
+type builtinLeastDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeastDecimalSig) Clone() builtinFunc {
+	newSig := &builtinLeastDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals a builtinLeastDecimalSig.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_least
+func (b *builtinLeastDecimalSig) evalDecimal(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	for i, arg := range b.args {
+		v, isNull, err := arg.EvalDecimal(b.ctx, row)
+		if isNull || err != nil {
+			return nil, true, err
+		}
+		if i == 0 || v.Compare(res) < 0 {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
+type builtinLeastStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeastStringSig) Clone() builtinFunc {
+	newSig := &builtinLeastStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinLeastStringSig.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_least
+func (b *builtinLeastStringSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	for i, arg := range b.args {
+		v, isNull, err := arg.EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return "", true, err
+		}
+		if i == 0 || types.CompareString(v, res) < 0 {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
+type builtinLeastTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeastTimeSig) Clone() builtinFunc {
+	newSig := &builtinLeastTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals a builtinLeastTimeSig.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_least
+func (b *builtinLeastTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	for i, arg := range b.args {
+		v, isNull, err := arg.EvalTime(b.ctx, row)
+		if isNull || err != nil {
+			return res, true, err
+		}
+		if i == 0 || v.Compare(res) < 0 {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
 type compareFunctionClass struct {
 	baseFunctionClass
 
@@ -72,6 +705,9 @@ func getBaseCmpType(lhs, rhs types.EvalType, lft, rft *types.FieldType) types.Ev
 		return types.ETString
 	} else if (lhs == types.ETInt || lft.Hybrid()) && (rhs == types.ETInt || rft.Hybrid()) {
 		return types.ETInt
+	} else if (lhs == types.ETInt || lhs == types.ETDecimal || lft.Hybrid()) &&
+		(rhs == types.ETInt || rhs == types.ETDecimal || rft.Hybrid()) {
+		return types.ETDecimal
 	}
 	return types.ETReal
 }
@@ -82,9 +718,61 @@ func GetAccurateCmpType(lhs, rhs Expression) types.EvalType {
 	lhsFieldType, rhsFieldType := lhs.GetType(), rhs.GetType()
 	lhsEvalType, rhsEvalType := lhsFieldType.EvalType(), rhsFieldType.EvalType()
 	cmpType := getBaseCmpType(lhsEvalType, rhsEvalType, lhsFieldType, rhsFieldType)
+	if lhsEvalType == types.ETJson || rhsEvalType == types.ETJson {
+		// json <cmp> any
+		// compare as json
+		cmpType = types.ETJson
+	} else if cmpType == types.ETString && (types.IsTypeTime(lhsFieldType.Tp) || types.IsTypeTime(rhsFieldType.Tp)) {
+		// date[time] <cmp> date[time]
+		// string <cmp> date[time]
+		// compare as time
+		if lhsFieldType.Tp == rhsFieldType.Tp {
+			cmpType = lhsFieldType.EvalType()
+		} else {
+			cmpType = types.ETDatetime
+		}
+	} else if lhsFieldType.Tp == mysql.TypeDuration && rhsFieldType.Tp == mysql.TypeDuration {
+		// duration <cmp> duration
+		// compare as duration
+		cmpType = types.ETDuration
+	} else if cmpType == types.ETReal || cmpType == types.ETString {
+		_, isLHSConst := lhs.(*Constant)
+		_, isRHSConst := rhs.(*Constant)
+		if isTemporalColumn(lhs) && isRHSConst || isTemporalColumn(rhs) && isLHSConst {
+			/*
+				<temporal column> <cmp> <non-temporal constant>
+				or
+				<non-temporal constant> <cmp> <temporal column>
+
+				Convert the constant to temporal type.
+			*/
+			col, isLHSColumn := lhs.(*Column)
+			if !isLHSColumn {
+				col = rhs.(*Column)
+			}
+			if col.GetType().Tp == mysql.TypeDuration {
+				cmpType = types.ETDuration
+			} else {
+				cmpType = types.ETDatetime
+			}
+		}
+	}
 	return cmpType
 }
 
+// isTemporalColumn checks if a expression is a temporal column,
+// temporal column indicates time column or duration column.
+func isTemporalColumn(expr Expression) bool {
+	ft := expr.GetType()
+	if _, isCol := expr.(*Column); !isCol {
+		return false
+	}
+	if !types.IsTypeTime(ft.Tp) && ft.Tp != mysql.TypeDuration {
+		return false
+	}
+	return true
+}
+
 // GetCmpFunction get the compare function according to two arguments.
 func GetCmpFunction(lhs, rhs Expression) CompareFunc {
 	switch GetAccurateCmpType(lhs, rhs) {
@@ -92,8 +780,16 @@ func GetCmpFunction(lhs, rhs Expression) CompareFunc {
 		return CompareInt
 	case types.ETReal:
 		return CompareReal
+	case types.ETDecimal:
+		return CompareDecimal
 	case types.ETString:
 		return CompareString
+	case types.ETDatetime, types.ETTimestamp:
+		return CompareTime
+	case types.ETDuration:
+		return CompareDuration
+	case types.ETJson:
+		return CompareJSON
 	}
 	return nil
 }
@@ -110,6 +806,9 @@ func (c *compareFunctionClass) getFunction(ctx sessionctx.Context, rawArgs []Exp
 
 // generateCmpSigs generates compare function signatures.
 func (c *compareFunctionClass) generateCmpSigs(ctx sessionctx.Context, args []Expression, tp types.EvalType) (sig builtinFunc, err error) {
+	if tp == types.ETJson {
+		args[0], args[1] = wrapWithCastAsJSON(ctx, args[0], false), wrapWithCastAsJSON(ctx, args[1], false)
+	}
 	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, tp, tp)
 	bf.tp.Flen = 1
 	switch tp {
@@ -155,6 +854,27 @@ func (c *compareFunctionClass) generateCmpSigs(ctx sessionctx.Context, args []Ex
 			sig = &builtinNERealSig{bf}
 			sig.setPbCode(tipb.ScalarFuncSig_NEReal)
 		}
+	case types.ETDecimal:
+		switch c.op {
+		case opcode.LT:
+			sig = &builtinLTDecimalSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LTDecimal)
+		case opcode.LE:
+			sig = &builtinLEDecimalSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LEDecimal)
+		case opcode.GT:
+			sig = &builtinGTDecimalSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GTDecimal)
+		case opcode.GE:
+			sig = &builtinGEDecimalSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GEDecimal)
+		case opcode.EQ:
+			sig = &builtinEQDecimalSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_EQDecimal)
+		case opcode.NE:
+			sig = &builtinNEDecimalSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NEDecimal)
+		}
 	case types.ETString:
 		switch c.op {
 		case opcode.LT:
@@ -176,50 +896,169 @@ func (c *compareFunctionClass) generateCmpSigs(ctx sessionctx.Context, args []Ex
 			sig = &builtinNEStringSig{bf}
 			sig.setPbCode(tipb.ScalarFuncSig_NEString)
 		}
+	case types.ETDatetime, types.ETTimestamp:
+		switch c.op {
+		case opcode.LT:
+			sig = &builtinLTTimeSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LTTime)
+		case opcode.LE:
+			sig = &builtinLETimeSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LETime)
+		case opcode.GT:
+			sig = &builtinGTTimeSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GTTime)
+		case opcode.GE:
+			sig = &builtinGETimeSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GETime)
+		case opcode.EQ:
+			sig = &builtinEQTimeSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_EQTime)
+		case opcode.NE:
+			sig = &builtinNETimeSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NETime)
+		}
+	case types.ETDuration:
+		switch c.op {
+		case opcode.LT:
+			sig = &builtinLTDurationSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LTDuration)
+		case opcode.LE:
+			sig = &builtinLEDurationSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LEDuration)
+		case opcode.GT:
+			sig = &builtinGTDurationSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GTDuration)
+		case opcode.GE:
+			sig = &builtinGEDurationSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GEDuration)
+		case opcode.EQ:
+			sig = &builtinEQDurationSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_EQDuration)
+		case opcode.NE:
+			sig = &builtinNEDurationSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NEDuration)
+		}
+	case types.ETJson:
+		switch c.op {
+		case opcode.LT:
+			sig = &builtinLTJSONSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LTJson)
+		case opcode.LE:
+			sig = &builtinLEJSONSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LEJson)
+		case opcode.GT:
+			sig = &builtinGTJSONSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GTJson)
+		case opcode.GE:
+			sig = &builtinGEJSONSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GEJson)
+		case opcode.EQ:
+			sig = &builtinEQJSONSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_EQJson)
+		case opcode.NE:
+			sig = &builtinNEJSONSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NEJson)
+		}
 	}
 	return
 }
 
-type builtinLTIntSig struct {
+type builtinLTIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLTIntSig) Clone() builtinFunc {
+	newSig := &builtinLTIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLTIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLT(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLTRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLTRealSig) Clone() builtinFunc {
+	newSig := &builtinLTRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLTRealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLT(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLTDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLTDecimalSig) Clone() builtinFunc {
+	newSig := &builtinLTDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLTDecimalSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLT(CompareDecimal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLTStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLTStringSig) Clone() builtinFunc {
+	newSig := &builtinLTStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLTStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLT(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLTTimeSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinLTIntSig) Clone() builtinFunc {
-	newSig := &builtinLTIntSig{}
+func (b *builtinLTTimeSig) Clone() builtinFunc {
+	newSig := &builtinLTTimeSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinLTIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfLT(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinLTTimeSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLT(CompareTime(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinLTRealSig struct {
+type builtinLTDurationSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinLTRealSig) Clone() builtinFunc {
-	newSig := &builtinLTRealSig{}
+func (b *builtinLTDurationSig) Clone() builtinFunc {
+	newSig := &builtinLTDurationSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinLTRealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfLT(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinLTDurationSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLT(CompareDuration(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinLTStringSig struct {
+type builtinLTJSONSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinLTStringSig) Clone() builtinFunc {
-	newSig := &builtinLTStringSig{}
+func (b *builtinLTJSONSig) Clone() builtinFunc {
+	newSig := &builtinLTJSONSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinLTStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfLT(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinLTJSONSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLT(CompareJSON(b.ctx, b.args[0], b.args[1], row, row))
 }
 
 type builtinLEIntSig struct {
@@ -250,6 +1089,20 @@ func (b *builtinLERealSig) evalInt(row chunk.Row) (val int64, isNull bool, err e
 	return resOfLE(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
 }
 
+type builtinLEDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLEDecimalSig) Clone() builtinFunc {
+	newSig := &builtinLEDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLEDecimalSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLE(CompareDecimal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
 type builtinLEStringSig struct {
 	baseBuiltinFunc
 }
@@ -264,6 +1117,48 @@ func (b *builtinLEStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err
 	return resOfLE(CompareString(b.ctx, b.args[0], b.args[1], row, row))
 }
 
+type builtinLETimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLETimeSig) Clone() builtinFunc {
+	newSig := &builtinLETimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLETimeSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLE(CompareTime(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLEDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLEDurationSig) Clone() builtinFunc {
+	newSig := &builtinLEDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLEDurationSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLE(CompareDuration(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLEJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLEJSONSig) Clone() builtinFunc {
+	newSig := &builtinLEJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLEJSONSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLE(CompareJSON(b.ctx, b.args[0], b.args[1], row, row))
+}
+
 type builtinGTIntSig struct {
 	baseBuiltinFunc
 }
@@ -292,6 +1187,20 @@ func (b *builtinGTRealSig) evalInt(row chunk.Row) (val int64, isNull bool, err e
 	return resOfGT(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
 }
 
+type builtinGTDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGTDecimalSig) Clone() builtinFunc {
+	newSig := &builtinGTDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGTDecimalSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGT(CompareDecimal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
 type builtinGTStringSig struct {
 	baseBuiltinFunc
 }
@@ -306,6 +1215,48 @@ func (b *builtinGTStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err
 	return resOfGT(CompareString(b.ctx, b.args[0], b.args[1], row, row))
 }
 
+type builtinGTTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGTTimeSig) Clone() builtinFunc {
+	newSig := &builtinGTTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGTTimeSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGT(CompareTime(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGTDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGTDurationSig) Clone() builtinFunc {
+	newSig := &builtinGTDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGTDurationSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGT(CompareDuration(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGTJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGTJSONSig) Clone() builtinFunc {
+	newSig := &builtinGTJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGTJSONSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGT(CompareJSON(b.ctx, b.args[0], b.args[1], row, row))
+}
+
 type builtinGEIntSig struct {
 	baseBuiltinFunc
 }
@@ -334,6 +1285,20 @@ func (b *builtinGERealSig) evalInt(row chunk.Row) (val int64, isNull bool, err e
 	return resOfGE(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
 }
 
+type builtinGEDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGEDecimalSig) Clone() builtinFunc {
+	newSig := &builtinGEDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGEDecimalSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGE(CompareDecimal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
 type builtinGEStringSig struct {
 	baseBuiltinFunc
 }
@@ -348,6 +1313,48 @@ func (b *builtinGEStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err
 	return resOfGE(CompareString(b.ctx, b.args[0], b.args[1], row, row))
 }
 
+type builtinGETimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGETimeSig) Clone() builtinFunc {
+	newSig := &builtinGETimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGETimeSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGE(CompareTime(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGEDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGEDurationSig) Clone() builtinFunc {
+	newSig := &builtinGEDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGEDurationSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGE(CompareDuration(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGEJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGEJSONSig) Clone() builtinFunc {
+	newSig := &builtinGEJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGEJSONSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGE(CompareJSON(b.ctx, b.args[0], b.args[1], row, row))
+}
+
 type builtinEQIntSig struct {
 	baseBuiltinFunc
 }
@@ -376,6 +1383,20 @@ func (b *builtinEQRealSig) evalInt(row chunk.Row) (val int64, isNull bool, err e
 	return resOfEQ(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
 }
 
+type builtinEQDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinEQDecimalSig) Clone() builtinFunc {
+	newSig := &builtinEQDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinEQDecimalSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfEQ(CompareDecimal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
 type builtinEQStringSig struct {
 	baseBuiltinFunc
 }
@@ -390,6 +1411,48 @@ func (b *builtinEQStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err
 	return resOfEQ(CompareString(b.ctx, b.args[0], b.args[1], row, row))
 }
 
+type builtinEQTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinEQTimeSig) Clone() builtinFunc {
+	newSig := &builtinEQTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinEQTimeSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfEQ(CompareTime(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinEQDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinEQDurationSig) Clone() builtinFunc {
+	newSig := &builtinEQDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinEQDurationSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfEQ(CompareDuration(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinEQJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinEQJSONSig) Clone() builtinFunc {
+	newSig := &builtinEQJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinEQJSONSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfEQ(CompareJSON(b.ctx, b.args[0], b.args[1], row, row))
+}
+
 type builtinNEIntSig struct {
 	baseBuiltinFunc
 }
@@ -418,6 +1481,20 @@ func (b *builtinNERealSig) evalInt(row chunk.Row) (val int64, isNull bool, err e
 	return resOfNE(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
 }
 
+type builtinNEDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinNEDecimalSig) Clone() builtinFunc {
+	newSig := &builtinNEDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinNEDecimalSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNE(CompareDecimal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
 type builtinNEStringSig struct {
 	baseBuiltinFunc
 }
@@ -432,6 +1509,48 @@ func (b *builtinNEStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err
 	return resOfNE(CompareString(b.ctx, b.args[0], b.args[1], row, row))
 }
 
+type builtinNETimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinNETimeSig) Clone() builtinFunc {
+	newSig := &builtinNETimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinNETimeSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNE(CompareTime(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinNEDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinNEDurationSig) Clone() builtinFunc {
+	newSig := &builtinNEDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinNEDurationSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNE(CompareDuration(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinNEJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinNEJSONSig) Clone() builtinFunc {
+	newSig := &builtinNEJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinNEJSONSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNE(CompareJSON(b.ctx, b.args[0], b.args[1], row, row))
+}
+
 func resOfLT(val int64, isNull bool, err error) (int64, bool, error) {
 	if isNull || err != nil {
 		return 0, isNull, err
@@ -596,3 +1715,75 @@ func CompareReal(sctx sessionctx.Context, lhsArg, rhsArg Expression, lhsRow, rhs
 	}
 	return int64(types.CompareFloat64(arg0, arg1)), false, nil
 }
+
+// CompareDecimal compares two decimals.
+func CompareDecimal(sctx sessionctx.Context, lhsArg, rhsArg Expression, lhsRow, rhsRow chunk.Row) (int64, bool, error) {
+	arg0, isNull0, err := lhsArg.EvalDecimal(sctx, lhsRow)
+	if err != nil {
+		return 0, true, err
+	}
+
+	arg1, isNull1, err := rhsArg.EvalDecimal(sctx, rhsRow)
+	if err != nil {
+		return 0, true, err
+	}
+
+	if isNull0 || isNull1 {
+		return compareNull(isNull0, isNull1), true, nil
+	}
+	return int64(arg0.Compare(arg1)), false, nil
+}
+
+// CompareTime compares two datetime or timestamps.
+func CompareTime(sctx sessionctx.Context, lhsArg, rhsArg Expression, lhsRow, rhsRow chunk.Row) (int64, bool, error) {
+	arg0, isNull0, err := lhsArg.EvalTime(sctx, lhsRow)
+	if err != nil {
+		return 0, true, err
+	}
+
+	arg1, isNull1, err := rhsArg.EvalTime(sctx, rhsRow)
+	if err != nil {
+		return 0, true, err
+	}
+
+	if isNull0 || isNull1 {
+		return compareNull(isNull0, isNull1), true, nil
+	}
+	return int64(arg0.Compare(arg1)), false, nil
+}
+
+// CompareJSON compares two JSONs.
+func CompareJSON(sctx sessionctx.Context, lhsArg, rhsArg Expression, lhsRow, rhsRow chunk.Row) (int64, bool, error) {
+	arg0, isNull0, err := lhsArg.EvalJSON(sctx, lhsRow)
+	if err != nil {
+		return 0, true, err
+	}
+
+	arg1, isNull1, err := rhsArg.EvalJSON(sctx, rhsRow)
+	if err != nil {
+		return 0, true, err
+	}
+
+	if isNull0 || isNull1 {
+		return compareNull(isNull0, isNull1), true, nil
+	}
+	return int64(json.CompareBinary(arg0, arg1)), false, nil
+}
+
+// CompareDuration compares two durations.
+func CompareDuration(sctx sessionctx.Context, lhsArg, rhsArg Expression, lhsRow, rhsRow chunk.Row) (int64, bool, error) {
+	arg0, isNull0, err := lhsArg.EvalDuration(sctx, lhsRow)
+	if err != nil {
+		return 0, true, err
+	}
+
+	arg1, isNull1, err := rhsArg.EvalDuration(sctx, rhsRow)
+	if err != nil {
+		return 0, true, err
+	}
+
+	if isNull0 || isNull1 {
+		return compareNull(isNull0, isNull1), true, nil
+	}
+	return int64(arg0.Compare(arg1)), false, nil
+}
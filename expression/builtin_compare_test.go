@@ -14,10 +14,14 @@
 package expression
 
 import (
+	"math"
+
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/testutil"
 )
 
 func (s *testEvaluatorSuite) TestCompare(c *C) {
@@ -48,3 +52,75 @@ func (s *testEvaluatorSuite) TestCompare(c *C) {
 		c.Assert(res, Equals, t.expected)
 	}
 }
+
+func (s *testEvaluatorSuite) TestCoalesce(c *C) {
+	cases := []struct {
+		args     []interface{}
+		expected interface{}
+		isNil    bool
+	}{
+		{[]interface{}{nil}, nil, true},
+		{[]interface{}{nil, nil}, nil, true},
+		{[]interface{}{nil, nil, nil}, nil, true},
+		{[]interface{}{nil, 1}, int64(1), false},
+		{[]interface{}{nil, 1.1}, float64(1.1), false},
+		{[]interface{}{1, 1.1}, float64(1), false},
+		{[]interface{}{nil, types.NewDecFromFloatForTest(123.456)}, types.NewDecFromFloatForTest(123.456), false},
+		{[]interface{}{1, types.NewDecFromFloatForTest(123.456)}, types.NewDecFromInt(1), false},
+		{[]interface{}{nil, "abc"}, "abc", false},
+		{[]interface{}{"abc", 1}, "abc", false},
+		{[]interface{}{1, "abc"}, "1", false},
+	}
+
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, ast.Coalesce, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		if t.isNil {
+			c.Assert(d.Kind(), Equals, types.KindNull)
+		} else {
+			c.Assert(d, testutil.DatumEquals, types.NewDatum(t.expected))
+		}
+	}
+
+	_, err := funcs[ast.Coalesce].getFunction(s.ctx, []Expression{Null, Null})
+	c.Assert(err, IsNil)
+}
+
+func (s *testEvaluatorSuite) TestGreatestLeast(c *C) {
+	cases := []struct {
+		args     []interface{}
+		greatest interface{}
+		least    interface{}
+	}{
+		{[]interface{}{1, 2, 3}, int64(3), int64(1)},
+		{[]interface{}{-1, 3, 2}, int64(3), int64(-1)},
+		{[]interface{}{uint64(1), uint64(math.MaxUint64)}, uint64(math.MaxUint64), uint64(1)},
+		{[]interface{}{1.5, 2, -3}, float64(2), float64(-3)},
+		{[]interface{}{types.NewDecFromStringForTest("1.5"), 2, 3}, types.NewDecFromStringForTest("3"), types.NewDecFromStringForTest("1.5")},
+		{[]interface{}{"abc", "abd", "ab"}, "abd", "ab"},
+		{[]interface{}{"11", 2}, float64(11), float64(2)},
+		{[]interface{}{1, nil, 3}, nil, nil},
+		{[]interface{}{nil, nil}, nil, nil},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, ast.Greatest, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.greatest), Commentf("greatest%v", t.args))
+
+		f, err = newFunctionForTest(s.ctx, ast.Least, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err = f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.least), Commentf("least%v", t.args))
+	}
+
+	_, err := funcs[ast.Greatest].getFunction(s.ctx, []Expression{One})
+	c.Assert(err, NotNil)
+	_, err = funcs[ast.Least].getFunction(s.ctx, []Expression{One})
+	c.Assert(err, NotNil)
+}
@@ -211,6 +211,309 @@ func (b *builtinLTIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) e
 	return nil
 }
 
+func (b *builtinCoalesceIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCoalesceIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.ResizeInt64(n, true)
+	rs := result.Int64s()
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	for j := 0; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalInt(b.ctx, input, buf); err != nil {
+			return err
+		}
+		args := buf.Int64s()
+		for i := 0; i < n; i++ {
+			if result.IsNull(i) && !buf.IsNull(i) {
+				rs[i] = args[i]
+				result.SetNull(i, false)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinCoalesceRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCoalesceRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.ResizeFloat64(n, true)
+	rs := result.Float64s()
+	buf, err := b.bufAllocator.get(types.ETReal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	for j := 0; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalReal(b.ctx, input, buf); err != nil {
+			return err
+		}
+		args := buf.Float64s()
+		for i := 0; i < n; i++ {
+			if result.IsNull(i) && !buf.IsNull(i) {
+				rs[i] = args[i]
+				result.SetNull(i, false)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinCoalesceStringSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCoalesceStringSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs := make([]*chunk.Column, len(b.args))
+	for j := 0; j < len(b.args); j++ {
+		buf, err := b.bufAllocator.get(types.ETString, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(buf)
+		if err := b.args[j].VecEvalString(b.ctx, input, buf); err != nil {
+			return err
+		}
+		bufs[j] = buf
+	}
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		isNull := true
+		for _, buf := range bufs {
+			if !buf.IsNull(i) {
+				result.AppendString(buf.GetString(i))
+				isNull = false
+				break
+			}
+		}
+		if isNull {
+			result.AppendNull()
+		}
+	}
+	return nil
+}
+
+func (b *builtinGreatestIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinGreatestIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	rs := result.Int64s()
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalInt(b.ctx, input, buf); err != nil {
+			return err
+		}
+		result.MergeNulls(buf)
+		args := buf.Int64s()
+		for i := 0; i < n; i++ {
+			if result.IsNull(i) {
+				continue
+			}
+			if b.compareInt(args[i], rs[i]) > 0 {
+				rs[i] = args[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinGreatestRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinGreatestRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	if err := b.args[0].VecEvalReal(b.ctx, input, result); err != nil {
+		return err
+	}
+	buf, err := b.bufAllocator.get(types.ETReal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	rs := result.Float64s()
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalReal(b.ctx, input, buf); err != nil {
+			return err
+		}
+		result.MergeNulls(buf)
+		args := buf.Float64s()
+		for i := 0; i < n; i++ {
+			if result.IsNull(i) {
+				continue
+			}
+			if args[i] > rs[i] {
+				rs[i] = args[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinGreatestStringSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinGreatestStringSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs := make([]*chunk.Column, len(b.args))
+	for j := 0; j < len(b.args); j++ {
+		buf, err := b.bufAllocator.get(types.ETString, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(buf)
+		if err := b.args[j].VecEvalString(b.ctx, input, buf); err != nil {
+			return err
+		}
+		bufs[j] = buf
+	}
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		isNull := false
+		var res string
+		for j, buf := range bufs {
+			if buf.IsNull(i) {
+				isNull = true
+				break
+			}
+			if v := buf.GetString(i); j == 0 || types.CompareString(v, res) > 0 {
+				res = v
+			}
+		}
+		if isNull {
+			result.AppendNull()
+		} else {
+			result.AppendString(res)
+		}
+	}
+	return nil
+}
+
+func (b *builtinLeastIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLeastIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	rs := result.Int64s()
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalInt(b.ctx, input, buf); err != nil {
+			return err
+		}
+		result.MergeNulls(buf)
+		args := buf.Int64s()
+		for i := 0; i < n; i++ {
+			if result.IsNull(i) {
+				continue
+			}
+			if b.compareInt(args[i], rs[i]) < 0 {
+				rs[i] = args[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinLeastRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLeastRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	if err := b.args[0].VecEvalReal(b.ctx, input, result); err != nil {
+		return err
+	}
+	buf, err := b.bufAllocator.get(types.ETReal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	rs := result.Float64s()
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalReal(b.ctx, input, buf); err != nil {
+			return err
+		}
+		result.MergeNulls(buf)
+		args := buf.Float64s()
+		for i := 0; i < n; i++ {
+			if result.IsNull(i) {
+				continue
+			}
+			if args[i] < rs[i] {
+				rs[i] = args[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinLeastStringSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLeastStringSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs := make([]*chunk.Column, len(b.args))
+	for j := 0; j < len(b.args); j++ {
+		buf, err := b.bufAllocator.get(types.ETString, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(buf)
+		if err := b.args[j].VecEvalString(b.ctx, input, buf); err != nil {
+			return err
+		}
+		bufs[j] = buf
+	}
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		isNull := false
+		var res string
+		for j, buf := range bufs {
+			if buf.IsNull(i) {
+				isNull = true
+				break
+			}
+			if v := buf.GetString(i); j == 0 || types.CompareString(v, res) < 0 {
+				res = v
+			}
+		}
+		if isNull {
+			result.AppendNull()
+		} else {
+			result.AppendString(res)
+		}
+	}
+	return nil
+}
+
 func vecResOfLT(res []int64) {
 	n := len(res)
 	for i := 0; i < n; i++ {
@@ -19,25 +19,42 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tipb/go-tipb"
 )
 
 var (
+	_ functionClass = &caseWhenFunctionClass{}
 	_ functionClass = &ifFunctionClass{}
 	_ functionClass = &ifNullFunctionClass{}
 )
 
 var (
+	_ builtinFunc = &builtinCaseWhenIntSig{}
+	_ builtinFunc = &builtinCaseWhenRealSig{}
+	_ builtinFunc = &builtinCaseWhenDecimalSig{}
+	_ builtinFunc = &builtinCaseWhenStringSig{}
+	_ builtinFunc = &builtinCaseWhenTimeSig{}
+	_ builtinFunc = &builtinCaseWhenDurationSig{}
+	_ builtinFunc = &builtinCaseWhenJSONSig{}
 	_ builtinFunc = &builtinIfNullIntSig{}
 	_ builtinFunc = &builtinIfNullRealSig{}
+	_ builtinFunc = &builtinIfNullDecimalSig{}
 	_ builtinFunc = &builtinIfNullStringSig{}
+	_ builtinFunc = &builtinIfNullTimeSig{}
+	_ builtinFunc = &builtinIfNullDurationSig{}
+	_ builtinFunc = &builtinIfNullJSONSig{}
 	_ builtinFunc = &builtinIfIntSig{}
 	_ builtinFunc = &builtinIfRealSig{}
+	_ builtinFunc = &builtinIfDecimalSig{}
 	_ builtinFunc = &builtinIfStringSig{}
+	_ builtinFunc = &builtinIfTimeSig{}
+	_ builtinFunc = &builtinIfDurationSig{}
+	_ builtinFunc = &builtinIfJSONSig{}
 )
 
-// InferType4ControlFuncs infer result type for builtin IF, IFNULL, NULLIF, LEAD and LAG.
+// InferType4ControlFuncs infer result type for builtin CASE WHEN, IF, IFNULL, NULLIF, COALESCE, LEAD and LAG.
 func InferType4ControlFuncs(lhs, rhs *types.FieldType) *types.FieldType {
 	resultFieldType := &types.FieldType{}
 	if lhs.Tp == mysql.TypeNull {
@@ -77,7 +94,7 @@ func InferType4ControlFuncs(lhs, rhs *types.FieldType) *types.FieldType {
 		} else {
 			resultFieldType.Charset, resultFieldType.Collate, resultFieldType.Flag = mysql.DefaultCharset, mysql.DefaultCollationName, 0
 		}
-		if evalType == types.ETInt {
+		if evalType == types.ETInt || evalType == types.ETDecimal {
 			lhsUnsignedFlag, rhsUnsignedFlag := mysql.HasUnsignedFlag(lhs.Flag), mysql.HasUnsignedFlag(rhs.Flag)
 			lhsFlagLen, rhsFlagLen := 0, 0
 			if !lhsUnsignedFlag {
@@ -111,6 +128,311 @@ func InferType4ControlFuncs(lhs, rhs *types.FieldType) *types.FieldType {
 	return resultFieldType
 }
 
+type caseWhenFunctionClass struct {
+	baseFunctionClass
+}
+
+// getFunction see https://dev.mysql.com/doc/refman/5.7/en/control-flow-functions.html#operator_case
+// The arguments are organized as [cond1, result1, cond2, result2, ..., else],
+// the else clause is optional. A simple CASE statement is rewritten to this
+// form in the planner, every condition is then an EQ function.
+func (c *caseWhenFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err = c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	l := len(args)
+	hasElse := l%2 == 1
+	// Infer the result type from each 'THEN' clause and the 'ELSE' clause.
+	retTp, notNull := types.NewFieldType(mysql.TypeNull), hasElse
+	for i := 1; i < l; i += 2 {
+		retTp = InferType4ControlFuncs(retTp, args[i].GetType())
+		notNull = notNull && mysql.HasNotNullFlag(args[i].GetType().Flag)
+	}
+	if hasElse {
+		retTp = InferType4ControlFuncs(retTp, args[l-1].GetType())
+		notNull = notNull && mysql.HasNotNullFlag(args[l-1].GetType().Flag)
+	}
+	// The result is NULL when no condition is matched and there is no 'ELSE' clause.
+	retTp.Flag &^= mysql.NotNullFlag
+	if notNull {
+		retTp.Flag |= mysql.NotNullFlag
+	}
+	evalTps := retTp.EvalType()
+	argTps := make([]types.EvalType, 0, l)
+	for i := 0; i < l-1; i += 2 {
+		argTps = append(argTps, types.ETInt, evalTps)
+	}
+	if hasElse {
+		argTps = append(argTps, evalTps)
+	}
+	wrapArgsWithCast(ctx, args, argTps...)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, evalTps, argTps...)
+	retTp.Flag |= bf.tp.Flag
+	bf.tp = retTp
+	switch evalTps {
+	case types.ETInt:
+		sig = &builtinCaseWhenIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CaseWhenInt)
+	case types.ETReal:
+		sig = &builtinCaseWhenRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CaseWhenReal)
+	case types.ETDecimal:
+		sig = &builtinCaseWhenDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CaseWhenDecimal)
+	case types.ETString:
+		sig = &builtinCaseWhenStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CaseWhenString)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinCaseWhenTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CaseWhenTime)
+	case types.ETDuration:
+		sig = &builtinCaseWhenDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CaseWhenDuration)
+	case types.ETJson:
+		sig = &builtinCaseWhenJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CaseWhenJson)
+	}
+	return sig, nil
+}
+
+type builtinCaseWhenIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenIntSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinCaseWhenIntSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/control-flow-functions.html#operator_case
+func (b *builtinCaseWhenIntSig) evalInt(row chunk.Row) (ret int64, isNull bool, err error) {
+	var condition int64
+	l := len(b.args)
+	for i := 0; i < l-1; i += 2 {
+		condition, isNull, err = b.args[i].EvalInt(b.ctx, row)
+		if err != nil {
+			return 0, true, err
+		}
+		if isNull || condition == 0 {
+			continue
+		}
+		return b.args[i+1].EvalInt(b.ctx, row)
+	}
+	// When clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// Else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	if l%2 == 1 {
+		return b.args[l-1].EvalInt(b.ctx, row)
+	}
+	return 0, true, nil
+}
+
+type builtinCaseWhenRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenRealSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinCaseWhenRealSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/control-flow-functions.html#operator_case
+func (b *builtinCaseWhenRealSig) evalReal(row chunk.Row) (ret float64, isNull bool, err error) {
+	var condition int64
+	l := len(b.args)
+	for i := 0; i < l-1; i += 2 {
+		condition, isNull, err = b.args[i].EvalInt(b.ctx, row)
+		if err != nil {
+			return 0, true, err
+		}
+		if isNull || condition == 0 {
+			continue
+		}
+		return b.args[i+1].EvalReal(b.ctx, row)
+	}
+	// When clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// Else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	if l%2 == 1 {
+		return b.args[l-1].EvalReal(b.ctx, row)
+	}
+	return 0, true, nil
+}
+
+type builtinCaseWhenDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenDecimalSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals a builtinCaseWhenDecimalSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/control-flow-functions.html#operator_case
+func (b *builtinCaseWhenDecimalSig) evalDecimal(row chunk.Row) (ret *types.MyDecimal, isNull bool, err error) {
+	var condition int64
+	l := len(b.args)
+	for i := 0; i < l-1; i += 2 {
+		condition, isNull, err = b.args[i].EvalInt(b.ctx, row)
+		if err != nil {
+			return nil, true, err
+		}
+		if isNull || condition == 0 {
+			continue
+		}
+		return b.args[i+1].EvalDecimal(b.ctx, row)
+	}
+	// When clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// Else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	if l%2 == 1 {
+		return b.args[l-1].EvalDecimal(b.ctx, row)
+	}
+	return nil, true, nil
+}
+
+type builtinCaseWhenStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenStringSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinCaseWhenStringSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/control-flow-functions.html#operator_case
+func (b *builtinCaseWhenStringSig) evalString(row chunk.Row) (ret string, isNull bool, err error) {
+	var condition int64
+	l := len(b.args)
+	for i := 0; i < l-1; i += 2 {
+		condition, isNull, err = b.args[i].EvalInt(b.ctx, row)
+		if err != nil {
+			return "", true, err
+		}
+		if isNull || condition == 0 {
+			continue
+		}
+		return b.args[i+1].EvalString(b.ctx, row)
+	}
+	// When clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// Else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	if l%2 == 1 {
+		return b.args[l-1].EvalString(b.ctx, row)
+	}
+	return "", true, nil
+}
+
+type builtinCaseWhenTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenTimeSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals a builtinCaseWhenTimeSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/control-flow-functions.html#operator_case
+func (b *builtinCaseWhenTimeSig) evalTime(row chunk.Row) (ret types.Time, isNull bool, err error) {
+	var condition int64
+	l := len(b.args)
+	for i := 0; i < l-1; i += 2 {
+		condition, isNull, err = b.args[i].EvalInt(b.ctx, row)
+		if err != nil {
+			return ret, true, err
+		}
+		if isNull || condition == 0 {
+			continue
+		}
+		return b.args[i+1].EvalTime(b.ctx, row)
+	}
+	// When clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// Else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	if l%2 == 1 {
+		return b.args[l-1].EvalTime(b.ctx, row)
+	}
+	return ret, true, nil
+}
+
+type builtinCaseWhenDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenDurationSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDuration evals a builtinCaseWhenDurationSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/control-flow-functions.html#operator_case
+func (b *builtinCaseWhenDurationSig) evalDuration(row chunk.Row) (ret types.Duration, isNull bool, err error) {
+	var condition int64
+	l := len(b.args)
+	for i := 0; i < l-1; i += 2 {
+		condition, isNull, err = b.args[i].EvalInt(b.ctx, row)
+		if err != nil {
+			return ret, true, err
+		}
+		if isNull || condition == 0 {
+			continue
+		}
+		return b.args[i+1].EvalDuration(b.ctx, row)
+	}
+	// When clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// Else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	if l%2 == 1 {
+		return b.args[l-1].EvalDuration(b.ctx, row)
+	}
+	return ret, true, nil
+}
+
+type builtinCaseWhenJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenJSONSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinCaseWhenJSONSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/control-flow-functions.html#operator_case
+func (b *builtinCaseWhenJSONSig) evalJSON(row chunk.Row) (ret json.BinaryJSON, isNull bool, err error) {
+	var condition int64
+	l := len(b.args)
+	for i := 0; i < l-1; i += 2 {
+		condition, isNull, err = b.args[i].EvalInt(b.ctx, row)
+		if err != nil {
+			return ret, true, err
+		}
+		if isNull || condition == 0 {
+			continue
+		}
+		return b.args[i+1].EvalJSON(b.ctx, row)
+	}
+	// When clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// Else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	if l%2 == 1 {
+		return b.args[l-1].EvalJSON(b.ctx, row)
+	}
+	return ret, true, nil
+}
+
 type ifFunctionClass struct {
 	baseFunctionClass
 }
@@ -132,9 +454,21 @@ func (c *ifFunctionClass) getFunction(ctx sessionctx.Context, args []Expression)
 	case types.ETReal:
 		sig = &builtinIfRealSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_IfReal)
+	case types.ETDecimal:
+		sig = &builtinIfDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IfDecimal)
 	case types.ETString:
 		sig = &builtinIfStringSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_IfString)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinIfTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IfTime)
+	case types.ETDuration:
+		sig = &builtinIfDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IfDuration)
+	case types.ETJson:
+		sig = &builtinIfJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IfJson)
 	}
 	return sig, nil
 }
@@ -185,6 +519,29 @@ func (b *builtinIfRealSig) evalReal(row chunk.Row) (ret float64, isNull bool, er
 	return arg2, isNull2, err
 }
 
+type builtinIfDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIfDecimalSig) Clone() builtinFunc {
+	newSig := &builtinIfDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIfDecimalSig) evalDecimal(row chunk.Row) (ret *types.MyDecimal, isNull bool, err error) {
+	arg0, isNull0, err := b.args[0].EvalInt(b.ctx, row)
+	if err != nil {
+		return nil, true, err
+	}
+	arg1, isNull1, err := b.args[1].EvalDecimal(b.ctx, row)
+	if (!isNull0 && arg0 != 0) || err != nil {
+		return arg1, isNull1, err
+	}
+	arg2, isNull2, err := b.args[2].EvalDecimal(b.ctx, row)
+	return arg2, isNull2, err
+}
+
 type builtinIfStringSig struct {
 	baseBuiltinFunc
 }
@@ -208,6 +565,75 @@ func (b *builtinIfStringSig) evalString(row chunk.Row) (ret string, isNull bool,
 	return arg2, isNull2, err
 }
 
+type builtinIfTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIfTimeSig) Clone() builtinFunc {
+	newSig := &builtinIfTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIfTimeSig) evalTime(row chunk.Row) (ret types.Time, isNull bool, err error) {
+	arg0, isNull0, err := b.args[0].EvalInt(b.ctx, row)
+	if err != nil {
+		return ret, true, err
+	}
+	arg1, isNull1, err := b.args[1].EvalTime(b.ctx, row)
+	if (!isNull0 && arg0 != 0) || err != nil {
+		return arg1, isNull1, err
+	}
+	arg2, isNull2, err := b.args[2].EvalTime(b.ctx, row)
+	return arg2, isNull2, err
+}
+
+type builtinIfDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIfDurationSig) Clone() builtinFunc {
+	newSig := &builtinIfDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIfDurationSig) evalDuration(row chunk.Row) (ret types.Duration, isNull bool, err error) {
+	arg0, isNull0, err := b.args[0].EvalInt(b.ctx, row)
+	if err != nil {
+		return ret, true, err
+	}
+	arg1, isNull1, err := b.args[1].EvalDuration(b.ctx, row)
+	if (!isNull0 && arg0 != 0) || err != nil {
+		return arg1, isNull1, err
+	}
+	arg2, isNull2, err := b.args[2].EvalDuration(b.ctx, row)
+	return arg2, isNull2, err
+}
+
+type builtinIfJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIfJSONSig) Clone() builtinFunc {
+	newSig := &builtinIfJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIfJSONSig) evalJSON(row chunk.Row) (ret json.BinaryJSON, isNull bool, err error) {
+	arg0, isNull0, err := b.args[0].EvalInt(b.ctx, row)
+	if err != nil {
+		return ret, true, err
+	}
+	arg1, isNull1, err := b.args[1].EvalJSON(b.ctx, row)
+	if (!isNull0 && arg0 != 0) || err != nil {
+		return arg1, isNull1, err
+	}
+	arg2, isNull2, err := b.args[2].EvalJSON(b.ctx, row)
+	return arg2, isNull2, err
+}
+
 type ifNullFunctionClass struct {
 	baseFunctionClass
 }
@@ -234,9 +660,21 @@ func (c *ifNullFunctionClass) getFunction(ctx sessionctx.Context, args []Express
 	case types.ETReal:
 		sig = &builtinIfNullRealSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_IfNullReal)
+	case types.ETDecimal:
+		sig = &builtinIfNullDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IfNullDecimal)
 	case types.ETString:
 		sig = &builtinIfNullStringSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_IfNullString)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinIfNullTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IfNullTime)
+	case types.ETDuration:
+		sig = &builtinIfNullDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IfNullDuration)
+	case types.ETJson:
+		sig = &builtinIfNullJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IfNullJson)
 	}
 	return sig, nil
 }
@@ -279,6 +717,25 @@ func (b *builtinIfNullRealSig) evalReal(row chunk.Row) (float64, bool, error) {
 	return arg1, isNull || err != nil, err
 }
 
+type builtinIfNullDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIfNullDecimalSig) Clone() builtinFunc {
+	newSig := &builtinIfNullDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIfNullDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	arg0, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if !isNull || err != nil {
+		return arg0, err != nil, err
+	}
+	arg1, isNull, err := b.args[1].EvalDecimal(b.ctx, row)
+	return arg1, isNull || err != nil, err
+}
+
 type builtinIfNullStringSig struct {
 	baseBuiltinFunc
 }
@@ -297,3 +754,60 @@ func (b *builtinIfNullStringSig) evalString(row chunk.Row) (string, bool, error)
 	arg1, isNull, err := b.args[1].EvalString(b.ctx, row)
 	return arg1, isNull || err != nil, err
 }
+
+type builtinIfNullTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIfNullTimeSig) Clone() builtinFunc {
+	newSig := &builtinIfNullTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIfNullTimeSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	arg0, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if !isNull || err != nil {
+		return arg0, err != nil, err
+	}
+	arg1, isNull, err := b.args[1].EvalTime(b.ctx, row)
+	return arg1, isNull || err != nil, err
+}
+
+type builtinIfNullDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIfNullDurationSig) Clone() builtinFunc {
+	newSig := &builtinIfNullDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIfNullDurationSig) evalDuration(row chunk.Row) (types.Duration, bool, error) {
+	arg0, isNull, err := b.args[0].EvalDuration(b.ctx, row)
+	if !isNull || err != nil {
+		return arg0, err != nil, err
+	}
+	arg1, isNull, err := b.args[1].EvalDuration(b.ctx, row)
+	return arg1, isNull || err != nil, err
+}
+
+type builtinIfNullJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIfNullJSONSig) Clone() builtinFunc {
+	newSig := &builtinIfNullJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIfNullJSONSig) evalJSON(row chunk.Row) (json.BinaryJSON, bool, error) {
+	arg0, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if !isNull || err != nil {
+		return arg0, err != nil, err
+	}
+	arg1, isNull, err := b.args[1].EvalJSON(b.ctx, row)
+	return arg1, isNull || err != nil, err
+}
@@ -0,0 +1,83 @@
+// Copyright 2015 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/testutil"
+)
+
+func (s *testEvaluatorSuite) TestCaseWhen(c *C) {
+	tbl := []struct {
+		Arg []interface{}
+		Ret interface{}
+	}{
+		{[]interface{}{true, 1, true, 2, 3}, 1},
+		{[]interface{}{false, 1, true, 2, 3}, 2},
+		{[]interface{}{nil, 1, true, 2, 3}, 2},
+		{[]interface{}{false, 1, false, 2, 3}, 3},
+		{[]interface{}{nil, 1, nil, 2, 3}, 3},
+		{[]interface{}{false, 1, nil, 2, 3}, 3},
+		{[]interface{}{nil, 1, false, 2, 3}, 3},
+		{[]interface{}{1, 1.5, 2, 2.5}, 1.5},
+		{[]interface{}{0, 1, false, 2}, nil},
+		{[]interface{}{0, "a", 1, "b", "c"}, "b"},
+		{[]interface{}{0, "a", 0, "b", "c"}, "c"},
+		{[]interface{}{1, 1, 2, "a"}, "1"},
+		{[]interface{}{0, 1, 1, nil, 3}, nil},
+		{[]interface{}{nil, nil}, nil},
+	}
+	fc := funcs[ast.Case]
+	for _, t := range tbl {
+		f, err := fc.getFunction(s.ctx, s.primitiveValsToConstants(t.Arg))
+		c.Assert(err, IsNil)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.Ret), Commentf("%v", t.Arg))
+	}
+}
+
+func (s *testEvaluatorSuite) TestCaseWhenRetType(c *C) {
+	intCon := newNotNullConstant(types.NewIntDatum(1), mysql.TypeLonglong)
+	realCon := newNotNullConstant(types.NewFloat64Datum(2.5), mysql.TypeDouble)
+	strCon := newNotNullConstant(types.NewStringDatum("a"), mysql.TypeVarString)
+	tbl := []struct {
+		Arg     []Expression
+		EvalTp  types.EvalType
+		NotNull bool
+	}{
+		{[]Expression{One, intCon, Zero, intCon, intCon}, types.ETInt, true},
+		{[]Expression{One, intCon, Zero, intCon}, types.ETInt, false},
+		{[]Expression{One, intCon, Zero, realCon, intCon}, types.ETReal, true},
+		{[]Expression{One, intCon, Zero, strCon, intCon}, types.ETString, true},
+		{[]Expression{One, intCon, Zero, Null, intCon}, types.ETInt, false},
+	}
+	fc := funcs[ast.Case]
+	for i, t := range tbl {
+		f, err := fc.getFunction(s.ctx, t.Arg)
+		c.Assert(err, IsNil)
+		c.Assert(f.getRetTp().EvalType(), Equals, t.EvalTp, Commentf("case %d", i))
+		c.Assert(mysql.HasNotNullFlag(f.getRetTp().Flag), Equals, t.NotNull, Commentf("case %d", i))
+	}
+}
+
+func newNotNullConstant(d types.Datum, tp byte) *Constant {
+	ft := types.NewFieldType(tp)
+	ft.Flag |= mysql.NotNullFlag
+	return &Constant{Value: d, RetType: ft}
+}
@@ -259,3 +259,205 @@ func (b *builtinIfStringSig) vecEvalString(input *chunk.Chunk, result *chunk.Col
 func (b *builtinIfStringSig) vectorized() bool {
 	return true
 }
+
+func (b *builtinCaseWhenIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	l := len(b.args)
+	bufWhens := make([]*chunk.Column, l/2)
+	bufThens := make([]*chunk.Column, l/2)
+	var bufElse *chunk.Column
+	for j := 0; j < l-1; j += 2 {
+		bufWhen, err := b.bufAllocator.get(types.ETInt, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(bufWhen)
+		if err := b.args[j].VecEvalInt(b.ctx, input, bufWhen); err != nil {
+			return err
+		}
+		bufWhens[j/2] = bufWhen
+
+		bufThen, err := b.bufAllocator.get(types.ETInt, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(bufThen)
+		if err := b.args[j+1].VecEvalInt(b.ctx, input, bufThen); err != nil {
+			return err
+		}
+		bufThens[j/2] = bufThen
+	}
+	// When clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// Else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	if l%2 == 1 {
+		var err error
+		bufElse, err = b.bufAllocator.get(types.ETInt, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(bufElse)
+		if err := b.args[l-1].VecEvalInt(b.ctx, input, bufElse); err != nil {
+			return err
+		}
+	}
+	result.ResizeInt64(n, false)
+	rs := result.Int64s()
+ROW:
+	for i := 0; i < n; i++ {
+		for j := 0; j < l/2; j++ {
+			if bufWhens[j].IsNull(i) || bufWhens[j].GetInt64(i) == 0 {
+				continue
+			}
+			rs[i] = bufThens[j].GetInt64(i)
+			result.SetNull(i, bufThens[j].IsNull(i))
+			continue ROW
+		}
+		if bufElse != nil {
+			rs[i] = bufElse.GetInt64(i)
+			result.SetNull(i, bufElse.IsNull(i))
+		} else {
+			result.SetNull(i, true)
+		}
+	}
+	return nil
+}
+
+func (b *builtinCaseWhenIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCaseWhenRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	l := len(b.args)
+	bufWhens := make([]*chunk.Column, l/2)
+	bufThens := make([]*chunk.Column, l/2)
+	var bufElse *chunk.Column
+	for j := 0; j < l-1; j += 2 {
+		bufWhen, err := b.bufAllocator.get(types.ETInt, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(bufWhen)
+		if err := b.args[j].VecEvalInt(b.ctx, input, bufWhen); err != nil {
+			return err
+		}
+		bufWhens[j/2] = bufWhen
+
+		bufThen, err := b.bufAllocator.get(types.ETReal, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(bufThen)
+		if err := b.args[j+1].VecEvalReal(b.ctx, input, bufThen); err != nil {
+			return err
+		}
+		bufThens[j/2] = bufThen
+	}
+	// When clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// Else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	if l%2 == 1 {
+		var err error
+		bufElse, err = b.bufAllocator.get(types.ETReal, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(bufElse)
+		if err := b.args[l-1].VecEvalReal(b.ctx, input, bufElse); err != nil {
+			return err
+		}
+	}
+	result.ResizeFloat64(n, false)
+	rs := result.Float64s()
+ROW:
+	for i := 0; i < n; i++ {
+		for j := 0; j < l/2; j++ {
+			if bufWhens[j].IsNull(i) || bufWhens[j].GetInt64(i) == 0 {
+				continue
+			}
+			rs[i] = bufThens[j].GetFloat64(i)
+			result.SetNull(i, bufThens[j].IsNull(i))
+			continue ROW
+		}
+		if bufElse != nil {
+			rs[i] = bufElse.GetFloat64(i)
+			result.SetNull(i, bufElse.IsNull(i))
+		} else {
+			result.SetNull(i, true)
+		}
+	}
+	return nil
+}
+
+func (b *builtinCaseWhenRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCaseWhenStringSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	l := len(b.args)
+	bufWhens := make([]*chunk.Column, l/2)
+	bufThens := make([]*chunk.Column, l/2)
+	var bufElse *chunk.Column
+	for j := 0; j < l-1; j += 2 {
+		bufWhen, err := b.bufAllocator.get(types.ETInt, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(bufWhen)
+		if err := b.args[j].VecEvalInt(b.ctx, input, bufWhen); err != nil {
+			return err
+		}
+		bufWhens[j/2] = bufWhen
+
+		bufThen, err := b.bufAllocator.get(types.ETString, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(bufThen)
+		if err := b.args[j+1].VecEvalString(b.ctx, input, bufThen); err != nil {
+			return err
+		}
+		bufThens[j/2] = bufThen
+	}
+	// When clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// Else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	if l%2 == 1 {
+		var err error
+		bufElse, err = b.bufAllocator.get(types.ETString, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(bufElse)
+		if err := b.args[l-1].VecEvalString(b.ctx, input, bufElse); err != nil {
+			return err
+		}
+	}
+	result.ReserveString(n)
+ROW:
+	for i := 0; i < n; i++ {
+		for j := 0; j < l/2; j++ {
+			if bufWhens[j].IsNull(i) || bufWhens[j].GetInt64(i) == 0 {
+				continue
+			}
+			if bufThens[j].IsNull(i) {
+				result.AppendNull()
+			} else {
+				result.AppendString(bufThens[j].GetString(i))
+			}
+			continue ROW
+		}
+		if bufElse != nil && !bufElse.IsNull(i) {
+			result.AppendString(bufElse.GetString(i))
+		} else {
+			result.AppendNull()
+		}
+	}
+	return nil
+}
+
+func (b *builtinCaseWhenStringSig) vectorized() bool {
+	return true
+}
@@ -0,0 +1,373 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+var (
+	_ functionClass = &jsonExtractFunctionClass{}
+	_ functionClass = &jsonUnquoteFunctionClass{}
+	_ functionClass = &jsonSetFunctionClass{}
+	_ functionClass = &jsonObjectFunctionClass{}
+	_ functionClass = &jsonArrayFunctionClass{}
+	_ functionClass = &jsonContainsFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinJSONExtractSig{}
+	_ builtinFunc = &builtinJSONUnquoteSig{}
+	_ builtinFunc = &builtinJSONSetSig{}
+	_ builtinFunc = &builtinJSONObjectSig{}
+	_ builtinFunc = &builtinJSONArraySig{}
+	_ builtinFunc = &builtinJSONContainsSig{}
+)
+
+// evalPathExprs evaluates args as JSON path expressions. isNull is true if any
+// of them is NULL.
+func evalPathExprs(ctx sessionctx.Context, args []Expression, row chunk.Row) (pathExprs []json.PathExpression, isNull bool, err error) {
+	pathExprs = make([]json.PathExpression, 0, len(args))
+	for _, arg := range args {
+		s, isNull, err := arg.EvalString(ctx, row)
+		if isNull || err != nil {
+			return nil, isNull, err
+		}
+		pathExpr, err := json.ParseJSONPathExpr(s)
+		if err != nil {
+			return nil, true, err
+		}
+		pathExprs = append(pathExprs, pathExpr)
+	}
+	return pathExprs, false, nil
+}
+
+type jsonExtractFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonExtractFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	argTps = append(argTps, types.ETJson)
+	for range args[1:] {
+		argTps = append(argTps, types.ETString)
+	}
+	args[0] = wrapWithCastAsJSON(ctx, args[0], true)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETJson, argTps...)
+	sig := &builtinJSONExtractSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonExtractSig)
+	return sig, nil
+}
+
+// builtinJSONExtractSig see https://dev.mysql.com/doc/refman/5.7/en/json-search-functions.html#function_json-extract
+type builtinJSONExtractSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONExtractSig) Clone() builtinFunc {
+	newSig := &builtinJSONExtractSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinJSONExtractSig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	res, isNull, err = b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return
+	}
+	pathExprs, isNull, err := evalPathExprs(b.ctx, b.args[1:], row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	var found bool
+	if res, found = res.Extract(pathExprs); !found {
+		return res, true, nil
+	}
+	return res, false, nil
+}
+
+type jsonUnquoteFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonUnquoteFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	if args[0].GetType().EvalType() != types.ETString {
+		// JSON values are unquoted from their text representation.
+		tp := types.NewFieldType(mysql.TypeLongBlob)
+		tp.Charset, tp.Collate = mysql.DefaultCharset, mysql.DefaultCollationName
+		args[0] = BuildCastFunction(ctx, args[0], tp)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+	bf.tp.Flen = mysql.MaxFieldVarCharLength
+	sig := &builtinJSONUnquoteSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonUnquoteSig)
+	return sig, nil
+}
+
+// builtinJSONUnquoteSig see https://dev.mysql.com/doc/refman/5.7/en/json-modification-functions.html#function_json-unquote
+type builtinJSONUnquoteSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONUnquoteSig) Clone() builtinFunc {
+	newSig := &builtinJSONUnquoteSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinJSONUnquoteSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	str, err = json.UnquoteString(str)
+	if err != nil {
+		return "", false, err
+	}
+	return str, false, nil
+}
+
+type jsonSetFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonSetFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	if len(args)&1 != 1 {
+		return nil, ErrIncorrectParameterCount.GenWithStackByArgs(c.funcName)
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	argTps = append(argTps, types.ETJson)
+	args[0] = wrapWithCastAsJSON(ctx, args[0], true)
+	for i := 1; i < len(args)-1; i += 2 {
+		argTps = append(argTps, types.ETString, types.ETJson)
+		args[i+1] = wrapWithCastAsJSON(ctx, args[i+1], false)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETJson, argTps...)
+	sig := &builtinJSONSetSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonSetSig)
+	return sig, nil
+}
+
+// builtinJSONSetSig see https://dev.mysql.com/doc/refman/5.7/en/json-modification-functions.html#function_json-set
+type builtinJSONSetSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONSetSig) Clone() builtinFunc {
+	newSig := &builtinJSONSetSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinJSONSetSig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	res, isNull, err = b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	pathExprs := make([]json.PathExpression, 0, (len(b.args)-1)/2)
+	values := make([]json.BinaryJSON, 0, (len(b.args)-1)/2)
+	for i := 1; i < len(b.args); i += 2 {
+		exprs, isNull, err := evalPathExprs(b.ctx, b.args[i:i+1], row)
+		if isNull || err != nil {
+			return res, true, err
+		}
+		value, isNull, err := b.args[i+1].EvalJSON(b.ctx, row)
+		if err != nil {
+			return res, true, err
+		}
+		if isNull {
+			value = json.CreateBinary(nil)
+		}
+		pathExprs = append(pathExprs, exprs[0])
+		values = append(values, value)
+	}
+	res, err = res.Modify(pathExprs, values, json.ModifySet)
+	if err != nil {
+		return res, true, err
+	}
+	return res, false, nil
+}
+
+type jsonObjectFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonObjectFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	if len(args)&1 != 0 {
+		return nil, ErrIncorrectParameterCount.GenWithStackByArgs(c.funcName)
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for i := 0; i < len(args)-1; i += 2 {
+		argTps = append(argTps, types.ETString, types.ETJson)
+		args[i+1] = wrapWithCastAsJSON(ctx, args[i+1], false)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETJson, argTps...)
+	sig := &builtinJSONObjectSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonObjectSig)
+	return sig, nil
+}
+
+// builtinJSONObjectSig see https://dev.mysql.com/doc/refman/5.7/en/json-creation-functions.html#function_json-object
+type builtinJSONObjectSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONObjectSig) Clone() builtinFunc {
+	newSig := &builtinJSONObjectSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinJSONObjectSig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	jsons := make(map[string]interface{}, len(b.args)/2)
+	for i := 0; i < len(b.args); i += 2 {
+		key, isNull, err := b.args[i].EvalString(b.ctx, row)
+		if err != nil {
+			return res, true, err
+		}
+		if isNull {
+			return res, true, json.ErrJSONDocumentNULLKey
+		}
+		value, isNull, err := b.args[i+1].EvalJSON(b.ctx, row)
+		if err != nil {
+			return res, true, err
+		}
+		if isNull {
+			value = json.CreateBinary(nil)
+		}
+		jsons[key] = value
+	}
+	return json.CreateBinary(jsons), false, nil
+}
+
+type jsonArrayFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonArrayFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for i := range args {
+		argTps = append(argTps, types.ETJson)
+		args[i] = wrapWithCastAsJSON(ctx, args[i], false)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETJson, argTps...)
+	sig := &builtinJSONArraySig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonArraySig)
+	return sig, nil
+}
+
+// builtinJSONArraySig see https://dev.mysql.com/doc/refman/5.7/en/json-creation-functions.html#function_json-array
+type builtinJSONArraySig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONArraySig) Clone() builtinFunc {
+	newSig := &builtinJSONArraySig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinJSONArraySig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	jsons := make([]interface{}, 0, len(b.args))
+	for _, arg := range b.args {
+		j, isNull, err := arg.EvalJSON(b.ctx, row)
+		if err != nil {
+			return res, true, err
+		}
+		if isNull {
+			j = json.CreateBinary(nil)
+		}
+		jsons = append(jsons, j)
+	}
+	return json.CreateBinary(jsons), false, nil
+}
+
+type jsonContainsFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonContainsFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETJson, types.ETJson}
+	if len(args) == 3 {
+		argTps = append(argTps, types.ETString)
+	}
+	args[0], args[1] = wrapWithCastAsJSON(ctx, args[0], true), wrapWithCastAsJSON(ctx, args[1], true)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argTps...)
+	bf.tp.Flen = 1
+	sig := &builtinJSONContainsSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonContainsSig)
+	return sig, nil
+}
+
+// builtinJSONContainsSig see https://dev.mysql.com/doc/refman/5.7/en/json-search-functions.html#function_json-contains
+type builtinJSONContainsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONContainsSig) Clone() builtinFunc {
+	newSig := &builtinJSONContainsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinJSONContainsSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	obj, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	target, isNull, err := b.args[1].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	if len(b.args) == 3 {
+		pathExprs, isNull, err := evalPathExprs(b.ctx, b.args[2:], row)
+		if isNull || err != nil {
+			return res, isNull, err
+		}
+		if pathExprs[0].ContainsAnyAsterisk() {
+			return res, true, json.ErrInvalidJSONPathWildcard
+		}
+		var found bool
+		if obj, found = obj.Extract(pathExprs); !found {
+			return res, true, nil
+		}
+	}
+	if json.ContainsBinary(obj, target) {
+		return 1, false, nil
+	}
+	return 0, false, nil
+}
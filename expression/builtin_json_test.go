@@ -0,0 +1,182 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func (s *testEvaluatorSuite) TestJSONExtract(c *C) {
+	jstr := `{"a": [{"aa": [{"aaa": 1}]}], "aaa": 2}`
+	cases := []struct {
+		args     []interface{}
+		expected interface{}
+		success  bool
+	}{
+		{[]interface{}{nil, nil}, nil, true},
+		{[]interface{}{jstr, `$.a[0].aa[0].aaa`, `$.aaa`}, `[1, 2]`, true},
+		{[]interface{}{jstr, `$.a[0].aa[0].aaa`, `$InvalidPath`}, nil, false},
+		{[]interface{}{jstr, `$.b`}, nil, true},
+		{[]interface{}{jstr, nil}, nil, true},
+		{[]interface{}{`[1, 2`, `$[0]`}, nil, false},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, ast.JSONExtract, s.primitiveValsToConstants(t.args)...)
+		if err != nil {
+			c.Assert(t.success, IsFalse)
+			continue
+		}
+		d, err := f.Eval(chunk.Row{})
+		if !t.success {
+			c.Assert(err, NotNil)
+			continue
+		}
+		c.Assert(err, IsNil)
+		if t.expected == nil {
+			c.Assert(d.IsNull(), IsTrue)
+		} else {
+			c.Assert(d.GetMysqlJSON().String(), Equals, t.expected)
+		}
+	}
+}
+
+func (s *testEvaluatorSuite) TestJSONUnquote(c *C) {
+	cases := []struct {
+		arg    interface{}
+		result string
+	}{
+		{``, ``},
+		{`""`, ``},
+		{`"a"`, `a`},
+		{`"a\tb"`, "a\tb"},
+		{`"你"`, "你"},
+		{`{"a": "b"}`, `{"a": "b"}`},
+		{`[1, 2]`, `[1, 2]`},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, ast.JSONUnquote, s.primitiveValsToConstants([]interface{}{t.arg})...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d.GetString(), Equals, t.result)
+	}
+
+	// A JSON value is unquoted from its text representation.
+	extract, err := newFunctionForTest(s.ctx, ast.JSONExtract, s.primitiveValsToConstants([]interface{}{`{"a": "b"}`, `$.a`})...)
+	c.Assert(err, IsNil)
+	f, err := newFunctionForTest(s.ctx, ast.JSONUnquote, extract)
+	c.Assert(err, IsNil)
+	d, err := f.Eval(chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(d.GetString(), Equals, "b")
+}
+
+func (s *testEvaluatorSuite) TestJSONSet(c *C) {
+	cases := []struct {
+		args     []interface{}
+		expected interface{}
+		success  bool
+	}{
+		{[]interface{}{nil, `$.a`, 1}, nil, true},
+		{[]interface{}{`{}`, `$.a`, 3}, `{"a": 3}`, true},
+		{[]interface{}{`{"a": 3}`, `$.a`, "x", `$.b`, nil}, `{"a": "x", "b": null}`, true},
+		{[]interface{}{`[1, 2]`, `$[5]`, 3.5}, `[1, 2, 3.5]`, true},
+		{[]interface{}{`{}`, `$.*`, 1}, nil, false},
+		{[]interface{}{`{}`, `$.a`}, nil, false},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, ast.JSONSet, s.primitiveValsToConstants(t.args)...)
+		if err != nil {
+			c.Assert(t.success, IsFalse)
+			continue
+		}
+		d, err := f.Eval(chunk.Row{})
+		if !t.success {
+			c.Assert(err, NotNil)
+			continue
+		}
+		c.Assert(err, IsNil)
+		if t.expected == nil {
+			c.Assert(d.IsNull(), IsTrue)
+		} else {
+			c.Assert(d.GetMysqlJSON().String(), Equals, t.expected)
+		}
+	}
+}
+
+func (s *testEvaluatorSuite) TestJSONObjectAndArray(c *C) {
+	f, err := newFunctionForTest(s.ctx, ast.JSONObject, s.primitiveValsToConstants([]interface{}{"b", 1, "a", "x", "c", nil})...)
+	c.Assert(err, IsNil)
+	d, err := f.Eval(chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(d.GetMysqlJSON().String(), Equals, `{"a": "x", "b": 1, "c": null}`)
+
+	_, err = newFunctionForTest(s.ctx, ast.JSONObject, s.primitiveValsToConstants([]interface{}{"a"})...)
+	c.Assert(ErrIncorrectParameterCount.Equal(err), IsTrue)
+
+	f, err = newFunctionForTest(s.ctx, ast.JSONObject, s.primitiveValsToConstants([]interface{}{nil, 1})...)
+	c.Assert(err, IsNil)
+	_, err = f.Eval(chunk.Row{})
+	c.Assert(json.ErrJSONDocumentNULLKey.Equal(err), IsTrue)
+
+	f, err = newFunctionForTest(s.ctx, ast.JSONArray, s.primitiveValsToConstants([]interface{}{1, "[1]", nil, 2.5})...)
+	c.Assert(err, IsNil)
+	d, err = f.Eval(chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(d.GetMysqlJSON().String(), Equals, `[1, "[1]", null, 2.5]`)
+
+	f, err = newFunctionForTest(s.ctx, ast.JSONArray)
+	c.Assert(err, IsNil)
+	d, err = f.Eval(chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(d.GetMysqlJSON().String(), Equals, `[]`)
+}
+
+func (s *testEvaluatorSuite) TestJSONContains(c *C) {
+	jstr := `{"a": [1, "2", {"aa": "bb"}, 4.0], "b": true}`
+	cases := []struct {
+		args     []interface{}
+		expected interface{}
+		success  bool
+	}{
+		{[]interface{}{jstr, `{"a": [1]}`}, int64(1), true},
+		{[]interface{}{jstr, `{"a": [1, 2]}`}, int64(0), true},
+		{[]interface{}{jstr, `4`, `$.a`}, int64(1), true},
+		{[]interface{}{jstr, `true`, `$.b`}, int64(1), true},
+		{[]interface{}{jstr, `1`, `$.c`}, nil, true},
+		{[]interface{}{jstr, `1`, `$.*`}, nil, false},
+		{[]interface{}{nil, `1`}, nil, true},
+		{[]interface{}{jstr, nil}, nil, true},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, ast.JSONContains, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		if !t.success {
+			c.Assert(err, NotNil)
+			continue
+		}
+		c.Assert(err, IsNil)
+		if t.expected == nil {
+			c.Assert(d.IsNull(), IsTrue)
+		} else {
+			c.Assert(d.Kind(), Equals, types.KindInt64)
+			c.Assert(d.GetInt64(), Equals, t.expected)
+		}
+	}
+}
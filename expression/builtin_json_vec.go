@@ -0,0 +1,177 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func (b *builtinJSONExtractSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONExtractSig) vecEvalJSON(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	jsonBuf, err := b.bufAllocator.get(types.ETJson, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(jsonBuf)
+	if err := b.args[0].VecEvalJSON(b.ctx, input, jsonBuf); err != nil {
+		return err
+	}
+	pathBufs := make([]*chunk.Column, len(b.args)-1)
+	for i := range pathBufs {
+		if pathBufs[i], err = b.bufAllocator.get(types.ETString, n); err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(pathBufs[i])
+		if err := b.args[i+1].VecEvalString(b.ctx, input, pathBufs[i]); err != nil {
+			return err
+		}
+	}
+
+	result.ReserveJSON(n)
+	pathExprs := make([]json.PathExpression, len(pathBufs))
+	for i := 0; i < n; i++ {
+		if jsonBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		isNull := false
+		for j, pathBuf := range pathBufs {
+			if pathBuf.IsNull(i) {
+				isNull = true
+				break
+			}
+			if pathExprs[j], err = json.ParseJSONPathExpr(pathBuf.GetString(i)); err != nil {
+				return err
+			}
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		if res, found := jsonBuf.GetJSON(i).Extract(pathExprs); found {
+			result.AppendJSON(res)
+		} else {
+			result.AppendNull()
+		}
+	}
+	return nil
+}
+
+func (b *builtinJSONUnquoteSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONUnquoteSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		str, err := json.UnquoteString(buf.GetString(i))
+		if err != nil {
+			return err
+		}
+		result.AppendString(str)
+	}
+	return nil
+}
+
+func (b *builtinJSONArraySig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONArraySig) vecEvalJSON(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs := make([]*chunk.Column, len(b.args))
+	var err error
+	for i := range bufs {
+		if bufs[i], err = b.bufAllocator.get(types.ETJson, n); err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(bufs[i])
+		if err := b.args[i].VecEvalJSON(b.ctx, input, bufs[i]); err != nil {
+			return err
+		}
+	}
+
+	result.ReserveJSON(n)
+	for i := 0; i < n; i++ {
+		jsons := make([]interface{}, 0, len(bufs))
+		for _, buf := range bufs {
+			if buf.IsNull(i) {
+				jsons = append(jsons, json.CreateBinary(nil))
+			} else {
+				jsons = append(jsons, buf.GetJSON(i))
+			}
+		}
+		result.AppendJSON(json.CreateBinary(jsons))
+	}
+	return nil
+}
+
+func (b *builtinJSONContainsSig) vectorized() bool {
+	return len(b.args) == 2
+}
+
+func (b *builtinJSONContainsSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	objBuf, err := b.bufAllocator.get(types.ETJson, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(objBuf)
+	if err := b.args[0].VecEvalJSON(b.ctx, input, objBuf); err != nil {
+		return err
+	}
+	targetBuf, err := b.bufAllocator.get(types.ETJson, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(targetBuf)
+	if err := b.args[1].VecEvalJSON(b.ctx, input, targetBuf); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(objBuf, targetBuf)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		if json.ContainsBinary(objBuf.GetJSON(i), targetBuf.GetJSON(i)) {
+			i64s[i] = 1
+		} else {
+			i64s[i] = 0
+		}
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"regexp"
+	"sync"
+
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/stringutil"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+var (
+	_ functionClass = &likeFunctionClass{}
+	_ functionClass = &regexpFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinLikeSig{}
+	_ builtinFunc = &builtinRegexpSig{}
+	_ builtinFunc = &builtinRegexpUTF8Sig{}
+)
+
+// isMemorizable checks whether the arguments evaluate to the same value in
+// every execution, so a function may cache what it computes from them.
+func isMemorizable(args ...Expression) bool {
+	for _, arg := range args {
+		if !arg.ConstItem() || ContainMutableConst([]Expression{arg}) {
+			return false
+		}
+	}
+	return true
+}
+
+type likeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *likeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETString, types.ETInt}
+	wrapArgsWithCast(ctx, args, argTps...)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argTps...)
+	bf.tp.Flen = 1
+	sig := &builtinLikeSig{baseBuiltinFunc: bf}
+	sig.setPbCode(tipb.ScalarFuncSig_LikeSig)
+	return sig, nil
+}
+
+type builtinLikeSig struct {
+	baseBuiltinFunc
+
+	// patChars and patTypes cache the compiled pattern when both the pattern
+	// and the escape character are constant. They are not serialized.
+	patChars           []byte
+	patTypes           []byte
+	isMemorizedPattern bool
+	once               sync.Once
+}
+
+func (b *builtinLikeSig) Clone() builtinFunc {
+	newSig := &builtinLikeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// compilePattern compiles the pattern, or returns the cached result if the
+// pattern has been memorized.
+func (b *builtinLikeSig) compilePattern(pattern string, escape int64) (patChars, patTypes []byte) {
+	b.once.Do(func() {
+		if isMemorizable(b.args[1], b.args[2]) {
+			b.patChars, b.patTypes = stringutil.CompilePattern(pattern, byte(escape))
+			b.isMemorizedPattern = true
+		}
+	})
+	if b.isMemorizedPattern {
+		return b.patChars, b.patTypes
+	}
+	return stringutil.CompilePattern(pattern, byte(escape))
+}
+
+// evalInt evals a builtinLikeSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-comparison-functions.html#operator_like
+func (b *builtinLikeSig) evalInt(row chunk.Row) (int64, bool, error) {
+	valStr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	patternStr, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	escape, isNull, err := b.args[2].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	patChars, patTypes := b.compilePattern(patternStr, escape)
+	return boolToInt64(stringutil.DoMatch(valStr, patChars, patTypes)), false, nil
+}
+
+type regexpFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	wrapArgsWithCast(ctx, args, types.ETString, types.ETString)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETString, types.ETString)
+	bf.tp.Flen = 1
+	var sig builtinFunc
+	if types.IsBinaryStr(args[0].GetType()) || types.IsBinaryStr(args[1].GetType()) {
+		sig = &builtinRegexpSig{builtinRegexpSharedSig{baseBuiltinFunc: bf}}
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpSig)
+	} else {
+		sig = &builtinRegexpUTF8Sig{builtinRegexpSharedSig{baseBuiltinFunc: bf}}
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpUTF8Sig)
+	}
+	return sig, nil
+}
+
+// builtinRegexpSharedSig holds the evaluation shared by the binary and the
+// non-binary REGEXP signatures, which only differ in how the pattern is compiled.
+type builtinRegexpSharedSig struct {
+	baseBuiltinFunc
+
+	// memorizedRegexp and memorizedErr cache the compiled pattern when the
+	// pattern is constant. They are not serialized.
+	memorizedRegexp    *regexp.Regexp
+	memorizedErr       error
+	isMemorizedPattern bool
+	once               sync.Once
+}
+
+// getRegexp compiles the pattern with compile, or returns the cached result
+// if the pattern has been memorized.
+func (b *builtinRegexpSharedSig) getRegexp(pattern string, compile func(string) (*regexp.Regexp, error)) (*regexp.Regexp, error) {
+	b.once.Do(func() {
+		if isMemorizable(b.args[1]) {
+			b.memorizedRegexp, b.memorizedErr = compile(pattern)
+			b.isMemorizedPattern = true
+		}
+	})
+	if b.isMemorizedPattern {
+		return b.memorizedRegexp, b.memorizedErr
+	}
+	return compile(pattern)
+}
+
+func (b *builtinRegexpSharedSig) evalRegexp(row chunk.Row, compile func(string) (*regexp.Regexp, error)) (int64, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	pattern, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	re, err := b.getRegexp(pattern, compile)
+	if err != nil {
+		return 0, true, ErrRegexp.GenWithStackByArgs(err.Error())
+	}
+	return boolToInt64(re.MatchString(expr)), false, nil
+}
+
+// compileBinaryRegexp compiles a pattern that matches byte by byte and case sensitively.
+func compileBinaryRegexp(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile(pattern)
+}
+
+// compileUTF8Regexp compiles a pattern that matches case insensitively.
+func compileUTF8Regexp(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)" + pattern)
+}
+
+type builtinRegexpSig struct {
+	builtinRegexpSharedSig
+}
+
+func (b *builtinRegexpSig) Clone() builtinFunc {
+	newSig := &builtinRegexpSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals `expr REGEXP pat`, or `expr RLIKE pat` for binary strings.
+// See https://dev.mysql.com/doc/refman/5.7/en/regexp.html#operator_regexp
+func (b *builtinRegexpSig) evalInt(row chunk.Row) (int64, bool, error) {
+	return b.evalRegexp(row, compileBinaryRegexp)
+}
+
+type builtinRegexpUTF8Sig struct {
+	builtinRegexpSharedSig
+}
+
+func (b *builtinRegexpUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinRegexpUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals `expr REGEXP pat`, or `expr RLIKE pat` for non-binary strings.
+// See https://dev.mysql.com/doc/refman/5.7/en/regexp.html#operator_regexp
+func (b *builtinRegexpUTF8Sig) evalInt(row chunk.Row) (int64, bool, error) {
+	return b.evalRegexp(row, compileUTF8Regexp)
+}
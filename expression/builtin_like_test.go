@@ -0,0 +1,155 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/mock"
+	"github.com/pingcap/tidb/util/testutil"
+)
+
+func (s *testEvaluatorSuite) TestLike(c *C) {
+	tests := []struct {
+		input   string
+		pattern string
+		escape  int
+		match   int
+	}{
+		{"a", "", '\\', 0},
+		{"a", "a", '\\', 1},
+		{"a", "b", '\\', 0},
+		{"aA", "Aa", '\\', 0},
+		{"aAb", "Aa%", '\\', 0},
+		{"aAb", "aA_", '\\', 1},
+		{"abc", "a%c", '\\', 1},
+		{"abc", "%b%", '\\', 1},
+		{"abc", "_", '\\', 0},
+		{"a%c", `a\%c`, '\\', 1},
+		{"abc", `a\%c`, '\\', 0},
+		{"a_c", "a|_c", '|', 1},
+		{"abc", "a|_c", '|', 0},
+	}
+	for _, tt := range tests {
+		fc := funcs[ast.Like]
+		f, err := fc.getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{tt.input, tt.pattern, tt.escape}))
+		c.Assert(err, IsNil)
+		r, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(r, testutil.DatumEquals, types.NewDatum(tt.match), Commentf("%v", tt))
+	}
+
+	fc := funcs[ast.Like]
+	f, err := fc.getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{nil, "a%", '\\'}))
+	c.Assert(err, IsNil)
+	r, err := evalBuiltinFunc(f, chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(r.IsNull(), IsTrue)
+}
+
+func (s *testEvaluatorSuite) TestLikeMemorizedPattern(c *C) {
+	ctx := mock.NewContext()
+	ctx.GetSessionVars().PreparedParams = []types.Datum{types.NewStringDatum("a%")}
+	input := &Column{Index: 0, RetType: types.NewFieldType(mysql.TypeVarString)}
+	escape := &Constant{Value: types.NewIntDatum('\\'), RetType: types.NewFieldType(mysql.TypeLonglong)}
+	row := chunk.MutRowFromDatums([]types.Datum{types.NewStringDatum("abc")}).ToRow()
+
+	pattern := &Constant{Value: types.NewStringDatum("a%"), RetType: types.NewFieldType(mysql.TypeVarString)}
+	f, err := funcs[ast.Like].getFunction(ctx, []Expression{input, pattern, escape})
+	c.Assert(err, IsNil)
+	match, _, err := f.evalInt(row)
+	c.Assert(err, IsNil)
+	c.Assert(match, Equals, int64(1))
+	c.Assert(f.(*builtinLikeSig).isMemorizedPattern, IsTrue)
+
+	// The pattern of a parameter may change between executions, so it is never memorized.
+	param := &Constant{
+		Value:       types.NewStringDatum("a%"),
+		RetType:     types.NewFieldType(mysql.TypeVarString),
+		ParamMarker: NewParamMarker(ctx, 0),
+	}
+	f, err = funcs[ast.Like].getFunction(ctx, []Expression{input, param, escape})
+	c.Assert(err, IsNil)
+	match, _, err = f.evalInt(row)
+	c.Assert(err, IsNil)
+	c.Assert(match, Equals, int64(1))
+	c.Assert(f.(*builtinLikeSig).isMemorizedPattern, IsFalse)
+	ctx.GetSessionVars().PreparedParams[0] = types.NewStringDatum("b%")
+	match, _, err = f.evalInt(row)
+	c.Assert(err, IsNil)
+	c.Assert(match, Equals, int64(0))
+}
+
+func (s *testEvaluatorSuite) TestRegexp(c *C) {
+	tests := []struct {
+		pattern string
+		input   string
+		match   int64
+		err     error
+	}{
+		{"^$", "a", 0, nil},
+		{"a", "a", 1, nil},
+		{"a", "b", 0, nil},
+		{"aA", "aA", 1, nil},
+		{".", "a", 1, nil},
+		{"^.$", "ab", 0, nil},
+		{"..", "b", 0, nil},
+		{".ab", "aab", 1, nil},
+		{"ab.", "abcd", 1, nil},
+		{".*", "abcd", 1, nil},
+		{"(", "", 0, ErrRegexp},
+		{"(*", "", 0, ErrRegexp},
+		{"[a", "", 0, ErrRegexp},
+		{"\\", "", 0, ErrRegexp},
+	}
+	for _, tt := range tests {
+		fc := funcs[ast.Regexp]
+		f, err := fc.getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{tt.input, tt.pattern}))
+		c.Assert(err, IsNil)
+		match, err := evalBuiltinFunc(f, chunk.Row{})
+		if tt.err == nil {
+			c.Assert(err, IsNil)
+			c.Assert(match, testutil.DatumEquals, types.NewDatum(tt.match), Commentf("%v", tt))
+		} else {
+			c.Assert(terror.ErrorEqual(err, tt.err), IsTrue)
+		}
+	}
+}
+
+func (s *testEvaluatorSuite) TestRegexpCaseSensitivity(c *C) {
+	binaryTp := types.NewFieldType(mysql.TypeVarString)
+	binaryTp.Collate = charset.CollationBin
+	tests := []struct {
+		input   *Constant
+		pattern string
+		match   int64
+	}{
+		{&Constant{Value: types.NewStringDatum("ABC"), RetType: types.NewFieldType(mysql.TypeVarString)}, "^abc$", 1},
+		{&Constant{Value: types.NewStringDatum("ABC"), RetType: binaryTp}, "^abc$", 0},
+		{&Constant{Value: types.NewStringDatum("abc"), RetType: binaryTp}, "^abc$", 1},
+	}
+	for _, tt := range tests {
+		pattern := &Constant{Value: types.NewStringDatum(tt.pattern), RetType: types.NewFieldType(mysql.TypeVarString)}
+		f, err := funcs[ast.Regexp].getFunction(s.ctx, []Expression{tt.input, pattern})
+		c.Assert(err, IsNil)
+		match, _, err := f.evalInt(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(match, Equals, tt.match, Commentf("%v", tt.input.Value.GetString()))
+	}
+}
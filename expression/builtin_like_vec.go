@@ -0,0 +1,118 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"regexp"
+
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/stringutil"
+)
+
+func (b *builtinLikeSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLikeSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufVal, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufVal)
+	if err = b.args[0].VecEvalString(b.ctx, input, bufVal); err != nil {
+		return err
+	}
+	bufPattern, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufPattern)
+	if err = b.args[1].VecEvalString(b.ctx, input, bufPattern); err != nil {
+		return err
+	}
+	bufEscape, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufEscape)
+	if err = b.args[2].VecEvalInt(b.ctx, input, bufEscape); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(bufVal, bufPattern, bufEscape)
+	escapes := bufEscape.Int64s()
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		patChars, patTypes := b.compilePattern(bufPattern.GetString(i), escapes[i])
+		i64s[i] = boolToInt64(stringutil.DoMatch(bufVal.GetString(i), patChars, patTypes))
+	}
+	return nil
+}
+
+func (b *builtinRegexpSharedSig) vecEvalRegexp(input *chunk.Chunk, result *chunk.Column, compile func(string) (*regexp.Regexp, error)) error {
+	n := input.NumRows()
+	bufExpr, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufExpr)
+	if err = b.args[0].VecEvalString(b.ctx, input, bufExpr); err != nil {
+		return err
+	}
+	bufPattern, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufPattern)
+	if err = b.args[1].VecEvalString(b.ctx, input, bufPattern); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(bufExpr, bufPattern)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		re, err := b.getRegexp(bufPattern.GetString(i), compile)
+		if err != nil {
+			return ErrRegexp.GenWithStackByArgs(err.Error())
+		}
+		i64s[i] = boolToInt64(re.MatchString(bufExpr.GetString(i)))
+	}
+	return nil
+}
+
+func (b *builtinRegexpSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalRegexp(input, result, compileBinaryRegexp)
+}
+
+func (b *builtinRegexpUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpUTF8Sig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalRegexp(input, result, compileUTF8Regexp)
+}
@@ -0,0 +1,48 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+)
+
+var vecBuiltinLikeCases = map[string][]vecExprBenchCase{
+	ast.Like: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt},
+			geners: []dataGenerator{&selectStringGener{[]string{"abc", "abd", "a_c", "xabc", ""}}, &selectStringGener{[]string{"a%", "%b%", "a\\_c", "_b_", ""}}, &rangeInt64Gener{'\\', '\\' + 1}}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt},
+			geners:    []dataGenerator{&selectStringGener{[]string{"abc", "abd", "a_c", "xabc", ""}}},
+			constants: []*Constant{nil, {Value: types.NewStringDatum("a%c"), RetType: types.NewFieldType(mysql.TypeVarString)}, {Value: types.NewIntDatum('\\'), RetType: types.NewFieldType(mysql.TypeLonglong)}}},
+	},
+	ast.Regexp: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners: []dataGenerator{&selectStringGener{[]string{"abc", "ABC", "xyz", ""}}, &selectStringGener{[]string{"^a", "c$", "[x-z]+", ".*"}}}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners:    []dataGenerator{&selectStringGener{[]string{"abc", "ABC", "xyz", ""}}},
+			constants: []*Constant{nil, {Value: types.NewStringDatum("^a.c$"), RetType: types.NewFieldType(mysql.TypeVarString)}}},
+	},
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinLikeFunc(c *C) {
+	testVectorizedBuiltinFunc(c, vecBuiltinLikeCases)
+}
+
+func BenchmarkVectorizedBuiltinLikeFunc(b *testing.B) {
+	benchmarkVectorizedBuiltinFunc(b, vecBuiltinLikeCases)
+}
@@ -0,0 +1,1602 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/cznic/mathutil"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	utilMath "github.com/pingcap/tidb/util/math"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+var (
+	_ functionClass = &absFunctionClass{}
+	_ functionClass = &ceilFunctionClass{}
+	_ functionClass = &floorFunctionClass{}
+	_ functionClass = &roundFunctionClass{}
+	_ functionClass = &truncateFunctionClass{}
+	_ functionClass = &powFunctionClass{}
+	_ functionClass = &sqrtFunctionClass{}
+	_ functionClass = &expFunctionClass{}
+	_ functionClass = &lnFunctionClass{}
+	_ functionClass = &logFunctionClass{}
+	_ functionClass = &log2FunctionClass{}
+	_ functionClass = &log10FunctionClass{}
+	_ functionClass = &signFunctionClass{}
+	_ functionClass = &randFunctionClass{}
+	_ functionClass = &piFunctionClass{}
+	_ functionClass = &sinFunctionClass{}
+	_ functionClass = &cosFunctionClass{}
+	_ functionClass = &tanFunctionClass{}
+	_ functionClass = &cotFunctionClass{}
+	_ functionClass = &asinFunctionClass{}
+	_ functionClass = &acosFunctionClass{}
+	_ functionClass = &atanFunctionClass{}
+	_ functionClass = &degreesFunctionClass{}
+	_ functionClass = &radiansFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinAbsIntSig{}
+	_ builtinFunc = &builtinAbsUIntSig{}
+	_ builtinFunc = &builtinAbsRealSig{}
+	_ builtinFunc = &builtinAbsDecSig{}
+	_ builtinFunc = &builtinCeilIntToIntSig{}
+	_ builtinFunc = &builtinCeilDecToDecSig{}
+	_ builtinFunc = &builtinCeilRealSig{}
+	_ builtinFunc = &builtinFloorIntToIntSig{}
+	_ builtinFunc = &builtinFloorDecToDecSig{}
+	_ builtinFunc = &builtinFloorRealSig{}
+	_ builtinFunc = &builtinRoundIntSig{}
+	_ builtinFunc = &builtinRoundRealSig{}
+	_ builtinFunc = &builtinRoundDecSig{}
+	_ builtinFunc = &builtinRoundWithFracIntSig{}
+	_ builtinFunc = &builtinRoundWithFracRealSig{}
+	_ builtinFunc = &builtinRoundWithFracDecSig{}
+	_ builtinFunc = &builtinTruncateIntSig{}
+	_ builtinFunc = &builtinTruncateUintSig{}
+	_ builtinFunc = &builtinTruncateRealSig{}
+	_ builtinFunc = &builtinTruncateDecimalSig{}
+	_ builtinFunc = &builtinPowSig{}
+	_ builtinFunc = &builtinSqrtSig{}
+	_ builtinFunc = &builtinExpSig{}
+	_ builtinFunc = &builtinLog1ArgSig{}
+	_ builtinFunc = &builtinLog2ArgsSig{}
+	_ builtinFunc = &builtinLog2Sig{}
+	_ builtinFunc = &builtinLog10Sig{}
+	_ builtinFunc = &builtinSignSig{}
+	_ builtinFunc = &builtinRandSig{}
+	_ builtinFunc = &builtinRandWithSeedSig{}
+	_ builtinFunc = &builtinPISig{}
+	_ builtinFunc = &builtinSinSig{}
+	_ builtinFunc = &builtinCosSig{}
+	_ builtinFunc = &builtinTanSig{}
+	_ builtinFunc = &builtinCotSig{}
+	_ builtinFunc = &builtinAsinSig{}
+	_ builtinFunc = &builtinAcosSig{}
+	_ builtinFunc = &builtinAtan1ArgSig{}
+	_ builtinFunc = &builtinAtan2ArgsSig{}
+	_ builtinFunc = &builtinDegreesSig{}
+	_ builtinFunc = &builtinRadiansSig{}
+)
+
+// realFunc computes a math function of a real value, isNull is true if the
+// result of the function is NULL.
+type realFunc func(val float64) (res float64, isNull bool, err error)
+
+// evalRealWithFunc evaluates the only real argument of b and applies fn to it.
+func evalRealWithFunc(b *baseBuiltinFunc, row chunk.Row, fn realFunc) (float64, bool, error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return fn(val)
+}
+
+// newRealBaseBuiltinFunc builds the base function of a math function which
+// takes real arguments and returns a real value.
+func newRealBaseBuiltinFunc(ctx sessionctx.Context, args []Expression) baseBuiltinFunc {
+	argTps := make([]types.EvalType, 0, len(args))
+	for range args {
+		argTps = append(argTps, types.ETReal)
+	}
+	wrapArgsWithCast(ctx, args, argTps...)
+	return newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, argTps...)
+}
+
+type absFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *absFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argFieldTp := args[0].GetType()
+	argTp := numericContextResultType(argFieldTp)
+	wrapArgsWithCast(ctx, args, argTp)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, argTp, argTp)
+	isUnsigned := mysql.HasUnsignedFlag(argFieldTp.Flag)
+	if isUnsigned {
+		bf.tp.Flag |= mysql.UnsignedFlag
+	}
+	if argTp == types.ETReal {
+		bf.tp.Flen, bf.tp.Decimal = mysql.GetDefaultFieldLengthAndDecimal(mysql.TypeDouble)
+	} else {
+		bf.tp.Flen, bf.tp.Decimal = argFieldTp.Flen, argFieldTp.Decimal
+	}
+	var sig builtinFunc
+	switch argTp {
+	case types.ETInt:
+		if isUnsigned {
+			sig = &builtinAbsUIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_AbsUInt)
+		} else {
+			sig = &builtinAbsIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_AbsInt)
+		}
+	case types.ETDecimal:
+		sig = &builtinAbsDecSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_AbsDecimal)
+	default:
+		sig = &builtinAbsRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_AbsReal)
+	}
+	return sig, nil
+}
+
+type builtinAbsRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinAbsRealSig) Clone() builtinFunc {
+	newSig := &builtinAbsRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals ABS(value).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_abs
+func (b *builtinAbsRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return math.Abs(val), false, nil
+}
+
+type builtinAbsIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinAbsIntSig) Clone() builtinFunc {
+	newSig := &builtinAbsIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// absInt returns the absolute value of val, the absolute value of
+// math.MinInt64 overflows BIGINT.
+func (b *builtinAbsIntSig) absInt(val int64) (int64, error) {
+	if val == math.MinInt64 {
+		return 0, types.ErrOverflow.GenWithStackByArgs("BIGINT", fmt.Sprintf("abs(%s)", b.args[0].String()))
+	}
+	return utilMath.Abs(val), nil
+}
+
+// evalInt evals ABS(value).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_abs
+func (b *builtinAbsIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	val, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	res, err := b.absInt(val)
+	return res, err != nil, err
+}
+
+type builtinAbsUIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinAbsUIntSig) Clone() builtinFunc {
+	newSig := &builtinAbsUIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals ABS(value).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_abs
+func (b *builtinAbsUIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	return b.args[0].EvalInt(b.ctx, row)
+}
+
+type builtinAbsDecSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinAbsDecSig) Clone() builtinFunc {
+	newSig := &builtinAbsDecSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals ABS(value).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_abs
+func (b *builtinAbsDecSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	to := new(types.MyDecimal)
+	if !val.IsNegative() {
+		*to = *val
+	} else {
+		if err = types.DecimalSub(new(types.MyDecimal), val, to); err != nil {
+			return nil, true, err
+		}
+	}
+	return to, false, nil
+}
+
+// newCeilFloorBaseBuiltinFunc builds the base function of CEIL and FLOOR, the
+// result has the same type as the argument, but without the fraction part.
+func newCeilFloorBaseBuiltinFunc(ctx sessionctx.Context, args []Expression) (baseBuiltinFunc, types.EvalType) {
+	argFieldTp := args[0].GetType()
+	argTp := numericContextResultType(argFieldTp)
+	wrapArgsWithCast(ctx, args, argTp)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, argTp, argTp)
+	if mysql.HasUnsignedFlag(argFieldTp.Flag) {
+		bf.tp.Flag |= mysql.UnsignedFlag
+	}
+	if argTp != types.ETReal {
+		bf.tp.Flen = args[0].GetType().Flen
+	}
+	bf.tp.Decimal = 0
+	return bf, argTp
+}
+
+type ceilFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *ceilFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, argTp := newCeilFloorBaseBuiltinFunc(ctx, args)
+	var sig builtinFunc
+	switch argTp {
+	case types.ETInt:
+		sig = &builtinCeilIntToIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CeilIntToInt)
+	case types.ETDecimal:
+		sig = &builtinCeilDecToDecSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CeilDecToDec)
+	default:
+		sig = &builtinCeilRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CeilReal)
+	}
+	return sig, nil
+}
+
+type builtinCeilRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCeilRealSig) Clone() builtinFunc {
+	newSig := &builtinCeilRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinCeilRealSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_ceil
+func (b *builtinCeilRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return math.Ceil(val), false, nil
+}
+
+type builtinCeilIntToIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCeilIntToIntSig) Clone() builtinFunc {
+	newSig := &builtinCeilIntToIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinCeilIntToIntSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_ceil
+func (b *builtinCeilIntToIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	return b.args[0].EvalInt(b.ctx, row)
+}
+
+type builtinCeilDecToDecSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCeilDecToDecSig) Clone() builtinFunc {
+	newSig := &builtinCeilDecToDecSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals a builtinCeilDecToDecSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_ceil
+func (b *builtinCeilDecToDecSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	res := new(types.MyDecimal)
+	err = val.Round(res, 0, types.ModeTruncate)
+	if err != nil || val.IsNegative() || res.Compare(val) == 0 {
+		return res, err != nil, err
+	}
+	err = types.DecimalAdd(res, types.NewDecFromInt(1), res)
+	return res, err != nil, err
+}
+
+type floorFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *floorFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, argTp := newCeilFloorBaseBuiltinFunc(ctx, args)
+	var sig builtinFunc
+	switch argTp {
+	case types.ETInt:
+		sig = &builtinFloorIntToIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_FloorIntToInt)
+	case types.ETDecimal:
+		sig = &builtinFloorDecToDecSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_FloorDecToDec)
+	default:
+		sig = &builtinFloorRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_FloorReal)
+	}
+	return sig, nil
+}
+
+type builtinFloorRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinFloorRealSig) Clone() builtinFunc {
+	newSig := &builtinFloorRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinFloorRealSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_floor
+func (b *builtinFloorRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return math.Floor(val), false, nil
+}
+
+type builtinFloorIntToIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinFloorIntToIntSig) Clone() builtinFunc {
+	newSig := &builtinFloorIntToIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinFloorIntToIntSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_floor
+func (b *builtinFloorIntToIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	return b.args[0].EvalInt(b.ctx, row)
+}
+
+type builtinFloorDecToDecSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinFloorDecToDecSig) Clone() builtinFunc {
+	newSig := &builtinFloorDecToDecSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals a builtinFloorDecToDecSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_floor
+func (b *builtinFloorDecToDecSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	res := new(types.MyDecimal)
+	err = val.Round(res, 0, types.ModeTruncate)
+	if err != nil || !val.IsNegative() || res.Compare(val) == 0 {
+		return res, err != nil, err
+	}
+	err = types.DecimalSub(res, types.NewDecFromInt(1), res)
+	return res, err != nil, err
+}
+
+// calculateDecimal4RoundAndTruncate returns the decimal of the result of
+// ROUND and TRUNCATE, which is the second argument if it is a constant.
+func calculateDecimal4RoundAndTruncate(ctx sessionctx.Context, args []Expression, retType types.EvalType) int {
+	if retType == types.ETInt || len(args) <= 1 {
+		return 0
+	}
+	secondConst, secondIsConst := args[1].(*Constant)
+	if !secondIsConst {
+		return args[0].GetType().Decimal
+	}
+	argDec, isNull, err := secondConst.EvalInt(ctx, chunk.Row{})
+	if err != nil || isNull || argDec < 0 {
+		return 0
+	}
+	if argDec > mysql.MaxDecimalScale {
+		return mysql.MaxDecimalScale
+	}
+	return int(argDec)
+}
+
+type roundFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *roundFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argFieldTp := args[0].GetType()
+	argTp := numericContextResultType(argFieldTp)
+	argTps := []types.EvalType{argTp}
+	if len(args) > 1 {
+		argTps = append(argTps, types.ETInt)
+	}
+	wrapArgsWithCast(ctx, args, argTps...)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, argTp, argTps...)
+	if mysql.HasUnsignedFlag(argFieldTp.Flag) {
+		bf.tp.Flag |= mysql.UnsignedFlag
+	}
+	bf.tp.Flen = args[0].GetType().Flen
+	bf.tp.Decimal = calculateDecimal4RoundAndTruncate(ctx, args, argTp)
+
+	var sig builtinFunc
+	if len(args) > 1 {
+		switch argTp {
+		case types.ETInt:
+			sig = &builtinRoundWithFracIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_RoundWithFracInt)
+		case types.ETDecimal:
+			sig = &builtinRoundWithFracDecSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_RoundWithFracDec)
+		default:
+			sig = &builtinRoundWithFracRealSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_RoundWithFracReal)
+		}
+		return sig, nil
+	}
+	switch argTp {
+	case types.ETInt:
+		sig = &builtinRoundIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_RoundInt)
+	case types.ETDecimal:
+		sig = &builtinRoundDecSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_RoundDec)
+	default:
+		sig = &builtinRoundRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_RoundReal)
+	}
+	return sig, nil
+}
+
+type builtinRoundRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRoundRealSig) Clone() builtinFunc {
+	newSig := &builtinRoundRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals ROUND(value).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_round
+func (b *builtinRoundRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return types.RoundFloat(val), false, nil
+}
+
+type builtinRoundIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRoundIntSig) Clone() builtinFunc {
+	newSig := &builtinRoundIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals ROUND(value).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_round
+func (b *builtinRoundIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	return b.args[0].EvalInt(b.ctx, row)
+}
+
+type builtinRoundDecSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRoundDecSig) Clone() builtinFunc {
+	newSig := &builtinRoundDecSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals ROUND(value).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_round
+func (b *builtinRoundDecSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	to := new(types.MyDecimal)
+	if err = val.Round(to, 0, types.ModeHalfEven); err != nil {
+		return nil, true, err
+	}
+	return to, false, nil
+}
+
+type builtinRoundWithFracRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRoundWithFracRealSig) Clone() builtinFunc {
+	newSig := &builtinRoundWithFracRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals ROUND(value, frac).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_round
+func (b *builtinRoundWithFracRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	frac, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return types.Round(val, int(frac)), false, nil
+}
+
+type builtinRoundWithFracIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRoundWithFracIntSig) Clone() builtinFunc {
+	newSig := &builtinRoundWithFracIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals ROUND(value, frac).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_round
+func (b *builtinRoundWithFracIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	val, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	frac, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return int64(types.Round(float64(val), int(frac))), false, nil
+}
+
+type builtinRoundWithFracDecSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRoundWithFracDecSig) Clone() builtinFunc {
+	newSig := &builtinRoundWithFracDecSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals ROUND(value, frac).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_round
+func (b *builtinRoundWithFracDecSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	frac, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	to := new(types.MyDecimal)
+	if err = val.Round(to, mathutil.Min(int(frac), b.tp.Decimal), types.ModeHalfEven); err != nil {
+		return nil, true, err
+	}
+	return to, false, nil
+}
+
+type truncateFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *truncateFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTp := numericContextResultType(args[0].GetType())
+	wrapArgsWithCast(ctx, args, argTp, types.ETInt)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, argTp, argTp, types.ETInt)
+	argFieldTp := args[0].GetType()
+	bf.tp.Decimal = calculateDecimal4RoundAndTruncate(ctx, args, argTp)
+	bf.tp.Flen = argFieldTp.Flen
+	if argFieldTp.Decimal > 0 && argFieldTp.Flen > 0 {
+		bf.tp.Flen += bf.tp.Decimal - argFieldTp.Decimal
+	}
+	bf.tp.Flag |= argFieldTp.Flag & mysql.UnsignedFlag
+
+	var sig builtinFunc
+	switch argTp {
+	case types.ETInt:
+		if mysql.HasUnsignedFlag(argFieldTp.Flag) {
+			sig = &builtinTruncateUintSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_TruncateUint)
+		} else {
+			sig = &builtinTruncateIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_TruncateInt)
+		}
+	case types.ETDecimal:
+		sig = &builtinTruncateDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_TruncateDecimal)
+	default:
+		sig = &builtinTruncateRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_TruncateReal)
+	}
+	return sig, nil
+}
+
+type builtinTruncateDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTruncateDecimalSig) Clone() builtinFunc {
+	newSig := &builtinTruncateDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals a TRUNCATE(X,D).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_truncate
+func (b *builtinTruncateDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	x, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	d, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	result := new(types.MyDecimal)
+	if err := x.Round(result, mathutil.Min(int(d), b.getRetTp().Decimal), types.ModeTruncate); err != nil {
+		return nil, true, err
+	}
+	return result, false, nil
+}
+
+type builtinTruncateRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTruncateRealSig) Clone() builtinFunc {
+	newSig := &builtinTruncateRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a TRUNCATE(X,D).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_truncate
+func (b *builtinTruncateRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	x, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	d, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return types.Truncate(x, int(d)), false, nil
+}
+
+// truncateInt truncates x to d digits before the decimal point, a negative
+// d zeroes the last -d digits, and a non-negative d keeps x unchanged.
+func truncateInt(x, d int64) int64 {
+	if d >= 0 {
+		return x
+	}
+	if -d >= mysql.MaxIntWidth-1 {
+		return 0
+	}
+	shift := int64(math.Pow10(int(-d)))
+	return x / shift * shift
+}
+
+// truncateUint is the unsigned version of truncateInt.
+func truncateUint(x uint64, d int64) uint64 {
+	if d >= 0 {
+		return x
+	}
+	if -d >= mysql.MaxIntWidth {
+		return 0
+	}
+	shift := uint64(math.Pow10(int(-d)))
+	return x / shift * shift
+}
+
+type builtinTruncateIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTruncateIntSig) Clone() builtinFunc {
+	newSig := &builtinTruncateIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a TRUNCATE(X,D).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_truncate
+func (b *builtinTruncateIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	x, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	d, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if mysql.HasUnsignedFlag(b.args[1].GetType().Flag) {
+		return x, false, nil
+	}
+	return truncateInt(x, d), false, nil
+}
+
+type builtinTruncateUintSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTruncateUintSig) Clone() builtinFunc {
+	newSig := &builtinTruncateUintSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a TRUNCATE(X,D).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_truncate
+func (b *builtinTruncateUintSig) evalInt(row chunk.Row) (int64, bool, error) {
+	x, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	d, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if mysql.HasUnsignedFlag(b.args[1].GetType().Flag) {
+		return x, false, nil
+	}
+	return int64(truncateUint(uint64(x), d)), false, nil
+}
+
+type powFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *powFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	sig := &builtinPowSig{newRealBaseBuiltinFunc(ctx, args)}
+	sig.setPbCode(tipb.ScalarFuncSig_Pow)
+	return sig, nil
+}
+
+type builtinPowSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinPowSig) Clone() builtinFunc {
+	newSig := &builtinPowSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// pow returns x**y, it reports an error if the result overflows DOUBLE.
+func (b *builtinPowSig) pow(x, y float64) (float64, error) {
+	power := math.Pow(x, y)
+	if math.IsInf(power, -1) || math.IsInf(power, 1) || math.IsNaN(power) {
+		return 0, types.ErrOverflow.GenWithStackByArgs("DOUBLE", fmt.Sprintf("pow(%s, %s)", b.args[0].String(), b.args[1].String()))
+	}
+	return power, nil
+}
+
+// evalReal evals POW(x, y).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_pow
+func (b *builtinPowSig) evalReal(row chunk.Row) (float64, bool, error) {
+	x, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	y, isNull, err := b.args[1].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	power, err := b.pow(x, y)
+	return power, err != nil, err
+}
+
+type sqrtFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *sqrtFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	sig := &builtinSqrtSig{newRealBaseBuiltinFunc(ctx, args)}
+	sig.setPbCode(tipb.ScalarFuncSig_Sqrt)
+	return sig, nil
+}
+
+type builtinSqrtSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSqrtSig) Clone() builtinFunc {
+	newSig := &builtinSqrtSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinSqrtSig) sqrt(val float64) (float64, bool, error) {
+	if val < 0 {
+		return 0, true, nil
+	}
+	return math.Sqrt(val), false, nil
+}
+
+// evalReal evals a SQRT(x).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_sqrt
+func (b *builtinSqrtSig) evalReal(row chunk.Row) (float64, bool, error) {
+	return evalRealWithFunc(&b.baseBuiltinFunc, row, b.sqrt)
+}
+
+type expFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *expFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	sig := &builtinExpSig{newRealBaseBuiltinFunc(ctx, args)}
+	sig.setPbCode(tipb.ScalarFuncSig_Exp)
+	return sig, nil
+}
+
+type builtinExpSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinExpSig) Clone() builtinFunc {
+	newSig := &builtinExpSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinExpSig) exp(val float64) (float64, bool, error) {
+	exp := math.Exp(val)
+	if math.IsInf(exp, 0) || math.IsNaN(exp) {
+		return 0, true, types.ErrOverflow.GenWithStackByArgs("DOUBLE", fmt.Sprintf("exp(%s)", b.args[0].String()))
+	}
+	return exp, false, nil
+}
+
+// evalReal evals a builtinExpSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_exp
+func (b *builtinExpSig) evalReal(row chunk.Row) (float64, bool, error) {
+	return evalRealWithFunc(&b.baseBuiltinFunc, row, b.exp)
+}
+
+type lnFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *lnFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	sig := &builtinLog1ArgSig{newRealBaseBuiltinFunc(ctx, args)}
+	sig.setPbCode(tipb.ScalarFuncSig_Log1Arg)
+	return sig, nil
+}
+
+type logFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *logFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newRealBaseBuiltinFunc(ctx, args)
+	if len(args) == 1 {
+		sig := &builtinLog1ArgSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Log1Arg)
+		return sig, nil
+	}
+	sig := &builtinLog2ArgsSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Log2Args)
+	return sig, nil
+}
+
+// logWithFunc returns fn(val), the logarithm is NULL if val is not positive.
+func logWithFunc(val float64, fn func(float64) float64) (float64, bool, error) {
+	if val <= 0 {
+		return 0, true, nil
+	}
+	return fn(val), false, nil
+}
+
+func ln(val float64) (float64, bool, error) {
+	return logWithFunc(val, math.Log)
+}
+
+func log2(val float64) (float64, bool, error) {
+	return logWithFunc(val, math.Log2)
+}
+
+func log10(val float64) (float64, bool, error) {
+	return logWithFunc(val, math.Log10)
+}
+
+// logBase returns the logarithm of val to base, it is NULL if base <= 1 or
+// val <= 0.
+func logBase(base, val float64) (float64, bool) {
+	if val <= 0 || base <= 1 {
+		return 0, true
+	}
+	return math.Log(val) / math.Log(base), false
+}
+
+type builtinLog1ArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLog1ArgSig) Clone() builtinFunc {
+	newSig := &builtinLog1ArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinLog1ArgSig, corresponding to log(x) and ln(x).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_log
+func (b *builtinLog1ArgSig) evalReal(row chunk.Row) (float64, bool, error) {
+	return evalRealWithFunc(&b.baseBuiltinFunc, row, ln)
+}
+
+type builtinLog2ArgsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLog2ArgsSig) Clone() builtinFunc {
+	newSig := &builtinLog2ArgsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinLog2ArgsSig, corresponding to log(b, x).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_log
+func (b *builtinLog2ArgsSig) evalReal(row chunk.Row) (float64, bool, error) {
+	val1, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	val2, isNull, err := b.args[1].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	res, isNull := logBase(val1, val2)
+	return res, isNull, nil
+}
+
+type log2FunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *log2FunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	sig := &builtinLog2Sig{newRealBaseBuiltinFunc(ctx, args)}
+	sig.setPbCode(tipb.ScalarFuncSig_Log2)
+	return sig, nil
+}
+
+type builtinLog2Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLog2Sig) Clone() builtinFunc {
+	newSig := &builtinLog2Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinLog2Sig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_log2
+func (b *builtinLog2Sig) evalReal(row chunk.Row) (float64, bool, error) {
+	return evalRealWithFunc(&b.baseBuiltinFunc, row, log2)
+}
+
+type log10FunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *log10FunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	sig := &builtinLog10Sig{newRealBaseBuiltinFunc(ctx, args)}
+	sig.setPbCode(tipb.ScalarFuncSig_Log10)
+	return sig, nil
+}
+
+type builtinLog10Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLog10Sig) Clone() builtinFunc {
+	newSig := &builtinLog10Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinLog10Sig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_log10
+func (b *builtinLog10Sig) evalReal(row chunk.Row) (float64, bool, error) {
+	return evalRealWithFunc(&b.baseBuiltinFunc, row, log10)
+}
+
+type signFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *signFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	wrapArgsWithCast(ctx, args, types.ETReal)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETReal)
+	sig := &builtinSignSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Sign)
+	return sig, nil
+}
+
+type builtinSignSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSignSig) Clone() builtinFunc {
+	newSig := &builtinSignSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func sign(val float64) int64 {
+	if val > 0 {
+		return 1
+	} else if val == 0 {
+		return 0
+	}
+	return -1
+}
+
+// evalInt evals SIGN(v).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_sign
+func (b *builtinSignSig) evalInt(row chunk.Row) (int64, bool, error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return sign(val), false, nil
+}
+
+type randFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *randFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	var argTps []types.EvalType
+	if len(args) > 0 {
+		argTps = []types.EvalType{types.ETInt}
+	}
+	wrapArgsWithCast(ctx, args, argTps...)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, argTps...)
+	bt := bf.tp
+	bt.Flen, bt.Decimal = 23, 0
+	if len(args) == 0 {
+		sig := &builtinRandSig{bf, utilMath.NewWithTime()}
+		sig.setPbCode(tipb.ScalarFuncSig_Rand)
+		return sig, nil
+	}
+	// A constant seed generates a repeatable sequence for the rows, otherwise
+	// every row uses its own seed.
+	if constant, ok := args[0].(*Constant); ok {
+		seed, isNull, err := constant.EvalInt(ctx, chunk.Row{})
+		if err != nil {
+			return nil, err
+		}
+		if isNull {
+			seed = 0
+		}
+		sig := &builtinRandSig{bf, utilMath.NewWithSeed(seed)}
+		sig.setPbCode(tipb.ScalarFuncSig_Rand)
+		return sig, nil
+	}
+	sig := &builtinRandWithSeedSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_RandWithSeed)
+	return sig, nil
+}
+
+type builtinRandSig struct {
+	baseBuiltinFunc
+	mysqlRng *utilMath.MysqlRng
+}
+
+func (b *builtinRandSig) Clone() builtinFunc {
+	newSig := &builtinRandSig{mysqlRng: b.mysqlRng}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals RAND() and RAND(N) with a constant N.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_rand
+func (b *builtinRandSig) evalReal(row chunk.Row) (float64, bool, error) {
+	return b.mysqlRng.Gen(), false, nil
+}
+
+type builtinRandWithSeedSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRandWithSeedSig) Clone() builtinFunc {
+	newSig := &builtinRandWithSeedSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals RAND(N) with a non-constant N.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_rand
+func (b *builtinRandWithSeedSig) evalReal(row chunk.Row) (float64, bool, error) {
+	seed, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if err != nil {
+		return 0, true, err
+	}
+	// A NULL seed is treated as 0, like MySQL does.
+	if isNull {
+		seed = 0
+	}
+	return utilMath.NewWithSeed(seed).Gen(), false, nil
+}
+
+type piFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *piFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETReal)
+	bf.tp.Decimal = 6
+	bf.tp.Flen = 8
+	sig := &builtinPISig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_PI)
+	return sig, nil
+}
+
+type builtinPISig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinPISig) Clone() builtinFunc {
+	newSig := &builtinPISig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals PI().
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_pi
+func (b *builtinPISig) evalReal(_ chunk.Row) (float64, bool, error) {
+	return float64(math.Pi), false, nil
+}
+
+type sinFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *sinFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	sig := &builtinSinSig{newRealBaseBuiltinFunc(ctx, args)}
+	sig.setPbCode(tipb.ScalarFuncSig_Sin)
+	return sig, nil
+}
+
+type builtinSinSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSinSig) Clone() builtinFunc {
+	newSig := &builtinSinSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func sin(val float64) (float64, bool, error) {
+	return math.Sin(val), false, nil
+}
+
+// evalReal evals a builtinSinSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_sin
+func (b *builtinSinSig) evalReal(row chunk.Row) (float64, bool, error) {
+	return evalRealWithFunc(&b.baseBuiltinFunc, row, sin)
+}
+
+type cosFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *cosFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	sig := &builtinCosSig{newRealBaseBuiltinFunc(ctx, args)}
+	sig.setPbCode(tipb.ScalarFuncSig_Cos)
+	return sig, nil
+}
+
+type builtinCosSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCosSig) Clone() builtinFunc {
+	newSig := &builtinCosSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func cos(val float64) (float64, bool, error) {
+	return math.Cos(val), false, nil
+}
+
+// evalReal evals a builtinCosSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_cos
+func (b *builtinCosSig) evalReal(row chunk.Row) (float64, bool, error) {
+	return evalRealWithFunc(&b.baseBuiltinFunc, row, cos)
+}
+
+type tanFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tanFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	sig := &builtinTanSig{newRealBaseBuiltinFunc(ctx, args)}
+	sig.setPbCode(tipb.ScalarFuncSig_Tan)
+	return sig, nil
+}
+
+type builtinTanSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTanSig) Clone() builtinFunc {
+	newSig := &builtinTanSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func tan(val float64) (float64, bool, error) {
+	return math.Tan(val), false, nil
+}
+
+// evalReal evals a builtinTanSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_tan
+func (b *builtinTanSig) evalReal(row chunk.Row) (float64, bool, error) {
+	return evalRealWithFunc(&b.baseBuiltinFunc, row, tan)
+}
+
+type cotFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *cotFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	sig := &builtinCotSig{newRealBaseBuiltinFunc(ctx, args)}
+	sig.setPbCode(tipb.ScalarFuncSig_Cot)
+	return sig, nil
+}
+
+type builtinCotSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCotSig) Clone() builtinFunc {
+	newSig := &builtinCotSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCotSig) cot(val float64) (float64, bool, error) {
+	tan := math.Tan(val)
+	if tan != 0 {
+		cot := 1 / tan
+		if !math.IsInf(cot, 0) && !math.IsNaN(cot) {
+			return cot, false, nil
+		}
+	}
+	return 0, true, types.ErrOverflow.GenWithStackByArgs("DOUBLE", fmt.Sprintf("cot(%s)", b.args[0].String()))
+}
+
+// evalReal evals a builtinCotSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_cot
+func (b *builtinCotSig) evalReal(row chunk.Row) (float64, bool, error) {
+	return evalRealWithFunc(&b.baseBuiltinFunc, row, b.cot)
+}
+
+type asinFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *asinFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	sig := &builtinAsinSig{newRealBaseBuiltinFunc(ctx, args)}
+	sig.setPbCode(tipb.ScalarFuncSig_Asin)
+	return sig, nil
+}
+
+type builtinAsinSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinAsinSig) Clone() builtinFunc {
+	newSig := &builtinAsinSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// asin returns the arc sine of val, it is NULL if val is not in the range -1 to 1.
+func asin(val float64) (float64, bool, error) {
+	if val < -1 || val > 1 {
+		return 0, true, nil
+	}
+	return math.Asin(val), false, nil
+}
+
+// evalReal evals a builtinAsinSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_asin
+func (b *builtinAsinSig) evalReal(row chunk.Row) (float64, bool, error) {
+	return evalRealWithFunc(&b.baseBuiltinFunc, row, asin)
+}
+
+type acosFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *acosFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	sig := &builtinAcosSig{newRealBaseBuiltinFunc(ctx, args)}
+	sig.setPbCode(tipb.ScalarFuncSig_Acos)
+	return sig, nil
+}
+
+type builtinAcosSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinAcosSig) Clone() builtinFunc {
+	newSig := &builtinAcosSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// acos returns the arc cosine of val, it is NULL if val is not in the range -1 to 1.
+func acos(val float64) (float64, bool, error) {
+	if val < -1 || val > 1 {
+		return 0, true, nil
+	}
+	return math.Acos(val), false, nil
+}
+
+// evalReal evals a builtinAcosSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_acos
+func (b *builtinAcosSig) evalReal(row chunk.Row) (float64, bool, error) {
+	return evalRealWithFunc(&b.baseBuiltinFunc, row, acos)
+}
+
+type atanFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *atanFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newRealBaseBuiltinFunc(ctx, args)
+	if len(args) == 1 {
+		sig := &builtinAtan1ArgSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Atan1Arg)
+		return sig, nil
+	}
+	sig := &builtinAtan2ArgsSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Atan2Args)
+	return sig, nil
+}
+
+type builtinAtan1ArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinAtan1ArgSig) Clone() builtinFunc {
+	newSig := &builtinAtan1ArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func atan(val float64) (float64, bool, error) {
+	return math.Atan(val), false, nil
+}
+
+// evalReal evals a builtinAtan1ArgSig, corresponding to atan(x).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_atan
+func (b *builtinAtan1ArgSig) evalReal(row chunk.Row) (float64, bool, error) {
+	return evalRealWithFunc(&b.baseBuiltinFunc, row, atan)
+}
+
+type builtinAtan2ArgsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinAtan2ArgsSig) Clone() builtinFunc {
+	newSig := &builtinAtan2ArgsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinAtan2ArgsSig, corresponding to atan(y, x) and atan2(y, x).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_atan
+func (b *builtinAtan2ArgsSig) evalReal(row chunk.Row) (float64, bool, error) {
+	y, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	x, isNull, err := b.args[1].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return math.Atan2(y, x), false, nil
+}
+
+type degreesFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *degreesFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	sig := &builtinDegreesSig{newRealBaseBuiltinFunc(ctx, args)}
+	sig.setPbCode(tipb.ScalarFuncSig_Degrees)
+	return sig, nil
+}
+
+type builtinDegreesSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinDegreesSig) Clone() builtinFunc {
+	newSig := &builtinDegreesSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func degrees(val float64) (float64, bool, error) {
+	return val * 180 / math.Pi, false, nil
+}
+
+// evalReal evals a builtinDegreesSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_degrees
+func (b *builtinDegreesSig) evalReal(row chunk.Row) (float64, bool, error) {
+	return evalRealWithFunc(&b.baseBuiltinFunc, row, degrees)
+}
+
+type radiansFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *radiansFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	sig := &builtinRadiansSig{newRealBaseBuiltinFunc(ctx, args)}
+	sig.setPbCode(tipb.ScalarFuncSig_Radians)
+	return sig, nil
+}
+
+type builtinRadiansSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRadiansSig) Clone() builtinFunc {
+	newSig := &builtinRadiansSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func radians(val float64) (float64, bool, error) {
+	return val * math.Pi / 180, false, nil
+}
+
+// evalReal evals RADIANS(X).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_radians
+func (b *builtinRadiansSig) evalReal(row chunk.Row) (float64, bool, error) {
+	return evalRealWithFunc(&b.baseBuiltinFunc, row, radians)
+}
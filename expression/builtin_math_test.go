@@ -0,0 +1,306 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"math"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/testutil"
+)
+
+func (s *testEvaluatorSuite) TestAbs(c *C) {
+	testCases := []struct {
+		args   interface{}
+		expect interface{}
+	}{
+		{nil, nil},
+		{int64(1), int64(1)},
+		{int64(-1), int64(1)},
+		{uint64(1), uint64(1)},
+		{float64(-1.5), float64(1.5)},
+		{types.NewDecFromStringForTest("-12.34"), types.NewDecFromStringForTest("12.34")},
+		{types.NewDecFromStringForTest("0.00"), types.NewDecFromStringForTest("0.00")},
+	}
+
+	for _, tc := range testCases {
+		f, err := newFunctionForTest(s.ctx, ast.Abs, s.primitiveValsToConstants([]interface{}{tc.args})...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(tc.expect))
+	}
+
+	f, err := newFunctionForTest(s.ctx, ast.Abs, s.primitiveValsToConstants([]interface{}{int64(math.MinInt64)})...)
+	c.Assert(err, IsNil)
+	_, err = f.Eval(chunk.Row{})
+	c.Assert(terror.ErrorEqual(err, types.ErrOverflow), IsTrue)
+}
+
+func (s *testEvaluatorSuite) TestCeilFloor(c *C) {
+	testCases := []struct {
+		args  interface{}
+		ceil  interface{}
+		floor interface{}
+	}{
+		{nil, nil, nil},
+		{int64(3), int64(3), int64(3)},
+		{uint64(3), uint64(3), uint64(3)},
+		{float64(1.23), float64(2), float64(1)},
+		{float64(-1.23), float64(-1), float64(-2)},
+		{types.NewDecFromStringForTest("1.23"), types.NewDecFromStringForTest("2"), types.NewDecFromStringForTest("1")},
+		{types.NewDecFromStringForTest("-1.23"), types.NewDecFromStringForTest("-1"), types.NewDecFromStringForTest("-2")},
+		{types.NewDecFromStringForTest("-3.00"), types.NewDecFromStringForTest("-3"), types.NewDecFromStringForTest("-3")},
+		{"1.5", float64(2), float64(1)},
+	}
+
+	for _, tc := range testCases {
+		for _, funcName := range []string{ast.Ceil, ast.Ceiling} {
+			f, err := newFunctionForTest(s.ctx, funcName, s.primitiveValsToConstants([]interface{}{tc.args})...)
+			c.Assert(err, IsNil)
+			d, err := f.Eval(chunk.Row{})
+			c.Assert(err, IsNil)
+			c.Assert(d, testutil.DatumEquals, types.NewDatum(tc.ceil))
+		}
+		f, err := newFunctionForTest(s.ctx, ast.Floor, s.primitiveValsToConstants([]interface{}{tc.args})...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(tc.floor))
+	}
+}
+
+func (s *testEvaluatorSuite) TestRound(c *C) {
+	testCases := []struct {
+		args   []interface{}
+		expect interface{}
+	}{
+		{[]interface{}{nil}, nil},
+		{[]interface{}{int64(-3)}, int64(-3)},
+		{[]interface{}{float64(-1.23)}, float64(-1)},
+		{[]interface{}{float64(-1.58)}, float64(-2)},
+		{[]interface{}{float64(1.58)}, float64(2)},
+		{[]interface{}{float64(2.5)}, float64(3)},
+		{[]interface{}{float64(1.298), int64(1)}, float64(1.3)},
+		{[]interface{}{float64(1.298), int64(0)}, float64(1)},
+		{[]interface{}{float64(23.298), int64(-1)}, float64(20)},
+		{[]interface{}{int64(1234), int64(-2)}, int64(1200)},
+		{[]interface{}{float64(1.298), nil}, nil},
+		{[]interface{}{types.NewDecFromStringForTest("1.58")}, types.NewDecFromStringForTest("2")},
+		{[]interface{}{types.NewDecFromStringForTest("-1.5")}, types.NewDecFromStringForTest("-2")},
+		{[]interface{}{types.NewDecFromStringForTest("1.298"), int64(1)}, types.NewDecFromStringForTest("1.3")},
+		{[]interface{}{types.NewDecFromStringForTest("23.298"), int64(-1)}, types.NewDecFromStringForTest("20")},
+	}
+
+	for _, tc := range testCases {
+		f, err := newFunctionForTest(s.ctx, ast.Round, s.primitiveValsToConstants(tc.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(tc.expect))
+	}
+}
+
+func (s *testEvaluatorSuite) TestTruncate(c *C) {
+	testCases := []struct {
+		args   []interface{}
+		expect interface{}
+	}{
+		{[]interface{}{nil, int64(1)}, nil},
+		{[]interface{}{float64(1.223), nil}, nil},
+		{[]interface{}{float64(1.223), int64(1)}, float64(1.2)},
+		{[]interface{}{float64(1.999), int64(1)}, float64(1.9)},
+		{[]interface{}{float64(1.999), int64(0)}, float64(1)},
+		{[]interface{}{float64(-1.999), int64(1)}, float64(-1.9)},
+		{[]interface{}{float64(122), int64(-2)}, float64(100)},
+		{[]interface{}{int64(1028), int64(-2)}, int64(1000)},
+		{[]interface{}{int64(-1028), int64(-2)}, int64(-1000)},
+		{[]interface{}{int64(1028), int64(-20)}, int64(0)},
+		{[]interface{}{int64(1028), int64(2)}, int64(1028)},
+		{[]interface{}{uint64(math.MaxUint64), int64(-2)}, uint64(18446744073709551600)},
+		{[]interface{}{types.NewDecFromStringForTest("10.28"), int64(1)}, types.NewDecFromStringForTest("10.2")},
+		{[]interface{}{types.NewDecFromStringForTest("-10.28"), int64(0)}, types.NewDecFromStringForTest("-10")},
+	}
+
+	for _, tc := range testCases {
+		f, err := newFunctionForTest(s.ctx, ast.Truncate, s.primitiveValsToConstants(tc.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(tc.expect))
+	}
+}
+
+func (s *testEvaluatorSuite) TestPowSqrtExp(c *C) {
+	testCases := []struct {
+		funcName string
+		args     []interface{}
+		expect   interface{}
+		err      error
+	}{
+		{ast.Pow, []interface{}{int64(2), int64(10)}, float64(1024), nil},
+		{ast.Power, []interface{}{float64(2), float64(-1)}, float64(0.5), nil},
+		{ast.Pow, []interface{}{"4", "0.5"}, float64(2), nil},
+		{ast.Pow, []interface{}{nil, int64(2)}, nil, nil},
+		{ast.Pow, []interface{}{float64(10), float64(400)}, nil, types.ErrOverflow},
+		{ast.Sqrt, []interface{}{int64(16)}, float64(4), nil},
+		{ast.Sqrt, []interface{}{int64(-16)}, nil, nil},
+		{ast.Sqrt, []interface{}{nil}, nil, nil},
+		{ast.Exp, []interface{}{int64(0)}, float64(1), nil},
+		{ast.Exp, []interface{}{float64(2)}, math.Exp(2), nil},
+		{ast.Exp, []interface{}{int64(1000)}, nil, types.ErrOverflow},
+	}
+
+	for _, tc := range testCases {
+		f, err := newFunctionForTest(s.ctx, tc.funcName, s.primitiveValsToConstants(tc.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		if tc.err != nil {
+			c.Assert(terror.ErrorEqual(err, tc.err), IsTrue, Commentf("%s%v", tc.funcName, tc.args))
+			continue
+		}
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(tc.expect))
+	}
+}
+
+func (s *testEvaluatorSuite) TestLog(c *C) {
+	testCases := []struct {
+		funcName string
+		args     []interface{}
+		expect   interface{}
+	}{
+		{ast.Ln, []interface{}{float64(math.E)}, float64(1)},
+		{ast.Ln, []interface{}{int64(0)}, nil},
+		{ast.Ln, []interface{}{int64(-1)}, nil},
+		{ast.Log, []interface{}{int64(1)}, float64(0)},
+		{ast.Log, []interface{}{nil}, nil},
+		{ast.Log, []interface{}{int64(2), int64(65536)}, float64(16)},
+		{ast.Log, []interface{}{int64(10), int64(100)}, float64(2)},
+		{ast.Log, []interface{}{int64(1), int64(100)}, nil},
+		{ast.Log, []interface{}{int64(2), int64(-1)}, nil},
+		{ast.Log2, []interface{}{int64(65536)}, float64(16)},
+		{ast.Log2, []interface{}{int64(-1)}, nil},
+		{ast.Log10, []interface{}{int64(100)}, float64(2)},
+		{ast.Log10, []interface{}{float64(0)}, nil},
+	}
+
+	for _, tc := range testCases {
+		f, err := newFunctionForTest(s.ctx, tc.funcName, s.primitiveValsToConstants(tc.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(tc.expect), Commentf("%s%v", tc.funcName, tc.args))
+	}
+}
+
+func (s *testEvaluatorSuite) TestSign(c *C) {
+	testCases := []struct {
+		args   interface{}
+		expect interface{}
+	}{
+		{nil, nil},
+		{int64(-32), int64(-1)},
+		{uint64(32), int64(1)},
+		{float64(0), int64(0)},
+		{types.NewDecFromStringForTest("-0.01"), int64(-1)},
+		{"1.5", int64(1)},
+	}
+
+	for _, tc := range testCases {
+		f, err := newFunctionForTest(s.ctx, ast.Sign, s.primitiveValsToConstants([]interface{}{tc.args})...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(tc.expect))
+	}
+}
+
+func (s *testEvaluatorSuite) TestRand(c *C) {
+	fc := funcs[ast.Rand]
+	f, err := fc.getFunction(s.ctx, nil)
+	c.Assert(err, IsNil)
+	v, err := evalBuiltinFunc(f, chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(v.GetFloat64(), Less, float64(1))
+	c.Assert(v.GetFloat64(), GreaterEqual, float64(0))
+
+	// A constant seed generates the same sequence as MySQL.
+	f, err = fc.getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{int64(3)}))
+	c.Assert(err, IsNil)
+	for _, expect := range []float64{0.9057697559760601, 0.37307905813034536, 0.14808605345719125} {
+		v, err = evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(v.GetFloat64(), Equals, expect)
+	}
+
+	// RAND(NULL) is the same as RAND(0).
+	f, err = fc.getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{nil}))
+	c.Assert(err, IsNil)
+	g, err := fc.getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{int64(0)}))
+	c.Assert(err, IsNil)
+	v1, err := evalBuiltinFunc(f, chunk.Row{})
+	c.Assert(err, IsNil)
+	v2, err := evalBuiltinFunc(g, chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(v1.GetFloat64(), Equals, v2.GetFloat64())
+
+	// RAND() can not be folded.
+	expr, err := NewFunction(s.ctx, ast.Rand, types.NewFieldType(0))
+	c.Assert(err, IsNil)
+	_, isConst := expr.(*Constant)
+	c.Assert(isConst, IsFalse)
+}
+
+func (s *testEvaluatorSuite) TestTrigonometric(c *C) {
+	testCases := []struct {
+		funcName string
+		args     []interface{}
+		expect   interface{}
+	}{
+		{ast.PI, nil, float64(math.Pi)},
+		{ast.Sin, []interface{}{float64(1)}, math.Sin(1)},
+		{ast.Sin, []interface{}{nil}, nil},
+		{ast.Cos, []interface{}{int64(0)}, float64(1)},
+		{ast.Tan, []interface{}{float64(0.8)}, math.Tan(0.8)},
+		{ast.Cot, []interface{}{float64(1)}, 1 / math.Tan(1)},
+		{ast.Asin, []interface{}{int64(1)}, math.Pi / 2},
+		{ast.Asin, []interface{}{int64(2)}, nil},
+		{ast.Acos, []interface{}{int64(1)}, float64(0)},
+		{ast.Acos, []interface{}{int64(-2)}, nil},
+		{ast.Atan, []interface{}{int64(1)}, math.Pi / 4},
+		{ast.Atan, []interface{}{int64(-2), int64(2)}, -math.Pi / 4},
+		{ast.Atan2, []interface{}{int64(1), int64(0)}, math.Pi / 2},
+		{ast.Atan2, []interface{}{nil, int64(0)}, nil},
+		{ast.Degrees, []interface{}{float64(math.Pi)}, float64(180)},
+		{ast.Radians, []interface{}{int64(180)}, float64(math.Pi)},
+	}
+
+	for _, tc := range testCases {
+		f, err := newFunctionForTest(s.ctx, tc.funcName, s.primitiveValsToConstants(tc.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(tc.expect), Commentf("%s%v", tc.funcName, tc.args))
+	}
+
+	f, err := newFunctionForTest(s.ctx, ast.Cot, s.primitiveValsToConstants([]interface{}{int64(0)})...)
+	c.Assert(err, IsNil)
+	_, err = f.Eval(chunk.Row{})
+	c.Assert(terror.ErrorEqual(err, types.ErrOverflow), IsTrue)
+}
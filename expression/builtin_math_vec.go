@@ -0,0 +1,579 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"math"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	utilMath "github.com/pingcap/tidb/util/math"
+)
+
+// vecEvalRealWithFunc evaluates the only real argument of b and applies fn to
+// every non-NULL row of it.
+func vecEvalRealWithFunc(b *baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column, fn realFunc) error {
+	if err := b.args[0].VecEvalReal(b.ctx, input, result); err != nil {
+		return err
+	}
+	f64s := result.Float64s()
+	for i := 0; i < len(f64s); i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		res, isNull, err := fn(f64s[i])
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.SetNull(i, true)
+			continue
+		}
+		f64s[i] = res
+	}
+	return nil
+}
+
+// vecEvalRealWith2Args evaluates the two real arguments of b and sets fn(x, y)
+// to result for every row that neither x nor y is NULL.
+func vecEvalRealWith2Args(b *baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column, fn func(x, y float64) (float64, bool, error)) error {
+	if err := b.args[0].VecEvalReal(b.ctx, input, result); err != nil {
+		return err
+	}
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETReal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[1].VecEvalReal(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.MergeNulls(buf)
+	x := result.Float64s()
+	y := buf.Float64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		res, isNull, err := fn(x[i], y[i])
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.SetNull(i, true)
+			continue
+		}
+		x[i] = res
+	}
+	return nil
+}
+
+func (b *builtinAbsIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinAbsIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+	i64s := result.Int64s()
+	for i := 0; i < len(i64s); i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		res, err := b.absInt(i64s[i])
+		if err != nil {
+			return err
+		}
+		i64s[i] = res
+	}
+	return nil
+}
+
+func (b *builtinAbsUIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinAbsUIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return b.args[0].VecEvalInt(b.ctx, input, result)
+}
+
+func (b *builtinAbsRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinAbsRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalReal(b.ctx, input, result); err != nil {
+		return err
+	}
+	f64s := result.Float64s()
+	for i := 0; i < len(f64s); i++ {
+		f64s[i] = math.Abs(f64s[i])
+	}
+	return nil
+}
+
+func (b *builtinCeilIntToIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCeilIntToIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return b.args[0].VecEvalInt(b.ctx, input, result)
+}
+
+func (b *builtinCeilRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCeilRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalReal(b.ctx, input, result); err != nil {
+		return err
+	}
+	f64s := result.Float64s()
+	for i := 0; i < len(f64s); i++ {
+		f64s[i] = math.Ceil(f64s[i])
+	}
+	return nil
+}
+
+func (b *builtinFloorIntToIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinFloorIntToIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return b.args[0].VecEvalInt(b.ctx, input, result)
+}
+
+func (b *builtinFloorRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinFloorRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalReal(b.ctx, input, result); err != nil {
+		return err
+	}
+	f64s := result.Float64s()
+	for i := 0; i < len(f64s); i++ {
+		f64s[i] = math.Floor(f64s[i])
+	}
+	return nil
+}
+
+func (b *builtinRoundIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRoundIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return b.args[0].VecEvalInt(b.ctx, input, result)
+}
+
+func (b *builtinRoundRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRoundRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalReal(b.ctx, input, result); err != nil {
+		return err
+	}
+	f64s := result.Float64s()
+	for i := 0; i < len(f64s); i++ {
+		f64s[i] = types.RoundFloat(f64s[i])
+	}
+	return nil
+}
+
+func (b *builtinRoundWithFracRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRoundWithFracRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalReal(b.ctx, input, result); err != nil {
+		return err
+	}
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[1].VecEvalInt(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.MergeNulls(buf)
+	x := result.Float64s()
+	d := buf.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		x[i] = types.Round(x[i], int(d[i]))
+	}
+	return nil
+}
+
+func (b *builtinRoundWithFracIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRoundWithFracIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[1].VecEvalInt(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.MergeNulls(buf)
+	x := result.Int64s()
+	d := buf.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		x[i] = int64(types.Round(float64(x[i]), int(d[i])))
+	}
+	return nil
+}
+
+func (b *builtinTruncateRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTruncateRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalReal(b.ctx, input, result); err != nil {
+		return err
+	}
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[1].VecEvalInt(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.MergeNulls(buf)
+	x := result.Float64s()
+	d := buf.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		x[i] = types.Truncate(x[i], int(d[i]))
+	}
+	return nil
+}
+
+func (b *builtinTruncateIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTruncateIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[1].VecEvalInt(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.MergeNulls(buf)
+	if mysql.HasUnsignedFlag(b.args[1].GetType().Flag) {
+		return nil
+	}
+	x := result.Int64s()
+	d := buf.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		x[i] = truncateInt(x[i], d[i])
+	}
+	return nil
+}
+
+func (b *builtinTruncateUintSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTruncateUintSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[1].VecEvalInt(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.MergeNulls(buf)
+	if mysql.HasUnsignedFlag(b.args[1].GetType().Flag) {
+		return nil
+	}
+	x := result.Uint64s()
+	d := buf.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		x[i] = truncateUint(x[i], d[i])
+	}
+	return nil
+}
+
+func (b *builtinPowSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinPowSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalRealWith2Args(&b.baseBuiltinFunc, input, result, func(x, y float64) (float64, bool, error) {
+		power, err := b.pow(x, y)
+		return power, false, err
+	})
+}
+
+func (b *builtinSqrtSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSqrtSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalRealWithFunc(&b.baseBuiltinFunc, input, result, b.sqrt)
+}
+
+func (b *builtinExpSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinExpSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalRealWithFunc(&b.baseBuiltinFunc, input, result, b.exp)
+}
+
+func (b *builtinLog1ArgSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLog1ArgSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalRealWithFunc(&b.baseBuiltinFunc, input, result, ln)
+}
+
+func (b *builtinLog2ArgsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLog2ArgsSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalRealWith2Args(&b.baseBuiltinFunc, input, result, func(base, val float64) (float64, bool, error) {
+		res, isNull := logBase(base, val)
+		return res, isNull, nil
+	})
+}
+
+func (b *builtinLog2Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLog2Sig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalRealWithFunc(&b.baseBuiltinFunc, input, result, log2)
+}
+
+func (b *builtinLog10Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLog10Sig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalRealWithFunc(&b.baseBuiltinFunc, input, result, log10)
+}
+
+func (b *builtinSignSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSignSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETReal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalReal(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf)
+	f64s := buf.Float64s()
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		i64s[i] = sign(f64s[i])
+	}
+	return nil
+}
+
+func (b *builtinRandSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRandSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.ResizeFloat64(n, false)
+	f64s := result.Float64s()
+	for i := 0; i < n; i++ {
+		f64s[i] = b.mysqlRng.Gen()
+	}
+	return nil
+}
+
+func (b *builtinRandWithSeedSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRandWithSeedSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalInt(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeFloat64(n, false)
+	seeds := buf.Int64s()
+	f64s := result.Float64s()
+	for i := 0; i < n; i++ {
+		seed := seeds[i]
+		// A NULL seed is treated as 0, like MySQL does.
+		if buf.IsNull(i) {
+			seed = 0
+		}
+		f64s[i] = utilMath.NewWithSeed(seed).Gen()
+	}
+	return nil
+}
+
+func (b *builtinPISig) vectorized() bool {
+	return true
+}
+
+func (b *builtinPISig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.ResizeFloat64(n, false)
+	f64s := result.Float64s()
+	for i := 0; i < n; i++ {
+		f64s[i] = math.Pi
+	}
+	return nil
+}
+
+func (b *builtinSinSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSinSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalRealWithFunc(&b.baseBuiltinFunc, input, result, sin)
+}
+
+func (b *builtinCosSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCosSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalRealWithFunc(&b.baseBuiltinFunc, input, result, cos)
+}
+
+func (b *builtinTanSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTanSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalRealWithFunc(&b.baseBuiltinFunc, input, result, tan)
+}
+
+func (b *builtinCotSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCotSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalRealWithFunc(&b.baseBuiltinFunc, input, result, b.cot)
+}
+
+func (b *builtinAsinSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinAsinSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalRealWithFunc(&b.baseBuiltinFunc, input, result, asin)
+}
+
+func (b *builtinAcosSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinAcosSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalRealWithFunc(&b.baseBuiltinFunc, input, result, acos)
+}
+
+func (b *builtinAtan1ArgSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinAtan1ArgSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalRealWithFunc(&b.baseBuiltinFunc, input, result, atan)
+}
+
+func (b *builtinAtan2ArgsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinAtan2ArgsSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalRealWith2Args(&b.baseBuiltinFunc, input, result, func(y, x float64) (float64, bool, error) {
+		return math.Atan2(y, x), false, nil
+	})
+}
+
+func (b *builtinDegreesSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinDegreesSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalRealWithFunc(&b.baseBuiltinFunc, input, result, degrees)
+}
+
+func (b *builtinRadiansSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRadiansSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalRealWithFunc(&b.baseBuiltinFunc, input, result, radians)
+}
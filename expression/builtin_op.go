@@ -37,10 +37,16 @@ var (
 	_ builtinFunc = &builtinLogicAndSig{}
 	_ builtinFunc = &builtinLogicOrSig{}
 	_ builtinFunc = &builtinUnaryMinusIntSig{}
+	_ builtinFunc = &builtinUnaryMinusDecimalSig{}
 	_ builtinFunc = &builtinIntIsNullSig{}
+	_ builtinFunc = &builtinDecimalIsNullSig{}
 	_ builtinFunc = &builtinRealIsNullSig{}
 	_ builtinFunc = &builtinStringIsNullSig{}
+	_ builtinFunc = &builtinTimeIsNullSig{}
+	_ builtinFunc = &builtinDurationIsNullSig{}
+	_ builtinFunc = &builtinJSONIsNullSig{}
 	_ builtinFunc = &builtinUnaryNotRealSig{}
+	_ builtinFunc = &builtinUnaryNotDecimalSig{}
 	_ builtinFunc = &builtinUnaryNotIntSig{}
 )
 
@@ -144,7 +150,9 @@ func (c *unaryNotFunctionClass) getFunction(ctx sessionctx.Context, args []Expre
 	}
 
 	argTp := args[0].GetType().EvalType()
-	if argTp == types.ETString {
+	if argTp == types.ETTimestamp || argTp == types.ETDatetime || argTp == types.ETDuration {
+		argTp = types.ETInt
+	} else if argTp == types.ETString {
 		argTp = types.ETReal
 	}
 
@@ -156,6 +164,9 @@ func (c *unaryNotFunctionClass) getFunction(ctx sessionctx.Context, args []Expre
 	case types.ETReal:
 		sig = &builtinUnaryNotRealSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_UnaryNotReal)
+	case types.ETDecimal:
+		sig = &builtinUnaryNotDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_UnaryNotDecimal)
 	case types.ETInt:
 		sig = &builtinUnaryNotIntSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_UnaryNotInt)
@@ -186,6 +197,27 @@ func (b *builtinUnaryNotRealSig) evalInt(row chunk.Row) (int64, bool, error) {
 	return 0, false, nil
 }
 
+type builtinUnaryNotDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUnaryNotDecimalSig) Clone() builtinFunc {
+	newSig := &builtinUnaryNotDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinUnaryNotDecimalSig) evalInt(row chunk.Row) (int64, bool, error) {
+	arg, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	if arg.IsZero() {
+		return 1, false, nil
+	}
+	return 0, false, nil
+}
+
 type builtinUnaryNotIntSig struct {
 	baseBuiltinFunc
 }
@@ -211,15 +243,42 @@ type unaryMinusFunctionClass struct {
 	baseFunctionClass
 }
 
+func (c *unaryMinusFunctionClass) handleIntOverflow(arg *Constant) (overflow bool) {
+	if mysql.HasUnsignedFlag(arg.GetType().Flag) {
+		uval := arg.Value.GetUint64()
+		// -math.MinInt64 is 9223372036854775808, so if uval is more than 9223372036854775808, like
+		// 9223372036854775809, -9223372036854775809 is less than math.MinInt64, overflow occurs.
+		if uval > uint64(-math.MinInt64) {
+			return true
+		}
+	} else {
+		val := arg.Value.GetInt64()
+		// The math.MinInt64 is -9223372036854775808, the math.MaxInt64 is 9223372036854775807,
+		// which is less than abs(-9223372036854775808). When val == math.MinInt64, overflow occurs.
+		if val == math.MinInt64 {
+			return true
+		}
+	}
+	return false
+}
+
 // typeInfer infers unaryMinus function return type. when the arg is an int constant and overflow,
 // typerInfer will infers the return type as types.ETDecimal, not types.ETInt.
 func (c *unaryMinusFunctionClass) typeInfer(argExpr Expression) (types.EvalType, bool) {
 	tp := argExpr.GetType().EvalType()
-	if tp != types.ETInt {
+	if tp != types.ETInt && tp != types.ETDecimal {
 		tp = types.ETReal
 	}
 
-	return tp, false
+	overflow := false
+	// TODO: Handle float overflow.
+	if arg, ok := argExpr.(*Constant); ok && tp == types.ETInt {
+		overflow = c.handleIntOverflow(arg)
+		if overflow {
+			tp = types.ETDecimal
+		}
+	}
+	return tp, overflow
 }
 
 func (c *unaryMinusFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
@@ -234,13 +293,20 @@ func (c *unaryMinusFunctionClass) getFunction(ctx sessionctx.Context, args []Exp
 	switch argExprTp.EvalType() {
 	case types.ETInt:
 		if intOverflow {
-			panic("overflows int")
+			bf = newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDecimal)
+			sig = &builtinUnaryMinusDecimalSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_UnaryMinusDecimal)
 		} else {
 			bf = newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETInt)
 			sig = &builtinUnaryMinusIntSig{bf}
 			sig.setPbCode(tipb.ScalarFuncSig_UnaryMinusInt)
 		}
 		bf.tp.Decimal = 0
+	case types.ETDecimal:
+		bf = newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDecimal)
+		bf.tp.Decimal = argExprTp.Decimal
+		sig = &builtinUnaryMinusDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_UnaryMinusDecimal)
 	case types.ETReal:
 		bf = newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, types.ETReal)
 		sig = &builtinUnaryMinusRealSig{bf}
@@ -284,6 +350,24 @@ func (b *builtinUnaryMinusIntSig) evalInt(row chunk.Row) (res int64, isNull bool
 	return -val, false, nil
 }
 
+type builtinUnaryMinusDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUnaryMinusDecimalSig) Clone() builtinFunc {
+	newSig := &builtinUnaryMinusDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinUnaryMinusDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	dec, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if err != nil || isNull {
+		return dec, isNull, err
+	}
+	return types.DecimalNeg(dec), false, nil
+}
+
 type builtinUnaryMinusRealSig struct {
 	baseBuiltinFunc
 }
@@ -315,12 +399,24 @@ func (c *isNullFunctionClass) getFunction(ctx sessionctx.Context, args []Express
 	case types.ETInt:
 		sig = &builtinIntIsNullSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_IntIsNull)
+	case types.ETDecimal:
+		sig = &builtinDecimalIsNullSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_DecimalIsNull)
 	case types.ETReal:
 		sig = &builtinRealIsNullSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_RealIsNull)
 	case types.ETString:
 		sig = &builtinStringIsNullSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_StringIsNull)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinTimeIsNullSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_TimeIsNull)
+	case types.ETDuration:
+		sig = &builtinDurationIsNullSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_DurationIsNull)
+	case types.ETJson:
+		sig = &builtinJSONIsNullSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_JsonIsNull)
 	default:
 		panic("unexpected types.EvalType")
 	}
@@ -337,6 +433,21 @@ func evalIsNull(isNull bool, err error) (int64, bool, error) {
 	return 0, false, nil
 }
 
+type builtinDecimalIsNullSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinDecimalIsNullSig) Clone() builtinFunc {
+	newSig := &builtinDecimalIsNullSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinDecimalIsNullSig) evalInt(row chunk.Row) (int64, bool, error) {
+	_, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	return evalIsNull(isNull, err)
+}
+
 type builtinIntIsNullSig struct {
 	baseBuiltinFunc
 }
@@ -381,3 +492,48 @@ func (b *builtinStringIsNullSig) evalInt(row chunk.Row) (int64, bool, error) {
 	_, isNull, err := b.args[0].EvalString(b.ctx, row)
 	return evalIsNull(isNull, err)
 }
+
+type builtinTimeIsNullSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTimeIsNullSig) Clone() builtinFunc {
+	newSig := &builtinTimeIsNullSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinTimeIsNullSig) evalInt(row chunk.Row) (int64, bool, error) {
+	_, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	return evalIsNull(isNull, err)
+}
+
+type builtinDurationIsNullSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinDurationIsNullSig) Clone() builtinFunc {
+	newSig := &builtinDurationIsNullSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinDurationIsNullSig) evalInt(row chunk.Row) (int64, bool, error) {
+	_, isNull, err := b.args[0].EvalDuration(b.ctx, row)
+	return evalIsNull(isNull, err)
+}
+
+type builtinJSONIsNullSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONIsNullSig) Clone() builtinFunc {
+	newSig := &builtinJSONIsNullSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinJSONIsNullSig) evalInt(row chunk.Row) (int64, bool, error) {
+	_, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	return evalIsNull(isNull, err)
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/stringutil"
 	"github.com/pingcap/tipb/go-tipb"
@@ -37,12 +38,20 @@ var (
 	_ builtinFunc = &builtinInIntSig{}
 	_ builtinFunc = &builtinInStringSig{}
 	_ builtinFunc = &builtinInRealSig{}
+	_ builtinFunc = &builtinInDecimalSig{}
+	_ builtinFunc = &builtinInTimeSig{}
+	_ builtinFunc = &builtinInDurationSig{}
+	_ builtinFunc = &builtinInJSONSig{}
 	_ builtinFunc = &builtinRowSig{}
 	_ builtinFunc = &builtinSetVarSig{}
 	_ builtinFunc = &builtinGetVarSig{}
 	_ builtinFunc = &builtinValuesIntSig{}
 	_ builtinFunc = &builtinValuesRealSig{}
+	_ builtinFunc = &builtinValuesDecimalSig{}
 	_ builtinFunc = &builtinValuesStringSig{}
+	_ builtinFunc = &builtinValuesTimeSig{}
+	_ builtinFunc = &builtinValuesDurationSig{}
+	_ builtinFunc = &builtinValuesJSONSig{}
 )
 
 type inFunctionClass struct {
@@ -56,6 +65,9 @@ func (c *inFunctionClass) getFunction(ctx sessionctx.Context, args []Expression)
 	argTps := make([]types.EvalType, len(args))
 	for i := range args {
 		argTps[i] = args[0].GetType().EvalType()
+		if argTps[i] == types.ETJson {
+			args[i] = wrapWithCastAsJSON(ctx, args[i], false)
+		}
 	}
 	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argTps...)
 	bf.tp.Flen = 1
@@ -69,6 +81,18 @@ func (c *inFunctionClass) getFunction(ctx sessionctx.Context, args []Expression)
 	case types.ETReal:
 		sig = &builtinInRealSig{baseBuiltinFunc: bf}
 		sig.setPbCode(tipb.ScalarFuncSig_InReal)
+	case types.ETDecimal:
+		sig = &builtinInDecimalSig{baseBuiltinFunc: bf}
+		sig.setPbCode(tipb.ScalarFuncSig_InDecimal)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinInTimeSig{baseBuiltinFunc: bf}
+		sig.setPbCode(tipb.ScalarFuncSig_InTime)
+	case types.ETDuration:
+		sig = &builtinInDurationSig{baseBuiltinFunc: bf}
+		sig.setPbCode(tipb.ScalarFuncSig_InDuration)
+	case types.ETJson:
+		sig = &builtinInJSONSig{baseBuiltinFunc: bf}
+		sig.setPbCode(tipb.ScalarFuncSig_InJson)
 	}
 	return sig, nil
 }
@@ -188,6 +212,138 @@ func (b *builtinInRealSig) evalInt(row chunk.Row) (int64, bool, error) {
 	return 0, hasNull, nil
 }
 
+// builtinInDecimalSig see https://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_in
+type builtinInDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinInDecimalSig) Clone() builtinFunc {
+	newSig := &builtinInDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinInDecimalSig) evalInt(row chunk.Row) (int64, bool, error) {
+	arg0, isNull0, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull0 || err != nil {
+		return 0, isNull0, err
+	}
+	var hasNull bool
+	for _, arg := range b.args[1:] {
+		evaledArg, isNull, err := arg.EvalDecimal(b.ctx, row)
+		if err != nil {
+			return 0, true, err
+		}
+		if isNull {
+			hasNull = true
+			continue
+		}
+		if arg0.Compare(evaledArg) == 0 {
+			return 1, false, nil
+		}
+	}
+	return 0, hasNull, nil
+}
+
+// builtinInTimeSig see https://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_in
+type builtinInTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinInTimeSig) Clone() builtinFunc {
+	newSig := &builtinInTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinInTimeSig) evalInt(row chunk.Row) (int64, bool, error) {
+	arg0, isNull0, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull0 || err != nil {
+		return 0, isNull0, err
+	}
+	var hasNull bool
+	for _, arg := range b.args[1:] {
+		evaledArg, isNull, err := arg.EvalTime(b.ctx, row)
+		if err != nil {
+			return 0, true, err
+		}
+		if isNull {
+			hasNull = true
+			continue
+		}
+		if arg0.Compare(evaledArg) == 0 {
+			return 1, false, nil
+		}
+	}
+	return 0, hasNull, nil
+}
+
+// builtinInDurationSig see https://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_in
+type builtinInDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinInDurationSig) Clone() builtinFunc {
+	newSig := &builtinInDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinInDurationSig) evalInt(row chunk.Row) (int64, bool, error) {
+	arg0, isNull0, err := b.args[0].EvalDuration(b.ctx, row)
+	if isNull0 || err != nil {
+		return 0, isNull0, err
+	}
+	var hasNull bool
+	for _, arg := range b.args[1:] {
+		evaledArg, isNull, err := arg.EvalDuration(b.ctx, row)
+		if err != nil {
+			return 0, true, err
+		}
+		if isNull {
+			hasNull = true
+			continue
+		}
+		if arg0.Compare(evaledArg) == 0 {
+			return 1, false, nil
+		}
+	}
+	return 0, hasNull, nil
+}
+
+// builtinInJSONSig see https://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_in
+type builtinInJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinInJSONSig) Clone() builtinFunc {
+	newSig := &builtinInJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinInJSONSig) evalInt(row chunk.Row) (int64, bool, error) {
+	arg0, isNull0, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull0 || err != nil {
+		return 0, isNull0, err
+	}
+	var hasNull bool
+	for _, arg := range b.args[1:] {
+		evaledArg, isNull, err := arg.EvalJSON(b.ctx, row)
+		if err != nil {
+			return 0, true, err
+		}
+		if isNull {
+			hasNull = true
+			continue
+		}
+		if json.CompareBinary(arg0, evaledArg) == 0 {
+			return 1, false, nil
+		}
+	}
+	return 0, hasNull, nil
+}
+
 type rowFunctionClass struct {
 	baseFunctionClass
 }
@@ -321,8 +477,16 @@ func (c *valuesFunctionClass) getFunction(ctx sessionctx.Context, args []Express
 		sig = &builtinValuesIntSig{bf, c.offset}
 	case types.ETReal:
 		sig = &builtinValuesRealSig{bf, c.offset}
+	case types.ETDecimal:
+		sig = &builtinValuesDecimalSig{bf, c.offset}
 	case types.ETString:
 		sig = &builtinValuesStringSig{bf, c.offset}
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinValuesTimeSig{bf, c.offset}
+	case types.ETDuration:
+		sig = &builtinValuesDurationSig{bf, c.offset}
+	case types.ETJson:
+		sig = &builtinValuesJSONSig{bf, c.offset}
 	}
 	return sig, nil
 }
@@ -392,6 +556,37 @@ func (b *builtinValuesRealSig) evalReal(_ chunk.Row) (float64, bool, error) {
 	return 0, true, errors.Errorf("Session current insert values len %d and column's offset %v don't match", row.Len(), b.offset)
 }
 
+type builtinValuesDecimalSig struct {
+	baseBuiltinFunc
+
+	offset int
+}
+
+func (b *builtinValuesDecimalSig) Clone() builtinFunc {
+	newSig := &builtinValuesDecimalSig{offset: b.offset}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals a builtinValuesDecimalSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/miscellaneous-functions.html#function_values
+func (b *builtinValuesDecimalSig) evalDecimal(_ chunk.Row) (*types.MyDecimal, bool, error) {
+	if !b.ctx.GetSessionVars().StmtCtx.InInsertStmt {
+		return nil, true, nil
+	}
+	row := b.ctx.GetSessionVars().CurrInsertValues
+	if row.IsEmpty() {
+		return nil, true, errors.New("Session current insert values is nil")
+	}
+	if b.offset < row.Len() {
+		if row.IsNull(b.offset) {
+			return nil, true, nil
+		}
+		return row.GetMyDecimal(b.offset), false, nil
+	}
+	return nil, true, errors.Errorf("Session current insert values len %d and column's offset %v don't match", row.Len(), b.offset)
+}
+
 type builtinValuesStringSig struct {
 	baseBuiltinFunc
 
@@ -431,3 +626,97 @@ func (b *builtinValuesStringSig) evalString(_ chunk.Row) (string, bool, error) {
 
 	return row.GetString(b.offset), false, nil
 }
+
+type builtinValuesTimeSig struct {
+	baseBuiltinFunc
+
+	offset int
+}
+
+func (b *builtinValuesTimeSig) Clone() builtinFunc {
+	newSig := &builtinValuesTimeSig{offset: b.offset}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals a builtinValuesTimeSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/miscellaneous-functions.html#function_values
+func (b *builtinValuesTimeSig) evalTime(_ chunk.Row) (types.Time, bool, error) {
+	if !b.ctx.GetSessionVars().StmtCtx.InInsertStmt {
+		return types.ZeroTime, true, nil
+	}
+	row := b.ctx.GetSessionVars().CurrInsertValues
+	if row.IsEmpty() {
+		return types.ZeroTime, true, errors.New("Session current insert values is nil")
+	}
+	if b.offset < row.Len() {
+		if row.IsNull(b.offset) {
+			return types.ZeroTime, true, nil
+		}
+		return row.GetTime(b.offset), false, nil
+	}
+	return types.ZeroTime, true, errors.Errorf("Session current insert values len %d and column's offset %v don't match", row.Len(), b.offset)
+}
+
+type builtinValuesDurationSig struct {
+	baseBuiltinFunc
+
+	offset int
+}
+
+func (b *builtinValuesDurationSig) Clone() builtinFunc {
+	newSig := &builtinValuesDurationSig{offset: b.offset}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDuration evals a builtinValuesDurationSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/miscellaneous-functions.html#function_values
+func (b *builtinValuesDurationSig) evalDuration(_ chunk.Row) (types.Duration, bool, error) {
+	if !b.ctx.GetSessionVars().StmtCtx.InInsertStmt {
+		return types.Duration{}, true, nil
+	}
+	row := b.ctx.GetSessionVars().CurrInsertValues
+	if row.IsEmpty() {
+		return types.Duration{}, true, errors.New("Session current insert values is nil")
+	}
+	if b.offset < row.Len() {
+		if row.IsNull(b.offset) {
+			return types.Duration{}, true, nil
+		}
+		duration := row.GetDuration(b.offset, b.getRetTp().Decimal)
+		return duration, false, nil
+	}
+	return types.Duration{}, true, errors.Errorf("Session current insert values len %d and column's offset %v don't match", row.Len(), b.offset)
+}
+
+type builtinValuesJSONSig struct {
+	baseBuiltinFunc
+
+	offset int
+}
+
+func (b *builtinValuesJSONSig) Clone() builtinFunc {
+	newSig := &builtinValuesJSONSig{offset: b.offset}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinValuesJSONSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/miscellaneous-functions.html#function_values
+func (b *builtinValuesJSONSig) evalJSON(_ chunk.Row) (json.BinaryJSON, bool, error) {
+	if !b.ctx.GetSessionVars().StmtCtx.InInsertStmt {
+		return json.BinaryJSON{}, true, nil
+	}
+	row := b.ctx.GetSessionVars().CurrInsertValues
+	if row.IsEmpty() {
+		return json.BinaryJSON{}, true, errors.New("Session current insert values is nil")
+	}
+	if b.offset < row.Len() {
+		if row.IsNull(b.offset) {
+			return json.BinaryJSON{}, true, nil
+		}
+		return row.GetJSON(b.offset), false, nil
+	}
+	return json.BinaryJSON{}, true, errors.Errorf("Session current insert values len %d and column's offset %v don't match", row.Len(), b.offset)
+}
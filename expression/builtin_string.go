@@ -18,8 +18,17 @@
 package expression
 
 import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tipb/go-tipb"
@@ -28,13 +37,74 @@ import (
 var (
 	_ functionClass = &lengthFunctionClass{}
 	_ functionClass = &strcmpFunctionClass{}
+	_ functionClass = &concatFunctionClass{}
+	_ functionClass = &concatWSFunctionClass{}
+	_ functionClass = &substringFunctionClass{}
+	_ functionClass = &leftFunctionClass{}
+	_ functionClass = &rightFunctionClass{}
+	_ functionClass = &upperFunctionClass{}
+	_ functionClass = &lowerFunctionClass{}
+	_ functionClass = &trimFunctionClass{}
+	_ functionClass = &lTrimFunctionClass{}
+	_ functionClass = &rTrimFunctionClass{}
+	_ functionClass = &replaceFunctionClass{}
+	_ functionClass = &locateFunctionClass{}
+	_ functionClass = &instrFunctionClass{}
+	_ functionClass = &lpadFunctionClass{}
+	_ functionClass = &rpadFunctionClass{}
+	_ functionClass = &repeatFunctionClass{}
+	_ functionClass = &reverseFunctionClass{}
+	_ functionClass = &hexFunctionClass{}
+	_ functionClass = &unhexFunctionClass{}
+	_ functionClass = &fieldFunctionClass{}
+	_ functionClass = &eltFunctionClass{}
 )
 
 var (
 	_ builtinFunc = &builtinLengthSig{}
 	_ builtinFunc = &builtinStrcmpSig{}
+	_ builtinFunc = &builtinConcatSig{}
+	_ builtinFunc = &builtinConcatWSSig{}
+	_ builtinFunc = &builtinSubstring2ArgsSig{}
+	_ builtinFunc = &builtinSubstring3ArgsSig{}
+	_ builtinFunc = &builtinSubstring2ArgsUTF8Sig{}
+	_ builtinFunc = &builtinSubstring3ArgsUTF8Sig{}
+	_ builtinFunc = &builtinLeftSig{}
+	_ builtinFunc = &builtinLeftUTF8Sig{}
+	_ builtinFunc = &builtinRightSig{}
+	_ builtinFunc = &builtinRightUTF8Sig{}
+	_ builtinFunc = &builtinUpperSig{}
+	_ builtinFunc = &builtinLowerSig{}
+	_ builtinFunc = &builtinTrim1ArgSig{}
+	_ builtinFunc = &builtinTrim2ArgsSig{}
+	_ builtinFunc = &builtinLTrimSig{}
+	_ builtinFunc = &builtinRTrimSig{}
+	_ builtinFunc = &builtinReplaceSig{}
+	_ builtinFunc = &builtinLocate2ArgsSig{}
+	_ builtinFunc = &builtinLocate3ArgsSig{}
+	_ builtinFunc = &builtinLocate2ArgsUTF8Sig{}
+	_ builtinFunc = &builtinLocate3ArgsUTF8Sig{}
+	_ builtinFunc = &builtinInstrSig{}
+	_ builtinFunc = &builtinInstrUTF8Sig{}
+	_ builtinFunc = &builtinLpadSig{}
+	_ builtinFunc = &builtinLpadUTF8Sig{}
+	_ builtinFunc = &builtinRpadSig{}
+	_ builtinFunc = &builtinRpadUTF8Sig{}
+	_ builtinFunc = &builtinRepeatSig{}
+	_ builtinFunc = &builtinReverseSig{}
+	_ builtinFunc = &builtinReverseUTF8Sig{}
+	_ builtinFunc = &builtinHexStrArgSig{}
+	_ builtinFunc = &builtinHexIntArgSig{}
+	_ builtinFunc = &builtinUnHexSig{}
+	_ builtinFunc = &builtinFieldIntSig{}
+	_ builtinFunc = &builtinFieldRealSig{}
+	_ builtinFunc = &builtinFieldStringSig{}
+	_ builtinFunc = &builtinEltSig{}
 )
 
+// spaceChars are the characters removed by TRIM, LTRIM and RTRIM by default.
+const spaceChars = " "
+
 // SetBinFlagOrBinStr sets resTp to binary string if argTp is a binary string,
 // if not, sets the binary flag of resTp to true if argTp has binary flag.
 func SetBinFlagOrBinStr(argTp *types.FieldType, resTp *types.FieldType) {
@@ -126,3 +196,1779 @@ func (b *builtinStrcmpSig) evalInt(row chunk.Row) (int64, bool, error) {
 	res := types.CompareString(left, right)
 	return int64(res), false, nil
 }
+
+// getMaxAllowedPacket returns the session value of max_allowed_packet, which
+// limits the length of the strings built by the string functions.
+func getMaxAllowedPacket(ctx sessionctx.Context) (uint64, error) {
+	valStr, _ := ctx.GetSessionVars().GetSystemVar(variable.MaxAllowedPacket)
+	return strconv.ParseUint(valStr, 10, 64)
+}
+
+// appendAllowedPacketOverflowedWarning appends the warning reported when the
+// result of a function exceeds max_allowed_packet, the result is NULL then.
+func appendAllowedPacketOverflowedWarning(ctx sessionctx.Context, funcName string, maxAllowedPacket uint64) {
+	ctx.GetSessionVars().StmtCtx.AppendWarning(errWarnAllowedPacketOverflowed.GenWithStackByArgs(funcName, maxAllowedPacket))
+}
+
+type concatFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *concatFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for range args {
+		argTps = append(argTps, types.ETString)
+	}
+	wrapArgsWithCast(ctx, args, argTps...)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, argTps...)
+	bf.tp.Flen = 0
+	for _, arg := range args {
+		argType := arg.GetType()
+		SetBinFlagOrBinStr(argType, bf.tp)
+		if argType.Flen < 0 {
+			bf.tp.Flen = mysql.MaxBlobWidth
+		} else {
+			bf.tp.Flen += argType.Flen
+		}
+	}
+	if bf.tp.Flen >= mysql.MaxBlobWidth {
+		bf.tp.Flen = mysql.MaxBlobWidth
+	}
+	maxAllowedPacket, err := getMaxAllowedPacket(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinConcatSig{bf, maxAllowedPacket}
+	sig.setPbCode(tipb.ScalarFuncSig_Concat)
+	return sig, nil
+}
+
+type builtinConcatSig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinConcatSig) Clone() builtinFunc {
+	newSig := &builtinConcatSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	newSig.maxAllowedPacket = b.maxAllowedPacket
+	return newSig
+}
+
+// evalString evals a builtinConcatSig
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_concat
+func (b *builtinConcatSig) evalString(row chunk.Row) (string, bool, error) {
+	var s []byte
+	for _, arg := range b.args {
+		d, isNull, err := arg.EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return d, isNull, err
+		}
+		if uint64(len(s)+len(d)) > b.maxAllowedPacket {
+			appendAllowedPacketOverflowedWarning(b.ctx, "concat", b.maxAllowedPacket)
+			return "", true, nil
+		}
+		s = append(s, d...)
+	}
+	return string(s), false, nil
+}
+
+type concatWSFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *concatWSFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for range args {
+		argTps = append(argTps, types.ETString)
+	}
+	wrapArgsWithCast(ctx, args, argTps...)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, argTps...)
+	bf.tp.Flen = 0
+	for i, arg := range args {
+		argType := arg.GetType()
+		SetBinFlagOrBinStr(argType, bf.tp)
+		if i == 0 {
+			continue
+		}
+		if argType.Flen < 0 {
+			bf.tp.Flen = mysql.MaxBlobWidth
+		} else {
+			bf.tp.Flen += argType.Flen
+		}
+	}
+	// The separator appears between every two strings.
+	if sepFlen := args[0].GetType().Flen; sepFlen < 0 {
+		bf.tp.Flen = mysql.MaxBlobWidth
+	} else {
+		bf.tp.Flen += (len(args) - 2) * sepFlen
+	}
+	if bf.tp.Flen >= mysql.MaxBlobWidth {
+		bf.tp.Flen = mysql.MaxBlobWidth
+	}
+	maxAllowedPacket, err := getMaxAllowedPacket(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinConcatWSSig{bf, maxAllowedPacket}
+	sig.setPbCode(tipb.ScalarFuncSig_ConcatWS)
+	return sig, nil
+}
+
+type builtinConcatWSSig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinConcatWSSig) Clone() builtinFunc {
+	newSig := &builtinConcatWSSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	newSig.maxAllowedPacket = b.maxAllowedPacket
+	return newSig
+}
+
+// evalString evals a builtinConcatWSSig.
+// The NULL arguments after the separator are skipped.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_concat-ws
+func (b *builtinConcatWSSig) evalString(row chunk.Row) (string, bool, error) {
+	sep, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	strs := make([]string, 0, len(b.args)-1)
+	var targetLength int
+	for _, arg := range b.args[1:] {
+		val, isNull, err := arg.EvalString(b.ctx, row)
+		if err != nil {
+			return "", true, err
+		}
+		if isNull {
+			continue
+		}
+		targetLength += len(val)
+		if len(strs) > 0 {
+			targetLength += len(sep)
+		}
+		if uint64(targetLength) > b.maxAllowedPacket {
+			appendAllowedPacketOverflowedWarning(b.ctx, "concat_ws", b.maxAllowedPacket)
+			return "", true, nil
+		}
+		strs = append(strs, val)
+	}
+	return strings.Join(strs, sep), false, nil
+}
+
+type substringFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *substringFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETInt}
+	if len(args) == 3 {
+		argTps = append(argTps, types.ETInt)
+	}
+	wrapArgsWithCast(ctx, args, argTps...)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, argTps...)
+	argType := args[0].GetType()
+	bf.tp.Flen = argType.Flen
+	SetBinFlagOrBinStr(argType, bf.tp)
+
+	var sig builtinFunc
+	switch {
+	case len(args) == 3 && types.IsBinaryStr(argType):
+		sig = &builtinSubstring3ArgsSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Substring3Args)
+	case len(args) == 3:
+		sig = &builtinSubstring3ArgsUTF8Sig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Substring3ArgsUTF8)
+	case types.IsBinaryStr(argType):
+		sig = &builtinSubstring2ArgsSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Substring2Args)
+	default:
+		sig = &builtinSubstring2ArgsUTF8Sig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Substring2ArgsUTF8)
+	}
+	return sig, nil
+}
+
+// substringStartPos returns the 0-based start position of SUBSTRING in a
+// string of the given length. A negative pos counts from the end of the
+// string, the start position equals the length if pos is out of range.
+func substringStartPos(pos, length int64) int64 {
+	if pos < 0 {
+		pos += length
+	} else {
+		pos--
+	}
+	if pos > length || pos < 0 {
+		pos = length
+	}
+	return pos
+}
+
+// substringEndPos returns the 0-based end position of SUBSTRING, which
+// starts at start and has at most subLen characters.
+func substringEndPos(start, subLen, length int64) int64 {
+	if subLen <= 0 {
+		return start
+	}
+	if subLen > length-start {
+		return length
+	}
+	return start + subLen
+}
+
+type builtinSubstring2ArgsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSubstring2ArgsSig) Clone() builtinFunc {
+	newSig := &builtinSubstring2ArgsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals SUBSTR(str,pos), SUBSTR(str FROM pos) for binary strings.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_substr
+func (b *builtinSubstring2ArgsSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pos, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return str[substringStartPos(pos, int64(len(str))):], false, nil
+}
+
+type builtinSubstring3ArgsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSubstring3ArgsSig) Clone() builtinFunc {
+	newSig := &builtinSubstring3ArgsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals SUBSTR(str,pos,len), SUBSTR(str FROM pos FOR len) for binary strings.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_substr
+func (b *builtinSubstring3ArgsSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pos, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	subLen, isNull, err := b.args[2].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	length := int64(len(str))
+	start := substringStartPos(pos, length)
+	return str[start:substringEndPos(start, subLen, length)], false, nil
+}
+
+type builtinSubstring2ArgsUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSubstring2ArgsUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinSubstring2ArgsUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals SUBSTR(str,pos), SUBSTR(str FROM pos) for non-binary strings.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_substr
+func (b *builtinSubstring2ArgsUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pos, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	runes := []rune(str)
+	return string(runes[substringStartPos(pos, int64(len(runes))):]), false, nil
+}
+
+type builtinSubstring3ArgsUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSubstring3ArgsUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinSubstring3ArgsUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals SUBSTR(str,pos,len), SUBSTR(str FROM pos FOR len) for non-binary strings.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_substr
+func (b *builtinSubstring3ArgsUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pos, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	subLen, isNull, err := b.args[2].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	runes := []rune(str)
+	length := int64(len(runes))
+	start := substringStartPos(pos, length)
+	return string(runes[start:substringEndPos(start, subLen, length)]), false, nil
+}
+
+// leftRightLength returns the number of characters taken by LEFT and RIGHT
+// from a string of the given length.
+func leftRightLength(n, length int64) int64 {
+	if n < 0 {
+		return 0
+	}
+	if n > length {
+		return length
+	}
+	return n
+}
+
+type leftFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *leftFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	wrapArgsWithCast(ctx, args, types.ETString, types.ETInt)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETInt)
+	argType := args[0].GetType()
+	bf.tp.Flen = argType.Flen
+	SetBinFlagOrBinStr(argType, bf.tp)
+	if types.IsBinaryStr(argType) {
+		sig := &builtinLeftSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Left)
+		return sig, nil
+	}
+	sig := &builtinLeftUTF8Sig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_LeftUTF8)
+	return sig, nil
+}
+
+type builtinLeftSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeftSig) Clone() builtinFunc {
+	newSig := &builtinLeftSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals LEFT(str,len) for binary strings.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_left
+func (b *builtinLeftSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	n, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return str[:leftRightLength(n, int64(len(str)))], false, nil
+}
+
+type builtinLeftUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeftUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinLeftUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals LEFT(str,len) for non-binary strings.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_left
+func (b *builtinLeftUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	n, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	runes := []rune(str)
+	return string(runes[:leftRightLength(n, int64(len(runes)))]), false, nil
+}
+
+type rightFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *rightFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	wrapArgsWithCast(ctx, args, types.ETString, types.ETInt)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETInt)
+	argType := args[0].GetType()
+	bf.tp.Flen = argType.Flen
+	SetBinFlagOrBinStr(argType, bf.tp)
+	if types.IsBinaryStr(argType) {
+		sig := &builtinRightSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Right)
+		return sig, nil
+	}
+	sig := &builtinRightUTF8Sig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_RightUTF8)
+	return sig, nil
+}
+
+type builtinRightSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRightSig) Clone() builtinFunc {
+	newSig := &builtinRightSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals RIGHT(str,len) for binary strings.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_right
+func (b *builtinRightSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	n, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	length := int64(len(str))
+	return str[length-leftRightLength(n, length):], false, nil
+}
+
+type builtinRightUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRightUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinRightUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals RIGHT(str,len) for non-binary strings.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_right
+func (b *builtinRightUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	n, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	runes := []rune(str)
+	length := int64(len(runes))
+	return string(runes[length-leftRightLength(n, length):]), false, nil
+}
+
+type upperFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *upperFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	wrapArgsWithCast(ctx, args, types.ETString)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+	argTp := args[0].GetType()
+	bf.tp.Flen = argTp.Flen
+	SetBinFlagOrBinStr(argTp, bf.tp)
+	sig := &builtinUpperSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Upper)
+	return sig, nil
+}
+
+type builtinUpperSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUpperSig) Clone() builtinFunc {
+	newSig := &builtinUpperSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinUpperSig.
+// Binary strings are returned unchanged.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_upper
+func (b *builtinUpperSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	if types.IsBinaryStr(b.args[0].GetType()) {
+		return str, false, nil
+	}
+	return strings.ToUpper(str), false, nil
+}
+
+type lowerFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *lowerFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	wrapArgsWithCast(ctx, args, types.ETString)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+	argTp := args[0].GetType()
+	bf.tp.Flen = argTp.Flen
+	SetBinFlagOrBinStr(argTp, bf.tp)
+	sig := &builtinLowerSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Lower)
+	return sig, nil
+}
+
+type builtinLowerSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLowerSig) Clone() builtinFunc {
+	newSig := &builtinLowerSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinLowerSig.
+// Binary strings are returned unchanged.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_lower
+func (b *builtinLowerSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	if types.IsBinaryStr(b.args[0].GetType()) {
+		return str, false, nil
+	}
+	return strings.ToLower(str), false, nil
+}
+
+// trimLeft removes all the leading remstr of str.
+func trimLeft(str, remstr string) string {
+	if len(remstr) == 0 {
+		return str
+	}
+	for strings.HasPrefix(str, remstr) {
+		str = str[len(remstr):]
+	}
+	return str
+}
+
+// trimRight removes all the trailing remstr of str.
+func trimRight(str, remstr string) string {
+	if len(remstr) == 0 {
+		return str
+	}
+	for strings.HasSuffix(str, remstr) {
+		str = str[:len(str)-len(remstr)]
+	}
+	return str
+}
+
+type trimFunctionClass struct {
+	baseFunctionClass
+}
+
+// getFunction sets trim built-in function signature.
+// The syntax of trim in mysql is 'TRIM([{BOTH | LEADING | TRAILING} [remstr] FROM] str), TRIM([remstr FROM] str)',
+// only the forms without the direction are supported, so both the leading
+// and trailing remstr are removed.
+func (c *trimFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for range args {
+		argTps = append(argTps, types.ETString)
+	}
+	wrapArgsWithCast(ctx, args, argTps...)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, argTps...)
+	argType := args[0].GetType()
+	bf.tp.Flen = argType.Flen
+	SetBinFlagOrBinStr(argType, bf.tp)
+	if len(args) == 1 {
+		sig := &builtinTrim1ArgSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Trim1Arg)
+		return sig, nil
+	}
+	sig := &builtinTrim2ArgsSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Trim2Args)
+	return sig, nil
+}
+
+type builtinTrim1ArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTrim1ArgSig) Clone() builtinFunc {
+	newSig := &builtinTrim1ArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinTrim1ArgSig, corresponding to trim(str)
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_trim
+func (b *builtinTrim1ArgSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return strings.Trim(str, spaceChars), false, nil
+}
+
+type builtinTrim2ArgsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTrim2ArgsSig) Clone() builtinFunc {
+	newSig := &builtinTrim2ArgsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinTrim2ArgsSig, corresponding to trim(remstr from str)
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_trim
+func (b *builtinTrim2ArgsSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	remstr, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return trimRight(trimLeft(str, remstr), remstr), false, nil
+}
+
+type lTrimFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *lTrimFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	wrapArgsWithCast(ctx, args, types.ETString)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+	argType := args[0].GetType()
+	bf.tp.Flen = argType.Flen
+	SetBinFlagOrBinStr(argType, bf.tp)
+	sig := &builtinLTrimSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_LTrim)
+	return sig, nil
+}
+
+type builtinLTrimSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLTrimSig) Clone() builtinFunc {
+	newSig := &builtinLTrimSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinLTrimSig
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_ltrim
+func (b *builtinLTrimSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return strings.TrimLeft(str, spaceChars), false, nil
+}
+
+type rTrimFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *rTrimFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	wrapArgsWithCast(ctx, args, types.ETString)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+	argType := args[0].GetType()
+	bf.tp.Flen = argType.Flen
+	SetBinFlagOrBinStr(argType, bf.tp)
+	sig := &builtinRTrimSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_RTrim)
+	return sig, nil
+}
+
+type builtinRTrimSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRTrimSig) Clone() builtinFunc {
+	newSig := &builtinRTrimSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinRTrimSig
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_rtrim
+func (b *builtinRTrimSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return strings.TrimRight(str, spaceChars), false, nil
+}
+
+type replaceFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *replaceFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	wrapArgsWithCast(ctx, args, types.ETString, types.ETString, types.ETString)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETString, types.ETString)
+	bf.tp.Flen = c.fixLength(args)
+	for _, a := range args {
+		SetBinFlagOrBinStr(a.GetType(), bf.tp)
+	}
+	sig := &builtinReplaceSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Replace)
+	return sig, nil
+}
+
+// fixLength calculate the Flen of the return type.
+func (c *replaceFunctionClass) fixLength(args []Expression) int {
+	charLen := args[0].GetType().Flen
+	oldStrLen := args[1].GetType().Flen
+	diff := args[2].GetType().Flen - oldStrLen
+	if diff > 0 && oldStrLen > 0 {
+		charLen += (charLen / oldStrLen) * diff
+	}
+	if charLen < 0 || charLen > mysql.MaxBlobWidth {
+		charLen = mysql.MaxBlobWidth
+	}
+	return charLen
+}
+
+type builtinReplaceSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinReplaceSig) Clone() builtinFunc {
+	newSig := &builtinReplaceSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinReplaceSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_replace
+func (b *builtinReplaceSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	oldStr, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	newStr, isNull, err := b.args[2].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	if oldStr == "" {
+		return str, false, nil
+	}
+	return strings.Replace(str, oldStr, newStr, -1), false, nil
+}
+
+// locateBinary returns the 1-based byte position of the first occurrence of
+// subStr in str, the search starts at the 1-based position pos. It returns 0
+// if subStr is not found or pos is out of range.
+func locateBinary(subStr, str string, pos int64) int64 {
+	if pos < 1 || pos > int64(len(str)+1) {
+		return 0
+	}
+	idx := strings.Index(str[pos-1:], subStr)
+	if idx == -1 {
+		return 0
+	}
+	return pos + int64(idx)
+}
+
+// locateUTF8 returns the 1-based character position of the first occurrence
+// of subStr in str, the search starts at the 1-based position pos and is
+// case-insensitive. It returns 0 if subStr is not found or pos is out of range.
+func locateUTF8(subStr, str string, pos int64) int64 {
+	runes := []rune(str)
+	if pos < 1 || pos > int64(len(runes)+1) {
+		return 0
+	}
+	slice := strings.ToLower(string(runes[pos-1:]))
+	idx := strings.Index(slice, strings.ToLower(subStr))
+	if idx == -1 {
+		return 0
+	}
+	return pos + int64(utf8.RuneCountInString(slice[:idx]))
+}
+
+type locateFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *locateFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	hasStartPos, argTps := len(args) == 3, []types.EvalType{types.ETString, types.ETString}
+	if hasStartPos {
+		argTps = append(argTps, types.ETInt)
+	}
+	wrapArgsWithCast(ctx, args, argTps...)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argTps...)
+	var sig builtinFunc
+	// Loacte is multibyte safe, and is case-sensitive only if at least one argument is a binary string.
+	if types.IsBinaryStr(args[0].GetType()) || types.IsBinaryStr(args[1].GetType()) {
+		if hasStartPos {
+			sig = &builtinLocate3ArgsSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_Locate3Args)
+		} else {
+			sig = &builtinLocate2ArgsSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_Locate2Args)
+		}
+	} else {
+		if hasStartPos {
+			sig = &builtinLocate3ArgsUTF8Sig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_Locate3ArgsUTF8)
+		} else {
+			sig = &builtinLocate2ArgsUTF8Sig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_Locate2ArgsUTF8)
+		}
+	}
+	return sig, nil
+}
+
+type builtinLocate2ArgsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLocate2ArgsSig) Clone() builtinFunc {
+	newSig := &builtinLocate2ArgsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals LOCATE(substr,str), case-sensitive.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_locate
+func (b *builtinLocate2ArgsSig) evalInt(row chunk.Row) (int64, bool, error) {
+	subStr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	str, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return locateBinary(subStr, str, 1), false, nil
+}
+
+type builtinLocate3ArgsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLocate3ArgsSig) Clone() builtinFunc {
+	newSig := &builtinLocate3ArgsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals LOCATE(substr,str,pos), case-sensitive.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_locate
+func (b *builtinLocate3ArgsSig) evalInt(row chunk.Row) (int64, bool, error) {
+	subStr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	str, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	pos, isNull, err := b.args[2].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return locateBinary(subStr, str, pos), false, nil
+}
+
+type builtinLocate2ArgsUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLocate2ArgsUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinLocate2ArgsUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals LOCATE(substr,str), non case-sensitive.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_locate
+func (b *builtinLocate2ArgsUTF8Sig) evalInt(row chunk.Row) (int64, bool, error) {
+	subStr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	str, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return locateUTF8(subStr, str, 1), false, nil
+}
+
+type builtinLocate3ArgsUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLocate3ArgsUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinLocate3ArgsUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals LOCATE(substr,str,pos), non case-sensitive.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_locate
+func (b *builtinLocate3ArgsUTF8Sig) evalInt(row chunk.Row) (int64, bool, error) {
+	subStr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	str, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	pos, isNull, err := b.args[2].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return locateUTF8(subStr, str, pos), false, nil
+}
+
+type instrFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *instrFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	wrapArgsWithCast(ctx, args, types.ETString, types.ETString)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETString, types.ETString)
+	bf.tp.Flen = 11
+	if types.IsBinaryStr(bf.args[0].GetType()) || types.IsBinaryStr(bf.args[1].GetType()) {
+		sig := &builtinInstrSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Instr)
+		return sig, nil
+	}
+	sig := &builtinInstrUTF8Sig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_InstrUTF8)
+	return sig, nil
+}
+
+type builtinInstrSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinInstrSig) Clone() builtinFunc {
+	newSig := &builtinInstrSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals INSTR(str,substr), case-sensitive.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_instr
+func (b *builtinInstrSig) evalInt(row chunk.Row) (int64, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	subStr, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return locateBinary(subStr, str, 1), false, nil
+}
+
+type builtinInstrUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinInstrUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinInstrUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals INSTR(str,substr), non case-sensitive.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_instr
+func (b *builtinInstrUTF8Sig) evalInt(row chunk.Row) (int64, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	subStr, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return locateUTF8(subStr, str, 1), false, nil
+}
+
+// getFlen4LpadAndRpad returns the Flen of the result of LPAD and RPAD, which
+// is the length argument if it is a constant.
+func getFlen4LpadAndRpad(ctx sessionctx.Context, arg Expression) int {
+	if constant, ok := arg.(*Constant); ok {
+		length, isNull, err := constant.EvalInt(ctx, chunk.Row{})
+		if isNull || err != nil || length < 0 || length > mysql.MaxBlobWidth {
+			return mysql.MaxBlobWidth
+		}
+		return int(length)
+	}
+	return mysql.MaxBlobWidth
+}
+
+// padString pads str with padStr on the left or right side until its length
+// is targetLength, each element of str and padStr is a byte or a character.
+// It returns false if the result can not be built, the result is NULL then.
+func padString(str, padStr []rune, targetLength int, left bool) ([]rune, bool) {
+	if targetLength <= len(str) {
+		return str[:targetLength], true
+	}
+	if len(padStr) == 0 {
+		return nil, false
+	}
+	tailLen := targetLength - len(str)
+	pad := make([]rune, 0, tailLen)
+	for len(pad) < tailLen {
+		pad = append(pad, padStr...)
+	}
+	pad = pad[:tailLen]
+	if left {
+		return append(pad, str...), true
+	}
+	return append(str, pad...), true
+}
+
+// bytesToRunes widens every byte of s into a rune, so padString can handle
+// binary strings byte by byte.
+func bytesToRunes(s string) []rune {
+	runes := make([]rune, len(s))
+	for i := 0; i < len(s); i++ {
+		runes[i] = rune(s[i])
+	}
+	return runes
+}
+
+// runesToBytes narrows the runes built by bytesToRunes back to bytes.
+func runesToBytes(runes []rune) string {
+	bs := make([]byte, len(runes))
+	for i, r := range runes {
+		bs[i] = byte(r)
+	}
+	return string(bs)
+}
+
+type lpadFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *lpadFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	wrapArgsWithCast(ctx, args, types.ETString, types.ETInt, types.ETString)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETInt, types.ETString)
+	bf.tp.Flen = getFlen4LpadAndRpad(ctx, args[1])
+	SetBinFlagOrBinStr(args[0].GetType(), bf.tp)
+	SetBinFlagOrBinStr(args[2].GetType(), bf.tp)
+	maxAllowedPacket, err := getMaxAllowedPacket(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if types.IsBinaryStr(args[0].GetType()) || types.IsBinaryStr(args[2].GetType()) {
+		sig := &builtinLpadSig{bf, maxAllowedPacket}
+		sig.setPbCode(tipb.ScalarFuncSig_Lpad)
+		return sig, nil
+	}
+	sig := &builtinLpadUTF8Sig{bf, maxAllowedPacket}
+	sig.setPbCode(tipb.ScalarFuncSig_LpadUTF8)
+	return sig, nil
+}
+
+// evalPadArgs evaluates the arguments of LPAD and RPAD, isNull is true if
+// any of them is NULL or the target length is invalid.
+func evalPadArgs(ctx sessionctx.Context, args []Expression, row chunk.Row, funcName string, maxAllowedPacket uint64) (str string, length int64, padStr string, isNull bool, err error) {
+	str, isNull, err = args[0].EvalString(ctx, row)
+	if isNull || err != nil {
+		return "", 0, "", true, err
+	}
+	length, isNull, err = args[1].EvalInt(ctx, row)
+	if isNull || err != nil {
+		return "", 0, "", true, err
+	}
+	if length < 0 {
+		return "", 0, "", true, nil
+	}
+	if uint64(length) > maxAllowedPacket {
+		appendAllowedPacketOverflowedWarning(ctx, funcName, maxAllowedPacket)
+		return "", 0, "", true, nil
+	}
+	padStr, isNull, err = args[2].EvalString(ctx, row)
+	if isNull || err != nil {
+		return "", 0, "", true, err
+	}
+	return str, length, padStr, false, nil
+}
+
+type builtinLpadSig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinLpadSig) Clone() builtinFunc {
+	newSig := &builtinLpadSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	newSig.maxAllowedPacket = b.maxAllowedPacket
+	return newSig
+}
+
+// evalString evals LPAD(str,len,padstr) for binary strings.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_lpad
+func (b *builtinLpadSig) evalString(row chunk.Row) (string, bool, error) {
+	str, length, padStr, isNull, err := evalPadArgs(b.ctx, b.args, row, "lpad", b.maxAllowedPacket)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	res, ok := padString(bytesToRunes(str), bytesToRunes(padStr), int(length), true)
+	if !ok {
+		return "", true, nil
+	}
+	return runesToBytes(res), false, nil
+}
+
+type builtinLpadUTF8Sig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinLpadUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinLpadUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	newSig.maxAllowedPacket = b.maxAllowedPacket
+	return newSig
+}
+
+// evalString evals LPAD(str,len,padstr) for non-binary strings.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_lpad
+func (b *builtinLpadUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, length, padStr, isNull, err := evalPadArgs(b.ctx, b.args, row, "lpad", b.maxAllowedPacket)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	res, ok := padString([]rune(str), []rune(padStr), int(length), true)
+	if !ok {
+		return "", true, nil
+	}
+	return string(res), false, nil
+}
+
+type rpadFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *rpadFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	wrapArgsWithCast(ctx, args, types.ETString, types.ETInt, types.ETString)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETInt, types.ETString)
+	bf.tp.Flen = getFlen4LpadAndRpad(ctx, args[1])
+	SetBinFlagOrBinStr(args[0].GetType(), bf.tp)
+	SetBinFlagOrBinStr(args[2].GetType(), bf.tp)
+	maxAllowedPacket, err := getMaxAllowedPacket(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if types.IsBinaryStr(args[0].GetType()) || types.IsBinaryStr(args[2].GetType()) {
+		sig := &builtinRpadSig{bf, maxAllowedPacket}
+		sig.setPbCode(tipb.ScalarFuncSig_Rpad)
+		return sig, nil
+	}
+	sig := &builtinRpadUTF8Sig{bf, maxAllowedPacket}
+	sig.setPbCode(tipb.ScalarFuncSig_RpadUTF8)
+	return sig, nil
+}
+
+type builtinRpadSig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinRpadSig) Clone() builtinFunc {
+	newSig := &builtinRpadSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	newSig.maxAllowedPacket = b.maxAllowedPacket
+	return newSig
+}
+
+// evalString evals RPAD(str,len,padstr) for binary strings.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_rpad
+func (b *builtinRpadSig) evalString(row chunk.Row) (string, bool, error) {
+	str, length, padStr, isNull, err := evalPadArgs(b.ctx, b.args, row, "rpad", b.maxAllowedPacket)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	res, ok := padString(bytesToRunes(str), bytesToRunes(padStr), int(length), false)
+	if !ok {
+		return "", true, nil
+	}
+	return runesToBytes(res), false, nil
+}
+
+type builtinRpadUTF8Sig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinRpadUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinRpadUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	newSig.maxAllowedPacket = b.maxAllowedPacket
+	return newSig
+}
+
+// evalString evals RPAD(str,len,padstr) for non-binary strings.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_rpad
+func (b *builtinRpadUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, length, padStr, isNull, err := evalPadArgs(b.ctx, b.args, row, "rpad", b.maxAllowedPacket)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	res, ok := padString([]rune(str), []rune(padStr), int(length), false)
+	if !ok {
+		return "", true, nil
+	}
+	return string(res), false, nil
+}
+
+type repeatFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *repeatFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	wrapArgsWithCast(ctx, args, types.ETString, types.ETInt)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETInt)
+	bf.tp.Flen = mysql.MaxBlobWidth
+	SetBinFlagOrBinStr(args[0].GetType(), bf.tp)
+	maxAllowedPacket, err := getMaxAllowedPacket(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinRepeatSig{bf, maxAllowedPacket}
+	sig.setPbCode(tipb.ScalarFuncSig_Repeat)
+	return sig, nil
+}
+
+type builtinRepeatSig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinRepeatSig) Clone() builtinFunc {
+	newSig := &builtinRepeatSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	newSig.maxAllowedPacket = b.maxAllowedPacket
+	return newSig
+}
+
+// repeatString repeats str num times, it returns false if the result is
+// longer than maxAllowedPacket.
+func repeatString(str string, num int64, maxAllowedPacket uint64) (string, bool) {
+	if num < 1 {
+		return "", true
+	}
+	if num > math.MaxInt32 {
+		num = math.MaxInt32
+	}
+	if int64(len(str)) > int64(maxAllowedPacket)/num {
+		return "", false
+	}
+	return strings.Repeat(str, int(num)), true
+}
+
+// evalString evals a builtinRepeatSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_repeat
+func (b *builtinRepeatSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	num, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	res, ok := repeatString(str, num, b.maxAllowedPacket)
+	if !ok {
+		appendAllowedPacketOverflowedWarning(b.ctx, "repeat", b.maxAllowedPacket)
+		return "", true, nil
+	}
+	return res, false, nil
+}
+
+type reverseFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *reverseFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	wrapArgsWithCast(ctx, args, types.ETString)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+	argTp := args[0].GetType()
+	bf.tp.Flen = argTp.Flen
+	SetBinFlagOrBinStr(argTp, bf.tp)
+	if types.IsBinaryStr(argTp) {
+		sig := &builtinReverseSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Reverse)
+		return sig, nil
+	}
+	sig := &builtinReverseUTF8Sig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_ReverseUTF8)
+	return sig, nil
+}
+
+// reverseBytes reverses the bytes of origin in place.
+func reverseBytes(origin []byte) []byte {
+	for i, length := 0, len(origin); i < length/2; i++ {
+		origin[i], origin[length-i-1] = origin[length-i-1], origin[i]
+	}
+	return origin
+}
+
+// reverseRunes reverses the runes of origin in place.
+func reverseRunes(origin []rune) []rune {
+	for i, length := 0, len(origin); i < length/2; i++ {
+		origin[i], origin[length-i-1] = origin[length-i-1], origin[i]
+	}
+	return origin
+}
+
+type builtinReverseSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinReverseSig) Clone() builtinFunc {
+	newSig := &builtinReverseSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals REVERSE(str) for binary strings.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_reverse
+func (b *builtinReverseSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return string(reverseBytes([]byte(str))), false, nil
+}
+
+type builtinReverseUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinReverseUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinReverseUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals REVERSE(str) for non-binary strings.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_reverse
+func (b *builtinReverseUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return string(reverseRunes([]rune(str))), false, nil
+}
+
+type hexFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *hexFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argFlen := args[0].GetType().Flen
+	switch args[0].GetType().EvalType() {
+	case types.ETString, types.ETDatetime, types.ETTimestamp, types.ETDuration, types.ETJson:
+		wrapArgsWithCast(ctx, args, types.ETString)
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+		// Use UTF8MB4 as default.
+		bf.tp.Flen = argFlen * 4 * 2
+		if argFlen < 0 || bf.tp.Flen > mysql.MaxBlobWidth {
+			bf.tp.Flen = mysql.MaxBlobWidth
+		}
+		sig := &builtinHexStrArgSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_HexStrArg)
+		return sig, nil
+	case types.ETInt, types.ETReal, types.ETDecimal:
+		wrapArgsWithCast(ctx, args, types.ETInt)
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETInt)
+		bf.tp.Flen = argFlen * 2
+		if argFlen < 0 || bf.tp.Flen > mysql.MaxBlobWidth {
+			bf.tp.Flen = mysql.MaxBlobWidth
+		}
+		sig := &builtinHexIntArgSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_HexIntArg)
+		return sig, nil
+	default:
+		return nil, errors.Errorf("Hex invalid args, need int or string but get %T", args[0].GetType())
+	}
+}
+
+type builtinHexStrArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinHexStrArgSig) Clone() builtinFunc {
+	newSig := &builtinHexStrArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinHexStrArgSig, corresponding to hex(str)
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_hex
+func (b *builtinHexStrArgSig) evalString(row chunk.Row) (string, bool, error) {
+	d, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return d, isNull, err
+	}
+	return strings.ToUpper(hex.EncodeToString([]byte(d))), false, nil
+}
+
+type builtinHexIntArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinHexIntArgSig) Clone() builtinFunc {
+	newSig := &builtinHexIntArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinHexIntArgSig, corresponding to hex(N)
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_hex
+func (b *builtinHexIntArgSig) evalString(row chunk.Row) (string, bool, error) {
+	x, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return strings.ToUpper(fmt.Sprintf("%x", uint64(x))), false, nil
+}
+
+type unhexFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *unhexFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	var retFlen int
+	argType := args[0].GetType()
+	switch argType.EvalType() {
+	case types.ETString, types.ETDatetime, types.ETTimestamp, types.ETDuration, types.ETJson:
+		// Use UTF8MB4 as default charset, so there're (Flen * 4 + 1) / 2 byte-pairs.
+		retFlen = (argType.Flen*4 + 1) / 2
+	case types.ETInt, types.ETReal, types.ETDecimal:
+		// For number value, there're (Flen + 1) / 2 byte-pairs.
+		retFlen = (argType.Flen + 1) / 2
+	default:
+		return nil, errors.Errorf("Unhex invalid args, need int or string but get %s", argType)
+	}
+	if argType.Flen < 0 {
+		retFlen = mysql.MaxBlobWidth
+	}
+	wrapArgsWithCast(ctx, args, types.ETString)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+	bf.tp.Flen = retFlen
+	types.SetBinChsClnFlag(bf.tp)
+	sig := &builtinUnHexSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_UnHex)
+	return sig, nil
+}
+
+type builtinUnHexSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUnHexSig) Clone() builtinFunc {
+	newSig := &builtinUnHexSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// unhexString decodes the hexadecimal string d, it returns false if d
+// contains any non-hexadecimal digit.
+func unhexString(d string) (string, bool) {
+	// Add a '0' to the front, if the length is not the multiple of 2.
+	if len(d)%2 != 0 {
+		d = "0" + d
+	}
+	bs, err := hex.DecodeString(d)
+	if err != nil {
+		return "", false
+	}
+	return string(bs), true
+}
+
+// evalString evals a builtinUnHexSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_unhex
+func (b *builtinUnHexSig) evalString(row chunk.Row) (string, bool, error) {
+	d, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return d, isNull, err
+	}
+	res, ok := unhexString(d)
+	if !ok {
+		return "", true, nil
+	}
+	return res, false, nil
+}
+
+type fieldFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *fieldFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+
+	isAllString, isAllNumber := true, true
+	for _, arg := range args {
+		argTp := arg.GetType().EvalType()
+		isAllString = isAllString && (argTp == types.ETString)
+		isAllNumber = isAllNumber && (argTp == types.ETInt)
+	}
+
+	// If all arguments to FIELD() are strings, all arguments are compared as
+	// strings. If all arguments are numbers, they are compared as numbers.
+	// Otherwise, the arguments are compared as double.
+	argTp := types.ETReal
+	if isAllString {
+		argTp = types.ETString
+	} else if isAllNumber {
+		argTp = types.ETInt
+	}
+	argTps := make([]types.EvalType, len(args))
+	for i := range args {
+		argTps[i] = argTp
+	}
+	wrapArgsWithCast(ctx, args, argTps...)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argTps...)
+	var sig builtinFunc
+	switch argTp {
+	case types.ETReal:
+		sig = &builtinFieldRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_FieldReal)
+	case types.ETInt:
+		sig = &builtinFieldIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_FieldInt)
+	case types.ETString:
+		sig = &builtinFieldStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_FieldString)
+	}
+	return sig, nil
+}
+
+type builtinFieldIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinFieldIntSig) Clone() builtinFunc {
+	newSig := &builtinFieldIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinFieldIntSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_field
+func (b *builtinFieldIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	str, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, err != nil, err
+	}
+	for i, length := 1, len(b.args); i < length; i++ {
+		stri, isNull, err := b.args[i].EvalInt(b.ctx, row)
+		if err != nil {
+			return 0, true, err
+		}
+		if !isNull && str == stri {
+			return int64(i), false, nil
+		}
+	}
+	return 0, false, nil
+}
+
+type builtinFieldRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinFieldRealSig) Clone() builtinFunc {
+	newSig := &builtinFieldRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinFieldRealSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_field
+func (b *builtinFieldRealSig) evalInt(row chunk.Row) (int64, bool, error) {
+	str, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, err != nil, err
+	}
+	for i, length := 1, len(b.args); i < length; i++ {
+		stri, isNull, err := b.args[i].EvalReal(b.ctx, row)
+		if err != nil {
+			return 0, true, err
+		}
+		if !isNull && str == stri {
+			return int64(i), false, nil
+		}
+	}
+	return 0, false, nil
+}
+
+type builtinFieldStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinFieldStringSig) Clone() builtinFunc {
+	newSig := &builtinFieldStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinFieldStringSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_field
+func (b *builtinFieldStringSig) evalInt(row chunk.Row) (int64, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, err != nil, err
+	}
+	for i, length := 1, len(b.args); i < length; i++ {
+		stri, isNull, err := b.args[i].EvalString(b.ctx, row)
+		if err != nil {
+			return 0, true, err
+		}
+		if !isNull && str == stri {
+			return int64(i), false, nil
+		}
+	}
+	return 0, false, nil
+}
+
+type eltFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *eltFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if argsErr := c.verifyArgs(args); argsErr != nil {
+		return nil, argsErr
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	argTps = append(argTps, types.ETInt)
+	for i := 1; i < len(args); i++ {
+		argTps = append(argTps, types.ETString)
+	}
+	wrapArgsWithCast(ctx, args, argTps...)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, argTps...)
+	for _, arg := range args[1:] {
+		argType := arg.GetType()
+		if types.IsBinaryStr(argType) {
+			types.SetBinChsClnFlag(bf.tp)
+		}
+		if argType.Flen > bf.tp.Flen {
+			bf.tp.Flen = argType.Flen
+		}
+	}
+	sig := &builtinEltSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Elt)
+	return sig, nil
+}
+
+type builtinEltSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinEltSig) Clone() builtinFunc {
+	newSig := &builtinEltSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a ELT(N,str1,str2,str3,...).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_elt
+func (b *builtinEltSig) evalString(row chunk.Row) (string, bool, error) {
+	idx, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	if idx < 1 || idx >= int64(len(b.args)) {
+		return "", true, nil
+	}
+	arg, isNull, err := b.args[idx].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return arg, false, nil
+}
@@ -17,6 +17,8 @@ import (
 	. "github.com/pingcap/check"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 )
@@ -98,3 +100,222 @@ func (s *testEvaluatorSuite) TestStrcmp(c *C) {
 		}
 	}
 }
+
+func (s *testEvaluatorSuite) TestConcatAndConcatWS(c *C) {
+	cases := []struct {
+		fn     string
+		args   []interface{}
+		isNil  bool
+		getErr bool
+		res    string
+	}{
+		{ast.Concat, []interface{}{nil}, true, false, ""},
+		{ast.Concat, []interface{}{"a", "b", 1, 2, 1.1, 1.2}, false, false, "ab121.11.2"},
+		{ast.Concat, []interface{}{"a", nil, "b"}, true, false, ""},
+		{ast.Concat, []interface{}{errors.New("must error")}, false, true, ""},
+		{ast.ConcatWS, []interface{}{",", "a", "b"}, false, false, "a,b"},
+		{ast.ConcatWS, []interface{}{",", "a", nil, "b", nil}, false, false, "a,b"},
+		{ast.ConcatWS, []interface{}{",", nil, nil}, false, false, ""},
+		{ast.ConcatWS, []interface{}{nil, "a", "b"}, true, false, ""},
+		{ast.ConcatWS, []interface{}{"--", 1, 1.5, "c"}, false, false, "1--1.5--c"},
+		{ast.ConcatWS, []interface{}{",", errors.New("must error")}, false, true, ""},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, t.fn, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		if t.getErr {
+			c.Assert(err, NotNil)
+		} else {
+			c.Assert(err, IsNil)
+			if t.isNil {
+				c.Assert(d.Kind(), Equals, types.KindNull)
+			} else {
+				c.Assert(d.GetString(), Equals, t.res)
+			}
+		}
+	}
+}
+
+func (s *testEvaluatorSuite) TestConcatOverflow(c *C) {
+	defer func(val string) {
+		c.Assert(s.ctx.GetSessionVars().SetSystemVar(variable.MaxAllowedPacket, val), IsNil)
+	}(s.getMaxAllowedPacket())
+	c.Assert(s.ctx.GetSessionVars().SetSystemVar(variable.MaxAllowedPacket, "10"), IsNil)
+
+	for _, fn := range []string{ast.Concat, ast.Repeat} {
+		args := []interface{}{"0123456789", "a"}
+		if fn == ast.Repeat {
+			args = []interface{}{"0123", 3}
+		}
+		warnCnt := len(s.ctx.GetSessionVars().StmtCtx.GetWarnings())
+		f, err := newFunctionForTest(s.ctx, fn, s.primitiveValsToConstants(args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d.IsNull(), IsTrue)
+		warnings := s.ctx.GetSessionVars().StmtCtx.GetWarnings()
+		c.Assert(len(warnings), Equals, warnCnt+1)
+		c.Assert(terror.ErrorEqual(errWarnAllowedPacketOverflowed, warnings[len(warnings)-1].Err), IsTrue)
+	}
+}
+
+func (s *testEvaluatorSuite) getMaxAllowedPacket() string {
+	val, _ := s.ctx.GetSessionVars().GetSystemVar(variable.MaxAllowedPacket)
+	return val
+}
+
+func (s *testEvaluatorSuite) TestSubstringLeftRight(c *C) {
+	cases := []struct {
+		fn    string
+		args  []interface{}
+		isNil bool
+		res   string
+	}{
+		{ast.Substring, []interface{}{"Quadratically", 5}, false, "ratically"},
+		{ast.Substring, []interface{}{"Sakila", -3}, false, "ila"},
+		{ast.Substring, []interface{}{"Sakila", 0}, false, ""},
+		{ast.Substring, []interface{}{"Sakila", 7}, false, ""},
+		{ast.Substring, []interface{}{"Quadratically", 5, 6}, false, "ratica"},
+		{ast.Substring, []interface{}{"Sakila", -5, 3}, false, "aki"},
+		{ast.Substring, []interface{}{"Sakila", 2, -1}, false, ""},
+		{ast.Substring, []interface{}{"Sakila", 2, 100}, false, "akila"},
+		{ast.Substring, []interface{}{"中文字符串", 2, 3}, false, "文字符"},
+		{ast.Substring, []interface{}{"Sakila", nil}, true, ""},
+		{ast.Substr, []interface{}{123456, 2, 2}, false, "23"},
+		{ast.Mid, []interface{}{"Sakila", 3, 2}, false, "ki"},
+		{ast.Left, []interface{}{"foobarbar", 5}, false, "fooba"},
+		{ast.Left, []interface{}{"foobarbar", -1}, false, ""},
+		{ast.Left, []interface{}{"foobarbar", 100}, false, "foobarbar"},
+		{ast.Left, []interface{}{"中文字符串", 2}, false, "中文"},
+		{ast.Left, []interface{}{nil, 1}, true, ""},
+		{ast.Right, []interface{}{"foobarbar", 4}, false, "rbar"},
+		{ast.Right, []interface{}{"foobarbar", -1}, false, ""},
+		{ast.Right, []interface{}{"foobarbar", 100}, false, "foobarbar"},
+		{ast.Right, []interface{}{"中文字符串", 2}, false, "符串"},
+		{ast.Right, []interface{}{"foobarbar", nil}, true, ""},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, t.fn, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		if t.isNil {
+			c.Assert(d.Kind(), Equals, types.KindNull)
+		} else {
+			c.Assert(d.GetString(), Equals, t.res, Commentf("%s%v", t.fn, t.args))
+		}
+	}
+}
+
+func (s *testEvaluatorSuite) TestStringTransform(c *C) {
+	cases := []struct {
+		fn    string
+		args  []interface{}
+		isNil bool
+		res   string
+	}{
+		{ast.Upper, []interface{}{"abc中文"}, false, "ABC中文"},
+		{ast.Ucase, []interface{}{1.5}, false, "1.5"},
+		{ast.Lower, []interface{}{"ABC"}, false, "abc"},
+		{ast.Lcase, []interface{}{nil}, true, ""},
+		{ast.Trim, []interface{}{"  bar   "}, false, "bar"},
+		{ast.Trim, []interface{}{"xxxbarxxx", "x"}, false, "bar"},
+		{ast.Trim, []interface{}{"xyxbarxyx", "xy"}, false, "xbarxyx"},
+		{ast.Trim, []interface{}{"bar", ""}, false, "bar"},
+		{ast.Trim, []interface{}{"bar", nil}, true, ""},
+		{ast.LTrim, []interface{}{"  barbar  "}, false, "barbar  "},
+		{ast.RTrim, []interface{}{"  barbar  "}, false, "  barbar"},
+		{ast.Replace, []interface{}{"www.mysql.com", "w", "Ww"}, false, "WwWwWw.mysql.com"},
+		{ast.Replace, []interface{}{"www.mysql.com", "", "x"}, false, "www.mysql.com"},
+		{ast.Replace, []interface{}{1234, 2, 55}, false, "15534"},
+		{ast.Replace, []interface{}{"abc", nil, "x"}, true, ""},
+		{ast.Reverse, []interface{}{"abc"}, false, "cba"},
+		{ast.Reverse, []interface{}{"中文"}, false, "文中"},
+		{ast.Reverse, []interface{}{123}, false, "321"},
+		{ast.Reverse, []interface{}{nil}, true, ""},
+		{ast.Repeat, []interface{}{"ab", 3}, false, "ababab"},
+		{ast.Repeat, []interface{}{"ab", 0}, false, ""},
+		{ast.Repeat, []interface{}{"ab", -1}, false, ""},
+		{ast.Repeat, []interface{}{"ab", nil}, true, ""},
+		{ast.Lpad, []interface{}{"hi", 4, "??"}, false, "??hi"},
+		{ast.Lpad, []interface{}{"hi", 5, "ab"}, false, "abahi"},
+		{ast.Lpad, []interface{}{"hi", 1, "??"}, false, "h"},
+		{ast.Lpad, []interface{}{"中文", 4, "字"}, false, "字字中文"},
+		{ast.Lpad, []interface{}{"hi", -1, "??"}, true, ""},
+		{ast.Lpad, []interface{}{"hi", 5, ""}, true, ""},
+		{ast.Rpad, []interface{}{"hi", 5, "?"}, false, "hi???"},
+		{ast.Rpad, []interface{}{"hi", 5, "ab"}, false, "hiaba"},
+		{ast.Rpad, []interface{}{"hi", 1, "?"}, false, "h"},
+		{ast.Rpad, []interface{}{"hi", 2, ""}, false, "hi"},
+		{ast.Rpad, []interface{}{nil, 2, "?"}, true, ""},
+		{ast.Hex, []interface{}{"abc"}, false, "616263"},
+		{ast.Hex, []interface{}{255}, false, "FF"},
+		{ast.Hex, []interface{}{-1}, false, "FFFFFFFFFFFFFFFF"},
+		{ast.Hex, []interface{}{nil}, true, ""},
+		{ast.Unhex, []interface{}{"4D7953514C"}, false, "MySQL"},
+		{ast.Unhex, []interface{}{"123"}, false, "\x01\x23"},
+		{ast.Unhex, []interface{}{"GG"}, true, ""},
+		{ast.Unhex, []interface{}{nil}, true, ""},
+		{ast.Elt, []interface{}{1, "Aa", "Bb", "Cc"}, false, "Aa"},
+		{ast.Elt, []interface{}{3, "Aa", "Bb", "Cc"}, false, "Cc"},
+		{ast.Elt, []interface{}{0, "Aa", "Bb", "Cc"}, true, ""},
+		{ast.Elt, []interface{}{4, "Aa", "Bb", "Cc"}, true, ""},
+		{ast.Elt, []interface{}{"2", 1, 2}, false, "2"},
+		{ast.Elt, []interface{}{nil, "Aa"}, true, ""},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, t.fn, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		if t.isNil {
+			c.Assert(d.Kind(), Equals, types.KindNull, Commentf("%s%v", t.fn, t.args))
+		} else {
+			c.Assert(d.GetString(), Equals, t.res, Commentf("%s%v", t.fn, t.args))
+		}
+	}
+}
+
+func (s *testEvaluatorSuite) TestLocateInstrField(c *C) {
+	cases := []struct {
+		fn    string
+		args  []interface{}
+		isNil bool
+		res   int64
+	}{
+		{ast.Locate, []interface{}{"bar", "foobarbar"}, false, 4},
+		{ast.Locate, []interface{}{"xbar", "foobar"}, false, 0},
+		{ast.Locate, []interface{}{"bar", "foobarbar", 5}, false, 7},
+		{ast.Locate, []interface{}{"BAR", "foobarbar"}, false, 4},
+		{ast.Locate, []interface{}{"", "foobarbar"}, false, 1},
+		{ast.Locate, []interface{}{"", "foobarbar", 3}, false, 3},
+		{ast.Locate, []interface{}{"", "foobarbar", 11}, false, 0},
+		{ast.Locate, []interface{}{"bar", "foobarbar", 0}, false, 0},
+		{ast.Locate, []interface{}{"字", "中文字符串"}, false, 3},
+		{ast.Locate, []interface{}{"bar", nil}, true, 0},
+		{ast.Position, []interface{}{"bar", "foobarbar"}, false, 4},
+		{ast.Instr, []interface{}{"foobarbar", "bar"}, false, 4},
+		{ast.Instr, []interface{}{"xbar", "foobar"}, false, 0},
+		{ast.Instr, []interface{}{"中文字符串", "符"}, false, 4},
+		{ast.Instr, []interface{}{123456, 34}, false, 3},
+		{ast.Instr, []interface{}{nil, "bar"}, true, 0},
+		{ast.Field, []interface{}{"Bb", "Aa", "Bb", "Cc", "Dd", "Ff"}, false, 2},
+		{ast.Field, []interface{}{"Gg", "Aa", "Bb", "Cc", "Dd", "Ff"}, false, 0},
+		{ast.Field, []interface{}{2, 1, 2, 3}, false, 2},
+		{ast.Field, []interface{}{1.5, "1.5", 2}, false, 1},
+		{ast.Field, []interface{}{"a", nil, "a"}, false, 2},
+		{ast.Field, []interface{}{nil, nil, "a"}, false, 0},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, t.fn, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		if t.isNil {
+			c.Assert(d.Kind(), Equals, types.KindNull, Commentf("%s%v", t.fn, t.args))
+		} else {
+			c.Assert(d.GetInt64(), Equals, t.res, Commentf("%s%v", t.fn, t.args))
+		}
+	}
+}
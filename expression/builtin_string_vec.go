@@ -14,6 +14,10 @@
 package expression
 
 import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 )
@@ -109,3 +113,1073 @@ func (b *builtinLengthSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column)
 	}
 	return nil
 }
+
+func (b *builtinConcatSig) vectorized() bool {
+	return true
+}
+
+// vecEvalString evals a CONCAT(str1,str2,...)
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_concat
+func (b *builtinConcatSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+
+	strs := make([][]byte, n)
+	isNulls := make([]bool, n)
+	for _, arg := range b.args {
+		if err := arg.VecEvalString(b.ctx, input, buf); err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			if isNulls[i] {
+				continue
+			}
+			if buf.IsNull(i) {
+				isNulls[i] = true
+				continue
+			}
+			str := buf.GetBytes(i)
+			if uint64(len(strs[i])+len(str)) > b.maxAllowedPacket {
+				appendAllowedPacketOverflowedWarning(b.ctx, "concat", b.maxAllowedPacket)
+				isNulls[i] = true
+				continue
+			}
+			strs[i] = append(strs[i], str...)
+		}
+	}
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if isNulls[i] {
+			result.AppendNull()
+		} else {
+			result.AppendBytes(strs[i])
+		}
+	}
+	return nil
+}
+
+func (b *builtinConcatWSSig) vectorized() bool {
+	return true
+}
+
+// vecEvalString evals a CONCAT_WS(separator,str1,str2,...).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_concat-ws
+func (b *builtinConcatWSSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	sepBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(sepBuf)
+	if err := b.args[0].VecEvalString(b.ctx, input, sepBuf); err != nil {
+		return err
+	}
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+
+	strs := make([][]byte, n)
+	hasStr := make([]bool, n)
+	isNulls := make([]bool, n)
+	for i := 0; i < n; i++ {
+		isNulls[i] = sepBuf.IsNull(i)
+	}
+	for _, arg := range b.args[1:] {
+		if err := arg.VecEvalString(b.ctx, input, buf); err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			if isNulls[i] || buf.IsNull(i) {
+				continue
+			}
+			sep, str := sepBuf.GetBytes(i), buf.GetBytes(i)
+			targetLength := len(strs[i]) + len(str)
+			if hasStr[i] {
+				targetLength += len(sep)
+			}
+			if uint64(targetLength) > b.maxAllowedPacket {
+				appendAllowedPacketOverflowedWarning(b.ctx, "concat_ws", b.maxAllowedPacket)
+				isNulls[i] = true
+				continue
+			}
+			if hasStr[i] {
+				strs[i] = append(strs[i], sep...)
+			}
+			strs[i] = append(strs[i], str...)
+			hasStr[i] = true
+		}
+	}
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if isNulls[i] {
+			result.AppendNull()
+		} else {
+			result.AppendBytes(strs[i])
+		}
+	}
+	return nil
+}
+
+func (b *builtinSubstring2ArgsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSubstring2ArgsSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	posBuf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(posBuf)
+	if err := b.args[1].VecEvalInt(b.ctx, input, posBuf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	poses := posBuf.Int64s()
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) || posBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		str := buf.GetString(i)
+		result.AppendString(str[substringStartPos(poses[i], int64(len(str))):])
+	}
+	return nil
+}
+
+func (b *builtinSubstring3ArgsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSubstring3ArgsSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	posBuf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(posBuf)
+	if err := b.args[1].VecEvalInt(b.ctx, input, posBuf); err != nil {
+		return err
+	}
+	lenBuf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(lenBuf)
+	if err := b.args[2].VecEvalInt(b.ctx, input, lenBuf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	poses, lens := posBuf.Int64s(), lenBuf.Int64s()
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) || posBuf.IsNull(i) || lenBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		str := buf.GetString(i)
+		length := int64(len(str))
+		start := substringStartPos(poses[i], length)
+		result.AppendString(str[start:substringEndPos(start, lens[i], length)])
+	}
+	return nil
+}
+
+func (b *builtinSubstring2ArgsUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSubstring2ArgsUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	posBuf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(posBuf)
+	if err := b.args[1].VecEvalInt(b.ctx, input, posBuf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	poses := posBuf.Int64s()
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) || posBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		runes := []rune(buf.GetString(i))
+		result.AppendString(string(runes[substringStartPos(poses[i], int64(len(runes))):]))
+	}
+	return nil
+}
+
+func (b *builtinSubstring3ArgsUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSubstring3ArgsUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	posBuf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(posBuf)
+	if err := b.args[1].VecEvalInt(b.ctx, input, posBuf); err != nil {
+		return err
+	}
+	lenBuf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(lenBuf)
+	if err := b.args[2].VecEvalInt(b.ctx, input, lenBuf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	poses, lens := posBuf.Int64s(), lenBuf.Int64s()
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) || posBuf.IsNull(i) || lenBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		runes := []rune(buf.GetString(i))
+		length := int64(len(runes))
+		start := substringStartPos(poses[i], length)
+		result.AppendString(string(runes[start:substringEndPos(start, lens[i], length)]))
+	}
+	return nil
+}
+
+func (b *builtinLeftSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLeftSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	lenBuf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(lenBuf)
+	if err := b.args[1].VecEvalInt(b.ctx, input, lenBuf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	lens := lenBuf.Int64s()
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) || lenBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		str := buf.GetString(i)
+		result.AppendString(str[:leftRightLength(lens[i], int64(len(str)))])
+	}
+	return nil
+}
+
+func (b *builtinLeftUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLeftUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	lenBuf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(lenBuf)
+	if err := b.args[1].VecEvalInt(b.ctx, input, lenBuf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	lens := lenBuf.Int64s()
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) || lenBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		runes := []rune(buf.GetString(i))
+		result.AppendString(string(runes[:leftRightLength(lens[i], int64(len(runes)))]))
+	}
+	return nil
+}
+
+func (b *builtinRightSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRightSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	lenBuf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(lenBuf)
+	if err := b.args[1].VecEvalInt(b.ctx, input, lenBuf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	lens := lenBuf.Int64s()
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) || lenBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		str := buf.GetString(i)
+		length := int64(len(str))
+		result.AppendString(str[length-leftRightLength(lens[i], length):])
+	}
+	return nil
+}
+
+func (b *builtinRightUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRightUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	lenBuf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(lenBuf)
+	if err := b.args[1].VecEvalInt(b.ctx, input, lenBuf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	lens := lenBuf.Int64s()
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) || lenBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		runes := []rune(buf.GetString(i))
+		length := int64(len(runes))
+		result.AppendString(string(runes[length-leftRightLength(lens[i], length):]))
+	}
+	return nil
+}
+
+// vecEvalStringTransform evaluates the only string argument of a built-in
+// function and appends fn(str) for every non-NULL row to result.
+func vecEvalStringTransform(b *baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column, fn func(string) string) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(fn(buf.GetString(i)))
+	}
+	return nil
+}
+
+func (b *builtinUpperSig) vectorized() bool {
+	return true
+}
+
+// vecEvalString evals a builtinUpperSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_upper
+func (b *builtinUpperSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	if types.IsBinaryStr(b.args[0].GetType()) {
+		return b.args[0].VecEvalString(b.ctx, input, result)
+	}
+	return vecEvalStringTransform(&b.baseBuiltinFunc, input, result, strings.ToUpper)
+}
+
+func (b *builtinLowerSig) vectorized() bool {
+	return true
+}
+
+// vecEvalString evals a builtinLowerSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_lower
+func (b *builtinLowerSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	if types.IsBinaryStr(b.args[0].GetType()) {
+		return b.args[0].VecEvalString(b.ctx, input, result)
+	}
+	return vecEvalStringTransform(&b.baseBuiltinFunc, input, result, strings.ToLower)
+}
+
+func (b *builtinTrim1ArgSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTrim1ArgSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalStringTransform(&b.baseBuiltinFunc, input, result, func(str string) string {
+		return strings.Trim(str, spaceChars)
+	})
+}
+
+func (b *builtinTrim2ArgsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTrim2ArgsSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	remBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(remBuf)
+	if err := b.args[1].VecEvalString(b.ctx, input, remBuf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) || remBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		remstr := remBuf.GetString(i)
+		result.AppendString(trimRight(trimLeft(buf.GetString(i), remstr), remstr))
+	}
+	return nil
+}
+
+func (b *builtinLTrimSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLTrimSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalStringTransform(&b.baseBuiltinFunc, input, result, func(str string) string {
+		return strings.TrimLeft(str, spaceChars)
+	})
+}
+
+func (b *builtinRTrimSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRTrimSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalStringTransform(&b.baseBuiltinFunc, input, result, func(str string) string {
+		return strings.TrimRight(str, spaceChars)
+	})
+}
+
+func (b *builtinReplaceSig) vectorized() bool {
+	return true
+}
+
+// vecEvalString evals a builtinReplaceSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_replace
+func (b *builtinReplaceSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	oldBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(oldBuf)
+	if err := b.args[1].VecEvalString(b.ctx, input, oldBuf); err != nil {
+		return err
+	}
+	newBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(newBuf)
+	if err := b.args[2].VecEvalString(b.ctx, input, newBuf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) || oldBuf.IsNull(i) || newBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		str, oldStr := buf.GetString(i), oldBuf.GetString(i)
+		if oldStr == "" {
+			result.AppendString(str)
+			continue
+		}
+		result.AppendString(strings.Replace(str, oldStr, newBuf.GetString(i), -1))
+	}
+	return nil
+}
+
+// vecEvalLocate evaluates the arguments of LOCATE(substr,str[,pos]) and sets
+// locate(substr, str, pos) for every row to result, pos is 1 if it is absent.
+func vecEvalLocate(b *baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column, subStrIdx, strIdx int, locate func(subStr, str string, pos int64) int64) error {
+	n := input.NumRows()
+	subBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(subBuf)
+	if err := b.args[subStrIdx].VecEvalString(b.ctx, input, subBuf); err != nil {
+		return err
+	}
+	strBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(strBuf)
+	if err := b.args[strIdx].VecEvalString(b.ctx, input, strBuf); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	i64s := result.Int64s()
+	var poses []int64
+	if len(b.args) == 3 {
+		posBuf, err := b.bufAllocator.get(types.ETInt, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(posBuf)
+		if err := b.args[2].VecEvalInt(b.ctx, input, posBuf); err != nil {
+			return err
+		}
+		result.MergeNulls(subBuf, strBuf, posBuf)
+		poses = posBuf.Int64s()
+	} else {
+		result.MergeNulls(subBuf, strBuf)
+	}
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		pos := int64(1)
+		if poses != nil {
+			pos = poses[i]
+		}
+		i64s[i] = locate(subBuf.GetString(i), strBuf.GetString(i), pos)
+	}
+	return nil
+}
+
+func (b *builtinLocate2ArgsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLocate2ArgsSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalLocate(&b.baseBuiltinFunc, input, result, 0, 1, locateBinary)
+}
+
+func (b *builtinLocate3ArgsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLocate3ArgsSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalLocate(&b.baseBuiltinFunc, input, result, 0, 1, locateBinary)
+}
+
+func (b *builtinLocate2ArgsUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLocate2ArgsUTF8Sig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalLocate(&b.baseBuiltinFunc, input, result, 0, 1, locateUTF8)
+}
+
+func (b *builtinLocate3ArgsUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLocate3ArgsUTF8Sig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalLocate(&b.baseBuiltinFunc, input, result, 0, 1, locateUTF8)
+}
+
+func (b *builtinInstrSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinInstrSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalLocate(&b.baseBuiltinFunc, input, result, 1, 0, locateBinary)
+}
+
+func (b *builtinInstrUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinInstrUTF8Sig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalLocate(&b.baseBuiltinFunc, input, result, 1, 0, locateUTF8)
+}
+
+// vecEvalPad evaluates LPAD(str,len,padstr) or RPAD(str,len,padstr), isBinary
+// tells whether the strings are padded byte by byte or character by character.
+func vecEvalPad(b *baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column, funcName string, maxAllowedPacket uint64, isBinary, left bool) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	lenBuf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(lenBuf)
+	if err := b.args[1].VecEvalInt(b.ctx, input, lenBuf); err != nil {
+		return err
+	}
+	padBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(padBuf)
+	if err := b.args[2].VecEvalString(b.ctx, input, padBuf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	lens := lenBuf.Int64s()
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) || lenBuf.IsNull(i) || padBuf.IsNull(i) || lens[i] < 0 {
+			result.AppendNull()
+			continue
+		}
+		if uint64(lens[i]) > maxAllowedPacket {
+			appendAllowedPacketOverflowedWarning(b.ctx, funcName, maxAllowedPacket)
+			result.AppendNull()
+			continue
+		}
+		if isBinary {
+			res, ok := padString(bytesToRunes(buf.GetString(i)), bytesToRunes(padBuf.GetString(i)), int(lens[i]), left)
+			if !ok {
+				result.AppendNull()
+				continue
+			}
+			result.AppendString(runesToBytes(res))
+			continue
+		}
+		res, ok := padString([]rune(buf.GetString(i)), []rune(padBuf.GetString(i)), int(lens[i]), left)
+		if !ok {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(string(res))
+	}
+	return nil
+}
+
+func (b *builtinLpadSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLpadSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalPad(&b.baseBuiltinFunc, input, result, "lpad", b.maxAllowedPacket, true, true)
+}
+
+func (b *builtinLpadUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLpadUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalPad(&b.baseBuiltinFunc, input, result, "lpad", b.maxAllowedPacket, false, true)
+}
+
+func (b *builtinRpadSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRpadSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalPad(&b.baseBuiltinFunc, input, result, "rpad", b.maxAllowedPacket, true, false)
+}
+
+func (b *builtinRpadUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRpadUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalPad(&b.baseBuiltinFunc, input, result, "rpad", b.maxAllowedPacket, false, false)
+}
+
+func (b *builtinRepeatSig) vectorized() bool {
+	return true
+}
+
+// vecEvalString evals a builtinRepeatSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_repeat
+func (b *builtinRepeatSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	numBuf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(numBuf)
+	if err := b.args[1].VecEvalInt(b.ctx, input, numBuf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	nums := numBuf.Int64s()
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) || numBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		res, ok := repeatString(buf.GetString(i), nums[i], b.maxAllowedPacket)
+		if !ok {
+			appendAllowedPacketOverflowedWarning(b.ctx, "repeat", b.maxAllowedPacket)
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+func (b *builtinReverseSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinReverseSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalStringTransform(&b.baseBuiltinFunc, input, result, func(str string) string {
+		return string(reverseBytes([]byte(str)))
+	})
+}
+
+func (b *builtinReverseUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinReverseUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalStringTransform(&b.baseBuiltinFunc, input, result, func(str string) string {
+		return string(reverseRunes([]rune(str)))
+	})
+}
+
+func (b *builtinHexStrArgSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinHexStrArgSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalStringTransform(&b.baseBuiltinFunc, input, result, func(str string) string {
+		return strings.ToUpper(hex.EncodeToString([]byte(str)))
+	})
+}
+
+func (b *builtinHexIntArgSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinHexIntArgSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalInt(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	i64s := buf.Int64s()
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(strings.ToUpper(strconv.FormatUint(uint64(i64s[i]), 16)))
+	}
+	return nil
+}
+
+func (b *builtinUnHexSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUnHexSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		res, ok := unhexString(buf.GetString(i))
+		if !ok {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+func (b *builtinFieldIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinFieldIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf0, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf0)
+	if err := b.args[0].VecEvalInt(b.ctx, input, buf0); err != nil {
+		return err
+	}
+	buf1, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf1)
+
+	result.ResizeInt64(n, false)
+	i64s, args0 := result.Int64s(), buf0.Int64s()
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalInt(b.ctx, input, buf1); err != nil {
+			return err
+		}
+		args1 := buf1.Int64s()
+		for i := 0; i < n; i++ {
+			if i64s[i] > 0 || buf0.IsNull(i) || buf1.IsNull(i) {
+				continue
+			}
+			if args0[i] == args1[i] {
+				i64s[i] = int64(j)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinFieldRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinFieldRealSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf0, err := b.bufAllocator.get(types.ETReal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf0)
+	if err := b.args[0].VecEvalReal(b.ctx, input, buf0); err != nil {
+		return err
+	}
+	buf1, err := b.bufAllocator.get(types.ETReal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf1)
+
+	result.ResizeInt64(n, false)
+	i64s, args0 := result.Int64s(), buf0.Float64s()
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalReal(b.ctx, input, buf1); err != nil {
+			return err
+		}
+		args1 := buf1.Float64s()
+		for i := 0; i < n; i++ {
+			if i64s[i] > 0 || buf0.IsNull(i) || buf1.IsNull(i) {
+				continue
+			}
+			if args0[i] == args1[i] {
+				i64s[i] = int64(j)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinFieldStringSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinFieldStringSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf0, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf0)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf0); err != nil {
+		return err
+	}
+	buf1, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf1)
+
+	result.ResizeInt64(n, false)
+	i64s := result.Int64s()
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalString(b.ctx, input, buf1); err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			if i64s[i] > 0 || buf0.IsNull(i) || buf1.IsNull(i) {
+				continue
+			}
+			if buf0.GetString(i) == buf1.GetString(i) {
+				i64s[i] = int64(j)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinEltSig) vectorized() bool {
+	return true
+}
+
+// vecEvalString evals a ELT(N,str1,str2,str3,...).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_elt
+func (b *builtinEltSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	idxBuf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(idxBuf)
+	if err := b.args[0].VecEvalInt(b.ctx, input, idxBuf); err != nil {
+		return err
+	}
+	argBufs := make([]*chunk.Column, len(b.args)-1)
+	for j := range argBufs {
+		argBuf, err := b.bufAllocator.get(types.ETString, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(argBuf)
+		if err := b.args[j+1].VecEvalString(b.ctx, input, argBuf); err != nil {
+			return err
+		}
+		argBufs[j] = argBuf
+	}
+
+	result.ReserveString(n)
+	idxes := idxBuf.Int64s()
+	for i := 0; i < n; i++ {
+		if idxBuf.IsNull(i) || idxes[i] < 1 || idxes[i] > int64(len(argBufs)) {
+			result.AppendNull()
+			continue
+		}
+		argBuf := argBufs[idxes[i]-1]
+		if argBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(argBuf.GetString(i))
+	}
+	return nil
+}
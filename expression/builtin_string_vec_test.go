@@ -44,6 +44,96 @@ var vecBuiltinStringCases = map[string][]vecExprBenchCase{
 			},
 		}},
 	},
+	ast.Concat: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString}},
+	},
+	ast.ConcatWS: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString, types.ETString}},
+	},
+	ast.Substring: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt},
+			geners: []dataGenerator{&randLenStrGener{0, 20}, &rangeInt64Gener{-25, 25}}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt, types.ETInt},
+			geners: []dataGenerator{&randLenStrGener{0, 20}, &rangeInt64Gener{-25, 25}, &rangeInt64Gener{-25, 25}}},
+	},
+	ast.Left: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt},
+			geners: []dataGenerator{&randLenStrGener{0, 20}, &rangeInt64Gener{-10, 20}}},
+	},
+	ast.Right: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt},
+			geners: []dataGenerator{&randLenStrGener{0, 20}, &rangeInt64Gener{-10, 20}}},
+	},
+	ast.Upper: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}},
+	},
+	ast.Lower: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}},
+	},
+	ast.Trim: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString},
+			geners: []dataGenerator{&selectStringGener{[]string{"  a b  ", "ab ", " ab", ""}}}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners: []dataGenerator{&selectStringGener{[]string{"xxaxx", "xxxab", "abx", "x"}}, &selectStringGener{[]string{"x", "xx", ""}}}},
+	},
+	ast.LTrim: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString},
+			geners: []dataGenerator{&selectStringGener{[]string{"  a b  ", "ab ", " ab", ""}}}},
+	},
+	ast.RTrim: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString},
+			geners: []dataGenerator{&selectStringGener{[]string{"  a b  ", "ab ", " ab", ""}}}},
+	},
+	ast.Replace: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString},
+			geners: []dataGenerator{&selectStringGener{[]string{"www.mysql.com", "abcabc", ""}}, &selectStringGener{[]string{"w", "bc", ""}}}},
+	},
+	ast.Locate: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners: []dataGenerator{&selectStringGener{[]string{"bar", "BAR", ""}}, &selectStringGener{[]string{"foobarbar", "xbar", ""}}}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt},
+			geners: []dataGenerator{&selectStringGener{[]string{"bar", "BAR", ""}}, &selectStringGener{[]string{"foobarbar", "xbar", ""}}, &rangeInt64Gener{-2, 12}}},
+	},
+	ast.Instr: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners: []dataGenerator{&selectStringGener{[]string{"foobarbar", "xbar", ""}}, &selectStringGener{[]string{"bar", "BAR", ""}}}},
+	},
+	ast.Lpad: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt, types.ETString},
+			geners: []dataGenerator{&randLenStrGener{0, 10}, &rangeInt64Gener{-5, 20}, &randLenStrGener{0, 3}}},
+	},
+	ast.Rpad: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt, types.ETString},
+			geners: []dataGenerator{&randLenStrGener{0, 10}, &rangeInt64Gener{-5, 20}, &randLenStrGener{0, 3}}},
+	},
+	ast.Repeat: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt},
+			geners: []dataGenerator{&randLenStrGener{0, 10}, &rangeInt64Gener{-5, 10}}},
+	},
+	ast.Reverse: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}},
+	},
+	ast.Hex: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETInt}},
+	},
+	ast.Unhex: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString},
+			geners: []dataGenerator{&selectStringGener{[]string{"4D7953514C", "123", "abc", "GG", ""}}}},
+	},
+	ast.Field: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETInt, types.ETInt, types.ETInt},
+			geners: []dataGenerator{&rangeInt64Gener{0, 3}, &rangeInt64Gener{0, 3}, &rangeInt64Gener{0, 3}}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETReal, types.ETReal}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString},
+			geners: []dataGenerator{&selectStringGener{[]string{"a", "b"}}, &selectStringGener{[]string{"a", "b"}}, &selectStringGener{[]string{"a", "b"}}}},
+	},
+	ast.Elt: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETInt, types.ETString, types.ETString},
+			geners: []dataGenerator{&rangeInt64Gener{-1, 4}}},
+	},
 }
 
 func (s *testEvaluatorSuite) TestVectorizedBuiltinStringEvalOneVec(c *C) {
@@ -0,0 +1,1497 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+var (
+	_ functionClass = &nowFunctionClass{}
+	_ functionClass = &utcTimestampFunctionClass{}
+	_ functionClass = &sysDateFunctionClass{}
+	_ functionClass = &currentDateFunctionClass{}
+	_ functionClass = &utcDateFunctionClass{}
+	_ functionClass = &currentTimeFunctionClass{}
+	_ functionClass = &utcTimeFunctionClass{}
+	_ functionClass = &yearFunctionClass{}
+	_ functionClass = &monthFunctionClass{}
+	_ functionClass = &dayOfMonthFunctionClass{}
+	_ functionClass = &dateDiffFunctionClass{}
+	_ functionClass = &dateFormatFunctionClass{}
+	_ functionClass = &strToDateFunctionClass{}
+	_ functionClass = &unixTimestampFunctionClass{}
+	_ functionClass = &fromUnixTimeFunctionClass{}
+	_ functionClass = &extractFunctionClass{}
+	_ functionClass = &timestampDiffFunctionClass{}
+	_ functionClass = &dateArithFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinNowWithArgSig{}
+	_ builtinFunc = &builtinNowWithoutArgSig{}
+	_ builtinFunc = &builtinUTCTimestampWithArgSig{}
+	_ builtinFunc = &builtinUTCTimestampWithoutArgSig{}
+	_ builtinFunc = &builtinSysDateWithFspSig{}
+	_ builtinFunc = &builtinSysDateWithoutFspSig{}
+	_ builtinFunc = &builtinCurrentDateSig{}
+	_ builtinFunc = &builtinUTCDateSig{}
+	_ builtinFunc = &builtinCurrentTime0ArgSig{}
+	_ builtinFunc = &builtinCurrentTime1ArgSig{}
+	_ builtinFunc = &builtinUTCTime0ArgSig{}
+	_ builtinFunc = &builtinUTCTime1ArgSig{}
+	_ builtinFunc = &builtinYearSig{}
+	_ builtinFunc = &builtinMonthSig{}
+	_ builtinFunc = &builtinDayOfMonthSig{}
+	_ builtinFunc = &builtinDateDiffSig{}
+	_ builtinFunc = &builtinDateFormatSig{}
+	_ builtinFunc = &builtinStrToDateDateSig{}
+	_ builtinFunc = &builtinStrToDateDatetimeSig{}
+	_ builtinFunc = &builtinStrToDateDurationSig{}
+	_ builtinFunc = &builtinUnixTimestampCurrentSig{}
+	_ builtinFunc = &builtinUnixTimestampIntSig{}
+	_ builtinFunc = &builtinUnixTimestampDecSig{}
+	_ builtinFunc = &builtinFromUnixTime1ArgSig{}
+	_ builtinFunc = &builtinFromUnixTime2ArgSig{}
+	_ builtinFunc = &builtinExtractDatetimeSig{}
+	_ builtinFunc = &builtinExtractDurationSig{}
+	_ builtinFunc = &builtinTimestampDiffSig{}
+	_ builtinFunc = &builtinDateArithDatetimeSig{}
+	_ builtinFunc = &builtinDateArithStringSig{}
+)
+
+// setDatetimeFsp sets the fractional seconds precision and the display
+// length of a datetime return type.
+func setDatetimeFsp(tp *types.FieldType, fsp int8) {
+	tp.Decimal = int(fsp)
+	tp.Flen = mysql.MaxDatetimeWidthNoFsp
+	if fsp > 0 {
+		tp.Flen += 1 + int(fsp)
+	}
+}
+
+// setDurationFsp sets the fractional seconds precision and the display
+// length of a duration return type.
+func setDurationFsp(tp *types.FieldType, fsp int8) {
+	tp.Decimal = int(fsp)
+	tp.Flen = mysql.MaxDurationWidthNoFsp
+	if fsp > 0 {
+		tp.Flen += 1 + int(fsp)
+	}
+}
+
+// setDateRetTp sets the return type to a date.
+func setDateRetTp(tp *types.FieldType) {
+	tp.Tp = mysql.TypeDate
+	tp.Flen, tp.Decimal = mysql.MaxDateWidth, int(types.MinFsp)
+}
+
+// checkFspArg checks the fractional seconds precision passed to functions
+// like NOW(fsp).
+func checkFspArg(fsp int64, funcName string) (int8, error) {
+	if fsp > int64(types.MaxFsp) {
+		return 0, types.ErrTooBigPrecision.GenWithStackByArgs(fsp, funcName, types.MaxFsp)
+	}
+	if fsp < int64(types.MinFsp) {
+		return 0, errors.Errorf("Invalid negative %d specified, must in [0, 6].", fsp)
+	}
+	return int8(fsp), nil
+}
+
+// getFspByIntArg gets the fractional seconds precision of functions like
+// NOW(fsp) when the plan is built. The fsp is always a constant in SQL, the
+// max fsp is used for the other expressions.
+func getFspByIntArg(ctx sessionctx.Context, args []Expression, funcName string) (int8, error) {
+	if len(args) == 0 {
+		return types.DefaultFsp, nil
+	}
+	if _, ok := args[0].(*Constant); !ok {
+		return types.MaxFsp, nil
+	}
+	fsp, isNull, err := args[0].EvalInt(ctx, chunk.Row{})
+	if isNull || err != nil {
+		return types.DefaultFsp, err
+	}
+	return checkFspArg(fsp, funcName)
+}
+
+// evalFspArg evaluates the fractional seconds precision of functions like
+// NOW(fsp) in row.
+func evalFspArg(ctx sessionctx.Context, arg Expression, row chunk.Row, funcName string) (int8, bool, error) {
+	fsp, isNull, err := arg.EvalInt(ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	res, err := checkFspArg(fsp, funcName)
+	return res, err != nil, err
+}
+
+// goTimeToDatetime converts t to a datetime with fsp. Like MySQL, the
+// fractional seconds are truncated instead of rounded.
+func goTimeToDatetime(t time.Time, fsp int8) types.Time {
+	t = t.Truncate(time.Duration(math.Pow10(9 - int(fsp))))
+	return types.NewTime(types.FromGoTime(t), mysql.TypeDatetime, fsp)
+}
+
+// goTimeToDate converts t to a date.
+func goTimeToDate(t time.Time) types.Time {
+	year, month, day := t.Date()
+	return types.NewTime(types.FromDate(year, int(month), day, 0, 0, 0, 0), mysql.TypeDate, types.DefaultFsp)
+}
+
+// goTimeToDuration converts the time of day of t to a duration with fsp.
+func goTimeToDuration(t time.Time, fsp int8) (types.Duration, error) {
+	return goTimeToDatetime(t, fsp).ConvertToDuration()
+}
+
+// evalNowWithFsp returns the timestamp of the statement in the session time zone.
+func evalNowWithFsp(ctx sessionctx.Context, fsp int8) types.Time {
+	return goTimeToDatetime(getStmtTimestamp(ctx).In(ctx.GetSessionVars().Location()), fsp)
+}
+
+// evalUTCNowWithFsp returns the timestamp of the statement in UTC.
+func evalUTCNowWithFsp(ctx sessionctx.Context, fsp int8) types.Time {
+	return goTimeToDatetime(getStmtTimestamp(ctx).UTC(), fsp)
+}
+
+// evalSysDateWithFsp returns the time at which SYSDATE() is evaluated in the
+// session time zone. Unlike NOW(), it differs from row to row.
+func evalSysDateWithFsp(ctx sessionctx.Context, fsp int8) types.Time {
+	return goTimeToDatetime(time.Now().In(ctx.GetSessionVars().Location()), fsp)
+}
+
+type nowFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *nowFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	fsp, err := getFspByIntArg(ctx, args, c.funcName)
+	if err != nil {
+		return nil, err
+	}
+	var sig builtinFunc
+	if len(args) == 1 {
+		wrapArgsWithCast(ctx, args, types.ETInt)
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime, types.ETInt)
+		setDatetimeFsp(bf.tp, fsp)
+		sig = &builtinNowWithArgSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_NowWithArg)
+	} else {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime)
+		setDatetimeFsp(bf.tp, fsp)
+		sig = &builtinNowWithoutArgSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_NowWithoutArg)
+	}
+	return sig, nil
+}
+
+type builtinNowWithArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinNowWithArgSig) Clone() builtinFunc {
+	newSig := &builtinNowWithArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals NOW(fsp)
+// see: https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_now
+func (b *builtinNowWithArgSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	fsp, isNull, err := evalFspArg(b.ctx, b.args[0], row, "now")
+	if isNull || err != nil {
+		return types.ZeroTime, true, err
+	}
+	return evalNowWithFsp(b.ctx, fsp), false, nil
+}
+
+type builtinNowWithoutArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinNowWithoutArgSig) Clone() builtinFunc {
+	newSig := &builtinNowWithoutArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals NOW()
+// see: https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_now
+func (b *builtinNowWithoutArgSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	return evalNowWithFsp(b.ctx, types.DefaultFsp), false, nil
+}
+
+type utcTimestampFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *utcTimestampFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	fsp, err := getFspByIntArg(ctx, args, c.funcName)
+	if err != nil {
+		return nil, err
+	}
+	var sig builtinFunc
+	if len(args) == 1 {
+		wrapArgsWithCast(ctx, args, types.ETInt)
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime, types.ETInt)
+		setDatetimeFsp(bf.tp, fsp)
+		sig = &builtinUTCTimestampWithArgSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_UTCTimestampWithArg)
+	} else {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime)
+		setDatetimeFsp(bf.tp, fsp)
+		sig = &builtinUTCTimestampWithoutArgSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_UTCTimestampWithoutArg)
+	}
+	return sig, nil
+}
+
+type builtinUTCTimestampWithArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUTCTimestampWithArgSig) Clone() builtinFunc {
+	newSig := &builtinUTCTimestampWithArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals UTC_TIMESTAMP(fsp).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_utc-timestamp
+func (b *builtinUTCTimestampWithArgSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	fsp, isNull, err := evalFspArg(b.ctx, b.args[0], row, "utc_timestamp")
+	if isNull || err != nil {
+		return types.ZeroTime, true, err
+	}
+	return evalUTCNowWithFsp(b.ctx, fsp), false, nil
+}
+
+type builtinUTCTimestampWithoutArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUTCTimestampWithoutArgSig) Clone() builtinFunc {
+	newSig := &builtinUTCTimestampWithoutArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals UTC_TIMESTAMP().
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_utc-timestamp
+func (b *builtinUTCTimestampWithoutArgSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	return evalUTCNowWithFsp(b.ctx, types.DefaultFsp), false, nil
+}
+
+type sysDateFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *sysDateFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	fsp, err := getFspByIntArg(ctx, args, c.funcName)
+	if err != nil {
+		return nil, err
+	}
+	var sig builtinFunc
+	if len(args) == 1 {
+		wrapArgsWithCast(ctx, args, types.ETInt)
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime, types.ETInt)
+		setDatetimeFsp(bf.tp, fsp)
+		sig = &builtinSysDateWithFspSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_SysDateWithFsp)
+	} else {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime)
+		setDatetimeFsp(bf.tp, fsp)
+		sig = &builtinSysDateWithoutFspSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_SysDateWithoutFsp)
+	}
+	return sig, nil
+}
+
+type builtinSysDateWithFspSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSysDateWithFspSig) Clone() builtinFunc {
+	newSig := &builtinSysDateWithFspSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals SYSDATE(fsp).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_sysdate
+func (b *builtinSysDateWithFspSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	fsp, isNull, err := evalFspArg(b.ctx, b.args[0], row, "sysdate")
+	if isNull || err != nil {
+		return types.ZeroTime, true, err
+	}
+	return evalSysDateWithFsp(b.ctx, fsp), false, nil
+}
+
+type builtinSysDateWithoutFspSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSysDateWithoutFspSig) Clone() builtinFunc {
+	newSig := &builtinSysDateWithoutFspSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals SYSDATE().
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_sysdate
+func (b *builtinSysDateWithoutFspSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	return evalSysDateWithFsp(b.ctx, types.DefaultFsp), false, nil
+}
+
+type currentDateFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *currentDateFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime)
+	setDateRetTp(bf.tp)
+	sig := &builtinCurrentDateSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_CurrentDate)
+	return sig, nil
+}
+
+type builtinCurrentDateSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCurrentDateSig) Clone() builtinFunc {
+	newSig := &builtinCurrentDateSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals CURDATE().
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_curdate
+func (b *builtinCurrentDateSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	return goTimeToDate(getStmtTimestamp(b.ctx).In(b.ctx.GetSessionVars().Location())), false, nil
+}
+
+type utcDateFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *utcDateFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime)
+	setDateRetTp(bf.tp)
+	sig := &builtinUTCDateSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_UTCDate)
+	return sig, nil
+}
+
+type builtinUTCDateSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUTCDateSig) Clone() builtinFunc {
+	newSig := &builtinUTCDateSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals UTC_DATE().
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_utc-date
+func (b *builtinUTCDateSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	return goTimeToDate(getStmtTimestamp(b.ctx).UTC()), false, nil
+}
+
+type currentTimeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *currentTimeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	fsp, err := getFspByIntArg(ctx, args, c.funcName)
+	if err != nil {
+		return nil, err
+	}
+	var sig builtinFunc
+	if len(args) == 1 {
+		wrapArgsWithCast(ctx, args, types.ETInt)
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDuration, types.ETInt)
+		setDurationFsp(bf.tp, fsp)
+		sig = &builtinCurrentTime1ArgSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CurrentTime1Arg)
+	} else {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDuration)
+		setDurationFsp(bf.tp, fsp)
+		sig = &builtinCurrentTime0ArgSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CurrentTime0Arg)
+	}
+	return sig, nil
+}
+
+type builtinCurrentTime0ArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCurrentTime0ArgSig) Clone() builtinFunc {
+	newSig := &builtinCurrentTime0ArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDuration evals CURTIME().
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_curtime
+func (b *builtinCurrentTime0ArgSig) evalDuration(row chunk.Row) (types.Duration, bool, error) {
+	res, err := goTimeToDuration(getStmtTimestamp(b.ctx).In(b.ctx.GetSessionVars().Location()), types.DefaultFsp)
+	if err != nil {
+		return types.Duration{}, true, err
+	}
+	return res, false, nil
+}
+
+type builtinCurrentTime1ArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCurrentTime1ArgSig) Clone() builtinFunc {
+	newSig := &builtinCurrentTime1ArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDuration evals CURTIME(fsp).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_curtime
+func (b *builtinCurrentTime1ArgSig) evalDuration(row chunk.Row) (types.Duration, bool, error) {
+	fsp, isNull, err := evalFspArg(b.ctx, b.args[0], row, "curtime")
+	if isNull || err != nil {
+		return types.Duration{}, true, err
+	}
+	res, err := goTimeToDuration(getStmtTimestamp(b.ctx).In(b.ctx.GetSessionVars().Location()), fsp)
+	if err != nil {
+		return types.Duration{}, true, err
+	}
+	return res, false, nil
+}
+
+type utcTimeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *utcTimeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	fsp, err := getFspByIntArg(ctx, args, c.funcName)
+	if err != nil {
+		return nil, err
+	}
+	var sig builtinFunc
+	if len(args) == 1 {
+		wrapArgsWithCast(ctx, args, types.ETInt)
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDuration, types.ETInt)
+		setDurationFsp(bf.tp, fsp)
+		sig = &builtinUTCTime1ArgSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_UTCTime1Arg)
+	} else {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDuration)
+		setDurationFsp(bf.tp, fsp)
+		sig = &builtinUTCTime0ArgSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_UTCTime0Arg)
+	}
+	return sig, nil
+}
+
+type builtinUTCTime0ArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUTCTime0ArgSig) Clone() builtinFunc {
+	newSig := &builtinUTCTime0ArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDuration evals UTC_TIME().
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_utc-time
+func (b *builtinUTCTime0ArgSig) evalDuration(row chunk.Row) (types.Duration, bool, error) {
+	res, err := goTimeToDuration(getStmtTimestamp(b.ctx).UTC(), types.DefaultFsp)
+	if err != nil {
+		return types.Duration{}, true, err
+	}
+	return res, false, nil
+}
+
+type builtinUTCTime1ArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUTCTime1ArgSig) Clone() builtinFunc {
+	newSig := &builtinUTCTime1ArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDuration evals UTC_TIME(fsp).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_utc-time
+func (b *builtinUTCTime1ArgSig) evalDuration(row chunk.Row) (types.Duration, bool, error) {
+	fsp, isNull, err := evalFspArg(b.ctx, b.args[0], row, "utc_time")
+	if isNull || err != nil {
+		return types.Duration{}, true, err
+	}
+	res, err := goTimeToDuration(getStmtTimestamp(b.ctx).UTC(), fsp)
+	if err != nil {
+		return types.Duration{}, true, err
+	}
+	return res, false, nil
+}
+
+type yearFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *yearFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	wrapArgsWithCast(ctx, args, types.ETDatetime)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETDatetime)
+	bf.tp.Flen = 4
+	sig := &builtinYearSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Year)
+	return sig, nil
+}
+
+type builtinYearSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinYearSig) Clone() builtinFunc {
+	newSig := &builtinYearSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals YEAR(date).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_year
+func (b *builtinYearSig) evalInt(row chunk.Row) (int64, bool, error) {
+	date, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, handleInvalidTimeError(b.ctx, err)
+	}
+	return int64(date.Year()), false, nil
+}
+
+type monthFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *monthFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	wrapArgsWithCast(ctx, args, types.ETDatetime)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETDatetime)
+	bf.tp.Flen = 2
+	sig := &builtinMonthSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Month)
+	return sig, nil
+}
+
+type builtinMonthSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinMonthSig) Clone() builtinFunc {
+	newSig := &builtinMonthSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals MONTH(date).
+// see: https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_month
+func (b *builtinMonthSig) evalInt(row chunk.Row) (int64, bool, error) {
+	date, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, handleInvalidTimeError(b.ctx, err)
+	}
+	return int64(date.Month()), false, nil
+}
+
+type dayOfMonthFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *dayOfMonthFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	wrapArgsWithCast(ctx, args, types.ETDatetime)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETDatetime)
+	bf.tp.Flen = 2
+	sig := &builtinDayOfMonthSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_DayOfMonth)
+	return sig, nil
+}
+
+type builtinDayOfMonthSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinDayOfMonthSig) Clone() builtinFunc {
+	newSig := &builtinDayOfMonthSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals DAYOFMONTH(date).
+// see: https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_dayofmonth
+func (b *builtinDayOfMonthSig) evalInt(row chunk.Row) (int64, bool, error) {
+	date, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, handleInvalidTimeError(b.ctx, err)
+	}
+	return int64(date.Day()), false, nil
+}
+
+// checkInvalidZeroTime reports the times whose month or day is zero, which
+// the date arithmetic functions cannot handle.
+func checkInvalidZeroTime(ctx sessionctx.Context, times ...types.Time) (bool, error) {
+	for _, t := range times {
+		if t.InvalidZero() {
+			return true, handleInvalidTimeError(ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, t.String()))
+		}
+	}
+	return false, nil
+}
+
+type dateDiffFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *dateDiffFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	wrapArgsWithCast(ctx, args, types.ETDatetime, types.ETDatetime)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETDatetime, types.ETDatetime)
+	sig := &builtinDateDiffSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_DateDiff)
+	return sig, nil
+}
+
+type builtinDateDiffSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinDateDiffSig) Clone() builtinFunc {
+	newSig := &builtinDateDiffSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinDateDiffSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_datediff
+func (b *builtinDateDiffSig) evalInt(row chunk.Row) (int64, bool, error) {
+	lhs, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, handleInvalidTimeError(b.ctx, err)
+	}
+	rhs, isNull, err := b.args[1].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, handleInvalidTimeError(b.ctx, err)
+	}
+	if invalid, err := checkInvalidZeroTime(b.ctx, lhs, rhs); invalid {
+		return 0, true, err
+	}
+	return int64(types.DateDiff(lhs.CoreTime(), rhs.CoreTime())), false, nil
+}
+
+type dateFormatFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *dateFormatFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	wrapArgsWithCast(ctx, args, types.ETDatetime, types.ETString)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETDatetime, types.ETString)
+	// The worst case is a format like "%r%r...%r", every "%r" takes 11 characters.
+	bf.tp.Flen = mysql.MaxBlobWidth
+	if formatLen := args[1].GetType().Flen; formatLen != types.UnspecifiedLength {
+		bf.tp.Flen = (formatLen + 1) / 2 * 11
+	}
+	sig := &builtinDateFormatSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_DateFormatSig)
+	return sig, nil
+}
+
+type builtinDateFormatSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinDateFormatSig) Clone() builtinFunc {
+	newSig := &builtinDateFormatSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinDateFormatSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-format
+func (b *builtinDateFormatSig) evalString(row chunk.Row) (string, bool, error) {
+	t, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, handleInvalidTimeError(b.ctx, err)
+	}
+	formatMask, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	if invalid, err := checkInvalidZeroTime(b.ctx, t); invalid {
+		return "", true, err
+	}
+	res, err := t.DateFormat(formatMask)
+	return res, err != nil, err
+}
+
+type strToDateFunctionClass struct {
+	baseFunctionClass
+}
+
+// getRetTp infers the return type of STR_TO_DATE from the format: a format
+// with only date parts returns a date, one with only time parts returns a
+// duration, otherwise it returns a datetime.
+func (c *strToDateFunctionClass) getRetTp(ctx sessionctx.Context, arg Expression) (tp byte, fsp int8) {
+	tp = mysql.TypeDatetime
+	if !isMemorizable(arg) {
+		return tp, types.MaxFsp
+	}
+	format, isNull, err := arg.EvalString(ctx, chunk.Row{})
+	if isNull || err != nil {
+		return tp, types.MaxFsp
+	}
+	isDuration, isDate := types.GetFormatType(format)
+	if isDuration && !isDate {
+		tp = mysql.TypeDuration
+	} else if !isDuration && isDate {
+		tp = mysql.TypeDate
+	}
+	if strings.Contains(format, "%f") {
+		fsp = types.MaxFsp
+	}
+	return tp, fsp
+}
+
+func (c *strToDateFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	wrapArgsWithCast(ctx, args, types.ETString, types.ETString)
+	retTp, fsp := c.getRetTp(ctx, args[1])
+	var sig builtinFunc
+	switch retTp {
+	case mysql.TypeDate:
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime, types.ETString, types.ETString)
+		setDateRetTp(bf.tp)
+		sig = &builtinStrToDateDateSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_StrToDateDate)
+	case mysql.TypeDuration:
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDuration, types.ETString, types.ETString)
+		setDurationFsp(bf.tp, fsp)
+		sig = &builtinStrToDateDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_StrToDateDuration)
+	default:
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime, types.ETString, types.ETString)
+		setDatetimeFsp(bf.tp, fsp)
+		sig = &builtinStrToDateDatetimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_StrToDateDatetime)
+	}
+	return sig, nil
+}
+
+// evalStrToDate parses the date and the format of STR_TO_DATE in row. A date
+// that does not match the format is NULL with a warning.
+func evalStrToDate(ctx sessionctx.Context, args []Expression, row chunk.Row) (types.Time, bool, error) {
+	date, isNull, err := args[0].EvalString(ctx, row)
+	if isNull || err != nil {
+		return types.ZeroTime, true, err
+	}
+	format, isNull, err := args[1].EvalString(ctx, row)
+	if isNull || err != nil {
+		return types.ZeroTime, true, err
+	}
+	return strToDate(ctx, date, format)
+}
+
+func strToDate(ctx sessionctx.Context, date, format string) (types.Time, bool, error) {
+	var t types.Time
+	if !t.StrToDate(ctx.GetSessionVars().StmtCtx, date, format) {
+		return types.ZeroTime, true, handleInvalidTimeError(ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, date))
+	}
+	return t, false, nil
+}
+
+type builtinStrToDateDateSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinStrToDateDateSig) Clone() builtinFunc {
+	newSig := &builtinStrToDateDateSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals STR_TO_DATE(str, format) for a format with only date parts.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_str-to-date
+func (b *builtinStrToDateDateSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	t, isNull, err := evalStrToDate(b.ctx, b.args, row)
+	if isNull || err != nil {
+		return types.ZeroTime, true, err
+	}
+	t.SetType(mysql.TypeDate)
+	t.SetFsp(types.MinFsp)
+	return t, false, nil
+}
+
+type builtinStrToDateDatetimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinStrToDateDatetimeSig) Clone() builtinFunc {
+	newSig := &builtinStrToDateDatetimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals STR_TO_DATE(str, format) for a format with date and time parts.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_str-to-date
+func (b *builtinStrToDateDatetimeSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	t, isNull, err := evalStrToDate(b.ctx, b.args, row)
+	if isNull || err != nil {
+		return types.ZeroTime, true, err
+	}
+	t.SetFsp(int8(b.tp.Decimal))
+	return t, false, nil
+}
+
+type builtinStrToDateDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinStrToDateDurationSig) Clone() builtinFunc {
+	newSig := &builtinStrToDateDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDuration evals STR_TO_DATE(str, format) for a format with only time parts.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_str-to-date
+func (b *builtinStrToDateDurationSig) evalDuration(row chunk.Row) (types.Duration, bool, error) {
+	t, isNull, err := evalStrToDate(b.ctx, b.args, row)
+	if isNull || err != nil {
+		return types.Duration{}, true, err
+	}
+	t.SetFsp(int8(b.tp.Decimal))
+	dur, err := t.ConvertToDuration()
+	return dur, err != nil, err
+}
+
+// maxUnixTimestamp is the largest timestamp UNIX_TIMESTAMP and FROM_UNIXTIME
+// support, which is '2038-01-19 03:14:07.999999' UTC.
+const maxUnixTimestamp = math.MaxInt32
+
+type unixTimestampFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *unixTimestampFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt)
+		bf.tp.Flen = 11
+		sig := &builtinUnixTimestampCurrentSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_UnixTimestampCurrent)
+		return sig, nil
+	}
+
+	// The result has as many fractional digits as the argument.
+	argTp := args[0].GetType()
+	retDecimal := argTp.Decimal
+	if argTp.EvalType() == types.ETString {
+		retDecimal = types.UnspecifiedLength
+		if con, ok := args[0].(*Constant); ok && isMemorizable(con) {
+			str, isNull, err := con.EvalString(ctx, chunk.Row{})
+			if err != nil {
+				return nil, err
+			}
+			retDecimal = 0
+			if dotIdx := strings.LastIndex(str, "."); !isNull && dotIdx >= 0 {
+				retDecimal = len(str) - dotIdx - 1
+			}
+		}
+	}
+	if retDecimal > int(types.MaxFsp) || retDecimal == types.UnspecifiedLength {
+		retDecimal = int(types.MaxFsp)
+	}
+
+	wrapArgsWithCast(ctx, args, types.ETDatetime)
+	var sig builtinFunc
+	if retDecimal <= 0 {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETDatetime)
+		bf.tp.Flen = 11
+		sig = &builtinUnixTimestampIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_UnixTimestampInt)
+	} else {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDatetime)
+		bf.tp.Flen, bf.tp.Decimal = 12+retDecimal, retDecimal
+		sig = &builtinUnixTimestampDecSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_UnixTimestampDec)
+	}
+	return sig, nil
+}
+
+// evalUnixTimestamp converts t in the session time zone to the nanoseconds
+// since the unix epoch. Times out of the range of UNIX_TIMESTAMP are converted
+// to 0 like in MySQL.
+func evalUnixTimestamp(ctx sessionctx.Context, t types.Time) int64 {
+	goTime, err := t.GoTime(ctx.GetSessionVars().Location())
+	if err != nil {
+		return 0
+	}
+	if secs := goTime.Unix(); secs < 0 || secs > maxUnixTimestamp {
+		return 0
+	}
+	return goTime.UnixNano()
+}
+
+// unixNanosToDecimal converts nanoseconds to seconds with frac fractional
+// digits. Like MySQL, the extra digits are truncated instead of rounded.
+func unixNanosToDecimal(nanos int64, frac int) (*types.MyDecimal, error) {
+	dec := new(types.MyDecimal)
+	err := types.DecimalDiv(new(types.MyDecimal).FromInt(nanos), new(types.MyDecimal).FromInt(int64(time.Second)), dec, 9)
+	if err != nil {
+		return nil, err
+	}
+	err = dec.Round(dec, frac, types.ModeTruncate)
+	return dec, err
+}
+
+type builtinUnixTimestampCurrentSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUnixTimestampCurrentSig) Clone() builtinFunc {
+	newSig := &builtinUnixTimestampCurrentSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals UNIX_TIMESTAMP().
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_unix-timestamp
+func (b *builtinUnixTimestampCurrentSig) evalInt(row chunk.Row) (int64, bool, error) {
+	return getStmtTimestamp(b.ctx).Unix(), false, nil
+}
+
+type builtinUnixTimestampIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUnixTimestampIntSig) Clone() builtinFunc {
+	newSig := &builtinUnixTimestampIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals UNIX_TIMESTAMP(time) for a time without fractional seconds.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_unix-timestamp
+func (b *builtinUnixTimestampIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	t, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, handleInvalidTimeError(b.ctx, err)
+	}
+	return evalUnixTimestamp(b.ctx, t) / int64(time.Second), false, nil
+}
+
+type builtinUnixTimestampDecSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUnixTimestampDecSig) Clone() builtinFunc {
+	newSig := &builtinUnixTimestampDecSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals UNIX_TIMESTAMP(time) for a time with fractional seconds.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_unix-timestamp
+func (b *builtinUnixTimestampDecSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	t, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return nil, true, handleInvalidTimeError(b.ctx, err)
+	}
+	res, err := unixNanosToDecimal(evalUnixTimestamp(b.ctx, t), b.tp.Decimal)
+	return res, err != nil, err
+}
+
+type fromUnixTimeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *fromUnixTimeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTp := args[0].GetType()
+	if len(args) == 2 {
+		wrapArgsWithCast(ctx, args, types.ETDecimal, types.ETString)
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETDecimal, types.ETString)
+		bf.tp.Flen = args[1].GetType().Flen
+		sig := &builtinFromUnixTime2ArgSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_FromUnixTime2Arg)
+		return sig, nil
+	}
+
+	wrapArgsWithCast(ctx, args, types.ETDecimal)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime, types.ETDecimal)
+	fsp := types.MaxFsp
+	if argTp.EvalType() != types.ETString && argTp.Decimal != types.UnspecifiedLength && argTp.Decimal < int(fsp) {
+		fsp = int8(argTp.Decimal)
+	}
+	setDatetimeFsp(bf.tp, fsp)
+	sig := &builtinFromUnixTime1ArgSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_FromUnixTime1Arg)
+	return sig, nil
+}
+
+// evalFromUnixTime converts the seconds since the unix epoch to a datetime
+// in the session time zone. A timestamp out of [0, 2^31) is NULL.
+func evalFromUnixTime(ctx sessionctx.Context, fsp int8, unixTimeStamp *types.MyDecimal) (types.Time, bool, error) {
+	if unixTimeStamp.IsNegative() {
+		return types.ZeroTime, true, nil
+	}
+	nanoDec := new(types.MyDecimal)
+	if err := types.DecimalMul(unixTimeStamp, new(types.MyDecimal).FromInt(int64(time.Second)), nanoDec); err != nil {
+		return types.ZeroTime, true, nil
+	}
+	if err := nanoDec.Round(nanoDec, 0, types.ModeHalfEven); err != nil {
+		return types.ZeroTime, true, err
+	}
+	nanos, err := nanoDec.ToInt()
+	if err != nil || nanos/int64(time.Second) > maxUnixTimestamp {
+		return types.ZeroTime, true, nil
+	}
+	goTime := time.Unix(0, nanos).In(ctx.GetSessionVars().Location())
+	t, err := types.NewTime(types.FromGoTime(goTime), mysql.TypeDatetime, types.MaxFsp).RoundFrac(ctx.GetSessionVars().StmtCtx, fsp)
+	if err != nil {
+		return types.ZeroTime, true, err
+	}
+	return t, false, nil
+}
+
+type builtinFromUnixTime1ArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinFromUnixTime1ArgSig) Clone() builtinFunc {
+	newSig := &builtinFromUnixTime1ArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals a builtinFromUnixTime1ArgSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_from-unixtime
+func (b *builtinFromUnixTime1ArgSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	unixTimeStamp, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return types.ZeroTime, true, err
+	}
+	return evalFromUnixTime(b.ctx, int8(b.tp.Decimal), unixTimeStamp)
+}
+
+type builtinFromUnixTime2ArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinFromUnixTime2ArgSig) Clone() builtinFunc {
+	newSig := &builtinFromUnixTime2ArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinFromUnixTime2ArgSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_from-unixtime
+func (b *builtinFromUnixTime2ArgSig) evalString(row chunk.Row) (string, bool, error) {
+	unixTimeStamp, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	format, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	t, isNull, err := evalFromUnixTime(b.ctx, types.MaxFsp, unixTimeStamp)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	res, err := t.DateFormat(format)
+	return res, err != nil, err
+}
+
+type extractFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *extractFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	// The unit is always a constant in SQL. A date unit is extracted from a
+	// datetime, a time unit is extracted from a duration so that EXTRACT(HOUR
+	// FROM '100:00:00') returns 100.
+	isDatetimeUnit := true
+	if isMemorizable(args[0]) {
+		unit, isNull, err := args[0].EvalString(ctx, chunk.Row{})
+		if err != nil {
+			return nil, err
+		}
+		isDatetimeUnit = isNull || types.IsDatetimeUnit(unit)
+	}
+	var sig builtinFunc
+	if isDatetimeUnit {
+		wrapArgsWithCast(ctx, args, types.ETString, types.ETDatetime)
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETString, types.ETDatetime)
+		sig = &builtinExtractDatetimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_ExtractDatetime)
+	} else {
+		wrapArgsWithCast(ctx, args, types.ETString, types.ETDuration)
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETString, types.ETDuration)
+		sig = &builtinExtractDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_ExtractDuration)
+	}
+	return sig, nil
+}
+
+type builtinExtractDatetimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinExtractDatetimeSig) Clone() builtinFunc {
+	newSig := &builtinExtractDatetimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinExtractDatetimeSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_extract
+func (b *builtinExtractDatetimeSig) evalInt(row chunk.Row) (int64, bool, error) {
+	unit, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	t, isNull, err := b.args[1].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, handleInvalidTimeError(b.ctx, err)
+	}
+	res, err := types.ExtractDatetimeNum(&t, unit)
+	return res, err != nil, err
+}
+
+type builtinExtractDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinExtractDurationSig) Clone() builtinFunc {
+	newSig := &builtinExtractDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinExtractDurationSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_extract
+func (b *builtinExtractDurationSig) evalInt(row chunk.Row) (int64, bool, error) {
+	unit, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	dur, isNull, err := b.args[1].EvalDuration(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	res, err := types.ExtractDurationNum(&dur, unit)
+	return res, err != nil, err
+}
+
+type timestampDiffFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *timestampDiffFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETDatetime, types.ETDatetime}
+	wrapArgsWithCast(ctx, args, argTps...)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argTps...)
+	sig := &builtinTimestampDiffSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_TimestampDiff)
+	return sig, nil
+}
+
+type builtinTimestampDiffSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTimestampDiffSig) Clone() builtinFunc {
+	newSig := &builtinTimestampDiffSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinTimestampDiffSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_timestampdiff
+func (b *builtinTimestampDiffSig) evalInt(row chunk.Row) (int64, bool, error) {
+	unit, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	lhs, isNull, err := b.args[1].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, handleInvalidTimeError(b.ctx, err)
+	}
+	rhs, isNull, err := b.args[2].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, handleInvalidTimeError(b.ctx, err)
+	}
+	if invalid, err := checkInvalidZeroTime(b.ctx, lhs, rhs); invalid {
+		return 0, true, err
+	}
+	return types.TimestampDiff(unit, lhs, rhs), false, nil
+}
+
+// dateArithFunctionClass builds DATE_ADD, DATE_SUB, ADDDATE and SUBDATE, the
+// arguments are the date, the interval value and the interval unit.
+type dateArithFunctionClass struct {
+	baseFunctionClass
+
+	isSub bool
+}
+
+// getIntervalFsp returns the fractional seconds precision an interval of
+// unit adds to a datetime.
+func getIntervalFsp(unit string, interval Expression) int8 {
+	switch strings.ToUpper(unit) {
+	case "MICROSECOND", "SECOND_MICROSECOND", "MINUTE_MICROSECOND", "HOUR_MICROSECOND", "DAY_MICROSECOND":
+		return types.MaxFsp
+	case "SECOND":
+		if tp := interval.GetType(); tp.EvalType() != types.ETString && tp.Decimal >= 0 && tp.Decimal < int(types.MaxFsp) {
+			return int8(tp.Decimal)
+		}
+		return types.MaxFsp
+	}
+	return types.MinFsp
+}
+
+func (c *dateArithFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	// The unit is always a constant in SQL, it decides the type of the result.
+	unit := ""
+	if isMemorizable(args[2]) {
+		var err error
+		if unit, _, err = args[2].EvalString(ctx, chunk.Row{}); err != nil {
+			return nil, err
+		}
+	}
+	intervalFsp := types.MaxFsp
+	if unit != "" {
+		intervalFsp = getIntervalFsp(unit, args[1])
+	}
+
+	dateTp := args[0].GetType()
+	var sig builtinFunc
+	switch dateTp.EvalType() {
+	case types.ETDatetime, types.ETTimestamp, types.ETDuration:
+		isDate := dateTp.Tp == mysql.TypeDate && unit != "" && !types.IsClockUnit(unit)
+		wrapArgsWithCast(ctx, args, types.ETDatetime, types.ETString, types.ETString)
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime, types.ETDatetime, types.ETString, types.ETString)
+		if isDate {
+			setDateRetTp(bf.tp)
+		} else {
+			fsp := intervalFsp
+			if argFsp := int8(args[0].GetType().Decimal); argFsp > fsp && argFsp <= types.MaxFsp {
+				fsp = argFsp
+			}
+			setDatetimeFsp(bf.tp, fsp)
+		}
+		sig = &builtinDateArithDatetimeSig{bf, c.isSub}
+		if c.isSub {
+			sig.setPbCode(tipb.ScalarFuncSig_SubDateDatetimeString)
+		} else {
+			sig.setPbCode(tipb.ScalarFuncSig_AddDateDatetimeString)
+		}
+	default:
+		// The date of other types is parsed from a string, the result is a
+		// string too.
+		wrapArgsWithCast(ctx, args, types.ETString, types.ETString, types.ETString)
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETString, types.ETString)
+		bf.tp.Flen = mysql.MaxDatetimeFullWidth
+		sig = &builtinDateArithStringSig{bf, c.isSub}
+		if c.isSub {
+			sig.setPbCode(tipb.ScalarFuncSig_SubDateStringString)
+		} else {
+			sig.setPbCode(tipb.ScalarFuncSig_AddDateStringString)
+		}
+	}
+	return sig, nil
+}
+
+// dateArith adds the interval of unit to date, or subtracts it when isSub
+// is true. A result out of the range of datetime is NULL with a warning.
+func dateArith(ctx sessionctx.Context, date types.Time, interval, unit string, isSub bool) (types.Time, bool, error) {
+	year, month, day, nano, err := types.ParseDurationValue(unit, interval)
+	if err != nil {
+		return types.ZeroTime, true, handleInvalidTimeError(ctx, err)
+	}
+	if isSub {
+		year, month, day, nano = -year, -month, -day, -nano
+	}
+	// The calculation is done in UTC, which has no daylight saving time.
+	goTime, err := date.GoTime(time.UTC)
+	if err != nil {
+		return types.ZeroTime, true, handleInvalidTimeError(ctx, err)
+	}
+	goTime = types.AddDate(year, month, day, goTime.Add(time.Duration(nano)))
+	if goTime.Year() < 0 || goTime.Year() > 9999 {
+		return types.ZeroTime, true, handleInvalidTimeError(ctx, types.ErrDatetimeFunctionOverflow.GenWithStackByArgs("datetime"))
+	}
+	date.SetCoreTime(types.FromGoTime(goTime))
+	return date, false, nil
+}
+
+type builtinDateArithDatetimeSig struct {
+	baseBuiltinFunc
+
+	isSub bool
+}
+
+func (b *builtinDateArithDatetimeSig) Clone() builtinFunc {
+	newSig := &builtinDateArithDatetimeSig{isSub: b.isSub}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals DATE_ADD(date, INTERVAL expr unit) and DATE_SUB for a date of time types.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-add
+func (b *builtinDateArithDatetimeSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	date, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, err)
+	}
+	interval, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return types.ZeroTime, true, err
+	}
+	unit, isNull, err := b.args[2].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return types.ZeroTime, true, err
+	}
+	return b.arith(date, interval, unit)
+}
+
+func (b *builtinDateArithDatetimeSig) arith(date types.Time, interval, unit string) (types.Time, bool, error) {
+	res, isNull, err := dateArith(b.ctx, date, interval, unit, b.isSub)
+	if isNull || err != nil {
+		return types.ZeroTime, true, err
+	}
+	res.SetType(b.tp.Tp)
+	res.SetFsp(int8(b.tp.Decimal))
+	return res, false, nil
+}
+
+type builtinDateArithStringSig struct {
+	baseBuiltinFunc
+
+	isSub bool
+}
+
+func (b *builtinDateArithStringSig) Clone() builtinFunc {
+	newSig := &builtinDateArithStringSig{isSub: b.isSub}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals DATE_ADD(date, INTERVAL expr unit) and DATE_SUB for a date of other types.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-add
+func (b *builtinDateArithStringSig) evalString(row chunk.Row) (string, bool, error) {
+	dateStr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	interval, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	unit, isNull, err := b.args[2].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return b.arith(dateStr, interval, unit)
+}
+
+// arith parses dateStr and does the arithmetic. The result is a date if
+// dateStr is a date and the unit has no time part, otherwise it is a
+// datetime, whose fractional seconds are only shown if they are not zero.
+func (b *builtinDateArithStringSig) arith(dateStr, interval, unit string) (string, bool, error) {
+	tp := mysql.TypeDatetime
+	if types.IsDateFormat(dateStr) && !types.IsClockUnit(unit) {
+		tp = mysql.TypeDate
+	}
+	date, err := types.ParseTime(b.ctx.GetSessionVars().StmtCtx, dateStr, tp, types.MaxFsp)
+	if err != nil {
+		return "", true, handleInvalidTimeError(b.ctx, err)
+	}
+	res, isNull, err := dateArith(b.ctx, date, interval, unit, b.isSub)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	if res.Microsecond() == 0 {
+		res.SetFsp(types.MinFsp)
+	}
+	return res.String(), false, nil
+}
@@ -0,0 +1,353 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/testutil"
+)
+
+func (s *testEvaluatorSuite) TestDate(c *C) {
+	tests := []struct {
+		funcName string
+		arg      interface{}
+		expect   interface{}
+	}{
+		{ast.Year, "2011-11-11 10:10:10.123456", int64(2011)},
+		{ast.Month, "2011-11-11 10:10:10.123456", int64(11)},
+		{ast.Day, "2011-11-11 10:10:10.123456", int64(11)},
+		{ast.DayOfMonth, "2011-11-01", int64(1)},
+		{ast.Year, nil, nil},
+		{ast.Month, "abc", nil},
+	}
+	for _, t := range tests {
+		f, err := funcs[t.funcName].getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{t.arg}))
+		c.Assert(err, IsNil)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.expect), Commentf("%s(%v)", t.funcName, t.arg))
+	}
+}
+
+func (s *testEvaluatorSuite) TestDateDiff(c *C) {
+	tests := []struct {
+		t1     interface{}
+		t2     interface{}
+		expect interface{}
+	}{
+		{"2004-05-21", "2003-05-21", int64(366)},
+		{"2010-11-30 23:59:59", "2010-12-31", int64(-31)},
+		{"2010-11-30 00:00:00", "2010-11-30 23:59:59", int64(0)},
+		{"2010-11-31", "2010-11-30", nil},
+		{nil, "2010-11-30", nil},
+	}
+	for _, t := range tests {
+		f, err := funcs[ast.DateDiff].getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{t.t1, t.t2}))
+		c.Assert(err, IsNil)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.expect), Commentf("%v", t))
+	}
+}
+
+func (s *testEvaluatorSuite) TestDateFormat(c *C) {
+	tests := []struct {
+		date   interface{}
+		format interface{}
+		expect interface{}
+	}{
+		{"2010-01-07 23:12:34.12345",
+			"%b %M %m %c %D %d %e %j %k %h %i %p %r %T %s %f %U %u %V %v %a %W %w %X %x %Y %y %%",
+			"Jan January 01 1 7th 07 7 007 23 11 12 PM 11:12:34 PM 23:12:34 34 123450 01 01 01 01 Thu Thursday 4 2010 2010 2010 10 %"},
+		{"2012-12-21 11:11:11", "%Y-%m-%d %H:%i:%s %Q", "2012-12-21 11:11:11 Q"},
+		{"0000-00-00 00:00:00", "%Y-%m-%d", "0000-00-00"},
+		{"abc", "%Y", nil},
+		{"2012-12-21", nil, nil},
+	}
+	for _, t := range tests {
+		f, err := funcs[ast.DateFormat].getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{t.date, t.format}))
+		c.Assert(err, IsNil)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.expect), Commentf("%v", t))
+	}
+}
+
+func (s *testEvaluatorSuite) TestStrToDate(c *C) {
+	tests := []struct {
+		date   string
+		format string
+		retTp  byte
+		isNull bool
+		expect string
+	}{
+		{"10/28/2011 9:46:29 pm", "%m/%d/%Y %l:%i:%s %p", mysql.TypeDatetime, false, "2011-10-28 21:46:29"},
+		{"2011-10-28 9:46:29.123", "%Y-%m-%d %H:%i:%s.%f", mysql.TypeDatetime, false, "2011-10-28 09:46:29.123000"},
+		{"Friday October 28 2011", "%W %M %d %Y", mysql.TypeDate, false, "2011-10-28"},
+		{"2011-10-28", "%Y-%m-%d", mysql.TypeDate, false, "2011-10-28"},
+		{"2011-02-30", "%Y-%m-%d", mysql.TypeDate, true, ""},
+		{"abc", "%Y-%m-%d", mysql.TypeDate, true, ""},
+		{"9:46:29", "%H:%i:%s", mysql.TypeDuration, false, "09:46:29"},
+		{"25:46:29", "%H:%i:%s", mysql.TypeDuration, true, ""},
+	}
+	for _, t := range tests {
+		f, err := funcs[ast.StrToDate].getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{t.date, t.format}))
+		c.Assert(err, IsNil)
+		c.Assert(f.getRetTp().Tp, Equals, t.retTp, Commentf("%v", t))
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d.IsNull(), Equals, t.isNull, Commentf("%v", t))
+		if t.isNull {
+			continue
+		}
+		str, err := d.ToString()
+		c.Assert(err, IsNil)
+		c.Assert(str, Equals, t.expect, Commentf("%v", t))
+	}
+}
+
+func (s *testEvaluatorSuite) TestNowAndCurrent(c *C) {
+	vars := s.ctx.GetSessionVars()
+	loc := time.FixedZone("UTC+8", 8*3600)
+	originTZ := vars.TimeZone
+	vars.TimeZone = loc
+	defer func() {
+		vars.TimeZone = originTZ
+	}()
+	nowTs := vars.StmtCtx.GetNowTsCached()
+
+	tests := []struct {
+		funcName string
+		args     []interface{}
+		retTp    byte
+		fsp      int
+		expect   string
+	}{
+		{ast.Now, nil, mysql.TypeDatetime, 0, nowTs.In(loc).Format("2006-01-02 15:04:05")},
+		{ast.CurrentTimestamp, []interface{}{3}, mysql.TypeDatetime, 3, nowTs.In(loc).Truncate(time.Millisecond).Format("2006-01-02 15:04:05.000000")},
+		{ast.UTCTimestamp, nil, mysql.TypeDatetime, 0, nowTs.UTC().Format("2006-01-02 15:04:05")},
+		{ast.Curdate, nil, mysql.TypeDate, 0, nowTs.In(loc).Format("2006-01-02")},
+		{ast.UTCDate, nil, mysql.TypeDate, 0, nowTs.UTC().Format("2006-01-02")},
+		{ast.Curtime, nil, mysql.TypeDuration, 0, nowTs.In(loc).Format("15:04:05")},
+		{ast.UTCTime, []interface{}{6}, mysql.TypeDuration, 6, nowTs.UTC().Truncate(time.Microsecond).Format("15:04:05.000000")},
+	}
+	for _, t := range tests {
+		f, err := funcs[t.funcName].getFunction(s.ctx, s.primitiveValsToConstants(t.args))
+		c.Assert(err, IsNil)
+		c.Assert(f.getRetTp().Tp, Equals, t.retTp, Commentf("%v", t.funcName))
+		c.Assert(f.getRetTp().Decimal, Equals, t.fsp, Commentf("%v", t.funcName))
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		str, err := d.ToString()
+		c.Assert(err, IsNil)
+		c.Assert(str, Equals, t.expect, Commentf("%v", t.funcName))
+	}
+
+	_, err := funcs[ast.Now].getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{7}))
+	c.Assert(terror.ErrorEqual(err, types.ErrTooBigPrecision), IsTrue)
+	_, err = funcs[ast.Curtime].getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{-1}))
+	c.Assert(err, NotNil)
+}
+
+func (s *testEvaluatorSuite) TestUnixTimestampAndFromUnixTime(c *C) {
+	vars := s.ctx.GetSessionVars()
+	originTZ := vars.TimeZone
+	vars.TimeZone = time.FixedZone("UTC+8", 8*3600)
+	defer func() {
+		vars.TimeZone = originTZ
+	}()
+
+	f, err := funcs[ast.UnixTimestamp].getFunction(s.ctx, nil)
+	c.Assert(err, IsNil)
+	d, err := evalBuiltinFunc(f, chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(d.GetInt64(), Equals, vars.StmtCtx.GetNowTsCached().Unix())
+
+	unixTests := []struct {
+		arg    interface{}
+		expect string
+	}{
+		{"1970-01-01 08:00:01", "1"},
+		{"1970-01-01 08:00:01.5", "1.5"},
+		{"2015-11-13 10:20:19.012", "1447381219.012"},
+		{"1960-01-01", "0"},
+		{"2038-01-20", "0"},
+		{nil, "<nil>"},
+	}
+	for _, t := range unixTests {
+		f, err := funcs[ast.UnixTimestamp].getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{t.arg}))
+		c.Assert(err, IsNil)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		str := "<nil>"
+		if !d.IsNull() {
+			str, err = d.ToString()
+			c.Assert(err, IsNil)
+		}
+		c.Assert(str, Equals, t.expect, Commentf("%v", t.arg))
+	}
+
+	fromTests := []struct {
+		args   []interface{}
+		expect string
+	}{
+		{[]interface{}{0}, "1970-01-01 08:00:00"},
+		{[]interface{}{1447381219}, "2015-11-13 10:20:19"},
+		{[]interface{}{1, "%Y-%m-%d %H:%i:%s"}, "1970-01-01 08:00:01"},
+		{[]interface{}{1447381219, "%Y %D %M"}, "2015 13th November"},
+		{[]interface{}{-1}, "<nil>"},
+		{[]interface{}{int64(1) << 32}, "<nil>"},
+	}
+	for _, t := range fromTests {
+		f, err := funcs[ast.FromUnixTime].getFunction(s.ctx, s.primitiveValsToConstants(t.args))
+		c.Assert(err, IsNil)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		str := "<nil>"
+		if !d.IsNull() {
+			str, err = d.ToString()
+			c.Assert(err, IsNil)
+		}
+		c.Assert(str, Equals, t.expect, Commentf("%v", t.args))
+	}
+}
+
+func (s *testEvaluatorSuite) TestExtract(c *C) {
+	tests := []struct {
+		unit   string
+		date   interface{}
+		expect interface{}
+	}{
+		{"MICROSECOND", "2011-11-11 10:10:10.123456", int64(123456)},
+		{"YEAR", "2011-11-11 10:10:10.123456", int64(2011)},
+		{"QUARTER", "2011-11-11 10:10:10.123456", int64(4)},
+		{"WEEK", "2011-11-11 10:10:10.123456", int64(45)},
+		{"YEAR_MONTH", "2011-11-11 10:10:10.123456", int64(201111)},
+		{"DAY_MICROSECOND", "2011-11-11 10:10:10.123456", int64(11101010123456)},
+		{"HOUR_SECOND", "2011-11-11 10:10:10.123456", int64(101010)},
+		{"DAY", "abc", nil},
+	}
+	for _, t := range tests {
+		f, err := funcs[ast.Extract].getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{t.unit, t.date}))
+		c.Assert(err, IsNil)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.expect), Commentf("%v", t))
+	}
+
+	// A unit without the date part extracts from a duration.
+	dur, err := types.ParseDuration(s.ctx.GetSessionVars().StmtCtx, "-100:20:30.5", types.MaxFsp)
+	c.Assert(err, IsNil)
+	durTests := []struct {
+		unit   string
+		expect int64
+	}{
+		{"HOUR", -100},
+		{"MINUTE_SECOND", -2030},
+		{"HOUR_MICROSECOND", -1002030500000},
+	}
+	for _, t := range durTests {
+		arg := &Constant{Value: types.NewDurationDatum(dur), RetType: types.NewFieldType(mysql.TypeDuration)}
+		f, err := funcs[ast.Extract].getFunction(s.ctx, []Expression{newUnitConstant(t.unit), arg})
+		c.Assert(err, IsNil)
+		c.Assert(f, FitsTypeOf, &builtinExtractDurationSig{})
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d.GetInt64(), Equals, t.expect, Commentf("%v", t.unit))
+	}
+}
+
+func (s *testEvaluatorSuite) TestTimestampDiff(c *C) {
+	tests := []struct {
+		unit   string
+		t1     interface{}
+		t2     interface{}
+		expect interface{}
+	}{
+		{"MONTH", "2003-02-01", "2003-05-01", int64(3)},
+		{"YEAR", "2002-05-01", "2001-01-01", int64(-1)},
+		{"MINUTE", "2003-02-01", "2003-05-01 12:05:55", int64(128885)},
+		{"QUARTER", "2003-02-01", "2004-05-01", int64(5)},
+		{"WEEK", "2003-02-01", "2003-02-15", int64(2)},
+		{"SECOND", "2003-02-01 00:00:00", "2003-02-01 00:00:01.9", int64(1)},
+		{"MICROSECOND", "2003-02-01 00:00:00", "2003-02-01 00:00:01.5", int64(1500000)},
+		{"DAY", "abc", "2003-02-01", nil},
+	}
+	for _, t := range tests {
+		f, err := funcs[ast.TimestampDiff].getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{t.unit, t.t1, t.t2}))
+		c.Assert(err, IsNil)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.expect), Commentf("%v", t))
+	}
+}
+
+func (s *testEvaluatorSuite) TestDateArith(c *C) {
+	tests := []struct {
+		funcName string
+		date     interface{}
+		interval interface{}
+		unit     string
+		expect   interface{}
+	}{
+		{ast.DateAdd, "2011-11-11", 1, "DAY", "2011-11-12"},
+		{ast.DateAdd, "2011-11-11", 10, "HOUR", "2011-11-11 10:00:00"},
+		{ast.DateAdd, "2011-11-11 10:10:10", "1 1:1:1", "DAY_SECOND", "2011-11-12 11:11:11"},
+		{ast.DateAdd, "2018-01-31", 1, "MONTH", "2018-02-28"},
+		{ast.DateAdd, "2011-11-11", "1.5", "SECOND", "2011-11-11 00:00:01.500000"},
+		{ast.AddDate, "2011-11-11", -1, "WEEK", "2011-11-04"},
+		{ast.DateAdd, "9999-12-31", 1, "DAY", nil},
+		{ast.DateAdd, "abc", 1, "DAY", nil},
+		{ast.DateAdd, "2011-11-11", nil, "DAY", nil},
+		{ast.DateSub, "2011-01-01", "1 1", "YEAR_MONTH", "2009-12-01"},
+		{ast.SubDate, "2011-01-01 00:00:00", 1, "SECOND", "2010-12-31 23:59:59"},
+	}
+	for _, t := range tests {
+		args := s.primitiveValsToConstants([]interface{}{t.date, t.interval})
+		args = append(args, newUnitConstant(t.unit))
+		f, err := funcs[t.funcName].getFunction(s.ctx, args)
+		c.Assert(err, IsNil)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.expect), Commentf("%v", t))
+	}
+
+	// The result is a date if the date is of a date type and the unit has no time part.
+	date := types.NewTime(types.FromDate(2011, 11, 11, 0, 0, 0, 0), mysql.TypeDate, types.DefaultFsp)
+	dateArg := &Constant{Value: types.NewTimeDatum(date), RetType: types.NewFieldType(mysql.TypeDate)}
+	unitTests := []struct {
+		unit   string
+		retTp  byte
+		expect string
+	}{
+		{"DAY", mysql.TypeDate, "2011-11-12"},
+		{"HOUR", mysql.TypeDatetime, "2011-11-11 01:00:00"},
+	}
+	for _, t := range unitTests {
+		args := []Expression{dateArg, s.primitiveValsToConstants([]interface{}{1})[0], newUnitConstant(t.unit)}
+		f, err := funcs[ast.DateAdd].getFunction(s.ctx, args)
+		c.Assert(err, IsNil)
+		c.Assert(f.getRetTp().Tp, Equals, t.retTp, Commentf("%v", t.unit))
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d.GetMysqlTime().String(), Equals, t.expect, Commentf("%v", t.unit))
+	}
+}
@@ -0,0 +1,836 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"time"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// vecFillTime fills the n rows of result with t.
+func vecFillTime(n int, result *chunk.Column, t types.Time) {
+	result.ResizeTime(n, false)
+	times := result.Times()
+	for i := range times {
+		times[i] = t
+	}
+}
+
+// vecFillDuration fills the n rows of result with d.
+func vecFillDuration(n int, result *chunk.Column, d types.Duration) {
+	result.ResizeGoDuration(n, false)
+	ds := result.GoDurations()
+	for i := range ds {
+		ds[i] = d.Duration
+	}
+}
+
+// vecEvalTimeWithFsp evaluates the fsp argument of functions like NOW(fsp)
+// and fills result with the time gen returns for it.
+func vecEvalTimeWithFsp(b *baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column, funcName string, gen func(fsp int8) types.Time) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err = b.args[0].VecEvalInt(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeTime(n, false)
+	result.MergeNulls(buf)
+	fsps := buf.Int64s()
+	times := result.Times()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		fsp, err := checkFspArg(fsps[i], funcName)
+		if err != nil {
+			return err
+		}
+		times[i] = gen(fsp)
+	}
+	return nil
+}
+
+// vecEvalDurationWithFsp evaluates the fsp argument of functions like
+// CURTIME(fsp) and fills result with the duration gen returns for it.
+func vecEvalDurationWithFsp(b *baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column, funcName string, gen func(fsp int8) (types.Duration, error)) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err = b.args[0].VecEvalInt(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeGoDuration(n, false)
+	result.MergeNulls(buf)
+	fsps := buf.Int64s()
+	ds := result.GoDurations()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		fsp, err := checkFspArg(fsps[i], funcName)
+		if err != nil {
+			return err
+		}
+		d, err := gen(fsp)
+		if err != nil {
+			return err
+		}
+		ds[i] = d.Duration
+	}
+	return nil
+}
+
+// vecEvalIntFromTime evaluates the time argument and fills result with the
+// int f extracts from it.
+func vecEvalIntFromTime(b *baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column, f func(t types.Time) int64) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETDatetime, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err = b.args[0].VecEvalTime(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf)
+	times := buf.Times()
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		i64s[i] = f(times[i])
+	}
+	return nil
+}
+
+func (b *builtinNowWithArgSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinNowWithArgSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalTimeWithFsp(&b.baseBuiltinFunc, input, result, "now", func(fsp int8) types.Time {
+		return evalNowWithFsp(b.ctx, fsp)
+	})
+}
+
+func (b *builtinNowWithoutArgSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinNowWithoutArgSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
+	vecFillTime(input.NumRows(), result, evalNowWithFsp(b.ctx, types.DefaultFsp))
+	return nil
+}
+
+func (b *builtinUTCTimestampWithArgSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUTCTimestampWithArgSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalTimeWithFsp(&b.baseBuiltinFunc, input, result, "utc_timestamp", func(fsp int8) types.Time {
+		return evalUTCNowWithFsp(b.ctx, fsp)
+	})
+}
+
+func (b *builtinUTCTimestampWithoutArgSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUTCTimestampWithoutArgSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
+	vecFillTime(input.NumRows(), result, evalUTCNowWithFsp(b.ctx, types.DefaultFsp))
+	return nil
+}
+
+func (b *builtinSysDateWithFspSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSysDateWithFspSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalTimeWithFsp(&b.baseBuiltinFunc, input, result, "sysdate", func(fsp int8) types.Time {
+		return evalSysDateWithFsp(b.ctx, fsp)
+	})
+}
+
+func (b *builtinSysDateWithoutFspSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSysDateWithoutFspSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.ResizeTime(n, false)
+	times := result.Times()
+	for i := range times {
+		times[i] = evalSysDateWithFsp(b.ctx, types.DefaultFsp)
+	}
+	return nil
+}
+
+func (b *builtinCurrentDateSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCurrentDateSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
+	vecFillTime(input.NumRows(), result, goTimeToDate(getStmtTimestamp(b.ctx).In(b.ctx.GetSessionVars().Location())))
+	return nil
+}
+
+func (b *builtinUTCDateSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUTCDateSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
+	vecFillTime(input.NumRows(), result, goTimeToDate(getStmtTimestamp(b.ctx).UTC()))
+	return nil
+}
+
+func (b *builtinCurrentTime0ArgSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCurrentTime0ArgSig) vecEvalDuration(input *chunk.Chunk, result *chunk.Column) error {
+	d, err := goTimeToDuration(getStmtTimestamp(b.ctx).In(b.ctx.GetSessionVars().Location()), types.DefaultFsp)
+	if err != nil {
+		return err
+	}
+	vecFillDuration(input.NumRows(), result, d)
+	return nil
+}
+
+func (b *builtinCurrentTime1ArgSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCurrentTime1ArgSig) vecEvalDuration(input *chunk.Chunk, result *chunk.Column) error {
+	now := getStmtTimestamp(b.ctx).In(b.ctx.GetSessionVars().Location())
+	return vecEvalDurationWithFsp(&b.baseBuiltinFunc, input, result, "curtime", func(fsp int8) (types.Duration, error) {
+		return goTimeToDuration(now, fsp)
+	})
+}
+
+func (b *builtinUTCTime0ArgSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUTCTime0ArgSig) vecEvalDuration(input *chunk.Chunk, result *chunk.Column) error {
+	d, err := goTimeToDuration(getStmtTimestamp(b.ctx).UTC(), types.DefaultFsp)
+	if err != nil {
+		return err
+	}
+	vecFillDuration(input.NumRows(), result, d)
+	return nil
+}
+
+func (b *builtinUTCTime1ArgSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUTCTime1ArgSig) vecEvalDuration(input *chunk.Chunk, result *chunk.Column) error {
+	now := getStmtTimestamp(b.ctx).UTC()
+	return vecEvalDurationWithFsp(&b.baseBuiltinFunc, input, result, "utc_time", func(fsp int8) (types.Duration, error) {
+		return goTimeToDuration(now, fsp)
+	})
+}
+
+func (b *builtinYearSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinYearSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalIntFromTime(&b.baseBuiltinFunc, input, result, func(t types.Time) int64 {
+		return int64(t.Year())
+	})
+}
+
+func (b *builtinMonthSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinMonthSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalIntFromTime(&b.baseBuiltinFunc, input, result, func(t types.Time) int64 {
+		return int64(t.Month())
+	})
+}
+
+func (b *builtinDayOfMonthSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinDayOfMonthSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalIntFromTime(&b.baseBuiltinFunc, input, result, func(t types.Time) int64 {
+		return int64(t.Day())
+	})
+}
+
+func (b *builtinDateDiffSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinDateDiffSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufLHS, err := b.bufAllocator.get(types.ETDatetime, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufLHS)
+	if err = b.args[0].VecEvalTime(b.ctx, input, bufLHS); err != nil {
+		return err
+	}
+	bufRHS, err := b.bufAllocator.get(types.ETDatetime, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufRHS)
+	if err = b.args[1].VecEvalTime(b.ctx, input, bufRHS); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(bufLHS, bufRHS)
+	lhs, rhs := bufLHS.Times(), bufRHS.Times()
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		if invalid, err := checkInvalidZeroTime(b.ctx, lhs[i], rhs[i]); invalid {
+			if err != nil {
+				return err
+			}
+			result.SetNull(i, true)
+			continue
+		}
+		i64s[i] = int64(types.DateDiff(lhs[i].CoreTime(), rhs[i].CoreTime()))
+	}
+	return nil
+}
+
+func (b *builtinDateFormatSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinDateFormatSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufTime, err := b.bufAllocator.get(types.ETDatetime, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufTime)
+	if err = b.args[0].VecEvalTime(b.ctx, input, bufTime); err != nil {
+		return err
+	}
+	bufFormat, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufFormat)
+	if err = b.args[1].VecEvalString(b.ctx, input, bufFormat); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	times := bufTime.Times()
+	for i := 0; i < n; i++ {
+		if bufTime.IsNull(i) || bufFormat.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		if invalid, err := checkInvalidZeroTime(b.ctx, times[i]); invalid {
+			if err != nil {
+				return err
+			}
+			result.AppendNull()
+			continue
+		}
+		res, err := times[i].DateFormat(bufFormat.GetString(i))
+		if err != nil {
+			return err
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+// vecEvalStrToDate evaluates the date and the format of STR_TO_DATE, and
+// calls set with the parsed time of every row that is not NULL.
+func vecEvalStrToDate(b *baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column, set func(i int, t types.Time) error) error {
+	n := input.NumRows()
+	bufDate, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufDate)
+	if err = b.args[0].VecEvalString(b.ctx, input, bufDate); err != nil {
+		return err
+	}
+	bufFormat, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufFormat)
+	if err = b.args[1].VecEvalString(b.ctx, input, bufFormat); err != nil {
+		return err
+	}
+
+	result.MergeNulls(bufDate, bufFormat)
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		t, isNull, err := strToDate(b.ctx, bufDate.GetString(i), bufFormat.GetString(i))
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.SetNull(i, true)
+			continue
+		}
+		if err = set(i, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *builtinStrToDateDateSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinStrToDateDateSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
+	result.ResizeTime(input.NumRows(), false)
+	times := result.Times()
+	return vecEvalStrToDate(&b.baseBuiltinFunc, input, result, func(i int, t types.Time) error {
+		t.SetType(mysql.TypeDate)
+		t.SetFsp(types.MinFsp)
+		times[i] = t
+		return nil
+	})
+}
+
+func (b *builtinStrToDateDatetimeSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinStrToDateDatetimeSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
+	result.ResizeTime(input.NumRows(), false)
+	times := result.Times()
+	return vecEvalStrToDate(&b.baseBuiltinFunc, input, result, func(i int, t types.Time) error {
+		t.SetFsp(int8(b.tp.Decimal))
+		times[i] = t
+		return nil
+	})
+}
+
+func (b *builtinStrToDateDurationSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinStrToDateDurationSig) vecEvalDuration(input *chunk.Chunk, result *chunk.Column) error {
+	result.ResizeGoDuration(input.NumRows(), false)
+	ds := result.GoDurations()
+	return vecEvalStrToDate(&b.baseBuiltinFunc, input, result, func(i int, t types.Time) error {
+		t.SetFsp(int8(b.tp.Decimal))
+		d, err := t.ConvertToDuration()
+		ds[i] = d.Duration
+		return err
+	})
+}
+
+func (b *builtinUnixTimestampCurrentSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUnixTimestampCurrentSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.ResizeInt64(n, false)
+	ts := getStmtTimestamp(b.ctx).Unix()
+	i64s := result.Int64s()
+	for i := range i64s {
+		i64s[i] = ts
+	}
+	return nil
+}
+
+func (b *builtinUnixTimestampIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUnixTimestampIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalIntFromTime(&b.baseBuiltinFunc, input, result, func(t types.Time) int64 {
+		return evalUnixTimestamp(b.ctx, t) / int64(time.Second)
+	})
+}
+
+func (b *builtinUnixTimestampDecSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUnixTimestampDecSig) vecEvalDecimal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETDatetime, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err = b.args[0].VecEvalTime(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeDecimal(n, false)
+	result.MergeNulls(buf)
+	times := buf.Times()
+	decs := result.Decimals()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		dec, err := unixNanosToDecimal(evalUnixTimestamp(b.ctx, times[i]), b.tp.Decimal)
+		if err != nil {
+			return err
+		}
+		decs[i] = *dec
+	}
+	return nil
+}
+
+func (b *builtinFromUnixTime1ArgSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinFromUnixTime1ArgSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETDecimal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err = b.args[0].VecEvalDecimal(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeTime(n, false)
+	result.MergeNulls(buf)
+	decs := buf.Decimals()
+	times := result.Times()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		t, isNull, err := evalFromUnixTime(b.ctx, int8(b.tp.Decimal), &decs[i])
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.SetNull(i, true)
+			continue
+		}
+		times[i] = t
+	}
+	return nil
+}
+
+func (b *builtinFromUnixTime2ArgSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinFromUnixTime2ArgSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufDec, err := b.bufAllocator.get(types.ETDecimal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufDec)
+	if err = b.args[0].VecEvalDecimal(b.ctx, input, bufDec); err != nil {
+		return err
+	}
+	bufFormat, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufFormat)
+	if err = b.args[1].VecEvalString(b.ctx, input, bufFormat); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	decs := bufDec.Decimals()
+	for i := 0; i < n; i++ {
+		if bufDec.IsNull(i) || bufFormat.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		t, isNull, err := evalFromUnixTime(b.ctx, types.MaxFsp, &decs[i])
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		res, err := t.DateFormat(bufFormat.GetString(i))
+		if err != nil {
+			return err
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+func (b *builtinExtractDatetimeSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinExtractDatetimeSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufUnit, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufUnit)
+	if err = b.args[0].VecEvalString(b.ctx, input, bufUnit); err != nil {
+		return err
+	}
+	bufTime, err := b.bufAllocator.get(types.ETDatetime, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufTime)
+	if err = b.args[1].VecEvalTime(b.ctx, input, bufTime); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(bufUnit, bufTime)
+	times := bufTime.Times()
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		res, err := types.ExtractDatetimeNum(&times[i], bufUnit.GetString(i))
+		if err != nil {
+			return err
+		}
+		i64s[i] = res
+	}
+	return nil
+}
+
+func (b *builtinExtractDurationSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinExtractDurationSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufUnit, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufUnit)
+	if err = b.args[0].VecEvalString(b.ctx, input, bufUnit); err != nil {
+		return err
+	}
+	bufDur, err := b.bufAllocator.get(types.ETDuration, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufDur)
+	if err = b.args[1].VecEvalDuration(b.ctx, input, bufDur); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(bufUnit, bufDur)
+	ds := bufDur.GoDurations()
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		dur := types.Duration{Duration: ds[i], Fsp: int8(b.args[1].GetType().Decimal)}
+		res, err := types.ExtractDurationNum(&dur, bufUnit.GetString(i))
+		if err != nil {
+			return err
+		}
+		i64s[i] = res
+	}
+	return nil
+}
+
+func (b *builtinTimestampDiffSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTimestampDiffSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufUnit, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufUnit)
+	if err = b.args[0].VecEvalString(b.ctx, input, bufUnit); err != nil {
+		return err
+	}
+	bufLHS, err := b.bufAllocator.get(types.ETDatetime, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufLHS)
+	if err = b.args[1].VecEvalTime(b.ctx, input, bufLHS); err != nil {
+		return err
+	}
+	bufRHS, err := b.bufAllocator.get(types.ETDatetime, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufRHS)
+	if err = b.args[2].VecEvalTime(b.ctx, input, bufRHS); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(bufUnit, bufLHS, bufRHS)
+	lhs, rhs := bufLHS.Times(), bufRHS.Times()
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		if invalid, err := checkInvalidZeroTime(b.ctx, lhs[i], rhs[i]); invalid {
+			if err != nil {
+				return err
+			}
+			result.SetNull(i, true)
+			continue
+		}
+		i64s[i] = types.TimestampDiff(bufUnit.GetString(i), lhs[i], rhs[i])
+	}
+	return nil
+}
+
+func (b *builtinDateArithDatetimeSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinDateArithDatetimeSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufDate, err := b.bufAllocator.get(types.ETDatetime, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufDate)
+	if err = b.args[0].VecEvalTime(b.ctx, input, bufDate); err != nil {
+		return err
+	}
+	bufInterval, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufInterval)
+	if err = b.args[1].VecEvalString(b.ctx, input, bufInterval); err != nil {
+		return err
+	}
+	bufUnit, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufUnit)
+	if err = b.args[2].VecEvalString(b.ctx, input, bufUnit); err != nil {
+		return err
+	}
+
+	result.ResizeTime(n, false)
+	result.MergeNulls(bufDate, bufInterval, bufUnit)
+	dates := bufDate.Times()
+	times := result.Times()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		res, isNull, err := b.arith(dates[i], bufInterval.GetString(i), bufUnit.GetString(i))
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.SetNull(i, true)
+			continue
+		}
+		times[i] = res
+	}
+	return nil
+}
+
+func (b *builtinDateArithStringSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinDateArithStringSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufDate, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufDate)
+	if err = b.args[0].VecEvalString(b.ctx, input, bufDate); err != nil {
+		return err
+	}
+	bufInterval, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufInterval)
+	if err = b.args[1].VecEvalString(b.ctx, input, bufInterval); err != nil {
+		return err
+	}
+	bufUnit, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufUnit)
+	if err = b.args[2].VecEvalString(b.ctx, input, bufUnit); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if bufDate.IsNull(i) || bufInterval.IsNull(i) || bufUnit.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		res, isNull, err := b.arith(bufDate.GetString(i), bufInterval.GetString(i), bufUnit.GetString(i))
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
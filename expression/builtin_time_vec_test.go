@@ -0,0 +1,138 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+)
+
+func newUnitConstant(unit string) *Constant {
+	return &Constant{Value: types.NewStringDatum(unit), RetType: types.NewFieldType(mysql.TypeVarString)}
+}
+
+func newDatetimeFieldType(fsp int) *types.FieldType {
+	ft := types.NewFieldType(mysql.TypeDatetime)
+	ft.Decimal = fsp
+	return ft
+}
+
+var vecBuiltinTimeCases = map[string][]vecExprBenchCase{
+	ast.Now: {
+		{retEvalType: types.ETDatetime},
+		{retEvalType: types.ETDatetime, childrenTypes: []types.EvalType{types.ETInt}, geners: []dataGenerator{&rangeInt64Gener{0, 7}}},
+	},
+	ast.UTCTimestamp: {
+		{retEvalType: types.ETDatetime},
+		{retEvalType: types.ETDatetime, childrenTypes: []types.EvalType{types.ETInt}, geners: []dataGenerator{&rangeInt64Gener{0, 7}}},
+	},
+	ast.Curdate: {
+		{retEvalType: types.ETDatetime},
+	},
+	ast.UTCDate: {
+		{retEvalType: types.ETDatetime},
+	},
+	ast.Curtime: {
+		{retEvalType: types.ETDuration},
+		{retEvalType: types.ETDuration, childrenTypes: []types.EvalType{types.ETInt}, geners: []dataGenerator{&rangeInt64Gener{0, 7}}},
+	},
+	ast.UTCTime: {
+		{retEvalType: types.ETDuration},
+		{retEvalType: types.ETDuration, childrenTypes: []types.EvalType{types.ETInt}, geners: []dataGenerator{&rangeInt64Gener{0, 7}}},
+	},
+	ast.Year: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETDatetime}},
+	},
+	ast.Month: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETDatetime}},
+	},
+	ast.DayOfMonth: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETDatetime}},
+	},
+	ast.DateDiff: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETDatetime, types.ETDatetime}},
+	},
+	ast.DateFormat: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETDatetime, types.ETString},
+			geners: []dataGenerator{nil, &selectStringGener{[]string{"%Y-%m-%d %H:%i:%s", "%W %M %D %Y", "%r %p %f", "%j %U %u %%", "%a %b %c %e"}}}},
+	},
+	ast.StrToDate: {
+		{retEvalType: types.ETDatetime, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners:    []dataGenerator{&selectStringGener{[]string{"2019-10-01", "2019-02-30", "20191001", "abc"}}},
+			constants: []*Constant{nil, {Value: types.NewStringDatum("%Y-%m-%d"), RetType: types.NewFieldType(mysql.TypeVarString)}}},
+		{retEvalType: types.ETDatetime, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners: []dataGenerator{&selectStringGener{[]string{"10/01/2019 10:20:30.123", "01/31/2019 11:59:59 PM", "abc"}},
+				&selectStringGener{[]string{"%m/%d/%Y %H:%i:%s.%f", "%m/%d/%Y %h:%i:%s %p"}}}},
+		{retEvalType: types.ETDuration, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners:    []dataGenerator{&selectStringGener{[]string{"10:20:30", "23:59:59", "25:00:00", "abc"}}},
+			constants: []*Constant{nil, {Value: types.NewStringDatum("%H:%i:%s"), RetType: types.NewFieldType(mysql.TypeVarString)}}},
+	},
+	ast.UnixTimestamp: {
+		{retEvalType: types.ETInt},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETDatetime}, childrenFieldTypes: []*types.FieldType{newDatetimeFieldType(0)},
+			geners: []dataGenerator{&dateTimeGener{1960, 2050, 0}}},
+		{retEvalType: types.ETDecimal, childrenTypes: []types.EvalType{types.ETDatetime}, childrenFieldTypes: []*types.FieldType{newDatetimeFieldType(3)},
+			geners: []dataGenerator{&dateTimeGener{1960, 2050, 3}}},
+	},
+	ast.FromUnixTime: {
+		{retEvalType: types.ETDatetime, childrenTypes: []types.EvalType{types.ETDecimal}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETDecimal, types.ETString},
+			geners: []dataGenerator{nil, &selectStringGener{[]string{"%Y-%m-%d %H:%i:%s", "%Y %D %M %h:%i:%s %x"}}}},
+	},
+	ast.Extract: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETDatetime},
+			constants: []*Constant{newUnitConstant("YEAR_MONTH")}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETDatetime},
+			constants: []*Constant{newUnitConstant("DAY_MICROSECOND")}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETDuration},
+			constants: []*Constant{newUnitConstant("HOUR_MICROSECOND")}},
+	},
+	ast.TimestampDiff: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETDatetime, types.ETDatetime},
+			geners: []dataGenerator{&selectStringGener{[]string{"YEAR", "QUARTER", "MONTH", "WEEK", "DAY", "HOUR", "MINUTE", "SECOND", "MICROSECOND"}}}},
+	},
+	ast.DateAdd: {
+		{retEvalType: types.ETDatetime, childrenTypes: []types.EvalType{types.ETDatetime, types.ETString, types.ETString},
+			geners:    []dataGenerator{nil, &selectStringGener{[]string{"1", "-10", "100000", "abc"}}},
+			constants: []*Constant{nil, nil, newUnitConstant("DAY")}},
+		{retEvalType: types.ETDatetime, childrenTypes: []types.EvalType{types.ETDatetime, types.ETString, types.ETString},
+			geners:    []dataGenerator{nil, &selectStringGener{[]string{"1 1:1:1.5", "-1 10:00:00", "1:1"}}},
+			constants: []*Constant{nil, nil, newUnitConstant("DAY_MICROSECOND")}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString},
+			geners: []dataGenerator{&selectStringGener{[]string{"2019-01-31", "2019-10-01 10:20:30", "20191001", "abc"}},
+				&selectStringGener{[]string{"1", "-1", "12"}}},
+			constants: []*Constant{nil, nil, newUnitConstant("MONTH")}},
+	},
+	ast.DateSub: {
+		{retEvalType: types.ETDatetime, childrenTypes: []types.EvalType{types.ETDatetime, types.ETString, types.ETString},
+			geners:    []dataGenerator{nil, &selectStringGener{[]string{"1", "-10", "1.5"}}},
+			constants: []*Constant{nil, nil, newUnitConstant("SECOND")}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString},
+			geners: []dataGenerator{&selectStringGener{[]string{"2019-03-31", "2019-10-01 10:20:30.5", "abc"}},
+				&selectStringGener{[]string{"1", "10", "-100"}}},
+			constants: []*Constant{nil, nil, newUnitConstant("HOUR")}},
+	},
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinTimeFunc(c *C) {
+	testVectorizedBuiltinFunc(c, vecBuiltinTimeCases)
+}
+
+func BenchmarkVectorizedBuiltinTimeFunc(b *testing.B) {
+	benchmarkVectorizedBuiltinFunc(b, vecBuiltinTimeCases)
+}
@@ -14,8 +14,6 @@
 package expression
 
 import (
-	"strconv"
-
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
@@ -82,6 +80,21 @@ func evalOneVec(ctx sessionctx.Context, expr Expression, input *chunk.Chunk, out
 		if err := expr.VecEvalInt(ctx, input, result); err != nil {
 			return err
 		}
+		if ft.Tp == mysql.TypeBit {
+			i64s := result.Int64s()
+			n := input.NumRows()
+			buf := chunk.NewColumn(ft, n)
+			buf.ReserveBytes(n)
+			byteSize := (ft.Flen + 7) >> 3
+			for i := range i64s {
+				if result.IsNull(i) {
+					buf.AppendNull()
+				} else {
+					buf.AppendBytes(types.NewBinaryLiteralFromUint(uint64(i64s[i]), byteSize))
+				}
+			}
+			output.SetCol(colIdx, buf)
+		}
 	case types.ETReal:
 		if err := expr.VecEvalReal(ctx, input, result); err != nil {
 			return err
@@ -101,10 +114,41 @@ func evalOneVec(ctx sessionctx.Context, expr Expression, input *chunk.Chunk, out
 			}
 			output.SetCol(colIdx, buf)
 		}
+	case types.ETDecimal:
+		if err := expr.VecEvalDecimal(ctx, input, result); err != nil {
+			return err
+		}
+	case types.ETDatetime, types.ETTimestamp:
+		if err := expr.VecEvalTime(ctx, input, result); err != nil {
+			return err
+		}
+	case types.ETDuration:
+		if err := expr.VecEvalDuration(ctx, input, result); err != nil {
+			return err
+		}
 	case types.ETString:
 		if err := expr.VecEvalString(ctx, input, result); err != nil {
 			return err
 		}
+		if ft.Tp == mysql.TypeEnum || ft.Tp == mysql.TypeSet {
+			n := input.NumRows()
+			buf := chunk.NewColumn(ft, n)
+			buf.ReserveEnum(n)
+			for i := 0; i < n; i++ {
+				if result.IsNull(i) {
+					buf.AppendNull()
+				} else if d := hybridDatumFromString(ft, result.GetString(i)); ft.Tp == mysql.TypeEnum {
+					buf.AppendEnum(d.GetMysqlEnum())
+				} else {
+					buf.AppendSet(d.GetMysqlSet())
+				}
+			}
+			output.SetCol(colIdx, buf)
+		}
+	case types.ETJson:
+		if err := expr.VecEvalJSON(ctx, input, result); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -119,10 +163,26 @@ func evalOneColumn(ctx sessionctx.Context, expr Expression, iterator *chunk.Iter
 		for row := iterator.Begin(); err == nil && row != iterator.End(); row = iterator.Next() {
 			err = executeToReal(ctx, expr, fieldType, row, output, colID)
 		}
+	case types.ETDecimal:
+		for row := iterator.Begin(); err == nil && row != iterator.End(); row = iterator.Next() {
+			err = executeToDecimal(ctx, expr, fieldType, row, output, colID)
+		}
+	case types.ETDatetime, types.ETTimestamp:
+		for row := iterator.Begin(); err == nil && row != iterator.End(); row = iterator.Next() {
+			err = executeToDatetime(ctx, expr, fieldType, row, output, colID)
+		}
+	case types.ETDuration:
+		for row := iterator.Begin(); err == nil && row != iterator.End(); row = iterator.Next() {
+			err = executeToDuration(ctx, expr, fieldType, row, output, colID)
+		}
 	case types.ETString:
 		for row := iterator.Begin(); err == nil && row != iterator.End(); row = iterator.Next() {
 			err = executeToString(ctx, expr, fieldType, row, output, colID)
 		}
+	case types.ETJson:
+		for row := iterator.Begin(); err == nil && row != iterator.End(); row = iterator.Next() {
+			err = executeToJSON(ctx, expr, fieldType, row, output, colID)
+		}
 	}
 	return err
 }
@@ -133,8 +193,16 @@ func evalOneCell(ctx sessionctx.Context, expr Expression, row chunk.Row, output
 		err = executeToInt(ctx, expr, fieldType, row, output, colID)
 	case types.ETReal:
 		err = executeToReal(ctx, expr, fieldType, row, output, colID)
+	case types.ETDecimal:
+		err = executeToDecimal(ctx, expr, fieldType, row, output, colID)
+	case types.ETDatetime, types.ETTimestamp:
+		err = executeToDatetime(ctx, expr, fieldType, row, output, colID)
+	case types.ETDuration:
+		err = executeToDuration(ctx, expr, fieldType, row, output, colID)
 	case types.ETString:
 		err = executeToString(ctx, expr, fieldType, row, output, colID)
+	case types.ETJson:
+		err = executeToJSON(ctx, expr, fieldType, row, output, colID)
 	}
 	return err
 }
@@ -149,7 +217,7 @@ func executeToInt(ctx sessionctx.Context, expr Expression, fieldType *types.Fiel
 		return nil
 	}
 	if fieldType.Tp == mysql.TypeBit {
-		output.AppendBytes(colID, strconv.AppendUint(make([]byte, 0, 8), uint64(res), 10))
+		output.AppendBytes(colID, types.NewBinaryLiteralFromUint(uint64(res), (fieldType.Flen+7)>>3))
 		return nil
 	}
 	if mysql.HasUnsignedFlag(fieldType.Flag) {
@@ -177,6 +245,45 @@ func executeToReal(ctx sessionctx.Context, expr Expression, fieldType *types.Fie
 	return nil
 }
 
+func executeToDecimal(ctx sessionctx.Context, expr Expression, fieldType *types.FieldType, row chunk.Row, output *chunk.Chunk, colID int) error {
+	res, isNull, err := expr.EvalDecimal(ctx, row)
+	if err != nil {
+		return err
+	}
+	if isNull {
+		output.AppendNull(colID)
+		return nil
+	}
+	output.AppendMyDecimal(colID, res)
+	return nil
+}
+
+func executeToDatetime(ctx sessionctx.Context, expr Expression, fieldType *types.FieldType, row chunk.Row, output *chunk.Chunk, colID int) error {
+	res, isNull, err := expr.EvalTime(ctx, row)
+	if err != nil {
+		return err
+	}
+	if isNull {
+		output.AppendNull(colID)
+	} else {
+		output.AppendTime(colID, res)
+	}
+	return nil
+}
+
+func executeToDuration(ctx sessionctx.Context, expr Expression, fieldType *types.FieldType, row chunk.Row, output *chunk.Chunk, colID int) error {
+	res, isNull, err := expr.EvalDuration(ctx, row)
+	if err != nil {
+		return err
+	}
+	if isNull {
+		output.AppendNull(colID)
+	} else {
+		output.AppendDuration(colID, res)
+	}
+	return nil
+}
+
 func executeToString(ctx sessionctx.Context, expr Expression, fieldType *types.FieldType, row chunk.Row, output *chunk.Chunk, colID int) error {
 	res, isNull, err := expr.EvalString(ctx, row)
 	if err != nil {
@@ -184,12 +291,33 @@ func executeToString(ctx sessionctx.Context, expr Expression, fieldType *types.F
 	}
 	if isNull {
 		output.AppendNull(colID)
+	} else if fieldType.Tp == mysql.TypeEnum || fieldType.Tp == mysql.TypeSet {
+		d := hybridDatumFromString(fieldType, res)
+		output.AppendDatum(colID, &d)
 	} else {
 		output.AppendString(colID, res)
 	}
 	return nil
 }
 
+// hybridDatumFromString converts the string result of an ENUM/SET typed
+// expression to the datum stored in chunk, which keeps the index value
+// together with the name. Names outside of the element list keep value 0.
+func hybridDatumFromString(fieldType *types.FieldType, str string) types.Datum {
+	if fieldType.Tp == mysql.TypeEnum {
+		enum, err := types.ParseEnumName(fieldType.Elems, str)
+		if err != nil {
+			enum = types.Enum{Name: str}
+		}
+		return types.NewMysqlEnumDatum(enum)
+	}
+	set, err := types.ParseSetName(fieldType.Elems, str)
+	if err != nil {
+		set = types.Set{Name: str}
+	}
+	return types.NewDatum(set)
+}
+
 // VectorizedFilter applies a list of filters to a Chunk and
 // returns a bool slice, which indicates whether a row is passed the filters.
 // Filters is executed vectorized.
@@ -311,3 +439,16 @@ func vectorizedFilter(ctx sessionctx.Context, filters []Expression, iterator *ch
 
 	return selected, isNull, nil
 }
+
+func executeToJSON(ctx sessionctx.Context, expr Expression, fieldType *types.FieldType, row chunk.Row, output *chunk.Chunk, colID int) error {
+	res, isNull, err := expr.EvalJSON(ctx, row)
+	if err != nil {
+		return err
+	}
+	if isNull {
+		output.AppendNull(colID)
+	} else {
+		output.AppendJSON(colID, res)
+	}
+	return nil
+}
@@ -23,10 +23,163 @@ import (
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
 )
 
+// CorrelatedColumn stands for a column in a correlated sub query.
+type CorrelatedColumn struct {
+	Column
+
+	Data *types.Datum
+}
+
+// Clone implements Expression interface.
+func (col *CorrelatedColumn) Clone() Expression {
+	return col
+}
+
+// VecEvalInt evaluates this expression in a vectorized manner.
+func (col *CorrelatedColumn) VecEvalInt(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, col, types.ETInt, input, result)
+}
+
+// VecEvalReal evaluates this expression in a vectorized manner.
+func (col *CorrelatedColumn) VecEvalReal(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, col, types.ETReal, input, result)
+}
+
+// VecEvalString evaluates this expression in a vectorized manner.
+func (col *CorrelatedColumn) VecEvalString(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, col, types.ETString, input, result)
+}
+
+// VecEvalDecimal evaluates this expression in a vectorized manner.
+func (col *CorrelatedColumn) VecEvalDecimal(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, col, types.ETDecimal, input, result)
+}
+
+// VecEvalTime evaluates this expression in a vectorized manner.
+func (col *CorrelatedColumn) VecEvalTime(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, col, types.ETTimestamp, input, result)
+}
+
+// VecEvalDuration evaluates this expression in a vectorized manner.
+func (col *CorrelatedColumn) VecEvalDuration(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, col, types.ETDuration, input, result)
+}
+
+// VecEvalJSON evaluates this expression in a vectorized manner.
+func (col *CorrelatedColumn) VecEvalJSON(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, col, types.ETJson, input, result)
+}
+
+// Eval implements Expression interface.
+func (col *CorrelatedColumn) Eval(row chunk.Row) (types.Datum, error) {
+	return *col.Data, nil
+}
+
+// EvalInt returns int representation of CorrelatedColumn.
+func (col *CorrelatedColumn) EvalInt(ctx sessionctx.Context, row chunk.Row) (int64, bool, error) {
+	if col.Data.IsNull() {
+		return 0, true, nil
+	}
+	if col.GetType().Hybrid() || col.Data.Kind() == types.KindMysqlTime || col.Data.Kind() == types.KindMysqlDuration || col.Data.Kind() == types.KindMysqlJSON {
+		res, err := col.Data.ToInt64(ctx.GetSessionVars().StmtCtx)
+		return res, err != nil, err
+	}
+	return col.Data.GetInt64(), false, nil
+}
+
+// EvalReal returns real representation of CorrelatedColumn.
+func (col *CorrelatedColumn) EvalReal(ctx sessionctx.Context, row chunk.Row) (float64, bool, error) {
+	if col.Data.IsNull() {
+		return 0, true, nil
+	}
+	if col.GetType().Hybrid() || col.Data.Kind() == types.KindMysqlTime || col.Data.Kind() == types.KindMysqlDuration || col.Data.Kind() == types.KindMysqlJSON {
+		res, err := col.Data.ToFloat64(ctx.GetSessionVars().StmtCtx)
+		return res, err != nil, err
+	}
+	return col.Data.GetFloat64(), false, nil
+}
+
+// EvalString returns string representation of CorrelatedColumn.
+func (col *CorrelatedColumn) EvalString(ctx sessionctx.Context, row chunk.Row) (string, bool, error) {
+	if col.Data.IsNull() {
+		return "", true, nil
+	}
+	res, err := col.Data.ToString()
+	return res, err != nil, err
+}
+
+// EvalDecimal returns decimal representation of CorrelatedColumn.
+func (col *CorrelatedColumn) EvalDecimal(ctx sessionctx.Context, row chunk.Row) (*types.MyDecimal, bool, error) {
+	if col.Data.IsNull() {
+		return nil, true, nil
+	}
+	return col.Data.GetMysqlDecimal(), false, nil
+}
+
+// EvalTime returns DATE/DATETIME/TIMESTAMP representation of CorrelatedColumn.
+func (col *CorrelatedColumn) EvalTime(ctx sessionctx.Context, row chunk.Row) (types.Time, bool, error) {
+	if col.Data.IsNull() {
+		return types.ZeroTime, true, nil
+	}
+	return datumToTime(ctx, *col.Data, col.RetType)
+}
+
+// EvalDuration returns Duration representation of CorrelatedColumn.
+func (col *CorrelatedColumn) EvalDuration(ctx sessionctx.Context, row chunk.Row) (types.Duration, bool, error) {
+	if col.Data.IsNull() {
+		return types.ZeroDuration, true, nil
+	}
+	return datumToDuration(ctx, *col.Data, col.RetType)
+}
+
+// EvalJSON returns JSON representation of CorrelatedColumn.
+func (col *CorrelatedColumn) EvalJSON(ctx sessionctx.Context, row chunk.Row) (json.BinaryJSON, bool, error) {
+	if col.Data.IsNull() {
+		return json.BinaryJSON{}, true, nil
+	}
+	return datumToJSON(ctx, *col.Data)
+}
+
+// Equal implements Expression interface.
+func (col *CorrelatedColumn) Equal(ctx sessionctx.Context, expr Expression) bool {
+	if cc, ok := expr.(*CorrelatedColumn); ok {
+		return col.Column.Equal(ctx, &cc.Column)
+	}
+	return false
+}
+
+// IsCorrelated implements Expression interface.
+func (col *CorrelatedColumn) IsCorrelated() bool {
+	return true
+}
+
+// ConstItem implements Expression interface.
+func (col *CorrelatedColumn) ConstItem() bool {
+	return false
+}
+
+// Decorrelate implements Expression interface.
+func (col *CorrelatedColumn) Decorrelate(schema *Schema) Expression {
+	if !schema.Contains(&col.Column) {
+		return col
+	}
+	return &col.Column
+}
+
+// ResolveIndices implements Expression interface.
+func (col *CorrelatedColumn) ResolveIndices(_ *Schema) (Expression, error) {
+	return col, nil
+}
+
+func (col *CorrelatedColumn) resolveIndices(_ *Schema) error {
+	return nil
+}
+
 // Column represents a column.
 type Column struct {
 	RetType *types.FieldType
@@ -41,6 +194,10 @@ type Column struct {
 
 	hashcode []byte
 
+	// InOperand indicates whether this column is the inner operand of column equal condition converted
+	// from `[not] in (subq)`.
+	InOperand bool
+
 	OrigName string
 }
 
@@ -54,7 +211,7 @@ func (col *Column) Equal(_ sessionctx.Context, expr Expression) bool {
 
 // VecEvalInt evaluates this expression in a vectorized manner.
 func (col *Column) VecEvalInt(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
-	if col.RetType.Hybrid() {
+	if col.RetType.Hybrid() || col.RetType.Tp == mysql.TypeNewDecimal || col.RetType.Tp == mysql.TypeJSON || isTemporalColumn(col) {
 		it := chunk.NewIterator4Chunk(input)
 		result.ResizeInt64(0, false)
 		for row := it.Begin(); row != it.End(); row = it.Next() {
@@ -103,13 +260,29 @@ func (col *Column) VecEvalReal(ctx sessionctx.Context, input *chunk.Chunk, resul
 		}
 		return nil
 	}
+	if col.GetType().Hybrid() || col.GetType().Tp == mysql.TypeNewDecimal || col.GetType().Tp == mysql.TypeJSON || isTemporalColumn(col) {
+		it := chunk.NewIterator4Chunk(input)
+		result.ResizeFloat64(0, false)
+		for row := it.Begin(); row != it.End(); row = it.Next() {
+			v, null, err := col.EvalReal(ctx, row)
+			if err != nil {
+				return err
+			}
+			if null {
+				result.AppendNull()
+			} else {
+				result.AppendFloat64(v)
+			}
+		}
+		return nil
+	}
 	input.Column(col.Index).CopyReconstruct(input.Sel(), result)
 	return nil
 }
 
 // VecEvalString evaluates this expression in a vectorized manner.
 func (col *Column) VecEvalString(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
-	if col.RetType.Hybrid() || ctx.GetSessionVars().StmtCtx.PadCharToFullLength {
+	if col.RetType.Hybrid() || ctx.GetSessionVars().StmtCtx.PadCharToFullLength || col.RetType.Tp == mysql.TypeJSON || isTemporalColumn(col) {
 		it := chunk.NewIterator4Chunk(input)
 		result.ReserveString(input.NumRows())
 		for row := it.Begin(); row != it.End(); row = it.Next() {
@@ -129,6 +302,94 @@ func (col *Column) VecEvalString(ctx sessionctx.Context, input *chunk.Chunk, res
 	return nil
 }
 
+// VecEvalDecimal evaluates this expression in a vectorized manner.
+func (col *Column) VecEvalDecimal(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	if col.GetType().EvalType() != types.ETDecimal {
+		it := chunk.NewIterator4Chunk(input)
+		result.ResizeDecimal(0, false)
+		for row := it.Begin(); row != it.End(); row = it.Next() {
+			v, null, err := col.EvalDecimal(ctx, row)
+			if err != nil {
+				return err
+			}
+			if null {
+				result.AppendNull()
+			} else {
+				result.AppendMyDecimal(v)
+			}
+		}
+		return nil
+	}
+	input.Column(col.Index).CopyReconstruct(input.Sel(), result)
+	return nil
+}
+
+// VecEvalTime evaluates this expression in a vectorized manner.
+func (col *Column) VecEvalTime(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	if !types.IsTypeTime(col.GetType().Tp) {
+		it := chunk.NewIterator4Chunk(input)
+		result.ResizeTime(0, false)
+		for row := it.Begin(); row != it.End(); row = it.Next() {
+			v, null, err := col.EvalTime(ctx, row)
+			if err != nil {
+				return err
+			}
+			if null {
+				result.AppendNull()
+			} else {
+				result.AppendTime(v)
+			}
+		}
+		return nil
+	}
+	input.Column(col.Index).CopyReconstruct(input.Sel(), result)
+	return nil
+}
+
+// VecEvalDuration evaluates this expression in a vectorized manner.
+func (col *Column) VecEvalDuration(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	if col.GetType().Tp != mysql.TypeDuration {
+		it := chunk.NewIterator4Chunk(input)
+		result.ResizeGoDuration(0, false)
+		for row := it.Begin(); row != it.End(); row = it.Next() {
+			v, null, err := col.EvalDuration(ctx, row)
+			if err != nil {
+				return err
+			}
+			if null {
+				result.AppendNull()
+			} else {
+				result.AppendDuration(v)
+			}
+		}
+		return nil
+	}
+	input.Column(col.Index).CopyReconstruct(input.Sel(), result)
+	return nil
+}
+
+// VecEvalJSON evaluates this expression in a vectorized manner.
+func (col *Column) VecEvalJSON(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	if col.GetType().Tp != mysql.TypeJSON {
+		it := chunk.NewIterator4Chunk(input)
+		result.ReserveJSON(input.NumRows())
+		for row := it.Begin(); row != it.End(); row = it.Next() {
+			v, null, err := col.EvalJSON(ctx, row)
+			if err != nil {
+				return err
+			}
+			if null {
+				result.AppendNull()
+			} else {
+				result.AppendJSON(v)
+			}
+		}
+		return nil
+	}
+	input.Column(col.Index).CopyReconstruct(input.Sel(), result)
+	return nil
+}
+
 const columnPrefix = "Column#"
 
 // String implements Stringer interface.
@@ -158,7 +419,7 @@ func (col *Column) Eval(row chunk.Row) (types.Datum, error) {
 
 // EvalInt returns int representation of Column.
 func (col *Column) EvalInt(ctx sessionctx.Context, row chunk.Row) (int64, bool, error) {
-	if col.GetType().Hybrid() {
+	if col.GetType().Hybrid() || col.GetType().Tp == mysql.TypeNewDecimal || col.GetType().Tp == mysql.TypeJSON || isTemporalColumn(col) {
 		val := row.GetDatum(col.Index, col.RetType)
 		if val.IsNull() {
 			return 0, true, nil
@@ -180,6 +441,15 @@ func (col *Column) EvalReal(ctx sessionctx.Context, row chunk.Row) (float64, boo
 	if col.GetType().Tp == mysql.TypeFloat {
 		return float64(row.GetFloat32(col.Index)), false, nil
 	}
+	if col.GetType().Tp == mysql.TypeNewDecimal {
+		res, err := row.GetMyDecimal(col.Index).ToFloat64()
+		return res, err != nil, err
+	}
+	if col.GetType().Hybrid() || col.GetType().Tp == mysql.TypeJSON || isTemporalColumn(col) {
+		val := row.GetDatum(col.Index, col.RetType)
+		res, err := val.ToFloat64(ctx.GetSessionVars().StmtCtx)
+		return res, err != nil, err
+	}
 	return row.GetFloat64(col.Index), false, nil
 }
 
@@ -189,8 +459,8 @@ func (col *Column) EvalString(ctx sessionctx.Context, row chunk.Row) (string, bo
 		return "", true, nil
 	}
 
-	// Specially handle the ENUM/SET/BIT input value.
-	if col.GetType().Hybrid() {
+	// Specially handle the ENUM/SET/BIT, JSON and temporal input value.
+	if col.GetType().Hybrid() || col.GetType().Tp == mysql.TypeJSON || isTemporalColumn(col) {
 		val := row.GetDatum(col.Index, col.RetType)
 		res, err := val.ToString()
 		return res, err != nil, err
@@ -206,6 +476,53 @@ func (col *Column) EvalString(ctx sessionctx.Context, row chunk.Row) (string, bo
 	return val, false, nil
 }
 
+// EvalDecimal returns decimal representation of Column.
+func (col *Column) EvalDecimal(ctx sessionctx.Context, row chunk.Row) (*types.MyDecimal, bool, error) {
+	if row.IsNull(col.Index) {
+		return nil, true, nil
+	}
+	// Specially handle the non-decimal input value, e.g. comparing an integer column with a decimal constant.
+	if col.GetType().EvalType() != types.ETDecimal {
+		val := row.GetDatum(col.Index, col.RetType)
+		res, err := val.ToDecimal(ctx.GetSessionVars().StmtCtx)
+		return res, err != nil, err
+	}
+	return row.GetMyDecimal(col.Index), false, nil
+}
+
+// EvalTime returns DATE/DATETIME/TIMESTAMP representation of Column.
+func (col *Column) EvalTime(ctx sessionctx.Context, row chunk.Row) (types.Time, bool, error) {
+	if row.IsNull(col.Index) {
+		return types.ZeroTime, true, nil
+	}
+	if !types.IsTypeTime(col.GetType().Tp) {
+		return datumToTime(ctx, row.GetDatum(col.Index, col.RetType), col.RetType)
+	}
+	return row.GetTime(col.Index), false, nil
+}
+
+// EvalDuration returns Duration representation of Column.
+func (col *Column) EvalDuration(ctx sessionctx.Context, row chunk.Row) (types.Duration, bool, error) {
+	if row.IsNull(col.Index) {
+		return types.ZeroDuration, true, nil
+	}
+	if col.GetType().Tp != mysql.TypeDuration {
+		return datumToDuration(ctx, row.GetDatum(col.Index, col.RetType), col.RetType)
+	}
+	return row.GetDuration(col.Index, col.RetType.Decimal), false, nil
+}
+
+// EvalJSON returns JSON representation of Column.
+func (col *Column) EvalJSON(ctx sessionctx.Context, row chunk.Row) (json.BinaryJSON, bool, error) {
+	if row.IsNull(col.Index) {
+		return json.BinaryJSON{}, true, nil
+	}
+	if col.GetType().Tp != mysql.TypeJSON {
+		return datumToJSON(ctx, row.GetDatum(col.Index, col.RetType))
+	}
+	return row.GetJSON(col.Index), false, nil
+}
+
 // Clone implements Expression interface.
 func (col *Column) Clone() Expression {
 	newCol := *col
@@ -15,6 +15,7 @@ package expression
 
 import (
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
@@ -22,6 +23,30 @@ import (
 	"github.com/pingcap/tidb/util/mock"
 )
 
+func (s *testEvaluatorSuite) TestCorrelatedColumn(c *C) {
+	col := &Column{RetType: types.NewFieldType(mysql.TypeLonglong), UniqueID: 1}
+	corCol := &CorrelatedColumn{Column: *col, Data: new(types.Datum)}
+	c.Assert(corCol.IsCorrelated(), IsTrue)
+	c.Assert(corCol.ConstItem(), IsFalse)
+	c.Assert(corCol.Equal(nil, corCol.Clone()), IsTrue)
+	c.Assert(corCol.Equal(nil, col), IsFalse)
+	c.Assert(corCol.Decorrelate(NewSchema(col)).Equal(nil, col), IsTrue)
+	c.Assert(corCol.Decorrelate(NewSchema()).Equal(nil, corCol), IsTrue)
+
+	_, isNull, err := corCol.EvalInt(s.ctx, chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(isNull, IsTrue)
+	*corCol.Data = types.NewIntDatum(3)
+	v, isNull, err := corCol.EvalInt(s.ctx, chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(isNull, IsFalse)
+	c.Assert(v, Equals, int64(3))
+
+	cols := ExtractCorColumns(newFunction(ast.Plus, corCol, col))
+	c.Assert(len(cols), Equals, 1)
+	c.Assert(cols[0], Equals, corCol)
+}
+
 func (s *testEvaluatorSuite) TestColumnHashCode(c *C) {
 	col1 := &Column{
 		UniqueID: 12,
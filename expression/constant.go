@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
 )
@@ -47,9 +48,50 @@ var (
 
 // Constant stands for a constant value.
 type Constant struct {
-	Value    types.Datum
-	RetType  *types.FieldType
-	hashcode []byte
+	Value   types.Datum
+	RetType *types.FieldType
+	// ParamMarker is not nil when the constant comes from a parameter marker
+	// of a prepared statement whose plan may be cached. Its value is read from
+	// the parameters of the current execution.
+	ParamMarker *ParamMarker
+	// DeferredExpr is not nil when the constant is folded from an expression
+	// that contains parameter markers, the expression is evaluated again on
+	// every execution.
+	DeferredExpr Expression
+	hashcode     []byte
+}
+
+// ParamMarker indicates a parameter provided by EXECUTE or COM_STMT_EXECUTE.
+type ParamMarker struct {
+	ctx   sessionctx.Context
+	order int
+}
+
+// NewParamMarker creates a ParamMarker referring to the order-th parameter.
+func NewParamMarker(ctx sessionctx.Context, order int) *ParamMarker {
+	return &ParamMarker{ctx: ctx, order: order}
+}
+
+// GetUserVar returns the value of the parameter in the current execution.
+func (d *ParamMarker) GetUserVar() types.Datum {
+	return d.ctx.GetSessionVars().PreparedParams[d.order]
+}
+
+// isMutable returns true if the value of the constant may change between
+// executions of a cached plan.
+func (c *Constant) isMutable() bool {
+	return c.ParamMarker != nil || c.DeferredExpr != nil
+}
+
+// getValue returns the value of the constant in the current execution.
+func (c *Constant) getValue(row chunk.Row) (types.Datum, error) {
+	if c.ParamMarker != nil {
+		return c.ParamMarker.GetUserVar(), nil
+	}
+	if c.DeferredExpr != nil {
+		return c.DeferredExpr.Eval(row)
+	}
+	return c.Value, nil
 }
 
 // String implements fmt.Stringer interface.
@@ -87,54 +129,141 @@ func (c *Constant) VecEvalString(ctx sessionctx.Context, input *chunk.Chunk, res
 	return genVecFromConstExpr(ctx, c, types.ETString, input, result)
 }
 
+// VecEvalDecimal evaluates this expression in a vectorized manner.
+func (c *Constant) VecEvalDecimal(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, c, types.ETDecimal, input, result)
+}
+
+// VecEvalTime evaluates this expression in a vectorized manner.
+func (c *Constant) VecEvalTime(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, c, types.ETTimestamp, input, result)
+}
+
+// VecEvalDuration evaluates this expression in a vectorized manner.
+func (c *Constant) VecEvalDuration(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, c, types.ETDuration, input, result)
+}
+
+// VecEvalJSON evaluates this expression in a vectorized manner.
+func (c *Constant) VecEvalJSON(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, c, types.ETJson, input, result)
+}
+
 // Eval implements Expression interface.
-func (c *Constant) Eval(_ chunk.Row) (types.Datum, error) {
-	return c.Value, nil
+func (c *Constant) Eval(row chunk.Row) (types.Datum, error) {
+	return c.getValue(row)
 }
 
 // EvalInt returns int representation of Constant.
-func (c *Constant) EvalInt(ctx sessionctx.Context, _ chunk.Row) (int64, bool, error) {
-	if c.GetType().Tp == mysql.TypeNull || c.Value.IsNull() {
+func (c *Constant) EvalInt(ctx sessionctx.Context, row chunk.Row) (int64, bool, error) {
+	dt, err := c.getValue(row)
+	if err != nil {
+		return 0, true, err
+	}
+	if c.GetType().Tp == mysql.TypeNull || dt.IsNull() {
 		return 0, true, nil
 	}
-	if c.GetType().Hybrid() || c.Value.Kind() == types.KindString {
-		res, err := c.Value.ToInt64(ctx.GetSessionVars().StmtCtx)
+	if c.GetType().Hybrid() || dt.Kind() == types.KindString || dt.Kind() == types.KindMysqlDecimal ||
+		dt.Kind() == types.KindMysqlTime || dt.Kind() == types.KindMysqlDuration || dt.Kind() == types.KindMysqlJSON ||
+		dt.Kind() == types.KindFloat32 || dt.Kind() == types.KindFloat64 || dt.Kind() == types.KindBytes {
+		res, err := dt.ToInt64(ctx.GetSessionVars().StmtCtx)
 		return res, err != nil, err
 	}
-	return c.Value.GetInt64(), false, nil
+	return dt.GetInt64(), false, nil
 }
 
 // EvalReal returns real representation of Constant.
-func (c *Constant) EvalReal(ctx sessionctx.Context, _ chunk.Row) (float64, bool, error) {
-	if c.GetType().Tp == mysql.TypeNull || c.Value.IsNull() {
+func (c *Constant) EvalReal(ctx sessionctx.Context, row chunk.Row) (float64, bool, error) {
+	dt, err := c.getValue(row)
+	if err != nil {
+		return 0, true, err
+	}
+	if c.GetType().Tp == mysql.TypeNull || dt.IsNull() {
 		return 0, true, nil
 	}
-	if c.GetType().Hybrid() || c.Value.Kind() == types.KindString {
-		res, err := c.Value.ToFloat64(ctx.GetSessionVars().StmtCtx)
+	if c.GetType().Hybrid() || dt.Kind() == types.KindString || dt.Kind() == types.KindMysqlDecimal ||
+		dt.Kind() == types.KindMysqlTime || dt.Kind() == types.KindMysqlDuration || dt.Kind() == types.KindMysqlJSON ||
+		dt.Kind() == types.KindInt64 || dt.Kind() == types.KindUint64 || dt.Kind() == types.KindBytes {
+		res, err := dt.ToFloat64(ctx.GetSessionVars().StmtCtx)
 		return res, err != nil, err
 	}
-	return c.Value.GetFloat64(), false, nil
+	return dt.GetFloat64(), false, nil
 }
 
 // EvalString returns string representation of Constant.
-func (c *Constant) EvalString(ctx sessionctx.Context, _ chunk.Row) (string, bool, error) {
-	if c.GetType().Tp == mysql.TypeNull || c.Value.IsNull() {
+func (c *Constant) EvalString(ctx sessionctx.Context, row chunk.Row) (string, bool, error) {
+	dt, err := c.getValue(row)
+	if err != nil {
+		return "", true, err
+	}
+	if c.GetType().Tp == mysql.TypeNull || dt.IsNull() {
 		return "", true, nil
 	}
-	res, err := c.Value.ToString()
+	res, err := dt.ToString()
 	return res, err != nil, err
 }
 
+// EvalDecimal returns decimal representation of Constant.
+func (c *Constant) EvalDecimal(ctx sessionctx.Context, row chunk.Row) (*types.MyDecimal, bool, error) {
+	dt, err := c.getValue(row)
+	if err != nil {
+		return nil, true, err
+	}
+	if c.GetType().Tp == mysql.TypeNull || dt.IsNull() {
+		return nil, true, nil
+	}
+	res, err := dt.ToDecimal(ctx.GetSessionVars().StmtCtx)
+	return res, err != nil, err
+}
+
+// EvalTime returns DATE/DATETIME/TIMESTAMP representation of Constant.
+func (c *Constant) EvalTime(ctx sessionctx.Context, row chunk.Row) (types.Time, bool, error) {
+	dt, err := c.getValue(row)
+	if err != nil {
+		return types.ZeroTime, true, err
+	}
+	if c.GetType().Tp == mysql.TypeNull || dt.IsNull() {
+		return types.ZeroTime, true, nil
+	}
+	return datumToTime(ctx, dt, c.RetType)
+}
+
+// EvalDuration returns Duration representation of Constant.
+func (c *Constant) EvalDuration(ctx sessionctx.Context, row chunk.Row) (types.Duration, bool, error) {
+	dt, err := c.getValue(row)
+	if err != nil {
+		return types.ZeroDuration, true, err
+	}
+	if c.GetType().Tp == mysql.TypeNull || dt.IsNull() {
+		return types.ZeroDuration, true, nil
+	}
+	return datumToDuration(ctx, dt, c.RetType)
+}
+
+// EvalJSON returns JSON representation of Constant.
+func (c *Constant) EvalJSON(ctx sessionctx.Context, row chunk.Row) (json.BinaryJSON, bool, error) {
+	dt, err := c.getValue(row)
+	if err != nil {
+		return json.BinaryJSON{}, true, err
+	}
+	if c.GetType().Tp == mysql.TypeNull || dt.IsNull() {
+		return json.BinaryJSON{}, true, nil
+	}
+	return datumToJSON(ctx, dt)
+}
+
 // Equal implements Expression interface.
 func (c *Constant) Equal(ctx sessionctx.Context, b Expression) bool {
 	y, ok := b.(*Constant)
 	if !ok {
 		return false
 	}
-	_, err1 := y.Eval(chunk.Row{})
-	_, err2 := c.Eval(chunk.Row{})
-	if err1 != nil || err2 != nil {
-		return false
+	if c.isMutable() || y.isMutable() {
+		// The values of mutable constants may differ in later executions.
+		if c.ParamMarker != nil && y.ParamMarker != nil {
+			return c.ParamMarker.order == y.ParamMarker.order
+		}
+		return c == y
 	}
 	con, err := c.Value.CompareDatum(ctx.GetSessionVars().StmtCtx, &y.Value)
 	if err != nil || con != 0 {
@@ -163,11 +292,18 @@ func (c *Constant) HashCode(sc *stmtctx.StatementContext) []byte {
 	if len(c.hashcode) > 0 {
 		return c.hashcode
 	}
-	_, err := c.Eval(chunk.Row{})
-	if err != nil {
-		terror.Log(err)
-	}
 	c.hashcode = append(c.hashcode, constantFlag)
+	if c.ParamMarker != nil {
+		c.hashcode = append(c.hashcode, parameterFlag)
+		c.hashcode = codec.EncodeInt(c.hashcode, int64(c.ParamMarker.order))
+		return c.hashcode
+	}
+	if c.DeferredExpr != nil {
+		c.hashcode = append(c.hashcode, deferredFlag)
+		c.hashcode = append(c.hashcode, c.DeferredExpr.HashCode(sc)...)
+		return c.hashcode
+	}
+	var err error
 	c.hashcode, err = codec.EncodeValue(sc, c.hashcode, c.Value)
 	if err != nil {
 		terror.Log(err)
@@ -14,11 +14,67 @@
 package expression
 
 import (
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/logutil"
 	"go.uber.org/zap"
 )
 
+// specialFoldHandler stores functions which can be folded even if not all of
+// their arguments are constants.
+var specialFoldHandler = map[string]func(*ScalarFunction) Expression{}
+
+func init() {
+	specialFoldHandler = map[string]func(*ScalarFunction) Expression{
+		ast.Case: caseWhenHandler,
+	}
+}
+
+// caseWhenHandler folds the arguments of CASE WHEN one by one. If all the
+// conditions before a true condition are constants, the whole function is
+// replaced by the folded result of that clause.
+func caseWhenHandler(expr *ScalarFunction) Expression {
+	args, l := expr.GetArgs(), len(expr.GetArgs())
+	hasNonConstCondition := false
+	for i := 0; i < l-1; i += 2 {
+		args[i] = foldConstant(args[i])
+		if con, isConst := args[i].(*Constant); isConst && !con.isMutable() && !hasNonConstCondition {
+			val, isNull, err := con.EvalInt(expr.GetCtx(), chunk.Row{})
+			if err != nil {
+				return expr
+			}
+			if !isNull && val != 0 {
+				return foldCaseWhenResult(expr, args[i+1])
+			}
+		} else {
+			hasNonConstCondition = true
+		}
+		args[i+1] = foldConstant(args[i+1])
+	}
+	if hasNonConstCondition {
+		if l%2 == 1 {
+			args[l-1] = foldConstant(args[l-1])
+		}
+		return expr
+	}
+	// All the conditions are false, the result is the else clause or NULL.
+	if l%2 == 1 {
+		return foldCaseWhenResult(expr, args[l-1])
+	}
+	return &Constant{Value: types.NewDatum(nil), RetType: expr.RetType}
+}
+
+// foldCaseWhenResult folds the chosen result of CASE WHEN and keeps the
+// return type of the whole function.
+func foldCaseWhenResult(expr *ScalarFunction, result Expression) Expression {
+	result = foldConstant(result)
+	if con, isConst := result.(*Constant); isConst && !con.isMutable() {
+		return &Constant{Value: con.Value, RetType: expr.RetType}
+	}
+	return BuildCastFunction(expr.GetCtx(), result, expr.RetType)
+}
+
 // FoldConstant does constant folding optimization on an expression excluding deferred ones.
 func FoldConstant(expr Expression) Expression {
 	return foldConstant(expr)
@@ -30,23 +86,28 @@ func foldConstant(expr Expression) Expression {
 		if _, ok := unFoldableFunctions[x.FuncName.L]; ok {
 			return expr
 		}
+		if function := specialFoldHandler[x.FuncName.L]; function != nil {
+			return function(x)
+		}
 
 		args := x.GetArgs()
 		sc := x.GetCtx().GetSessionVars().StmtCtx
 		argIsConst := make([]bool, len(args))
 		hasNullArg := false
 		allConstArg := true
+		isDeferredConst := false
 		for i := 0; i < len(args); i++ {
 			switch x := args[i].(type) {
 			case *Constant:
 				argIsConst[i] = true
-				hasNullArg = hasNullArg || x.Value.IsNull()
+				isDeferredConst = isDeferredConst || x.isMutable()
+				hasNullArg = hasNullArg || (!x.isMutable() && x.Value.IsNull())
 			default:
 				allConstArg = false
 			}
 		}
 		if !allConstArg {
-			if !hasNullArg || !sc.InNullRejectCheck {
+			if !hasNullArg || !sc.InNullRejectCheck || isDeferredConst {
 				return expr
 			}
 			constArgs := make([]Expression, len(args))
@@ -78,6 +139,9 @@ func foldConstant(expr Expression) Expression {
 			logutil.BgLogger().Debug("fold expression to constant", zap.String("expression", x.ExplainInfo()), zap.Error(err))
 			return expr
 		}
+		if isDeferredConst {
+			return &Constant{Value: value, RetType: x.RetType, DeferredExpr: x}
+		}
 		return &Constant{Value: value, RetType: x.RetType}
 	}
 	return expr
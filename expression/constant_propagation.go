@@ -51,12 +51,25 @@ func (s *basePropConstSolver) insertCol(col *Column) {
 // tryToUpdateEQList tries to update the eqList. When the eqList has store this column with a different constant, like
 // a = 1 and a = 2, we set the second return value to false.
 func (s *basePropConstSolver) tryToUpdateEQList(col *Column, con *Constant) (bool, bool) {
+	if con.isMutable() {
+		// The value of a mutable constant may change in a cached plan, so
+		// it can't be used to decide the condition is always false.
+		id := s.getColID(col)
+		if s.eqList[id] != nil {
+			return false, false
+		}
+		s.eqList[id] = con
+		return true, false
+	}
 	if con.Value.IsNull() {
 		return false, true
 	}
 	id := s.getColID(col)
 	oldCon := s.eqList[id]
 	if oldCon != nil {
+		if oldCon.isMutable() {
+			return false, false
+		}
 		return false, !oldCon.Equal(s.ctx, con)
 	}
 	s.eqList[id] = con
@@ -245,7 +258,7 @@ func (s *propConstSolver) pickNewEQConds(visited []bool) (retMapper map[int]*Con
 		// Then we check if this CNF item is a false constant. If so, we will set the whole condition to false.
 		var ok bool
 		if col == nil {
-			if con, ok = cond.(*Constant); ok {
+			if con, ok = cond.(*Constant); ok && !con.isMutable() {
 				value, _, err := EvalBool(s.ctx, []Expression{con}, chunk.Row{})
 				if err != nil {
 					terror.Log(err)
@@ -337,7 +350,7 @@ func (s *propOuterJoinConstSolver) pickEQCondsOnOuterCol(retMapper map[int]*Cons
 		// Then we check if this CNF item is a false constant. If so, we will set the whole condition to false.
 		var ok bool
 		if col == nil {
-			if con, ok = cond.(*Constant); ok {
+			if con, ok = cond.(*Constant); ok && !con.isMutable() {
 				value, _, err := EvalBool(s.ctx, []Expression{con}, chunk.Row{})
 				if err != nil {
 					terror.Log(err)
@@ -18,6 +18,7 @@ import (
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/mock"
 	"sort"
 	"strings"
@@ -181,9 +182,54 @@ func (*testExpressionSuite) TestConstantFolding(c *C) {
 			condition: newFunction(ast.LT, newColumn(0), newFunction(ast.Plus, newColumn(1), newFunction(ast.Plus, newLonglong(2), newLonglong(1)))),
 			result:    "lt(Column#0, plus(Column#1, 3))",
 		},
+		{
+			condition: newFunction(ast.Case, newFunction(ast.LT, newLonglong(2), newLonglong(1)), newColumn(0), newFunction(ast.GT, newLonglong(2), newLonglong(1)), newLonglong(3), newColumn(1)),
+			result:    "3",
+		},
+		{
+			condition: newFunction(ast.Case, newFunction(ast.LT, newLonglong(2), newLonglong(1)), newColumn(0), newLonglong(3)),
+			result:    "3",
+		},
+		{
+			condition: newFunction(ast.Case, newFunction(ast.LT, newLonglong(2), newLonglong(1)), newColumn(0)),
+			result:    "<nil>",
+		},
+		{
+			condition: newFunction(ast.Case, newFunction(ast.LT, newColumn(0), newLonglong(1)), newFunction(ast.Plus, newLonglong(1), newLonglong(2)), newColumn(1)),
+			result:    "case(lt(Column#0, 1), 3, Column#1)",
+		},
 	}
 	for _, tt := range tests {
 		newConds := FoldConstant(tt.condition)
 		c.Assert(newConds.String(), Equals, tt.result, Commentf("different for expr %s", tt.condition))
 	}
 }
+
+func (*testExpressionSuite) TestDeferredExprNotNull(c *C) {
+	ctx := mock.NewContext()
+	ctx.GetSessionVars().PreparedParams = []types.Datum{types.NewIntDatum(1)}
+	param := &Constant{
+		Value:       types.NewIntDatum(1),
+		RetType:     types.NewFieldType(mysql.TypeLonglong),
+		ParamMarker: NewParamMarker(ctx, 0),
+	}
+	cond := FoldConstant(newFunction(ast.Plus, param, newLonglong(2)))
+	con, ok := cond.(*Constant)
+	c.Assert(ok, IsTrue)
+	c.Assert(con.DeferredExpr, NotNil)
+	c.Assert(ContainMutableConst([]Expression{cond}), IsTrue)
+
+	// The folded constant is evaluated with the parameter of the current execution.
+	ctx.GetSessionVars().PreparedParams[0] = types.NewIntDatum(5)
+	v, isNull, err := con.EvalInt(ctx, chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(isNull, IsFalse)
+	c.Assert(v, Equals, int64(7))
+	c.Assert(param.Equal(ctx, con), IsFalse)
+	c.Assert(param.Equal(ctx, &Constant{RetType: param.RetType, ParamMarker: NewParamMarker(ctx, 0)}), IsTrue)
+
+	// CASE WHEN is not folded to one of its results if the condition contains parameters.
+	caseWhen := FoldConstant(newFunction(ast.Case, newFunction(ast.EQ, param, newLonglong(1)), newLonglong(10), newLonglong(20)))
+	_, ok = caseWhen.(*ScalarFunction)
+	c.Assert(ok, IsTrue)
+}
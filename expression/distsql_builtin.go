@@ -15,12 +15,15 @@ package expression
 
 import (
 	"fmt"
+	"time"
+
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tipb/go-tipb"
@@ -42,59 +45,207 @@ func getSignatureByPB(ctx sessionctx.Context, sigCode tipb.ScalarFuncSig, tp *ti
 	fieldTp := PbTypeToFieldType(tp)
 	base := newBaseBuiltinFunc(ctx, args)
 	base.tp = fieldTp
+	maxAllowedPacket, err := getMaxAllowedPacket(ctx)
+	if err != nil {
+		return nil, err
+	}
 	switch sigCode {
 	case tipb.ScalarFuncSig_LTInt:
 		f = &builtinLTIntSig{base}
 	case tipb.ScalarFuncSig_LTReal:
 		f = &builtinLTRealSig{base}
+	case tipb.ScalarFuncSig_LTDecimal:
+		f = &builtinLTDecimalSig{base}
 	case tipb.ScalarFuncSig_LTString:
 		f = &builtinLTStringSig{base}
+	case tipb.ScalarFuncSig_LTTime:
+		f = &builtinLTTimeSig{base}
+	case tipb.ScalarFuncSig_LTDuration:
+		f = &builtinLTDurationSig{base}
+	case tipb.ScalarFuncSig_LTJson:
+		f = &builtinLTJSONSig{base}
 	case tipb.ScalarFuncSig_LEInt:
 		f = &builtinLEIntSig{base}
 	case tipb.ScalarFuncSig_LEReal:
 		f = &builtinLERealSig{base}
+	case tipb.ScalarFuncSig_LEDecimal:
+		f = &builtinLEDecimalSig{base}
 	case tipb.ScalarFuncSig_LEString:
 		f = &builtinLEStringSig{base}
+	case tipb.ScalarFuncSig_LETime:
+		f = &builtinLETimeSig{base}
+	case tipb.ScalarFuncSig_LEDuration:
+		f = &builtinLEDurationSig{base}
+	case tipb.ScalarFuncSig_LEJson:
+		f = &builtinLEJSONSig{base}
 	case tipb.ScalarFuncSig_GTInt:
 		f = &builtinGTIntSig{base}
 	case tipb.ScalarFuncSig_GTReal:
 		f = &builtinGTRealSig{base}
+	case tipb.ScalarFuncSig_GTDecimal:
+		f = &builtinGTDecimalSig{base}
 	case tipb.ScalarFuncSig_GTString:
 		f = &builtinGTStringSig{base}
+	case tipb.ScalarFuncSig_GTTime:
+		f = &builtinGTTimeSig{base}
+	case tipb.ScalarFuncSig_GTDuration:
+		f = &builtinGTDurationSig{base}
+	case tipb.ScalarFuncSig_GTJson:
+		f = &builtinGTJSONSig{base}
 	case tipb.ScalarFuncSig_GEInt:
 		f = &builtinGEIntSig{base}
 	case tipb.ScalarFuncSig_GEReal:
 		f = &builtinGERealSig{base}
+	case tipb.ScalarFuncSig_GEDecimal:
+		f = &builtinGEDecimalSig{base}
 	case tipb.ScalarFuncSig_GEString:
 		f = &builtinGEStringSig{base}
+	case tipb.ScalarFuncSig_GETime:
+		f = &builtinGETimeSig{base}
+	case tipb.ScalarFuncSig_GEDuration:
+		f = &builtinGEDurationSig{base}
+	case tipb.ScalarFuncSig_GEJson:
+		f = &builtinGEJSONSig{base}
 	case tipb.ScalarFuncSig_EQInt:
 		f = &builtinEQIntSig{base}
 	case tipb.ScalarFuncSig_EQReal:
 		f = &builtinEQRealSig{base}
+	case tipb.ScalarFuncSig_EQDecimal:
+		f = &builtinEQDecimalSig{base}
 	case tipb.ScalarFuncSig_EQString:
 		f = &builtinEQStringSig{base}
+	case tipb.ScalarFuncSig_EQTime:
+		f = &builtinEQTimeSig{base}
+	case tipb.ScalarFuncSig_EQDuration:
+		f = &builtinEQDurationSig{base}
+	case tipb.ScalarFuncSig_EQJson:
+		f = &builtinEQJSONSig{base}
 	case tipb.ScalarFuncSig_NEInt:
 		f = &builtinNEIntSig{base}
 	case tipb.ScalarFuncSig_NEReal:
 		f = &builtinNERealSig{base}
+	case tipb.ScalarFuncSig_NEDecimal:
+		f = &builtinNEDecimalSig{base}
 	case tipb.ScalarFuncSig_NEString:
 		f = &builtinNEStringSig{base}
+	case tipb.ScalarFuncSig_NETime:
+		f = &builtinNETimeSig{base}
+	case tipb.ScalarFuncSig_NEDuration:
+		f = &builtinNEDurationSig{base}
+	case tipb.ScalarFuncSig_NEJson:
+		f = &builtinNEJSONSig{base}
 	case tipb.ScalarFuncSig_PlusReal:
 		f = &builtinArithmeticPlusRealSig{base}
+	case tipb.ScalarFuncSig_PlusDecimal:
+		f = &builtinArithmeticPlusDecimalSig{base}
 	case tipb.ScalarFuncSig_PlusInt:
 		f = &builtinArithmeticPlusIntSig{base}
 	case tipb.ScalarFuncSig_MinusReal:
 		f = &builtinArithmeticMinusRealSig{base}
+	case tipb.ScalarFuncSig_MinusDecimal:
+		f = &builtinArithmeticMinusDecimalSig{base}
 	case tipb.ScalarFuncSig_MinusInt:
 		f = &builtinArithmeticMinusIntSig{base}
 	case tipb.ScalarFuncSig_MultiplyReal:
 		f = &builtinArithmeticMultiplyRealSig{base}
+	case tipb.ScalarFuncSig_MultiplyDecimal:
+		f = &builtinArithmeticMultiplyDecimalSig{base}
 	case tipb.ScalarFuncSig_MultiplyInt:
 		f = &builtinArithmeticMultiplyIntSig{base}
 	case tipb.ScalarFuncSig_DivideReal:
 		f = &builtinArithmeticDivideRealSig{base}
+	case tipb.ScalarFuncSig_DivideDecimal:
+		f = &builtinArithmeticDivideDecimalSig{base}
 	case tipb.ScalarFuncSig_MultiplyIntUnsigned:
 		f = &builtinArithmeticMultiplyIntUnsignedSig{base}
+	case tipb.ScalarFuncSig_IntDivideInt:
+		f = &builtinArithmeticIntDivideIntSig{base}
+	case tipb.ScalarFuncSig_IntDivideDecimal:
+		f = &builtinArithmeticIntDivideDecimalSig{base}
+	case tipb.ScalarFuncSig_ModReal:
+		f = &builtinArithmeticModRealSig{base}
+	case tipb.ScalarFuncSig_ModDecimal:
+		f = &builtinArithmeticModDecimalSig{base}
+	case tipb.ScalarFuncSig_ModInt:
+		f = &builtinArithmeticModIntSig{base}
+	case tipb.ScalarFuncSig_AbsInt:
+		f = &builtinAbsIntSig{base}
+	case tipb.ScalarFuncSig_AbsUInt:
+		f = &builtinAbsUIntSig{base}
+	case tipb.ScalarFuncSig_AbsReal:
+		f = &builtinAbsRealSig{base}
+	case tipb.ScalarFuncSig_AbsDecimal:
+		f = &builtinAbsDecSig{base}
+	case tipb.ScalarFuncSig_CeilIntToInt:
+		f = &builtinCeilIntToIntSig{base}
+	case tipb.ScalarFuncSig_CeilDecToDec:
+		f = &builtinCeilDecToDecSig{base}
+	case tipb.ScalarFuncSig_CeilReal:
+		f = &builtinCeilRealSig{base}
+	case tipb.ScalarFuncSig_FloorIntToInt:
+		f = &builtinFloorIntToIntSig{base}
+	case tipb.ScalarFuncSig_FloorDecToDec:
+		f = &builtinFloorDecToDecSig{base}
+	case tipb.ScalarFuncSig_FloorReal:
+		f = &builtinFloorRealSig{base}
+	case tipb.ScalarFuncSig_RoundInt:
+		f = &builtinRoundIntSig{base}
+	case tipb.ScalarFuncSig_RoundReal:
+		f = &builtinRoundRealSig{base}
+	case tipb.ScalarFuncSig_RoundDec:
+		f = &builtinRoundDecSig{base}
+	case tipb.ScalarFuncSig_RoundWithFracInt:
+		f = &builtinRoundWithFracIntSig{base}
+	case tipb.ScalarFuncSig_RoundWithFracReal:
+		f = &builtinRoundWithFracRealSig{base}
+	case tipb.ScalarFuncSig_RoundWithFracDec:
+		f = &builtinRoundWithFracDecSig{base}
+	case tipb.ScalarFuncSig_TruncateInt:
+		f = &builtinTruncateIntSig{base}
+	case tipb.ScalarFuncSig_TruncateUint:
+		f = &builtinTruncateUintSig{base}
+	case tipb.ScalarFuncSig_TruncateReal:
+		f = &builtinTruncateRealSig{base}
+	case tipb.ScalarFuncSig_TruncateDecimal:
+		f = &builtinTruncateDecimalSig{base}
+	case tipb.ScalarFuncSig_Pow:
+		f = &builtinPowSig{base}
+	case tipb.ScalarFuncSig_Sqrt:
+		f = &builtinSqrtSig{base}
+	case tipb.ScalarFuncSig_Exp:
+		f = &builtinExpSig{base}
+	case tipb.ScalarFuncSig_Log1Arg:
+		f = &builtinLog1ArgSig{base}
+	case tipb.ScalarFuncSig_Log2Args:
+		f = &builtinLog2ArgsSig{base}
+	case tipb.ScalarFuncSig_Log2:
+		f = &builtinLog2Sig{base}
+	case tipb.ScalarFuncSig_Log10:
+		f = &builtinLog10Sig{base}
+	case tipb.ScalarFuncSig_Sign:
+		f = &builtinSignSig{base}
+	case tipb.ScalarFuncSig_PI:
+		f = &builtinPISig{base}
+	case tipb.ScalarFuncSig_Sin:
+		f = &builtinSinSig{base}
+	case tipb.ScalarFuncSig_Cos:
+		f = &builtinCosSig{base}
+	case tipb.ScalarFuncSig_Tan:
+		f = &builtinTanSig{base}
+	case tipb.ScalarFuncSig_Cot:
+		f = &builtinCotSig{base}
+	case tipb.ScalarFuncSig_Asin:
+		f = &builtinAsinSig{base}
+	case tipb.ScalarFuncSig_Acos:
+		f = &builtinAcosSig{base}
+	case tipb.ScalarFuncSig_Atan1Arg:
+		f = &builtinAtan1ArgSig{base}
+	case tipb.ScalarFuncSig_Atan2Args:
+		f = &builtinAtan2ArgsSig{base}
+	case tipb.ScalarFuncSig_Degrees:
+		f = &builtinDegreesSig{base}
+	case tipb.ScalarFuncSig_Radians:
+		f = &builtinRadiansSig{base}
 	case tipb.ScalarFuncSig_LogicalAnd:
 		f = &builtinLogicAndSig{base}
 	case tipb.ScalarFuncSig_LogicalOr:
@@ -103,16 +254,28 @@ func getSignatureByPB(ctx sessionctx.Context, sigCode tipb.ScalarFuncSig, tp *ti
 		f = &builtinUnaryNotIntSig{base}
 	case tipb.ScalarFuncSig_UnaryNotReal:
 		f = &builtinUnaryNotRealSig{base}
+	case tipb.ScalarFuncSig_UnaryNotDecimal:
+		f = &builtinUnaryNotDecimalSig{base}
 	case tipb.ScalarFuncSig_UnaryMinusInt:
 		f = &builtinUnaryMinusIntSig{base}
 	case tipb.ScalarFuncSig_UnaryMinusReal:
 		f = &builtinUnaryMinusRealSig{base}
+	case tipb.ScalarFuncSig_UnaryMinusDecimal:
+		f = &builtinUnaryMinusDecimalSig{base}
 	case tipb.ScalarFuncSig_RealIsNull:
 		f = &builtinRealIsNullSig{base}
+	case tipb.ScalarFuncSig_DecimalIsNull:
+		f = &builtinDecimalIsNullSig{base}
 	case tipb.ScalarFuncSig_StringIsNull:
 		f = &builtinStringIsNullSig{base}
 	case tipb.ScalarFuncSig_IntIsNull:
 		f = &builtinIntIsNullSig{base}
+	case tipb.ScalarFuncSig_TimeIsNull:
+		f = &builtinTimeIsNullSig{base}
+	case tipb.ScalarFuncSig_DurationIsNull:
+		f = &builtinDurationIsNullSig{base}
+	case tipb.ScalarFuncSig_JsonIsNull:
+		f = &builtinJSONIsNullSig{base}
 	case tipb.ScalarFuncSig_GetVar:
 		f = &builtinGetVarSig{base}
 	case tipb.ScalarFuncSig_SetVar:
@@ -121,24 +284,198 @@ func getSignatureByPB(ctx sessionctx.Context, sigCode tipb.ScalarFuncSig, tp *ti
 		f = &builtinInIntSig{base}
 	case tipb.ScalarFuncSig_InReal:
 		f = &builtinInRealSig{base}
+	case tipb.ScalarFuncSig_InDecimal:
+		f = &builtinInDecimalSig{base}
 	case tipb.ScalarFuncSig_InString:
 		f = &builtinInStringSig{base}
+	case tipb.ScalarFuncSig_InTime:
+		f = &builtinInTimeSig{base}
+	case tipb.ScalarFuncSig_InDuration:
+		f = &builtinInDurationSig{base}
+	case tipb.ScalarFuncSig_InJson:
+		f = &builtinInJSONSig{base}
 	case tipb.ScalarFuncSig_IfNullInt:
 		f = &builtinIfNullIntSig{base}
 	case tipb.ScalarFuncSig_IfNullReal:
 		f = &builtinIfNullRealSig{base}
+	case tipb.ScalarFuncSig_IfNullDecimal:
+		f = &builtinIfNullDecimalSig{base}
 	case tipb.ScalarFuncSig_IfNullString:
 		f = &builtinIfNullStringSig{base}
+	case tipb.ScalarFuncSig_IfNullTime:
+		f = &builtinIfNullTimeSig{base}
+	case tipb.ScalarFuncSig_IfNullDuration:
+		f = &builtinIfNullDurationSig{base}
+	case tipb.ScalarFuncSig_IfNullJson:
+		f = &builtinIfNullJSONSig{base}
 	case tipb.ScalarFuncSig_IfInt:
 		f = &builtinIfIntSig{base}
 	case tipb.ScalarFuncSig_IfReal:
 		f = &builtinIfRealSig{base}
+	case tipb.ScalarFuncSig_IfDecimal:
+		f = &builtinIfDecimalSig{base}
 	case tipb.ScalarFuncSig_IfString:
 		f = &builtinIfStringSig{base}
+	case tipb.ScalarFuncSig_IfTime:
+		f = &builtinIfTimeSig{base}
+	case tipb.ScalarFuncSig_IfDuration:
+		f = &builtinIfDurationSig{base}
+	case tipb.ScalarFuncSig_IfJson:
+		f = &builtinIfJSONSig{base}
+	case tipb.ScalarFuncSig_CaseWhenInt:
+		f = &builtinCaseWhenIntSig{base}
+	case tipb.ScalarFuncSig_CaseWhenReal:
+		f = &builtinCaseWhenRealSig{base}
+	case tipb.ScalarFuncSig_CaseWhenDecimal:
+		f = &builtinCaseWhenDecimalSig{base}
+	case tipb.ScalarFuncSig_CaseWhenString:
+		f = &builtinCaseWhenStringSig{base}
+	case tipb.ScalarFuncSig_CaseWhenTime:
+		f = &builtinCaseWhenTimeSig{base}
+	case tipb.ScalarFuncSig_CaseWhenDuration:
+		f = &builtinCaseWhenDurationSig{base}
+	case tipb.ScalarFuncSig_CaseWhenJson:
+		f = &builtinCaseWhenJSONSig{base}
+	case tipb.ScalarFuncSig_CoalesceInt:
+		f = &builtinCoalesceIntSig{base}
+	case tipb.ScalarFuncSig_CoalesceReal:
+		f = &builtinCoalesceRealSig{base}
+	case tipb.ScalarFuncSig_CoalesceDecimal:
+		f = &builtinCoalesceDecimalSig{base}
+	case tipb.ScalarFuncSig_CoalesceString:
+		f = &builtinCoalesceStringSig{base}
+	case tipb.ScalarFuncSig_CoalesceTime:
+		f = &builtinCoalesceTimeSig{base}
+	case tipb.ScalarFuncSig_CoalesceDuration:
+		f = &builtinCoalesceDurationSig{base}
+	case tipb.ScalarFuncSig_CoalesceJson:
+		f = &builtinCoalesceJSONSig{base}
+	case tipb.ScalarFuncSig_GreatestInt:
+		f = &builtinGreatestIntSig{base}
+	case tipb.ScalarFuncSig_GreatestReal:
+		f = &builtinGreatestRealSig{base}
+	case tipb.ScalarFuncSig_GreatestDecimal:
+		f = &builtinGreatestDecimalSig{base}
+	case tipb.ScalarFuncSig_GreatestString:
+		f = &builtinGreatestStringSig{base}
+	case tipb.ScalarFuncSig_GreatestTime:
+		f = &builtinGreatestTimeSig{base}
+	case tipb.ScalarFuncSig_LeastInt:
+		f = &builtinLeastIntSig{base}
+	case tipb.ScalarFuncSig_LeastReal:
+		f = &builtinLeastRealSig{base}
+	case tipb.ScalarFuncSig_LeastDecimal:
+		f = &builtinLeastDecimalSig{base}
+	case tipb.ScalarFuncSig_LeastString:
+		f = &builtinLeastStringSig{base}
+	case tipb.ScalarFuncSig_LeastTime:
+		f = &builtinLeastTimeSig{base}
 	case tipb.ScalarFuncSig_Length:
 		f = &builtinLengthSig{base}
 	case tipb.ScalarFuncSig_Strcmp:
 		f = &builtinStrcmpSig{base}
+	case tipb.ScalarFuncSig_Concat:
+		f = &builtinConcatSig{base, maxAllowedPacket}
+	case tipb.ScalarFuncSig_ConcatWS:
+		f = &builtinConcatWSSig{base, maxAllowedPacket}
+	case tipb.ScalarFuncSig_Substring2Args:
+		f = &builtinSubstring2ArgsSig{base}
+	case tipb.ScalarFuncSig_Substring3Args:
+		f = &builtinSubstring3ArgsSig{base}
+	case tipb.ScalarFuncSig_Substring2ArgsUTF8:
+		f = &builtinSubstring2ArgsUTF8Sig{base}
+	case tipb.ScalarFuncSig_Substring3ArgsUTF8:
+		f = &builtinSubstring3ArgsUTF8Sig{base}
+	case tipb.ScalarFuncSig_Left:
+		f = &builtinLeftSig{base}
+	case tipb.ScalarFuncSig_LeftUTF8:
+		f = &builtinLeftUTF8Sig{base}
+	case tipb.ScalarFuncSig_Right:
+		f = &builtinRightSig{base}
+	case tipb.ScalarFuncSig_RightUTF8:
+		f = &builtinRightUTF8Sig{base}
+	case tipb.ScalarFuncSig_Upper:
+		f = &builtinUpperSig{base}
+	case tipb.ScalarFuncSig_Lower:
+		f = &builtinLowerSig{base}
+	case tipb.ScalarFuncSig_Trim1Arg:
+		f = &builtinTrim1ArgSig{base}
+	case tipb.ScalarFuncSig_Trim2Args:
+		f = &builtinTrim2ArgsSig{base}
+	case tipb.ScalarFuncSig_LTrim:
+		f = &builtinLTrimSig{base}
+	case tipb.ScalarFuncSig_RTrim:
+		f = &builtinRTrimSig{base}
+	case tipb.ScalarFuncSig_Replace:
+		f = &builtinReplaceSig{base}
+	case tipb.ScalarFuncSig_Locate2Args:
+		f = &builtinLocate2ArgsSig{base}
+	case tipb.ScalarFuncSig_Locate3Args:
+		f = &builtinLocate3ArgsSig{base}
+	case tipb.ScalarFuncSig_Locate2ArgsUTF8:
+		f = &builtinLocate2ArgsUTF8Sig{base}
+	case tipb.ScalarFuncSig_Locate3ArgsUTF8:
+		f = &builtinLocate3ArgsUTF8Sig{base}
+	case tipb.ScalarFuncSig_Instr:
+		f = &builtinInstrSig{base}
+	case tipb.ScalarFuncSig_InstrUTF8:
+		f = &builtinInstrUTF8Sig{base}
+	case tipb.ScalarFuncSig_Lpad:
+		f = &builtinLpadSig{base, maxAllowedPacket}
+	case tipb.ScalarFuncSig_LpadUTF8:
+		f = &builtinLpadUTF8Sig{base, maxAllowedPacket}
+	case tipb.ScalarFuncSig_Rpad:
+		f = &builtinRpadSig{base, maxAllowedPacket}
+	case tipb.ScalarFuncSig_RpadUTF8:
+		f = &builtinRpadUTF8Sig{base, maxAllowedPacket}
+	case tipb.ScalarFuncSig_Repeat:
+		f = &builtinRepeatSig{base, maxAllowedPacket}
+	case tipb.ScalarFuncSig_Reverse:
+		f = &builtinReverseSig{base}
+	case tipb.ScalarFuncSig_ReverseUTF8:
+		f = &builtinReverseUTF8Sig{base}
+	case tipb.ScalarFuncSig_HexStrArg:
+		f = &builtinHexStrArgSig{base}
+	case tipb.ScalarFuncSig_HexIntArg:
+		f = &builtinHexIntArgSig{base}
+	case tipb.ScalarFuncSig_UnHex:
+		f = &builtinUnHexSig{base}
+	case tipb.ScalarFuncSig_FieldInt:
+		f = &builtinFieldIntSig{base}
+	case tipb.ScalarFuncSig_FieldReal:
+		f = &builtinFieldRealSig{base}
+	case tipb.ScalarFuncSig_FieldString:
+		f = &builtinFieldStringSig{base}
+	case tipb.ScalarFuncSig_Elt:
+		f = &builtinEltSig{base}
+	case tipb.ScalarFuncSig_LikeSig:
+		f = &builtinLikeSig{baseBuiltinFunc: base}
+	case tipb.ScalarFuncSig_RegexpSig:
+		f = &builtinRegexpSig{builtinRegexpSharedSig{baseBuiltinFunc: base}}
+	case tipb.ScalarFuncSig_RegexpUTF8Sig:
+		f = &builtinRegexpUTF8Sig{builtinRegexpSharedSig{baseBuiltinFunc: base}}
+	case tipb.ScalarFuncSig_Year:
+		f = &builtinYearSig{base}
+	case tipb.ScalarFuncSig_Month:
+		f = &builtinMonthSig{base}
+	case tipb.ScalarFuncSig_DayOfMonth:
+		f = &builtinDayOfMonthSig{base}
+	case tipb.ScalarFuncSig_DateFormatSig:
+		f = &builtinDateFormatSig{base}
+	case tipb.ScalarFuncSig_DateDiff:
+		f = &builtinDateDiffSig{base}
+	case tipb.ScalarFuncSig_ExtractDatetime:
+		f = &builtinExtractDatetimeSig{base}
+	case tipb.ScalarFuncSig_ExtractDuration:
+		f = &builtinExtractDurationSig{base}
+	case tipb.ScalarFuncSig_TimestampDiff:
+		f = &builtinTimestampDiffSig{base}
+	case tipb.ScalarFuncSig_StrToDateDate:
+		f = &builtinStrToDateDateSig{base}
+	case tipb.ScalarFuncSig_StrToDateDatetime:
+		f = &builtinStrToDateDatetimeSig{base}
+	case tipb.ScalarFuncSig_StrToDateDuration:
+		f = &builtinStrToDateDurationSig{base}
 
 	default:
 		e = errFunctionNotExists.GenWithStackByArgs("FUNCTION", sigCode)
@@ -201,6 +538,14 @@ func PBToExpr(expr *tipb.Expr, tps []*types.FieldType, sc *stmtctx.StatementCont
 		return convertFloat(expr.Val, true)
 	case tipb.ExprType_Float64:
 		return convertFloat(expr.Val, false)
+	case tipb.ExprType_MysqlDecimal:
+		return convertDecimal(expr.Val)
+	case tipb.ExprType_MysqlDuration:
+		return convertDuration(expr.Val)
+	case tipb.ExprType_MysqlTime:
+		return convertTime(expr.Val, expr.FieldType, sc.TimeZone)
+	case tipb.ExprType_MysqlJson:
+		return convertJSON(expr.Val)
 	}
 	if expr.Tp != tipb.ExprType_ScalarFunc {
 		panic("should be a tipb.ExprType_ScalarFunc")
@@ -269,6 +614,48 @@ func convertString(val []byte) (*Constant, error) {
 	return &Constant{Value: d, RetType: types.NewFieldType(mysql.TypeVarString)}, nil
 }
 
+func convertDecimal(val []byte) (*Constant, error) {
+	_, dec, precision, frac, err := codec.DecodeDecimal(val)
+	if err != nil {
+		return nil, errors.Errorf("invalid decimal % x", val)
+	}
+	var d types.Datum
+	d.SetMysqlDecimal(dec)
+	d.SetLength(precision)
+	d.SetFrac(frac)
+	return &Constant{Value: d, RetType: types.NewFieldType(mysql.TypeNewDecimal)}, nil
+}
+
+func convertTime(data []byte, ftPB *tipb.FieldType, tz *time.Location) (*Constant, error) {
+	ft := PbTypeToFieldType(ftPB)
+	_, v, err := codec.DecodeUint(data)
+	if err != nil {
+		return nil, err
+	}
+	t := types.NewTime(types.ZeroCoreTime, ft.Tp, int8(ft.Decimal))
+	err = t.FromPackedUint(v)
+	if err != nil {
+		return nil, err
+	}
+	if ft.Tp == mysql.TypeTimestamp && tz != nil && tz != time.UTC {
+		err = t.ConvertTimeZone(time.UTC, tz)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &Constant{Value: types.NewTimeDatum(t), RetType: ft}, nil
+}
+
+func convertDuration(val []byte) (*Constant, error) {
+	var d types.Datum
+	_, i, err := codec.DecodeInt(val)
+	if err != nil {
+		return nil, errors.Errorf("invalid duration %d", i)
+	}
+	d.SetMysqlDuration(types.Duration{Duration: time.Duration(i), Fsp: types.MaxFsp})
+	return &Constant{Value: d, RetType: types.NewFieldType(mysql.TypeDuration)}, nil
+}
+
 func convertFloat(val []byte, f32 bool) (*Constant, error) {
 	var d types.Datum
 	_, f, err := codec.DecodeFloat(val)
@@ -282,3 +669,12 @@ func convertFloat(val []byte, f32 bool) (*Constant, error) {
 	}
 	return &Constant{Value: d, RetType: types.NewFieldType(mysql.TypeDouble)}, nil
 }
+
+func convertJSON(val []byte) (*Constant, error) {
+	if len(val) == 0 {
+		return nil, errors.Errorf("invalid json % x", val)
+	}
+	var d types.Datum
+	d.SetMysqlJSON(json.BinaryJSON{TypeCode: val[0], Value: val[1:]})
+	return &Constant{Value: d, RetType: types.NewFieldType(mysql.TypeJSON)}, nil
+}
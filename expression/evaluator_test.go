@@ -86,6 +86,8 @@ func (s *testEvaluatorSuite) kindToFieldType(kind byte) types.FieldType {
 		ft.Tp = mysql.TypeDouble
 	case types.KindFloat64:
 		ft.Tp = mysql.TypeDouble
+	case types.KindMysqlDecimal:
+		ft.Tp = mysql.TypeNewDecimal
 	case types.KindString:
 		ft.Tp = mysql.TypeVarString
 	case types.KindBytes:
@@ -85,7 +85,7 @@ func ExplainExpressionList(exprs []Expression, schema *Schema) string {
 	builder := &strings.Builder{}
 	for i, expr := range exprs {
 		switch expr.(type) {
-		case *Column:
+		case *Column, *CorrelatedColumn:
 			builder.WriteString(expr.String())
 		default:
 			fmt.Fprintf(builder, "%v->%v", expr.String(), schema.Columns[i])
@@ -88,7 +88,7 @@ func NewPBConverter(client kv.Client, sc *stmtctx.StatementContext) PbConverter
 // ExprToPB converts Expression to TiPB.
 func (pc PbConverter) ExprToPB(expr Expression) *tipb.Expr {
 	switch x := expr.(type) {
-	case *Constant:
+	case *Constant, *CorrelatedColumn:
 		return pc.conOrCorColToPBExpr(expr)
 	case *Column:
 		return pc.columnToPBExpr(x)
@@ -142,6 +142,31 @@ func (pc *PbConverter) encodeDatum(ft *types.FieldType, d types.Datum) (tipb.Exp
 	case types.KindFloat64:
 		tp = tipb.ExprType_Float64
 		val = codec.EncodeFloat(nil, d.GetFloat64())
+	case types.KindMysqlDecimal:
+		tp = tipb.ExprType_MysqlDecimal
+		var err error
+		val, err = codec.EncodeDecimal(nil, d.GetMysqlDecimal(), d.Length(), d.Frac())
+		if err != nil {
+			logutil.BgLogger().Error("encode decimal", zap.Error(err))
+			return tp, nil, false
+		}
+	case types.KindMysqlJSON:
+		tp = tipb.ExprType_MysqlJson
+		j := d.GetMysqlJSON()
+		val = make([]byte, 0, 1+len(j.Value))
+		val = append(val, j.TypeCode)
+		val = append(val, j.Value...)
+	case types.KindMysqlDuration:
+		tp = tipb.ExprType_MysqlDuration
+		val = codec.EncodeInt(nil, int64(d.GetMysqlDuration().Duration))
+	case types.KindMysqlTime:
+		tp = tipb.ExprType_MysqlTime
+		var err error
+		val, err = codec.EncodeMySQLTime(pc.sc, d.GetMysqlTime(), ft.Tp, nil)
+		if err != nil {
+			logutil.BgLogger().Error("encode mysql time", zap.Error(err))
+			return tp, nil, false
+		}
 	default:
 		return tp, nil, false
 	}
@@ -300,13 +325,74 @@ func (pc PbConverter) canFuncBePushed(sf *ScalarFunction) bool {
 		ast.Minus,
 		ast.Mul,
 		ast.Div,
+		ast.Mod,
+		ast.IntDiv,
+
+		// math functions.
+		ast.Abs,
+		ast.Ceil,
+		ast.Ceiling,
+		ast.Floor,
+		ast.Round,
+		ast.Truncate,
+		ast.Pow,
+		ast.Power,
+		ast.Sqrt,
+		ast.Exp,
+		ast.Ln,
+		ast.Log,
+		ast.Log2,
+		ast.Log10,
+		ast.Sign,
+		ast.PI,
+		ast.Sin,
+		ast.Cos,
+		ast.Tan,
+		ast.Cot,
+		ast.Asin,
+		ast.Acos,
+		ast.Atan,
+		ast.Atan2,
+		ast.Degrees,
+		ast.Radians,
 
 		// control flow functions.
+		ast.Case,
 		ast.If,
 		ast.Ifnull,
 
+		// compare functions.
+		ast.Coalesce,
+		ast.Greatest,
+		ast.Least,
+
 		// string functions.
-		ast.Length:
+		ast.Length,
+		ast.Strcmp,
+		ast.Concat,
+		ast.ConcatWS,
+		ast.Locate,
+		ast.Replace,
+		ast.Hex,
+		ast.Reverse,
+		ast.LTrim,
+		ast.RTrim,
+		ast.Left,
+		ast.Elt,
+		ast.Field,
+		ast.Like,
+		ast.Regexp,
+
+		// date functions.
+		ast.Year,
+		ast.Month,
+		ast.Day,
+		ast.DayOfMonth,
+		ast.DateFormat,
+		ast.DateDiff,
+		ast.Extract,
+		ast.TimestampDiff,
+		ast.StrToDate:
 		return true
 	}
 	return false
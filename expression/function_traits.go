@@ -22,6 +22,25 @@ var unFoldableFunctions = map[string]struct{}{
 	ast.RowFunc: {},
 	ast.SetVar:  {},
 	ast.GetVar:  {},
+	ast.Rand:    {},
+	ast.Sysdate: {},
+}
+
+// DeferredFunctions stores functions which are foldable but should be deferred as well when plan cache is enabled.
+// Note that, these functions must be foldable at first place, i.e, they are not in `unFoldableFunctions`.
+var DeferredFunctions = map[string]struct{}{
+	ast.Now:              {},
+	ast.CurrentTimestamp: {},
+	ast.LocalTime:        {},
+	ast.LocalTimestamp:   {},
+	ast.UTCTime:          {},
+	ast.Curtime:          {},
+	ast.CurrentTime:      {},
+	ast.UTCTimestamp:     {},
+	ast.UnixTimestamp:    {},
+	ast.Curdate:          {},
+	ast.CurrentDate:      {},
+	ast.UTCDate:          {},
 }
 
 // inequalFunctions stores functions which cannot be propagated from column equal condition.
@@ -32,6 +51,8 @@ var inequalFunctions = map[string]struct{}{
 // mutableEffectsFunctions stores functions which are mutable or have side effects, specifically,
 // we cannot remove them from filter even if they have duplicates.
 var mutableEffectsFunctions = map[string]struct{}{
-	ast.SetVar: {},
-	ast.GetVar: {},
+	ast.SetVar:  {},
+	ast.GetVar:  {},
+	ast.Rand:    {},
+	ast.Sysdate: {},
 }
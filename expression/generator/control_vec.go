@@ -198,6 +198,99 @@ func (b *builtinIf{{ .TypeName }}Sig) vectorized() bool {
 {{ end }}{{/* range .Sigs */}}
 `))
 
+var builtinCaseWhenVec = template.Must(template.New("builtinCaseWhenVec").Parse(`
+{{ range .Sigs }}{{ with .Arg0 }}
+func (b *builtinCaseWhen{{ .TypeName }}Sig) vecEval{{ .TypeName }}(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	l := len(b.args)
+	bufWhens := make([]*chunk.Column, l/2)
+	bufThens := make([]*chunk.Column, l/2)
+	var bufElse *chunk.Column
+	for j := 0; j < l-1; j += 2 {
+		bufWhen, err := b.bufAllocator.get(types.ETInt, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(bufWhen)
+		if err := b.args[j].VecEvalInt(b.ctx, input, bufWhen); err != nil {
+			return err
+		}
+		bufWhens[j/2] = bufWhen
+
+		bufThen, err := b.bufAllocator.get(types.ET{{ .ETName }}, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(bufThen)
+		if err := b.args[j+1].VecEval{{ .TypeName }}(b.ctx, input, bufThen); err != nil {
+			return err
+		}
+		bufThens[j/2] = bufThen
+	}
+	// When clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// Else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	if l%2 == 1 {
+		var err error
+		bufElse, err = b.bufAllocator.get(types.ET{{ .ETName }}, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(bufElse)
+		if err := b.args[l-1].VecEval{{ .TypeName }}(b.ctx, input, bufElse); err != nil {
+			return err
+		}
+	}
+
+{{- if .Fixed }}
+	result.Resize{{ .TypeNameInColumn }}(n, false)
+	rs := result.{{ .TypeNameInColumn }}s()
+{{- else }}
+	result.Reserve{{ .TypeNameInColumn }}(n)
+{{- end }}
+ROW:
+	for i := 0; i < n; i++ {
+		for j := 0; j < l/2; j++ {
+			if bufWhens[j].IsNull(i) || bufWhens[j].GetInt64(i) == 0 {
+				continue
+			}
+{{- if .Fixed }}
+			rs[i] = bufThens[j].Get{{ .TypeNameInColumn }}(i)
+			result.SetNull(i, bufThens[j].IsNull(i))
+{{- else }}
+			if bufThens[j].IsNull(i) {
+				result.AppendNull()
+			} else {
+				result.Append{{ .TypeNameInColumn }}(bufThens[j].Get{{ .TypeNameInColumn }}(i))
+			}
+{{- end }}
+			continue ROW
+		}
+{{- if .Fixed }}
+		if bufElse != nil {
+			rs[i] = bufElse.Get{{ .TypeNameInColumn }}(i)
+			result.SetNull(i, bufElse.IsNull(i))
+		} else {
+			result.SetNull(i, true)
+		}
+{{- else }}
+		if bufElse != nil && !bufElse.IsNull(i) {
+			result.Append{{ .TypeNameInColumn }}(bufElse.Get{{ .TypeNameInColumn }}(i))
+		} else {
+			result.AppendNull()
+		}
+{{- end }}
+	}
+	return nil
+}
+
+func (b *builtinCaseWhen{{ .TypeName }}Sig) vectorized() bool {
+	return true
+}
+{{ end }}{{/* with */}}
+{{ end }}{{/* range .Sigs */}}
+`))
+
 var testFile = template.Must(template.New("testFile").Parse(`// Copyright 2019 PingCAP, Inc.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
@@ -255,6 +348,16 @@ var vecBuiltin{{.Category}}Cases = map[string][]vecExprBenchCase{
 	{{ end }}
 	},
 {{ end }}
+
+{{ with index .Functions 2 }}
+	ast.Case: {
+	{{ range .Sigs }}
+		{retEvalType: types.ET{{ .Arg0.ETName }}, childrenTypes: []types.EvalType{types.ETInt, types.ET{{ .Arg0.ETName }}}, geners: []dataGenerator{defaultControlIntGener}},
+		{retEvalType: types.ET{{ .Arg0.ETName }}, childrenTypes: []types.EvalType{types.ETInt, types.ET{{ .Arg0.ETName }}, types.ET{{ .Arg0.ETName }}}, geners: []dataGenerator{defaultControlIntGener}},
+		{retEvalType: types.ET{{ .Arg0.ETName }}, childrenTypes: []types.EvalType{types.ETInt, types.ET{{ .Arg0.ETName }}, types.ETInt, types.ET{{ .Arg0.ETName }}, types.ET{{ .Arg0.ETName }}}, geners: []dataGenerator{defaultControlIntGener, nil, defaultControlIntGener}},
+	{{ end }}
+	},
+{{ end }}
 }
 
 func (s *testEvaluatorSuite) TestVectorizedBuiltin{{.Category}}EvalOneVecGenerated(c *C) {
@@ -301,6 +404,12 @@ var ifSigs = []sig{
 	{Arg0: TypeString},
 }
 
+var caseWhenSigs = []sig{
+	{Arg0: TypeInt},
+	{Arg0: TypeReal},
+	{Arg0: TypeString},
+}
+
 type sig struct {
 	Arg0 TypeContext
 }
@@ -319,6 +428,7 @@ var tmplVal = struct {
 	Functions: []function{
 		{FuncName: "Ifnull", Sigs: ifNullSigs, Tmpl: builtinIfNullVec},
 		{FuncName: "If", Sigs: ifSigs, Tmpl: builtinIfVec},
+		{FuncName: "Case", Sigs: caseWhenSigs, Tmpl: builtinCaseWhenVec},
 	},
 }
 
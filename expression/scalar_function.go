@@ -18,12 +18,14 @@ import (
 	"fmt"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/hack"
@@ -69,6 +71,11 @@ func (sf *ScalarFunction) VecEvalDuration(ctx sessionctx.Context, input *chunk.C
 	return sf.Function.vecEvalDuration(input, result)
 }
 
+// VecEvalJSON evaluates this expression in a vectorized manner.
+func (sf *ScalarFunction) VecEvalJSON(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return sf.Function.vecEvalJSON(input, result)
+}
+
 // GetArgs gets arguments of function.
 func (sf *ScalarFunction) GetArgs() []Expression {
 	return sf.Function.getArgs()
@@ -108,6 +115,9 @@ func newFunctionImpl(ctx sessionctx.Context, fold bool, funcName string, retType
 	if retType == nil {
 		return nil, errors.Errorf("RetType cannot be nil for ScalarFunction.")
 	}
+	if funcName == ast.Cast {
+		return BuildCastFunction(ctx, args[0], retType), nil
+	}
 	fc, ok := funcs[funcName]
 	if !ok {
 		return nil, errFunctionNotExists.GenWithStackByArgs("FUNCTION", funcName)
@@ -234,8 +244,16 @@ func (sf *ScalarFunction) Eval(row chunk.Row) (d types.Datum, err error) {
 		}
 	case types.ETReal:
 		res, isNull, err = sf.EvalReal(sf.GetCtx(), row)
+	case types.ETDecimal:
+		res, isNull, err = sf.EvalDecimal(sf.GetCtx(), row)
 	case types.ETString:
 		res, isNull, err = sf.EvalString(sf.GetCtx(), row)
+	case types.ETDatetime, types.ETTimestamp:
+		res, isNull, err = sf.EvalTime(sf.GetCtx(), row)
+	case types.ETDuration:
+		res, isNull, err = sf.EvalDuration(sf.GetCtx(), row)
+	case types.ETJson:
+		res, isNull, err = sf.EvalJSON(sf.GetCtx(), row)
 	}
 
 	if isNull || err != nil {
@@ -261,6 +279,26 @@ func (sf *ScalarFunction) EvalString(ctx sessionctx.Context, row chunk.Row) (str
 	return sf.Function.evalString(row)
 }
 
+// EvalDecimal implements Expression interface.
+func (sf *ScalarFunction) EvalDecimal(ctx sessionctx.Context, row chunk.Row) (*types.MyDecimal, bool, error) {
+	return sf.Function.evalDecimal(row)
+}
+
+// EvalTime implements Expression interface.
+func (sf *ScalarFunction) EvalTime(ctx sessionctx.Context, row chunk.Row) (types.Time, bool, error) {
+	return sf.Function.evalTime(row)
+}
+
+// EvalDuration implements Expression interface.
+func (sf *ScalarFunction) EvalDuration(ctx sessionctx.Context, row chunk.Row) (types.Duration, bool, error) {
+	return sf.Function.evalDuration(row)
+}
+
+// EvalJSON implements Expression interface.
+func (sf *ScalarFunction) EvalJSON(ctx sessionctx.Context, row chunk.Row) (json.BinaryJSON, bool, error) {
+	return sf.Function.evalJSON(row)
+}
+
 // HashCode implements Expression interface.
 func (sf *ScalarFunction) HashCode(sc *stmtctx.StatementContext) []byte {
 	if len(sf.hashcode) > 0 {
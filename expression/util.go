@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/logutil"
 	"go.uber.org/zap"
@@ -138,6 +139,34 @@ func extractColumnSet(expr Expression, set *intsets.Sparse) {
 	}
 }
 
+// ExtractCorColumns extracts correlated column from given expression.
+func ExtractCorColumns(expr Expression) (cols []*CorrelatedColumn) {
+	switch v := expr.(type) {
+	case *CorrelatedColumn:
+		return []*CorrelatedColumn{v}
+	case *ScalarFunction:
+		for _, arg := range v.GetArgs() {
+			cols = append(cols, ExtractCorColumns(arg)...)
+		}
+	}
+	return
+}
+
+func isColumnInOperand(c *Column) bool {
+	return c.InOperand
+}
+
+// IsEQCondFromIn checks if an expression is equal condition converted from `[not] in (subq)`.
+func IsEQCondFromIn(expr Expression) bool {
+	sf, ok := expr.(*ScalarFunction)
+	if !ok || sf.FuncName.L != ast.EQ {
+		return false
+	}
+	cols := make([]*Column, 0, 1)
+	cols = ExtractColumnsFromExpressions(cols, sf.GetArgs(), isColumnInOperand)
+	return len(cols) > 0
+}
+
 // ColumnSubstitute substitutes the columns in filter to expressions in select fields.
 // e.g. select * from (select b as a from t) k where a < 10 => select * from (select b as a from t where b < 10) k.
 func ColumnSubstitute(expr Expression, schema *Schema, newExprs []Expression) Expression {
@@ -421,6 +450,51 @@ func DatumToConstant(d types.Datum, tp byte) *Constant {
 	return &Constant{Value: d, RetType: types.NewFieldType(tp)}
 }
 
+// datumToTime converts a non-null datum to a DATE/DATETIME/TIMESTAMP value. The field type
+// is used as the target type only when it's a time type, otherwise DATETIME(6) is used.
+func datumToTime(ctx sessionctx.Context, d types.Datum, tp *types.FieldType) (types.Time, bool, error) {
+	if d.Kind() == types.KindMysqlTime {
+		return d.GetMysqlTime(), false, nil
+	}
+	if !types.IsTypeTime(tp.Tp) {
+		tp = types.NewFieldType(mysql.TypeDatetime)
+		tp.Decimal = int(types.MaxFsp)
+	}
+	v, err := d.ConvertTo(ctx.GetSessionVars().StmtCtx, tp)
+	if err != nil || v.IsNull() {
+		return types.ZeroTime, true, handleInvalidTimeError(ctx, err)
+	}
+	return v.GetMysqlTime(), false, nil
+}
+
+// datumToDuration converts a non-null datum to a TIME value.
+func datumToDuration(ctx sessionctx.Context, d types.Datum, tp *types.FieldType) (types.Duration, bool, error) {
+	if d.Kind() == types.KindMysqlDuration {
+		return d.GetMysqlDuration(), false, nil
+	}
+	if tp.Tp != mysql.TypeDuration {
+		tp = types.NewFieldType(mysql.TypeDuration)
+		tp.Decimal = int(types.MaxFsp)
+	}
+	v, err := d.ConvertTo(ctx.GetSessionVars().StmtCtx, tp)
+	if err != nil || v.IsNull() {
+		return types.ZeroDuration, true, handleInvalidTimeError(ctx, err)
+	}
+	return v.GetMysqlDuration(), false, nil
+}
+
+// datumToJSON converts a non-null datum to a JSON value. Strings are parsed as JSON text.
+func datumToJSON(ctx sessionctx.Context, d types.Datum) (json.BinaryJSON, bool, error) {
+	if d.Kind() == types.KindMysqlJSON {
+		return d.GetMysqlJSON(), false, nil
+	}
+	v, err := d.ConvertTo(ctx.GetSessionVars().StmtCtx, types.NewFieldType(mysql.TypeJSON))
+	if err != nil || v.IsNull() {
+		return json.BinaryJSON{}, true, err
+	}
+	return v.GetMysqlJSON(), false, nil
+}
+
 // GetStringFromConstant gets a string value from the Constant expression.
 func GetStringFromConstant(ctx sessionctx.Context, value Expression) (string, bool, error) {
 	con, ok := value.(*Constant)
@@ -472,6 +546,25 @@ func IsMutableEffectsExpr(expr Expression) bool {
 	return false
 }
 
+// ContainMutableConst checks if the expressions contain a constant whose value
+// may change between executions of a cached plan, such a constant must not be
+// used to simplify the plan.
+func ContainMutableConst(exprs []Expression) bool {
+	for _, expr := range exprs {
+		switch x := expr.(type) {
+		case *Constant:
+			if x.isMutable() {
+				return true
+			}
+		case *ScalarFunction:
+			if ContainMutableConst(x.GetArgs()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // RemoveDupExprs removes identical exprs. Not that if expr contains functions which
 // are mutable or have side effects, we cannot remove it even if it has duplicates.
 func RemoveDupExprs(ctx sessionctx.Context, exprs []Expression) []Expression {
@@ -496,6 +589,9 @@ func GetUint64FromConstant(expr Expression) (uint64, bool, bool) {
 		return 0, false, false
 	}
 	dt := con.Value
+	if con.ParamMarker != nil {
+		dt = con.ParamMarker.GetUserVar()
+	}
 	switch dt.Kind() {
 	case types.KindNull:
 		return 0, true, true
@@ -510,3 +606,10 @@ func GetUint64FromConstant(expr Expression) (uint64, bool, bool) {
 	}
 	return 0, false, false
 }
+
+func boolToInt64(v bool) int64 {
+	if v {
+		return 1
+	}
+	return 0
+}
@@ -56,7 +56,15 @@ func (b *Builder) ApplyDiff(m *meta.Meta, diff *model.SchemaDiff) ([]int64, erro
 	case model.ActionCreateTable:
 		newTableID = diff.TableID
 		tblIDs = append(tblIDs, newTableID)
-	case model.ActionDropTable:
+	case model.ActionCreateView:
+		// A replaced view is dropped and the new one is created.
+		oldTableID = diff.OldTableID
+		newTableID = diff.TableID
+		if tableIDIsValid(oldTableID) {
+			tblIDs = append(tblIDs, oldTableID)
+		}
+		tblIDs = append(tblIDs, newTableID)
+	case model.ActionDropTable, model.ActionDropView:
 		oldTableID = diff.TableID
 		tblIDs = append(tblIDs, oldTableID)
 	default:
@@ -122,3 +122,13 @@ func (t *InjectedSnapshot) Get(ctx context.Context, k Key) ([]byte, error) {
 	}
 	return t.Snapshot.Get(ctx, k)
 }
+
+// BatchGet returns an error if cfg.getError is set.
+func (t *InjectedSnapshot) BatchGet(ctx context.Context, keys []Key) (map[string][]byte, error) {
+	t.cfg.RLock()
+	defer t.cfg.RUnlock()
+	if t.cfg.getError != nil {
+		return nil, t.cfg.getError
+	}
+	return t.Snapshot.BatchGet(ctx, keys)
+}
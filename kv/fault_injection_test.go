@@ -46,6 +46,9 @@ func (s testFaultInjectionSuite) TestFaultInjectionBasic(c *C) {
 	b, err = snap.Get(context.TODO(), []byte{'a'})
 	c.Assert(err.Error(), Equals, err1.Error())
 	c.Assert(b, IsNil)
+	bs, err := snap.BatchGet(context.Background(), []kv.Key{[]byte("a")})
+	c.Assert(err.Error(), Equals, err1.Error())
+	c.Assert(bs, IsNil)
 
 	err = txn.Commit(context.Background())
 	c.Assert(err.Error(), Equals, err1.Error())
@@ -63,6 +66,10 @@ func (s testFaultInjectionSuite) TestFaultInjectionBasic(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(b, IsNil)
 
+	bs, err = snap.BatchGet(context.Background(), []kv.Key{[]byte("a")})
+	c.Assert(err, IsNil)
+	c.Assert(len(bs), Equals, 0)
+
 	err = txn.Commit(context.Background())
 	c.Assert(err, NotNil)
 	c.Assert(terror.ErrorEqual(err, kv.ErrTxnRetryable), IsTrue)
@@ -47,6 +47,8 @@ const (
 	SnapshotTS
 	// Set replica read
 	ReplicaRead
+	// Pessimistic is defined for pessimistic transaction.
+	Pessimistic
 )
 
 // Priority value for transaction priority.
@@ -175,16 +177,27 @@ type Transaction interface {
 	Valid() bool
 	// GetMemBuffer return the MemBuffer binding to this transaction.
 	GetMemBuffer() MemBuffer
+	// GetSnapshot returns the snapshot of this transaction.
+	GetSnapshot() Snapshot
 	// SetVars sets variables to the transaction.
 	SetVars(vars *Variables)
 }
 
 // LockCtx contains information for LockKeys method.
 type LockCtx struct {
-	Killed      *uint32
-	ForUpdateTS uint64
+	Killed       *uint32
+	ForUpdateTS  uint64
+	LockWaitTime int64
 }
 
+// Lock wait time values of LockCtx.
+const (
+	// LockAlwaysWait is the lock wait time which means waiting for the lock until the lock is released.
+	LockAlwaysWait = int64(0)
+	// LockNoWait is the lock wait time which means returning an error immediately if the lock is held by others.
+	LockNoWait = int64(-1)
+)
+
 // Client is used to send request to KV layer.
 type Client interface {
 	// Send sends request to KV layer, returns a Response.
@@ -258,6 +271,9 @@ type Response interface {
 // Snapshot defines the interface for the snapshot fetched from KV store.
 type Snapshot interface {
 	Retriever
+	// BatchGet gets a batch of values from snapshot.
+	// The returned map only contains the keys that exist.
+	BatchGet(ctx context.Context, keys []Key) (map[string][]byte, error)
 }
 
 // Driver is the interface that must be implemented by a KV storage.
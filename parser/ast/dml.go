@@ -23,6 +23,7 @@ var (
 	_ DMLNode = &InsertStmt{}
 	_ DMLNode = &SelectStmt{}
 	_ DMLNode = &ShowStmt{}
+	_ DMLNode = &UpdateStmt{}
 
 	_ Node = &Assignment{}
 	_ Node = &ByItem{}
@@ -390,6 +391,29 @@ func (n *OrderByClause) Accept(v Visitor) (Node, bool) {
 
 // SelectStmt represents the select query node.
 // See https://dev.mysql.com/doc/refman/5.7/en/select.html
+// SelectLockType is the lock type for SelectStmt.
+type SelectLockType int
+
+// Select lock types.
+const (
+	SelectLockNone SelectLockType = iota
+	SelectLockForUpdate
+	SelectLockForUpdateNoWait
+)
+
+// String implements fmt.Stringer.
+func (slt SelectLockType) String() string {
+	switch slt {
+	case SelectLockNone:
+		return "none"
+	case SelectLockForUpdate:
+		return "for update"
+	case SelectLockForUpdateNoWait:
+		return "for update nowait"
+	}
+	return "unsupported select lock type"
+}
+
 type SelectStmt struct {
 	dmlNode
 
@@ -411,10 +435,18 @@ type SelectStmt struct {
 	OrderBy *OrderByClause
 	// Limit is the limit clause.
 	Limit *Limit
+	// WindowSpecs is the window specification list defined in the WINDOW clause.
+	WindowSpecs []WindowSpec
 	// TableHints represents the table level Optimizer Hint for join type
 	TableHints []*TableOptimizerHint
 	// IsInBraces indicates whether it's a stmt in brace.
 	IsInBraces bool
+	// AfterSetOperator indicates the SelectStmt after which type of set operator.
+	AfterSetOperator *SetOprType
+	// With is the WITH clause of the select statement.
+	With *WithClause
+	// LockTp is the lock type
+	LockTp SelectLockType
 }
 
 // Accept implements Node Accept interface.
@@ -425,6 +457,14 @@ func (n *SelectStmt) Accept(v Visitor) (Node, bool) {
 	}
 
 	n = newNode.(*SelectStmt)
+	if n.With != nil {
+		node, ok := n.With.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.With = node.(*WithClause)
+	}
+
 	if n.TableHints != nil && len(n.TableHints) != 0 {
 		newHints := make([]*TableOptimizerHint, len(n.TableHints))
 		for i, hint := range n.TableHints {
@@ -477,6 +517,14 @@ func (n *SelectStmt) Accept(v Visitor) (Node, bool) {
 		n.Having = node.(*HavingClause)
 	}
 
+	for i, spec := range n.WindowSpecs {
+		node, ok := spec.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.WindowSpecs[i] = *node.(*WindowSpec)
+	}
+
 	if n.OrderBy != nil {
 		node, ok := n.OrderBy.Accept(v)
 		if !ok {
@@ -496,6 +544,311 @@ func (n *SelectStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// WindowSpec is the specification of a window.
+// See https://dev.mysql.com/doc/refman/8.0/en/window-functions-named-windows.html
+type WindowSpec struct {
+	node
+
+	// Name is the name of the window defined in the WINDOW clause.
+	Name model.CIStr
+	// Ref is the name of the existing window this specification refers to.
+	Ref model.CIStr
+
+	PartitionBy *PartitionByClause
+	OrderBy     *OrderByClause
+	Frame       *FrameClause
+
+	// OnlyAlias is true when the window function is written as `OVER w`.
+	OnlyAlias bool
+}
+
+// Accept implements Node Accept interface.
+func (n *WindowSpec) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*WindowSpec)
+	if n.PartitionBy != nil {
+		node, ok := n.PartitionBy.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.PartitionBy = node.(*PartitionByClause)
+	}
+	if n.OrderBy != nil {
+		node, ok := n.OrderBy.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.OrderBy = node.(*OrderByClause)
+	}
+	if n.Frame != nil {
+		node, ok := n.Frame.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Frame = node.(*FrameClause)
+	}
+	return v.Leave(n)
+}
+
+// PartitionByClause represents partition by clause of a window specification.
+type PartitionByClause struct {
+	node
+
+	Items []*ByItem
+}
+
+// Accept implements Node Accept interface.
+func (n *PartitionByClause) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*PartitionByClause)
+	for i, val := range n.Items {
+		node, ok := val.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Items[i] = node.(*ByItem)
+	}
+	return v.Leave(n)
+}
+
+// FrameType is the type of window frame.
+type FrameType int
+
+// Window frame types.
+const (
+	Rows FrameType = iota
+	Ranges
+)
+
+// FrameClause represents frame clause of a window specification.
+type FrameClause struct {
+	node
+
+	Type   FrameType
+	Extent FrameExtent
+}
+
+// Accept implements Node Accept interface.
+func (n *FrameClause) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*FrameClause)
+	node, ok := n.Extent.Start.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Extent.Start = *node.(*FrameBound)
+	node, ok = n.Extent.End.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Extent.End = *node.(*FrameBound)
+	return v.Leave(n)
+}
+
+// FrameExtent represents the start and end of a window frame.
+type FrameExtent struct {
+	Start FrameBound
+	End   FrameBound
+}
+
+// BoundType is the type of window frame bound.
+type BoundType int
+
+// Window frame bound types.
+const (
+	Following BoundType = iota
+	Preceding
+	CurrentRow
+)
+
+// FrameBound represents a bound of a window frame.
+type FrameBound struct {
+	node
+
+	Type      BoundType
+	UnBounded bool
+	// Expr is the offset of the bound, it is nil for UNBOUNDED and CURRENT ROW.
+	Expr ExprNode
+}
+
+// Accept implements Node Accept interface.
+func (n *FrameBound) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*FrameBound)
+	if n.Expr != nil {
+		node, ok := n.Expr.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Expr = node.(ExprNode)
+	}
+	return v.Leave(n)
+}
+
+// SetOprType is the type of a set operator.
+type SetOprType uint8
+
+// Set operator types.
+const (
+	Union SetOprType = iota
+	UnionAll
+	Except
+	Intersect
+)
+
+func (s *SetOprType) String() string {
+	switch *s {
+	case Union:
+		return "UNION"
+	case UnionAll:
+		return "UNION ALL"
+	case Except:
+		return "EXCEPT"
+	case Intersect:
+		return "INTERSECT"
+	}
+	return ""
+}
+
+// SetOprSelectList represents the select list in a set operation statement.
+type SetOprSelectList struct {
+	node
+
+	Selects []*SelectStmt
+}
+
+// Accept implements Node Accept interface.
+func (n *SetOprSelectList) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*SetOprSelectList)
+	for i, sel := range n.Selects {
+		node, ok := sel.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Selects[i] = node.(*SelectStmt)
+	}
+	return v.Leave(n)
+}
+
+// SetOprStmt represents "union/except/intersect statement"
+// See https://dev.mysql.com/doc/refman/5.7/en/union.html
+type SetOprStmt struct {
+	dmlNode
+
+	SelectList *SetOprSelectList
+	OrderBy    *OrderByClause
+	Limit      *Limit
+	With       *WithClause
+}
+
+// Accept implements Node Accept interface.
+func (n *SetOprStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*SetOprStmt)
+	if n.With != nil {
+		node, ok := n.With.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.With = node.(*WithClause)
+	}
+	if n.SelectList != nil {
+		node, ok := n.SelectList.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.SelectList = node.(*SetOprSelectList)
+	}
+	if n.OrderBy != nil {
+		node, ok := n.OrderBy.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.OrderBy = node.(*OrderByClause)
+	}
+	if n.Limit != nil {
+		node, ok := n.Limit.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Limit = node.(*Limit)
+	}
+	return v.Leave(n)
+}
+
+// CommonTableExpression is a named temporary result set defined in a WITH clause.
+// See https://dev.mysql.com/doc/refman/8.0/en/with.html
+type CommonTableExpression struct {
+	node
+
+	// Name is the name of the common table expression.
+	Name model.CIStr
+	// Query is the query which defines the common table expression.
+	Query *SubqueryExpr
+	// ColNameList is the optional list of column names.
+	ColNameList []model.CIStr
+}
+
+// Accept implements Node Accept interface.
+func (n *CommonTableExpression) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*CommonTableExpression)
+	node, ok := n.Query.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Query = node.(*SubqueryExpr)
+	return v.Leave(n)
+}
+
+// WithClause is the WITH clause of a SELECT or set operation statement.
+type WithClause struct {
+	node
+
+	// IsRecursive indicates whether the RECURSIVE keyword is specified.
+	IsRecursive bool
+	CTEs        []*CommonTableExpression
+}
+
+// Accept implements Node Accept interface.
+func (n *WithClause) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*WithClause)
+	for i, cte := range n.CTEs {
+		node, ok := cte.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.CTEs[i] = node.(*CommonTableExpression)
+	}
+	return v.Leave(n)
+}
+
 // Assignment is the expression for assignment, like a = 1.
 type Assignment struct {
 	node
@@ -644,6 +997,65 @@ func (n *DeleteStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// UpdateStmt is a statement to update columns of existing rows in tables with new values.
+// See https://dev.mysql.com/doc/refman/5.7/en/update.html
+type UpdateStmt struct {
+	dmlNode
+
+	// TableRefs is used in both single table and multiple table update statement.
+	TableRefs     *TableRefsClause
+	List          []*Assignment
+	Where         ExprNode
+	Order         *OrderByClause
+	Limit         *Limit
+	Priority      mysql.PriorityEnum
+	MultipleTable bool
+	TableHints    []*TableOptimizerHint
+}
+
+// Accept implements Node Accept interface.
+func (n *UpdateStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*UpdateStmt)
+	node, ok := n.TableRefs.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.TableRefs = node.(*TableRefsClause)
+	for i, val := range n.List {
+		node, ok = val.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.List[i] = node.(*Assignment)
+	}
+	if n.Where != nil {
+		node, ok = n.Where.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Where = node.(ExprNode)
+	}
+	if n.Order != nil {
+		node, ok = n.Order.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Order = node.(*OrderByClause)
+	}
+	if n.Limit != nil {
+		node, ok = n.Limit.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Limit = node.(*Limit)
+	}
+	return v.Leave(n)
+}
+
 // Limit is the limit clause.
 type Limit struct {
 	node
@@ -691,6 +1103,8 @@ const (
 	ShowProcessList
 	ShowCreateDatabase
 	ShowErrors
+	ShowCreateView
+	ShowStatus
 )
 
 // ShowStmt is a statement to provide information about databases, tables, columns and so on.
@@ -708,7 +1122,7 @@ type ShowStmt struct {
 	IfNotExists bool // Used for `show create database if not exists`
 	Extended    bool // Used for `show extended columns from ...`
 
-	// GlobalScope is used by `show variables` and `show bindings`
+	// GlobalScope is used by `show variables`, `show status` and `show bindings`
 	GlobalScope bool
 	Where       ExprNode
 }
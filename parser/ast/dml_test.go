@@ -46,10 +46,14 @@ func (ts *testDMLSuite) TestDMLVisitorCover(c *C) {
 		{tableRefsClause, 1, 1},
 		{&TableSource{Source: &TableName{}}, 0, 0},
 		{&WildCardField{}, 0, 0},
+		{&WithClause{CTEs: []*CommonTableExpression{{Query: &SubqueryExpr{Query: &SelectStmt{Where: ce}}}}}, 1, 1},
 
 		// TODO: cover childrens
 		{&InsertStmt{Table: tableRefsClause}, 1, 1},
+		{&UpdateStmt{TableRefs: tableRefsClause}, 1, 1},
 		{&SelectStmt{}, 0, 0},
+		{&SetOprStmt{}, 0, 0},
+		{&SetOprSelectList{}, 0, 0},
 		{&FieldList{}, 0, 0},
 	}
 
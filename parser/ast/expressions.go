@@ -25,17 +25,25 @@ import (
 var (
 	_ ExprNode = &BetweenExpr{}
 	_ ExprNode = &BinaryOperationExpr{}
+	_ ExprNode = &CaseExpr{}
 	_ ExprNode = &ColumnNameExpr{}
+	_ ExprNode = &CompareSubqueryExpr{}
 	_ ExprNode = &DefaultExpr{}
+	_ ExprNode = &ExistsSubqueryExpr{}
 	_ ExprNode = &IsNullExpr{}
 	_ ExprNode = &ParenthesesExpr{}
 	_ ExprNode = &PatternInExpr{}
+	_ ExprNode = &PatternLikeExpr{}
+	_ ExprNode = &PatternRegexpExpr{}
 	_ ExprNode = &RowExpr{}
+	_ ExprNode = &SubqueryExpr{}
+	_ ExprNode = &TimeUnitExpr{}
 	_ ExprNode = &UnaryOperationExpr{}
 	_ ExprNode = &ValuesExpr{}
 	_ ExprNode = &VariableExpr{}
 
 	_ Node = &ColumnName{}
+	_ Node = &WhenClause{}
 )
 
 // ValueExpr define a interface for ValueExpr.
@@ -52,6 +60,16 @@ type ValueExpr interface {
 // NewValueExpr creates a ValueExpr with value, and sets default field type.
 var NewValueExpr func(interface{}) ValueExpr
 
+// ParamMarkerExpr expression holds a place for another expression.
+// Used in parsing prepare statement.
+type ParamMarkerExpr interface {
+	ValueExpr
+	SetOrder(int)
+}
+
+// NewParamMarkerExpr creates a ParamMarkerExpr.
+var NewParamMarkerExpr func(offset int) ParamMarkerExpr
+
 // BetweenExpr is for "between and" or "not between and" expression.
 type BetweenExpr struct {
 	exprNode
@@ -150,6 +168,102 @@ func (n *BinaryOperationExpr) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// WhenClause is the when clause in Case expression for "when condition then result".
+type WhenClause struct {
+	node
+	// Expr is the condition expression in WhenClause.
+	Expr ExprNode
+	// Result is the result expression in WhenClause.
+	Result ExprNode
+}
+
+// Accept implements Node Accept interface.
+func (n *WhenClause) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+
+	n = newNode.(*WhenClause)
+	node, ok := n.Expr.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Expr = node.(ExprNode)
+
+	node, ok = n.Result.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Result = node.(ExprNode)
+
+	return v.Leave(n)
+}
+
+// CaseExpr is the case expression.
+type CaseExpr struct {
+	exprNode
+	// Value is the compare value expression.
+	Value ExprNode
+	// WhenClauses is the condition check expression.
+	WhenClauses []*WhenClause
+	// ElseClause is the else result expression.
+	ElseClause ExprNode
+}
+
+// Format the ExprNode into a Writer.
+func (n *CaseExpr) Format(w io.Writer) {
+	fmt.Fprint(w, "CASE")
+	// Value is nil for the searched case expression "CASE WHEN ...".
+	if n.Value != nil {
+		fmt.Fprint(w, " ")
+		n.Value.Format(w)
+	}
+	for _, clause := range n.WhenClauses {
+		fmt.Fprint(w, " WHEN ")
+		clause.Expr.Format(w)
+		fmt.Fprint(w, " THEN ")
+		clause.Result.Format(w)
+	}
+	if n.ElseClause != nil {
+		fmt.Fprint(w, " ELSE ")
+		n.ElseClause.Format(w)
+	}
+	fmt.Fprint(w, " END")
+}
+
+// Accept implements Node Accept interface.
+func (n *CaseExpr) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+
+	n = newNode.(*CaseExpr)
+	if n.Value != nil {
+		node, ok := n.Value.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Value = node.(ExprNode)
+	}
+	for i, val := range n.WhenClauses {
+		node, ok := val.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.WhenClauses[i] = node.(*WhenClause)
+	}
+	if n.ElseClause != nil {
+		node, ok := n.ElseClause.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.ElseClause = node.(ExprNode)
+	}
+	return v.Leave(n)
+}
+
 // ColumnName represents column name.
 type ColumnName struct {
 	node
@@ -227,6 +341,47 @@ func (n *ColumnNameExpr) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// CompareSubqueryExpr is the expression for "expr cmp (select ...)".
+// See https://dev.mysql.com/doc/refman/5.7/en/comparisons-using-subqueries.html
+// See https://dev.mysql.com/doc/refman/5.7/en/any-in-some-subqueries.html
+// See https://dev.mysql.com/doc/refman/5.7/en/all-subqueries.html
+type CompareSubqueryExpr struct {
+	exprNode
+	// L is the left expression
+	L ExprNode
+	// Op is the comparison opcode.
+	Op opcode.Op
+	// R is the subquery for right expression, may be rewritten to other type of expression.
+	R ExprNode
+	// All is true, we should compare all records in subquery.
+	All bool
+}
+
+// Format the ExprNode into a Writer.
+func (n *CompareSubqueryExpr) Format(w io.Writer) {
+	panic("Not implemented")
+}
+
+// Accept implements Node Accept interface.
+func (n *CompareSubqueryExpr) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*CompareSubqueryExpr)
+	node, ok := n.L.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.L = node.(ExprNode)
+	node, ok = n.R.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.R = node.(ExprNode)
+	return v.Leave(n)
+}
+
 // DefaultExpr is the default expression using default value for a column.
 type DefaultExpr struct {
 	exprNode
@@ -256,6 +411,36 @@ func (n *DefaultExpr) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// ExistsSubqueryExpr is the expression for "exists (select ...)".
+// See https://dev.mysql.com/doc/refman/5.7/en/exists-and-not-exists-subqueries.html
+type ExistsSubqueryExpr struct {
+	exprNode
+	// Sel is the subquery, may be rewritten to other type of expression.
+	Sel ExprNode
+	// Not is true, the expression is "not exists".
+	Not bool
+}
+
+// Format the ExprNode into a Writer.
+func (n *ExistsSubqueryExpr) Format(w io.Writer) {
+	panic("Not implemented")
+}
+
+// Accept implements Node Accept interface.
+func (n *ExistsSubqueryExpr) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*ExistsSubqueryExpr)
+	node, ok := n.Sel.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Sel = node.(ExprNode)
+	return v.Leave(n)
+}
+
 // PatternInExpr is the expression for in operator, like "expr in (1, 2, 3)" or "expr in (select c from t)".
 type PatternInExpr struct {
 	exprNode
@@ -265,6 +450,8 @@ type PatternInExpr struct {
 	List []ExprNode
 	// Not is true, the expression is "not in".
 	Not bool
+	// Sel is the subquery, may be rewritten to other type of expression.
+	Sel ExprNode
 }
 
 // Format the ExprNode into a Writer.
@@ -303,6 +490,102 @@ func (n *PatternInExpr) Accept(v Visitor) (Node, bool) {
 		}
 		n.List[i] = node.(ExprNode)
 	}
+	if n.Sel != nil {
+		node, ok = n.Sel.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Sel = node.(ExprNode)
+	}
+	return v.Leave(n)
+}
+
+// PatternLikeExpr is the expression for like operator, e.g, expr like "%123%"
+type PatternLikeExpr struct {
+	exprNode
+	// Expr is the expression to be checked.
+	Expr ExprNode
+	// Pattern is the like expression.
+	Pattern ExprNode
+	// Not is true, the expression is "not like".
+	Not bool
+	// Escape is the escape character of the pattern, it is '\\' by default.
+	Escape byte
+}
+
+// Format the ExprNode into a Writer.
+func (n *PatternLikeExpr) Format(w io.Writer) {
+	n.Expr.Format(w)
+	if n.Not {
+		fmt.Fprint(w, " NOT LIKE ")
+	} else {
+		fmt.Fprint(w, " LIKE ")
+	}
+	n.Pattern.Format(w)
+	if n.Escape != '\\' {
+		fmt.Fprintf(w, " ESCAPE '%c'", n.Escape)
+	}
+}
+
+// Accept implements Node Accept interface.
+func (n *PatternLikeExpr) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*PatternLikeExpr)
+	node, ok := n.Expr.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Expr = node.(ExprNode)
+	node, ok = n.Pattern.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Pattern = node.(ExprNode)
+	return v.Leave(n)
+}
+
+// PatternRegexpExpr is the pattern expression for pattern match, e.g, expr regexp "^a.*".
+type PatternRegexpExpr struct {
+	exprNode
+	// Expr is the expression to be checked.
+	Expr ExprNode
+	// Pattern is the expression for pattern.
+	Pattern ExprNode
+	// Not is true, the expression is "not regexp".
+	Not bool
+}
+
+// Format the ExprNode into a Writer.
+func (n *PatternRegexpExpr) Format(w io.Writer) {
+	n.Expr.Format(w)
+	if n.Not {
+		fmt.Fprint(w, " NOT REGEXP ")
+	} else {
+		fmt.Fprint(w, " REGEXP ")
+	}
+	n.Pattern.Format(w)
+}
+
+// Accept implements Node Accept interface.
+func (n *PatternRegexpExpr) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*PatternRegexpExpr)
+	node, ok := n.Expr.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Expr = node.(ExprNode)
+	node, ok = n.Pattern.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Pattern = node.(ExprNode)
 	return v.Leave(n)
 }
 
@@ -401,6 +684,59 @@ func (n *RowExpr) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// SubqueryExpr represents a subquery.
+type SubqueryExpr struct {
+	exprNode
+	// Query is the query SelectNode.
+	Query      ResultSetNode
+	Evaluated  bool
+	Correlated bool
+	MultiRows  bool
+	Exists     bool
+}
+
+// Format the ExprNode into a Writer.
+func (n *SubqueryExpr) Format(w io.Writer) {
+	panic("Not implemented")
+}
+
+// Accept implements Node Accept interface.
+func (n *SubqueryExpr) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*SubqueryExpr)
+	node, ok := n.Query.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Query = node.(ResultSetNode)
+	return v.Leave(n)
+}
+
+// TimeUnitExpr is an expression representing a time or timestamp unit.
+type TimeUnitExpr struct {
+	exprNode
+	// Unit is the time or timestamp unit.
+	Unit TimeUnitType
+}
+
+// Format the ExprNode into a Writer.
+func (n *TimeUnitExpr) Format(w io.Writer) {
+	fmt.Fprint(w, n.Unit.String())
+}
+
+// Accept implements Node Accept interface.
+func (n *TimeUnitExpr) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*TimeUnitExpr)
+	return v.Leave(n)
+}
+
 // UnaryOperationExpr is the expression for unary operator.
 type UnaryOperationExpr struct {
 	exprNode
@@ -72,11 +72,20 @@ func (tc *testExpressionsSuite) TestExpresionsVisitorCover(c *C) {
 		}{
 			{&BetweenExpr{Expr: ce, Left: ce, Right: ce}, 3, 3},
 			{&BinaryOperationExpr{L: ce, R: ce}, 2, 2},
+			{&CaseExpr{Value: ce, WhenClauses: []*WhenClause{{Expr: ce, Result: ce},
+				{Expr: ce, Result: ce}}, ElseClause: ce}, 6, 6},
 			{&ColumnNameExpr{Name: &ColumnName{}}, 0, 0},
+			{&CompareSubqueryExpr{L: ce, R: ce}, 2, 2},
 			{&DefaultExpr{Name: &ColumnName{}}, 0, 0},
+			{&ExistsSubqueryExpr{Sel: ce}, 1, 1},
 			{&IsNullExpr{Expr: ce}, 1, 1},
 			{&ParenthesesExpr{Expr: ce}, 1, 1},
+			{&PatternInExpr{Expr: ce, Sel: ce}, 2, 2},
+			{&PatternLikeExpr{Expr: ce, Pattern: ce}, 2, 2},
+			{&PatternRegexpExpr{Expr: ce, Pattern: ce}, 2, 2},
 			{&RowExpr{Values: []ExprNode{ce, ce}}, 2, 2},
+			{&SubqueryExpr{Query: &SelectStmt{}}, 0, 0},
+			{&TimeUnitExpr{Unit: TimeUnitDay}, 0, 0},
 			{&UnaryOperationExpr{V: ce}, 1, 1},
 			{NewValueExpr(0), 0, 0},
 			{&ValuesExpr{Column: &ColumnNameExpr{Name: &ColumnName{}}}, 0, 0},
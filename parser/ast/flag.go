@@ -18,6 +18,11 @@ func HasAggFlag(expr ExprNode) bool {
 	return expr.GetFlag()&FlagHasAggregateFunc > 0
 }
 
+// HasWindowFlag checks if the expr contains FlagHasWindowFunc.
+func HasWindowFlag(expr ExprNode) bool {
+	return expr.GetFlag()&FlagHasWindowFunc > 0
+}
+
 // SetFlag sets flag for expression.
 func SetFlag(n Node) {
 	var setter flagSetter
@@ -41,8 +46,12 @@ func (f *flagSetter) Leave(in Node) (Node, bool) {
 		x.SetFlag(x.L.GetFlag() | x.R.GetFlag())
 	case *ColumnNameExpr:
 		x.SetFlag(FlagHasReference)
+	case *CompareSubqueryExpr:
+		x.SetFlag(x.L.GetFlag() | x.R.GetFlag())
 	case *DefaultExpr:
 		x.SetFlag(FlagHasDefault)
+	case *ExistsSubqueryExpr:
+		x.SetFlag(x.Sel.GetFlag())
 	case *FuncCallExpr:
 		f.funcCall(x)
 	case *IsNullExpr:
@@ -53,6 +62,8 @@ func (f *flagSetter) Leave(in Node) (Node, bool) {
 		f.patternIn(x)
 	case *RowExpr:
 		f.row(x)
+	case *SubqueryExpr:
+		x.SetFlag(FlagHasSubquery)
 	case *UnaryOperationExpr:
 		x.SetFlag(x.V.GetFlag())
 	case *ValuesExpr:
@@ -63,6 +74,8 @@ func (f *flagSetter) Leave(in Node) (Node, bool) {
 		} else {
 			x.SetFlag(FlagHasVariable | x.Value.GetFlag())
 		}
+	case *WindowFuncExpr:
+		f.windowFunc(x)
 	}
 
 	return in, true
@@ -73,6 +86,9 @@ func (f *flagSetter) patternIn(x *PatternInExpr) {
 	for _, val := range x.List {
 		flag |= val.GetFlag()
 	}
+	if x.Sel != nil {
+		flag |= x.Sel.GetFlag()
+	}
 	x.SetFlag(flag)
 }
 
@@ -99,3 +115,11 @@ func (f *flagSetter) aggregateFunc(x *AggregateFuncExpr) {
 	}
 	x.SetFlag(flag)
 }
+
+func (f *flagSetter) windowFunc(x *WindowFuncExpr) {
+	flag := FlagHasWindowFunc
+	for _, val := range x.Args {
+		flag |= val.GetFlag()
+	}
+	x.SetFlag(flag)
+}
@@ -51,3 +51,12 @@ func (ts *testFlagSuite) TestHasAggFlag(c *C) {
 		c.Assert(ast.HasAggFlag(expr), Equals, tt.hasAgg)
 	}
 }
+
+func (ts *testFlagSuite) TestHasWindowFlag(c *C) {
+	stmt, err := ts.ParseOneStmt("select a, sum(a) over (order by b) + 1 from t", "", "")
+	c.Assert(err, IsNil)
+	fields := stmt.(*ast.SelectStmt).Fields.Fields
+	c.Assert(ast.HasWindowFlag(fields[0].Expr), IsFalse)
+	c.Assert(ast.HasWindowFlag(fields[1].Expr), IsTrue)
+	c.Assert(ast.HasAggFlag(fields[1].Expr), IsFalse)
+}
@@ -18,11 +18,13 @@ import (
 	"io"
 
 	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/types"
 )
 
 var (
 	_ FuncNode = &AggregateFuncExpr{}
 	_ FuncNode = &FuncCallExpr{}
+	_ FuncNode = &FuncCastExpr{}
 )
 
 // List scalar function names.
@@ -33,6 +35,8 @@ const (
 	OctetLength = "octet_length"
 	If          = "if"
 	Ifnull      = "ifnull"
+	Nullif      = "nullif"
+	Case        = "case"
 	LogicAnd    = "and"
 	LogicOr     = "or"
 	GE          = "ge"
@@ -41,17 +45,119 @@ const (
 	NE          = "ne"
 	LT          = "lt"
 	GT          = "gt"
+	Coalesce    = "coalesce"
+	Greatest    = "greatest"
+	Least       = "least"
 	Plus        = "plus"
 	Minus       = "minus"
 	Div         = "div"
 	Mul         = "mul"
+	Mod         = "mod"
+	IntDiv      = "intdiv"
 	UnaryNot    = "not"
 	UnaryMinus  = "unaryminus"
 	In          = "in"
+	Like        = "like"
+	Regexp      = "regexp"
 	RowFunc     = "row"
 	SetVar      = "setvar"
 	GetVar      = "getvar"
 	Values      = "values"
+	Cast        = "cast"
+
+	// string functions
+	Concat    = "concat"
+	ConcatWS  = "concat_ws"
+	Elt       = "elt"
+	Field     = "field"
+	Hex       = "hex"
+	Instr     = "instr"
+	Lcase     = "lcase"
+	Left      = "left"
+	Locate    = "locate"
+	Lower     = "lower"
+	Lpad      = "lpad"
+	LTrim     = "ltrim"
+	Mid       = "mid"
+	Position  = "position"
+	Repeat    = "repeat"
+	Replace   = "replace"
+	Reverse   = "reverse"
+	Right     = "right"
+	Rpad      = "rpad"
+	RTrim     = "rtrim"
+	Substr    = "substr"
+	Substring = "substring"
+	Trim      = "trim"
+	Ucase     = "ucase"
+	Unhex     = "unhex"
+	Upper     = "upper"
+
+	// math functions
+	Abs      = "abs"
+	Acos     = "acos"
+	Asin     = "asin"
+	Atan     = "atan"
+	Atan2    = "atan2"
+	Ceil     = "ceil"
+	Ceiling  = "ceiling"
+	Cos      = "cos"
+	Cot      = "cot"
+	Degrees  = "degrees"
+	Exp      = "exp"
+	Floor    = "floor"
+	Ln       = "ln"
+	Log      = "log"
+	Log2     = "log2"
+	Log10    = "log10"
+	PI       = "pi"
+	Pow      = "pow"
+	Power    = "power"
+	Radians  = "radians"
+	Rand     = "rand"
+	Round    = "round"
+	Sign     = "sign"
+	Sin      = "sin"
+	Sqrt     = "sqrt"
+	Tan      = "tan"
+	Truncate = "truncate"
+
+	// time functions
+	AddDate          = "adddate"
+	Curdate          = "curdate"
+	CurrentDate      = "current_date"
+	CurrentTime      = "current_time"
+	CurrentTimestamp = "current_timestamp"
+	Curtime          = "curtime"
+	DateAdd          = "date_add"
+	DateDiff         = "datediff"
+	DateFormat       = "date_format"
+	DateSub          = "date_sub"
+	Day              = "day"
+	DayOfMonth       = "dayofmonth"
+	Extract          = "extract"
+	FromUnixTime     = "from_unixtime"
+	LocalTime        = "localtime"
+	LocalTimestamp   = "localtimestamp"
+	Month            = "month"
+	Now              = "now"
+	StrToDate        = "str_to_date"
+	SubDate          = "subdate"
+	Sysdate          = "sysdate"
+	TimestampDiff    = "timestampdiff"
+	UnixTimestamp    = "unix_timestamp"
+	UTCDate          = "utc_date"
+	UTCTime          = "utc_time"
+	UTCTimestamp     = "utc_timestamp"
+	Year             = "year"
+
+	// json functions
+	JSONExtract  = "json_extract"
+	JSONUnquote  = "json_unquote"
+	JSONSet      = "json_set"
+	JSONObject   = "json_object"
+	JSONArray    = "json_array"
+	JSONContains = "json_contains"
 )
 
 // FuncCallExpr is for function expression.
@@ -92,6 +198,114 @@ func (n *FuncCallExpr) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// FuncCastExpr is the cast function converting value to another type, e.g, cast(expr AS signed).
+// See https://dev.mysql.com/doc/refman/5.7/en/cast-functions.html
+type FuncCastExpr struct {
+	funcNode
+	// Expr is the expression to be converted.
+	Expr ExprNode
+	// Tp is the conversion type.
+	Tp *types.FieldType
+}
+
+// Format the ExprNode into a Writer.
+func (n *FuncCastExpr) Format(w io.Writer) {
+	fmt.Fprint(w, "CAST(")
+	n.Expr.Format(w)
+	fmt.Fprintf(w, " AS %s)", n.Tp.String())
+}
+
+// Accept implements Node Accept interface.
+func (n *FuncCastExpr) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*FuncCastExpr)
+	node, ok := n.Expr.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Expr = node.(ExprNode)
+	return v.Leave(n)
+}
+
+// TimeUnitType is the type for time and timestamp units.
+type TimeUnitType int
+
+const (
+	// TimeUnitInvalid is a placeholder for an invalid time or timestamp unit
+	TimeUnitInvalid TimeUnitType = iota
+	// TimeUnitMicrosecond is the time or timestamp unit MICROSECOND.
+	TimeUnitMicrosecond
+	// TimeUnitSecond is the time or timestamp unit SECOND.
+	TimeUnitSecond
+	// TimeUnitMinute is the time or timestamp unit MINUTE.
+	TimeUnitMinute
+	// TimeUnitHour is the time or timestamp unit HOUR.
+	TimeUnitHour
+	// TimeUnitDay is the time or timestamp unit DAY.
+	TimeUnitDay
+	// TimeUnitWeek is the time or timestamp unit WEEK.
+	TimeUnitWeek
+	// TimeUnitMonth is the time or timestamp unit MONTH.
+	TimeUnitMonth
+	// TimeUnitQuarter is the time or timestamp unit QUARTER.
+	TimeUnitQuarter
+	// TimeUnitYear is the time or timestamp unit YEAR.
+	TimeUnitYear
+	// TimeUnitSecondMicrosecond is the time unit SECOND_MICROSECOND.
+	TimeUnitSecondMicrosecond
+	// TimeUnitMinuteMicrosecond is the time unit MINUTE_MICROSECOND.
+	TimeUnitMinuteMicrosecond
+	// TimeUnitMinuteSecond is the time unit MINUTE_SECOND.
+	TimeUnitMinuteSecond
+	// TimeUnitHourMicrosecond is the time unit HOUR_MICROSECOND.
+	TimeUnitHourMicrosecond
+	// TimeUnitHourSecond is the time unit HOUR_SECOND.
+	TimeUnitHourSecond
+	// TimeUnitHourMinute is the time unit HOUR_MINUTE.
+	TimeUnitHourMinute
+	// TimeUnitDayMicrosecond is the time unit DAY_MICROSECOND.
+	TimeUnitDayMicrosecond
+	// TimeUnitDaySecond is the time unit DAY_SECOND.
+	TimeUnitDaySecond
+	// TimeUnitDayMinute is the time unit DAY_MINUTE.
+	TimeUnitDayMinute
+	// TimeUnitDayHour is the time unit DAY_HOUR.
+	TimeUnitDayHour
+	// TimeUnitYearMonth is the time unit YEAR_MONTH.
+	TimeUnitYearMonth
+)
+
+var timeUnitNames = map[TimeUnitType]string{
+	TimeUnitMicrosecond:       "MICROSECOND",
+	TimeUnitSecond:            "SECOND",
+	TimeUnitMinute:            "MINUTE",
+	TimeUnitHour:              "HOUR",
+	TimeUnitDay:               "DAY",
+	TimeUnitWeek:              "WEEK",
+	TimeUnitMonth:             "MONTH",
+	TimeUnitQuarter:           "QUARTER",
+	TimeUnitYear:              "YEAR",
+	TimeUnitSecondMicrosecond: "SECOND_MICROSECOND",
+	TimeUnitMinuteMicrosecond: "MINUTE_MICROSECOND",
+	TimeUnitMinuteSecond:      "MINUTE_SECOND",
+	TimeUnitHourMicrosecond:   "HOUR_MICROSECOND",
+	TimeUnitHourSecond:        "HOUR_SECOND",
+	TimeUnitHourMinute:        "HOUR_MINUTE",
+	TimeUnitDayMicrosecond:    "DAY_MICROSECOND",
+	TimeUnitDaySecond:         "DAY_SECOND",
+	TimeUnitDayMinute:         "DAY_MINUTE",
+	TimeUnitDayHour:           "DAY_HOUR",
+	TimeUnitYearMonth:         "YEAR_MONTH",
+}
+
+// String implements fmt.Stringer interface.
+func (unit TimeUnitType) String() string {
+	return timeUnitNames[unit]
+}
+
 const (
 	// AggFuncCount is the name of Count function.
 	AggFuncCount = "count"
@@ -105,6 +319,22 @@ const (
 	AggFuncMax = "max"
 	// AggFuncMin is the name of min function.
 	AggFuncMin = "min"
+	// AggFuncGroupConcat is the name of group_concat function.
+	AggFuncGroupConcat = "group_concat"
+	// AggFuncBitOr is the name of bit_or function.
+	AggFuncBitOr = "bit_or"
+	// AggFuncBitXor is the name of bit_xor function.
+	AggFuncBitXor = "bit_xor"
+	// AggFuncBitAnd is the name of bit_and function.
+	AggFuncBitAnd = "bit_and"
+	// AggFuncVarPop is the name of var_pop function.
+	AggFuncVarPop = "var_pop"
+	// AggFuncVarSamp is the name of var_samp function.
+	AggFuncVarSamp = "var_samp"
+	// AggFuncStddevPop is the name of stddev_pop function.
+	AggFuncStddevPop = "stddev_pop"
+	// AggFuncStddevSamp is the name of stddev_samp function.
+	AggFuncStddevSamp = "stddev_samp"
 )
 
 // AggregateFuncExpr represents aggregate function expression.
@@ -114,6 +344,12 @@ type AggregateFuncExpr struct {
 	F string
 	// Args is the function args.
 	Args []ExprNode
+	// Distinct is true, function hence only aggregate distinct values.
+	// For example, column c1 values are "1", "2", "2",  "sum(c1)" is "5",
+	// but "sum(distinct c1)" is "3".
+	Distinct bool
+	// Order is only used in GROUP_CONCAT.
+	Order *OrderByClause
 }
 
 // Format the ExprNode into a Writer.
@@ -135,5 +371,70 @@ func (n *AggregateFuncExpr) Accept(v Visitor) (Node, bool) {
 		}
 		n.Args[i] = node.(ExprNode)
 	}
+	if n.Order != nil {
+		node, ok := n.Order.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Order = node.(*OrderByClause)
+	}
+	return v.Leave(n)
+}
+
+const (
+	// WindowFuncRowNumber is the name of row_number function.
+	WindowFuncRowNumber = "row_number"
+	// WindowFuncRank is the name of rank function.
+	WindowFuncRank = "rank"
+	// WindowFuncDenseRank is the name of dense_rank function.
+	WindowFuncDenseRank = "dense_rank"
+	// WindowFuncFirstValue is the name of first_value function.
+	WindowFuncFirstValue = "first_value"
+	// WindowFuncLead is the name of lead function.
+	WindowFuncLead = "lead"
+	// WindowFuncLag is the name of lag function.
+	WindowFuncLag = "lag"
+)
+
+// WindowFuncExpr represents window function expression.
+// Aggregate functions followed by an OVER clause are also represented by it.
+type WindowFuncExpr struct {
+	funcNode
+
+	// F is the function name.
+	F string
+	// Args is the function args.
+	Args []ExprNode
+	// Distinct cannot be true for most window functions, except `max` and `min`.
+	// We need to raise error if it is not allowed to be true.
+	Distinct bool
+	// Spec is the specification of this window.
+	Spec WindowSpec
+}
+
+// Format the ExprNode into a Writer.
+func (n *WindowFuncExpr) Format(w io.Writer) {
+	panic("Not implemented")
+}
+
+// Accept implements Node Accept interface.
+func (n *WindowFuncExpr) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*WindowFuncExpr)
+	for i, val := range n.Args {
+		node, ok := val.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Args[i] = node.(ExprNode)
+	}
+	node, ok := n.Spec.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Spec = *node.(*WindowSpec)
 	return v.Leave(n)
 }
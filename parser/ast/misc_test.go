@@ -57,3 +57,16 @@ func (ts *testMiscSuite) TestSensitiveStatement(c *C) {
 		c.Assert(ok, IsFalse)
 	}
 }
+
+func (ts *testMiscSuite) TestMiscVisitorCover(c *C) {
+	valueExpr := NewValueExpr(42)
+	stmts := []Node{
+		&DeallocateStmt{},
+		&ExecuteStmt{UsingVars: []ExprNode{valueExpr}},
+		&PrepareStmt{SQLVar: &VariableExpr{Value: valueExpr}},
+	}
+	for _, v := range stmts {
+		v.Accept(visitor{})
+		v.Accept(visitor1{})
+	}
+}
@@ -133,6 +133,7 @@ func init() {
 	initTokenByte('=', eq)
 	initTokenByte('{', int('{'))
 	initTokenByte('}', int('}'))
+	initTokenByte('?', paramMarker)
 
 	initTokenString("||", pipes)
 	initTokenString("&&", andand)
@@ -273,6 +274,7 @@ var tokenMap = map[string]int{
 	"DELAY_KEY_WRITE":          delayKeyWrite,
 	"DELAYED":                  delayed,
 	"DELETE":                   deleteKwd,
+	"DENSE_RANK":               denseRank,
 	"DEPTH":                    depth,
 	"DESC":                     desc,
 	"DESCRIBE":                 describe,
@@ -320,6 +322,7 @@ var tokenMap = map[string]int{
 	"FAULTS":                   faultsSym,
 	"FIELDS":                   fields,
 	"FIRST":                    first,
+	"FIRST_VALUE":              firstValue,
 	"FIXED":                    fixed,
 	"FLOAT":                    floatType,
 	"FLUSH":                    flush,
@@ -379,6 +382,7 @@ var tokenMap = map[string]int{
 	"IO":                       io,
 	"IPC":                      ipc,
 	"INTEGER":                  integerType,
+	"INTERSECT":                intersect,
 	"INTERVAL":                 interval,
 	"INTERNAL":                 internal,
 	"INTO":                     into,
@@ -396,8 +400,10 @@ var tokenMap = map[string]int{
 	"KEYS":                     keys,
 	"KILL":                     kill,
 	"LABELS":                   labels,
+	"LAG":                      lag,
 	"LANGUAGE":                 language,
 	"LAST":                     last,
+	"LEAD":                     lead,
 	"LEADING":                  leading,
 	"LEFT":                     left,
 	"LESS":                     less,
@@ -484,6 +490,7 @@ var tokenMap = map[string]int{
 	"OR":                       or,
 	"ORDER":                    order,
 	"OUTER":                    outer,
+	"OVER":                     over,
 	"PACK_KEYS":                packKeys,
 	"PAGE":                     pageSym,
 	"PARSER":                   parser,
@@ -517,6 +524,7 @@ var tokenMap = map[string]int{
 	"SHARD_ROW_ID_BITS":        shardRowIDBits,
 	"PRE_SPLIT_REGIONS":        preSplitRegions,
 	"RANGE":                    rangeKwd,
+	"RANK":                     rank,
 	"RECOVER":                  recover,
 	"REBUILD":                  rebuild,
 	"READ":                     read,
@@ -524,6 +532,7 @@ var tokenMap = map[string]int{
 	"READ_FROM_STORAGE":        hintReadFromStorage,
 	"REAL":                     realType,
 	"RECENT":                   recent,
+	"RECURSIVE":                recursive,
 	"REDUNDANT":                redundant,
 	"REFERENCES":               references,
 	"REGEXP":                   regexpKwd,
@@ -550,6 +559,8 @@ var tokenMap = map[string]int{
 	"ROLLBACK":                 rollback,
 	"ROUTINE":                  routine,
 	"ROW":                      row,
+	"ROW_NUMBER":               rowNumber,
+	"ROWS":                     rows,
 	"ROW_COUNT":                rowCount,
 	"ROW_FORMAT":               rowFormat,
 	"RTREE":                    rtree,
@@ -720,6 +731,7 @@ var tokenMap = map[string]int{
 	"WHEN":                     when,
 	"WHERE":                    where,
 	"WIDTH":                    width,
+	"WINDOW":                   window,
 	"WITH":                     with,
 	"WITHOUT":                  without,
 	"WRITE":                    write,
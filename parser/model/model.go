@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/types"
 	"github.com/pingcap/tipb/go-tipb"
@@ -85,6 +86,7 @@ type ColumnInfo struct {
 	Offset             int         `json:"offset"`
 	OriginDefaultValue interface{} `json:"origin_default"`
 	DefaultValue       interface{} `json:"default"`
+	DefaultValueBit    []byte      `json:"default_bit"`
 	types.FieldType    `json:"type"`
 	State              SchemaState `json:"state"`
 	Comment            string      `json:"comment"`
@@ -107,11 +109,30 @@ func (c *ColumnInfo) Clone() *ColumnInfo {
 // SetDefaultValue sets the default value.
 func (c *ColumnInfo) SetDefaultValue(value interface{}) error {
 	c.DefaultValue = value
+	if c.Tp == mysql.TypeBit {
+		// For mysql.TypeBit type, the default value storage format must be a string.
+		// Other value such as int must convert to string format first.
+		// The mysql.TypeBit type supports the null default value.
+		if value == nil {
+			c.DefaultValueBit = nil
+			return nil
+		}
+		if v, ok := value.(string); ok {
+			c.DefaultValueBit = []byte(v)
+			return nil
+		}
+		return types.ErrInvalidDefault.GenWithStackByArgs(c.Name)
+	}
 	return nil
 }
 
 // GetDefaultValue gets the default value of the column.
+// Default value use to stored in DefaultValue field, but now,
+// bit type default value will store in DefaultValueBit for fix bit default value decode/encode bug.
 func (c *ColumnInfo) GetDefaultValue() interface{} {
+	if c.Tp == mysql.TypeBit && c.DefaultValueBit != nil {
+		return string(c.DefaultValueBit)
+	}
 	return c.DefaultValue
 }
 
@@ -224,6 +245,9 @@ type TableInfo struct {
 
 	// TiFlashReplica means the TiFlash replica info.
 	TiFlashReplica *TiFlashReplicaInfo `json:"tiflash_replica"`
+
+	// View is not nil if the table is a view.
+	View *ViewInfo `json:"view"`
 }
 
 // TableLockInfo provides meta data describing a table lock.
@@ -436,6 +460,88 @@ func (t *TableInfo) IsLocked() bool {
 	return t.Lock != nil && len(t.Lock.Sessions) > 0
 }
 
+// IsView checks if TableInfo is a view.
+func (t *TableInfo) IsView() bool {
+	return t.View != nil
+}
+
+// ViewAlgorithm is VIEW's SQL ALGORITHM characteristic.
+// See https://dev.mysql.com/doc/refman/5.7/en/view-algorithms.html
+type ViewAlgorithm int
+
+// ViewAlgorithm values.
+const (
+	AlgorithmUndefined ViewAlgorithm = iota
+	AlgorithmMerge
+	AlgorithmTemptable
+)
+
+// String implements fmt.Stringer interface.
+func (v ViewAlgorithm) String() string {
+	switch v {
+	case AlgorithmMerge:
+		return "MERGE"
+	case AlgorithmTemptable:
+		return "TEMPTABLE"
+	default:
+		return "UNDEFINED"
+	}
+}
+
+// ViewSecurity is VIEW's SQL SECURITY characteristic.
+// See https://dev.mysql.com/doc/refman/5.7/en/create-view.html
+type ViewSecurity int
+
+// ViewSecurity values.
+const (
+	SecurityDefiner ViewSecurity = iota
+	SecurityInvoker
+)
+
+// String implements fmt.Stringer interface.
+func (v ViewSecurity) String() string {
+	switch v {
+	case SecurityInvoker:
+		return "INVOKER"
+	default:
+		return "DEFINER"
+	}
+}
+
+// ViewCheckOption is VIEW's WITH CHECK OPTION clause part.
+// See https://dev.mysql.com/doc/refman/5.7/en/view-check-option.html
+type ViewCheckOption int
+
+// ViewCheckOption values.
+const (
+	CheckOptionLocal ViewCheckOption = iota
+	CheckOptionCascaded
+	// CheckOptionNone means the view has no WITH CHECK OPTION clause.
+	CheckOptionNone
+)
+
+// String implements fmt.Stringer interface.
+func (v ViewCheckOption) String() string {
+	switch v {
+	case CheckOptionLocal:
+		return "LOCAL"
+	case CheckOptionNone:
+		return "NONE"
+	default:
+		return "CASCADED"
+	}
+}
+
+// ViewInfo provides meta data describing a DB view.
+type ViewInfo struct {
+	Algorithm   ViewAlgorithm      `json:"view_algorithm"`
+	Definer     *auth.UserIdentity `json:"view_definer"`
+	Security    ViewSecurity       `json:"view_security"`
+	SelectStmt  string             `json:"view_select"`
+	CheckOption ViewCheckOption    `json:"view_checkoption"`
+	Cols        []CIStr            `json:"view_cols"`
+}
+
 // NewExtraHandleColInfo mocks a column info for extra handle column.
 func NewExtraHandleColInfo() *ColumnInfo {
 	colInfo := &ColumnInfo{
@@ -235,6 +235,21 @@ func (*testModelSuite) TestJobCodec(c *C) {
 	c.Assert(job.GetRowCount(), Equals, int64(3))
 }
 
+func (testModelSuite) TestDefaultValue(c *C) {
+	col := &ColumnInfo{FieldType: *types.NewFieldType(mysql.TypeBit)}
+	c.Assert(col.SetDefaultValue("\x00\xff"), IsNil)
+	c.Assert(col.GetDefaultValue(), Equals, "\x00\xff")
+	c.Assert(col.DefaultValueBit, DeepEquals, []byte{0, 0xff})
+	c.Assert(col.SetDefaultValue(nil), IsNil)
+	c.Assert(col.GetDefaultValue(), IsNil)
+	c.Assert(types.ErrInvalidDefault.Equal(col.SetDefaultValue(1)), IsTrue)
+
+	col = &ColumnInfo{FieldType: *types.NewFieldType(mysql.TypeLong)}
+	c.Assert(col.SetDefaultValue(1), IsNil)
+	c.Assert(col.GetDefaultValue(), Equals, 1)
+	c.Assert(col.DefaultValueBit, IsNil)
+}
+
 func (testModelSuite) TestState(c *C) {
 	schemaTbl := []SchemaState{
 		StateDeleteOnly,
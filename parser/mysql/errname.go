@@ -896,6 +896,7 @@ var MySQLErrName = map[uint16]string{
 	ErrInvalidJSONPathWildcard:                               "In this situation, path expressions may not contain the * and ** tokens.",
 	ErrInvalidJSONContainsPathType:                           "The second argument can only be either 'one' or 'all'.",
 	ErrJSONUsedAsKey:                                         "JSON column '%-.192s' cannot be used in key specification.",
+	ErrJSONDocumentNULLKey:                                   "JSON documents may not contain NULL member names.",
 	ErrBadUser:                                               "User %s does not exist.",
 	ErrUserAlreadyExists:                                     "User %s already exists.",
 	ErrInvalidJSONPathArrayCell:                              "A path expression is not a path to a cell in an array.",
@@ -924,6 +925,11 @@ var MySQLErrName = map[uint16]string{
 	ErrRoleNotGranted:                                        "%s is is not granted to %s",
 	ErrMaxExecTimeExceeded:                                   "Query execution was interrupted, max_execution_time exceeded.",
 	ErrLockAcquireFailAndNoWaitSet:                           "Statement aborted because lock(s) could not be acquired immediately and NOWAIT is set.",
+	ErrCTERecursiveRequiresUnion:                             "Recursive Common Table Expression '%s' should contain a UNION",
+	ErrCTERecursiveRequiresNonRecursiveFirst:                 "Recursive Common Table Expression '%s' should have one or more non-recursive query blocks followed by one or more recursive ones",
+	ErrCTERecursiveForbidsAggregation:                        "Recursive Common Table Expression '%s' can contain neither aggregation nor window functions in recursive query block",
+	ErrCTERecursiveRequiresSingleReference:                   "In recursive query block of Recursive Common Table Expression '%s', the recursive table must be referenced only once, and not in any subquery",
+	ErrCTEMaxRecursionDepth:                                  "Recursive query aborted after %d iterations. Try increasing @@cte_max_recursion_depth to a larger value.",
 	ErrDataTruncatedFunctionalIndex:                          "Data truncated for functional index '%s' at row %d",
 	ErrDataOutOfRangeFunctionalIndex:                         "Value is out of range for functional index '%s' at row %d",
 	ErrFunctionalIndexOnJsonOrGeometryFunction:               "Cannot create a functional index on a function that returns a JSON or GEOMETRY value",
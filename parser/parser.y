@@ -30,6 +30,7 @@ import (
 
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/opcode"
 	"github.com/pingcap/tidb/parser/charset"
@@ -104,6 +105,7 @@ import (
 	defaultKwd		"DEFAULT"
 	delayed			"DELAYED"
 	deleteKwd		"DELETE"
+	denseRank		"DENSE_RANK"
 	desc			"DESC"
 	describe		"DESCRIBE"
 	distinct		"DISTINCT"
@@ -119,6 +121,7 @@ import (
 	explain			"EXPLAIN"
 	except			"EXCEPT"
 	falseKwd		"FALSE"
+	firstValue		"FIRST_VALUE"
 	floatType		"FLOAT"
 	forKwd			"FOR"
 	force			"FORCE"
@@ -140,6 +143,7 @@ import (
 	infile			"INFILE"
 	inner 			"INNER"
 	integerType		"INTEGER"
+	intersect		"INTERSECT"
 	interval		"INTERVAL"
 	into			"INTO"
 	is			"IS"
@@ -154,7 +158,9 @@ import (
 	key			"KEY"
 	keys			"KEYS"
 	kill			"KILL"
+	lag			"LAG"
 	language		"LANGUAGE"
+	lead			"LEAD"
 	leading			"LEADING"
 	left			"LEFT"
 	like			"LIKE"
@@ -188,6 +194,7 @@ import (
 	or			"OR"
 	order			"ORDER"
 	outer			"OUTER"
+	over			"OVER"
 	packKeys		"PACK_KEYS"
 	partition		"PARTITION"
 	parser			"PARSER"
@@ -197,8 +204,10 @@ import (
 	shardRowIDBits		"SHARD_ROW_ID_BITS"
 	preSplitRegions		"PRE_SPLIT_REGIONS"
 	rangeKwd		"RANGE"
+	rank			"RANK"
 	read			"READ"
 	realType		"REAL"
+	recursive		"RECURSIVE"
 	references		"REFERENCES"
 	regexpKwd		"REGEXP"
 	rename         		"RENAME"
@@ -210,6 +219,8 @@ import (
 	right			"RIGHT"
 	rlike			"RLIKE"
 	row			"ROW"
+	rowNumber		"ROW_NUMBER"
+	rows			"ROWS"
 	secondMicrosecond	"SECOND_MICROSECOND"
 	selectKwd		"SELECT"
 	set			"SET"
@@ -255,6 +266,7 @@ import (
 	virtual			"VIRTUAL"
 	when			"WHEN"
 	where			"WHERE"
+	window			"WINDOW"
 	write			"WRITE"
 	with			"WITH"
 	xor 			"XOR"
@@ -682,6 +694,7 @@ import (
 	neq		"!="
 	neqSynonym	"<>"
 	nulleq		"<=>"
+	paramMarker	"?"
 	rsh		">>"
 
 %token not2
@@ -695,8 +708,10 @@ import (
 	BitExpr				"bit expression"
 	SimpleExpr			"simple expression"
 	SimpleIdent			"Simple Identifier expression"
+	SubSelect			"Sub Select"
 	SumExpr				"aggregate functions"
 	FunctionCallGeneric		"Function call with Identifier"
+	WindowFuncCall			"WINDOW function call"
 	FunctionCallKeyword		"Function call with keyword as function name"
 	FunctionCallNonKeyword		"Function call with nonkeyword as function name"
 	Literal				"literal value"
@@ -720,21 +735,29 @@ import (
 	CreateTableStmt			"CREATE TABLE statement"
 	CreateDatabaseStmt		"Create Database Statement"
 	CreateIndexStmt			"CREATE INDEX statement"
+	CreateViewStmt			"CREATE VIEW statement"
+	DeallocateStmt			"Deallocate prepared statement"
 	DropDatabaseStmt		"DROP DATABASE statement"
 	DropIndexStmt			"DROP INDEX statement"
 	DropTableStmt			"DROP TABLE statement"
+	DropViewStmt			"DROP VIEW statement"
 	DeleteFromStmt			"DELETE FROM statement"
 	EmptyStmt			"empty statement"
+	ExecuteStmt			"Execute statement"
 	ExplainStmt			"EXPLAIN statement"
 	ExplainableStmt			"explainable statement"
 	InsertIntoStmt			"INSERT INTO statement"
+	PreparedStmt			"PreparedStmt"
 	SelectStmt			"SELECT statement"
+	SelectStmtWithClause		"common table expression SELECT statement"
 	ReplaceIntoStmt			"REPLACE INTO statement"
 	RollbackStmt			"ROLLBACK statement"
+	SetOprStmt			"Set operation statement, e.g. UNION, INTERSECT and EXCEPT"
 	SetStmt				"Set variable statement"
 	ShowStmt			"Show engines/databases/tables/user/columns/warnings/status statement"
 	Statement			"statement"
 	TruncateTableStmt		"TRUNCATE TABLE statement"
+	UpdateStmt			"UPDATE statement"
 	UseStmt				"USE statement"
 
 %type   <item>
@@ -764,7 +787,9 @@ import (
 	ColumnOptionListOpt		"optional column definition option list"
 	Constraint			"table constraint"
 	ConstraintElem			"table constraint element"
+	CommonTableExpr			"Common table expression"
 	ConstraintKeywordOpt		"Constraint Keyword or empty"
+	CreateViewSelectOpt		"Select/Union statement in CREATE VIEW ... AS SELECT"
 	DatabaseOption			"CREATE Database specification"
 	DatabaseOptionList		"CREATE Database specification list"
 	DatabaseOptionListOpt		"CREATE Database specification list opt"
@@ -772,6 +797,7 @@ import (
 	DistinctOpt			"Explicit distinct option"
 	DefaultFalseDistinctOpt		"Distinct option which defaults to false"
 	DefaultTrueDistinctOpt		"Distinct option which defaults to true"
+	ElseOpt				"Optional else clause"
 	EqOpt				"= or empty"
 	EscapedTableRef 		"escaped table reference"
 	ExplainFormatType		"explain format type"
@@ -784,10 +810,14 @@ import (
 	FieldAsNameOpt			"Field alias name opt"
 	FieldList			"field expression list"
 	TableRefsClause			"Table references clause"
+	TimeUnit		"Time unit for 'DATE_ADD', 'DATE_SUB', 'ADDDATE', 'SUBDATE', 'EXTRACT'"
+	TimestampUnit		"Time unit for 'TIMESTAMPDIFF'"
 	FuncDatetimePrec		"Function datetime precision"
 	GlobalScope			"The scope of variable"
 	GroupByClause			"GROUP BY clause"
 	HavingClause			"HAVING clause"
+	IdentList			"Identifier list"
+	IdentListWithParenOpt		"Optional identifier list with parentheses"
 	IfExists			"If Exists"
 	IfNotExists			"If Not Exists"
 	IndexHint			"index hint"
@@ -819,6 +849,7 @@ import (
 	NumLiteral			"Num/Int/Float/Decimal Literal"
 	OptFull				"Full or empty"
 	OptTemporary			"TEMPORARY or empty"
+	OrReplace			"or replace"
 	Order				"ORDER BY clause optional collation specification"
 	OrderBy				"ORDER BY clause"
 	ByItem				"BY item"
@@ -826,6 +857,7 @@ import (
 	ByList				"BY list"
 	QuickOptional			"QUICK or empty"
 	QueryBlockOpt			"Query block identifier optional"
+	PrepareSQL			"Prepare statement sql string"
 	PriorityOpt			"Statement priority option"
 	OptGConcatSeparator		"optional GROUP_CONCAT SEPARATOR"
 	RowValue			"Row value"
@@ -836,12 +868,16 @@ import (
 	SelectStmtSQLSmallResult	"SELECT statement optional SQL_SMALL_RESULT"
 	SelectStmtStraightJoin		"SELECT statement optional STRAIGHT_JOIN"
 	SelectStmtFieldList		"SELECT statement field list"
+	SelectLockOpt			"FOR UPDATE [NOWAIT] or none"
 	SelectStmtLimit			"SELECT statement optional LIMIT clause"
 	SelectStmtOpts			"Select statement options"
 	SelectStmtBasic			"SELECT statement from constant value"
 	SelectStmtFromDualTable			"SELECT statement from dual table"
 	SelectStmtFromTable			"SELECT statement from table"
 	SelectStmtGroup			"SELECT statement optional GROUP BY clause"
+	SetOpr				"Set operator, e.g. UNION, INTERSECT and EXCEPT"
+	SetOprClauseList		"Set operation clause list"
+	SetOprSelect			"SELECT statement in a set operation"
 	ShowTargetFilterable    	"Show target that can be filtered by WHERE or LIKE"
 	ShowDatabaseNameOpt		"Show tables/columns statement database name option"
 	ShowTableAliasOpt       	"Show table alias option"
@@ -863,14 +899,46 @@ import (
 	TableNameListOpt		"Table name list opt"
 	TableRef 			"table reference"
 	TableRefs 			"table references"
+	TransactionChar			"Transaction characteristic"
+	TransactionChars		"Transaction characteristic list"
+	Username			"Username"
+	UserVariableList		"User defined variable name list"
 
 	Values			"values"
 	ValuesList		"values list"
 	ValuesOpt		"values optional"
 	VariableAssignment	"set variable value"
 	VariableAssignmentList	"set variable value list"
+	ViewAlgorithm			"view algorithm"
+	ViewCheckOption			"view check option"
+	ViewDefiner			"view definer"
+	ViewSQLSecurity			"view sql security"
+	WhenClause		"When clause"
+	WhenClauseList		"When clause list"
 	WhereClause		"WHERE clause"
 	WhereClauseOptional	"Optional WHERE clause"
+	WindowClauseOptional	"Optional WINDOW clause"
+	WindowDefinitionList	"WINDOW definition list"
+	WindowDefinition	"WINDOW definition"
+	WindowFrameUnits	"WINDOW frame units"
+	WindowFrameBetween	"WINDOW frame between"
+	WindowFrameBound	"WINDOW frame bound"
+	WindowFrameExtent	"WINDOW frame extent"
+	WindowFrameStart	"WINDOW frame start"
+	WindowName		"WINDOW name"
+	WindowNameOrSpec	"WINDOW name or spec"
+	WindowSpec		"WINDOW spec"
+	WindowSpecDetails	"WINDOW spec details"
+	WindowingClause		"WINDOW clause"
+	OptWindowingClause	"Optional OVER clause"
+	OptExistingWindowName	"Optional existing WINDOW name"
+	OptPartitionClause	"Optional PARTITION BY clause"
+	OptWindowOrderByClause	"Optional ORDER BY clause in WINDOW"
+	OptWindowFrameClause	"Optional FRAME clause in WINDOW"
+	OptLeadLagInfo		"Optional LEAD/LAG info"
+	OptLLDefault		"Optional LEAD/LAG default value"
+	WithClause		"WITH clause"
+	WithList		"WITH list"
 	WithValidation		"with validation"
 	WithValidationOpt	"optional with validation"
 	Type			"Types"
@@ -880,6 +948,8 @@ import (
 	BetweenOrNotOp		"Between predicate"
 	IsOrNotOp		"Is predicate"
 	InOrNotOp		"In predicate"
+	LikeOrNotOp		"Like predicate"
+	RegexpOrNotOp		"Regexp predicate"
 
 	NumericType		"Numeric types"
 	IntegerType		"Integer Types types"
@@ -930,7 +1000,10 @@ import (
 	NowSymFunc		"CURRENT_TIMESTAMP/LOCALTIME/LOCALTIMESTAMP/NOW"
 	DefaultKwdOpt		"optional DEFAULT keyword"
 	DatabaseSym		"DATABASE or SCHEMA"
+	DeallocateSym		"Deallocate or drop"
 	ExplainSym		"EXPLAIN or DESCRIBE or DESC"
+	RegexpSym		"REGEXP or RLIKE"
+	IsolationLevel		"Isolation level"
 	IntoOpt			"INTO or EmptyString"
 	ValueSym		"Value or Values"
 	Char			"{CHAR|CHARACTER}"
@@ -1401,6 +1474,18 @@ BeginTransactionStmt:
 	{
 		$$ = &ast.BeginStmt{}
 	}
+|	"BEGIN" "PESSIMISTIC"
+	{
+		$$ = &ast.BeginStmt{
+			Mode: ast.Pessimistic,
+		}
+	}
+|	"BEGIN" "OPTIMISTIC"
+	{
+		$$ = &ast.BeginStmt{
+			Mode: ast.Optimistic,
+		}
+	}
 |	"START" "TRANSACTION"
 	{
 		$$ = &ast.BeginStmt{}
@@ -2033,6 +2118,133 @@ LikeTableWithOrWithoutParen:
 		$$ = $3
 	}
 
+/*******************************************************************
+ *
+ *  Create View Statement
+ *
+ *  Example:
+ *      CREATE OR REPLACE ALGORITHM = MERGE DEFINER = 'root'@'localhost' SQL SECURITY DEFINER VIEW view_name (col1, col2)
+ *          AS SELECT col1, col2 FROM t WITH LOCAL CHECK OPTION
+ *******************************************************************/
+CreateViewStmt:
+	"CREATE" OrReplace ViewAlgorithm ViewDefiner ViewSQLSecurity "VIEW" TableName IdentListWithParenOpt "AS" CreateViewSelectOpt ViewCheckOption
+	{
+		startOffset := parser.startOffset(&yyS[yypt-1])
+		var endOffset int
+		x := &ast.CreateViewStmt {
+			OrReplace:	$2.(bool),
+			ViewName:	$7.(*ast.TableName),
+			Cols:		$8.([]model.CIStr),
+			Select:		$10.(ast.StmtNode),
+			Algorithm:	$3.(model.ViewAlgorithm),
+			Definer:	$4.(*auth.UserIdentity),
+			Security:	$5.(model.ViewSecurity),
+		}
+		if $11 != nil {
+			x.CheckOption = $11.(model.ViewCheckOption)
+			endOffset = parser.endOffset(&yyS[yypt])
+		} else {
+			x.CheckOption = model.CheckOptionNone
+			// The lookahead token follows the end of the statement.
+			endOffset = parser.endOffset(&parser.yylval)
+		}
+		x.Select.SetText(strings.TrimSpace(parser.src[startOffset:endOffset]))
+		$$ = x
+	}
+
+OrReplace:
+	{
+		$$ = false
+	}
+|	"OR" "REPLACE"
+	{
+		$$ = true
+	}
+
+ViewAlgorithm:
+	{
+		$$ = model.AlgorithmUndefined
+	}
+|	"ALGORITHM" '=' "UNDEFINED"
+	{
+		$$ = model.AlgorithmUndefined
+	}
+|	"ALGORITHM" '=' "MERGE"
+	{
+		$$ = model.AlgorithmMerge
+	}
+|	"ALGORITHM" '=' "TEMPTABLE"
+	{
+		$$ = model.AlgorithmTemptable
+	}
+
+ViewDefiner:
+	{
+		$$ = &auth.UserIdentity{CurrentUser: true}
+	}
+|	"DEFINER" '=' Username
+	{
+		$$ = $3
+	}
+
+ViewSQLSecurity:
+	{
+		$$ = model.SecurityDefiner
+	}
+|	"SQL" "SECURITY" "DEFINER"
+	{
+		$$ = model.SecurityDefiner
+	}
+|	"SQL" "SECURITY" "INVOKER"
+	{
+		$$ = model.SecurityInvoker
+	}
+
+CreateViewSelectOpt:
+	SelectStmt
+	{
+		$$ = $1
+	}
+|	SelectStmtWithClause
+	{
+		$$ = $1
+	}
+|	SetOprStmt
+	{
+		$$ = $1
+	}
+
+ViewCheckOption:
+	{
+		$$ = nil
+	}
+|	"WITH" "CHECK" "OPTION"
+	{
+		$$ = model.CheckOptionCascaded
+	}
+|	"WITH" "CASCADED" "CHECK" "OPTION"
+	{
+		$$ = model.CheckOptionCascaded
+	}
+|	"WITH" "LOCAL" "CHECK" "OPTION"
+	{
+		$$ = model.CheckOptionLocal
+	}
+
+Username:
+	StringName
+	{
+		$$ = &auth.UserIdentity{Username: $1.(string), Hostname: "%"}
+	}
+|	StringName singleAtIdentifier
+	{
+		$$ = &auth.UserIdentity{Username: $1.(string), Hostname: strings.TrimPrefix($2, "@")}
+	}
+|	"CURRENT_USER" OptionalBraces
+	{
+		$$ = &auth.UserIdentity{CurrentUser: true}
+	}
+
 /*******************************************************************
  *
  *  Delete Statement
@@ -2106,6 +2318,12 @@ OptTemporary:
 		parser.lastErrorAsWarn()
 	}
 
+DropViewStmt:
+	"DROP" "VIEW" IfExists TableNameList RestrictOrCascadeOpt
+	{
+		$$ = &ast.DropTableStmt{IfExists: $3.(bool), Tables: $4.([]*ast.TableName), IsView: true}
+	}
+
 RestrictOrCascadeOpt:
 	{}
 |	"RESTRICT"
@@ -2196,7 +2414,13 @@ Expression:
 	}
 |	"NOT" Expression %prec not
 	{
-		$$ = &ast.UnaryOperationExpr{Op: opcode.Not, V: $2}
+		expr, ok := $2.(*ast.ExistsSubqueryExpr)
+		if ok {
+			expr.Not = !expr.Not
+			$$ = $2
+		} else {
+			$$ = &ast.UnaryOperationExpr{Op: opcode.Not, V: $2}
+		}
 	}
 |	BoolPri IsOrNotOp "UNKNOWN" %prec is
 	{
@@ -2265,6 +2489,11 @@ BoolPri:
 		}
 		$$ = &ast.BinaryOperationExpr{Op: $2.(opcode.Op), L: $1, R: variable}
 	}
+|	BoolPri CompareOp AnyOrAll SubSelect %prec eq
+	{
+		sq := $4.(*ast.SubqueryExpr)
+		$$ = &ast.CompareSubqueryExpr{Op: $2.(opcode.Op), L: $1, R: sq, All: $3.(bool)}
+	}
 |	PredicateExpr
 
 CompareOp:
@@ -2331,6 +2560,30 @@ InOrNotOp:
 		$$ = false
 	}
 
+LikeOrNotOp:
+	"LIKE"
+	{
+		$$ = true
+	}
+|	"NOT" "LIKE"
+	{
+		$$ = false
+	}
+
+RegexpOrNotOp:
+	RegexpSym
+	{
+		$$ = true
+	}
+|	"NOT" RegexpSym
+	{
+		$$ = false
+	}
+
+RegexpSym:
+	"REGEXP"
+|	"RLIKE"
+
 AnyOrAll:
 	"ANY"
 	{
@@ -2350,6 +2603,11 @@ PredicateExpr:
 	{
 		$$ = &ast.PatternInExpr{Expr: $1, Not: !$2.(bool), List: $4.([]ast.ExprNode)}
 	}
+|	BitExpr InOrNotOp SubSelect
+	{
+		sq := $3.(*ast.SubqueryExpr)
+		$$ = &ast.PatternInExpr{Expr: $1, Not: !$2.(bool), Sel: sq}
+	}
 |	BitExpr BetweenOrNotOp BitExpr "AND" PredicateExpr
 	{
 		$$ = &ast.BetweenExpr{
@@ -2359,6 +2617,26 @@ PredicateExpr:
 			Not:	!$2.(bool),
 		}
 	}
+|	BitExpr LikeOrNotOp SimpleExpr LikeEscapeOpt
+	{
+		escape := $4.(string)
+		if len(escape) > 1 {
+			yylex.AppendError(ErrWrongArguments.GenWithStackByArgs("ESCAPE"))
+			return 1
+		} else if len(escape) == 0 {
+			escape = "\\"
+		}
+		$$ = &ast.PatternLikeExpr{
+			Expr:		$1,
+			Pattern:	$3,
+			Not:		!$2.(bool),
+			Escape:		escape[0],
+		}
+	}
+|	BitExpr RegexpOrNotOp SimpleExpr
+	{
+		$$ = &ast.PatternRegexpExpr{Expr: $1, Pattern: $3, Not: !$2.(bool)}
+	}
 |	BitExpr
 
 LikeEscapeOpt:
@@ -2705,6 +2983,14 @@ InsertValues:
 	{
 		$$ = &ast.InsertStmt{Columns: $2.([]*ast.ColumnName), Select: $5.(*ast.SelectStmt)}
 	}
+|	'(' ColumnNameListOpt ')' SetOprStmt
+	{
+		$$ = &ast.InsertStmt{Columns: $2.([]*ast.ColumnName), Select: $4.(*ast.SetOprStmt)}
+	}
+|	'(' ColumnNameListOpt ')' SelectStmtWithClause
+	{
+		$$ = &ast.InsertStmt{Columns: $2.([]*ast.ColumnName), Select: $4.(ast.ResultSetNode)}
+	}
 |	ValueSym ValuesList %prec insertValues
 	{
 		$$ = &ast.InsertStmt{Lists:  $2.([][]ast.ExprNode)}
@@ -2717,6 +3003,14 @@ InsertValues:
 	{
 		$$ = &ast.InsertStmt{Select: $1.(*ast.SelectStmt)}
 	}
+|	SetOprStmt
+	{
+		$$ = &ast.InsertStmt{Select: $1.(*ast.SetOprStmt)}
+	}
+|	SelectStmtWithClause
+	{
+		$$ = &ast.InsertStmt{Select: $1.(ast.ResultSetNode)}
+	}
 |	"SET" ColumnSetValueList
 	{
 		$$ = &ast.InsertStmt{Setlist: $2.([]*ast.Assignment)}
@@ -2952,6 +3246,28 @@ BitExpr:
 	{
 		$$ = &ast.BinaryOperationExpr{Op: opcode.Minus, L: $1, R: $3}
 	}
+|	BitExpr '+' "INTERVAL" Expression TimeUnit %prec '+'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr(ast.DateAdd),
+			Args: []ast.ExprNode{
+				$1,
+				$4,
+				&ast.TimeUnitExpr{Unit: $5.(ast.TimeUnitType)},
+			},
+		}
+	}
+|	BitExpr '-' "INTERVAL" Expression TimeUnit %prec '+'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr(ast.DateSub),
+			Args: []ast.ExprNode{
+				$1,
+				$4,
+				&ast.TimeUnitExpr{Unit: $5.(ast.TimeUnitType)},
+			},
+		}
+	}
 |	BitExpr '*' BitExpr %prec '*'
 	{
 		$$ = &ast.BinaryOperationExpr{Op: opcode.Mul, L: $1, R: $3}
@@ -3010,9 +3326,24 @@ SimpleIdent:
 
 SimpleExpr:
 	SimpleIdent
+|	SimpleIdent jss stringLit
+	{
+		expr := ast.NewValueExpr($3)
+		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr(ast.JSONExtract), Args: []ast.ExprNode{$1, expr}}
+	}
+|	SimpleIdent juss stringLit
+	{
+		expr := ast.NewValueExpr($3)
+		extract := &ast.FuncCallExpr{FnName: model.NewCIStr(ast.JSONExtract), Args: []ast.ExprNode{$1, expr}}
+		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr(ast.JSONUnquote), Args: []ast.ExprNode{extract}}
+	}
 |	FunctionCallKeyword
 |	FunctionCallNonKeyword
 |	FunctionCallGeneric
+|	paramMarker
+	{
+		$$ = ast.NewParamMarkerExpr(yyS[yypt].offset)
+	}
 |	SimpleExpr "COLLATE" StringName %prec neg
 	{
 		// TODO: Create a builtin function hold expr and collation. When do evaluation, convert expr result using the collation.
@@ -3021,6 +3352,7 @@ SimpleExpr:
 |	Literal
 |	Variable
 |	SumExpr
+|	WindowFuncCall
 |	'!' SimpleExpr %prec neg
 	{
 		$$ = &ast.UnaryOperationExpr{Op: opcode.Not, V: $2}
@@ -3058,6 +3390,13 @@ SimpleExpr:
 		values := append($3.([]ast.ExprNode), $5)
 		$$ = &ast.RowExpr{Values: values}
 	}
+|	SubSelect
+|	"EXISTS" SubSelect
+	{
+		sq := $2.(*ast.SubqueryExpr)
+		sq.Exists = true
+		$$ = &ast.ExistsSubqueryExpr{Sel: sq}
+	}
 |	"CONVERT" '(' Expression "USING" CharsetName ')'
 	{
 		// See https://dev.mysql.com/doc/refman/5.7/en/cast-functions.html#function_convert
@@ -3071,11 +3410,79 @@ SimpleExpr:
 	{
 		$$ = &ast.DefaultExpr{Name: $3.(*ast.ColumnNameExpr).Name}
 	}
+|	"CASE" ExpressionOpt WhenClauseList ElseOpt "END"
+	{
+		x := &ast.CaseExpr{WhenClauses: $3.([]*ast.WhenClause)}
+		if $2 != nil {
+			x.Value = $2
+		}
+		if $4 != nil {
+			x.ElseClause = $4.(ast.ExprNode)
+		}
+		$$ = x
+	}
 |	"VALUES" '(' SimpleIdent ')' %prec lowerThanInsertValues
 	{
 		$$ = &ast.ValuesExpr{Column: $3.(*ast.ColumnNameExpr)}
 	}
 
+WhenClauseList:
+	WhenClause
+	{
+		$$ = []*ast.WhenClause{$1.(*ast.WhenClause)}
+	}
+|	WhenClauseList WhenClause
+	{
+		$$ = append($1.([]*ast.WhenClause), $2.(*ast.WhenClause))
+	}
+
+WhenClause:
+	"WHEN" Expression "THEN" Expression
+	{
+		$$ = &ast.WhenClause{
+			Expr:   $2,
+			Result: $4,
+		}
+	}
+
+ElseOpt:
+	/* empty */
+	{
+		$$ = nil
+	}
+|	"ELSE" Expression
+	{
+		$$ = $2
+	}
+
+SubSelect:
+	'(' SelectStmt ')'
+	{
+		s := $2.(*ast.SelectStmt)
+		endOffset := parser.endOffset(&yyS[yypt])
+		parser.setLastSelectFieldText(s, endOffset)
+		src := parser.src
+		// See the implementation of yyParse function
+		s.SetText(src[yyS[yypt-1].offset:yyS[yypt].offset])
+		$$ = &ast.SubqueryExpr{Query: s}
+	}
+|	'(' SetOprStmt ')'
+	{
+		s := $2.(*ast.SetOprStmt)
+		src := parser.src
+		// See the implementation of yyParse function
+		s.SetText(src[yyS[yypt-1].offset:yyS[yypt].offset])
+		$$ = &ast.SubqueryExpr{Query: s}
+	}
+|	'(' SelectStmtWithClause ')'
+	{
+		rs := $2.(ast.ResultSetNode)
+		src := parser.src
+		// See the implementation of yyParse function
+		rs.SetText(src[yyS[yypt-1].offset:yyS[yypt].offset])
+		$$ = &ast.SubqueryExpr{Query: rs}
+	}
+
 DistinctKwd:
 	"DISTINCT"
 |	"DISTINCTROW"
@@ -3186,6 +3593,14 @@ FunctionCallNonKeyword:
 	{
 		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1), Args: $3.([]ast.ExprNode)}
 	}
+|	builtinCast '(' Expression "AS" CastType ')'
+	{
+		/* See https://dev.mysql.com/doc/refman/5.7/en/cast-functions.html#function_cast */
+		$$ = &ast.FuncCastExpr{
+			Expr: $3,
+			Tp:   $5.(*types.FieldType),
+		}
+	}
 |	builtinSysDate '(' FuncDatetimePrecListOpt ')'
 	{
 		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1), Args: $3.([]ast.ExprNode)}
@@ -3236,52 +3651,553 @@ FunctionCallNonKeyword:
 			Args: []ast.ExprNode{$5, $3},
 		}
 	}
+|	FunctionNameDateArith '(' Expression ',' "INTERVAL" Expression TimeUnit ')'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args: []ast.ExprNode{
+				$3,
+				$6,
+				&ast.TimeUnitExpr{Unit: $7.(ast.TimeUnitType)},
+			},
+		}
+	}
+|	FunctionNameDateArithMultiForms '(' Expression ',' Expression ')'
+	{
+		/* ADDDATE(expr, days) is a synonym of ADDDATE(expr, INTERVAL days DAY) */
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args: []ast.ExprNode{
+				$3,
+				$5,
+				&ast.TimeUnitExpr{Unit: ast.TimeUnitDay},
+			},
+		}
+	}
+|	FunctionNameDateArithMultiForms '(' Expression ',' "INTERVAL" Expression TimeUnit ')'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args: []ast.ExprNode{
+				$3,
+				$6,
+				&ast.TimeUnitExpr{Unit: $7.(ast.TimeUnitType)},
+			},
+		}
+	}
+|	builtinExtract '(' TimeUnit "FROM" Expression ')'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args: []ast.ExprNode{&ast.TimeUnitExpr{Unit: $3.(ast.TimeUnitType)}, $5},
+		}
+	}
+|	"TIMESTAMPDIFF" '(' TimestampUnit ',' Expression ',' Expression ')'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args: []ast.ExprNode{&ast.TimeUnitExpr{Unit: $3.(ast.TimeUnitType)}, $5, $7},
+		}
+	}
+
+FunctionNameDateArith:
+	builtinDateAdd
+|	builtinDateSub
+
+
+FunctionNameDateArithMultiForms:
+	builtinAddDate
+|	builtinSubDate
+
+TimeUnit:
+	TimestampUnit
+|	"SECOND_MICROSECOND"
+	{
+		$$ = ast.TimeUnitSecondMicrosecond
+	}
+|	"MINUTE_MICROSECOND"
+	{
+		$$ = ast.TimeUnitMinuteMicrosecond
+	}
+|	"MINUTE_SECOND"
+	{
+		$$ = ast.TimeUnitMinuteSecond
+	}
+|	"HOUR_MICROSECOND"
+	{
+		$$ = ast.TimeUnitHourMicrosecond
+	}
+|	"HOUR_SECOND"
+	{
+		$$ = ast.TimeUnitHourSecond
+	}
+|	"HOUR_MINUTE"
+	{
+		$$ = ast.TimeUnitHourMinute
+	}
+|	"DAY_MICROSECOND"
+	{
+		$$ = ast.TimeUnitDayMicrosecond
+	}
+|	"DAY_SECOND"
+	{
+		$$ = ast.TimeUnitDaySecond
+	}
+|	"DAY_MINUTE"
+	{
+		$$ = ast.TimeUnitDayMinute
+	}
+|	"DAY_HOUR"
+	{
+		$$ = ast.TimeUnitDayHour
+	}
+|	"YEAR_MONTH"
+	{
+		$$ = ast.TimeUnitYearMonth
+	}
+
+TimestampUnit:
+	"MICROSECOND"
+	{
+		$$ = ast.TimeUnitMicrosecond
+	}
+|	"SECOND"
+	{
+		$$ = ast.TimeUnitSecond
+	}
+|	"MINUTE"
+	{
+		$$ = ast.TimeUnitMinute
+	}
+|	"HOUR"
+	{
+		$$ = ast.TimeUnitHour
+	}
+|	"DAY"
+	{
+		$$ = ast.TimeUnitDay
+	}
+|	"WEEK"
+	{
+		$$ = ast.TimeUnitWeek
+	}
+|	"MONTH"
+	{
+		$$ = ast.TimeUnitMonth
+	}
+|	"QUARTER"
+	{
+		$$ = ast.TimeUnitQuarter
+	}
+|	"YEAR"
+	{
+		$$ = ast.TimeUnitYear
+	}
+|	"SQL_TSI_SECOND"
+	{
+		$$ = ast.TimeUnitSecond
+	}
+|	"SQL_TSI_MINUTE"
+	{
+		$$ = ast.TimeUnitMinute
+	}
+|	"SQL_TSI_HOUR"
+	{
+		$$ = ast.TimeUnitHour
+	}
+|	"SQL_TSI_DAY"
+	{
+		$$ = ast.TimeUnitDay
+	}
+|	"SQL_TSI_WEEK"
+	{
+		$$ = ast.TimeUnitWeek
+	}
+|	"SQL_TSI_MONTH"
+	{
+		$$ = ast.TimeUnitMonth
+	}
+|	"SQL_TSI_QUARTER"
+	{
+		$$ = ast.TimeUnitQuarter
+	}
+|	"SQL_TSI_YEAR"
+	{
+		$$ = ast.TimeUnitYear
+	}
+
+SumExpr:
+	"AVG" '(' DefaultFalseDistinctOpt Expression ')' OptWindowingClause
+	{
+		if $6 != nil {
+			$$ = &ast.WindowFuncExpr{F: $1, Args: []ast.ExprNode{$4}, Distinct: $3.(bool), Spec: *($6.(*ast.WindowSpec)),}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$4}, Distinct: $3.(bool)}
+		}
+	}
+|	builtinBitAnd '(' DefaultFalseDistinctOpt Expression ')' OptWindowingClause
+	{
+		if $6 != nil {
+			$$ = &ast.WindowFuncExpr{F: ast.AggFuncBitAnd, Args: []ast.ExprNode{$4}, Distinct: $3.(bool), Spec: *($6.(*ast.WindowSpec)),}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: ast.AggFuncBitAnd, Args: []ast.ExprNode{$4}, Distinct: $3.(bool)}
+		}
+	}
+|	builtinBitOr '(' DefaultFalseDistinctOpt Expression ')' OptWindowingClause
+	{
+		if $6 != nil {
+			$$ = &ast.WindowFuncExpr{F: ast.AggFuncBitOr, Args: []ast.ExprNode{$4}, Distinct: $3.(bool), Spec: *($6.(*ast.WindowSpec)),}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: ast.AggFuncBitOr, Args: []ast.ExprNode{$4}, Distinct: $3.(bool)}
+		}
+	}
+|	builtinBitXor '(' DefaultFalseDistinctOpt Expression ')' OptWindowingClause
+	{
+		if $6 != nil {
+			$$ = &ast.WindowFuncExpr{F: ast.AggFuncBitXor, Args: []ast.ExprNode{$4}, Distinct: $3.(bool), Spec: *($6.(*ast.WindowSpec)),}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: ast.AggFuncBitXor, Args: []ast.ExprNode{$4}, Distinct: $3.(bool)}
+		}
+	}
+|	builtinCount '(' DistinctKwd ExpressionList ')'
+	{
+		$$ = &ast.AggregateFuncExpr{F: $1, Args: $4.([]ast.ExprNode), Distinct: true}
+	}
+|	builtinCount '(' "ALL" Expression ')' OptWindowingClause
+	{
+		if $6 != nil {
+			$$ = &ast.WindowFuncExpr{F: $1, Args: []ast.ExprNode{$4}, Spec: *($6.(*ast.WindowSpec)),}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$4}}
+		}
+	}
+|	builtinCount '(' Expression ')' OptWindowingClause
+	{
+		if $5 != nil {
+			$$ = &ast.WindowFuncExpr{F: $1, Args: []ast.ExprNode{$3}, Spec: *($5.(*ast.WindowSpec)),}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		}
+	}
+|	builtinCount '(' '*' ')' OptWindowingClause
+	{
+		args := []ast.ExprNode{ast.NewValueExpr(1)}
+		if $5 != nil {
+			$$ = &ast.WindowFuncExpr{F: $1, Args: args, Spec: *($5.(*ast.WindowSpec)),}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: $1, Args: args,}
+		}
+	}
+|	builtinGroupConcat '(' DefaultFalseDistinctOpt ExpressionList OrderByOptional OptGConcatSeparator ')'
+	{
+		args := $4.([]ast.ExprNode)
+		args = append(args, $6.(ast.ExprNode))
+		agg := &ast.AggregateFuncExpr{F: ast.AggFuncGroupConcat, Args: args, Distinct: $3.(bool)}
+		if $5 != nil {
+			agg.Order = $5.(*ast.OrderByClause)
+		}
+		$$ = agg
+	}
+|	builtinMax '(' DefaultFalseDistinctOpt Expression ')' OptWindowingClause
+	{
+		if $6 != nil {
+			$$ = &ast.WindowFuncExpr{F: $1, Args: []ast.ExprNode{$4}, Distinct: $3.(bool), Spec: *($6.(*ast.WindowSpec)),}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$4}, Distinct: $3.(bool)}
+		}
+	}
+|	builtinMin '(' DefaultFalseDistinctOpt Expression ')' OptWindowingClause
+	{
+		if $6 != nil {
+			$$ = &ast.WindowFuncExpr{F: $1, Args: []ast.ExprNode{$4}, Distinct: $3.(bool), Spec: *($6.(*ast.WindowSpec)),}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$4}, Distinct: $3.(bool)}
+		}
+	}
+|	builtinSum '(' DefaultFalseDistinctOpt Expression ')' OptWindowingClause
+	{
+		if $6 != nil {
+			$$ = &ast.WindowFuncExpr{F: $1, Args: []ast.ExprNode{$4}, Distinct: $3.(bool), Spec: *($6.(*ast.WindowSpec)),}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$4}, Distinct: $3.(bool)}
+		}
+	}
+|	builtinStddevPop '(' DefaultFalseDistinctOpt Expression ')' OptWindowingClause
+	{
+		if $6 != nil {
+			$$ = &ast.WindowFuncExpr{F: ast.AggFuncStddevPop, Args: []ast.ExprNode{$4}, Distinct: $3.(bool), Spec: *($6.(*ast.WindowSpec)),}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: ast.AggFuncStddevPop, Args: []ast.ExprNode{$4}, Distinct: $3.(bool)}
+		}
+	}
+|	builtinStddevSamp '(' DefaultFalseDistinctOpt Expression ')' OptWindowingClause
+	{
+		if $6 != nil {
+			$$ = &ast.WindowFuncExpr{F: ast.AggFuncStddevSamp, Args: []ast.ExprNode{$4}, Distinct: $3.(bool), Spec: *($6.(*ast.WindowSpec)),}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: ast.AggFuncStddevSamp, Args: []ast.ExprNode{$4}, Distinct: $3.(bool)}
+		}
+	}
+|	builtinVarPop '(' DefaultFalseDistinctOpt Expression ')' OptWindowingClause
+	{
+		if $6 != nil {
+			$$ = &ast.WindowFuncExpr{F: ast.AggFuncVarPop, Args: []ast.ExprNode{$4}, Distinct: $3.(bool), Spec: *($6.(*ast.WindowSpec)),}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: ast.AggFuncVarPop, Args: []ast.ExprNode{$4}, Distinct: $3.(bool)}
+		}
+	}
+|	builtinVarSamp '(' DefaultFalseDistinctOpt Expression ')' OptWindowingClause
+	{
+		if $6 != nil {
+			$$ = &ast.WindowFuncExpr{F: ast.AggFuncVarSamp, Args: []ast.ExprNode{$4}, Distinct: $3.(bool), Spec: *($6.(*ast.WindowSpec)),}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: ast.AggFuncVarSamp, Args: []ast.ExprNode{$4}, Distinct: $3.(bool)}
+		}
+	}
+
+OptGConcatSeparator:
+        {
+            	$$ = ast.NewValueExpr(",")
+        }
+| "SEPARATOR" stringLit
+	{
+		$$ = ast.NewValueExpr($2)
+	}
+
+
+// See https://dev.mysql.com/doc/refman/8.0/en/window-functions-usage.html
+WindowClauseOptional:
+	{
+		$$ = nil
+	}
+|	"WINDOW" WindowDefinitionList
+	{
+		$$ = $2.([]ast.WindowSpec)
+	}
+
+WindowDefinitionList:
+	WindowDefinition
+	{
+		$$ = []ast.WindowSpec{$1.(ast.WindowSpec)}
+	}
+|	WindowDefinitionList ',' WindowDefinition
+	{
+		$$ = append($1.([]ast.WindowSpec), $3.(ast.WindowSpec))
+	}
+
+WindowDefinition:
+	WindowName "AS" WindowSpec
+	{
+		var spec = $3.(ast.WindowSpec)
+		spec.Name = $1.(model.CIStr)
+		$$ = spec
+	}
+
+WindowName:
+	Identifier
+	{
+		$$ = model.NewCIStr($1)
+	}
+
+WindowSpec:
+	'(' WindowSpecDetails ')'
+	{
+		$$ = $2.(ast.WindowSpec)
+	}
+
+WindowSpecDetails:
+	OptExistingWindowName OptPartitionClause OptWindowOrderByClause OptWindowFrameClause
+	{
+		spec := ast.WindowSpec{Ref: $1.(model.CIStr),}
+		if $2 != nil {
+			spec.PartitionBy = $2.(*ast.PartitionByClause)
+		}
+		if $3 != nil {
+			spec.OrderBy = $3.(*ast.OrderByClause)
+		}
+		if $4 != nil {
+			spec.Frame = $4.(*ast.FrameClause)
+		}
+		$$ = spec
+	}
+
+OptExistingWindowName:
+	{
+		$$ = model.CIStr{}
+	}
+|	WindowName
+	{
+		$$ = $1
+	}
+
+OptPartitionClause:
+	{
+		$$ = nil
+	}
+|	"PARTITION" "BY" ByList
+	{
+		$$ = &ast.PartitionByClause{Items: $3.([]*ast.ByItem)}
+	}
+
+OptWindowOrderByClause:
+	{
+		$$ = nil
+	}
+|	"ORDER" "BY" ByList
+	{
+		$$ = &ast.OrderByClause{Items: $3.([]*ast.ByItem)}
+	}
+
+OptWindowFrameClause:
+	{
+		$$ = nil
+	}
+|	WindowFrameUnits WindowFrameExtent
+	{
+		$$ = &ast.FrameClause{
+			Type: $1.(ast.FrameType),
+			Extent: $2.(ast.FrameExtent),
+		}
+	}
+
+WindowFrameUnits:
+	"ROWS"
+	{
+		$$ = ast.FrameType(ast.Rows)
+	}
+|	"RANGE"
+	{
+		$$ = ast.FrameType(ast.Ranges)
+	}
+
+WindowFrameExtent:
+	WindowFrameStart
+	{
+		$$ = ast.FrameExtent {
+			Start: $1.(ast.FrameBound),
+			End: ast.FrameBound{Type: ast.CurrentRow,},
+		}
+	}
+|	WindowFrameBetween
+	{
+		$$ = $1.(ast.FrameExtent)
+	}
+
+WindowFrameStart:
+	"UNBOUNDED" "PRECEDING"
+	{
+		$$ = ast.FrameBound{Type: ast.Preceding, UnBounded: true,}
+	}
+|	NumLiteral "PRECEDING"
+	{
+		$$ = ast.FrameBound{Type: ast.Preceding, Expr: ast.NewValueExpr($1),}
+	}
+|	"CURRENT" "ROW"
+	{
+		$$ = ast.FrameBound{Type: ast.CurrentRow,}
+	}
+
+WindowFrameBetween:
+	"BETWEEN" WindowFrameBound "AND" WindowFrameBound
+	{
+		$$ = ast.FrameExtent{Start: $2.(ast.FrameBound), End: $4.(ast.FrameBound),}
+	}
+
+WindowFrameBound:
+	WindowFrameStart
+	{
+		$$ = $1.(ast.FrameBound)
+	}
+|	"UNBOUNDED" "FOLLOWING"
+	{
+		$$ = ast.FrameBound{Type: ast.Following, UnBounded: true,}
+	}
+|	NumLiteral "FOLLOWING"
+	{
+		$$ = ast.FrameBound{Type: ast.Following, Expr: ast.NewValueExpr($1),}
+	}
+
+OptWindowingClause:
+	{
+		$$ = nil
+	}
+|	WindowingClause
+	{
+		spec := $1.(ast.WindowSpec)
+		$$ = &spec
+	}
 
-FunctionNameDateArith:
-	builtinDateAdd
-|	builtinDateSub
-
+WindowingClause:
+	"OVER" WindowNameOrSpec
+	{
+		$$ = $2.(ast.WindowSpec)
+	}
 
-FunctionNameDateArithMultiForms:
-	builtinAddDate
-|	builtinSubDate
+WindowNameOrSpec:
+	WindowName
+	{
+		$$ = ast.WindowSpec{Name: $1.(model.CIStr), OnlyAlias: true,}
+	}
+|	WindowSpec
+	{
+		$$ = $1.(ast.WindowSpec)
+	}
 
-SumExpr:
-	"AVG" '(' Expression ')'
+WindowFuncCall:
+	"ROW_NUMBER" '(' ')' WindowingClause
 	{
-		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		$$ = &ast.WindowFuncExpr{F: $1, Spec: $4.(ast.WindowSpec),}
 	}
-|	builtinCount '(' Expression ')'
+|	"RANK" '(' ')' WindowingClause
 	{
-		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		$$ = &ast.WindowFuncExpr{F: $1, Spec: $4.(ast.WindowSpec),}
 	}
-|	builtinCount '(' '*' ')'
+|	"DENSE_RANK" '(' ')' WindowingClause
 	{
-		args := []ast.ExprNode{ast.NewValueExpr(1)}
-		$$ = &ast.AggregateFuncExpr{F: $1, Args: args,}
+		$$ = &ast.WindowFuncExpr{F: $1, Spec: $4.(ast.WindowSpec),}
 	}
-|	builtinMax '(' Expression ')'
+|	"LEAD" '(' Expression OptLeadLagInfo ')' WindowingClause
 	{
-		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		args := []ast.ExprNode{$3}
+		if $4 != nil {
+			args = append(args, $4.([]ast.ExprNode)...)
+		}
+		$$ = &ast.WindowFuncExpr{F: $1, Args: args, Spec: $6.(ast.WindowSpec),}
 	}
-|	builtinMin '(' Expression ')'
+|	"LAG" '(' Expression OptLeadLagInfo ')' WindowingClause
 	{
-		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		args := []ast.ExprNode{$3}
+		if $4 != nil {
+			args = append(args, $4.([]ast.ExprNode)...)
+		}
+		$$ = &ast.WindowFuncExpr{F: $1, Args: args, Spec: $6.(ast.WindowSpec),}
 	}
-|	builtinSum '(' Expression ')'
+|	"FIRST_VALUE" '(' Expression ')' WindowingClause
 	{
-		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		$$ = &ast.WindowFuncExpr{F: $1, Args: []ast.ExprNode{$3}, Spec: $5.(ast.WindowSpec),}
 	}
 
-OptGConcatSeparator:
-        {
-            	$$ = ast.NewValueExpr(",")
-        }
-| "SEPARATOR" stringLit
+OptLeadLagInfo:
 	{
-		$$ = ast.NewValueExpr($2)
+		$$ = nil
+	}
+|	',' NumLiteral OptLLDefault
+	{
+		args := []ast.ExprNode{ast.NewValueExpr($2)}
+		if $3 != nil {
+			args = append(args, $3.(ast.ExprNode))
+		}
+		$$ = args
 	}
 
+OptLLDefault:
+	{
+		$$ = nil
+	}
+|	',' Expression
+	{
+		$$ = $2
+	}
 
 FunctionCallGeneric:
 	identifier '(' ExpressionListOpt ')'
@@ -3560,13 +4476,13 @@ SelectStmtFromDualTable:
 
 SelectStmtFromTable:
 	SelectStmtBasic "FROM"
-	TableRefsClause WhereClauseOptional SelectStmtGroup HavingClause
+	TableRefsClause WhereClauseOptional SelectStmtGroup HavingClause WindowClauseOptional
 	{
 		st := $1.(*ast.SelectStmt)
 		st.From = $3.(*ast.TableRefsClause)
 		lastField := st.Fields.Fields[len(st.Fields.Fields)-1]
 		if lastField.Expr != nil && lastField.AsName.O == "" {
-			lastEnd := parser.endOffset(&yyS[yypt-4])
+			lastEnd := parser.endOffset(&yyS[yypt-5])
 			lastField.SetText(parser.src[lastField.Offset:lastEnd])
 		}
 		if $4 != nil {
@@ -3578,21 +4494,27 @@ SelectStmtFromTable:
 		if $6 != nil {
 			st.Having = $6.(*ast.HavingClause)
 		}
+		if $7 != nil {
+			st.WindowSpecs = ($7.([]ast.WindowSpec))
+		}
 		$$ = st
 	}
 
 SelectStmt:
-	SelectStmtBasic OrderByOptional SelectStmtLimit
+	SelectStmtBasic OrderByOptional SelectStmtLimit SelectLockOpt
 	{
 		st := $1.(*ast.SelectStmt)
+		st.LockTp = $4.(ast.SelectLockType)
 		lastField := st.Fields.Fields[len(st.Fields.Fields)-1]
 		if lastField.Expr != nil && lastField.AsName.O == "" {
 			src := parser.src
 			var lastEnd int
 			if $2 != nil {
-				lastEnd = yyS[yypt-1].offset-1
+				lastEnd = yyS[yypt-2].offset-1
 			} else if $3 != nil {
-				lastEnd = yyS[yypt-0].offset-1
+				lastEnd = yyS[yypt-1].offset-1
+			} else if $4 != ast.SelectLockNone {
+				lastEnd = yyS[yypt].offset-1
 			} else {
 				lastEnd = len(src)
 				if src[lastEnd-1] == ';' {
@@ -3609,9 +4531,10 @@ SelectStmt:
 		}
 		$$ = st
 	}
-|	SelectStmtFromDualTable OrderByOptional SelectStmtLimit
+|	SelectStmtFromDualTable OrderByOptional SelectStmtLimit SelectLockOpt
 	{
 		st := $1.(*ast.SelectStmt)
+		st.LockTp = $4.(ast.SelectLockType)
 		if $2 != nil {
 			st.OrderBy = $2.(*ast.OrderByClause)
 		}
@@ -3620,9 +4543,10 @@ SelectStmt:
 		}
 		$$ = st
 	}
-|	SelectStmtFromTable OrderByOptional SelectStmtLimit
+|	SelectStmtFromTable OrderByOptional SelectStmtLimit SelectLockOpt
 	{
 		st := $1.(*ast.SelectStmt)
+		st.LockTp = $4.(ast.SelectLockType)
 		if $2 != nil {
 			st.OrderBy = $2.(*ast.OrderByClause)
 		}
@@ -3632,6 +4556,220 @@ SelectStmt:
 		$$ = st
 	}
 
+// See https://dev.mysql.com/doc/refman/5.7/en/union.html
+SetOprStmt:
+	SetOprClauseList SetOpr SelectStmtBasic OrderByOptional SelectStmtLimit
+	{
+		st := $3.(*ast.SelectStmt)
+		setOpr := $1.(*ast.SetOprStmt)
+		st.AfterSetOperator = $2.(*ast.SetOprType)
+		lastSelect := setOpr.SelectList.Selects[len(setOpr.SelectList.Selects)-1]
+		endOffset := parser.endOffset(&yyS[yypt-3])
+		parser.setLastSelectFieldText(lastSelect, endOffset)
+		setOpr.SelectList.Selects = append(setOpr.SelectList.Selects, st)
+		lastField := st.Fields.Fields[len(st.Fields.Fields)-1]
+		if lastField.Expr != nil && lastField.AsName.O == "" {
+			src := parser.src
+			var lastEnd int
+			if $4 != nil {
+				lastEnd = yyS[yypt-1].offset-1
+			} else if $5 != nil {
+				lastEnd = yyS[yypt-0].offset-1
+			} else {
+				lastEnd = len(src)
+				if src[lastEnd-1] == ';' {
+					lastEnd--
+				}
+			}
+			lastField.SetText(src[lastField.Offset:lastEnd])
+		}
+		if $4 != nil {
+			setOpr.OrderBy = $4.(*ast.OrderByClause)
+		}
+		if $5 != nil {
+			setOpr.Limit = $5.(*ast.Limit)
+		}
+		$$ = setOpr
+	}
+|	SetOprClauseList SetOpr SelectStmtFromDualTable OrderByOptional SelectStmtLimit
+	{
+		st := $3.(*ast.SelectStmt)
+		setOpr := $1.(*ast.SetOprStmt)
+		st.AfterSetOperator = $2.(*ast.SetOprType)
+		lastSelect := setOpr.SelectList.Selects[len(setOpr.SelectList.Selects)-1]
+		endOffset := parser.endOffset(&yyS[yypt-3])
+		parser.setLastSelectFieldText(lastSelect, endOffset)
+		setOpr.SelectList.Selects = append(setOpr.SelectList.Selects, st)
+		if $4 != nil {
+			setOpr.OrderBy = $4.(*ast.OrderByClause)
+		}
+		if $5 != nil {
+			setOpr.Limit = $5.(*ast.Limit)
+		}
+		$$ = setOpr
+	}
+|	SetOprClauseList SetOpr SelectStmtFromTable OrderByOptional SelectStmtLimit
+	{
+		st := $3.(*ast.SelectStmt)
+		setOpr := $1.(*ast.SetOprStmt)
+		st.AfterSetOperator = $2.(*ast.SetOprType)
+		lastSelect := setOpr.SelectList.Selects[len(setOpr.SelectList.Selects)-1]
+		endOffset := parser.endOffset(&yyS[yypt-3])
+		parser.setLastSelectFieldText(lastSelect, endOffset)
+		setOpr.SelectList.Selects = append(setOpr.SelectList.Selects, st)
+		if $4 != nil {
+			setOpr.OrderBy = $4.(*ast.OrderByClause)
+		}
+		if $5 != nil {
+			setOpr.Limit = $5.(*ast.Limit)
+		}
+		$$ = setOpr
+	}
+|	SetOprClauseList SetOpr '(' SelectStmt ')' OrderByOptional SelectStmtLimit
+	{
+		setOpr := $1.(*ast.SetOprStmt)
+		lastSelect := setOpr.SelectList.Selects[len(setOpr.SelectList.Selects)-1]
+		endOffset := parser.endOffset(&yyS[yypt-5])
+		parser.setLastSelectFieldText(lastSelect, endOffset)
+		st := $4.(*ast.SelectStmt)
+		st.IsInBraces = true
+		st.AfterSetOperator = $2.(*ast.SetOprType)
+		endOffset = parser.endOffset(&yyS[yypt-2])
+		parser.setLastSelectFieldText(st, endOffset)
+		setOpr.SelectList.Selects = append(setOpr.SelectList.Selects, st)
+		if $6 != nil {
+			setOpr.OrderBy = $6.(*ast.OrderByClause)
+		}
+		if $7 != nil {
+			setOpr.Limit = $7.(*ast.Limit)
+		}
+		$$ = setOpr
+	}
+
+SetOprClauseList:
+	SetOprSelect
+	{
+		selectList := &ast.SetOprSelectList{Selects: []*ast.SelectStmt{$1.(*ast.SelectStmt)}}
+		$$ = &ast.SetOprStmt{
+			SelectList: selectList,
+		}
+	}
+|	SetOprClauseList SetOpr SetOprSelect
+	{
+		setOpr := $1.(*ast.SetOprStmt)
+		st := $3.(*ast.SelectStmt)
+		st.AfterSetOperator = $2.(*ast.SetOprType)
+		lastSelect := setOpr.SelectList.Selects[len(setOpr.SelectList.Selects)-1]
+		endOffset := parser.endOffset(&yyS[yypt-1])
+		parser.setLastSelectFieldText(lastSelect, endOffset)
+		setOpr.SelectList.Selects = append(setOpr.SelectList.Selects, st)
+		$$ = setOpr
+	}
+
+SetOprSelect:
+	SelectStmt
+	{
+		$$ = $1.(interface{})
+	}
+|	'(' SelectStmt ')'
+	{
+		st := $2.(*ast.SelectStmt)
+		st.IsInBraces = true
+		endOffset := parser.endOffset(&yyS[yypt])
+		parser.setLastSelectFieldText(st, endOffset)
+		$$ = $2
+	}
+
+SetOpr:
+	"UNION" DefaultTrueDistinctOpt
+	{
+		var tp ast.SetOprType
+		tp = ast.Union
+		if !$2.(bool) {
+			tp = ast.UnionAll
+		}
+		$$ = &tp
+	}
+|	"EXCEPT"
+	{
+		var tp ast.SetOprType
+		tp = ast.Except
+		$$ = &tp
+	}
+|	"INTERSECT"
+	{
+		var tp ast.SetOprType
+		tp = ast.Intersect
+		$$ = &tp
+	}
+
+SelectStmtWithClause:
+	WithClause SelectStmt
+	{
+		sel := $2.(*ast.SelectStmt)
+		sel.With = $1.(*ast.WithClause)
+		$$ = sel
+	}
+|	WithClause SetOprStmt
+	{
+		setOpr := $2.(*ast.SetOprStmt)
+		setOpr.With = $1.(*ast.WithClause)
+		$$ = setOpr
+	}
+
+WithClause:
+	"WITH" WithList
+	{
+		$$ = $2
+	}
+|	"WITH" "RECURSIVE" WithList
+	{
+		ws := $3.(*ast.WithClause)
+		ws.IsRecursive = true
+		$$ = ws
+	}
+
+WithList:
+	WithList ',' CommonTableExpr
+	{
+		ws := $1.(*ast.WithClause)
+		ws.CTEs = append(ws.CTEs, $3.(*ast.CommonTableExpression))
+		$$ = ws
+	}
+|	CommonTableExpr
+	{
+		$$ = &ast.WithClause{CTEs: []*ast.CommonTableExpression{$1.(*ast.CommonTableExpression)}}
+	}
+
+CommonTableExpr:
+	Identifier IdentListWithParenOpt "AS" SubSelect
+	{
+		$$ = &ast.CommonTableExpression{
+			Name:        model.NewCIStr($1),
+			ColNameList: $2.([]model.CIStr),
+			Query:       $4.(*ast.SubqueryExpr),
+		}
+	}
+
+IdentListWithParenOpt:
+	{
+		$$ = []model.CIStr(nil)
+	}
+|	'(' IdentList ')'
+	{
+		$$ = $2
+	}
+
+IdentList:
+	Identifier
+	{
+		$$ = []model.CIStr{model.NewCIStr($1)}
+	}
+|	IdentList ',' Identifier
+	{
+		$$ = append($1.([]model.CIStr), model.NewCIStr($3))
+	}
+
 FromDual:
 	"FROM" "DUAL"
 
@@ -3695,6 +4833,14 @@ TableFactor:
 		parser.setLastSelectFieldText(st, endOffset)
 		$$ = &ast.TableSource{Source: $2.(*ast.SelectStmt), AsName: $4.(model.CIStr)}
 	}
+|	'(' SetOprStmt ')' TableAsName
+	{
+		$$ = &ast.TableSource{Source: $2.(*ast.SetOprStmt), AsName: $4.(model.CIStr)}
+	}
+|	'(' SelectStmtWithClause ')' TableAsName
+	{
+		$$ = &ast.TableSource{Source: $2.(ast.ResultSetNode), AsName: $4.(model.CIStr)}
+	}
 |	'(' TableRefs ')'
 	{
 		$$ = $2
@@ -3853,6 +4999,10 @@ LimitOption:
 	{
 		$$ = ast.NewValueExpr($1)
 	}
+|	paramMarker
+	{
+		$$ = ast.NewParamMarkerExpr(yyS[yypt].offset)
+	}
 
 SelectStmtLimit:
 	{
@@ -3871,6 +5021,19 @@ SelectStmtLimit:
 		$$ = &ast.Limit{Offset: $4.(ast.ExprNode), Count: $2.(ast.ExprNode)}
 	}
 
+SelectLockOpt:
+	/* empty */
+	{
+		$$ = ast.SelectLockNone
+	}
+|	"FOR" "UPDATE"
+	{
+		$$ = ast.SelectLockForUpdate
+	}
+|	"FOR" "UPDATE" "NOWAIT"
+	{
+		$$ = ast.SelectLockForUpdateNoWait
+	}
 
 SelectStmtOpts:
 	TableOptimizerHints DefaultFalseDistinctOpt PriorityOpt SelectStmtSQLSmallResult SelectStmtSQLBigResult SelectStmtSQLBufferResult SelectStmtSQLCache SelectStmtCalcFoundRows SelectStmtStraightJoin
@@ -4228,12 +5391,142 @@ SelectStmtGroup:
 	}
 |	GroupByClause
 
+/************************************************************************************
+ *
+ *  Prepared Statement
+ *  See https://dev.mysql.com/doc/refman/5.7/en/sql-syntax-prepared-statements.html
+ *
+ ************************************************************************************/
+PreparedStmt:
+	"PREPARE" Identifier "FROM" PrepareSQL
+	{
+		var sqlText string
+		var sqlVar *ast.VariableExpr
+		switch x := $4.(type) {
+		case string:
+			sqlText = x
+		case *ast.VariableExpr:
+			sqlVar = x
+		}
+		$$ = &ast.PrepareStmt{
+			Name:		$2,
+			SQLText:	sqlText,
+			SQLVar:		sqlVar,
+		}
+	}
+
+PrepareSQL:
+	stringLit
+	{
+		$$ = $1
+	}
+|	UserVariable
+	{
+		$$ = $1
+	}
+
+/*
+ * See https://dev.mysql.com/doc/refman/5.7/en/execute.html
+ */
+ExecuteStmt:
+	"EXECUTE" Identifier
+	{
+		$$ = &ast.ExecuteStmt{Name: $2}
+	}
+|	"EXECUTE" Identifier "USING" UserVariableList
+	{
+		$$ = &ast.ExecuteStmt{
+			Name:		$2,
+			UsingVars:	$4.([]ast.ExprNode),
+		}
+	}
+
+/*
+ * See https://dev.mysql.com/doc/refman/5.7/en/deallocate-prepare.html
+ */
+DeallocateStmt:
+	DeallocateSym "PREPARE" Identifier
+	{
+		$$ = &ast.DeallocateStmt{Name: $3}
+	}
+
+DeallocateSym:
+	"DEALLOCATE" | "DROP"
+
 /********************Set Statement*******************************/
 SetStmt:
 	"SET" VariableAssignmentList
 	{
 		$$ = &ast.SetStmt{Variables: $2.([]*ast.VariableAssignment)}
 	}
+|	"SET" "TRANSACTION" TransactionChars
+	{
+		assigns := $3.([]*ast.VariableAssignment)
+		for i := 0; i < len(assigns); i++ {
+			if assigns[i].Name == "tx_isolation" {
+				// A special session variable that makes setting tx_isolation take effect one time.
+				assigns[i].Name = "tx_isolation_one_shot"
+			}
+		}
+		$$ = &ast.SetStmt{Variables: assigns}
+	}
+|	"SET" "GLOBAL" "TRANSACTION" TransactionChars
+	{
+		assigns := $4.([]*ast.VariableAssignment)
+		for i := 0; i < len(assigns); i++ {
+			assigns[i].IsGlobal = true
+		}
+		$$ = &ast.SetStmt{Variables: assigns}
+	}
+|	"SET" "SESSION" "TRANSACTION" TransactionChars
+	{
+		$$ = &ast.SetStmt{Variables: $4.([]*ast.VariableAssignment)}
+	}
+
+TransactionChars:
+	TransactionChar
+|	TransactionChars ',' TransactionChar
+	{
+		$$ = append($1.([]*ast.VariableAssignment), $3.([]*ast.VariableAssignment)...)
+	}
+
+TransactionChar:
+	"ISOLATION" "LEVEL" IsolationLevel
+	{
+		$$ = []*ast.VariableAssignment{
+			{Name: "tx_isolation", Value: ast.NewValueExpr($3), IsSystem: true},
+		}
+	}
+|	"READ" "WRITE"
+	{
+		$$ = []*ast.VariableAssignment{
+			{Name: "tx_read_only", Value: ast.NewValueExpr("0"), IsSystem: true},
+		}
+	}
+|	"READ" "ONLY"
+	{
+		$$ = []*ast.VariableAssignment{
+			{Name: "tx_read_only", Value: ast.NewValueExpr("1"), IsSystem: true},
+		}
+	}
+
+IsolationLevel:
+	"REPEATABLE" "READ"
+	{
+		$$ = ast.RepeatableRead
+	}
+|	"READ" "COMMITTED"
+	{
+		$$ = ast.ReadCommitted
+	}
+|	"READ" "UNCOMMITTED"
+	{
+		$$ = ast.ReadUncommitted
+	}
+|	"SERIALIZABLE"
+	{
+		$$ = ast.Serializable
+	}
 
 SetExpr:
 	"ON"
@@ -4374,6 +5667,16 @@ UserVariable:
 		$$ = &ast.VariableExpr{Name: v, IsGlobal: false, IsSystem: false}
 	}
 
+UserVariableList:
+	UserVariable
+	{
+		$$ = []ast.ExprNode{$1}
+	}
+|	UserVariableList ',' UserVariable
+	{
+		$$ = append($1.([]ast.ExprNode), $3)
+	}
+
 /****************************Admin Statement*******************************/
 AdminStmt:
 	"ADMIN" "SHOW" "DDL"
@@ -4417,6 +5720,13 @@ ShowStmt:
 			Table:	$4.(*ast.TableName),
 		}
 	}
+|	"SHOW" "CREATE" "VIEW" TableName
+	{
+		$$ = &ast.ShowStmt{
+			Tp:	ast.ShowCreateView,
+			Table:	$4.(*ast.TableName),
+		}
+	}
 |	"SHOW" "CREATE" "DATABASE" IfNotExists DBName
 	{
 		$$ = &ast.ShowStmt{
@@ -4469,6 +5779,13 @@ ShowTargetFilterable:
 			GlobalScope: $1.(bool),
 		}
 	}
+|	GlobalScope "STATUS"
+	{
+		$$ = &ast.ShowStmt{
+			Tp: ast.ShowStatus,
+			GlobalScope: $1.(bool),
+		}
+	}
 
 ShowLikeOrWhereOpt:
 	{
@@ -4539,21 +5856,32 @@ Statement:
 |	CreateDatabaseStmt
 |	CreateIndexStmt
 |	CreateTableStmt
+|	CreateViewStmt
+|	DeallocateStmt
 |	DropDatabaseStmt
 |	DropIndexStmt
 |	DropTableStmt
+|	DropViewStmt
+|	ExecuteStmt
 |	InsertIntoStmt
+|	PreparedStmt
 |	RollbackStmt
 |	ReplaceIntoStmt
 |	SelectStmt
+|	SelectStmtWithClause
+|	SetOprStmt
 |	SetStmt
 |	ShowStmt
 |	TruncateTableStmt
+|	UpdateStmt
 |	UseStmt
 
 ExplainableStmt:
 	SelectStmt
+|	SelectStmtWithClause
+|	SetOprStmt
 |	DeleteFromStmt
+|	UpdateStmt
 |	InsertIntoStmt
 |	ReplaceIntoStmt
 
@@ -5281,6 +6609,55 @@ StringName:
 		$$ = $1
 	}
 
+/***********************************************************************************
+ * Update Statement
+ * See https://dev.mysql.com/doc/refman/5.7/en/update.html
+ ***********************************************************************************/
+UpdateStmt:
+	"UPDATE" TableOptimizerHints PriorityOpt TableRef "SET" AssignmentList WhereClauseOptional OrderByOptional LimitClause
+	{
+		var refs *ast.Join
+		if x, ok := $4.(*ast.Join); ok {
+			refs = x
+		} else {
+			refs = &ast.Join{Left: $4.(ast.ResultSetNode)}
+		}
+		st := &ast.UpdateStmt{
+			Priority:  $3.(mysql.PriorityEnum),
+			TableRefs: &ast.TableRefsClause{TableRefs: refs},
+			List:	   $6.([]*ast.Assignment),
+		}
+		if $2 != nil {
+			st.TableHints = $2.([]*ast.TableOptimizerHint)
+		}
+		if $7 != nil {
+			st.Where = $7.(ast.ExprNode)
+		}
+		if $8 != nil {
+			st.Order = $8.(*ast.OrderByClause)
+		}
+		if $9 != nil {
+			st.Limit = $9.(*ast.Limit)
+		}
+		$$ = st
+	}
+|	"UPDATE" TableOptimizerHints PriorityOpt TableRefs "SET" AssignmentList WhereClauseOptional
+	{
+		st := &ast.UpdateStmt{
+			Priority:  $3.(mysql.PriorityEnum),
+			TableRefs: &ast.TableRefsClause{TableRefs: $4.(*ast.Join)},
+			List:	   $6.([]*ast.Assignment),
+		}
+		if $2 != nil {
+			st.TableHints = $2.([]*ast.TableOptimizerHint)
+		}
+		if $7 != nil {
+			st.Where = $7.(ast.ExprNode)
+		}
+		st.MultipleTable = true
+		$$ = st
+	}
+
 UseStmt:
 	"USE" DBName
 	{
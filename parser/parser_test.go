@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/types"
@@ -49,14 +50,14 @@ func (s *testParserSuite) TestSimple(c *C) {
 		"current_timestamp", "current_user", "database", "databases", "day_hour", "day_microsecond",
 		"day_minute", "day_second", "decimal", "default", "delete", "desc", "describe",
 		"distinct", "distinctRow", "div", "double", "drop", "dual", "else", "enclosed", "escaped",
-		"exists", "explain", "false", "float", "for", "force", "foreign", "from",
+		"except", "exists", "explain", "false", "float", "for", "force", "foreign", "from",
 		"fulltext", "grant", "group", "having", "hour_microsecond", "hour_minute",
 		"hour_second", "if", "ignore", "in", "index", "infile", "inner", "insert", "int", "into", "integer",
-		"interval", "is", "join", "key", "keys", "kill", "leading", "left", "like", "limit", "lines", "load",
+		"intersect", "interval", "is", "join", "key", "keys", "kill", "leading", "left", "like", "limit", "lines", "load",
 		"localtime", "localtimestamp", "lock", "longblob", "longtext", "mediumblob", "maxvalue", "mediumint", "mediumtext",
 		"minute_microsecond", "minute_second", "mod", "not", "no_write_to_binlog", "null", "numeric",
 		"on", "option", "optionally", "or", "order", "outer", "partition", "precision", "primary", "procedure", "range", "read", "real",
-		"references", "regexp", "rename", "repeat", "replace", "revoke", "restrict", "right", "rlike",
+		"recursive", "references", "regexp", "rename", "repeat", "replace", "revoke", "restrict", "right", "rlike",
 		"schema", "schemas", "second_microsecond", "select", "set", "show", "smallint",
 		"starting", "table", "terminated", "then", "tinyblob", "tinyint", "tinytext", "to",
 		"trailing", "true", "union", "unique", "unlock", "unsigned",
@@ -350,6 +351,8 @@ func (s *testParserSuite) TestDMLStmt(c *C) {
 		{"REPLACE INTO foo () VALUES ()", true, "REPLACE INTO `foo` () VALUES ()"},
 		{"REPLACE INTO foo VALUE ()", true, "REPLACE INTO `foo` VALUES ()"},
 		{"BEGIN", true, "START TRANSACTION"},
+		{"BEGIN PESSIMISTIC", true, "BEGIN PESSIMISTIC"},
+		{"BEGIN OPTIMISTIC", true, "BEGIN OPTIMISTIC"},
 		// 45
 		{"COMMIT", true, "COMMIT"},
 		{"ROLLBACK", true, "ROLLBACK"},
@@ -392,6 +395,65 @@ func (s *testParserSuite) TestDMLStmt(c *C) {
 		{"DELETE t1, t2 FROM t1 INNER JOIN t2 INNER JOIN t3 WHERE t1.id=t2.id AND t2.id=t3.id limit 10;", false, ""},
 		{"DELETE t1, t2 FROM t1 INNER JOIN t2 INNER JOIN t3 WHERE t1.id=t2.id AND t2.id=t3.id order by t1.id;", false, ""},
 
+		// update statement
+		// single table syntax
+		{"UPDATE t1 SET a = 1", true, "UPDATE `t1` SET `a`=1"},
+		{"UPDATE LOW_PRIORITY t1 SET a = 1", true, "UPDATE LOW_PRIORITY `t1` SET `a`=1"},
+		{"UPDATE t1 SET a = a + 1, b = default WHERE a > 0", true, "UPDATE `t1` SET `a`=`a`+1,`b`=DEFAULT WHERE `a`>0"},
+		{"UPDATE t1 AS w SET w.a = 1 WHERE w.b = 2 ORDER BY w.a LIMIT 10", true, "UPDATE `t1` AS `w` SET `w`.`a`=1 WHERE `w`.`b`=2 ORDER BY `w`.`a` LIMIT 10"},
+		{"UPDATE /*+ HASH_JOIN(t1) */ t1 SET a = 1", true, "UPDATE /*+ HASH_JOIN(`t1`)*/ `t1` SET `a`=1"},
+		{"UPDATE t1 SET", false, ""},
+		{"UPDATE t1 a = 1", false, ""},
+		// multiple table syntax
+		{"UPDATE t1, t2 SET t1.a = t2.a WHERE t1.id = t2.id", true, "UPDATE (`t1`) JOIN `t2` SET `t1`.`a`=`t2`.`a` WHERE `t1`.`id`=`t2`.`id`"},
+		{"UPDATE t1 JOIN t2 ON t1.id = t2.id SET t1.a = 1, t2.b = 2", true, "UPDATE `t1` JOIN `t2` ON `t1`.`id`=`t2`.`id` SET `t1`.`a`=1,`t2`.`b`=2"},
+		{"UPDATE t1, t2 SET t1.a = 1 ORDER BY t1.a", false, ""},
+		{"UPDATE t1, t2 SET t1.a = 1 LIMIT 1", false, ""},
+
+		// set operation statement
+		{"select 1 union select 2", true, "SELECT 1 UNION SELECT 2"},
+		{"select 1 union all select 2", true, "SELECT 1 UNION ALL SELECT 2"},
+		{"select 1 union distinct select 2", true, "SELECT 1 UNION SELECT 2"},
+		{"select a from t1 union select b from t2 order by a limit 1", true, "SELECT `a` FROM `t1` UNION SELECT `b` FROM `t2` ORDER BY `a` LIMIT 1"},
+		{"(select a from t1 order by a limit 1) union all (select b from t2 order by b limit 2) order by a", true, "(SELECT `a` FROM `t1` ORDER BY `a` LIMIT 1) UNION ALL (SELECT `b` FROM `t2` ORDER BY `b` LIMIT 2) ORDER BY `a`"},
+		{"select 1 from dual union select 2 from dual", true, "SELECT 1 UNION SELECT 2"},
+		{"select a from t1 intersect select b from t2", true, "SELECT `a` FROM `t1` INTERSECT SELECT `b` FROM `t2`"},
+		{"select a from t1 except select b from t2 except select c from t3", true, "SELECT `a` FROM `t1` EXCEPT SELECT `b` FROM `t2` EXCEPT SELECT `c` FROM `t3`"},
+		{"select a from t1 union select b from t2 intersect select c from t3", true, "SELECT `a` FROM `t1` UNION SELECT `b` FROM `t2` INTERSECT SELECT `c` FROM `t3`"},
+		{"select * from (select 1 union select 2) as t", true, "SELECT * FROM (SELECT 1 UNION SELECT 2) AS `t`"},
+		{"select * from t where a in (select 1 union select 2)", true, "SELECT * FROM `t` WHERE `a` IN (SELECT 1 UNION SELECT 2)"},
+		{"insert into t select a from t1 union select b from t2", true, "INSERT INTO `t` SELECT `a` FROM `t1` UNION SELECT `b` FROM `t2`"},
+		{"insert into t (a) select a from t1 union all select b from t2", true, "INSERT INTO `t` (`a`) SELECT `a` FROM `t1` UNION ALL SELECT `b` FROM `t2`"},
+		{"select 1 union", false, ""},
+		{"select 1 union all", false, ""},
+		{"select 1 intersect all select 2", false, ""},
+
+		// window functions
+		{"select row_number() over (partition by a order by b) from t", true, ""},
+		{"select rank() over w, dense_rank() over w from t window w as (order by a)", true, ""},
+		{"select lead(a) over (order by a), lag(a, 2, 0) over (order by a) from t", true, ""},
+		{"select first_value(a) over (partition by b order by a rows between unbounded preceding and current row) from t", true, ""},
+		{"select sum(a) over (order by a rows 2 preceding), avg(a) over (order by a range between 1 preceding and 1 following) from t", true, ""},
+		{"select count(*) over (w order by b rows between current row and unbounded following) from t window w as (partition by a)", true, ""},
+		{"select max(a) over (), min(a) over () from t", true, ""},
+		{"select row_number() from t", false, ""},
+		{"select rank() over (rows) from t", false, ""},
+		{"select lead(a, b) over () from t", false, ""},
+		{"select a from t window w", false, ""},
+
+		// common table expressions
+		{"with cte as (select 1) select * from cte", true, ""},
+		{"with cte(a, b) as (select 1, 2), cte2 as (select a from cte) select * from cte join cte2", true, ""},
+		{"with recursive cte(n) as (select 1 union all select n + 1 from cte where n < 10) select * from cte", true, ""},
+		{"with cte as (select 1 union select 2) select * from cte union select 3", true, ""},
+		{"select * from t where a in (with cte as (select 1) select * from cte)", true, ""},
+		{"select * from (with cte as (select 1) select * from cte) as t", true, ""},
+		{"insert into t with cte as (select 1) select * from cte", true, ""},
+		{"explain with cte as (select 1) select * from cte", true, ""},
+		{"with cte as select 1 select * from cte", false, ""},
+		{"with cte() as (select 1) select * from cte", false, ""},
+		{"with recursive select 1", false, ""},
+
 		// for admin
 		{"admin show ddl;", true, "ADMIN SHOW DDL"},
 		{"admin show ddl jobs;", true, "ADMIN SHOW DDL JOBS"},
@@ -406,6 +468,12 @@ func (s *testParserSuite) TestDMLStmt(c *C) {
 		// for select with where clause
 		{"SELECT * FROM t WHERE 1 = 1", true, "SELECT * FROM `t` WHERE 1=1"},
 
+		// for select lock
+		{"select * from t for update", true, "SELECT * FROM `t` FOR UPDATE"},
+		{"select * from t where a = 1 limit 1 for update nowait", true, "SELECT * FROM `t` WHERE `a`=1 LIMIT 1 FOR UPDATE NOWAIT"},
+		{"select 1 for update", true, "SELECT 1 FOR UPDATE"},
+		{"select * from t for update wait", false, ""},
+
 		// for dual
 		{"select 1 from dual", true, "SELECT 1"},
 		{"select 1 from dual limit 1", true, "SELECT 1 LIMIT 1"},
@@ -422,6 +490,11 @@ func (s *testParserSuite) TestDMLStmt(c *C) {
 		{`/* 20180417 **/ show databases;`, true, "SHOW DATABASES"},
 		{`/** 20180417 */ show databases;`, true, "SHOW DATABASES"},
 		{`/** 20180417 ******/ show databases;`, true, "SHOW DATABASES"},
+
+		// for show status
+		{"show status", true, "SHOW SESSION STATUS"},
+		{"show global status", true, "SHOW GLOBAL STATUS"},
+		{"show status where variable_name = 'a'", true, "SHOW SESSION STATUS WHERE `variable_name`=_UTF8MB4'a'"},
 	}
 	s.RunTest(c, table)
 }
@@ -446,6 +519,12 @@ func (s *testParserSuite) TestSetVariable(c *C) {
 
 		// Set user defined variable xx.xx
 		{"set @xx.xx = 666", "xx.xx", false, false},
+
+		// Set transaction characteristics.
+		{"set transaction isolation level read committed", "tx_isolation_one_shot", false, true},
+		{"set session transaction isolation level repeatable read", "tx_isolation", false, true},
+		{"set global transaction isolation level serializable", "tx_isolation", true, true},
+		{"set transaction read only", "tx_read_only", false, true},
 	}
 
 	parser := parser.New()
@@ -465,6 +544,16 @@ func (s *testParserSuite) TestSetVariable(c *C) {
 
 	_, err := parser.ParseOneStmt("set xx.xx.xx = 666", "", "")
 	c.Assert(err, NotNil)
+
+	stmt, err := parser.ParseOneStmt("set transaction read write, isolation level read uncommitted", "", "")
+	c.Assert(err, IsNil)
+	setStmt := stmt.(*ast.SetStmt)
+	c.Assert(setStmt.Variables, HasLen, 2)
+	c.Assert(setStmt.Variables[0].Name, Equals, "tx_read_only")
+	c.Assert(setStmt.Variables[1].Name, Equals, "tx_isolation_one_shot")
+	c.Assert(setStmt.Variables[1].Value.(ast.ValueExpr).GetValue(), Equals, ast.ReadUncommitted)
+	_, err = parser.ParseOneStmt("set transaction isolation level read", "", "")
+	c.Assert(err, NotNil)
 }
 
 func (s *testParserSuite) TestExpression(c *C) {
@@ -510,6 +599,43 @@ func (s *testParserSuite) TestExpression(c *C) {
 		{"select {ts123 '1989-09-10 11:11:11'}", true, "SELECT '1989-09-10 11:11:11'"},
 		{"select {ts123 123}", true, "SELECT 123"},
 		{"select {ts123 1 xor 1}", true, "SELECT 1 XOR 1"},
+
+		// for subquery
+		{"select (select 1)", true, "SELECT (SELECT 1)"},
+		{"select * from t where a = (select max(a) from t1)", true, "SELECT * FROM `t` WHERE `a`=(SELECT MAX(`a`) FROM `t1`)"},
+		{"select * from t where a in (select a from t1)", true, "SELECT * FROM `t` WHERE `a` IN (SELECT `a` FROM `t1`)"},
+		{"select * from t where a not in (select a from t1 where t1.b = t.b)", true, "SELECT * FROM `t` WHERE `a` NOT IN (SELECT `a` FROM `t1` WHERE `t1`.`b`=`t`.`b`)"},
+		{"select exists (select 1)", true, "SELECT EXISTS (SELECT 1)"},
+		{"select * from t where not exists (select * from t1 where t1.a = t.a)", true, "SELECT * FROM `t` WHERE NOT EXISTS (SELECT * FROM `t1` WHERE `t1`.`a`=`t`.`a`)"},
+		{"select * from t where a > any (select a from t1)", true, "SELECT * FROM `t` WHERE `a`>ANY (SELECT `a` FROM `t1`)"},
+		{"select * from t where a = some (select a from t1)", true, "SELECT * FROM `t` WHERE `a`=ANY (SELECT `a` FROM `t1`)"},
+		{"select * from t where a <= all (select a from t1)", true, "SELECT * FROM `t` WHERE `a`<=ALL (SELECT `a` FROM `t1`)"},
+		{"select * from t where a > all (1, 2)", false, ""},
+		{"select exists 1", false, ""},
+
+		// for json operators
+		{"select a->'$.b' from t", true, "SELECT JSON_EXTRACT(`a`, '$.b') FROM `t`"},
+		{"select t.a->>'$[0]' from t", true, "SELECT JSON_UNQUOTE(JSON_EXTRACT(`t`.`a`, '$[0]')) FROM `t`"},
+		{"select * from t where a->'$.b' = 1", true, "SELECT * FROM `t` WHERE JSON_EXTRACT(`a`, '$.b')=1"},
+		{"select a->b from t", false, ""},
+		{"select cast('[1]' as json)", true, "SELECT CAST('[1]' AS JSON)"},
+		{"select cast(a as signed), cast(b as char(10)) from t", true, "SELECT CAST(`a` AS SIGNED),CAST(`b` AS CHAR(10)) FROM `t`"},
+		{"select cast(a) from t", false, ""},
+
+		// for case expression
+		{"select case a when 1 then 'x' when 2 then 'y' else 'z' end from t", true, "SELECT CASE `a` WHEN 1 THEN 'x' WHEN 2 THEN 'y' ELSE 'z' END FROM `t`"},
+		{"select case when a > 1 then b end from t", true, "SELECT CASE WHEN `a`>1 THEN `b` END FROM `t`"},
+		{"select case when a is null then 1 when a between 1 and 2 then 2 else 3 end", true, "SELECT CASE WHEN `a` IS NULL THEN 1 WHEN `a` BETWEEN 1 AND 2 THEN 2 ELSE 3 END"},
+		{"select case a else 1 end", false, ""},
+		{"select case when 1 then 2", false, ""},
+
+		// for like and regexp
+		{"select * from t where a like 'abc%'", true, "SELECT * FROM `t` WHERE `a` LIKE 'abc%'"},
+		{"select * from t where a not like '_b%' escape '|'", true, "SELECT * FROM `t` WHERE `a` NOT LIKE '_b%' ESCAPE '|'"},
+		{"select * from t where a like 'abc' escape ''", true, "SELECT * FROM `t` WHERE `a` LIKE 'abc'"},
+		{"select * from t where a like 'abc' escape '||'", false, ""},
+		{"select * from t where a regexp '^a.*'", true, "SELECT * FROM `t` WHERE `a` REGEXP '^a.*'"},
+		{"select * from t where a not rlike 'b$'", true, "SELECT * FROM `t` WHERE `a` NOT REGEXP 'b$'"},
 	}
 	s.RunTest(c, table)
 }
@@ -523,6 +649,9 @@ func (s *testParserSuite) TestBuiltin(c *C) {
 		{"SELECT POW(1, -1)", true, "SELECT POW(1, -1)"},
 		{"SELECT POW(-1, 1)", true, "SELECT POW(-1, 1)"},
 		{"SELECT RAND();", true, "SELECT RAND()"},
+		{"SELECT COALESCE(NULL, 1, 2);", true, "SELECT COALESCE(NULL, 1, 2)"},
+		{"SELECT GREATEST(1, 2, 3), LEAST('a', 'b');", true, "SELECT GREATEST(1, 2, 3),LEAST('a', 'b')"},
+		{"SELECT NULLIF(1, 1);", true, "SELECT NULLIF(1, 1)"},
 		{"SELECT RAND(1);", true, "SELECT RAND(1)"},
 		{"SELECT MOD(10, 2);", true, "SELECT 10%2"},
 		{"SELECT ROUND(-1.23);", true, "SELECT ROUND(-1.23)"},
@@ -721,6 +850,29 @@ func (s *testParserSuite) TestBuiltin(c *C) {
 		{"SELECT UTC_DATE, UTC_DATE();", true, "SELECT UTC_DATE(),UTC_DATE()"},
 		{"SELECT UTC_DATE(), UTC_DATE()+0", true, "SELECT UTC_DATE(),UTC_DATE()+0"},
 
+		// for date_add, date_sub, adddate, subdate
+		{"SELECT DATE_ADD('2008-01-02', INTERVAL 31 DAY);", true, "SELECT DATE_ADD('2008-01-02', INTERVAL 31 DAY)"},
+		{"SELECT DATE_SUB('2008-01-02 10:00:00', INTERVAL '1 2:3:4' DAY_SECOND);", true, "SELECT DATE_SUB('2008-01-02 10:00:00', INTERVAL '1 2:3:4' DAY_SECOND)"},
+		{"SELECT DATE_ADD('2008-01-02', INTERVAL 1 SQL_TSI_MONTH);", false, ""},
+		{"SELECT DATE_ADD('2008-01-02', 31);", false, ""},
+		{"SELECT ADDDATE('2008-01-02', 31), SUBDATE('2008-01-02', 31);", true, "SELECT ADDDATE('2008-01-02', INTERVAL 31 DAY),SUBDATE('2008-01-02', INTERVAL 31 DAY)"},
+		{"SELECT ADDDATE('2008-01-02', INTERVAL '1-2' YEAR_MONTH);", true, "SELECT ADDDATE('2008-01-02', INTERVAL '1-2' YEAR_MONTH)"},
+		{"SELECT '2008-01-02' + INTERVAL 1 HOUR, '2008-01-02' - INTERVAL 1 WEEK;", true, "SELECT DATE_ADD('2008-01-02', INTERVAL 1 HOUR),DATE_SUB('2008-01-02', INTERVAL 1 WEEK)"},
+		{"SELECT INTERVAL(1, 2, 3) + 1;", true, "SELECT INTERVAL(1, 2, 3)+1"},
+
+		// for extract and timestampdiff
+		{"SELECT EXTRACT(YEAR_MONTH FROM '2009-07-02 01:02:03');", true, "SELECT EXTRACT(YEAR_MONTH FROM '2009-07-02 01:02:03')"},
+		{"SELECT EXTRACT(MICROSECOND FROM '2009-07-02 01:02:03.000123');", true, "SELECT EXTRACT(MICROSECOND FROM '2009-07-02 01:02:03.000123')"},
+		{"SELECT EXTRACT(YEAR, '2009-07-02');", false, ""},
+		{"SELECT TIMESTAMPDIFF(MONTH, '2003-02-01', '2003-05-01');", true, "SELECT TIMESTAMPDIFF(MONTH, '2003-02-01', '2003-05-01')"},
+		{"SELECT TIMESTAMPDIFF(SQL_TSI_MINUTE, '2003-02-01', '2003-05-01 12:05:55');", true, "SELECT TIMESTAMPDIFF(MINUTE, '2003-02-01', '2003-05-01 12:05:55')"},
+		{"SELECT TIMESTAMPDIFF(DAY_HOUR, '2003-02-01', '2003-05-01');", false, ""},
+
+		// for str_to_date, unix_timestamp, from_unixtime
+		{"SELECT STR_TO_DATE('01,5,2013', '%d,%m,%Y');", true, "SELECT STR_TO_DATE('01,5,2013', '%d,%m,%Y')"},
+		{"SELECT UNIX_TIMESTAMP(), UNIX_TIMESTAMP('2015-11-13 10:20:19.012');", true, "SELECT UNIX_TIMESTAMP(),UNIX_TIMESTAMP('2015-11-13 10:20:19.012')"},
+		{"SELECT FROM_UNIXTIME(1447430881, '%Y %D %M');", true, "SELECT FROM_UNIXTIME(1447430881, '%Y %D %M')"},
+
 		// for week, month, year
 		{"SELECT WEEK();", true, "SELECT WEEK()"},
 		{"SELECT WEEK('2007-02-03');", true, "SELECT WEEK('2007-02-03')"},
@@ -863,6 +1015,17 @@ func (s *testParserSuite) TestBuiltin(c *C) {
 		{`select count(c1) from t;`, true, "SELECT COUNT(`c1`) FROM `t`"},
 		{`select count(*) from t;`, true, "SELECT COUNT(1) FROM `t`"},
 		{`select count(c1, c2) from t;`, false, ""},
+		{`select count(distinct c1, c2) from t;`, true, "SELECT COUNT(DISTINCT `c1`, `c2`) FROM `t`"},
+		{`select count(all c1) from t;`, true, "SELECT COUNT(`c1`) FROM `t`"},
+		{`select sum(distinct c2), avg(distinctrow c2), max(all c2) from t;`, true, "SELECT SUM(DISTINCT `c2`),AVG(DISTINCT `c2`),MAX(`c2`) FROM `t`"},
+		{`select bit_and(c1), bit_or(c1), bit_xor(distinct c1) from t;`, true, "SELECT BIT_AND(`c1`),BIT_OR(`c1`),BIT_XOR(DISTINCT `c1`) FROM `t`"},
+		{`select bit_and(c1, c2) from t;`, false, ""},
+		{`select std(c1), stddev(c1), stddev_pop(c1), stddev_samp(c1) from t;`, true, "SELECT STDDEV_POP(`c1`),STDDEV_POP(`c1`),STDDEV_POP(`c1`),STDDEV_SAMP(`c1`) FROM `t`"},
+		{`select variance(c1), var_pop(c1), var_samp(distinct c1) from t;`, true, "SELECT VAR_POP(`c1`),VAR_POP(`c1`),VAR_SAMP(DISTINCT `c1`) FROM `t`"},
+		{`select group_concat(c1) from t;`, true, "SELECT GROUP_CONCAT(`c1` SEPARATOR ',') FROM `t`"},
+		{`select group_concat(distinct c1, c2 order by c1 desc, c2 separator ';') from t;`, true, "SELECT GROUP_CONCAT(DISTINCT `c1`, `c2` ORDER BY `c1` DESC,`c2` SEPARATOR ';') FROM `t`"},
+		{`select group_concat(c1 separator 1) from t;`, false, ""},
+		{`select bit_xor(c1) over (order by c2), var_pop(c1) over w from t window w as (partition by c2);`, true, ""},
 	}
 	s.RunTest(c, table)
 }
@@ -1250,10 +1413,48 @@ func (s *testParserSuite) TestDDL(c *C) {
 		{"create table t (a bigint auto_random primary key, b varchar(255))", true, "CREATE TABLE `t` (`a` BIGINT AUTO_RANDOM PRIMARY KEY,`b` VARCHAR(255))"},
 		{"create table t (a bigint primary key auto_random(4), b varchar(255))", true, "CREATE TABLE `t` (`a` BIGINT PRIMARY KEY AUTO_RANDOM(4),`b` VARCHAR(255))"},
 		{"create table t (a bigint primary key auto_random(3) primary key unique, b varchar(255))", true, "CREATE TABLE `t` (`a` BIGINT PRIMARY KEY AUTO_RANDOM(3) PRIMARY KEY UNIQUE KEY,`b` VARCHAR(255))"},
+
+		// for view
+		{"create view v as select * from t", true, "CREATE ALGORITHM = UNDEFINED DEFINER = CURRENT_USER SQL SECURITY DEFINER VIEW `v` AS SELECT * FROM `t`"},
+		{"create or replace view v (a, b) as select c, d from t", true, "CREATE OR REPLACE ALGORITHM = UNDEFINED DEFINER = CURRENT_USER SQL SECURITY DEFINER VIEW `v` (`a`,`b`) AS SELECT `c`,`d` FROM `t`"},
+		{"create algorithm = merge definer = 'root'@'localhost' sql security invoker view v as select 1", true, "CREATE ALGORITHM = MERGE DEFINER = `root`@`localhost` SQL SECURITY INVOKER VIEW `v` AS SELECT 1"},
+		{"create definer = current_user() view v as select 1 union select 2", true, "CREATE ALGORITHM = UNDEFINED DEFINER = CURRENT_USER SQL SECURITY DEFINER VIEW `v` AS SELECT 1 UNION SELECT 2"},
+		{"create view v as with cte as (select 1) select * from cte with local check option", true, "CREATE ALGORITHM = UNDEFINED DEFINER = CURRENT_USER SQL SECURITY DEFINER VIEW `v` AS WITH `cte` AS (SELECT 1) SELECT * FROM `cte` WITH LOCAL CHECK OPTION"},
+		{"create algorithm = bad view v as select 1", false, ""},
+		{"create view v as select 1 with check option", true, "CREATE ALGORITHM = UNDEFINED DEFINER = CURRENT_USER SQL SECURITY DEFINER VIEW `v` AS SELECT 1 WITH CASCADED CHECK OPTION"},
+		{"create view v", false, ""},
+		{"drop view v", true, "DROP VIEW `v`"},
+		{"drop view if exists v1, v2 cascade", true, "DROP VIEW IF EXISTS `v1`, `v2`"},
+		{"drop view", false, ""},
+		{"show create view v", true, "SHOW CREATE VIEW `v`"},
 	}
 	s.RunTest(c, table)
 }
 
+func (s *testParserSuite) TestCreateView(c *C) {
+	parser := parser.New()
+	src := "create definer = 'u'@'h' view test.v (a) as select c from t  where c > 1 ;"
+	stmt, err := parser.ParseOneStmt(src, "", "")
+	c.Assert(err, IsNil)
+	v := stmt.(*ast.CreateViewStmt)
+	c.Assert(v.ViewName.Schema.L, Equals, "test")
+	c.Assert(v.ViewName.Name.L, Equals, "v")
+	c.Assert(v.Cols, HasLen, 1)
+	c.Assert(v.Definer.Username, Equals, "u")
+	c.Assert(v.Definer.Hostname, Equals, "h")
+	c.Assert(v.Security, Equals, model.SecurityDefiner)
+	c.Assert(v.CheckOption, Equals, model.CheckOptionNone)
+	c.Assert(v.Select.Text(), Equals, "select c from t  where c > 1")
+
+	src = "create view v as select 1 with cascaded check option"
+	stmt, err = parser.ParseOneStmt(src, "", "")
+	c.Assert(err, IsNil)
+	v = stmt.(*ast.CreateViewStmt)
+	c.Assert(v.Definer.CurrentUser, IsTrue)
+	c.Assert(v.CheckOption, Equals, model.CheckOptionCascaded)
+	c.Assert(v.Select.Text(), Equals, "select 1")
+}
+
 func (s *testParserSuite) TestType(c *C) {
 	table := []testCase{
 		// for time fsp
@@ -1330,6 +1531,42 @@ func (s *testParserSuite) TestSQLNoCache(c *C) {
 	}
 }
 
+func (s *testParserSuite) TestSelectLock(c *C) {
+	table := []struct {
+		src    string
+		lockTp ast.SelectLockType
+	}{
+		{"select * from t", ast.SelectLockNone},
+		{"select * from t for update", ast.SelectLockForUpdate},
+		{"select * from t order by a limit 1 for update", ast.SelectLockForUpdate},
+		{"select * from t where a = 1 for update nowait", ast.SelectLockForUpdateNoWait},
+		{"select a from t for update", ast.SelectLockForUpdate},
+	}
+
+	parser := parser.New()
+	for _, tt := range table {
+		stmt, _, err := parser.Parse(tt.src, "", "")
+		c.Assert(err, IsNil)
+
+		sel := stmt[0].(*ast.SelectStmt)
+		c.Assert(sel.LockTp, Equals, tt.lockTp, Commentf("source %v", tt.src))
+	}
+
+	// The lock clause should not be a part of the text of the last field.
+	stmt, _, err := parser.Parse("select 1 + 1 for update", "", "")
+	c.Assert(err, IsNil)
+	sel := stmt[0].(*ast.SelectStmt)
+	c.Assert(sel.LockTp, Equals, ast.SelectLockForUpdate)
+	c.Assert(sel.Fields.Fields[0].Text(), Equals, "1 + 1")
+
+	stmt, _, err = parser.Parse("begin pessimistic", "", "")
+	c.Assert(err, IsNil)
+	c.Assert(stmt[0].(*ast.BeginStmt).Mode, Equals, ast.Pessimistic)
+	stmt, _, err = parser.Parse("begin", "", "")
+	c.Assert(err, IsNil)
+	c.Assert(stmt[0].(*ast.BeginStmt).Mode, Equals, "")
+}
+
 func (s *testParserSuite) TestEscape(c *C) {
 	table := []testCase{
 		{`select """;`, false, ""},
@@ -1368,6 +1605,36 @@ func (s *testParserSuite) TestExplain(c *C) {
 	s.RunTest(c, table)
 }
 
+func (s *testParserSuite) TestPrepare(c *C) {
+	table := []testCase{
+		{"PREPARE pname FROM 'SELECT ?'", true, "PREPARE `pname` FROM 'SELECT ?'"},
+		{"PREPARE pname FROM @test", true, "PREPARE `pname` FROM @`test`"},
+		{"PREPARE `` FROM @test", true, "PREPARE `` FROM @`test`"},
+		{"PREPARE pname FROM 'SELECT ?', 'SELECT 1'", false, ""},
+		{"EXECUTE stmt", true, "EXECUTE `stmt`"},
+		{"EXECUTE stmt USING @a, @b", true, "EXECUTE `stmt` USING @`a`,@`b`"},
+		{"EXECUTE stmt USING 1", false, ""},
+		{"DEALLOCATE PREPARE stmt", true, "DEALLOCATE PREPARE `stmt`"},
+		{"DROP PREPARE stmt", true, "DEALLOCATE PREPARE `stmt`"},
+		{"DEALLOCATE stmt", false, ""},
+		{"select * from t where a = ? and b > ?", true, "SELECT * FROM `t` WHERE `a`=? AND `b`>?"},
+		{"select * from t limit ?, ?", true, "SELECT * FROM `t` LIMIT ?,?"},
+		{"insert into t values (?, ?)", true, "INSERT INTO `t` VALUES (?,?)"},
+		{"update t set a = ? where b = ?", true, "UPDATE `t` SET `a`=? WHERE `b`=?"},
+	}
+	s.RunTest(c, table)
+
+	parser := parser.New()
+	stmt, err := parser.ParseOneStmt("select ? from t where a = ?", "", "")
+	c.Assert(err, IsNil)
+	fields := stmt.(*ast.SelectStmt).Fields.Fields
+	marker, ok := fields[0].Expr.(ast.ParamMarkerExpr)
+	c.Assert(ok, IsTrue)
+	c.Assert(marker.(*driver.ParamMarkerExpr).Offset, Equals, 7)
+	where := stmt.(*ast.SelectStmt).Where.(*ast.BinaryOperationExpr)
+	c.Assert(where.R.(*driver.ParamMarkerExpr).Offset, Equals, 26)
+}
+
 func (s *testParserSuite) TestSQLModeANSIQuotes(c *C) {
 	parser := parser.New()
 	parser.SetSQLMode(mysql.ModeANSIQuotes)
@@ -0,0 +1,96 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"github.com/pingcap/tidb/parser/ast"
+	driver "github.com/pingcap/tidb/types/parser_driver"
+)
+
+// Cacheable checks whether the input ast is cacheable.
+// The plan of a cacheable statement must only depend on the values of its
+// parameters through expressions that are evaluated at execution time.
+func Cacheable(node ast.Node) bool {
+	switch node.(type) {
+	case *ast.SelectStmt, *ast.UpdateStmt, *ast.DeleteStmt:
+	default:
+		return false
+	}
+	checker := cacheableChecker{
+		cacheable: true,
+	}
+	node.Accept(&checker)
+	return checker.cacheable
+}
+
+// cacheableChecker checks whether a query's plan can be cached. Queries that
+// have subqueries or VariableExpr, or use parameters in ORDER BY, GROUP BY,
+// LIMIT, window frame bounds or LIKE patterns, will not be cached currently.
+// NOTE: we can add more rules in the future.
+type cacheableChecker struct {
+	cacheable bool
+}
+
+// Enter implements Visitor interface.
+func (checker *cacheableChecker) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
+	switch node := in.(type) {
+	case *ast.VariableExpr, *ast.ExistsSubqueryExpr, *ast.SubqueryExpr:
+		checker.cacheable = false
+		return in, true
+	case *ast.OrderByClause:
+		for _, item := range node.Items {
+			if _, isParamMarker := item.Expr.(*driver.ParamMarkerExpr); isParamMarker {
+				checker.cacheable = false
+				return in, true
+			}
+		}
+	case *ast.GroupByClause:
+		for _, item := range node.Items {
+			if _, isParamMarker := item.Expr.(*driver.ParamMarkerExpr); isParamMarker {
+				checker.cacheable = false
+				return in, true
+			}
+		}
+	case *ast.Limit:
+		if node.Count != nil {
+			if _, isParamMarker := node.Count.(*driver.ParamMarkerExpr); isParamMarker {
+				checker.cacheable = false
+				return in, true
+			}
+		}
+		if node.Offset != nil {
+			if _, isParamMarker := node.Offset.(*driver.ParamMarkerExpr); isParamMarker {
+				checker.cacheable = false
+				return in, true
+			}
+		}
+	case *ast.PatternLikeExpr:
+		// The plan is built from the pattern value, e.g. the index ranges of a prefix pattern.
+		if _, isParamMarker := node.Pattern.(*driver.ParamMarkerExpr); isParamMarker {
+			checker.cacheable = false
+			return in, true
+		}
+	case *ast.FrameBound:
+		if _, isParamMarker := node.Expr.(*driver.ParamMarkerExpr); isParamMarker {
+			checker.cacheable = false
+			return in, true
+		}
+	}
+	return in, false
+}
+
+// Leave implements Visitor interface.
+func (checker *cacheableChecker) Leave(in ast.Node) (out ast.Node, ok bool) {
+	return in, checker.cacheable
+}
@@ -15,17 +15,24 @@ package core
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/types"
+	driver "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/kvcache"
+	"github.com/pingcap/tidb/util/ranger"
 )
 
 // ShowDDL is for showing DDL information.
@@ -33,6 +40,233 @@ type ShowDDL struct {
 	baseSchemaProducer
 }
 
+// Prepare represents prepare plan.
+type Prepare struct {
+	baseSchemaProducer
+
+	Name    string
+	SQLText string
+}
+
+// Execute represents prepare plan.
+type Execute struct {
+	baseSchemaProducer
+
+	Name          string
+	UsingVars     []expression.Expression
+	PrepareParams []types.Datum
+	ExecID        uint32
+	Stmt          ast.StmtNode
+	Plan          Plan
+}
+
+// OptimizePreparedPlan optimizes the prepared statement.
+func (e *Execute) OptimizePreparedPlan(ctx context.Context, sctx sessionctx.Context, is infoschema.InfoSchema) error {
+	vars := sctx.GetSessionVars()
+	if e.Name != "" {
+		e.ExecID = vars.PreparedStmtNameToID[e.Name]
+	}
+	prepared, ok := vars.PreparedStmts[e.ExecID]
+	if !ok {
+		return errors.Trace(ErrStmtNotFound)
+	}
+
+	paramLen := len(e.PrepareParams)
+	if paramLen > 0 {
+		// For the binary protocol, the arguments are decoded by the server.
+		if len(prepared.Params) != paramLen {
+			return errors.Trace(ErrWrongParamCount)
+		}
+		vars.PreparedParams = e.PrepareParams
+	} else {
+		// For `execute stmt using @a, @b`, the arguments are the user variables.
+		if len(prepared.Params) != len(e.UsingVars) {
+			return errors.Trace(ErrWrongParamCount)
+		}
+		for _, usingVar := range e.UsingVars {
+			val, err := usingVar.Eval(chunk.Row{})
+			if err != nil {
+				return err
+			}
+			vars.PreparedParams = append(vars.PreparedParams, val)
+		}
+	}
+	for i, val := range vars.PreparedParams {
+		param := prepared.Params[i].(*driver.ParamMarkerExpr)
+		param.Datum = val
+		param.InExecute = true
+	}
+
+	if prepared.SchemaVersion != is.SchemaMetaVersion() {
+		// If the schema version has changed we need to preprocess it again,
+		// if this time it failed, the real reason for the error is schema changed.
+		err := Preprocess(sctx, prepared.Stmt, is, InPrepare)
+		if err != nil {
+			return ErrSchemaChanged.GenWithStack("Schema change caused error: %s", err.Error())
+		}
+		// The plan cached for the old schema can never be hit again.
+		if prepared.UseCache {
+			sctx.PreparedPlanCache().Delete(NewPSTMTPlanCacheKey(vars, e.ExecID, prepared.SchemaVersion))
+		}
+		prepared.SchemaVersion = is.SchemaMetaVersion()
+	}
+	return e.getPhysicalPlan(ctx, sctx, is, prepared)
+}
+
+func (e *Execute) getPhysicalPlan(ctx context.Context, sctx sessionctx.Context, is infoschema.InfoSchema, prepared *ast.Prepared) error {
+	var cacheKey kvcache.Key
+	vars := sctx.GetSessionVars()
+	vars.StmtCtx.UseCache = prepared.UseCache
+	paramTypes := getParamTypes(vars.PreparedParams)
+	if prepared.UseCache {
+		cacheKey = NewPSTMTPlanCacheKey(vars, e.ExecID, prepared.SchemaVersion)
+		if cacheValue, exists := sctx.PreparedPlanCache().Get(cacheKey); exists {
+			cachedVal := cacheValue.(*PSTMTPlanCacheValue)
+			if matchParamTypes(cachedVal.ParamTypes, paramTypes) {
+				if err := e.rebuildRange(cachedVal.Plan); err != nil {
+					return err
+				}
+				e.names = cachedVal.OutPutNames
+				e.Stmt = prepared.Stmt
+				e.Plan = cachedVal.Plan
+				vars.FoundInPlanCache = true
+				return nil
+			}
+		}
+	}
+	p, names, err := OptimizeAstNode(ctx, sctx, prepared.Stmt, is)
+	if err != nil {
+		return err
+	}
+	e.names = names
+	e.Stmt = prepared.Stmt
+	e.Plan = p
+	if prepared.UseCache {
+		sctx.PreparedPlanCache().Put(cacheKey, NewPSTMTPlanCacheValue(p, names, paramTypes))
+	}
+	return nil
+}
+
+// rebuildRange rebuilds the ranges of the scans in a cached plan, since they
+// are calculated from the parameters of the last execution.
+func (e *Execute) rebuildRange(p Plan) error {
+	sctx := p.SCtx()
+	sc := sctx.GetSessionVars().StmtCtx
+	var err error
+	switch x := p.(type) {
+	case *PhysicalTableReader:
+		ts := x.TablePlans[0].(*PhysicalTableScan)
+		if len(ts.AccessCondition) == 0 {
+			break
+		}
+		tp := types.NewFieldType(mysql.TypeLonglong)
+		if ts.Table.PKIsHandle {
+			if pkColInfo := ts.Table.GetPkColInfo(); pkColInfo != nil {
+				tp = &pkColInfo.FieldType
+			}
+		}
+		ts.Ranges, err = ranger.BuildTableRange(ts.AccessCondition, sc, tp)
+		if err != nil {
+			return err
+		}
+	case *PhysicalIndexReader:
+		is := x.IndexPlans[0].(*PhysicalIndexScan)
+		is.Ranges, err = e.buildRangeForIndexScan(sctx, is)
+		if err != nil {
+			return err
+		}
+	case *PhysicalIndexLookUpReader:
+		is := x.IndexPlans[0].(*PhysicalIndexScan)
+		is.Ranges, err = e.buildRangeForIndexScan(sctx, is)
+		if err != nil {
+			return err
+		}
+	case *PointGetPlan:
+		if x.HandleParam != nil {
+			var ok bool
+			x.Handle, ok, err = convertPointGetHandle(sc, x.HandleParam.Datum, getHandleFieldType(x.TblInfo))
+			if err != nil {
+				return err
+			}
+			// No row can match the handle, the plan returns an empty result.
+			x.IsTableDual = !ok
+		}
+		for i, param := range x.IndexValueParams {
+			if param != nil {
+				x.IndexValues[i] = param.Datum
+			}
+		}
+	case *BatchPointGetPlan:
+		x.InvalidHandles = nil
+		for i, param := range x.HandleParams {
+			if param == nil {
+				continue
+			}
+			var ok bool
+			x.Handles[i], ok, err = convertPointGetHandle(sc, param.Datum, getHandleFieldType(x.TblInfo))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				if x.InvalidHandles == nil {
+					x.InvalidHandles = make([]bool, len(x.Handles))
+				}
+				x.InvalidHandles[i] = true
+			}
+		}
+		for i, params := range x.IndexValueParams {
+			for j, param := range params {
+				if param != nil {
+					x.IndexValues[i][j] = param.Datum
+				}
+			}
+		}
+	case *PhysicalCTE:
+		if err = e.rebuildRange(x.SeedPlan); err != nil {
+			return err
+		}
+		if x.RecurPlan != nil {
+			if err = e.rebuildRange(x.RecurPlan); err != nil {
+				return err
+			}
+		}
+	case *Update:
+		if x.SelectPlan != nil {
+			return e.rebuildRange(x.SelectPlan)
+		}
+	case *Delete:
+		if x.SelectPlan != nil {
+			return e.rebuildRange(x.SelectPlan)
+		}
+	}
+	if pp, ok := p.(PhysicalPlan); ok {
+		for _, child := range pp.Children() {
+			if err = e.rebuildRange(child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *Execute) buildRangeForIndexScan(sctx sessionctx.Context, is *PhysicalIndexScan) ([]*ranger.Range, error) {
+	if len(is.AccessCondition) == 0 {
+		return is.Ranges, nil
+	}
+	res, err := ranger.DetachCondAndBuildRangeForIndex(sctx, is.AccessCondition, is.IdxCols, is.IdxColLens)
+	if err != nil {
+		return nil, err
+	}
+	return res.Ranges, nil
+}
+
+// Deallocate represents deallocate plan.
+type Deallocate struct {
+	baseSchemaProducer
+
+	Name string
+}
+
 // Set represents a plan for set stmt.
 type Set struct {
 	baseSchemaProducer
@@ -68,6 +302,17 @@ type Insert struct {
 	AllAssignmentsAreConstant bool
 }
 
+// Update represents Update plan.
+type Update struct {
+	baseSchemaProducer
+
+	OrderedList []*expression.Assignment
+
+	SelectPlan PhysicalPlan
+
+	TblColPosInfos TblColPosInfoSlice
+}
+
 // Delete represents a delete plan.
 type Delete struct {
 	baseSchemaProducer
@@ -205,10 +450,23 @@ func (e *Explain) explainPlanInRowFormat(p Plan, taskType, indent string, isLast
 		if x.SelectPlan != nil {
 			err = e.explainPlanInRowFormat(x.SelectPlan, "root", childIndent, true)
 		}
+	case *Update:
+		if x.SelectPlan != nil {
+			err = e.explainPlanInRowFormat(x.SelectPlan, "root", childIndent, true)
+		}
 	case *Delete:
 		if x.SelectPlan != nil {
 			err = e.explainPlanInRowFormat(x.SelectPlan, "root", childIndent, true)
 		}
+	case *PhysicalCTE:
+		// The seed part and the recursive part are shared by all the references,
+		// they are only explained under the first one.
+		if !e.explainedPlans[x.SeedPlan.ID()] {
+			err = e.explainPlanInRowFormat(x.SeedPlan, "root", childIndent, x.RecurPlan == nil)
+		}
+		if err == nil && x.RecurPlan != nil && !e.explainedPlans[x.RecurPlan.ID()] {
+			err = e.explainPlanInRowFormat(x.RecurPlan, "root", childIndent, true)
+		}
 	}
 	return
 }
@@ -61,8 +61,29 @@ var (
 	ErrCartesianProductUnsupported     = terror.ClassOptimizer.New(mysql.ErrCartesianProductUnsupported, mysql.MySQLErrName[mysql.ErrCartesianProductUnsupported])
 	ErrStmtNotFound                    = terror.ClassOptimizer.New(mysql.ErrPreparedStmtNotFound, mysql.MySQLErrName[mysql.ErrPreparedStmtNotFound])
 	ErrAmbiguous                       = terror.ClassOptimizer.New(mysql.ErrNonUniq, mysql.MySQLErrName[mysql.ErrNonUniq])
+	ErrWindowInvalidWindowFuncUse      = terror.ClassOptimizer.New(mysql.ErrWindowInvalidWindowFuncUse, mysql.MySQLErrName[mysql.ErrWindowInvalidWindowFuncUse])
+	ErrWindowInvalidWindowFuncAliasUse = terror.ClassOptimizer.New(mysql.ErrWindowInvalidWindowFuncAliasUse, mysql.MySQLErrName[mysql.ErrWindowInvalidWindowFuncAliasUse])
+	ErrWindowNoSuchWindow              = terror.ClassOptimizer.New(mysql.ErrWindowNoSuchWindow, mysql.MySQLErrName[mysql.ErrWindowNoSuchWindow])
+	ErrWindowCircularityInWindowGraph  = terror.ClassOptimizer.New(mysql.ErrWindowCircularityInWindowGraph, mysql.MySQLErrName[mysql.ErrWindowCircularityInWindowGraph])
+	ErrWindowNoChildPartitioning       = terror.ClassOptimizer.New(mysql.ErrWindowNoChildPartitioning, mysql.MySQLErrName[mysql.ErrWindowNoChildPartitioning])
+	ErrWindowNoInherentFrame           = terror.ClassOptimizer.New(mysql.ErrWindowNoInherentFrame, mysql.MySQLErrName[mysql.ErrWindowNoInherentFrame])
+	ErrWindowNoRedefineOrderBy         = terror.ClassOptimizer.New(mysql.ErrWindowNoRedefineOrderBy, mysql.MySQLErrName[mysql.ErrWindowNoRedefineOrderBy])
+	ErrWindowDuplicateName             = terror.ClassOptimizer.New(mysql.ErrWindowDuplicateName, mysql.MySQLErrName[mysql.ErrWindowDuplicateName])
+	ErrWindowFrameStartIllegal         = terror.ClassOptimizer.New(mysql.ErrWindowFrameStartIllegal, mysql.MySQLErrName[mysql.ErrWindowFrameStartIllegal])
+	ErrWindowFrameEndIllegal           = terror.ClassOptimizer.New(mysql.ErrWindowFrameEndIllegal, mysql.MySQLErrName[mysql.ErrWindowFrameEndIllegal])
+	ErrWindowFrameIllegal              = terror.ClassOptimizer.New(mysql.ErrWindowFrameIllegal, mysql.MySQLErrName[mysql.ErrWindowFrameIllegal])
+	ErrWindowRangeFrameOrderType       = terror.ClassOptimizer.New(mysql.ErrWindowRangeFrameOrderType, mysql.MySQLErrName[mysql.ErrWindowRangeFrameOrderType])
+	ErrWindowRangeBoundNotConstant     = terror.ClassOptimizer.New(mysql.ErrWindowRangeBoundNotConstant, mysql.MySQLErrName[mysql.ErrWindowRangeBoundNotConstant])
+	ErrWindowFunctionIgnoresFrame      = terror.ClassOptimizer.New(mysql.ErrWindowFunctionIgnoresFrame, mysql.MySQLErrName[mysql.ErrWindowFunctionIgnoresFrame])
 	// Since we cannot know if user loggined with a password, use message of ErrAccessDeniedNoPassword instead
 	ErrAccessDenied = terror.ClassOptimizer.New(mysql.ErrAccessDenied, mysql.MySQLErrName[mysql.ErrAccessDeniedNoPassword])
+
+	ErrViewWrongList                  = terror.ClassOptimizer.New(mysql.ErrViewWrongList, mysql.MySQLErrName[mysql.ErrViewWrongList])
+	ErrCTERecursiveRequiresUnion      = terror.ClassOptimizer.New(mysql.ErrCTERecursiveRequiresUnion, mysql.MySQLErrName[mysql.ErrCTERecursiveRequiresUnion])
+	ErrCTERecursiveNonRecursiveFirst  = terror.ClassOptimizer.New(mysql.ErrCTERecursiveRequiresNonRecursiveFirst, mysql.MySQLErrName[mysql.ErrCTERecursiveRequiresNonRecursiveFirst])
+	ErrCTERecursiveForbidsAggregation = terror.ClassOptimizer.New(mysql.ErrCTERecursiveForbidsAggregation, mysql.MySQLErrName[mysql.ErrCTERecursiveForbidsAggregation])
+	ErrCTERecursiveSingleReference    = terror.ClassOptimizer.New(mysql.ErrCTERecursiveRequiresSingleReference, mysql.MySQLErrName[mysql.ErrCTERecursiveRequiresSingleReference])
+	ErrNonInsertableTable             = terror.ClassOptimizer.New(mysql.ErrNonInsertableTable, mysql.MySQLErrName[mysql.ErrNonInsertableTable])
 )
 
 func init() {
@@ -110,6 +131,27 @@ func init() {
 		mysql.ErrNonuniqTable:                        mysql.ErrNonuniqTable,
 		mysql.ErrTooBigPrecision:                     mysql.ErrTooBigPrecision,
 		mysql.ErrInvalidWildCard:                     mysql.ErrInvalidWildCard,
+		mysql.ErrWindowInvalidWindowFuncUse:          mysql.ErrWindowInvalidWindowFuncUse,
+		mysql.ErrWindowInvalidWindowFuncAliasUse:     mysql.ErrWindowInvalidWindowFuncAliasUse,
+		mysql.ErrWindowNoSuchWindow:                  mysql.ErrWindowNoSuchWindow,
+		mysql.ErrWindowCircularityInWindowGraph:      mysql.ErrWindowCircularityInWindowGraph,
+		mysql.ErrWindowNoChildPartitioning:           mysql.ErrWindowNoChildPartitioning,
+		mysql.ErrWindowNoInherentFrame:               mysql.ErrWindowNoInherentFrame,
+		mysql.ErrWindowNoRedefineOrderBy:             mysql.ErrWindowNoRedefineOrderBy,
+		mysql.ErrWindowDuplicateName:                 mysql.ErrWindowDuplicateName,
+		mysql.ErrWindowFrameStartIllegal:             mysql.ErrWindowFrameStartIllegal,
+		mysql.ErrWindowFrameEndIllegal:               mysql.ErrWindowFrameEndIllegal,
+		mysql.ErrWindowFrameIllegal:                  mysql.ErrWindowFrameIllegal,
+		mysql.ErrWindowRangeFrameOrderType:           mysql.ErrWindowRangeFrameOrderType,
+		mysql.ErrWindowRangeBoundNotConstant:         mysql.ErrWindowRangeBoundNotConstant,
+		mysql.ErrWindowFunctionIgnoresFrame:          mysql.ErrWindowFunctionIgnoresFrame,
+
+		mysql.ErrViewWrongList:                         mysql.ErrViewWrongList,
+		mysql.ErrCTERecursiveRequiresUnion:             mysql.ErrCTERecursiveRequiresUnion,
+		mysql.ErrCTERecursiveRequiresNonRecursiveFirst: mysql.ErrCTERecursiveRequiresNonRecursiveFirst,
+		mysql.ErrCTERecursiveForbidsAggregation:        mysql.ErrCTERecursiveForbidsAggregation,
+		mysql.ErrCTERecursiveRequiresSingleReference:   mysql.ErrCTERecursiveRequiresSingleReference,
+		mysql.ErrNonInsertableTable:                    mysql.ErrNonInsertableTable,
 	}
 	terror.ErrClassToMySQLCodes[terror.ClassOptimizer] = mysqlErrCodeMap
 }
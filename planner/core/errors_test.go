@@ -64,6 +64,20 @@ func (s testErrorSuite) TestError(c *C) {
 		ErrCartesianProductUnsupported,
 		ErrStmtNotFound,
 		ErrAmbiguous,
+		ErrWindowInvalidWindowFuncUse,
+		ErrWindowInvalidWindowFuncAliasUse,
+		ErrWindowNoSuchWindow,
+		ErrWindowCircularityInWindowGraph,
+		ErrWindowNoChildPartitioning,
+		ErrWindowNoInherentFrame,
+		ErrWindowNoRedefineOrderBy,
+		ErrWindowDuplicateName,
+		ErrWindowFrameStartIllegal,
+		ErrWindowFrameEndIllegal,
+		ErrWindowFrameIllegal,
+		ErrWindowRangeFrameOrderType,
+		ErrWindowRangeBoundNotConstant,
+		ErrWindowFunctionIgnoresFrame,
 	}
 	for _, err := range kvErrs {
 		code := err.ToSQLError().Code
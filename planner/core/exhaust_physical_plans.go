@@ -14,11 +14,19 @@
 package core
 
 import (
+	"bytes"
+	"fmt"
 	"math"
 
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/expression/aggregation"
 	"github.com/pingcap/tidb/planner/property"
+	"github.com/pingcap/tidb/planner/util"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/ranger"
 	"github.com/pingcap/tidb/util/set"
+	"go.uber.org/zap"
 )
 
 func (p *LogicalUnionScan) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
@@ -250,7 +258,7 @@ func (p *LogicalJoin) getHashJoins(prop *property.PhysicalProperty) []PhysicalPl
 	}
 	joins := make([]PhysicalPlan, 0, 2)
 	switch p.JoinType {
-	case LeftOuterJoin:
+	case SemiJoin, AntiSemiJoin, LeftOuterSemiJoin, AntiLeftOuterSemiJoin, LeftOuterJoin:
 		joins = append(joins, p.getHashJoin(prop, 1))
 	case RightOuterJoin:
 		joins = append(joins, p.getHashJoin(prop, 0))
@@ -274,6 +282,447 @@ func (p *LogicalJoin) getHashJoin(prop *property.PhysicalProperty, innerIdx int)
 	return hashJoin
 }
 
+// When inner plan is TableReader, the parameter `ranges` will be nil. Because pk only have one column. So all of its range
+// is generated during execution time.
+func (p *LogicalJoin) constructIndexJoin(
+	prop *property.PhysicalProperty,
+	outerIdx int,
+	innerTask task,
+	ranges []*ranger.Range,
+	keyOff2IdxOff []int,
+	path *util.AccessPath,
+) []PhysicalPlan {
+	var (
+		innerJoinKeys []*expression.Column
+		outerJoinKeys []*expression.Column
+	)
+	if outerIdx == 0 {
+		outerJoinKeys = p.LeftJoinKeys
+		innerJoinKeys = p.RightJoinKeys
+	} else {
+		innerJoinKeys = p.LeftJoinKeys
+		outerJoinKeys = p.RightJoinKeys
+	}
+	chReqProps := make([]*property.PhysicalProperty, 2)
+	chReqProps[outerIdx] = &property.PhysicalProperty{TaskTp: property.RootTaskType, ExpectedCnt: math.MaxFloat64, Items: prop.Items}
+	if prop.ExpectedCnt < p.stats.RowCount {
+		expCntScale := prop.ExpectedCnt / p.stats.RowCount
+		chReqProps[outerIdx].ExpectedCnt = p.children[outerIdx].statsInfo().RowCount * expCntScale
+	}
+	newInnerKeys := make([]*expression.Column, 0, len(innerJoinKeys))
+	newOuterKeys := make([]*expression.Column, 0, len(outerJoinKeys))
+	newKeyOff := make([]int, 0, len(keyOff2IdxOff))
+	newOtherConds := make([]expression.Expression, len(p.OtherConditions), len(p.OtherConditions)+len(p.EqualConditions))
+	copy(newOtherConds, p.OtherConditions)
+	for keyOff, idxOff := range keyOff2IdxOff {
+		// The equal conditions whose keys are not used to build ranges are
+		// evaluated as other conditions.
+		if idxOff < 0 {
+			newOtherConds = append(newOtherConds, p.EqualConditions[keyOff])
+			continue
+		}
+		newInnerKeys = append(newInnerKeys, innerJoinKeys[keyOff])
+		newOuterKeys = append(newOuterKeys, outerJoinKeys[keyOff])
+		newKeyOff = append(newKeyOff, idxOff)
+	}
+	baseJoin := basePhysicalJoin{
+		InnerChildIdx:   1 - outerIdx,
+		LeftConditions:  p.LeftConditions,
+		RightConditions: p.RightConditions,
+		OtherConditions: newOtherConds,
+		JoinType:        p.JoinType,
+		OuterJoinKeys:   newOuterKeys,
+		InnerJoinKeys:   newInnerKeys,
+		DefaultValues:   p.DefaultValues,
+	}
+	join := PhysicalIndexJoin{
+		basePhysicalJoin: baseJoin,
+		innerTask:        innerTask,
+		KeyOff2IdxOff:    newKeyOff,
+		Ranges:           ranges,
+	}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), chReqProps...)
+	if path != nil {
+		join.IdxColLens = path.IdxColLens
+	}
+	join.SetSchema(p.schema)
+	return []PhysicalPlan{join}
+}
+
+// getIndexJoinByOuterIdx will generate index join by outerIndex. OuterIdx points out the outer child.
+// First of all, we'll check whether the inner child is DataSource.
+// Then, we will extract the join keys of p's equal conditions. Then check whether all of them are just the primary key
+// or match some part of on index. If so we will choose the best one and construct a index join.
+func (p *LogicalJoin) getIndexJoinByOuterIdx(prop *property.PhysicalProperty, outerIdx int) []PhysicalPlan {
+	outerChild, innerChild := p.children[outerIdx], p.children[1-outerIdx]
+	all, _ := prop.AllSameOrder()
+	// If the order by columns are not all from outer child, index join cannot promise the order.
+	if !prop.AllColsFromSchema(outerChild.Schema()) || !all {
+		return nil
+	}
+	var (
+		innerJoinKeys []*expression.Column
+		outerJoinKeys []*expression.Column
+	)
+	if outerIdx == 0 {
+		outerJoinKeys = p.LeftJoinKeys
+		innerJoinKeys = p.RightJoinKeys
+	} else {
+		innerJoinKeys = p.LeftJoinKeys
+		outerJoinKeys = p.RightJoinKeys
+	}
+	if len(innerJoinKeys) == 0 {
+		return nil
+	}
+	ds, isDataSource := innerChild.(*DataSource)
+	us, isUnionScan := innerChild.(*LogicalUnionScan)
+	if !isDataSource && !isUnionScan {
+		return nil
+	}
+	if isUnionScan {
+		ds, isDataSource = us.Children()[0].(*DataSource)
+		if !isDataSource {
+			return nil
+		}
+	}
+	var avgInnerRowCnt float64
+	if outerChild.statsInfo().RowCount > 0 {
+		avgInnerRowCnt = p.equalCondOutCnt / outerChild.statsInfo().RowCount
+	}
+	var tblPath *util.AccessPath
+	for _, path := range ds.possibleAccessPaths {
+		if path.IsTablePath {
+			tblPath = path
+			break
+		}
+	}
+	if pkCol := ds.getPKIsHandleCol(); pkCol != nil && tblPath != nil {
+		keyOff2IdxOff := make([]int, len(innerJoinKeys))
+		pkMatched := false
+		for i, key := range innerJoinKeys {
+			if !key.Equal(nil, pkCol) {
+				keyOff2IdxOff[i] = -1
+				continue
+			}
+			pkMatched = true
+			keyOff2IdxOff[i] = 0
+		}
+		if pkMatched {
+			innerTask := ds.constructInnerTableScanTask(pkCol, outerJoinKeys, avgInnerRowCnt)
+			innerTask = p.constructInnerUnionScan(us, innerTask)
+			// Since the primary key means one value corresponding to exact one row, this will always be a no worse one
+			// comparing to other index.
+			return p.constructIndexJoin(prop, outerIdx, innerTask, nil, keyOff2IdxOff, nil)
+		}
+	}
+	helper := &indexJoinBuildHelper{join: p}
+	for _, path := range ds.possibleAccessPaths {
+		if path.IsTablePath {
+			continue
+		}
+		emptyRange, err := helper.analyzeLookUpFilters(path, ds, innerJoinKeys)
+		if emptyRange {
+			return nil
+		}
+		if err != nil {
+			logutil.BgLogger().Warn("build index join failed", zap.Error(err))
+		}
+	}
+	if helper.chosenPath == nil {
+		return nil
+	}
+	keyOff2IdxOff := make([]int, len(innerJoinKeys))
+	for i := range keyOff2IdxOff {
+		keyOff2IdxOff[i] = -1
+	}
+	for idxOff, keyOff := range helper.idxOff2KeyOff {
+		if keyOff != -1 {
+			keyOff2IdxOff[keyOff] = idxOff
+		}
+	}
+	rangeInfo := helper.buildRangeDecidedByInformation(helper.chosenPath.IdxCols, outerJoinKeys)
+	innerTask := ds.constructInnerIndexScanTask(helper.chosenPath, helper.chosenRemained, rangeInfo, avgInnerRowCnt)
+	innerTask = p.constructInnerUnionScan(us, innerTask)
+	return p.constructIndexJoin(prop, outerIdx, innerTask, helper.chosenRanges, keyOff2IdxOff, helper.chosenPath)
+}
+
+// constructInnerUnionScan puts a union scan above the inner reader if the
+// inner child of the join is a LogicalUnionScan.
+func (p *LogicalJoin) constructInnerUnionScan(us *LogicalUnionScan, innerTask task) task {
+	if us == nil {
+		return innerTask
+	}
+	t := innerTask.(*rootTask)
+	reader := t.p
+	// Use `reader.stats` instead of `us.stats` because it should be more accurate. No need to specify
+	// childrenReqProps now since we have got reader already.
+	physicalUnionScan := PhysicalUnionScan{
+		Conditions: us.conditions,
+		HandleCol:  us.handleCol,
+	}.Init(us.ctx, reader.statsInfo())
+	physicalUnionScan.SetChildren(reader)
+	t.p = physicalUnionScan
+	return t
+}
+
+type indexJoinBuildHelper struct {
+	join *LogicalJoin
+
+	chosenPath     *util.AccessPath
+	maxUsedCols    int
+	chosenAccess   []expression.Expression
+	chosenRemained []expression.Expression
+	idxOff2KeyOff  []int
+	chosenRanges   []*ranger.Range
+
+	curPossibleUsedKeys []*expression.Column
+	curNotUsedIndexCols []*expression.Column
+	curNotUsedColLens   []int
+	curIdxOff2KeyOff    []int
+}
+
+func (ijHelper *indexJoinBuildHelper) buildRangeDecidedByInformation(idxCols []*expression.Column, outerJoinKeys []*expression.Column) string {
+	buffer := bytes.NewBufferString("[")
+	isFirst := true
+	for idxOff, keyOff := range ijHelper.idxOff2KeyOff {
+		if keyOff == -1 {
+			continue
+		}
+		if !isFirst {
+			buffer.WriteString(" ")
+		} else {
+			isFirst = false
+		}
+		fmt.Fprintf(buffer, "eq(%v, %v)", idxCols[idxOff], outerJoinKeys[keyOff])
+	}
+	buffer.WriteString("]")
+	return buffer.String()
+}
+
+func (ijHelper *indexJoinBuildHelper) resetContextForIndex(innerKeys []*expression.Column, idxCols []*expression.Column, colLens []int) {
+	tmpSchema := expression.NewSchema(innerKeys...)
+	ijHelper.curIdxOff2KeyOff = make([]int, len(idxCols))
+	ijHelper.curNotUsedIndexCols = make([]*expression.Column, 0, len(idxCols))
+	ijHelper.curNotUsedColLens = make([]int, 0, len(idxCols))
+	for i, idxCol := range idxCols {
+		ijHelper.curIdxOff2KeyOff[i] = tmpSchema.ColumnIndex(idxCol)
+		// A prefix index column can not be used to look up the outer key.
+		if ijHelper.curIdxOff2KeyOff[i] >= 0 && colLens[i] == types.UnspecifiedLength {
+			continue
+		}
+		ijHelper.curIdxOff2KeyOff[i] = -1
+		ijHelper.curNotUsedIndexCols = append(ijHelper.curNotUsedIndexCols, idxCol)
+		ijHelper.curNotUsedColLens = append(ijHelper.curNotUsedColLens, colLens[i])
+	}
+}
+
+// findUsefulEqAndInFilters analyzes the pushedDownConds held by inner child and split them to three parts.
+// usefulEqOrInFilters is the continuous eq/in conditions on current unused index columns.
+// uselessFilters is the conditions which cannot be used for building ranges.
+// remainingRangeCandidates is the other conditions for future use.
+func (ijHelper *indexJoinBuildHelper) findUsefulEqAndInFilters(innerPlan *DataSource) (usefulEqOrInFilters, uselessFilters, remainingRangeCandidates []expression.Expression) {
+	// The template ranges are not rebuilt when a cached plan is reused, so the
+	// conditions of the inner child, which may hold parameters, are not used to build them.
+	if innerPlan.ctx.GetSessionVars().StmtCtx.UseCache {
+		return nil, innerPlan.pushedDownConds, nil
+	}
+	// Extract the eq/in functions of possible join key.
+	// you can see the comment of ExtractEqAndInCondition to get the meaning of the second return value.
+	usefulEqOrInFilters, uselessFilters, remainingRangeCandidates, _ = ranger.ExtractEqAndInCondition(
+		innerPlan.ctx, innerPlan.pushedDownConds,
+		ijHelper.curNotUsedIndexCols,
+		ijHelper.curNotUsedColLens,
+	)
+	return usefulEqOrInFilters, uselessFilters, remainingRangeCandidates
+}
+
+// removeUselessEqAndInFunc removes the useless eq/in conditions. It's designed for the following case:
+//   t1 join t2 on t1.a=t2.a and t1.c=t2.c where t1.b > t2.b-10 and t1.b < t2.b+10 there's index(a, b, c) on t1.
+//   In this case the curIdxOff2KeyOff is [0 -1 1] and the notKeyEqAndIn is [].
+//   It's clearly that the column c cannot be used to access data. So we need to remove it and reset the IdxOff2KeyOff to
+//   [0 -1 -1].
+//   So this function does the following things:
+//     1. Remove the useless eq/in conditions.
+//     2. Update the idxOff2KeyOff.
+func (ijHelper *indexJoinBuildHelper) removeUselessEqAndInFunc(
+	idxCols []*expression.Column,
+	notKeyEqAndIn []expression.Expression) (
+	usefulEqAndIn, uselessOnes []expression.Expression,
+) {
+	ijHelper.curPossibleUsedKeys = make([]*expression.Column, 0, len(idxCols))
+	for idxColPos, notKeyColPos := 0, 0; idxColPos < len(idxCols); idxColPos++ {
+		if ijHelper.curIdxOff2KeyOff[idxColPos] != -1 {
+			ijHelper.curPossibleUsedKeys = append(ijHelper.curPossibleUsedKeys, idxCols[idxColPos])
+			continue
+		}
+		if notKeyColPos < len(notKeyEqAndIn) && ijHelper.curNotUsedIndexCols[notKeyColPos].Equal(nil, idxCols[idxColPos]) {
+			notKeyColPos++
+			continue
+		}
+		for i := idxColPos + 1; i < len(idxCols); i++ {
+			ijHelper.curIdxOff2KeyOff[i] = -1
+		}
+		remained := make([]expression.Expression, 0, len(notKeyEqAndIn)-notKeyColPos)
+		remained = append(remained, notKeyEqAndIn[notKeyColPos:]...)
+		notKeyEqAndIn = notKeyEqAndIn[:notKeyColPos]
+		return notKeyEqAndIn, remained
+	}
+	return notKeyEqAndIn, nil
+}
+
+// analyzeLookUpFilters checks whether the index of the path can be used to
+// look up the inner rows by the join keys, and records the path if it uses
+// more index columns than the chosen one.
+func (ijHelper *indexJoinBuildHelper) analyzeLookUpFilters(path *util.AccessPath, innerPlan *DataSource, innerJoinKeys []*expression.Column) (emptyRange bool, err error) {
+	if len(path.IdxCols) == 0 {
+		return false, nil
+	}
+	accesses := make([]expression.Expression, 0, len(path.IdxCols))
+	ijHelper.resetContextForIndex(innerJoinKeys, path.IdxCols, path.IdxColLens)
+	notKeyEqAndIn, remained, rangeFilterCandidates := ijHelper.findUsefulEqAndInFilters(innerPlan)
+	var remainedEqAndIn []expression.Expression
+	notKeyEqAndIn, remainedEqAndIn = ijHelper.removeUselessEqAndInFunc(path.IdxCols, notKeyEqAndIn)
+	matchedKeyCnt := len(ijHelper.curPossibleUsedKeys)
+	// If no join key is matched, we don't choose index join for now.
+	// e.g. select * from t1, t2 where t2.a=1 and t2.b=1. And t2 has index(a, b).
+	//      If we don't have this check, TiDB will build index join for this case.
+	if matchedKeyCnt <= 0 {
+		return false, nil
+	}
+	accesses = append(accesses, notKeyEqAndIn...)
+	remained = append(remained, remainedEqAndIn...)
+	remained = append(remained, rangeFilterCandidates...)
+	ranges, emptyRange, err := ijHelper.buildTemplateRange(matchedKeyCnt, notKeyEqAndIn)
+	if err != nil {
+		return false, err
+	}
+	if emptyRange {
+		return true, nil
+	}
+	ijHelper.updateBestChoice(ranges, path, accesses, remained)
+	return false, nil
+}
+
+func (ijHelper *indexJoinBuildHelper) updateBestChoice(ranges []*ranger.Range, path *util.AccessPath, accesses,
+	remained []expression.Expression) {
+	// We choose the index by the number of used columns of the range, the much the better.
+	// Notice that there may be the cases like `t1.a=t2.a and b > 2 and b < 1`. So ranges can be nil though the conditions are valid.
+	// But obviously when the range is nil, we don't need index join.
+	if len(ranges) > 0 && len(ranges[0].LowVal) > ijHelper.maxUsedCols {
+		ijHelper.chosenPath = path
+		ijHelper.maxUsedCols = len(ranges[0].LowVal)
+		ijHelper.chosenRanges = ranges
+		ijHelper.chosenAccess = accesses
+		ijHelper.chosenRemained = remained
+		ijHelper.idxOff2KeyOff = ijHelper.curIdxOff2KeyOff
+	}
+}
+
+// buildTemplateRange builds the ranges of the inner index scan, the values of
+// the positions occupied by join keys are filled by the outer rows during
+// execution.
+func (ijHelper *indexJoinBuildHelper) buildTemplateRange(matchedKeyCnt int, eqAndInFuncs []expression.Expression) (ranges []*ranger.Range, emptyRange bool, err error) {
+	pointLength := matchedKeyCnt + len(eqAndInFuncs)
+	ranges = append(ranges, &ranger.Range{
+		LowVal:  make([]types.Datum, pointLength),
+		HighVal: make([]types.Datum, pointLength),
+	})
+	sc := ijHelper.join.ctx.GetSessionVars().StmtCtx
+	for i, j := 0, 0; j < len(eqAndInFuncs); i++ {
+		// This position is occupied by join key.
+		if ijHelper.curIdxOff2KeyOff[i] != -1 {
+			continue
+		}
+		oneColumnRan, err := ranger.BuildColumnRange([]expression.Expression{eqAndInFuncs[j]}, sc, ijHelper.curNotUsedIndexCols[j].RetType, ijHelper.curNotUsedColLens[j])
+		if err != nil {
+			return nil, false, err
+		}
+		if len(oneColumnRan) == 0 {
+			return nil, true, nil
+		}
+		for _, ran := range ranges {
+			ran.LowVal[i] = oneColumnRan[0].LowVal[0]
+			ran.HighVal[i] = oneColumnRan[0].HighVal[0]
+		}
+		curRangeLen := len(ranges)
+		for ranIdx := 1; ranIdx < len(oneColumnRan); ranIdx++ {
+			newRanges := make([]*ranger.Range, 0, curRangeLen)
+			for oldRangeIdx := 0; oldRangeIdx < curRangeLen; oldRangeIdx++ {
+				newRange := ranges[oldRangeIdx].Clone()
+				newRange.LowVal[i] = oneColumnRan[ranIdx].LowVal[0]
+				newRange.HighVal[i] = oneColumnRan[ranIdx].HighVal[0]
+				newRanges = append(newRanges, newRange)
+			}
+			ranges = append(ranges, newRanges...)
+		}
+		j++
+	}
+	return ranges, false, nil
+}
+
+// tryToGetIndexJoin will get index join by hints. If we can generate a valid index join by hint, the second return value
+// will be true, which means we force to choose this index join. Otherwise we will select a join algorithm with min-cost.
+func (p *LogicalJoin) tryToGetIndexJoin(prop *property.PhysicalProperty) (indexJoins []PhysicalPlan, forced bool) {
+	rightOuter := (p.preferJoinType & preferLeftAsIndexInner) > 0
+	leftOuter := (p.preferJoinType & preferRightAsIndexInner) > 0
+	hasIndexJoinHint := leftOuter || rightOuter
+
+	defer func() {
+		if !forced && hasIndexJoinHint {
+			// Construct warning message prefix.
+			errMsg := "Optimizer Hint INL_JOIN or TIDB_INLJ is inapplicable"
+			if p.hintInfo != nil {
+				errMsg = fmt.Sprintf("Optimizer Hint %s or %s is inapplicable",
+					restore2JoinHint(HintINLJ, p.hintInfo.indexNestedLoopJoinTables),
+					restore2JoinHint(TiDBIndexNestedLoopJoin, p.hintInfo.indexNestedLoopJoinTables))
+			}
+
+			// Append inapplicable reason.
+			if len(p.EqualConditions) == 0 {
+				errMsg += " without column equal ON condition"
+			}
+
+			// Generate warning message to client.
+			warning := ErrInternal.GenWithStack(errMsg)
+			p.ctx.GetSessionVars().StmtCtx.AppendWarning(warning)
+		}
+	}()
+
+	switch p.JoinType {
+	case SemiJoin, AntiSemiJoin, LeftOuterSemiJoin, AntiLeftOuterSemiJoin, LeftOuterJoin:
+		join := p.getIndexJoinByOuterIdx(prop, 0)
+		return join, join != nil && leftOuter
+	case RightOuterJoin:
+		join := p.getIndexJoinByOuterIdx(prop, 1)
+		return join, join != nil && rightOuter
+	case InnerJoin:
+		lhsCardinality := p.Children()[0].statsInfo().Count()
+		rhsCardinality := p.Children()[1].statsInfo().Count()
+
+		leftJoins := p.getIndexJoinByOuterIdx(prop, 0)
+		if leftJoins != nil && leftOuter && !rightOuter {
+			return leftJoins, true
+		}
+
+		rightJoins := p.getIndexJoinByOuterIdx(prop, 1)
+		if rightJoins != nil && rightOuter && !leftOuter {
+			return rightJoins, true
+		}
+
+		if leftJoins != nil && lhsCardinality < rhsCardinality {
+			return leftJoins, hasIndexJoinHint
+		}
+
+		if rightJoins != nil && rhsCardinality < lhsCardinality {
+			return rightJoins, hasIndexJoinHint
+		}
+
+		joins := append(leftJoins, rightJoins...)
+		return joins, hasIndexJoinHint && len(joins) != 0
+	}
+
+	return nil, false
+}
+
 // LogicalJoin can generates hash join, index join and sort merge join.
 // Firstly we check the hint, if hint is figured by user, we force to choose the corresponding physical plan.
 // If the hint is not matched, it will get other candidates.
@@ -286,6 +735,12 @@ func (p *LogicalJoin) exhaustPhysicalPlans(prop *property.PhysicalProperty) []Ph
 	joins := make([]PhysicalPlan, 0, 3)
 	joins = append(joins, mergeJoins...)
 
+	indexJoins, forced := p.tryToGetIndexJoin(prop)
+	if forced {
+		return indexJoins
+	}
+	joins = append(joins, indexJoins...)
+
 	hashJoins := p.getHashJoins(prop)
 	if (p.preferJoinType & preferHashJoin) > 0 {
 		return hashJoins
@@ -294,6 +749,22 @@ func (p *LogicalJoin) exhaustPhysicalPlans(prop *property.PhysicalProperty) []Ph
 	return joins
 }
 
+func (la *LogicalApply) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	if !prop.AllColsFromSchema(la.children[0].Schema()) { // for convenience, we only pass through the sort property of outer child
+		return nil
+	}
+	join := la.getHashJoin(prop, 1)
+	apply := PhysicalApply{
+		PhysicalHashJoin: *join,
+		OuterSchema:      la.CorCols,
+	}.Init(la.ctx,
+		la.stats.ScaleByExpectCnt(prop.ExpectedCnt),
+		&property.PhysicalProperty{ExpectedCnt: math.MaxFloat64, Items: prop.Items},
+		&property.PhysicalProperty{ExpectedCnt: math.MaxFloat64})
+	apply.SetSchema(la.schema)
+	return []PhysicalPlan{apply}
+}
+
 // TryToGetChildProp will check if this sort property can be pushed or not.
 // When a sort column will be replaced by scalar function, we refuse it.
 // When a sort column will be replaced by a constant, we just remove it.
@@ -396,8 +867,52 @@ func (la *LogicalAggregation) getHashAggs(prop *property.PhysicalProperty) []Phy
 	return hashAggs
 }
 
+func (la *LogicalAggregation) getStreamAggs(prop *property.PhysicalProperty) []PhysicalPlan {
+	all, desc := prop.AllSameOrder()
+	if !all {
+		return nil
+	}
+	for _, aggFunc := range la.AggFuncs {
+		if aggFunc.Mode == aggregation.FinalMode {
+			return nil
+		}
+	}
+	// group by a + b is not interested in any order.
+	if len(la.groupByCols) != len(la.GroupByItems) {
+		return nil
+	}
+
+	streamAggs := make([]PhysicalPlan, 0, len(la.possibleProperties)*(len(wholeTaskTypes)-1))
+	childProp := &property.PhysicalProperty{
+		ExpectedCnt: math.Max(prop.ExpectedCnt*la.inputCount/la.stats.RowCount, prop.ExpectedCnt),
+	}
+	for _, possibleChildProperty := range la.possibleProperties {
+		childProp.Items = property.ItemsFromCols(possibleChildProperty[:len(la.groupByCols)], desc)
+		if !prop.IsPrefix(childProp) {
+			continue
+		}
+		// The table read of "CopDoubleReadTaskType" can't promise the sort
+		// property that the stream aggregation requires, no need to consider.
+		for _, taskTp := range []property.TaskType{property.CopSingleReadTaskType, property.RootTaskType} {
+			copiedChildProperty := new(property.PhysicalProperty)
+			*copiedChildProperty = *childProp // It's ok to not deep copy the "Items" field.
+			copiedChildProperty.TaskTp = taskTp
+			agg := basePhysicalAgg{
+				GroupByItems: la.GroupByItems,
+				AggFuncs:     la.AggFuncs,
+			}.initForStream(la.ctx, la.stats.ScaleByExpectCnt(prop.ExpectedCnt), copiedChildProperty)
+			agg.SetSchema(la.schema.Clone())
+			streamAggs = append(streamAggs, agg)
+		}
+	}
+	return streamAggs
+}
+
 func (la *LogicalAggregation) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
-	return la.getHashAggs(prop)
+	aggs := make([]PhysicalPlan, 0, len(la.possibleProperties)+1)
+	aggs = append(aggs, la.getHashAggs(prop)...)
+	aggs = append(aggs, la.getStreamAggs(prop)...)
+	return aggs
 }
 
 func (p *LogicalSelection) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
@@ -424,6 +939,57 @@ func (p *LogicalLimit) exhaustPhysicalPlans(prop *property.PhysicalProperty) []P
 	return ret
 }
 
+func (p *LogicalMaxOneRow) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	if !prop.IsEmpty() {
+		return nil
+	}
+	mor := PhysicalMaxOneRow{}.Init(p.ctx, p.stats, &property.PhysicalProperty{ExpectedCnt: 2})
+	return []PhysicalPlan{mor}
+}
+
+func (p *LogicalLock) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	childProp := prop.Clone()
+	lock := PhysicalLock{
+		Lock:         p.Lock,
+		TblID2Handle: p.tblID2Handle,
+	}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), childProp)
+	return []PhysicalPlan{lock}
+}
+
+func (p *LogicalWindow) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	var byItems []property.Item
+	byItems = append(byItems, p.PartitionBy...)
+	byItems = append(byItems, p.OrderBy...)
+	childProperty := &property.PhysicalProperty{ExpectedCnt: math.MaxFloat64, Items: byItems, Enforced: true}
+	// The window operator keeps the order of its child, so it can satisfy
+	// any required property which is a prefix of the child's.
+	if !prop.IsPrefix(childProperty) {
+		return nil
+	}
+	window := PhysicalWindow{
+		WindowFuncDescs: p.WindowFuncDescs,
+		PartitionBy:     p.PartitionBy,
+		OrderBy:         p.OrderBy,
+		Frame:           p.Frame,
+	}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), childProperty)
+	window.SetSchema(p.Schema())
+	return []PhysicalPlan{window}
+}
+
+func (p *LogicalUnionAll) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	// UnionAll can not pass any order.
+	if !prop.IsEmpty() {
+		return nil
+	}
+	chReqProps := make([]*property.PhysicalProperty, 0, len(p.children))
+	for range p.children {
+		chReqProps = append(chReqProps, &property.PhysicalProperty{ExpectedCnt: prop.ExpectedCnt})
+	}
+	ua := PhysicalUnionAll{}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), chReqProps...)
+	ua.SetSchema(p.Schema())
+	return []PhysicalPlan{ua}
+}
+
 func (ls *LogicalSort) getPhysicalSort(prop *property.PhysicalProperty) *PhysicalSort {
 	ps := PhysicalSort{ByItems: ls.ByItems}.Init(ls.ctx, ls.stats.ScaleByExpectCnt(prop.ExpectedCnt), &property.PhysicalProperty{ExpectedCnt: math.MaxFloat64})
 	return ps
@@ -20,6 +20,7 @@ import (
 
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/expression/aggregation"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/statistics"
 )
 
@@ -44,7 +45,11 @@ func (p *PhysicalIndexScan) explainInfo(normalized bool) string {
 			}
 		}
 	}
-	if len(p.Ranges) > 0 {
+	if len(p.rangeInfo) > 0 {
+		if !normalized {
+			fmt.Fprintf(buffer, ", range: decided by %v", p.rangeInfo)
+		}
+	} else if len(p.Ranges) > 0 {
 		if normalized {
 			fmt.Fprint(buffer, ", range:[?,?]")
 		} else {
@@ -92,7 +97,11 @@ func (p *PhysicalTableScan) explainInfo(normalized bool) string {
 	if p.pkCol != nil {
 		fmt.Fprintf(buffer, ", pk col:%s", p.pkCol.ExplainInfo())
 	}
-	if len(p.Ranges) > 0 {
+	if len(p.rangeDecidedBy) > 0 {
+		if !normalized {
+			fmt.Fprintf(buffer, ", range: decided by %v", p.rangeDecidedBy)
+		}
+	} else if len(p.Ranges) > 0 {
 		if normalized {
 			fmt.Fprint(buffer, ", range:[?,?]")
 		} else {
@@ -170,6 +179,19 @@ func (p *PhysicalTableDual) ExplainInfo() string {
 	return fmt.Sprintf("rows:%v", p.RowCount)
 }
 
+// ExplainInfo implements Plan interface.
+func (p *PhysicalCTE) ExplainInfo() string {
+	if p.RecurPlan == nil {
+		return fmt.Sprintf("cte:%s", p.cteAsName.O)
+	}
+	return fmt.Sprintf("cte:%s, recursive, distinct:%v", p.cteAsName.O, p.CTE.IsDistinct)
+}
+
+// ExplainInfo implements Plan interface.
+func (p *PhysicalCTETable) ExplainInfo() string {
+	return fmt.Sprintf("cte:%s", p.name.O)
+}
+
 // ExplainInfo implements Plan interface.
 func (p *PhysicalSort) ExplainInfo() string {
 	buffer := bytes.NewBufferString("")
@@ -260,6 +282,47 @@ func (p *PhysicalHashJoin) explainInfo(normalized bool) string {
 	return buffer.String()
 }
 
+// ExplainInfo implements Plan interface.
+func (p *PhysicalIndexJoin) ExplainInfo() string {
+	return p.explainInfo(false)
+}
+
+// ExplainNormalizedInfo implements Plan interface.
+func (p *PhysicalIndexJoin) ExplainNormalizedInfo() string {
+	return p.explainInfo(true)
+}
+
+func (p *PhysicalIndexJoin) explainInfo(normalized bool) string {
+	sortedExplainExpressionList := expression.SortedExplainExpressionList
+	if normalized {
+		sortedExplainExpressionList = expression.SortedExplainNormalizedExpressionList
+	}
+
+	buffer := bytes.NewBufferString(p.JoinType.String())
+	fmt.Fprintf(buffer, ", inner:%s", p.Children()[p.InnerChildIdx].ExplainID())
+	if len(p.OuterJoinKeys) > 0 {
+		fmt.Fprintf(buffer, ", outer key:%s",
+			expression.ExplainColumnList(p.OuterJoinKeys))
+	}
+	if len(p.InnerJoinKeys) > 0 {
+		fmt.Fprintf(buffer, ", inner key:%s",
+			expression.ExplainColumnList(p.InnerJoinKeys))
+	}
+	if len(p.LeftConditions) > 0 {
+		fmt.Fprintf(buffer, ", left cond:%s",
+			sortedExplainExpressionList(p.LeftConditions))
+	}
+	if len(p.RightConditions) > 0 {
+		fmt.Fprintf(buffer, ", right cond:%s",
+			sortedExplainExpressionList(p.RightConditions))
+	}
+	if len(p.OtherConditions) > 0 {
+		fmt.Fprintf(buffer, ", other cond:%s",
+			sortedExplainExpressionList(p.OtherConditions))
+	}
+	return buffer.String()
+}
+
 // ExplainInfo implements Plan interface.
 func (p *PhysicalMergeJoin) ExplainInfo() string {
 	return p.explainInfo(false)
@@ -458,3 +521,86 @@ func (p *TiKVSingleGather) ExplainInfo() string {
 	}
 	return buffer.String()
 }
+
+func formatWindowFuncDescs(buffer *bytes.Buffer, descs []*aggregation.WindowFuncDesc, schema *expression.Schema) *bytes.Buffer {
+	winFuncStartIdx := len(schema.Columns) - len(descs)
+	for i, desc := range descs {
+		if i != 0 {
+			buffer.WriteString(", ")
+		}
+		fmt.Fprintf(buffer, "%v->%v", desc, schema.Columns[winFuncStartIdx+i])
+	}
+	return buffer
+}
+
+func (p *PhysicalWindow) formatFrameBound(buffer *bytes.Buffer, bound *FrameBound) {
+	if bound.Type == ast.CurrentRow {
+		buffer.WriteString("current row")
+		return
+	}
+	if bound.UnBounded {
+		buffer.WriteString("unbounded")
+	} else if len(bound.CalcFuncs) > 0 {
+		// For `1 preceding` of range frame.
+		sf := bound.CalcFuncs[0].(*expression.ScalarFunction)
+		buffer.WriteString(sf.GetArgs()[1].ExplainInfo())
+	} else {
+		fmt.Fprintf(buffer, "%d", bound.Num)
+	}
+	if bound.Type == ast.Preceding {
+		buffer.WriteString(" preceding")
+	} else {
+		buffer.WriteString(" following")
+	}
+}
+
+// ExplainInfo implements Plan interface.
+func (p *PhysicalWindow) ExplainInfo() string {
+	buffer := bytes.NewBufferString("")
+	formatWindowFuncDescs(buffer, p.WindowFuncDescs, p.schema)
+	buffer.WriteString(" over(")
+	isFirst := true
+	if len(p.PartitionBy) > 0 {
+		buffer.WriteString("partition by ")
+		for i, item := range p.PartitionBy {
+			buffer.WriteString(item.Col.ExplainInfo())
+			if i+1 < len(p.PartitionBy) {
+				buffer.WriteString(", ")
+			}
+		}
+		isFirst = false
+	}
+	if len(p.OrderBy) > 0 {
+		if !isFirst {
+			buffer.WriteString(" ")
+		}
+		buffer.WriteString("order by ")
+		for i, item := range p.OrderBy {
+			order := "asc"
+			if item.Desc {
+				order = "desc"
+			}
+			fmt.Fprintf(buffer, "%s %s", item.Col.ExplainInfo(), order)
+			if i+1 < len(p.OrderBy) {
+				buffer.WriteString(", ")
+			}
+		}
+		isFirst = false
+	}
+	if p.Frame != nil {
+		if !isFirst {
+			buffer.WriteString(" ")
+		}
+		if p.Frame.Type == ast.Rows {
+			buffer.WriteString("rows")
+		} else {
+			buffer.WriteString("range")
+		}
+		buffer.WriteString(" between ")
+		p.formatFrameBound(buffer, p.Frame.Start)
+		buffer.WriteString(" and ")
+		p.formatFrameBound(buffer, p.Frame.End)
+	}
+	buffer.WriteString(")")
+	return buffer.String()
+}
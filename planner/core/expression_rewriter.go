@@ -19,6 +19,7 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/expression/aggregation"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
@@ -30,6 +31,7 @@ import (
 	"github.com/pingcap/tidb/types"
 	driver "github.com/pingcap/tidb/types/parser_driver"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/stringutil"
 )
 
 // evalAstExpr evaluates ast expression directly.
@@ -50,12 +52,15 @@ func evalAstExpr(sctx sessionctx.Context, expr ast.ExprNode) (types.Datum, error
 	return newExpr.Eval(chunk.Row{})
 }
 
+// EvalSubquery evaluates incorrelated subqueries once.
+var EvalSubquery func(ctx context.Context, p PhysicalPlan, is infoschema.InfoSchema, sctx sessionctx.Context) ([][]types.Datum, error)
+
 // rewrite function rewrites ast expr to expression.Expression.
 // aggMapper maps ast.AggregateFuncExpr to the columns offset in p's output schema.
 // asScalar means whether this expression must be treated as a scalar expression.
 // And this function returns a result expression, a new plan that may have apply or semi-join.
 func (b *PlanBuilder) rewrite(ctx context.Context, exprNode ast.ExprNode, p LogicalPlan, aggMapper map[*ast.AggregateFuncExpr]int, asScalar bool) (expression.Expression, LogicalPlan, error) {
-	expr, resultPlan, err := b.rewriteWithPreprocess(ctx, exprNode, p, aggMapper, asScalar, nil)
+	expr, resultPlan, err := b.rewriteWithPreprocess(ctx, exprNode, p, aggMapper, nil, asScalar, nil)
 	return expr, resultPlan, err
 }
 
@@ -66,6 +71,7 @@ func (b *PlanBuilder) rewriteWithPreprocess(
 	ctx context.Context,
 	exprNode ast.ExprNode,
 	p LogicalPlan, aggMapper map[*ast.AggregateFuncExpr]int,
+	windowMapper map[*ast.WindowFuncExpr]int,
 	asScalar bool,
 	preprocess func(ast.Node) ast.Node,
 ) (expression.Expression, LogicalPlan, error) {
@@ -82,6 +88,7 @@ func (b *PlanBuilder) rewriteWithPreprocess(
 	}
 
 	rewriter.aggrMap = aggMapper
+	rewriter.windowMap = windowMapper
 	rewriter.asScalar = asScalar
 	rewriter.preprocess = preprocess
 
@@ -107,6 +114,7 @@ func (b *PlanBuilder) getExpressionRewriter(ctx context.Context, p LogicalPlan)
 	rewriter.p = p
 	rewriter.asScalar = false
 	rewriter.aggrMap = nil
+	rewriter.windowMap = nil
 	rewriter.preprocess = nil
 	rewriter.insertPlan = nil
 	rewriter.ctxStack = rewriter.ctxStack[:0]
@@ -141,6 +149,7 @@ type expressionRewriter struct {
 	names      []*types.FieldName
 	err        error
 	aggrMap    map[*ast.AggregateFuncExpr]int
+	windowMap  map[*ast.WindowFuncExpr]int
 	b          *PlanBuilder
 	sctx       sessionctx.Context
 	ctx        context.Context
@@ -227,6 +236,26 @@ func (er *expressionRewriter) constructBinaryOpFunction(l expression.Expression,
 	}
 }
 
+func (er *expressionRewriter) buildSubquery(ctx context.Context, subq *ast.SubqueryExpr) (LogicalPlan, error) {
+	if er.schema != nil {
+		outerSchema := er.schema.Clone()
+		er.b.outerSchemas = append(er.b.outerSchemas, outerSchema)
+		er.b.outerNames = append(er.b.outerNames, er.names)
+		defer func() {
+			er.b.outerSchemas = er.b.outerSchemas[0 : len(er.b.outerSchemas)-1]
+			er.b.outerNames = er.b.outerNames[0 : len(er.b.outerNames)-1]
+		}()
+	}
+
+	np, err := er.b.buildResultSetNode(ctx, subq.Query)
+	if err != nil {
+		return nil, err
+	}
+	// Pop the handle map generated by the subquery.
+	er.b.handleHelper.popMap()
+	return np, nil
+}
+
 // Enter implements Visitor interface.
 func (er *expressionRewriter) Enter(inNode ast.Node) (ast.Node, bool) {
 	switch v := inNode.(type) {
@@ -241,12 +270,30 @@ func (er *expressionRewriter) Enter(inNode ast.Node) (ast.Node, bool) {
 		}
 		er.ctxStackAppend(er.schema.Columns[index], er.names[index])
 		return inNode, true
+	case *ast.WindowFuncExpr:
+		index, ok := -1, false
+		if er.windowMap != nil {
+			index, ok = er.windowMap[v]
+		}
+		if !ok {
+			er.err = ErrWindowInvalidWindowFuncUse.GenWithStackByArgs(strings.ToLower(v.F))
+			return inNode, true
+		}
+		er.ctxStackAppend(er.schema.Columns[index], er.names[index])
+		return inNode, true
 	case *ast.ColumnNameExpr:
 		if index, ok := er.b.colMapper[v]; ok {
 			er.ctxStackAppend(er.schema.Columns[index], er.names[index])
 			return inNode, true
 		}
+	case *ast.CompareSubqueryExpr:
+		return er.handleCompareSubquery(er.ctx, v)
+	case *ast.ExistsSubqueryExpr:
+		return er.handleExistSubquery(er.ctx, v)
 	case *ast.PatternInExpr:
+		if v.Sel != nil {
+			return er.handleInSubquery(er.ctx, v)
+		}
 		if len(v.List) != 1 {
 			break
 		}
@@ -255,12 +302,17 @@ func (er *expressionRewriter) Enter(inNode ast.Node) (ast.Node, bool) {
 		x := v.List[0]
 		for {
 			switch y := x.(type) {
+			case *ast.SubqueryExpr:
+				v.Sel = y
+				return er.handleInSubquery(er.ctx, v)
 			case *ast.ParenthesesExpr:
 				x = y.Expr
 			default:
 				return inNode, false
 			}
 		}
+	case *ast.SubqueryExpr:
+		return er.handleScalarSubquery(er.ctx, v)
 	case *ast.ParenthesesExpr:
 	case *ast.ValuesExpr:
 		schema, names := er.schema, er.names
@@ -290,6 +342,493 @@ func (er *expressionRewriter) Enter(inNode ast.Node) (ast.Node, bool) {
 	return inNode, false
 }
 
+func (er *expressionRewriter) buildSemiApplyFromEqualSubq(np LogicalPlan, l, r expression.Expression, not bool) {
+	var condition expression.Expression
+	if rCol, ok := r.(*expression.Column); ok && (er.asScalar || not) {
+		// If both input columns of `!= all / = any` expression are not null, we can treat the expression
+		// as normal column equal condition.
+		if lCol, ok := l.(*expression.Column); !ok || !mysql.HasNotNullFlag(lCol.GetType().Flag) || !mysql.HasNotNullFlag(rCol.GetType().Flag) {
+			rColCopy := *rCol
+			rColCopy.InOperand = true
+			r = &rColCopy
+		}
+	}
+	condition, er.err = er.constructBinaryOpFunction(l, r, ast.EQ)
+	if er.err != nil {
+		return
+	}
+	er.p = er.b.buildSemiApply(er.p, np, []expression.Expression{condition}, er.asScalar, not)
+}
+
+func (er *expressionRewriter) handleCompareSubquery(ctx context.Context, v *ast.CompareSubqueryExpr) (ast.Node, bool) {
+	v.L.Accept(er)
+	if er.err != nil {
+		return v, true
+	}
+	lexpr := er.ctxStack[len(er.ctxStack)-1]
+	subq, ok := v.R.(*ast.SubqueryExpr)
+	if !ok {
+		er.err = errors.Errorf("Unknown compare type %T.", v.R)
+		return v, true
+	}
+	np, err := er.buildSubquery(ctx, subq)
+	if err != nil {
+		er.err = err
+		return v, true
+	}
+	// Only (a,b,c) = any (...) and (a,b,c) != all (...) can use row expression.
+	canMultiCol := (!v.All && v.Op == opcode.EQ) || (v.All && v.Op == opcode.NE)
+	if !canMultiCol && (expression.GetRowLen(lexpr) != 1 || np.Schema().Len() != 1) {
+		er.err = expression.ErrOperandColumns.GenWithStackByArgs(1)
+		return v, true
+	}
+	lLen := expression.GetRowLen(lexpr)
+	if lLen != np.Schema().Len() {
+		er.err = expression.ErrOperandColumns.GenWithStackByArgs(lLen)
+		return v, true
+	}
+	var rexpr expression.Expression
+	if np.Schema().Len() == 1 {
+		rexpr = np.Schema().Columns[0]
+	} else {
+		args := make([]expression.Expression, 0, np.Schema().Len())
+		for _, col := range np.Schema().Columns {
+			args = append(args, col)
+		}
+		rexpr, er.err = er.newFunction(ast.RowFunc, args[0].GetType(), args...)
+		if er.err != nil {
+			return v, true
+		}
+	}
+	switch v.Op {
+	// Only EQ, NE and NullEQ can be composed with and.
+	case opcode.EQ, opcode.NE, opcode.NullEQ:
+		if v.Op == opcode.EQ {
+			if v.All {
+				er.handleEQAll(lexpr, rexpr, np)
+			} else {
+				// `a = any(subq)` will be rewritten as `a in (subq)`.
+				er.buildSemiApplyFromEqualSubq(np, lexpr, rexpr, false)
+			}
+		} else if v.Op == opcode.NE {
+			if v.All {
+				// `a != all(subq)` will be rewritten as `a not in (subq)`.
+				er.buildSemiApplyFromEqualSubq(np, lexpr, rexpr, true)
+			} else {
+				er.handleNEAny(lexpr, rexpr, np)
+			}
+		} else {
+			// TODO: Support this in future.
+			er.err = errors.New("We don't support <=> all or <=> any now")
+		}
+	default:
+		// When < all or > any , the agg function should use min.
+		useMin := ((v.Op == opcode.LT || v.Op == opcode.LE) && v.All) || ((v.Op == opcode.GT || v.Op == opcode.GE) && !v.All)
+		er.handleOtherComparableSubq(lexpr, rexpr, np, useMin, v.Op.String(), v.All)
+	}
+	if er.err != nil {
+		return v, true
+	}
+	er.ctxStackPop(1)
+	if er.asScalar {
+		// The parent expression only use the last column in schema, which represents whether the condition is matched.
+		er.ctxStackAppend(er.p.Schema().Columns[er.p.Schema().Len()-1], er.p.OutputNames()[er.p.Schema().Len()-1])
+	}
+	return v, true
+}
+
+// handleOtherComparableSubq handles the queries like < any, < max, etc. For example, if the query is t.id < any (select s.id from s),
+// it will be rewrote to t.id < (select max(s.id) from s).
+func (er *expressionRewriter) handleOtherComparableSubq(lexpr, rexpr expression.Expression, np LogicalPlan, useMin bool, cmpFunc string, all bool) {
+	plan4Agg := LogicalAggregation{}.Init(er.sctx)
+	plan4Agg.SetChildren(np)
+
+	// Create a "max" or "min" aggregation.
+	funcName := ast.AggFuncMax
+	if useMin {
+		funcName = ast.AggFuncMin
+	}
+	funcMaxOrMin, err := aggregation.NewAggFuncDesc(er.sctx, funcName, []expression.Expression{rexpr}, false)
+	if err != nil {
+		er.err = err
+		return
+	}
+
+	// Create a column and append it to the schema of that aggregation.
+	colMaxOrMin := &expression.Column{
+		UniqueID: er.sctx.GetSessionVars().AllocPlanColumnID(),
+		RetType:  funcMaxOrMin.RetTp,
+	}
+	schema := expression.NewSchema(colMaxOrMin)
+
+	plan4Agg.names = append(plan4Agg.names, types.EmptyName)
+	plan4Agg.SetSchema(schema)
+	plan4Agg.AggFuncs = []*aggregation.AggFuncDesc{funcMaxOrMin}
+
+	cond := expression.NewFunctionInternal(er.sctx, cmpFunc, types.NewFieldType(mysql.TypeTiny), lexpr, colMaxOrMin)
+	er.buildQuantifierPlan(plan4Agg, cond, lexpr, rexpr, all)
+}
+
+// buildQuantifierPlan adds extra condition for any / all subquery.
+func (er *expressionRewriter) buildQuantifierPlan(plan4Agg *LogicalAggregation, cond, lexpr, rexpr expression.Expression, all bool) {
+	innerIsNull := expression.NewFunctionInternal(er.sctx, ast.IsNull, types.NewFieldType(mysql.TypeTiny), rexpr)
+	outerIsNull := expression.NewFunctionInternal(er.sctx, ast.IsNull, types.NewFieldType(mysql.TypeTiny), lexpr)
+
+	funcSum, err := aggregation.NewAggFuncDesc(er.sctx, ast.AggFuncSum, []expression.Expression{innerIsNull}, false)
+	if err != nil {
+		er.err = err
+		return
+	}
+	colSum := &expression.Column{
+		UniqueID: er.sctx.GetSessionVars().AllocPlanColumnID(),
+		RetType:  funcSum.RetTp,
+	}
+	plan4Agg.AggFuncs = append(plan4Agg.AggFuncs, funcSum)
+	plan4Agg.schema.Append(colSum)
+	plan4Agg.names = append(plan4Agg.names, types.EmptyName)
+	innerHasNull := expression.NewFunctionInternal(er.sctx, ast.NE, types.NewFieldType(mysql.TypeTiny), colSum, expression.Zero)
+
+	// Build `count(1)` aggregation to check if subquery is empty.
+	funcCount, err := aggregation.NewAggFuncDesc(er.sctx, ast.AggFuncCount, []expression.Expression{expression.One}, false)
+	if err != nil {
+		er.err = err
+		return
+	}
+	colCount := &expression.Column{
+		UniqueID: er.sctx.GetSessionVars().AllocPlanColumnID(),
+		RetType:  funcCount.RetTp,
+	}
+	plan4Agg.AggFuncs = append(plan4Agg.AggFuncs, funcCount)
+	plan4Agg.schema.Append(colCount)
+	plan4Agg.names = append(plan4Agg.names, types.EmptyName)
+
+	if all {
+		// All of the inner record set should not contain null value. So for t.id < all(select s.id from s), it
+		// should be rewrote to t.id < min(s.id) and if(sum(s.id is null) != 0, null, true).
+		innerNullChecker := expression.NewFunctionInternal(er.sctx, ast.If, types.NewFieldType(mysql.TypeTiny), innerHasNull, expression.Null, expression.One)
+		cond = expression.ComposeCNFCondition(er.sctx, cond, innerNullChecker)
+		// If the subquery is empty, it should always return true.
+		emptyChecker := expression.NewFunctionInternal(er.sctx, ast.EQ, types.NewFieldType(mysql.TypeTiny), colCount, expression.Zero)
+		// If outer key is null, and subquery is not empty, it should always return null, even when it is `null = all (1, 2)`.
+		outerNullChecker := expression.NewFunctionInternal(er.sctx, ast.If, types.NewFieldType(mysql.TypeTiny), outerIsNull, expression.Null, expression.Zero)
+		cond = expression.ComposeDNFCondition(er.sctx, cond, emptyChecker, outerNullChecker)
+	} else {
+		// For "any" expression, if the subquery has null and the cond returns false, the result should be NULL.
+		// Specifically, `t.id < any (select s.id from s)` would be rewrote to `t.id < max(s.id) or if(sum(s.id is null) != 0, null, false)`
+		innerNullChecker := expression.NewFunctionInternal(er.sctx, ast.If, types.NewFieldType(mysql.TypeTiny), innerHasNull, expression.Null, expression.Zero)
+		cond = expression.ComposeDNFCondition(er.sctx, cond, innerNullChecker)
+		// If the subquery is empty, it should always return false.
+		emptyChecker := expression.NewFunctionInternal(er.sctx, ast.NE, types.NewFieldType(mysql.TypeTiny), colCount, expression.Zero)
+		// If outer key is null, and subquery is not empty, it should return null.
+		outerNullChecker := expression.NewFunctionInternal(er.sctx, ast.If, types.NewFieldType(mysql.TypeTiny), outerIsNull, expression.Null, expression.One)
+		cond = expression.ComposeCNFCondition(er.sctx, cond, emptyChecker, outerNullChecker)
+	}
+
+	if !er.asScalar {
+		// For Semi LogicalApply without aux column, the result is no matter false or null. So we can add it to join predicate.
+		er.p = er.b.buildSemiApply(er.p, plan4Agg, []expression.Expression{cond}, false, false)
+		return
+	}
+	// If we treat the result as a scalar value, we will add a projection with a extra column to output true, false or null.
+	outerSchemaLen := er.p.Schema().Len()
+	er.p = er.b.buildApplyWithJoinType(er.p, plan4Agg, InnerJoin)
+	joinSchema := er.p.Schema()
+	proj := LogicalProjection{
+		Exprs: expression.Column2Exprs(joinSchema.Clone().Columns[:outerSchemaLen]),
+	}.Init(er.sctx)
+	proj.names = make([]*types.FieldName, outerSchemaLen, outerSchemaLen+1)
+	copy(proj.names, er.p.OutputNames())
+	proj.SetSchema(expression.NewSchema(joinSchema.Clone().Columns[:outerSchemaLen]...))
+	proj.Exprs = append(proj.Exprs, cond)
+	proj.schema.Append(&expression.Column{
+		UniqueID: er.sctx.GetSessionVars().AllocPlanColumnID(),
+		RetType:  cond.GetType(),
+	})
+	proj.names = append(proj.names, types.EmptyName)
+	proj.SetChildren(er.p)
+	er.p = proj
+}
+
+// handleNEAny handles the case of != any. For example, if the query is t.id != any (select s.id from s), it will be rewrote to
+// t.id != max(s.id) or max(s.id) != min(s.id) or [any checker]. If there are two different values in s.id,
+// there must exist a s.id that doesn't equal to t.id.
+func (er *expressionRewriter) handleNEAny(lexpr, rexpr expression.Expression, np LogicalPlan) {
+	// If there is NULL in s.id column, s.id should be the value that isn't null in condition t.id != s.id.
+	// So use function max and min to filter NULL.
+	maxFunc, err := aggregation.NewAggFuncDesc(er.sctx, ast.AggFuncMax, []expression.Expression{rexpr}, false)
+	if err != nil {
+		er.err = err
+		return
+	}
+	minFunc, err := aggregation.NewAggFuncDesc(er.sctx, ast.AggFuncMin, []expression.Expression{rexpr}, false)
+	if err != nil {
+		er.err = err
+		return
+	}
+	plan4Agg := LogicalAggregation{
+		AggFuncs: []*aggregation.AggFuncDesc{maxFunc, minFunc},
+	}.Init(er.sctx)
+	plan4Agg.SetChildren(np)
+	maxResultCol := &expression.Column{
+		UniqueID: er.sctx.GetSessionVars().AllocPlanColumnID(),
+		RetType:  maxFunc.RetTp,
+	}
+	minResultCol := &expression.Column{
+		UniqueID: er.sctx.GetSessionVars().AllocPlanColumnID(),
+		RetType:  minFunc.RetTp,
+	}
+	plan4Agg.names = append(plan4Agg.names, types.EmptyName, types.EmptyName)
+	plan4Agg.SetSchema(expression.NewSchema(maxResultCol, minResultCol))
+	diffCond := expression.NewFunctionInternal(er.sctx, ast.NE, types.NewFieldType(mysql.TypeTiny), maxResultCol, minResultCol)
+	neCond := expression.NewFunctionInternal(er.sctx, ast.NE, types.NewFieldType(mysql.TypeTiny), lexpr, maxResultCol)
+	cond := expression.ComposeDNFCondition(er.sctx, diffCond, neCond)
+	er.buildQuantifierPlan(plan4Agg, cond, lexpr, rexpr, false)
+}
+
+// handleEQAll handles the case of = all. For example, if the query is t.id = all (select s.id from s), it will be rewrote to
+// t.id = max(s.id) and max(s.id) = min(s.id) and [all checker].
+func (er *expressionRewriter) handleEQAll(lexpr, rexpr expression.Expression, np LogicalPlan) {
+	maxFunc, err := aggregation.NewAggFuncDesc(er.sctx, ast.AggFuncMax, []expression.Expression{rexpr}, false)
+	if err != nil {
+		er.err = err
+		return
+	}
+	minFunc, err := aggregation.NewAggFuncDesc(er.sctx, ast.AggFuncMin, []expression.Expression{rexpr}, false)
+	if err != nil {
+		er.err = err
+		return
+	}
+	plan4Agg := LogicalAggregation{
+		AggFuncs: []*aggregation.AggFuncDesc{maxFunc, minFunc},
+	}.Init(er.sctx)
+	plan4Agg.SetChildren(np)
+	maxResultCol := &expression.Column{
+		UniqueID: er.sctx.GetSessionVars().AllocPlanColumnID(),
+		RetType:  maxFunc.RetTp,
+	}
+	minResultCol := &expression.Column{
+		UniqueID: er.sctx.GetSessionVars().AllocPlanColumnID(),
+		RetType:  minFunc.RetTp,
+	}
+	plan4Agg.names = append(plan4Agg.names, types.EmptyName, types.EmptyName)
+	plan4Agg.SetSchema(expression.NewSchema(maxResultCol, minResultCol))
+	sameCond := expression.NewFunctionInternal(er.sctx, ast.EQ, types.NewFieldType(mysql.TypeTiny), maxResultCol, minResultCol)
+	eqCond := expression.NewFunctionInternal(er.sctx, ast.EQ, types.NewFieldType(mysql.TypeTiny), lexpr, maxResultCol)
+	cond := expression.ComposeCNFCondition(er.sctx, sameCond, eqCond)
+	er.buildQuantifierPlan(plan4Agg, cond, lexpr, rexpr, true)
+}
+
+func (er *expressionRewriter) handleExistSubquery(ctx context.Context, v *ast.ExistsSubqueryExpr) (ast.Node, bool) {
+	subq, ok := v.Sel.(*ast.SubqueryExpr)
+	if !ok {
+		er.err = errors.Errorf("Unknown exists type %T.", v.Sel)
+		return v, true
+	}
+	np, err := er.buildSubquery(ctx, subq)
+	if err != nil {
+		er.err = err
+		return v, true
+	}
+	np = er.popExistsSubPlan(np)
+	if len(np.extractCorrelatedCols()) > 0 {
+		er.p = er.b.buildSemiApply(er.p, np, nil, er.asScalar, v.Not)
+		if !er.asScalar {
+			return v, true
+		}
+		er.ctxStackAppend(er.p.Schema().Columns[er.p.Schema().Len()-1], er.p.OutputNames()[er.p.Schema().Len()-1])
+		return v, true
+	}
+	// One row is enough to decide the result of an incorrelated exists subquery.
+	limit := LogicalLimit{Count: 1}.Init(er.sctx)
+	limit.SetChildren(np)
+	physicalPlan, err := DoOptimize(ctx, er.b.optFlag, limit)
+	if err != nil {
+		er.err = err
+		return v, true
+	}
+	rows, err := EvalSubquery(ctx, physicalPlan, er.b.is, er.b.ctx)
+	if err != nil {
+		er.err = err
+		return v, true
+	}
+	if (len(rows) > 0 && !v.Not) || (len(rows) == 0 && v.Not) {
+		er.ctxStackAppend(expression.One.Clone(), types.EmptyName)
+	} else {
+		er.ctxStackAppend(expression.Zero.Clone(), types.EmptyName)
+	}
+	return v, true
+}
+
+// popExistsSubPlan will remove the useless plan in exist's child.
+// See comments inside the method for more details.
+func (er *expressionRewriter) popExistsSubPlan(p LogicalPlan) LogicalPlan {
+out:
+	for {
+		switch plan := p.(type) {
+		// This can be removed when in exists clause,
+		// e.g. exists(select count(*) from t order by a) is equal to exists t.
+		case *LogicalProjection, *LogicalSort:
+			p = p.Children()[0]
+		case *LogicalAggregation:
+			if len(plan.GroupByItems) == 0 {
+				p = LogicalTableDual{RowCount: 1}.Init(er.sctx)
+				break out
+			}
+			p = p.Children()[0]
+		default:
+			break out
+		}
+	}
+	return p
+}
+
+func (er *expressionRewriter) handleInSubquery(ctx context.Context, v *ast.PatternInExpr) (ast.Node, bool) {
+	asScalar := er.asScalar
+	er.asScalar = true
+	v.Expr.Accept(er)
+	if er.err != nil {
+		return v, true
+	}
+	lexpr := er.ctxStack[len(er.ctxStack)-1]
+	subq, ok := v.Sel.(*ast.SubqueryExpr)
+	if !ok {
+		er.err = errors.Errorf("Unknown compare type %T.", v.Sel)
+		return v, true
+	}
+	np, err := er.buildSubquery(ctx, subq)
+	if err != nil {
+		er.err = err
+		return v, true
+	}
+	lLen := expression.GetRowLen(lexpr)
+	if lLen != np.Schema().Len() {
+		er.err = expression.ErrOperandColumns.GenWithStackByArgs(lLen)
+		return v, true
+	}
+	var rexpr expression.Expression
+	if np.Schema().Len() == 1 {
+		rexpr = np.Schema().Columns[0]
+		rCol := rexpr.(*expression.Column)
+		// For AntiSemiJoin/LeftOuterSemiJoin/AntiLeftOuterSemiJoin, we cannot treat `in` expression as
+		// normal column equal condition, so we specially mark the inner operand here.
+		if v.Not || asScalar {
+			// If both input columns of `in` expression are not null, we can treat the expression
+			// as normal column equal condition instead.
+			if !mysql.HasNotNullFlag(lexpr.GetType().Flag) || !mysql.HasNotNullFlag(rCol.GetType().Flag) {
+				rColCopy := *rCol
+				rColCopy.InOperand = true
+				rexpr = &rColCopy
+			}
+		}
+	} else {
+		args := make([]expression.Expression, 0, np.Schema().Len())
+		for _, col := range np.Schema().Columns {
+			args = append(args, col)
+		}
+		rexpr, er.err = er.newFunction(ast.RowFunc, args[0].GetType(), args...)
+		if er.err != nil {
+			return v, true
+		}
+	}
+	checkCondition, err := er.constructBinaryOpFunction(lexpr, rexpr, ast.EQ)
+	if err != nil {
+		er.err = err
+		return v, true
+	}
+
+	// If it's not the form of `not in (SUBQUERY)`,
+	// and has no correlated column from the current level plan(if the correlated column is from upper level,
+	// we can treat it as constant, because the upper LogicalApply cannot be eliminated since current node is a join node),
+	// and don't need to append a scalar value, we can rewrite it to inner join.
+	if er.sctx.GetSessionVars().GetAllowInSubqToJoinAndAgg() && !v.Not && !asScalar && len(extractCorColumnsBySchema(np, er.p.Schema())) == 0 {
+		// We need to try to eliminate the agg and the projection produced by this operation.
+		er.b.optFlag |= flagEliminateAgg
+		er.b.optFlag |= flagEliminateProjection
+		er.b.optFlag |= flagJoinReOrder
+		// Build distinct for the inner query.
+		agg, err := er.b.buildDistinct(np, np.Schema().Len())
+		if err != nil {
+			er.err = err
+			return v, true
+		}
+		// Build inner join above the aggregation.
+		join := LogicalJoin{JoinType: InnerJoin}.Init(er.sctx)
+		join.SetChildren(er.p, agg)
+		join.SetSchema(expression.MergeSchema(er.p.Schema(), agg.schema))
+		join.names = make([]*types.FieldName, er.p.Schema().Len()+agg.Schema().Len())
+		copy(join.names, er.p.OutputNames())
+		copy(join.names[er.p.Schema().Len():], agg.OutputNames())
+		join.attachOnConds(expression.SplitCNFItems(checkCondition))
+		er.p = join
+	} else {
+		er.p = er.b.buildSemiApply(er.p, np, expression.SplitCNFItems(checkCondition), asScalar, v.Not)
+	}
+
+	er.ctxStackPop(1)
+	if asScalar {
+		col := er.p.Schema().Columns[er.p.Schema().Len()-1]
+		er.ctxStackAppend(col, er.p.OutputNames()[er.p.Schema().Len()-1])
+	}
+	return v, true
+}
+
+func (er *expressionRewriter) handleScalarSubquery(ctx context.Context, v *ast.SubqueryExpr) (ast.Node, bool) {
+	np, err := er.buildSubquery(ctx, v)
+	if err != nil {
+		er.err = err
+		return v, true
+	}
+	np = er.b.buildMaxOneRow(np)
+	if len(np.extractCorrelatedCols()) > 0 {
+		er.p = er.b.buildApplyWithJoinType(er.p, np, LeftOuterJoin)
+		if np.Schema().Len() > 1 {
+			newCols := make([]expression.Expression, 0, np.Schema().Len())
+			for _, col := range np.Schema().Columns {
+				newCols = append(newCols, col)
+			}
+			expr, err1 := er.newFunction(ast.RowFunc, newCols[0].GetType(), newCols...)
+			if err1 != nil {
+				er.err = err1
+				return v, true
+			}
+			er.ctxStackAppend(expr, types.EmptyName)
+		} else {
+			er.ctxStackAppend(er.p.Schema().Columns[er.p.Schema().Len()-1], er.p.OutputNames()[er.p.Schema().Len()-1])
+		}
+		return v, true
+	}
+	physicalPlan, err := DoOptimize(ctx, er.b.optFlag, np)
+	if err != nil {
+		er.err = err
+		return v, true
+	}
+	rows, err := EvalSubquery(ctx, physicalPlan, er.b.is, er.b.ctx)
+	if err != nil {
+		er.err = err
+		return v, true
+	}
+	if np.Schema().Len() > 1 {
+		newCols := make([]expression.Expression, 0, np.Schema().Len())
+		for i, data := range rows[0] {
+			newCols = append(newCols, &expression.Constant{
+				Value:   data,
+				RetType: np.Schema().Columns[i].GetType()})
+		}
+		expr, err1 := er.newFunction(ast.RowFunc, newCols[0].GetType(), newCols...)
+		if err1 != nil {
+			er.err = err1
+			return v, true
+		}
+		er.ctxStackAppend(expr, types.EmptyName)
+	} else {
+		er.ctxStackAppend(&expression.Constant{
+			Value:   rows[0][0],
+			RetType: np.Schema().Columns[0].GetType(),
+		}, types.EmptyName)
+	}
+	return v, true
+}
+
 // Leave implements Visitor interface.
 func (er *expressionRewriter) Leave(originInNode ast.Node) (retNode ast.Node, ok bool) {
 	if er.err != nil {
@@ -300,14 +839,30 @@ func (er *expressionRewriter) Leave(originInNode ast.Node) (retNode ast.Node, ok
 		inNode = er.preprocess(inNode)
 	}
 	switch v := inNode.(type) {
-	case *ast.AggregateFuncExpr, *ast.ColumnNameExpr, *ast.ParenthesesExpr, *ast.ValuesExpr:
+	case *ast.AggregateFuncExpr, *ast.ColumnNameExpr, *ast.ParenthesesExpr, *ast.WhenClause, *ast.WindowFuncExpr,
+		*ast.SubqueryExpr, *ast.ExistsSubqueryExpr, *ast.CompareSubqueryExpr, *ast.ValuesExpr:
 	case *driver.ValueExpr:
 		value := &expression.Constant{Value: v.Datum, RetType: &v.Type}
 		er.ctxStackAppend(value, types.EmptyName)
+	case *driver.ParamMarkerExpr:
+		var value expression.Expression
+		value, er.err = expression.ParamMarkerExpression(er.sctx, v)
+		if er.err != nil {
+			return retNode, false
+		}
+		er.ctxStackAppend(value, types.EmptyName)
 	case *ast.VariableExpr:
 		er.rewriteVariable(v)
 	case *ast.FuncCallExpr:
 		er.funcCallToExpression(v)
+	case *ast.FuncCastExpr:
+		arg := er.ctxStack[len(er.ctxStack)-1]
+		er.err = expression.CheckArgsNotMultiColumnRow(arg)
+		if er.err != nil {
+			return retNode, false
+		}
+		er.ctxStack[len(er.ctxStack)-1] = expression.BuildCastFunction(er.sctx, arg, v.Tp)
+		er.ctxNameStk[len(er.ctxNameStk)-1] = types.EmptyName
 	case *ast.ColumnName:
 		er.toColumn(v)
 	case *ast.UnaryOperationExpr:
@@ -316,14 +871,27 @@ func (er *expressionRewriter) Leave(originInNode ast.Node) (retNode ast.Node, ok
 		er.binaryOpToExpression(v)
 	case *ast.BetweenExpr:
 		er.betweenToExpression(v)
+	case *ast.CaseExpr:
+		er.caseToExpression(v)
 	case *ast.RowExpr:
 		er.rowToScalarFunc(v)
 	case *ast.PatternInExpr:
-		er.inToExpression(len(v.List), v.Not, &v.Type)
+		if v.Sel == nil {
+			er.inToExpression(len(v.List), v.Not, &v.Type)
+		}
 	case *ast.IsNullExpr:
 		er.isNullToExpression(v)
+	case *ast.PatternLikeExpr:
+		er.patternLikeToExpression(v)
+	case *ast.PatternRegexpExpr:
+		er.regexpToScalarFunc(v)
 	case *ast.DefaultExpr:
 		er.evalDefaultExpr(v)
+	case *ast.TimeUnitExpr:
+		er.ctxStackAppend(&expression.Constant{
+			Value:   types.NewStringDatum(v.Unit.String()),
+			RetType: types.NewFieldType(mysql.TypeVarString),
+		}, types.EmptyName)
 	default:
 		er.err = errors.Errorf("UnknownType: %T", v)
 		return retNode, false
@@ -469,6 +1037,60 @@ func (er *expressionRewriter) isNullToExpression(v *ast.IsNullExpr) {
 	er.ctxStackAppend(function, types.EmptyName)
 }
 
+func (er *expressionRewriter) patternLikeToExpression(v *ast.PatternLikeExpr) {
+	l := len(er.ctxStack)
+	er.err = expression.CheckArgsNotMultiColumnRow(er.ctxStack[l-2:]...)
+	if er.err != nil {
+		return
+	}
+
+	var function expression.Expression
+	isPatternExactMatch := false
+	// Treat predicate 'like' the same way as predicate '=' when it is an exact match.
+	if patExpression, ok := er.ctxStack[l-1].(*expression.Constant); ok {
+		patString, isNull, err := patExpression.EvalString(nil, chunk.Row{})
+		if err != nil {
+			er.err = err
+			return
+		}
+		if !isNull {
+			patValue, patTypes := stringutil.CompilePattern(patString, v.Escape)
+			if stringutil.IsExactMatch(patTypes) && er.ctxStack[l-2].GetType().EvalType() == types.ETString {
+				op := ast.EQ
+				if v.Not {
+					op = ast.NE
+				}
+				fieldType := &types.FieldType{}
+				types.DefaultTypeForValue(string(patValue), fieldType)
+				function, er.err = er.constructBinaryOpFunction(er.ctxStack[l-2],
+					&expression.Constant{Value: types.NewStringDatum(string(patValue)), RetType: fieldType},
+					op)
+				isPatternExactMatch = true
+			}
+		}
+	}
+	if !isPatternExactMatch {
+		fieldType := &types.FieldType{}
+		types.DefaultTypeForValue(int64(v.Escape), fieldType)
+		function = er.notToExpression(v.Not, ast.Like, &v.Type,
+			er.ctxStack[l-2], er.ctxStack[l-1], &expression.Constant{Value: types.NewIntDatum(int64(v.Escape)), RetType: fieldType})
+	}
+
+	er.ctxStackPop(2)
+	er.ctxStackAppend(function, types.EmptyName)
+}
+
+func (er *expressionRewriter) regexpToScalarFunc(v *ast.PatternRegexpExpr) {
+	l := len(er.ctxStack)
+	er.err = expression.CheckArgsNotMultiColumnRow(er.ctxStack[l-2:]...)
+	if er.err != nil {
+		return
+	}
+	function := er.notToExpression(v.Not, ast.Regexp, &v.Type, er.ctxStack[l-2], er.ctxStack[l-1])
+	er.ctxStackPop(2)
+	er.ctxStackAppend(function, types.EmptyName)
+}
+
 // inToExpression converts in expression to a scalar function. The argument lLen means the length of in list.
 // The argument not means if the expression is not in. The tp stands for the expression type, which is always bool.
 // a in (b, c, d) will be rewritten as `(a = b) or (a = c) or (a = d)`.
@@ -539,6 +1161,33 @@ func (er *expressionRewriter) rowToScalarFunc(v *ast.RowExpr) {
 	er.ctxStackAppend(function, types.EmptyName)
 }
 
+// wrapExpWithCast wraps the three arguments of BETWEEN with casts, so that
+// they are compared using the same type.
+func (er *expressionRewriter) wrapExpWithCast() (expr, lexp, rexp expression.Expression) {
+	stkLen := len(er.ctxStack)
+	expr, lexp, rexp = er.ctxStack[stkLen-3], er.ctxStack[stkLen-2], er.ctxStack[stkLen-1]
+	var castFunc func(sessionctx.Context, expression.Expression) expression.Expression
+	switch expression.ResolveType4Between([3]expression.Expression{expr, lexp, rexp}) {
+	case types.ETInt:
+		castFunc = expression.WrapWithCastAsInt
+	case types.ETReal:
+		castFunc = expression.WrapWithCastAsReal
+	case types.ETDecimal:
+		castFunc = expression.WrapWithCastAsDecimal
+	case types.ETString:
+		castFunc = func(ctx sessionctx.Context, e expression.Expression) expression.Expression {
+			// string kind expression do not need cast
+			if e.GetType().EvalType().IsStringKind() {
+				return e
+			}
+			return expression.WrapWithCastAsString(ctx, e)
+		}
+	default:
+		return
+	}
+	return castFunc(er.sctx, expr), castFunc(er.sctx, lexp), castFunc(er.sctx, rexp)
+}
+
 func (er *expressionRewriter) betweenToExpression(v *ast.BetweenExpr) {
 	stkLen := len(er.ctxStack)
 	er.err = expression.CheckArgsNotMultiColumnRow(er.ctxStack[stkLen-3:]...)
@@ -546,7 +1195,7 @@ func (er *expressionRewriter) betweenToExpression(v *ast.BetweenExpr) {
 		return
 	}
 
-	expr, lexp, rexp := er.ctxStack[stkLen-3], er.ctxStack[stkLen-2], er.ctxStack[stkLen-1]
+	expr, lexp, rexp := er.wrapExpWithCast()
 
 	var op string
 	var l, r expression.Expression
@@ -574,6 +1223,60 @@ func (er *expressionRewriter) betweenToExpression(v *ast.BetweenExpr) {
 	er.ctxStackAppend(function, types.EmptyName)
 }
 
+func (er *expressionRewriter) caseToExpression(v *ast.CaseExpr) {
+	stkLen := len(er.ctxStack)
+	argsLen := 2 * len(v.WhenClauses)
+	if v.ElseClause != nil {
+		argsLen++
+	}
+	er.err = expression.CheckArgsNotMultiColumnRow(er.ctxStack[stkLen-argsLen:]...)
+	if er.err != nil {
+		return
+	}
+
+	// value                          -> ctxStack[stkLen-argsLen-1]
+	// when clause(condition, result) -> ctxStack[stkLen-argsLen:stkLen-1];
+	// else clause                    -> ctxStack[stkLen-1]
+	var args []expression.Expression
+	if v.Value != nil {
+		// args:  eq scalar func(args: value, condition1), result1,
+		//        eq scalar func(args: value, condition2), result2,
+		//        ...
+		//        else clause
+		value := er.ctxStack[stkLen-argsLen-1]
+		er.err = expression.CheckArgsNotMultiColumnRow(value)
+		if er.err != nil {
+			return
+		}
+		args = make([]expression.Expression, 0, argsLen)
+		for i := stkLen - argsLen; i < stkLen-1; i += 2 {
+			arg, err := er.newFunction(ast.EQ, types.NewFieldType(mysql.TypeTiny), value, er.ctxStack[i])
+			if err != nil {
+				er.err = err
+				return
+			}
+			args = append(args, arg, er.ctxStack[i+1])
+		}
+		if v.ElseClause != nil {
+			args = append(args, er.ctxStack[stkLen-1])
+		}
+		argsLen++ // for trimming the value element later
+	} else {
+		// args:  condition1, result1,
+		//        condition2, result2,
+		//        ...
+		//        else clause
+		args = er.ctxStack[stkLen-argsLen:]
+	}
+	function, err := er.newFunction(ast.Case, &v.Type, args...)
+	if err != nil {
+		er.err = err
+		return
+	}
+	er.ctxStackPop(argsLen)
+	er.ctxStackAppend(function, types.EmptyName)
+}
+
 // rewriteFuncCall handles a FuncCallExpr and generates a customized function.
 // It should return true if for the given FuncCallExpr a rewrite is performed so that original behavior is skipped.
 // Otherwise it should return false to indicate (the caller) that original behavior needs to be performed.
@@ -599,6 +1302,36 @@ func (er *expressionRewriter) rewriteFuncCall(v *ast.FuncCallExpr) bool {
 		}
 
 		return false
+	case ast.Nullif:
+		if len(v.Args) != 2 {
+			er.err = expression.ErrIncorrectParameterCount.GenWithStackByArgs(v.FnName.O)
+			return true
+		}
+		stackLen := len(er.ctxStack)
+		param1 := er.ctxStack[stackLen-2]
+		param2 := er.ctxStack[stackLen-1]
+		// param1 = param2
+		funcCompare, err := er.constructBinaryOpFunction(param1, param2, ast.EQ)
+		if err != nil {
+			er.err = err
+			return true
+		}
+		// NULL
+		nullTp := types.NewFieldType(mysql.TypeNull)
+		nullTp.Flen, nullTp.Decimal = mysql.GetDefaultFieldLengthAndDecimal(mysql.TypeNull)
+		paramNull := &expression.Constant{
+			Value:   types.NewDatum(nil),
+			RetType: nullTp,
+		}
+		// if(param1 = param2, NULL, param1)
+		funcIf, err := er.newFunction(ast.If, &v.Type, funcCompare, paramNull, param1)
+		if err != nil {
+			er.err = err
+			return true
+		}
+		er.ctxStackPop(len(v.Args))
+		er.ctxStackAppend(funcIf, types.EmptyName)
+		return true
 	default:
 		return false
 	}
@@ -618,7 +1351,23 @@ func (er *expressionRewriter) funcCallToExpression(v *ast.FuncCallExpr) {
 
 	var function expression.Expression
 	er.ctxStackPop(len(v.Args))
-	function, er.err = er.newFunction(v.FnName.L, &v.Type, args...)
+	if _, ok := expression.DeferredFunctions[v.FnName.L]; er.sctx.GetSessionVars().StmtCtx.UseCache && ok {
+		// The result of functions like NOW() changes between executions, so
+		// they are wrapped in a deferred constant instead of being folded into
+		// the cached plan.
+		function, er.err = expression.NewFunctionBase(er.sctx, v.FnName.L, &v.Type, args...)
+		if er.err != nil {
+			return
+		}
+		var value types.Datum
+		value, er.err = function.Eval(chunk.Row{})
+		if er.err != nil {
+			return
+		}
+		function = &expression.Constant{Value: value, RetType: function.GetType(), DeferredExpr: function}
+	} else {
+		function, er.err = er.newFunction(v.FnName.L, &v.Type, args...)
+	}
 	er.ctxStackAppend(function, types.EmptyName)
 }
 
@@ -633,6 +1382,19 @@ func (er *expressionRewriter) toColumn(v *ast.ColumnName) {
 		er.ctxStackAppend(column, er.names[idx])
 		return
 	}
+	for i := len(er.b.outerSchemas) - 1; i >= 0; i-- {
+		outerSchema, outerName := er.b.outerSchemas[i], er.b.outerNames[i]
+		idx, err = expression.FindFieldName(outerName, v)
+		if idx >= 0 {
+			column := outerSchema.Columns[idx]
+			er.ctxStackAppend(&expression.CorrelatedColumn{Column: *column, Data: new(types.Datum)}, outerName[idx])
+			return
+		}
+		if err != nil {
+			er.err = ErrAmbiguous.GenWithStackByArgs(v.Name, clauseMsg[fieldList])
+			return
+		}
+	}
 	if er.b.curClause == globalOrderByClause {
 		er.b.curClause = orderByClause
 	}
@@ -14,12 +14,15 @@
 package core
 
 import (
+	"context"
 	"fmt"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tidb/util/testleak"
 )
@@ -66,10 +69,107 @@ func (s *testExpressionSuite) TestBetween(c *C) {
 	tests := []testCase{
 		{exprStr: "1 between 2 and 3", resultStr: "0"},
 		{exprStr: "1 not between 2 and 3", resultStr: "1"},
+		{exprStr: "'2' between 1 and 3", resultStr: "1"},
+		{exprStr: "'10' between '1' and '2'", resultStr: "1"},
+		{exprStr: "2.5 between 2 and '3'", resultStr: "1"},
+		{exprStr: "null between 1 and 2", resultStr: "<nil>"},
 	}
 	s.runTests(c, tests)
 }
 
+func (s *testExpressionSuite) TestCaseWhen(c *C) {
+	defer testleak.AfterTest(c)()
+	tests := []testCase{
+		{exprStr: "case 1 when 1 then 'str1' when 2 then 'str2' end", resultStr: "str1"},
+		{exprStr: "case 2 when 1 then 'str1' when 2 then 'str2' end", resultStr: "str2"},
+		{exprStr: "case 3 when 1 then 'str1' when 2 then 'str2' end", resultStr: "<nil>"},
+		{exprStr: "case 4 when 1 then 'str1' when 2 then 'str2' else 'str3' end", resultStr: "str3"},
+		{exprStr: "case when 1 > 2 then 1 when 2 > 1 then 2 end", resultStr: "2"},
+		{exprStr: "case when null then 1 else 2 end", resultStr: "2"},
+		{exprStr: "case null when null then 1 else 2 end", resultStr: "2"},
+	}
+	s.runTests(c, tests)
+}
+
+func (s *testExpressionSuite) TestControlFunctions(c *C) {
+	defer testleak.AfterTest(c)()
+	tests := []testCase{
+		{exprStr: "nullif(1, 1)", resultStr: "<nil>"},
+		{exprStr: "nullif(1, 2)", resultStr: "1"},
+		{exprStr: "nullif(null, 1)", resultStr: "<nil>"},
+		{exprStr: "coalesce(null, 2, 3)", resultStr: "2"},
+		{exprStr: "coalesce(null, null)", resultStr: "<nil>"},
+		{exprStr: "greatest(1, 3, 2)", resultStr: "3"},
+		{exprStr: "least('b', 'a', 'c')", resultStr: "a"},
+		{exprStr: "greatest(1, null, 3)", resultStr: "<nil>"},
+	}
+	s.runTests(c, tests)
+}
+
+func (s *testExpressionSuite) TestPatternLikeAndRegexp(c *C) {
+	defer testleak.AfterTest(c)()
+	tests := []testCase{
+		{exprStr: "'abc' like 'a%'", resultStr: "1"},
+		{exprStr: "'abc' like 'a_'", resultStr: "0"},
+		{exprStr: "'abc' not like 'a%'", resultStr: "0"},
+		{exprStr: "'abc' like 'abc'", resultStr: "1"},
+		{exprStr: "'a%c' like 'a|%c' escape '|'", resultStr: "1"},
+		{exprStr: "'abc' like 'a|%c' escape '|'", resultStr: "0"},
+		{exprStr: "null like 'a%'", resultStr: "<nil>"},
+		{exprStr: "'abc' regexp '^a.c$'", resultStr: "1"},
+		{exprStr: "'abc' rlike 'x'", resultStr: "0"},
+		{exprStr: "'abc' not regexp 'b'", resultStr: "0"},
+	}
+	s.runTests(c, tests)
+}
+
+func (s *testExpressionSuite) TestTimeFunctions(c *C) {
+	defer testleak.AfterTest(c)()
+	tests := []testCase{
+		{exprStr: "date_add('2011-11-11', interval 1 day)", resultStr: "2011-11-12"},
+		{exprStr: "date_sub('2011-11-11 10:10:10', interval '1:1' hour_minute)", resultStr: "2011-11-11 09:09:10"},
+		{exprStr: "'2011-11-11' + interval 1 month", resultStr: "2011-12-11"},
+		{exprStr: "adddate('2011-11-11', interval 1 week)", resultStr: "2011-11-18"},
+		{exprStr: "extract(year_month from '2011-11-11 10:10:10')", resultStr: "201111"},
+		{exprStr: "timestampdiff(month, '2003-02-01', '2003-05-01')", resultStr: "3"},
+		{exprStr: "datediff('2004-05-21', '2003-05-21')", resultStr: "366"},
+		{exprStr: "date_format('2011-11-11', '%Y/%m/%d')", resultStr: "2011/11/11"},
+		{exprStr: "year('2011-11-11')", resultStr: "2011"},
+		{exprStr: "month(null)", resultStr: "<nil>"},
+	}
+	s.runTests(c, tests)
+}
+
+func (s *testExpressionSuite) TestDeferredTimeFunctions(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := s.ctx.GetSessionVars().StmtCtx
+	rewrite := func(exprStr string) expression.Expression {
+		b := NewPlanBuilder(s.ctx, nil)
+		expr, _, err := b.rewrite(context.TODO(), s.parseExpr(c, exprStr), LogicalTableDual{}.Init(s.ctx), nil, true)
+		c.Assert(err, IsNil)
+		return expr
+	}
+
+	// Without the plan cache, NOW() is folded to its value.
+	con, ok := rewrite("now()").(*expression.Constant)
+	c.Assert(ok, IsTrue)
+	c.Assert(con.DeferredExpr, IsNil)
+
+	// A cached plan is executed many times, so NOW() must be evaluated in every execution.
+	sc.UseCache = true
+	defer func() {
+		sc.UseCache = false
+	}()
+	for _, exprStr := range []string{"now()", "curdate()", "unix_timestamp()", "date_add(now(), interval 1 day)"} {
+		con, ok = rewrite(exprStr).(*expression.Constant)
+		c.Assert(ok, IsTrue, Commentf("for %s", exprStr))
+		c.Assert(con.DeferredExpr, NotNil, Commentf("for %s", exprStr))
+		val, err := con.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(val.IsNull(), IsFalse, Commentf("for %s", exprStr))
+	}
+}
+
 func (s *testExpressionSuite) TestPatternIn(c *C) {
 	defer testleak.AfterTest(c)()
 	tests := []testCase{
@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/ranger"
 	"golang.org/x/tools/container/intsets"
 )
 
@@ -39,6 +40,11 @@ var aggFuncFactor = map[string]float64{
 	ast.AggFuncFirstRow: 0.1,
 	ast.AggFuncMax:      1.0,
 	ast.AggFuncMin:      1.0,
+	ast.AggFuncBitAnd:   1.0,
+	ast.AggFuncBitOr:    1.0,
+	ast.AggFuncBitXor:   1.0,
+	ast.AggFuncVarPop:   3.0,
+	ast.AggFuncVarSamp:  3.0,
 	"default":           1.5,
 }
 
@@ -73,6 +79,32 @@ func (p *LogicalTableDual) findBestTask(prop *property.PhysicalProperty) (task,
 	return &rootTask{p: dual}, nil
 }
 
+func (p *LogicalCTE) findBestTask(prop *property.PhysicalProperty) (task, error) {
+	if !prop.IsEmpty() {
+		return invalidTask, nil
+	}
+	pcte := PhysicalCTE{
+		SeedPlan:  p.cte.seedPartPhysicalPlan,
+		RecurPlan: p.cte.recursivePartPhysicalPlan,
+		CTE:       p.cte,
+		cteAsName: p.cteAsName,
+	}.Init(p.ctx, p.stats)
+	pcte.SetSchema(p.schema)
+	return &rootTask{p: pcte}, nil
+}
+
+func (p *LogicalCTETable) findBestTask(prop *property.PhysicalProperty) (task, error) {
+	if !prop.IsEmpty() {
+		return invalidTask, nil
+	}
+	pcteTable := PhysicalCTETable{
+		IDForStorage: p.cte.IDForStorage,
+		name:         p.name,
+	}.Init(p.ctx, p.stats)
+	pcteTable.SetSchema(p.schema)
+	return &rootTask{p: pcteTable}, nil
+}
+
 func (p *LogicalShow) findBestTask(prop *property.PhysicalProperty) (task, error) {
 	if !prop.IsEmpty() {
 		return invalidTask, nil
@@ -93,6 +125,11 @@ func (p *LogicalShowDDLJobs) findBestTask(prop *property.PhysicalProperty) (task
 
 // findBestTask implements LogicalPlan interface.
 func (p *baseLogicalPlan) findBestTask(prop *property.PhysicalProperty) (bestTask task, err error) {
+	// If p is an inner plan in an IndexJoin, the IndexJoin will generate an inner plan by itself,
+	// and set inner child prop nil, so here we do nothing.
+	if prop == nil {
+		return nil, nil
+	}
 	// Look up the task with this prop in the task map.
 	// It's used to reduce double counting.
 	bestTask = p.getTask(prop)
@@ -179,7 +216,7 @@ func (p *LogicalMemTable) findBestTask(prop *property.PhysicalProperty) (t task,
 // tryToGetDualTask will check if the push down predicate has false constant. If so, it will return table dual.
 func (ds *DataSource) tryToGetDualTask() (task, error) {
 	for _, cond := range ds.pushedDownConds {
-		if _, ok := cond.(*expression.Constant); ok {
+		if con, ok := cond.(*expression.Constant); ok && !expression.ContainMutableConst([]expression.Expression{con}) {
 			result, _, err := expression.EvalBool(ds.ctx, []expression.Expression{cond}, chunk.Row{})
 			if err != nil {
 				return nil, err
@@ -294,7 +331,7 @@ func (ds *DataSource) skylinePruning(prop *property.PhysicalProperty) []*candida
 	candidates := make([]*candidatePath, 0, 4)
 	for _, path := range ds.possibleAccessPaths {
 		// if we already know the range of the scan is empty, just return a TableDual
-		if len(path.Ranges) == 0 {
+		if len(path.Ranges) == 0 && !ds.ctx.GetSessionVars().StmtCtx.UseCache {
 			return []*candidatePath{{path: path}}
 		}
 		var currentCandidate *candidatePath
@@ -334,6 +371,11 @@ func (ds *DataSource) skylinePruning(prop *property.PhysicalProperty) []*candida
 // findBestTask implements the PhysicalPlan interface.
 // It will enumerate all the available indices and choose a plan with least cost.
 func (ds *DataSource) findBestTask(prop *property.PhysicalProperty) (t task, err error) {
+	// If ds is an inner plan in an IndexJoin, the IndexJoin will generate an inner plan by itself,
+	// and set inner child prop nil, so here we do nothing.
+	if prop == nil {
+		return nil, nil
+	}
 	t = ds.getTask(prop)
 	if t != nil {
 		return
@@ -378,7 +420,7 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty) (t task, err
 	for _, candidate := range candidates {
 		path := candidate.path
 		// if we already know the range of the scan is empty, just return a TableDual
-		if len(path.Ranges) == 0 {
+		if len(path.Ranges) == 0 && !ds.ctx.GetSessionVars().StmtCtx.UseCache {
 			dual := PhysicalTableDual{}.Init(ds.ctx, ds.stats)
 			dual.SetSchema(ds.schema)
 			return &rootTask{
@@ -497,6 +539,130 @@ func (ds *DataSource) convertToIndexScan(prop *property.PhysicalProperty, candid
 	return task, nil
 }
 
+// constructInnerTableScanTask is specially used to construct the inner plan for PhysicalIndexJoin.
+// The ranges of the table scan are decided by the handles from the outer rows,
+// rowCount is the estimated count of inner rows joined with one outer row.
+func (ds *DataSource) constructInnerTableScanTask(pk *expression.Column, outerJoinKeys []*expression.Column, rowCount float64) task {
+	ranges := ranger.FullIntRange(mysql.HasUnsignedFlag(pk.RetType.Flag))
+	ts := PhysicalTableScan{
+		Table:           ds.tableInfo,
+		Columns:         ds.Columns,
+		TableAsName:     ds.TableAsName,
+		DBName:          ds.DBName,
+		filterCondition: ds.pushedDownConds,
+		Ranges:          ranges,
+		rangeDecidedBy:  outerJoinKeys,
+	}.Init(ds.ctx)
+	ts.SetSchema(ds.schema.Clone())
+	if rowCount <= 0 {
+		rowCount = float64(1)
+	}
+	selectivity := float64(1)
+	countAfterAccess := rowCount
+	if len(ts.filterCondition) > 0 {
+		var err error
+		selectivity, err = ds.tableStats.HistColl.Selectivity(ds.ctx, ts.filterCondition, ds.possibleAccessPaths)
+		if err != nil || selectivity <= 0 {
+			selectivity = selectionFactor
+		}
+		// rowCount is computed from result row count of join, which has already accounted the filters on DataSource,
+		// i.e, rowCount equals to `countAfterAccess * selectivity`.
+		countAfterAccess = rowCount / selectivity
+	}
+	ts.stats = &property.StatsInfo{
+		// TableScan as inner child of IndexJoin can return at most 1 tuple for each outer row.
+		RowCount:     math.Min(1.0, countAfterAccess),
+		StatsVersion: ds.stats.StatsVersion,
+	}
+	rowSize := ds.TblColHists.GetTableAvgRowSize(ds.TblCols)
+	sessVars := ds.ctx.GetSessionVars()
+	copTask := &copTask{
+		tablePlan:         ts,
+		indexPlanFinished: true,
+		cst:               sessVars.ScanFactor * rowSize * ts.stats.RowCount,
+		tblColHists:       ds.TblColHists,
+	}
+	selStats := ts.stats.Scale(selectivity)
+	ts.addPushedDownSelection(copTask, selStats)
+	return finishCopTask(ds.ctx, copTask)
+}
+
+// constructInnerIndexScanTask is specially used to construct the inner plan for PhysicalIndexJoin.
+// The ranges of the index scan are decided by the outer rows, rowCount is the
+// estimated count of inner rows joined with one outer row.
+func (ds *DataSource) constructInnerIndexScanTask(path *util.AccessPath, filterConds []expression.Expression, rangeInfo string, rowCount float64) task {
+	is := PhysicalIndexScan{
+		Table:            ds.tableInfo,
+		TableAsName:      ds.TableAsName,
+		DBName:           ds.DBName,
+		Columns:          ds.Columns,
+		Index:            path.Index,
+		IdxCols:          path.IdxCols,
+		IdxColLens:       path.IdxColLens,
+		dataSourceSchema: ds.schema,
+		Ranges:           ranger.FullRange(),
+		rangeInfo:        rangeInfo,
+	}.Init(ds.ctx)
+	cop := &copTask{
+		indexPlan:   is,
+		tblColHists: ds.TblColHists,
+		tblCols:     ds.TblCols,
+	}
+	if !isCoveringIndex(ds.schema.Columns, path.FullIdxCols, path.FullIdxColLens, is.Table.PKIsHandle) {
+		// On this way, it's double read case.
+		ts := PhysicalTableScan{
+			Columns:     ds.Columns,
+			Table:       is.Table,
+			TableAsName: ds.TableAsName,
+		}.Init(ds.ctx)
+		ts.SetSchema(ds.schema.Clone())
+		cop.tablePlan = ts
+	}
+	is.initSchema(path.Index, path.FullIdxCols, cop.tablePlan != nil)
+	indexConds, tblConds := splitIndexFilterConditions(filterConds, path.FullIdxCols, path.FullIdxColLens, ds.tableInfo)
+	// Specially handle cases when input rowCount is 0, which can only happen in 2 scenarios:
+	// - estimated row count of outer plan is 0;
+	// - estimated row count of inner "DataSource + filters" is 0;
+	// if it is the first case, it does not matter what row count we set for inner task, since the cost of index join would
+	// always be 0 then;
+	// if it is the second case, HashJoin should always be cheaper than IndexJoin then, so we set row count of inner task
+	// to table size, to simply make it more expensive.
+	if rowCount <= 0 {
+		rowCount = ds.tableStats.RowCount
+	}
+	tmpPath := &util.AccessPath{
+		IndexFilters:     indexConds,
+		TableFilters:     tblConds,
+		CountAfterIndex:  rowCount,
+		CountAfterAccess: rowCount,
+	}
+	// Assume equal conditions used by index join and other conditions are independent.
+	if len(tblConds) > 0 {
+		selectivity, err := ds.tableStats.HistColl.Selectivity(ds.ctx, tblConds, ds.possibleAccessPaths)
+		if err != nil || selectivity <= 0 {
+			selectivity = selectionFactor
+		}
+		// rowCount is computed from result row count of join, which has already accounted the filters on DataSource,
+		// i.e, rowCount equals to `countAfterIndex * selectivity`.
+		tmpPath.CountAfterIndex = rowCount / selectivity
+		tmpPath.CountAfterAccess = tmpPath.CountAfterIndex
+	}
+	if len(indexConds) > 0 {
+		selectivity, err := ds.tableStats.HistColl.Selectivity(ds.ctx, indexConds, ds.possibleAccessPaths)
+		if err != nil || selectivity <= 0 {
+			selectivity = selectionFactor
+		}
+		tmpPath.CountAfterAccess = tmpPath.CountAfterIndex / selectivity
+	}
+	is.stats = ds.tableStats.ScaleByExpectCnt(tmpPath.CountAfterAccess)
+	rowSize := is.indexScanRowSize(path.Index, ds, true)
+	sessVars := ds.ctx.GetSessionVars()
+	cop.cst = tmpPath.CountAfterAccess * rowSize * sessVars.ScanFactor
+	finalStats := ds.tableStats.ScaleByExpectCnt(rowCount)
+	is.addPushedDownSelection(cop, ds, tmpPath, finalStats)
+	return finishCopTask(ds.ctx, cop)
+}
+
 func (is *PhysicalIndexScan) indexScanRowSize(idx *model.IndexInfo, ds *DataSource, isForScan bool) float64 {
 	scanCols := make([]*expression.Column, 0, len(idx.Columns)+1)
 	// If `initSchema` has already appended the handle column in schema, just use schema columns, otherwise, add extra handle column.
@@ -14,8 +14,10 @@
 package core
 
 import (
+	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/planner/property"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
 )
 
 const (
@@ -27,6 +29,8 @@ const (
 	TypeAgg = "Aggregation"
 	// TypeHashAgg is the type of HashAgg.
 	TypeHashAgg = "HashAgg"
+	// TypeStreamAgg is the type of StreamAgg.
+	TypeStreamAgg = "StreamAgg"
 	// TypeShow is the type of show.
 	TypeShow = "Show"
 	// TypeJoin is the type of Join.
@@ -51,14 +55,22 @@ const (
 	TypeHashRightJoin = "HashRightJoin"
 	// TypeMergeJoin is the type of merge join.
 	TypeMergeJoin = "MergeJoin"
+	// TypeIndexJoin is the type of index look up join.
+	TypeIndexJoin = "IndexJoin"
 	// TypeApply is the type of Apply.
 	TypeApply = "Apply"
 	// TypeMaxOneRow is the type of MaxOneRow.
 	TypeMaxOneRow = "MaxOneRow"
+	// TypeLock is the type of SelectLock.
+	TypeLock = "SelectLock"
+	// TypeUnion is the type of Union.
+	TypeUnion = "Union"
 	// TypeDual is the type of TableDual.
 	TypeDual = "TableDual"
 	// TypeInsert is the type of Insert
 	TypeInsert = "Insert"
+	// TypeUpdate is the type of Update.
+	TypeUpdate = "Update"
 	// TypeDelete is the type of Delete.
 	TypeDelete = "Delete"
 	// TypeIndexLookUp is the type of IndexLookUp.
@@ -71,6 +83,16 @@ const (
 	TypeTiKVSingleGather = "TiKVSingleGather"
 	// TypeShowDDLJobs is the type of show ddl jobs.
 	TypeShowDDLJobs = "ShowDDLJobs"
+	// TypeWindow is the type of Window.
+	TypeWindow = "Window"
+	// TypeCTE is the type of CTE.
+	TypeCTE = "CTE"
+	// TypeCTETable is the type of CTETable.
+	TypeCTETable = "CTETable"
+	// TypePointGet is the type of PointGetPlan.
+	TypePointGet = "Point_Get"
+	// TypeBatchPointGet is the type of BatchPointGetPlan.
+	TypeBatchPointGet = "Batch_Point_Get"
 )
 
 // Init initializes LogicalAggregation.
@@ -192,6 +214,76 @@ func (p PhysicalLimit) Init(ctx sessionctx.Context, stats *property.StatsInfo, p
 	return &p
 }
 
+// Init initializes LogicalApply.
+func (la LogicalApply) Init(ctx sessionctx.Context) *LogicalApply {
+	la.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeApply, &la)
+	return &la
+}
+
+// Init initializes LogicalMaxOneRow.
+func (p LogicalMaxOneRow) Init(ctx sessionctx.Context) *LogicalMaxOneRow {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeMaxOneRow, &p)
+	return &p
+}
+
+// Init initializes LogicalLock.
+func (p LogicalLock) Init(ctx sessionctx.Context) *LogicalLock {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeLock, &p)
+	return &p
+}
+
+// Init initializes PhysicalApply.
+func (p PhysicalApply) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalApply {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeApply, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
+// Init initializes PhysicalMaxOneRow.
+func (p PhysicalMaxOneRow) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalMaxOneRow {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeMaxOneRow, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
+// Init initializes PhysicalLock.
+func (p PhysicalLock) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalLock {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeLock, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
+// Init initializes LogicalWindow.
+func (p LogicalWindow) Init(ctx sessionctx.Context) *LogicalWindow {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeWindow, &p)
+	return &p
+}
+
+// Init initializes PhysicalWindow.
+func (p PhysicalWindow) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalWindow {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeWindow, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
+// Init initializes LogicalUnionAll.
+func (p LogicalUnionAll) Init(ctx sessionctx.Context) *LogicalUnionAll {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeUnion, &p)
+	return &p
+}
+
+// Init initializes PhysicalUnionAll.
+func (p PhysicalUnionAll) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalUnionAll {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeUnion, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
 // Init initializes LogicalTableDual.
 func (p LogicalTableDual) Init(ctx sessionctx.Context) *LogicalTableDual {
 	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeDual, &p)
@@ -205,6 +297,38 @@ func (p PhysicalTableDual) Init(ctx sessionctx.Context, stats *property.StatsInf
 	return &p
 }
 
+// Init initializes LogicalCTE.
+func (p LogicalCTE) Init(ctx sessionctx.Context) *LogicalCTE {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeCTE, &p)
+	return &p
+}
+
+// Init initializes PhysicalCTE.
+func (p PhysicalCTE) Init(ctx sessionctx.Context, stats *property.StatsInfo) *PhysicalCTE {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeCTE, &p)
+	p.stats = stats
+	return &p
+}
+
+// Init initializes LogicalCTETable.
+func (p LogicalCTETable) Init(ctx sessionctx.Context) *LogicalCTETable {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeCTETable, &p)
+	return &p
+}
+
+// Init initializes PhysicalCTETable.
+func (p PhysicalCTETable) Init(ctx sessionctx.Context, stats *property.StatsInfo) *PhysicalCTETable {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeCTETable, &p)
+	p.stats = stats
+	return &p
+}
+
+// Init initializes Update.
+func (p Update) Init(ctx sessionctx.Context) *Update {
+	p.basePlan = newBasePlan(ctx, TypeUpdate)
+	return &p
+}
+
 // Init initializes Delete.
 func (p Delete) Init(ctx sessionctx.Context) *Delete {
 	p.basePlan = newBasePlan(ctx, TypeDelete)
@@ -289,6 +413,14 @@ func (p PhysicalMergeJoin) Init(ctx sessionctx.Context, stats *property.StatsInf
 	return &p
 }
 
+// Init initializes PhysicalIndexJoin.
+func (p PhysicalIndexJoin) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalIndexJoin {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeIndexJoin, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
 // Init initializes basePhysicalAgg.
 func (base basePhysicalAgg) Init(ctx sessionctx.Context, stats *property.StatsInfo) *basePhysicalAgg {
 	base.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeHashAgg, &base)
@@ -304,6 +436,14 @@ func (base basePhysicalAgg) initForHash(ctx sessionctx.Context, stats *property.
 	return p
 }
 
+func (base basePhysicalAgg) initForStream(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalStreamAgg {
+	p := &PhysicalStreamAgg{base}
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeStreamAgg, p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return p
+}
+
 // Init initializes PhysicalUnionScan.
 func (p PhysicalUnionScan) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalUnionScan {
 	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeUnionScan, &p)
@@ -338,6 +478,15 @@ func (p PhysicalIndexReader) Init(ctx sessionctx.Context) *PhysicalIndexReader {
 	return &p
 }
 
+// Init initializes BatchPointGetPlan.
+func (p BatchPointGetPlan) Init(ctx sessionctx.Context, stats *property.StatsInfo, schema *expression.Schema, names []*types.FieldName) *BatchPointGetPlan {
+	p.basePlan = newBasePlan(ctx, TypeBatchPointGet)
+	p.schema = schema
+	p.outputNames = names
+	p.stats = stats
+	return &p
+}
+
 // flattenPushDownPlan converts a plan tree to a list, whose head is the leaf node like table scan.
 func flattenPushDownPlan(p PhysicalPlan) []PhysicalPlan {
 	plans := make([]PhysicalPlan, 0, 5)
@@ -22,6 +22,7 @@ import (
 	"strings"
 	"unicode"
 
+	"github.com/cznic/mathutil"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/expression"
@@ -31,6 +32,8 @@ import (
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/opcode"
+	"github.com/pingcap/tidb/planner/property"
+	"github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/table"
@@ -48,6 +51,10 @@ const (
 	TiDBHashJoin = "tidb_hj"
 	// HintHJ is hint enforce hash join.
 	HintHJ = "hash_join"
+	// TiDBIndexNestedLoopJoin is hint enforce index nested loop join.
+	TiDBIndexNestedLoopJoin = "tidb_inlj"
+	// HintINLJ is hint enforce index nested loop join.
+	HintINLJ = "inl_join"
 	// HintUseIndex is hint enforce using some indexes.
 	HintUseIndex = "use_index"
 	// HintIgnoreIndex is hint enforce ignoring some indexes.
@@ -91,10 +98,20 @@ func (b *PlanBuilder) buildAggregation(ctx context.Context, p LogicalPlan, aggFu
 			p = np
 			newArgList = append(newArgList, newArg)
 		}
-		newFunc, err := aggregation.NewAggFuncDesc(b.ctx, aggFunc.F, newArgList)
+		newFunc, err := aggregation.NewAggFuncDesc(b.ctx, aggFunc.F, newArgList, aggFunc.Distinct)
 		if err != nil {
 			return nil, nil, err
 		}
+		if aggFunc.Order != nil {
+			for _, byItem := range aggFunc.Order.Items {
+				newByItem, np, err := b.rewrite(ctx, byItem.Expr, p, nil, true)
+				if err != nil {
+					return nil, nil, err
+				}
+				p = np
+				newFunc.OrderByItems = append(newFunc.OrderByItems, &util.ByItems{Expr: newByItem, Desc: byItem.Desc})
+			}
+		}
 		combined := false
 		for j, oldFunc := range plan4Agg.AggFuncs {
 			if oldFunc.Equal(b.ctx, newFunc) {
@@ -115,7 +132,7 @@ func (b *PlanBuilder) buildAggregation(ctx context.Context, p LogicalPlan, aggFu
 		}
 	}
 	for i, col := range p.Schema().Columns {
-		newFunc, err := aggregation.NewAggFuncDesc(b.ctx, ast.AggFuncFirstRow, []expression.Expression{col})
+		newFunc, err := aggregation.NewAggFuncDesc(b.ctx, ast.AggFuncFirstRow, []expression.Expression{col}, false)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -141,8 +158,14 @@ func (b *PlanBuilder) buildResultSetNode(ctx context.Context, node ast.ResultSet
 		switch v := x.Source.(type) {
 		case *ast.SelectStmt:
 			p, err = b.buildSelect(ctx, v)
+		case *ast.SetOprStmt:
+			p, err = b.buildSetOpr(ctx, v)
 		case *ast.TableName:
-			p, err = b.buildDataSource(ctx, v, &x.AsName)
+			if cte := b.findCTE(v); cte != nil {
+				p, err = b.buildCTERef(ctx, cte, x.AsName)
+			} else {
+				p, err = b.buildDataSource(ctx, v, &x.AsName)
+			}
 		default:
 			err = ErrUnsupportedType.GenWithStackByArgs(v)
 		}
@@ -171,6 +194,8 @@ func (b *PlanBuilder) buildResultSetNode(ctx context.Context, node ast.ResultSet
 		return p, nil
 	case *ast.SelectStmt:
 		return b.buildSelect(ctx, x)
+	case *ast.SetOprStmt:
+		return b.buildSetOpr(ctx, x)
 	default:
 		return nil, ErrUnsupportedType.GenWithStack("Unsupported ast.ResultSetNode(%T) for buildResultSetNode()", x)
 	}
@@ -182,7 +207,7 @@ func (b *PlanBuilder) buildResultSetNode(ctx context.Context, node ast.ResultSet
 func (p *LogicalJoin) pushDownConstExpr(expr expression.Expression, leftCond []expression.Expression,
 	rightCond []expression.Expression, filterCond bool) ([]expression.Expression, []expression.Expression) {
 	switch p.JoinType {
-	case LeftOuterJoin:
+	case LeftOuterJoin, LeftOuterSemiJoin, AntiLeftOuterSemiJoin:
 		if filterCond {
 			leftCond = append(leftCond, expr)
 			// Append the expr to right join condition instead of `rightCond`, to make it able to be
@@ -198,9 +223,14 @@ func (p *LogicalJoin) pushDownConstExpr(expr expression.Expression, leftCond []e
 		} else {
 			leftCond = append(leftCond, expr)
 		}
-	case InnerJoin:
+	case SemiJoin, InnerJoin:
 		leftCond = append(leftCond, expr)
 		rightCond = append(rightCond, expr)
+	case AntiSemiJoin:
+		if filterCond {
+			leftCond = append(leftCond, expr)
+		}
+		rightCond = append(rightCond, expr)
 	}
 	return leftCond, rightCond
 }
@@ -248,7 +278,12 @@ func (p *LogicalJoin) ExtractOnCondition(
 							rightCond = append(rightCond, notNullExpr)
 						}
 					}
-					if binop.FuncName.L == ast.EQ {
+					// For queries like `select a in (select a from s where s.b = t.b) from t`,
+					// if subquery is empty caused by `s.b = t.b`, the result should always be
+					// false even if t.a is null or s.a is null. To make this join "empty aware",
+					// we should differentiate `t.a = s.a` from other column equal conditions, so
+					// we put it into OtherConditions instead of EqualConditions of join.
+					if binop.FuncName.L == ast.EQ && !arg0.InOperand && !arg1.InOperand {
 						cond := expression.NewFunctionInternal(ctx, ast.EQ, types.NewFieldType(mysql.TypeTiny), arg0, arg1)
 						eqCond = append(eqCond, cond.(*expression.ScalarFunction))
 						continue
@@ -327,6 +362,13 @@ func (p *LogicalJoin) setPreferredJoinType(hintInfo *tableHintInfo) {
 	if hintInfo.ifPreferHashJoin(lhsAlias, rhsAlias) {
 		p.preferJoinType |= preferHashJoin
 	}
+	// The table specified by the INL_JOIN hint is used as the inner side.
+	if hintInfo.ifPreferINLJ(lhsAlias) {
+		p.preferJoinType |= preferLeftAsIndexInner
+	}
+	if hintInfo.ifPreferINLJ(rhsAlias) {
+		p.preferJoinType |= preferRightAsIndexInner
+	}
 
 	// set hintInfo for further usage if this hint info can be used.
 	if p.preferJoinType != 0 {
@@ -441,7 +483,7 @@ func (b *PlanBuilder) buildSelection(ctx context.Context, p LogicalPlan, where a
 		}
 		cnfItems := expression.SplitCNFItems(expr)
 		for _, item := range cnfItems {
-			if con, ok := item.(*expression.Constant); ok {
+			if con, ok := item.(*expression.Constant); ok && !expression.ContainMutableConst([]expression.Expression{con}) {
 				ret, _, err := expression.EvalBool(b.ctx, expression.CNFExprs{con}, chunk.Row{})
 				if err != nil || ret {
 					continue
@@ -568,23 +610,56 @@ func (b *PlanBuilder) buildProjectionField(ctx context.Context, p LogicalPlan, f
 }
 
 // buildProjection returns a Projection plan and non-aux columns length.
-func (b *PlanBuilder) buildProjection(ctx context.Context, p LogicalPlan, fields []*ast.SelectField, mapper map[*ast.AggregateFuncExpr]int) (LogicalPlan, int, error) {
+// Window functions are evaluated after the HAVING clause, so the projection is
+// built twice when the select fields contain them: with considerWindow unset,
+// window fields get a placeholder; with considerWindow set, the other fields
+// are taken from the child's schema and window fields are built via windowMapper.
+func (b *PlanBuilder) buildProjection(ctx context.Context, p LogicalPlan, fields []*ast.SelectField, mapper map[*ast.AggregateFuncExpr]int,
+	windowMapper map[*ast.WindowFuncExpr]int, considerWindow bool) (LogicalPlan, int, error) {
 	b.optFlag |= flagEliminateProjection
 	b.curClause = fieldList
 	proj := LogicalProjection{Exprs: make([]expression.Expression, 0, len(fields))}.Init(b.ctx)
 	schema := expression.NewSchema(make([]*expression.Column, 0, len(fields))...)
 	oldLen := 0
 	newNames := make([]*types.FieldName, 0, len(fields))
-	for _, field := range fields {
+	for i, field := range fields {
 		if !field.Auxiliary {
 			oldLen++
 		}
 
-		newExpr, np, err := b.rewriteWithPreprocess(ctx, field.Expr, p, mapper, true, nil)
+		isWindowFuncField := ast.HasWindowFlag(field.Expr)
+		if considerWindow && !isWindowFuncField {
+			col := p.Schema().Columns[i]
+			proj.Exprs = append(proj.Exprs, col)
+			schema.Append(col)
+			newNames = append(newNames, p.OutputNames()[i])
+			continue
+		} else if !considerWindow && isWindowFuncField {
+			// The placeholder will be pruned by column pruning.
+			expr := expression.Zero
+			proj.Exprs = append(proj.Exprs, expr)
+			col, name, err := b.buildProjectionField(ctx, p, field, expr)
+			if err != nil {
+				return nil, 0, err
+			}
+			schema.Append(col)
+			newNames = append(newNames, name)
+			continue
+		}
+
+		newExpr, np, err := b.rewriteWithPreprocess(ctx, field.Expr, p, mapper, windowMapper, true, nil)
 		if err != nil {
 			return nil, 0, err
 		}
 
+		// The window functions in the order by clause are appended as
+		// auxiliary fields, remap them so that the sort can find them.
+		if considerWindow && isWindowFuncField && field.Auxiliary {
+			if windowExpr, ok := field.Expr.(*ast.WindowFuncExpr); ok {
+				windowMapper[windowExpr] = i
+			}
+		}
+
 		p = np
 		proj.Exprs = append(proj.Exprs, newExpr)
 
@@ -610,7 +685,7 @@ func (b *PlanBuilder) buildDistinct(child LogicalPlan, length int) (*LogicalAggr
 	}.Init(b.ctx)
 	plan4Agg.collectGroupByColumns()
 	for _, col := range child.Schema().Columns {
-		aggDesc, err := aggregation.NewAggFuncDesc(b.ctx, ast.AggFuncFirstRow, []expression.Expression{col})
+		aggDesc, err := aggregation.NewAggFuncDesc(b.ctx, ast.AggFuncFirstRow, []expression.Expression{col}, false)
 		if err != nil {
 			return nil, err
 		}
@@ -628,30 +703,14 @@ func (b *PlanBuilder) buildDistinct(child LogicalPlan, length int) (*LogicalAggr
 }
 
 // ByItems wraps a "by" item.
-type ByItems struct {
-	Expr expression.Expression
-	Desc bool
-}
-
-// String implements fmt.Stringer interface.
-func (by *ByItems) String() string {
-	if by.Desc {
-		return fmt.Sprintf("%s true", by.Expr)
-	}
-	return by.Expr.String()
-}
-
-// Clone makes a copy of ByItems.
-func (by *ByItems) Clone() *ByItems {
-	return &ByItems{Expr: by.Expr.Clone(), Desc: by.Desc}
-}
+type ByItems = util.ByItems
 
-func (b *PlanBuilder) buildSort(ctx context.Context, p LogicalPlan, byItems []*ast.ByItem, aggMapper map[*ast.AggregateFuncExpr]int) (*LogicalSort, error) {
+func (b *PlanBuilder) buildSort(ctx context.Context, p LogicalPlan, byItems []*ast.ByItem, aggMapper map[*ast.AggregateFuncExpr]int, windowMapper map[*ast.WindowFuncExpr]int) (*LogicalSort, error) {
 	b.curClause = orderByClause
 	sort := LogicalSort{}.Init(b.ctx)
 	exprs := make([]*ByItems, 0, len(byItems))
 	for _, item := range byItems {
-		it, np, err := b.rewriteWithPreprocess(ctx, item.Expr, p, aggMapper, true, nil)
+		it, np, err := b.rewriteWithPreprocess(ctx, item.Expr, p, aggMapper, windowMapper, true, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -671,6 +730,12 @@ func getUintFromNode(ctx sessionctx.Context, n ast.Node) (uVal uint64, isNull bo
 	switch v := n.(type) {
 	case *driver.ValueExpr:
 		val = v.GetValue()
+	case *driver.ParamMarkerExpr:
+		// The value of the parameter is unknown when preparing the statement.
+		if !v.InExecute {
+			return 0, false, true
+		}
+		val = v.GetValue()
 	default:
 		return 0, false, false
 	}
@@ -798,6 +863,8 @@ func resolveFromSelectFields(v *ast.ColumnNameExpr, fields []*ast.SelectField, i
 // It converts ColunmNameExpr to AggregateFuncExpr and collects AggregateFuncExpr.
 type havingAndOrderbyExprResolver struct {
 	inAggFunc    bool
+	inWindowFunc bool
+	inWindowSpec bool
 	inExpr       bool
 	orderBy      bool
 	err          error
@@ -814,7 +881,15 @@ func (a *havingAndOrderbyExprResolver) Enter(n ast.Node) (node ast.Node, skipChi
 	switch n.(type) {
 	case *ast.AggregateFuncExpr:
 		a.inAggFunc = true
+	case *ast.WindowFuncExpr:
+		a.inWindowFunc = true
+	case *ast.WindowSpec:
+		a.inWindowSpec = true
 	case *ast.ColumnNameExpr, *ast.ColumnName:
+	case *ast.SubqueryExpr, *ast.ExistsSubqueryExpr:
+		// Enter a new context, skip it.
+		// For example: select sum(c) + c + exists(select c from t) from t;
+		return n, true
 	default:
 		a.inExpr = true
 	}
@@ -861,9 +936,24 @@ func (a *havingAndOrderbyExprResolver) Leave(n ast.Node) (node ast.Node, ok bool
 			Expr:      v,
 			AsName:    model.NewCIStr(fmt.Sprintf("sel_agg_%d", len(a.selectFields))),
 		})
+	case *ast.WindowFuncExpr:
+		a.inWindowFunc = false
+		if a.curClause == havingClause {
+			a.err = ErrWindowInvalidWindowFuncUse.GenWithStackByArgs(strings.ToLower(v.F))
+			return node, false
+		}
+		if a.curClause == orderByClause {
+			a.selectFields = append(a.selectFields, &ast.SelectField{
+				Auxiliary: true,
+				Expr:      v,
+				AsName:    model.NewCIStr(fmt.Sprintf("sel_window_%d", len(a.selectFields))),
+			})
+		}
+	case *ast.WindowSpec:
+		a.inWindowSpec = false
 	case *ast.ColumnNameExpr:
 		resolveFieldsFirst := true
-		if a.inAggFunc || (a.orderBy && a.inExpr) || a.curClause == fieldList {
+		if a.inAggFunc || a.inWindowFunc || a.inWindowSpec || (a.orderBy && a.inExpr) || a.curClause == fieldList {
 			resolveFieldsFirst = false
 		}
 		if !a.inAggFunc && !a.orderBy {
@@ -881,6 +971,10 @@ func (a *havingAndOrderbyExprResolver) Leave(n ast.Node) (node ast.Node, ok bool
 			if a.err != nil {
 				return node, false
 			}
+			if index != -1 && a.curClause == havingClause && ast.HasWindowFlag(a.selectFields[index].Expr) {
+				a.err = ErrWindowInvalidWindowFuncAliasUse.GenWithStackByArgs(v.Name.Name.O)
+				return node, false
+			}
 			if index == -1 {
 				if a.orderBy {
 					index, a.err = a.resolveFromPlan(v, a.p)
@@ -944,6 +1038,9 @@ func (b *PlanBuilder) resolveHavingAndOrderBy(sel *ast.SelectStmt, p LogicalPlan
 	if sel.OrderBy != nil {
 		extractor.curClause = orderByClause
 		for _, item := range sel.OrderBy.Items {
+			if ast.HasWindowFlag(item.Expr) {
+				continue
+			}
 			n, ok := item.Expr.Accept(extractor)
 			if !ok {
 				return nil, nil, errors.Trace(extractor.err)
@@ -955,6 +1052,53 @@ func (b *PlanBuilder) resolveHavingAndOrderBy(sel *ast.SelectStmt, p LogicalPlan
 	return havingAggMapper, extractor.aggMapper, nil
 }
 
+// resolveWindowFunction resolves the columns referenced by the window functions
+// in the select fields and the order by clause, as well as the ones in the
+// named window specifications. The aggregate functions inside window functions
+// are appended to the select fields and the returned map records their offsets.
+func (b *PlanBuilder) resolveWindowFunction(sel *ast.SelectStmt, p LogicalPlan) (
+	map[*ast.AggregateFuncExpr]int, error) {
+	b.curClause = fieldList
+	extractor := &havingAndOrderbyExprResolver{
+		p:            p,
+		selectFields: sel.Fields.Fields,
+		aggMapper:    make(map[*ast.AggregateFuncExpr]int),
+		colMapper:    b.colMapper,
+		curClause:    fieldList,
+	}
+	for _, field := range sel.Fields.Fields {
+		if !ast.HasWindowFlag(field.Expr) {
+			continue
+		}
+		n, ok := field.Expr.Accept(extractor)
+		if !ok {
+			return nil, extractor.err
+		}
+		field.Expr = n.(ast.ExprNode)
+	}
+	for i := range sel.WindowSpecs {
+		_, ok := sel.WindowSpecs[i].Accept(extractor)
+		if !ok {
+			return nil, extractor.err
+		}
+	}
+	if sel.OrderBy != nil {
+		extractor.curClause = orderByClause
+		for _, item := range sel.OrderBy.Items {
+			if !ast.HasWindowFlag(item.Expr) {
+				continue
+			}
+			n, ok := item.Expr.Accept(extractor)
+			if !ok {
+				return nil, extractor.err
+			}
+			item.Expr = n.(ast.ExprNode)
+		}
+	}
+	sel.Fields.Fields = extractor.selectFields
+	return extractor.aggMapper, nil
+}
+
 func (b *PlanBuilder) extractAggFuncs(fields []*ast.SelectField) ([]*ast.AggregateFuncExpr, map[*ast.AggregateFuncExpr]int) {
 	extractor := &AggregateFuncExtractor{}
 	for _, f := range fields {
@@ -985,6 +1129,8 @@ type gbyResolver struct {
 func (g *gbyResolver) Enter(inNode ast.Node) (ast.Node, bool) {
 	g.exprDepth++
 	switch inNode.(type) {
+	case *ast.SubqueryExpr, *ast.CompareSubqueryExpr, *ast.ExistsSubqueryExpr:
+		return inNode, true
 	case *driver.ValueExpr, *ast.ColumnNameExpr, *ast.ParenthesesExpr, *ast.ColumnName:
 	default:
 		g.inExpr = true
@@ -1094,11 +1240,13 @@ func (b *PlanBuilder) unfoldWildStar(p LogicalPlan, selectFields []*ast.SelectFi
 
 func (b *PlanBuilder) pushTableHints(hints []*ast.TableOptimizerHint) {
 	var (
-		sortMergeTables, hashJoinTables []hintTableInfo
-		indexHintList                   []indexHintInfo
+		sortMergeTables, indexNestedLoopJoinTables, hashJoinTables []hintTableInfo
+		indexHintList                                              []indexHintInfo
 	)
 	for _, hint := range hints {
 		switch hint.HintName.L {
+		case TiDBIndexNestedLoopJoin, HintINLJ:
+			indexNestedLoopJoinTables = append(indexNestedLoopJoinTables, tableNames2HintTableInfo(b.ctx, hint.Tables)...)
 		case TiDBMergeJoin, HintSMJ:
 			sortMergeTables = append(sortMergeTables, tableNames2HintTableInfo(b.ctx, hint.Tables)...)
 		case TiDBHashJoin, HintHJ:
@@ -1141,9 +1289,10 @@ func (b *PlanBuilder) pushTableHints(hints []*ast.TableOptimizerHint) {
 		}
 	}
 	b.tableHintInfo = append(b.tableHintInfo, tableHintInfo{
-		sortMergeJoinTables: sortMergeTables,
-		hashJoinTables:      hashJoinTables,
-		indexHintList:       indexHintList,
+		indexNestedLoopJoinTables: indexNestedLoopJoinTables,
+		sortMergeJoinTables:       sortMergeTables,
+		hashJoinTables:            hashJoinTables,
+		indexHintList:             indexHintList,
 	})
 }
 
@@ -1151,6 +1300,7 @@ func (b *PlanBuilder) popTableHints() {
 	hintInfo := b.tableHintInfo[len(b.tableHintInfo)-1]
 	b.appendUnmatchedJoinHintWarning(HintSMJ, TiDBMergeJoin, hintInfo.sortMergeJoinTables)
 	b.appendUnmatchedJoinHintWarning(HintHJ, TiDBHashJoin, hintInfo.hashJoinTables)
+	b.appendUnmatchedJoinHintWarning(HintINLJ, TiDBIndexNestedLoopJoin, hintInfo.indexNestedLoopJoinTables)
 	b.tableHintInfo = b.tableHintInfo[:len(b.tableHintInfo)-1]
 }
 
@@ -1177,6 +1327,14 @@ func (b *PlanBuilder) TableHints() *tableHintInfo {
 }
 
 func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p LogicalPlan, err error) {
+	if sel.With != nil {
+		l := len(b.outerCTEs)
+		// The common table expressions are only visible in the current statement.
+		defer func() { b.outerCTEs = b.outerCTEs[:l] }()
+		if err = b.buildWith(sel, sel.With); err != nil {
+			return nil, err
+		}
+	}
 	b.pushTableHints(sel.TableHints)
 	defer func() {
 		// table hints are only visible in the current SELECT statement.
@@ -1192,6 +1350,7 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 	var (
 		aggFuncs                      []*ast.AggregateFuncExpr
 		havingMap, orderMap, totalMap map[*ast.AggregateFuncExpr]int
+		windowAggMap                  map[*ast.AggregateFuncExpr]int
 		gbyCols                       []expression.Expression
 	)
 
@@ -1217,6 +1376,13 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 		}
 	}
 
+	hasWindowFuncField := b.detectSelectWindow(sel)
+	if hasWindowFuncField {
+		windowAggMap, err = b.resolveWindowFunction(sel, p)
+		if err != nil {
+			return nil, err
+		}
+	}
 	// We must resolve having and order by clause before build projection,
 	// because when the query is "select a+1 as b from t having sum(b) < 0", we must replace sum(b) to sum(a+1),
 	// which only can be done before building projection and extracting Agg functions.
@@ -1232,6 +1398,10 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 		}
 	}
 
+	if sel.LockTp != ast.SelectLockNone {
+		p = b.buildSelectLock(p, sel.LockTp)
+	}
+
 	b.handleHelper.popMap()
 	b.handleHelper.pushMap(nil)
 
@@ -1249,7 +1419,9 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 	}
 
 	var oldLen int
-	p, oldLen, err = b.buildProjection(ctx, p, sel.Fields.Fields, totalMap)
+	// According to https://dev.mysql.com/doc/refman/8.0/en/window-functions-usage.html,
+	// we can only process window functions after having clause, so `considerWindow` is false now.
+	p, oldLen, err = b.buildProjection(ctx, p, sel.Fields.Fields, totalMap, nil, false)
 	if err != nil {
 		return nil, err
 	}
@@ -1262,6 +1434,29 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 		}
 	}
 
+	b.windowSpecs, err = buildWindowSpecs(sel.WindowSpecs)
+	if err != nil {
+		return nil, err
+	}
+
+	var windowMapper map[*ast.WindowFuncExpr]int
+	if hasWindowFuncField {
+		windowFuncs := extractWindowFuncs(sel.Fields.Fields)
+		groupedFuncs, orderedSpec, err := b.groupWindowFuncs(windowFuncs)
+		if err != nil {
+			return nil, err
+		}
+		p, windowMapper, err = b.buildWindowFunctions(ctx, p, groupedFuncs, orderedSpec, windowAggMap)
+		if err != nil {
+			return nil, err
+		}
+		// Now we build the window function fields.
+		p, oldLen, err = b.buildProjection(ctx, p, sel.Fields.Fields, windowAggMap, windowMapper, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if sel.Distinct {
 		p, err = b.buildDistinct(p, oldLen)
 		if err != nil {
@@ -1270,7 +1465,7 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 	}
 
 	if sel.OrderBy != nil {
-		p, err = b.buildSort(ctx, p, sel.OrderBy.Items, orderMap)
+		p, err = b.buildSort(ctx, p, sel.OrderBy.Items, orderMap, windowMapper)
 		if err != nil {
 			return nil, err
 		}
@@ -1299,6 +1494,370 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 	return p, nil
 }
 
+// unionJoinFieldType finds the type which can carry the given types in a set operation.
+func unionJoinFieldType(a, b *types.FieldType) *types.FieldType {
+	resultTp := types.NewFieldType(types.AggFieldType([]*types.FieldType{a, b}).Tp)
+	// Results will be unsigned when the first SQL statement result in the set operation is unsigned.
+	resultTp.Flag |= a.Flag & mysql.UnsignedFlag
+	resultTp.Decimal = mathutil.Max(a.Decimal, b.Decimal)
+	// `Flen - Decimal` is the fraction before '.'
+	resultTp.Flen = mathutil.Max(a.Flen-a.Decimal, b.Flen-b.Decimal) + resultTp.Decimal
+	if resultTp.EvalType() != types.ETInt && (a.EvalType() == types.ETInt || b.EvalType() == types.ETInt) && resultTp.Flen < mysql.MaxIntWidth {
+		resultTp.Flen = mysql.MaxIntWidth
+	}
+	resultTp.Charset = a.Charset
+	resultTp.Collate = a.Collate
+	expression.SetBinFlagOrBinStr(b, resultTp)
+	return resultTp
+}
+
+// buildProjection4Union infers the result types of the union by its children's schemas,
+// and adds a projection above every child, which casts the child's output to
+// the result types. So the schema of `UnionAll` can be the same with its children's.
+func (b *PlanBuilder) buildProjection4Union(ctx context.Context, u *LogicalUnionAll) {
+	unionCols := make([]*expression.Column, 0, u.children[0].Schema().Len())
+	names := make([]*types.FieldName, 0, u.children[0].Schema().Len())
+
+	// Infer union result types by its children's schema.
+	for i, col := range u.children[0].Schema().Columns {
+		resultTp := col.RetType
+		for j := 1; j < len(u.children); j++ {
+			childTp := u.children[j].Schema().Columns[i].RetType
+			resultTp = unionJoinFieldType(resultTp, childTp)
+		}
+		names = append(names, &types.FieldName{ColName: u.children[0].OutputNames()[i].ColName})
+		unionCols = append(unionCols, &expression.Column{
+			RetType:  resultTp,
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+		})
+	}
+	u.schema = expression.NewSchema(unionCols...)
+	u.names = names
+	// Process each child and add a projection above original child.
+	for childID, child := range u.children {
+		exprs := make([]expression.Expression, len(child.Schema().Columns))
+		for i, srcCol := range child.Schema().Columns {
+			dstType := unionCols[i].RetType
+			srcType := srcCol.RetType
+			if !srcType.Equal(dstType) {
+				exprs[i] = expression.BuildCastFunction(b.ctx, srcCol, dstType)
+			} else {
+				exprs[i] = srcCol
+			}
+		}
+		b.optFlag |= flagEliminateProjection
+		proj := LogicalProjection{Exprs: exprs}.Init(b.ctx)
+		proj.SetSchema(u.schema.Clone())
+		// reset the schema type to make the "not null" flag right.
+		for i, expr := range exprs {
+			proj.schema.Columns[i].RetType = expr.GetType()
+		}
+		proj.names = child.OutputNames()
+		proj.SetChildren(child)
+		u.children[childID] = proj
+	}
+}
+
+// buildUnionAll builds a LogicalUnionAll on the given plans. It returns the
+// plan itself if there is only one plan.
+func (b *PlanBuilder) buildUnionAll(ctx context.Context, subPlan []LogicalPlan) LogicalPlan {
+	if len(subPlan) == 0 {
+		return nil
+	}
+	if len(subPlan) == 1 {
+		return subPlan[0]
+	}
+	u := LogicalUnionAll{}.Init(b.ctx)
+	u.children = subPlan
+	b.buildProjection4Union(ctx, u)
+	return u
+}
+
+// buildIntersectOrExcept builds INTERSECT and EXCEPT through aggregation. Rows
+// from both sides are tagged, grouped by all the output columns so that NULLs
+// are treated as equal, and then every group is filtered by the tags it has.
+// The left side is tagged with 1 and the right side with 0, so INTERSECT keeps
+// the groups whose min tag is 0 and max tag is 1, while EXCEPT keeps the groups
+// whose min tag is 1.
+func (b *PlanBuilder) buildIntersectOrExcept(ctx context.Context, left, right LogicalPlan, tp ast.SetOprType) (LogicalPlan, error) {
+	length := left.Schema().Len()
+	tagged := make([]LogicalPlan, 0, 2)
+	for i, child := range []LogicalPlan{left, right} {
+		tag := expression.One.Clone()
+		if i == 1 {
+			tag = expression.Zero.Clone()
+		}
+		tagCol := &expression.Column{
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  tag.GetType(),
+		}
+		proj := LogicalProjection{Exprs: append(expression.Column2Exprs(child.Schema().Columns), tag)}.Init(b.ctx)
+		proj.SetChildren(child)
+		proj.SetSchema(expression.NewSchema(append(child.Schema().Clone().Columns, tagCol)...))
+		proj.names = append(append([]*types.FieldName{}, child.OutputNames()...), &types.FieldName{ColName: model.NewCIStr("set_opr_tag")})
+		tagged = append(tagged, proj)
+	}
+	u := b.buildUnionAll(ctx, tagged)
+	tagCol := u.Schema().Columns[length]
+
+	b.optFlag = b.optFlag | flagBuildKeyInfo
+	agg := LogicalAggregation{
+		AggFuncs:     make([]*aggregation.AggFuncDesc, 0, length+2),
+		GroupByItems: expression.Column2Exprs(u.Schema().Clone().Columns[:length]),
+	}.Init(b.ctx)
+	agg.collectGroupByColumns()
+	schema := expression.NewSchema()
+	for _, col := range u.Schema().Columns[:length] {
+		aggDesc, err := aggregation.NewAggFuncDesc(b.ctx, ast.AggFuncFirstRow, []expression.Expression{col}, false)
+		if err != nil {
+			return nil, err
+		}
+		agg.AggFuncs = append(agg.AggFuncs, aggDesc)
+		newCol := col.Clone().(*expression.Column)
+		newCol.RetType = aggDesc.RetTp
+		schema.Append(newCol)
+	}
+	tagFuncs := []string{ast.AggFuncMin}
+	if tp == ast.Intersect {
+		tagFuncs = append(tagFuncs, ast.AggFuncMax)
+	}
+	tagAggCols := make([]*expression.Column, 0, len(tagFuncs))
+	for _, name := range tagFuncs {
+		aggDesc, err := aggregation.NewAggFuncDesc(b.ctx, name, []expression.Expression{tagCol}, false)
+		if err != nil {
+			return nil, err
+		}
+		agg.AggFuncs = append(agg.AggFuncs, aggDesc)
+		col := &expression.Column{
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  aggDesc.RetTp,
+		}
+		schema.Append(col)
+		tagAggCols = append(tagAggCols, col)
+	}
+	agg.SetChildren(u)
+	agg.SetSchema(schema)
+	agg.names = make([]*types.FieldName, schema.Len())
+	copy(agg.names, u.OutputNames())
+	for i := length; i < schema.Len(); i++ {
+		agg.names[i] = &types.FieldName{ColName: model.NewCIStr(fmt.Sprintf("set_opr_tag_%d", i-length))}
+	}
+
+	var conds []expression.Expression
+	if tp == ast.Intersect {
+		// min(tag) = 0 and max(tag) = 1
+		conds = []expression.Expression{
+			expression.NewFunctionInternal(b.ctx, ast.EQ, types.NewFieldType(mysql.TypeTiny), tagAggCols[0], expression.Zero),
+			expression.NewFunctionInternal(b.ctx, ast.EQ, types.NewFieldType(mysql.TypeTiny), tagAggCols[1], expression.One),
+		}
+	} else {
+		// min(tag) = 1
+		conds = []expression.Expression{
+			expression.NewFunctionInternal(b.ctx, ast.EQ, types.NewFieldType(mysql.TypeTiny), tagAggCols[0], expression.One),
+		}
+	}
+	sel := LogicalSelection{Conditions: conds}.Init(b.ctx)
+	sel.SetChildren(agg)
+
+	proj := LogicalProjection{Exprs: expression.Column2Exprs(schema.Columns[:length])}.Init(b.ctx)
+	proj.SetChildren(sel)
+	projSchema := expression.NewSchema(schema.Clone().Columns[:length]...)
+	for _, col := range projSchema.Columns {
+		col.UniqueID = b.ctx.GetSessionVars().AllocPlanColumnID()
+	}
+	proj.SetSchema(projSchema)
+	proj.names = agg.names[:length]
+	return proj, nil
+}
+
+// buildSetOpr builds the plan for UNION, INTERSECT and EXCEPT.
+// See https://dev.mysql.com/doc/refman/5.7/en/union.html
+func (b *PlanBuilder) buildSetOpr(ctx context.Context, setOpr *ast.SetOprStmt) (LogicalPlan, error) {
+	if setOpr.With != nil {
+		l := len(b.outerCTEs)
+		defer func() { b.outerCTEs = b.outerCTEs[:l] }()
+		if err := b.buildWith(setOpr, setOpr.With); err != nil {
+			return nil, err
+		}
+	}
+	selects := setOpr.SelectList.Selects
+	columnNums := -1
+	// INTERSECT has higher precedence than UNION and EXCEPT, so every run of
+	// INTERSECT operations is built first.
+	plans := make([]LogicalPlan, 0, len(selects))
+	oprs := make([]ast.SetOprType, 0, len(selects))
+	for i, sel := range selects {
+		p, err := b.buildSelect(ctx, sel)
+		if err != nil {
+			return nil, err
+		}
+		if columnNums == -1 {
+			columnNums = p.Schema().Len()
+		}
+		if p.Schema().Len() != columnNums {
+			return nil, ErrWrongNumberOfColumnsInSelect.GenWithStackByArgs()
+		}
+		if i == 0 {
+			plans = append(plans, p)
+			oprs = append(oprs, ast.UnionAll)
+			continue
+		}
+		if *sel.AfterSetOperator == ast.Intersect {
+			last := len(plans) - 1
+			plans[last], err = b.buildIntersectOrExcept(ctx, plans[last], p, ast.Intersect)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		plans = append(plans, p)
+		oprs = append(oprs, *sel.AfterSetOperator)
+	}
+
+	// Then UNION, UNION ALL and EXCEPT are built from left to right. The plans
+	// combined by UNION ALL are collected into one LogicalUnionAll. Note that
+	// a DISTINCT union overrides any ALL union to its left.
+	unionPlans := []LogicalPlan{plans[0]}
+	for i := 1; i < len(plans); i++ {
+		var err error
+		switch oprs[i] {
+		case ast.UnionAll:
+			unionPlans = append(unionPlans, plans[i])
+		case ast.Union:
+			unionPlans = append(unionPlans, plans[i])
+			u := b.buildUnionAll(ctx, unionPlans)
+			u, err = b.buildDistinct(u, u.Schema().Len())
+			if err != nil {
+				return nil, err
+			}
+			unionPlans = []LogicalPlan{u}
+		case ast.Except:
+			var p LogicalPlan
+			p, err = b.buildIntersectOrExcept(ctx, b.buildUnionAll(ctx, unionPlans), plans[i], ast.Except)
+			if err != nil {
+				return nil, err
+			}
+			unionPlans = []LogicalPlan{p}
+		}
+	}
+	setOprPlan := b.buildUnionAll(ctx, unionPlans)
+
+	oldLen := setOprPlan.Schema().Len()
+
+	for i := 0; i < len(selects); i++ {
+		b.handleHelper.popMap()
+	}
+	b.handleHelper.pushMap(nil)
+
+	var err error
+	if setOpr.OrderBy != nil {
+		setOprPlan, err = b.buildSort(ctx, setOprPlan, setOpr.OrderBy.Items, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if setOpr.Limit != nil {
+		setOprPlan, err = b.buildLimit(setOprPlan, setOpr.Limit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// If there are extra expressions generated from `ORDER BY` clause, generate a `Projection` to remove them.
+	if oldLen != setOprPlan.Schema().Len() {
+		proj := LogicalProjection{Exprs: expression.Column2Exprs(setOprPlan.Schema().Columns[:oldLen])}.Init(b.ctx)
+		proj.SetChildren(setOprPlan)
+		schema := expression.NewSchema(setOprPlan.Schema().Clone().Columns[:oldLen]...)
+		for _, col := range schema.Columns {
+			col.UniqueID = b.ctx.GetSessionVars().AllocPlanColumnID()
+		}
+		proj.names = setOprPlan.OutputNames()[:oldLen]
+		proj.SetSchema(schema)
+		return proj, nil
+	}
+	return setOprPlan, nil
+}
+
+func (b *PlanBuilder) buildApplyWithJoinType(outerPlan, innerPlan LogicalPlan, tp JoinType) LogicalPlan {
+	b.optFlag = b.optFlag | flagPredicatePushDown | flagBuildKeyInfo | flagDecorrelate
+	ap := LogicalApply{LogicalJoin: LogicalJoin{JoinType: tp}}.Init(b.ctx)
+	ap.SetChildren(outerPlan, innerPlan)
+	ap.names = make([]*types.FieldName, outerPlan.Schema().Len()+innerPlan.Schema().Len())
+	copy(ap.names, outerPlan.OutputNames())
+	ap.SetSchema(expression.MergeSchema(outerPlan.Schema(), innerPlan.Schema()))
+	// Note that, tp can only be LeftOuterJoin or InnerJoin, so we don't consider other outer joins.
+	if tp == LeftOuterJoin {
+		b.optFlag = b.optFlag | flagEliminateOuterJoin
+		resetNotNullFlag(ap.schema, outerPlan.Schema().Len(), ap.schema.Len())
+	}
+	for i := outerPlan.Schema().Len(); i < ap.Schema().Len(); i++ {
+		ap.names[i] = types.EmptyName
+	}
+	return ap
+}
+
+// buildSemiApply builds apply plan with outerPlan and innerPlan, which apply semi-join for every row from outerPlan and the whole innerPlan.
+func (b *PlanBuilder) buildSemiApply(outerPlan, innerPlan LogicalPlan, condition []expression.Expression, asScalar, not bool) LogicalPlan {
+	b.optFlag = b.optFlag | flagPredicatePushDown | flagBuildKeyInfo | flagDecorrelate
+
+	join := b.buildSemiJoin(outerPlan, innerPlan, condition, asScalar, not)
+	ap := &LogicalApply{LogicalJoin: *join}
+	ap.tp = TypeApply
+	ap.self = ap
+	return ap
+}
+
+func (b *PlanBuilder) buildSelectLock(src LogicalPlan, lock ast.SelectLockType) *LogicalLock {
+	selectLock := LogicalLock{
+		Lock:         lock,
+		tblID2Handle: b.handleHelper.tailMap(),
+	}.Init(b.ctx)
+	selectLock.SetChildren(src)
+	return selectLock
+}
+
+func (b *PlanBuilder) buildMaxOneRow(p LogicalPlan) LogicalPlan {
+	maxOneRow := LogicalMaxOneRow{}.Init(b.ctx)
+	maxOneRow.SetChildren(p)
+	return maxOneRow
+}
+
+func (b *PlanBuilder) buildSemiJoin(outerPlan, innerPlan LogicalPlan, onCondition []expression.Expression, asScalar bool, not bool) *LogicalJoin {
+	joinPlan := LogicalJoin{}.Init(b.ctx)
+	for i, expr := range onCondition {
+		onCondition[i] = expr.Decorrelate(outerPlan.Schema())
+	}
+	joinPlan.SetChildren(outerPlan, innerPlan)
+	joinPlan.attachOnConds(onCondition)
+	joinPlan.names = make([]*types.FieldName, outerPlan.Schema().Len(), outerPlan.Schema().Len()+innerPlan.Schema().Len()+1)
+	copy(joinPlan.names, outerPlan.OutputNames())
+	if asScalar {
+		newSchema := outerPlan.Schema().Clone()
+		newSchema.Append(&expression.Column{
+			RetType:  types.NewFieldType(mysql.TypeTiny),
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+		})
+		joinPlan.names = append(joinPlan.names, types.EmptyName)
+		joinPlan.SetSchema(newSchema)
+		if not {
+			joinPlan.JoinType = AntiLeftOuterSemiJoin
+		} else {
+			joinPlan.JoinType = LeftOuterSemiJoin
+		}
+	} else {
+		joinPlan.SetSchema(outerPlan.Schema().Clone())
+		if not {
+			joinPlan.JoinType = AntiSemiJoin
+		} else {
+			joinPlan.JoinType = SemiJoin
+		}
+	}
+	// Apply forces to choose hash join currently, so don't worry the hints will take effect if the semi join is in one apply.
+	joinPlan.setPreferredJoinType(b.TableHints())
+	return joinPlan
+}
+
 func (b *PlanBuilder) buildTableDual() *LogicalTableDual {
 	b.handleHelper.pushMap(nil)
 	return LogicalTableDual{RowCount: 1}.Init(b.ctx)
@@ -1361,6 +1920,10 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 
 	tableInfo := tbl.Meta()
 
+	if tableInfo.IsView() {
+		return b.buildDataSourceFromView(ctx, dbName, tableInfo)
+	}
+
 	if tbl.Type().IsVirtualTable() {
 		return b.buildMemTable(ctx, dbName, tableInfo)
 	}
@@ -1461,6 +2024,65 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 	return result, nil
 }
 
+// buildDataSourceFromView expands a view by building its select statement, the
+// output columns of which are renamed to the columns of the view.
+func (b *PlanBuilder) buildDataSourceFromView(ctx context.Context, dbName model.CIStr, tableInfo *model.TableInfo) (LogicalPlan, error) {
+	sessVars := b.ctx.GetSessionVars()
+	charset, collation := sessVars.GetCharsetInfo()
+	selectNode, err := parser.New().ParseOneStmt(tableInfo.View.SelectStmt, charset, collation)
+	if err != nil {
+		return nil, err
+	}
+	// Like the definer, the view resolves the tables without a database name in
+	// the database of the view instead of the current one. There is no privilege
+	// system, so the SQL SECURITY of the view doesn't change how it's expanded.
+	currentDB := sessVars.CurrentDB
+	sessVars.CurrentDB = dbName.O
+	err = Preprocess(b.ctx, selectNode, b.is)
+	sessVars.CurrentDB = currentDB
+	if err != nil {
+		return nil, ErrViewInvalid.GenWithStackByArgs(dbName.O, tableInfo.Name.O)
+	}
+
+	// The select statement of the view can't reference the common table
+	// expressions and the columns of the statement using the view.
+	outerCTEs, outerSchemas, outerNames := b.outerCTEs, b.outerSchemas, b.outerNames
+	b.outerCTEs, b.outerSchemas, b.outerNames = nil, nil, nil
+	p, err := b.buildResultSetNode(ctx, selectNode.(ast.ResultSetNode))
+	b.outerCTEs, b.outerSchemas, b.outerNames = outerCTEs, outerSchemas, outerNames
+	if err != nil {
+		return nil, ErrViewInvalid.GenWithStackByArgs(dbName.O, tableInfo.Name.O)
+	}
+	// The number of output columns changes if the underlying tables are altered
+	// and the view selects "*" from them.
+	if p.Schema().Len() != len(tableInfo.Columns) {
+		return nil, ErrViewInvalid.GenWithStackByArgs(dbName.O, tableInfo.Name.O)
+	}
+
+	exprs := make([]expression.Expression, 0, len(tableInfo.Columns))
+	schema := expression.NewSchema(make([]*expression.Column, 0, len(tableInfo.Columns))...)
+	names := make(types.NameSlice, 0, len(tableInfo.Columns))
+	for i, col := range tableInfo.Columns {
+		exprs = append(exprs, p.Schema().Columns[i])
+		schema.Append(&expression.Column{
+			UniqueID: sessVars.AllocPlanColumnID(),
+			RetType:  p.Schema().Columns[i].RetType,
+		})
+		names = append(names, &types.FieldName{
+			DBName:      dbName,
+			TblName:     tableInfo.Name,
+			ColName:     col.Name,
+			OrigTblName: tableInfo.Name,
+			OrigColName: col.Name,
+		})
+	}
+	proj := LogicalProjection{Exprs: exprs}.Init(b.ctx)
+	proj.SetChildren(p)
+	proj.SetSchema(schema)
+	proj.SetOutputNames(names)
+	return proj, nil
+}
+
 func (b *PlanBuilder) buildMemTable(ctx context.Context, dbName model.CIStr, tableInfo *model.TableInfo) (LogicalPlan, error) {
 	// We can use the `tableInfo.Columns` directly because the memory table has
 	// a stable schema and there is no online DDL on the memory table.
@@ -1594,44 +2216,169 @@ func extractDefaultExpr(node ast.ExprNode) *ast.DefaultExpr {
 	return nil
 }
 
-func (b *PlanBuilder) buildDelete(ctx context.Context, delete *ast.DeleteStmt) (Plan, error) {
-	p, err := b.buildResultSetNode(ctx, delete.TableRefs.TableRefs)
+func (b *PlanBuilder) buildUpdate(ctx context.Context, update *ast.UpdateStmt) (Plan, error) {
+	b.pushTableHints(update.TableHints)
+	defer func() {
+		// table hints are only visible in the current UPDATE statement.
+		b.popTableHints()
+	}()
+
+	p, err := b.buildResultSetNode(ctx, update.TableRefs.TableRefs)
 	if err != nil {
 		return nil, err
 	}
-	oldSchema := p.Schema()
-	oldLen := oldSchema.Len()
-
-	if delete.Where != nil {
-		p, err = b.buildSelection(ctx, p, delete.Where, nil)
+	oldSchemaLen := p.Schema().Len()
+	if update.Where != nil {
+		p, err = b.buildSelection(ctx, p, update.Where, nil)
 		if err != nil {
 			return nil, err
 		}
 	}
-
-	if delete.Order != nil {
-		p, err = b.buildSort(ctx, p, delete.Order.Items, nil)
+	if update.Order != nil {
+		p, err = b.buildSort(ctx, p, update.Order.Items, nil, nil)
 		if err != nil {
 			return nil, err
 		}
 	}
-
-	if delete.Limit != nil {
-		p, err = b.buildLimit(p, delete.Limit)
+	if update.Limit != nil && !update.MultipleTable {
+		p, err = b.buildLimit(p, update.Limit)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	proj := LogicalProjection{Exprs: expression.Column2Exprs(p.Schema().Columns[:oldLen])}.Init(b.ctx)
+	// Add projection to freeze the order of output columns.
+	proj := LogicalProjection{Exprs: expression.Column2Exprs(p.Schema().Columns[:oldSchemaLen])}.Init(b.ctx)
+	proj.SetSchema(expression.NewSchema(make([]*expression.Column, oldSchemaLen)...))
+	proj.names = make(types.NameSlice, oldSchemaLen)
+	copy(proj.names, p.OutputNames()[:oldSchemaLen])
+	copy(proj.schema.Columns, p.Schema().Columns[:oldSchemaLen])
 	proj.SetChildren(p)
-	proj.SetSchema(oldSchema.Clone())
-	proj.names = p.OutputNames()[:oldLen]
 	p = proj
 
-	del := Delete{}.Init(b.ctx)
-
-	del.names = p.OutputNames()
+	orderedList, np, err := b.buildUpdateLists(ctx, update.List, p)
+	if err != nil {
+		return nil, err
+	}
+	p = np
+
+	updt := Update{OrderedList: orderedList}.Init(b.ctx)
+	updt.names = p.OutputNames()
+	// We cannot apply projection elimination when building the subplan, because
+	// columns in orderedList cannot be resolved.
+	updt.SelectPlan, err = DoOptimize(ctx, b.optFlag&^flagEliminateProjection, p)
+	if err != nil {
+		return nil, err
+	}
+	err = updt.ResolveIndices()
+	if err != nil {
+		return nil, err
+	}
+	tblID2Handle, err := resolveIndicesForTblID2Handle(b.handleHelper.tailMap(), updt.SelectPlan.Schema())
+	if err != nil {
+		return nil, err
+	}
+	tblID2table := make(map[int64]table.Table, len(tblID2Handle))
+	for id := range tblID2Handle {
+		tblID2table[id], _ = b.is.TableByID(id)
+	}
+	updt.TblColPosInfos, err = buildColumns2Handle(updt.OutputNames(), tblID2Handle, tblID2table, true)
+	if err == nil {
+		err = checkUpdateList(updt)
+	}
+	return updt, err
+}
+
+// checkUpdateList checks that every assigned column belongs to a base table,
+// columns of a derived table can not be updated.
+func checkUpdateList(updt *Update) error {
+	for _, assign := range updt.OrderedList {
+		updatable := false
+		for _, content := range updt.TblColPosInfos {
+			if assign.Col.Index >= content.Start && assign.Col.Index < content.End {
+				updatable = true
+				break
+			}
+		}
+		if !updatable {
+			return ErrNonUpdatableTable.GenWithStackByArgs(updt.names[assign.Col.Index].TblName.O, "UPDATE")
+		}
+	}
+	return nil
+}
+
+// buildUpdateLists rewrites the assignments of an UPDATE statement against the
+// output of p. The returned plan may differ from p if the expressions need it.
+func (b *PlanBuilder) buildUpdateLists(ctx context.Context, list []*ast.Assignment, p LogicalPlan) ([]*expression.Assignment, LogicalPlan, error) {
+	b.curClause = fieldList
+	newList := make([]*expression.Assignment, 0, len(list))
+	for _, assign := range list {
+		idx, err := expression.FindFieldName(p.OutputNames(), assign.Column)
+		if err != nil {
+			return nil, nil, err
+		}
+		if idx < 0 {
+			return nil, nil, ErrUnknownColumn.GenWithStackByArgs(assign.Column.Name, "field list")
+		}
+		col := p.Schema().Columns[idx]
+		name := p.OutputNames()[idx]
+		// If assign `DEFAULT` to column, fill the `defaultExpr.Name` before rewrite expression.
+		if expr := extractDefaultExpr(assign.Expr); expr != nil {
+			expr.Name = assign.Column
+		}
+		newExpr, np, err := b.rewrite(ctx, assign.Expr, p, nil, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		p = np
+		newList = append(newList, &expression.Assignment{Col: col, ColName: name.ColName, Expr: newExpr})
+	}
+	return newList, p, nil
+}
+
+func (b *PlanBuilder) buildDelete(ctx context.Context, delete *ast.DeleteStmt) (Plan, error) {
+	for _, tn := range extractTableList(delete.TableRefs.TableRefs, nil) {
+		if tn.TableInfo != nil && tn.TableInfo.IsView() {
+			return nil, ErrNonUpdatableTable.GenWithStackByArgs(tn.Name.O, "DELETE")
+		}
+	}
+	p, err := b.buildResultSetNode(ctx, delete.TableRefs.TableRefs)
+	if err != nil {
+		return nil, err
+	}
+	oldSchema := p.Schema()
+	oldLen := oldSchema.Len()
+
+	if delete.Where != nil {
+		p, err = b.buildSelection(ctx, p, delete.Where, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if delete.Order != nil {
+		p, err = b.buildSort(ctx, p, delete.Order.Items, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if delete.Limit != nil {
+		p, err = b.buildLimit(p, delete.Limit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	proj := LogicalProjection{Exprs: expression.Column2Exprs(p.Schema().Columns[:oldLen])}.Init(b.ctx)
+	proj.SetChildren(p)
+	proj.SetSchema(oldSchema.Clone())
+	proj.names = p.OutputNames()[:oldLen]
+	p = proj
+
+	del := Delete{}.Init(b.ctx)
+
+	del.names = p.OutputNames()
 	del.SelectPlan, err = DoOptimize(ctx, b.optFlag, p)
 	if err != nil {
 		return nil, err
@@ -1649,6 +2396,22 @@ func (b *PlanBuilder) buildDelete(ctx context.Context, delete *ast.DeleteStmt) (
 	return del, err
 }
 
+// extractTableList extracts the table names from node.
+func extractTableList(node ast.ResultSetNode, input []*ast.TableName) []*ast.TableName {
+	switch x := node.(type) {
+	case *ast.Join:
+		input = extractTableList(x.Left, input)
+		if x.Right != nil {
+			input = extractTableList(x.Right, input)
+		}
+	case *ast.TableSource:
+		if s, ok := x.Source.(*ast.TableName); ok {
+			input = append(input, s)
+		}
+	}
+	return input
+}
+
 func resolveIndicesForTblID2Handle(tblID2Handle map[int64][]*expression.Column, schema *expression.Schema) (map[int64][]*expression.Column, error) {
 	newMap := make(map[int64][]*expression.Column, len(tblID2Handle))
 	for i, cols := range tblID2Handle {
@@ -1676,5 +2439,875 @@ func getInnerFromParenthesesAndUnaryPlus(expr ast.ExprNode) ast.ExprNode {
 // containDifferentJoinTypes checks whether `preferJoinType` contains different
 // join types.
 func containDifferentJoinTypes(preferJoinType uint) bool {
+	// Both sides can be specified as the inner side of an index join, they
+	// are counted as one join type.
+	inlMask := preferLeftAsIndexInner | preferRightAsIndexInner
+	if preferJoinType&inlMask > 0 {
+		preferJoinType = preferJoinType&^inlMask | preferRightAsIndexInner
+	}
 	return bits.OnesCount(preferJoinType) > 1
 }
+
+// buildProjectionForWindow builds the projection below the window operator, it
+// appends the partition by items, order by items and arguments of the window
+// functions which are not columns to the output of the child plan.
+func (b *PlanBuilder) buildProjectionForWindow(ctx context.Context, p LogicalPlan, spec *ast.WindowSpec, args []ast.ExprNode, aggMap map[*ast.AggregateFuncExpr]int) (LogicalPlan, []property.Item, []property.Item, []expression.Expression, error) {
+	b.optFlag |= flagEliminateProjection
+
+	var partitionItems, orderItems []*ast.ByItem
+	if spec.PartitionBy != nil {
+		partitionItems = spec.PartitionBy.Items
+	}
+	if spec.OrderBy != nil {
+		orderItems = spec.OrderBy.Items
+	}
+
+	projLen := len(p.Schema().Columns) + len(partitionItems) + len(orderItems) + len(args)
+	proj := LogicalProjection{Exprs: make([]expression.Expression, 0, projLen)}.Init(b.ctx)
+	proj.SetSchema(expression.NewSchema(make([]*expression.Column, 0, projLen)...))
+	proj.names = make([]*types.FieldName, p.Schema().Len(), projLen)
+	for _, col := range p.Schema().Columns {
+		proj.Exprs = append(proj.Exprs, col)
+		proj.schema.Append(col)
+	}
+	copy(proj.names, p.OutputNames())
+
+	propertyItems := make([]property.Item, 0, len(partitionItems)+len(orderItems))
+	var err error
+	b.curClause = partitionByClause
+	p, propertyItems, err = b.buildByItemsForWindow(ctx, p, proj, partitionItems, propertyItems, aggMap)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	lenPartition := len(propertyItems)
+	b.curClause = windowClause
+	p, propertyItems, err = b.buildByItemsForWindow(ctx, p, proj, orderItems, propertyItems, aggMap)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	b.curClause = fieldList
+	newArgList := make([]expression.Expression, 0, len(args))
+	for _, arg := range args {
+		newArg, np, err := b.rewrite(ctx, arg, p, aggMap, true)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		p = np
+		switch newArg.(type) {
+		case *expression.Column, *expression.Constant:
+			newArgList = append(newArgList, newArg)
+			continue
+		}
+		proj.Exprs = append(proj.Exprs, newArg)
+		proj.names = append(proj.names, types.EmptyName)
+		col := &expression.Column{
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  newArg.GetType(),
+		}
+		proj.schema.Append(col)
+		newArgList = append(newArgList, col)
+	}
+
+	proj.SetChildren(p)
+	return proj, propertyItems[:lenPartition], propertyItems[lenPartition:], newArgList, nil
+}
+
+func (b *PlanBuilder) buildByItemsForWindow(
+	ctx context.Context,
+	p LogicalPlan,
+	proj *LogicalProjection,
+	items []*ast.ByItem,
+	retItems []property.Item,
+	aggMap map[*ast.AggregateFuncExpr]int,
+) (LogicalPlan, []property.Item, error) {
+	for _, item := range items {
+		it, np, err := b.rewrite(ctx, item.Expr, p, aggMap, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		p = np
+		if it.GetType().Tp == mysql.TypeNull {
+			continue
+		}
+		if col, ok := it.(*expression.Column); ok {
+			retItems = append(retItems, property.Item{Col: col, Desc: item.Desc})
+			continue
+		}
+		proj.Exprs = append(proj.Exprs, it)
+		proj.names = append(proj.names, types.EmptyName)
+		col := &expression.Column{
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  it.GetType(),
+		}
+		proj.schema.Append(col)
+		retItems = append(retItems, property.Item{Col: col, Desc: item.Desc})
+	}
+	return p, retItems, nil
+}
+
+// buildWindowFunctionFrameBound builds the bounds of window function frames.
+// For type `Rows`, the bound expr must be an unsigned integer.
+// For type `Range`, the bound expr must be a number, and the only order by
+// item must be of a numeric type.
+func (b *PlanBuilder) buildWindowFunctionFrameBound(ctx context.Context, spec *ast.WindowSpec, orderByItems []property.Item, boundClause *ast.FrameBound) (*FrameBound, error) {
+	frameType := spec.Frame.Type
+	bound := &FrameBound{Type: boundClause.Type, UnBounded: boundClause.UnBounded}
+	if bound.UnBounded {
+		return bound, nil
+	}
+
+	if frameType == ast.Rows {
+		if bound.Type == ast.CurrentRow {
+			return bound, nil
+		}
+		// Rows type does not support interval range.
+		numRows, _, _ := getUintFromNode(b.ctx, boundClause.Expr)
+		bound.Num = numRows
+		return bound, nil
+	}
+
+	bound.CalcFuncs = make([]expression.Expression, len(orderByItems))
+	bound.CmpFuncs = make([]expression.CompareFunc, len(orderByItems))
+	if bound.Type == ast.CurrentRow {
+		for i, item := range orderByItems {
+			col := item.Col
+			bound.CalcFuncs[i] = col
+			bound.CmpFuncs[i] = expression.GetCmpFunction(col, col)
+		}
+		return bound, nil
+	}
+
+	col := orderByItems[0].Col
+	val, err := evalAstExpr(b.ctx, boundClause.Expr)
+	if err != nil {
+		return nil, ErrWindowRangeBoundNotConstant.GenWithStackByArgs(getWindowName(spec.Name.O))
+	}
+	expr := &expression.Constant{Value: val, RetType: boundClause.Expr.GetType()}
+
+	// Do not raise warnings for truncate.
+	sc := b.ctx.GetSessionVars().StmtCtx
+	oriIgnoreTruncate := sc.IgnoreTruncate
+	sc.IgnoreTruncate = true
+	uVal, isNull, err := expr.EvalInt(b.ctx, chunk.Row{})
+	sc.IgnoreTruncate = oriIgnoreTruncate
+	if uVal < 0 || isNull || err != nil {
+		return nil, ErrWindowFrameIllegal.GenWithStackByArgs(getWindowName(spec.Name.O))
+	}
+
+	// When the order is asc:
+	//   `+` for following, and `-` for the preceding
+	// When the order is desc, `+` becomes `-` and vice-versa.
+	desc := orderByItems[0].Desc
+	funcName := ast.Plus
+	if (!desc && bound.Type == ast.Preceding) || (desc && bound.Type == ast.Following) {
+		funcName = ast.Minus
+	}
+	bound.CalcFuncs[0], err = expression.NewFunctionBase(b.ctx, funcName, col.RetType, col, expr)
+	if err != nil {
+		return nil, err
+	}
+	bound.CmpFuncs[0] = expression.GetCmpFunction(orderByItems[0].Col, bound.CalcFuncs[0])
+	return bound, nil
+}
+
+// buildWindowFunctionFrame builds the window function frames.
+// See https://dev.mysql.com/doc/refman/8.0/en/window-functions-frames.html
+func (b *PlanBuilder) buildWindowFunctionFrame(ctx context.Context, spec *ast.WindowSpec, orderByItems []property.Item) (*WindowFrame, error) {
+	frameClause := spec.Frame
+	if frameClause == nil {
+		return nil, nil
+	}
+	frame := &WindowFrame{Type: frameClause.Type}
+	var err error
+	frame.Start, err = b.buildWindowFunctionFrameBound(ctx, spec, orderByItems, &frameClause.Extent.Start)
+	if err != nil {
+		return nil, err
+	}
+	frame.End, err = b.buildWindowFunctionFrameBound(ctx, spec, orderByItems, &frameClause.Extent.End)
+	return frame, err
+}
+
+// buildWindowFunctions builds a LogicalWindow on top of a projection for each
+// group of window functions sharing the same window specification. It returns
+// the offsets of the window functions in the output schema.
+func (b *PlanBuilder) buildWindowFunctions(ctx context.Context, p LogicalPlan, groupedFuncs map[*ast.WindowSpec][]*ast.WindowFuncExpr, orderedSpec []*ast.WindowSpec, aggMap map[*ast.AggregateFuncExpr]int) (LogicalPlan, map[*ast.WindowFuncExpr]int, error) {
+	args := make([]ast.ExprNode, 0, 4)
+	windowMap := make(map[*ast.WindowFuncExpr]int)
+	for _, spec := range orderedSpec {
+		funcs := groupedFuncs[spec]
+		args = args[:0]
+		for _, windowFunc := range funcs {
+			args = append(args, windowFunc.Args...)
+		}
+		np, partitionBy, orderBy, args, err := b.buildProjectionForWindow(ctx, p, spec, args, aggMap)
+		if err != nil {
+			return nil, nil, err
+		}
+		err = b.checkOriginWindowSpecs(funcs, orderBy)
+		if err != nil {
+			return nil, nil, err
+		}
+		frame, err := b.buildWindowFunctionFrame(ctx, spec, orderBy)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		window := LogicalWindow{
+			PartitionBy: partitionBy,
+			OrderBy:     orderBy,
+			Frame:       frame,
+		}.Init(b.ctx)
+		window.names = make([]*types.FieldName, np.Schema().Len())
+		copy(window.names, np.OutputNames())
+		schema := np.Schema().Clone()
+		descs := make([]*aggregation.WindowFuncDesc, 0, len(funcs))
+		preArgs := 0
+		for _, windowFunc := range funcs {
+			desc, err := aggregation.NewWindowFuncDesc(b.ctx, windowFunc.F, args[preArgs:preArgs+len(windowFunc.Args)])
+			if err != nil {
+				return nil, nil, err
+			}
+			if desc == nil {
+				return nil, nil, ErrWrongArguments.GenWithStackByArgs(strings.ToLower(windowFunc.F))
+			}
+			preArgs += len(windowFunc.Args)
+			descs = append(descs, desc)
+			windowMap[windowFunc] = schema.Len()
+			schema.Append(&expression.Column{
+				UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+				RetType:  desc.RetTp,
+			})
+			window.names = append(window.names, types.EmptyName)
+		}
+		window.WindowFuncDescs = descs
+		window.SetChildren(np)
+		window.SetSchema(schema)
+		p = window
+	}
+	return p, windowMap, nil
+}
+
+// checkOriginWindowSpecs checks the validation for origin window specifications for a group of functions.
+// Because of the grouped specification is different from it, we should especially check them before build window frame.
+func (b *PlanBuilder) checkOriginWindowSpecs(funcs []*ast.WindowFuncExpr, orderByItems []property.Item) error {
+	for _, f := range funcs {
+		spec := &f.Spec
+		if f.Spec.Name.L != "" {
+			spec = b.windowSpecs[f.Spec.Name.L]
+		}
+		if spec.Frame == nil {
+			continue
+		}
+		start, end := spec.Frame.Extent.Start, spec.Frame.Extent.End
+		if start.Type == ast.Following && start.UnBounded {
+			return ErrWindowFrameStartIllegal.GenWithStackByArgs(getWindowName(spec.Name.O))
+		}
+		if end.Type == ast.Preceding && end.UnBounded {
+			return ErrWindowFrameEndIllegal.GenWithStackByArgs(getWindowName(spec.Name.O))
+		}
+		if start.Type == ast.Following && (end.Type == ast.Preceding || end.Type == ast.CurrentRow) {
+			return ErrWindowFrameIllegal.GenWithStackByArgs(getWindowName(spec.Name.O))
+		}
+		if (start.Type == ast.Following || start.Type == ast.CurrentRow) && end.Type == ast.Preceding {
+			return ErrWindowFrameIllegal.GenWithStackByArgs(getWindowName(spec.Name.O))
+		}
+
+		err := b.checkOriginWindowFrameBound(&start, spec, orderByItems)
+		if err != nil {
+			return err
+		}
+		err = b.checkOriginWindowFrameBound(&end, spec, orderByItems)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *PlanBuilder) checkOriginWindowFrameBound(bound *ast.FrameBound, spec *ast.WindowSpec, orderByItems []property.Item) error {
+	if bound.Type == ast.CurrentRow || bound.UnBounded {
+		return nil
+	}
+
+	frameType := spec.Frame.Type
+	if frameType == ast.Rows {
+		_, isNull, isExpectedType := getUintFromNode(b.ctx, bound.Expr)
+		if isNull || !isExpectedType {
+			return ErrWindowFrameIllegal.GenWithStackByArgs(getWindowName(spec.Name.O))
+		}
+		return nil
+	}
+
+	if len(orderByItems) != 1 {
+		return ErrWindowRangeFrameOrderType.GenWithStackByArgs(getWindowName(spec.Name.O))
+	}
+	if !types.IsTypeNumeric(orderByItems[0].Col.RetType.Tp) {
+		return ErrWindowRangeFrameOrderType.GenWithStackByArgs(getWindowName(spec.Name.O))
+	}
+	return nil
+}
+
+func extractWindowFuncs(fields []*ast.SelectField) []*ast.WindowFuncExpr {
+	extractor := &WindowFuncExtractor{}
+	for _, f := range fields {
+		n, _ := f.Expr.Accept(extractor)
+		f.Expr = n.(ast.ExprNode)
+	}
+	return extractor.windowFuncs
+}
+
+func (b *PlanBuilder) handleDefaultFrame(spec *ast.WindowSpec, windowFuncName string) (*ast.WindowSpec, bool) {
+	needFrame := aggregation.NeedFrame(windowFuncName)
+	// According to MySQL, In the absence of a frame clause, the default frame depends on whether an ORDER BY clause is present:
+	//   (1) With order by, the default frame is equivalent to "RANGE BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW";
+	//   (2) Without order by, the default frame includes all partition rows, equivalent to "RANGE BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING",
+	//       or "ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING", which is the same as an empty frame.
+	if needFrame && spec.Frame == nil && spec.OrderBy != nil {
+		newSpec := *spec
+		newSpec.Frame = &ast.FrameClause{
+			Type: ast.Ranges,
+			Extent: ast.FrameExtent{
+				Start: ast.FrameBound{Type: ast.Preceding, UnBounded: true},
+				End:   ast.FrameBound{Type: ast.CurrentRow},
+			},
+		}
+		return &newSpec, true
+	}
+	// "RANGE/ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING" is equivalent to empty frame.
+	if needFrame && spec.Frame != nil &&
+		spec.Frame.Extent.Start.UnBounded && spec.Frame.Extent.Start.Type == ast.Preceding &&
+		spec.Frame.Extent.End.UnBounded && spec.Frame.Extent.End.Type == ast.Following {
+		newSpec := *spec
+		newSpec.Frame = nil
+		return &newSpec, true
+	}
+	// For functions that operate on the entire partition, the frame clause will be ignored.
+	if !needFrame && spec.Frame != nil {
+		b.ctx.GetSessionVars().StmtCtx.AppendNote(ErrWindowFunctionIgnoresFrame.GenWithStackByArgs(strings.ToLower(windowFuncName), getWindowName(spec.Name.O)))
+		newSpec := *spec
+		newSpec.Frame = nil
+		return &newSpec, true
+	}
+	return spec, false
+}
+
+// groupWindowFuncs groups the window functions according to the window specification name.
+// TODO: We can group the window function by the definition of window specification.
+func (b *PlanBuilder) groupWindowFuncs(windowFuncs []*ast.WindowFuncExpr) (map[*ast.WindowSpec][]*ast.WindowFuncExpr, []*ast.WindowSpec, error) {
+	// updatedSpecMap is used to handle the specifications that have frame clause changed.
+	updatedSpecMap := make(map[string]*ast.WindowSpec)
+	groupedWindow := make(map[*ast.WindowSpec][]*ast.WindowFuncExpr)
+	orderedSpec := make([]*ast.WindowSpec, 0, len(windowFuncs))
+	for _, windowFunc := range windowFuncs {
+		funcName := strings.ToLower(windowFunc.F)
+		if windowFunc.Distinct && funcName != ast.AggFuncMax && funcName != ast.AggFuncMin {
+			return nil, nil, ErrNotSupportedYet.GenWithStackByArgs("<window function>(DISTINCT ..)")
+		}
+		if windowFunc.Spec.Name.L == "" {
+			spec := &windowFunc.Spec
+			if spec.Ref.L != "" {
+				ref, ok := b.windowSpecs[spec.Ref.L]
+				if !ok {
+					return nil, nil, ErrWindowNoSuchWindow.GenWithStackByArgs(getWindowName(spec.Ref.O))
+				}
+				err := mergeWindowSpec(spec, ref)
+				if err != nil {
+					return nil, nil, err
+				}
+			}
+			spec, _ = b.handleDefaultFrame(spec, windowFunc.F)
+			groupedWindow[spec] = append(groupedWindow[spec], windowFunc)
+			orderedSpec = append(orderedSpec, spec)
+			continue
+		}
+
+		name := windowFunc.Spec.Name.L
+		spec, ok := b.windowSpecs[name]
+		if !ok {
+			return nil, nil, ErrWindowNoSuchWindow.GenWithStackByArgs(windowFunc.Spec.Name.O)
+		}
+		newSpec, updated := b.handleDefaultFrame(spec, windowFunc.F)
+		if updated {
+			if _, ok := updatedSpecMap[name]; !ok {
+				updatedSpecMap[name] = newSpec
+			}
+			spec = updatedSpecMap[name]
+		}
+		if _, ok := groupedWindow[spec]; !ok {
+			orderedSpec = append(orderedSpec, spec)
+		}
+		groupedWindow[spec] = append(groupedWindow[spec], windowFunc)
+	}
+	return groupedWindow, orderedSpec, nil
+}
+
+// resolveWindowSpec resolves window specifications for sql like `select ... from t window w1 as (w2), w2 as (partition by a)`.
+// We need to resolve the referenced window to get the definition of current window spec.
+func resolveWindowSpec(spec *ast.WindowSpec, specs map[string]*ast.WindowSpec, inStack map[string]bool) error {
+	if inStack[spec.Name.L] {
+		return errors.Trace(ErrWindowCircularityInWindowGraph)
+	}
+	if spec.Ref.L == "" {
+		return nil
+	}
+	ref, ok := specs[spec.Ref.L]
+	if !ok {
+		return ErrWindowNoSuchWindow.GenWithStackByArgs(spec.Ref.O)
+	}
+	inStack[spec.Name.L] = true
+	err := resolveWindowSpec(ref, specs, inStack)
+	if err != nil {
+		return err
+	}
+	inStack[spec.Name.L] = false
+	return mergeWindowSpec(spec, ref)
+}
+
+func mergeWindowSpec(spec, ref *ast.WindowSpec) error {
+	if ref.Frame != nil {
+		return ErrWindowNoInherentFrame.GenWithStackByArgs(ref.Name.O)
+	}
+	if spec.PartitionBy != nil {
+		return errors.Trace(ErrWindowNoChildPartitioning)
+	}
+	if ref.OrderBy != nil {
+		if spec.OrderBy != nil {
+			return ErrWindowNoRedefineOrderBy.GenWithStackByArgs(getWindowName(spec.Name.O), ref.Name.O)
+		}
+		spec.OrderBy = ref.OrderBy
+	}
+	spec.PartitionBy = ref.PartitionBy
+	spec.Ref = model.NewCIStr("")
+	return nil
+}
+
+// buildWindowSpecs checks the named window specifications of the WINDOW
+// clause and resolves the references between them.
+func buildWindowSpecs(specs []ast.WindowSpec) (map[string]*ast.WindowSpec, error) {
+	specsMap := make(map[string]*ast.WindowSpec, len(specs))
+	for _, spec := range specs {
+		if _, ok := specsMap[spec.Name.L]; ok {
+			return nil, ErrWindowDuplicateName.GenWithStackByArgs(spec.Name.O)
+		}
+		newSpec := spec
+		specsMap[spec.Name.L] = &newSpec
+	}
+	inStack := make(map[string]bool, len(specs))
+	for name, spec := range specsMap {
+		err := resolveWindowSpec(spec, specsMap, inStack)
+		if err != nil {
+			return nil, err
+		}
+		inStack[name] = false
+	}
+	return specsMap, nil
+}
+
+func getWindowName(name string) string {
+	if name == "" {
+		return "<unnamed window>"
+	}
+	return name
+}
+
+// cteInfo records a common table expression defined by a WITH clause.
+type cteInfo struct {
+	def *ast.CommonTableExpression
+	// isRecursive indicates the common table expression is defined in a
+	// WITH RECURSIVE clause and references itself.
+	isRecursive bool
+	// isInline indicates the common table expression is not recursive and is
+	// referenced no more than once, so it is built in place like a derived table.
+	// Otherwise it is materialized and shared by all the references.
+	isInline bool
+	// cteScope and outerSchemaLen are the number of common table expressions and
+	// outer query schemas visible to the definition.
+	cteScope       int
+	outerSchemaLen int
+
+	// cteClass is set once a materialized common table expression is built.
+	cteClass *CTEClass
+	// seedSchema is the output schema of the seed part of a recursive common table expression.
+	seedSchema *expression.Schema
+	// buildingRecursivePart indicates the recursive part is being built, the
+	// references met then are the recursive references.
+	buildingRecursivePart bool
+	recursiveRefOuterLen  int
+	recursiveRefCnt       int
+}
+
+// cteRefCounter counts the unqualified table names referencing a common table expression.
+type cteRefCounter struct {
+	name model.CIStr
+	// skip is the definition of the common table expression, the references
+	// in it are not counted.
+	skip  *ast.CommonTableExpression
+	count int
+}
+
+// Enter implements Visitor interface.
+func (c *cteRefCounter) Enter(n ast.Node) (ast.Node, bool) {
+	switch x := n.(type) {
+	case *ast.CommonTableExpression:
+		return n, x == c.skip
+	case *ast.TableName:
+		if x.Schema.L == "" && x.Name.L == c.name.L {
+			c.count++
+		}
+	}
+	return n, false
+}
+
+// Leave implements Visitor interface.
+func (c *cteRefCounter) Leave(n ast.Node) (ast.Node, bool) {
+	return n, true
+}
+
+func countCTERefs(node ast.Node, name model.CIStr, skip *ast.CommonTableExpression) int {
+	counter := &cteRefCounter{name: name, skip: skip}
+	node.Accept(counter)
+	return counter.count
+}
+
+// cteAggChecker checks whether there are aggregate or window functions in a
+// query block, the subqueries are not checked.
+type cteAggChecker struct {
+	hasAgg bool
+}
+
+// Enter implements Visitor interface.
+func (c *cteAggChecker) Enter(n ast.Node) (ast.Node, bool) {
+	switch n.(type) {
+	case *ast.AggregateFuncExpr, *ast.WindowFuncExpr:
+		c.hasAgg = true
+		return n, true
+	case *ast.SubqueryExpr:
+		return n, true
+	}
+	return n, false
+}
+
+// Leave implements Visitor interface.
+func (c *cteAggChecker) Leave(n ast.Node) (ast.Node, bool) {
+	return n, true
+}
+
+func hasAggInCTERecursivePart(sel *ast.SelectStmt) bool {
+	if sel.GroupBy != nil || sel.Distinct {
+		return true
+	}
+	checker := &cteAggChecker{}
+	sel.Fields.Accept(checker)
+	if sel.Having != nil {
+		sel.Having.Accept(checker)
+	}
+	if sel.OrderBy != nil {
+		sel.OrderBy.Accept(checker)
+	}
+	return checker.hasAgg
+}
+
+// buildWith records the common table expressions defined by the WITH clause
+// of node, they are built when they are referenced.
+func (b *PlanBuilder) buildWith(node ast.ResultSetNode, with *ast.WithClause) error {
+	names := make(map[string]struct{}, len(with.CTEs))
+	for _, cte := range with.CTEs {
+		if _, ok := names[cte.Name.L]; ok {
+			return ErrNonUniqTable.GenWithStackByArgs(cte.Name.O)
+		}
+		names[cte.Name.L] = struct{}{}
+		info := &cteInfo{
+			def:            cte,
+			cteScope:       len(b.outerCTEs),
+			outerSchemaLen: len(b.outerSchemas),
+		}
+		if with.IsRecursive {
+			// A recursive common table expression is visible to itself.
+			info.cteScope++
+			info.isRecursive = countCTERefs(cte.Query, cte.Name, nil) > 0
+		}
+		info.isInline = !info.isRecursive && countCTERefs(node, cte.Name, cte) <= 1
+		b.outerCTEs = append(b.outerCTEs, info)
+	}
+	return nil
+}
+
+// findCTE finds the innermost common table expression the table name refers to.
+func (b *PlanBuilder) findCTE(tn *ast.TableName) *cteInfo {
+	if tn.Schema.L != "" {
+		return nil
+	}
+	for i := len(b.outerCTEs) - 1; i >= 0; i-- {
+		if b.outerCTEs[i].def.Name.L == tn.Name.L {
+			return b.outerCTEs[i]
+		}
+	}
+	return nil
+}
+
+// switchToCTEScope makes only the common table expressions and outer queries
+// visible to the definition of cte visible, it returns a function to restore
+// the original scope.
+func (b *PlanBuilder) switchToCTEScope(cte *cteInfo) func() {
+	outerCTEs, outerSchemas, outerNames := b.outerCTEs, b.outerSchemas, b.outerNames
+	// The slices are copied so that the original ones are not overwritten by
+	// the appends when building the definition.
+	b.outerCTEs = append([]*cteInfo(nil), outerCTEs[:cte.cteScope]...)
+	b.outerSchemas = append([]*expression.Schema(nil), outerSchemas[:cte.outerSchemaLen]...)
+	b.outerNames = append([][]*types.FieldName(nil), outerNames[:cte.outerSchemaLen]...)
+	return func() {
+		b.outerCTEs, b.outerSchemas, b.outerNames = outerCTEs, outerSchemas, outerNames
+	}
+}
+
+// buildCTERef builds a reference to a common table expression.
+func (b *PlanBuilder) buildCTERef(ctx context.Context, cte *cteInfo, asName model.CIStr) (LogicalPlan, error) {
+	tblName := asName
+	if tblName.L == "" {
+		tblName = cte.def.Name
+	}
+	if cte.buildingRecursivePart {
+		return b.buildCTETable(cte, tblName)
+	}
+	if cte.isInline {
+		restore := b.switchToCTEScope(cte)
+		p, err := b.buildResultSetNode(ctx, cte.def.Query.Query)
+		restore()
+		if err != nil {
+			return nil, err
+		}
+		names, err := buildCTENames(cte.def, p.OutputNames(), tblName)
+		if err != nil {
+			return nil, err
+		}
+		p.SetOutputNames(names)
+		return p, nil
+	}
+	if cte.cteClass == nil {
+		if err := b.buildCTEClass(ctx, cte); err != nil {
+			return nil, err
+		}
+	} else if cte.cteClass.seedPartPhysicalPlan == nil {
+		// The common table expression is referenced by its own seed part.
+		return nil, ErrCTERecursiveNonRecursiveFirst.GenWithStackByArgs(cte.def.Name.O)
+	}
+	seedPlan := cte.cteClass.seedPartLogicalPlan
+	names, err := buildCTENames(cte.def, seedPlan.OutputNames(), tblName)
+	if err != nil {
+		return nil, err
+	}
+	p := LogicalCTE{cte: cte.cteClass, cteAsName: tblName}.Init(b.ctx)
+	p.SetSchema(b.buildCTESchema(seedPlan.Schema(), cte.isRecursive))
+	p.SetOutputNames(names)
+	b.handleHelper.pushMap(nil)
+	return p, nil
+}
+
+// buildCTETable builds the recursive reference of a recursive common table expression.
+func (b *PlanBuilder) buildCTETable(cte *cteInfo, tblName model.CIStr) (LogicalPlan, error) {
+	// The recursive reference must appear only once and not in any subquery.
+	if cte.recursiveRefCnt > 0 || len(b.outerSchemas) != cte.recursiveRefOuterLen {
+		return nil, ErrCTERecursiveSingleReference.GenWithStackByArgs(cte.def.Name.O)
+	}
+	cte.recursiveRefCnt++
+	names, err := buildCTENames(cte.def, cte.cteClass.seedPartLogicalPlan.OutputNames(), tblName)
+	if err != nil {
+		return nil, err
+	}
+	p := LogicalCTETable{cte: cte.cteClass, name: tblName}.Init(b.ctx)
+	p.SetSchema(b.buildCTESchema(cte.seedSchema, true))
+	p.SetOutputNames(names)
+	b.handleHelper.pushMap(nil)
+	return p, nil
+}
+
+// buildCTESchema builds the schema of a reference to a materialized common
+// table expression with new columns.
+func (b *PlanBuilder) buildCTESchema(seedSchema *expression.Schema, isRecursive bool) *expression.Schema {
+	cols := make([]*expression.Column, 0, seedSchema.Len())
+	for _, col := range seedSchema.Columns {
+		tp := col.RetType.Clone()
+		if isRecursive {
+			// The rows produced by the recursive part may contain NULL.
+			tp.Flag &= ^mysql.NotNullFlag
+		}
+		cols = append(cols, &expression.Column{
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  tp,
+		})
+	}
+	return expression.NewSchema(cols...)
+}
+
+// buildCTENames builds the output names of a reference to a common table expression.
+func buildCTENames(cte *ast.CommonTableExpression, names types.NameSlice, tblName model.CIStr) (types.NameSlice, error) {
+	if len(cte.ColNameList) > 0 && len(cte.ColNameList) != len(names) {
+		return nil, ErrViewWrongList
+	}
+	newNames := make(types.NameSlice, 0, len(names))
+	for i, name := range names {
+		colName := name.ColName
+		if len(cte.ColNameList) > 0 {
+			colName = cte.ColNameList[i]
+		}
+		newNames = append(newNames, &types.FieldName{
+			TblName:     tblName,
+			OrigTblName: cte.Name,
+			ColName:     colName,
+			OrigColName: colName,
+		})
+	}
+	return newNames, nil
+}
+
+// buildCTEClass builds and optimizes the parts of a materialized common table expression.
+func (b *PlanBuilder) buildCTEClass(ctx context.Context, cte *cteInfo) error {
+	restore := b.switchToCTEScope(cte)
+	defer restore()
+	cteClass := &CTEClass{IDForStorage: int(b.ctx.GetSessionVars().AllocPlanColumnID())}
+	cte.cteClass = cteClass
+	if cte.isRecursive {
+		if err := b.buildRecursiveCTE(ctx, cte); err != nil {
+			return err
+		}
+	} else {
+		p, err := b.buildResultSetNode(ctx, cte.def.Query.Query)
+		if err != nil {
+			return err
+		}
+		b.handleHelper.popMap()
+		cteClass.seedPartLogicalPlan = p
+	}
+	// A materialized common table expression is evaluated only once, so it
+	// can not reference the columns of the outer queries.
+	if len(cteClass.seedPartLogicalPlan.extractCorrelatedCols()) > 0 ||
+		(cteClass.recursivePartLogicalPlan != nil && len(cteClass.recursivePartLogicalPlan.extractCorrelatedCols()) > 0) {
+		return ErrNotSupportedYet.GenWithStackByArgs("correlated common table expression referenced more than once")
+	}
+	var err error
+	cteClass.seedPartPhysicalPlan, err = DoOptimize(ctx, b.optFlag, cteClass.seedPartLogicalPlan)
+	if err != nil {
+		return err
+	}
+	if cteClass.recursivePartLogicalPlan != nil {
+		cteClass.recursivePartPhysicalPlan, err = DoOptimize(ctx, b.optFlag, cteClass.recursivePartLogicalPlan)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildRecursiveCTE builds the seed part and the recursive part of a recursive
+// common table expression. The query must be a UNION whose leading query blocks
+// which do not reference the common table expression form the seed part, and
+// the other ones form the recursive part.
+func (b *PlanBuilder) buildRecursiveCTE(ctx context.Context, cte *cteInfo) error {
+	name := cte.def.Name
+	setOpr, ok := cte.def.Query.Query.(*ast.SetOprStmt)
+	if !ok {
+		return ErrCTERecursiveRequiresUnion.GenWithStackByArgs(name.O)
+	}
+	if setOpr.With != nil {
+		l := len(b.outerCTEs)
+		defer func() { b.outerCTEs = b.outerCTEs[:l] }()
+		if err := b.buildWith(setOpr, setOpr.With); err != nil {
+			return err
+		}
+	}
+	if setOpr.OrderBy != nil || setOpr.Limit != nil {
+		return ErrNotSupportedYet.GenWithStackByArgs("ORDER BY / LIMIT in recursive Common Table Expression")
+	}
+	selects := setOpr.SelectList.Selects
+	seedCnt := 0
+	for seedCnt < len(selects) && countCTERefs(selects[seedCnt], name, nil) == 0 {
+		seedCnt++
+	}
+	if seedCnt == 0 {
+		return ErrCTERecursiveNonRecursiveFirst.GenWithStackByArgs(name.O)
+	}
+	cteClass := cte.cteClass
+	for _, sel := range selects[seedCnt:] {
+		switch *sel.AfterSetOperator {
+		case ast.Union:
+			cteClass.IsDistinct = true
+		case ast.UnionAll:
+		default:
+			return ErrCTERecursiveRequiresUnion.GenWithStackByArgs(name.O)
+		}
+		if hasAggInCTERecursivePart(sel) {
+			return ErrCTERecursiveForbidsAggregation.GenWithStackByArgs(name.O)
+		}
+	}
+
+	seed, err := b.buildCTEPart(ctx, selects[:seedCnt])
+	if err != nil {
+		return err
+	}
+	cteClass.seedPartLogicalPlan = seed
+	cte.seedSchema = seed.Schema()
+
+	cte.buildingRecursivePart = true
+	cte.recursiveRefOuterLen = len(b.outerSchemas)
+	recur, err := b.buildCTEPart(ctx, selects[seedCnt:])
+	cte.buildingRecursivePart = false
+	if err != nil {
+		return err
+	}
+	if recur.Schema().Len() != seed.Schema().Len() {
+		return ErrWrongNumberOfColumnsInSelect.GenWithStackByArgs()
+	}
+	cteClass.recursivePartLogicalPlan = b.castCTERecursivePart(recur, seed.Schema())
+	return nil
+}
+
+// buildCTEPart builds the query blocks of the seed part or the recursive part.
+func (b *PlanBuilder) buildCTEPart(ctx context.Context, selects []*ast.SelectStmt) (LogicalPlan, error) {
+	var (
+		p   LogicalPlan
+		err error
+	)
+	if len(selects) == 1 {
+		p, err = b.buildSelect(ctx, selects[0])
+	} else {
+		p, err = b.buildSetOpr(ctx, &ast.SetOprStmt{SelectList: &ast.SetOprSelectList{Selects: selects}})
+	}
+	if err != nil {
+		return nil, err
+	}
+	b.handleHelper.popMap()
+	return p, nil
+}
+
+// castCTERecursivePart casts the output of the recursive part to the types of
+// the seed part, since the types of a recursive common table expression are
+// decided by the seed part only.
+func (b *PlanBuilder) castCTERecursivePart(recur LogicalPlan, seedSchema *expression.Schema) LogicalPlan {
+	needCast := false
+	exprs := make([]expression.Expression, 0, seedSchema.Len())
+	for i, col := range recur.Schema().Columns {
+		seedTp := seedSchema.Columns[i].RetType
+		if col.RetType.Equal(seedTp) {
+			exprs = append(exprs, col)
+			continue
+		}
+		needCast = true
+		exprs = append(exprs, expression.BuildCastFunction(b.ctx, col, seedTp))
+	}
+	if !needCast {
+		return recur
+	}
+	proj := LogicalProjection{Exprs: exprs}.Init(b.ctx)
+	proj.SetChildren(recur)
+	schema := make([]*expression.Column, 0, len(exprs))
+	for _, expr := range exprs {
+		schema = append(schema, &expression.Column{
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  expr.GetType(),
+		})
+	}
+	proj.SetSchema(expression.NewSchema(schema...))
+	proj.names = recur.OutputNames()
+	return proj
+}
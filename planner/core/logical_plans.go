@@ -43,6 +43,13 @@ var (
 	_ LogicalPlan = &LogicalIndexScan{}
 	_ LogicalPlan = &LogicalSort{}
 	_ LogicalPlan = &LogicalLimit{}
+	_ LogicalPlan = &LogicalApply{}
+	_ LogicalPlan = &LogicalMaxOneRow{}
+	_ LogicalPlan = &LogicalLock{}
+	_ LogicalPlan = &LogicalUnionAll{}
+	_ LogicalPlan = &LogicalWindow{}
+	_ LogicalPlan = &LogicalCTE{}
+	_ LogicalPlan = &LogicalCTETable{}
 )
 
 // JoinType contains CrossJoin, InnerJoin, LeftOuterJoin, RightOuterJoin, FullOuterJoin, SemiJoin.
@@ -55,11 +62,20 @@ const (
 	LeftOuterJoin
 	// RightOuterJoin means right join.
 	RightOuterJoin
+	// SemiJoin means if row a in table A matches some rows in B, just output a.
+	SemiJoin
+	// AntiSemiJoin means if row a in table A does not match any row in B, then output a.
+	AntiSemiJoin
+	// LeftOuterSemiJoin means if row a in table A matches some rows in B, output (a, true), otherwise, output (a, false).
+	LeftOuterSemiJoin
+	// AntiLeftOuterSemiJoin means if row a in table A matches some rows in B, output (a, false), otherwise, output (a, true).
+	AntiLeftOuterSemiJoin
 )
 
 // IsOuterJoin returns if this joiner is a outer joiner
 func (tp JoinType) IsOuterJoin() bool {
-	return tp == LeftOuterJoin || tp == RightOuterJoin
+	return tp == LeftOuterJoin || tp == RightOuterJoin ||
+		tp == LeftOuterSemiJoin || tp == AntiLeftOuterSemiJoin
 }
 
 func (tp JoinType) String() string {
@@ -70,12 +86,22 @@ func (tp JoinType) String() string {
 		return "left outer join"
 	case RightOuterJoin:
 		return "right outer join"
+	case SemiJoin:
+		return "semi join"
+	case AntiSemiJoin:
+		return "anti semi join"
+	case LeftOuterSemiJoin:
+		return "left outer semi join"
+	case AntiLeftOuterSemiJoin:
+		return "anti left outer semi join"
 	}
 	return "unsupported join type"
 }
 
 const (
-	preferHashJoin uint = 1 << iota
+	preferLeftAsIndexInner uint = 1 << iota
+	preferRightAsIndexInner
+	preferHashJoin
 	preferMergeJoin
 )
 
@@ -120,6 +146,68 @@ func (p *LogicalJoin) attachOnConds(onConds []expression.Expression) {
 	p.OtherConditions = append(other, p.OtherConditions...)
 }
 
+func (p *LogicalJoin) columnSubstitute(schema *expression.Schema, exprs []expression.Expression) {
+	for i, cond := range p.LeftConditions {
+		p.LeftConditions[i] = expression.ColumnSubstitute(cond, schema, exprs)
+	}
+	for i, cond := range p.RightConditions {
+		p.RightConditions[i] = expression.ColumnSubstitute(cond, schema, exprs)
+	}
+	for i, cond := range p.OtherConditions {
+		p.OtherConditions[i] = expression.ColumnSubstitute(cond, schema, exprs)
+	}
+	for i := len(p.EqualConditions) - 1; i >= 0; i-- {
+		newCond := expression.ColumnSubstitute(p.EqualConditions[i], schema, exprs).(*expression.ScalarFunction)
+
+		// If the columns used in the new filter all come from the left child,
+		// we can push this filter to it.
+		if expression.ExprFromSchema(newCond, p.children[0].Schema()) {
+			p.LeftConditions = append(p.LeftConditions, newCond)
+			p.EqualConditions = append(p.EqualConditions[:i], p.EqualConditions[i+1:]...)
+			continue
+		}
+
+		// If the columns used in the new filter all come from the right
+		// child, we can push this filter to it.
+		if expression.ExprFromSchema(newCond, p.children[1].Schema()) {
+			p.RightConditions = append(p.RightConditions, newCond)
+			p.EqualConditions = append(p.EqualConditions[:i], p.EqualConditions[i+1:]...)
+			continue
+		}
+
+		_, lhsIsCol := newCond.GetArgs()[0].(*expression.Column)
+		_, rhsIsCol := newCond.GetArgs()[1].(*expression.Column)
+
+		// If the columns used in the new filter are not all expression.Column,
+		// we can not use it as join's equal condition.
+		if !(lhsIsCol && rhsIsCol) {
+			p.OtherConditions = append(p.OtherConditions, newCond)
+			p.EqualConditions = append(p.EqualConditions[:i], p.EqualConditions[i+1:]...)
+			continue
+		}
+
+		p.EqualConditions[i] = newCond
+	}
+}
+
+// extractCorrelatedCols implements LogicalPlan interface.
+func (p *LogicalJoin) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := p.baseLogicalPlan.extractCorrelatedCols()
+	for _, fun := range p.EqualConditions {
+		corCols = append(corCols, expression.ExtractCorColumns(fun)...)
+	}
+	for _, fun := range p.LeftConditions {
+		corCols = append(corCols, expression.ExtractCorColumns(fun)...)
+	}
+	for _, fun := range p.RightConditions {
+		corCols = append(corCols, expression.ExtractCorColumns(fun)...)
+	}
+	for _, fun := range p.OtherConditions {
+		corCols = append(corCols, expression.ExtractCorColumns(fun)...)
+	}
+	return corCols
+}
+
 // LogicalProjection represents a select fields plan.
 type LogicalProjection struct {
 	logicalSchemaProducer
@@ -127,6 +215,15 @@ type LogicalProjection struct {
 	Exprs []expression.Expression
 }
 
+// extractCorrelatedCols implements LogicalPlan interface.
+func (p *LogicalProjection) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := p.baseLogicalPlan.extractCorrelatedCols()
+	for _, expr := range p.Exprs {
+		corCols = append(corCols, expression.ExtractCorColumns(expr)...)
+	}
+	return corCols
+}
+
 // LogicalAggregation represents an aggregate plan.
 type LogicalAggregation struct {
 	logicalSchemaProducer
@@ -156,6 +253,23 @@ func (la *LogicalAggregation) GetGroupByCols() []*expression.Column {
 	return la.groupByCols
 }
 
+// extractCorrelatedCols implements LogicalPlan interface.
+func (la *LogicalAggregation) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := la.baseLogicalPlan.extractCorrelatedCols()
+	for _, expr := range la.GroupByItems {
+		corCols = append(corCols, expression.ExtractCorColumns(expr)...)
+	}
+	for _, fun := range la.AggFuncs {
+		for _, arg := range fun.Args {
+			corCols = append(corCols, expression.ExtractCorColumns(arg)...)
+		}
+		for _, byItem := range fun.OrderByItems {
+			corCols = append(corCols, expression.ExtractCorColumns(byItem.Expr)...)
+		}
+	}
+	return corCols
+}
+
 // LogicalSelection represents a where or having predicate.
 type LogicalSelection struct {
 	baseLogicalPlan
@@ -166,6 +280,51 @@ type LogicalSelection struct {
 	Conditions []expression.Expression
 }
 
+// extractCorrelatedCols implements LogicalPlan interface.
+func (p *LogicalSelection) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := p.baseLogicalPlan.extractCorrelatedCols()
+	for _, cond := range p.Conditions {
+		corCols = append(corCols, expression.ExtractCorColumns(cond)...)
+	}
+	return corCols
+}
+
+// LogicalApply gets one row from outer executor and gets one row from inner executor according to outer row.
+type LogicalApply struct {
+	LogicalJoin
+
+	CorCols []*expression.CorrelatedColumn
+}
+
+// extractCorrelatedCols implements LogicalPlan interface.
+func (la *LogicalApply) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := la.LogicalJoin.extractCorrelatedCols()
+	for i := len(corCols) - 1; i >= 0; i-- {
+		if la.children[0].Schema().Contains(&corCols[i].Column) {
+			corCols = append(corCols[:i], corCols[i+1:]...)
+		}
+	}
+	return corCols
+}
+
+// LogicalMaxOneRow checks if a query returns no more than one row.
+type LogicalMaxOneRow struct {
+	baseLogicalPlan
+}
+
+// LogicalLock represents a select lock plan.
+type LogicalLock struct {
+	baseLogicalPlan
+
+	Lock         ast.SelectLockType
+	tblID2Handle map[int64][]*expression.Column
+}
+
+// LogicalUnionAll represents LogicalUnionAll plan.
+type LogicalUnionAll struct {
+	logicalSchemaProducer
+}
+
 // LogicalTableDual represents a dual table plan.
 type LogicalTableDual struct {
 	logicalSchemaProducer
@@ -173,6 +332,40 @@ type LogicalTableDual struct {
 	RowCount int
 }
 
+// CTEClass holds the information of a materialized common table expression.
+// All the references to the same common table expression share one CTEClass,
+// so the expression is only evaluated once in a query.
+type CTEClass struct {
+	// IsDistinct indicates the seed part and the recursive part are joined by UNION DISTINCT.
+	IsDistinct bool
+	// IDForStorage is the id of the storage holding the result, the recursive
+	// part reads the work table of the storage by this id.
+	IDForStorage int
+
+	seedPartLogicalPlan      LogicalPlan
+	recursivePartLogicalPlan LogicalPlan
+	seedPartPhysicalPlan     PhysicalPlan
+	// recursivePartPhysicalPlan is nil for non-recursive common table expressions.
+	recursivePartPhysicalPlan PhysicalPlan
+}
+
+// LogicalCTE is a reference to a materialized common table expression.
+type LogicalCTE struct {
+	logicalSchemaProducer
+
+	cte       *CTEClass
+	cteAsName model.CIStr
+}
+
+// LogicalCTETable is the reference to a recursive common table expression
+// inside its recursive part, it reads the rows produced by the previous iteration.
+type LogicalCTETable struct {
+	logicalSchemaProducer
+
+	cte  *CTEClass
+	name model.CIStr
+}
+
 // LogicalMemTable represents a memory table or virtual table
 type LogicalMemTable struct {
 	logicalSchemaProducer
@@ -224,6 +417,15 @@ type DataSource struct {
 	TblColHists *statistics.HistColl
 }
 
+// extractCorrelatedCols implements LogicalPlan interface.
+func (ds *DataSource) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := make([]*expression.CorrelatedColumn, 0, len(ds.pushedDownConds))
+	for _, expr := range ds.pushedDownConds {
+		corCols = append(corCols, expression.ExtractCorColumns(expr)...)
+	}
+	return corCols
+}
+
 // TiKVSingleGather is a leaf logical operator of TiDB layer to gather
 // tuples from TiKV regions.
 type TiKVSingleGather struct {
@@ -551,6 +753,15 @@ type LogicalSort struct {
 	ByItems []*ByItems
 }
 
+// extractCorrelatedCols implements LogicalPlan interface.
+func (ls *LogicalSort) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := ls.baseLogicalPlan.extractCorrelatedCols()
+	for _, item := range ls.ByItems {
+		corCols = append(corCols, expression.ExtractCorColumns(item.Expr)...)
+	}
+	return corCols
+}
+
 // LogicalTopN represents a top-n plan.
 type LogicalTopN struct {
 	baseLogicalPlan
@@ -565,6 +776,72 @@ func (lt *LogicalTopN) isLimit() bool {
 	return len(lt.ByItems) == 0
 }
 
+// extractCorrelatedCols implements LogicalPlan interface.
+func (lt *LogicalTopN) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := lt.baseLogicalPlan.extractCorrelatedCols()
+	for _, item := range lt.ByItems {
+		corCols = append(corCols, expression.ExtractCorColumns(item.Expr)...)
+	}
+	return corCols
+}
+
+// FrameBound is the boundary of a frame.
+type FrameBound struct {
+	Type      ast.BoundType
+	UnBounded bool
+	Num       uint64
+	// CalcFuncs is used for range framed windows.
+	// We will build plus or minus functions for frames like `1 preceding`.
+	CalcFuncs []expression.Expression
+	// CmpFuncs is used to decide whether one row is included in the current frame.
+	CmpFuncs []expression.CompareFunc
+}
+
+// WindowFrame represents a window function frame.
+type WindowFrame struct {
+	Type  ast.FrameType
+	Start *FrameBound
+	End   *FrameBound
+}
+
+// LogicalWindow represents a logical window function plan.
+type LogicalWindow struct {
+	logicalSchemaProducer
+
+	WindowFuncDescs []*aggregation.WindowFuncDesc
+	PartitionBy     []property.Item
+	OrderBy         []property.Item
+	Frame           *WindowFrame
+}
+
+// GetWindowResultColumns returns the columns storing the result of the window function.
+func (p *LogicalWindow) GetWindowResultColumns() []*expression.Column {
+	return p.schema.Columns[p.schema.Len()-len(p.WindowFuncDescs):]
+}
+
+// extractCorrelatedCols implements LogicalPlan interface.
+func (p *LogicalWindow) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := p.baseLogicalPlan.extractCorrelatedCols()
+	for _, windowFunc := range p.WindowFuncDescs {
+		for _, arg := range windowFunc.Args {
+			corCols = append(corCols, expression.ExtractCorColumns(arg)...)
+		}
+	}
+	if p.Frame != nil {
+		if p.Frame.Start != nil {
+			for _, expr := range p.Frame.Start.CalcFuncs {
+				corCols = append(corCols, expression.ExtractCorColumns(expr)...)
+			}
+		}
+		if p.Frame.End != nil {
+			for _, expr := range p.Frame.End.CalcFuncs {
+				corCols = append(corCols, expression.ExtractCorColumns(expr)...)
+			}
+		}
+	}
+	return corCols
+}
+
 // LogicalLimit represents offset and limit plan.
 type LogicalLimit struct {
 	baseLogicalPlan
@@ -352,6 +352,77 @@ func (s *testPlanSuite) TestRequestTypeSupportedOff(c *C) {
 	c.Assert(core.ToString(p), Equals, expect, Commentf("for %s", sql))
 }
 
+func (s *testPlanSuite) TestPointGetPlan(c *C) {
+	defer testleak.AfterTest(c)()
+	store, dom, err := newStoreWithBootstrap()
+	c.Assert(err, IsNil)
+	defer func() {
+		dom.Close()
+		store.Close()
+	}()
+	se, err := session.CreateSession4Test(store)
+	c.Assert(err, IsNil)
+	_, err = se.Execute(context.Background(), "use test")
+	c.Assert(err, IsNil)
+
+	tryFastPlan := func(sql string) core.Plan {
+		stmt, err := s.ParseOneStmt(sql, "", "")
+		c.Assert(err, IsNil, Commentf("for %s", sql))
+		c.Assert(core.Preprocess(se, stmt, s.is), IsNil)
+		return core.TryFastPlan(se, stmt)
+	}
+
+	tests := []struct {
+		sql  string
+		best string
+	}{
+		{sql: "select * from t where a = 1", best: "PointGet(Handle(t.a)1)"},
+		{sql: "select b, c from t as x where 1 = x.a", best: "PointGet(Handle(t.a)1)"},
+		{sql: "select * from t where a = 1 limit 1", best: "PointGet(Handle(t.a)1)"},
+		{sql: "select * from t where a = 2147483648", best: "Dual"},
+		{sql: "select * from t where a in (1, 2, 3)", best: "BatchPointGet(Handle(t.a)[1 2 3])"},
+	}
+	for _, tt := range tests {
+		p := tryFastPlan(tt.sql)
+		c.Assert(p, NotNil, Commentf("for %s", tt.sql))
+		c.Assert(core.ToString(p), Equals, tt.best, Commentf("for %s", tt.sql))
+	}
+
+	p, ok := tryFastPlan("select * from t where e = 3 and c = 1 and d = 2").(*core.PointGetPlan)
+	c.Assert(ok, IsTrue)
+	c.Assert(p.IndexInfo.Name.L, Equals, "c_d_e")
+	c.Assert(p.IndexValues, HasLen, 3)
+	for i, val := range []int64{1, 2, 3} {
+		c.Assert(p.IndexValues[i].GetInt64(), Equals, val)
+	}
+
+	bp, ok := tryFastPlan("select a from t where (g, f) in ((2, 1), (4, 3))").(*core.BatchPointGetPlan)
+	c.Assert(ok, IsTrue)
+	c.Assert(bp.IndexInfo.Name.L, Equals, "f_g")
+	c.Assert(bp.IndexValues, HasLen, 2)
+	for i, vals := range [][]int64{{1, 2}, {3, 4}} {
+		c.Assert(bp.IndexValues[i][0].GetInt64(), Equals, vals[0])
+		c.Assert(bp.IndexValues[i][1].GetInt64(), Equals, vals[1])
+	}
+
+	for _, sql := range []string{
+		"select * from t where a > 1",
+		"select * from t where a = 1 or a = 2",
+		"select * from t where a = 1 and b = 1",
+		"select * from t where c = 1 and d = 2",
+		"select count(*) from t where a = 1",
+		"select a + 1 from t where a = 1",
+		"select * from t where a = 1 group by b",
+		"select * from t where a = 1 limit 1, 1",
+		"select * from t t1, t t2 where t1.a = 1",
+		"select * from t where a in (1, b)",
+		"select distinct b from t where a in (1, 2)",
+		"select * from t where a in (1, 2) order by b",
+	} {
+		c.Assert(tryFastPlan(sql), IsNil, Commentf("for %s", sql))
+	}
+}
+
 func (s *testPlanSuite) TestHintAlias(c *C) {
 	defer testleak.AfterTest(c)()
 	store, dom, err := newStoreWithBootstrap()
@@ -16,6 +16,7 @@ package core
 import (
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/expression/aggregation"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/planner/property"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
@@ -37,9 +38,18 @@ var (
 	_ PhysicalPlan = &PhysicalIndexReader{}
 	_ PhysicalPlan = &PhysicalIndexLookUpReader{}
 	_ PhysicalPlan = &PhysicalHashAgg{}
+	_ PhysicalPlan = &PhysicalStreamAgg{}
 	_ PhysicalPlan = &PhysicalHashJoin{}
 	_ PhysicalPlan = &PhysicalMergeJoin{}
+	_ PhysicalPlan = &PhysicalIndexJoin{}
 	_ PhysicalPlan = &PhysicalUnionScan{}
+	_ PhysicalPlan = &PhysicalApply{}
+	_ PhysicalPlan = &PhysicalMaxOneRow{}
+	_ PhysicalPlan = &PhysicalLock{}
+	_ PhysicalPlan = &PhysicalUnionAll{}
+	_ PhysicalPlan = &PhysicalWindow{}
+	_ PhysicalPlan = &PhysicalCTE{}
+	_ PhysicalPlan = &PhysicalCTETable{}
 )
 
 // PhysicalTableReader is the table reader in tidb.
@@ -92,7 +102,7 @@ func (p *PhysicalIndexReader) SetSchema(_ *expression.Schema) {
 	if p.indexPlan != nil {
 		p.IndexPlans = flattenPushDownPlan(p.indexPlan)
 		switch p.indexPlan.(type) {
-		case *PhysicalHashAgg:
+		case *PhysicalHashAgg, *PhysicalStreamAgg:
 			p.schema = p.indexPlan.Schema()
 		default:
 			is := p.IndexPlans[0].(*PhysicalIndexScan)
@@ -139,6 +149,10 @@ type PhysicalIndexScan struct {
 
 	TableAsName *model.CIStr
 
+	// rangeInfo describes how the ranges are built when the index scan is
+	// the inner child of an index join.
+	rangeInfo string
+
 	// dataSourceSchema is the original schema of DataSource. The schema of index scan in KV and index reader in TiDB
 	// will be different. The schema of index scan will decode all columns of index but the TiDB only need some of them.
 	dataSourceSchema *expression.Schema
@@ -175,6 +189,10 @@ type PhysicalTableScan struct {
 
 	TableAsName *model.CIStr
 
+	// rangeDecidedBy is the outer join keys when the table scan is the inner
+	// child of an index join.
+	rangeDecidedBy []*expression.Column
+
 	HandleIdx int
 
 	// KeepOrder is true, if sort data by scanning pkcol,
@@ -243,6 +261,20 @@ func NewPhysicalHashJoin(p *LogicalJoin, innerIdx int, newStats *property.StatsI
 	return hashJoin
 }
 
+// PhysicalIndexJoin represents the plan of index look up join.
+type PhysicalIndexJoin struct {
+	basePhysicalJoin
+
+	innerTask task
+
+	// Ranges stores the IndexRanges when the inner plan is index scan.
+	Ranges []*ranger.Range
+	// KeyOff2IdxOff maps the offsets in join key to the offsets in the index.
+	KeyOff2IdxOff []int
+	// IdxColLens stores the length of each index column.
+	IdxColLens []int
+}
+
 // PhysicalMergeJoin represents merge join implementation of LogicalJoin.
 type PhysicalMergeJoin struct {
 	basePhysicalJoin
@@ -250,6 +282,13 @@ type PhysicalMergeJoin struct {
 	CompareFuncs []expression.CompareFunc
 }
 
+// PhysicalApply represents apply plan, only used for subquery.
+type PhysicalApply struct {
+	PhysicalHashJoin
+
+	OuterSchema []*expression.CorrelatedColumn
+}
+
 // PhysicalLimit is the physical operator of Limit.
 type PhysicalLimit struct {
 	basePhysicalPlan
@@ -294,6 +333,12 @@ func NewPhysicalHashAgg(la *LogicalAggregation, newStats *property.StatsInfo, pr
 	return agg
 }
 
+// PhysicalStreamAgg is stream operator of aggregate, it requires its input
+// to be sorted by the group-by items.
+type PhysicalStreamAgg struct {
+	basePhysicalAgg
+}
+
 // PhysicalSort is the physical operator of sort, which implements a memory sort.
 type PhysicalSort struct {
 	basePhysicalPlan
@@ -331,6 +376,35 @@ type PhysicalSelection struct {
 	Conditions []expression.Expression
 }
 
+// PhysicalMaxOneRow is the physical operator of maxOneRow.
+type PhysicalMaxOneRow struct {
+	basePhysicalPlan
+}
+
+// PhysicalLock is the physical operator of lock, which is used for `select ... for update` clause.
+type PhysicalLock struct {
+	basePhysicalPlan
+
+	Lock ast.SelectLockType
+
+	TblID2Handle map[int64][]*expression.Column
+}
+
+// PhysicalUnionAll is the physical operator of UnionAll.
+type PhysicalUnionAll struct {
+	physicalSchemaProducer
+}
+
+// PhysicalWindow is the physical operator of window function.
+type PhysicalWindow struct {
+	physicalSchemaProducer
+
+	WindowFuncDescs []*aggregation.WindowFuncDesc
+	PartitionBy     []property.Item
+	OrderBy         []property.Item
+	Frame           *WindowFrame
+}
+
 // PhysicalTableDual is the physical operator of dual.
 type PhysicalTableDual struct {
 	physicalSchemaProducer
@@ -352,6 +426,25 @@ func (p *PhysicalTableDual) SetOutputNames(names types.NameSlice) {
 	p.names = names
 }
 
+// PhysicalCTE is the physical operator of a materialized common table expression.
+type PhysicalCTE struct {
+	physicalSchemaProducer
+
+	SeedPlan  PhysicalPlan
+	RecurPlan PhysicalPlan
+	CTE       *CTEClass
+	cteAsName model.CIStr
+}
+
+// PhysicalCTETable is the physical operator reading the work table of a
+// recursive common table expression.
+type PhysicalCTETable struct {
+	physicalSchemaProducer
+
+	IDForStorage int
+	name         model.CIStr
+}
+
 // PhysicalShow represents a show plan.
 type PhysicalShow struct {
 	physicalSchemaProducer
@@ -124,6 +124,12 @@ type LogicalPlan interface {
 
 	// SetChild sets the ith child for the plan.
 	SetChild(i int, child LogicalPlan)
+
+	// MaxOneRow means whether this operator only returns max one row.
+	MaxOneRow() bool
+
+	// extractCorrelatedCols extracts correlated columns inside the LogicalPlan.
+	extractCorrelatedCols() []*expression.CorrelatedColumn
 }
 
 // PhysicalPlan is a tree of the physical operators.
@@ -165,9 +171,16 @@ type PhysicalPlan interface {
 type baseLogicalPlan struct {
 	basePlan
 
-	taskMap  map[string]task
-	self     LogicalPlan
-	children []LogicalPlan
+	taskMap map[string]task
+	// maxOneRow indicates that this plan will output at most one row.
+	maxOneRow bool
+	self      LogicalPlan
+	children  []LogicalPlan
+}
+
+// MaxOneRow implements LogicalPlan MaxOneRow interface.
+func (p *baseLogicalPlan) MaxOneRow() bool {
+	return p.maxOneRow
 }
 
 // ExplainInfo implements Plan interface.
@@ -209,11 +222,17 @@ func (p *baseLogicalPlan) storeTask(prop *property.PhysicalProperty, task task)
 
 // BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
 func (p *baseLogicalPlan) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
+	childMaxOneRow := make([]bool, len(p.children))
+	for i := range p.children {
+		childMaxOneRow[i] = p.children[i].MaxOneRow()
+	}
+	p.maxOneRow = HasMaxOneRow(p.self, childMaxOneRow)
 }
 
 // BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
 func (p *logicalSchemaProducer) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
 	selfSchema.Keys = nil
+	p.baseLogicalPlan.BuildKeyInfo(selfSchema, childSchema)
 }
 
 func newBasePlan(ctx sessionctx.Context, tp string) basePlan {
@@ -249,6 +268,15 @@ func (p *baseLogicalPlan) PruneColumns(parentUsedCols []*expression.Column) erro
 	return p.children[0].PruneColumns(parentUsedCols)
 }
 
+// extractCorrelatedCols implements LogicalPlan interface.
+func (p *baseLogicalPlan) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := make([]*expression.CorrelatedColumn, 0, len(p.children))
+	for _, child := range p.children {
+		corCols = append(corCols, child.extractCorrelatedCols()...)
+	}
+	return corCols
+}
+
 // basePlan implements base Plan interface.
 // Should be used as embedded struct in Plan implementations.
 type basePlan struct {
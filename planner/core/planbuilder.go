@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
@@ -35,9 +36,10 @@ import (
 )
 
 type tableHintInfo struct {
-	sortMergeJoinTables []hintTableInfo
-	hashJoinTables      []hintTableInfo
-	indexHintList       []indexHintInfo
+	indexNestedLoopJoinTables []hintTableInfo
+	sortMergeJoinTables       []hintTableInfo
+	hashJoinTables            []hintTableInfo
+	indexHintList             []indexHintInfo
 }
 
 type hintTableInfo struct {
@@ -76,6 +78,10 @@ func (info *tableHintInfo) ifPreferHashJoin(tableNames ...*hintTableInfo) bool {
 	return info.matchTableName(tableNames, info.hashJoinTables)
 }
 
+func (info *tableHintInfo) ifPreferINLJ(tableNames ...*hintTableInfo) bool {
+	return info.matchTableName(tableNames, info.indexNestedLoopJoinTables)
+}
+
 // matchTableName checks whether the hint hit the need.
 // Only need either side matches one on the list.
 // Even though you can put 2 tables on the list,
@@ -138,6 +144,8 @@ const (
 	groupByClause
 	showStatement
 	globalOrderByClause
+	windowClause
+	partitionByClause
 )
 
 var clauseMsg = map[clauseCode]string{
@@ -150,6 +158,8 @@ var clauseMsg = map[clauseCode]string{
 	groupByClause:       "group statement",
 	showStatement:       "show statement",
 	globalOrderByClause: "global ORDER clause",
+	windowClause:        "window order by",
+	partitionByClause:   "window partition by",
 }
 
 // PlanBuilder builds Plan from an ast.Node.
@@ -166,6 +176,11 @@ type PlanBuilder struct {
 
 	curClause clauseCode
 
+	// outerSchemas and outerNames store the schema and names of outer queries
+	// which can be referenced by a correlated subquery.
+	outerSchemas []*expression.Schema
+	outerNames   [][]*types.FieldName
+
 	// rewriterPool stores the expressionRewriter we have created to reuse it if it has been released.
 	// rewriterCounter counts how many rewriter is being used.
 	rewriterPool    []*expressionRewriter
@@ -184,6 +199,14 @@ type PlanBuilder struct {
 	//   If it's a join, we pop its children's out then merge them and push the new map to stack.
 	//   If we meet a subquery, it's clearly that it's a independent problem so we just pop one map out when we finish building the subquery.
 	handleHelper *handleColHelper
+
+	// windowSpecs stores the named window specifications of the current
+	// "SELECT" statement, keyed by the lower-cased window name.
+	windowSpecs map[string]*ast.WindowSpec
+
+	// outerCTEs stores the common table expressions defined by the WITH
+	// clauses in scope, the inner ones are at the end.
+	outerCTEs []*cteInfo
 }
 
 type handleColHelper struct {
@@ -245,14 +268,24 @@ func (b *PlanBuilder) Build(ctx context.Context, node ast.Node) (Plan, error) {
 	switch x := node.(type) {
 	case *ast.AdminStmt:
 		return b.buildAdmin(ctx, x)
+	case *ast.DeallocateStmt:
+		return &Deallocate{Name: x.Name}, nil
 	case *ast.DeleteStmt:
 		return b.buildDelete(ctx, x)
+	case *ast.UpdateStmt:
+		return b.buildUpdate(ctx, x)
+	case *ast.ExecuteStmt:
+		return b.buildExecute(ctx, x)
 	case *ast.ExplainStmt:
 		return b.buildExplain(ctx, x)
 	case *ast.InsertStmt:
 		return b.buildInsert(ctx, x)
 	case *ast.SelectStmt:
 		return b.buildSelect(ctx, x)
+	case *ast.SetOprStmt:
+		return b.buildSetOpr(ctx, x)
+	case *ast.PrepareStmt:
+		return b.buildPrepare(x), nil
 	case *ast.ShowStmt:
 		return b.buildShow(ctx, x)
 	case *ast.SetStmt:
@@ -267,6 +300,41 @@ func (b *PlanBuilder) Build(ctx context.Context, node ast.Node) (Plan, error) {
 	return nil, ErrUnsupportedType.GenWithStack("Unsupported type %T", node)
 }
 
+func (b *PlanBuilder) buildExecute(ctx context.Context, v *ast.ExecuteStmt) (Plan, error) {
+	vars := make([]expression.Expression, 0, len(v.UsingVars))
+	for _, expr := range v.UsingVars {
+		newExpr, _, err := b.rewrite(ctx, expr, nil, nil, true)
+		if err != nil {
+			return nil, err
+		}
+		vars = append(vars, newExpr)
+	}
+	exe := &Execute{Name: v.Name, UsingVars: vars, ExecID: v.ExecID}
+	if v.BinaryArgs != nil {
+		exe.PrepareParams = v.BinaryArgs.([]types.Datum)
+	}
+	return exe, nil
+}
+
+func (b *PlanBuilder) buildPrepare(x *ast.PrepareStmt) Plan {
+	p := &Prepare{
+		Name: x.Name,
+	}
+	if x.SQLVar != nil {
+		b.ctx.GetSessionVars().UsersLock.RLock()
+		v, ok := b.ctx.GetSessionVars().Users[strings.ToLower(x.SQLVar.Name)]
+		b.ctx.GetSessionVars().UsersLock.RUnlock()
+		if ok {
+			p.SQLText = v
+		} else {
+			p.SQLText = "NULL"
+		}
+	} else {
+		p.SQLText = x.SQLText
+	}
+	return p
+}
+
 func (b *PlanBuilder) buildSet(ctx context.Context, v *ast.SetStmt) (Plan, error) {
 	p := &Set{}
 	for _, vars := range v.Variables {
@@ -319,6 +387,23 @@ func (b *PlanBuilder) detectSelectAgg(sel *ast.SelectStmt) bool {
 	return false
 }
 
+// detectSelectWindow detects a window function in the select fields or the order by clause.
+func (b *PlanBuilder) detectSelectWindow(sel *ast.SelectStmt) bool {
+	for _, f := range sel.Fields.Fields {
+		if ast.HasWindowFlag(f.Expr) {
+			return true
+		}
+	}
+	if sel.OrderBy != nil {
+		for _, item := range sel.OrderBy.Items {
+			if ast.HasWindowFlag(item.Expr) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func getPathByIndexName(paths []*util.AccessPath, idxName model.CIStr, tblInfo *model.TableInfo) *util.AccessPath {
 	var tablePath *util.AccessPath
 	for _, path := range paths {
@@ -480,6 +565,9 @@ func getColsInfo(tn *ast.TableName) (indicesInfo []*model.IndexInfo, colsInfo []
 func (b *PlanBuilder) buildAnalyze(as *ast.AnalyzeTableStmt) (Plan, error) {
 	p := &Analyze{}
 	for _, tbl := range as.TableNames {
+		if tbl.TableInfo.IsView() {
+			return nil, errors.Errorf("analyze view %s is not supported now.", tbl.Name.O)
+		}
 		idxInfo, colInfo, pkInfo := getColsInfo(tbl)
 		for _, idx := range idxInfo {
 			info := analyzeInfo{DBName: tbl.Schema.O, TableName: tbl.Name.O, PhysicalTableID: tbl.TableInfo.ID}
@@ -685,6 +773,9 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 		return nil, infoschema.ErrTableNotExists.GenWithStackByArgs()
 	}
 	tableInfo := tn.TableInfo
+	if tableInfo.IsView() {
+		return nil, ErrNonInsertableTable.GenWithStackByArgs(tn.Name.O, "INSERT")
+	}
 	// Build Schema with DBName otherwise ColumnRef with DBName cannot match any Column in Schema.
 	schema, names := expression.TableInfo2SchemaAndNames(b.ctx, tn.Schema, tableInfo)
 	tableInPlan, ok := b.is.TableByID(tableInfo.ID)
@@ -783,7 +874,7 @@ func (b *PlanBuilder) buildSetValuesOfInsert(ctx context.Context, insert *ast.In
 		if defaultExpr != nil {
 			defaultExpr.Name = assign.Column
 		}
-		expr, _, err := b.rewriteWithPreprocess(ctx, assign.Expr, mockTablePlan, nil, true, checkRefColumn)
+		expr, _, err := b.rewriteWithPreprocess(ctx, assign.Expr, mockTablePlan, nil, nil, true, checkRefColumn)
 		if err != nil {
 			return err
 		}
@@ -845,7 +936,7 @@ func (b *PlanBuilder) buildValuesListOfInsert(ctx context.Context, insert *ast.I
 					RetType: &x.Type,
 				}
 			default:
-				expr, _, err = b.rewriteWithPreprocess(ctx, valueItem, mockTablePlan, nil, true, checkRefColumn)
+				expr, _, err = b.rewriteWithPreprocess(ctx, valueItem, mockTablePlan, nil, nil, true, checkRefColumn)
 			}
 			if err != nil {
 				return err
@@ -907,10 +998,53 @@ func (b *PlanBuilder) buildSelectPlanOfInsert(ctx context.Context, insert *ast.I
 }
 
 func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (Plan, error) {
+	if v, ok := node.(*ast.CreateViewStmt); ok {
+		if err := b.buildCreateView(ctx, v); err != nil {
+			return nil, err
+		}
+	}
 	p := &DDL{Statement: node}
 	return p, nil
 }
 
+// buildCreateView builds the select statement of the view to validate it, then
+// resolves the column names and the definer of the view.
+func (b *PlanBuilder) buildCreateView(ctx context.Context, v *ast.CreateViewStmt) error {
+	plan, err := b.Build(ctx, v.Select)
+	if err != nil {
+		return err
+	}
+	names := plan.OutputNames()
+	if v.Cols == nil {
+		v.SchemaCols = make([]model.CIStr, 0, len(names))
+		for _, name := range names {
+			v.SchemaCols = append(v.SchemaCols, name.ColName)
+		}
+	} else {
+		if len(v.Cols) != len(names) {
+			return ErrViewWrongList
+		}
+		v.SchemaCols = v.Cols
+	}
+	dupNames := make(map[string]struct{}, len(v.SchemaCols))
+	for _, col := range v.SchemaCols {
+		if _, ok := dupNames[col.L]; ok {
+			return ErrDupFieldName.GenWithStackByArgs(col.O)
+		}
+		dupNames[col.L] = struct{}{}
+	}
+	if v.Definer.CurrentUser {
+		// The internal sessions have no user, they are treated as root since
+		// there is no privilege system.
+		if user := b.ctx.GetSessionVars().User; user != nil {
+			v.Definer = &auth.UserIdentity{Username: user.Username, Hostname: user.Hostname}
+		} else {
+			v.Definer = &auth.UserIdentity{Username: "root", Hostname: "%"}
+		}
+	}
+	return nil
+}
+
 func (b *PlanBuilder) buildExplainPlan(targetPlan Plan, format string, execStmt ast.StmtNode) (Plan, error) {
 	p := &Explain{
 		TargetPlan: targetPlan,
@@ -956,10 +1090,14 @@ func buildShowSchema(s *ast.ShowStmt) (schema *expression.Schema, outputNames []
 		if s.Full {
 			names = append(names, "Table_type")
 		}
-	case ast.ShowVariables:
+	case ast.ShowVariables, ast.ShowStatus:
 		names = []string{"Variable_name", "Value"}
-	case ast.ShowCreateTable:
-		names = []string{"Table", "Create Table"}
+	case ast.ShowCreateTable, ast.ShowCreateView:
+		if s.Table.TableInfo.IsView() {
+			names = []string{"View", "Create View", "character_set_client", "collation_connection"}
+		} else {
+			names = []string{"Table", "Create Table"}
+		}
 	case ast.ShowCreateDatabase:
 		names = []string{"Database", "Create Database"}
 	}
@@ -39,6 +39,7 @@ func (s *testPlanBuilderSuite) TestShow(c *C) {
 		ast.ShowTables,
 		ast.ShowWarnings,
 		ast.ShowVariables,
+		ast.ShowStatus,
 		ast.ShowCreateTable,
 		ast.ShowCreateDatabase,
 	}
@@ -0,0 +1,820 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/opcode"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/planner/property"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
+	driver "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+// PointGetPlan is a fast plan for simple point get.
+// When we detect that the statement has a unique equal access condition, this plan is used.
+// This plan is much faster to build and to execute because it avoid the optimization and coprocessor cost.
+type PointGetPlan struct {
+	basePlan
+	dbName           string
+	schema           *expression.Schema
+	TblInfo          *model.TableInfo
+	IndexInfo        *model.IndexInfo
+	Handle           int64
+	HandleParam      *driver.ParamMarkerExpr
+	UnsignedHandle   bool
+	IndexValues      []types.Datum
+	IndexValueParams []*driver.ParamMarkerExpr
+	IsTableDual      bool
+	outputNames      []*types.FieldName
+}
+
+type nameValuePair struct {
+	colName string
+	value   types.Datum
+	param   *driver.ParamMarkerExpr
+}
+
+// Schema implements the Plan interface.
+func (p *PointGetPlan) Schema() *expression.Schema {
+	return p.schema
+}
+
+// attach2Task makes the current physical plan as the father of task's physicalPlan and updates the cost of
+// current task. If the child's task is cop task, some operator may close this task and return a new rootTask.
+func (p *PointGetPlan) attach2Task(...task) task {
+	return nil
+}
+
+// ToPB converts physical plan to tipb executor.
+func (p *PointGetPlan) ToPB(ctx sessionctx.Context) (*tipb.Executor, error) {
+	return nil, nil
+}
+
+// ExplainInfo returns operator information to be explained.
+func (p *PointGetPlan) ExplainInfo() string {
+	return p.explainInfo(false)
+}
+
+// ExplainNormalizedInfo returns normalized operator information to be explained.
+func (p *PointGetPlan) ExplainNormalizedInfo() string {
+	return p.explainInfo(true)
+}
+
+func (p *PointGetPlan) explainInfo(normalized bool) string {
+	buffer := bytes.NewBufferString("")
+	fmt.Fprintf(buffer, "table:%s", p.TblInfo.Name.O)
+	if p.IndexInfo != nil {
+		buffer.WriteString(", index:")
+		for i, col := range p.IndexInfo.Columns {
+			buffer.WriteString(col.Name.O)
+			if i+1 < len(p.IndexInfo.Columns) {
+				buffer.WriteString(", ")
+			}
+		}
+	} else if normalized {
+		buffer.WriteString(", handle:?")
+	} else if p.UnsignedHandle {
+		fmt.Fprintf(buffer, ", handle:%d", uint64(p.Handle))
+	} else {
+		fmt.Fprintf(buffer, ", handle:%d", p.Handle)
+	}
+	return buffer.String()
+}
+
+// GetChildReqProps gets the required property by child index.
+func (p *PointGetPlan) GetChildReqProps(idx int) *property.PhysicalProperty {
+	return nil
+}
+
+// StatsCount will return the RowCount of property.StatsInfo for this plan.
+func (p *PointGetPlan) StatsCount() float64 {
+	return 1
+}
+
+// statsInfo will return the RowCount of property.StatsInfo for this plan.
+func (p *PointGetPlan) statsInfo() *property.StatsInfo {
+	if p.stats == nil {
+		p.stats = &property.StatsInfo{}
+	}
+	p.stats.RowCount = 1
+	return p.stats
+}
+
+// Stats returns the StatsInfo of the plan.
+func (p *PointGetPlan) Stats() *property.StatsInfo {
+	return p.statsInfo()
+}
+
+// Children gets all the children.
+func (p *PointGetPlan) Children() []PhysicalPlan {
+	return nil
+}
+
+// SetChildren sets the children for the plan.
+func (p *PointGetPlan) SetChildren(...PhysicalPlan) {}
+
+// SetChild sets a specific plan for a specific child.
+func (p *PointGetPlan) SetChild(i int, child PhysicalPlan) {}
+
+// ResolveIndices resolves the indices for columns. After doing this, the columns can evaluate the rows by their indices.
+func (p *PointGetPlan) ResolveIndices() error {
+	return nil
+}
+
+// OutputNames returns the outputting names of each column.
+func (p *PointGetPlan) OutputNames() types.NameSlice {
+	return p.outputNames
+}
+
+// SetOutputNames sets the outputting name by the given slice.
+func (p *PointGetPlan) SetOutputNames(names types.NameSlice) {
+	p.outputNames = names
+}
+
+// BatchPointGetPlan represents a physical plan which contains a bunch of
+// keys reference the same table and use the same `unique key`
+type BatchPointGetPlan struct {
+	basePlan
+	dbName           string
+	schema           *expression.Schema
+	TblInfo          *model.TableInfo
+	IndexInfo        *model.IndexInfo
+	Handles          []int64
+	HandleParams     []*driver.ParamMarkerExpr
+	IndexValues      [][]types.Datum
+	IndexValueParams [][]*driver.ParamMarkerExpr
+	outputNames      []*types.FieldName
+
+	// InvalidHandles marks the handles rebuilt from the parameters which no row can have,
+	// it's nil if all the handles are valid.
+	InvalidHandles []bool
+}
+
+// Schema implements the Plan interface.
+func (p *BatchPointGetPlan) Schema() *expression.Schema {
+	return p.schema
+}
+
+// attach2Task makes the current physical plan as the father of task's physicalPlan and updates the cost of
+// current task. If the child's task is cop task, some operator may close this task and return a new rootTask.
+func (p *BatchPointGetPlan) attach2Task(...task) task {
+	return nil
+}
+
+// ToPB converts physical plan to tipb executor.
+func (p *BatchPointGetPlan) ToPB(ctx sessionctx.Context) (*tipb.Executor, error) {
+	return nil, nil
+}
+
+// ExplainInfo returns operator information to be explained.
+func (p *BatchPointGetPlan) ExplainInfo() string {
+	return p.explainInfo(false)
+}
+
+// ExplainNormalizedInfo returns normalized operator information to be explained.
+func (p *BatchPointGetPlan) ExplainNormalizedInfo() string {
+	return p.explainInfo(true)
+}
+
+func (p *BatchPointGetPlan) explainInfo(normalized bool) string {
+	buffer := bytes.NewBufferString("")
+	fmt.Fprintf(buffer, "table:%s", p.TblInfo.Name.O)
+	if p.IndexInfo != nil {
+		buffer.WriteString(", index:")
+		for i, col := range p.IndexInfo.Columns {
+			buffer.WriteString(col.Name.O)
+			if i+1 < len(p.IndexInfo.Columns) {
+				buffer.WriteString(", ")
+			}
+		}
+	} else if normalized {
+		buffer.WriteString(", handle:?")
+	} else {
+		fmt.Fprintf(buffer, ", handle:%v", p.Handles)
+	}
+	return buffer.String()
+}
+
+// GetChildReqProps gets the required property by child index.
+func (p *BatchPointGetPlan) GetChildReqProps(idx int) *property.PhysicalProperty {
+	return nil
+}
+
+// StatsCount will return the RowCount of property.StatsInfo for this plan.
+func (p *BatchPointGetPlan) StatsCount() float64 {
+	return p.stats.RowCount
+}
+
+// Children gets all the children.
+func (p *BatchPointGetPlan) Children() []PhysicalPlan {
+	return nil
+}
+
+// SetChildren sets the children for the plan.
+func (p *BatchPointGetPlan) SetChildren(...PhysicalPlan) {}
+
+// SetChild sets a specific plan for a specific child.
+func (p *BatchPointGetPlan) SetChild(i int, child PhysicalPlan) {}
+
+// ResolveIndices resolves the indices for columns. After doing this, the columns can evaluate the rows by their indices.
+func (p *BatchPointGetPlan) ResolveIndices() error {
+	return nil
+}
+
+// OutputNames returns the outputting names of each column.
+func (p *BatchPointGetPlan) OutputNames() types.NameSlice {
+	return p.outputNames
+}
+
+// SetOutputNames sets the outputting name by the given slice.
+func (p *BatchPointGetPlan) SetOutputNames(names types.NameSlice) {
+	p.outputNames = names
+}
+
+// TryFastPlan tries to use the PointGetPlan for the query.
+func TryFastPlan(ctx sessionctx.Context, node ast.Node) Plan {
+	ctx.GetSessionVars().PlanID = 0
+	ctx.GetSessionVars().PlanColumnID = 0
+	selStmt, ok := node.(*ast.SelectStmt)
+	if !ok {
+		return nil
+	}
+	// The fast plans don't lock the rows they read, leave `SELECT ... FOR UPDATE`
+	// to the normal plan which has a Lock operator on top of the readers.
+	if selStmt.LockTp != ast.SelectLockNone {
+		return nil
+	}
+	// Try to convert `SELECT a, b, c FROM t WHERE (a, b, c) in ((1, 2, 4), (1, 3, 5))`
+	// to BatchPointGet if there is a unique key (a, b, c) on table `t`.
+	if fp := tryWhereIn2BatchPointGet(ctx, selStmt); fp != nil {
+		return fp
+	}
+	fp := tryPointGetPlan(ctx, selStmt)
+	if fp == nil {
+		return nil
+	}
+	if fp.IsTableDual {
+		tableDual := PhysicalTableDual{}
+		tableDual.names = fp.outputNames
+		tableDual.SetSchema(fp.Schema())
+		return tableDual.Init(ctx, &property.StatsInfo{})
+	}
+	return fp
+}
+
+// isSimpleSelect checks whether the select statement only reads rows from one table,
+// without any clause that makes the result depend on more than the matched rows.
+func isSimpleSelect(selStmt *ast.SelectStmt) bool {
+	return selStmt.GroupBy == nil && selStmt.Having == nil && selStmt.With == nil &&
+		len(selStmt.WindowSpecs) == 0 && selStmt.AfterSetOperator == nil
+}
+
+func tryWhereIn2BatchPointGet(ctx sessionctx.Context, selStmt *ast.SelectStmt) *BatchPointGetPlan {
+	if !isSimpleSelect(selStmt) || selStmt.OrderBy != nil || selStmt.Limit != nil || selStmt.Distinct {
+		return nil
+	}
+	in, ok := selStmt.Where.(*ast.PatternInExpr)
+	if !ok || in.Not || len(in.List) < 1 {
+		return nil
+	}
+	tblName, tblAlias := getSingleTableNameAndAlias(selStmt.From)
+	if tblName == nil {
+		return nil
+	}
+	tbl := tblName.TableInfo
+	if !isPointGetTable(tbl) {
+		return nil
+	}
+	dbName := getPointGetDBName(ctx, tblName)
+	schema, names := buildSchemaFromFields(ctx, dbName, tbl, tblAlias, selStmt.Fields.Fields)
+	if schema == nil {
+		return nil
+	}
+
+	var (
+		handleCol     *model.ColumnInfo
+		whereColNames []string
+	)
+	colExpr := in.Expr
+	if p, ok := colExpr.(*ast.ParenthesesExpr); ok {
+		colExpr = p.Expr
+	}
+	switch colName := colExpr.(type) {
+	case *ast.ColumnNameExpr:
+		if name := colName.Name.Table.L; name != "" && name != tblAlias.L {
+			return nil
+		}
+		if tbl.PKIsHandle {
+			if pkCol := tbl.GetPkColInfo(); pkCol != nil && pkCol.Name.L == colName.Name.Name.L {
+				handleCol = pkCol
+			}
+		}
+		whereColNames = append(whereColNames, colName.Name.Name.L)
+	case *ast.RowExpr:
+		for _, col := range colName.Values {
+			c, ok := col.(*ast.ColumnNameExpr)
+			if !ok {
+				return nil
+			}
+			if name := c.Name.Table.L; name != "" && name != tblAlias.L {
+				return nil
+			}
+			whereColNames = append(whereColNames, c.Name.Name.L)
+		}
+	default:
+		return nil
+	}
+
+	p := newBatchPointGetPlan(ctx, in, handleCol, tbl, schema, names, whereColNames)
+	if p == nil {
+		return nil
+	}
+	p.dbName = dbName
+	return p
+}
+
+func newBatchPointGetPlan(ctx sessionctx.Context, patternInExpr *ast.PatternInExpr, handleCol *model.ColumnInfo,
+	tbl *model.TableInfo, schema *expression.Schema, names []*types.FieldName, whereColNames []string) *BatchPointGetPlan {
+	sc := ctx.GetSessionVars().StmtCtx
+	statsInfo := &property.StatsInfo{RowCount: float64(len(patternInExpr.List))}
+	if handleCol != nil {
+		handles := make([]int64, len(patternInExpr.List))
+		handleParams := make([]*driver.ParamMarkerExpr, len(patternInExpr.List))
+		for i, item := range patternInExpr.List {
+			if p, ok := item.(*ast.ParenthesesExpr); ok {
+				item = p.Expr
+			}
+			d, param, ok := getValueOrParam(item)
+			if !ok || d.IsNull() {
+				return nil
+			}
+			handle, ok, err := convertPointGetHandle(sc, d, &handleCol.FieldType)
+			if err != nil || !ok {
+				return nil
+			}
+			handles[i] = handle
+			handleParams[i] = param
+		}
+		return BatchPointGetPlan{
+			TblInfo:      tbl,
+			Handles:      handles,
+			HandleParams: handleParams,
+		}.Init(ctx, statsInfo, schema, names)
+	}
+
+	// The columns in the where clause must be exactly the columns of a unique index.
+	var matchIdxInfo *model.IndexInfo
+	permutations := make([]int, len(whereColNames))
+	for _, idxInfo := range tbl.Indices {
+		if !idxInfo.Unique || idxInfo.State != model.StatePublic || idxInfo.HasPrefixIndex() ||
+			len(idxInfo.Columns) != len(whereColNames) {
+			continue
+		}
+		matched := true
+		for whereColIndex, whereColName := range whereColNames {
+			found := false
+			for i, col := range idxInfo.Columns {
+				if col.Name.L == whereColName {
+					permutations[whereColIndex] = i
+					found = true
+					break
+				}
+			}
+			if !found {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			matchIdxInfo = idxInfo
+			break
+		}
+	}
+	if matchIdxInfo == nil {
+		return nil
+	}
+
+	indexValues := make([][]types.Datum, len(patternInExpr.List))
+	indexValueParams := make([][]*driver.ParamMarkerExpr, len(patternInExpr.List))
+	for i, item := range patternInExpr.List {
+		if p, ok := item.(*ast.ParenthesesExpr); ok {
+			item = p.Expr
+		}
+		var items []ast.ExprNode
+		if row, ok := item.(*ast.RowExpr); ok {
+			items = row.Values
+		} else {
+			items = []ast.ExprNode{item}
+		}
+		if len(items) != len(whereColNames) {
+			return nil
+		}
+		values := make([]types.Datum, len(items))
+		valueParams := make([]*driver.ParamMarkerExpr, len(items))
+		for j, inner := range items {
+			d, param, ok := getValueOrParam(inner)
+			if !ok {
+				return nil
+			}
+			permIndex := permutations[j]
+			dVal, isTableDual, ok := convertPointGetValue(sc, tbl.Columns[matchIdxInfo.Columns[permIndex].Offset], d)
+			if !ok || isTableDual {
+				return nil
+			}
+			values[permIndex] = dVal
+			valueParams[permIndex] = param
+		}
+		indexValues[i] = values
+		indexValueParams[i] = valueParams
+	}
+	return BatchPointGetPlan{
+		TblInfo:          tbl,
+		IndexInfo:        matchIdxInfo,
+		IndexValues:      indexValues,
+		IndexValueParams: indexValueParams,
+	}.Init(ctx, statsInfo, schema, names)
+}
+
+func tryPointGetPlan(ctx sessionctx.Context, selStmt *ast.SelectStmt) *PointGetPlan {
+	if !isSimpleSelect(selStmt) {
+		return nil
+	}
+	if selStmt.Limit != nil {
+		count, offset, err := extractLimitCountOffset(ctx, selStmt.Limit)
+		if err != nil || count == 0 || offset > 0 {
+			return nil
+		}
+	}
+	tblName, tblAlias := getSingleTableNameAndAlias(selStmt.From)
+	if tblName == nil {
+		return nil
+	}
+	tbl := tblName.TableInfo
+	if !isPointGetTable(tbl) {
+		return nil
+	}
+	dbName := getPointGetDBName(ctx, tblName)
+	schema, names := buildSchemaFromFields(ctx, dbName, tbl, tblAlias, selStmt.Fields.Fields)
+	if schema == nil {
+		return nil
+	}
+
+	sc := ctx.GetSessionVars().StmtCtx
+	pairs := make([]nameValuePair, 0, 4)
+	pairs, isTableDual := getNameValuePairs(sc, tbl, tblAlias, pairs, selStmt.Where)
+	if pairs == nil {
+		return nil
+	}
+	// The result of a cached plan must not depend on the values of the parameters.
+	if isTableDual && sc.UseCache {
+		return nil
+	}
+
+	handlePair, fieldType := findPKHandle(tbl, pairs)
+	if fieldType != nil && len(pairs) == 1 {
+		p := newPointGetPlan(ctx, dbName, schema, tbl, names)
+		if isTableDual {
+			p.IsTableDual = true
+			return p
+		}
+		handle, ok, err := convertPointGetHandle(sc, handlePair.value, fieldType)
+		if err != nil {
+			return nil
+		} else if !ok {
+			if sc.UseCache {
+				return nil
+			}
+			p.IsTableDual = true
+			return p
+		}
+		p.Handle = handle
+		p.UnsignedHandle = mysql.HasUnsignedFlag(fieldType.Flag)
+		p.HandleParam = handlePair.param
+		return p
+	}
+
+	for _, idxInfo := range tbl.Indices {
+		if !idxInfo.Unique || idxInfo.State != model.StatePublic {
+			continue
+		}
+		idxValues, idxValueParams := getIndexValues(idxInfo, pairs)
+		if idxValues == nil {
+			continue
+		}
+		p := newPointGetPlan(ctx, dbName, schema, tbl, names)
+		if isTableDual {
+			p.IsTableDual = true
+			return p
+		}
+		p.IndexInfo = idxInfo
+		p.IndexValues = idxValues
+		p.IndexValueParams = idxValueParams
+		return p
+	}
+	return nil
+}
+
+func newPointGetPlan(ctx sessionctx.Context, dbName string, schema *expression.Schema, tbl *model.TableInfo, names []*types.FieldName) *PointGetPlan {
+	return &PointGetPlan{
+		basePlan:    newBasePlan(ctx, TypePointGet),
+		dbName:      dbName,
+		schema:      schema,
+		TblInfo:     tbl,
+		outputNames: names,
+	}
+}
+
+// isPointGetTable checks whether the rows of the table can be read by the key directly.
+func isPointGetTable(tbl *model.TableInfo) bool {
+	if tbl == nil || tbl.IsView() {
+		return false
+	}
+	for _, col := range tbl.Columns {
+		// Only handle tables that all columns are public.
+		if col.State != model.StatePublic {
+			return false
+		}
+	}
+	return true
+}
+
+func getPointGetDBName(ctx sessionctx.Context, tblName *ast.TableName) string {
+	if tblName.Schema.L != "" {
+		return tblName.Schema.L
+	}
+	return ctx.GetSessionVars().CurrentDB
+}
+
+func buildSchemaFromFields(ctx sessionctx.Context, dbName string, tbl *model.TableInfo, tblName model.CIStr,
+	fields []*ast.SelectField) (*expression.Schema, []*types.FieldName) {
+	db := model.NewCIStr(dbName)
+	columns := make([]*expression.Column, 0, len(tbl.Columns)+1)
+	names := make([]*types.FieldName, 0, len(tbl.Columns)+1)
+	for _, field := range fields {
+		if field.WildCard != nil {
+			if field.WildCard.Table.L != "" && field.WildCard.Table.L != tblName.L {
+				return nil, nil
+			}
+			for _, col := range tbl.Columns {
+				names = append(names, &types.FieldName{
+					DBName:      db,
+					OrigTblName: tbl.Name,
+					TblName:     tblName,
+					OrigColName: col.Name,
+					ColName:     col.Name,
+				})
+				columns = append(columns, colInfoToColumn(ctx, col, len(columns)))
+			}
+			continue
+		}
+		colNameExpr, ok := field.Expr.(*ast.ColumnNameExpr)
+		if !ok {
+			return nil, nil
+		}
+		if colNameExpr.Name.Table.L != "" && colNameExpr.Name.Table.L != tblName.L {
+			return nil, nil
+		}
+		col := model.FindColumnInfo(tbl.Columns, colNameExpr.Name.Name.L)
+		if col == nil {
+			return nil, nil
+		}
+		asName := col.Name
+		if field.AsName.L != "" {
+			asName = field.AsName
+		}
+		names = append(names, &types.FieldName{
+			DBName:      db,
+			OrigTblName: tbl.Name,
+			TblName:     tblName,
+			OrigColName: col.Name,
+			ColName:     asName,
+		})
+		columns = append(columns, colInfoToColumn(ctx, col, len(columns)))
+	}
+	return expression.NewSchema(columns...), names
+}
+
+// getSingleTableNameAndAlias returns the ast node of the queried table name and the alias string.
+// `tblName` is `nil` if there are multiple tables in the query.
+// `tblAlias` will be the real table name if there is no table alias in the query.
+func getSingleTableNameAndAlias(tableRefs *ast.TableRefsClause) (tblName *ast.TableName, tblAlias model.CIStr) {
+	if tableRefs == nil || tableRefs.TableRefs == nil || tableRefs.TableRefs.Right != nil {
+		return nil, tblAlias
+	}
+	tblSrc, ok := tableRefs.TableRefs.Left.(*ast.TableSource)
+	if !ok {
+		return nil, tblAlias
+	}
+	tblName, ok = tblSrc.Source.(*ast.TableName)
+	if !ok {
+		return nil, tblAlias
+	}
+	tblAlias = tblSrc.AsName
+	if tblSrc.AsName.L == "" {
+		tblAlias = tblName.Name
+	}
+	return tblName, tblAlias
+}
+
+// getValueOrParam returns the value of a constant or a parameter marker.
+func getValueOrParam(expr ast.ExprNode) (d types.Datum, param *driver.ParamMarkerExpr, ok bool) {
+	switch x := expr.(type) {
+	case *driver.ValueExpr:
+		return x.Datum, nil, true
+	case *driver.ParamMarkerExpr:
+		return x.Datum, x, true
+	}
+	return d, nil, false
+}
+
+// getNameValuePairs extracts `column = constant/paramMarker` conditions from expr as name value pairs.
+func getNameValuePairs(sc *stmtctx.StatementContext, tbl *model.TableInfo, tblName model.CIStr,
+	nvPairs []nameValuePair, expr ast.ExprNode) (pairs []nameValuePair, isTableDual bool) {
+	binOp, ok := expr.(*ast.BinaryOperationExpr)
+	if !ok {
+		return nil, false
+	}
+	if binOp.Op == opcode.LogicAnd {
+		nvPairs, isTableDual = getNameValuePairs(sc, tbl, tblName, nvPairs, binOp.L)
+		if nvPairs == nil {
+			return nil, false
+		}
+		var rightIsTableDual bool
+		nvPairs, rightIsTableDual = getNameValuePairs(sc, tbl, tblName, nvPairs, binOp.R)
+		if nvPairs == nil {
+			return nil, false
+		}
+		return nvPairs, isTableDual || rightIsTableDual
+	}
+	if binOp.Op != opcode.EQ {
+		return nil, false
+	}
+	var (
+		colName *ast.ColumnNameExpr
+		d       types.Datum
+		param   *driver.ParamMarkerExpr
+	)
+	if colName, ok = binOp.L.(*ast.ColumnNameExpr); ok {
+		d, param, ok = getValueOrParam(binOp.R)
+	} else if colName, ok = binOp.R.(*ast.ColumnNameExpr); ok {
+		d, param, ok = getValueOrParam(binOp.L)
+	}
+	if !ok || d.IsNull() {
+		return nil, false
+	}
+	if colName.Name.Table.L != "" && colName.Name.Table.L != tblName.L {
+		return nil, false
+	}
+	pair := nameValuePair{colName: colName.Name.Name.L, value: d, param: param}
+	col := model.FindColumnInfo(tbl.Columns, colName.Name.Name.L)
+	if col == nil {
+		// The column is _tidb_rowid, which is compared by findPKHandle.
+		if colName.Name.Name.L != model.ExtraHandleName.L {
+			return nil, false
+		}
+		return append(nvPairs, pair), false
+	}
+	pair.value, isTableDual, ok = convertPointGetValue(sc, col, d)
+	if !ok {
+		return nil, false
+	}
+	return append(nvPairs, pair), isTableDual
+}
+
+// convertPointGetValue converts the value to the type of the column, so that it can be
+// encoded into the key. isTableDual is true if no row of the column can be equal to the value.
+func convertPointGetValue(sc *stmtctx.StatementContext, col *model.ColumnInfo, d types.Datum) (dVal types.Datum, isTableDual bool, ok bool) {
+	if !checkCanConvertInPointGet(col, d) {
+		return dVal, false, false
+	}
+	dVal, err := d.ConvertTo(sc, &col.FieldType)
+	if err != nil {
+		if terror.ErrorEqual(types.ErrOverflow, err) {
+			return d, true, true
+		}
+		return dVal, false, false
+	}
+	// The converted result must be the same as the original datum.
+	cmp, err := d.CompareDatum(sc, &dVal)
+	if err != nil {
+		return dVal, false, false
+	}
+	return dVal, cmp != 0, true
+}
+
+// convertPointGetHandle converts the value to a handle of the type. ok is false if no row
+// can have the value as its handle, i.e. the value overflows the type or it's changed by
+// the conversion, e.g. 1.5 is converted to 2.
+func convertPointGetHandle(sc *stmtctx.StatementContext, d types.Datum, fieldType *types.FieldType) (handle int64, ok bool, err error) {
+	intDatum, err := d.ConvertTo(sc, fieldType)
+	if err != nil {
+		if terror.ErrorEqual(types.ErrOverflow, err) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	cmp, err := intDatum.CompareDatum(sc, &d)
+	if err != nil {
+		return 0, false, err
+	}
+	return intDatum.GetInt64(), cmp == 0, nil
+}
+
+// getHandleFieldType returns the type of the handle of the table.
+func getHandleFieldType(tblInfo *model.TableInfo) *types.FieldType {
+	if tblInfo.PKIsHandle {
+		if pkCol := tblInfo.GetPkColInfo(); pkCol != nil {
+			return &pkCol.FieldType
+		}
+	}
+	return types.NewFieldType(mysql.TypeLonglong)
+}
+
+// checkCanConvertInPointGet checks whether the value is compared with the column in the
+// column's type. A string column compared with a number is compared as numbers, e.g.
+// '1.0' = 1, so it can't be used to build the key.
+func checkCanConvertInPointGet(col *model.ColumnInfo, d types.Datum) bool {
+	if col.FieldType.EvalType() == types.ETString {
+		switch d.Kind() {
+		case types.KindInt64, types.KindUint64, types.KindFloat32, types.KindFloat64, types.KindMysqlDecimal:
+			return false
+		}
+	}
+	return true
+}
+
+func findPKHandle(tblInfo *model.TableInfo, pairs []nameValuePair) (handlePair nameValuePair, fieldType *types.FieldType) {
+	if !tblInfo.PKIsHandle {
+		rowIDIdx := findInPairs(model.ExtraHandleName.L, pairs)
+		if rowIDIdx != -1 {
+			return pairs[rowIDIdx], types.NewFieldType(mysql.TypeLonglong)
+		}
+		return handlePair, nil
+	}
+	pkCol := tblInfo.GetPkColInfo()
+	if pkCol == nil {
+		return handlePair, nil
+	}
+	i := findInPairs(pkCol.Name.L, pairs)
+	if i == -1 {
+		return handlePair, nil
+	}
+	return pairs[i], &pkCol.FieldType
+}
+
+func getIndexValues(idxInfo *model.IndexInfo, pairs []nameValuePair) ([]types.Datum, []*driver.ParamMarkerExpr) {
+	if len(idxInfo.Columns) != len(pairs) || idxInfo.HasPrefixIndex() {
+		return nil, nil
+	}
+	idxValues := make([]types.Datum, 0, len(pairs))
+	idxValueParams := make([]*driver.ParamMarkerExpr, 0, len(pairs))
+	for _, idxCol := range idxInfo.Columns {
+		i := findInPairs(idxCol.Name.L, pairs)
+		if i == -1 {
+			return nil, nil
+		}
+		idxValues = append(idxValues, pairs[i].value)
+		idxValueParams = append(idxValueParams, pairs[i].param)
+	}
+	return idxValues, idxValueParams
+}
+
+func findInPairs(colName string, pairs []nameValuePair) int {
+	for i, pair := range pairs {
+		if pair.colName == colName {
+			return i
+		}
+	}
+	return -1
+}
+
+func colInfoToColumn(ctx sessionctx.Context, col *model.ColumnInfo, idx int) *expression.Column {
+	return &expression.Column{
+		RetType:  &col.FieldType,
+		ID:       col.ID,
+		UniqueID: ctx.GetSessionVars().AllocPlanColumnID(),
+		Index:    idx,
+		OrigName: col.Name.L,
+	}
+}
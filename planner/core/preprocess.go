@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/tidb/ddl"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/meta/autoid"
+	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
@@ -33,6 +34,11 @@ import (
 // PreprocessOpt presents optional parameters to `Preprocess` method.
 type PreprocessOpt func(*preprocessor)
 
+// InPrepare is a PreprocessOpt that indicates preprocess is executing under prepare statement.
+func InPrepare(p *preprocessor) {
+	p.flag |= inPrepare
+}
+
 // Preprocess resolves table names of the node, and checks some statements validation.
 func Preprocess(ctx sessionctx.Context, node ast.Node, is infoschema.InfoSchema, preprocessOpt ...PreprocessOpt) error {
 	v := preprocessor{is: is, ctx: ctx, tableAliasInJoin: make([]map[string]interface{}, 0)}
@@ -50,6 +56,8 @@ const (
 	inCreateOrDropTable preprocessorFlag = 1 << iota
 	// parentIsJoin is set when visiting node's parent is join.
 	parentIsJoin
+	// inPrepare is set when visiting the statement of a prepare statement.
+	inPrepare
 )
 
 // preprocessor is an ast.Visitor that preprocess
@@ -63,6 +71,18 @@ type preprocessor struct {
 	// tableAliasInJoin is a stack that keeps the table alias names for joins.
 	// len(tableAliasInJoin) may bigger than 1 because the left/right child of join may be subquery that contains `JOIN`
 	tableAliasInJoin []map[string]interface{}
+
+	// withScopes is a stack that keeps the names of the common table expressions
+	// visible in the current scope, such names are not resolved as tables.
+	withScopes []*withScope
+}
+
+// withScope keeps the names of the common table expressions defined by a WITH clause.
+// A common table expression is only visible to the ones defined after it, unless
+// the WITH clause is recursive.
+type withScope struct {
+	isRecursive bool
+	names       map[string]struct{}
 }
 
 func (p *preprocessor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
@@ -70,6 +90,9 @@ func (p *preprocessor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
 	case *ast.CreateTableStmt:
 		p.flag |= inCreateOrDropTable
 		p.checkCreateTableGrammar(node)
+	case *ast.CreateViewStmt:
+		p.flag |= inCreateOrDropTable
+		p.checkCreateViewGrammar(node)
 	case *ast.DropTableStmt:
 		p.flag |= inCreateOrDropTable
 		p.checkDropTableGrammar(node)
@@ -86,6 +109,17 @@ func (p *preprocessor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
 		p.resolveShowStmt(node)
 	case *ast.Join:
 		p.checkNonUniqTableAlias(node)
+	case *ast.SelectStmt:
+		p.flag &= ^parentIsJoin
+		p.pushWithNames(node.With)
+	case *ast.SetOprStmt:
+		p.flag &= ^parentIsJoin
+		p.pushWithNames(node.With)
+	case *ast.CommonTableExpression:
+		p.flag &= ^parentIsJoin
+		if scope := p.withScopes[len(p.withScopes)-1]; scope.isRecursive {
+			scope.names[node.Name.L] = struct{}{}
+		}
 	default:
 		p.flag &= ^parentIsJoin
 	}
@@ -98,7 +132,7 @@ func (p *preprocessor) Leave(in ast.Node) (out ast.Node, ok bool) {
 		p.flag &= ^inCreateOrDropTable
 		p.checkAutoIncrement(x)
 		p.checkContainDotColumn(x)
-	case *ast.DropTableStmt, *ast.AlterTableStmt:
+	case *ast.CreateViewStmt, *ast.DropTableStmt, *ast.AlterTableStmt:
 		p.flag &= ^inCreateOrDropTable
 	case *ast.ExplainStmt:
 		if _, ok := x.Stmt.(*ast.ShowStmt); ok {
@@ -116,10 +150,20 @@ func (p *preprocessor) Leave(in ast.Node) (out ast.Node, ok bool) {
 		}
 	case *ast.TableName:
 		p.handleTableName(x)
+	case *ast.SelectStmt:
+		p.popWithNames(x.With)
+	case *ast.SetOprStmt:
+		p.popWithNames(x.With)
+	case *ast.CommonTableExpression:
+		p.withScopes[len(p.withScopes)-1].names[x.Name.L] = struct{}{}
 	case *ast.Join:
 		if len(p.tableAliasInJoin) > 0 {
 			p.tableAliasInJoin = p.tableAliasInJoin[:len(p.tableAliasInJoin)-1]
 		}
+	case *driver.ParamMarkerExpr:
+		if p.flag&inPrepare == 0 {
+			p.err = parser.ErrSyntax.GenWithStack("syntax error, unexpected '?'")
+		}
 	case *ast.FuncCallExpr:
 	}
 
@@ -261,6 +305,20 @@ func (p *preprocessor) checkCreateTableGrammar(stmt *ast.CreateTableStmt) {
 	}
 }
 
+func (p *preprocessor) checkCreateViewGrammar(stmt *ast.CreateViewStmt) {
+	vName := stmt.ViewName.Name.String()
+	if isIncorrectName(vName) {
+		p.err = ddl.ErrWrongTableName.GenWithStackByArgs(vName)
+		return
+	}
+	for _, col := range stmt.Cols {
+		if isIncorrectName(col.String()) {
+			p.err = ddl.ErrWrongColumnName.GenWithStackByArgs(col)
+			return
+		}
+	}
+}
+
 func (p *preprocessor) checkDropTableGrammar(stmt *ast.DropTableStmt) {
 	for _, t := range stmt.Tables {
 		if isIncorrectName(t.Name.String()) {
@@ -501,7 +559,40 @@ func (p *preprocessor) checkContainDotColumn(stmt *ast.CreateTableStmt) {
 	}
 }
 
+func (p *preprocessor) pushWithNames(with *ast.WithClause) {
+	if with == nil {
+		return
+	}
+	p.withScopes = append(p.withScopes, &withScope{
+		isRecursive: with.IsRecursive,
+		names:       make(map[string]struct{}, len(with.CTEs)),
+	})
+}
+
+func (p *preprocessor) popWithNames(with *ast.WithClause) {
+	if with == nil {
+		return
+	}
+	p.withScopes = p.withScopes[:len(p.withScopes)-1]
+}
+
+// isCTEName checks whether the unqualified table name refers to a common table expression.
+func (p *preprocessor) isCTEName(tn *ast.TableName) bool {
+	if tn.Schema.L != "" {
+		return false
+	}
+	for i := len(p.withScopes) - 1; i >= 0; i-- {
+		if _, ok := p.withScopes[i].names[tn.Name.L]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *preprocessor) handleTableName(tn *ast.TableName) {
+	if p.isCTEName(tn) {
+		return
+	}
 	if tn.Schema.L == "" {
 		currentDB := p.ctx.GetSessionVars().CurrentDB
 		if currentDB == "" {
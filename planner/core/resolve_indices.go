@@ -113,6 +113,42 @@ func (p *PhysicalHashJoin) ResolveIndices() (err error) {
 	return
 }
 
+// ResolveIndices implements Plan interface.
+func (p *PhysicalApply) ResolveIndices() (err error) {
+	err = p.PhysicalHashJoin.ResolveIndices()
+	if err != nil {
+		return err
+	}
+	// p.OuterSchema may have duplicated CorrelatedColumns,
+	// we deduplicate it here.
+	dedupCols := make(map[int64]*expression.CorrelatedColumn, len(p.OuterSchema))
+	for _, col := range p.OuterSchema {
+		dedupCols[col.UniqueID] = col
+	}
+	p.OuterSchema = make([]*expression.CorrelatedColumn, 0, len(dedupCols))
+	for _, col := range dedupCols {
+		newCol, err := col.Column.ResolveIndices(p.children[0].Schema())
+		if err != nil {
+			return err
+		}
+		col.Column = *newCol.(*expression.Column)
+		p.OuterSchema = append(p.OuterSchema, col)
+	}
+	// Resolve index for equal conditions again, because apply is different from
+	// hash join on the fact that equal conditions are evaluated against the join result,
+	// so columns from equal conditions come from merged schema of children, instead of
+	// single child's schema.
+	joinedSchema := expression.MergeSchema(p.children[0].Schema(), p.children[1].Schema())
+	for i, cond := range p.PhysicalHashJoin.EqualConditions {
+		newSf, err := cond.ResolveIndices(joinedSchema)
+		if err != nil {
+			return err
+		}
+		p.PhysicalHashJoin.EqualConditions[i] = newSf.(*expression.ScalarFunction)
+	}
+	return
+}
+
 // ResolveIndices implements Plan interface.
 func (p *PhysicalMergeJoin) ResolveIndices() (err error) {
 	err = p.physicalSchemaProducer.ResolveIndices()
@@ -156,6 +192,48 @@ func (p *PhysicalMergeJoin) ResolveIndices() (err error) {
 	return
 }
 
+// ResolveIndices implements Plan interface.
+func (p *PhysicalIndexJoin) ResolveIndices() (err error) {
+	err = p.physicalSchemaProducer.ResolveIndices()
+	if err != nil {
+		return err
+	}
+	lSchema := p.children[0].Schema()
+	rSchema := p.children[1].Schema()
+	for i := range p.InnerJoinKeys {
+		newOuterKey, err := p.OuterJoinKeys[i].ResolveIndices(p.children[1-p.InnerChildIdx].Schema())
+		if err != nil {
+			return err
+		}
+		p.OuterJoinKeys[i] = newOuterKey.(*expression.Column)
+		newInnerKey, err := p.InnerJoinKeys[i].ResolveIndices(p.children[p.InnerChildIdx].Schema())
+		if err != nil {
+			return err
+		}
+		p.InnerJoinKeys[i] = newInnerKey.(*expression.Column)
+	}
+	for i, expr := range p.LeftConditions {
+		p.LeftConditions[i], err = expr.ResolveIndices(lSchema)
+		if err != nil {
+			return err
+		}
+	}
+	for i, expr := range p.RightConditions {
+		p.RightConditions[i], err = expr.ResolveIndices(rSchema)
+		if err != nil {
+			return err
+		}
+	}
+	mergedSchema := expression.MergeSchema(lSchema, rSchema)
+	for i, expr := range p.OtherConditions {
+		p.OtherConditions[i], err = expr.ResolveIndices(mergedSchema)
+		if err != nil {
+			return err
+		}
+	}
+	return
+}
+
 // ResolveIndices implements Plan interface.
 func (p *PhysicalUnionScan) ResolveIndices() (err error) {
 	err = p.basePhysicalPlan.ResolveIndices()
@@ -249,6 +327,12 @@ func (p *basePhysicalAgg) ResolveIndices() (err error) {
 				return err
 			}
 		}
+		for _, byItem := range aggFun.OrderByItems {
+			byItem.Expr, err = byItem.Expr.ResolveIndices(p.children[0].Schema())
+			if err != nil {
+				return err
+			}
+		}
 	}
 	for i, item := range p.GroupByItems {
 		p.GroupByItems[i], err = item.ResolveIndices(p.children[0].Schema())
@@ -289,6 +373,37 @@ func (p *PhysicalTopN) ResolveIndices() (err error) {
 	return
 }
 
+// ResolveIndices implements Plan interface.
+func (p *PhysicalLock) ResolveIndices() (err error) {
+	err = p.basePhysicalPlan.ResolveIndices()
+	if err != nil {
+		return err
+	}
+	p.TblID2Handle, err = resolveIndicesForTblID2Handle(p.TblID2Handle, p.children[0].Schema())
+	return err
+}
+
+// ResolveIndices implements Plan interface.
+func (p *Update) ResolveIndices() (err error) {
+	err = p.baseSchemaProducer.ResolveIndices()
+	if err != nil {
+		return err
+	}
+	schema := p.SelectPlan.Schema()
+	for _, assign := range p.OrderedList {
+		newCol, err := assign.Col.ResolveIndices(schema)
+		if err != nil {
+			return err
+		}
+		assign.Col = newCol.(*expression.Column)
+		assign.Expr, err = assign.Expr.ResolveIndices(schema)
+		if err != nil {
+			return err
+		}
+	}
+	return
+}
+
 // ResolveIndices implements Plan interface.
 func (p *Insert) ResolveIndices() (err error) {
 	err = p.baseSchemaProducer.ResolveIndices()
@@ -309,6 +424,59 @@ func (p *Insert) ResolveIndices() (err error) {
 	return
 }
 
+// ResolveIndices implements Plan interface.
+func (p *PhysicalWindow) ResolveIndices() (err error) {
+	err = p.physicalSchemaProducer.ResolveIndices()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(p.Schema().Columns)-len(p.WindowFuncDescs); i++ {
+		col := p.Schema().Columns[i]
+		newCol, err := col.ResolveIndices(p.children[0].Schema())
+		if err != nil {
+			return err
+		}
+		p.Schema().Columns[i] = newCol.(*expression.Column)
+	}
+	for i, item := range p.PartitionBy {
+		newCol, err := item.Col.ResolveIndices(p.children[0].Schema())
+		if err != nil {
+			return err
+		}
+		p.PartitionBy[i].Col = newCol.(*expression.Column)
+	}
+	for i, item := range p.OrderBy {
+		newCol, err := item.Col.ResolveIndices(p.children[0].Schema())
+		if err != nil {
+			return err
+		}
+		p.OrderBy[i].Col = newCol.(*expression.Column)
+	}
+	for _, desc := range p.WindowFuncDescs {
+		for i, arg := range desc.Args {
+			desc.Args[i], err = arg.ResolveIndices(p.children[0].Schema())
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if p.Frame != nil {
+		for i := range p.Frame.Start.CalcFuncs {
+			p.Frame.Start.CalcFuncs[i], err = p.Frame.Start.CalcFuncs[i].ResolveIndices(p.children[0].Schema())
+			if err != nil {
+				return err
+			}
+		}
+		for i := range p.Frame.End.CalcFuncs {
+			p.Frame.End.CalcFuncs[i], err = p.Frame.End.CalcFuncs[i].ResolveIndices(p.children[0].Schema())
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (p *physicalSchemaProducer) ResolveIndices() (err error) {
 	err = p.basePhysicalPlan.ResolveIndices()
 	return err
@@ -44,6 +44,12 @@ func (a *aggregationEliminateChecker) tryToEliminateAggregation(agg *LogicalAggr
 		}
 	}
 	if coveredByUniqueKey {
+		for _, fun := range agg.AggFuncs {
+			// The functions with a result not decided by a single row can't be rewritten.
+			if fun.Name == ast.AggFuncGroupConcat || aggregation.IsVarianceFunc(fun.Name) {
+				return nil
+			}
+		}
 		// GroupByCols has unique key, so this aggregation can be removed.
 		proj := a.convertAggToProj(agg)
 		proj.SetChildren(agg.children[0])
@@ -74,11 +80,20 @@ func (a *aggregationEliminateChecker) rewriteExpr(ctx sessionctx.Context, aggFun
 		return a.rewriteCount(ctx, aggFunc.Args, aggFunc.RetTp)
 	case ast.AggFuncSum, ast.AggFuncAvg, ast.AggFuncFirstRow, ast.AggFuncMax, ast.AggFuncMin:
 		return aggFunc.Args[0]
+	case ast.AggFuncBitAnd, ast.AggFuncBitOr, ast.AggFuncBitXor:
+		return a.rewriteBitFunc(ctx, aggFunc)
 	default:
 		panic("Unsupported function")
 	}
 }
 
+// rewriteBitFunc rewrites bit_and(expr), bit_or(expr) and bit_xor(expr) to
+// ifnull(expr, default value of the function).
+func (a *aggregationEliminateChecker) rewriteBitFunc(ctx sessionctx.Context, aggFunc *aggregation.AggFuncDesc) expression.Expression {
+	defaultValue := &expression.Constant{Value: aggFunc.GetDefaultValue(), RetType: aggFunc.RetTp}
+	return expression.NewFunctionInternal(ctx, ast.Ifnull, aggFunc.RetTp, aggFunc.Args[0], defaultValue)
+}
+
 func (a *aggregationEliminateChecker) rewriteCount(ctx sessionctx.Context, exprs []expression.Expression, targetTp *types.FieldType) expression.Expression {
 	// If is count(expr), we will change it to if(isnull(expr), 0, 1).
 	// If is count(distinct x, y, z) we will change it to if(isnull(x) or isnull(y) or isnull(z), 0, 1).
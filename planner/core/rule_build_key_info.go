@@ -39,6 +39,31 @@ func buildKeyInfo(lp LogicalPlan) {
 	lp.BuildKeyInfo(lp.Schema(), childSchema)
 }
 
+// HasMaxOneRow returns if the LogicalPlan will output at most one row.
+func HasMaxOneRow(p LogicalPlan, childMaxOneRow []bool) bool {
+	if len(childMaxOneRow) == 0 {
+		// The reason why we use this check is that, this function
+		// is used both in planner/core and planner/cascades.
+		// In cascades planner, LogicalPlan may have no `children`.
+		return false
+	}
+	switch x := p.(type) {
+	case *LogicalLimit, *LogicalSort, *LogicalSelection,
+		*LogicalApply, *LogicalProjection, *LogicalAggregation:
+		return childMaxOneRow[0]
+	case *LogicalMaxOneRow:
+		return true
+	case *LogicalJoin:
+		switch x.JoinType {
+		case SemiJoin, AntiSemiJoin, LeftOuterSemiJoin, AntiLeftOuterSemiJoin:
+			return childMaxOneRow[0]
+		default:
+			return childMaxOneRow[0] && childMaxOneRow[1]
+		}
+	}
+	return false
+}
+
 // BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
 func (la *LogicalAggregation) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
 	if la.IsPartialModeAgg() {
@@ -66,6 +91,9 @@ func (la *LogicalAggregation) BuildKeyInfo(selfSchema *expression.Schema, childS
 			selfSchema.Keys = append(selfSchema.Keys, newKey)
 		}
 	}
+	if len(la.GroupByItems) == 0 {
+		la.maxOneRow = true
+	}
 }
 
 // BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
@@ -76,6 +104,17 @@ func (p *LogicalSelection) BuildKeyInfo(selfSchema *expression.Schema, childSche
 // BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
 func (p *LogicalLimit) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
 	p.baseLogicalPlan.BuildKeyInfo(selfSchema, childSchema)
+	if p.Count == 1 {
+		p.maxOneRow = true
+	}
+}
+
+// BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
+func (p *LogicalTableDual) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
+	p.logicalSchemaProducer.BuildKeyInfo(selfSchema, childSchema)
+	if p.RowCount == 1 {
+		p.maxOneRow = true
+	}
 }
 
 // A bijection exists between columns of a projection's schema and this projection's Exprs.
@@ -117,6 +156,8 @@ func (p *LogicalProjection) BuildKeyInfo(selfSchema *expression.Schema, childSch
 func (p *LogicalJoin) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
 	p.logicalSchemaProducer.BuildKeyInfo(selfSchema, childSchema)
 	switch p.JoinType {
+	case SemiJoin, LeftOuterSemiJoin, AntiSemiJoin, AntiLeftOuterSemiJoin:
+		selfSchema.Keys = childSchema[0].Clone().Keys
 	case InnerJoin, LeftOuterJoin, RightOuterJoin:
 		// If there is no equal conditions, then cartesian product can't be prevented and unique key information will destroy.
 		if len(p.EqualConditions) == 0 {
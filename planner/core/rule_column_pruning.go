@@ -106,10 +106,13 @@ func (la *LogicalAggregation) PruneColumns(parentUsedCols []*expression.Column)
 	var selfUsedCols []*expression.Column
 	for _, aggrFunc := range la.AggFuncs {
 		selfUsedCols = expression.ExtractColumnsFromExpressions(selfUsedCols, aggrFunc.Args, nil)
+		for _, byItem := range aggrFunc.OrderByItems {
+			selfUsedCols = append(selfUsedCols, expression.ExtractColumns(byItem.Expr)...)
+		}
 	}
 	if len(la.AggFuncs) == 0 {
 		// If all the aggregate functions are pruned, we should add an aggregate function to keep the correctness.
-		one, err := aggregation.NewAggFuncDesc(la.ctx, ast.AggFuncFirstRow, []expression.Expression{expression.One})
+		one, err := aggregation.NewAggFuncDesc(la.ctx, ast.AggFuncFirstRow, []expression.Expression{expression.One}, false)
 		if err != nil {
 			return err
 		}
@@ -139,6 +142,39 @@ func (la *LogicalAggregation) PruneColumns(parentUsedCols []*expression.Column)
 	return child.PruneColumns(selfUsedCols)
 }
 
+// PruneColumns implements LogicalPlan interface.
+func (p *LogicalUnionAll) PruneColumns(parentUsedCols []*expression.Column) error {
+	used := getUsedList(parentUsedCols, p.schema)
+	hasBeenUsed := false
+	for i := range used {
+		hasBeenUsed = hasBeenUsed || used[i]
+		if hasBeenUsed {
+			break
+		}
+	}
+	if !hasBeenUsed {
+		parentUsedCols = make([]*expression.Column, len(p.schema.Columns))
+		copy(parentUsedCols, p.schema.Columns)
+	}
+	for _, child := range p.Children() {
+		err := child.PruneColumns(parentUsedCols)
+		if err != nil {
+			return err
+		}
+	}
+
+	if hasBeenUsed {
+		// keep the schema of LogicalUnionAll same as its children's
+		used := getUsedList(p.children[0].Schema().Columns, p.schema)
+		for i := len(used) - 1; i >= 0; i-- {
+			if !used[i] {
+				p.schema.Columns = append(p.schema.Columns[:i], p.schema.Columns[i+1:]...)
+			}
+		}
+	}
+	return nil
+}
+
 // PruneColumns implements LogicalPlan interface.
 // If any expression can view as a constant in execution stage, such as correlated column, constant,
 // we do prune them. Note that we can't prune the expressions contain non-deterministic functions, such as rand().
@@ -160,6 +196,14 @@ func (ls *LogicalSort) PruneColumns(parentUsedCols []*expression.Column) error {
 	return child.PruneColumns(parentUsedCols)
 }
 
+// PruneColumns implements LogicalPlan interface.
+func (p *LogicalLock) PruneColumns(parentUsedCols []*expression.Column) error {
+	for _, cols := range p.tblID2Handle {
+		parentUsedCols = append(parentUsedCols, cols...)
+	}
+	return p.children[0].PruneColumns(parentUsedCols)
+}
+
 // PruneColumns implements LogicalPlan interface.
 func (p *LogicalUnionScan) PruneColumns(parentUsedCols []*expression.Column) error {
 	parentUsedCols = append(parentUsedCols, p.handleCol)
@@ -238,9 +282,7 @@ func (p *LogicalJoin) extractUsedCols(parentUsedCols []*expression.Column) (left
 }
 
 func (p *LogicalJoin) mergeSchema() {
-	lChild := p.children[0]
-	rChild := p.children[1]
-	p.schema = expression.MergeSchema(lChild.Schema(), rChild.Schema())
+	p.schema = buildLogicalJoinSchema(p.JoinType, p)
 }
 
 // PruneColumns implements LogicalPlan interface.
@@ -261,6 +303,73 @@ func (p *LogicalJoin) PruneColumns(parentUsedCols []*expression.Column) error {
 	return nil
 }
 
+// PruneColumns implements LogicalPlan interface.
+func (la *LogicalApply) PruneColumns(parentUsedCols []*expression.Column) error {
+	leftCols, rightCols := la.extractUsedCols(parentUsedCols)
+
+	err := la.children[1].PruneColumns(rightCols)
+	if err != nil {
+		return err
+	}
+
+	la.CorCols = extractCorColumnsBySchema(la.children[1], la.children[0].Schema())
+	for _, col := range la.CorCols {
+		leftCols = append(leftCols, &col.Column)
+	}
+
+	err = la.children[0].PruneColumns(leftCols)
+	if err != nil {
+		return err
+	}
+
+	la.mergeSchema()
+	return nil
+}
+
 func (*columnPruner) name() string {
 	return "column_prune"
 }
+
+// PruneColumns implements LogicalPlan interface.
+func (p *LogicalWindow) PruneColumns(parentUsedCols []*expression.Column) error {
+	windowColumns := p.GetWindowResultColumns()
+	n := 0
+	for _, col := range parentUsedCols {
+		used := false
+		for _, windowColumn := range windowColumns {
+			if windowColumn.Equal(nil, col) {
+				used = true
+				break
+			}
+		}
+		if !used {
+			parentUsedCols[n] = col
+			n++
+		}
+	}
+	parentUsedCols = parentUsedCols[:n]
+	parentUsedCols = p.extractUsedCols(parentUsedCols)
+	err := p.children[0].PruneColumns(parentUsedCols)
+	if err != nil {
+		return err
+	}
+
+	p.SetSchema(p.children[0].Schema().Clone())
+	p.Schema().Append(windowColumns...)
+	return nil
+}
+
+func (p *LogicalWindow) extractUsedCols(parentUsedCols []*expression.Column) []*expression.Column {
+	for _, desc := range p.WindowFuncDescs {
+		for _, arg := range desc.Args {
+			parentUsedCols = append(parentUsedCols, expression.ExtractColumns(arg)...)
+		}
+	}
+	for _, by := range p.PartitionBy {
+		parentUsedCols = append(parentUsedCols, by.Col)
+	}
+	for _, by := range p.OrderBy {
+		parentUsedCols = append(parentUsedCols, by.Col)
+	}
+	return parentUsedCols
+}
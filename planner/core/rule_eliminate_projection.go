@@ -64,6 +64,8 @@ func ResolveExprAndReplace(origin expression.Expression, replace map[string]*exp
 	switch expr := origin.(type) {
 	case *expression.Column:
 		resolveColumnAndReplace(expr, replace)
+	case *expression.CorrelatedColumn:
+		resolveColumnAndReplace(&expr.Column, replace)
 	case *expression.ScalarFunction:
 		for _, arg := range expr.GetArgs() {
 			ResolveExprAndReplace(arg, replace)
@@ -112,7 +114,9 @@ func (pe *projectionEliminator) optimize(ctx context.Context, lp LogicalPlan) (L
 func (pe *projectionEliminator) eliminate(p LogicalPlan, replace map[string]*expression.Column, canEliminate bool) LogicalPlan {
 	proj, isProj := p.(*LogicalProjection)
 	childFlag := canEliminate
-	if _, isAgg := p.(*LogicalAggregation); isAgg || isProj {
+	if _, isUnion := p.(*LogicalUnionAll); isUnion {
+		childFlag = false
+	} else if _, isAgg := p.(*LogicalAggregation); isAgg || isProj {
 		childFlag = true
 	}
 	for i, child := range p.Children() {
@@ -122,6 +126,8 @@ func (pe *projectionEliminator) eliminate(p LogicalPlan, replace map[string]*exp
 	switch x := p.(type) {
 	case *LogicalJoin:
 		x.schema = buildLogicalJoinSchema(x.JoinType, x)
+	case *LogicalApply:
+		x.schema = buildLogicalJoinSchema(x.JoinType, x)
 	default:
 		for _, dst := range p.Schema().Columns {
 			resolveColumnAndReplace(dst, replace)
@@ -178,6 +184,16 @@ func (p *LogicalJoin) replaceExprColumns(replace map[string]*expression.Column)
 	}
 }
 
+func (la *LogicalApply) replaceExprColumns(replace map[string]*expression.Column) {
+	la.LogicalJoin.replaceExprColumns(replace)
+	for _, coCol := range la.CorCols {
+		dst := replace[string(coCol.Column.HashCode(nil))]
+		if dst != nil {
+			coCol.Column = *dst
+		}
+	}
+}
+
 func (p *LogicalProjection) replaceExprColumns(replace map[string]*expression.Column) {
 	for _, expr := range p.Exprs {
 		ResolveExprAndReplace(expr, replace)
@@ -189,6 +205,9 @@ func (la *LogicalAggregation) replaceExprColumns(replace map[string]*expression.
 		for _, aggExpr := range agg.Args {
 			ResolveExprAndReplace(aggExpr, replace)
 		}
+		for _, byItem := range agg.OrderByItems {
+			ResolveExprAndReplace(byItem.Expr, replace)
+		}
 	}
 	for _, gbyItem := range la.GroupByItems {
 		ResolveExprAndReplace(gbyItem, replace)
@@ -208,6 +227,28 @@ func (ls *LogicalSort) replaceExprColumns(replace map[string]*expression.Column)
 	}
 }
 
+func (p *LogicalWindow) replaceExprColumns(replace map[string]*expression.Column) {
+	for _, desc := range p.WindowFuncDescs {
+		for _, arg := range desc.Args {
+			ResolveExprAndReplace(arg, replace)
+		}
+	}
+	for _, item := range p.PartitionBy {
+		resolveColumnAndReplace(item.Col, replace)
+	}
+	for _, item := range p.OrderBy {
+		resolveColumnAndReplace(item.Col, replace)
+	}
+	if p.Frame != nil {
+		for _, expr := range p.Frame.Start.CalcFuncs {
+			ResolveExprAndReplace(expr, replace)
+		}
+		for _, expr := range p.Frame.End.CalcFuncs {
+			ResolveExprAndReplace(expr, replace)
+		}
+	}
+}
+
 func (lt *LogicalTopN) replaceExprColumns(replace map[string]*expression.Column) {
 	for _, byItem := range lt.ByItems {
 		ResolveExprAndReplace(byItem.Expr, replace)
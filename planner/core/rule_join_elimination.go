@@ -165,7 +165,10 @@ func (o *outerJoinEliminator) getDupAgnosticAggCols(
 	for _, aggDesc := range agg.AggFuncs {
 		if aggDesc.Name != ast.AggFuncFirstRow &&
 			aggDesc.Name != ast.AggFuncMax &&
-			aggDesc.Name != ast.AggFuncMin {
+			aggDesc.Name != ast.AggFuncMin &&
+			aggDesc.Name != ast.AggFuncBitAnd &&
+			aggDesc.Name != ast.AggFuncBitOr &&
+			!aggDesc.HasDistinct {
 			// If not all aggregate functions are duplicate agnostic,
 			// we should clean the aggCols, so `return true, newAggCols[:0]`.
 			return true, newAggCols[:0]
@@ -173,6 +176,9 @@ func (o *outerJoinEliminator) getDupAgnosticAggCols(
 		for _, expr := range aggDesc.Args {
 			newAggCols = append(newAggCols, expression.ExtractColumns(expr)...)
 		}
+		for _, byItem := range aggDesc.OrderByItems {
+			newAggCols = append(newAggCols, expression.ExtractColumns(byItem.Expr)...)
+		}
 	}
 	return true, newAggCols
 }
@@ -205,6 +211,9 @@ func (o *outerJoinEliminator) doOptimize(p LogicalPlan, aggCols []*expression.Co
 			for _, expr := range aggDesc.Args {
 				parentCols = append(parentCols, expression.ExtractColumns(expr)...)
 			}
+			for _, byItem := range aggDesc.OrderByItems {
+				parentCols = append(parentCols, expression.ExtractColumns(byItem.Expr)...)
+			}
 		}
 	default:
 		parentCols = append(parentCols[:0], p.Schema().Columns...)
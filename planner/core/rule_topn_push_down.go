@@ -112,6 +112,23 @@ func (p *LogicalProjection) pushDownTopN(topN *LogicalTopN) LogicalPlan {
 	return p
 }
 
+func (p *LogicalUnionAll) pushDownTopN(topN *LogicalTopN) LogicalPlan {
+	for i, child := range p.children {
+		var newTopN *LogicalTopN
+		if topN != nil {
+			newTopN = LogicalTopN{Count: topN.Count + topN.Offset}.Init(p.ctx)
+			for _, by := range topN.ByItems {
+				newTopN.ByItems = append(newTopN.ByItems, by.Clone())
+			}
+		}
+		p.children[i] = child.pushDownTopN(newTopN)
+	}
+	if topN != nil {
+		return topN.setChild(p)
+	}
+	return p
+}
+
 // pushDownTopNToChild will push a topN to one child of join. The idx stands for join child index. 0 is for left child.
 func (p *LogicalJoin) pushDownTopNToChild(topN *LogicalTopN, idx int) LogicalPlan {
 	if topN == nil {
@@ -44,6 +44,35 @@ func (p *LogicalTableDual) DeriveStats(childStats []*property.StatsInfo, selfSch
 	return p.stats, nil
 }
 
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (p *LogicalCTE) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
+	p.stats = deriveCTEStats(p.cte, selfSchema)
+	return p.stats, nil
+}
+
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (p *LogicalCTETable) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
+	p.stats = deriveCTEStats(p.cte, selfSchema)
+	return p.stats, nil
+}
+
+// deriveCTEStats estimates the row count of a common table expression by its seed part,
+// the number of iterations of the recursive part is unknown at plan time.
+func deriveCTEStats(cte *CTEClass, selfSchema *expression.Schema) *property.StatsInfo {
+	if cte.seedPartPhysicalPlan == nil {
+		return getFakeStats(selfSchema.Len())
+	}
+	rowCount := cte.seedPartPhysicalPlan.statsInfo().RowCount
+	profile := &property.StatsInfo{
+		RowCount:    rowCount,
+		Cardinality: make([]float64, selfSchema.Len()),
+	}
+	for i := range profile.Cardinality {
+		profile.Cardinality[i] = rowCount
+	}
+	return profile
+}
+
 // DeriveStats implement LogicalPlan DeriveStats interface.
 func (p *LogicalMemTable) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
 	statsTable := statistics.PseudoTable(p.tableInfo)
@@ -347,6 +376,25 @@ func (la *LogicalAggregation) DeriveStats(childStats []*property.StatsInfo, self
 // every matched bucket.
 func (p *LogicalJoin) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
 	leftProfile, rightProfile := childStats[0], childStats[1]
+	if p.JoinType == SemiJoin || p.JoinType == AntiSemiJoin {
+		p.stats = &property.StatsInfo{
+			RowCount:    leftProfile.RowCount * selectionFactor,
+			Cardinality: make([]float64, len(leftProfile.Cardinality)),
+		}
+		for i := range p.stats.Cardinality {
+			p.stats.Cardinality[i] = leftProfile.Cardinality[i] * selectionFactor
+		}
+		return p.stats, nil
+	}
+	if p.JoinType == LeftOuterSemiJoin || p.JoinType == AntiLeftOuterSemiJoin {
+		p.stats = &property.StatsInfo{
+			RowCount:    leftProfile.RowCount,
+			Cardinality: make([]float64, selfSchema.Len()),
+		}
+		copy(p.stats.Cardinality, leftProfile.Cardinality)
+		p.stats.Cardinality[len(p.stats.Cardinality)-1] = 2.0
+		return p.stats, nil
+	}
 	helper := &fullJoinRowCountHelper{
 		cartesian:     0 == len(p.EqualConditions),
 		leftProfile:   leftProfile,
@@ -376,6 +424,56 @@ func (p *LogicalJoin) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 	return p.stats, nil
 }
 
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (la *LogicalApply) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
+	leftProfile := childStats[0]
+	la.stats = &property.StatsInfo{
+		RowCount:    leftProfile.RowCount,
+		Cardinality: make([]float64, selfSchema.Len()),
+	}
+	copy(la.stats.Cardinality, leftProfile.Cardinality)
+	if la.JoinType == LeftOuterSemiJoin || la.JoinType == AntiLeftOuterSemiJoin {
+		la.stats.Cardinality[len(la.stats.Cardinality)-1] = 2.0
+	} else {
+		for i := childSchema[0].Len(); i < selfSchema.Len(); i++ {
+			la.stats.Cardinality[i] = leftProfile.RowCount
+		}
+	}
+	return la.stats, nil
+}
+
+// Exists and MaxOneRow produce at most one row, so we set the RowCount of stats one.
+func getSingletonStats(len int) *property.StatsInfo {
+	ret := &property.StatsInfo{
+		RowCount:    1.0,
+		Cardinality: make([]float64, len),
+	}
+	for i := 0; i < len; i++ {
+		ret.Cardinality[i] = 1
+	}
+	return ret
+}
+
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (p *LogicalMaxOneRow) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
+	p.stats = getSingletonStats(selfSchema.Len())
+	return p.stats, nil
+}
+
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (p *LogicalUnionAll) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
+	p.stats = &property.StatsInfo{
+		Cardinality: make([]float64, selfSchema.Len()),
+	}
+	for _, childProfile := range childStats {
+		p.stats.RowCount += childProfile.RowCount
+		for i := range p.stats.Cardinality {
+			p.stats.Cardinality[i] += childProfile.Cardinality[i]
+		}
+	}
+	return p.stats, nil
+}
+
 type fullJoinRowCountHelper struct {
 	cartesian     bool
 	leftProfile   *property.StatsInfo
@@ -395,3 +493,21 @@ func (h *fullJoinRowCountHelper) estimate() float64 {
 	count := h.leftProfile.RowCount * h.rightProfile.RowCount / math.Max(leftKeyCardinality, rightKeyCardinality)
 	return count
 }
+
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (p *LogicalWindow) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
+	childProfile := childStats[0]
+	p.stats = &property.StatsInfo{
+		RowCount:    childProfile.RowCount,
+		Cardinality: make([]float64, selfSchema.Len()),
+	}
+	childLen := selfSchema.Len() - len(p.WindowFuncDescs)
+	for i := 0; i < childLen; i++ {
+		colIdx := childSchema[0].ColumnIndex(selfSchema.Columns[i])
+		p.stats.Cardinality[i] = childProfile.Cardinality[colIdx]
+	}
+	for i := childLen; i < selfSchema.Len(); i++ {
+		p.stats.Cardinality[i] = childProfile.RowCount
+	}
+	return p.stats, nil
+}
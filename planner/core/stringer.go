@@ -14,6 +14,7 @@
 package core
 
 import (
+	"bytes"
 	"fmt"
 	"strings"
 )
@@ -80,6 +81,14 @@ func toString(in Plan, strs []string, idxs []int) ([]string, []int) {
 			id = "MergeRightOuterJoin"
 		case InnerJoin:
 			id = "MergeInnerJoin"
+		case SemiJoin:
+			id = "MergeSemiJoin"
+		case AntiSemiJoin:
+			id = "MergeAntiSemiJoin"
+		case LeftOuterSemiJoin:
+			id = "MergeLeftOuterSemiJoin"
+		case AntiLeftOuterSemiJoin:
+			id = "MergeAntiLeftOuterSemiJoin"
 		}
 		str = id + "{" + strings.Join(children, "->") + "}"
 		for i := range x.LeftJoinKeys {
@@ -87,6 +96,49 @@ func toString(in Plan, strs []string, idxs []int) ([]string, []int) {
 			r := x.RightJoinKeys[i].String()
 			str += fmt.Sprintf("(%s,%s)", l, r)
 		}
+	case *PhysicalIndexJoin:
+		last := len(idxs) - 1
+		idx := idxs[last]
+		children := strs[idx:]
+		strs = strs[:idx]
+		idxs = idxs[:last]
+		str = "IndexJoin{" + strings.Join(children, "->") + "}"
+		for i := range x.OuterJoinKeys {
+			l := x.OuterJoinKeys[i]
+			r := x.InnerJoinKeys[i]
+			str += fmt.Sprintf("(%s,%s)", l, r)
+		}
+	case *LogicalApply:
+		last := len(idxs) - 1
+		idx := idxs[last]
+		children := strs[idx:]
+		strs = strs[:idx]
+		idxs = idxs[:last]
+		str = "Apply{" + strings.Join(children, "->") + "}"
+	case *PhysicalApply:
+		last := len(idxs) - 1
+		idx := idxs[last]
+		children := strs[idx:]
+		strs = strs[:idx]
+		idxs = idxs[:last]
+		str = "Apply{" + strings.Join(children, "->") + "}"
+	case *LogicalUnionAll, *PhysicalUnionAll:
+		last := len(idxs) - 1
+		idx := idxs[last]
+		children := strs[idx:]
+		strs = strs[:idx]
+		idxs = idxs[:last]
+		str = "UnionAll{" + strings.Join(children, "->") + "}"
+	case *LogicalMaxOneRow, *PhysicalMaxOneRow:
+		str = "MaxOneRow"
+	case *LogicalLock, *PhysicalLock:
+		str = "Lock"
+	case *LogicalWindow:
+		buffer := bytes.NewBufferString("")
+		formatWindowFuncDescs(buffer, x.WindowFuncDescs, x.schema)
+		str = fmt.Sprintf("Window(%s)", buffer.String())
+	case *PhysicalWindow:
+		str = fmt.Sprintf("Window(%s)", x.ExplainInfo())
 	case *LogicalLimit, *PhysicalLimit:
 		str = "Limit"
 	case *ShowDDL:
@@ -127,8 +179,26 @@ func toString(in Plan, strs []string, idxs []int) ([]string, []int) {
 		str = fmt.Sprintf("TopN(%v,%d,%d)", x.ByItems, x.Offset, x.Count)
 	case *LogicalTableDual, *PhysicalTableDual:
 		str = "Dual"
+	case *PointGetPlan:
+		if x.IndexInfo != nil {
+			str = fmt.Sprintf("PointGet(Index(%s.%s)%v)", x.TblInfo.Name.L, x.IndexInfo.Name.L, x.IndexValues)
+		} else {
+			str = fmt.Sprintf("PointGet(Handle(%s.%s)%v)", x.TblInfo.Name.L, x.TblInfo.GetPkName().L, x.Handle)
+		}
+	case *BatchPointGetPlan:
+		if x.IndexInfo != nil {
+			str = fmt.Sprintf("BatchPointGet(Index(%s.%s)%v)", x.TblInfo.Name.L, x.IndexInfo.Name.L, x.IndexValues)
+		} else {
+			str = fmt.Sprintf("BatchPointGet(Handle(%s.%s)%v)", x.TblInfo.Name.L, x.TblInfo.GetPkName().L, x.Handles)
+		}
+	case *LogicalCTE, *PhysicalCTE:
+		str = "CTE"
+	case *LogicalCTETable, *PhysicalCTETable:
+		str = "CTETable"
 	case *PhysicalHashAgg:
 		str = "HashAgg"
+	case *PhysicalStreamAgg:
+		str = "StreamAgg"
 	case *LogicalAggregation:
 		str = "Aggr("
 		for i, aggFunc := range x.AggFuncs {
@@ -163,6 +233,8 @@ func toString(in Plan, strs []string, idxs []int) ([]string, []int) {
 			children = append(children, fmt.Sprintf("Table(%s)", strings.Join(colNames, ", ")))
 		}
 		str = str + strings.Join(children, ",") + "}"
+	case *Update:
+		str = fmt.Sprintf("%s->Update", ToString(x.SelectPlan))
 	case *Delete:
 		str = fmt.Sprintf("%s->Delete", ToString(x.SelectPlan))
 	case *Insert:
@@ -142,6 +142,128 @@ func (p *basePhysicalPlan) attach2Task(tasks ...task) task {
 	return attachPlan2Task(p.self, t)
 }
 
+func (p *PhysicalApply) attach2Task(tasks ...task) task {
+	lTask := finishCopTask(p.ctx, tasks[0].copy())
+	rTask := finishCopTask(p.ctx, tasks[1].copy())
+	p.SetChildren(lTask.plan(), rTask.plan())
+	p.schema = BuildPhysicalJoinSchema(p.JoinType, p)
+	return &rootTask{
+		p:   p,
+		cst: p.GetCost(lTask.count(), rTask.count(), lTask.cost(), rTask.cost()),
+	}
+}
+
+func (p *PhysicalUnionAll) attach2Task(tasks ...task) task {
+	t := &rootTask{p: p}
+	childPlans := make([]PhysicalPlan, 0, len(tasks))
+	var childMaxCost float64
+	for _, task := range tasks {
+		task = finishCopTask(p.ctx, task)
+		childCost := task.cost()
+		if childCost > childMaxCost {
+			childMaxCost = childCost
+		}
+		childPlans = append(childPlans, task.plan())
+	}
+	p.SetChildren(childPlans...)
+	sessVars := p.ctx.GetSessionVars()
+	// Children of UnionExec are executed in parallel.
+	t.cst = childMaxCost + float64(1+len(tasks))*sessVars.ConcurrencyFactor
+	return t
+}
+
+// GetCost computes the cost of apply operator.
+func (p *PhysicalApply) GetCost(lCount, rCount, lCost, rCost float64) float64 {
+	var cpuCost float64
+	sessVars := p.ctx.GetSessionVars()
+	if len(p.LeftConditions) > 0 {
+		cpuCost += lCount * sessVars.CPUFactor
+		lCount *= selectionFactor
+	}
+	if len(p.RightConditions) > 0 {
+		cpuCost += lCount * rCount * sessVars.CPUFactor
+		rCount *= selectionFactor
+	}
+	if len(p.EqualConditions)+len(p.OtherConditions) > 0 {
+		if p.JoinType == SemiJoin || p.JoinType == AntiSemiJoin ||
+			p.JoinType == LeftOuterSemiJoin || p.JoinType == AntiLeftOuterSemiJoin {
+			cpuCost += lCount * rCount * sessVars.CPUFactor * 0.5
+		} else {
+			cpuCost += lCount * rCount * sessVars.CPUFactor
+		}
+	}
+	// Apply uses a NestedLoop method for execution.
+	// For every row from the left(outer) side, it executes
+	// the whole right(inner) plan tree. So the cost of apply
+	// should be : apply cost + left cost + left count * right cost
+	return cpuCost + lCost + lCount*rCost
+}
+
+func (p *PhysicalIndexJoin) attach2Task(tasks ...task) task {
+	innerTask := p.innerTask
+	outerTask := finishCopTask(p.ctx, tasks[1-p.InnerChildIdx].copy())
+	if p.InnerChildIdx == 1 {
+		p.SetChildren(outerTask.plan(), innerTask.plan())
+	} else {
+		p.SetChildren(innerTask.plan(), outerTask.plan())
+	}
+	p.schema = BuildPhysicalJoinSchema(p.JoinType, p)
+	return &rootTask{
+		p:   p,
+		cst: p.GetCost(outerTask, innerTask),
+	}
+}
+
+// GetCost computes the cost of index join operator and its children.
+func (p *PhysicalIndexJoin) GetCost(outerTask, innerTask task) float64 {
+	var cpuCost float64
+	outerCnt, innerCnt := outerTask.count(), innerTask.count()
+	sessVars := p.ctx.GetSessionVars()
+	// Add the cost of evaluating outer filter, since inner filter of index join
+	// is always empty, we can simply tell whether outer filter is empty using the
+	// summed length of left/right conditions.
+	if len(p.LeftConditions)+len(p.RightConditions) > 0 {
+		cpuCost += sessVars.CPUFactor * outerCnt
+		outerCnt *= selectionFactor
+	}
+	// Cost of extracting lookup keys.
+	innerCPUCost := sessVars.CPUFactor * outerCnt
+	// Cost of sorting and removing duplicate lookup keys:
+	// (outerCnt / batchSize) * (batchSize * Log2(batchSize) + batchSize) * CPUFactor
+	batchSize := math.Min(float64(sessVars.MaxChunkSize), outerCnt)
+	if batchSize > 2 {
+		innerCPUCost += outerCnt * (math.Log2(batchSize) + 1) * sessVars.CPUFactor
+	}
+	// Add cost of building inner executors. CPU cost of building copTasks:
+	// (outerCnt / batchSize) * (batchSize * distinctFactor) * CPUFactor
+	// Since we don't know the number of copTasks built, ignore these network cost now.
+	innerCPUCost += outerCnt * distinctFactor * sessVars.CPUFactor
+	// CPU cost of building hash table for inner results:
+	// (outerCnt / batchSize) * (batchSize * distinctFactor) * innerCnt * CPUFactor
+	innerCPUCost += outerCnt * distinctFactor * innerCnt * sessVars.CPUFactor
+	innerConcurrency := float64(sessVars.IndexLookupJoinConcurrency)
+	cpuCost += innerCPUCost / innerConcurrency
+	// Cost of probing hash table in main thread.
+	numPairs := outerCnt * innerCnt
+	if p.JoinType == SemiJoin || p.JoinType == AntiSemiJoin ||
+		p.JoinType == LeftOuterSemiJoin || p.JoinType == AntiLeftOuterSemiJoin {
+		if len(p.OtherConditions) > 0 {
+			numPairs *= 0.5
+		} else {
+			numPairs = 0
+		}
+	}
+	probeCost := numPairs * sessVars.CPUFactor
+	// Cost of additional concurrent goroutines.
+	cpuCost += probeCost + (innerConcurrency+1.0)*sessVars.ConcurrencyFactor
+	// Memory cost of hash tables for inner rows. The computed result is the upper bound,
+	// since the executor is pipelined and not all workers are always in full load.
+	memoryCost := innerConcurrency * (batchSize * distinctFactor) * innerCnt * sessVars.MemoryFactor
+	// Cost of inner child plan, i.e, mainly I/O and network cost.
+	innerPlanCost := outerCnt * innerTask.cost()
+	return outerTask.cost() + innerPlanCost + cpuCost + memoryCost
+}
+
 // GetCost computes cost of hash join operator itself.
 func (p *PhysicalHashJoin) GetCost(lCnt, rCnt float64) float64 {
 	buildCnt, probeCnt := lCnt, rCnt
@@ -244,6 +366,9 @@ func (p *PhysicalMergeJoin) attach2Task(tasks ...task) task {
 // Now it's only used for TableReader.
 func splitCopAvg2CountAndSum(p PhysicalPlan) {
 	var baseAgg *basePhysicalAgg
+	if agg, ok := p.(*PhysicalStreamAgg); ok {
+		baseAgg = &agg.basePhysicalAgg
+	}
 	if agg, ok := p.(*PhysicalHashAgg); ok {
 		baseAgg = &agg.basePhysicalAgg
 	}
@@ -594,6 +719,14 @@ func BuildFinalModeAggregation(
 			args = append(args, partialSchema.Columns[partialCursor])
 			partialCursor++
 		}
+		if aggregation.IsVarianceFunc(finalAggFunc.Name) {
+			partialSchema.Append(&expression.Column{
+				UniqueID: sctx.GetSessionVars().AllocPlanColumnID(),
+				RetType:  types.NewFieldType(mysql.TypeDouble),
+			})
+			args = append(args, partialSchema.Columns[partialCursor])
+			partialCursor++
+		}
 		finalAggFunc.Args = args
 		finalAggFunc.Mode = aggregation.FinalMode
 		finalAggFunc.RetTp = aggFunc.RetTp
@@ -629,6 +762,15 @@ func (p *basePhysicalAgg) newPartialAggregate() (partial, final PhysicalPlan) {
 	p.schema = partialSchema
 	partialAgg := p.self
 	// Create physical "final" aggregation.
+	if p.tp == TypeStreamAgg {
+		finalAgg := basePhysicalAgg{
+			AggFuncs:     finalAggFuncs,
+			GroupByItems: finalGbyItems,
+		}.initForStream(p.ctx, p.stats)
+		finalAgg.schema = finalSchema
+		return partialAgg, finalAgg
+	}
+
 	finalAgg := basePhysicalAgg{
 		AggFuncs:     finalAggFuncs,
 		GroupByItems: finalGbyItems,
@@ -674,11 +816,71 @@ func RemoveUnnecessaryFirstRow(
 		if aggregation.NeedValue(aggFunc.Name) {
 			partialCursor++
 		}
+		if aggregation.IsVarianceFunc(aggFunc.Name) {
+			partialCursor++
+		}
 		newAggFuncs = append(newAggFuncs, aggFunc)
 	}
 	return newAggFuncs
 }
 
+func (p *PhysicalStreamAgg) attach2Task(tasks ...task) task {
+	t := tasks[0].copy()
+	inputRows := t.count()
+	if cop, ok := t.(*copTask); ok {
+		// We should not push agg down across double read, since the data of second read is ordered by handle instead of index.
+		// The `extraHandleCol` is added if the double read needs to keep order. So we just use it to decided
+		// whether the following plan is double read with order reserved.
+		if cop.extraHandleCol != nil {
+			t = finishCopTask(p.ctx, cop)
+			inputRows = t.count()
+			attachPlan2Task(p, t)
+		} else {
+			partialAgg, finalAgg := p.newPartialAggregate()
+			if partialAgg != nil {
+				if cop.tablePlan != nil {
+					cop.finishIndexPlan()
+					partialAgg.SetChildren(cop.tablePlan)
+					cop.tablePlan = partialAgg
+				} else {
+					partialAgg.SetChildren(cop.indexPlan)
+					cop.indexPlan = partialAgg
+				}
+				cop.addCost(p.GetCost(inputRows, false))
+			}
+			t = finishCopTask(p.ctx, cop)
+			inputRows = t.count()
+			attachPlan2Task(finalAgg, t)
+		}
+	} else {
+		attachPlan2Task(p, t)
+	}
+	t.addCost(p.GetCost(inputRows, true))
+	return t
+}
+
+// GetCost computes cost of stream aggregation considering CPU/memory.
+func (p *PhysicalStreamAgg) GetCost(inputRows float64, isRoot bool) float64 {
+	aggFuncFactor := p.getAggFuncCostFactor()
+	var cpuCost float64
+	sessVars := p.ctx.GetSessionVars()
+	if isRoot {
+		cpuCost = inputRows * sessVars.CPUFactor * aggFuncFactor
+	} else {
+		cpuCost = inputRows * sessVars.CopCPUFactor * aggFuncFactor
+	}
+	// Only the functions with DISTINCT keep the values of the rows in a group.
+	numDistinctFunc := 0
+	for _, fun := range p.AggFuncs {
+		if fun.HasDistinct {
+			numDistinctFunc++
+		}
+	}
+	rowsPerGroup := inputRows / p.statsInfo().RowCount
+	memoryCost := rowsPerGroup * distinctFactor * sessVars.MemoryFactor * float64(numDistinctFunc)
+	return cpuCost + memoryCost
+}
+
 // cpuCostDivisor computes the concurrency to which we would amortize CPU cost
 // for hash aggregation.
 func (p *PhysicalHashAgg) cpuCostDivisor() (float64, float64) {
@@ -32,7 +32,7 @@ func (a *AggregateFuncExtractor) Enter(n ast.Node) (ast.Node, bool) {
 	switch n.(type) {
 	case *ast.AggregateFuncExpr:
 		a.inAggregateFuncExpr = true
-	case *ast.SelectStmt:
+	case *ast.SelectStmt, *ast.SetOprStmt:
 		return n, true
 	}
 	return n, false
@@ -48,6 +48,31 @@ func (a *AggregateFuncExtractor) Leave(n ast.Node) (ast.Node, bool) {
 	return n, true
 }
 
+// WindowFuncExtractor visits Expr tree.
+// It collects WindowFuncExpr.
+type WindowFuncExtractor struct {
+	// windowFuncs is the collected WindowFuncExprs.
+	windowFuncs []*ast.WindowFuncExpr
+}
+
+// Enter implements Visitor interface.
+func (a *WindowFuncExtractor) Enter(n ast.Node) (ast.Node, bool) {
+	switch n.(type) {
+	case *ast.SelectStmt, *ast.SetOprStmt:
+		return n, true
+	}
+	return n, false
+}
+
+// Leave implements Visitor interface.
+func (a *WindowFuncExtractor) Leave(n ast.Node) (ast.Node, bool) {
+	switch v := n.(type) {
+	case *ast.WindowFuncExpr:
+		a.windowFuncs = append(a.windowFuncs, v)
+	}
+	return n, true
+}
+
 // logicalSchemaProducer stores the schema for the logical plans who can produce schema directly.
 type logicalSchemaProducer struct {
 	schema *expression.Schema
@@ -135,6 +160,14 @@ func (s *baseSchemaProducer) setSchemaAndNames(schema *expression.Schema, names
 }
 
 func buildLogicalJoinSchema(joinType JoinType, join LogicalPlan) *expression.Schema {
+	switch joinType {
+	case SemiJoin, AntiSemiJoin:
+		return join.Children()[0].Schema().Clone()
+	case LeftOuterSemiJoin, AntiLeftOuterSemiJoin:
+		newSchema := join.Children()[0].Schema().Clone()
+		newSchema.Append(join.Schema().Columns[join.Schema().Len()-1])
+		return newSchema
+	}
 	leftSchema := join.Children()[0].Schema()
 	newSchema := expression.MergeSchema(leftSchema, join.Children()[1].Schema())
 	if joinType == LeftOuterJoin {
@@ -147,5 +180,13 @@ func buildLogicalJoinSchema(joinType JoinType, join LogicalPlan) *expression.Sch
 
 // BuildPhysicalJoinSchema builds the schema of PhysicalJoin from it's children's schema.
 func BuildPhysicalJoinSchema(joinType JoinType, join PhysicalPlan) *expression.Schema {
+	switch joinType {
+	case SemiJoin, AntiSemiJoin:
+		return join.Children()[0].Schema().Clone()
+	case LeftOuterSemiJoin, AntiLeftOuterSemiJoin:
+		newSchema := join.Children()[0].Schema().Clone()
+		newSchema.Append(join.Schema().Columns[join.Schema().Len()-1])
+		return newSchema
+	}
 	return expression.MergeSchema(join.Children()[0].Schema(), join.Children()[1].Schema())
 }
@@ -29,6 +29,11 @@ import (
 func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is infoschema.InfoSchema) (plannercore.Plan, types.NameSlice, error) {
 	sctx.PrepareTxnFuture(ctx)
 
+	// The point get queries are planned directly from the AST without the optimizer.
+	if fp := plannercore.TryFastPlan(sctx, node); fp != nil {
+		return fp, fp.OutputNames(), nil
+	}
+
 	// build logical plan
 	sctx.GetSessionVars().PlanID = 0
 	sctx.GetSessionVars().PlanColumnID = 0
@@ -38,6 +43,12 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 		return nil, nil, err
 	}
 
+	// Handle the execute statement.
+	if execPlan, ok := p.(*plannercore.Execute); ok {
+		err := execPlan.OptimizePreparedPlan(ctx, sctx, is)
+		return p, p.OutputNames(), err
+	}
+
 	names := p.OutputNames()
 
 	// Handle the non-logical plan statement.
@@ -52,6 +52,7 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx/variable"
@@ -509,6 +510,11 @@ func (cc *clientConn) openSessionAndDoAuth() error {
 	if err != nil {
 		return err
 	}
+	host, err := cc.PeerHost("NO")
+	if err != nil {
+		return err
+	}
+	cc.ctx.GetSessionVars().User = &auth.UserIdentity{Username: cc.user, Hostname: host}
 	if cc.dbname != "" {
 		err = cc.useDB(context.Background(), cc.dbname)
 		if err != nil {
@@ -703,6 +709,18 @@ func (cc *clientConn) dispatch(ctx context.Context, data []byte) error {
 		return cc.writeOK()
 	case mysql.ComFieldList:
 		return cc.handleFieldList(dataStr)
+	case mysql.ComStmtPrepare:
+		return cc.handleStmtPrepare(dataStr)
+	case mysql.ComStmtExecute:
+		return cc.handleStmtExecute(ctx, data)
+	case mysql.ComStmtSendLongData:
+		return cc.handleStmtSendLongData(data)
+	case mysql.ComStmtClose:
+		return cc.handleStmtClose(data)
+	case mysql.ComStmtReset:
+		return cc.handleStmtReset(data)
+	case mysql.ComStmtFetch:
+		return cc.handleStmtFetch(ctx, data)
 	default:
 		return mysql.NewErrf(mysql.ErrUnknown, "command %d not supported now", cmd)
 	}
@@ -729,7 +747,7 @@ func (cc *clientConn) flush() error {
 }
 
 func (cc *clientConn) writeOK() error {
-	return cc.writeOkWith("", cc.ctx.AffectedRows(), cc.ctx.LastInsertID(), cc.ctx.Status(), cc.ctx.WarningCount())
+	return cc.writeOkWith(cc.ctx.LastMessage(), cc.ctx.AffectedRows(), cc.ctx.LastInsertID(), cc.ctx.Status(), cc.ctx.WarningCount())
 }
 
 func (cc *clientConn) writeOkWith(msg string, affectedRows, lastInsertID uint64, status, warnCnt uint16) error {
@@ -1041,7 +1059,7 @@ func (cc *clientConn) writeMultiResultset(ctx context.Context, rss []ResultSet,
 			if !lastRs {
 				status |= mysql.ServerMoreResultsExists
 			}
-			if err := cc.writeOkWith("", r.AffectedRows(), r.LastInsertID(), status, r.WarnCount()); err != nil {
+			if err := cc.writeOkWith(r.LastMessage(), r.AffectedRows(), r.LastInsertID(), status, r.WarnCount()); err != nil {
 				return err
 			}
 			continue
@@ -1096,6 +1114,9 @@ func (cc getLastStmtInConn) String() string {
 		return "ListFields " + string(data)
 	case mysql.ComQuery, mysql.ComStmtPrepare:
 		return queryStrForLog(string(hack.String(data)))
+	case mysql.ComStmtExecute, mysql.ComStmtFetch:
+		stmtID := binary.LittleEndian.Uint32(data[0:4])
+		return queryStrForLog(cc.preparedStmt2String(stmtID))
 	case mysql.ComStmtClose, mysql.ComStmtReset:
 		stmtID := binary.LittleEndian.Uint32(data[0:4])
 		return mysql.Command2Str[cmd] + " " + strconv.Itoa(int(stmtID))
@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/testleak"
 )
@@ -204,6 +205,59 @@ func mapBelong(m1, m2 map[string]string) bool {
 	return true
 }
 
+func (ts *ConnTestSuite) TestParseExecArgs(c *C) {
+	tests := []struct {
+		paramTypes  []byte
+		paramValues []byte
+		expect      interface{}
+	}{
+		{[]byte{mysql.TypeTiny, 0}, []byte{0xff}, int64(-1)},
+		{[]byte{mysql.TypeTiny, 0x80}, []byte{0xff}, uint64(255)},
+		{[]byte{mysql.TypeShort, 0}, []byte{0x01, 0x01}, int64(257)},
+		{[]byte{mysql.TypeLong, 0}, []byte{0xfe, 0xff, 0xff, 0xff}, int64(-2)},
+		{[]byte{mysql.TypeLonglong, 0x80}, []byte{0x01, 0, 0, 0, 0, 0, 0, 0x80}, uint64(0x8000000000000001)},
+		{[]byte{mysql.TypeDouble, 0}, []byte{0, 0, 0, 0, 0, 0, 0xf8, 0x3f}, float64(1.5)},
+		{[]byte{mysql.TypeVarString, 0}, []byte{0x03, 'a', 'b', 'c'}, "abc"},
+		{[]byte{mysql.TypeDate, 0}, []byte{0x04, 0xda, 0x07, 0x0a, 0x11}, "2010-10-17"},
+		{[]byte{mysql.TypeDatetime, 0}, []byte{0x07, 0xda, 0x07, 0x0a, 0x11, 0x13, 0x1b, 0x1e}, "2010-10-17 19:27:30"},
+		{[]byte{mysql.TypeDuration, 0}, []byte{0x08, 0x01, 0x01, 0, 0, 0, 0x02, 0x03, 0x04}, "-1 02:03:04"},
+		{[]byte{mysql.TypeNull, 0}, nil, nil},
+	}
+	for _, tt := range tests {
+		args := make([]types.Datum, 1)
+		err := parseExecArgs(make([][]byte, 1), args, []byte{0}, tt.paramTypes, tt.paramValues)
+		c.Assert(err, IsNil)
+		c.Assert(args[0].GetValue(), Equals, tt.expect)
+	}
+
+	// The null bitmap and the long data take precedence over the parameter values.
+	args := make([]types.Datum, 2)
+	err := parseExecArgs([][]byte{nil, []byte("long")}, args, []byte{0x01},
+		[]byte{mysql.TypeLong, 0, mysql.TypeBlob, 0}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(args[0].IsNull(), IsTrue)
+	c.Assert(args[1].GetBytes(), BytesEquals, []byte("long"))
+
+	err = parseExecArgs(make([][]byte, 1), make([]types.Datum, 1), []byte{0}, []byte{mysql.TypeLong, 0}, []byte{0x01})
+	c.Assert(err, Equals, mysql.ErrMalformPacket)
+	// The binary time values are truncated.
+	for _, tt := range []struct {
+		paramTypes  []byte
+		paramValues []byte
+	}{
+		{[]byte{mysql.TypeDate, 0}, []byte{0x04, 0xda, 0x07}},
+		{[]byte{mysql.TypeDatetime, 0}, []byte{0x07, 0xda, 0x07, 0x0a, 0x11}},
+		{[]byte{mysql.TypeTimestamp, 0}, []byte{0x0b, 0xda, 0x07, 0x0a, 0x11, 0x13, 0x1b, 0x1e}},
+		{[]byte{mysql.TypeDuration, 0}, []byte{0x08}},
+		{[]byte{mysql.TypeDuration, 0}, []byte{0x0c, 0x01, 0x01, 0, 0, 0, 0x02, 0x03, 0x04}},
+	} {
+		err = parseExecArgs(make([][]byte, 1), make([]types.Datum, 1), []byte{0}, tt.paramTypes, tt.paramValues)
+		c.Assert(err, Equals, mysql.ErrMalformPacket)
+	}
+	err = parseExecArgs(make([][]byte, 1), make([]types.Datum, 1), []byte{0}, []byte{0xf0, 0}, nil)
+	c.Assert(errUnknownFieldType.Equal(err), IsTrue)
+}
+
 type mockTiDBCtx struct {
 	TiDBContext
 	rs  []ResultSet
@@ -39,6 +39,9 @@ type QueryCtx interface {
 	// AffectedRows returns affected rows of last executed command.
 	AffectedRows() uint64
 
+	// LastMessage returns last info message generated by some commands
+	LastMessage() string
+
 	// Value returns the value associated with this context for key.
 	Value(key fmt.Stringer) interface{}
 
@@ -66,6 +69,12 @@ type QueryCtx interface {
 	// FieldList returns columns of a table.
 	FieldList(tableName string) (columns []*ColumnInfo, err error)
 
+	// Prepare prepares a statement.
+	Prepare(sql string) (statement PreparedStatement, columns, params []*ColumnInfo, err error)
+
+	// GetStatement gets PreparedStatement by statement ID.
+	GetStatement(stmtID int) PreparedStatement
+
 	// Close closes the QueryCtx.
 	Close() error
 
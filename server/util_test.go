@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/testleak"
 )
@@ -130,6 +131,55 @@ func (s *testUtilSuite) TestDumpTextValue(c *C) {
 	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{year}).ToRow())
 	c.Assert(err, IsNil)
 	c.Assert(mustDecodeStr(c, bs), Equals, "1984")
+
+	dt := types.NewTimeDatum(types.NewTime(types.FromDate(2017, 1, 6, 12, 5, 3, 0), mysql.TypeDatetime, 0))
+	columns[0].Type = mysql.TypeDatetime
+	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{dt}).ToRow())
+	c.Assert(err, IsNil)
+	c.Assert(mustDecodeStr(c, bs), Equals, "2017-01-06 12:05:03")
+
+	dur := types.NewDurationDatum(types.NewDuration(11, 30, 45, 0, 0))
+	columns[0].Type = mysql.TypeDuration
+	columns[0].Decimal = 0
+	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{dur}).ToRow())
+	c.Assert(err, IsNil)
+	c.Assert(mustDecodeStr(c, bs), Equals, "11:30:45")
+
+	j := types.NewDatum(json.CreateBinary(map[string]interface{}{"a": []interface{}{int64(1), "b"}}))
+	columns[0].Type = mysql.TypeJSON
+	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{j}).ToRow())
+	c.Assert(err, IsNil)
+	c.Assert(mustDecodeStr(c, bs), Equals, `{"a": [1, "b"]}`)
+
+	columns[0].Type = mysql.TypeEnum
+	enum := types.NewMysqlEnumDatum(types.Enum{Name: "b", Value: 2})
+	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{enum}).ToRow())
+	c.Assert(err, IsNil)
+	c.Assert(mustDecodeStr(c, bs), Equals, "b")
+
+	columns[0].Type = mysql.TypeSet
+	set := types.NewDatum(types.Set{Name: "a,c", Value: 5})
+	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{set}).ToRow())
+	c.Assert(err, IsNil)
+	c.Assert(mustDecodeStr(c, bs), Equals, "a,c")
+}
+
+func (s *testUtilSuite) TestDumpBinaryTime(c *C) {
+	t := types.NewTime(types.FromDate(2017, 1, 6, 0, 0, 0, 0), mysql.TypeDate, 0)
+	c.Assert(dumpBinaryDateTime(nil, t), DeepEquals, []byte{4, 225, 7, 1, 6})
+
+	t = types.NewTime(types.FromDate(2017, 1, 6, 12, 5, 3, 0), mysql.TypeDatetime, 0)
+	c.Assert(dumpBinaryDateTime(nil, t), DeepEquals, []byte{7, 225, 7, 1, 6, 12, 5, 3})
+
+	t = types.NewTime(types.ZeroCoreTime, mysql.TypeTimestamp, 0)
+	c.Assert(dumpBinaryDateTime(nil, t), DeepEquals, []byte{0})
+
+	d := types.NewDuration(1, 2, 3, 0, 0)
+	c.Assert(dumpBinaryTime(d.Duration), DeepEquals, []byte{8, 0, 0, 0, 0, 0, 1, 2, 3})
+
+	c.Assert(dumpBinaryTime(-d.Duration), DeepEquals, []byte{8, 1, 0, 0, 0, 0, 1, 2, 3})
+
+	c.Assert(dumpBinaryTime(0), DeepEquals, []byte{0})
 }
 
 func mustDecodeStr(c *C, b []byte) string {
@@ -42,12 +42,14 @@ import (
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
+	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/kvcache"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/sqlexec"
 	"go.uber.org/zap"
@@ -59,6 +61,7 @@ type Session interface {
 	Status() uint16                                               // Flag of current status, such as autocommit.
 	LastInsertID() uint64                                         // LastInsertID is the last inserted auto_increment ID.
 	AffectedRows() uint64                                         // Affected rows by latest executed stmt.
+	LastMessage() string                                          // LastMessage is the info message that may be generated by last command
 	Execute(context.Context, string) ([]sqlexec.RecordSet, error) // Execute a sql statement.
 	String() string                                               // String is used to debug.
 	CommitTxn(context.Context) error
@@ -73,6 +76,12 @@ type Session interface {
 	PrepareTxnCtx(context.Context)
 	// FieldList returns fields list of a table.
 	FieldList(tableName string) (fields []*ast.ResultField, err error)
+	// PrepareStmt executes prepare statement in binary protocol.
+	PrepareStmt(sql string) (stmtID uint32, paramCount int, fields []*ast.ResultField, err error)
+	// ExecutePreparedStmt executes a prepared statement.
+	ExecutePreparedStmt(ctx context.Context, stmtID uint32, param []types.Datum) (sqlexec.RecordSet, error)
+	// DropPreparedStmt removes the prepared statement with the given ID.
+	DropPreparedStmt(stmtID uint32) error
 }
 
 var (
@@ -122,6 +131,8 @@ type session struct {
 
 	// shared coprocessor client per session
 	client kv.Client
+
+	preparedPlanCache *kvcache.SimpleLRUCache
 }
 
 // DDLOwnerChecker returns s.ddlOwnerChecker.
@@ -148,6 +159,10 @@ func (s *session) AffectedRows() uint64 {
 	return s.sessionVars.StmtCtx.AffectedRows()
 }
 
+func (s *session) LastMessage() string {
+	return s.sessionVars.StmtCtx.GetMessage()
+}
+
 func (s *session) SetClientCapability(capability uint32) {
 	s.sessionVars.ClientCapability = capability
 }
@@ -236,26 +251,42 @@ func (s *session) doCommit(ctx context.Context) error {
 	return s.txn.Commit(sessionctx.SetCommitCtx(ctx, s))
 }
 
+func (s *session) doCommitWithRetry(ctx context.Context) error {
+	err := s.doCommit(ctx)
+	if err == nil || !isTxnRetryableError(err) {
+		return err
+	}
+	txnCtx := s.sessionVars.TxnCtx
+	if txnCtx.IsPessimistic || !s.isTxnRetryable() {
+		return err
+	}
+	if txnCtx.ForUpdate {
+		return ErrForUpdateCantRetry.GenWithStackByArgs(s.sessionVars.ConnectionID)
+	}
+	if !txnCtx.CouldRetry {
+		logutil.Logger(ctx).Warn("can not retry txn, the results have been returned to the client",
+			zap.String("txn", s.txn.GoString()),
+			zap.Error(err))
+		return err
+	}
+	logutil.Logger(ctx).Warn("commit failed, retry txn",
+		zap.String("txn", s.txn.GoString()),
+		zap.Error(err))
+	return s.retry(ctx, uint(s.sessionVars.RetryLimit))
+}
+
 func (s *session) commitTxn(ctx context.Context) error {
 	defer func() {
+		// The one shot isolation level is moved forward even if there is no transaction to commit,
+		// so `SET TRANSACTION ISOLATION LEVEL` takes effect on the next transaction.
+		s.sessionVars.SetTxnIsolationLevelOneShotStateForNextTxn()
 		s.txn.changeToInvalid()
 	}()
 	if !s.txn.Valid() {
 		// If the transaction is invalid, maybe it has already been rolled back by the client.
 		return nil
 	}
-	err := s.doCommit(ctx)
-
-	if isoLevelOneShot := &s.sessionVars.TxnIsolationLevelOneShot; isoLevelOneShot.State != 0 {
-		switch isoLevelOneShot.State {
-		case 1:
-			isoLevelOneShot.State = 2
-		case 2:
-			isoLevelOneShot.State = 0
-			isoLevelOneShot.Value = ""
-		}
-	}
-
+	err := s.doCommitWithRetry(ctx)
 	if err != nil {
 		logutil.Logger(ctx).Warn("commit failed",
 			zap.String("finished txn", s.txn.GoString()),
@@ -287,6 +318,85 @@ func (s *session) RollbackTxn(ctx context.Context) {
 	s.sessionVars.SetStatusFlag(mysql.ServerStatusInTrans, false)
 }
 
+// isTxnRetryable returns whether the transactions of the session could be retried
+// automatically when they fail to commit.
+func (s *session) isTxnRetryable() bool {
+	return !s.sessionVars.DisableTxnAutoRetry && s.sessionVars.RetryLimit > 0
+}
+
+func isTxnRetryableError(err error) bool {
+	return kv.IsTxnRetryableError(err) || domain.ErrInfoSchemaChanged.Equal(err)
+}
+
+// retry re-executes the statements of the failed transaction with a new start ts
+// and commits it again, it gives up after maxCnt attempts.
+func (s *session) retry(ctx context.Context, maxCnt uint) (err error) {
+	connID := s.sessionVars.ConnectionID
+	nh := GetHistory(s)
+	var retryCnt uint
+	defer func() {
+		if err != nil {
+			s.sessionVars.RetryInfo.FailedRetryCount++
+			s.RollbackTxn(ctx)
+		}
+		s.txn.changeToInvalid()
+		s.sessionVars.SetStatusFlag(mysql.ServerStatusInTrans, false)
+	}()
+
+	for {
+		s.PrepareTxnCtx(ctx)
+		s.sessionVars.RetryInfo.RetryCount++
+		for _, sr := range nh.history {
+			st := sr.st
+			s.sessionVars.StmtCtx = sr.stmtCtx
+			s.sessionVars.StmtCtx.ResetForRetry()
+			_, err = st.RebuildPlan(ctx)
+			if err != nil {
+				return err
+			}
+			logutil.Logger(ctx).Debug("retry txn", zap.Uint64("conn", connID),
+				zap.Uint("retry count", retryCnt),
+				zap.Stringer("sql", executor.FormatSQL(st.OriginText())))
+			_, err = st.Exec(ctx)
+			if err != nil {
+				s.StmtRollback()
+				break
+			}
+			err = s.StmtCommit()
+			if err != nil {
+				return err
+			}
+		}
+		if err == nil {
+			err = s.doCommit(ctx)
+			if err == nil {
+				break
+			}
+		}
+		if !isTxnRetryableError(err) {
+			logutil.Logger(ctx).Warn("sql", zap.Uint64("conn", connID),
+				zap.String("label", "retry"),
+				zap.Error(err))
+			return err
+		}
+		retryCnt++
+		if retryCnt >= maxCnt {
+			logutil.Logger(ctx).Warn("sql", zap.Uint64("conn", connID),
+				zap.String("label", "retry"),
+				zap.Uint("retry reached max count", retryCnt))
+			return err
+		}
+		logutil.Logger(ctx).Warn("sql", zap.Uint64("conn", connID),
+			zap.String("label", "retry"),
+			zap.Uint("retry count", retryCnt),
+			zap.Error(err))
+		kv.BackOff(retryCnt)
+		s.txn.changeToInvalid()
+		s.sessionVars.SetStatusFlag(mysql.ServerStatusInTrans, false)
+	}
+	return err
+}
+
 func (s *session) GetClient() kv.Client {
 	return s.client
 }
@@ -548,6 +658,7 @@ func (s *session) executeStatement(ctx context.Context, connID uint64, stmtNode
 		recordSet = &multiQueryNoDelayRecordSet{
 			affectedRows: s.AffectedRows(),
 			warnCount:    s.sessionVars.StmtCtx.WarningCount(),
+			lastMessage:  s.LastMessage(),
 			lastInsertID: s.sessionVars.StmtCtx.LastInsertID,
 			status:       s.sessionVars.Status,
 		}
@@ -628,6 +739,59 @@ func (s *session) execute(ctx context.Context, sql string) (recordSets []sqlexec
 	return recordSets, nil
 }
 
+// PrepareStmt is used for executing prepare statement in binary protocol
+func (s *session) PrepareStmt(sql string) (stmtID uint32, paramCount int, fields []*ast.ResultField, err error) {
+	if s.sessionVars.TxnCtx.InfoSchema == nil {
+		// We don't need to create a transaction for prepare statement, just get information schema will do.
+		s.sessionVars.TxnCtx.InfoSchema = domain.GetDomain(s).InfoSchema()
+	}
+	err = s.loadCommonGlobalVariablesIfNeeded()
+	if err != nil {
+		return
+	}
+
+	ctx := context.Background()
+	inTxn := s.GetSessionVars().InTxn()
+	// NewPrepareExec may need startTS to build the executor, for example prepare statement has subquery in int.
+	// So we have to call PrepareTxnCtx here.
+	s.PrepareTxnCtx(ctx)
+	prepareExec := executor.NewPrepareExec(s, infoschema.GetInfoSchema(s), sql)
+	err = prepareExec.Next(ctx, nil)
+	if err != nil {
+		return
+	}
+	if !inTxn {
+		// We could start a transaction to build the prepare executor before, we should rollback it here.
+		s.RollbackTxn(ctx)
+	}
+	return prepareExec.ID, prepareExec.ParamCount, prepareExec.Fields, nil
+}
+
+// ExecutePreparedStmt executes a prepared statement.
+func (s *session) ExecutePreparedStmt(ctx context.Context, stmtID uint32, args []types.Datum) (sqlexec.RecordSet, error) {
+	s.PrepareTxnCtx(ctx)
+	s.sessionVars.StartTime = time.Now()
+	st, err := executor.CompileExecutePreparedStmt(ctx, s, stmtID, args)
+	if err != nil {
+		return nil, err
+	}
+	return runStmt(ctx, s, st)
+}
+
+// DropPreparedStmt removes the prepared statement with the given ID.
+func (s *session) DropPreparedStmt(stmtID uint32) error {
+	vars := s.sessionVars
+	prepared, ok := vars.PreparedStmts[stmtID]
+	if !ok {
+		return plannercore.ErrStmtNotFound
+	}
+	if prepared.UseCache {
+		s.PreparedPlanCache().Delete(plannercore.NewPSTMTPlanCacheKey(vars, stmtID, prepared.SchemaVersion))
+	}
+	vars.RemovePreparedStmt(stmtID)
+	return nil
+}
+
 // rollbackOnError makes sure the next statement starts a new transaction with the latest InfoSchema.
 func (s *session) rollbackOnError(ctx context.Context) {
 	if !s.sessionVars.InTxn() {
@@ -658,6 +822,13 @@ func (s *session) Txn(active bool) (kv.Transaction, error) {
 		if s.sessionVars.GetReplicaRead().IsFollowerRead() {
 			s.txn.SetOption(kv.ReplicaRead, kv.ReplicaReadFollower)
 		}
+		if s.sessionVars.TxnCtx.IsPessimistic {
+			s.txn.SetOption(kv.Pessimistic, true)
+		}
+		s.sessionVars.TxnCtx.CouldRetry = s.isTxnRetryable()
+		if s.sessionVars.IsReadCommittedTxn() {
+			s.txn.SetOption(kv.IsolationLevel, kv.RC)
+		}
 	}
 	return &s.txn, nil
 }
@@ -691,6 +862,11 @@ func (s *session) NewTxn(ctx context.Context) error {
 		SchemaVersion: is.SchemaMetaVersion(),
 		CreateTime:    time.Now(),
 		StartTS:       txn.StartTS(),
+		CouldRetry:    s.isTxnRetryable(),
+		Isolation:     s.sessionVars.TxnIsolationLevel(),
+	}
+	if s.sessionVars.IsReadCommittedTxn() {
+		txn.SetOption(kv.IsolationLevel, kv.RC)
 	}
 	return nil
 }
@@ -718,6 +894,9 @@ func (s *session) ClearValue(key fmt.Stringer) {
 func (s *session) Close() {
 	ctx := context.TODO()
 	s.RollbackTxn(ctx)
+	if s.sessionVars != nil {
+		s.sessionVars.WithdrawAllPreparedStmt()
+	}
 }
 
 // GetSessionVars implements the context.Context interface.
@@ -725,6 +904,14 @@ func (s *session) GetSessionVars() *variable.SessionVars {
 	return s.sessionVars
 }
 
+// PreparedPlanCache implements the sessionctx.Context interface.
+func (s *session) PreparedPlanCache() *kvcache.SimpleLRUCache {
+	if s.preparedPlanCache == nil {
+		s.preparedPlanCache = kvcache.NewSimpleLRUCache(plannercore.PreparedPlanCacheCapacity)
+	}
+	return s.preparedPlanCache
+}
+
 // CreateSession4Test creates a new session environment for test.
 func CreateSession4Test(store kv.Storage) (Session, error) {
 	s, err := CreateSession(store)
@@ -1026,6 +1213,10 @@ func (s *session) PrepareTxnCtx(ctx context.Context) {
 		InfoSchema:    is,
 		SchemaVersion: is.SchemaMetaVersion(),
 		CreateTime:    time.Now(),
+		Isolation:     s.sessionVars.TxnIsolationLevel(),
+	}
+	if !s.sessionVars.IsAutocommit() {
+		s.sessionVars.TxnCtx.IsPessimistic = s.sessionVars.TxnMode == ast.Pessimistic
 	}
 }
 
@@ -1079,6 +1270,7 @@ type multiQueryNoDelayRecordSet struct {
 	affectedRows uint64
 	status       uint16
 	warnCount    uint16
+	lastMessage  string
 	lastInsertID uint64
 }
 
@@ -1090,6 +1282,10 @@ func (c *multiQueryNoDelayRecordSet) AffectedRows() uint64 {
 	return c.affectedRows
 }
 
+func (c *multiQueryNoDelayRecordSet) LastMessage() string {
+	return c.lastMessage
+}
+
 func (c *multiQueryNoDelayRecordSet) WarnCount() uint16 {
 	return c.warnCount
 }
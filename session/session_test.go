@@ -31,6 +31,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/store/mockstore/mocktikv"
+	"github.com/pingcap/tidb/store/tikv"
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/sqlexec"
@@ -809,3 +810,166 @@ func (s *testSessionSuite2) TestReplicaRead(c *C) {
 	tk.MustExec("set @@tidb_replica_read = 'leader';")
 	c.Assert(tk.Se.GetSessionVars().GetReplicaRead(), Equals, kv.ReplicaReadLeader)
 }
+
+func (s *testSessionSuite2) TestPessimisticTxn(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists pessimistic")
+	tk.MustExec("create table pessimistic (k int primary key, v int)")
+	tk.MustExec("insert into pessimistic values (1, 1), (2, 2)")
+
+	// The transaction mode of BEGIN overrides tidb_txn_mode.
+	tk.MustExec("set @@tidb_txn_mode = 'pessimistic'")
+	tk.MustExec("begin")
+	c.Assert(tk.Se.GetSessionVars().TxnCtx.IsPessimistic, IsTrue)
+	tk.MustExec("rollback")
+	tk.MustExec("begin optimistic")
+	c.Assert(tk.Se.GetSessionVars().TxnCtx.IsPessimistic, IsFalse)
+	tk.MustExec("rollback")
+	tk.MustExec("set @@tidb_txn_mode = ''")
+
+	// DML in a pessimistic transaction works on the latest data, so the commit doesn't conflict.
+	tk.MustExec("begin pessimistic")
+	tk1.MustExec("update pessimistic set v = v + 1 where k = 1")
+	tk.MustExec("update pessimistic set v = v + 1 where k = 1")
+	tk.MustExec("commit")
+	tk.MustQuery("select v from pessimistic where k = 1").Check(testkit.Rows("3"))
+
+	// The rows locked by SELECT FOR UPDATE can't be locked by other transactions.
+	tk.MustExec("begin pessimistic")
+	tk.MustQuery("select * from pessimistic where k = 2 for update").Check(testkit.Rows("2 2"))
+	tk1.MustExec("begin pessimistic")
+	err := tk1.ExecToErr("select * from pessimistic where k = 2 for update nowait")
+	c.Assert(tikv.ErrLockAcquireFailAndNoWaitSet.Equal(err), IsTrue, Commentf("err %v", err))
+	tk1.MustExec("set @@innodb_lock_wait_timeout = 1")
+	err = tk1.ExecToErr("update pessimistic set v = v + 1 where k = 2")
+	c.Assert(tikv.ErrLockWaitTimeout.Equal(err), IsTrue, Commentf("err %v", err))
+	// Other rows are not locked.
+	tk1.MustExec("update pessimistic set v = v + 1 where k = 1")
+	tk.MustExec("update pessimistic set v = v + 1 where k = 2")
+	tk.MustExec("commit")
+	tk1.MustExec("update pessimistic set v = v + 1 where k = 2")
+	tk1.MustExec("commit")
+	tk.MustQuery("select * from pessimistic").Check(testkit.Rows("1 4", "2 4"))
+}
+
+func (s *testSessionSuite2) TestPessimisticDeadlock(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists deadlock")
+	tk.MustExec("create table deadlock (k int primary key, v int)")
+	tk.MustExec("insert into deadlock values (1, 1), (2, 2)")
+
+	tk.MustExec("begin pessimistic")
+	tk1.MustExec("begin pessimistic")
+	tk.MustExec("update deadlock set v = v + 1 where k = 1")
+	tk1.MustExec("update deadlock set v = v + 1 where k = 2")
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- tk.ExecToErr("update deadlock set v = v + 1 where k = 2")
+	}()
+	// Wait for tk to be blocked by the lock of tk1.
+	time.Sleep(100 * time.Millisecond)
+	err := tk1.ExecToErr("update deadlock set v = v + 1 where k = 1")
+	c.Assert(tikv.ErrLockDeadlock.Equal(err), IsTrue, Commentf("err %v", err))
+	// The deadlock victim is rolled back, so the blocked transaction gets the lock.
+	c.Assert(tk1.Se.GetSessionVars().InTxn(), IsFalse)
+	c.Assert(<-errCh, IsNil)
+	tk.MustExec("commit")
+	tk.MustQuery("select * from deadlock").Check(testkit.Rows("1 2", "2 3"))
+}
+
+func (s *testSessionSuite2) TestTxnRetry(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists retry")
+	tk.MustExec("create table retry (k int primary key, v int)")
+	tk.MustExec("insert into retry values (1, 1), (2, 2)")
+
+	// The transaction is not retried by default.
+	tk.MustExec("begin")
+	tk.MustExec("update retry set v = v + 1 where k = 1")
+	tk1.MustExec("update retry set v = v + 1 where k = 1")
+	err := tk.ExecToErr("commit")
+	c.Assert(kv.ErrWriteConflict.Equal(err), IsTrue, Commentf("err %v", err))
+	tk.MustQuery("select v from retry where k = 1").Check(testkit.Rows("2"))
+
+	// The statements are re-executed with a new start ts.
+	tk.MustExec("set @@tidb_disable_txn_auto_retry = 0")
+	tk.MustExec("begin")
+	tk.MustExec("update retry set v = v + 1 where k = 1")
+	tk1.MustExec("update retry set v = v + 1 where k = 1")
+	tk.MustExec("commit")
+	tk.MustQuery("select v from retry where k = 1").Check(testkit.Rows("4"))
+	tk.MustQuery("show status where variable_name like 'Txn%'").Check(testkit.Rows("Txn_failed_retry_count 0", "Txn_retry_count 1"))
+
+	// The transaction which has returned results to the client is not retried.
+	tk.MustExec("begin")
+	tk.MustQuery("select v from retry where k = 2").Check(testkit.Rows("2"))
+	tk.MustExec("update retry set v = v + 1 where k = 2")
+	tk1.MustExec("update retry set v = v + 1 where k = 2")
+	err = tk.ExecToErr("commit")
+	c.Assert(kv.ErrWriteConflict.Equal(err), IsTrue, Commentf("err %v", err))
+
+	tk.MustExec("begin")
+	tk.MustQuery("select v from retry where k = 2 for update").Check(testkit.Rows("3"))
+	tk1.MustExec("update retry set v = v + 1 where k = 2")
+	err = tk.ExecToErr("commit")
+	c.Assert(session.ErrForUpdateCantRetry.Equal(err), IsTrue, Commentf("err %v", err))
+
+	// The retry fails if the re-executed statement fails.
+	tk.MustExec("begin")
+	tk.MustExec("insert into retry values (3, 3)")
+	tk1.MustExec("insert into retry values (3, 4)")
+	err = tk.ExecToErr("commit")
+	c.Assert(kv.ErrKeyExists.Equal(err), IsTrue, Commentf("err %v", err))
+	tk.MustQuery("show status where variable_name like 'Txn%'").Check(testkit.Rows("Txn_failed_retry_count 1", "Txn_retry_count 2"))
+	tk.MustQuery("select * from retry").Check(testkit.Rows("1 4", "2 4", "3 4"))
+
+	// The retry is disabled when tidb_retry_limit is 0.
+	tk.MustExec("set @@tidb_retry_limit = 0")
+	tk.MustExec("begin")
+	tk.MustExec("update retry set v = v + 1 where k = 1")
+	tk1.MustExec("update retry set v = v + 1 where k = 1")
+	err = tk.ExecToErr("commit")
+	c.Assert(kv.ErrWriteConflict.Equal(err), IsTrue, Commentf("err %v", err))
+}
+
+func (s *testSessionSuite2) TestReadCommitted(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists rc")
+	tk.MustExec("create table rc (k int primary key, v int)")
+	tk.MustExec("insert into rc values (1, 1), (2, 2)")
+
+	// Every statement of a repeatable read transaction reads the same snapshot.
+	tk.MustExec("begin")
+	tk.MustQuery("select v from rc where k = 1").Check(testkit.Rows("1"))
+	tk1.MustExec("update rc set v = 10 where k = 1")
+	tk.MustQuery("select v from rc where k = 1").Check(testkit.Rows("1"))
+	tk.MustQuery("select sum(v) from rc").Check(testkit.Rows("3"))
+	tk.MustExec("commit")
+
+	// Every statement of a read committed transaction reads the latest committed data.
+	tk.MustExec("set tx_isolation = 'READ-COMMITTED'")
+	tk.MustExec("begin")
+	tk.MustQuery("select v from rc where k = 1").Check(testkit.Rows("10"))
+	tk1.MustExec("update rc set v = 20 where k = 1")
+	tk.MustQuery("select v from rc where k = 1").Check(testkit.Rows("20"))
+	tk.MustQuery("select sum(v) from rc").Check(testkit.Rows("22"))
+	tk.MustExec("commit")
+	tk.MustExec("set tx_isolation = 'REPEATABLE-READ'")
+
+	// SET TRANSACTION only affects the next transaction.
+	tk.MustExec("set transaction isolation level read committed")
+	tk.MustExec("begin")
+	tk.MustQuery("select v from rc where k = 2").Check(testkit.Rows("2"))
+	tk1.MustExec("update rc set v = 30 where k = 2")
+	tk.MustQuery("select v from rc where k = 2").Check(testkit.Rows("30"))
+	tk.MustExec("commit")
+	tk.MustExec("begin")
+	tk.MustQuery("select v from rc where k = 2").Check(testkit.Rows("30"))
+	tk1.MustExec("update rc set v = 40 where k = 2")
+	tk.MustQuery("select v from rc where k = 2").Check(testkit.Rows("30"))
+	tk.MustExec("commit")
+}
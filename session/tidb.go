@@ -33,6 +33,7 @@ import (
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/store/tikv"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/logutil"
@@ -170,6 +171,10 @@ func finishStmt(ctx context.Context, sctx sessionctx.Context, se *session, sessV
 		if !sessVars.InTxn() {
 			logutil.BgLogger().Info("rollbackTxn for ddl/autocommit failed")
 			se.RollbackTxn(ctx)
+		} else if sessVars.TxnCtx.IsPessimistic && tikv.ErrLockDeadlock.Equal(meetsErr) {
+			// The transaction chosen as the deadlock victim is rolled back to release its locks.
+			logutil.BgLogger().Info("rollbackTxn for deadlock", zap.Uint64("txn", sessVars.TxnCtx.StartTS))
+			se.RollbackTxn(ctx)
 		}
 		return meetsErr
 	}
@@ -218,7 +223,16 @@ func runStmt(ctx context.Context, sctx sessionctx.Context, s sqlexec.Statement)
 	}
 	rs, err = s.Exec(ctx)
 	sessVars.TxnCtx.StatementCount++
+	if rs != nil && sessVars.InTxn() {
+		// The results are returned to the client, retrying the transaction with
+		// a new start ts may make them inconsistent with the committed data.
+		sessVars.TxnCtx.CouldRetry = false
+	}
 	if !s.IsReadOnly() {
+		// Record the statement for retrying the transaction when it fails to commit.
+		if err == nil && sessVars.TxnCtx.CouldRetry && !sessVars.TxnCtx.IsPessimistic {
+			GetHistory(sctx).Add(s, sessVars.StmtCtx)
+		}
 		// Handle the stmt commit/rollback.
 		if txn, err1 := sctx.Txn(false); err1 == nil {
 			if txn.Valid() {
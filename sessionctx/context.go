@@ -20,6 +20,7 @@ import (
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/owner"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/kvcache"
 )
 
 // Context is an interface for transaction and executive args environment.
@@ -49,6 +50,9 @@ type Context interface {
 
 	GetSessionVars() *variable.SessionVars
 
+	// PreparedPlanCache returns the cache of the physical plans of the prepared statements.
+	PreparedPlanCache() *kvcache.SimpleLRUCache
+
 	// RefreshTxnCtx commits old transaction without retry,
 	// and creates a new transaction.
 	// now just for load data and batch insert.
@@ -48,6 +48,7 @@ type StatementContext struct {
 	// If IsDDLJobInQueue is true, it means the DDL job is in the queue of storage, and it can be handled by the DDL worker.
 	IsDDLJobInQueue        bool
 	InInsertStmt           bool
+	InUpdateStmt           bool
 	InDeleteStmt           bool
 	InSelectStmt           bool
 	InExplainStmt          bool
@@ -62,6 +63,9 @@ type StatementContext struct {
 	BatchCheck             bool
 	InNullRejectCheck      bool
 	AllowInvalidDate       bool
+	// UseCache is true when the plan of the statement may be put into the
+	// prepared plan cache, so the planner must not depend on parameter values.
+	UseCache bool
 	// CastStrToIntStrict is used to control the way we cast float format string to int.
 	// If ConvertStrToIntStrict is false, we convert it to a valid float string first,
 	// then cast the float string to int string. Otherwise, we cast string to integer
@@ -93,6 +97,7 @@ type StatementContext struct {
 		copied  uint64
 		touched uint64
 
+		message    string
 		warnings   []SQLWarn
 		errorCount uint16
 	}
@@ -236,6 +241,21 @@ func (sc *StatementContext) AddTouchedRows(rows uint64) {
 	sc.mu.Unlock()
 }
 
+// GetMessage returns the extra message of the last executed command, if there is no message, it returns empty string
+func (sc *StatementContext) GetMessage() string {
+	sc.mu.Lock()
+	msg := sc.mu.message
+	sc.mu.Unlock()
+	return msg
+}
+
+// SetMessage sets the info message generated by some commands
+func (sc *StatementContext) SetMessage(msg string) {
+	sc.mu.Lock()
+	sc.mu.message = msg
+	sc.mu.Unlock()
+}
+
 // GetWarnings gets warnings.
 func (sc *StatementContext) GetWarnings() []SQLWarn {
 	sc.mu.Lock()
@@ -376,7 +396,7 @@ func (sc *StatementContext) PushDownFlags() uint64 {
 	var flags uint64
 	if sc.InInsertStmt {
 		flags |= model.FlagInInsertStmt
-	} else if sc.InDeleteStmt {
+	} else if sc.InUpdateStmt || sc.InDeleteStmt {
 		flags |= model.FlagInUpdateOrDeleteStmt
 	} else if sc.InSelectStmt {
 		flags |= model.FlagInSelectStmt
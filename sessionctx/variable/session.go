@@ -25,6 +25,8 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta/autoid"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
@@ -38,6 +40,9 @@ var (
 	ErrSnapshotTooOld = terror.ClassVariable.New(mysql.ErrSnapshotTooOld, mysql.MySQLErrName[mysql.ErrSnapshotTooOld])
 )
 
+// preparedStmtCount is the number of prepared statements of all the sessions.
+var preparedStmtCount int64
+
 // TransactionContext is used to store variables that has transaction scope.
 type TransactionContext struct {
 	forUpdateTS   uint64
@@ -51,6 +56,14 @@ type TransactionContext struct {
 
 	CreateTime     time.Time
 	StatementCount int
+	IsPessimistic  bool
+	// CouldRetry indicates whether the transaction could be retried when it fails to commit.
+	CouldRetry bool
+	// ForUpdate is set when the transaction has locked rows by `SELECT ... FOR UPDATE`,
+	// the results were returned to the client so the transaction can't be retried.
+	ForUpdate bool
+	// Isolation is the isolation level of the transaction, it's fixed when the transaction context is created.
+	Isolation string
 }
 
 // UpdateDeltaForTable updates the delta info for some table.
@@ -98,6 +111,14 @@ func (tc *TransactionContext) SetForUpdateTS(forUpdateTS uint64) {
 	}
 }
 
+// RetryInfo saves the transaction retry information of a session.
+type RetryInfo struct {
+	// RetryCount is the number of times the transactions of the session were retried.
+	RetryCount uint64
+	// FailedRetryCount is the number of retried transactions which still failed to commit.
+	FailedRetryCount uint64
+}
+
 // WriteStmtBufs can be used by insert/replace/delete/update statement.
 // TODO: use a common memory pool to replace this.
 type WriteStmtBufs struct {
@@ -165,6 +186,9 @@ type SessionVars struct {
 	// ConnectionID is the connection id of the current session.
 	ConnectionID uint64
 
+	// User is the user identity with which the session login.
+	User *auth.UserIdentity
+
 	// PlanID is the unique id of logical and physical plan.
 	PlanID int
 
@@ -192,6 +216,18 @@ type SessionVars struct {
 	// StmtCtx holds variables for current executing statement.
 	StmtCtx *stmtctx.StatementContext
 
+	// PreparedStmts stores prepared statement.
+	PreparedStmts        map[uint32]*ast.Prepared
+	PreparedStmtNameToID map[string]uint32
+	// preparedStmtID is id of prepared statement.
+	preparedStmtID uint32
+	// PreparedParams params for prepared statements
+	PreparedParams []types.Datum
+	// FoundInPlanCache indicates whether the plan of the current statement is found in the prepared plan cache.
+	FoundInPlanCache bool
+	// PrevFoundInPlanCache indicates whether the plan of the last statement is found in the prepared plan cache.
+	PrevFoundInPlanCache bool
+
 	// AllowAggPushDown can be set to false to forbid aggregation push down.
 	AllowAggPushDown bool
 
@@ -282,6 +318,10 @@ type SessionVars struct {
 	// See https://dev.mysql.com/doc/refman/5.7/en/server-system-variables.html#sysvar_max_execution_time
 	MaxExecutionTime uint64
 
+	// CTEMaxRecursionDepth is the maximum number of iterations of a recursive common table expression.
+	// See https://dev.mysql.com/doc/refman/8.0/en/server-system-variables.html#sysvar_cte_max_recursion_depth
+	CTEMaxRecursionDepth int
+
 	// Killed is a flag to indicate that this query is killed.
 	Killed uint32
 
@@ -306,6 +346,21 @@ type SessionVars struct {
 	// AllowRemoveAutoInc indicates whether a user can drop the auto_increment column attribute or not.
 	AllowRemoveAutoInc bool
 
+	// TxnMode indicates should be pessimistic or optimistic.
+	TxnMode string
+
+	// LockWaitTimeout is the duration waiting for pessimistic lock in milliseconds.
+	LockWaitTimeout int64
+
+	// RetryLimit is the maximum number of retries when committing a transaction.
+	RetryLimit int64
+
+	// DisableTxnAutoRetry disables the transaction auto retry.
+	DisableTxnAutoRetry bool
+
+	// RetryInfo records the transaction retries of the session.
+	RetryInfo RetryInfo
+
 	// Unexported fields should be accessed and set through interfaces like GetReplicaRead() and SetReplicaRead().
 
 	// allowInSubqToJoinAndAgg can be set to false to forbid rewriting the semi join to inner join with agg.
@@ -340,6 +395,9 @@ func NewSessionVars() *SessionVars {
 	vars := &SessionVars{
 		Users:                       make(map[string]string),
 		systems:                     make(map[string]string),
+		PreparedStmts:               make(map[uint32]*ast.Prepared),
+		PreparedStmtNameToID:        make(map[string]uint32),
+		PreparedParams:              make([]types.Datum, 0, 10),
 		TxnCtx:                      &TransactionContext{},
 		KVVars:                      kv.NewVariables(),
 		StrictSQLMode:               true,
@@ -368,6 +426,11 @@ func NewSessionVars() *SessionVars {
 		EnableNoopFuncs:             DefTiDBEnableNoopFuncs,
 		replicaRead:                 kv.ReplicaReadLeader,
 		AllowRemoveAutoInc:          DefTiDBAllowRemoveAutoInc,
+		CTEMaxRecursionDepth:        DefCTEMaxRecursionDepth,
+		TxnMode:                     DefTiDBTxnMode,
+		LockWaitTimeout:             DefInnodbLockWaitTimeout * 1000,
+		RetryLimit:                  DefTiDBRetryLimit,
+		DisableTxnAutoRetry:         DefTiDBDisableTxnAutoRetry,
 	}
 	vars.Concurrency = Concurrency{
 		IndexLookupConcurrency:     DefIndexLookupConcurrency,
@@ -428,6 +491,48 @@ func (s *SessionVars) CleanBuffers() {
 	s.GetWriteStmtBufs().clean()
 }
 
+// GetNextPreparedStmtID generates and returns the next session scope prepared statement id.
+func (s *SessionVars) GetNextPreparedStmtID() uint32 {
+	s.preparedStmtID++
+	return s.preparedStmtID
+}
+
+// AddPreparedStmt adds prepareStmt to current session and count in global.
+func (s *SessionVars) AddPreparedStmt(stmtID uint32, stmt *ast.Prepared) error {
+	if _, exists := s.PreparedStmts[stmtID]; !exists {
+		valStr, _ := s.GetSystemVar(MaxPreparedStmtCount)
+		maxPreparedStmtCount, err := strconv.ParseInt(valStr, 10, 64)
+		if err != nil {
+			maxPreparedStmtCount = DefMaxPreparedStmtCount
+		}
+		newPreparedStmtCount := atomic.AddInt64(&preparedStmtCount, 1)
+		if maxPreparedStmtCount >= 0 && newPreparedStmtCount > maxPreparedStmtCount {
+			atomic.AddInt64(&preparedStmtCount, -1)
+			return ErrMaxPreparedStmtCountReached.GenWithStackByArgs(maxPreparedStmtCount)
+		}
+	}
+	s.PreparedStmts[stmtID] = stmt
+	return nil
+}
+
+// RemovePreparedStmt removes preparedStmt from current session and decrease count in global.
+func (s *SessionVars) RemovePreparedStmt(stmtID uint32) {
+	if _, exists := s.PreparedStmts[stmtID]; !exists {
+		return
+	}
+	delete(s.PreparedStmts, stmtID)
+	atomic.AddInt64(&preparedStmtCount, -1)
+}
+
+// WithdrawAllPreparedStmt remove all preparedStmt in current session and decrease count in global.
+func (s *SessionVars) WithdrawAllPreparedStmt() {
+	psCount := len(s.PreparedStmts)
+	if psCount == 0 {
+		return
+	}
+	atomic.AddInt64(&preparedStmtCount, -int64(psCount))
+}
+
 // AllocPlanColumnID allocates column id for plan.
 func (s *SessionVars) AllocPlanColumnID() int64 {
 	s.PlanColumnID++
@@ -515,6 +620,35 @@ func (s *SessionVars) setDDLReorgPriority(val string) {
 	}
 }
 
+// IsReadCommittedTxn returns whether the current transaction runs in the READ COMMITTED isolation level.
+func (s *SessionVars) IsReadCommittedTxn() bool {
+	return s.TxnCtx.Isolation == ast.ReadCommitted
+}
+
+// TxnIsolationLevel returns the isolation level for a new transaction context. The isolation level
+// set by `SET TRANSACTION ISOLATION LEVEL` takes precedence over tx_isolation.
+func (s *SessionVars) TxnIsolationLevel() string {
+	if s.TxnIsolationLevelOneShot.State == 2 {
+		return s.TxnIsolationLevelOneShot.Value
+	}
+	isolation, _ := s.GetSystemVar(TxnIsolation)
+	return isolation
+}
+
+// SetTxnIsolationLevelOneShotStateForNextTxn is called after a transaction is finished, the isolation
+// level set by `SET TRANSACTION ISOLATION LEVEL` takes effect on the next transaction only.
+func (s *SessionVars) SetTxnIsolationLevelOneShotStateForNextTxn() {
+	if isoLevelOneShot := &s.TxnIsolationLevelOneShot; isoLevelOneShot.State != 0 {
+		switch isoLevelOneShot.State {
+		case 1:
+			isoLevelOneShot.State = 2
+		case 2:
+			isoLevelOneShot.State = 0
+			isoLevelOneShot.Value = ""
+		}
+	}
+}
+
 // SetSystemVar sets the value of a system variable.
 func (s *SessionVars) SetSystemVar(name string, val string) error {
 	switch name {
@@ -549,6 +683,12 @@ func (s *SessionVars) SetSystemVar(name string, val string) error {
 		s.StrictSQLMode = sqlMode.HasStrictMode()
 		s.SQLMode = sqlMode
 		s.SetStatusFlag(mysql.ServerStatusNoBackslashEscaped, sqlMode.HasNoBackslashEscapesMode())
+	case TimeZone:
+		tz, err := parseTimeZone(val)
+		if err != nil {
+			return err
+		}
+		s.TimeZone = tz
 	case AutoCommit:
 		isAutocommit := TiDBOptOn(val)
 		s.SetStatusFlag(mysql.ServerStatusAutocommit, isAutocommit)
@@ -558,6 +698,8 @@ func (s *SessionVars) SetSystemVar(name string, val string) error {
 	case MaxExecutionTime:
 		timeoutMS := tidbOptPositiveInt32(val, 0)
 		s.MaxExecutionTime = uint64(timeoutMS)
+	case CTEMaxRecursionDepth:
+		s.CTEMaxRecursionDepth = int(tidbOptInt64(val, DefCTEMaxRecursionDepth))
 	case TiDBSkipUTF8Check:
 		s.SkipUTF8Check = TiDBOptOn(val)
 	case TiDBOptAggPushDown:
@@ -612,7 +754,7 @@ func (s *SessionVars) SetSystemVar(name string, val string) error {
 		s.KVVars.BackOffWeight = tidbOptPositiveInt32(val, kv.DefBackOffWeight)
 	case TiDBConstraintCheckInPlace:
 		s.ConstraintCheckInPlace = TiDBOptOn(val)
-	case TiDBCurrentTS, TiDBConfig:
+	case TiDBCurrentTS, TiDBConfig, TiDBFoundInPlanCache:
 		return ErrReadOnly
 	case TiDBMaxChunkSize:
 		s.MaxChunkSize = tidbOptPositiveInt32(val, DefMaxChunkSize)
@@ -646,6 +788,15 @@ func (s *SessionVars) SetSystemVar(name string, val string) error {
 		}
 	case TiDBAllowRemoveAutoInc:
 		s.AllowRemoveAutoInc = TiDBOptOn(val)
+	case TiDBTxnMode:
+		s.TxnMode = strings.ToUpper(val)
+	case InnodbLockWaitTimeout:
+		lockWaitSec := tidbOptInt64(val, DefInnodbLockWaitTimeout)
+		s.LockWaitTimeout = lockWaitSec * 1000
+	case TiDBRetryLimit:
+		s.RetryLimit = tidbOptInt64(val, DefTiDBRetryLimit)
+	case TiDBDisableTxnAutoRetry:
+		s.DisableTxnAutoRetry = TiDBOptOn(val)
 	// It's a global variable, but it also wants to be cached in server.
 	case TiDBMaxDeltaSchemaCount:
 		SetMaxDeltaSchemaCount(tidbOptInt64(val, DefTiDBMaxDeltaSchemaCount))
@@ -676,6 +827,7 @@ const (
 	TransactionIsolation = "transaction_isolation"
 	TxnIsolationOneShot  = "tx_isolation_one_shot"
 	MaxExecutionTime     = "max_execution_time"
+	CTEMaxRecursionDepth = "cte_max_recursion_depth"
 )
 
 // these variables are useless for TiDB, but still need to validate their values for some compatible issues.
@@ -163,6 +163,33 @@ func (s defaultStatusStat) Stats(vars *SessionVars) (map[string]interface{}, err
 	return statusVars, nil
 }
 
+// Status variables of the transaction retries, they are session scope.
+const (
+	// TxnRetryCount is the number of times the transactions of the session were retried.
+	TxnRetryCount = "Txn_retry_count"
+	// TxnFailedRetryCount is the number of retried transactions of the session which still failed to commit.
+	TxnFailedRetryCount = "Txn_failed_retry_count"
+)
+
+type txnRetryStat struct {
+}
+
+func (s txnRetryStat) GetScope(status string) ScopeFlag {
+	return ScopeSession
+}
+
+func (s txnRetryStat) Stats(vars *SessionVars) (map[string]interface{}, error) {
+	var info RetryInfo
+	// `vars` may be nil in unit tests.
+	if vars != nil {
+		info = vars.RetryInfo
+	}
+	return map[string]interface{}{
+		TxnRetryCount:       info.RetryCount,
+		TxnFailedRetryCount: info.FailedRetryCount,
+	}, nil
+}
+
 func init() {
 	var ciphersBuffer bytes.Buffer
 	for _, v := range tlsCiphers {
@@ -173,4 +200,5 @@ func init() {
 
 	var stat defaultStatusStat
 	RegisterStatistics(stat)
+	RegisterStatistics(txnRetryStat{})
 }
@@ -50,6 +50,10 @@ const (
 	// tidb_config is a read-only variable that shows the config of the current server.
 	TiDBConfig = "tidb_config"
 
+	// TiDBFoundInPlanCache indicates whether the plan of the last statement was found in the prepared plan cache.
+	// It is read-only.
+	TiDBFoundInPlanCache = "last_plan_from_cache"
+
 	// tidb_general_log is used to log every query in the server in info level.
 	TiDBGeneralLog = "tidb_general_log"
 
@@ -211,6 +215,18 @@ const (
 
 	// TiDBEnableNoopFuncs set true will enable using fake funcs(like get_lock release_lock)
 	TiDBEnableNoopFuncs = "tidb_enable_noop_functions"
+
+	// tidb_txn_mode is used to control the transaction behavior.
+	// It can be "pessimistic" or "optimistic", the empty value means optimistic.
+	TiDBTxnMode = "tidb_txn_mode"
+
+	// tidb_retry_limit is the maximum number of retries when committing a transaction.
+	TiDBRetryLimit = "tidb_retry_limit"
+
+	// tidb_disable_txn_auto_retry disables transaction auto retry.
+	// When it is OFF, an optimistic transaction which fails to commit for write conflicts
+	// replays its statements with a new start ts and commits again.
+	TiDBDisableTxnAutoRetry = "tidb_disable_txn_auto_retry"
 )
 
 // Default TiDB system variable values.
@@ -264,6 +280,9 @@ const (
 	DefTiDBEnableNoopFuncs           = false
 	DefTiDBAllowRemoveAutoInc        = false
 	DefInnodbLockWaitTimeout         = 50 // 50s
+	DefCTEMaxRecursionDepth          = 1000
+	DefTiDBFoundInPlanCache          = false
+	DefTiDBTxnMode                   = ""
 )
 
 // Process global variables.
@@ -24,6 +24,7 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/types"
 )
 
@@ -109,6 +110,8 @@ func GetSessionOnlySysVars(s *SessionVars, key string) (string, bool, error) {
 	switch sysVar.Name {
 	case TiDBCurrentTS:
 		return fmt.Sprintf("%d", s.TxnCtx.StartTS), true, nil
+	case TiDBFoundInPlanCache:
+		return BoolToIntStr(s.PrevFoundInPlanCache), true, nil
 	case TiDBGeneralLog:
 		return fmt.Sprintf("%d", atomic.LoadUint32(&ProcessGeneralLog)), true, nil
 	case TiDBConfig:
@@ -363,7 +366,7 @@ func ValidateSetSystemVar(vars *SessionVars, name string, value string) (string,
 		}
 		return value, ErrWrongValueForVar.GenWithStackByArgs(name, value)
 	case TiDBSkipUTF8Check, TiDBOptAggPushDown, TiDBOptInSubqToJoinAndAgg,
-		TiDBEnableCascadesPlanner, TiDBEnableNoopFuncs,
+		TiDBEnableCascadesPlanner, TiDBEnableNoopFuncs, TiDBDisableTxnAutoRetry,
 		TiDBScatterRegion, TiDBGeneralLog, TiDBConstraintCheckInPlace, TiDBEnableVectorizedExpression:
 		fallthrough
 	case GeneralLog, AvoidTemporalUpgrade, BigTables, CheckProxyUsers, LogBin,
@@ -409,11 +412,13 @@ func ValidateSetSystemVar(vars *SessionVars, name string, value string) (string,
 		return value, ErrWrongValueForVar.GenWithStackByArgs(name, value)
 	case MaxExecutionTime:
 		return checkUInt64SystemVar(name, value, 0, math.MaxUint64, vars)
+	case CTEMaxRecursionDepth:
+		return checkUInt64SystemVar(name, value, 0, math.MaxUint32, vars)
 	case ThreadPoolSize:
 		return checkUInt64SystemVar(name, value, 1, 64, vars)
 	case TiDBDDLReorgBatchSize:
 		return checkUInt64SystemVar(name, value, uint64(MinDDLReorgBatchSize), uint64(MaxDDLReorgBatchSize), vars)
-	case TiDBDDLErrorCountLimit:
+	case TiDBDDLErrorCountLimit, TiDBRetryLimit:
 		return checkUInt64SystemVar(name, value, uint64(0), math.MaxInt64, vars)
 	case TiDBIndexLookupConcurrency, TiDBIndexLookupJoinConcurrency,
 		TiDBIndexLookupSize,
@@ -472,6 +477,12 @@ func ValidateSetSystemVar(vars *SessionVars, name string, value string) (string,
 			return value, ErrWrongValueForVar.GenWithStackByArgs(name)
 		}
 		return value, nil
+	case TimeZone:
+		if strings.EqualFold(value, "SYSTEM") {
+			return "SYSTEM", nil
+		}
+		_, err := parseTimeZone(value)
+		return value, err
 	case TxnIsolation, TransactionIsolation:
 		upVal := strings.ToUpper(value)
 		_, exists := TxIsolationNames[upVal]
@@ -541,6 +552,12 @@ func ValidateSetSystemVar(vars *SessionVars, name string, value string) (string,
 			return "leader", nil
 		}
 		return value, ErrWrongValueForVar.GenWithStackByArgs(name, value)
+	case TiDBTxnMode:
+		switch strings.ToUpper(value) {
+		case ast.Pessimistic, ast.Optimistic, "":
+			return strings.ToLower(value), nil
+		}
+		return value, ErrWrongValueForVar.GenWithStackByArgs(name, value)
 	case TiDBAllowRemoveAutoInc:
 		switch {
 		case strings.EqualFold(value, "ON") || value == "1":
@@ -582,6 +599,43 @@ func tidbOptFloat64(opt string, defaultVal float64) float64 {
 	return val
 }
 
+// parseTimeZone parses the time zone, which can be "SYSTEM", a named time zone
+// or an offset from UTC such as '+10:00' or '-6:00'.
+func parseTimeZone(s string) (*time.Location, error) {
+	if strings.EqualFold(s, "SYSTEM") {
+		return time.Local, nil
+	}
+
+	loc, err := time.LoadLocation(s)
+	if err == nil {
+		return loc, nil
+	}
+
+	// The time zone's value should in [-12:59,+14:00].
+	if strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-") {
+		d, err := types.ParseDuration(nil, s[1:], 0)
+		if err == nil {
+			if s[0] == '-' {
+				if d.Duration > 12*time.Hour+59*time.Minute {
+					return nil, ErrUnknownTimeZone.GenWithStackByArgs(s)
+				}
+			} else {
+				if d.Duration > 14*time.Hour {
+					return nil, ErrUnknownTimeZone.GenWithStackByArgs(s)
+				}
+			}
+
+			ofst := int(d.Duration / time.Second)
+			if s[0] == '-' {
+				ofst = -ofst
+			}
+			return time.FixedZone("", ofst), nil
+		}
+	}
+
+	return nil, ErrUnknownTimeZone.GenWithStackByArgs(s)
+}
+
 // GoTimeToTS converts a Go time to uint64 timestamp.
 func GoTimeToTS(t time.Time) uint64 {
 	ts := (t.UnixNano() / int64(time.Millisecond)) << epochShiftBits
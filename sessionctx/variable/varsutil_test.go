@@ -65,6 +65,7 @@ func (s *testVarsutilSuite) TestNewSessionVars(c *C) {
 	c.Assert(vars.EnableRadixJoin, Equals, DefTiDBUseRadixJoin)
 	c.Assert(vars.AllowWriteRowID, Equals, DefOptWriteRowID)
 	c.Assert(vars.TiDBOptJoinReorderThreshold, Equals, DefTiDBOptJoinReorderThreshold)
+	c.Assert(vars.CTEMaxRecursionDepth, Equals, DefCTEMaxRecursionDepth)
 
 	assertFieldsGreaterThanZero(c, reflect.ValueOf(vars.Concurrency))
 	assertFieldsGreaterThanZero(c, reflect.ValueOf(vars.BatchSize))
@@ -95,6 +96,39 @@ func (s *testVarsutilSuite) TestSetOverflowBehave(c *C) {
 	c.Assert(val2, Equals, 1.2)
 }
 
+func (s *testVarsutilSuite) TestSetTimeZone(c *C) {
+	defer testleak.AfterTest(c)()
+	v := NewSessionVars()
+
+	tests := []struct {
+		input        string
+		expect       string
+		compareValue bool
+		diff         time.Duration
+	}{
+		{"Asia/Shanghai", "Asia/Shanghai", false, 0},
+		{"UTC", "UTC", false, 0},
+		{"+08:00", "", true, 8 * time.Hour},
+		{"-08:00", "", true, -8 * time.Hour},
+		{"+00:00", "", true, 0},
+	}
+	for _, tt := range tests {
+		err := v.SetSystemVar(TimeZone, tt.input)
+		c.Assert(err, IsNil)
+		c.Assert(v.TimeZone.String(), Equals, tt.expect)
+		if tt.compareValue {
+			t1 := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+			t2 := time.Date(2000, 1, 1, 0, 0, 0, 0, v.TimeZone)
+			c.Assert(t2.Sub(t1), Equals, -tt.diff)
+		}
+	}
+	err := v.SetSystemVar(TimeZone, "SYSTEM")
+	c.Assert(err, IsNil)
+	c.Assert(v.Location(), Equals, time.Local)
+	err = v.SetSystemVar(TimeZone, "+15:00")
+	c.Assert(err, NotNil)
+}
+
 func (s *testVarsutilSuite) TestValidate(c *C) {
 	v := NewSessionVars()
 	v.GlobalVarsAccessor = NewMockGlobalAccessor()
@@ -124,6 +158,12 @@ func (s *testVarsutilSuite) TestValidate(c *C) {
 		{QueryCacheType, "3", true},
 		{SecureAuth, "1", false},
 		{SecureAuth, "3", true},
+		{TimeZone, "SYSTEM", false},
+		{TimeZone, "+08:00", false},
+		{TimeZone, "-12:59", false},
+		{TimeZone, "+14:01", true},
+		{TimeZone, "UTC", false},
+		{TimeZone, "unknown/zone", true},
 		{MyISAMUseMmap, "ON", false},
 		{MyISAMUseMmap, "OFF", false},
 		{TiDBOptCorrelationExpFactor, "a", true},
@@ -156,6 +196,14 @@ func (s *testVarsutilSuite) TestValidate(c *C) {
 		{TiDBOptJoinReorderThreshold, "a", true},
 		{TiDBOptJoinReorderThreshold, "-1", true},
 		{TiDBReplicaRead, "invalid", true},
+		{TiDBTxnMode, "pessimistic", false},
+		{TiDBTxnMode, "optimistic", false},
+		{TiDBTxnMode, "", false},
+		{TiDBTxnMode, "invalid", true},
+		{TiDBRetryLimit, "a", true},
+		{TiDBRetryLimit, "-1", false},
+		{TiDBDisableTxnAutoRetry, "a", true},
+		{TiDBDisableTxnAutoRetry, "0", false},
 	}
 
 	for _, t := range tests {
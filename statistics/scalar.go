@@ -15,8 +15,13 @@ package statistics
 
 import (
 	"encoding/binary"
-	"github.com/pingcap/tidb/types"
 	"math"
+	"time"
+
+	"github.com/cznic/mathutil"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
 )
 
 // calcFraction is used to calculate the fraction of the interval [lower, upper] that lies within the [lower, value]
@@ -40,6 +45,14 @@ func calcFraction(lower, upper, value float64) float64 {
 
 func convertDatumToScalar(value *types.Datum, commonPfxLen int) float64 {
 	switch value.Kind() {
+	case types.KindMysqlDecimal:
+		scalar, err := value.GetMysqlDecimal().ToFloat64()
+		if err != nil {
+			return 0
+		}
+		return scalar
+	case types.KindMysqlTime:
+		return convertTimeToScalar(value.GetMysqlTime())
 	case types.KindString, types.KindBytes:
 		bytes := value.GetBytes()
 		if len(bytes) <= commonPfxLen {
@@ -62,6 +75,14 @@ func (hg *Histogram) PreCalculateScalar() {
 		return
 	}
 	switch hg.GetLower(0).Kind() {
+	case types.KindMysqlDecimal, types.KindMysqlTime:
+		hg.scalars = make([]scalar, len)
+		for i := 0; i < len; i++ {
+			hg.scalars[i] = scalar{
+				lower: convertDatumToScalar(hg.GetLower(i), 0),
+				upper: convertDatumToScalar(hg.GetUpper(i), 0),
+			}
+		}
 	case types.KindBytes, types.KindString:
 		hg.scalars = make([]scalar, len)
 		for i := 0; i < len; i++ {
@@ -87,6 +108,10 @@ func (hg *Histogram) calcFraction(index int, value *types.Datum) float64 {
 		return calcFraction(float64(lower.GetInt64(0)), float64(upper.GetInt64(0)), float64(value.GetInt64()))
 	case types.KindUint64:
 		return calcFraction(float64(lower.GetUint64(0)), float64(upper.GetUint64(0)), float64(value.GetUint64()))
+	case types.KindMysqlDuration:
+		return calcFraction(float64(lower.GetDuration(0, 0).Duration), float64(upper.GetDuration(0, 0).Duration), float64(value.GetMysqlDuration().Duration))
+	case types.KindMysqlDecimal, types.KindMysqlTime:
+		return calcFraction(hg.scalars[index].lower, hg.scalars[index].upper, convertDatumToScalar(value, 0))
 	case types.KindBytes, types.KindString:
 		return calcFraction(hg.scalars[index].lower, hg.scalars[index].upper, convertDatumToScalar(value, hg.scalars[index].commonPfxLen))
 	}
@@ -113,6 +138,14 @@ func convertBytesToScalar(value []byte) float64 {
 	return float64(binary.BigEndian.Uint64(buf[:]))
 }
 
+// convertTimeToScalar converts the time to the microseconds elapsed since the
+// Unix epoch. Invalid dates like '2019-00-00' are normalized by Go's time
+// package, which is good enough for estimation.
+func convertTimeToScalar(t types.Time) float64 {
+	goTime, _ := t.GoTime(time.UTC)
+	return float64(goTime.Unix())*1e6 + float64(goTime.Nanosecond()/1000)
+}
+
 const maxNumStep = 10
 
 func enumRangeValues(low, high types.Datum, lowExclude, highExclude bool) []types.Datum {
@@ -170,6 +203,62 @@ func enumRangeValues(low, high types.Datum, lowExclude, highExclude bool) []type
 			values = append(values, types.NewUintDatum(startValue+i))
 		}
 		return values
+	case types.KindMysqlDuration:
+		lowDur, highDur := low.GetMysqlDuration(), high.GetMysqlDuration()
+		fsp := mathutil.MaxInt8(lowDur.Fsp, highDur.Fsp)
+		stepSize := int64(math.Pow10(int(types.MaxFsp-fsp))) * int64(time.Microsecond)
+		lowDur.Duration = lowDur.Duration.Round(time.Duration(stepSize))
+		remaining := int64(highDur.Duration-lowDur.Duration)/stepSize + 1 - int64(exclude)
+		if remaining >= maxNumStep {
+			return nil
+		}
+		startValue := int64(lowDur.Duration)
+		if lowExclude {
+			startValue += stepSize
+		}
+		values := make([]types.Datum, 0, remaining)
+		for i := int64(0); i < remaining; i++ {
+			values = append(values, types.NewDurationDatum(types.Duration{Duration: time.Duration(startValue + i*stepSize), Fsp: fsp}))
+		}
+		return values
+	case types.KindMysqlTime:
+		lowTime, highTime := low.GetMysqlTime(), high.GetMysqlTime()
+		if lowTime.Type() != highTime.Type() {
+			return nil
+		}
+		fsp := mathutil.MaxInt8(lowTime.Fsp(), highTime.Fsp())
+		var stepSize int64
+		if lowTime.Type() == mysql.TypeDate {
+			stepSize = 24 * int64(time.Hour)
+		} else {
+			var err error
+			lowTime, err = lowTime.RoundFrac(&stmtctx.StatementContext{TimeZone: time.UTC}, fsp)
+			if err != nil {
+				return nil
+			}
+			stepSize = int64(math.Pow10(int(types.MaxFsp-fsp))) * int64(time.Microsecond)
+		}
+		lowGoTime, err := lowTime.GoTime(time.UTC)
+		if err != nil {
+			return nil
+		}
+		highGoTime, err := highTime.GoTime(time.UTC)
+		if err != nil {
+			return nil
+		}
+		remaining := int64(highGoTime.Sub(lowGoTime))/stepSize + 1 - int64(exclude)
+		if remaining >= maxNumStep {
+			return nil
+		}
+		if lowExclude {
+			lowGoTime = lowGoTime.Add(time.Duration(stepSize))
+		}
+		values := make([]types.Datum, 0, remaining)
+		for i := int64(0); i < remaining; i++ {
+			t := lowGoTime.Add(time.Duration(i * stepSize))
+			values = append(values, types.NewTimeDatum(types.NewTime(types.FromGoTime(t), lowTime.Type(), fsp)))
+		}
+		return values
 	}
 	return nil
 }
@@ -15,14 +15,28 @@ package statistics
 
 import (
 	"math"
+	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
 )
 
 const eps = 1e-9
 
+func getTimeDatum(c *C, str string, tp byte, fsp int8) types.Datum {
+	t, err := types.ParseTime(&stmtctx.StatementContext{TimeZone: time.UTC}, str, tp, fsp)
+	c.Assert(err, IsNil)
+	return types.NewTimeDatum(t)
+}
+
+func getDurationDatum(c *C, str string, fsp int8) types.Datum {
+	d, err := types.ParseDuration(nil, str, fsp)
+	c.Assert(err, IsNil)
+	return types.NewDurationDatum(d)
+}
+
 func getUnsignedFieldType() *types.FieldType {
 	tp := types.NewFieldType(mysql.TypeLonglong)
 	tp.Flag |= mysql.UnsignedFlag
@@ -93,6 +107,20 @@ func (s *testStatisticsSuite) TestCalcFraction(c *C) {
 			fraction: 0.0529216802217269,
 			tp:       types.NewFieldType(mysql.TypeBlob),
 		},
+		{
+			lower:    getTimeDatum(c, "2019-01-01 00:00:00", mysql.TypeDatetime, 0),
+			upper:    getTimeDatum(c, "2019-01-05 00:00:00", mysql.TypeDatetime, 0),
+			value:    getTimeDatum(c, "2019-01-02 00:00:00", mysql.TypeDatetime, 0),
+			fraction: 0.25,
+			tp:       types.NewFieldType(mysql.TypeDatetime),
+		},
+		{
+			lower:    getDurationDatum(c, "00:00:00", 0),
+			upper:    getDurationDatum(c, "04:00:00", 0),
+			value:    getDurationDatum(c, "03:00:00", 0),
+			fraction: 0.75,
+			tp:       types.NewFieldType(mysql.TypeDuration),
+		},
 	}
 	for _, test := range tests {
 		hg := NewHistogram(0, 0, 0, 0, test.tp, 1, 0)
@@ -132,6 +160,27 @@ func (s *testStatisticsSuite) TestEnumRangeValues(c *C) {
 			highExclude: true,
 			res:         "(0, 1, 2, 3, 4)",
 		},
+		{
+			low:         getTimeDatum(c, "2019-12-30", mysql.TypeDate, 0),
+			high:        getTimeDatum(c, "2020-01-02", mysql.TypeDate, 0),
+			lowExclude:  true,
+			highExclude: false,
+			res:         "(2019-12-31, 2020-01-01, 2020-01-02)",
+		},
+		{
+			low:         getTimeDatum(c, "2019-01-01 00:00:00.1", mysql.TypeDatetime, 1),
+			high:        getTimeDatum(c, "2019-01-01 00:00:00.4", mysql.TypeDatetime, 1),
+			lowExclude:  false,
+			highExclude: true,
+			res:         "(2019-01-01 00:00:00.1, 2019-01-01 00:00:00.2, 2019-01-01 00:00:00.3)",
+		},
+		{
+			low:         getDurationDatum(c, "00:00:01", 0),
+			high:        getDurationDatum(c, "00:00:03", 0),
+			lowExclude:  false,
+			highExclude: false,
+			res:         "(00:00:01, 00:00:02, 00:00:03)",
+		},
 		// fix issue 11610
 		{
 			low:         types.NewIntDatum(math.MinInt64),
@@ -180,3 +180,151 @@ func (e *hashAggExec) getContexts(groupKey []byte) []*aggregation.AggEvaluateCon
 	}
 	return aggCtxs
 }
+
+var _ executor = &streamAggExec{}
+
+// streamAggExec aggregates the rows which are sorted by the group-by items,
+// a group is finished once a row of the next group arrives.
+type streamAggExec struct {
+	evalCtx           *evalContext
+	aggExprs          []aggregation.Aggregation
+	aggCtxs           []*aggregation.AggEvaluateContext
+	groupByExprs      []expression.Expression
+	relatedColOffsets []int
+	row               []types.Datum
+	// currGroupByRow is the group-by values of the group being aggregated.
+	currGroupByRow []types.Datum
+	// nextGroupByRow is the group-by values of the last read row.
+	nextGroupByRow []types.Datum
+	executed       bool
+	hasData        bool
+	count          int64
+
+	src executor
+}
+
+func (e *streamAggExec) SetSrcExec(exec executor) {
+	e.src = exec
+}
+
+func (e *streamAggExec) GetSrcExec() executor {
+	return e.src
+}
+
+func (e *streamAggExec) ResetCounts() {
+	e.src.ResetCounts()
+}
+
+func (e *streamAggExec) Counts() []int64 {
+	return e.src.Counts()
+}
+
+// meetNewGroup evaluates the group-by values of e.row, and returns whether the
+// row starts a new group other than the first one.
+func (e *streamAggExec) meetNewGroup() (bool, error) {
+	if len(e.groupByExprs) == 0 {
+		return false, nil
+	}
+
+	groupByRow := make([]types.Datum, 0, len(e.groupByExprs))
+	matched, firstGroup := true, false
+	if e.nextGroupByRow == nil {
+		matched, firstGroup = false, true
+	}
+	row := chunk.MutRowFromDatums(e.row).ToRow()
+	for i, item := range e.groupByExprs {
+		d, err := item.Eval(row)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		if matched {
+			c, err := d.CompareDatum(e.evalCtx.sc, &e.nextGroupByRow[i])
+			if err != nil {
+				return false, errors.Trace(err)
+			}
+			matched = c == 0
+		}
+		groupByRow = append(groupByRow, *d.Copy())
+	}
+	if firstGroup {
+		e.currGroupByRow = groupByRow
+	}
+	if matched {
+		return false, nil
+	}
+	e.nextGroupByRow = groupByRow
+	return !firstGroup, nil
+}
+
+func (e *streamAggExec) Next(ctx context.Context) (retRow [][]byte, err error) {
+	e.count++
+	if e.executed {
+		return nil, nil
+	}
+
+	for {
+		values, err := e.src.Next(ctx)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if values == nil {
+			e.executed = true
+			if !e.hasData && len(e.groupByExprs) > 0 {
+				return nil, nil
+			}
+			return e.getPartialResult()
+		}
+
+		e.hasData = true
+		err = e.evalCtx.decodeRelatedColumnVals(e.relatedColOffsets, values, e.row)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		newGroup, err := e.meetNewGroup()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if newGroup {
+			retRow, err = e.getPartialResult()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		for i, agg := range e.aggExprs {
+			err = agg.Update(e.aggCtxs[i], e.evalCtx.sc, chunk.MutRowFromDatums(e.row).ToRow())
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if newGroup {
+			return retRow, nil
+		}
+	}
+}
+
+// getPartialResult returns the partial results of the current group followed
+// by its group-by values, then starts the next group.
+func (e *streamAggExec) getPartialResult() ([][]byte, error) {
+	value := make([][]byte, 0, len(e.groupByExprs)+2*len(e.aggExprs))
+	for i, agg := range e.aggExprs {
+		partialResults := agg.GetPartialResult(e.aggCtxs[i])
+		for _, result := range partialResults {
+			data, err := codec.EncodeValue(e.evalCtx.sc, nil, result)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			value = append(value, data)
+		}
+		// Clear the aggregate context.
+		e.aggCtxs[i] = agg.CreateContext(e.evalCtx.sc)
+	}
+	for _, d := range e.currGroupByRow {
+		data, err := codec.EncodeValue(e.evalCtx.sc, nil, d)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		value = append(value, data)
+	}
+	e.currGroupByRow = e.nextGroupByRow
+	return value, nil
+}
@@ -118,7 +118,10 @@ func (h *rpcHandler) buildDAGExecutor(req *coprocessor.Request) (*dagContext, ex
 // is set, the daylight saving problem must be considered. Otherwise the
 // timezone offset in seconds east of UTC is used to constructed the timezone.
 func constructTimeZone(name string, offset int) (*time.Location, error) {
-	return time.Local, nil
+	if name != "" {
+		return time.LoadLocation(name)
+	}
+	return time.FixedZone("", offset), nil
 }
 
 func (h *rpcHandler) buildExec(ctx *dagContext, curr *tipb.Executor) (executor, error) {
@@ -133,6 +136,8 @@ func (h *rpcHandler) buildExec(ctx *dagContext, curr *tipb.Executor) (executor,
 		currExec, err = h.buildSelection(ctx, curr)
 	case tipb.ExecType_TypeAggregation:
 		currExec, err = h.buildHashAgg(ctx, curr)
+	case tipb.ExecType_TypeStreamAgg:
+		currExec, err = h.buildStreamAgg(ctx, curr)
 	case tipb.ExecType_TypeTopN:
 		currExec, err = h.buildTopN(ctx, curr)
 	case tipb.ExecType_TypeLimit:
@@ -295,6 +300,26 @@ func (h *rpcHandler) buildHashAgg(ctx *dagContext, executor *tipb.Executor) (*ha
 	}, nil
 }
 
+func (h *rpcHandler) buildStreamAgg(ctx *dagContext, executor *tipb.Executor) (*streamAggExec, error) {
+	aggs, groupBys, relatedColOffsets, err := h.getAggInfo(ctx, executor)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	aggCtxs := make([]*aggregation.AggEvaluateContext, 0, len(aggs))
+	for _, agg := range aggs {
+		aggCtxs = append(aggCtxs, agg.CreateContext(ctx.evalCtx.sc))
+	}
+
+	return &streamAggExec{
+		evalCtx:           ctx.evalCtx,
+		aggExprs:          aggs,
+		aggCtxs:           aggCtxs,
+		groupByExprs:      groupBys,
+		relatedColOffsets: relatedColOffsets,
+		row:               make([]types.Datum, len(ctx.evalCtx.columnInfos)),
+	}, nil
+}
+
 func (h *rpcHandler) buildTopN(ctx *dagContext, executor *tipb.Executor) (*topNExec, error) {
 	topN := executor.TopN
 	var err error
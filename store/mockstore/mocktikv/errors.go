@@ -79,3 +79,14 @@ type ErrConflict struct {
 func (e *ErrConflict) Error() string {
 	return "write conflict"
 }
+
+// ErrDeadlock is returned when deadlock is detected.
+type ErrDeadlock struct {
+	LockKey         []byte
+	LockTS          uint64
+	DeadlockKeyHash uint64
+}
+
+func (e *ErrDeadlock) Error() string {
+	return "deadlock"
+}
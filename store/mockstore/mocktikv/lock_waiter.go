@@ -0,0 +1,115 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mocktikv
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/util/deadlock"
+)
+
+// maxLockWaitTime is the max time a pessimistic lock request waits for a lock
+// on the server side, the client retries the request if it's still blocked.
+const maxLockWaitTime = time.Second
+
+// lockWaiterManager manages the pessimistic lock requests waiting for the locks
+// of other transactions, it wakes the waiters up when the locks are released and
+// detects deadlocks between the waiting transactions.
+type lockWaiterManager struct {
+	mu sync.Mutex
+	// waiters is indexed by the start ts of the transaction which holds the lock.
+	waiters map[uint64][]chan struct{}
+	// version is increased every time locks are released, it's used to find out
+	// whether the lock has been released between the lock request and the wait.
+	version  uint64
+	detector *deadlock.Detector
+}
+
+func newLockWaiterManager() *lockWaiterManager {
+	return &lockWaiterManager{
+		waiters:  make(map[uint64][]chan struct{}),
+		detector: deadlock.NewDetector(),
+	}
+}
+
+func (lw *lockWaiterManager) getVersion() uint64 {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	return lw.version
+}
+
+// wait blocks the transaction of startTS until the lock is released or timeout.
+// It returns ErrDeadlock if waiting for the lock leads to a deadlock.
+func (lw *lockWaiterManager) wait(startTS uint64, lock *ErrLocked, version uint64, timeout time.Duration) error {
+	keyHash := hashKey(lock.Key)
+	lw.mu.Lock()
+	if lw.version != version {
+		// Some locks are released after the lock request, retry it immediately.
+		lw.mu.Unlock()
+		return nil
+	}
+	if err := lw.detector.Detect(startTS, lock.StartTS, keyHash); err != nil {
+		lw.mu.Unlock()
+		return &ErrDeadlock{
+			LockKey:         lock.Key.Raw(),
+			LockTS:          lock.StartTS,
+			DeadlockKeyHash: err.KeyHash,
+		}
+	}
+	ch := make(chan struct{})
+	lw.waiters[lock.StartTS] = append(lw.waiters[lock.StartTS], ch)
+	lw.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	select {
+	case <-ch:
+	case <-timer.C:
+	}
+	timer.Stop()
+
+	lw.mu.Lock()
+	waiters := lw.waiters[lock.StartTS]
+	for i, waiter := range waiters {
+		if waiter == ch {
+			lw.waiters[lock.StartTS] = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(lw.waiters[lock.StartTS]) == 0 {
+		delete(lw.waiters, lock.StartTS)
+	}
+	lw.mu.Unlock()
+	lw.detector.CleanUpWaitFor(startTS, lock.StartTS, keyHash)
+	return nil
+}
+
+// wakeUp wakes up the waiters of the locks held by the transaction of lockTS.
+func (lw *lockWaiterManager) wakeUp(lockTS uint64) {
+	lw.mu.Lock()
+	lw.version++
+	for _, ch := range lw.waiters[lockTS] {
+		close(ch)
+	}
+	delete(lw.waiters, lockTS)
+	lw.mu.Unlock()
+	lw.detector.CleanUp(lockTS)
+}
+
+func hashKey(key []byte) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write(key)
+	return h.Sum64()
+}
@@ -16,9 +16,11 @@ package mocktikv
 import (
 	"math"
 	"testing"
+	"time"
 
 	"github.com/pingcap-incubator/tinykv/proto/pkg/kvrpcpb"
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/store/tikv/tikvrpc"
 )
 
 func TestT(t *testing.T) {
@@ -572,6 +574,151 @@ func (s *testMockTiKVSuite) TestDeleteRange(c *C) {
 	s.mustScanOK(c, "0", 10, 70)
 }
 
+func (s *testMockTiKVSuite) mustPessimisticLock(c *C, keys []string, primary string, startTS, forUpdateTS uint64) []error {
+	var ks [][]byte
+	for _, key := range keys {
+		ks = append(ks, []byte(key))
+	}
+	return s.store.PessimisticLock(&tikvrpc.PessimisticLockRequest{
+		Keys:         ks,
+		PrimaryLock:  []byte(primary),
+		StartVersion: startTS,
+		ForUpdateTs:  forUpdateTS,
+		LockTtl:      1000,
+	})
+}
+
+func (s *testMockTiKVSuite) mustPessimisticLockOK(c *C, keys []string, primary string, startTS, forUpdateTS uint64) {
+	errs := s.mustPessimisticLock(c, keys, primary, startTS, forUpdateTS)
+	for _, err := range errs {
+		c.Assert(err, IsNil)
+	}
+}
+
+func (s *testMockTiKVSuite) TestPessimisticLock(c *C) {
+	s.mustPutOK(c, "A", "A10", 5, 10)
+
+	// The write committed after forUpdateTS conflicts with the pessimistic lock.
+	errs := s.mustPessimisticLock(c, []string{"A"}, "A", 6, 8)
+	s.mustWriteWriteConflict(c, errs, 0)
+	s.mustPessimisticLockOK(c, []string{"A", "B"}, "A", 6, 11)
+	// Locking the keys again is idempotent.
+	s.mustPessimisticLockOK(c, []string{"A"}, "A", 6, 12)
+
+	// The pessimistic lock doesn't block reads.
+	s.mustGetOK(c, "A", 20, "A10")
+	s.mustGetNone(c, "B", 20)
+
+	// The pessimistic lock blocks others from locking or writing the keys.
+	errs = s.mustPessimisticLock(c, []string{"A"}, "A", 7, 13)
+	_, ok := errs[0].(*ErrLocked)
+	c.Assert(ok, IsTrue)
+	errs = s.store.Prewrite(&kvrpcpb.PrewriteRequest{
+		Mutations:    putMutations("B", "B15"),
+		PrimaryLock:  []byte("B"),
+		StartVersion: 15,
+	})
+	_, ok = errs[0].(*ErrLocked)
+	c.Assert(ok, IsTrue)
+
+	// Prewrite the pessimistic locked keys and commit them.
+	mutations := putMutations("A", "A20")
+	mutations = append(mutations, &kvrpcpb.Mutation{Op: kvrpcpb.Op_Lock, Key: []byte("B")})
+	errs = s.store.PessimisticPrewrite(&tikvrpc.PessimisticPrewriteRequest{
+		PrewriteRequest: &kvrpcpb.PrewriteRequest{
+			Mutations:    mutations,
+			PrimaryLock:  []byte("A"),
+			StartVersion: 6,
+		},
+		ForUpdateTs:       12,
+		IsPessimisticLock: []bool{true, true},
+	})
+	for _, err := range errs {
+		c.Assert(err, IsNil)
+	}
+	s.mustCommitOK(c, [][]byte{[]byte("A"), []byte("B")}, 6, 20)
+	s.mustGetOK(c, "A", 21, "A20")
+	s.mustGetNone(c, "B", 21)
+
+	// Prewrite fails if the pessimistic lock is missing.
+	errs = s.store.PessimisticPrewrite(&tikvrpc.PessimisticPrewriteRequest{
+		PrewriteRequest: &kvrpcpb.PrewriteRequest{
+			Mutations:    putMutations("C", "C30"),
+			PrimaryLock:  []byte("C"),
+			StartVersion: 25,
+		},
+		ForUpdateTs:       25,
+		IsPessimisticLock: []bool{true},
+	})
+	_, ok = errs[0].(ErrAbort)
+	c.Assert(ok, IsTrue)
+}
+
+func (s *testMockTiKVSuite) TestPessimisticRollback(c *C) {
+	s.mustPessimisticLockOK(c, []string{"A", "B"}, "A", 10, 10)
+	// The rollback with an older forUpdateTS doesn't release the lock.
+	errs := s.store.PessimisticRollback([][]byte{[]byte("A")}, 10, 9)
+	for _, err := range errs {
+		c.Assert(err, IsNil)
+	}
+	errs = s.mustPessimisticLock(c, []string{"A"}, "A", 11, 11)
+	_, ok := errs[0].(*ErrLocked)
+	c.Assert(ok, IsTrue)
+
+	errs = s.store.PessimisticRollback([][]byte{[]byte("A"), []byte("B")}, 10, 10)
+	for _, err := range errs {
+		c.Assert(err, IsNil)
+	}
+	s.mustPessimisticLockOK(c, []string{"A", "B"}, "A", 11, 11)
+	s.mustScanLock(c, 20, []*kvrpcpb.LockInfo{
+		lock("A", "A", 11),
+		lock("B", "A", 11),
+	})
+	s.mustRollbackOK(c, [][]byte{[]byte("A"), []byte("B")}, 11)
+	s.mustScanLock(c, 20, nil)
+}
+
+func (s *testMockTiKVSuite) TestLockWaiter(c *C) {
+	lw := newLockWaiterManager()
+	lockA := &ErrLocked{Key: NewMvccKey([]byte("A")), StartTS: 1}
+	lockB := &ErrLocked{Key: NewMvccKey([]byte("B")), StartTS: 2}
+
+	// The waiter is woken up when the lock is released.
+	version := lw.getVersion()
+	done := make(chan error, 1)
+	go func() {
+		done <- lw.wait(2, lockA, version, 10*time.Second)
+	}()
+	for {
+		lw.mu.Lock()
+		waiting := len(lw.waiters[1]) > 0
+		lw.mu.Unlock()
+		if waiting {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	// Txn 1 waits for txn 2 which is waiting for txn 1.
+	err := lw.wait(1, lockB, version, 10*time.Second)
+	dl, ok := err.(*ErrDeadlock)
+	c.Assert(ok, IsTrue)
+	c.Assert(dl.LockTS, Equals, uint64(2))
+	c.Assert(dl.LockKey, BytesEquals, []byte("B"))
+	lw.wakeUp(1)
+	c.Assert(<-done, IsNil)
+
+	// The wait returns immediately if any lock is released after the lock request.
+	version = lw.getVersion()
+	lw.wakeUp(3)
+	c.Assert(lw.wait(2, lockA, version, 10*time.Second), IsNil)
+
+	// The wait times out.
+	start := time.Now()
+	c.Assert(lw.wait(2, lockA, lw.getVersion(), 10*time.Millisecond), IsNil)
+	c.Assert(time.Since(start), GreaterEqual, 10*time.Millisecond)
+	c.Assert(lw.waiters, HasLen, 0)
+}
+
 func (s *testMockTiKVSuite) mustWriteWriteConflict(c *C, errs []error, i int) {
 	c.Assert(errs[i], NotNil)
 	_, ok := errs[i].(*ErrConflict)
@@ -22,6 +22,7 @@ import (
 	"github.com/google/btree"
 	"github.com/pingcap-incubator/tinykv/proto/pkg/kvrpcpb"
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/store/tikv/tikvrpc"
 	"github.com/pingcap/tidb/util/codec"
 )
 
@@ -33,6 +34,10 @@ const (
 	typeRollback
 )
 
+// opPessimisticLock is the op of the pessimistic locks, which kvrpcpb of TinyKV doesn't define.
+// A pessimistic lock holds no value and doesn't block the reads.
+const opPessimisticLock = kvrpcpb.Op_Lock + 1
+
 type mvccValue struct {
 	valueType mvccValueType
 	startTS   uint64
@@ -195,8 +200,8 @@ func (l *mvccLock) lockErr(key []byte) error {
 }
 
 func (l *mvccLock) check(ts uint64, key []byte) (uint64, error) {
-	// ignore when ts is older than lock or lock's type is Lock.
-	if l.startTS > ts || l.op == kvrpcpb.Op_Lock {
+	// ignore when ts is older than lock or lock's type is Lock or PessimisticLock.
+	if l.startTS > ts || l.op == kvrpcpb.Op_Lock || l.op == opPessimisticLock {
 		return ts, nil
 	}
 	// for point get latest version.
@@ -231,7 +236,10 @@ type MVCCStore interface {
 	Get(key []byte, startTS uint64) ([]byte, error)
 	Scan(startKey, endKey []byte, limit int, startTS uint64) []Pair
 	ReverseScan(startKey, endKey []byte, limit int, startTS uint64) []Pair
+	PessimisticLock(req *tikvrpc.PessimisticLockRequest) []error
+	PessimisticRollback(keys [][]byte, startTS, forUpdateTS uint64) []error
 	Prewrite(req *kvrpcpb.PrewriteRequest) []error
+	PessimisticPrewrite(req *tikvrpc.PessimisticPrewriteRequest) []error
 	Commit(keys [][]byte, startTS, commitTS uint64) error
 	Rollback(keys [][]byte, startTS uint64) error
 	Cleanup(key []byte, startTS, currentTS uint64) error
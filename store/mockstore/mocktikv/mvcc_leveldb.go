@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/goleveldb/leveldb/util"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/store/tikv/oracle"
+	"github.com/pingcap/tidb/store/tikv/tikvrpc"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/logutil"
 	"go.uber.org/zap"
@@ -438,8 +439,143 @@ func reverse(values []mvccValue) {
 	}
 }
 
+// PessimisticLock implements the MVCCStore interface.
+func (mvcc *MVCCLevelDB) PessimisticLock(req *tikvrpc.PessimisticLockRequest) []error {
+	keys := req.Keys
+	startTS := req.StartVersion
+	forUpdateTS := req.ForUpdateTs
+	ttl := req.LockTtl
+	mvcc.mu.Lock()
+	defer mvcc.mu.Unlock()
+
+	anyError := false
+	batch := &leveldb.Batch{}
+	errs := make([]error, 0, len(keys))
+	for _, k := range keys {
+		err := pessimisticLockKey(mvcc.db, batch, k, startTS, forUpdateTS, req.PrimaryLock, ttl)
+		errs = append(errs, err)
+		if err != nil {
+			anyError = true
+		}
+		// With nowait set, return the first lock error immediately.
+		if _, ok := err.(*ErrLocked); ok && req.WaitTimeout < 0 {
+			break
+		}
+	}
+	if anyError {
+		return errs
+	}
+	if err := mvcc.db.Write(batch, nil); err != nil {
+		return []error{err}
+	}
+
+	return errs
+}
+
+func pessimisticLockKey(db *leveldb.DB, batch *leveldb.Batch, key []byte, startTS, forUpdateTS uint64,
+	primary []byte, ttl uint64) error {
+	startKey := mvccEncode(key, lockVer)
+	iter := newIterator(db, &util.Range{
+		Start: startKey,
+	})
+	defer iter.Release()
+
+	dec := lockDecoder{
+		expectKey: key,
+	}
+	ok, err := dec.Decode(iter)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if ok {
+		if dec.lock.startTS != startTS {
+			return dec.lock.lockErr(key)
+		}
+		// The key is already locked by the transaction itself.
+		return nil
+	}
+
+	// The lock is granted only when there is no newer write than forUpdateTS.
+	err = checkConflictValue(iter, &kvrpcpb.Mutation{Key: key}, forUpdateTS+1)
+	if err != nil {
+		return err
+	}
+
+	lock := mvccLock{
+		startTS:     startTS,
+		primary:     primary,
+		op:          opPessimisticLock,
+		ttl:         ttl,
+		forUpdateTS: forUpdateTS,
+	}
+	writeKey := mvccEncode(key, lockVer)
+	writeValue, err := lock.MarshalBinary()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	batch.Put(writeKey, writeValue)
+	return nil
+}
+
+// PessimisticRollback implements the MVCCStore interface.
+func (mvcc *MVCCLevelDB) PessimisticRollback(keys [][]byte, startTS, forUpdateTS uint64) []error {
+	mvcc.mu.Lock()
+	defer mvcc.mu.Unlock()
+
+	anyError := false
+	batch := &leveldb.Batch{}
+	errs := make([]error, 0, len(keys))
+	for _, key := range keys {
+		err := pessimisticRollbackKey(mvcc.db, batch, key, startTS, forUpdateTS)
+		errs = append(errs, err)
+		if err != nil {
+			anyError = true
+		}
+	}
+	if anyError {
+		return errs
+	}
+	if err := mvcc.db.Write(batch, nil); err != nil {
+		return []error{err}
+	}
+	return errs
+}
+
+func pessimisticRollbackKey(db *leveldb.DB, batch *leveldb.Batch, key []byte, startTS, forUpdateTS uint64) error {
+	startKey := mvccEncode(key, lockVer)
+	iter := newIterator(db, &util.Range{
+		Start: startKey,
+	})
+	defer iter.Release()
+
+	dec := lockDecoder{
+		expectKey: key,
+	}
+	ok, err := dec.Decode(iter)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if ok {
+		lock := dec.lock
+		if lock.op == opPessimisticLock && lock.startTS == startTS && lock.forUpdateTS <= forUpdateTS {
+			batch.Delete(startKey)
+		}
+	}
+	return nil
+}
+
 // Prewrite implements the MVCCStore interface.
 func (mvcc *MVCCLevelDB) Prewrite(req *kvrpcpb.PrewriteRequest) []error {
+	return mvcc.prewrite(req, nil, 0)
+}
+
+// PessimisticPrewrite implements the MVCCStore interface.
+func (mvcc *MVCCLevelDB) PessimisticPrewrite(req *tikvrpc.PessimisticPrewriteRequest) []error {
+	return mvcc.prewrite(req.PrewriteRequest, req.IsPessimisticLock, req.ForUpdateTs)
+}
+
+func (mvcc *MVCCLevelDB) prewrite(req *kvrpcpb.PrewriteRequest, isPessimisticLocks []bool, forUpdateTS uint64) []error {
 	mutations := req.Mutations
 	primary := req.PrimaryLock
 	startTS := req.StartVersion
@@ -450,10 +586,9 @@ func (mvcc *MVCCLevelDB) Prewrite(req *kvrpcpb.PrewriteRequest) []error {
 	anyError := false
 	batch := &leveldb.Batch{}
 	errs := make([]error, 0, len(mutations))
-	for _, m := range mutations {
-		// If the operation is Insert, check if key is exists at first.
-		var err error
-		err = prewriteMutation(mvcc.db, batch, m, startTS, primary, ttl)
+	for i, m := range mutations {
+		isPessimisticLock := len(isPessimisticLocks) > 0 && isPessimisticLocks[i]
+		err := prewriteMutation(mvcc.db, batch, m, startTS, primary, ttl, forUpdateTS, isPessimisticLock)
 		errs = append(errs, err)
 		if err != nil {
 			anyError = true
@@ -492,7 +627,7 @@ func checkConflictValue(iter *Iterator, m *kvrpcpb.Mutation, startTS uint64) err
 
 func prewriteMutation(db *leveldb.DB, batch *leveldb.Batch,
 	mutation *kvrpcpb.Mutation, startTS uint64,
-	primary []byte, ttl uint64) error {
+	primary []byte, ttl uint64, forUpdateTS uint64, isPessimisticLock bool) error {
 	startKey := mvccEncode(mutation.Key, lockVer)
 	iter := newIterator(db, &util.Range{
 		Start: startKey,
@@ -508,10 +643,27 @@ func prewriteMutation(db *leveldb.DB, batch *leveldb.Batch,
 	}
 	if ok {
 		if dec.lock.startTS != startTS {
+			if isPessimisticLock {
+				// The pessimistic lock is resolved by others, the transaction must abort.
+				return ErrAbort("pessimistic lock not found")
+			}
 			return dec.lock.lockErr(mutation.Key)
 		}
+		// Overwrite the pessimistic lock.
+		if dec.lock.op == opPessimisticLock && ttl < dec.lock.ttl {
+			ttl = dec.lock.ttl
+		}
 	} else {
-		err = checkConflictValue(iter, mutation, startTS)
+		if isPessimisticLock {
+			return ErrAbort("pessimistic lock not found")
+		}
+		// In pessimistic transactions, the keys without pessimistic locks are derived from the locked keys,
+		// they only conflict with the writes newer than forUpdateTS.
+		conflictTS := startTS
+		if forUpdateTS > 0 {
+			conflictTS = forUpdateTS + 1
+		}
+		err = checkConflictValue(iter, mutation, conflictTS)
 		if err != nil {
 			return err
 		}
@@ -588,7 +740,7 @@ func commitKey(db *leveldb.DB, batch *leveldb.Batch, key []byte, startTS, commit
 }
 
 func commitLock(batch *leveldb.Batch, lock mvccLock, key []byte, startTS, commitTS uint64) error {
-	if lock.op != kvrpcpb.Op_Lock {
+	if lock.op != kvrpcpb.Op_Lock && lock.op != opPessimisticLock {
 		var valueType mvccValueType
 		if lock.op == kvrpcpb.Op_Put {
 			valueType = typePut
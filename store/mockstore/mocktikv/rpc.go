@@ -105,8 +105,9 @@ func convertToPbPairs(pairs []Pair) []*kvrpcpb.KvPair {
 // rpcHandler mocks tikv's side handler behavior. In general, you may assume
 // TiKV just translate the logic from Go to Rust.
 type rpcHandler struct {
-	cluster   *Cluster
-	mvccStore MVCCStore
+	cluster    *Cluster
+	mvccStore  MVCCStore
+	lockWaiter *lockWaiterManager
 
 	// storeID stores id for current request
 	storeID uint64
@@ -227,6 +228,62 @@ func (h *rpcHandler) handleKvScan(req *kvrpcpb.ScanRequest) *kvrpcpb.ScanRespons
 	}
 }
 
+func (h *rpcHandler) handleKvPessimisticLock(req *tikvrpc.PessimisticLockRequest) *tikvrpc.PessimisticLockResponse {
+	for _, k := range req.Keys {
+		if !h.checkKeyInRegion(k) {
+			panic("KvPessimisticLock: key not in region")
+		}
+	}
+	waitTimeout := time.Duration(req.WaitTimeout) * time.Millisecond
+	if waitTimeout <= 0 || waitTimeout > maxLockWaitTime {
+		waitTimeout = maxLockWaitTime
+	}
+	deadline := time.Now().Add(waitTimeout)
+	for {
+		version := h.lockWaiter.getVersion()
+		errs := h.mvccStore.PessimisticLock(req)
+		var locked *ErrLocked
+		for _, err := range errs {
+			if l, ok := err.(*ErrLocked); ok {
+				locked = l
+				break
+			}
+		}
+		remaining := time.Until(deadline)
+		// Return the lock error directly with nowait set.
+		if locked == nil || req.WaitTimeout < 0 || remaining <= 0 {
+			return &tikvrpc.PessimisticLockResponse{
+				Errors: convertToKeyErrors(errs),
+			}
+		}
+		if err := h.lockWaiter.wait(req.StartVersion, locked, version, remaining); err != nil {
+			resp := &tikvrpc.PessimisticLockResponse{
+				Errors: convertToKeyErrors([]error{err}),
+			}
+			if dl, ok := errors.Cause(err).(*ErrDeadlock); ok {
+				resp.Deadlock = &tikvrpc.Deadlock{
+					LockTs:          dl.LockTS,
+					LockKey:         dl.LockKey,
+					DeadlockKeyHash: dl.DeadlockKeyHash,
+				}
+			}
+			return resp
+		}
+	}
+}
+
+func (h *rpcHandler) handleKvPessimisticRollback(req *tikvrpc.PessimisticRollbackRequest) *tikvrpc.PessimisticRollbackResponse {
+	for _, key := range req.Keys {
+		if !h.checkKeyInRegion(key) {
+			panic("KvPessimisticRollback: key not in region")
+		}
+	}
+	errs := h.mvccStore.PessimisticRollback(req.Keys, req.StartVersion, req.ForUpdateTs)
+	return &tikvrpc.PessimisticRollbackResponse{
+		Errors: convertToKeyErrors(errs),
+	}
+}
+
 func (h *rpcHandler) handleKvPrewrite(req *kvrpcpb.PrewriteRequest) *kvrpcpb.PrewriteResponse {
 	for _, m := range req.Mutations {
 		if !h.checkKeyInRegion(m.Key) {
@@ -239,6 +296,18 @@ func (h *rpcHandler) handleKvPrewrite(req *kvrpcpb.PrewriteRequest) *kvrpcpb.Pre
 	}
 }
 
+func (h *rpcHandler) handleKvPessimisticPrewrite(req *tikvrpc.PessimisticPrewriteRequest) *kvrpcpb.PrewriteResponse {
+	for _, m := range req.Mutations {
+		if !h.checkKeyInRegion(m.Key) {
+			panic("KvPessimisticPrewrite: key not in region")
+		}
+	}
+	errs := h.mvccStore.PessimisticPrewrite(req)
+	return &kvrpcpb.PrewriteResponse{
+		Errors: convertToKeyErrors(errs),
+	}
+}
+
 func (h *rpcHandler) handleKvCommit(req *kvrpcpb.CommitRequest) *kvrpcpb.CommitResponse {
 	for _, k := range req.Keys {
 		if !h.checkKeyInRegion(k) {
@@ -349,9 +418,10 @@ func (h *rpcHandler) handleKvRawScan(req *kvrpcpb.RawScanRequest) *kvrpcpb.RawSc
 // RPCClient sends kv RPC calls to mock cluster. RPCClient mocks the behavior of
 // a rpc client at tikv's side.
 type RPCClient struct {
-	Cluster   *Cluster
-	MvccStore MVCCStore
-	done      chan struct{}
+	Cluster    *Cluster
+	MvccStore  MVCCStore
+	lockWaiter *lockWaiterManager
+	done       chan struct{}
 }
 
 // NewRPCClient creates an RPCClient.
@@ -359,9 +429,10 @@ type RPCClient struct {
 func NewRPCClient(cluster *Cluster, mvccStore MVCCStore) *RPCClient {
 	done := make(chan struct{})
 	return &RPCClient{
-		Cluster:   cluster,
-		MvccStore: mvccStore,
-		done:      done,
+		Cluster:    cluster,
+		MvccStore:  mvccStore,
+		lockWaiter: newLockWaiterManager(),
+		done:       done,
 	}
 }
 
@@ -390,8 +461,9 @@ func (c *RPCClient) checkArgs(ctx context.Context, addr string) (*rpcHandler, er
 		return nil, err
 	}
 	handler := &rpcHandler{
-		cluster:   c.Cluster,
-		mvccStore: c.MvccStore,
+		cluster:    c.Cluster,
+		mvccStore:  c.MvccStore,
+		lockWaiter: c.lockWaiter,
 		// set store id for current request
 		storeID: store.GetId(),
 	}
@@ -439,6 +511,28 @@ func (c *RPCClient) SendRequest(ctx context.Context, addr string, req *tikvrpc.R
 			return resp, nil
 		}
 		resp.Resp = handler.handleKvPrewrite(r)
+	case tikvrpc.CmdPessimisticPrewrite:
+		r := req.PessimisticPrewrite()
+		if err := handler.checkRequest(reqCtx, r.Size()); err != nil {
+			resp.Resp = &kvrpcpb.PrewriteResponse{RegionError: err}
+			return resp, nil
+		}
+		resp.Resp = handler.handleKvPessimisticPrewrite(r)
+	case tikvrpc.CmdPessimisticLock:
+		r := req.PessimisticLock()
+		if err := handler.checkRequest(reqCtx, r.Size()); err != nil {
+			resp.Resp = &tikvrpc.PessimisticLockResponse{RegionError: err}
+			return resp, nil
+		}
+		resp.Resp = handler.handleKvPessimisticLock(r)
+	case tikvrpc.CmdPessimisticRollback:
+		r := req.PessimisticRollback()
+		if err := handler.checkRequest(reqCtx, r.Size()); err != nil {
+			resp.Resp = &tikvrpc.PessimisticRollbackResponse{RegionError: err}
+			return resp, nil
+		}
+		resp.Resp = handler.handleKvPessimisticRollback(r)
+		c.lockWaiter.wakeUp(r.StartVersion)
 	case tikvrpc.CmdCommit:
 		failpoint.Inject("rpcCommitResult", func(val failpoint.Value) {
 			switch val.(string) {
@@ -461,6 +555,7 @@ func (c *RPCClient) SendRequest(ctx context.Context, addr string, req *tikvrpc.R
 			return resp, nil
 		}
 		resp.Resp = handler.handleKvCommit(r)
+		c.lockWaiter.wakeUp(r.StartVersion)
 		failpoint.Inject("rpcCommitTimeout", func(val failpoint.Value) {
 			if val.(bool) {
 				failpoint.Return(nil, undeterminedErr)
@@ -473,6 +568,7 @@ func (c *RPCClient) SendRequest(ctx context.Context, addr string, req *tikvrpc.R
 			return resp, nil
 		}
 		resp.Resp, err = handler.handleKvCheckTxnStatus(r)
+		c.lockWaiter.wakeUp(r.LockTs)
 		return resp, err
 	case tikvrpc.CmdBatchRollback:
 		r := req.BatchRollback()
@@ -481,6 +577,7 @@ func (c *RPCClient) SendRequest(ctx context.Context, addr string, req *tikvrpc.R
 			return resp, nil
 		}
 		resp.Resp = handler.handleKvBatchRollback(r)
+		c.lockWaiter.wakeUp(r.StartVersion)
 	case tikvrpc.CmdResolveLock:
 		r := req.ResolveLock()
 		if err := handler.checkRequest(reqCtx, r.Size()); err != nil {
@@ -488,6 +585,7 @@ func (c *RPCClient) SendRequest(ctx context.Context, addr string, req *tikvrpc.R
 			return resp, nil
 		}
 		resp.Resp = handler.handleKvResolveLock(r)
+		c.lockWaiter.wakeUp(r.StartVersion)
 	case tikvrpc.CmdRawGet:
 		r := req.RawGet()
 		if err := handler.checkRequest(reqCtx, r.Size()); err != nil {
@@ -18,6 +18,7 @@ import (
 	"context"
 	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	pb "github.com/pingcap-incubator/tinykv/proto/pkg/kvrpcpb"
@@ -39,11 +40,17 @@ type twoPhaseCommitAction interface {
 type actionPrewrite struct{}
 type actionCommit struct{}
 type actionCleanup struct{}
+type actionPessimisticLock struct {
+	*kv.LockCtx
+}
+type actionPessimisticRollback struct{}
 
 var (
 	_ twoPhaseCommitAction = actionPrewrite{}
 	_ twoPhaseCommitAction = actionCommit{}
 	_ twoPhaseCommitAction = actionCleanup{}
+	_ twoPhaseCommitAction = actionPessimisticLock{}
+	_ twoPhaseCommitAction = actionPessimisticRollback{}
 )
 
 // Global variable set by config file.
@@ -63,6 +70,14 @@ func (actionCleanup) String() string {
 	return "cleanup"
 }
 
+func (actionPessimisticLock) String() string {
+	return "pessimistic_lock"
+}
+
+func (actionPessimisticRollback) String() string {
+	return "pessimistic_rollback"
+}
+
 // twoPhaseCommitter executes a two-phase commit protocol.
 type twoPhaseCommitter struct {
 	store     *tikvStore
@@ -77,6 +92,9 @@ type twoPhaseCommitter struct {
 	txnSize   int
 
 	primaryKey []byte
+	// forUpdateTS is the for update ts of the last pessimistic lock request.
+	forUpdateTS   uint64
+	isPessimistic bool
 
 	mu struct {
 		sync.RWMutex
@@ -99,6 +117,7 @@ type batchExecutor struct {
 
 type mutationEx struct {
 	pb.Mutation
+	isPessimisticLock bool
 }
 
 // newTwoPhaseCommitter creates a twoPhaseCommitter.
@@ -109,6 +128,7 @@ func newTwoPhaseCommitter(txn *tikvTxn, connID uint64) (*twoPhaseCommitter, erro
 		startTS:       txn.StartTS(),
 		connID:        connID,
 		regionTxnSize: map[uint64]int{},
+		isPessimistic: txn.IsPessimistic(),
 	}, nil
 }
 
@@ -157,13 +177,17 @@ func (c *twoPhaseCommitter) initKeysAndMutations() error {
 		return errors.Trace(err)
 	}
 	for _, lockKey := range txn.lockKeys {
-		_, ok := mutations[string(lockKey)]
-		if !ok {
+		muEx, ok := mutations[string(lockKey)]
+		if ok {
+			// The key is locked by a pessimistic lock request before it's written.
+			muEx.isPessimisticLock = c.isPessimistic
+		} else {
 			mutations[string(lockKey)] = &mutationEx{
 				Mutation: pb.Mutation{
 					Op:  pb.Op_Lock,
 					Key: lockKey,
 				},
+				isPessimisticLock: c.isPessimistic,
 			}
 			lockCnt++
 			keys = append(keys, lockKey)
@@ -174,6 +198,15 @@ func (c *twoPhaseCommitter) initKeysAndMutations() error {
 		return nil
 	}
 	c.txnSize = size
+	if len(c.primaryKey) > 0 {
+		// The primary key is chosen by the first pessimistic lock request, it must be prewritten first.
+		for i, key := range keys {
+			if bytes.Equal(key, c.primaryKey) {
+				keys[0], keys[i] = keys[i], keys[0]
+				break
+			}
+		}
+	}
 
 	if size > int(kv.TxnTotalSizeLimit) {
 		return kv.ErrTxnTooLarge.GenWithStackByArgs(size)
@@ -274,8 +307,9 @@ func (c *twoPhaseCommitter) doActionOnKeys(bo *Backoffer, action twoPhaseCommitA
 	firstIsPrimary := bytes.Equal(keys[0], c.primary())
 	_, actionIsCommit := action.(actionCommit)
 	_, actionIsCleanup := action.(actionCleanup)
-	if firstIsPrimary && (actionIsCommit || actionIsCleanup) {
-		// primary should be committed/cleanup first
+	_, actionIsPessimisticLock := action.(actionPessimisticLock)
+	if firstIsPrimary && (actionIsCommit || actionIsCleanup || actionIsPessimisticLock) {
+		// primary should be committed/cleanup/pessimistically locked first
 		err = c.doActionOnBatches(bo, action, batches[:1])
 		if err != nil {
 			return errors.Trace(err)
@@ -347,9 +381,16 @@ func (c *twoPhaseCommitter) keySize(key []byte) int {
 
 func (c *twoPhaseCommitter) buildPrewriteRequest(batch batchKeys) *tikvrpc.Request {
 	mutations := make([]*pb.Mutation, len(batch.keys))
+	var isPessimisticLock []bool
+	if c.isPessimistic {
+		isPessimisticLock = make([]bool, len(batch.keys))
+	}
 	for i, k := range batch.keys {
 		tmp := c.mutations[string(k)]
 		mutations[i] = &tmp.Mutation
+		if tmp.isPessimisticLock {
+			isPessimisticLock[i] = true
+		}
 	}
 
 	req := &pb.PrewriteRequest{
@@ -358,6 +399,13 @@ func (c *twoPhaseCommitter) buildPrewriteRequest(batch batchKeys) *tikvrpc.Reque
 		StartVersion: c.startTS,
 		LockTtl:      c.lockTTL,
 	}
+	if c.isPessimistic {
+		return tikvrpc.NewRequest(tikvrpc.CmdPessimisticPrewrite, &tikvrpc.PessimisticPrewriteRequest{
+			PrewriteRequest:   req,
+			IsPessimisticLock: isPessimisticLock,
+			ForUpdateTs:       c.forUpdateTS,
+		}, pb.Context{})
+	}
 	return tikvrpc.NewRequest(tikvrpc.CmdPrewrite, req, pb.Context{})
 }
 
@@ -529,6 +577,124 @@ func (actionCleanup) handleSingleBatch(c *twoPhaseCommitter, bo *Backoffer, batc
 	return nil
 }
 
+func (action actionPessimisticLock) handleSingleBatch(c *twoPhaseCommitter, bo *Backoffer, batch batchKeys) error {
+	// The lock wait time of the request is the time TiKV waits for the lock to be released before
+	// returning the lock error, the total wait time is controlled by the loop below.
+	lockWaitStartTime := time.Now()
+	for {
+		// The lock TTL is increased by the time the transaction has lived, because there is no
+		// heartbeat to keep the pessimistic locks of a long transaction alive.
+		elapsed := uint64(time.Since(c.txn.startTime) / time.Millisecond)
+		req := tikvrpc.NewRequest(tikvrpc.CmdPessimisticLock, &tikvrpc.PessimisticLockRequest{
+			Keys:         batch.keys,
+			PrimaryLock:  c.primary(),
+			StartVersion: c.startTS,
+			ForUpdateTs:  c.forUpdateTS,
+			LockTtl:      elapsed + ManagedLockTTL,
+			WaitTimeout:  action.LockWaitTime,
+		}, pb.Context{})
+		resp, err := c.store.SendReq(bo, req, batch.region, readTimeoutShort)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		regionErr, err := resp.GetRegionError()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if regionErr != nil {
+			err = bo.Backoff(BoRegionMiss, errors.New(regionErr.String()))
+			if err != nil {
+				return errors.Trace(err)
+			}
+			err = c.pessimisticLockKeys(bo, action.LockCtx, batch.keys)
+			return errors.Trace(err)
+		}
+		if resp.Resp == nil {
+			return errors.Trace(ErrBodyMissing)
+		}
+		lockResp := resp.Resp.(*tikvrpc.PessimisticLockResponse)
+		// Check deadlock error
+		if deadlock := lockResp.Deadlock; deadlock != nil {
+			logutil.BgLogger().Info("pessimistic lock encounters deadlock",
+				zap.Uint64("conn", c.connID),
+				zap.Uint64("txnStartTS", c.startTS),
+				zap.Uint64("lockTS", deadlock.LockTs),
+				zap.Uint64("deadlockKeyHash", deadlock.DeadlockKeyHash))
+			return errors.Trace(ErrLockDeadlock)
+		}
+		keyErrs := lockResp.Errors
+		if len(keyErrs) == 0 {
+			return nil
+		}
+		var locks []*Lock
+		for _, keyErr := range keyErrs {
+			// Extract lock from key error
+			lock, err1 := extractLockFromKeyErr(keyErr)
+			if err1 != nil {
+				return errors.Trace(err1)
+			}
+			locks = append(locks, lock)
+		}
+		// Because we already waited on tikv, no need to Backoff here.
+		msBeforeTxnExpired, _, err := c.store.lockResolver.ResolveLocks(bo, 0, locks)
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		// If msBeforeTxnExpired is not zero, it means there are still locks blocking us acquiring
+		// the pessimistic lock. We should return acquire fail with nowait set or timeout error if necessary.
+		if msBeforeTxnExpired > 0 {
+			if action.LockWaitTime == kv.LockNoWait {
+				return errors.Trace(ErrLockAcquireFailAndNoWaitSet)
+			} else if action.LockWaitTime == kv.LockAlwaysWait {
+				// do nothing but keep wait
+			} else {
+				// the lockWaitTime is set, we should return wait timeout if we are still blocked by a lock
+				if int64(time.Since(lockWaitStartTime)/time.Millisecond) >= action.LockWaitTime {
+					return errors.Trace(ErrLockWaitTimeout)
+				}
+			}
+		}
+
+		// Handle the killed flag when waiting for the pessimistic lock.
+		// When a txn runs into LockKeys() and backoff here, it has no chance to call
+		// executor.Next() and check the killed flag.
+		if action.Killed != nil {
+			// Do not reset the killed flag here!
+			// actionPessimisticLock runs on each region parallelly, we have to consider that
+			// the error may be dropped.
+			if atomic.LoadUint32(action.Killed) == 1 {
+				return errors.Trace(ErrQueryInterrupted)
+			}
+		}
+	}
+}
+
+func (actionPessimisticRollback) handleSingleBatch(c *twoPhaseCommitter, bo *Backoffer, batch batchKeys) error {
+	req := tikvrpc.NewRequest(tikvrpc.CmdPessimisticRollback, &tikvrpc.PessimisticRollbackRequest{
+		StartVersion: c.startTS,
+		ForUpdateTs:  c.forUpdateTS,
+		Keys:         batch.keys,
+	}, pb.Context{})
+	resp, err := c.store.SendReq(bo, req, batch.region, readTimeoutShort)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	regionErr, err := resp.GetRegionError()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if regionErr != nil {
+		err = bo.Backoff(BoRegionMiss, errors.New(regionErr.String()))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		err = c.pessimisticRollbackKeys(bo, batch.keys)
+		return errors.Trace(err)
+	}
+	return nil
+}
+
 func (c *twoPhaseCommitter) prewriteKeys(bo *Backoffer, keys [][]byte) error {
 	return c.doActionOnKeys(bo, actionPrewrite{}, keys)
 }
@@ -541,6 +707,14 @@ func (c *twoPhaseCommitter) cleanupKeys(bo *Backoffer, keys [][]byte) error {
 	return c.doActionOnKeys(bo, actionCleanup{}, keys)
 }
 
+func (c *twoPhaseCommitter) pessimisticLockKeys(bo *Backoffer, lockCtx *kv.LockCtx, keys [][]byte) error {
+	return c.doActionOnKeys(bo, actionPessimisticLock{lockCtx}, keys)
+}
+
+func (c *twoPhaseCommitter) pessimisticRollbackKeys(bo *Backoffer, keys [][]byte) error {
+	return c.doActionOnKeys(bo, actionPessimisticRollback{}, keys)
+}
+
 // execute executes the two-phase commit protocol.
 func (c *twoPhaseCommitter) execute(ctx context.Context) (err error) {
 	defer func() {
@@ -18,11 +18,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"sync"
+	"unsafe"
+
 	pb "github.com/pingcap-incubator/tinykv/proto/pkg/kvrpcpb"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/kv"
-	"strings"
 
 	"github.com/pingcap/tidb/store/tikv/tikvrpc"
 	"github.com/pingcap/tidb/tablecodec"
@@ -36,6 +39,7 @@ var (
 
 const (
 	scanBatchSize = 256
+	batchGetSize  = 5120
 )
 
 // tikvSnapshot implements the kv.Snapshot interface.
@@ -45,6 +49,8 @@ type tikvSnapshot struct {
 	syncLog bool
 	keyOnly bool
 	vars    *kv.Variables
+	// isolationLevel is SI by default, the locks are ignored when reading in RC.
+	isolationLevel kv.IsoLevel
 	minCommitTSPushed
 
 	// Cache the result of BatchGet.
@@ -77,6 +83,132 @@ func (s *tikvSnapshot) setSnapshotTS(ts uint64) {
 	s.minCommitTSPushed.data = make(map[uint64]struct{}, 5)
 }
 
+// BatchGet gets all the keys' value from kv-server and returns a map contains key/value pairs.
+// The map will not contain nonexistent keys.
+func (s *tikvSnapshot) BatchGet(ctx context.Context, keys []kv.Key) (map[string][]byte, error) {
+	m := make(map[string][]byte, len(keys))
+	// Check the cached values first, only the missed keys are sent to kv-server.
+	if s.cached != nil {
+		missed := make([]kv.Key, 0, len(keys))
+		for _, k := range keys {
+			if val, ok := s.cached[string(k)]; ok {
+				if len(val) > 0 {
+					m[string(k)] = val
+				}
+				continue
+			}
+			missed = append(missed, k)
+		}
+		keys = missed
+	}
+	if len(keys) == 0 {
+		return m, nil
+	}
+
+	// We want [][]byte instead of []kv.Key, use some magic to save memory.
+	bytesKeys := *(*[][]byte)(unsafe.Pointer(&keys))
+	ctx = context.WithValue(ctx, txnStartKey, s.version.Ver)
+	bo := NewBackoffer(ctx, batchGetMaxBackoff)
+
+	// Create a map to collect key-values from region servers.
+	var mu sync.Mutex
+	err := s.batchGetKeysByRegions(bo, bytesKeys, func(k, v []byte) {
+		if len(v) == 0 {
+			return
+		}
+		mu.Lock()
+		m[string(k)] = v
+		mu.Unlock()
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	err = s.store.CheckVisibility(s.version.Ver)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Update the cache, a nonexistent key is cached as an empty value.
+	if s.cached == nil {
+		s.cached = make(map[string][]byte, len(keys))
+	}
+	for _, k := range keys {
+		s.cached[string(k)] = m[string(k)]
+	}
+	return m, nil
+}
+
+func (s *tikvSnapshot) batchGetKeysByRegions(bo *Backoffer, keys [][]byte, collectF func(k, v []byte)) error {
+	groups, _, err := s.store.regionCache.GroupKeysByRegion(bo, keys, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	var batches []batchKeys
+	for id, g := range groups {
+		batches = appendBatchBySize(batches, id, g, func([]byte) int { return 1 }, batchGetSize)
+	}
+
+	if len(batches) == 0 {
+		return nil
+	}
+	if len(batches) == 1 {
+		return errors.Trace(s.batchGetSingleRegion(bo, batches[0], collectF))
+	}
+	ch := make(chan error, len(batches))
+	for _, batch1 := range batches {
+		batch := batch1
+		go func() {
+			backoffer, cancel := bo.Fork()
+			defer cancel()
+			ch <- s.batchGetSingleRegion(backoffer, batch, collectF)
+		}()
+	}
+	for i := 0; i < len(batches); i++ {
+		if e := <-ch; e != nil {
+			logutil.BgLogger().Debug("snapshot batchGet failed",
+				zap.Error(e),
+				zap.Uint64("txnStartTS", s.version.Ver))
+			err = e
+		}
+	}
+	return errors.Trace(err)
+}
+
+// batchGetSingleRegion reads the keys of a batch in the same region. TinyKV has
+// no batch read command, so one KvGet request is sent for each key concurrently.
+func (s *tikvSnapshot) batchGetSingleRegion(bo *Backoffer, batch batchKeys, collectF func(k, v []byte)) error {
+	if len(batch.keys) == 1 {
+		val, err := s.get(bo, batch.keys[0])
+		if err != nil {
+			return errors.Trace(err)
+		}
+		collectF(batch.keys[0], val)
+		return nil
+	}
+	ch := make(chan error, len(batch.keys))
+	for _, k1 := range batch.keys {
+		k := k1
+		go func() {
+			backoffer, cancel := bo.Fork()
+			defer cancel()
+			val, err := s.get(backoffer, k)
+			if err == nil {
+				collectF(k, val)
+			}
+			ch <- err
+		}()
+	}
+	var err error
+	for i := 0; i < len(batch.keys); i++ {
+		if e := <-ch; e != nil {
+			err = e
+		}
+	}
+	return errors.Trace(err)
+}
+
 // Get gets the value for key k from snapshot.
 func (s *tikvSnapshot) Get(ctx context.Context, k kv.Key) ([]byte, error) {
 	ctx = context.WithValue(ctx, txnStartKey, s.version.Ver)
@@ -146,6 +278,13 @@ func (s *tikvSnapshot) get(bo *Backoffer, k kv.Key) ([]byte, error) {
 			if err != nil {
 				return nil, errors.Trace(err)
 			}
+			if s.isolationLevel == kv.RC {
+				// TinyKV has no isolation level in the request context. Every committed version
+				// of the key is older than the lock, so reading right before the start ts of the
+				// lock gets the latest committed value just like ignoring the lock.
+				req.Get().Version = lock.TxnID - 1
+				continue
+			}
 			msBeforeExpired, err := cli.ResolveLocks(bo, s.version.Ver, []*Lock{lock})
 			if err != nil {
 				return nil, errors.Trace(err)
@@ -19,6 +19,8 @@ import (
 	"time"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/kv"
 )
 
 type testSnapshotSuite struct {
@@ -61,9 +63,117 @@ func (s *testSnapshotSuite) beginTxn(c *C) *tikvTxn {
 	return txn.(*tikvTxn)
 }
 
+func (s *testSnapshotSuite) checkAll(keys []kv.Key, c *C) {
+	txn := s.beginTxn(c)
+	snapshot := newTiKVSnapshot(s.store, kv.Version{Ver: txn.StartTS()})
+	m, err := snapshot.BatchGet(context.Background(), keys)
+	c.Assert(err, IsNil)
+
+	scan, err := txn.Iter(encodeKey(s.prefix, ""), nil)
+	c.Assert(err, IsNil)
+	cnt := 0
+	for scan.Valid() {
+		cnt++
+		k := scan.Key()
+		v := scan.Value()
+		v2, ok := m[string(k)]
+		c.Assert(ok, IsTrue, Commentf("key: %q", k))
+		c.Assert(v, BytesEquals, v2)
+		scan.Next()
+	}
+	err = txn.Commit(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(m, HasLen, cnt)
+}
+
+func (s *testSnapshotSuite) deleteKeys(keys []kv.Key, c *C) {
+	txn := s.beginTxn(c)
+	for _, k := range keys {
+		err := txn.Delete(k)
+		c.Assert(err, IsNil)
+	}
+	err := txn.Commit(context.Background())
+	c.Assert(err, IsNil)
+}
+
+func (s *testSnapshotSuite) TestBatchGet(c *C) {
+	for _, rowNum := range s.rowNums {
+		txn := s.beginTxn(c)
+		keys := make([]kv.Key, 0, rowNum+1)
+		for i := 0; i < rowNum; i++ {
+			k := encodeKey(s.prefix, s08d("key", i))
+			err := txn.Set(k, valueBytes(i))
+			c.Assert(err, IsNil)
+			keys = append(keys, k)
+		}
+		err := txn.Commit(context.Background())
+		c.Assert(err, IsNil)
+
+		// A nonexistent key is not returned.
+		s.checkAll(append(keys, encodeKey(s.prefix, s08d("key", rowNum))), c)
+		s.deleteKeys(keys, c)
+	}
+}
+
+func (s *testSnapshotSuite) TestSnapshotCache(c *C) {
+	txn := s.beginTxn(c)
+	c.Assert(txn.Set(kv.Key("x"), []byte("x")), IsNil)
+	c.Assert(txn.Delete(kv.Key("y")), IsNil) // store data is affected by other tests.
+	c.Assert(txn.Commit(context.Background()), IsNil)
+
+	txn = s.beginTxn(c)
+	snapshot := newTiKVSnapshot(s.store, kv.Version{Ver: txn.StartTS()})
+	_, err := snapshot.BatchGet(context.Background(), []kv.Key{kv.Key("x"), kv.Key("y")})
+	c.Assert(err, IsNil)
+
+	// The later reads are served by the cache of BatchGet.
+	c.Assert(failpoint.Enable("github.com/pingcap/tidb/store/tikv/snapshot-get-cache-fail", `return(true)`), IsNil)
+	ctx := context.WithValue(context.Background(), "TestSnapshotCache", true)
+	_, err = snapshot.Get(ctx, kv.Key("x"))
+	c.Assert(err, IsNil)
+
+	_, err = snapshot.Get(ctx, kv.Key("y"))
+	c.Assert(kv.IsErrNotFound(err), IsTrue)
+
+	m, err := snapshot.BatchGet(ctx, []kv.Key{kv.Key("x"), kv.Key("y")})
+	c.Assert(err, IsNil)
+	c.Assert(m, HasLen, 1)
+	c.Assert(m["x"], BytesEquals, []byte("x"))
+
+	c.Assert(failpoint.Disable("github.com/pingcap/tidb/store/tikv/snapshot-get-cache-fail"), IsNil)
+}
+
 func (s *testSnapshotSuite) TestLockNotFoundPrint(c *C) {
 	msg := "Txn(Mvcc(TxnLockNotFound { start_ts: 408090278408224772, commit_ts: 408090279311835140, " +
 		"key: [116, 128, 0, 0, 0, 0, 0, 50, 137, 95, 105, 128, 0, 0, 0, 0,0 ,0, 1, 1, 67, 49, 57, 48, 57, 50, 57, 48, 255, 48, 48, 48, 48, 48, 52, 56, 54, 255, 50, 53, 53, 50, 51, 0, 0, 0, 252] }))"
 	key := prettyLockNotFoundKey(msg)
 	c.Assert(key, Equals, "{tableID=12937, indexID=1, indexValues={C19092900000048625523, }}")
 }
+
+func (s *testSnapshotSuite) TestReadCommittedIgnoreLock(c *C) {
+	k := encodeKey(s.prefix, "read_committed")
+	txn := s.beginTxn(c)
+	c.Assert(txn.Set(k, []byte("v1")), IsNil)
+	c.Assert(txn.Commit(context.Background()), IsNil)
+
+	// Leave a lock on the key.
+	txn1 := s.beginTxn(c)
+	c.Assert(txn1.Set(k, []byte("v2")), IsNil)
+	committer, err := newTwoPhaseCommitterWithInit(txn1, 0)
+	c.Assert(err, IsNil)
+	err = committer.prewriteKeys(NewBackoffer(context.Background(), PrewriteMaxBackoff), committer.keys)
+	c.Assert(err, IsNil)
+
+	// The lock is ignored in RC, the value committed before it is read.
+	txn2 := s.beginTxn(c)
+	snapshot := newTiKVSnapshot(s.store, kv.Version{Ver: txn2.StartTS()})
+	snapshot.isolationLevel = kv.RC
+	v, err := snapshot.Get(context.Background(), k)
+	c.Assert(err, IsNil)
+	c.Assert(v, BytesEquals, []byte("v1"))
+	m, err := snapshot.BatchGet(context.Background(), []kv.Key{k})
+	c.Assert(err, IsNil)
+	c.Assert(m[string(k)], BytesEquals, []byte("v1"))
+
+	c.Assert(committer.cleanupKeys(NewBackoffer(context.Background(), cleanupMaxBackoff), committer.keys), IsNil)
+}
@@ -0,0 +1,102 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tikvrpc
+
+import (
+	"github.com/pingcap-incubator/tinykv/proto/pkg/errorpb"
+	"github.com/pingcap-incubator/tinykv/proto/pkg/kvrpcpb"
+)
+
+// The kvrpcpb protocol of TinyKV has no pessimistic lock, the requests and responses
+// of the pessimistic transactions are defined here in the same shape as the ones of
+// kvrpcpb. They are served by mocktikv only, a TinyKV server rejects them.
+
+// PessimisticLockRequest acquires the pessimistic locks of the keys.
+type PessimisticLockRequest struct {
+	Context      *kvrpcpb.Context
+	Keys         [][]byte
+	PrimaryLock  []byte
+	StartVersion uint64
+	ForUpdateTs  uint64
+	LockTtl      uint64
+	// WaitTimeout is the time in milliseconds to wait for the locks of other transactions,
+	// a negative value means the lock error is returned without waiting.
+	WaitTimeout int64
+}
+
+// Size returns the approximate size of the request.
+func (r *PessimisticLockRequest) Size() int {
+	size := len(r.PrimaryLock)
+	for _, key := range r.Keys {
+		size += len(key)
+	}
+	return size
+}
+
+// Deadlock describes the deadlock found when a pessimistic lock request waits for a lock.
+type Deadlock struct {
+	LockTs          uint64
+	LockKey         []byte
+	DeadlockKeyHash uint64
+}
+
+// PessimisticLockResponse is the response of PessimisticLockRequest.
+type PessimisticLockResponse struct {
+	RegionError *errorpb.Error
+	Errors      []*kvrpcpb.KeyError
+	// Deadlock is not nil if waiting for the lock of the keys leads to a deadlock.
+	Deadlock *Deadlock
+}
+
+// GetRegionError returns the region error of the response.
+func (r *PessimisticLockResponse) GetRegionError() *errorpb.Error {
+	return r.RegionError
+}
+
+// PessimisticRollbackRequest releases the pessimistic locks of the keys.
+type PessimisticRollbackRequest struct {
+	Context      *kvrpcpb.Context
+	StartVersion uint64
+	ForUpdateTs  uint64
+	Keys         [][]byte
+}
+
+// Size returns the approximate size of the request.
+func (r *PessimisticRollbackRequest) Size() int {
+	size := 0
+	for _, key := range r.Keys {
+		size += len(key)
+	}
+	return size
+}
+
+// PessimisticRollbackResponse is the response of PessimisticRollbackRequest.
+type PessimisticRollbackResponse struct {
+	RegionError *errorpb.Error
+	Errors      []*kvrpcpb.KeyError
+}
+
+// GetRegionError returns the region error of the response.
+func (r *PessimisticRollbackResponse) GetRegionError() *errorpb.Error {
+	return r.RegionError
+}
+
+// PessimisticPrewriteRequest prewrites the keys of a pessimistic transaction, its response
+// is a kvrpcpb.PrewriteResponse.
+type PessimisticPrewriteRequest struct {
+	*kvrpcpb.PrewriteRequest
+	// IsPessimisticLock marks the mutations whose keys are locked by the pessimistic locks.
+	IsPessimisticLock []bool
+	ForUpdateTs       uint64
+}
@@ -37,6 +37,9 @@ const (
 	CmdBatchRollback
 	CmdResolveLock
 	CmdCheckTxnStatus
+	CmdPessimisticPrewrite
+	CmdPessimisticLock
+	CmdPessimisticRollback
 
 	CmdRawGet CmdType = 256 + iota
 	CmdRawPut
@@ -72,6 +75,12 @@ func (t CmdType) String() string {
 		return "Cop"
 	case CmdCheckTxnStatus:
 		return "CheckTxnStatus"
+	case CmdPessimisticPrewrite:
+		return "PessimisticPrewrite"
+	case CmdPessimisticLock:
+		return "PessimisticLock"
+	case CmdPessimisticRollback:
+		return "PessimisticRollback"
 	}
 	return "Unknown"
 }
@@ -159,6 +168,21 @@ func (req *Request) CheckTxnStatus() *kvrpcpb.CheckTxnStatusRequest {
 	return req.req.(*kvrpcpb.CheckTxnStatusRequest)
 }
 
+// PessimisticPrewrite returns PessimisticPrewriteRequest in request.
+func (req *Request) PessimisticPrewrite() *PessimisticPrewriteRequest {
+	return req.req.(*PessimisticPrewriteRequest)
+}
+
+// PessimisticLock returns PessimisticLockRequest in request.
+func (req *Request) PessimisticLock() *PessimisticLockRequest {
+	return req.req.(*PessimisticLockRequest)
+}
+
+// PessimisticRollback returns PessimisticRollbackRequest in request.
+func (req *Request) PessimisticRollback() *PessimisticRollbackRequest {
+	return req.req.(*PessimisticRollbackRequest)
+}
+
 // Response wraps all kv/coprocessor responses.
 type Response struct {
 	Resp interface{}
@@ -198,6 +222,12 @@ func SetContext(req *Request, region *metapb.Region, peer *metapb.Peer) error {
 		req.Cop().Context = ctx
 	case CmdCheckTxnStatus:
 		req.CheckTxnStatus().Context = ctx
+	case CmdPessimisticPrewrite:
+		req.PessimisticPrewrite().Context = ctx
+	case CmdPessimisticLock:
+		req.PessimisticLock().Context = ctx
+	case CmdPessimisticRollback:
+		req.PessimisticRollback().Context = ctx
 	default:
 		return fmt.Errorf("invalid request type %v", req.Type)
 	}
@@ -258,6 +288,18 @@ func GenRegionErrorResp(req *Request, e *errorpb.Error) (*Response, error) {
 		p = &kvrpcpb.CheckTxnStatusResponse{
 			RegionError: e,
 		}
+	case CmdPessimisticPrewrite:
+		p = &kvrpcpb.PrewriteResponse{
+			RegionError: e,
+		}
+	case CmdPessimisticLock:
+		p = &PessimisticLockResponse{
+			RegionError: e,
+		}
+	case CmdPessimisticRollback:
+		p = &PessimisticRollbackResponse{
+			RegionError: e,
+		}
 	default:
 		return nil, fmt.Errorf("invalid request type %v", req.Type)
 	}
@@ -312,6 +354,8 @@ func CallRPC(ctx context.Context, client tikvpb.TikvClient, req *Request) (*Resp
 		resp.Resp, err = client.Coprocessor(ctx, req.Cop())
 	case CmdCheckTxnStatus:
 		resp.Resp, err = client.KvCheckTxnStatus(ctx, req.CheckTxnStatus())
+	case CmdPessimisticPrewrite, CmdPessimisticLock, CmdPessimisticRollback:
+		return nil, errors.Errorf("request type %v is not supported by TinyKV", req.Type)
 	default:
 		return nil, errors.Errorf("invalid request type: %v", req.Type)
 	}
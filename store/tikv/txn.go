@@ -22,7 +22,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/kv"
-
+	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/util/logutil"
 	"go.uber.org/zap"
@@ -143,6 +143,8 @@ func (txn *tikvTxn) SetOption(opt kv.Option, val interface{}) {
 		txn.snapshot.keyOnly = val.(bool)
 	case kv.SnapshotTS:
 		txn.snapshot.setSnapshotTS(val.(uint64))
+	case kv.IsolationLevel:
+		txn.snapshot.isolationLevel = val.(kv.IsoLevel)
 	}
 }
 
@@ -197,11 +199,30 @@ func (txn *tikvTxn) Rollback() error {
 	if !txn.valid {
 		return kv.ErrInvalidTxn
 	}
+	// Clean up pessimistic lock.
+	if txn.IsPessimistic() && txn.committer != nil {
+		err := txn.rollbackPessimisticLocks()
+		if err != nil {
+			logutil.BgLogger().Error(err.Error())
+		}
+	}
 	txn.close()
 	logutil.BgLogger().Debug("[kv] rollback txn", zap.Uint64("txnStartTS", txn.StartTS()))
 	return nil
 }
 
+// IsPessimistic returns true if it is pessimistic.
+func (txn *tikvTxn) IsPessimistic() bool {
+	return txn.us.GetOption(kv.Pessimistic) != nil
+}
+
+func (txn *tikvTxn) rollbackPessimisticLocks() error {
+	if len(txn.lockKeys) == 0 {
+		return nil
+	}
+	return txn.committer.pessimisticRollbackKeys(NewBackoffer(context.Background(), cleanupMaxBackoff), txn.lockKeys)
+}
+
 // lockWaitTime in ms, except that kv.LockAlwaysWait(0) means always wait lock, kv.LockNowait(-1) means nowait lock
 func (txn *tikvTxn) LockKeys(ctx context.Context, lockCtx *kv.LockCtx, keysInput ...kv.Key) error {
 	// Exclude keys that are already locked.
@@ -216,6 +237,46 @@ func (txn *tikvTxn) LockKeys(ctx context.Context, lockCtx *kv.LockCtx, keysInput
 	if len(keys) == 0 {
 		return nil
 	}
+	if txn.IsPessimistic() && lockCtx.ForUpdateTS > 0 {
+		if txn.committer == nil {
+			// connID is used for log.
+			var connID uint64
+			var err error
+			val := ctx.Value(sessionctx.ConnID)
+			if val != nil {
+				connID = val.(uint64)
+			}
+			txn.committer, err = newTwoPhaseCommitter(txn, connID)
+			if err != nil {
+				return err
+			}
+		}
+		var assignedPrimaryKey bool
+		if txn.committer.primaryKey == nil {
+			txn.committer.primaryKey = keys[0]
+			assignedPrimaryKey = true
+		}
+
+		bo := NewBackoffer(ctx, pessimisticLockMaxBackoff).WithVars(txn.vars)
+		txn.committer.forUpdateTS = lockCtx.ForUpdateTS
+		err := txn.committer.pessimisticLockKeys(bo, lockCtx, keys)
+		if err != nil {
+			keyMayBeLocked := terror.ErrorNotEqual(kv.ErrWriteConflict, err)
+			// If there is only 1 key and lock fails, no need to do pessimistic rollback.
+			if len(keys) > 1 || keyMayBeLocked {
+				wg := txn.asyncPessimisticRollback(ctx, keys)
+				if ErrLockDeadlock.Equal(err) {
+					// Wait for the rollback to finish, so the blocked transaction can get the lock.
+					wg.Wait()
+				}
+			}
+			if assignedPrimaryKey {
+				// unset the primary key if we assigned primary key when failed to lock it.
+				txn.committer.primaryKey = nil
+			}
+			return err
+		}
+	}
 	txn.mu.Lock()
 	txn.lockKeys = append(txn.lockKeys, keys...)
 	for _, key := range keys {
@@ -226,6 +287,27 @@ func (txn *tikvTxn) LockKeys(ctx context.Context, lockCtx *kv.LockCtx, keysInput
 	return nil
 }
 
+func (txn *tikvTxn) asyncPessimisticRollback(ctx context.Context, keys [][]byte) *sync.WaitGroup {
+	// Clone a new committer for execute in background.
+	committer := &twoPhaseCommitter{
+		store:       txn.committer.store,
+		connID:      txn.committer.connID,
+		startTS:     txn.committer.startTS,
+		forUpdateTS: txn.committer.forUpdateTS,
+		primaryKey:  txn.committer.primaryKey,
+	}
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		err := committer.pessimisticRollbackKeys(NewBackoffer(ctx, pessimisticRollbackMaxBackoff), keys)
+		if err != nil {
+			logutil.Logger(ctx).Warn("[kv] pessimisticRollback failed.", zap.Error(err))
+		}
+		wg.Done()
+	}()
+	return wg
+}
+
 func (txn *tikvTxn) IsReadOnly() bool {
 	return !txn.dirty
 }
@@ -249,3 +331,7 @@ func (txn *tikvTxn) Size() int {
 func (txn *tikvTxn) GetMemBuffer() kv.MemBuffer {
 	return txn.us.GetMemBuffer()
 }
+
+func (txn *tikvTxn) GetSnapshot() kv.Snapshot {
+	return txn.snapshot
+}
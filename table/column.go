@@ -20,8 +20,10 @@ package table
 import (
 	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
 
+	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/model"
@@ -30,6 +32,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/logutil"
 	"go.uber.org/zap"
@@ -328,9 +331,38 @@ func getColDefaultValue(ctx sessionctx.Context, col *model.ColumnInfo, defaultVa
 		return getColDefaultValueFromNil(ctx, col)
 	}
 
-	value, err := CastValue(ctx, types.NewDatum(defaultVal), col)
+	if col.Tp != mysql.TypeTimestamp && col.Tp != mysql.TypeDatetime {
+		value, err := CastValue(ctx, types.NewDatum(defaultVal), col)
+		if err != nil {
+			return types.Datum{}, err
+		}
+		return value, nil
+	}
+
+	// Check and get timestamp/datetime default value.
+	sc := ctx.GetSessionVars().StmtCtx
+	var needChangeTimeZone bool
+	// If the column's default value is not ZeroDatetimeStr nor CurrentTimestamp, it is
+	// stored in UTC, so it should be parsed in UTC and converted to the session time zone.
+	if col.Tp == mysql.TypeTimestamp {
+		if vv, ok := defaultVal.(string); ok && vv != types.ZeroDatetimeStr && !strings.EqualFold(vv, ast.CurrentTimestamp) {
+			needChangeTimeZone = true
+			originalTZ := sc.TimeZone
+			sc.TimeZone = time.UTC
+			defer func() { sc.TimeZone = originalTZ }()
+		}
+	}
+	value, err := expression.GetTimeValue(ctx, defaultVal, col.Tp, int8(col.Decimal))
 	if err != nil {
-		return types.Datum{}, err
+		return types.Datum{}, errGetDefaultFailed.GenWithStackByArgs(col.Name)
+	}
+	if needChangeTimeZone {
+		t := value.GetMysqlTime()
+		err = t.ConvertTimeZone(time.UTC, ctx.GetSessionVars().Location())
+		if err != nil {
+			return value, err
+		}
+		value.SetMysqlTime(t)
 	}
 	return value, nil
 }
@@ -370,6 +402,10 @@ func GetZeroValue(col *model.ColumnInfo) types.Datum {
 		d.SetFloat32(0)
 	case mysql.TypeDouble:
 		d.SetFloat64(0)
+	case mysql.TypeNewDecimal:
+		d.SetLength(col.Flen)
+		d.SetFrac(col.Decimal)
+		d.SetMysqlDecimal(new(types.MyDecimal))
 	case mysql.TypeString:
 		if col.Flen > 0 && col.Charset == charset.CharsetBin {
 			d.SetBytes(make([]byte, col.Flen))
@@ -380,6 +416,27 @@ func GetZeroValue(col *model.ColumnInfo) types.Datum {
 		d.SetString("")
 	case mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
 		d.SetBytes([]byte{})
+	case mysql.TypeDuration:
+		d.SetMysqlDuration(types.ZeroDuration)
+	case mysql.TypeDate:
+		d.SetMysqlTime(types.ZeroDate)
+	case mysql.TypeTimestamp:
+		d.SetMysqlTime(types.ZeroTimestamp)
+	case mysql.TypeDatetime:
+		d.SetMysqlTime(types.ZeroDatetime)
+	case mysql.TypeBit:
+		d.SetMysqlBit(types.ZeroBinaryLiteral)
+	case mysql.TypeSet:
+		d.SetMysqlSet(types.Set{})
+	case mysql.TypeEnum:
+		// The implicit default of a NOT NULL enum is its first element.
+		if len(col.Elems) > 0 {
+			d.SetMysqlEnum(types.Enum{Name: col.Elems[0], Value: 1})
+		} else {
+			d.SetMysqlEnum(types.Enum{})
+		}
+	case mysql.TypeJSON:
+		d.SetMysqlJSON(json.CreateBinary(nil))
 	}
 	return d
 }
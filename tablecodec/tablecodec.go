@@ -169,7 +169,10 @@ func EncodeRow(sc *stmtctx.StatementContext, row []types.Datum, colIDs []int64,
 	for i, c := range row {
 		id := colIDs[i]
 		values[2*i].SetInt64(id)
-		values[2*i+1] = c
+		err := flatten(sc, c, &values[2*i+1])
+		if err != nil {
+			return valBuf, errors.Trace(err)
+		}
 	}
 	if len(values) == 0 {
 		// We could not set nil value into kv.
@@ -178,6 +181,44 @@ func EncodeRow(sc *stmtctx.StatementContext, row []types.Datum, colIDs []int64,
 	return codec.EncodeValue(sc, valBuf, values...)
 }
 
+func flatten(sc *stmtctx.StatementContext, data types.Datum, ret *types.Datum) error {
+	switch data.Kind() {
+	case types.KindMysqlTime:
+		// for mysql datetime, timestamp and date type
+		t := data.GetMysqlTime()
+		if t.Type() == mysql.TypeTimestamp && sc.TimeZone != nil && sc.TimeZone != time.UTC {
+			err := t.ConvertTimeZone(sc.TimeZone, time.UTC)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		v, err := t.ToPackedUint()
+		ret.SetUint64(v)
+		return errors.Trace(err)
+	case types.KindMysqlDuration:
+		// for mysql time type
+		ret.SetInt64(int64(data.GetMysqlDuration().Duration))
+		return nil
+	case types.KindMysqlEnum:
+		ret.SetUint64(data.GetMysqlEnum().Value)
+		return nil
+	case types.KindMysqlSet:
+		ret.SetUint64(data.GetMysqlSet().Value)
+		return nil
+	case types.KindBinaryLiteral, types.KindMysqlBit:
+		// We don't need to handle errors here since the literal is ensured to be able to store in uint64 in convertToMysqlBit.
+		val, err := data.GetBinaryLiteral().ToInt(sc)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		ret.SetUint64(val)
+		return nil
+	default:
+		*ret = data
+		return nil
+	}
+}
+
 // EncodeRowKey encodes the table id and record handle into a kv.Key
 func EncodeRowKey(tableID int64, encodedHandle []byte) kv.Key {
 	buf := make([]byte, 0, RecordRowKeyLen)
@@ -296,7 +337,11 @@ func DecodeColumnValue(data []byte, ft *types.FieldType, loc *time.Location) (ty
 	if err != nil {
 		return types.Datum{}, errors.Trace(err)
 	}
-	return d, nil
+	colDatum, err := unflatten(d, ft, loc)
+	if err != nil {
+		return types.Datum{}, errors.Trace(err)
+	}
+	return colDatum, nil
 }
 
 // DecodeRowWithMap decodes a byte slice into datums with a existing row map.
@@ -400,6 +445,50 @@ func CutRowNew(data []byte, colIDs map[int64]int) ([][]byte, error) {
 
 // unflatten converts a raw datum to a column datum.
 func unflatten(datum types.Datum, ft *types.FieldType, loc *time.Location) (types.Datum, error) {
+	if datum.IsNull() {
+		return datum, nil
+	}
+	switch ft.Tp {
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		t := types.NewTime(types.ZeroCoreTime, ft.Tp, int8(ft.Decimal))
+		err := t.FromPackedUint(datum.GetUint64())
+		if err != nil {
+			return datum, errors.Trace(err)
+		}
+		if ft.Tp == mysql.TypeTimestamp && !t.IsZero() && loc != nil {
+			err = t.ConvertTimeZone(time.UTC, loc)
+			if err != nil {
+				return datum, errors.Trace(err)
+			}
+		}
+		datum.SetMysqlTime(t)
+		return datum, nil
+	case mysql.TypeDuration:
+		// duration should read fsp from column meta data
+		dur := types.Duration{Duration: time.Duration(datum.GetInt64()), Fsp: int8(ft.Decimal)}
+		datum.SetMysqlDuration(dur)
+		return datum, nil
+	case mysql.TypeEnum:
+		// ignore error deliberately, to read empty enum value.
+		enum, err := types.ParseEnumValue(ft.Elems, datum.GetUint64())
+		if err != nil {
+			enum = types.Enum{}
+		}
+		datum.SetMysqlEnum(enum)
+		return datum, nil
+	case mysql.TypeSet:
+		set, err := types.ParseSetValue(ft.Elems, datum.GetUint64())
+		if err != nil {
+			return datum, errors.Trace(err)
+		}
+		datum.SetMysqlSet(set)
+		return datum, nil
+	case mysql.TypeBit:
+		val := datum.GetUint64()
+		byteSize := (ft.Flen + 7) >> 3
+		datum.SetMysqlBit(types.NewBinaryLiteralFromUint(val, byteSize))
+		return datum, nil
+	}
 	return datum, nil
 }
 
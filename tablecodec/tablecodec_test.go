@@ -14,6 +14,7 @@
 package tablecodec
 
 import (
+	"bytes"
 	"fmt"
 	"math"
 	"testing"
@@ -100,6 +101,90 @@ func (s *testTableCodecSuite) TestCutRow(c *C) {
 	}
 }
 
+func (s *testTableCodecSuite) TestTimeCodec(c *C) {
+	defer testleak.AfterTest(c)()
+
+	c1 := &column{id: 1, tp: types.NewFieldType(mysql.TypeLonglong)}
+	c2 := &column{id: 2, tp: types.NewFieldType(mysql.TypeTimestamp)}
+	c3 := &column{id: 3, tp: types.NewFieldType(mysql.TypeDuration)}
+	c3.tp.Decimal = 2
+	cols := []*column{c1, c2, c3}
+
+	loc := time.FixedZone("UTC+8", 8*3600)
+	sc := &stmtctx.StatementContext{TimeZone: loc}
+	row := make([]types.Datum, 3)
+	row[0] = types.NewIntDatum(100)
+	ts, err := types.ParseTimestamp(sc, "2016-06-23 11:30:45")
+	c.Assert(err, IsNil)
+	row[1] = types.NewTimeDatum(ts)
+	dur, err := types.ParseDuration(sc, "-12:59:59.99", 2)
+	c.Assert(err, IsNil)
+	row[2] = types.NewDurationDatum(dur)
+
+	colIDs := make([]int64, 0, 3)
+	colMap := make(map[int64]*types.FieldType, 3)
+	for _, col := range cols {
+		colIDs = append(colIDs, col.id)
+		colMap[col.id] = col.tp
+	}
+	bs, err := EncodeRow(sc, row, colIDs, nil, nil)
+	c.Assert(err, IsNil)
+
+	// Timestamp is stored in UTC, and converted back to the session time zone.
+	r, err := DecodeRow(bs, colMap, loc)
+	c.Assert(err, IsNil)
+	tsDatum, durDatum := r[1], r[2]
+	c.Assert(tsDatum.GetMysqlTime().Compare(ts), Equals, 0)
+	c.Assert(durDatum.GetMysqlDuration(), Equals, dur)
+
+	r, err = DecodeRow(bs, colMap, time.UTC)
+	c.Assert(err, IsNil)
+	tsDatum = r[1]
+	c.Assert(tsDatum.GetMysqlTime().String(), Equals, "2016-06-23 03:30:45")
+}
+
+func (s *testTableCodecSuite) TestHybridCodec(c *C) {
+	defer testleak.AfterTest(c)()
+
+	c1 := &column{id: 1, tp: types.NewFieldType(mysql.TypeEnum)}
+	c1.tp.Elems = []string{"a", "b", "c"}
+	c2 := &column{id: 2, tp: types.NewFieldType(mysql.TypeSet)}
+	c2.tp.Elems = []string{"x", "y", "z"}
+	c3 := &column{id: 3, tp: types.NewFieldType(mysql.TypeBit)}
+	c3.tp.Flen = 10
+	cols := []*column{c1, c2, c3}
+
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	row := make([]types.Datum, 3)
+	row[0] = types.NewMysqlEnumDatum(types.Enum{Name: "b", Value: 2})
+	row[1] = types.NewDatum(types.Set{Name: "x,z", Value: 5})
+	row[2] = types.NewMysqlBitDatum(types.NewBinaryLiteralFromUint(513, 2))
+
+	colIDs := make([]int64, 0, 3)
+	colMap := make(map[int64]*types.FieldType, 3)
+	for _, col := range cols {
+		colIDs = append(colIDs, col.id)
+		colMap[col.id] = col.tp
+	}
+	bs, err := EncodeRow(sc, row, colIDs, nil, nil)
+	c.Assert(err, IsNil)
+
+	r, err := DecodeRow(bs, colMap, time.UTC)
+	c.Assert(err, IsNil)
+	enumDatum, setDatum, bitDatum := r[1], r[2], r[3]
+	c.Assert(enumDatum.GetMysqlEnum(), Equals, types.Enum{Name: "b", Value: 2})
+	c.Assert(setDatum.GetMysqlSet(), Equals, types.Set{Name: "x,z", Value: 5})
+	c.Assert(bitDatum.Kind(), Equals, types.KindMysqlBit)
+	c.Assert(bitDatum.GetMysqlBit(), DeepEquals, types.NewBinaryLiteralFromUint(513, 2))
+
+	// Index keys of ENUM values are ordered by the element index, not by the name.
+	k1, err := codec.EncodeKey(sc, nil, types.NewMysqlEnumDatum(types.Enum{Name: "z", Value: 1}))
+	c.Assert(err, IsNil)
+	k2, err := codec.EncodeKey(sc, nil, types.NewMysqlEnumDatum(types.Enum{Name: "a", Value: 2}))
+	c.Assert(err, IsNil)
+	c.Assert(bytes.Compare(k1, k2), Less, 0)
+}
+
 func (s *testTableCodecSuite) TestCutKeyNew(c *C) {
 	values := []types.Datum{types.NewIntDatum(1), types.NewBytesDatum([]byte("abc")), types.NewFloat64Datum(5.5)}
 	handle := types.NewIntDatum(100)
@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/terror"
+	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/server"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx/variable"
@@ -239,6 +240,10 @@ func setGlobalVars() {
 
 	variable.SysVars[variable.Port].Value = fmt.Sprintf("%d", cfg.Port)
 	variable.SysVars[variable.DataDir].Value = cfg.Path
+
+	// A cache without capacity can hold no plan, so it is disabled.
+	plannercore.SetPreparedPlanCache(cfg.PreparedPlanCache.Enabled && cfg.PreparedPlanCache.Capacity > 0)
+	plannercore.PreparedPlanCacheCapacity = cfg.PreparedPlanCache.Capacity
 }
 
 func setupLog() {
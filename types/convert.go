@@ -25,6 +25,8 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/hack"
 )
 
 func truncateStr(str string, flen int) string {
@@ -45,7 +47,7 @@ func IntergerUnsignedUpperBound(intType byte) uint64 {
 		return mysql.MaxUint24
 	case mysql.TypeLong:
 		return math.MaxUint32
-	case mysql.TypeLonglong:
+	case mysql.TypeLonglong, mysql.TypeBit, mysql.TypeEnum, mysql.TypeSet:
 		return math.MaxUint64
 	default:
 		panic("Input byte is not a mysql type")
@@ -173,6 +175,26 @@ func ConvertFloatToUint(sc *stmtctx.StatementContext, fval float64, upperBound u
 	return uint64(val), nil
 }
 
+// ConvertDecimalToUint converts a decimal value to an uint value, the decimal is rounded to integer first.
+func ConvertDecimalToUint(sc *stmtctx.StatementContext, d *MyDecimal, upperBound uint64, tp byte) (uint64, error) {
+	var to MyDecimal
+	if err := d.Round(&to, 0, ModeHalfEven); err != nil {
+		return 0, errors.Trace(err)
+	}
+	if to.IsNegative() {
+		val, err := to.ToInt()
+		if err != nil {
+			return 0, overflow(d, tp)
+		}
+		return ConvertIntToUint(sc, val, upperBound, tp)
+	}
+	val, err := to.ToUint()
+	if err != nil {
+		return upperBound, overflow(d, tp)
+	}
+	return ConvertUintToUint(val, upperBound, tp)
+}
+
 // convertScientificNotation converts a decimal string with scientific notation to a normal decimal string.
 // 1E6 => 1000000, .12345E+5 => 12345
 func convertScientificNotation(str string) (string, error) {
@@ -428,7 +450,7 @@ func StrToFloat(sc *stmtctx.StatementContext, str string) (float64, error) {
 
 // getValidFloatPrefix gets prefix of string which can be successfully parsed as float.
 func getValidFloatPrefix(sc *stmtctx.StatementContext, s string) (valid string, err error) {
-	if (sc.InDeleteStmt || sc.InSelectStmt) && s == "" {
+	if (sc.InDeleteStmt || sc.InSelectStmt || sc.InUpdateStmt) && s == "" {
 		return "0", nil
 	}
 
@@ -477,6 +499,81 @@ func getValidFloatPrefix(sc *stmtctx.StatementContext, s string) (valid string,
 	return valid, err
 }
 
+// ConvertJSONToInt casts JSON into int64.
+func ConvertJSONToInt(sc *stmtctx.StatementContext, j json.BinaryJSON, unsigned bool) (int64, error) {
+	switch j.TypeCode {
+	case json.TypeCodeObject, json.TypeCodeArray:
+		return 0, nil
+	case json.TypeCodeLiteral:
+		switch j.Value[0] {
+		case json.LiteralNil, json.LiteralFalse:
+			return 0, nil
+		default:
+			return 1, nil
+		}
+	case json.TypeCodeInt64, json.TypeCodeUint64:
+		return j.GetInt64(), nil
+	case json.TypeCodeFloat64:
+		f := j.GetFloat64()
+		if !unsigned {
+			lBound := IntergerSignedLowerBound(mysql.TypeLonglong)
+			uBound := IntergerSignedUpperBound(mysql.TypeLonglong)
+			return ConvertFloatToInt(f, lBound, uBound, mysql.TypeDouble)
+		}
+		bound := IntergerUnsignedUpperBound(mysql.TypeLonglong)
+		u, err := ConvertFloatToUint(sc, f, bound, mysql.TypeDouble)
+		return int64(u), errors.Trace(err)
+	case json.TypeCodeString:
+		str := string(hack.String(j.GetString()))
+		if !unsigned {
+			return StrToInt(sc, str)
+		}
+		u, err := StrToUint(sc, str)
+		return int64(u), errors.Trace(err)
+	}
+	return 0, errors.New("Unknown type code in JSON")
+}
+
+// ConvertJSONToFloat casts JSON into float64.
+func ConvertJSONToFloat(sc *stmtctx.StatementContext, j json.BinaryJSON) (float64, error) {
+	switch j.TypeCode {
+	case json.TypeCodeObject, json.TypeCodeArray:
+		return 0, nil
+	case json.TypeCodeLiteral:
+		switch j.Value[0] {
+		case json.LiteralNil, json.LiteralFalse:
+			return 0, nil
+		default:
+			return 1, nil
+		}
+	case json.TypeCodeInt64:
+		return float64(j.GetInt64()), nil
+	case json.TypeCodeUint64:
+		return float64(j.GetUint64()), nil
+	case json.TypeCodeFloat64:
+		return j.GetFloat64(), nil
+	case json.TypeCodeString:
+		str := string(hack.String(j.GetString()))
+		return StrToFloat(sc, str)
+	}
+	return 0, errors.New("Unknown type code in JSON")
+}
+
+// ConvertJSONToDecimal casts JSON into decimal.
+func ConvertJSONToDecimal(sc *stmtctx.StatementContext, j json.BinaryJSON) (*MyDecimal, error) {
+	res := new(MyDecimal)
+	if j.TypeCode != json.TypeCodeString {
+		f64, err := ConvertJSONToFloat(sc, j)
+		if err != nil {
+			return res, errors.Trace(err)
+		}
+		err = res.FromFloat64(f64)
+		return res, errors.Trace(err)
+	}
+	err := sc.HandleTruncate(res.FromString(j.GetString()))
+	return res, errors.Trace(err)
+}
+
 // ToString converts an interface to a string.
 func ToString(value interface{}) (string, error) {
 	switch v := value.(type) {
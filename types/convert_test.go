@@ -158,6 +158,53 @@ func (s *testTypeConvertSuite) TestConvertType(c *C) {
 	v, err = Convert("100", ft)
 	c.Assert(err, IsNil)
 	c.Assert(v, Equals, uint64(100))
+
+	// For TypeBit
+	ft = NewFieldType(mysql.TypeBit)
+	ft.Flen = 24 // 3 bytes.
+	v, err = Convert("100", ft)
+	c.Assert(err, IsNil)
+	c.Assert(v, DeepEquals, NewBinaryLiteralFromUint(3223600, 3))
+	v, err = Convert(NewBinaryLiteralFromUint(100, -1), ft)
+	c.Assert(err, IsNil)
+	c.Assert(v, DeepEquals, NewBinaryLiteralFromUint(100, 3))
+	ft.Flen = 1
+	_, err = Convert(1, ft)
+	c.Assert(err, IsNil)
+	_, err = Convert(2, ft)
+	c.Assert(ErrDataTooLong.Equal(err), IsTrue)
+
+	// For TypeEnum
+	ft = NewFieldType(mysql.TypeEnum)
+	ft.Elems = append(ft.Elems, "a", "b", "c")
+	v, err = Convert("a", ft)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, Enum{Name: "a", Value: 1})
+	v, err = Convert("B", ft)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, Enum{Name: "b", Value: 2})
+	v, err = Convert(3, ft)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, Enum{Name: "c", Value: 3})
+	_, err = Convert("d", ft)
+	c.Assert(ErrTruncated.Equal(err), IsTrue)
+	_, err = Convert(4, ft)
+	c.Assert(ErrTruncated.Equal(err), IsTrue)
+
+	// For TypeSet
+	ft = NewFieldType(mysql.TypeSet)
+	ft.Elems = append(ft.Elems, "a", "b", "c")
+	v, err = Convert("a", ft)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, Set{Name: "a", Value: 1})
+	v, err = Convert("c,a", ft)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, Set{Name: "a,c", Value: 5})
+	v, err = Convert(2, ft)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, Set{Name: "b", Value: 2})
+	_, err = Convert("d", ft)
+	c.Assert(ErrTruncated.Equal(err), IsTrue)
 }
 
 func testStrToInt(c *C, str string, expect int64, truncateAsErr bool, expectErr error) {
@@ -73,6 +73,9 @@ func (ts *testDatumSuite) TestToBool(c *C) {
 	testDatumToBool(c, "0.1", 0)
 	testDatumToBool(c, []byte{}, 0)
 	testDatumToBool(c, []byte("0.1"), 0)
+	testDatumToBool(c, NewBinaryLiteralFromUint(0, -1), 0)
+	testDatumToBool(c, Enum{Name: "a", Value: 1}, 1)
+	testDatumToBool(c, Set{Name: "a", Value: 1}, 1)
 }
 
 func (ts *testDatumSuite) TestEqualDatums(c *C) {
@@ -127,6 +130,9 @@ func (ts *testTypeConvertSuite) TestToInt64(c *C) {
 	testDatumToInt64(c, uint64(0), int64(0))
 	testDatumToInt64(c, float32(3.1), int64(3))
 	testDatumToInt64(c, float64(3.1), int64(3))
+	testDatumToInt64(c, NewBinaryLiteralFromUint(100, -1), int64(100))
+	testDatumToInt64(c, Enum{Name: "a", Value: 1}, int64(1))
+	testDatumToInt64(c, Set{Name: "a", Value: 1}, int64(1))
 }
 
 func (ts *testTypeConvertSuite) TestToFloat32(c *C) {
@@ -52,6 +52,22 @@ func IsTypePrefixable(tp byte) bool {
 	return IsTypeBlob(tp) || IsTypeChar(tp)
 }
 
+// IsTypeTime returns a boolean indicating
+// whether the tp is time type like datetime, date or timestamp type.
+func IsTypeTime(tp byte) bool {
+	return tp == mysql.TypeDatetime || tp == mysql.TypeDate || tp == mysql.TypeTimestamp
+}
+
+// IsTypeTemporal checks if a type is a temporal type.
+func IsTypeTemporal(tp byte) bool {
+	switch tp {
+	case mysql.TypeDuration, mysql.TypeDatetime, mysql.TypeTimestamp,
+		mysql.TypeDate, mysql.TypeNewDate:
+		return true
+	}
+	return false
+}
+
 // IsTypeNumeric returns a boolean indicating whether the tp is numeric type.
 func IsTypeNumeric(tp byte) bool {
 	switch tp {
@@ -84,17 +100,25 @@ func IsString(tp byte) bool {
 }
 
 var kind2Str = map[byte]string{
-	KindNull:       "null",
-	KindInt64:      "bigint",
-	KindUint64:     "unsigned bigint",
-	KindFloat32:    "float",
-	KindFloat64:    "double",
-	KindString:     "char",
-	KindBytes:      "bytes",
-	KindInterface:  "interface",
-	KindMinNotNull: "min_not_null",
-	KindMaxValue:   "max_value",
-	KindRaw:        "raw",
+	KindNull:          "null",
+	KindInt64:         "bigint",
+	KindUint64:        "unsigned bigint",
+	KindFloat32:       "float",
+	KindFloat64:       "double",
+	KindString:        "char",
+	KindBytes:         "bytes",
+	KindBinaryLiteral: "bit/hex literal",
+	KindMysqlDecimal:  "decimal",
+	KindMysqlDuration: "time",
+	KindMysqlEnum:     "enum",
+	KindMysqlBit:      "bit",
+	KindMysqlSet:      "set",
+	KindMysqlTime:     "datetime",
+	KindMysqlJSON:     "json",
+	KindInterface:     "interface",
+	KindMinNotNull:    "min_not_null",
+	KindMaxValue:      "max_value",
+	KindRaw:           "raw",
 }
 
 // TypeStr converts tp to a string.
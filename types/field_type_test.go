@@ -328,6 +328,9 @@ func (s *testFieldTypeSuite) TestAggregateEvalType(c *C) {
 		case mysql.TypeFloat, mysql.TypeDouble:
 			c.Assert(aggregatedEvalType, Equals, ETReal)
 			c.Assert(flag, Equals, uint(mysql.BinaryFlag))
+		case mysql.TypeNewDecimal:
+			c.Assert(aggregatedEvalType, Equals, ETDecimal)
+			c.Assert(flag, Equals, uint(mysql.BinaryFlag))
 		}
 
 		flag = 0
@@ -347,6 +350,9 @@ func (s *testFieldTypeSuite) TestAggregateEvalType(c *C) {
 		case mysql.TypeFloat, mysql.TypeDouble:
 			c.Assert(aggregatedEvalType, Equals, ETReal)
 			c.Assert(flag, Equals, uint(mysql.BinaryFlag))
+		case mysql.TypeNewDecimal:
+			c.Assert(aggregatedEvalType, Equals, ETDecimal)
+			c.Assert(flag, Equals, uint(mysql.BinaryFlag))
 		}
 		flag = 0
 		aggregatedEvalType = AggregateEvalType([]*FieldType{fts[i], NewFieldType(mysql.TypeLong)}, &flag)
@@ -365,6 +371,9 @@ func (s *testFieldTypeSuite) TestAggregateEvalType(c *C) {
 		case mysql.TypeFloat, mysql.TypeDouble:
 			c.Assert(aggregatedEvalType, Equals, ETReal)
 			c.Assert(flag, Equals, uint(mysql.BinaryFlag))
+		case mysql.TypeNewDecimal:
+			c.Assert(aggregatedEvalType, Equals, ETDecimal)
+			c.Assert(flag, Equals, uint(mysql.BinaryFlag))
 		}
 	}
 }
@@ -0,0 +1,497 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/util/hack"
+)
+
+// Type returns type of BinaryJSON as string.
+func (bj BinaryJSON) Type() string {
+	switch bj.TypeCode {
+	case TypeCodeObject:
+		return "OBJECT"
+	case TypeCodeArray:
+		return "ARRAY"
+	case TypeCodeLiteral:
+		switch bj.Value[0] {
+		case LiteralNil:
+			return "NULL"
+		default:
+			return "BOOLEAN"
+		}
+	case TypeCodeInt64:
+		return "INTEGER"
+	case TypeCodeUint64:
+		return "UNSIGNED INTEGER"
+	case TypeCodeFloat64:
+		return "DOUBLE"
+	case TypeCodeString:
+		return "STRING"
+	default:
+		msg := fmt.Sprintf(unknownTypeCodeErrorMsg, bj.TypeCode)
+		panic(msg)
+	}
+}
+
+// Unquote is for JSON_UNQUOTE.
+func (bj BinaryJSON) Unquote() (string, error) {
+	switch bj.TypeCode {
+	case TypeCodeString:
+		tmp := string(hack.String(bj.GetString()))
+		tlen := len(tmp)
+		if tlen < 2 {
+			return tmp, nil
+		}
+		head, tail := tmp[0], tmp[tlen-1]
+		if head == '"' && tail == '"' {
+			// Remove prefix and suffix '"' before unquoting
+			return unquoteString(tmp[1 : tlen-1])
+		}
+		// if value is not double quoted, do nothing
+		return tmp, nil
+	default:
+		return bj.String(), nil
+	}
+}
+
+// UnquoteString remove quotes in a string,
+// including the quotes at the head and tail of string.
+func UnquoteString(str string) (string, error) {
+	strLen := len(str)
+	if strLen < 2 {
+		return str, nil
+	}
+	head, tail := str[0], str[strLen-1]
+	if head == '"' && tail == '"' {
+		// Remove prefix and suffix '"' before unquoting
+		return unquoteString(str[1 : strLen-1])
+	}
+	// if value is not double quoted, do nothing
+	return str, nil
+}
+
+// unquoteString recognizes the escape sequences shown in:
+// https://dev.mysql.com/doc/refman/5.7/en/json-modification-functions.html#json-unquote-character-escape-sequences
+func unquoteString(s string) (string, error) {
+	ret := new(bytes.Buffer)
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			if i == len(s) {
+				return "", errors.New("Missing a closing quotation mark in string")
+			}
+			switch s[i] {
+			case '"':
+				ret.WriteByte('"')
+			case 'b':
+				ret.WriteByte('\b')
+			case 'f':
+				ret.WriteByte('\f')
+			case 'n':
+				ret.WriteByte('\n')
+			case 'r':
+				ret.WriteByte('\r')
+			case 't':
+				ret.WriteByte('\t')
+			case '\\':
+				ret.WriteByte('\\')
+			case 'u':
+				if i+4 > len(s) {
+					return "", errors.Errorf("Invalid unicode: %s", s[i+1:])
+				}
+				char, size, err := decodeEscapedUnicode(hack.Slice(s[i+1 : i+5]))
+				if err != nil {
+					return "", errors.Trace(err)
+				}
+				ret.Write(char[0:size])
+				i += 4
+			default:
+				// For all other escape sequences, backslash is ignored.
+				ret.WriteByte(s[i])
+			}
+		} else {
+			ret.WriteByte(s[i])
+		}
+	}
+	return ret.String(), nil
+}
+
+// decodeEscapedUnicode decodes unicode into utf8 bytes specified in RFC 3629.
+// According RFC 3629, the max length of utf8 characters is 4 bytes.
+// And MySQL use 4 bytes to represent the unicode which must be in [0, 65536).
+func decodeEscapedUnicode(s []byte) (char [4]byte, size int, err error) {
+	size, err = hex.Decode(char[0:2], s)
+	if err != nil || size != 2 {
+		// The unicode must can be represented in 2 bytes.
+		return char, 0, errors.Trace(err)
+	}
+	var unicode uint16
+	unicode = uint16(char[0])<<8 | uint16(char[1])
+	size = utf8.EncodeRune(char[0:4], rune(unicode))
+	return
+}
+
+// Extract receives several path expressions as arguments, matches them in bj, and returns
+// the target JSON matched by any path expression, which may be autowrapped as an array.
+// found is true if any path expression matched.
+func (bj BinaryJSON) Extract(pathExprList []PathExpression) (ret BinaryJSON, found bool) {
+	buf := make([]BinaryJSON, 0, 1)
+	for _, pathExpr := range pathExprList {
+		buf = bj.extractTo(buf, pathExpr)
+	}
+	if len(buf) == 0 {
+		found = false
+	} else if len(pathExprList) == 1 && len(buf) == 1 {
+		// If pathExpr contains asterisks, len(elemList) won't be 1
+		// even if len(pathExprList) equals to 1.
+		found = true
+		ret = buf[0]
+	} else {
+		found = true
+		ret = buildBinaryArray(buf)
+	}
+	return
+}
+
+func (bj BinaryJSON) extractTo(buf []BinaryJSON, pathExpr PathExpression) []BinaryJSON {
+	if len(pathExpr.legs) == 0 {
+		return append(buf, bj)
+	}
+	currentLeg, subPathExpr := pathExpr.popOneLeg()
+	if currentLeg.typ == pathLegIndex {
+		if bj.TypeCode != TypeCodeArray {
+			// A scalar or object is treated as an array with the only element.
+			if currentLeg.arrayIndex == 0 || currentLeg.arrayIndex == arrayIndexAsterisk {
+				buf = bj.extractTo(buf, subPathExpr)
+			}
+			return buf
+		}
+		elemCount := bj.GetElemCount()
+		if currentLeg.arrayIndex == arrayIndexAsterisk {
+			for i := 0; i < elemCount; i++ {
+				buf = bj.arrayGetElem(i).extractTo(buf, subPathExpr)
+			}
+		} else if currentLeg.arrayIndex < elemCount {
+			buf = bj.arrayGetElem(currentLeg.arrayIndex).extractTo(buf, subPathExpr)
+		}
+	} else if currentLeg.typ == pathLegKey && bj.TypeCode == TypeCodeObject {
+		elemCount := bj.GetElemCount()
+		if currentLeg.dotKey == "*" {
+			for i := 0; i < elemCount; i++ {
+				buf = bj.objectGetVal(i).extractTo(buf, subPathExpr)
+			}
+		} else {
+			child, ok := bj.objectSearchKey(hack.Slice(currentLeg.dotKey))
+			if ok {
+				buf = child.extractTo(buf, subPathExpr)
+			}
+		}
+	} else if currentLeg.typ == pathLegDoubleAsterisk {
+		buf = bj.extractTo(buf, subPathExpr)
+		if bj.TypeCode == TypeCodeArray {
+			elemCount := bj.GetElemCount()
+			for i := 0; i < elemCount; i++ {
+				buf = bj.arrayGetElem(i).extractTo(buf, pathExpr)
+			}
+		} else if bj.TypeCode == TypeCodeObject {
+			elemCount := bj.GetElemCount()
+			for i := 0; i < elemCount; i++ {
+				buf = bj.objectGetVal(i).extractTo(buf, pathExpr)
+			}
+		}
+	}
+	return buf
+}
+
+func (bj BinaryJSON) objectSearchKey(key []byte) (BinaryJSON, bool) {
+	idx, found := bj.objectSearchKeyIdx(key)
+	if !found {
+		return BinaryJSON{}, false
+	}
+	return bj.objectGetVal(idx), true
+}
+
+func (bj BinaryJSON) objectSearchKeyIdx(key []byte) (int, bool) {
+	elemCount := bj.GetElemCount()
+	idx := sort.Search(elemCount, func(i int) bool {
+		return bytes.Compare(bj.objectGetKey(i), key) >= 0
+	})
+	if idx < elemCount && bytes.Equal(bj.objectGetKey(idx), key) {
+		return idx, true
+	}
+	return idx, false
+}
+
+func buildBinaryArray(elems []BinaryJSON) BinaryJSON {
+	array := make([]interface{}, 0, len(elems))
+	for _, elem := range elems {
+		array = append(array, elem)
+	}
+	return CreateBinary(array)
+}
+
+func buildBinaryObject(keys [][]byte, elems []BinaryJSON) BinaryJSON {
+	obj := make(map[string]interface{}, len(keys))
+	for i, key := range keys {
+		obj[string(key)] = elems[i]
+	}
+	return CreateBinary(obj)
+}
+
+// Modify modifies a JSON object by insert, replace or set.
+// All path expressions cannot contain * or ** wildcard.
+// If any error occurs, the input won't be changed.
+func (bj BinaryJSON) Modify(pathExprList []PathExpression, values []BinaryJSON, mt ModifyType) (retj BinaryJSON, err error) {
+	if len(pathExprList) != len(values) {
+		// TODO: should return 1582(42000)
+		return retj, errors.New("Incorrect parameter count")
+	}
+	for _, pathExpr := range pathExprList {
+		if pathExpr.flags.containsAnyAsterisk() {
+			return retj, ErrInvalidJSONPathWildcard
+		}
+	}
+	for i := 0; i < len(pathExprList); i++ {
+		bj = bj.modifyTo(pathExprList[i], values[i], mt)
+	}
+	return bj, nil
+}
+
+// modifyTo rebuilds bj with the element addressed by pathExpr modified.
+// An existing element is only overwritten by ModifyReplace and ModifySet,
+// a missing one is only created by ModifyInsert and ModifySet.
+func (bj BinaryJSON) modifyTo(pathExpr PathExpression, value BinaryJSON, mt ModifyType) BinaryJSON {
+	if len(pathExpr.legs) == 0 {
+		if mt&ModifyReplace != 0 {
+			return value
+		}
+		return bj
+	}
+	currentLeg, subPathExpr := pathExpr.popOneLeg()
+	isLastLeg := len(subPathExpr.legs) == 0
+	switch currentLeg.typ {
+	case pathLegIndex:
+		if bj.TypeCode != TypeCodeArray {
+			if currentLeg.arrayIndex == 0 {
+				return bj.modifyTo(subPathExpr, value, mt)
+			}
+			if isLastLeg && mt&ModifyInsert != 0 {
+				// Autowrap the scalar or object as an array, then append the value.
+				return buildBinaryArray([]BinaryJSON{bj, value})
+			}
+			return bj
+		}
+		elemCount := bj.GetElemCount()
+		elems := make([]BinaryJSON, 0, elemCount+1)
+		for i := 0; i < elemCount; i++ {
+			elems = append(elems, bj.arrayGetElem(i))
+		}
+		if currentLeg.arrayIndex < elemCount {
+			elems[currentLeg.arrayIndex] = elems[currentLeg.arrayIndex].modifyTo(subPathExpr, value, mt)
+		} else if isLastLeg && mt&ModifyInsert != 0 {
+			elems = append(elems, value)
+		} else {
+			return bj
+		}
+		return buildBinaryArray(elems)
+	case pathLegKey:
+		if bj.TypeCode != TypeCodeObject {
+			return bj
+		}
+		elemCount := bj.GetElemCount()
+		keys := make([][]byte, 0, elemCount+1)
+		elems := make([]BinaryJSON, 0, elemCount+1)
+		for i := 0; i < elemCount; i++ {
+			keys = append(keys, bj.objectGetKey(i))
+			elems = append(elems, bj.objectGetVal(i))
+		}
+		key := hack.Slice(currentLeg.dotKey)
+		if idx, found := bj.objectSearchKeyIdx(key); found {
+			elems[idx] = elems[idx].modifyTo(subPathExpr, value, mt)
+		} else if isLastLeg && mt&ModifyInsert != 0 {
+			keys = append(keys, key)
+			elems = append(elems, value)
+		} else {
+			return bj
+		}
+		return buildBinaryObject(keys, elems)
+	}
+	return bj
+}
+
+// ContainsBinary check whether JSON document target is contained within JSON document obj.
+func ContainsBinary(obj, target BinaryJSON) bool {
+	switch obj.TypeCode {
+	case TypeCodeObject:
+		if target.TypeCode == TypeCodeObject {
+			elemCount := target.GetElemCount()
+			for i := 0; i < elemCount; i++ {
+				key := target.objectGetKey(i)
+				val := target.objectGetVal(i)
+				if exp, exists := obj.objectSearchKey(key); !exists || !ContainsBinary(exp, val) {
+					return false
+				}
+			}
+			return true
+		}
+		return false
+	case TypeCodeArray:
+		if target.TypeCode == TypeCodeArray {
+			elemCount := target.GetElemCount()
+			for i := 0; i < elemCount; i++ {
+				if !ContainsBinary(obj, target.arrayGetElem(i)) {
+					return false
+				}
+			}
+			return true
+		}
+		elemCount := obj.GetElemCount()
+		for i := 0; i < elemCount; i++ {
+			if ContainsBinary(obj.arrayGetElem(i), target) {
+				return true
+			}
+		}
+		return false
+	default:
+		return CompareBinary(obj, target) == 0
+	}
+}
+
+// jsonTypePrecedences is for comparing two json.
+// See: https://dev.mysql.com/doc/refman/5.7/en/json.html#json-comparison
+var jsonTypePrecedences = map[string]int{
+	"BLOB":             -1,
+	"BIT":              -2,
+	"OPAQUE":           -3,
+	"DATETIME":         -4,
+	"TIME":             -5,
+	"DATE":             -6,
+	"BOOLEAN":          -7,
+	"ARRAY":            -8,
+	"OBJECT":           -9,
+	"STRING":           -10,
+	"INTEGER":          -11,
+	"UNSIGNED INTEGER": -11,
+	"DOUBLE":           -11,
+	"NULL":             -12,
+}
+
+// CompareBinary compares two binary json objects. Returns -1 if left < right,
+// 0 if left == right, else returns 1.
+func CompareBinary(left, right BinaryJSON) int {
+	precedence1 := jsonTypePrecedences[left.Type()]
+	precedence2 := jsonTypePrecedences[right.Type()]
+	var cmp int
+	if precedence1 == precedence2 {
+		if precedence1 == jsonTypePrecedences["NULL"] {
+			// for JSON null.
+			return 0
+		}
+		switch left.TypeCode {
+		case TypeCodeLiteral:
+			// false is less than true.
+			cmp = int(right.Value[0]) - int(left.Value[0])
+		case TypeCodeInt64, TypeCodeUint64, TypeCodeFloat64:
+			cmp = compareNumber(left, right)
+		case TypeCodeString:
+			cmp = bytes.Compare(left.GetString(), right.GetString())
+		case TypeCodeArray:
+			leftCount := left.GetElemCount()
+			rightCount := right.GetElemCount()
+			for i := 0; i < leftCount && i < rightCount; i++ {
+				elem1 := left.arrayGetElem(i)
+				elem2 := right.arrayGetElem(i)
+				cmp = CompareBinary(elem1, elem2)
+				if cmp != 0 {
+					return cmp
+				}
+			}
+			cmp = leftCount - rightCount
+		case TypeCodeObject:
+			// only equal is defined on two json objects.
+			// larger and smaller are not defined.
+			cmp = bytes.Compare(left.Value, right.Value)
+		}
+	} else {
+		cmp = precedence1 - precedence2
+	}
+	if cmp > 0 {
+		return 1
+	} else if cmp < 0 {
+		return -1
+	}
+	return 0
+}
+
+func compareNumber(left, right BinaryJSON) int {
+	switch {
+	case left.TypeCode == TypeCodeInt64 && right.TypeCode == TypeCodeInt64:
+		return compareInt64(left.GetInt64(), right.GetInt64())
+	case left.TypeCode == TypeCodeUint64 && right.TypeCode == TypeCodeUint64:
+		return compareUint64(left.GetUint64(), right.GetUint64())
+	case left.TypeCode == TypeCodeInt64 && right.TypeCode == TypeCodeUint64:
+		if left.GetInt64() < 0 {
+			return -1
+		}
+		return compareUint64(uint64(left.GetInt64()), right.GetUint64())
+	case left.TypeCode == TypeCodeUint64 && right.TypeCode == TypeCodeInt64:
+		return -compareNumber(right, left)
+	}
+	return compareFloat64(numberAsFloat64(left), numberAsFloat64(right))
+}
+
+func numberAsFloat64(bj BinaryJSON) float64 {
+	switch bj.TypeCode {
+	case TypeCodeInt64:
+		return float64(bj.GetInt64())
+	case TypeCodeUint64:
+		return float64(bj.GetUint64())
+	}
+	return bj.GetFloat64()
+}
+
+func compareInt64(x, y int64) int {
+	if x < y {
+		return -1
+	} else if x == y {
+		return 0
+	}
+	return 1
+}
+
+func compareUint64(x, y uint64) int {
+	if x < y {
+		return -1
+	} else if x == y {
+		return 0
+	}
+	return 1
+}
+
+func compareFloat64(x, y float64) int {
+	if x < y {
+		return -1
+	} else if x == y {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,259 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testJSONSuite{})
+
+type testJSONSuite struct{}
+
+func mustParseBinaryFromString(c *C, s string) BinaryJSON {
+	bj, err := ParseBinaryFromString(s)
+	c.Assert(err, IsNil)
+	return bj
+}
+
+func (s *testJSONSuite) TestBinaryJSONMarshalUnmarshal(c *C) {
+	strs := []string{
+		`{"a": [1, "2", {"aa": "bb"}, 4, null], "b": true, "c": null}`,
+		`{"aaaaaaaaaaa": [1, "2", {"aa": "bb"}, 4.1], "bbbbbbbbbb": true, "ccccccccc": "d"}`,
+		`[{"a": 1, "b": true}, 3, 3.5, "hello, world", null, true]`,
+		`"\u0000\u001f\\\"a\nb"`,
+		`18446744073709551615`,
+		`-3`,
+		`1e-7`,
+		`null`,
+	}
+	for _, str := range strs {
+		bj := mustParseBinaryFromString(c, str)
+		c.Assert(bj.String(), Equals, str)
+	}
+
+	for _, str := range []string{"", "{", `{"a": 1}x`, `[1, 2`} {
+		_, err := ParseBinaryFromString(str)
+		c.Assert(ErrInvalidJSONText.Equal(err), IsTrue, Commentf("%s", str))
+	}
+}
+
+func (s *testJSONSuite) TestBinaryJSONType(c *C) {
+	var tests = []struct {
+		In  string
+		Out string
+	}{
+		{`{"a": "b"}`, "OBJECT"},
+		{`["a", "b"]`, "ARRAY"},
+		{`3`, "INTEGER"},
+		{`18446744073709551615`, "UNSIGNED INTEGER"},
+		{`3.0`, "DOUBLE"},
+		{`null`, "NULL"},
+		{`true`, "BOOLEAN"},
+		{`"abc"`, "STRING"},
+	}
+	for _, tt := range tests {
+		bj := mustParseBinaryFromString(c, tt.In)
+		c.Assert(bj.Type(), Equals, tt.Out)
+	}
+}
+
+func (s *testJSONSuite) TestBinaryJSONUnquote(c *C) {
+	var tests = []struct {
+		j        string
+		unquoted string
+	}{
+		{j: `3`, unquoted: "3"},
+		{j: `"3"`, unquoted: "3"},
+		{j: `"hello, \"escaped quotes\" world"`, unquoted: "hello, \"escaped quotes\" world"},
+		{j: "\"\\u4f60\"", unquoted: "你"},
+		{j: `true`, unquoted: "true"},
+		{j: `null`, unquoted: "null"},
+		{j: `{"a": [1, 2]}`, unquoted: `{"a": [1, 2]}`},
+	}
+	for _, tt := range tests {
+		bj := mustParseBinaryFromString(c, tt.j)
+		unquoted, err := bj.Unquote()
+		c.Assert(err, IsNil)
+		c.Assert(unquoted, Equals, tt.unquoted)
+	}
+
+	str, err := UnquoteString(`"a\tb"`)
+	c.Assert(err, IsNil)
+	c.Assert(str, Equals, "a\tb")
+	str, err = UnquoteString(`abc`)
+	c.Assert(err, IsNil)
+	c.Assert(str, Equals, "abc")
+}
+
+func (s *testJSONSuite) TestBinaryJSONExtract(c *C) {
+	bj1 := mustParseBinaryFromString(c, `{"\"hello\"": "world", "a": [1, "2", {"aa": "bb"}, 4.0, {"aa": "cc"}], "b": true, "c": ["d"]}`)
+	bj2 := mustParseBinaryFromString(c, `[{"a": 1, "b": true}, 3, 3.5, "hello, world", null, true]`)
+
+	var tests = []struct {
+		bj              BinaryJSON
+		pathExprStrings []string
+		expected        string
+		found           bool
+	}{
+		// test extract with only one path expression.
+		{bj1, []string{"$.a"}, `[1, "2", {"aa": "bb"}, 4.0, {"aa": "cc"}]`, true},
+		{bj2, []string{"$.a"}, "", false},
+		{bj1, []string{"$[0]"}, `{"\"hello\"": "world", "a": [1, "2", {"aa": "bb"}, 4.0, {"aa": "cc"}], "b": true, "c": ["d"]}`, true},
+		{bj2, []string{"$[0]"}, `{"a": 1, "b": true}`, true},
+		{bj1, []string{"$.a[2].aa"}, `"bb"`, true},
+		{bj1, []string{"$.a[*].aa"}, `["bb", "cc"]`, true},
+		{bj1, []string{"$.*[0]"}, `["world", 1, true, "d"]`, true},
+		{bj1, []string{`$.a[*]."aa"`}, `["bb", "cc"]`, true},
+		{bj1, []string{`$."\"hello\""`}, `"world"`, true},
+		{bj1, []string{`$**[1]`}, `"2"`, true},
+		{bj1, []string{`$.a[9]`}, "", false},
+
+		// test extract with multi path expressions.
+		{bj1, []string{"$.a", "$[5]"}, `[[1, "2", {"aa": "bb"}, 4.0, {"aa": "cc"}]]`, true},
+		{bj2, []string{"$.a", "$[0]"}, `[{"a": 1, "b": true}]`, true},
+		{bj2, []string{"$[1]", "$[4]"}, `[3, null]`, true},
+	}
+
+	for _, tt := range tests {
+		var pathExprList = make([]PathExpression, 0)
+		for _, peStr := range tt.pathExprStrings {
+			pe, err := ParseJSONPathExpr(peStr)
+			c.Assert(err, IsNil)
+			pathExprList = append(pathExprList, pe)
+		}
+
+		result, found := tt.bj.Extract(pathExprList)
+		c.Assert(found, Equals, tt.found, Commentf("%v", tt.pathExprStrings))
+		if found {
+			expected := mustParseBinaryFromString(c, tt.expected)
+			c.Assert(CompareBinary(result, expected), Equals, 0, Commentf("%v: %s", tt.pathExprStrings, result))
+		}
+	}
+}
+
+func (s *testJSONSuite) TestBinaryJSONModify(c *C) {
+	var tests = []struct {
+		base     string
+		setField string
+		setValue string
+		mt       ModifyType
+		expected string
+		success  bool
+	}{
+		{`null`, "$", `{}`, ModifySet, `{}`, true},
+		{`{}`, "$.a", `3`, ModifySet, `{"a": 3}`, true},
+		{`{"a": 3}`, "$.a", `[]`, ModifyReplace, `{"a": []}`, true},
+		{`{"a": 3}`, "$.b", `3`, ModifyReplace, `{"a": 3}`, true},
+		{`{"a": []}`, "$.a[0]", `3`, ModifySet, `{"a": [3]}`, true},
+		{`{"a": [3]}`, "$.a[1]", `4`, ModifyInsert, `{"a": [3, 4]}`, true},
+		{`{"a": [3]}`, "$.a[0]", `4`, ModifyInsert, `{"a": [3]}`, true},
+		{`{"a": [3]}`, "$[0]", `4`, ModifySet, `4`, true},
+		{`{"a": [3]}`, "$[1]", `4`, ModifySet, `[{"a": [3]}, 4]`, true},
+		{`{"a": {"b": 1}}`, "$.a.c", `"x"`, ModifySet, `{"a": {"b": 1, "c": "x"}}`, true},
+		{`{"a": {"b": 1}}`, "$.b.c", `"x"`, ModifySet, `{"a": {"b": 1}}`, true},
+
+		// nothing changed because the path is a wildcard.
+		{`{"a": [3]}`, "$.*", `4`, ModifySet, ``, false},
+		{`{"a": [3]}`, "$[*]", `4`, ModifySet, ``, false},
+	}
+	for _, tt := range tests {
+		pathExpr, err := ParseJSONPathExpr(tt.setField)
+		c.Assert(err, IsNil)
+
+		base := mustParseBinaryFromString(c, tt.base)
+		value := mustParseBinaryFromString(c, tt.setValue)
+		obtain, err := base.Modify([]PathExpression{pathExpr}, []BinaryJSON{value}, tt.mt)
+		if tt.success {
+			c.Assert(err, IsNil)
+			c.Assert(obtain.String(), Equals, tt.expected)
+		} else {
+			c.Assert(err, NotNil)
+		}
+	}
+}
+
+func (s *testJSONSuite) TestContainsBinary(c *C) {
+	bj := mustParseBinaryFromString(c, `{"a": [1, "2", {"aa": "bb"}, 4.0, {"aa": "cc"}], "b": true, "c": ["d"], "d": "d"}`)
+	var tests = []struct {
+		input    string
+		expected bool
+	}{
+		{`{"a": [1]}`, true},
+		{`{"a": [1, 2]}`, false},
+		{`{"a": [{"aa": "bb"}]}`, true},
+		{`{"c": "d"}`, true},
+		{`{"d": ["d"]}`, false},
+		{`{"b": true, "d": "d"}`, true},
+		{`{"e": 1}`, false},
+		{`1`, false},
+	}
+	for _, tt := range tests {
+		c.Assert(ContainsBinary(bj, mustParseBinaryFromString(c, tt.input)), Equals, tt.expected, Commentf("%s", tt.input))
+	}
+	c.Assert(ContainsBinary(mustParseBinaryFromString(c, `[1, 2, 3]`), mustParseBinaryFromString(c, `[3, 1]`)), IsTrue)
+	c.Assert(ContainsBinary(mustParseBinaryFromString(c, `[1, 2, 3]`), mustParseBinaryFromString(c, `2.0`)), IsTrue)
+	c.Assert(ContainsBinary(mustParseBinaryFromString(c, `"a"`), mustParseBinaryFromString(c, `"a"`)), IsTrue)
+}
+
+func (s *testJSONSuite) TestCompareBinary(c *C) {
+	jNull := mustParseBinaryFromString(c, `null`)
+	jBoolTrue := mustParseBinaryFromString(c, `true`)
+	jBoolFalse := mustParseBinaryFromString(c, `false`)
+	jIntegerLarge := CreateBinary(uint64(1 << 63))
+	jIntegerSmall := CreateBinary(int64(-1))
+	jStringLarge := CreateBinary("b")
+	jStringSmall := CreateBinary("a")
+	jArrayLarge := mustParseBinaryFromString(c, "[2]")
+	jArraySmall := mustParseBinaryFromString(c, "[1, 2]")
+
+	var tests = []struct {
+		left  BinaryJSON
+		right BinaryJSON
+	}{
+		{jNull, jIntegerSmall},
+		{jIntegerSmall, jIntegerLarge},
+		{jIntegerLarge, jStringSmall},
+		{jStringSmall, jStringLarge},
+		{jStringLarge, jArraySmall},
+		{jArraySmall, jArrayLarge},
+		{jArrayLarge, jBoolFalse},
+		{jBoolFalse, jBoolTrue},
+		{CreateBinary(int64(3)), CreateBinary(3.5)},
+	}
+	for _, tt := range tests {
+		c.Assert(CompareBinary(tt.left, tt.right), Equals, -1)
+		c.Assert(CompareBinary(tt.right, tt.left), Equals, 1)
+	}
+	c.Assert(CompareBinary(CreateBinary(int64(2)), CreateBinary(2.0)), Equals, 0)
+	c.Assert(CompareBinary(jNull, jNull), Equals, 0)
+}
+
+func (s *testJSONSuite) TestPeekBytesAsJSON(c *C) {
+	for _, str := range []string{`{"a": [1, 2]}`, `[true]`, `"abc"`, `1`, `null`} {
+		bj := mustParseBinaryFromString(c, str)
+		b := append([]byte{bj.TypeCode}, bj.Value...)
+		n, err := PeekBytesAsJSON(append(b, 0xff, 0xff))
+		c.Assert(err, IsNil)
+		c.Assert(n, Equals, len(b))
+	}
+	_, err := PeekBytesAsJSON(nil)
+	c.Assert(err, NotNil)
+}
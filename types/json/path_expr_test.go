@@ -0,0 +1,89 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	. "github.com/pingcap/check"
+)
+
+func (s *testJSONSuite) TestContainsAnyAsterisk(c *C) {
+	var tests = []struct {
+		exprString        string
+		containsAsterisks bool
+	}{
+		{"$.a[1]", false},
+		{"$.a[*]", true},
+		{"$.*[1]", true},
+		{"$**.a[1]", true},
+	}
+	for _, tt := range tests {
+		pe, err := ParseJSONPathExpr(tt.exprString)
+		c.Assert(err, IsNil)
+		c.Assert(pe.ContainsAnyAsterisk(), Equals, tt.containsAsterisks)
+	}
+}
+
+func (s *testJSONSuite) TestValidatePathExpr(c *C) {
+	var tests = []struct {
+		exprString string
+		success    bool
+		legs       int
+	}{
+		{`$`, true, 0},
+		{`   $   `, true, 0},
+		{`$.a`, true, 1},
+		{`$ . a`, true, 1},
+		{`$.a[2]`, true, 2},
+		{`$.a[ 2 ]`, true, 2},
+		{`$[*]`, true, 1},
+		{`$.*`, true, 1},
+		{`$**.a`, true, 2},
+		{`$."key with space"`, true, 1},
+		{`$."\"quoted\""`, true, 1},
+		{`$[1].b[0].*`, true, 4},
+		{`a`, false, 0},
+		{`x$.a`, false, 0},
+		{`$.a[-1]`, false, 0},
+		{`$.a x`, false, 0},
+		{`$.a**`, false, 0},
+		{`$.1a`, false, 0},
+	}
+
+	for _, tt := range tests {
+		pe, err := ParseJSONPathExpr(tt.exprString)
+		if tt.success {
+			c.Assert(err, IsNil, Commentf("%s", tt.exprString))
+			c.Assert(len(pe.legs), Equals, tt.legs, Commentf("%s", tt.exprString))
+		} else {
+			c.Assert(ErrInvalidJSONPath.Equal(err), IsTrue, Commentf("%s", tt.exprString))
+		}
+	}
+}
+
+func (s *testJSONSuite) TestPathExprToString(c *C) {
+	var tests = []struct {
+		exprString string
+	}{
+		{"$.a[1]"},
+		{"$.a[*]"},
+		{"$.*[2]"},
+		{"$**.a[3]"},
+		{`$."key with space"`},
+	}
+	for _, tt := range tests {
+		pe, err := ParseJSONPathExpr(tt.exprString)
+		c.Assert(err, IsNil)
+		c.Assert(pe.String(), Equals, tt.exprString)
+	}
+}
@@ -0,0 +1,312 @@
+// Copyright 2016 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"fmt"
+	gotime "time"
+
+	"github.com/pingcap/errors"
+)
+
+// CoreTime is the internal struct type for Time.
+// It packs the date and time fields into one uint64, the layout is:
+//
+//	| year (14) | month (4) | day (5) | hour (5) | minute (6) | second (6) | microsecond (20) | fspTt (4) |
+//
+// The fields are placed from the most significant bits, so two CoreTimes
+// can be compared directly once the fspTt bits are masked out.
+type CoreTime uint64
+
+const (
+	yearBitFieldOffset, yearBitFieldWidth               uint64 = 50, 14
+	monthBitFieldOffset, monthBitFieldWidth             uint64 = 46, 4
+	dayBitFieldOffset, dayBitFieldWidth                 uint64 = 41, 5
+	hourBitFieldOffset, hourBitFieldWidth               uint64 = 36, 5
+	minuteBitFieldOffset, minuteBitFieldWidth           uint64 = 30, 6
+	secondBitFieldOffset, secondBitFieldWidth           uint64 = 24, 6
+	microsecondBitFieldOffset, microsecondBitFieldWidth uint64 = 4, 20
+	fspTtBitFieldOffset, fspTtBitFieldWidth             uint64 = 0, 4
+
+	yearBitFieldMask        uint64 = ((1 << yearBitFieldWidth) - 1) << yearBitFieldOffset
+	monthBitFieldMask       uint64 = ((1 << monthBitFieldWidth) - 1) << monthBitFieldOffset
+	dayBitFieldMask         uint64 = ((1 << dayBitFieldWidth) - 1) << dayBitFieldOffset
+	hourBitFieldMask        uint64 = ((1 << hourBitFieldWidth) - 1) << hourBitFieldOffset
+	minuteBitFieldMask      uint64 = ((1 << minuteBitFieldWidth) - 1) << minuteBitFieldOffset
+	secondBitFieldMask      uint64 = ((1 << secondBitFieldWidth) - 1) << secondBitFieldOffset
+	microsecondBitFieldMask uint64 = ((1 << microsecondBitFieldWidth) - 1) << microsecondBitFieldOffset
+	fspTtBitFieldMask       uint64 = ((1 << fspTtBitFieldWidth) - 1) << fspTtBitFieldOffset
+
+	coreTimeBitFieldMask = ^fspTtBitFieldMask
+)
+
+// ZeroCoreTime is the zero value for CoreTime type.
+var ZeroCoreTime = CoreTime(0)
+
+// FromDate makes a internal time representation from the given date.
+func FromDate(year int, month int, day int, hour int, minute int, second int, microsecond int) CoreTime {
+	v := uint64(ZeroCoreTime)
+	v |= (uint64(year) << yearBitFieldOffset) & yearBitFieldMask
+	v |= (uint64(month) << monthBitFieldOffset) & monthBitFieldMask
+	v |= (uint64(day) << dayBitFieldOffset) & dayBitFieldMask
+	v |= (uint64(hour) << hourBitFieldOffset) & hourBitFieldMask
+	v |= (uint64(minute) << minuteBitFieldOffset) & minuteBitFieldMask
+	v |= (uint64(second) << secondBitFieldOffset) & secondBitFieldMask
+	v |= (uint64(microsecond) << microsecondBitFieldOffset) & microsecondBitFieldMask
+	return CoreTime(v)
+}
+
+// FromGoTime translates time.Time to mysql time internal representation.
+func FromGoTime(t gotime.Time) CoreTime {
+	// Plus 500 nanosecond for rounding of the millisecond part.
+	t = t.Add(500 * gotime.Nanosecond)
+
+	year, month, day := t.Date()
+	hour, minute, second := t.Clock()
+	microsecond := t.Nanosecond() / 1000
+	return FromDate(year, int(month), day, hour, minute, second, microsecond)
+}
+
+// String implements fmt.Stringer.
+func (t CoreTime) String() string {
+	return fmt.Sprintf("{%d %d %d %d %d %d %d}", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Microsecond())
+}
+
+// Year returns the year value.
+func (t CoreTime) Year() int {
+	return int((uint64(t) & yearBitFieldMask) >> yearBitFieldOffset)
+}
+
+// Month returns the month value.
+func (t CoreTime) Month() int {
+	return int((uint64(t) & monthBitFieldMask) >> monthBitFieldOffset)
+}
+
+// Day returns the day value.
+func (t CoreTime) Day() int {
+	return int((uint64(t) & dayBitFieldMask) >> dayBitFieldOffset)
+}
+
+// Hour returns the hour value.
+func (t CoreTime) Hour() int {
+	return int((uint64(t) & hourBitFieldMask) >> hourBitFieldOffset)
+}
+
+// Minute returns the minute value.
+func (t CoreTime) Minute() int {
+	return int((uint64(t) & minuteBitFieldMask) >> minuteBitFieldOffset)
+}
+
+// Second returns the second value.
+func (t CoreTime) Second() int {
+	return int((uint64(t) & secondBitFieldMask) >> secondBitFieldOffset)
+}
+
+// Microsecond returns the microsecond value.
+func (t CoreTime) Microsecond() int {
+	return int((uint64(t) & microsecondBitFieldMask) >> microsecondBitFieldOffset)
+}
+
+// Weekday returns weekday.
+func (t CoreTime) Weekday() gotime.Weekday {
+	// TODO: Consider time_zone variable.
+	t1, err := t.GoTime(gotime.Local)
+	// allow invalid dates
+	if err != nil {
+		return t1.Weekday()
+	}
+	return t1.Weekday()
+}
+
+// YearDay returns year day.
+func (t CoreTime) YearDay() int {
+	if t.Month() == 0 || t.Day() == 0 {
+		return 0
+	}
+	return calcDaynr(t.Year(), t.Month(), t.Day()) -
+		calcDaynr(t.Year(), 1, 1) + 1
+}
+
+// GoTime converts Time to GoTime.
+func (t CoreTime) GoTime(loc *gotime.Location) (gotime.Time, error) {
+	// gotime.Time can't represent month 0 or day 0, date contains 0 would be converted to a nearest date,
+	// For example, 2006-12-00 00:00:00 would become 2015-11-30 23:59:59.
+	year, month, day, hour, minute, second, microsecond := t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Microsecond()
+	tm := gotime.Date(year, gotime.Month(month), day, hour, minute, second, microsecond*1000, loc)
+	year2, month2, day2 := tm.Date()
+	hour2, minute2, second2 := tm.Clock()
+	microsec2 := tm.Nanosecond() / 1000
+	// This function will check the result, and return an error if it's not the same with the origin input.
+	if year2 != year || int(month2) != month || day2 != day ||
+		hour2 != hour || minute2 != minute || second2 != second ||
+		microsec2 != microsecond {
+		return tm, errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, t))
+	}
+	return tm, nil
+}
+
+// IsLeapYear returns if it's leap year.
+func (t CoreTime) IsLeapYear() bool {
+	return isLeapYear(uint16(t.Year()))
+}
+
+func isLeapYear(year uint16) bool {
+	return (year%4 == 0 && year%100 != 0) || year%400 == 0
+}
+
+var daysByMonth = [12]int{31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+
+// GetLastDay returns the last day of the month
+func GetLastDay(year, month int) int {
+	var day = 0
+	if month > 0 && month <= 12 {
+		day = daysByMonth[month-1]
+	}
+	if month == 2 && isLeapYear(uint16(year)) {
+		day = 29
+	}
+	return day
+}
+
+func compareTime(a, b CoreTime) int {
+	ta := uint64(a) & coreTimeBitFieldMask
+	tb := uint64(b) & coreTimeBitFieldMask
+	switch {
+	case ta < tb:
+		return -1
+	case ta > tb:
+		return 1
+	}
+	return 0
+}
+
+// calcDaynr calculates days since 0000-00-00.
+func calcDaynr(year, month, day int) int {
+	if year == 0 && month == 0 {
+		return 0
+	}
+
+	delsum := 365*year + 31*(month-1) + day
+	if month <= 2 {
+		year--
+	} else {
+		delsum -= (month*4 + 23) / 10
+	}
+	temp := ((year/100 + 1) * 3) / 4
+	return delsum + year/4 - temp
+}
+
+// Week returns the week value.
+func (t CoreTime) Week(mode int) int {
+	if t.Month() == 0 || t.Day() == 0 {
+		return 0
+	}
+	_, week := calcWeek(t, weekMode(mode))
+	return week
+}
+
+// YearWeek returns year and week.
+func (t CoreTime) YearWeek(mode int) (int, int) {
+	behavior := weekMode(mode) | weekBehaviourYear
+	return calcWeek(t, behavior)
+}
+
+// calcWeekday calculates weekday from daynr, returns 0 for Monday, 1 for Tuesday ...
+func calcWeekday(daynr int, sundayFirstDayOfWeek bool) int {
+	daynr += 5
+	if sundayFirstDayOfWeek {
+		daynr++
+	}
+	return daynr % 7
+}
+
+type weekBehaviour uint
+
+const (
+	// weekBehaviourMondayFirst set Monday as first day of week; otherwise Sunday is first day of week
+	weekBehaviourMondayFirst weekBehaviour = 1 << iota
+	// If set, Week is in range 1-53, otherwise Week is in range 0-53.
+	// Note that this flag is only relevant if WEEK_JANUARY is not set.
+	weekBehaviourYear
+	// If not set, Weeks are numbered according to ISO 8601:1988.
+	// If set, the week that contains the first 'first-day-of-week' is week 1.
+	weekBehaviourFirstWeekday
+)
+
+func (v weekBehaviour) test(flag weekBehaviour) bool {
+	return (v & flag) != 0
+}
+
+func weekMode(mode int) weekBehaviour {
+	weekFormat := weekBehaviour(mode & 7)
+	if (weekFormat & weekBehaviourMondayFirst) == 0 {
+		weekFormat ^= weekBehaviourFirstWeekday
+	}
+	return weekFormat
+}
+
+// calcDaysInYear calculates days in one year.
+func calcDaysInYear(year int) int {
+	if isLeapYear(uint16(year)) {
+		return 366
+	}
+	return 365
+}
+
+// calcWeek calculates week and year for the time.
+func calcWeek(t CoreTime, wb weekBehaviour) (year int, week int) {
+	var days int
+	ty, tm, td := t.Year(), t.Month(), t.Day()
+	daynr := calcDaynr(ty, tm, td)
+	firstDaynr := calcDaynr(ty, 1, 1)
+	mondayFirst := wb.test(weekBehaviourMondayFirst)
+	weekYear := wb.test(weekBehaviourYear)
+	firstWeekday := wb.test(weekBehaviourFirstWeekday)
+
+	weekday := calcWeekday(firstDaynr, !mondayFirst)
+
+	year = ty
+
+	if tm == 1 && td <= 7-weekday {
+		if !weekYear &&
+			((firstWeekday && weekday != 0) || (!firstWeekday && weekday >= 4)) {
+			week = 0
+			return
+		}
+		weekYear = true
+		year--
+		days = calcDaysInYear(year)
+		firstDaynr -= days
+		weekday = (weekday + 53*7 - days) % 7
+	}
+
+	if (firstWeekday && weekday != 0) ||
+		(!firstWeekday && weekday >= 4) {
+		days = daynr - (firstDaynr + 7 - weekday)
+	} else {
+		days = daynr - (firstDaynr - weekday)
+	}
+
+	if weekYear && days >= 52*7 {
+		weekday = (weekday + calcDaysInYear(year)) % 7
+		if (!firstWeekday && weekday < 4) ||
+			(firstWeekday && weekday == 0) {
+			year++
+			week = 1
+			return
+		}
+	}
+	week = days/7 + 1
+	return
+}
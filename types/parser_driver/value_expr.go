@@ -38,9 +38,11 @@ import (
 
 func init() {
 	ast.NewValueExpr = newValueExpr
+	ast.NewParamMarkerExpr = newParamMarkerExpr
 	ast.NewDecimal = func(str string) (interface{}, error) {
-		n, err := strconv.ParseFloat(str, 64)
-		return n, err
+		dec := new(types.MyDecimal)
+		err := dec.FromString([]byte(str))
+		return dec, err
 	}
 	ast.NewHexLiteral = func(str string) (interface{}, error) {
 		h, err := types.NewHexLiteral(str)
@@ -53,7 +55,8 @@ func init() {
 }
 
 var (
-	_ ast.ValueExpr = &ValueExpr{}
+	_ ast.ParamMarkerExpr = &ParamMarkerExpr{}
+	_ ast.ValueExpr       = &ValueExpr{}
 )
 
 // ValueExpr is the simple value expression.
@@ -90,6 +93,12 @@ func (n *ValueExpr) Format(w io.Writer) {
 		s = strconv.FormatFloat(n.GetFloat64(), 'e', -1, 32)
 	case types.KindFloat64:
 		s = strconv.FormatFloat(n.GetFloat64(), 'e', -1, 64)
+	case types.KindMysqlDecimal:
+		s = n.GetMysqlDecimal().String()
+	case types.KindMysqlTime:
+		s = strconv.Quote(n.GetMysqlTime().String())
+	case types.KindMysqlDuration:
+		s = strconv.Quote(n.GetMysqlDuration().String())
 	case types.KindString, types.KindBytes:
 		s = strconv.Quote(n.GetString())
 	default:
@@ -129,3 +138,43 @@ func (n *ValueExpr) Accept(v ast.Visitor) (ast.Node, bool) {
 	n = newNode.(*ValueExpr)
 	return v.Leave(n)
 }
+
+// ParamMarkerExpr expression holds a place for another expression.
+// Used in parsing prepare statement.
+type ParamMarkerExpr struct {
+	ValueExpr
+	Offset int
+	// Order is the position of the marker among all the markers of the statement.
+	Order int
+	// InExecute is true when the statement is being executed, so the Datum
+	// holds the value of the parameter.
+	InExecute bool
+}
+
+func newParamMarkerExpr(offset int) ast.ParamMarkerExpr {
+	p := &ParamMarkerExpr{
+		Offset: offset,
+	}
+	p.projectionOffset = -1
+	return p
+}
+
+// Format the ExprNode into a Writer.
+func (n *ParamMarkerExpr) Format(w io.Writer) {
+	fmt.Fprint(w, "?")
+}
+
+// Accept implements Node Accept interface.
+func (n *ParamMarkerExpr) Accept(v ast.Visitor) (ast.Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*ParamMarkerExpr)
+	return v.Leave(n)
+}
+
+// SetOrder implements the ast.ParamMarkerExpr interface.
+func (n *ParamMarkerExpr) SetOrder(order int) {
+	n.Order = order
+}
@@ -0,0 +1,2191 @@
+// Copyright 2015 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	gotime "time"
+	"unicode"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+)
+
+// Time format without fractional seconds precision.
+const (
+	DateFormat = "2006-01-02"
+	TimeFormat = "2006-01-02 15:04:05"
+	// TimeFSPFormat is time format with fractional seconds precision.
+	TimeFSPFormat = "2006-01-02 15:04:05.000000"
+)
+
+const (
+	// MinYear is the minimum for mysql year type.
+	MinYear int16 = 1901
+	// MaxYear is the maximum for mysql year type.
+	MaxYear int16 = 2155
+	// MaxDuration is the maximum for duration.
+	MaxDuration int64 = 838*10000 + 59*100 + 59
+	// MinTime is the minimum for mysql time type.
+	MinTime = -gotime.Duration(838*3600+59*60+59) * gotime.Second
+	// MaxTime is the maximum for mysql time type.
+	MaxTime = gotime.Duration(838*3600+59*60+59) * gotime.Second
+	// ZeroDatetimeStr is the string representation of a zero datetime.
+	ZeroDatetimeStr = "0000-00-00 00:00:00"
+	// ZeroDateStr is the string representation of a zero date.
+	ZeroDateStr = "0000-00-00"
+
+	// TimeMaxHour is the max hour for mysql time type.
+	TimeMaxHour = 838
+	// TimeMaxMinute is the max minute for mysql time type.
+	TimeMaxMinute = 59
+	// TimeMaxSecond is the max second for mysql time type.
+	TimeMaxSecond = 59
+	// TimeMaxValue is the maximum value for mysql time type.
+	TimeMaxValue = TimeMaxHour*10000 + TimeMaxMinute*100 + TimeMaxSecond
+	// TimeMaxValueSeconds is the maximum second value for mysql time type.
+	TimeMaxValueSeconds = TimeMaxHour*3600 + TimeMaxMinute*60 + TimeMaxSecond
+)
+
+// Zero values for different types.
+var (
+	// ZeroDuration is the zero value for Duration type.
+	ZeroDuration = Duration{Duration: gotime.Duration(0), Fsp: DefaultFsp}
+
+	// ZeroTime is the zero value for Time type.
+	ZeroTime = NewTime(ZeroCoreTime, mysql.TypeDatetime, DefaultFsp)
+
+	// ZeroDatetime is the zero value for datetime Time.
+	ZeroDatetime = NewTime(ZeroCoreTime, mysql.TypeDatetime, DefaultFsp)
+
+	// ZeroTimestamp is the zero value for timestamp Time.
+	ZeroTimestamp = NewTime(ZeroCoreTime, mysql.TypeTimestamp, DefaultFsp)
+
+	// ZeroDate is the zero value for date Time.
+	ZeroDate = NewTime(ZeroCoreTime, mysql.TypeDate, DefaultFsp)
+)
+
+var (
+	// MinDatetime is the minimum for Golang Time type.
+	MinDatetime = FromDate(1, 1, 1, 0, 0, 0, 0)
+	// MaxDatetime is the maximum for mysql datetime type.
+	MaxDatetime = FromDate(9999, 12, 31, 23, 59, 59, 999999)
+
+	// BoundTimezone is the timezone for min and max timestamp.
+	BoundTimezone = gotime.UTC
+	// MinTimestamp is the minimum for mysql timestamp type.
+	MinTimestamp = NewTime(FromDate(1970, 1, 1, 0, 0, 1, 0), mysql.TypeTimestamp, DefaultFsp)
+	// MaxTimestamp is the maximum for mysql timestamp type.
+	MaxTimestamp = NewTime(FromDate(2038, 1, 19, 3, 14, 7, 999999), mysql.TypeTimestamp, DefaultFsp)
+)
+
+const (
+	// fspTt packs fsp and the time type into the lowest 4 bits of CoreTime:
+	//   | fsp: 3 bits | type: 1 bit |
+	// When fsp is valid (in range [0, 6]), type bit 0 means DATETIME and 1 means TIMESTAMP.
+	// DATE does not need fsp, so fspTt 0b1110 is used to represent it.
+	fspTtForDate uint64 = 0b1110
+	ttBitMask    uint64 = 0b0001
+)
+
+// Time is the struct for handling datetime, timestamp and date.
+type Time struct {
+	coreTime CoreTime
+}
+
+// NewTime constructs time from core time, type and fsp.
+func NewTime(coreTime CoreTime, tp uint8, fsp int8) Time {
+	t := Time{coreTime: CoreTime(uint64(coreTime) & coreTimeBitFieldMask)}
+	if fsp == UnspecifiedFsp {
+		fsp = DefaultFsp
+	}
+	p := (*uint64)(&t.coreTime)
+	switch tp {
+	case mysql.TypeDate:
+		*p |= fspTtForDate
+	case mysql.TypeTimestamp:
+		*p |= uint64(fsp)<<1 | 1
+	default:
+		*p |= uint64(fsp) << 1
+	}
+	return t
+}
+
+func (t Time) getFspTt() uint64 {
+	return uint64(t.coreTime) & fspTtBitFieldMask
+}
+
+func (t *Time) setFspTt(fspTt uint64) {
+	*(*uint64)(&t.coreTime) &= coreTimeBitFieldMask
+	*(*uint64)(&t.coreTime) |= fspTt & fspTtBitFieldMask
+}
+
+// Type returns type value.
+func (t Time) Type() uint8 {
+	fspTt := t.getFspTt()
+	if fspTt == fspTtForDate {
+		return mysql.TypeDate
+	}
+	if fspTt&ttBitMask == 1 {
+		return mysql.TypeTimestamp
+	}
+	return mysql.TypeDatetime
+}
+
+// Fsp returns fsp value.
+func (t Time) Fsp() int8 {
+	fspTt := t.getFspTt()
+	if fspTt == fspTtForDate {
+		return 0
+	}
+	return int8(fspTt >> 1)
+}
+
+// SetType updates the type in Time.
+// Only DateTime/Date/Timestamp is valid.
+func (t *Time) SetType(tp uint8) {
+	fsp := uint64(t.Fsp())
+	switch tp {
+	case mysql.TypeDate:
+		t.setFspTt(fspTtForDate)
+	case mysql.TypeTimestamp:
+		t.setFspTt(fsp<<1 | 1)
+	case mysql.TypeDatetime, mysql.TypeNewDate:
+		t.setFspTt(fsp << 1)
+	default:
+		panic("invalid tp value")
+	}
+}
+
+// SetFsp updates the fsp in Time.
+func (t *Time) SetFsp(fsp int8) {
+	if t.getFspTt() == fspTtForDate {
+		return
+	}
+	if fsp == UnspecifiedFsp {
+		fsp = DefaultFsp
+	}
+	t.setFspTt(uint64(fsp)<<1 | t.getFspTt()&ttBitMask)
+}
+
+// CoreTime returns core time.
+func (t Time) CoreTime() CoreTime {
+	return CoreTime(uint64(t.coreTime) & coreTimeBitFieldMask)
+}
+
+// SetCoreTime updates core time.
+func (t *Time) SetCoreTime(ct CoreTime) {
+	fspTt := t.getFspTt()
+	t.coreTime = CoreTime(uint64(ct)&coreTimeBitFieldMask | fspTt)
+}
+
+// Year returns the year value.
+func (t Time) Year() int {
+	return t.coreTime.Year()
+}
+
+// Month returns the month value.
+func (t Time) Month() int {
+	return t.coreTime.Month()
+}
+
+// Day returns the day value.
+func (t Time) Day() int {
+	return t.coreTime.Day()
+}
+
+// Hour returns the hour value.
+func (t Time) Hour() int {
+	return t.coreTime.Hour()
+}
+
+// Minute returns the minute value.
+func (t Time) Minute() int {
+	return t.coreTime.Minute()
+}
+
+// Second returns the second value.
+func (t Time) Second() int {
+	return t.coreTime.Second()
+}
+
+// Microsecond returns the microsecond value.
+func (t Time) Microsecond() int {
+	return t.coreTime.Microsecond()
+}
+
+// Clock returns the hour, minute, and second within the day specified by t.
+func (t Time) Clock() (hour int, minute int, second int) {
+	return t.Hour(), t.Minute(), t.Second()
+}
+
+// GoTime converts Time to GoTime.
+func (t Time) GoTime(loc *gotime.Location) (gotime.Time, error) {
+	return t.coreTime.GoTime(loc)
+}
+
+// String returns the time formatted using default TimeFormat and fsp.
+func (t Time) String() string {
+	if t.Type() == mysql.TypeDate {
+		return fmt.Sprintf("%04d-%02d-%02d", t.Year(), t.Month(), t.Day())
+	}
+	s := fmt.Sprintf("%04d-%02d-%02d %02d:%02d:%02d", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second())
+	if fsp := int(t.Fsp()); fsp > 0 {
+		frac := fmt.Sprintf("%06d", t.Microsecond())
+		s = s + "." + frac[:fsp]
+	}
+	return s
+}
+
+// IsZero returns a boolean indicating whether the time is equal to ZeroCoreTime.
+func (t Time) IsZero() bool {
+	return compareTime(t.coreTime, ZeroCoreTime) == 0
+}
+
+// InvalidZero returns a boolean indicating whether the month or day is zero.
+func (t Time) InvalidZero() bool {
+	return t.Month() == 0 || t.Day() == 0
+}
+
+// ToNumber returns a formatted number.
+// e.g,
+// 2012-12-12 -> 20121212
+// 2012-12-12T10:10:10 -> 20121212101010
+// 2012-12-12T10:10:10.123456 -> 20121212101010.123456
+func (t Time) ToNumber() *MyDecimal {
+	dec := new(MyDecimal)
+	if t.IsZero() {
+		dec.FromInt(0)
+		return dec
+	}
+	var s string
+	if t.Type() == mysql.TypeDate {
+		// Prevents from converting 2012-12-12 to 20121212000000.
+		s = fmt.Sprintf("%04d%02d%02d", t.Year(), t.Month(), t.Day())
+	} else {
+		s = fmt.Sprintf("%04d%02d%02d%02d%02d%02d", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second())
+	}
+	if fsp := int(t.Fsp()); fsp > 0 && t.Type() != mysql.TypeDate {
+		frac := fmt.Sprintf("%06d", t.Microsecond())
+		s = s + "." + frac[:fsp]
+	}
+	// We skip checking error here because time formatted string can be parsed certainly.
+	terror.Log(dec.FromString([]byte(s)))
+	return dec
+}
+
+// Convert converts t with type tp.
+func (t Time) Convert(sc *stmtctx.StatementContext, tp uint8) (Time, error) {
+	t1 := t
+	if t.Type() == tp || t.IsZero() {
+		t1.SetType(tp)
+		return t1, nil
+	}
+
+	t1.SetType(tp)
+	err := t1.check(sc)
+	return t1, errors.Trace(err)
+}
+
+// ConvertToDuration converts mysql datetime, timestamp and date to mysql time type.
+// e.g,
+// 2012-12-12T10:10:10 -> 10:10:10
+// 2012-12-12 -> 0
+func (t Time) ConvertToDuration() (Duration, error) {
+	if t.IsZero() {
+		return ZeroDuration, nil
+	}
+
+	hour, minute, second := t.Clock()
+	frac := t.Microsecond() * 1000
+
+	d := gotime.Duration(hour*3600+minute*60+second)*gotime.Second + gotime.Duration(frac)
+	return Duration{Duration: d, Fsp: t.Fsp()}, nil
+}
+
+// Compare returns an integer comparing the time instant t to o.
+// If t is after o, returns 1, equal o, returns 0, before o, returns -1.
+func (t Time) Compare(o Time) int {
+	return compareTime(t.coreTime, o.coreTime)
+}
+
+// CompareString is like Compare,
+// but parses string to Time then compares.
+func (t Time) CompareString(sc *stmtctx.StatementContext, str string) (int, error) {
+	// use MaxFsp to parse the string
+	o, err := ParseTime(sc, str, t.Type(), MaxFsp)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+
+	return t.Compare(o), nil
+}
+
+// roundTime rounds the time value according to digits count specified by fsp.
+func roundTime(t gotime.Time, fsp int8) gotime.Time {
+	d := gotime.Duration(math.Pow10(9 - int(fsp)))
+	return t.Round(d)
+}
+
+// RoundFrac rounds the fraction part of a time-type value according to `fsp`.
+func (t Time) RoundFrac(sc *stmtctx.StatementContext, fsp int8) (Time, error) {
+	if t.Type() == mysql.TypeDate || t.IsZero() {
+		// date type has no fsp
+		return t, nil
+	}
+
+	fsp, err := CheckFsp(int(fsp))
+	if err != nil {
+		return t, errors.Trace(err)
+	}
+
+	if fsp == t.Fsp() {
+		// have same fsp
+		return t, nil
+	}
+
+	var nt CoreTime
+	// Rounding does not depend on the time zone, so UTC is used to avoid the gaps of daylight saving time.
+	if t1, err := t.GoTime(gotime.UTC); err == nil {
+		t1 = roundTime(t1, fsp)
+		nt = FromGoTime(t1)
+	} else {
+		// Take the hh:mm:ss part out to avoid handle month or day = 0.
+		hour, minute, second, microsecond := t.Hour(), t.Minute(), t.Second(), t.Microsecond()
+		t1 := gotime.Date(1, 1, 1, hour, minute, second, microsecond*1000, gotime.UTC)
+		t2 := roundTime(t1, fsp)
+		hour, minute, second = t2.Clock()
+		microsecond = t2.Nanosecond() / 1000
+
+		// TODO: when hh:mm:ss overflow one day after rounding, it should be add to yy:mm:dd part,
+		// but mm:dd may contain 0, it makes the code complex, so we ignore it here.
+		if t2.Day()-1 > 0 {
+			return t, errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, t.String()))
+		}
+		nt = FromDate(t.Year(), t.Month(), t.Day(), hour, minute, second, microsecond)
+	}
+
+	return NewTime(nt, t.Type(), fsp), nil
+}
+
+// GetFsp gets the fsp of a string.
+func GetFsp(s string) int8 {
+	index := GetFracIndex(s)
+	var fsp int
+	if index < 0 {
+		fsp = 0
+	} else {
+		fsp = len(s) - index - 1
+	}
+
+	if fsp == len(s) {
+		fsp = 0
+	} else if fsp > int(MaxFsp) {
+		fsp = int(MaxFsp)
+	}
+	return int8(fsp)
+}
+
+// GetFracIndex finds the last '.' for get fracStr, index = -1 means fracStr not found.
+// but for format like '2019.01.01 00:00:00', the index should be -1.
+func GetFracIndex(s string) (index int) {
+	index = -1
+	for i := len(s) - 1; i >= 0; i-- {
+		if unicode.IsPunct(rune(s[i])) {
+			if s[i] == '.' {
+				index = i
+			}
+			break
+		}
+	}
+
+	return index
+}
+
+// ConvertTimeZone converts the time value from one timezone to another.
+// The input time should be a valid timestamp.
+func (t *Time) ConvertTimeZone(from, to *gotime.Location) error {
+	if !t.IsZero() {
+		raw, err := t.GoTime(from)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		converted := raw.In(to)
+		t.SetCoreTime(FromGoTime(converted))
+	}
+	return nil
+}
+
+// ToPackedUint encodes Time to a packed uint64 value.
+//
+//	 1 bit  0
+//	17 bits year*13+month   (year 0-9999, month 0-12)
+//	 5 bits day             (0-31)
+//	 5 bits hour            (0-23)
+//	 6 bits minute          (0-59)
+//	 6 bits second          (0-59)
+//	24 bits microseconds    (0-999999)
+//
+//	Total: 64 bits = 8 bytes
+//
+//	0YYYYYYY.YYYYYYYY.YYdddddh.hhhhmmmm.mmssssss.ffffffff.ffffffff.ffffffff
+//
+// The packed value keeps the order of time values, so it is also used in memcomparable keys.
+func (t Time) ToPackedUint() (uint64, error) {
+	if t.IsZero() {
+		return 0, nil
+	}
+	year, month, day := t.Year(), t.Month(), t.Day()
+	hour, minute, sec := t.Hour(), t.Minute(), t.Second()
+	ymd := uint64(((year*13 + month) << 5) | day)
+	hms := uint64(hour<<12 | minute<<6 | sec)
+	micro := uint64(t.Microsecond())
+	return ((ymd<<17 | hms) << 24) | micro, nil
+}
+
+// FromPackedUint decodes Time from a packed uint64 value.
+func (t *Time) FromPackedUint(packed uint64) error {
+	if packed == 0 {
+		t.SetCoreTime(ZeroCoreTime)
+		return nil
+	}
+	ymdhms := packed >> 24
+	ymd := ymdhms >> 17
+	day := int(ymd & (1<<5 - 1))
+	ym := ymd >> 5
+	month := int(ym % 13)
+	year := int(ym / 13)
+
+	hms := ymdhms & (1<<17 - 1)
+	second := int(hms & (1<<6 - 1))
+	minute := int((hms >> 6) & (1<<6 - 1))
+	hour := int(hms >> 12)
+	microsec := int(packed % (1 << 24))
+
+	t.SetCoreTime(FromDate(year, month, day, hour, minute, second, microsec))
+
+	return nil
+}
+
+// check whether t matches valid Time format.
+// If allowZeroInDate is false, it returns ErrZeroDate when month or day is zero.
+// FIXME: See https://dev.mysql.com/doc/refman/5.7/en/sql-mode.html#sqlmode_no_zero_in_date
+func (t Time) check(sc *stmtctx.StatementContext) error {
+	allowZeroInDate := false
+	allowInvalidDate := false
+	// We should avoid passing sc as nil here as far as possible.
+	if sc != nil {
+		allowZeroInDate = sc.IgnoreZeroInDate
+		allowInvalidDate = sc.AllowInvalidDate
+	}
+	var err error
+	switch t.Type() {
+	case mysql.TypeTimestamp:
+		err = checkTimestampType(sc, t.coreTime)
+	case mysql.TypeDatetime, mysql.TypeDate:
+		err = checkDatetimeType(t.coreTime, allowZeroInDate, allowInvalidDate)
+	}
+	return errors.Trace(err)
+}
+
+// Check if 't' is valid
+func (t *Time) Check(sc *stmtctx.StatementContext) error {
+	return t.check(sc)
+}
+
+// CurrentTime returns current time with type tp.
+func CurrentTime(tp uint8) Time {
+	return NewTime(FromGoTime(gotime.Now()), tp, 0)
+}
+
+// ParseDateFormat parses a formatted date string and returns separated components.
+func ParseDateFormat(format string) []string {
+	format = strings.TrimSpace(format)
+
+	if len(format) == 0 {
+		return nil
+	}
+
+	// Date format must start with number.
+	if !isDigit(format[0]) {
+		return nil
+	}
+
+	start := 0
+	// Initialize `seps` with capacity of 6. The input `format` is typically
+	// a date time of the form "2006-01-02 15:04:05", which has 6 numeric parts
+	// (the fractional second part is usually removed by `splitDateTime`).
+	// Setting `seps`'s capacity to 6 avoids reallocation in this common case.
+	seps := make([]string, 0, 6)
+
+	for i := 1; i < len(format)-1; i++ {
+		if isValidSeparator(format[i], len(seps)) {
+			prevParts := len(seps)
+			seps = append(seps, format[start:i])
+			start = i + 1
+
+			// consume further consecutive separators
+			for j := i + 1; j < len(format); j++ {
+				if !isValidSeparator(format[j], prevParts) {
+					break
+				}
+
+				start++
+				i++
+			}
+
+			continue
+		}
+
+		if !isDigit(format[i]) {
+			return nil
+		}
+	}
+
+	seps = append(seps, format[start:])
+	return seps
+}
+
+// helper for date part splitting, punctuation characters are valid separators anywhere,
+// while space and 'T' are valid separators only between date and time.
+func isValidSeparator(c byte, prevParts int) bool {
+	if isPunctuation(c) {
+		return true
+	}
+
+	return prevParts == 2 && (c == ' ' || c == 'T')
+}
+
+func isPunctuation(c byte) bool {
+	return (c >= 0x21 && c <= 0x2F) || (c >= 0x3A && c <= 0x40) || (c >= 0x5B && c <= 0x60) || (c >= 0x7B && c <= 0x7E)
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-literals.html.
+// The only delimiter recognized between a date and time part and a fractional seconds part is the decimal point.
+func splitDateTime(format string) (seps []string, fracStr string) {
+	index := GetFracIndex(format)
+	if index > 0 {
+		fracStr = format[index+1:]
+		format = format[:index]
+	}
+
+	seps = ParseDateFormat(format)
+	return
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-literals.html.
+func parseDatetime(sc *stmtctx.StatementContext, str string, fsp int8, isFloat bool) (Time, error) {
+	var (
+		year, month, day, hour, minute, second int
+		fracStr                                string
+		hhmmss                                 bool
+		err                                    error
+	)
+
+	seps, fracStr := splitDateTime(str)
+	var truncatedOrIncorrect bool
+	switch len(seps) {
+	case 1:
+		l := len(seps[0])
+		switch l {
+		case 14: // No delimiter.
+			// YYYYMMDDHHMMSS
+			_, err = fmt.Sscanf(seps[0], "%4d%2d%2d%2d%2d%2d", &year, &month, &day, &hour, &minute, &second)
+			hhmmss = true
+		case 12: // YYMMDDHHMMSS
+			_, err = fmt.Sscanf(seps[0], "%2d%2d%2d%2d%2d%2d", &year, &month, &day, &hour, &minute, &second)
+			year = adjustYear(year)
+			hhmmss = true
+		case 11: // YYMMDDHHMMS
+			_, err = fmt.Sscanf(seps[0], "%2d%2d%2d%2d%2d%1d", &year, &month, &day, &hour, &minute, &second)
+			year = adjustYear(year)
+			hhmmss = true
+		case 10: // YYMMDDHHMM
+			_, err = fmt.Sscanf(seps[0], "%2d%2d%2d%2d%2d", &year, &month, &day, &hour, &minute)
+			year = adjustYear(year)
+		case 9: // YYMMDDHHM
+			_, err = fmt.Sscanf(seps[0], "%2d%2d%2d%2d%1d", &year, &month, &day, &hour, &minute)
+			year = adjustYear(year)
+		case 8: // YYYYMMDD
+			_, err = fmt.Sscanf(seps[0], "%4d%2d%2d", &year, &month, &day)
+		case 7: // YYMMDDH
+			_, err = fmt.Sscanf(seps[0], "%2d%2d%2d%1d", &year, &month, &day, &hour)
+			year = adjustYear(year)
+		case 6, 5:
+			// YYMMDD && YYMMD
+			_, err = fmt.Sscanf(seps[0], "%2d%2d%2d", &year, &month, &day)
+			year = adjustYear(year)
+		default:
+			return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, str))
+		}
+		if l == 5 || l == 6 || l == 8 {
+			// YYMMDD or YYYYMMDD
+			// We must handle float => string => datetime, the difference is that fractional
+			// part of float type is discarded directly, while fractional part of string type
+			// is parsed to HH:MM:SS.
+			if !isFloat {
+				// '20170118.123423' => 2017-01-18 12:34:23.234
+				switch len(fracStr) {
+				case 0:
+				case 1, 2:
+					_, err = fmt.Sscanf(fracStr, "%2d ", &hour)
+				case 3, 4:
+					_, err = fmt.Sscanf(fracStr, "%2d%2d ", &hour, &minute)
+				default:
+					_, err = fmt.Sscanf(fracStr, "%2d%2d%2d ", &hour, &minute, &second)
+				}
+				truncatedOrIncorrect = err != nil
+			}
+		}
+		if l == 9 || l == 10 {
+			if len(fracStr) == 0 {
+				second = 0
+			} else {
+				_, err = fmt.Sscanf(fracStr, "%2d ", &second)
+			}
+			truncatedOrIncorrect = err != nil
+		}
+		if truncatedOrIncorrect && sc != nil {
+			sc.AppendWarning(ErrTruncatedWrongVal.GenWithStackByArgs(DateTimeStr, str))
+			err = nil
+		}
+	case 2:
+		// YYYY-MM is not valid
+		if len(fracStr) == 0 {
+			return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, str))
+		}
+
+		// YYYY-MM.DD, DD is treat as fracStr
+		err = scanTimeArgs(append(seps, fracStr), &year, &month, &day)
+		fracStr = ""
+	case 3:
+		// YYYY-MM-DD
+		err = scanTimeArgs(seps, &year, &month, &day)
+	case 4:
+		// YYYY-MM-DD HH
+		err = scanTimeArgs(seps, &year, &month, &day, &hour)
+	case 5:
+		// YYYY-MM-DD HH-MM
+		err = scanTimeArgs(seps, &year, &month, &day, &hour, &minute)
+	case 6:
+		// We don't have fractional seconds part.
+		// YYYY-MM-DD HH-MM-SS
+		err = scanTimeArgs(seps, &year, &month, &day, &hour, &minute, &second)
+		hhmmss = true
+	default:
+		return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, str))
+	}
+	if err != nil {
+		return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, str))
+	}
+
+	// If str is sepereated by delimiters, the first one is year, and if the year is 2 digit,
+	// we should adjust it.
+	// TODO: adjust year is very complex, now we only consider the simplest way.
+	if len(seps[0]) == 2 {
+		if year == 0 && month == 0 && day == 0 && hour == 0 && minute == 0 && second == 0 && fracStr == "" {
+			// Skip a special case "00-00-00".
+		} else {
+			year = adjustYear(year)
+		}
+	}
+
+	var microsecond int
+	var overflow bool
+	if hhmmss {
+		// If input string is "20170118.999", without hhmmss, fsp is meaningless.
+		microsecond, overflow, err = ParseFrac(fracStr, fsp)
+		if err != nil {
+			return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, str))
+		}
+	}
+
+	tmp, ok := FromDateChecked(year, month, day, hour, minute, second, microsecond)
+	if !ok {
+		return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, str))
+	}
+	if overflow {
+		// Convert to Go time and add 1 second, to handle input like 2017-01-05 08:40:59.575601
+		t1, err := tmp.GoTime(gotime.UTC)
+		if err != nil {
+			return ZeroDatetime, errors.Trace(err)
+		}
+		tmp = FromGoTime(t1.Add(gotime.Second))
+	}
+
+	return NewTime(tmp, mysql.TypeDatetime, fsp), nil
+}
+
+func scanTimeArgs(seps []string, args ...*int) error {
+	if len(seps) != len(args) {
+		return errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, seps))
+	}
+
+	var err error
+	for i, s := range seps {
+		*args[i], err = strconv.Atoi(s)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// FromDateChecked makes a internal time representation from the given date,
+// returns false if the date fields can not be stored in a CoreTime.
+func FromDateChecked(year, month, day, hour, minute, second, microsecond int) (CoreTime, bool) {
+	if year < 0 || year > 9999 || month < 0 || month > 12 || day < 0 || day > 31 ||
+		hour < 0 || hour > 23 || minute < 0 || minute > 59 || second < 0 || second > 59 ||
+		microsecond < 0 || microsecond > 999999 {
+		return ZeroCoreTime, false
+	}
+	return FromDate(year, month, day, hour, minute, second, microsecond), true
+}
+
+// ParseYear parses a formatted string and returns a year number.
+func ParseYear(str string) (int16, error) {
+	v, err := strconv.ParseInt(str, 10, 16)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	y := int16(v)
+
+	if len(str) == 4 {
+		// Nothing to do.
+	} else if len(str) == 2 || len(str) == 1 {
+		y = int16(adjustYear(int(y)))
+	} else {
+		return 0, errors.Trace(ErrInvalidYearFormat)
+	}
+
+	if y < MinYear || y > MaxYear {
+		return 0, errors.Trace(ErrInvalidYearFormat)
+	}
+
+	return y, nil
+}
+
+// adjustYear adjusts year according to y.
+// See https://dev.mysql.com/doc/refman/5.7/en/two-digit-years.html
+func adjustYear(y int) int {
+	if y >= 0 && y <= 69 {
+		y = 2000 + y
+	} else if y >= 70 && y <= 99 {
+		y = 1900 + y
+	}
+	return y
+}
+
+// AdjustYear is used for adjusting year and checking its validation.
+func AdjustYear(y int64, shouldAdjust bool) (int64, error) {
+	if y == 0 && !shouldAdjust {
+		return y, nil
+	}
+	y = int64(adjustYear(int(y)))
+	if y < 0 {
+		return 0, errors.Trace(ErrInvalidYear)
+	}
+	if y < int64(MinYear) {
+		return int64(MinYear), errors.Trace(ErrInvalidYear)
+	}
+	if y > int64(MaxYear) {
+		return int64(MaxYear), errors.Trace(ErrInvalidYear)
+	}
+	return y, nil
+}
+
+// Duration is the type for MySQL TIME type.
+type Duration struct {
+	gotime.Duration
+	// Fsp is short for Fractional Seconds Precision.
+	// See http://dev.mysql.com/doc/refman/5.7/en/fractional-seconds.html
+	Fsp int8
+}
+
+// NewDuration construct duration with time.
+func NewDuration(hour, minute, second, microsecond int, fsp int8) Duration {
+	return Duration{
+		Duration: gotime.Duration(hour)*gotime.Hour + gotime.Duration(minute)*gotime.Minute + gotime.Duration(second)*gotime.Second + gotime.Duration(microsecond)*gotime.Microsecond,
+		Fsp:      fsp,
+	}
+}
+
+// Add adds d to d, returns a duration value.
+func (d Duration) Add(v Duration) (Duration, error) {
+	dsum, err := AddInt64(int64(d.Duration), int64(v.Duration))
+	if err != nil {
+		return Duration{}, errors.Trace(err)
+	}
+	if d.Fsp >= v.Fsp {
+		return Duration{Duration: gotime.Duration(dsum), Fsp: d.Fsp}, nil
+	}
+	return Duration{Duration: gotime.Duration(dsum), Fsp: v.Fsp}, nil
+}
+
+// Sub subtracts d to d, returns a duration value.
+func (d Duration) Sub(v Duration) (Duration, error) {
+	dsum, err := SubInt64(int64(d.Duration), int64(v.Duration))
+	if err != nil {
+		return Duration{}, errors.Trace(err)
+	}
+	if d.Fsp >= v.Fsp {
+		return Duration{Duration: gotime.Duration(dsum), Fsp: d.Fsp}, nil
+	}
+	return Duration{Duration: gotime.Duration(dsum), Fsp: v.Fsp}, nil
+}
+
+// String returns the time formatted using default TimeFormat and fsp.
+func (d Duration) String() string {
+	var buf bytes.Buffer
+
+	sign, hours, minutes, seconds, fraction := splitDuration(d.Duration)
+	if sign < 0 {
+		buf.WriteByte('-')
+	}
+
+	fmt.Fprintf(&buf, "%02d:%02d:%02d", hours, minutes, seconds)
+	if d.Fsp > 0 {
+		buf.WriteString(".")
+		buf.WriteString(d.formatFrac(fraction))
+	}
+
+	return buf.String()
+}
+
+func (d Duration) formatFrac(frac int) string {
+	s := fmt.Sprintf("%06d", frac)
+	return s[0:d.Fsp]
+}
+
+// ToNumber changes duration to number format.
+// e.g,
+// 10:10:10 -> 101010
+func (d Duration) ToNumber() *MyDecimal {
+	sign, hours, minutes, seconds, fraction := splitDuration(d.Duration)
+	var (
+		s       string
+		signStr string
+	)
+
+	if sign < 0 {
+		signStr = "-"
+	}
+
+	if d.Fsp == 0 {
+		s = fmt.Sprintf("%s%02d%02d%02d", signStr, hours, minutes, seconds)
+	} else {
+		s = fmt.Sprintf("%s%02d%02d%02d.%s", signStr, hours, minutes, seconds, d.formatFrac(fraction))
+	}
+
+	// We skip checking error here because time formatted string can be parsed certainly.
+	dec := new(MyDecimal)
+	terror.Log(dec.FromString([]byte(s)))
+	return dec
+}
+
+// ConvertToTime converts duration to Time.
+// Tp is TypeDatetime, TypeTimestamp and TypeDate.
+func (d Duration) ConvertToTime(sc *stmtctx.StatementContext, tp uint8) (Time, error) {
+	loc := gotime.Local
+	if sc != nil && sc.TimeZone != nil {
+		loc = sc.TimeZone
+	}
+	year, month, day := gotime.Now().In(loc).Date()
+	// The duration is added to the midnight of the current date in UTC,
+	// so that the result is not affected by daylight saving time.
+	t := gotime.Date(year, month, day, 0, 0, 0, 0, gotime.UTC).Add(d.Duration)
+	return NewTime(FromGoTime(t), mysql.TypeDatetime, d.Fsp).Convert(sc, tp)
+}
+
+// RoundFrac rounds fractional seconds precision with new fsp and returns a new one.
+// We will use the “round half up” rule, e.g, >= 0.5 -> 1, < 0.5 -> 0,
+// so 10:10:10.999999 round 0 -> 10:10:11
+// and 10:10:10.000000 round 0 -> 10:10:10
+func (d Duration) RoundFrac(fsp int8) (Duration, error) {
+	fsp, err := CheckFsp(int(fsp))
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+
+	if fsp == d.Fsp {
+		return d, nil
+	}
+
+	nd := d.Duration.Round(gotime.Duration(math.Pow10(9-int(fsp))) * gotime.Nanosecond)
+	return Duration{Duration: nd, Fsp: fsp}, nil
+}
+
+// Compare returns an integer comparing the Duration instant t to o.
+// If d is after o, returns 1, equal o, returns 0, before o, returns -1.
+func (d Duration) Compare(o Duration) int {
+	if d.Duration > o.Duration {
+		return 1
+	} else if d.Duration == o.Duration {
+		return 0
+	} else {
+		return -1
+	}
+}
+
+// CompareString is like Compare,
+// but parses str to Duration then compares.
+func (d Duration) CompareString(sc *stmtctx.StatementContext, str string) (int, error) {
+	// use MaxFsp to parse the string
+	o, err := ParseDuration(sc, str, MaxFsp)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.Compare(o), nil
+}
+
+// Hour returns current hour.
+// e.g, hour("11:11:11") -> 11
+func (d Duration) Hour() int {
+	_, hour, _, _, _ := splitDuration(d.Duration)
+	return hour
+}
+
+// Minute returns current minute.
+// e.g, hour("11:11:11") -> 11
+func (d Duration) Minute() int {
+	_, _, minute, _, _ := splitDuration(d.Duration)
+	return minute
+}
+
+// Second returns current second.
+// e.g, hour("11:11:11") -> 11
+func (d Duration) Second() int {
+	_, _, _, second, _ := splitDuration(d.Duration)
+	return second
+}
+
+// MicroSecond returns current microsecond.
+// e.g, hour("11:11:11.11") -> 110000
+func (d Duration) MicroSecond() int {
+	_, _, _, _, frac := splitDuration(d.Duration)
+	return frac
+}
+
+// ParseDuration parses the time form a formatted string with a fractional seconds part,
+// returns the duration type Time value.
+// See http://dev.mysql.com/doc/refman/5.7/en/fractional-seconds.html
+func ParseDuration(sc *stmtctx.StatementContext, str string, fsp int8) (Duration, error) {
+	var (
+		day, hour, minute, second int
+		err                       error
+		sign                      = 0
+		dayExists                 = false
+		origStr                   = str
+	)
+
+	fsp, err = CheckFsp(int(fsp))
+	if err != nil {
+		return ZeroDuration, errors.Trace(err)
+	}
+
+	str = strings.TrimSpace(str)
+	if len(str) == 0 {
+		return ZeroDuration, nil
+	} else if str[0] == '-' {
+		str = str[1:]
+		sign = -1
+	}
+
+	// Time format may has day.
+	if n := strings.IndexByte(str, ' '); n >= 0 {
+		if day, err = strconv.Atoi(str[:n]); err == nil {
+			dayExists = true
+		}
+		str = str[n+1:]
+	}
+
+	var (
+		integeralPart = str
+		fracPart      int
+		overflow      bool
+	)
+	if n := strings.IndexByte(str, '.'); n >= 0 {
+		// It has fractional precision parts.
+		fracStr := str[n+1:]
+		fracPart, overflow, err = ParseFrac(fracStr, fsp)
+		if err != nil {
+			return ZeroDuration, ErrTruncatedWrongVal.GenWithStackByArgs(TimeStr, origStr)
+		}
+		integeralPart = str[0:n]
+	}
+
+	// It tries to split integeralPart with delimiter, time delimiter must be :
+	seps := strings.Split(integeralPart, ":")
+
+	switch len(seps) {
+	case 1:
+		if dayExists {
+			hour, err = strconv.Atoi(seps[0])
+		} else {
+			// No delimiter.
+			switch len(integeralPart) {
+			case 7: // HHHMMSS
+				_, err = fmt.Sscanf(integeralPart, "%3d%2d%2d", &hour, &minute, &second)
+			case 6: // HHMMSS
+				_, err = fmt.Sscanf(integeralPart, "%2d%2d%2d", &hour, &minute, &second)
+			case 5: // HMMSS
+				_, err = fmt.Sscanf(integeralPart, "%1d%2d%2d", &hour, &minute, &second)
+			case 4: // MMSS
+				_, err = fmt.Sscanf(integeralPart, "%2d%2d", &minute, &second)
+			case 3: // MSS
+				_, err = fmt.Sscanf(integeralPart, "%1d%2d", &minute, &second)
+			case 2: // SS
+				_, err = fmt.Sscanf(integeralPart, "%2d", &second)
+			case 1: // 0S
+				_, err = fmt.Sscanf(integeralPart, "%1d", &second)
+			default: // Maybe contains date.
+				t, err1 := ParseDatetime(sc, str)
+				if err1 != nil {
+					return ZeroDuration, ErrTruncatedWrongVal.GenWithStackByArgs(TimeStr, origStr)
+				}
+				var dur Duration
+				dur, err1 = t.ConvertToDuration()
+				if err1 != nil {
+					return ZeroDuration, errors.Trace(err1)
+				}
+				return dur.RoundFrac(fsp)
+			}
+		}
+	case 2:
+		// HH:MM
+		_, err = fmt.Sscanf(integeralPart, "%2d:%2d", &hour, &minute)
+	case 3:
+		// Time format maybe HH:MM:SS or HHH:MM:SS.
+		// See https://dev.mysql.com/doc/refman/5.7/en/time.html
+		if len(seps[0]) == 3 {
+			_, err = fmt.Sscanf(integeralPart, "%3d:%2d:%2d", &hour, &minute, &second)
+		} else {
+			_, err = fmt.Sscanf(integeralPart, "%2d:%2d:%2d", &hour, &minute, &second)
+		}
+	default:
+		return ZeroDuration, ErrTruncatedWrongVal.GenWithStackByArgs(TimeStr, origStr)
+	}
+
+	if err != nil {
+		return ZeroDuration, ErrTruncatedWrongVal.GenWithStackByArgs(TimeStr, origStr)
+	}
+
+	if overflow {
+		second++
+		fracPart = 0
+	}
+	// Invalid TIME values are converted to '00:00:00'.
+	// See https://dev.mysql.com/doc/refman/5.7/en/time.html
+	if minute >= 60 || second > 60 || (!overflow && second == 60) {
+		return ZeroDuration, ErrTruncatedWrongVal.GenWithStackByArgs(TimeStr, origStr)
+	}
+	d := gotime.Duration(day*24*3600+hour*3600+minute*60+second)*gotime.Second + gotime.Duration(fracPart)*gotime.Microsecond
+	if sign == -1 {
+		d = -d
+	}
+
+	d, err = TruncateOverflowMySQLTime(d)
+	return Duration{Duration: d, Fsp: fsp}, errors.Trace(err)
+}
+
+// TruncateOverflowMySQLTime truncates d when it overflows, and return ErrTruncatedWrongVal.
+func TruncateOverflowMySQLTime(d gotime.Duration) (gotime.Duration, error) {
+	if d > MaxTime {
+		return MaxTime, ErrTruncatedWrongVal.GenWithStackByArgs(TimeStr, d)
+	} else if d < MinTime {
+		return MinTime, ErrTruncatedWrongVal.GenWithStackByArgs(TimeStr, d)
+	}
+
+	return d, nil
+}
+
+func splitDuration(t gotime.Duration) (int, int, int, int, int) {
+	sign := 1
+	if t < 0 {
+		t = -t
+		sign = -1
+	}
+
+	hours := t / gotime.Hour
+	t -= hours * gotime.Hour
+	minutes := t / gotime.Minute
+	t -= minutes * gotime.Minute
+	seconds := t / gotime.Second
+	t -= seconds * gotime.Second
+	fraction := t / gotime.Microsecond
+
+	return sign, int(hours), int(minutes), int(seconds), int(fraction)
+}
+
+// NumberToDuration converts number to Duration.
+func NumberToDuration(number int64, fsp int8) (Duration, error) {
+	if number > TimeMaxValue {
+		// Try to check whether it's a datetime.
+		// See http://dev.mysql.com/doc/refman/5.7/en/time.html
+		if number >= 10000000000 { // '2001-00-00 00-00-00'
+			if t, err := ParseDatetimeFromNum(nil, number); err == nil {
+				dur, err1 := t.ConvertToDuration()
+				return dur, errors.Trace(err1)
+			}
+		}
+		dur := NewDuration(TimeMaxHour, TimeMaxMinute, TimeMaxSecond, 0, fsp)
+		return dur, errors.Trace(ErrOverflow.GenWithStackByArgs("Duration", strconv.Itoa(int(number))))
+	} else if number < -TimeMaxValue {
+		dur := NewDuration(TimeMaxHour, TimeMaxMinute, TimeMaxSecond, 0, fsp)
+		dur.Duration = -dur.Duration
+		return dur, errors.Trace(ErrOverflow.GenWithStackByArgs("Duration", strconv.Itoa(int(number))))
+	}
+	var neg bool
+	if neg = number < 0; neg {
+		number = -number
+	}
+
+	if number/10000 > TimeMaxHour || number%100 >= 60 || (number/100)%100 >= 60 {
+		return ZeroDuration, errors.Trace(ErrInvalidTimeFormat.GenWithStackByArgs(strconv.Itoa(int(number))))
+	}
+	dur := NewDuration(int(number/10000), int((number/100)%100), int(number%100), 0, fsp)
+	if neg {
+		dur.Duration = -dur.Duration
+	}
+	return dur, nil
+}
+
+func getTime(sc *stmtctx.StatementContext, num int64, tp byte) (Time, error) {
+	s1 := num / 1000000
+	s2 := num - s1*1000000
+
+	year := int(s1 / 10000)
+	s1 %= 10000
+	month := int(s1 / 100)
+	day := int(s1 % 100)
+
+	hour := int(s2 / 10000)
+	s2 %= 10000
+	minute := int(s2 / 100)
+	second := int(s2 % 100)
+
+	ct, ok := FromDateChecked(year, month, day, hour, minute, second, 0)
+	if !ok {
+		numStr := strconv.FormatInt(num, 10)
+		return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, numStr))
+	}
+	t := NewTime(ct, tp, DefaultFsp)
+	err := t.check(sc)
+	return t, errors.Trace(err)
+}
+
+// parseDateTimeFromNum parses date time from num.
+// See number case in https://dev.mysql.com/doc/refman/5.7/en/date-and-time-literals.html
+func parseDateTimeFromNum(sc *stmtctx.StatementContext, num int64) (Time, error) {
+	t := ZeroDate
+	// Check zero.
+	if num == 0 {
+		return t, nil
+	}
+
+	// Check datetime type.
+	if num >= 10000101000000 {
+		t.SetType(mysql.TypeDatetime)
+		return getTime(sc, num, t.Type())
+	}
+
+	// Check MMDD.
+	if num < 101 {
+		return t, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, strconv.FormatInt(num, 10)))
+	}
+
+	// Adjust year
+	// YYMMDD, year: 2000-2069
+	if num <= (70-1)*10000+1231 {
+		num = (num + 20000000) * 1000000
+		return getTime(sc, num, t.Type())
+	}
+
+	// Check YYMMDD.
+	if num < 70*10000+101 {
+		return t, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, strconv.FormatInt(num, 10)))
+	}
+
+	// Adjust year
+	// YYMMDD, year: 1970-1999
+	if num <= 991231 {
+		num = (num + 19000000) * 1000000
+		return getTime(sc, num, t.Type())
+	}
+
+	// Adjust hour/min/second.
+	if num <= 99991231 {
+		num = num * 1000000
+		return getTime(sc, num, t.Type())
+	}
+
+	// Check MMDDHHMMSS.
+	if num < 101000000 {
+		return t, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, strconv.FormatInt(num, 10)))
+	}
+
+	// Set TypeDatetime type.
+	t.SetType(mysql.TypeDatetime)
+
+	// Adjust year
+	// YYMMDDHHMMSS, 2000-2069
+	if num <= 69*10000000000+1231235959 {
+		num = num + 20000000000000
+		return getTime(sc, num, t.Type())
+	}
+
+	// Check YYYYMMDDHHMMSS.
+	if num < 70*10000000000+101000000 {
+		return t, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, strconv.FormatInt(num, 10)))
+	}
+
+	// Adjust year
+	// YYMMDDHHMMSS, 1970-1999
+	if num <= 991231235959 {
+		num = num + 19000000000000
+		return getTime(sc, num, t.Type())
+	}
+
+	return getTime(sc, num, t.Type())
+}
+
+// ParseTime parses a formatted string with type tp and specific fsp.
+// Type is TypeDatetime, TypeTimestamp and TypeDate.
+// Fsp is in range [0, 6].
+// MySQL supports many valid datetime format, but still has some limitation.
+// If delimiter exists, the date part and time part is separated by a space or T,
+// other punctuation character can be used as the delimiter between date parts or time parts.
+// If no delimiter, the format must be YYYYMMDDHHMMSS or YYMMDDHHMMSS
+// If we have fractional seconds part, we must use decimal points as the delimiter.
+// The valid datetime range is from '1000-01-01 00:00:00.000000' to '9999-12-31 23:59:59.999999'.
+// The valid timestamp range is from '1970-01-01 00:00:01.000000' to '2038-01-19 03:14:07.999999'.
+// The valid date range is from '1000-01-01' to '9999-12-31'
+func ParseTime(sc *stmtctx.StatementContext, str string, tp byte, fsp int8) (Time, error) {
+	return parseTime(sc, str, tp, fsp, false)
+}
+
+// ParseTimeFromFloatString is similar to ParseTime, except that it's used to parse a float converted string.
+func ParseTimeFromFloatString(sc *stmtctx.StatementContext, str string, tp byte, fsp int8) (Time, error) {
+	// MySQL compatibility: 0.0 should not be converted to null.
+	if len(str) >= 3 && str[:3] == "0.0" {
+		return NewTime(ZeroCoreTime, tp, DefaultFsp), nil
+	}
+	return parseTime(sc, str, tp, fsp, true)
+}
+
+func parseTime(sc *stmtctx.StatementContext, str string, tp byte, fsp int8, isFloat bool) (Time, error) {
+	fsp, err := CheckFsp(int(fsp))
+	if err != nil {
+		return NewTime(ZeroCoreTime, tp, DefaultFsp), errors.Trace(err)
+	}
+
+	t, err := parseDatetime(sc, str, fsp, isFloat)
+	if err != nil {
+		return NewTime(ZeroCoreTime, tp, DefaultFsp), errors.Trace(err)
+	}
+
+	t.SetType(tp)
+	if tp == mysql.TypeDate {
+		// Truncate hh:mm:ss part if the type is Date.
+		t.SetCoreTime(FromDate(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0))
+	}
+	if err = t.check(sc); err != nil {
+		return NewTime(ZeroCoreTime, tp, DefaultFsp), errors.Trace(err)
+	}
+	return t, nil
+}
+
+// ParseDatetime is a helper function wrapping ParseTime with datetime type and default fsp.
+func ParseDatetime(sc *stmtctx.StatementContext, str string) (Time, error) {
+	return ParseTime(sc, str, mysql.TypeDatetime, GetFsp(str))
+}
+
+// ParseTimestamp is a helper function wrapping ParseTime with timestamp type and default fsp.
+func ParseTimestamp(sc *stmtctx.StatementContext, str string) (Time, error) {
+	return ParseTime(sc, str, mysql.TypeTimestamp, GetFsp(str))
+}
+
+// ParseDate is a helper function wrapping ParseTime with date type.
+func ParseDate(sc *stmtctx.StatementContext, str string) (Time, error) {
+	// date has no fractional seconds precision
+	return ParseTime(sc, str, mysql.TypeDate, MinFsp)
+}
+
+// ParseTimeFromNum parses a formatted int64,
+// returns the value which type is tp.
+func ParseTimeFromNum(sc *stmtctx.StatementContext, num int64, tp byte, fsp int8) (Time, error) {
+	// MySQL compatibility: 0 should not be converted to null.
+	if num == 0 {
+		return NewTime(ZeroCoreTime, tp, DefaultFsp), nil
+	}
+	fsp, err := CheckFsp(int(fsp))
+	if err != nil {
+		return NewTime(ZeroCoreTime, tp, DefaultFsp), errors.Trace(err)
+	}
+
+	t, err := parseDateTimeFromNum(sc, num)
+	if err != nil {
+		return NewTime(ZeroCoreTime, tp, DefaultFsp), errors.Trace(err)
+	}
+
+	t.SetType(tp)
+	t.SetFsp(fsp)
+	if tp == mysql.TypeDate {
+		t.SetCoreTime(FromDate(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0))
+	}
+	if err := t.check(sc); err != nil {
+		return NewTime(ZeroCoreTime, tp, DefaultFsp), errors.Trace(err)
+	}
+	return t, nil
+}
+
+// ParseDatetimeFromNum is a helper function wrapping ParseTimeFromNum with datetime type and default fsp.
+func ParseDatetimeFromNum(sc *stmtctx.StatementContext, num int64) (Time, error) {
+	return ParseTimeFromNum(sc, num, mysql.TypeDatetime, DefaultFsp)
+}
+
+// ParseTimestampFromNum is a helper function wrapping ParseTimeFromNum with timestamp type and default fsp.
+func ParseTimestampFromNum(sc *stmtctx.StatementContext, num int64) (Time, error) {
+	return ParseTimeFromNum(sc, num, mysql.TypeTimestamp, DefaultFsp)
+}
+
+// ParseDateFromNum is a helper function wrapping ParseTimeFromNum with date type.
+func ParseDateFromNum(sc *stmtctx.StatementContext, num int64) (Time, error) {
+	// date has no fractional seconds precision
+	return ParseTimeFromNum(sc, num, mysql.TypeDate, MinFsp)
+}
+
+// ParseTimeFromInt64 parses mysql time value from int64.
+func ParseTimeFromInt64(sc *stmtctx.StatementContext, num int64) (Time, error) {
+	return parseDateTimeFromNum(sc, num)
+}
+
+func checkDateType(t CoreTime, allowZeroInDate, allowInvalidDate bool) error {
+	year, month, day := t.Year(), t.Month(), t.Day()
+	if year == 0 && month == 0 && day == 0 {
+		return nil
+	}
+
+	if !allowZeroInDate && (month == 0 || day == 0) {
+		return ErrWrongValue.GenWithStackByArgs(DateTimeStr, fmt.Sprintf("%04d-%02d-%02d", year, month, day))
+	}
+
+	if err := checkDateRange(t); err != nil {
+		return errors.Trace(err)
+	}
+
+	if err := checkMonthDay(year, month, day, allowInvalidDate); err != nil {
+		return errors.Trace(err)
+	}
+
+	return nil
+}
+
+func checkDateRange(t CoreTime) error {
+	// Oddly enough, MySQL document says date range should larger than '1000-01-01',
+	// but we can insert '0001-01-01' actually.
+	if compareTime(t, MaxDatetime) > 0 {
+		return errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, t))
+	}
+	return nil
+}
+
+func checkMonthDay(year, month, day int, allowInvalidDate bool) error {
+	if month < 0 || month > 12 {
+		return errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, fmt.Sprintf("%04d-%02d-%02d", year, month, day)))
+	}
+
+	maxDay := 31
+	if !allowInvalidDate {
+		if month > 0 {
+			maxDay = GetLastDay(year, month)
+		}
+	}
+
+	if day < 0 || day > maxDay {
+		return errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, fmt.Sprintf("%04d-%02d-%02d", year, month, day)))
+	}
+	return nil
+}
+
+func checkTimestampType(sc *stmtctx.StatementContext, t CoreTime) error {
+	if compareTime(t, ZeroCoreTime) == 0 {
+		return nil
+	}
+
+	if sc == nil {
+		return errors.New("statementContext is required during checkTimestampType")
+	}
+
+	loc := sc.TimeZone
+	if loc == nil {
+		loc = gotime.Local
+	}
+	var checkTime CoreTime
+	if loc != BoundTimezone {
+		convertTime := NewTime(t, mysql.TypeTimestamp, DefaultFsp)
+		err := convertTime.ConvertTimeZone(loc, BoundTimezone)
+		if err != nil {
+			return err
+		}
+		checkTime = convertTime.coreTime
+	} else {
+		checkTime = t
+	}
+	if compareTime(checkTime, MaxTimestamp.coreTime) > 0 || compareTime(checkTime, MinTimestamp.coreTime) < 0 {
+		return errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, NewTime(t, mysql.TypeDatetime, DefaultFsp).String()))
+	}
+
+	if _, err := t.GoTime(loc); err != nil {
+		return errors.Trace(err)
+	}
+
+	return nil
+}
+
+func checkDatetimeType(t CoreTime, allowZeroInDate, allowInvalidDate bool) error {
+	if err := checkDateType(t, allowZeroInDate, allowInvalidDate); err != nil {
+		return errors.Trace(err)
+	}
+
+	hour, minute, second := t.Hour(), t.Minute(), t.Second()
+	if hour < 0 || hour >= 24 {
+		return errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, strconv.Itoa(hour)))
+	}
+	if minute < 0 || minute >= 60 {
+		return errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, strconv.Itoa(minute)))
+	}
+	if second < 0 || second >= 60 {
+		return errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, strconv.Itoa(second)))
+	}
+
+	return nil
+}
+
+// MonthNames lists names of months, which are used in builtin time function `monthname`.
+var MonthNames = []string{
+	"January", "February",
+	"March", "April",
+	"May", "June",
+	"July", "August",
+	"September", "October",
+	"November", "December",
+}
+
+var abbrevWeekdayName = []string{
+	"Sun", "Mon", "Tue",
+	"Wed", "Thu", "Fri", "Sat",
+}
+
+// FormatIntWidthN uses to format int with width. Insufficient digits are filled by 0.
+func FormatIntWidthN(num, n int) string {
+	numString := strconv.Itoa(num)
+	if len(numString) >= n {
+		return numString
+	}
+	return strings.Repeat("0", n-len(numString)) + numString
+}
+
+func abbrDayOfMonth(day int) string {
+	var str string
+	switch day {
+	case 1, 21, 31:
+		str = "st"
+	case 2, 22:
+		str = "nd"
+	case 3, 23:
+		str = "rd"
+	default:
+		str = "th"
+	}
+	return str
+}
+
+// DateFormat returns a textual representation of the time value formatted
+// according to layout.
+// See http://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-format
+func (t Time) DateFormat(layout string) (string, error) {
+	var buf bytes.Buffer
+	inPatternMatch := false
+	for _, b := range layout {
+		if inPatternMatch {
+			if err := t.convertDateFormat(b, &buf); err != nil {
+				return "", errors.Trace(err)
+			}
+			inPatternMatch = false
+			continue
+		}
+
+		// It's not in pattern match now.
+		if b == '%' {
+			inPatternMatch = true
+		} else {
+			buf.WriteRune(b)
+		}
+	}
+	return buf.String(), nil
+}
+
+func (t Time) convertDateFormat(b rune, buf *bytes.Buffer) error {
+	switch b {
+	case 'b':
+		m := t.Month()
+		if m == 0 || m > 12 {
+			return errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, strconv.Itoa(m)))
+		}
+		buf.WriteString(MonthNames[m-1][:3])
+	case 'M':
+		m := t.Month()
+		if m == 0 || m > 12 {
+			return errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, strconv.Itoa(m)))
+		}
+		buf.WriteString(MonthNames[m-1])
+	case 'm':
+		buf.WriteString(FormatIntWidthN(t.Month(), 2))
+	case 'c':
+		buf.WriteString(strconv.FormatInt(int64(t.Month()), 10))
+	case 'D':
+		buf.WriteString(strconv.FormatInt(int64(t.Day()), 10))
+		buf.WriteString(abbrDayOfMonth(t.Day()))
+	case 'd':
+		buf.WriteString(FormatIntWidthN(t.Day(), 2))
+	case 'e':
+		buf.WriteString(strconv.FormatInt(int64(t.Day()), 10))
+	case 'j':
+		fmt.Fprintf(buf, "%03d", t.coreTime.YearDay())
+	case 'H':
+		buf.WriteString(FormatIntWidthN(t.Hour(), 2))
+	case 'k':
+		buf.WriteString(strconv.FormatInt(int64(t.Hour()), 10))
+	case 'h', 'I':
+		tt := t.Hour()
+		if tt%12 == 0 {
+			buf.WriteString("12")
+		} else {
+			buf.WriteString(FormatIntWidthN(tt%12, 2))
+		}
+	case 'l':
+		tt := t.Hour()
+		if tt%12 == 0 {
+			buf.WriteString("12")
+		} else {
+			buf.WriteString(strconv.FormatInt(int64(tt%12), 10))
+		}
+	case 'i':
+		buf.WriteString(FormatIntWidthN(t.Minute(), 2))
+	case 'p':
+		hour := t.Hour()
+		if hour/12%2 == 0 {
+			buf.WriteString("AM")
+		} else {
+			buf.WriteString("PM")
+		}
+	case 'r':
+		h := t.Hour()
+		h %= 24
+		switch {
+		case h == 0:
+			fmt.Fprintf(buf, "%02d:%02d:%02d AM", 12, t.Minute(), t.Second())
+		case h == 12:
+			fmt.Fprintf(buf, "%02d:%02d:%02d PM", 12, t.Minute(), t.Second())
+		case h < 12:
+			fmt.Fprintf(buf, "%02d:%02d:%02d AM", h, t.Minute(), t.Second())
+		default:
+			fmt.Fprintf(buf, "%02d:%02d:%02d PM", h-12, t.Minute(), t.Second())
+		}
+	case 'T':
+		fmt.Fprintf(buf, "%02d:%02d:%02d", t.Hour(), t.Minute(), t.Second())
+	case 'S', 's':
+		buf.WriteString(FormatIntWidthN(t.Second(), 2))
+	case 'f':
+		fmt.Fprintf(buf, "%06d", t.Microsecond())
+	case 'U':
+		w := t.coreTime.Week(0)
+		buf.WriteString(FormatIntWidthN(w, 2))
+	case 'u':
+		w := t.coreTime.Week(1)
+		buf.WriteString(FormatIntWidthN(w, 2))
+	case 'V':
+		w := t.coreTime.Week(2)
+		buf.WriteString(FormatIntWidthN(w, 2))
+	case 'v':
+		_, w := t.coreTime.YearWeek(3)
+		buf.WriteString(FormatIntWidthN(w, 2))
+	case 'a':
+		weekday := t.coreTime.Weekday()
+		buf.WriteString(abbrevWeekdayName[weekday])
+	case 'W':
+		buf.WriteString(t.coreTime.Weekday().String())
+	case 'w':
+		buf.WriteString(strconv.FormatInt(int64(t.coreTime.Weekday()), 10))
+	case 'X':
+		year, _ := t.coreTime.YearWeek(2)
+		buf.WriteString(FormatIntWidthN(year, 4))
+	case 'x':
+		year, _ := t.coreTime.YearWeek(3)
+		buf.WriteString(FormatIntWidthN(year, 4))
+	case 'Y':
+		buf.WriteString(FormatIntWidthN(t.Year(), 4))
+	case 'y':
+		str := FormatIntWidthN(t.Year(), 4)
+		buf.WriteString(str[2:])
+	default:
+		buf.WriteRune(b)
+	}
+
+	return nil
+}
+
+// dateParts holds the fields collected by StrToDate before they are packed into a CoreTime.
+type dateParts struct {
+	year, month, day, hour, minute, second, microsecond int
+	// isHour12 is set when the hour is parsed with a 12-hour clock token.
+	isHour12 bool
+	// meridiem is 0 if no AM/PM token is parsed, 1 for AM and 2 for PM.
+	meridiem int
+}
+
+// StrToDate converts date string according to format, it returns false if
+// the date doesn't match the format or the result is not a valid datetime.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-format
+func (t *Time) StrToDate(sc *stmtctx.StatementContext, date, format string) bool {
+	var parts dateParts
+	if !strToDate(&parts, date, format) || !parts.fixHour() {
+		return false
+	}
+	t.SetCoreTime(FromDate(parts.year, parts.month, parts.day, parts.hour, parts.minute, parts.second, parts.microsecond))
+	t.SetType(mysql.TypeDatetime)
+	return t.check(sc) == nil
+}
+
+// fixHour converts a 12-hour clock hour into a 24-hour clock one.
+func (p *dateParts) fixHour() bool {
+	if p.meridiem != 0 && !p.isHour12 {
+		// AM/PM is only allowed with a 12-hour clock.
+		return false
+	}
+	if p.isHour12 {
+		p.hour %= 12
+		if p.meridiem == 2 {
+			p.hour += 12
+		}
+	}
+	return true
+}
+
+func strToDate(p *dateParts, date string, format string) bool {
+	date = skipWhiteSpace(date)
+	format = skipWhiteSpace(format)
+
+	token, formatRemain, succ := getFormatToken(format)
+	if !succ {
+		return false
+	}
+	if token == "" {
+		// Extra characters at the end of date are ignored.
+		return true
+	}
+	if len(date) == 0 {
+		// Extra tokens at the end of format leave the fields zero.
+		return true
+	}
+
+	dateRemain, succ := matchDateWithToken(p, date, token)
+	if !succ {
+		return false
+	}
+	return strToDate(p, dateRemain, formatRemain)
+}
+
+func skipWhiteSpace(input string) string {
+	for i, c := range input {
+		if !unicode.IsSpace(c) {
+			return input[i:]
+		}
+	}
+	return ""
+}
+
+// getFormatToken takes one format specifier like "%Y", or one literal character from the format.
+func getFormatToken(format string) (token string, remain string, succ bool) {
+	if len(format) == 0 {
+		return "", "", true
+	}
+	if format[0] == '%' {
+		if len(format) < 2 {
+			return "", "", false
+		}
+		return format[:2], format[2:], true
+	}
+	return format[:1], format[1:], true
+}
+
+// parseDigits parses at most n leading digits of input, it returns the
+// number of digits consumed and their value.
+func parseDigits(input string, n int) (int, int) {
+	count, value := 0, 0
+	for count < n && count < len(input) && isDigit(input[count]) {
+		value = value*10 + int(input[count]-'0')
+		count++
+	}
+	return count, value
+}
+
+// parseDigitsInRange parses at most n leading digits of input and checks the value is in [min, max].
+func parseDigitsInRange(input string, n, min, max int) (string, int, bool) {
+	count, value := parseDigits(input, n)
+	if count == 0 || value < min || value > max {
+		return input, 0, false
+	}
+	return input[count:], value, true
+}
+
+// matchPrefixFold matches the longest name in names that input starts with, ignoring case.
+// It returns the index of the name.
+func matchPrefixFold(input string, names []string) (string, int, bool) {
+	for i, name := range names {
+		if len(input) >= len(name) && strings.EqualFold(input[:len(name)], name) {
+			return input[len(name):], i, true
+		}
+	}
+	return input, 0, false
+}
+
+var abbrevMonthNames = []string{
+	"Jan", "Feb", "Mar", "Apr", "May", "Jun",
+	"Jul", "Aug", "Sep", "Oct", "Nov", "Dec",
+}
+
+func matchDateWithToken(p *dateParts, date string, token string) (remain string, succ bool) {
+	if token[0] != '%' {
+		// A literal character must be matched exactly.
+		if date[0] != token[0] {
+			return date, false
+		}
+		return date[1:], true
+	}
+	switch token[1] {
+	case 'Y':
+		var count int
+		count, p.year = parseDigits(date, 4)
+		if count == 0 {
+			return date, false
+		}
+		if count <= 2 {
+			p.year = adjustYear(p.year)
+		}
+		return date[count:], true
+	case 'y':
+		remain, p.year, succ = parseDigitsInRange(date, 2, 0, 99)
+		p.year = adjustYear(p.year)
+		return remain, succ
+	case 'm', 'c':
+		remain, p.month, succ = parseDigitsInRange(date, 2, 0, 12)
+		return remain, succ
+	case 'M':
+		var i int
+		remain, i, succ = matchPrefixFold(date, MonthNames)
+		p.month = i + 1
+		return remain, succ
+	case 'b':
+		var i int
+		remain, i, succ = matchPrefixFold(date, abbrevMonthNames)
+		p.month = i + 1
+		return remain, succ
+	case 'd', 'e':
+		remain, p.day, succ = parseDigitsInRange(date, 2, 0, 31)
+		return remain, succ
+	case 'H', 'k':
+		remain, p.hour, succ = parseDigitsInRange(date, 2, 0, 23)
+		return remain, succ
+	case 'h', 'I', 'l':
+		p.isHour12 = true
+		remain, p.hour, succ = parseDigitsInRange(date, 2, 1, 12)
+		return remain, succ
+	case 'i':
+		remain, p.minute, succ = parseDigitsInRange(date, 2, 0, 59)
+		return remain, succ
+	case 's', 'S':
+		remain, p.second, succ = parseDigitsInRange(date, 2, 0, 59)
+		return remain, succ
+	case 'f':
+		count, value := parseDigits(date, 6)
+		if count == 0 {
+			return date, false
+		}
+		// The digits are the leading part of the fraction, e.g. "12" means 120000 microseconds.
+		p.microsecond = value * int(math.Pow10(6-count))
+		return date[count:], true
+	case 'p':
+		var i int
+		remain, i, succ = matchPrefixFold(date, []string{"AM", "PM"})
+		p.meridiem = i + 1
+		return remain, succ
+	case 'T':
+		return matchDateWithFormat(p, date, "%H:%i:%s")
+	case 'r':
+		return matchDateWithFormat(p, date, "%I:%i:%S %p")
+	case '%':
+		if date[0] != '%' {
+			return date, false
+		}
+		return date[1:], true
+	}
+	return date, false
+}
+
+// matchDateWithFormat matches a prefix of date with all the tokens of format.
+func matchDateWithFormat(p *dateParts, date string, format string) (string, bool) {
+	for {
+		date = skipWhiteSpace(date)
+		format = skipWhiteSpace(format)
+		token, formatRemain, succ := getFormatToken(format)
+		if !succ || len(date) == 0 && token != "" {
+			return date, false
+		}
+		if token == "" {
+			return date, true
+		}
+		if date, succ = matchDateWithToken(p, date, token); !succ {
+			return date, false
+		}
+		format = formatRemain
+	}
+}
+
+// GetFormatType checks the type(Duration, Date or Datetime) of a format string.
+func GetFormatType(format string) (isDuration, isDate bool) {
+	for {
+		var token string
+		var succ bool
+		token, format, succ = getFormatToken(skipWhiteSpace(format))
+		if !succ {
+			return false, false
+		}
+		if token == "" {
+			return
+		}
+		if token[0] != '%' {
+			continue
+		}
+		switch token[1] {
+		case 'H', 'k', 'h', 'I', 'l', 'i', 's', 'S', 'f', 'p', 'T', 'r':
+			isDuration = true
+		case 'Y', 'y', 'm', 'c', 'M', 'b', 'd', 'e':
+			isDate = true
+		}
+	}
+}
+
+// intervalField is one of the fields of a compound interval unit like DAY_SECOND.
+type intervalField int
+
+const (
+	intervalYear intervalField = iota
+	intervalMonth
+	intervalDay
+	intervalHour
+	intervalMinute
+	intervalSecond
+	intervalMicrosecond
+)
+
+// compoundUnitFields lists the fields of every compound interval unit in the order they are written.
+// See https://dev.mysql.com/doc/refman/5.7/en/expressions.html#temporal-intervals
+var compoundUnitFields = map[string][]intervalField{
+	"YEAR_MONTH":         {intervalYear, intervalMonth},
+	"DAY_HOUR":           {intervalDay, intervalHour},
+	"DAY_MINUTE":         {intervalDay, intervalHour, intervalMinute},
+	"DAY_SECOND":         {intervalDay, intervalHour, intervalMinute, intervalSecond},
+	"DAY_MICROSECOND":    {intervalDay, intervalHour, intervalMinute, intervalSecond, intervalMicrosecond},
+	"HOUR_MINUTE":        {intervalHour, intervalMinute},
+	"HOUR_SECOND":        {intervalHour, intervalMinute, intervalSecond},
+	"HOUR_MICROSECOND":   {intervalHour, intervalMinute, intervalSecond, intervalMicrosecond},
+	"MINUTE_SECOND":      {intervalMinute, intervalSecond},
+	"MINUTE_MICROSECOND": {intervalMinute, intervalSecond, intervalMicrosecond},
+	"SECOND_MICROSECOND": {intervalSecond, intervalMicrosecond},
+}
+
+const nanosPerDay = int64(24 * gotime.Hour)
+
+// ParseDurationValue parses an interval value of unit, it returns the years,
+// months, days and nanoseconds of the interval. The nanoseconds are always
+// less than one day.
+func ParseDurationValue(unit string, format string) (y int64, m int64, d int64, n int64, err error) {
+	unit = strings.ToUpper(unit)
+	fields, ok := compoundUnitFields[unit]
+	if !ok {
+		y, m, d, n, err = extractSingleTimeValue(unit, format)
+	} else {
+		y, m, d, n, err = extractCompoundTimeValue(fields, format)
+	}
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	return y, m, d + n/nanosPerDay, n % nanosPerDay, nil
+}
+
+func extractSingleTimeValue(unit string, format string) (y int64, m int64, d int64, n int64, err error) {
+	fv, err := strconv.ParseFloat(strings.TrimSpace(format), 64)
+	if err != nil {
+		return 0, 0, 0, 0, ErrWrongValue.GenWithStackByArgs(DateTimeStr, format)
+	}
+	iv := int64(math.Round(fv))
+	switch unit {
+	case "MICROSECOND":
+		return 0, 0, 0, iv * int64(gotime.Microsecond), nil
+	case "SECOND":
+		// Only the SECOND unit keeps the fractional part.
+		micro := int64(math.Round(fv * 1e6))
+		return 0, 0, 0, micro * int64(gotime.Microsecond), nil
+	case "MINUTE":
+		return 0, 0, 0, iv * int64(gotime.Minute), nil
+	case "HOUR":
+		return 0, 0, 0, iv * int64(gotime.Hour), nil
+	case "DAY":
+		return 0, 0, iv, 0, nil
+	case "WEEK":
+		return 0, 0, 7 * iv, 0, nil
+	case "MONTH":
+		return 0, iv, 0, 0, nil
+	case "QUARTER":
+		return 0, 3 * iv, 0, 0, nil
+	case "YEAR":
+		return iv, 0, 0, 0, nil
+	}
+	return 0, 0, 0, 0, errors.Errorf("invalid single timeunit - %s", unit)
+}
+
+// extractCompoundTimeValue parses the value of a compound unit like '1 10:20:30' DAY_SECOND.
+// A value with fewer parts than the unit fills the rightmost fields, e.g. '10:20' DAY_SECOND
+// means 10 minutes and 20 seconds.
+func extractCompoundTimeValue(fields []intervalField, format string) (y int64, m int64, d int64, n int64, err error) {
+	format = strings.TrimSpace(format)
+	sign := int64(1)
+	if len(format) > 0 && format[0] == '-' {
+		sign = -1
+		format = format[1:]
+	}
+	parts := strings.FieldsFunc(format, func(c rune) bool { return c < '0' || c > '9' })
+	if len(parts) == 0 || len(parts) > len(fields) {
+		return 0, 0, 0, 0, ErrWrongValue.GenWithStackByArgs(DateTimeStr, format)
+	}
+	fields = fields[len(fields)-len(parts):]
+	for i, part := range parts {
+		if fields[i] == intervalMicrosecond {
+			if len(part) > 6 {
+				return 0, 0, 0, 0, ErrWrongValue.GenWithStackByArgs(DateTimeStr, format)
+			}
+			// The microsecond part is a fraction of the second, e.g. '1.5' SECOND_MICROSECOND is 1.5 seconds.
+			part += strings.Repeat("0", 6-len(part))
+		}
+		v, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return 0, 0, 0, 0, ErrWrongValue.GenWithStackByArgs(DateTimeStr, format)
+		}
+		switch fields[i] {
+		case intervalYear:
+			y = v
+		case intervalMonth:
+			m = v
+		case intervalDay:
+			d = v
+		case intervalHour:
+			n += v * int64(gotime.Hour)
+		case intervalMinute:
+			n += v * int64(gotime.Minute)
+		case intervalSecond:
+			n += v * int64(gotime.Second)
+		case intervalMicrosecond:
+			n += v * int64(gotime.Microsecond)
+		}
+	}
+	return sign * y, sign * m, sign * d, sign * n, nil
+}
+
+// AddDate adds years, months and days to ot. Unlike time.AddDate, a day that
+// overflows the target month is clamped to its last day, so
+// `date_add('2018-01-31', interval 1 month)` is '2018-02-28' like in MySQL.
+func AddDate(year, month, day int64, ot gotime.Time) gotime.Time {
+	nt := ot
+	if year != 0 || month != 0 {
+		nt = ot.AddDate(int(year), int(month), 0)
+		if nt.Day() != ot.Day() {
+			nt = nt.AddDate(0, 0, -nt.Day())
+		}
+	}
+	return nt.AddDate(0, 0, int(day))
+}
+
+// IsClockUnit returns true when unit is interval unit with hour, minute or second.
+func IsClockUnit(unit string) bool {
+	switch strings.ToUpper(unit) {
+	case "MICROSECOND", "SECOND", "MINUTE", "HOUR",
+		"SECOND_MICROSECOND", "MINUTE_MICROSECOND", "MINUTE_SECOND",
+		"HOUR_MICROSECOND", "HOUR_SECOND", "HOUR_MINUTE",
+		"DAY_MICROSECOND", "DAY_SECOND", "DAY_MINUTE", "DAY_HOUR":
+		return true
+	default:
+		return false
+	}
+}
+
+// IsDateFormat returns true when the specified time format could contain only date.
+func IsDateFormat(format string) bool {
+	format = strings.TrimSpace(format)
+	seps := ParseDateFormat(format)
+	length := len(format)
+	switch len(seps) {
+	case 1:
+		if (length == 8) || (length == 6) {
+			return true
+		}
+	case 3:
+		return true
+	}
+	return false
+}
+
+// IsDatetimeUnit returns true when unit is extracted from the date part of a datetime,
+// the other units are extracted from the time part.
+func IsDatetimeUnit(unit string) bool {
+	switch strings.ToUpper(unit) {
+	case "DAY", "WEEK", "MONTH", "QUARTER", "YEAR",
+		"DAY_MICROSECOND", "DAY_SECOND", "DAY_MINUTE", "DAY_HOUR", "YEAR_MONTH":
+		return true
+	default:
+		return false
+	}
+}
+
+// ExtractDatetimeNum extracts time value number from datetime unit and format.
+func ExtractDatetimeNum(t *Time, unit string) (int64, error) {
+	d := int64(t.Day())
+	h, m, s := int64(t.Hour()), int64(t.Minute()), int64(t.Second())
+	switch strings.ToUpper(unit) {
+	case "DAY":
+		return d, nil
+	case "WEEK":
+		return int64(t.coreTime.Week(0)), nil
+	case "MONTH":
+		return int64(t.Month()), nil
+	case "QUARTER":
+		return (int64(t.Month()) + 2) / 3, nil
+	case "YEAR":
+		return int64(t.Year()), nil
+	case "DAY_MICROSECOND":
+		return (d*1000000+h*10000+m*100+s)*1000000 + int64(t.Microsecond()), nil
+	case "DAY_SECOND":
+		return d*1000000 + h*10000 + m*100 + s, nil
+	case "DAY_MINUTE":
+		return d*10000 + h*100 + m, nil
+	case "DAY_HOUR":
+		return d*100 + h, nil
+	case "YEAR_MONTH":
+		return int64(t.Year())*100 + int64(t.Month()), nil
+	default:
+		return 0, errors.Errorf("invalid unit %s", unit)
+	}
+}
+
+// ExtractDurationNum extracts duration value number from duration unit and format.
+func ExtractDurationNum(d *Duration, unit string) (int64, error) {
+	sign := int64(1)
+	if d.Duration < 0 {
+		sign = -1
+	}
+	h, m, s, us := int64(d.Hour()), int64(d.Minute()), int64(d.Second()), int64(d.MicroSecond())
+	switch strings.ToUpper(unit) {
+	case "MICROSECOND":
+		return sign * us, nil
+	case "SECOND":
+		return sign * s, nil
+	case "MINUTE":
+		return sign * m, nil
+	case "HOUR":
+		return sign * h, nil
+	case "SECOND_MICROSECOND":
+		return sign * (s*1000000 + us), nil
+	case "MINUTE_MICROSECOND":
+		return sign * (m*100000000 + s*1000000 + us), nil
+	case "MINUTE_SECOND":
+		return sign * (m*100 + s), nil
+	case "HOUR_MICROSECOND":
+		return sign * (h*10000000000 + m*100000000 + s*1000000 + us), nil
+	case "HOUR_SECOND":
+		return sign * (h*10000 + m*100 + s), nil
+	case "HOUR_MINUTE":
+		return sign * (h*100 + m), nil
+	default:
+		return 0, errors.Errorf("invalid unit %s", unit)
+	}
+}
+
+// DateDiff calculates number of days between two days.
+func DateDiff(startTime, endTime CoreTime) int {
+	return calcDaynr(startTime.Year(), startTime.Month(), startTime.Day()) - calcDaynr(endTime.Year(), endTime.Month(), endTime.Day())
+}
+
+// clockMicroseconds returns the microseconds elapsed since the midnight of t.
+func clockMicroseconds(t CoreTime) int64 {
+	return ((int64(t.Hour())*60+int64(t.Minute()))*60+int64(t.Second()))*1000000 + int64(t.Microsecond())
+}
+
+// TimestampDiff returns t2 - t1 where t1 and t2 are date or datetime expressions.
+// The unit for the result (an integer) is given by the unit argument.
+// The legal values for unit are "YEAR" "QUARTER" "MONTH" "DAY" "HOUR" "SECOND" and so on.
+func TimestampDiff(unit string, t1 Time, t2 Time) int64 {
+	beg, end := t1.coreTime, t2.coreTime
+	sign := int64(1)
+	if compareTime(beg, end) > 0 {
+		beg, end = end, beg
+		sign = -1
+	}
+	micros := int64(DateDiff(end, beg))*(nanosPerDay/1000) + clockMicroseconds(end) - clockMicroseconds(beg)
+
+	// months counts the whole months between beg and end.
+	months := int64(end.Year()-beg.Year())*12 + int64(end.Month()-beg.Month())
+	if end.Day() < beg.Day() || (end.Day() == beg.Day() && clockMicroseconds(end) < clockMicroseconds(beg)) {
+		months--
+	}
+
+	switch strings.ToUpper(unit) {
+	case "YEAR":
+		return sign * (months / 12)
+	case "QUARTER":
+		return sign * (months / 3)
+	case "MONTH":
+		return sign * months
+	case "WEEK":
+		return sign * (micros / (nanosPerDay / 1000) / 7)
+	case "DAY":
+		return sign * (micros / (nanosPerDay / 1000))
+	case "HOUR":
+		return sign * (micros / 3600000000)
+	case "MINUTE":
+		return sign * (micros / 60000000)
+	case "SECOND":
+		return sign * (micros / 1000000)
+	case "MICROSECOND":
+		return sign * micros
+	}
+	return 0
+}
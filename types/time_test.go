@@ -0,0 +1,723 @@
+// Copyright 2015 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/util/testleak"
+)
+
+var _ = Suite(&testTimeSuite{})
+
+type testTimeSuite struct {
+}
+
+func (s *testTimeSuite) TestDateTime(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	table := []struct {
+		Input  string
+		Expect string
+	}{
+		{"2012-12-31 11:30:45", "2012-12-31 11:30:45"},
+		{"0000-00-00 00:00:00", "0000-00-00 00:00:00"},
+		{"0001-01-01 00:00:00", "0001-01-01 00:00:00"},
+		{"00-12-31 11:30:45", "2000-12-31 11:30:45"},
+		{"12-12-31 11:30:45", "2012-12-31 11:30:45"},
+		{"2012-12-31", "2012-12-31 00:00:00"},
+		{"20121231", "2012-12-31 00:00:00"},
+		{"121231", "2012-12-31 00:00:00"},
+		{"2012^12^31 11+30+45", "2012-12-31 11:30:45"},
+		{"2012^12^31T11+30+45", "2012-12-31 11:30:45"},
+		{"2012-2-1 11:30:45", "2012-02-01 11:30:45"},
+		{"12-2-1 11:30:45", "2012-02-01 11:30:45"},
+		{"20121231113045", "2012-12-31 11:30:45"},
+		{"121231113045", "2012-12-31 11:30:45"},
+		{"2012-02-29", "2012-02-29 00:00:00"},
+		{"1000-01-01 00:00:00", "1000-01-01 00:00:00"},
+	}
+
+	for _, test := range table {
+		t, err := ParseDatetime(sc, test.Input)
+		c.Assert(err, IsNil)
+		c.Assert(t.String(), Equals, test.Expect)
+	}
+
+	fspTbl := []struct {
+		Input  string
+		Fsp    int8
+		Expect string
+	}{
+		{"20170118.123", 6, "2017-01-18 12:03:00.000000"},
+		{"121231113045.123345", 6, "2012-12-31 11:30:45.123345"},
+		{"20121231113045.123345", 6, "2012-12-31 11:30:45.123345"},
+		{"121231113045.9999999", 6, "2012-12-31 11:30:46.000000"},
+		{"170105084059.575601", 6, "2017-01-05 08:40:59.575601"},
+		{"2012-12-31 11:30:45.123456", 3, "2012-12-31 11:30:45.123"},
+		{"2012-12-31 11:30:45.123456", 0, "2012-12-31 11:30:45"},
+	}
+
+	for _, test := range fspTbl {
+		t, err := ParseTime(sc, test.Input, mysql.TypeDatetime, test.Fsp)
+		c.Assert(err, IsNil)
+		c.Assert(t.String(), Equals, test.Expect)
+	}
+
+	errTable := []string{
+		"1000-01-01 00:00:70",
+		"1000-13-00 00:00:00",
+		"10000-01-01 00:00:00",
+		"1000-09-31 00:00:00",
+		"1001-02-29 00:00:00",
+		"20170118.999",
+	}
+
+	for _, test := range errTable {
+		_, err := ParseDatetime(sc, test)
+		c.Assert(err, NotNil)
+	}
+}
+
+func (s *testTimeSuite) TestTimestamp(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	table := []struct {
+		Input  string
+		Expect string
+	}{
+		{"2012-12-31 11:30:45", "2012-12-31 11:30:45"},
+		{"1970-01-01 00:00:01", "1970-01-01 00:00:01"},
+		{"2038-01-19 03:14:07", "2038-01-19 03:14:07"},
+	}
+
+	for _, test := range table {
+		t, err := ParseTimestamp(sc, test.Input)
+		c.Assert(err, IsNil)
+		c.Assert(t.String(), Equals, test.Expect)
+	}
+
+	errTable := []string{
+		"1969-12-31 23:59:59",
+		"2038-01-19 03:14:08",
+	}
+
+	for _, test := range errTable {
+		_, err := ParseTimestamp(sc, test)
+		c.Assert(err, NotNil)
+	}
+}
+
+func (s *testTimeSuite) TestDate(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	table := []struct {
+		Input  string
+		Expect string
+	}{
+		{"2012-12-31", "2012-12-31"},
+		{"00-12-31", "2000-12-31"},
+		{"20121231", "2012-12-31"},
+		{"121231", "2012-12-31"},
+		{"2015-06-01 12:12:12", "2015-06-01"},
+		{"0001-01-01 00:00:00", "0001-01-01"},
+		{"0000-00-00", "0000-00-00"},
+	}
+
+	for _, test := range table {
+		t, err := ParseDate(sc, test.Input)
+		c.Assert(err, IsNil)
+		c.Assert(t.String(), Equals, test.Expect)
+	}
+
+	errTable := []string{
+		"0121231",
+		"2012-13-01",
+	}
+
+	for _, test := range errTable {
+		_, err := ParseDate(sc, test)
+		c.Assert(err, NotNil)
+	}
+}
+
+func (s *testTimeSuite) TestTime(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	table := []struct {
+		Input  string
+		Expect string
+	}{
+		{"10:11:12", "10:11:12"},
+		{"101112", "10:11:12"},
+		{"020005", "02:00:05"},
+		{"112", "00:01:12"},
+		{"10:11", "10:11:00"},
+		{"101112.123456", "10:11:12"},
+		{"1112", "00:11:12"},
+		{"12", "00:00:12"},
+		{"1 12", "36:00:00"},
+		{"1 10:11:12", "34:11:12"},
+		{"-1 10:11:12", "-34:11:12"},
+		{"-10:11:12", "-10:11:12"},
+		{"838:59:59", "838:59:59"},
+	}
+
+	for _, test := range table {
+		t, err := ParseDuration(sc, test.Input, MinFsp)
+		c.Assert(err, IsNil)
+		c.Assert(t.String(), Equals, test.Expect)
+	}
+
+	fspTbl := []struct {
+		Input  string
+		Fsp    int8
+		Expect string
+	}{
+		{"10:11:12.123456", 6, "10:11:12.123456"},
+		{"10:11:12.123456", 3, "10:11:12.123"},
+		{"10:11:12.1237", 3, "10:11:12.124"},
+		{"10:11:12.4", 0, "10:11:12"},
+		{"10:11:12.5", 0, "10:11:13"},
+	}
+
+	for _, test := range fspTbl {
+		t, err := ParseDuration(sc, test.Input, test.Fsp)
+		c.Assert(err, IsNil)
+		c.Assert(t.String(), Equals, test.Expect)
+	}
+
+	errTable := []string{
+		"2011-11-11",
+		"232 10",
+		"-232 10",
+	}
+
+	for _, test := range errTable {
+		_, err := ParseDuration(sc, test, MinFsp)
+		c.Assert(err, NotNil)
+	}
+
+	t, err := ParseDuration(sc, "839:00:00", MinFsp)
+	c.Assert(err, NotNil)
+	c.Assert(t.String(), Equals, "838:59:59")
+}
+
+func (s *testTimeSuite) TestParseTimeFromNum(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	table := []struct {
+		Input                int64
+		ExpectDateTimeError  bool
+		ExpectDateTimeValue  string
+		ExpectTimeStampError bool
+		ExpectTimeStampValue string
+		ExpectDateError      bool
+		ExpectDateValue      string
+	}{
+		{20101010111111, false, "2010-10-10 11:11:11", false, "2010-10-10 11:11:11", false, "2010-10-10"},
+		{2010101011111, false, "0201-01-01 01:11:11", true, ZeroDatetimeStr, false, "0201-01-01"},
+		{201010101111, false, "2020-10-10 10:11:11", false, "2020-10-10 10:11:11", false, "2020-10-10"},
+		{20101010, false, "2010-10-10 00:00:00", false, "2010-10-10 00:00:00", false, "2010-10-10"},
+		{101010, false, "2010-10-10 00:00:00", false, "2010-10-10 00:00:00", false, "2010-10-10"},
+		{0, false, ZeroDatetimeStr, false, ZeroDatetimeStr, false, ZeroDateStr},
+		{-1, true, ZeroDatetimeStr, true, ZeroDatetimeStr, true, ZeroDateStr},
+	}
+
+	for _, test := range table {
+		// Test ParseDatetimeFromNum.
+		t, err := ParseDatetimeFromNum(sc, test.Input)
+		if test.ExpectDateTimeError {
+			c.Assert(err, NotNil)
+		} else {
+			c.Assert(err, IsNil)
+			c.Assert(t.Type(), Equals, mysql.TypeDatetime)
+		}
+		c.Assert(t.String(), Equals, test.ExpectDateTimeValue)
+
+		// Test ParseTimestampFromNum.
+		t, err = ParseTimestampFromNum(sc, test.Input)
+		if test.ExpectTimeStampError {
+			c.Assert(err, NotNil)
+		} else {
+			c.Assert(err, IsNil)
+			c.Assert(t.Type(), Equals, mysql.TypeTimestamp)
+		}
+		c.Assert(t.String(), Equals, test.ExpectTimeStampValue)
+
+		// Test ParseDateFromNum.
+		t, err = ParseDateFromNum(sc, test.Input)
+		if test.ExpectDateError {
+			c.Assert(err, NotNil)
+		} else {
+			c.Assert(err, IsNil)
+			c.Assert(t.Type(), Equals, mysql.TypeDate)
+		}
+		c.Assert(t.String(), Equals, test.ExpectDateValue)
+	}
+}
+
+func (s *testTimeSuite) TestCompare(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	table := []struct {
+		Arg1 string
+		Arg2 string
+		Ret  int
+	}{
+		{"2011-10-10 11:11:11", "2011-10-10 11:11:11", 0},
+		{"2011-10-10 11:11:11.123456", "2011-10-10 11:11:11.1", 1},
+		{"2011-10-10 11:11:11", "2011-10-10 11:11:11.123", -1},
+		{"0000-00-00 00:00:00", "2011-10-10 11:11:11", -1},
+		{"0000-00-00 00:00:00", "0000-00-00 00:00:00", 0},
+	}
+
+	for _, test := range table {
+		v1, err := ParseDatetime(sc, test.Arg1)
+		c.Assert(err, IsNil)
+
+		ret, err := v1.CompareString(sc, test.Arg2)
+		c.Assert(err, IsNil)
+		c.Assert(ret, Equals, test.Ret)
+	}
+
+	v1, err := ParseDatetime(sc, "2011-10-10 11:11:11")
+	c.Assert(err, IsNil)
+	res, err := v1.CompareString(sc, "Test should error")
+	c.Assert(err, NotNil)
+	c.Assert(res, Equals, 0)
+
+	for _, test := range table {
+		v1, err := ParseDuration(sc, test.Arg1[11:], MaxFsp)
+		c.Assert(err, IsNil)
+
+		ret, err := v1.CompareString(sc, test.Arg2[11:])
+		c.Assert(err, IsNil)
+		c.Assert(ret, Equals, test.Ret)
+	}
+}
+
+func (s *testTimeSuite) TestRoundFrac(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	tbl := []struct {
+		Input  string
+		Fsp    int8
+		Except string
+	}{
+		{"2012-12-31 11:30:45.123456", 4, "2012-12-31 11:30:45.1235"},
+		{"2012-12-31 11:30:45.123456", 6, "2012-12-31 11:30:45.123456"},
+		{"2012-12-31 11:30:45.123456", 0, "2012-12-31 11:30:45"},
+		{"2012-12-31 11:30:45.123456", 1, "2012-12-31 11:30:45.1"},
+		{"2012-12-31 11:30:45.999999", 4, "2012-12-31 11:30:46.0000"},
+		{"2012-12-31 11:30:45.999999", 0, "2012-12-31 11:30:46"},
+		{"2012-00-00 11:30:45.999999", 3, "2012-00-00 11:30:46.000"},
+	}
+
+	for _, t := range tbl {
+		v, err := ParseTime(sc, t.Input, mysql.TypeDatetime, MaxFsp)
+		c.Assert(err, IsNil)
+		nv, err := v.RoundFrac(sc, t.Fsp)
+		c.Assert(err, IsNil)
+		c.Assert(nv.String(), Equals, t.Except)
+	}
+
+	tbl = []struct {
+		Input  string
+		Fsp    int8
+		Except string
+	}{
+		{"11:30:45.123456", 4, "11:30:45.1235"},
+		{"11:30:45.123456", 6, "11:30:45.123456"},
+		{"11:30:45.123456", 0, "11:30:45"},
+		{"1 11:30:45.123456", 1, "35:30:45.1"},
+		{"1 11:30:45.999999", 4, "35:30:46.0000"},
+		{"-1 11:30:45.999999", 0, "-35:30:46"},
+	}
+
+	for _, t := range tbl {
+		v, err := ParseDuration(sc, t.Input, MaxFsp)
+		c.Assert(err, IsNil)
+		nv, err := v.RoundFrac(t.Fsp)
+		c.Assert(err, IsNil)
+		c.Assert(nv.String(), Equals, t.Except)
+	}
+}
+
+func (s *testTimeSuite) TestConvert(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	tbl := []struct {
+		Input  string
+		Fsp    int8
+		Except string
+	}{
+		{"2012-12-31 11:30:45.123456", 4, "11:30:45.1235"},
+		{"2012-12-31 11:30:45.123456", 6, "11:30:45.123456"},
+		{"2012-12-31 11:30:45.123456", 0, "11:30:45"},
+		{"2012-12-31 11:30:45.999999", 0, "11:30:46"},
+		{"2017-01-05 08:40:59.575601", 0, "08:41:00"},
+		{"2017-01-05 23:59:59.575601", 0, "00:00:00"},
+		{"0000-00-00 00:00:00", 6, "00:00:00"},
+	}
+
+	for _, t := range tbl {
+		v, err := ParseTime(sc, t.Input, mysql.TypeDatetime, t.Fsp)
+		c.Assert(err, IsNil)
+		nv, err := v.ConvertToDuration()
+		c.Assert(err, IsNil)
+		c.Assert(nv.String(), Equals, t.Except)
+	}
+
+	tblDuration := []struct {
+		Input string
+		Fsp   int8
+	}{
+		{"11:30:45.123456", 4},
+		{"11:30:45.123456", 6},
+		{"11:30:45.123456", 0},
+		{"1 11:30:45.999999", 0},
+	}
+	// test different time zone.
+	sc.TimeZone = time.UTC
+	for _, t := range tblDuration {
+		v, err := ParseDuration(sc, t.Input, t.Fsp)
+		c.Assert(err, IsNil)
+		year, month, day := time.Now().In(sc.TimeZone).Date()
+		n := time.Date(year, month, day, 0, 0, 0, 0, sc.TimeZone)
+		t, err := v.ConvertToTime(sc, mysql.TypeDatetime)
+		c.Assert(err, IsNil)
+		// TODO: Consider time_zone variable.
+		t1, _ := t.GoTime(sc.TimeZone)
+		c.Assert(t1.Sub(n), Equals, v.Duration)
+	}
+}
+
+func (s *testTimeSuite) TestTimeToNumber(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	tblDateTime := []struct {
+		Input  string
+		Fsp    int8
+		Expect string
+	}{
+		{"12-12-31 11:30:45", 0, "20121231113045"},
+		{"12-12-31 11:30:45", 6, "20121231113045.000000"},
+		{"12-12-31 11:30:45.123", 6, "20121231113045.123000"},
+		{"12-12-31 11:30:45.123345", 0, "20121231113045"},
+		{"12-12-31 11:30:45.123345", 3, "20121231113045.123"},
+		{"12-12-31 11:30:45.123345", 5, "20121231113045.12335"},
+		{"12-12-31 11:30:45.123345", 6, "20121231113045.123345"},
+		{"12-12-31 11:30:45.1233457", 6, "20121231113045.123346"},
+		{"12-12-31 11:30:45.823345", 0, "20121231113046"},
+	}
+
+	for _, test := range tblDateTime {
+		t, err := ParseTime(sc, test.Input, mysql.TypeDatetime, test.Fsp)
+		c.Assert(err, IsNil)
+		c.Assert(t.ToNumber().String(), Equals, test.Expect)
+	}
+
+	// Fix issue #1046
+	tblDate := []struct {
+		Input  string
+		Fsp    int8
+		Expect string
+	}{
+		{"12-12-31 11:30:45", 0, "20121231"},
+		{"12-12-31 11:30:45", 6, "20121231"},
+		{"12-12-31 11:30:45.123", 6, "20121231"},
+		{"12-12-31 11:30:45.123345", 0, "20121231"},
+		{"12-12-31 11:30:45.823345", 0, "20121231"},
+	}
+
+	for _, test := range tblDate {
+		t, err := ParseTime(sc, test.Input, mysql.TypeDate, 0)
+		c.Assert(err, IsNil)
+		c.Assert(t.ToNumber().String(), Equals, test.Expect)
+	}
+
+	tblDuration := []struct {
+		Input  string
+		Fsp    int8
+		Expect string
+	}{
+		{"11:30:45", 0, "113045"},
+		{"11:30:45", 6, "113045.000000"},
+		{"11:30:45.123", 6, "113045.123000"},
+		{"11:30:45.123345", 0, "113045"},
+		{"11:30:45.123345", 3, "113045.123"},
+		{"11:30:45.123345", 5, "113045.12335"},
+		{"11:30:45.123345", 6, "113045.123345"},
+		{"11:30:45.1233456", 6, "113045.123346"},
+		{"11:30:45.9233456", 0, "113046"},
+		{"-11:30:45.9233456", 0, "-113046"},
+	}
+
+	for _, test := range tblDuration {
+		t, err := ParseDuration(sc, test.Input, test.Fsp)
+		c.Assert(err, IsNil)
+		// now we can only changetypetest.Fsp, then test.Expect
+		c.Assert(t.ToNumber().String(), Equals, test.Expect)
+	}
+}
+
+func (s *testTimeSuite) TestPackedUint(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	tbl := []string{
+		"0000-00-00 00:00:00.000000",
+		"1000-01-01 00:00:00.000000",
+		"9999-12-31 23:59:59.000000",
+		"1970-01-01 00:00:00.000000",
+		"2016-06-01 11:30:45.123456",
+		"2012-12-31 11:30:45.123456",
+	}
+
+	for _, test := range tbl {
+		t, err := ParseTime(sc, test, mysql.TypeDatetime, MaxFsp)
+		c.Assert(err, IsNil)
+
+		packed, err := t.ToPackedUint()
+		c.Assert(err, IsNil)
+
+		var t1 Time
+		t1.SetType(mysql.TypeDatetime)
+		t1.SetFsp(MaxFsp)
+		err = t1.FromPackedUint(packed)
+		c.Assert(err, IsNil)
+		c.Assert(t.Compare(t1), Equals, 0)
+		c.Assert(t1.String(), Equals, test)
+	}
+}
+
+func (s *testTimeSuite) TestConvertTimeZone(c *C) {
+	defer testleak.AfterTest(c)()
+	loc, _ := time.LoadLocation("Asia/Shanghai")
+	tests := []struct {
+		input  Time
+		from   *time.Location
+		to     *time.Location
+		expect Time
+	}{
+		{NewTime(FromDate(2017, 1, 1, 0, 0, 0, 0), mysql.TypeTimestamp, 0), time.UTC, loc, NewTime(FromDate(2017, 1, 1, 8, 0, 0, 0), mysql.TypeTimestamp, 0)},
+		{NewTime(FromDate(2017, 1, 1, 8, 0, 0, 0), mysql.TypeTimestamp, 0), loc, time.UTC, NewTime(FromDate(2017, 1, 1, 0, 0, 0, 0), mysql.TypeTimestamp, 0)},
+		{NewTime(FromDate(0, 0, 0, 0, 0, 0, 0), mysql.TypeTimestamp, 0), loc, time.UTC, NewTime(FromDate(0, 0, 0, 0, 0, 0, 0), mysql.TypeTimestamp, 0)},
+	}
+
+	for _, test := range tests {
+		t := test.input
+		err := t.ConvertTimeZone(test.from, test.to)
+		c.Assert(err, IsNil)
+		c.Assert(t.Compare(test.expect), Equals, 0)
+	}
+}
+
+func (s *testTimeSuite) TestCheckTimestamp(c *C) {
+	defer testleak.AfterTest(c)()
+
+	shanghaiTz, _ := time.LoadLocation("Asia/Shanghai")
+	tests := []struct {
+		tz             *time.Location
+		input          CoreTime
+		expectRetError bool
+	}{{
+		tz:             shanghaiTz,
+		input:          FromDate(2038, 1, 19, 11, 14, 7, 0),
+		expectRetError: false,
+	}, {
+		tz:             shanghaiTz,
+		input:          FromDate(1970, 1, 1, 8, 1, 1, 0),
+		expectRetError: false,
+	}, {
+		tz:             shanghaiTz,
+		input:          FromDate(2038, 1, 19, 12, 14, 7, 0),
+		expectRetError: true,
+	}, {
+		tz:             shanghaiTz,
+		input:          FromDate(1970, 1, 1, 7, 1, 1, 0),
+		expectRetError: true,
+	}, {
+		tz:             time.UTC,
+		input:          FromDate(2038, 1, 19, 3, 14, 7, 0),
+		expectRetError: false,
+	}, {
+		tz:             time.UTC,
+		input:          FromDate(1970, 1, 1, 0, 0, 1, 0),
+		expectRetError: false,
+	}, {
+		tz:             time.UTC,
+		input:          FromDate(2038, 1, 19, 4, 14, 7, 0),
+		expectRetError: true,
+	}, {
+		tz:             time.UTC,
+		input:          FromDate(1969, 1, 1, 0, 0, 0, 0),
+		expectRetError: true,
+	},
+	}
+
+	for _, t := range tests {
+		validTimestamp := checkTimestampType(&stmtctx.StatementContext{TimeZone: t.tz}, t.input)
+		if t.expectRetError {
+			c.Assert(validTimestamp, NotNil, Commentf("For %s", t.input))
+		} else {
+			c.Assert(validTimestamp, IsNil, Commentf("For %s", t.input))
+		}
+	}
+}
+
+func (s *testTimeSuite) TestDateFormat(c *C) {
+	defer testleak.AfterTest(c)()
+	t := NewTime(FromDate(2010, 1, 7, 23, 12, 34, 123400), mysql.TypeDatetime, 6)
+	str, err := t.DateFormat("%b %M %m %c %D %d %e %j %k %h %i %p %r %T %s %f %U %u %V %v %a %W %w %X %x %Y %y %%")
+	c.Assert(err, IsNil)
+	c.Assert(str, Equals, "Jan January 01 1 7th 07 7 007 23 11 12 PM 11:12:34 PM 23:12:34 34 123400 01 01 01 01 Thu Thursday 4 2010 2010 2010 10 %")
+
+	t = NewTime(FromDate(2020, 1, 1, 0, 0, 0, 0), mysql.TypeDatetime, 0)
+	str, err = t.DateFormat("%U %u %V %v %X %x %r %l")
+	c.Assert(err, IsNil)
+	c.Assert(str, Equals, "00 01 52 01 2019 2020 12:00:00 AM 12")
+
+	t = NewTime(ZeroCoreTime, mysql.TypeDatetime, 0)
+	_, err = t.DateFormat("%M")
+	c.Assert(err, NotNil)
+}
+
+func (s *testTimeSuite) TestStrToDate(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC, IgnoreZeroInDate: true}
+	tests := []struct {
+		input  string
+		format string
+		expect string
+	}{
+		{"01,05,2013", "%d,%m,%Y", "2013-05-01 00:00:00"},
+		{"May 1, 2013", "%M %d,%Y", "2013-05-01 00:00:00"},
+		{"15-jan-99", "%d-%b-%y", "1999-01-15 00:00:00"},
+		{"a09:30:17", "a%h:%i:%s", "0000-00-00 09:30:17"},
+		{"09:30:17a", "%h:%i:%s", "0000-00-00 09:30:17"},
+		{"2013-05-01 10:20:30 pm", "%Y-%m-%d %h:%i:%s %p", "2013-05-01 22:20:30"},
+		{"12:00:00 AM 2013/05/01", "%r %Y/%m/%d", "2013-05-01 00:00:00"},
+		{"10.5 2000-01-02", "%s.%f %Y-%m-%d", "2000-01-02 00:00:10"},
+	}
+	for _, test := range tests {
+		var t Time
+		c.Assert(t.StrToDate(sc, test.input, test.format), IsTrue, Commentf("%v", test))
+		c.Assert(t.String(), Equals, test.expect, Commentf("%v", test))
+	}
+
+	errTests := []struct {
+		input  string
+		format string
+	}{
+		{"a09:30:17", "%h:%i:%s"},
+		{"2013-13-01", "%Y-%m-%d"},
+		{"10:20 pm", "%H:%i %p"},
+		{"2013-02-30", "%Y-%m-%d"},
+	}
+	for _, test := range errTests {
+		var t Time
+		c.Assert(t.StrToDate(sc, test.input, test.format), IsFalse, Commentf("%v", test))
+	}
+
+	isDuration, isDate := GetFormatType("%Y-%m-%d")
+	c.Assert(isDuration, IsFalse)
+	c.Assert(isDate, IsTrue)
+	isDuration, isDate = GetFormatType("%H:%i:%s")
+	c.Assert(isDuration, IsTrue)
+	c.Assert(isDate, IsFalse)
+}
+
+func (s *testTimeSuite) TestParseDurationValue(c *C) {
+	defer testleak.AfterTest(c)()
+	tests := []struct {
+		unit   string
+		format string
+		y      int64
+		m      int64
+		d      int64
+		n      time.Duration
+	}{
+		{"DAY", "3", 0, 0, 3, 0},
+		{"SECOND", "1.5", 0, 0, 0, 1500 * time.Millisecond},
+		{"HOUR", "25", 0, 0, 1, time.Hour},
+		{"day_second", "1 10:20:30", 0, 0, 1, 10*time.Hour + 20*time.Minute + 30*time.Second},
+		{"DAY_SECOND", "10:20", 0, 0, 0, 10*time.Minute + 20*time.Second},
+		{"YEAR_MONTH", "-1-2", -1, -2, 0, 0},
+		{"SECOND_MICROSECOND", "1.5", 0, 0, 0, 1500 * time.Millisecond},
+		{"QUARTER", "2", 0, 6, 0, 0},
+	}
+	for _, test := range tests {
+		y, m, d, n, err := ParseDurationValue(test.unit, test.format)
+		c.Assert(err, IsNil)
+		c.Assert([]int64{y, m, d, n}, DeepEquals, []int64{test.y, test.m, test.d, int64(test.n)}, Commentf("%v", test))
+	}
+	_, _, _, _, err := ParseDurationValue("DAY_SECOND", "1 2:3:4:5")
+	c.Assert(err, NotNil)
+	_, _, _, _, err = ParseDurationValue("DAY", "abc")
+	c.Assert(err, NotNil)
+
+	base := time.Date(2018, 1, 31, 0, 0, 0, 0, time.UTC)
+	c.Assert(AddDate(0, 1, 0, base).Format(DateFormat), Equals, "2018-02-28")
+	c.Assert(AddDate(0, -2, 0, base).Format(DateFormat), Equals, "2017-11-30")
+	c.Assert(AddDate(2, 1, 0, base).Format(DateFormat), Equals, "2020-02-29")
+	c.Assert(AddDate(0, 0, 1, base).Format(DateFormat), Equals, "2018-02-01")
+}
+
+func (s *testTimeSuite) TestTimestampDiff(c *C) {
+	defer testleak.AfterTest(c)()
+	t1 := NewTime(FromDate(2003, 2, 1, 0, 0, 0, 0), mysql.TypeDatetime, 0)
+	t2 := NewTime(FromDate(2003, 5, 1, 12, 5, 55, 0), mysql.TypeDatetime, 0)
+	tests := []struct {
+		unit   string
+		expect int64
+	}{
+		{"MONTH", 3},
+		{"YEAR", 0},
+		{"QUARTER", 1},
+		{"WEEK", 12},
+		{"DAY", 89},
+		{"HOUR", 2148},
+		{"MINUTE", 128885},
+		{"SECOND", 7733155},
+	}
+	for _, test := range tests {
+		c.Assert(TimestampDiff(test.unit, t1, t2), Equals, test.expect, Commentf("%v", test))
+		c.Assert(TimestampDiff(test.unit, t2, t1), Equals, -test.expect, Commentf("%v", test))
+	}
+	c.Assert(TimestampDiff("MONTH", NewTime(FromDate(2003, 1, 31, 0, 0, 0, 0), mysql.TypeDate, 0), NewTime(FromDate(2003, 2, 28, 0, 0, 0, 0), mysql.TypeDate, 0)), Equals, int64(0))
+	c.Assert(DateDiff(FromDate(2007, 12, 31, 23, 59, 59, 0), FromDate(2007, 12, 30, 0, 0, 0, 0)), Equals, 1)
+	c.Assert(DateDiff(FromDate(2010, 11, 30, 23, 59, 59, 0), FromDate(2010, 12, 31, 0, 0, 0, 0)), Equals, -31)
+}
+
+func (s *testTimeSuite) TestExtractNum(c *C) {
+	defer testleak.AfterTest(c)()
+	t := NewTime(FromDate(2019, 7, 2, 1, 2, 3, 0), mysql.TypeDatetime, 0)
+	for unit, expect := range map[string]int64{"YEAR_MONTH": 201907, "DAY_MINUTE": 20102, "WEEK": 26, "QUARTER": 3} {
+		v, err := ExtractDatetimeNum(&t, unit)
+		c.Assert(err, IsNil)
+		c.Assert(v, Equals, expect, Commentf("%s", unit))
+	}
+	d := Duration{Duration: -(3*time.Hour + 2*time.Minute + 1500*time.Millisecond), Fsp: 6}
+	for unit, expect := range map[string]int64{"HOUR": -3, "HOUR_SECOND": -30201, "SECOND_MICROSECOND": -1500000} {
+		v, err := ExtractDurationNum(&d, unit)
+		c.Assert(err, IsNil)
+		c.Assert(v, Equals, expect, Commentf("%s", unit))
+	}
+	_, err := ExtractDatetimeNum(&t, "HOUR")
+	c.Assert(err, NotNil)
+}
@@ -80,7 +80,7 @@ func IsJobRollbackable(job *model.Job) bool {
 			job.SchemaState == model.StateDeleteReorganization {
 			return false
 		}
-	case model.ActionDropSchema, model.ActionDropTable:
+	case model.ActionDropSchema, model.ActionDropTable, model.ActionDropView:
 		// To simplify the rollback logic, cannot be canceled in the following states.
 		if job.SchemaState == model.StateWriteOnly ||
 			job.SchemaState == model.StateDeleteOnly {
@@ -107,6 +107,17 @@ func (s *testChunkSuite) TestMutRow(c *check.C) {
 	row = mutRow.ToRow()
 	c.Assert(row.IsNull(0), check.IsTrue)
 	c.Assert(row.IsNull(1), check.IsFalse)
+
+	mutRow = MutRowFromValues(types.Enum{Name: "a", Value: 1}, types.Set{Name: "b,c", Value: 6}, types.NewBinaryLiteralFromUint(3, 1))
+	row = mutRow.ToRow()
+	c.Assert(row.GetEnum(0), check.Equals, types.Enum{Name: "a", Value: 1})
+	c.Assert(row.GetSet(1), check.Equals, types.Set{Name: "b,c", Value: 6})
+	c.Assert(row.GetBytes(2), check.DeepEquals, []byte{3})
+	mutRow.SetDatums(types.NewMysqlEnumDatum(types.Enum{Name: "bb", Value: 2}), types.NewDatum(types.Set{Name: "", Value: 0}), types.NewMysqlBitDatum(types.NewBinaryLiteralFromUint(258, 2)))
+	row = mutRow.ToRow()
+	c.Assert(row.GetEnum(0), check.Equals, types.Enum{Name: "bb", Value: 2})
+	c.Assert(row.GetSet(1), check.Equals, types.Set{Name: "", Value: 0})
+	c.Assert(row.GetBytes(2), check.DeepEquals, []byte{1, 2})
 }
 
 func BenchmarkMutRowSetDatums(b *testing.B) {
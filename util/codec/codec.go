@@ -24,8 +24,10 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 )
 
@@ -54,7 +56,7 @@ func preRealloc(b []byte, vals []types.Datum, comparable bool) []byte {
 	var size int
 	for i := range vals {
 		switch vals[i].Kind() {
-		case types.KindInt64, types.KindUint64:
+		case types.KindInt64, types.KindUint64, types.KindMysqlEnum, types.KindMysqlSet, types.KindMysqlBit, types.KindBinaryLiteral:
 			size += sizeInt(comparable)
 		case types.KindString, types.KindBytes:
 			size += sizeBytes(vals[i].GetBytes(), comparable)
@@ -84,6 +86,41 @@ func encode(sc *stmtctx.StatementContext, b []byte, vals []types.Datum, comparab
 			b = EncodeFloat(b, vals[i].GetFloat64())
 		case types.KindString, types.KindBytes:
 			b = encodeBytes(b, vals[i].GetBytes(), comparable)
+		case types.KindMysqlDecimal:
+			b = append(b, decimalFlag)
+			b, err = EncodeDecimal(b, vals[i].GetMysqlDecimal(), vals[i].Length(), vals[i].Frac())
+			if terror.ErrorEqual(err, types.ErrTruncated) {
+				err = sc.HandleTruncate(err)
+			} else if terror.ErrorEqual(err, types.ErrOverflow) {
+				err = sc.HandleOverflow(err, err)
+			}
+			if err != nil {
+				return b, errors.Trace(err)
+			}
+		case types.KindMysqlTime:
+			b = append(b, uintFlag)
+			b, err = EncodeMySQLTime(sc, vals[i].GetMysqlTime(), mysql.TypeUnspecified, b)
+			if err != nil {
+				return b, err
+			}
+		case types.KindMysqlDuration:
+			// duration may have negative value, so we cannot use String to encode directly.
+			b = append(b, durationFlag)
+			b = EncodeInt(b, int64(vals[i].GetMysqlDuration().Duration))
+		case types.KindMysqlEnum:
+			b = encodeUnsignedInt(b, uint64(vals[i].GetMysqlEnum().ToNumber()), comparable)
+		case types.KindMysqlSet:
+			b = encodeUnsignedInt(b, uint64(vals[i].GetMysqlSet().ToNumber()), comparable)
+		case types.KindMysqlBit, types.KindBinaryLiteral:
+			// We don't need to handle errors here since the literal is ensured to be able to store in uint64 in convertToMysqlBit.
+			var val uint64
+			val, err = vals[i].GetBinaryLiteral().ToInt(sc)
+			terror.Log(errors.Trace(err))
+			b = encodeUnsignedInt(b, val, comparable)
+		case types.KindMysqlJSON:
+			j := vals[i].GetMysqlJSON()
+			b = append(b, jsonFlag, j.TypeCode)
+			b = append(b, j.Value...)
 		case types.KindNull:
 			b = append(b, NilFlag)
 		case types.KindMinNotNull:
@@ -98,6 +135,28 @@ func encode(sc *stmtctx.StatementContext, b []byte, vals []types.Datum, comparab
 	return b, errors.Trace(err)
 }
 
+// EncodeMySQLTime encodes datum of `KindMysqlTime` to []byte.
+func EncodeMySQLTime(sc *stmtctx.StatementContext, t types.Time, tp byte, b []byte) (_ []byte, err error) {
+	// Encoding timestamp need to consider timezone. If it's not in UTC, transform to UTC first.
+	// This is compatible with `PBToExpr > convertTime`, and coprocessor assumes the passed timestamp is in UTC as well.
+	if tp == mysql.TypeUnspecified {
+		tp = t.Type()
+	}
+	if tp == mysql.TypeTimestamp && sc != nil && sc.TimeZone != nil && sc.TimeZone != time.UTC {
+		err = t.ConvertTimeZone(sc.TimeZone, time.UTC)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var v uint64
+	v, err = t.ToPackedUint()
+	if err != nil {
+		return nil, err
+	}
+	b = EncodeUint(b, v)
+	return b, nil
+}
+
 // EstimateValueSize uses to estimate the value  size of the encoded values.
 func EstimateValueSize(sc *stmtctx.StatementContext, val types.Datum) (int, error) {
 	l := 0
@@ -110,6 +169,20 @@ func EstimateValueSize(sc *stmtctx.StatementContext, val types.Datum) (int, erro
 		l = 9
 	case types.KindString, types.KindBytes:
 		l = valueSizeOfBytes(val.GetBytes())
+	case types.KindMysqlDecimal:
+		l = valueSizeOfDecimal(val.GetMysqlDecimal(), val.Length(), val.Frac()) + 1
+	case types.KindMysqlTime, types.KindMysqlDuration:
+		l = 9
+	case types.KindMysqlEnum:
+		l = valueSizeOfUnsignedInt(uint64(val.GetMysqlEnum().ToNumber()))
+	case types.KindMysqlSet:
+		l = valueSizeOfUnsignedInt(uint64(val.GetMysqlSet().ToNumber()))
+	case types.KindMysqlBit, types.KindBinaryLiteral:
+		val, err := val.GetBinaryLiteral().ToInt(sc)
+		terror.Log(errors.Trace(err))
+		l = valueSizeOfUnsignedInt(val)
+	case types.KindMysqlJSON:
+		l = 2 + len(val.GetMysqlJSON().Value)
 	case types.KindNull, types.KindMinNotNull, types.KindMaxValue:
 		l = 1
 	default:
@@ -233,6 +306,44 @@ func encodeHashChunkRowIdx(sc *stmtctx.StatementContext, row chunk.Row, tp *type
 	case mysql.TypeVarchar, mysql.TypeVarString, mysql.TypeString, mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
 		flag = compactBytesFlag
 		b = row.GetBytes(idx)
+	case mysql.TypeNewDecimal:
+		flag = decimalFlag
+		// If hash is true, we only consider the original value of this decimal and ignore it's precision.
+		dec := row.GetMyDecimal(idx)
+		b, err = dec.ToHashKey()
+		if err != nil {
+			return
+		}
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		flag = uintFlag
+		t := row.GetTime(idx)
+		var v uint64
+		v, err = t.ToPackedUint()
+		if err != nil {
+			return
+		}
+		b = (*[unsafe.Sizeof(v)]byte)(unsafe.Pointer(&v))[:]
+	case mysql.TypeDuration:
+		flag = durationFlag
+		// duration may have negative value, so we cannot use String to encode directly.
+		b = row.GetRaw(idx)
+	case mysql.TypeEnum:
+		flag = uvarintFlag
+		v := uint64(row.GetEnum(idx).ToNumber())
+		b = (*[sizeUint64]byte)(unsafe.Pointer(&v))[:]
+	case mysql.TypeSet:
+		flag = uvarintFlag
+		v := uint64(row.GetSet(idx).ToNumber())
+		b = (*[sizeUint64]byte)(unsafe.Pointer(&v))[:]
+	case mysql.TypeBit:
+		// We don't need to handle errors here since the literal is ensured to be able to store in uint64 in convertToMysqlBit.
+		flag = uvarintFlag
+		v, err1 := types.BinaryLiteral(row.GetBytes(idx)).ToInt(sc)
+		terror.Log(errors.Trace(err1))
+		b = (*[unsafe.Sizeof(v)]byte)(unsafe.Pointer(&v))[:]
+	case mysql.TypeJSON:
+		flag = jsonFlag
+		b = row.GetBytes(idx)
 	default:
 		return 0, nil, errors.Errorf("unsupport column type for encode %d", tp.Tp)
 	}
@@ -326,6 +437,148 @@ func HashChunkSelected(sc *stmtctx.StatementContext, h []hash.Hash64, chk *chunk
 				b = column.GetBytes(i)
 			}
 
+			// As the golang doc described, `Hash.Write` never returns an error.
+			// See https://golang.org/pkg/hash/#Hash
+			_, _ = h[i].Write(buf)
+			_, _ = h[i].Write(b)
+		}
+	case mysql.TypeNewDecimal:
+		ds := column.Decimals()
+		for i := range ds {
+			if sel != nil && !sel[i] {
+				continue
+			}
+			if column.IsNull(i) {
+				buf[0], b = NilFlag, nil
+				isNull[i] = true
+			} else {
+				buf[0] = decimalFlag
+				b, err = ds[i].ToHashKey()
+				if err != nil {
+					return
+				}
+			}
+
+			// As the golang doc described, `Hash.Write` never returns an error.
+			// See https://golang.org/pkg/hash/#Hash
+			_, _ = h[i].Write(buf)
+			_, _ = h[i].Write(b)
+		}
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		ts := column.Times()
+		for i, t := range ts {
+			if sel != nil && !sel[i] {
+				continue
+			}
+			if column.IsNull(i) {
+				buf[0], b = NilFlag, nil
+				isNull[i] = true
+			} else {
+				buf[0] = uintFlag
+				var v uint64
+				v, err = t.ToPackedUint()
+				if err != nil {
+					return
+				}
+				b = (*[unsafe.Sizeof(v)]byte)(unsafe.Pointer(&v))[:]
+			}
+
+			// As the golang doc described, `Hash.Write` never returns an error.
+			// See https://golang.org/pkg/hash/#Hash
+			_, _ = h[i].Write(buf)
+			_, _ = h[i].Write(b)
+		}
+	case mysql.TypeDuration:
+		for i := 0; i < rows; i++ {
+			if sel != nil && !sel[i] {
+				continue
+			}
+			if column.IsNull(i) {
+				buf[0], b = NilFlag, nil
+				isNull[i] = true
+			} else {
+				buf[0] = durationFlag
+				// duration may have negative value, so we cannot use String to encode directly.
+				b = column.GetRaw(i)
+			}
+
+			// As the golang doc described, `Hash.Write` never returns an error.
+			// See https://golang.org/pkg/hash/#Hash
+			_, _ = h[i].Write(buf)
+			_, _ = h[i].Write(b)
+		}
+	case mysql.TypeEnum:
+		for i := 0; i < rows; i++ {
+			if sel != nil && !sel[i] {
+				continue
+			}
+			if column.IsNull(i) {
+				buf[0], b = NilFlag, nil
+				isNull[i] = true
+			} else {
+				buf[0] = uvarintFlag
+				v := uint64(column.GetEnum(i).ToNumber())
+				b = (*[sizeUint64]byte)(unsafe.Pointer(&v))[:]
+			}
+
+			// As the golang doc described, `Hash.Write` never returns an error.
+			// See https://golang.org/pkg/hash/#Hash
+			_, _ = h[i].Write(buf)
+			_, _ = h[i].Write(b)
+		}
+	case mysql.TypeSet:
+		for i := 0; i < rows; i++ {
+			if sel != nil && !sel[i] {
+				continue
+			}
+			if column.IsNull(i) {
+				buf[0], b = NilFlag, nil
+				isNull[i] = true
+			} else {
+				buf[0] = uvarintFlag
+				v := uint64(column.GetSet(i).ToNumber())
+				b = (*[sizeUint64]byte)(unsafe.Pointer(&v))[:]
+			}
+
+			// As the golang doc described, `Hash.Write` never returns an error.
+			// See https://golang.org/pkg/hash/#Hash
+			_, _ = h[i].Write(buf)
+			_, _ = h[i].Write(b)
+		}
+	case mysql.TypeBit:
+		for i := 0; i < rows; i++ {
+			if sel != nil && !sel[i] {
+				continue
+			}
+			if column.IsNull(i) {
+				buf[0], b = NilFlag, nil
+				isNull[i] = true
+			} else {
+				// We don't need to handle errors here since the literal is ensured to be able to store in uint64 in convertToMysqlBit.
+				buf[0] = uvarintFlag
+				v, err1 := types.BinaryLiteral(column.GetBytes(i)).ToInt(sc)
+				terror.Log(errors.Trace(err1))
+				b = (*[unsafe.Sizeof(v)]byte)(unsafe.Pointer(&v))[:]
+			}
+
+			// As the golang doc described, `Hash.Write` never returns an error.
+			// See https://golang.org/pkg/hash/#Hash
+			_, _ = h[i].Write(buf)
+			_, _ = h[i].Write(b)
+		}
+	case mysql.TypeJSON:
+		for i := 0; i < rows; i++ {
+			if sel != nil && !sel[i] {
+				continue
+			}
+			if column.IsNull(i) {
+				buf[0], b = NilFlag, nil
+				isNull[i] = true
+			} else {
+				buf[0] = jsonFlag
+				b = column.GetBytes(i)
+			}
+
 			// As the golang doc described, `Hash.Write` never returns an error.
 			// See https://golang.org/pkg/hash/#Hash
 			_, _ = h[i].Write(buf)
@@ -480,6 +733,34 @@ func DecodeOne(b []byte) (remain []byte, d types.Datum, err error) {
 		var v []byte
 		b, v, err = DecodeCompactBytes(b)
 		d.SetBytes(v)
+	case decimalFlag:
+		var (
+			dec             *types.MyDecimal
+			precision, frac int
+		)
+		b, dec, precision, frac, err = DecodeDecimal(b)
+		if err == nil {
+			d.SetMysqlDecimal(dec)
+			d.SetLength(precision)
+			d.SetFrac(frac)
+		}
+	case durationFlag:
+		var r int64
+		b, r, err = DecodeInt(b)
+		if err == nil {
+			// use max fsp, let outer to do round manually.
+			v := types.Duration{Duration: time.Duration(r), Fsp: types.MaxFsp}
+			d.SetMysqlDuration(v)
+		}
+	case jsonFlag:
+		var size int
+		size, err = json.PeekBytesAsJSON(b)
+		if err != nil {
+			return b, d, err
+		}
+		j := json.BinaryJSON{TypeCode: b[0], Value: b[1:size]}
+		d.SetMysqlJSON(j)
+		b = b[size:]
 	case NilFlag:
 	default:
 		return b, d, errors.Errorf("invalid encoded key flag %v", flag)
@@ -542,10 +823,14 @@ func peek(b []byte) (length int, err error) {
 		l, err = peekBytes(b)
 	case compactBytesFlag:
 		l, err = peekCompactBytes(b)
+	case decimalFlag:
+		l, err = types.DecimalPeak(b)
 	case varintFlag:
 		l, err = peekVarint(b)
 	case uvarintFlag:
 		l, err = peekUvarint(b)
+	case jsonFlag:
+		l, err = json.PeekBytesAsJSON(b)
 	default:
 		return 0, errors.Errorf("invalid encoded key flag %v", flag)
 	}
@@ -680,6 +965,38 @@ func (decoder *Decoder) DecodeOne(b []byte, colIdx int, ft *types.FieldType) (re
 			return nil, errors.Trace(err)
 		}
 		chk.AppendBytes(colIdx, v)
+	case decimalFlag:
+		var dec *types.MyDecimal
+		var frac int
+		b, dec, _, frac, err = DecodeDecimal(b)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if ft.Decimal != types.UnspecifiedLength && frac > ft.Decimal {
+			to := new(types.MyDecimal)
+			err := dec.Round(to, ft.Decimal, types.ModeHalfEven)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			dec = to
+		}
+		chk.AppendMyDecimal(colIdx, dec)
+	case durationFlag:
+		var r int64
+		b, r, err = DecodeInt(b)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		v := types.Duration{Duration: time.Duration(r), Fsp: int8(ft.Decimal)}
+		chk.AppendDuration(colIdx, v)
+	case jsonFlag:
+		var size int
+		size, err = json.PeekBytesAsJSON(b)
+		if err != nil {
+			return nil, err
+		}
+		chk.AppendJSON(colIdx, json.BinaryJSON{TypeCode: b[0], Value: b[1:size]})
+		b = b[size:]
 	case NilFlag:
 		chk.AppendNull(colIdx)
 	default:
@@ -692,11 +1009,49 @@ func (decoder *Decoder) DecodeOne(b []byte, colIdx int, ft *types.FieldType) (re
 }
 
 func appendIntToChunk(val int64, chk *chunk.Chunk, colIdx int, ft *types.FieldType) {
-	chk.AppendInt64(colIdx, val)
+	switch ft.Tp {
+	case mysql.TypeDuration:
+		v := types.Duration{Duration: time.Duration(val), Fsp: int8(ft.Decimal)}
+		chk.AppendDuration(colIdx, v)
+	default:
+		chk.AppendInt64(colIdx, val)
+	}
 }
 
 func appendUintToChunk(val uint64, chk *chunk.Chunk, colIdx int, ft *types.FieldType, loc *time.Location) error {
-	chk.AppendUint64(colIdx, val)
+	switch ft.Tp {
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		t := types.NewTime(types.ZeroCoreTime, ft.Tp, int8(ft.Decimal))
+		err := t.FromPackedUint(val)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if ft.Tp == mysql.TypeTimestamp && !t.IsZero() && loc != nil {
+			err = t.ConvertTimeZone(time.UTC, loc)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		chk.AppendTime(colIdx, t)
+	case mysql.TypeEnum:
+		// ignore error deliberately, to read empty enum value.
+		enum, err := types.ParseEnumValue(ft.Elems, val)
+		if err != nil {
+			enum = types.Enum{}
+		}
+		chk.AppendEnum(colIdx, enum)
+	case mysql.TypeSet:
+		set, err := types.ParseSetValue(ft.Elems, val)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		chk.AppendSet(colIdx, set)
+	case mysql.TypeBit:
+		byteSize := (ft.Flen + 7) >> 3
+		chk.AppendBytes(colIdx, types.NewBinaryLiteralFromUint(val, byteSize))
+	default:
+		chk.AppendUint64(colIdx, val)
+	}
 	return nil
 }
 
@@ -731,6 +1086,54 @@ func HashGroupKey(sc *stmtctx.StatementContext, n int, col *chunk.Column, buf []
 				buf[i] = EncodeFloat(buf[i], f64s[i])
 			}
 		}
+	case types.ETDecimal:
+		ds := col.Decimals()
+		for i := 0; i < n; i++ {
+			if col.IsNull(i) {
+				buf[i] = append(buf[i], NilFlag)
+				continue
+			}
+			// Decimals with the same value but different fractions should be in the same group.
+			b, err := ds[i].ToHashKey()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			buf[i] = append(buf[i], decimalFlag)
+			buf[i] = append(buf[i], b...)
+		}
+	case types.ETDatetime, types.ETTimestamp:
+		ts := col.Times()
+		for i := 0; i < n; i++ {
+			if col.IsNull(i) {
+				buf[i] = append(buf[i], NilFlag)
+				continue
+			}
+			var err error
+			buf[i] = append(buf[i], uintFlag)
+			buf[i], err = EncodeMySQLTime(sc, ts[i], mysql.TypeUnspecified, buf[i])
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+	case types.ETDuration:
+		ds := col.GoDurations()
+		for i := 0; i < n; i++ {
+			if col.IsNull(i) {
+				buf[i] = append(buf[i], NilFlag)
+				continue
+			}
+			buf[i] = append(buf[i], durationFlag)
+			buf[i] = EncodeInt(buf[i], int64(ds[i]))
+		}
+	case types.ETJson:
+		for i := 0; i < n; i++ {
+			if col.IsNull(i) {
+				buf[i] = append(buf[i], NilFlag)
+				continue
+			}
+			buf[i] = append(buf[i], jsonFlag)
+			buf[i] = append(buf[i], col.GetBytes(i)...)
+		}
 	case types.ETString:
 		for i := 0; i < n; i++ {
 			if col.IsNull(i) {
@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/testleak"
 )
@@ -65,6 +66,16 @@ func (s *testCodecSuite) TestCodecKey(c *C) {
 			types.MakeDatums(int64(1), int64(0)),
 		},
 
+		{
+			types.MakeDatums(types.NewBinaryLiteralFromUint(100, -1), types.NewBinaryLiteralFromUint(100, 4)),
+			types.MakeDatums(uint64(100), uint64(100)),
+		},
+
+		{
+			types.MakeDatums(types.Enum{Name: "a", Value: 1}, types.Set{Name: "a", Value: 1}),
+			types.MakeDatums(uint64(1), uint64(1)),
+		},
+
 		{
 			types.MakeDatums(nil),
 			types.MakeDatums(nil),
@@ -641,6 +652,11 @@ func datumsForTest(sc *stmtctx.StatementContext) ([]types.Datum, []*types.FieldT
 		{[]byte("abc"), types.NewFieldType(mysql.TypeMediumBlob)},
 		{[]byte("abc"), types.NewFieldType(mysql.TypeLongBlob)},
 		{int64(1), types.NewFieldType(mysql.TypeYear)},
+		{json.CreateBinary("abc"), types.NewFieldType(mysql.TypeJSON)},
+		{json.CreateBinary(map[string]interface{}{"a": int64(1)}), types.NewFieldType(mysql.TypeJSON)},
+		{types.Enum{Name: "a", Value: 1}, &types.FieldType{Tp: mysql.TypeEnum, Elems: []string{"a"}}},
+		{types.Set{Name: "a", Value: 1}, &types.FieldType{Tp: mysql.TypeSet, Elems: []string{"a"}}},
+		{types.BinaryLiteral{100}, &types.FieldType{Tp: mysql.TypeBit, Flen: 8}},
 	}
 
 	datums := make([]types.Datum, 0, len(table)+2)
@@ -0,0 +1,64 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deadlock
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testDeadlockSuite{})
+
+func TestT(t *testing.T) {
+	CustomVerboseFlag = true
+	TestingT(t)
+}
+
+type testDeadlockSuite struct {
+}
+
+func (s *testDeadlockSuite) TestDeadlock(c *C) {
+	detector := NewDetector()
+	err := detector.Detect(1, 2, 100)
+	c.Assert(err, IsNil)
+	err = detector.Detect(2, 3, 200)
+	c.Assert(err, IsNil)
+	err = detector.Detect(3, 1, 300)
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "deadlock(200)")
+	// The edge that closes the cycle is not registered.
+	c.Assert(detector.waitForMap[3], IsNil)
+
+	detector.CleanUp(2)
+	c.Assert(detector.waitForMap[2], IsNil)
+	err = detector.Detect(3, 1, 300)
+	c.Assert(err, IsNil)
+	c.Assert(len(detector.waitForMap[3].txns), Equals, 1)
+
+	// Detect the same edge twice doesn't register it twice.
+	err = detector.Detect(3, 1, 300)
+	c.Assert(err, IsNil)
+	c.Assert(len(detector.waitForMap[3].txns), Equals, 1)
+	err = detector.Detect(3, 4, 400)
+	c.Assert(err, IsNil)
+	c.Assert(len(detector.waitForMap[3].txns), Equals, 2)
+
+	detector.CleanUpWaitFor(3, 1, 300)
+	c.Assert(len(detector.waitForMap[3].txns), Equals, 1)
+	detector.CleanUpWaitFor(3, 4, 400)
+	c.Assert(detector.waitForMap[3], IsNil)
+	detector.CleanUp(1)
+	c.Assert(detector.waitForMap, HasLen, 0)
+}
@@ -0,0 +1,113 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvcache
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+)
+
+func TestT(t *testing.T) {
+	CustomVerboseFlag = true
+	TestingT(t)
+}
+
+var _ = Suite(&testLRUCacheSuite{})
+
+type testLRUCacheSuite struct {
+}
+
+type mockCacheKey struct {
+	hash []byte
+	key  int64
+}
+
+func (mk *mockCacheKey) Hash() []byte {
+	if mk.hash != nil {
+		return mk.hash
+	}
+	mk.hash = make([]byte, 8)
+	for i := uint64(0); i < 8; i++ {
+		mk.hash[i] = byte((mk.key >> (i * 8)) & 0xff)
+	}
+	return mk.hash
+}
+
+func newMockHashKey(key int64) *mockCacheKey {
+	return &mockCacheKey{
+		key: key,
+	}
+}
+
+func (s *testLRUCacheSuite) TestPutGet(c *C) {
+	lru := NewSimpleLRUCache(3)
+
+	keys := make([]*mockCacheKey, 5)
+	for i := 0; i < 5; i++ {
+		keys[i] = newMockHashKey(int64(i))
+		lru.Put(keys[i], i)
+	}
+	c.Assert(lru.Size(), Equals, 3)
+
+	// The two least recently used entries are evicted.
+	for i := 0; i < 2; i++ {
+		_, ok := lru.Get(keys[i])
+		c.Assert(ok, IsFalse)
+	}
+	for i := 2; i < 5; i++ {
+		value, ok := lru.Get(keys[i])
+		c.Assert(ok, IsTrue)
+		c.Assert(value, Equals, i)
+	}
+
+	// Get moves the entry to the front, so keys[3] is evicted next.
+	_, ok := lru.Get(keys[2])
+	c.Assert(ok, IsTrue)
+	lru.Put(keys[0], 0)
+	_, ok = lru.Get(keys[3])
+	c.Assert(ok, IsFalse)
+	_, ok = lru.Get(keys[2])
+	c.Assert(ok, IsTrue)
+
+	// Put on an existing key replaces the value.
+	lru.Put(keys[2], 10)
+	value, ok := lru.Get(keys[2])
+	c.Assert(ok, IsTrue)
+	c.Assert(value, Equals, 10)
+	c.Assert(lru.Size(), Equals, 3)
+}
+
+func (s *testLRUCacheSuite) TestDelete(c *C) {
+	lru := NewSimpleLRUCache(3)
+
+	keys := make([]*mockCacheKey, 3)
+	for i := 0; i < 3; i++ {
+		keys[i] = newMockHashKey(int64(i))
+		lru.Put(keys[i], i)
+	}
+
+	lru.Delete(keys[1])
+	c.Assert(lru.Size(), Equals, 2)
+	_, ok := lru.Get(keys[1])
+	c.Assert(ok, IsFalse)
+	// Deleting a missing key is a no-op.
+	lru.Delete(keys[1])
+	c.Assert(lru.Size(), Equals, 2)
+
+	lru.DeleteAll()
+	c.Assert(lru.Size(), Equals, 0)
+	_, ok = lru.Get(keys[0])
+	c.Assert(ok, IsFalse)
+}
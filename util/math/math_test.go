@@ -54,3 +54,17 @@ func (s *testMath) TestStrLenOfUint64Fast_ManualTestCases(c *C) {
 		c.Assert(actual, Equals, expected)
 	}
 }
+
+func (s *testMath) TestMysqlRng(c *C) {
+	// The expected values are the results of `SELECT RAND(3)` and the following calls in MySQL.
+	rng := NewWithSeed(3)
+	expected := []float64{0.9057697559760601, 0.37307905813034536, 0.14808605345719125}
+	for _, val := range expected {
+		c.Assert(rng.Gen(), Equals, val)
+	}
+	rng = NewWithTime()
+	for i := 0; i < 1000; i++ {
+		val := rng.Gen()
+		c.Assert(val >= 0 && val < 1, IsTrue)
+	}
+}
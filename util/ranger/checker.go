@@ -16,7 +16,9 @@ package ranger
 import (
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
 )
 
 // conditionChecker checks if this condition can be pushed to index planner.
@@ -45,24 +47,30 @@ func (c *conditionChecker) checkScalarFunction(scalar *expression.ScalarFunction
 	case ast.EQ, ast.NE, ast.GE, ast.GT, ast.LE, ast.LT:
 		if _, ok := scalar.GetArgs()[0].(*expression.Constant); ok {
 			if c.checkColumn(scalar.GetArgs()[1]) {
-				return scalar.FuncName.L != ast.NE || c.length == types.UnspecifiedLength
+				return c.checkHybridOp(scalar.GetArgs()[1], scalar.FuncName.L) &&
+					(scalar.FuncName.L != ast.NE || c.length == types.UnspecifiedLength)
 			}
 		}
 		if _, ok := scalar.GetArgs()[1].(*expression.Constant); ok {
 			if c.checkColumn(scalar.GetArgs()[0]) {
-				return scalar.FuncName.L != ast.NE || c.length == types.UnspecifiedLength
+				return c.checkHybridOp(scalar.GetArgs()[0], scalar.FuncName.L) &&
+					(scalar.FuncName.L != ast.NE || c.length == types.UnspecifiedLength)
 			}
 		}
 	case ast.IsNull:
 		return c.checkColumn(scalar.GetArgs()[0])
 	case ast.UnaryNot:
-		if _, ok := scalar.GetArgs()[0].(*expression.ScalarFunction); ok {
+		if s, ok := scalar.GetArgs()[0].(*expression.ScalarFunction); ok {
+			// "not like" can't lead to a range.
+			if s.FuncName.L == ast.Like {
+				return false
+			}
 			return c.check(scalar.GetArgs()[0])
 		}
 		// "not column" or "not constant" can't lead to a range.
 		return false
 	case ast.In:
-		if !c.checkColumn(scalar.GetArgs()[0]) {
+		if !c.checkColumn(scalar.GetArgs()[0]) || isEnumOrSet(scalar.GetArgs()[0]) {
 			return false
 		}
 		for _, v := range scalar.GetArgs()[1:] {
@@ -71,10 +79,75 @@ func (c *conditionChecker) checkScalarFunction(scalar *expression.ScalarFunction
 			}
 		}
 		return true
+	case ast.Like:
+		return c.checkLikeFunc(scalar)
 	}
 	return false
 }
 
+// checkLikeFunc checks whether the like function on a string column has a
+// constant pattern with a non-empty prefix before its first wildcard. The
+// condition is reserved if the prefix range is not exactly what it matches.
+func (c *conditionChecker) checkLikeFunc(scalar *expression.ScalarFunction) bool {
+	args := scalar.GetArgs()
+	// The index of an enum or set column is ordered by the element index, not by the
+	// element string, so a string prefix can not be converted into a range on it.
+	if !c.checkColumn(args[0]) || args[0].GetType().EvalType() != types.ETString || isEnumOrSet(args[0]) {
+		return false
+	}
+	pattern, ok := args[1].(*expression.Constant)
+	if !ok || expression.ContainMutableConst([]expression.Expression{pattern}) {
+		return false
+	}
+	escape, ok := args[2].(*expression.Constant)
+	if !ok {
+		return false
+	}
+	patternStr, isNull, err := pattern.EvalString(nil, chunk.Row{})
+	if isNull || err != nil {
+		return false
+	}
+	escapeVal, isNull, err := escape.EvalInt(nil, chunk.Row{})
+	if isNull || err != nil {
+		return false
+	}
+	for i := 0; i < len(patternStr); i++ {
+		if patternStr[i] == byte(escapeVal) {
+			i++
+			continue
+		}
+		if patternStr[i] != '%' && patternStr[i] != '_' {
+			continue
+		}
+		if i == 0 {
+			// A pattern that starts with a wildcard can't lead to a range.
+			return false
+		}
+		if patternStr[i] == '_' || i != len(patternStr)-1 {
+			c.shouldReserve = true
+		}
+		break
+	}
+	return true
+}
+
+// checkHybridOp checks whether the comparison on an ENUM or SET column can be
+// used to build ranges. Those columns are indexed by their numeric value while
+// they are compared with strings by name, so only equality keeps the order, and
+// the condition is reserved to filter out values that are not in the element list.
+func (c *conditionChecker) checkHybridOp(expr expression.Expression, op string) bool {
+	if !isEnumOrSet(expr) {
+		return true
+	}
+	c.shouldReserve = true
+	return op == ast.EQ
+}
+
+func isEnumOrSet(expr expression.Expression) bool {
+	tp := expr.GetType().Tp
+	return tp == mysql.TypeEnum || tp == mysql.TypeSet
+}
+
 func (c *conditionChecker) checkColumn(expr expression.Expression) bool {
 	col, ok := expr.(*expression.Column)
 	if !ok {
@@ -117,7 +117,7 @@ func getEqOrInColOffset(expr expression.Expression, cols []*expression.Column) i
 	}
 	if f.FuncName.L == ast.In {
 		c, ok := f.GetArgs()[0].(*expression.Column)
-		if !ok {
+		if !ok || isEnumOrSet(c) {
 			return -1
 		}
 		for _, arg := range f.GetArgs()[1:] {
@@ -246,7 +246,7 @@ func ExtractEqAndInCondition(sctx sessionctx.Context, conditions []expression.Ex
 			accesses = accesses[:i]
 			break
 		}
-		if lengths[i] != types.UnspecifiedLength {
+		if lengths[i] != types.UnspecifiedLength || isEnumOrSet(cols[i]) {
 			filters = append(filters, cond)
 		}
 	}
@@ -407,6 +407,77 @@ func (r *builder) buildFromIn(expr *expression.ScalarFunction) ([]point, bool) {
 	return rangePoints[:curPos], hasNull
 }
 
+// buildFromPatternLike builds the range of the prefix before the first wildcard
+// of the pattern, e.g. 'abc%' leads to ["abc", "abd").
+func (r *builder) buildFromPatternLike(expr *expression.ScalarFunction) []point {
+	pdt, err := expr.GetArgs()[1].Eval(chunk.Row{})
+	if err != nil {
+		r.err = errors.Trace(err)
+		return fullRange
+	}
+	pattern, err := pdt.ToString()
+	if err != nil {
+		r.err = errors.Trace(err)
+		return fullRange
+	}
+	edt, err := expr.GetArgs()[2].Eval(chunk.Row{})
+	if err != nil {
+		r.err = errors.Trace(err)
+		return fullRange
+	}
+	escape := byte(edt.GetInt64())
+	lowValue := make([]byte, 0, len(pattern))
+	var exclude bool
+	isExactMatch := true
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == escape {
+			i++
+			if i < len(pattern) {
+				lowValue = append(lowValue, pattern[i])
+			} else {
+				lowValue = append(lowValue, escape)
+			}
+			continue
+		}
+		if pattern[i] == '%' {
+			isExactMatch = false
+			break
+		}
+		if pattern[i] == '_' {
+			// The matched strings are longer than the prefix, e.g. "abc_x" can't
+			// match "abc", so the start point is excluded.
+			exclude = true
+			isExactMatch = false
+			break
+		}
+		lowValue = append(lowValue, pattern[i])
+	}
+	if isExactMatch {
+		val := types.NewStringDatum(string(lowValue))
+		return []point{{value: val, start: true}, {value: val}}
+	}
+	if len(lowValue) == 0 {
+		return []point{{value: types.MinNotNullDatum(), start: true}, {value: types.MaxValueDatum()}}
+	}
+	startPoint := point{start: true, excl: exclude}
+	startPoint.value.SetBytesAsString(lowValue)
+	// The end point is the smallest string that is greater than every string
+	// with the prefix, e.g. "abd" for "abc" and "b" for "a\xff".
+	endPoint := point{value: types.MaxValueDatum()}
+	for i := len(lowValue) - 1; i >= 0; i-- {
+		if lowValue[i] == math.MaxUint8 {
+			continue
+		}
+		highValue := make([]byte, i+1)
+		copy(highValue, lowValue)
+		highValue[i]++
+		endPoint = point{excl: true}
+		endPoint.value.SetBytesAsString(highValue)
+		break
+	}
+	return []point{startPoint, endPoint}
+}
+
 func (r *builder) buildFromNot(expr *expression.ScalarFunction) []point {
 	switch n := expr.FuncName.L; n {
 	case ast.In:
@@ -460,6 +531,8 @@ func (r *builder) buildFromScalarFunc(expr *expression.ScalarFunction) []point {
 	case ast.In:
 		retPoints, _ := r.buildFromIn(expr)
 		return retPoints
+	case ast.Like:
+		return r.buildFromPatternLike(expr)
 	case ast.IsNull:
 		startPoint := point{start: true}
 		endPoint := point{}
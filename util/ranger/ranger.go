@@ -92,7 +92,11 @@ func convertPoint(sc *stmtctx.StatementContext, point point, tp *types.FieldType
 	}
 	casted, err := point.value.ConvertTo(sc, tp)
 	if err != nil {
-		return point, errors.Trace(err)
+		if (tp.Tp != mysql.TypeEnum && tp.Tp != mysql.TypeSet) || !types.ErrTruncated.Equal(err) {
+			return point, errors.Trace(err)
+		}
+		// The value is not in the element list, so the casted zero value can
+		// only match invalid rows, which are dropped by the reserved filter.
 	}
 	valCmpCasted, err := point.value.CompareDatum(sc, &casted)
 	if err != nil {
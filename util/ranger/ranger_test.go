@@ -327,6 +327,55 @@ func (s *testRangerSuite) TestIndexRange(c *C) {
 			filterConds: "[]",
 			resultStr:   `[]`,
 		},
+		{
+			indexPos:    0,
+			exprStr:     `a LIKE 'abc%'`,
+			accessConds: "[like(test.t.a, abc%, 92)]",
+			filterConds: "[]",
+			resultStr:   "[[\"abc\",\"abd\")]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE 'abc_'",
+			accessConds: "[like(test.t.a, abc_, 92)]",
+			filterConds: "[like(test.t.a, abc_, 92)]",
+			resultStr:   "[(\"abc\",\"abd\")]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE 'ab%c'",
+			accessConds: "[like(test.t.a, ab%c, 92)]",
+			filterConds: "[like(test.t.a, ab%c, 92)]",
+			resultStr:   "[[\"ab\",\"ac\")]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE 'ab|_c%' ESCAPE '|'",
+			accessConds: "[like(test.t.a, ab|_c%, 124)]",
+			filterConds: "[]",
+			resultStr:   "[[\"ab_c\",\"ab_d\")]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE '%abc'",
+			accessConds: "[]",
+			filterConds: "[like(test.t.a, %abc, 92)]",
+			resultStr:   "[[NULL,+inf]]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a NOT LIKE 'abc%'",
+			accessConds: "[]",
+			filterConds: "[not(like(test.t.a, abc%, 92))]",
+			resultStr:   "[[NULL,+inf]]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE 'abc'",
+			accessConds: "[eq(test.t.a, abc)]",
+			filterConds: "[]",
+			resultStr:   "[[\"abc\",\"abc\"]]",
+		},
 		{
 			indexPos:    0,
 			exprStr:     `a = 'a' and b in (1, 2, 3)`,
@@ -492,6 +541,24 @@ func (s *testRangerSuite) TestIndexRange(c *C) {
 	}
 }
 
+func (s *testRangerSuite) TestLikeOnEnumIndex(c *C) {
+	defer testleak.AfterTest(c)()
+	dom, store, err := newDomainStoreWithBootstrap(c)
+	defer func() {
+		dom.Close()
+		store.Close()
+	}()
+	c.Assert(err, IsNil)
+	testKit := testkit.NewTestKit(c, store)
+	testKit.MustExec("use test")
+	testKit.MustExec("drop table if exists t")
+	testKit.MustExec("create table t(a int, e enum('b', 'ab', 'a'), index idx_e(e))")
+	testKit.MustExec("insert into t values (1, 'a'), (2, 'ab'), (3, 'b')")
+	testKit.MustQuery("select a from t use index(idx_e) where e like 'a%' order by a").Check(testkit.Rows("1", "2"))
+	testKit.MustQuery("select a from t use index(idx_e) where e like 'b' order by a").Check(testkit.Rows("3"))
+	testKit.MustQuery("select a from t use index(idx_e) where e like 'ab%' and a > 0 order by a").Check(testkit.Rows("2"))
+}
+
 // for issue #6661
 func (s *testRangerSuite) TestIndexRangeForUnsignedInt(c *C) {
 	defer testleak.AfterTest(c)()
@@ -67,6 +67,9 @@ type Statement interface {
 
 	// IsReadOnly returns if the statement is read only. For example: SelectStmt without lock.
 	IsReadOnly() bool
+
+	// RebuildPlan rebuilds the plan of the statement, it's used when retrying a transaction.
+	RebuildPlan(ctx context.Context) (schemaVersion int64, err error)
 }
 
 // RecordSet is an abstract result set interface to help get data from Plan.
@@ -89,6 +92,8 @@ type RecordSet interface {
 type MultiQueryNoDelayResult interface {
 	// AffectedRows return affected row for one statement in multi-queries.
 	AffectedRows() uint64
+	// LastMessage return last message for one statement in multi-queries.
+	LastMessage() string
 	// WarnCount return warn count for one statement in multi-queries.
 	WarnCount() uint16
 	// Status return status when executing one statement in multi-queries.